@@ -0,0 +1,125 @@
+// Command powermem-bench generates load on a memory store with concurrent
+// mixes of Add and Search operations, and reports their throughput and
+// latency percentiles, to size deployments.
+//
+// It reads the same configuration as applications: the .env file (searched
+// upward from the current directory), or the file given with -env or
+// -config. Loading a server mode is blocked until powermem has one, so the
+// operations run in process, through the configured vector store, embedder
+// and LLM. See package
+// github.com/oceanbase/powermem-go/pkg/loadtest for details.
+//
+// Usage:
+//
+//	powermem-bench [-config file | -env file] [-workers 8] [-duration 30s]
+//	    [-requests n] [-add-ratio 0.2] [-users 100] [-rate ops/s] [-json]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/loadtest"
+)
+
+func main() {
+	var (
+		configPath = flag.String("config", "", "load the configuration from a YAML, TOML or JSON `file`")
+		envPath    = flag.String("env", "", "load the configuration from this .env `file`")
+		opts       loadtest.Options
+		asJSON     = flag.Bool("json", false, "print the report as JSON")
+		keep       = flag.Bool("keep", false, "keep the memories added by the test")
+	)
+	flag.IntVar(&opts.Workers, "workers", 8, "number of concurrent operations")
+	flag.DurationVar(&opts.Duration, "duration", 30*time.Second, "how long to start new operations")
+	flag.IntVar(&opts.Requests, "requests", 0, "stop after this many operations (0: run for -duration)")
+	flag.Float64Var(&opts.AddRatio, "add-ratio", 0.2, "fraction of operations that are Adds (0: Searches only)")
+	flag.IntVar(&opts.Users, "users", 100, "number of synthetic users")
+	flag.Float64Var(&opts.Rate, "rate", 0, "maximum operations started per second (0: no limit)")
+	flag.IntVar(&opts.SearchLimit, "limit", 10, "number of results of Searches")
+	flag.BoolVar(&opts.Infer, "infer", false, "add memories with intelligent memory (calls the LLM)")
+	flag.Int64Var(&opts.Seed, "seed", 1, "seed of the generated contents and queries")
+	flag.Parse()
+
+	if *configPath != "" && *envPath != "" {
+		fmt.Fprintln(os.Stderr, "powermem-bench: -config and -env are mutually exclusive")
+		os.Exit(2)
+	}
+	if opts.AddRatio == 0 {
+		opts.AddRatio = -1
+	}
+	if isSet("requests") && !isSet("duration") {
+		opts.Duration = 0
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx, *configPath, *envPath, opts, *asJSON, *keep)
+	stop()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "powermem-bench: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// isSet reports whether the flag name was given on the command line.
+func isSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func run(ctx context.Context, configPath, envPath string, opts loadtest.Options, asJSON, keep bool) (err error) {
+	var cfg *core.Config
+	switch {
+	case configPath != "":
+		cfg, err = core.LoadConfigFromFile(configPath)
+	case envPath != "":
+		cfg, err = core.LoadConfigFromEnvFile(envPath)
+	default:
+		cfg, err = core.LoadConfigFromEnv()
+	}
+	if err != nil {
+		return err
+	}
+	client, err := core.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := client.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	report, err := loadtest.Run(ctx, client, opts)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = report.Write(os.Stdout)
+	}
+	if err != nil {
+		return err
+	}
+
+	if keep {
+		return nil
+	}
+	// The test may have been interrupted, but the memories are deleted anyway
+	return loadtest.Cleanup(context.Background(), client, opts.Users)
+}
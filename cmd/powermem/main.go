@@ -0,0 +1,40 @@
+// Command powermem inspects and maintains memory stores from the command line.
+//
+// It reads the same configuration as applications: the .env file (searched
+// upward from the current directory), or the file given with -env or -config.
+//
+// Usage:
+//
+//	powermem [-config file | -env file] <command> [flags] [args]
+//
+// Run powermem -h for the list of commands. See package
+// github.com/oceanbase/powermem-go/pkg/cli for details.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/oceanbase/powermem-go/pkg/cli"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := cli.Run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, cli.ErrUsage):
+		fmt.Fprintf(os.Stderr, "powermem: %v\n", err)
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "powermem: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -21,10 +21,10 @@ func main() {
 
 	// Override test database path
 	if config.VectorStore.Provider == "sqlite" {
-		if config.VectorStore.Config == nil {
-			config.VectorStore.Config = make(map[string]interface{})
+		if config.VectorStore.SQLite == nil {
+			config.VectorStore.SQLite = &powermem.SQLiteConfig{}
 		}
-		config.VectorStore.Config["db_path"] = "./streaming_example.db"
+		config.VectorStore.SQLite.DBPath = "./streaming_example.db"
 	}
 
 	// Create client
@@ -21,11 +21,11 @@ func main() {
 
 	// Override example database path
 	if memoryConfig.VectorStore.Provider == "sqlite" {
-		if memoryConfig.VectorStore.Config == nil {
-			memoryConfig.VectorStore.Config = make(map[string]interface{})
+		if memoryConfig.VectorStore.SQLite == nil {
+			memoryConfig.VectorStore.SQLite = &core.SQLiteConfig{}
 		}
-		memoryConfig.VectorStore.Config["db_path"] = "./usermemory_example.db"
-		memoryConfig.VectorStore.Config["collection_name"] = "memories"
+		memoryConfig.VectorStore.SQLite.DBPath = "./usermemory_example.db"
+		memoryConfig.VectorStore.SQLite.CollectionName = "memories"
 	}
 
 	// Create UserMemory configuration (optional: enable query rewrite)
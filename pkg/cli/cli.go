@@ -0,0 +1,315 @@
+// Package cli implements the powermem command-line tool.
+//
+// The tool inspects and maintains memory stores with the same configuration
+// as applications: the .env file found by core.LoadConfigFromEnv, an explicit
+// .env file (-env), or a YAML, TOML or JSON config file (-config).
+//
+// Usage:
+//
+//	powermem [-config file | -env file] <command> [flags] [args]
+//
+// Commands:
+//
+//	add       add a memory
+//	search    search memories
+//	get       show memories by ID
+//	delete    delete memories by ID
+//	export    write memories as JSON lines
+//	import    add memories from JSON lines
+//	stats     count memories by user and agent
+//	profiles  list user profiles
+//	migrate   copy memories to another store
+//	snapshot  back up memories to a snapshot file
+//	restore   restore memories from a snapshot file
+//	dashboard serve the web dashboard (see package dashboard)
+//
+// The binary is cmd/powermem; Run is exported so that the commands can be
+// tested without building it.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// ErrUsage is returned (wrapped) by Run when the command line is invalid.
+// The usage has already been printed to stderr.
+var ErrUsage = errors.New("invalid usage")
+
+// pageSize is the number of memories fetched per call when a command walks
+// a whole store.
+const pageSize = 500
+
+// command is a subcommand of the tool.
+type command struct {
+	name    string
+	summary string
+	run     func(r *runner, args []string) error
+}
+
+// commands lists the subcommands in the order of the usage message.
+var commands = []command{
+	{"add", "add a memory", (*runner).add},
+	{"search", "search memories", (*runner).search},
+	{"get", "show memories by ID", (*runner).get},
+	{"delete", "delete memories by ID", (*runner).delete},
+	{"export", "write memories as JSON lines", (*runner).export},
+	{"import", "add memories from JSON lines", (*runner).importMemories},
+	{"stats", "count memories by user and agent", (*runner).stats},
+	{"profiles", "list user profiles", (*runner).profiles},
+	{"migrate", "copy memories to another store", (*runner).migrate},
+	{"snapshot", "back up memories to a snapshot file", (*runner).snapshot},
+	{"restore", "restore memories from a snapshot file", (*runner).restore},
+	{"dashboard", "serve the web dashboard", (*runner).serveDashboard},
+}
+
+// runner holds the state of one invocation.
+type runner struct {
+	ctx    context.Context
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	// configPath and envPath are the global -config and -env flags.
+	configPath string
+	envPath    string
+}
+
+// Run runs the tool with args (without the program name).
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - args: Command-line arguments
+//   - stdin: Input of the import and add commands
+//   - stdout: Command output
+//   - stderr: Usage and diagnostics
+//
+// Returns nil on success, flag.ErrHelp if help was requested, an error
+// wrapping ErrUsage if the command line is invalid, or the error of the
+// command.
+//
+// Operations are attributed to the user running the tool (see
+// core.ContextWithActor), unless ctx already has an actor.
+//
+// Example:
+//
+//	err := cli.Run(ctx, []string{"search", "-user", "user_001", "hiking"}, os.Stdin, os.Stdout, os.Stderr)
+func Run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if core.ActorFromContext(ctx) == "" {
+		// Attribute the changes and audited operations to the local user
+		ctx = core.ContextWithActor(ctx, localUser())
+	}
+	r := &runner{ctx: ctx, stdin: stdin, stdout: stdout, stderr: stderr}
+
+	fs := flag.NewFlagSet("powermem", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&r.configPath, "config", "", "load the configuration from a YAML, TOML or JSON `file`")
+	fs.StringVar(&r.envPath, "env", "", "load the configuration from this .env `file`")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: powermem [-config file | -env file] <command> [flags] [args]\n\nCommands:\n")
+		for _, cmd := range commands {
+			fmt.Fprintf(stderr, "  %-9s %s\n", cmd.name, cmd.summary)
+		}
+		fmt.Fprintf(stderr, "\nGlobal flags:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(stderr, "\nRun 'powermem <command> -h' for the flags of a command.\n")
+	}
+	if err := fs.Parse(args); err != nil {
+		return usageError(err)
+	}
+	if r.configPath != "" && r.envPath != "" {
+		fs.Usage()
+		return fmt.Errorf("%w: -config and -env are mutually exclusive", ErrUsage)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("%w: missing command", ErrUsage)
+	}
+
+	name := fs.Arg(0)
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(r, fs.Args()[1:])
+		}
+	}
+	fs.Usage()
+	return fmt.Errorf("%w: unknown command %q", ErrUsage, name)
+}
+
+// flagSet returns the flag set of a command.
+func (r *runner) flagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(r.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(r.stderr, "Usage: powermem %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses the flags of a command, which may be mixed with its
+// positional arguments, and returns the positional arguments.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, usageError(err)
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// usageError wraps a flag parsing error in ErrUsage, except flag.ErrHelp.
+func usageError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", ErrUsage, err)
+}
+
+// badUsage prints the usage of fs and returns an ErrUsage error.
+func badUsage(fs *flag.FlagSet, format string, args ...interface{}) error {
+	fs.Usage()
+	return fmt.Errorf("%w: %s", ErrUsage, fmt.Sprintf(format, args...))
+}
+
+// loadConfig loads the configuration selected by the global flags.
+func (r *runner) loadConfig() (*core.Config, error) {
+	switch {
+	case r.configPath != "":
+		return core.LoadConfigFromFile(r.configPath)
+	case r.envPath != "":
+		return core.LoadConfigFromEnvFile(r.envPath)
+	default:
+		return core.LoadConfigFromEnv()
+	}
+}
+
+// configSource describes where the configuration is loaded from.
+func (r *runner) configSource() string {
+	switch {
+	case r.configPath != "":
+		return r.configPath
+	case r.envPath != "":
+		return r.envPath
+	default:
+		return "environment"
+	}
+}
+
+// localUser returns the name of the user running the tool, or "" if it is
+// unknown.
+func localUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// openClient creates a memory client from the configuration.
+func (r *runner) openClient() (*core.Client, error) {
+	cfg, err := r.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return core.NewClient(cfg)
+}
+
+// closeClient closes client, reporting the error unless err is already set.
+func closeClient(client io.Closer, err *error) {
+	if closeErr := client.Close(); closeErr != nil && *err == nil {
+		*err = closeErr
+	}
+}
+
+// eachMemory calls fn for every memory matching opts, fetching them page by
+// page. Without a user ID in opts, it goes through the memories of every
+// user.
+func eachMemory(ctx context.Context, client *core.Client, fn func(memory *core.Memory) error, opts ...core.GetAllOption) error {
+	opts = append([]core.GetAllOption{core.WithAllowCrossUserForGetAll(true)}, opts...)
+	for offset := 0; ; offset += pageSize {
+		page, err := client.GetAll(ctx, append(opts, core.WithLimitForGetAll(pageSize), core.WithOffset(offset))...)
+		if err != nil {
+			return err
+		}
+		for _, memory := range page {
+			if err := fn(memory); err != nil {
+				return err
+			}
+		}
+		if len(page) < pageSize {
+			return nil
+		}
+	}
+}
+
+// memoryID returns the ID to show for memory: its UID if it has one.
+func memoryID(memory *core.Memory) string {
+	if memory.UID != "" {
+		return memory.UID
+	}
+	return strconv.FormatInt(memory.ID, 10)
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeMemories writes memories as a table, with their scores if withScore
+// is set.
+func writeMemories(w io.Writer, memories []*core.Memory, withScore bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if withScore {
+		fmt.Fprintln(tw, "ID\tUSER\tAGENT\tSCORE\tCONTENT")
+	} else {
+		fmt.Fprintln(tw, "ID\tUSER\tAGENT\tCREATED\tCONTENT")
+	}
+	for _, memory := range memories {
+		column := memory.CreatedAt.Format("2006-01-02 15:04")
+		if withScore {
+			column = strconv.FormatFloat(memory.Score, 'f', 3, 64)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			memoryID(memory), memory.UserID, memory.AgentID, column, truncate(memory.Content, 80))
+	}
+	return tw.Flush()
+}
+
+// truncate shortens text to at most n runes and puts it on one line.
+func truncate(text string, n int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= n {
+		return text
+	}
+	return string([]rune(text)[:n-1]) + "…"
+}
+
+// splitList splits a comma-separated flag value, dropping empty elements.
+func splitList(value string) []string {
+	var list []string
+	for _, element := range strings.Split(value, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			list = append(list, element)
+		}
+	}
+	return list
+}
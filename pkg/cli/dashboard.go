@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/dashboard"
+)
+
+// shutdownTimeout bounds the time given to in-flight requests when the
+// dashboard stops.
+const shutdownTimeout = 5 * time.Second
+
+// serveDashboard serves the web dashboard until the context is canceled.
+//
+// Profiles are shown if a profile store can be opened (see profiles);
+// otherwise the dashboard runs without them. With -api-keys, requests need
+// one of the keys of the file, a JSON array of dashboard.APIKey. With
+// -jwt-secret-file, they can carry a JSON Web Token signed with the secret
+// of the file instead (see dashboard.JWTConfig).
+func (r *runner) serveDashboard(args []string) (err error) {
+	fs := r.flagSet("dashboard", "")
+	addr := fs.String("addr", "localhost:8080", "listen `address`")
+	apiKeysPath := fs.String("api-keys", "", "require the API keys of a JSON `file`")
+	jwtSecretPath := fs.String("jwt-secret-file", "", "accept JSON Web Tokens signed with the HS256 secret of a `file`")
+	jwtIssuer := fs.String("jwt-issuer", "", "require JSON Web Tokens issued by `issuer`")
+	jwtAudience := fs.String("jwt-audience", "", "require JSON Web Tokens meant for `audience`")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return badUsage(fs, "unexpected argument %q", positional[0])
+	}
+	if *jwtSecretPath == "" && (*jwtIssuer != "" || *jwtAudience != "") {
+		return badUsage(fs, "-jwt-issuer and -jwt-audience need -jwt-secret-file")
+	}
+
+	cfg, err := r.loadConfig()
+	if err != nil {
+		return err
+	}
+	client, err := core.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeClient(client, &err)
+
+	dashboardConfig := &dashboard.Config{Memory: client}
+	if *apiKeysPath != "" {
+		if dashboardConfig.APIKeys, err = readAPIKeys(*apiKeysPath); err != nil {
+			return err
+		}
+	}
+	if *jwtSecretPath != "" {
+		secret, err := os.ReadFile(*jwtSecretPath)
+		if err != nil {
+			return err
+		}
+		dashboardConfig.JWT = &dashboard.JWTConfig{
+			Secret:   bytes.TrimSpace(secret),
+			Issuer:   *jwtIssuer,
+			Audience: *jwtAudience,
+		}
+	}
+	if cfg.Intelligence != nil {
+		dashboardConfig.DecayRate = cfg.Intelligence.DecayRate
+		dashboardConfig.DuplicateThreshold = cfg.Intelligence.DuplicateThreshold
+	}
+	if profiles, profilesErr := r.openProfiles(); profilesErr != nil {
+		fmt.Fprintf(r.stderr, "profiles disabled: %v\n", profilesErr)
+	} else {
+		defer closeClient(profiles, &err)
+		dashboardConfig.Profiles = profiles
+	}
+
+	handler, err := dashboard.NewHandler(dashboardConfig)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	fmt.Fprintf(r.stderr, "serving the dashboard on http://%s/\n", listener.Addr())
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.Serve(listener) }()
+	select {
+	case err := <-serveErr:
+		return err
+	case <-r.ctx.Done():
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// readAPIKeys reads the dashboard API keys of a JSON file.
+func readAPIKeys(path string) ([]dashboard.APIKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []dashboard.APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s: no API keys", path)
+	}
+	return keys, nil
+}
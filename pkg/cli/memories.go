@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// add adds a memory. The content is read from stdin if it is "-" or not
+// given.
+func (r *runner) add(args []string) (err error) {
+	fs := r.flagSet("add", "<content | ->")
+	userID := fs.String("user", "", "owner `user ID`")
+	agentID := fs.String("agent", "", "`agent ID`")
+	tags := fs.String("tags", "", "comma-separated `tags`")
+	metadata := fs.String("metadata", "", "metadata as a JSON `object`")
+	ttl := fs.Duration("ttl", 0, "expire the memory after this `duration`")
+	infer := fs.Bool("infer", false, "merge with duplicate memories")
+	asJSON := fs.Bool("json", false, "print the memory as JSON")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	content := strings.Join(positional, " ")
+	if content == "" || content == "-" {
+		data, err := io.ReadAll(r.stdin)
+		if err != nil {
+			return err
+		}
+		content = strings.TrimSpace(string(data))
+	}
+	if content == "" {
+		return badUsage(fs, "missing content")
+	}
+
+	opts := []core.AddOption{
+		core.WithUserID(*userID),
+		core.WithAgentID(*agentID),
+		core.WithInfer(*infer),
+	}
+	if *tags != "" {
+		opts = append(opts, core.WithTags(splitList(*tags)...))
+	}
+	if *metadata != "" {
+		var values map[string]interface{}
+		if err := json.Unmarshal([]byte(*metadata), &values); err != nil {
+			return badUsage(fs, "-metadata: %v", err)
+		}
+		opts = append(opts, core.WithMetadata(values))
+	}
+	if *ttl != 0 {
+		opts = append(opts, core.WithTTL(*ttl))
+	}
+
+	client, err := r.openClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient(client, &err)
+
+	memory, err := client.Add(r.ctx, content, opts...)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return writeJSON(r.stdout, memory)
+	}
+	_, err = fmt.Fprintf(r.stdout, "added memory %s\n", memoryID(memory))
+	return err
+}
+
+// search searches memories by similarity.
+func (r *runner) search(args []string) (err error) {
+	fs := r.flagSet("search", "<query>")
+	userID := fs.String("user", "", "only search the memories of this `user ID`")
+	agentID := fs.String("agent", "", "only search the memories of this `agent ID`")
+	tags := fs.String("tags", "", "only search memories with all these comma-separated `tags`")
+	limit := fs.Int("limit", 10, "maximum number of `results`")
+	minScore := fs.Float64("min-score", 0, "minimum similarity `score`")
+	asJSON := fs.Bool("json", false, "print the results as JSON")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	query := strings.Join(positional, " ")
+	if query == "" {
+		return badUsage(fs, "missing query")
+	}
+
+	opts := []core.SearchOption{
+		core.WithUserIDForSearch(*userID),
+		core.WithAgentIDForSearch(*agentID),
+		core.WithLimit(*limit),
+		core.WithMinScore(*minScore),
+		core.WithAllowCrossUser(*userID == ""),
+	}
+	if *tags != "" {
+		opts = append(opts, core.WithTagsForSearch(splitList(*tags)...))
+	}
+
+	client, err := r.openClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient(client, &err)
+
+	results, err := client.Search(r.ctx, query, opts...)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return writeJSON(r.stdout, results)
+	}
+	return writeMemories(r.stdout, results, true)
+}
+
+// get shows memories by ID.
+func (r *runner) get(args []string) (err error) {
+	fs := r.flagSet("get", "<id>...")
+	userID := fs.String("user", "", "require the memories to belong to this `user ID`")
+	agentID := fs.String("agent", "", "require the memories to belong to this `agent ID`")
+	asJSON := fs.Bool("json", false, "print the memories as JSON")
+	ids, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return badUsage(fs, "missing memory ID")
+	}
+
+	client, err := r.openClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient(client, &err)
+
+	opts := []core.GetOption{core.WithUserIDForGet(*userID), core.WithAgentIDForGet(*agentID)}
+	memories := make([]*core.Memory, 0, len(ids))
+	for _, id := range ids {
+		var memory *core.Memory
+		if numericID, parseErr := strconv.ParseInt(id, 10, 64); parseErr == nil {
+			memory, err = client.Get(r.ctx, numericID, opts...)
+		} else {
+			memory, err = client.GetByUID(r.ctx, id, opts...)
+		}
+		if err != nil {
+			return fmt.Errorf("memory %s: %w", id, err)
+		}
+		memories = append(memories, memory)
+	}
+
+	if *asJSON {
+		return writeJSON(r.stdout, memories)
+	}
+	return writeMemories(r.stdout, memories, false)
+}
+
+// delete deletes memories by ID.
+func (r *runner) delete(args []string) (err error) {
+	fs := r.flagSet("delete", "<id>...")
+	userID := fs.String("user", "", "require the memories to belong to this `user ID`")
+	agentID := fs.String("agent", "", "require the memories to belong to this `agent ID`")
+	ids, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return badUsage(fs, "missing memory ID")
+	}
+
+	client, err := r.openClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient(client, &err)
+
+	opts := []core.DeleteOption{core.WithUserIDForDelete(*userID), core.WithAgentIDForDelete(*agentID)}
+	for _, id := range ids {
+		if numericID, parseErr := strconv.ParseInt(id, 10, 64); parseErr == nil {
+			err = client.Delete(r.ctx, numericID, opts...)
+		} else {
+			err = client.DeleteByUID(r.ctx, id, opts...)
+		}
+		if err != nil {
+			return fmt.Errorf("memory %s: %w", id, err)
+		}
+		if _, err := fmt.Fprintf(r.stdout, "deleted memory %s\n", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
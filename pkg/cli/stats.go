@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	usermemory "github.com/oceanbase/powermem-go/pkg/user_memory"
+	usermemoryOceanBase "github.com/oceanbase/powermem-go/pkg/user_memory/oceanbase"
+	usermemoryPostgres "github.com/oceanbase/powermem-go/pkg/user_memory/postgres"
+	usermemorySQLite "github.com/oceanbase/powermem-go/pkg/user_memory/sqlite"
+)
+
+// Stats is the output of the stats command.
+type Stats struct {
+	// Total is the number of memories.
+	Total int `json:"total"`
+
+	// Expired is the number of memories past their expiration that have not
+	// been purged yet.
+	Expired int `json:"expired"`
+
+	// ByUser and ByAgent count the memories of each user and agent.
+	// Memories without a user or agent are counted under "".
+	ByUser  map[string]int `json:"by_user"`
+	ByAgent map[string]int `json:"by_agent"`
+
+	// Oldest and Newest are the creation times of the oldest and newest
+	// memories (nil if there are none).
+	Oldest *time.Time `json:"oldest,omitempty"`
+	Newest *time.Time `json:"newest,omitempty"`
+}
+
+// stats counts the memories by user and agent.
+func (r *runner) stats(args []string) (err error) {
+	fs := r.flagSet("stats", "")
+	userID := fs.String("user", "", "only count the memories of this `user ID`")
+	agentID := fs.String("agent", "", "only count the memories of this `agent ID`")
+	asJSON := fs.Bool("json", false, "print the statistics as JSON")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return badUsage(fs, "unexpected argument %q", positional[0])
+	}
+
+	client, err := r.openClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient(client, &err)
+
+	now := time.Now()
+	stats := &Stats{ByUser: make(map[string]int), ByAgent: make(map[string]int)}
+	err = eachMemory(r.ctx, client, func(memory *core.Memory) error {
+		stats.Total++
+		stats.ByUser[memory.UserID]++
+		stats.ByAgent[memory.AgentID]++
+		if memory.ExpiresAt != nil && !memory.ExpiresAt.After(now) {
+			stats.Expired++
+		}
+		createdAt := memory.CreatedAt
+		if stats.Oldest == nil || createdAt.Before(*stats.Oldest) {
+			stats.Oldest = &createdAt
+		}
+		if stats.Newest == nil || createdAt.After(*stats.Newest) {
+			stats.Newest = &createdAt
+		}
+		return nil
+	}, core.WithUserIDForGetAll(*userID), core.WithAgentIDForGetAll(*agentID))
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return writeJSON(r.stdout, stats)
+	}
+
+	tw := tabwriter.NewWriter(r.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Memories:\t%d\n", stats.Total)
+	fmt.Fprintf(tw, "Expired:\t%d\n", stats.Expired)
+	if stats.Oldest != nil {
+		fmt.Fprintf(tw, "Oldest:\t%s\n", stats.Oldest.Format(time.RFC3339))
+		fmt.Fprintf(tw, "Newest:\t%s\n", stats.Newest.Format(time.RFC3339))
+	}
+	writeCounts(tw, "USER", stats.ByUser)
+	writeCounts(tw, "AGENT", stats.ByAgent)
+	return tw.Flush()
+}
+
+// writeCounts writes counts under a header, by decreasing count.
+func writeCounts(tw *tabwriter.Writer, header string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	fmt.Fprintf(tw, "\n%s\tMEMORIES\n", header)
+	for _, key := range keys {
+		name := key
+		if name == "" {
+			name = "(none)"
+		}
+		fmt.Fprintf(tw, "%s\t%d\n", name, counts[key])
+	}
+}
+
+// profiles lists user profiles.
+func (r *runner) profiles(args []string) (err error) {
+	fs := r.flagSet("profiles", "")
+	userID := fs.String("user", "", "only show the profile of this `user ID`")
+	limit := fs.Int("limit", 100, "maximum number of `profiles`")
+	offset := fs.Int("offset", 0, "number of profiles to `skip`")
+	asJSON := fs.Bool("json", false, "print the profiles as JSON")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return badUsage(fs, "unexpected argument %q", positional[0])
+	}
+
+	client, err := r.openProfiles()
+	if err != nil {
+		return err
+	}
+	defer closeClient(client, &err)
+
+	profiles, err := client.GetProfiles(r.ctx, &usermemory.GetProfilesOptions{
+		UserID: *userID,
+		Limit:  *limit,
+		Offset: *offset,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return writeJSON(r.stdout, profiles)
+	}
+	tw := tabwriter.NewWriter(r.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "USER\tUPDATED\tTOPICS\tPROFILE")
+	for _, profile := range profiles {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n",
+			profile.UserID, profile.UpdatedAt.Format("2006-01-02 15:04"), len(profile.Topics), truncate(profile.ProfileContent, 80))
+	}
+	return tw.Flush()
+}
+
+// loadUserMemoryConfig loads the UserMemory configuration selected by the
+// global flags.
+//
+// A config file must have a user_memory.profile_store section. With a .env
+// file, profiles are read from the "user_profiles" table of the vector store
+// database.
+func (r *runner) loadUserMemoryConfig() (*usermemory.Config, error) {
+	if r.configPath != "" {
+		return usermemory.LoadConfigFromFile(r.configPath)
+	}
+
+	memoryConfig, err := r.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg := &usermemory.Config{MemoryConfig: memoryConfig, ProfileStoreType: memoryConfig.VectorStore.Provider}
+	switch store := memoryConfig.VectorStore; store.Provider {
+	case "sqlite":
+		profileConfig := &usermemorySQLite.Config{DBPath: "./powermem.db"}
+		if store.SQLite != nil && store.SQLite.DBPath != "" {
+			profileConfig.DBPath = store.SQLite.DBPath
+		}
+		cfg.ProfileStoreConfig = profileConfig
+	case "oceanbase":
+		profileConfig := &usermemoryOceanBase.Config{}
+		if store.OceanBase != nil {
+			profileConfig.Host = store.OceanBase.Host
+			profileConfig.Port = store.OceanBase.Port
+			profileConfig.User = store.OceanBase.User
+			profileConfig.Password = store.OceanBase.Password
+			profileConfig.DBName = store.OceanBase.DBName
+		}
+		cfg.ProfileStoreConfig = profileConfig
+	case "postgres":
+		profileConfig := &usermemoryPostgres.Config{}
+		if store.Postgres != nil {
+			profileConfig.Host = store.Postgres.Host
+			profileConfig.Port = store.Postgres.Port
+			profileConfig.User = store.Postgres.User
+			profileConfig.Password = store.Postgres.Password
+			profileConfig.DBName = store.Postgres.DBName
+			profileConfig.SSLMode = store.Postgres.SSLMode
+		}
+		cfg.ProfileStoreConfig = profileConfig
+	default:
+		return nil, fmt.Errorf("no profile store for vector store provider %q", store.Provider)
+	}
+	return cfg, nil
+}
+
+// openProfiles creates the UserMemory client that the profiles and dashboard
+// commands read profiles from.
+func (r *runner) openProfiles() (*usermemory.Client, error) {
+	cfg, err := r.loadUserMemoryConfig()
+	if err != nil {
+		return nil, err
+	}
+	return usermemory.NewClient(cfg)
+}
@@ -0,0 +1,347 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// maxLineSize is the maximum size of a line read by import.
+const maxLineSize = 16 << 20
+
+// export writes the memories as JSON lines, one core.Memory per line.
+func (r *runner) export(args []string) (err error) {
+	fs := r.flagSet("export", "")
+	output := fs.String("o", "", "write to this `file` instead of stdout")
+	userID := fs.String("user", "", "only export the memories of this `user ID`")
+	agentID := fs.String("agent", "", "only export the memories of this `agent ID`")
+	embeddings := fs.Bool("embeddings", false, "include the embeddings")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return badUsage(fs, "unexpected argument %q", positional[0])
+	}
+
+	client, err := r.openClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient(client, &err)
+
+	w := r.stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer closeClient(file, &err)
+		w = file
+	}
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+
+	count := 0
+	err = eachMemory(r.ctx, client, func(memory *core.Memory) error {
+		count++
+		if *embeddings {
+			return encoder.Encode(memory.WithEmbeddings())
+		}
+		return encoder.Encode(memory)
+	}, core.WithUserIDForGetAll(*userID), core.WithAgentIDForGetAll(*agentID))
+	if err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	if *output != "" {
+		fmt.Fprintf(r.stderr, "exported %d memories to %s\n", count, *output)
+	}
+	return nil
+}
+
+// importMemories adds the memories of a JSON lines file written by export.
+//
+// Memories get new IDs and are embedded again by the configured embedder.
+// Their user, agent, metadata, tags and expiration are kept; memories that
+// have already expired are skipped, and with -skip-duplicates those whose
+// user already has a memory with the same content. The import is recorded in
+// the audit log.
+func (r *runner) importMemories(args []string) (err error) {
+	fs := r.flagSet("import", "[file | -]")
+	userID := fs.String("user", "", "import the memories for this `user ID` instead of their own")
+	infer := fs.Bool("infer", false, "merge with duplicate memories")
+	skipDuplicates := fs.Bool("skip-duplicates", false, "skip memories whose user already has one with the same content")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return badUsage(fs, "unexpected argument %q", positional[1])
+	}
+
+	input := r.stdin
+	if len(positional) == 1 && positional[0] != "-" {
+		file, err := os.Open(positional[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	client, err := r.openClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient(client, &err)
+
+	source := "-"
+	if len(positional) == 1 {
+		source = positional[0]
+	}
+	err = client.RecordAudit(r.ctx, "Import", *userID, map[string]interface{}{
+		"file":            source,
+		"user_id":         *userID,
+		"infer":           *infer,
+		"skip_duplicates": *skipDuplicates,
+	})
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxLineSize)
+	var imported, skipped, duplicates int
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var memory core.Memory
+		if err := json.Unmarshal(scanner.Bytes(), &memory); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if *userID != "" {
+			memory.UserID = *userID
+		}
+		outcome, err := copyMemory(r.ctx, client, &memory, *infer, *skipDuplicates)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		switch outcome {
+		case copied:
+			imported++
+		case expired:
+			skipped++
+		case duplicate:
+			duplicates++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if *skipDuplicates {
+		_, err = fmt.Fprintf(r.stdout, "imported %d memories (%d expired, %d duplicates skipped)\n", imported, skipped, duplicates)
+		return err
+	}
+	_, err = fmt.Fprintf(r.stdout, "imported %d memories (%d expired skipped)\n", imported, skipped)
+	return err
+}
+
+// migrate copies the memories to the store of another configuration, for
+// example from SQLite to OceanBase, or to a store using another embedding
+// model. Memories are copied as by export and import. The migration is
+// recorded in the audit log of the target store.
+func (r *runner) migrate(args []string) (err error) {
+	fs := r.flagSet("migrate", "")
+	toConfig := fs.String("to", "", "config `file` (YAML, TOML or JSON) of the target store")
+	toEnv := fs.String("to-env", "", ".env `file` of the target store")
+	userID := fs.String("user", "", "only migrate the memories of this `user ID`")
+	agentID := fs.String("agent", "", "only migrate the memories of this `agent ID`")
+	dryRun := fs.Bool("dry-run", false, "count the memories to migrate without copying them")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return badUsage(fs, "unexpected argument %q", positional[0])
+	}
+	if (*toConfig == "") == (*toEnv == "") {
+		return badUsage(fs, "exactly one of -to and -to-env is required")
+	}
+
+	source, err := r.openClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient(source, &err)
+
+	var target *core.Client
+	if !*dryRun {
+		var targetConfig *core.Config
+		if *toConfig != "" {
+			targetConfig, err = core.LoadConfigFromFile(*toConfig)
+		} else {
+			targetConfig, err = core.LoadConfigFromEnvFile(*toEnv)
+		}
+		if err != nil {
+			return fmt.Errorf("target: %w", err)
+		}
+		if target, err = core.NewClient(targetConfig); err != nil {
+			return fmt.Errorf("target: %w", err)
+		}
+		defer closeClient(target, &err)
+
+		err = target.RecordAudit(r.ctx, "Migrate", *userID, map[string]interface{}{
+			"source":   r.configSource(),
+			"user_id":  *userID,
+			"agent_id": *agentID,
+		})
+		if err != nil {
+			return fmt.Errorf("target: %w", err)
+		}
+	}
+
+	var migrated, skipped int
+	err = eachMemory(r.ctx, source, func(memory *core.Memory) error {
+		if *dryRun {
+			migrated++
+			return nil
+		}
+		outcome, err := copyMemory(r.ctx, target, memory, false, false)
+		if err != nil {
+			return fmt.Errorf("memory %s: %w", memoryID(memory), err)
+		}
+		if outcome == copied {
+			migrated++
+		} else {
+			skipped++
+		}
+		return nil
+	}, core.WithUserIDForGetAll(*userID), core.WithAgentIDForGetAll(*agentID))
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		_, err = fmt.Fprintf(r.stdout, "would migrate %d memories\n", migrated)
+		return err
+	}
+	_, err = fmt.Fprintf(r.stdout, "migrated %d memories (%d expired skipped)\n", migrated, skipped)
+	return err
+}
+
+// copyOutcome is what copyMemory did with a memory.
+type copyOutcome int
+
+const (
+	// copied means that the memory was added.
+	copied copyOutcome = iota
+
+	// expired means that the memory was skipped as it has expired.
+	expired
+
+	// duplicate means that the memory was skipped as its user already has a
+	// memory with the same content.
+	duplicate
+)
+
+// copyMemory adds memory to client, keeping its user, agent, metadata, tags
+// and expiration, unless it has expired or, with skipDuplicates, its user
+// already has a memory with the same content.
+func copyMemory(ctx context.Context, client *core.Client, memory *core.Memory, infer, skipDuplicates bool) (copyOutcome, error) {
+	opts := []core.AddOption{
+		core.WithUserID(memory.UserID),
+		core.WithAgentID(memory.AgentID),
+		core.WithInfer(infer),
+	}
+	if len(memory.Metadata) > 0 {
+		opts = append(opts, core.WithMetadata(memory.Metadata))
+	}
+	if len(memory.Tags) > 0 {
+		opts = append(opts, core.WithTags(memory.Tags...))
+	}
+	if memory.ExpiresAt != nil {
+		if !memory.ExpiresAt.After(time.Now()) {
+			return expired, nil
+		}
+		opts = append(opts, core.WithExpiresAt(*memory.ExpiresAt))
+	}
+
+	if skipDuplicates {
+		existing, err := client.GetByHash(ctx, core.ContentHash(memory.Content),
+			core.WithUserIDForGet(memory.UserID), core.WithAgentIDForGet(memory.AgentID))
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return copied, err
+		}
+		// An empty user ID does not filter the lookup
+		if err == nil && existing.UserID == memory.UserID && existing.Content == memory.Content {
+			return duplicate, nil
+		}
+	}
+
+	if _, err := client.Add(ctx, memory.Content, opts...); err != nil {
+		return copied, err
+	}
+	return copied, nil
+}
+
+// snapshot writes a point-in-time snapshot of the memories to a new file
+// (see core.Client.Snapshot).
+func (r *runner) snapshot(args []string) (err error) {
+	fs := r.flagSet("snapshot", "<file>")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return badUsage(fs, "expected one snapshot file")
+	}
+
+	client, err := r.openClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient(client, &err)
+
+	if err := client.Snapshot(r.ctx, positional[0]); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(r.stdout, "wrote snapshot %s\n", positional[0])
+	return err
+}
+
+// restore replaces the memories with those of a snapshot (see
+// core.Client.RestoreSnapshot). The restore is recorded in the audit log.
+func (r *runner) restore(args []string) (err error) {
+	fs := r.flagSet("restore", "<file>")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return badUsage(fs, "expected one snapshot file")
+	}
+
+	client, err := r.openClient()
+	if err != nil {
+		return err
+	}
+	defer closeClient(client, &err)
+
+	if err := client.RestoreSnapshot(r.ctx, positional[0]); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(r.stdout, "restored snapshot %s\n", positional[0])
+	return err
+}
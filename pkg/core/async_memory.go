@@ -8,15 +8,23 @@ import (
 
 // AsyncClient provides asynchronous PowerMem operations.
 //
-// It wraps the synchronous Client and executes all operations in separate goroutines,
-// making it suitable for scenarios requiring concurrent processing of multiple operations.
+// It wraps the synchronous Client and executes operations on a bounded pool of
+// worker goroutines, making it suitable for scenarios requiring concurrent
+// processing of many operations without spawning a goroutine per call.
 //
 // All async methods return channels that will receive the results when operations complete.
-// The client tracks all goroutines and provides Wait() to ensure all operations finish.
+// When every worker is busy, operations wait in a queue. When the queue is full, the
+// backpressure policy applies: BackpressureBlock (default) makes async methods block
+// until space is available or ctx is done, BackpressureDrop makes them fail
+// immediately with ErrQueueFull.
+// The client tracks all pending operations and provides Wait() to ensure all operations finish.
 //
 // Example:
 //
-//	asyncClient, _ := core.NewAsyncClient(config)
+//	asyncClient, _ := core.NewAsyncClient(config,
+//	    core.WithAsyncWorkers(8),
+//	    core.WithAsyncQueueSize(500),
+//	)
 //	defer asyncClient.Close()
 //
 //	resultChan := asyncClient.AddAsync(ctx, "User likes Python", core.WithUserID("user_001"))
@@ -26,31 +34,108 @@ import (
 //	}
 type AsyncClient struct {
 	*Client
+
+	// wg tracks submitted operations that have not finished yet.
 	wg sync.WaitGroup
+
+	// tasks is the queue of operations waiting for a worker.
+	tasks chan func()
+
+	// backpressure is the policy applied when tasks is full.
+	backpressure BackpressurePolicy
+
+	// workers tracks the worker goroutines.
+	workers sync.WaitGroup
+
+	// closeMu guards closed and sends on tasks.
+	closeMu sync.RWMutex
+
+	// closed indicates that Close has been called.
+	closed bool
 }
 
 // NewAsyncClient creates a new asynchronous PowerMem client.
 //
 // Parameters:
 //   - cfg: PowerMem configuration
+//   - opts: Optional async options (WithAsyncWorkers, WithAsyncQueueSize, WithAsyncBackpressure)
 //
 // Returns:
 //   - *AsyncClient: The asynchronous client instance
 //   - error: Error if configuration is invalid or initialization fails
-func NewAsyncClient(cfg *Config) (*AsyncClient, error) {
+func NewAsyncClient(cfg *Config, opts ...AsyncOption) (*AsyncClient, error) {
 	client, err := NewClient(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	return &AsyncClient{
-		Client: client,
-	}, nil
+	return newAsyncClient(client, applyAsyncOptions(opts)), nil
 }
 
-// AddAsync adds a memory asynchronously.
+// newAsyncClient wraps client and starts the worker pool.
+func newAsyncClient(client *Client, opts *AsyncOptions) *AsyncClient {
+	ac := &AsyncClient{
+		Client:       client,
+		tasks:        make(chan func(), opts.QueueSize),
+		backpressure: opts.Backpressure,
+	}
+
+	ac.workers.Add(opts.Workers)
+	for i := 0; i < opts.Workers; i++ {
+		go func() {
+			defer ac.workers.Done()
+			for task := range ac.tasks {
+				task()
+			}
+		}()
+	}
+
+	return ac
+}
+
+// submit queues run for execution by a worker.
 //
-// The operation executes in a separate goroutine and returns results via a channel.
+// If the client is closed, ctx is done before the operation is queued, or the
+// queue is full under BackpressureDrop, fail is called with the corresponding error instead.
+func (ac *AsyncClient) submit(ctx context.Context, op string, run func(), fail func(error)) {
+	ac.closeMu.RLock()
+	defer ac.closeMu.RUnlock()
+
+	if ac.closed {
+		fail(NewMemoryError(op, ErrClientClosed))
+		return
+	}
+
+	ac.wg.Add(1)
+	task := func() {
+		defer ac.wg.Done()
+		run()
+	}
+
+	if ac.backpressure == BackpressureDrop {
+		select {
+		case ac.tasks <- task:
+		default:
+			ac.wg.Done()
+			fail(NewMemoryError(op, ErrQueueFull))
+		}
+		return
+	}
+
+	select {
+	case ac.tasks <- task:
+	case <-ctx.Done():
+		ac.wg.Done()
+		fail(NewMemoryError(op, ctx.Err()))
+	}
+}
+
+// QueueLength returns the number of operations waiting for a free worker.
+func (ac *AsyncClient) QueueLength() int {
+	return len(ac.tasks)
+}
+
+// AddAsync adds a memory asynchronously.
 //
 // Parameters:
 //   - ctx: Context for controlling request lifecycle
@@ -61,25 +146,41 @@ func NewAsyncClient(cfg *Config) (*AsyncClient, error) {
 //   - <-chan *MemoryResult: Channel that receives the result containing Memory and error
 func (ac *AsyncClient) AddAsync(ctx context.Context, content string, opts ...AddOption) <-chan *MemoryResult {
 	resultChan := make(chan *MemoryResult, 1)
-	ac.wg.Add(1)
-
-	go func() {
-		defer ac.wg.Done()
+	ac.submit(ctx, "AddAsync", func() {
 		memory, err := ac.Add(ctx, content, opts...)
-		resultChan <- &MemoryResult{
-			Memory: memory,
-			Error:  err,
-		}
+		resultChan <- &MemoryResult{Memory: memory, Error: err}
 		close(resultChan)
-	}()
+	}, func(err error) {
+		resultChan <- &MemoryResult{Error: err}
+		close(resultChan)
+	})
+	return resultChan
+}
 
+// IntelligentAddAsync performs an intelligent add asynchronously.
+//
+// Parameters:
+//   - ctx: Context for controlling request lifecycle
+//   - messages: Messages to process (string, []map[string]interface{}, or single map)
+//   - opts: Optional add options (UserID, AgentID, DryRun, etc.)
+//
+// Returns:
+//   - <-chan *AsyncIntelligentAddResult: Channel that receives the intelligent add result and error
+func (ac *AsyncClient) IntelligentAddAsync(ctx context.Context, messages interface{}, opts ...AddOption) <-chan *AsyncIntelligentAddResult {
+	resultChan := make(chan *AsyncIntelligentAddResult, 1)
+	ac.submit(ctx, "IntelligentAddAsync", func() {
+		result, err := ac.IntelligentAdd(ctx, messages, opts...)
+		resultChan <- &AsyncIntelligentAddResult{Result: result, Error: err}
+		close(resultChan)
+	}, func(err error) {
+		resultChan <- &AsyncIntelligentAddResult{Error: err}
+		close(resultChan)
+	})
 	return resultChan
 }
 
 // SearchAsync searches memories asynchronously.
 //
-// The operation executes in a separate goroutine and returns results via a channel.
-//
 // Parameters:
 //   - ctx: Context for controlling request lifecycle
 //   - query: Search query text
@@ -89,104 +190,85 @@ func (ac *AsyncClient) AddAsync(ctx context.Context, content string, opts ...Add
 //   - <-chan *AsyncSearchResult: Channel that receives search results containing Memories and error
 func (ac *AsyncClient) SearchAsync(ctx context.Context, query string, opts ...SearchOption) <-chan *AsyncSearchResult {
 	resultChan := make(chan *AsyncSearchResult, 1)
-	ac.wg.Add(1)
-
-	go func() {
-		defer ac.wg.Done()
+	ac.submit(ctx, "SearchAsync", func() {
 		memories, err := ac.Search(ctx, query, opts...)
-		resultChan <- &AsyncSearchResult{
-			Memories: memories,
-			Error:    err,
-		}
+		resultChan <- &AsyncSearchResult{Memories: memories, Error: err}
 		close(resultChan)
-	}()
-
+	}, func(err error) {
+		resultChan <- &AsyncSearchResult{Error: err}
+		close(resultChan)
+	})
 	return resultChan
 }
 
 // GetAsync retrieves a memory by ID asynchronously.
 //
-// The operation executes in a separate goroutine and returns results via a channel.
-//
 // Parameters:
 //   - ctx: Context for controlling request lifecycle
 //   - id: Memory ID
+//   - opts: Optional get options (UserID, AgentID for access control)
 //
 // Returns:
 //   - <-chan *MemoryResult: Channel that receives the result containing Memory and error
-func (ac *AsyncClient) GetAsync(ctx context.Context, id int64) <-chan *MemoryResult {
+func (ac *AsyncClient) GetAsync(ctx context.Context, id int64, opts ...GetOption) <-chan *MemoryResult {
 	resultChan := make(chan *MemoryResult, 1)
-	ac.wg.Add(1)
-
-	go func() {
-		defer ac.wg.Done()
-		memory, err := ac.Get(ctx, id)
-		resultChan <- &MemoryResult{
-			Memory: memory,
-			Error:  err,
-		}
+	ac.submit(ctx, "GetAsync", func() {
+		memory, err := ac.Get(ctx, id, opts...)
+		resultChan <- &MemoryResult{Memory: memory, Error: err}
 		close(resultChan)
-	}()
-
+	}, func(err error) {
+		resultChan <- &MemoryResult{Error: err}
+		close(resultChan)
+	})
 	return resultChan
 }
 
 // UpdateAsync updates a memory asynchronously.
 //
-// The operation executes in a separate goroutine and returns results via a channel.
-//
 // Parameters:
 //   - ctx: Context for controlling request lifecycle
 //   - id: Memory ID
 //   - content: New memory content
+//   - opts: Optional update options (UserID, AgentID, Metadata)
 //
 // Returns:
 //   - <-chan *MemoryResult: Channel that receives the result containing Memory and error
-func (ac *AsyncClient) UpdateAsync(ctx context.Context, id int64, content string) <-chan *MemoryResult {
+func (ac *AsyncClient) UpdateAsync(ctx context.Context, id int64, content string, opts ...UpdateOption) <-chan *MemoryResult {
 	resultChan := make(chan *MemoryResult, 1)
-	ac.wg.Add(1)
-
-	go func() {
-		defer ac.wg.Done()
-		memory, err := ac.Update(ctx, id, content)
-		resultChan <- &MemoryResult{
-			Memory: memory,
-			Error:  err,
-		}
+	ac.submit(ctx, "UpdateAsync", func() {
+		memory, err := ac.Update(ctx, id, content, opts...)
+		resultChan <- &MemoryResult{Memory: memory, Error: err}
 		close(resultChan)
-	}()
-
+	}, func(err error) {
+		resultChan <- &MemoryResult{Error: err}
+		close(resultChan)
+	})
 	return resultChan
 }
 
 // DeleteAsync deletes a memory asynchronously.
 //
-// The operation executes in a separate goroutine and returns results via a channel.
-//
 // Parameters:
 //   - ctx: Context for controlling request lifecycle
 //   - id: Memory ID
+//   - opts: Optional delete options (UserID, AgentID for access control)
 //
 // Returns:
 //   - <-chan error: Channel that receives error (nil if deletion succeeds)
-func (ac *AsyncClient) DeleteAsync(ctx context.Context, id int64) <-chan error {
+func (ac *AsyncClient) DeleteAsync(ctx context.Context, id int64, opts ...DeleteOption) <-chan error {
 	errChan := make(chan error, 1)
-	ac.wg.Add(1)
-
-	go func() {
-		defer ac.wg.Done()
-		err := ac.Delete(ctx, id)
+	ac.submit(ctx, "DeleteAsync", func() {
+		errChan <- ac.Delete(ctx, id, opts...)
+		close(errChan)
+	}, func(err error) {
 		errChan <- err
 		close(errChan)
-	}()
-
+	})
 	return errChan
 }
 
 // GetAllAsync retrieves all memories asynchronously.
 //
-// The operation executes in a separate goroutine and returns results via a channel.
-//
 // Parameters:
 //   - ctx: Context for controlling request lifecycle
 //   - opts: Optional retrieval options (UserID, AgentID, Limit, Offset, etc.)
@@ -195,25 +277,19 @@ func (ac *AsyncClient) DeleteAsync(ctx context.Context, id int64) <-chan error {
 //   - <-chan *AsyncGetAllResult: Channel that receives results containing Memories and error
 func (ac *AsyncClient) GetAllAsync(ctx context.Context, opts ...GetAllOption) <-chan *AsyncGetAllResult {
 	resultChan := make(chan *AsyncGetAllResult, 1)
-	ac.wg.Add(1)
-
-	go func() {
-		defer ac.wg.Done()
+	ac.submit(ctx, "GetAllAsync", func() {
 		memories, err := ac.GetAll(ctx, opts...)
-		resultChan <- &AsyncGetAllResult{
-			Memories: memories,
-			Error:    err,
-		}
+		resultChan <- &AsyncGetAllResult{Memories: memories, Error: err}
 		close(resultChan)
-	}()
-
+	}, func(err error) {
+		resultChan <- &AsyncGetAllResult{Error: err}
+		close(resultChan)
+	})
 	return resultChan
 }
 
 // DeleteAllAsync deletes all memories asynchronously.
 //
-// The operation executes in a separate goroutine and returns results via a channel.
-//
 // Parameters:
 //   - ctx: Context for controlling request lifecycle
 //   - opts: Optional deletion options (UserID, AgentID, etc.)
@@ -222,21 +298,85 @@ func (ac *AsyncClient) GetAllAsync(ctx context.Context, opts ...GetAllOption) <-
 //   - <-chan error: Channel that receives error (nil if deletion succeeds)
 func (ac *AsyncClient) DeleteAllAsync(ctx context.Context, opts ...DeleteAllOption) <-chan error {
 	errChan := make(chan error, 1)
-	ac.wg.Add(1)
-
-	go func() {
-		defer ac.wg.Done()
-		err := ac.DeleteAll(ctx, opts...)
+	ac.submit(ctx, "DeleteAllAsync", func() {
+		errChan <- ac.DeleteAll(ctx, opts...)
+		close(errChan)
+	}, func(err error) {
 		errChan <- err
 		close(errChan)
-	}()
-
+	})
 	return errChan
 }
 
+// BatchAddAsync adds multiple memories asynchronously.
+//
+// The whole batch is one queued operation; BatchAdd's own concurrency applies within it.
+//
+// Parameters:
+//   - ctx: Context for controlling request lifecycle
+//   - contents: Memory contents to add
+//   - opts: Optional add options applied to every memory
+//
+// Returns:
+//   - <-chan *AsyncBatchAddResult: Channel that receives the batch result and error
+func (ac *AsyncClient) BatchAddAsync(ctx context.Context, contents []string, opts ...AddOption) <-chan *AsyncBatchAddResult {
+	resultChan := make(chan *AsyncBatchAddResult, 1)
+	ac.submit(ctx, "BatchAddAsync", func() {
+		result, err := ac.BatchAdd(ctx, contents, opts...)
+		resultChan <- &AsyncBatchAddResult{Result: result, Error: err}
+		close(resultChan)
+	}, func(err error) {
+		resultChan <- &AsyncBatchAddResult{Error: err}
+		close(resultChan)
+	})
+	return resultChan
+}
+
+// BatchUpdateAsync updates multiple memories asynchronously.
+//
+// Parameters:
+//   - ctx: Context for controlling request lifecycle
+//   - items: Memories to update
+//
+// Returns:
+//   - <-chan *AsyncBatchUpdateResult: Channel that receives the batch result and error
+func (ac *AsyncClient) BatchUpdateAsync(ctx context.Context, items []BatchUpdateItem) <-chan *AsyncBatchUpdateResult {
+	resultChan := make(chan *AsyncBatchUpdateResult, 1)
+	ac.submit(ctx, "BatchUpdateAsync", func() {
+		result, err := ac.BatchUpdate(ctx, items)
+		resultChan <- &AsyncBatchUpdateResult{Result: result, Error: err}
+		close(resultChan)
+	}, func(err error) {
+		resultChan <- &AsyncBatchUpdateResult{Error: err}
+		close(resultChan)
+	})
+	return resultChan
+}
+
+// BatchDeleteAsync deletes multiple memories asynchronously.
+//
+// Parameters:
+//   - ctx: Context for controlling request lifecycle
+//   - ids: IDs of the memories to delete
+//
+// Returns:
+//   - <-chan *AsyncBatchDeleteResult: Channel that receives the batch result and error
+func (ac *AsyncClient) BatchDeleteAsync(ctx context.Context, ids []int64) <-chan *AsyncBatchDeleteResult {
+	resultChan := make(chan *AsyncBatchDeleteResult, 1)
+	ac.submit(ctx, "BatchDeleteAsync", func() {
+		result, err := ac.BatchDelete(ctx, ids)
+		resultChan <- &AsyncBatchDeleteResult{Result: result, Error: err}
+		close(resultChan)
+	}, func(err error) {
+		resultChan <- &AsyncBatchDeleteResult{Error: err}
+		close(resultChan)
+	})
+	return resultChan
+}
+
 // Wait waits for all asynchronous operations to complete.
 //
-// This method blocks until all goroutines started by async methods have finished.
+// This method blocks until every operation submitted so far has finished.
 // It should be called before program exit to ensure all operations complete.
 func (ac *AsyncClient) Wait() {
 	ac.wg.Wait()
@@ -244,9 +384,21 @@ func (ac *AsyncClient) Wait() {
 
 // Close closes the asynchronous client.
 //
-// It first waits for all asynchronous operations to complete, then closes the underlying client.
+// Close is graceful: it stops accepting new operations, drains every queued and
+// running operation, stops the workers, then closes the underlying client.
+// No accepted operation is dropped; operations submitted after Close fail with
+// ErrClientClosed. Calling Close more than once is a no-op.
 func (ac *AsyncClient) Close() error {
-	ac.Wait()
+	ac.closeMu.Lock()
+	if ac.closed {
+		ac.closeMu.Unlock()
+		return nil
+	}
+	ac.closed = true
+	close(ac.tasks)
+	ac.closeMu.Unlock()
+
+	ac.workers.Wait()
 	return ac.Client.Close()
 }
 
@@ -260,17 +412,15 @@ func (ac *AsyncClient) Close() error {
 // Returns:
 //   - <-chan error: Channel that receives an error if reset fails, or nil if successful
 func (ac *AsyncClient) ResetAsync(ctx context.Context) <-chan error {
-	resultChan := make(chan error, 1)
-	ac.wg.Add(1)
-
-	go func() {
-		defer ac.wg.Done()
-		err := ac.Reset(ctx)
-		resultChan <- err
-		close(resultChan)
-	}()
-
-	return resultChan
+	errChan := make(chan error, 1)
+	ac.submit(ctx, "ResetAsync", func() {
+		errChan <- ac.Reset(ctx)
+		close(errChan)
+	}, func(err error) {
+		errChan <- err
+		close(errChan)
+	})
+	return errChan
 }
 
 // MemoryResult contains the result of a memory operation.
@@ -299,3 +449,39 @@ type AsyncGetAllResult struct {
 	// Error is the error returned by the operation (nil if operation succeeded).
 	Error error
 }
+
+// AsyncIntelligentAddResult contains the result of an asynchronous IntelligentAdd operation.
+type AsyncIntelligentAddResult struct {
+	// Result is the intelligent add result (nil if error occurred).
+	Result *IntelligentAddResult
+
+	// Error is the error returned by the operation (nil if operation succeeded).
+	Error error
+}
+
+// AsyncBatchAddResult contains the result of an asynchronous BatchAdd operation.
+type AsyncBatchAddResult struct {
+	// Result is the batch add result (nil if error occurred).
+	Result *BatchAddResult
+
+	// Error is the error returned by the operation (nil if operation succeeded).
+	Error error
+}
+
+// AsyncBatchUpdateResult contains the result of an asynchronous BatchUpdate operation.
+type AsyncBatchUpdateResult struct {
+	// Result is the batch update result (nil if error occurred).
+	Result *BatchUpdateResult
+
+	// Error is the error returned by the operation (nil if operation succeeded).
+	Error error
+}
+
+// AsyncBatchDeleteResult contains the result of an asynchronous BatchDelete operation.
+type AsyncBatchDeleteResult struct {
+	// Result is the batch delete result (nil if error occurred).
+	Result *BatchDeleteResult
+
+	// Error is the error returned by the operation (nil if operation succeeded).
+	Error error
+}
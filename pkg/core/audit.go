@@ -0,0 +1,166 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// AuditEntry is an administrative operation read from the audit log (see
+// AuditConfig).
+type AuditEntry struct {
+	// Seq is the position of the entry in the log. Entries are returned in
+	// Seq order; pass the Seq of the last entry read to WithAuditAfter to
+	// read the next ones.
+	Seq int64 `json:"seq"`
+
+	// Time is when the operation was requested.
+	Time time.Time `json:"time"`
+
+	// Operation is the audited operation: the client method (e.g.
+	// "DeleteAll") or the operation passed to RecordAudit.
+	Operation string `json:"operation"`
+
+	// Actor is who requested the operation, as set with ContextWithActor
+	// (empty if unknown).
+	Actor string `json:"actor,omitempty"`
+
+	// UserID is the user whose data the operation affects (empty for
+	// operations on every user).
+	UserID string `json:"user_id,omitempty"`
+
+	// Params are the parameters of the operation.
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// RecordAudit records an administrative operation of the application, such
+// as an import or a migration, in the audit log, attributed to the actor of
+// ctx (see ContextWithActor). It does nothing if the audit log is disabled.
+//
+// DeleteAll, Reset, EraseUser, RestoreSnapshot, AddTeamMember,
+// RemoveTeamMember, ShareMemory, SearchAcrossUsers and the searches without
+// a user ID (Search, SearchWithDiagnostics, SearchByKeyword, SearchByEntity,
+// SearchMemories and SearchStream) record themselves.
+//
+// Parameters:
+//   - ctx: Context for cancellation, carrying the actor
+//   - operation: Name of the operation (required)
+//   - userID: User whose data the operation affects ("" for every user)
+//   - params: Parameters of the operation (optional)
+//
+// Returns an error if the entry cannot be recorded, in which case the
+// operation should not be run.
+//
+// Example:
+//
+//	ctx = core.ContextWithActor(ctx, "admin@example.com")
+//	err := client.RecordAudit(ctx, "Import", "user_001", map[string]interface{}{"file": path})
+func (c *Client) RecordAudit(ctx context.Context, operation, userID string, params map[string]interface{}) error {
+	if operation == "" {
+		return NewMemoryError("RecordAudit", fmt.Errorf("%w: operation is required", ErrInvalidInput))
+	}
+
+	ctx, err := c.begin(ctx, "RecordAudit")
+	if err != nil {
+		return err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if err := c.audit(ctx, operation, userID, params); err != nil {
+		return NewMemoryError("RecordAudit", err)
+	}
+	return nil
+}
+
+// auditEnabled reports whether administrative operations are recorded. It
+// must be called with the client locked.
+func (c *Client) auditEnabled() bool {
+	return c.config.Audit != nil && c.config.Audit.Enabled
+}
+
+// audit records operation in the audit log if it is enabled. It must be
+// called with the client locked, before the operation runs.
+func (c *Client) audit(ctx context.Context, operation, userID string, params map[string]interface{}) error {
+	if !c.auditEnabled() {
+		return nil
+	}
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+	err = c.storage.AppendAudit(ctx, &storage.AuditEntry{
+		Operation: operation,
+		Actor:     ActorFromContext(ctx),
+		UserID:    userID,
+		Params:    encoded,
+	})
+	if err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+	return nil
+}
+
+// ListAuditEntries returns the entries of the audit log after WithAuditAfter
+// (from the beginning by default), in Seq order. Entries recorded by every
+// client sharing the vector store are returned, whether or not this client
+// has the audit log enabled.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - opts: Optional parameters (After, Operations, Actor, UserID, Limit)
+//
+// Returns at most Limit entries (default 100), or an error.
+//
+// Example:
+//
+//	entries, err := client.ListAuditEntries(ctx,
+//	    core.WithAuditOperations("DeleteAll", "EraseUser"),
+//	    core.WithAuditLimit(500),
+//	)
+func (c *Client) ListAuditEntries(ctx context.Context, opts ...AuditOption) ([]*AuditEntry, error) {
+	ctx, err := c.begin(ctx, "ListAuditEntries")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	auditOpts := applyAuditOptions(opts)
+	stored, err := c.storage.ListAudit(ctx, &storage.ListAuditOptions{
+		After:      auditOpts.After,
+		Operations: auditOpts.Operations,
+		Actor:      auditOpts.Actor,
+		UserID:     auditOpts.UserID,
+		Limit:      auditOpts.Limit,
+	})
+	if err != nil {
+		return nil, NewMemoryError("ListAuditEntries", err)
+	}
+
+	entries := make([]*AuditEntry, 0, len(stored))
+	for _, s := range stored {
+		entry := &AuditEntry{
+			Seq:       s.Seq,
+			Time:      s.CreatedAt,
+			Operation: s.Operation,
+			Actor:     s.Actor,
+			UserID:    s.UserID,
+		}
+		if err := json.Unmarshal(s.Params, &entry.Params); err != nil {
+			return nil, NewMemoryError("ListAuditEntries", fmt.Errorf("invalid audit entry %d: %w", s.Seq, err))
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
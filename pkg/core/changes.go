@@ -0,0 +1,221 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// Change is a memory event read from the change log (see ChangeLogConfig).
+type Change struct {
+	// Seq is the position of the change in the log. Changes are returned in
+	// Seq order; pass the Seq of the last change processed to
+	// WithChangesAfter to resume from there.
+	Seq int64 `json:"seq"`
+
+	// Event is the recorded event.
+	Event
+}
+
+// ChangeResult is an element of a change stream: a change, or the error
+// that ended the stream.
+type ChangeResult struct {
+	// Change is the next change (nil if Error is set).
+	Change *Change
+
+	// Error is the error that ended the stream (if any).
+	Error error
+}
+
+// SubscribeChanges streams the changes of the change log, as they are
+// recorded by this client and by any other client sharing the vector store.
+//
+// It is meant for read replicas, cache invalidation and live UI updates.
+// Changes are only recorded by clients with ChangeLog enabled, in the order
+// of their mutations. By default the stream starts at the beginning of the
+// log; to follow only new changes, start after LastChangeSeq.
+//
+// The log is polled every WithChangesPollInterval (default 1s); changes made
+// by this client are delivered without waiting for the next poll.
+//
+// Parameters:
+//   - ctx: Context for cancellation; cancelling it ends the stream
+//   - opts: Optional parameters (After, UserID, AgentID, Types, Limit, PollInterval)
+//
+// Returns a channel that receives the changes. It is closed when ctx is
+// done, when the client shuts down, or after a ChangeResult with an error.
+// A consumer that stops reading blocks the stream, not the client.
+//
+// Example:
+//
+//	seq, err := client.LastChangeSeq(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	for result := range client.SubscribeChanges(ctx,
+//	    core.WithChangesAfter(seq),
+//	    core.WithUserIDForChanges("user_001"),
+//	) {
+//	    if result.Error != nil {
+//	        return result.Error
+//	    }
+//	    cache.Invalidate(result.Change.MemoryID)
+//	}
+func (c *Client) SubscribeChanges(ctx context.Context, opts ...ChangesOption) <-chan *ChangeResult {
+	results := make(chan *ChangeResult, 1)
+	changesOpts := applyChangesOptions(opts)
+	stopped := c.stopped()
+
+	go func() {
+		defer close(results)
+
+		ticker := time.NewTicker(changesOpts.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			// Take the signal before reading, so that no append is missed
+			changed := c.events.changeSignal()
+
+			changes, err := c.listChanges(ctx, "SubscribeChanges", changesOpts)
+			if err != nil {
+				select {
+				case results <- &ChangeResult{Error: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+			for _, change := range changes {
+				select {
+				case results <- &ChangeResult{Change: change}:
+					changesOpts.After = change.Seq
+				case <-ctx.Done():
+					return
+				case <-stopped:
+					return
+				}
+			}
+			if len(changes) == changesOpts.Limit {
+				// More changes are waiting
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-stopped:
+				return
+			case <-changed:
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return results
+}
+
+// ListChanges returns the changes of the change log after WithChangesAfter
+// (from the beginning by default), in Seq order.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - opts: Optional parameters (After, UserID, AgentID, Types, Limit)
+//
+// Returns at most Limit changes (default 100), or an error.
+//
+// Example:
+//
+//	changes, err := client.ListChanges(ctx, core.WithChangesAfter(lastSeq), core.WithChangesLimit(500))
+func (c *Client) ListChanges(ctx context.Context, opts ...ChangesOption) ([]*Change, error) {
+	return c.listChanges(ctx, "ListChanges", applyChangesOptions(opts))
+}
+
+// listChanges reads a page of the change log for operation op.
+func (c *Client) listChanges(ctx context.Context, op string, opts *ChangesOptions) ([]*Change, error) {
+	ctx, err := c.begin(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	types := make([]string, len(opts.Types))
+	for i, t := range opts.Types {
+		types[i] = string(t)
+	}
+	stored, err := c.storage.ListChanges(ctx, &storage.ListChangesOptions{
+		After:   opts.After,
+		UserID:  opts.UserID,
+		AgentID: opts.AgentID,
+		Types:   types,
+		Limit:   opts.Limit,
+	})
+	if err != nil {
+		return nil, NewMemoryError(op, err)
+	}
+
+	changes := make([]*Change, 0, len(stored))
+	for _, s := range stored {
+		change := &Change{Seq: s.Seq}
+		if err := json.Unmarshal(s.Payload, &change.Event); err != nil {
+			return nil, NewMemoryError(op, fmt.Errorf("invalid change %d: %w", s.Seq, err))
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// LastChangeSeq returns the Seq of the latest change in the change log, or 0
+// if it is empty.
+//
+// Example:
+//
+//	seq, err := client.LastChangeSeq(ctx)
+func (c *Client) LastChangeSeq(ctx context.Context) (int64, error) {
+	ctx, err := c.begin(ctx, "LastChangeSeq")
+	if err != nil {
+		return 0, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	seq, err := c.storage.LastChangeSeq(ctx)
+	if err != nil {
+		return 0, NewMemoryError("LastChangeSeq", err)
+	}
+	return seq, nil
+}
+
+// PurgeChanges deletes the changes recorded before the given time from the
+// change log, which otherwise grows with every mutation.
+//
+// Subscribers behind the purged changes miss them: keep the changes for
+// longer than consumers may lag.
+//
+// Returns the number of deleted changes.
+//
+// Example:
+//
+//	deleted, err := client.PurgeChanges(ctx, time.Now().Add(-7*24*time.Hour))
+func (c *Client) PurgeChanges(ctx context.Context, before time.Time) (int64, error) {
+	ctx, err := c.begin(ctx, "PurgeChanges")
+	if err != nil {
+		return 0, err
+	}
+	defer c.end()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	deleted, err := c.storage.PurgeChanges(ctx, before)
+	if err != nil {
+		return 0, NewMemoryError("PurgeChanges", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,328 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/oceanbase/powermem-go/pkg/embedder"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// chunkedContent is content split into chunks, with the embedding of each chunk.
+type chunkedContent struct {
+	chunks     []string
+	embeddings [][]float64
+}
+
+// checkContentSize returns ErrContentTooLarge if content is longer than
+// ChunkingConfig.MaxContentSize.
+func (c *Client) checkContentSize(content string) error {
+	if c.config.Chunking == nil || c.config.Chunking.MaxContentSize == 0 {
+		return nil
+	}
+	if len(content) > c.config.Chunking.MaxContentSize {
+		return fmt.Errorf("%w: %d bytes, maximum %d", ErrContentTooLarge, len(content), c.config.Chunking.MaxContentSize)
+	}
+	return nil
+}
+
+// embedContent returns the embedding of content by provider, and its chunks
+// if it is longer than ChunkingConfig.ChunkSize (nil otherwise).
+//
+// The embedding of chunked content is the normalized mean of the embeddings
+// of its chunks, which are computed in one batch.
+func (c *Client) embedContent(ctx context.Context, provider embedder.Provider, content string) ([]float64, *chunkedContent, error) {
+	cfg := c.config.Chunking
+	if cfg == nil || cfg.ChunkSize == 0 || len(content) <= cfg.ChunkSize {
+		embedding, err := provider.Embed(ctx, content)
+		return embedding, nil, err
+	}
+
+	chunks := SplitContent(content, cfg.ChunkSize, cfg.ChunkOverlap)
+	embeddings, err := provider.EmbedBatch(ctx, chunks)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(embeddings) != len(chunks) {
+		return nil, nil, fmt.Errorf("%w: got %d embeddings for %d chunks", ErrEmbeddingFailed, len(embeddings), len(chunks))
+	}
+	return meanEmbedding(embeddings), &chunkedContent{chunks: chunks, embeddings: embeddings}, nil
+}
+
+// insertChunks stores the chunks of parent, which share its owner, metadata,
+// tags and expiration.
+//
+// The chunks already stored are deleted if one cannot be stored.
+func (c *Client) insertChunks(ctx context.Context, parent *Memory, content *chunkedContent) error {
+	for i, chunk := range content.chunks {
+		uid, err := c.newUID()
+		if err != nil {
+			return err
+		}
+
+		metadata := make(map[string]interface{}, len(parent.Metadata))
+		for k, v := range parent.Metadata {
+			metadata[k] = v
+		}
+
+		memory := &Memory{
+			ID:                c.snowflakeNode.Generate().Int64(),
+			UID:               uid,
+			Version:           1,
+			ParentID:          parent.ID,
+			UserID:            parent.UserID,
+			AgentID:           parent.AgentID,
+			Content:           chunk,
+			Embedding:         content.embeddings[i],
+			Metadata:          metadata,
+			RetentionStrength: parent.RetentionStrength,
+			Tags:              parent.Tags,
+			ExpiresAt:         parent.ExpiresAt,
+		}
+		if err := c.insertNew(ctx, memory); err != nil {
+			_ = c.deleteChunks(ctx, parent.ID, parent.UserID)
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteChunks deletes the chunks of the memory id owned by userID.
+func (c *Client) deleteChunks(ctx context.Context, id int64, userID string) error {
+	if id == 0 {
+		// A zero ParentID would not restrict DeleteAll
+		return nil
+	}
+	return c.storage.DeleteAll(ctx, &storage.DeleteAllOptions{UserID: userID, ParentID: id})
+}
+
+// updateChunkMetadata sets the metadata of the chunks of parent to its metadata.
+func (c *Client) updateChunkMetadata(ctx context.Context, parent *storage.Memory) error {
+	chunks, err := c.getChunks(ctx, parent)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range chunks {
+		metadata := make(map[string]interface{}, len(parent.Metadata))
+		for k, v := range parent.Metadata {
+			metadata[k] = v
+		}
+		if _, err := c.storage.Update(ctx, chunk.ID, chunk.Content, chunk.Embedding, &storage.UpdateOptions{
+			UserID:   parent.UserID,
+			Metadata: metadata,
+			Now:      c.now(),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getChunks returns the chunks of parent in content order.
+func (c *Client) getChunks(ctx context.Context, parent *storage.Memory) ([]*storage.Memory, error) {
+	chunks, err := c.storage.GetAll(ctx, &storage.GetAllOptions{
+		UserID:   parent.UserID,
+		ParentID: parent.ID,
+		// Every chunk holds at least one byte of the content
+		Limit: len(parent.Content),
+		Now:   c.now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Chunks are stored in content order, with increasing IDs
+	sort.Slice(chunks, func(i, j int) bool { return chunks[i].ID < chunks[j].ID })
+	return chunks, nil
+}
+
+// resolveChunks replaces the chunks in search results, which are ordered by
+// score, with the memories they are chunks of. A memory takes the score of
+// its best result and is returned once.
+//
+// Memories in seen (if not nil) are skipped, and the returned ones are added
+// to it, so that consecutive batches of results can be resolved.
+func (c *Client) resolveChunks(ctx context.Context, memories []*storage.Memory, seen map[int64]bool) ([]*storage.Memory, error) {
+	var parentIDs []int64
+	for _, memory := range memories {
+		if memory.ParentID != 0 {
+			parentIDs = append(parentIDs, memory.ParentID)
+		}
+	}
+	if len(parentIDs) == 0 && seen == nil {
+		return memories, nil
+	}
+
+	parents := make(map[int64]*storage.Memory, len(parentIDs))
+	if len(parentIDs) > 0 {
+		found, err := c.storage.GetMany(ctx, parentIDs, &storage.GetOptions{Now: c.now()})
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range found {
+			parents[parent.ID] = parent
+		}
+	}
+
+	if seen == nil {
+		seen = make(map[int64]bool, len(memories))
+	}
+	result := make([]*storage.Memory, 0, len(memories))
+	for _, memory := range memories {
+		if memory.ParentID != 0 {
+			parent, ok := parents[memory.ParentID]
+			if !ok {
+				// Deleted since the search
+				continue
+			}
+			resolved := *parent
+			resolved.Score = memory.Score
+			memory = &resolved
+		}
+		if seen[memory.ID] {
+			continue
+		}
+		seen[memory.ID] = true
+		result = append(result, memory)
+	}
+	return result, nil
+}
+
+// GetChunks retrieves the chunks of a memory whose content was split by
+// chunking (see ChunkingConfig), in content order.
+//
+// Returns an empty list if the memory is not chunked, or an error if the
+// memory is not found or access is denied.
+//
+// Example:
+//
+//	chunks, err := client.GetChunks(ctx, memoryID, core.WithUserIDForGet("user_001"))
+func (c *Client) GetChunks(ctx context.Context, id int64, opts ...GetOption) ([]*Memory, error) {
+	ctx, err := c.begin(ctx, "GetChunks")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	getOpts := applyGetOptions(opts)
+
+	parent, err := c.storage.Get(ctx, id, &storage.GetOptions{
+		UserID:  getOpts.UserID,
+		AgentID: getOpts.AgentID,
+		Now:     c.now(),
+	})
+	if err != nil {
+		return nil, NewMemoryError("GetChunks", err)
+	}
+
+	chunks, err := c.getChunks(ctx, parent)
+	if err != nil {
+		return nil, NewMemoryError("GetChunks", err)
+	}
+
+	return fromStorageMemories(chunks), nil
+}
+
+// SplitContent splits content into chunks of at most size bytes, each
+// repeating up to overlap bytes of the end of the previous one. This is how
+// ChunkingConfig splits long content.
+//
+// Chunks end at the last paragraph break, line break, sentence end or space
+// in their second half if there is one, and never inside a UTF-8 sequence.
+// Surrounding whitespace is trimmed and blank chunks are dropped. A size of
+// 0 returns content as one chunk.
+//
+// Example:
+//
+//	chunks := core.SplitContent(document, 2000, 200)
+func SplitContent(content string, size, overlap int) []string {
+	if size <= 0 {
+		size = len(content)
+	}
+
+	var chunks []string
+	start := 0
+	for {
+		end := start + size
+		if end >= len(content) {
+			if chunk := strings.TrimSpace(content[start:]); chunk != "" {
+				chunks = append(chunks, chunk)
+			}
+			return chunks
+		}
+		end = chunkEnd(content, start, end)
+		if chunk := strings.TrimSpace(content[start:end]); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+
+		next := end
+		if overlap > 0 {
+			next = end - overlap
+			if next <= start {
+				next = end
+			}
+			for next < end && !utf8.RuneStart(content[next]) {
+				next++
+			}
+			// Start the overlap at a word
+			if i := strings.IndexAny(content[next:end], " \t\n"); i >= 0 && next+i+1 < end {
+				next += i + 1
+			}
+		}
+		start = next
+	}
+}
+
+// chunkEnd returns where the chunk of content starting at start and ending
+// at most at end should end.
+func chunkEnd(content string, start, end int) int {
+	window := content[start:end]
+	half := len(window) / 2
+	for _, sep := range []string{"\n\n", "\n", ". ", "! ", "? ", "。", " "} {
+		if i := strings.LastIndex(window, sep); i > 0 && i >= half {
+			return start + i + len(sep)
+		}
+	}
+
+	for end > start && !utf8.RuneStart(content[end]) {
+		end--
+	}
+	if end == start {
+		// A rune longer than the chunk size
+		_, n := utf8.DecodeRuneInString(content[start:])
+		end = start + n
+	}
+	return end
+}
+
+// meanEmbedding returns the normalized mean of embeddings.
+func meanEmbedding(embeddings [][]float64) []float64 {
+	mean := make([]float64, len(embeddings[0]))
+	for _, embedding := range embeddings {
+		for i := range mean {
+			if i < len(embedding) {
+				mean[i] += embedding[i]
+			}
+		}
+	}
+
+	var norm float64
+	for _, v := range mean {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+	if norm == 0 {
+		return mean
+	}
+	for i := range mean {
+		mean[i] /= norm
+	}
+	return mean
+}
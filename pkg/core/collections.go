@@ -0,0 +1,166 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oceanbase/powermem-go/pkg/storage/collections"
+)
+
+// ContextWithCollection returns a context whose operations run on the named
+// collection of the database instead of the collection of the client
+// configuration (see CreateCollection).
+//
+// Every operation, including the change feed, PurgeExpired and EraseUser,
+// applies to the collection of its context only.
+//
+// Example:
+//
+//	shop := core.ContextWithCollection(ctx, "shop")
+//	memory, err := client.Add(shop, "Prefers express delivery", core.WithUserID("user_001"))
+//	results, err := client.Search(shop, "delivery", core.WithUserIDForSearch("user_001"))
+func ContextWithCollection(ctx context.Context, name string) context.Context {
+	return collections.ContextWithCollection(ctx, name)
+}
+
+// CreateCollection creates a collection of memories in the database of the
+// client, e.g. one per product, so that one client serves them all instead of
+// one client per collection. Operations run on it with ContextWithCollection.
+//
+// Collection names are lower case letters, digits and underscores (at most
+// 63 characters); the collection of the client configuration is the default
+// collection. Creating a collection that exists is not an error.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Collection name, e.g. "shop"
+//
+// Returns ErrInvalidInput for an invalid name, and ErrInvalidConfig if the
+// vector store does not support collections (the routing store).
+//
+// Example:
+//
+//	if err := client.CreateCollection(ctx, "shop"); err != nil {
+//	    return err
+//	}
+//	_, err := client.Add(core.ContextWithCollection(ctx, "shop"), "Prefers express delivery")
+func (c *Client) CreateCollection(ctx context.Context, name string) error {
+	ctx, err := c.begin(ctx, "CreateCollection")
+	if err != nil {
+		return err
+	}
+	defer c.end()
+
+	store, err := c.collections("CreateCollection")
+	if err != nil {
+		return err
+	}
+	if err := collections.ValidateName(name); err != nil {
+		return NewMemoryError("CreateCollection", fmt.Errorf("%w: %v", ErrInvalidInput, err))
+	}
+	if err := store.Create(ctx, name); err != nil {
+		return NewMemoryError("CreateCollection", err)
+	}
+	return nil
+}
+
+// ListCollections returns the names of the collections of the database of
+// the client, sorted, including the default collection.
+//
+// Example:
+//
+//	names, err := client.ListCollections(ctx) // ["memories", "shop", "support"]
+func (c *Client) ListCollections(ctx context.Context) ([]string, error) {
+	ctx, err := c.begin(ctx, "ListCollections")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	store, err := c.collections("ListCollections")
+	if err != nil {
+		return nil, err
+	}
+	names, err := store.List(ctx)
+	if err != nil {
+		return nil, NewMemoryError("ListCollections", err)
+	}
+	return names, nil
+}
+
+// DropCollection permanently deletes a collection: its memories and its
+// change log. Events are not published for the deleted memories. Dropping a
+// collection that does not exist is not an error.
+//
+// WARNING: This operation cannot be undone.
+//
+// Returns ErrInvalidInput for an invalid name or the default collection.
+//
+// Example:
+//
+//	err := client.DropCollection(ctx, "shop")
+func (c *Client) DropCollection(ctx context.Context, name string) error {
+	ctx, err := c.begin(ctx, "DropCollection")
+	if err != nil {
+		return err
+	}
+	defer c.end()
+
+	store, err := c.collections("DropCollection")
+	if err != nil {
+		return err
+	}
+	if err := collections.ValidateName(name); err != nil {
+		return NewMemoryError("DropCollection", fmt.Errorf("%w: %v", ErrInvalidInput, err))
+	}
+	if name == store.DefaultName() {
+		return NewMemoryError("DropCollection", fmt.Errorf("%w: cannot drop the default collection %q", ErrInvalidInput, name))
+	}
+	if err := store.Drop(ctx, name); err != nil {
+		return NewMemoryError("DropCollection", err)
+	}
+	return nil
+}
+
+// collections returns the collections store of the client.
+func (c *Client) collections(op string) (*collections.Client, error) {
+	store, ok := c.storage.(*collections.Client)
+	if !ok {
+		return nil, NewMemoryError(op, fmt.Errorf("%w: the %s vector store does not support collections", ErrInvalidConfig, c.config.VectorStore.resolvedProvider()))
+	}
+	return store, nil
+}
+
+// collectionName returns the collection name of resolved store settings (see
+// resolveStoreConfig), or "" if the store does not support collections.
+func collectionName(typed interface{}) string {
+	switch c := typed.(type) {
+	case *SQLiteConfig:
+		return c.CollectionName
+	case *OceanBaseConfig:
+		return c.CollectionName
+	case *PostgresConfig:
+		return c.CollectionName
+	}
+	return ""
+}
+
+// withCollectionName returns a copy of resolved store settings using the
+// collection name.
+func withCollectionName(typed interface{}, name string) interface{} {
+	switch c := typed.(type) {
+	case *SQLiteConfig:
+		copied := *c
+		copied.CollectionName = name
+		return &copied
+	case *OceanBaseConfig:
+		copied := *c
+		copied.CollectionName = name
+		return &copied
+	case *PostgresConfig:
+		copied := *c
+		copied.CollectionName = name
+		return &copied
+	}
+	return typed
+}
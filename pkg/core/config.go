@@ -4,11 +4,17 @@ package core
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+	"github.com/oceanbase/powermem-go/pkg/secrets"
+	"github.com/oceanbase/powermem-go/pkg/storage/replication"
 )
 
 // Config contains the complete configuration for a PowerMem client.
@@ -36,9 +42,7 @@ import (
 //	    },
 //	    VectorStore: core.VectorStoreConfig{
 //	        Provider: "sqlite",
-//	        Config: map[string]interface{}{
-//	            "db_path": "./memories.db",
-//	        },
+//	        SQLite:   &core.SQLiteConfig{DBPath: "./memories.db"},
 //	    },
 //	}
 type Config struct {
@@ -48,6 +52,11 @@ type Config struct {
 	// Embedder contains embedding provider configuration.
 	Embedder EmbedderConfig `json:"embedder"`
 
+	// EmbedderRoutes embed some memories with other embedders than
+	// Embedder, chosen by memory type or language (optional, see
+	// EmbedderRoute).
+	EmbedderRoutes []EmbedderRoute `json:"embedder_routes,omitempty"`
+
 	// VectorStore contains vector store configuration.
 	VectorStore VectorStoreConfig `json:"vector_store"`
 
@@ -56,11 +65,291 @@ type Config struct {
 
 	// AgentMemory contains multi-agent memory configuration (optional).
 	AgentMemory *AgentMemoryConfig `json:"agent_memory,omitempty"`
+
+	// IDType selects which IDs are assigned to new memories:
+	// "snowflake" (int64 IDs only) or "uuid" (an additional UUIDv7 string ID).
+	// Default: "snowflake"
+	IDType IDType `json:"id_type,omitempty"`
+
+	// IDConflictRetries is the number of times the ID of a new memory is
+	// regenerated when the vector store already holds a memory with that
+	// ID, for instance after the clock moved backwards. Negative disables
+	// the retries. Default: 0 (3 retries)
+	IDConflictRetries int `json:"id_conflict_retries,omitempty"`
+
+	// NodeID is the snowflake node of the client, 1-1023, part of the IDs of
+	// new memories so that clients sharing a store do not assign the same
+	// IDs. Replicas (see Replication) must set one other than the default,
+	// unique among the replicas and writers of the remote store. Default: 1
+	NodeID int64 `json:"node_id,omitempty"`
+
+	// SkipExactDuplicates makes Add return the existing memory of the user
+	// whose content is identical, found by content hash, instead of
+	// embedding the content and running the intelligent add pipeline.
+	// Default: false
+	SkipExactDuplicates bool `json:"skip_exact_duplicates,omitempty"`
+
+	// Webhooks receive the memory events (see Client.Subscribe) as JSON
+	// POST requests (optional).
+	Webhooks []WebhookConfig `json:"webhooks,omitempty"`
+
+	// ChangeLog records the memory events in the vector store for
+	// SubscribeChanges (optional).
+	ChangeLog *ChangeLogConfig `json:"change_log,omitempty"`
+
+	// Audit records the administrative operations in the vector store for
+	// ListAuditEntries (optional).
+	Audit *AuditConfig `json:"audit,omitempty"`
+
+	// Language detects the language of memories and translates search
+	// queries for cross-lingual search (optional).
+	Language *LanguageConfig `json:"language,omitempty"`
+
+	// Staleness flags old preferences and facts in search results, so that
+	// agents re-ask instead of trusting them (optional).
+	Staleness *StalenessConfig `json:"staleness,omitempty"`
+
+	// QueryCache caches the embeddings of search queries, so that a query
+	// repeated every turn is embedded once (optional).
+	QueryCache *QueryCacheConfig `json:"query_cache,omitempty"`
+
+	// Chunking limits the content size of memories and splits long content
+	// into separately embedded chunks (optional).
+	Chunking *ChunkingConfig `json:"chunking,omitempty"`
+
+	// InjectionGuard screens search results for prompt injections
+	// (optional).
+	InjectionGuard *InjectionGuardConfig `json:"injection_guard,omitempty"`
+
+	// Replication synchronizes the vector store with a remote store for
+	// Sync (optional).
+	Replication *ReplicationConfig `json:"replication,omitempty"`
+
+	// Secrets resolves LLMConfig.APIKeySecret and EmbedderConfig.APIKeySecret
+	// (optional). Secrets are cached and refreshed lazily every
+	// secrets.DefaultRefreshInterval; pass a secrets.NewCache to use another
+	// interval.
+	Secrets secrets.Provider `json:"-"`
+
+	// Clock is the time source of retention and lifecycle decisions: the
+	// Ebbinghaus decay, ranking, review schedules, the timestamps of
+	// memories, merges, feedback, promotions and events, and the expiration
+	// of memories added with a TTL, including working memory, by reads and
+	// PurgeExpired (optional). If nil, intelligence.SystemClock is used. Tests
+	// and simulations pass an intelligence.ManualClock to advance time
+	// without sleeping.
+	Clock intelligence.Clock `json:"-"`
+}
+
+// WebhookConfig configures an endpoint notified of memory events.
+//
+// Each event is POSTed as a JSON Event, with its type in the
+// X-PowerMem-Event header. Deliveries are asynchronous and retried on network
+// errors and 429 or 5xx responses; see package webhook.
+//
+// Example:
+//
+//	Webhooks: []core.WebhookConfig{{
+//	    URL:    "https://example.com/hooks/powermem",
+//	    Secret: os.Getenv("POWERMEM_WEBHOOK_SECRET"),
+//	    Events: []core.EventType{core.EventDeleted, core.EventForgotten},
+//	}}
+type WebhookConfig struct {
+	// URL is the http or https endpoint receiving the events.
+	URL string `json:"url"`
+
+	// Secret signs the requests with HMAC-SHA256 in the X-PowerMem-Signature
+	// header (optional, see webhook.Sign).
+	Secret string `json:"secret,omitempty"`
+
+	// Events are the event types to send (optional, all events if empty).
+	Events []EventType `json:"events,omitempty"`
+
+	// TimeoutSeconds is the timeout of one request. Default: 10
+	TimeoutSeconds float64 `json:"timeout_seconds,omitempty"`
+
+	// MaxRetries is the number of retries of a failed delivery (negative to
+	// disable retries). Default: 3
+	MaxRetries int `json:"max_retries,omitempty"`
+}
+
+// ChangeLogConfig configures the change log: an outbox table next to the
+// memories (the collection name with a "_changes" suffix) recording every
+// memory event, so that other processes can follow the changes with
+// SubscribeChanges.
+//
+// Example:
+//
+//	ChangeLog: &core.ChangeLogConfig{Enabled: true}
+type ChangeLogConfig struct {
+	// Enabled records the memory events in the change log.
+	Enabled bool `json:"enabled"`
+}
+
+// AuditConfig configures the audit log: an append-only table next to the
+// memories (the collection name with an "_audit" suffix) recording who
+// requested the administrative operations (DeleteAll, Reset, EraseUser,
+// RestoreSnapshot, AddTeamMember, RemoveTeamMember, ShareMemory,
+// SearchAcrossUsers, the searches without a user ID and the operations of
+// RecordAudit), when, and with which parameters.
+//
+// Example:
+//
+//	Audit: &core.AuditConfig{Enabled: true}
+type AuditConfig struct {
+	// Enabled records the administrative operations in the audit log. An
+	// operation whose entry cannot be recorded is not run.
+	Enabled bool `json:"enabled"`
+}
+
+// ReplicationConfig configures the replication of the vector store, e.g.
+// the SQLite store of an edge agent, to a remote store shared with other
+// replicas, e.g. a central OceanBase or PostgreSQL store (see Client.Sync
+// and package replication). The agent works offline and exchanges its
+// changes with the remote store when it syncs.
+//
+// Changes are read from the change logs of both stores, so replication
+// requires ChangeLog, and the clients writing to the remote store must
+// enable it too.
+//
+// Example:
+//
+//	ChangeLog: &core.ChangeLogConfig{Enabled: true},
+//	Replication: &core.ReplicationConfig{
+//	    Remote: core.VectorStoreConfig{
+//	        Provider: "postgres",
+//	        Postgres: &core.PostgresConfig{DSN: os.Getenv("POSTGRES_DSN")},
+//	    },
+//	    StatePath: "./powermem.sync.json",
+//	}
+type ReplicationConfig struct {
+	// Remote is the remote vector store. It cannot be a routing store.
+	Remote VectorStoreConfig `json:"remote"`
+
+	// Conflict settles the memories changed both locally and remotely since
+	// the previous sync: "newest" (the latest update wins), "local" or
+	// "remote". Default: "newest"
+	Conflict string `json:"conflict,omitempty"`
+
+	// StatePath is the file keeping the position of the replication in the
+	// change logs across restarts (optional, every restart re-reads both
+	// change logs without it).
+	StatePath string `json:"state_path,omitempty"`
+}
+
+// ChunkingConfig configures content size limits and chunking.
+//
+// Content longer than ChunkSize is split into chunks of at most ChunkSize
+// bytes, preferably at paragraph, sentence or word boundaries. Each chunk is
+// embedded and stored as a memory linked to the memory holding the whole
+// content (Memory.ParentID). Searches match the chunks but return the whole
+// memory, and the chunks are updated and deleted with it.
+//
+// Example:
+//
+//	Chunking: &core.ChunkingConfig{
+//	    MaxContentSize: 1 << 20,
+//	    ChunkSize:      2000,
+//	    ChunkOverlap:   200,
+//	}
+type ChunkingConfig struct {
+	// MaxContentSize is the maximum content size in bytes; longer content is
+	// rejected with ErrContentTooLarge (0 for no limit).
+	MaxContentSize int `json:"max_content_size,omitempty"`
+
+	// ChunkSize is the maximum chunk size in bytes (0 to disable chunking).
+	ChunkSize int `json:"chunk_size,omitempty"`
+
+	// ChunkOverlap is the number of bytes repeated from the end of a chunk
+	// at the start of the next one. Must be less than ChunkSize.
+	ChunkOverlap int `json:"chunk_overlap,omitempty"`
+}
+
+// LanguageConfig configures language detection and cross-lingual search.
+//
+// Languages are ISO 639-1 codes such as "en" or "zh", stored in
+// metadata["language"] and matched by WithLanguage and
+// EmbedderRoute.Languages. Memories in different languages retrieve each
+// other poorly with a monolingual embedder; either embed them with a
+// multilingual embedder (see EmbedderRoute) or translate the queries.
+//
+// Example:
+//
+//	Language: &core.LanguageConfig{
+//	    Detect:           true,
+//	    TranslateQueries: []string{"en", "zh"},
+//	}
+type LanguageConfig struct {
+	// Detect stores the language of added memories, detected from their
+	// script and stopwords (see intelligence.DetectLanguage), unless it is
+	// given by WithLanguageForAdd or metadata["language"].
+	Detect bool `json:"detect"`
+
+	// TranslateQueries are the languages search queries are translated
+	// into with the LLM, besides the language of the query. The
+	// translations are searched too, and the results merged by score.
+	// Searches with WithLanguage are not translated. Default: none
+	TranslateQueries []string `json:"translate_queries,omitempty"`
+}
+
+// StalenessConfig configures the staleness policy: search results of some
+// memory types (preferences and facts by default) that were last confirmed
+// longer ago than MaxAgeDays get Memory.Stale, and optionally a
+// Memory.VerificationPrompt the agent can ask the user, instead of being
+// trusted as they are.
+//
+// A memory is confirmed when it is added or updated, and by ConfirmMemory.
+//
+// Example:
+//
+//	Staleness: &core.StalenessConfig{
+//	    Enabled:            true,
+//	    MaxAgeDays:         180,
+//	    VerificationPrompt: true,
+//	}
+type StalenessConfig struct {
+	// Enabled flags stale search results.
+	Enabled bool `json:"enabled"`
+
+	// MaxAgeDays is the number of days after which an unconfirmed memory is
+	// stale. Default: 90
+	MaxAgeDays float64 `json:"max_age_days,omitempty"`
+
+	// MemoryTypes are the memory types that go stale (see WithMemoryType).
+	// Default: preference and fact
+	MemoryTypes []string `json:"memory_types,omitempty"`
+
+	// VerificationPrompt sets Memory.VerificationPrompt on stale results: a
+	// prompt telling the agent to ask the user whether the memory is still
+	// true.
+	VerificationPrompt bool `json:"verification_prompt,omitempty"`
+}
+
+// QueryCacheConfig configures the cache of query embeddings. Searches look
+// up the embedding of their query, lowercased and with its whitespace
+// collapsed, before calling the embedder, so that a query such as "what do
+// you know about me", issued on every turn, does not call the embedding API
+// each time. Embeddings of stored content are not cached.
+//
+// Example:
+//
+//	QueryCache: &core.QueryCacheConfig{Enabled: true, TTLSeconds: 30}
+type QueryCacheConfig struct {
+	// Enabled caches query embeddings.
+	Enabled bool `json:"enabled"`
+
+	// Size is the maximum number of cached embeddings; the least recently
+	// used one is evicted first. Default: 1000
+	Size int `json:"size,omitempty"`
+
+	// TTLSeconds is how long an embedding stays cached. Default: 60
+	TTLSeconds float64 `json:"ttl_seconds,omitempty"`
 }
 
 // LLMConfig contains configuration for the LLM provider.
 //
-// Supported providers: openai, qwen, anthropic, deepseek, ollama
+// Supported providers: openai, qwen, anthropic, deepseek, ollama, and mock
+// for tests (a deterministic LLM; Parameters["responses"] scripts its answers)
 //
 // Example:
 //
@@ -71,12 +360,16 @@ type Config struct {
 //	    BaseURL:  "https://api.openai.com/v1",
 //	}
 type LLMConfig struct {
-	// Provider is the LLM provider name (openai, qwen, anthropic, deepseek, ollama).
+	// Provider is the LLM provider name (openai, qwen, anthropic, deepseek, ollama, mock).
 	Provider string `json:"provider"`
 
 	// APIKey is the API key for the LLM provider.
 	APIKey string `json:"api_key"`
 
+	// APIKeySecret is the name of a secret holding the API key, resolved
+	// through Config.Secrets instead of APIKey (optional).
+	APIKeySecret string `json:"api_key_secret,omitempty"`
+
 	// Model is the model name to use (e.g., "gpt-4", "qwen-plus").
 	Model string `json:"model"`
 
@@ -85,11 +378,79 @@ type LLMConfig struct {
 
 	// Parameters contains additional provider-specific parameters (optional).
 	Parameters map[string]interface{} `json:"parameters,omitempty"`
+
+	// Fallbacks are the LLM providers to use when this one fails, in order
+	// (optional). Fallback entries cannot have fallbacks or routing of their
+	// own.
+	Fallbacks []LLMConfig `json:"fallbacks,omitempty"`
+
+	// Routing configures failover between Provider and Fallbacks (optional,
+	// only used with Fallbacks).
+	Routing *LLMRoutingConfig `json:"routing,omitempty"`
+
+	// Recording records LLM interactions to a golden file or replays them
+	// (optional, for tests).
+	Recording *LLMRecordingConfig `json:"recording,omitempty"`
+}
+
+// LLMRecordingConfig configures recording and replaying of LLM interactions,
+// so that tests of logic driven by LLM responses are reproducible without
+// live API calls.
+//
+// Example:
+//
+//	LLM: core.LLMConfig{
+//	    Provider:  "openai",
+//	    APIKey:    os.Getenv("OPENAI_API_KEY"),
+//	    Recording: &core.LLMRecordingConfig{Path: "testdata/intelligent_add.json", Mode: os.Getenv("LLM_RECORD_MODE")},
+//	}
+type LLMRecordingConfig struct {
+	// Path is the golden file holding the recorded interactions.
+	Path string `json:"path"`
+
+	// Mode is "replay" (answer from the golden file only), "record" (call the
+	// provider and rewrite the golden file) or "auto" (replay recorded
+	// requests and record new ones). Default: "replay"
+	Mode string `json:"mode,omitempty"`
+}
+
+// LLMRoutingConfig configures how requests are routed across the primary LLM
+// provider and its fallbacks.
+//
+// A provider that fails FailureThreshold times in a row is skipped for
+// CooldownSeconds, and requests fail over to the next provider. Providers
+// that are cooling down are only tried when all others have failed.
+//
+// Example:
+//
+//	LLM: core.LLMConfig{
+//	    Provider: "openai",
+//	    APIKey:   "sk-...",
+//	    Fallbacks: []core.LLMConfig{
+//	        {Provider: "deepseek", APIKey: "sk-..."},
+//	        {Provider: "ollama", Model: "llama3.1:8b"},
+//	    },
+//	    Routing: &core.LLMRoutingConfig{Strategy: "latency"},
+//	}
+type LLMRoutingConfig struct {
+	// Strategy orders the healthy providers: "priority" (primary first, then
+	// the fallbacks in order) or "latency" (lowest average latency first).
+	// Default: "priority"
+	Strategy string `json:"strategy,omitempty"`
+
+	// FailureThreshold is the number of consecutive failures after which a
+	// provider is skipped. Default: 1
+	FailureThreshold int `json:"failure_threshold,omitempty"`
+
+	// CooldownSeconds is how long a failing provider is skipped before it is
+	// tried again. Default: 30
+	CooldownSeconds float64 `json:"cooldown_seconds,omitempty"`
 }
 
 // EmbedderConfig contains configuration for the embedding provider.
 //
-// Supported providers: openai, qwen, huggingface, ollama
+// Supported providers: openai, qwen, and mock for tests (deterministic
+// hash-based embeddings)
 //
 // Example:
 //
@@ -100,12 +461,16 @@ type LLMConfig struct {
 //	    Dimensions: 1536,
 //	}
 type EmbedderConfig struct {
-	// Provider is the embedding provider name (openai, qwen, huggingface, ollama).
+	// Provider is the embedding provider name (openai, qwen, mock).
 	Provider string `json:"provider"`
 
 	// APIKey is the API key for the embedding provider.
 	APIKey string `json:"api_key"`
 
+	// APIKeySecret is the name of a secret holding the API key, resolved
+	// through Config.Secrets instead of APIKey (optional).
+	APIKeySecret string `json:"api_key_secret,omitempty"`
+
 	// Model is the embedding model name (e.g., "text-embedding-ada-002", "text-embedding-v4").
 	Model string `json:"model"`
 
@@ -115,32 +480,109 @@ type EmbedderConfig struct {
 	// Dimensions is the dimension of the embedding vectors (e.g., 1536, 1024).
 	Dimensions int `json:"dimensions,omitempty"`
 
+	// TruncateDimensions, if positive, truncates the embeddings of the
+	// provider to this smaller dimension before they are stored or
+	// searched with, e.g. 1536 to 512, and rescales them to unit length
+	// (Matryoshka truncation, see embedder.Truncate). It is the dimension of
+	// the vector store. Default: 0 (embeddings are kept whole)
+	TruncateDimensions int `json:"truncate_dimensions,omitempty"`
+
 	// Parameters contains additional provider-specific parameters (optional).
 	Parameters map[string]interface{} `json:"parameters,omitempty"`
 }
 
+// vectorDimensions returns the dimension of the embeddings stored in the
+// vector store: TruncateDimensions if set, Dimensions otherwise.
+func (c EmbedderConfig) vectorDimensions() int {
+	if c.TruncateDimensions > 0 {
+		return c.TruncateDimensions
+	}
+	return c.Dimensions
+}
+
+// EmbedderRoute embeds the memories of some memory types or languages with
+// another embedder than Config.Embedder, e.g. code with a code embedding
+// model and Chinese content with a multilingual one.
+//
+// A new memory is embedded by the first route listing its memory type (see
+// WithMemoryType), or else by the first route listing its language
+// (metadata["language"]), or else by Config.Embedder. The route is recorded
+// in metadata["embedder"], and the memory is re-embedded by the same route
+// when its content changes.
+//
+// Each embedder has its own vector space, so Search embeds the query with
+// every embedder that may have embedded matching memories, searches the
+// memories of each, and merges the results by score. Filters on
+// memory_type, or on both memory_type and language, select a single
+// embedder. All embedders must produce vectors of the dimension of
+// Config.Embedder, using TruncateDimensions if needed.
+//
+// Example:
+//
+//	config.EmbedderRoutes = []core.EmbedderRoute{{
+//	    Name:        "code",
+//	    MemoryTypes: []string{"code"},
+//	    Embedder:    core.EmbedderConfig{Provider: "openai", Model: "code-embedding-model", Dimensions: 1536},
+//	}, {
+//	    Name:      "multilingual",
+//	    Languages: []string{"zh", "ja"},
+//	    Embedder:  core.EmbedderConfig{Provider: "qwen", Model: "text-embedding-v4", Dimensions: 1536},
+//	}}
+type EmbedderRoute struct {
+	// Name identifies the route in metadata["embedder"]. Renaming a route
+	// moves its memories back to Config.Embedder's vector space.
+	Name string `json:"name"`
+
+	// MemoryTypes are the memory types routed to Embedder.
+	MemoryTypes []string `json:"memory_types,omitempty"`
+
+	// Languages are the languages routed to Embedder, as stored in
+	// metadata["language"] (e.g. "zh").
+	Languages []string `json:"languages,omitempty"`
+
+	// Embedder is the embedder of the route.
+	Embedder EmbedderConfig `json:"embedder"`
+}
+
 // VectorStoreConfig contains configuration for the vector store.
 //
-// Supported providers: oceanbase, sqlite, postgres
+// Supported providers: oceanbase, sqlite, postgres, and routing, which routes
+// each user to one of several of these stores (see RoutingConfig).
+//
+// The provider settings are set in the typed field of the provider; zero
+// fields (or a nil field) use the defaults documented on SQLiteConfig,
+// OceanBaseConfig and PostgresConfig. If Provider is empty, it is inferred
+// from the one typed field that is set.
+//
+// In JSON the settings are kept under "config", as in earlier versions:
+//
+//	{"provider": "sqlite", "config": {"db_path": "./memories.db"}}
 //
 // Example:
 //
 //	storeConfig := core.VectorStoreConfig{
 //	    Provider: "sqlite",
-//	    Config: map[string]interface{}{
-//	        "db_path":         "./memories.db",
-//	        "collection_name": "memories",
+//	    SQLite: &core.SQLiteConfig{
+//	        DBPath:         "./memories.db",
+//	        CollectionName: "memories",
 //	    },
 //	}
 type VectorStoreConfig struct {
-	// Provider is the vector store provider name (oceanbase, sqlite, postgres).
-	Provider string `json:"provider"`
+	// Provider is the vector store provider name (oceanbase, sqlite, postgres,
+	// routing).
+	Provider string
+
+	// SQLite contains the settings of the "sqlite" provider.
+	SQLite *SQLiteConfig
+
+	// OceanBase contains the settings of the "oceanbase" provider.
+	OceanBase *OceanBaseConfig
 
-	// Config contains provider-specific configuration.
-	// For SQLite: db_path, collection_name, embedding_model_dims
-	// For OceanBase: host, port, user, password, db_name, collection_name, embedding_model_dims
-	// For PostgreSQL: host, port, user, password, db_name, collection_name, embedding_model_dims, ssl_mode
-	Config map[string]interface{} `json:"config"`
+	// Postgres contains the settings of the "postgres" provider.
+	Postgres *PostgresConfig
+
+	// Routing contains the settings of the "routing" provider.
+	Routing *RoutingConfig
 }
 
 // IntelligenceConfig contains configuration for intelligent memory management.
@@ -205,6 +647,105 @@ type IntelligenceConfig struct {
 	// when intelligent processing fails (e.g., no facts extracted).
 	// Default: false
 	FallbackToSimpleAdd bool `json:"fallback_to_simple_add,omitempty"`
+
+	// MergeStrategy selects how duplicate memories are merged:
+	// "concatenate", "keep_newest", "keep_longest", or "llm_merge".
+	// Default: "concatenate"
+	MergeStrategy string `json:"merge_strategy,omitempty"`
+
+	// MergePrompt is a custom prompt for the "llm_merge" strategy (optional).
+	// The placeholders {existing} and {new} are replaced with the memory contents.
+	MergePrompt string `json:"merge_prompt,omitempty"`
+
+	// MinFactConfidence is the minimum confidence (0.0-1.0) an extracted fact
+	// must have to be stored by IntelligentAdd. Facts below it are dropped.
+	// Default: 0 (keep all facts)
+	MinFactConfidence float64 `json:"min_fact_confidence,omitempty"`
+
+	// Ranking weights the components of the search score (optional).
+	// If nil, search results are ranked by keyword relevance multiplied by
+	// Ebbinghaus retention.
+	Ranking *RankingConfig `json:"ranking,omitempty"`
+
+	// AgentPolicies overrides the retention settings for the memories of
+	// some agents, by agent ID (optional). An agent's policy takes
+	// precedence over the policy of the memory type.
+	AgentPolicies map[string]RetentionPolicy `json:"agent_policies,omitempty"`
+
+	// MemoryTypePolicies overrides the retention settings for the memories
+	// of some types (see WithMemoryType), by memory type (optional).
+	MemoryTypePolicies map[string]RetentionPolicy `json:"memory_type_policies,omitempty"`
+}
+
+// RetentionPolicy overrides the retention settings of IntelligenceConfig for
+// the memories of an agent or a memory type, e.g. so that a task manager
+// agent forgets quickly while preferences are kept long-term. Zero fields
+// keep the settings of IntelligenceConfig (or of the memory type's policy,
+// for an agent's policy).
+//
+// The policy applies to the Ebbinghaus retention used to rank search
+// results and to the reinforcement of Feedback.
+//
+// Example:
+//
+//	Intelligence: &core.IntelligenceConfig{
+//	    Enabled:   true,
+//	    DecayRate: 0.1,
+//	    AgentPolicies: map[string]core.RetentionPolicy{
+//	        "task_manager": {DecayRate: 1.5},
+//	    },
+//	    MemoryTypePolicies: map[string]core.RetentionPolicy{
+//	        "preference": {DecayRate: 0.01, ReinforcementFactor: 0.5},
+//	    },
+//	}
+type RetentionPolicy struct {
+	// DecayRate overrides IntelligenceConfig.DecayRate.
+	DecayRate float64 `json:"decay_rate,omitempty"`
+
+	// ReinforcementFactor overrides IntelligenceConfig.ReinforcementFactor.
+	ReinforcementFactor float64 `json:"reinforcement_factor,omitempty"`
+
+	// WorkingThreshold overrides IntelligenceConfig.WorkingThreshold.
+	WorkingThreshold float64 `json:"working_threshold,omitempty"`
+
+	// ShortTermThreshold overrides IntelligenceConfig.ShortTermThreshold.
+	ShortTermThreshold float64 `json:"short_term_threshold,omitempty"`
+
+	// LongTermThreshold overrides IntelligenceConfig.LongTermThreshold.
+	LongTermThreshold float64 `json:"long_term_threshold,omitempty"`
+}
+
+// RankingConfig weights the components combined into the final search score
+// when intelligent memory is enabled.
+//
+// The final score is the weighted average of the vector similarity, recency
+// (time since last update), importance (evaluated on add) and Ebbinghaus
+// retention. The component scores are returned in Memory.ScoreComponents.
+// If all weights are zero, the default weights are used.
+//
+// Example:
+//
+//	Ranking: &core.RankingConfig{
+//	    SimilarityWeight:     0.7,
+//	    RecencyWeight:        0.3,
+//	    RecencyHalfLifeHours: 24 * 30,
+//	}
+type RankingConfig struct {
+	// SimilarityWeight is the weight of the vector similarity score. Default: 0.6
+	SimilarityWeight float64 `json:"similarity_weight"`
+
+	// RecencyWeight is the weight of the recency score. Default: 0.15
+	RecencyWeight float64 `json:"recency_weight"`
+
+	// ImportanceWeight is the weight of the importance score. Default: 0.15
+	ImportanceWeight float64 `json:"importance_weight"`
+
+	// RetentionWeight is the weight of the Ebbinghaus retention. Default: 0.1
+	RetentionWeight float64 `json:"retention_weight"`
+
+	// RecencyHalfLifeHours is the age in hours at which the recency score
+	// drops to 0.5. Default: 168 (7 days)
+	RecencyHalfLifeHours float64 `json:"recency_half_life_hours,omitempty"`
 }
 
 // AgentMemoryConfig contains configuration for multi-agent memory management.
@@ -244,10 +785,28 @@ type AgentMemoryConfig struct {
 //   - DATABASE_PROVIDER (sqlite, oceanbase, postgres)
 //   - OCEANBASE_HOST, OCEANBASE_PORT, OCEANBASE_USER, OCEANBASE_PASSWORD, etc.
 //   - SQLITE_PATH, SQLITE_COLLECTION, etc.
+//   - SQLITE_BUSY_TIMEOUT_SECONDS (or SQLITE_TIMEOUT), SQLITE_BUSY_RETRIES,
+//     SQLITE_SYNCHRONOUS, SQLITE_CHECKPOINT_INTERVAL_SECONDS
+//   - SQLITE_LOCKING (none, advisory, single_writer)
+//   - SQLITE_SEARCH_WORKERS, SQLITE_CLUSTERS, SQLITE_PROBE_CLUSTERS,
+//     SQLITE_VECTOR_CACHE
 //   - POSTGRES_HOST, POSTGRES_PORT, POSTGRES_USER, POSTGRES_PASSWORD, etc.
+//   - OCEANBASE_DSN, POSTGRES_DSN (primary connection strings)
+//   - OCEANBASE_READ_DSNS, POSTGRES_READ_DSNS (read replicas, separated by ";")
+//   - POSTGRES_MAX_REPLICA_LAG_SECONDS
+//   - POSTGRES_QUANTIZATION (none, halfvec, bit), POSTGRES_RERANK_FACTOR
+//   - OCEANBASE_DISABLE_VECTOR_INDEX, OCEANBASE_HNSW_M,
+//     OCEANBASE_HNSW_EF_CONSTRUCTION, OCEANBASE_HNSW_EF_SEARCH (HNSW index)
+//   - OCEANBASE_FULLTEXT_PARSER (parser of the FULLTEXT index)
+//   - OCEANBASE_SSL_MODE, OCEANBASE_SSL_CA, OCEANBASE_SSL_CERT, OCEANBASE_SSL_KEY,
+//     OCEANBASE_CHARSET, OCEANBASE_CONNECT_TIMEOUT_SECONDS,
+//     OCEANBASE_READ_TIMEOUT_SECONDS, OCEANBASE_WRITE_TIMEOUT_SECONDS
 //   - LLM_PROVIDER, LLM_API_KEY, LLM_MODEL, LLM_BASE_URL
 //   - EMBEDDING_PROVIDER, EMBEDDING_API_KEY, EMBEDDING_MODEL, EMBEDDING_BASE_URL
 //   - INTELLIGENCE_ENABLED (to enable intelligent memory)
+//   - INTELLIGENCE_MERGE_STRATEGY (duplicate merge strategy, default "concatenate")
+//   - INJECTION_GUARD_ENABLED, INJECTION_GUARD_ACTION (tag, neutralize, block)
+//   - AUDIT_LOG_ENABLED (to record administrative operations)
 //
 // Returns a Config instance, or an error if loading fails.
 //
@@ -272,46 +831,91 @@ func LoadConfigFromEnv() (*Config, error) {
 	provider := getEnvOrDefault("DATABASE_PROVIDER", "sqlite")
 
 	// Build different configurations based on provider
-	vectorStoreConfig := make(map[string]interface{})
+	vectorStoreConfig := VectorStoreConfig{Provider: provider}
 
 	switch provider {
 	case "oceanbase":
 		// Use Python SDK compatible environment variables
 		port, _ := strconv.Atoi(getEnvOrDefault("OCEANBASE_PORT", "2881"))
 		dims, _ := strconv.Atoi(getEnvOrDefault("OCEANBASE_EMBEDDING_MODEL_DIMS", "1536"))
+		disableIndex, _ := strconv.ParseBool(os.Getenv("OCEANBASE_DISABLE_VECTOR_INDEX"))
+		hnswM, _ := strconv.Atoi(os.Getenv("OCEANBASE_HNSW_M"))
+		hnswEfConstruction, _ := strconv.Atoi(os.Getenv("OCEANBASE_HNSW_EF_CONSTRUCTION"))
+		hnswEfSearch, _ := strconv.Atoi(os.Getenv("OCEANBASE_HNSW_EF_SEARCH"))
+		connectTimeout, _ := strconv.ParseFloat(os.Getenv("OCEANBASE_CONNECT_TIMEOUT_SECONDS"), 64)
+		readTimeout, _ := strconv.ParseFloat(os.Getenv("OCEANBASE_READ_TIMEOUT_SECONDS"), 64)
+		writeTimeout, _ := strconv.ParseFloat(os.Getenv("OCEANBASE_WRITE_TIMEOUT_SECONDS"), 64)
 
-		vectorStoreConfig = map[string]interface{}{
-			"host":                 getEnvOrDefault("OCEANBASE_HOST", "127.0.0.1"),
-			"port":                 port,
-			"user":                 getEnvOrDefault("OCEANBASE_USER", "root@sys"),
-			"password":             os.Getenv("OCEANBASE_PASSWORD"),
-			"db_name":              getEnvOrDefault("OCEANBASE_DATABASE", "powermem"),
-			"collection_name":      getEnvOrDefault("OCEANBASE_COLLECTION", "memories"),
-			"embedding_model_dims": dims,
+		vectorStoreConfig.OceanBase = &OceanBaseConfig{
+			Host:                  getEnvOrDefault("OCEANBASE_HOST", "127.0.0.1"),
+			Port:                  port,
+			User:                  getEnvOrDefault("OCEANBASE_USER", "root@sys"),
+			Password:              os.Getenv("OCEANBASE_PASSWORD"),
+			DBName:                getEnvOrDefault("OCEANBASE_DATABASE", "powermem"),
+			CollectionName:        getEnvOrDefault("OCEANBASE_COLLECTION", "memories"),
+			EmbeddingModelDims:    dims,
+			DSN:                   os.Getenv("OCEANBASE_DSN"),
+			ReadDSNs:              getEnvList("OCEANBASE_READ_DSNS"),
+			DisableVectorIndex:    disableIndex,
+			HNSWM:                 hnswM,
+			HNSWEfConstruction:    hnswEfConstruction,
+			HNSWEfSearch:          hnswEfSearch,
+			FullTextParser:        os.Getenv("OCEANBASE_FULLTEXT_PARSER"),
+			SSLMode:               getEnvOrDefault("OCEANBASE_SSL_MODE", "disabled"),
+			SSLCA:                 os.Getenv("OCEANBASE_SSL_CA"),
+			SSLCert:               os.Getenv("OCEANBASE_SSL_CERT"),
+			SSLKey:                os.Getenv("OCEANBASE_SSL_KEY"),
+			Charset:               os.Getenv("OCEANBASE_CHARSET"),
+			ConnectTimeoutSeconds: connectTimeout,
+			ReadTimeoutSeconds:    readTimeout,
+			WriteTimeoutSeconds:   writeTimeout,
 		}
 	case "sqlite":
 		// Use Python SDK compatible environment variables
 		dims, _ := strconv.Atoi(getEnvOrDefault("SQLITE_EMBEDDING_MODEL_DIMS", "1536"))
+		busyTimeout, _ := strconv.ParseFloat(getEnvOrDefault("SQLITE_BUSY_TIMEOUT_SECONDS", os.Getenv("SQLITE_TIMEOUT")), 64)
+		busyRetries, _ := strconv.Atoi(os.Getenv("SQLITE_BUSY_RETRIES"))
+		checkpointInterval, _ := strconv.ParseFloat(os.Getenv("SQLITE_CHECKPOINT_INTERVAL_SECONDS"), 64)
+		searchWorkers, _ := strconv.Atoi(os.Getenv("SQLITE_SEARCH_WORKERS"))
+		clusters, _ := strconv.Atoi(os.Getenv("SQLITE_CLUSTERS"))
+		probeClusters, _ := strconv.Atoi(os.Getenv("SQLITE_PROBE_CLUSTERS"))
+		vectorCache, _ := strconv.ParseBool(os.Getenv("SQLITE_VECTOR_CACHE"))
 
-		vectorStoreConfig = map[string]interface{}{
-			"db_path":              getEnvOrDefault("SQLITE_PATH", "./powermem.db"),
-			"collection_name":      getEnvOrDefault("SQLITE_COLLECTION", "memories"),
-			"embedding_model_dims": dims,
+		vectorStoreConfig.SQLite = &SQLiteConfig{
+			DBPath:                    getEnvOrDefault("SQLITE_PATH", "./powermem.db"),
+			CollectionName:            getEnvOrDefault("SQLITE_COLLECTION", "memories"),
+			EmbeddingModelDims:        dims,
+			BusyTimeoutSeconds:        busyTimeout,
+			BusyRetries:               busyRetries,
+			Synchronous:               os.Getenv("SQLITE_SYNCHRONOUS"),
+			CheckpointIntervalSeconds: checkpointInterval,
+			Locking:                   getEnvOrDefault("SQLITE_LOCKING", "none"),
+			SearchWorkers:             searchWorkers,
+			Clusters:                  clusters,
+			ProbeClusters:             probeClusters,
+			VectorCache:               vectorCache,
 		}
 	case "postgres":
 		// Use Python SDK compatible environment variables
 		port, _ := strconv.Atoi(getEnvOrDefault("POSTGRES_PORT", "5432"))
 		dims, _ := strconv.Atoi(getEnvOrDefault("POSTGRES_EMBEDDING_MODEL_DIMS", "1536"))
+		maxLag, _ := strconv.ParseFloat(os.Getenv("POSTGRES_MAX_REPLICA_LAG_SECONDS"), 64)
+		rerankFactor, _ := strconv.Atoi(os.Getenv("POSTGRES_RERANK_FACTOR"))
 
-		vectorStoreConfig = map[string]interface{}{
-			"host":                 getEnvOrDefault("POSTGRES_HOST", "localhost"),
-			"port":                 port,
-			"user":                 getEnvOrDefault("POSTGRES_USER", "postgres"),
-			"password":             os.Getenv("POSTGRES_PASSWORD"),
-			"db_name":              getEnvOrDefault("POSTGRES_DATABASE", "powermem"),
-			"collection_name":      getEnvOrDefault("POSTGRES_COLLECTION", "memories"),
-			"embedding_model_dims": dims,
-			"ssl_mode":             getEnvOrDefault("POSTGRES_SSLMODE", "disable"),
+		vectorStoreConfig.Postgres = &PostgresConfig{
+			Host:                 getEnvOrDefault("POSTGRES_HOST", "localhost"),
+			Port:                 port,
+			User:                 getEnvOrDefault("POSTGRES_USER", "postgres"),
+			Password:             os.Getenv("POSTGRES_PASSWORD"),
+			DBName:               getEnvOrDefault("POSTGRES_DATABASE", "powermem"),
+			CollectionName:       getEnvOrDefault("POSTGRES_COLLECTION", "memories"),
+			EmbeddingModelDims:   dims,
+			SSLMode:              getEnvOrDefault("POSTGRES_SSLMODE", "disable"),
+			DSN:                  os.Getenv("POSTGRES_DSN"),
+			ReadDSNs:             getEnvList("POSTGRES_READ_DSNS"),
+			MaxReplicaLagSeconds: maxLag,
+			Quantization:         getEnvOrDefault("POSTGRES_QUANTIZATION", "none"),
+			RerankFactor:         rerankFactor,
 		}
 	}
 
@@ -377,6 +981,9 @@ func LoadConfigFromEnv() (*Config, error) {
 		}
 	}
 
+	idConflictRetries, _ := strconv.Atoi(os.Getenv("MEMORY_ID_CONFLICT_RETRIES"))
+	nodeID, _ := strconv.ParseInt(os.Getenv("MEMORY_NODE_ID"), 10, 64)
+
 	config := &Config{
 		LLM: LLMConfig{
 			Provider: llmProvider,
@@ -390,10 +997,65 @@ func LoadConfigFromEnv() (*Config, error) {
 			Model:    embedderModel,
 			BaseURL:  embedderFinalBaseURL,
 		},
-		VectorStore: VectorStoreConfig{
-			Provider: provider,
-			Config:   vectorStoreConfig,
-		},
+		VectorStore:         vectorStoreConfig,
+		IDType:              IDType(os.Getenv("MEMORY_ID_TYPE")),
+		IDConflictRetries:   idConflictRetries,
+		NodeID:              nodeID,
+		SkipExactDuplicates: os.Getenv("MEMORY_SKIP_EXACT_DUPLICATES") == "true",
+	}
+
+	// Change log (optional)
+	if os.Getenv("CHANGE_LOG_ENABLED") == "true" {
+		config.ChangeLog = &ChangeLogConfig{Enabled: true}
+	}
+
+	// Audit log (optional)
+	if os.Getenv("AUDIT_LOG_ENABLED") == "true" {
+		config.Audit = &AuditConfig{Enabled: true}
+	}
+
+	// Language detection and query translation (optional)
+	if detect, translate := os.Getenv("LANGUAGE_DETECT") == "true", os.Getenv("LANGUAGE_TRANSLATE_QUERIES"); detect || translate != "" {
+		config.Language = &LanguageConfig{Detect: detect}
+		for _, language := range strings.Split(translate, ",") {
+			if language = strings.TrimSpace(language); language != "" {
+				config.Language.TranslateQueries = append(config.Language.TranslateQueries, language)
+			}
+		}
+	}
+
+	// Staleness policy of search results (optional)
+	if os.Getenv("STALENESS_ENABLED") == "true" {
+		maxAgeDays, _ := strconv.ParseFloat(os.Getenv("STALENESS_MAX_AGE_DAYS"), 64)
+		config.Staleness = &StalenessConfig{
+			Enabled:            true,
+			MaxAgeDays:         maxAgeDays,
+			VerificationPrompt: os.Getenv("STALENESS_VERIFICATION_PROMPT") == "true",
+		}
+	}
+
+	// Query embedding cache (optional)
+	if os.Getenv("QUERY_CACHE_ENABLED") == "true" {
+		size, _ := strconv.Atoi(os.Getenv("QUERY_CACHE_SIZE"))
+		ttl, _ := strconv.ParseFloat(os.Getenv("QUERY_CACHE_TTL_SECONDS"), 64)
+		config.QueryCache = &QueryCacheConfig{Enabled: true, Size: size, TTLSeconds: ttl}
+	}
+
+	// Content size limit and chunking (optional)
+	maxContentSize, _ := strconv.Atoi(os.Getenv("MEMORY_MAX_CONTENT_SIZE"))
+	chunkSize, _ := strconv.Atoi(os.Getenv("MEMORY_CHUNK_SIZE"))
+	chunkOverlap, _ := strconv.Atoi(os.Getenv("MEMORY_CHUNK_OVERLAP"))
+	if maxContentSize != 0 || chunkSize != 0 {
+		config.Chunking = &ChunkingConfig{
+			MaxContentSize: maxContentSize,
+			ChunkSize:      chunkSize,
+			ChunkOverlap:   chunkOverlap,
+		}
+	}
+
+	// Prompt injection screening of search results (optional)
+	if action := os.Getenv("INJECTION_GUARD_ACTION"); os.Getenv("INJECTION_GUARD_ENABLED") == "true" {
+		config.InjectionGuard = &InjectionGuardConfig{Enabled: true, Action: InjectionAction(action)}
 	}
 
 	// Intelligent memory configuration (optional)
@@ -408,6 +1070,7 @@ func LoadConfigFromEnv() (*Config, error) {
 			LongTermThreshold:   0.8,
 			InitialRetention:    1.0,
 			FallbackToSimpleAdd: false,
+			MergeStrategy:       os.Getenv("INTELLIGENCE_MERGE_STRATEGY"),
 		}
 	}
 
@@ -449,21 +1112,312 @@ func LoadConfigFromJSON(path string) (*Config, error) {
 
 // Validate validates the configuration.
 //
-// Checks that all required fields are set:
-//   - LLM provider must be specified
-//   - Embedder provider must be specified
-//   - Vector store provider must be specified
+// Every field is checked and all problems are reported together:
+//   - LLM (including fallbacks), embedder and vector store providers must be
+//     set and supported, and LLM routing and recording settings must be valid
+//   - The vector store settings must have valid values; see SQLiteConfig,
+//     OceanBaseConfig and PostgresConfig for the fields and defaults
+//   - Embedder dimensions must not be negative, and embedder routes must
+//     have unique names, list memory types or languages, and produce
+//     vectors of the dimension of Embedder
+//   - An APIKeySecret requires Secrets and excludes APIKey
+//   - IDType must be empty, "snowflake" or "uuid", and NodeID within 0-1023
+//   - Query translation languages must not be empty, and the staleness
+//     maximum age, query cache size and TTL must not be negative
+//   - Webhooks must have an http or https URL, known event types and a
+//     non-negative timeout
+//   - If the injection guard is enabled, Action must be known and Patterns
+//     must be valid regular expressions
+//   - Replication requires the change log, a NodeID other than the default,
+//     a remote vector store that is not a routing store, and a known
+//     conflict policy
+//   - If intelligence is enabled, thresholds and confidences must be within
+//     0-1, rates and ranking weights must not be negative, and MergeStrategy
+//     must be known
 //
-// Returns an error if validation fails, nil otherwise.
+// Returns an error wrapping a *ValidationError (and ErrInvalidConfig) if
+// validation fails, nil otherwise.
 func (c *Config) Validate() error {
-	if c.LLM.Provider == "" {
-		return NewMemoryError("Validate", ErrInvalidConfig)
+	var errs []*FieldError
+	invalid := func(field, format string, args ...interface{}) {
+		errs = append(errs, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	type llmField struct {
+		field string
+		cfg   LLMConfig
+	}
+	llms := []llmField{{"llm", c.LLM}}
+	for i, fallback := range c.LLM.Fallbacks {
+		field := fmt.Sprintf("llm.fallbacks[%d]", i)
+		llms = append(llms, llmField{field, fallback})
+		if len(fallback.Fallbacks) > 0 {
+			invalid(field+".fallbacks", "nested fallbacks are not supported")
+		}
+		if fallback.Routing != nil {
+			invalid(field+".routing", "is only supported on the primary llm")
+		}
+		if fallback.Recording != nil {
+			invalid(field+".recording", "is only supported on the primary llm")
+		}
+	}
+	for _, l := range llms {
+		switch l.cfg.Provider {
+		case "openai", "qwen", "deepseek", "ollama", "anthropic", "mock":
+		case "":
+			invalid(l.field+".provider", "is required")
+		default:
+			invalid(l.field+".provider", "unknown provider %q (want openai, qwen, deepseek, ollama, anthropic or mock)", l.cfg.Provider)
+		}
+	}
+	if r := c.LLM.Routing; r != nil {
+		switch r.Strategy {
+		case "", "priority", "latency":
+		default:
+			invalid("llm.routing.strategy", "unknown strategy %q (want priority or latency)", r.Strategy)
+		}
+		if r.FailureThreshold < 0 {
+			invalid("llm.routing.failure_threshold", "must not be negative, got %d", r.FailureThreshold)
+		}
+		if r.CooldownSeconds < 0 {
+			invalid("llm.routing.cooldown_seconds", "must not be negative, got %v", r.CooldownSeconds)
+		}
 	}
-	if c.Embedder.Provider == "" {
-		return NewMemoryError("Validate", ErrInvalidConfig)
+	if r := c.LLM.Recording; r != nil {
+		if r.Path == "" {
+			invalid("llm.recording.path", "is required")
+		}
+		switch r.Mode {
+		case "", "replay", "record", "auto":
+		default:
+			invalid("llm.recording.mode", "unknown mode %q (want replay, record or auto)", r.Mode)
+		}
 	}
-	if c.VectorStore.Provider == "" {
-		return NewMemoryError("Validate", ErrInvalidConfig)
+
+	type embedderField struct {
+		field string
+		cfg   EmbedderConfig
+	}
+	embedders := []embedderField{{"embedder", c.Embedder}}
+	routeNames := make(map[string]bool, len(c.EmbedderRoutes))
+	for i, route := range c.EmbedderRoutes {
+		field := fmt.Sprintf("embedder_routes[%d]", i)
+		embedders = append(embedders, embedderField{field + ".embedder", route.Embedder})
+		switch {
+		case route.Name == "":
+			invalid(field+".name", "is required")
+		case routeNames[route.Name]:
+			invalid(field+".name", "duplicate route name %q", route.Name)
+		}
+		routeNames[route.Name] = true
+		if len(route.MemoryTypes) == 0 && len(route.Languages) == 0 {
+			invalid(field, "must list memory_types or languages")
+		}
+		if dims := route.Embedder.vectorDimensions(); dims != c.Embedder.vectorDimensions() {
+			invalid(field+".embedder.dimensions", "must produce vectors of the dimension of embedder (%d), got %d", c.Embedder.vectorDimensions(), dims)
+		}
+	}
+	for _, e := range embedders {
+		switch e.cfg.Provider {
+		case "openai", "qwen", "mock":
+		case "":
+			invalid(e.field+".provider", "is required")
+		default:
+			invalid(e.field+".provider", "unknown provider %q (want openai, qwen or mock)", e.cfg.Provider)
+		}
+		if e.cfg.Dimensions < 0 {
+			invalid(e.field+".dimensions", "must not be negative, got %d", e.cfg.Dimensions)
+		}
+		if e.cfg.TruncateDimensions < 0 {
+			invalid(e.field+".truncate_dimensions", "must not be negative, got %d", e.cfg.TruncateDimensions)
+		} else if e.cfg.Dimensions > 0 && e.cfg.TruncateDimensions >= e.cfg.Dimensions {
+			invalid(e.field+".truncate_dimensions", "must be less than %s.dimensions (%d), got %d", e.field, e.cfg.Dimensions, e.cfg.TruncateDimensions)
+		}
+	}
+
+	type apiKeyFields struct {
+		field, apiKey, secret string
+	}
+	var apiKeys []apiKeyFields
+	for _, l := range llms {
+		apiKeys = append(apiKeys, apiKeyFields{l.field + ".api_key_secret", l.cfg.APIKey, l.cfg.APIKeySecret})
+	}
+	for _, e := range embedders {
+		apiKeys = append(apiKeys, apiKeyFields{e.field + ".api_key_secret", e.cfg.APIKey, e.cfg.APIKeySecret})
+	}
+	for _, f := range apiKeys {
+		switch {
+		case f.secret == "":
+		case f.apiKey != "":
+			invalid(f.field, "cannot be used together with api_key")
+		case c.Secrets == nil:
+			invalid(f.field, "requires a secrets provider (Config.Secrets)")
+		}
+	}
+
+	_, storeErrs := resolveStoreConfig(c.VectorStore, c.Embedder.vectorDimensions())
+	errs = append(errs, storeErrs...)
+
+	switch c.IDType {
+	case "", IDTypeSnowflake, IDTypeUUID:
+	default:
+		invalid("id_type", "unknown id type %q (want snowflake or uuid)", c.IDType)
+	}
+	if c.NodeID < 0 || c.NodeID > maxNodeID {
+		invalid("node_id", "must be within 1-%d, got %d", maxNodeID, c.NodeID)
+	}
+
+	for i, hook := range c.Webhooks {
+		field := fmt.Sprintf("webhooks[%d]", i)
+		if hook.URL == "" {
+			invalid(field+".url", "is required")
+		} else if u, err := url.Parse(hook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid(field+".url", "invalid URL %q (want an http or https URL)", hook.URL)
+		}
+		for _, eventType := range hook.Events {
+			if !isEventType(eventType) {
+				invalid(field+".events", "unknown event type %q", eventType)
+			}
+		}
+		if hook.TimeoutSeconds < 0 {
+			invalid(field+".timeout_seconds", "must not be negative, got %v", hook.TimeoutSeconds)
+		}
+	}
+
+	if l := c.Language; l != nil {
+		for i, language := range l.TranslateQueries {
+			if strings.TrimSpace(language) == "" {
+				invalid(fmt.Sprintf("language.translate_queries[%d]", i), "must not be empty")
+			}
+		}
+	}
+
+	if st := c.Staleness; st != nil && st.MaxAgeDays < 0 {
+		invalid("staleness.max_age_days", "must not be negative, got %v", st.MaxAgeDays)
+	}
+
+	if q := c.QueryCache; q != nil {
+		if q.Size < 0 {
+			invalid("query_cache.size", "must not be negative, got %d", q.Size)
+		}
+		if q.TTLSeconds < 0 {
+			invalid("query_cache.ttl_seconds", "must not be negative, got %v", q.TTLSeconds)
+		}
+	}
+
+	if ch := c.Chunking; ch != nil {
+		if ch.MaxContentSize < 0 {
+			invalid("chunking.max_content_size", "must not be negative, got %d", ch.MaxContentSize)
+		}
+		if ch.ChunkSize < 0 {
+			invalid("chunking.chunk_size", "must not be negative, got %d", ch.ChunkSize)
+		}
+		if ch.ChunkOverlap < 0 {
+			invalid("chunking.chunk_overlap", "must not be negative, got %d", ch.ChunkOverlap)
+		} else if ch.ChunkOverlap > 0 && ch.ChunkOverlap >= ch.ChunkSize {
+			invalid("chunking.chunk_overlap", "must be less than chunk_size, got %d", ch.ChunkOverlap)
+		}
+	}
+
+	if r := c.Replication; r != nil {
+		if c.ChangeLog == nil || !c.ChangeLog.Enabled {
+			invalid("replication", "requires the change log (change_log.enabled)")
+		}
+		if c.NodeID == 0 || c.NodeID == defaultNodeID {
+			invalid("node_id", "replication requires a node ID other than the default %d, unique among the replicas", defaultNodeID)
+		}
+		if r.Remote.resolvedProvider() == "routing" {
+			invalid("replication.remote.provider", "cannot be routing")
+		} else {
+			_, remoteErrs := resolveStoreConfig(r.Remote, c.Embedder.vectorDimensions())
+			errs = append(errs, prefixFieldErrors(remoteErrs, "replication.remote")...)
+		}
+		if _, err := replication.ParseConflictPolicy(r.Conflict); err != nil {
+			invalid("replication.conflict", "%v", err)
+		}
+	}
+
+	if g := c.InjectionGuard; g != nil && g.Enabled {
+		if g.Action != "" && !isInjectionAction(g.Action) {
+			invalid("injection_guard.action", "unknown action %q (want tag, neutralize or block)", g.Action)
+		}
+		for i, pattern := range g.Patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				invalid(fmt.Sprintf("injection_guard.patterns[%d]", i), "%v", err)
+			}
+		}
+	}
+
+	if intel := c.Intelligence; intel != nil && intel.Enabled {
+		for _, f := range []struct {
+			field string
+			value float64
+		}{
+			{"intelligence.duplicate_threshold", intel.DuplicateThreshold},
+			{"intelligence.working_threshold", intel.WorkingThreshold},
+			{"intelligence.short_term_threshold", intel.ShortTermThreshold},
+			{"intelligence.long_term_threshold", intel.LongTermThreshold},
+			{"intelligence.initial_retention", intel.InitialRetention},
+			{"intelligence.min_fact_confidence", intel.MinFactConfidence},
+		} {
+			if f.value < 0 || f.value > 1 {
+				invalid(f.field, "must be between 0 and 1, got %v", f.value)
+			}
+		}
+		if intel.DecayRate < 0 {
+			invalid("intelligence.decay_rate", "must not be negative, got %v", intel.DecayRate)
+		}
+		if intel.ReinforcementFactor < 0 {
+			invalid("intelligence.reinforcement_factor", "must not be negative, got %v", intel.ReinforcementFactor)
+		}
+		if _, err := intelligence.ParseMergeStrategy(intel.MergeStrategy); err != nil {
+			invalid("intelligence.merge_strategy", "%v", err)
+		}
+		validatePolicies := func(field string, policies map[string]RetentionPolicy) {
+			for name, policy := range policies {
+				prefix := fmt.Sprintf("intelligence.%s.%s", field, name)
+				for _, f := range []struct {
+					field string
+					value float64
+				}{
+					{prefix + ".working_threshold", policy.WorkingThreshold},
+					{prefix + ".short_term_threshold", policy.ShortTermThreshold},
+					{prefix + ".long_term_threshold", policy.LongTermThreshold},
+				} {
+					if f.value < 0 || f.value > 1 {
+						invalid(f.field, "must be between 0 and 1, got %v", f.value)
+					}
+				}
+				if policy.DecayRate < 0 {
+					invalid(prefix+".decay_rate", "must not be negative, got %v", policy.DecayRate)
+				}
+				if policy.ReinforcementFactor < 0 {
+					invalid(prefix+".reinforcement_factor", "must not be negative, got %v", policy.ReinforcementFactor)
+				}
+			}
+		}
+		validatePolicies("agent_policies", intel.AgentPolicies)
+		validatePolicies("memory_type_policies", intel.MemoryTypePolicies)
+		if r := intel.Ranking; r != nil {
+			for _, f := range []struct {
+				field string
+				value float64
+			}{
+				{"intelligence.ranking.similarity_weight", r.SimilarityWeight},
+				{"intelligence.ranking.recency_weight", r.RecencyWeight},
+				{"intelligence.ranking.importance_weight", r.ImportanceWeight},
+				{"intelligence.ranking.retention_weight", r.RetentionWeight},
+				{"intelligence.ranking.recency_half_life_hours", r.RecencyHalfLifeHours},
+			} {
+				if f.value < 0 {
+					invalid(f.field, "must not be negative, got %v", f.value)
+				}
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return NewMemoryError("Validate", &ValidationError{Fields: errs})
 	}
 	return nil
 }
@@ -476,6 +1430,18 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvList gets an environment variable holding a list separated by ";",
+// skipping empty items.
+func getEnvList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // FindEnvFile searches for .env or .env.example files.
 //
 // The search:
@@ -0,0 +1,282 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfigFromFile loads configuration from a YAML, TOML or JSON file.
+//
+// The format is selected by the file extension: .yaml or .yml, .toml, or
+// .json. Keys are the JSON names of the Config fields, so all sections
+// (llm, embedder, vector_store, intelligence, agent_memory, ...) are
+// supported in every format.
+//
+// String values may reference environment variables as ${NAME}, or as
+// ${NAME:-default} to fall back to a default when NAME is unset or empty.
+// Referencing an unset variable without a default is an error. Use $${ for a
+// literal "${". In YAML, an unquoted value that consists of a single
+// reference takes the type of the substituted text (port: ${DB_PORT} is an
+// integer); quote it to keep a string. In TOML and JSON, references are
+// only expanded inside strings, which keep their type.
+//
+// Example config.yaml:
+//
+//	llm:
+//	  provider: openai
+//	  api_key: ${LLM_API_KEY}
+//	  model: gpt-4
+//	embedder:
+//	  provider: openai
+//	  api_key: ${LLM_API_KEY}
+//	  dimensions: 1536
+//	vector_store:
+//	  provider: postgres
+//	  config:
+//	    host: ${POSTGRES_HOST:-localhost}
+//	    port: ${POSTGRES_PORT:-5432}
+//	    password: "${POSTGRES_PASSWORD}"
+//
+// Example:
+//
+//	config, err := core.LoadConfigFromFile("config.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func LoadConfigFromFile(path string) (*Config, error) {
+	var config Config
+	if err := DecodeConfigFile(path, &config); err != nil {
+		return nil, NewMemoryError("LoadConfigFromFile", err)
+	}
+	return &config, nil
+}
+
+// LoadConfigFromYAML loads configuration from a YAML file, whatever its
+// extension. See LoadConfigFromFile for the supported keys and environment
+// variable interpolation.
+func LoadConfigFromYAML(path string) (*Config, error) {
+	var config Config
+	if err := decodeConfigFileAs(path, configFormatYAML, &config); err != nil {
+		return nil, NewMemoryError("LoadConfigFromYAML", err)
+	}
+	return &config, nil
+}
+
+// LoadConfigFromTOML loads configuration from a TOML file, whatever its
+// extension. See LoadConfigFromFile for the supported keys and environment
+// variable interpolation.
+//
+// The file is parsed as TOML 1.0 by github.com/BurntSushi/toml.
+func LoadConfigFromTOML(path string) (*Config, error) {
+	var config Config
+	if err := decodeConfigFileAs(path, configFormatTOML, &config); err != nil {
+		return nil, NewMemoryError("LoadConfigFromTOML", err)
+	}
+	return &config, nil
+}
+
+// DecodeConfigFile decodes a YAML, TOML or JSON config file into out, which
+// is matched by its json tags, after interpolating environment variables as
+// described in LoadConfigFromFile.
+//
+// It lets packages that build on core read their own sections from the same
+// file, e.g. the user_memory section read by usermemory.LoadConfigFromFile.
+func DecodeConfigFile(path string, out interface{}) error {
+	format, err := configFormatOf(path)
+	if err != nil {
+		return err
+	}
+	return decodeConfigFileAs(path, format, out)
+}
+
+// configFormat is a supported config file format.
+type configFormat string
+
+const (
+	configFormatYAML configFormat = "yaml"
+	configFormatTOML configFormat = "toml"
+	configFormatJSON configFormat = "json"
+)
+
+// configFormatOf returns the format of a config file from its extension.
+func configFormatOf(path string) (configFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return configFormatYAML, nil
+	case ".toml":
+		return configFormatTOML, nil
+	case ".json":
+		return configFormatJSON, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported config file extension %q (want .yaml, .yml, .toml or .json)",
+			ErrInvalidConfig, filepath.Ext(path))
+	}
+}
+
+// decodeConfigFileAs reads a config file in the given format and decodes it
+// into out through its JSON form.
+func decodeConfigFileAs(path string, format configFormat, out interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var values interface{}
+	switch format {
+	case configFormatYAML:
+		values, err = parseYAMLConfig(data)
+	case configFormatTOML:
+		if _, err = toml.Decode(string(data), &values); err == nil {
+			values, err = expandEnvValues(values, "")
+		}
+	case configFormatJSON:
+		if err = json.Unmarshal(data, &values); err == nil {
+			values, err = expandEnvValues(values, "")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidConfig, filepath.Base(path), err)
+	}
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidConfig, filepath.Base(path), err)
+	}
+	if err := json.Unmarshal(encoded, out); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidConfig, filepath.Base(path), err)
+	}
+	return nil
+}
+
+// parseYAMLConfig parses a YAML document, interpolating environment
+// variables in scalar values.
+func parseYAMLConfig(data []byte) (interface{}, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if err := expandYAMLNode(&doc, ""); err != nil {
+		return nil, err
+	}
+
+	var values interface{}
+	if err := doc.Decode(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// expandYAMLNode interpolates environment variables in the string scalars
+// under node. path is the key path of node, used in error messages.
+func expandYAMLNode(node *yaml.Node, path string) error {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for i, child := range node.Content {
+			childPath := path
+			if node.Kind == yaml.SequenceNode {
+				childPath = fmt.Sprintf("%s[%d]", path, i)
+			}
+			if err := expandYAMLNode(child, childPath); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if err := expandYAMLNode(node.Content[i+1], joinKey(path, node.Content[i].Value)); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		if node.ShortTag() != "!!str" || !strings.Contains(node.Value, "$") {
+			return nil
+		}
+		whole := envReferencePattern.FindString(node.Value) == node.Value
+		value, err := expandEnv(node.Value)
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		node.Value = value
+		if whole && node.Style == 0 {
+			// Let the substituted text decide the type
+			node.Tag = ""
+		}
+	}
+	return nil
+}
+
+// expandEnvValues interpolates environment variables in the strings of a
+// decoded JSON or TOML value.
+func expandEnvValues(value interface{}, path string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		expanded, err := expandEnv(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		return expanded, nil
+	case map[string]interface{}:
+		for key, child := range v {
+			expanded, err := expandEnvValues(child, joinKey(path, key))
+			if err != nil {
+				return nil, err
+			}
+			v[key] = expanded
+		}
+	case []interface{}:
+		for i, child := range v {
+			expanded, err := expandEnvValues(child, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = expanded
+		}
+	case []map[string]interface{}:
+		// A TOML array of tables
+		for i, child := range v {
+			if _, err := expandEnvValues(child, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return value, nil
+}
+
+// envReferencePattern matches ${NAME} and ${NAME:-default}, and the escape $${.
+var envReferencePattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// expandEnv replaces the environment variable references in s.
+func expandEnv(s string) (string, error) {
+	var err error
+	expanded := envReferencePattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if ref == "$${" {
+			return "${"
+		}
+		match := envReferencePattern.FindStringSubmatch(ref)
+		if value := os.Getenv(match[1]); value != "" {
+			return value
+		}
+		if strings.Contains(ref, ":-") {
+			return match[2]
+		}
+		if _, ok := os.LookupEnv(match[1]); !ok && err == nil {
+			err = fmt.Errorf("environment variable %s is not set", match[1])
+		}
+		return ""
+	})
+	return expanded, err
+}
+
+// joinKey appends key to a dotted key path.
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,356 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// defaultConsolidateLimit is the default number of memories reviewed by Consolidate.
+const defaultConsolidateLimit = 100
+
+// longTermMemoryType is the memory type of promoted memories, stored in
+// metadata["intelligence"]["memory_type"].
+const longTermMemoryType = "long_term"
+
+// ConsolidationResult is the result of Consolidate.
+type ConsolidationResult struct {
+	// UserID is the user whose memories were reviewed.
+	UserID string `json:"user_id"`
+
+	// Reviewed is the number of memories given to the LLM.
+	Reviewed int `json:"reviewed"`
+
+	// Actions are the consolidations performed (or planned, when DryRun is true).
+	Actions []ConsolidationActionResult `json:"actions"`
+
+	// DryRun indicates that the consolidations were planned but not performed.
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// Conflicts are the consolidations that were not performed because one of
+	// the memories was changed by another writer during the review.
+	Conflicts []ConsolidationActionResult `json:"conflicts,omitempty"`
+}
+
+// ConsolidationActionResult is a consolidation of Consolidate.
+type ConsolidationActionResult struct {
+	// Event is the operation: MERGE, RESOLVE or PROMOTE.
+	Event string `json:"event"`
+
+	// ID is the memory that is kept (the first of the merged memories).
+	ID int64 `json:"id"`
+
+	// Memory is the content of the kept memory.
+	Memory string `json:"memory"`
+
+	// Replaced are the memories deleted by a MERGE or RESOLVE.
+	Replaced []int64 `json:"replaced,omitempty"`
+
+	// PreviousMemories is the content of the merged memories, kept memory first.
+	PreviousMemories []string `json:"previous_memories,omitempty"`
+
+	// Reason is the explanation of the LLM.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Consolidate reviews the memories of a user with the LLM, like the
+// "reflection" of generative agents:
+//   - MERGE: redundant memories are merged into the first one, the others are deleted
+//   - RESOLVE: contradicting memories are replaced with the fact that holds
+//   - PROMOTE: stable facts become long-term memories, with full retention strength
+//
+// The newest WithLimitForConsolidate memories (default 100) are reviewed in
+// one LLM call, oldest first. Memories flagged as incorrect by Feedback and
+// working memory items are left out. A merged memory keeps the metadata of the first memory and the
+// sources of all merged memories. Promoted memories get
+// metadata["intelligence"]["memory_type"] = "long_term".
+//
+// As with IntelligentAdd, actions that fail are logged and skipped, and
+// actions on memories changed since the review are reported in Conflicts.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userID: User whose memories are reviewed
+//   - opts: Optional parameters (AgentID, Limit, DryRun)
+//
+// Returns ErrInvalidInput without a user ID, and ErrInvalidConfig without an
+// LLM provider.
+//
+// Example:
+//
+//	result, err := client.Consolidate(ctx, "user_001")
+//	for _, action := range result.Actions {
+//	    fmt.Println(action.Event, action.Memory, action.Reason)
+//	}
+func (c *Client) Consolidate(ctx context.Context, userID string, opts ...ConsolidateOption) (*ConsolidationResult, error) {
+	ctx, err := c.begin(ctx, "Consolidate")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	events := c.recordEvents(ctx, "Consolidate")
+	defer events.publish()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	consolidateOpts := applyConsolidateOptions(opts)
+
+	if userID == "" {
+		return nil, NewMemoryError("Consolidate", fmt.Errorf("%w: user ID is required", ErrInvalidInput))
+	}
+	if c.llm == nil {
+		return nil, NewMemoryError("Consolidate", fmt.Errorf("%w: consolidation requires an LLM provider", ErrInvalidConfig))
+	}
+
+	memories, err := c.storage.GetAll(ctx, &storage.GetAllOptions{
+		UserID:  userID,
+		AgentID: consolidateOpts.AgentID,
+		Limit:   consolidateOpts.Limit,
+		Now:     c.now(),
+	})
+	if err != nil {
+		return nil, NewMemoryError("Consolidate", err)
+	}
+	memories = visible(memories, &SearchOptions{})
+	sort.SliceStable(memories, func(i, j int) bool {
+		return memories[i].CreatedAt.Before(memories[j].CreatedAt)
+	})
+
+	result := &ConsolidationResult{UserID: userID, Reviewed: len(memories), DryRun: consolidateOpts.DryRun}
+	if len(memories) == 0 {
+		return result, nil
+	}
+
+	// Give the LLM temporary IDs, as IntelligentAdd does
+	existingMemories := make([]intelligence.ExistingMemory, len(memories))
+	tempIDMapping := make(map[string]int64, len(memories))
+	current := make(map[int64]*storage.Memory, len(memories))
+	for i, memory := range memories {
+		tempID := strconv.Itoa(i)
+		existingMemories[i] = intelligence.ExistingMemory{ID: tempID, Text: memory.Content}
+		tempIDMapping[tempID] = memory.ID
+		current[memory.ID] = memory
+	}
+
+	actions, err := intelligence.NewMemoryConsolidator(c.llm).Consolidate(ctx, existingMemories)
+	if err != nil {
+		return nil, NewMemoryError("Consolidate", err)
+	}
+
+	// merged are the memories already in a MERGE or RESOLVE
+	merged := make(map[int64]bool)
+	for _, action := range actions {
+		var group []*storage.Memory
+		inGroup := make(map[int64]bool, len(action.IDs))
+		for _, tempID := range action.IDs {
+			id, ok := tempIDMapping[tempID]
+			if !ok {
+				log.Printf("Could not find real memory ID for consolidation ID: %s", tempID)
+				continue
+			}
+			memory, ok := current[id]
+			if !ok || inGroup[id] || (action.Event != intelligence.ConsolidationPromote && merged[id]) {
+				continue
+			}
+			inGroup[id] = true
+			group = append(group, memory)
+		}
+		if len(group) == 0 {
+			continue
+		}
+
+		if action.Event == intelligence.ConsolidationPromote {
+			for _, memory := range group {
+				actionResult := ConsolidationActionResult{Event: action.Event, ID: memory.ID, Memory: memory.Content, Reason: action.Reason}
+				if !consolidateOpts.DryRun {
+					promoted, err := c.promote(ctx, memory, events)
+					if errors.Is(err, storage.ErrVersionConflict) {
+						result.Conflicts = append(result.Conflicts, actionResult)
+						continue
+					}
+					if err != nil {
+						log.Printf("Failed to promote memory %d: %v", memory.ID, err)
+						continue
+					}
+					current[memory.ID] = promoted
+				}
+				result.Actions = append(result.Actions, actionResult)
+			}
+			continue
+		}
+
+		if action.Text == "" {
+			log.Printf("Skipped %s of memory %d without text", action.Event, group[0].ID)
+			continue
+		}
+		actionResult := ConsolidationActionResult{Event: action.Event, ID: group[0].ID, Memory: action.Text, Reason: action.Reason}
+		for i, memory := range group {
+			if i > 0 {
+				actionResult.Replaced = append(actionResult.Replaced, memory.ID)
+			}
+			actionResult.PreviousMemories = append(actionResult.PreviousMemories, memory.Content)
+		}
+		if !consolidateOpts.DryRun {
+			kept, err := c.mergeGroup(ctx, group, action.Text, action.Reason, events)
+			if errors.Is(err, storage.ErrVersionConflict) {
+				result.Conflicts = append(result.Conflicts, actionResult)
+				continue
+			}
+			if err != nil {
+				log.Printf("Failed to %s memory %d: %v", action.Event, group[0].ID, err)
+				continue
+			}
+			current[kept.ID] = kept
+		}
+		for i, memory := range group {
+			merged[memory.ID] = true
+			if i > 0 {
+				delete(current, memory.ID)
+			}
+		}
+		result.Actions = append(result.Actions, actionResult)
+	}
+
+	return result, nil
+}
+
+// mergeGroup replaces the memories of group with one memory with content,
+// kept in the first memory, and returns the kept memory. The merge is
+// recorded in the provenance of the kept memory with reason, after the
+// provenance of the other memories.
+func (c *Client) mergeGroup(ctx context.Context, group []*storage.Memory, content, reason string, events *eventRecorder) (*storage.Memory, error) {
+	first := group[0]
+	if err := c.checkContentSize(content); err != nil {
+		return nil, err
+	}
+	embedding, chunked, err := c.embedContent(ctx, c.embedderOf(c.routeOf(first.Metadata)), content)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := sourcesFromMetadata(first.Metadata)
+	for _, memory := range group[1:] {
+		sources = mergeSources(sources, sourcesFromMetadata(memory.Metadata)...)
+	}
+	entry := ProvenanceEntry{
+		Operation:       ProvenanceConsolidate,
+		Time:            c.now().UTC(),
+		PreviousContent: first.Content,
+		IncomingContent: content,
+		Reason:          reason,
+	}
+	for _, memory := range group[1:] {
+		entry.SourceMemoryIDs = append(entry.SourceMemoryIDs, memory.ID)
+		entry.SourceContents = append(entry.SourceContents, memory.Content)
+	}
+	metadata := withProvenance(first.Metadata, entry, group[1:]...)
+	if len(sources) > 0 {
+		metadata[sourcesKey] = sourcesMetadata(sources)
+	}
+
+	kept, err := c.storage.Update(ctx, first.ID, content, embedding, &storage.UpdateOptions{
+		UserID:          first.UserID,
+		Metadata:        metadata,
+		ExpectedVersion: first.Version,
+		Now:             c.now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.deleteChunks(ctx, first.ID, first.UserID); err != nil {
+		return nil, err
+	}
+	if chunked != nil {
+		if err := c.insertChunks(ctx, fromStorageMemory(kept), chunked); err != nil {
+			return nil, err
+		}
+	}
+	keptMemory := fromStorageMemory(kept)
+	events.add(&Event{Type: EventMerged, Memory: keptMemory, Diff: newEventDiff(fromStorageMemory(first), keptMemory)})
+
+	for _, memory := range group[1:] {
+		if err := c.storage.Delete(ctx, memory.ID, &storage.DeleteOptions{UserID: memory.UserID}); err != nil {
+			log.Printf("Failed to delete merged memory %d: %v", memory.ID, err)
+			continue
+		}
+		if err := c.deleteChunks(ctx, memory.ID, memory.UserID); err != nil {
+			log.Printf("Failed to delete chunks of memory %d: %v", memory.ID, err)
+		}
+		events.add(&Event{Type: EventDeleted, Memory: fromStorageMemory(memory)})
+	}
+	return kept, nil
+}
+
+// promote makes memory a long-term memory with full retention strength.
+func (c *Client) promote(ctx context.Context, memory *storage.Memory, events *eventRecorder) (*storage.Memory, error) {
+	metadata := copyMetadata(memory.Metadata)
+	intelligenceData := make(map[string]interface{})
+	if existing, ok := metadata["intelligence"].(map[string]interface{}); ok {
+		for k, v := range existing {
+			intelligenceData[k] = v
+		}
+	}
+	intelligenceData["memory_type"] = longTermMemoryType
+	intelligenceData["promoted_at"] = c.now().UTC().Format(time.RFC3339)
+	metadata["intelligence"] = intelligenceData
+
+	strength := 1.0
+	promoted, err := c.storage.Update(ctx, memory.ID, memory.Content, memory.Embedding, &storage.UpdateOptions{
+		UserID:            memory.UserID,
+		Metadata:          metadata,
+		ExpectedVersion:   memory.Version,
+		RetentionStrength: &strength,
+		Now:               c.now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.updateChunkMetadata(ctx, promoted); err != nil {
+		return nil, err
+	}
+	promotedMemory := fromStorageMemory(promoted)
+	events.add(&Event{Type: EventUpdated, Memory: promotedMemory, Diff: newEventDiff(fromStorageMemory(memory), promotedMemory)})
+	return promoted, nil
+}
+
+// StartConsolidation runs Consolidate for each of userIDs every interval in a
+// background goroutine until ctx is cancelled or the client is shut down.
+//
+// Consolidation errors are logged and do not stop the routine.
+//
+// Example:
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	client.StartConsolidation(ctx, 24*time.Hour, []string{"user_001", "user_002"})
+func (c *Client) StartConsolidation(ctx context.Context, interval time.Duration, userIDs []string, opts ...ConsolidateOption) {
+	stopped := c.stopped()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-stopped:
+				return
+			case <-ticker.C:
+				for _, userID := range userIDs {
+					if _, err := c.Consolidate(ctx, userID, opts...); err != nil && ctx.Err() == nil {
+						log.Printf("Failed to consolidate memories of user %s: %v", userID, err)
+					}
+				}
+			}
+		}
+	}()
+}
@@ -2,6 +2,7 @@
 package core
 
 import (
+	"strings"
 	"time"
 
 	"github.com/oceanbase/powermem-go/pkg/intelligence"
@@ -26,6 +27,11 @@ func toStorageMemory(m *Memory) *storage.Memory {
 		RetentionStrength: m.RetentionStrength,
 		LastAccessedAt:    m.LastAccessedAt,
 		Score:             m.Score,
+		Tags:              m.Tags,
+		ExpiresAt:         m.ExpiresAt,
+		UID:               m.UID,
+		Version:           m.Version,
+		ParentID:          m.ParentID,
 	}
 }
 
@@ -47,9 +53,38 @@ func fromStorageMemory(m *storage.Memory) *Memory {
 		RetentionStrength: m.RetentionStrength,
 		LastAccessedAt:    m.LastAccessedAt,
 		Score:             m.Score,
+		Tags:              m.Tags,
+		ExpiresAt:         m.ExpiresAt,
+		UID:               m.UID,
+		Version:           m.Version,
+		ParentID:          m.ParentID,
+		Sources:           sourcesFromMetadata(m.Metadata),
+		Entities:          entitiesFromMetadata(m.Metadata),
+		SharedFrom:        shareConsentFromMetadata(m.Metadata),
+		Hash:              m.Hash,
+		RunID:             m.RunID,
+		Scope:             MemoryScope(metadataString(m.Metadata, scopeKey)),
+		MemoryType:        metadataString(m.Metadata, memoryTypeKey),
+		Language:          metadataString(m.Metadata, languageKey),
 	}
 }
 
+// setDerivedFields sets the fields of a memory built by the client that the
+// store derives from its content and metadata, as fromStorageMemory does.
+func setDerivedFields(m *Memory) {
+	m.Hash = ContentHash(m.Content)
+	m.RunID = metadataString(m.Metadata, runIDKey)
+	m.Scope = MemoryScope(metadataString(m.Metadata, scopeKey))
+	m.MemoryType = metadataString(m.Metadata, memoryTypeKey)
+	m.Language = metadataString(m.Metadata, languageKey)
+}
+
+// metadataString returns metadata[key] if it is a string, and "" otherwise.
+func metadataString(metadata map[string]interface{}, key string) string {
+	value, _ := metadata[key].(string)
+	return value
+}
+
 // fromStorageMemories converts a slice of storage.Memory to a slice of core.Memory.
 //
 // This function is used internally for batch conversion between package types.
@@ -78,7 +113,50 @@ func fromIntelligenceMemory(m *intelligence.Memory) *Memory {
 		RetentionStrength: m.RetentionStrength,
 		LastAccessedAt:    m.LastAccessedAt,
 		Score:             m.Score,
+		Sources:           sourcesFromMetadata(m.Metadata),
+		Entities:          entitiesFromMetadata(m.Metadata),
+		SharedFrom:        shareConsentFromMetadata(m.Metadata),
+		Hash:              ContentHash(m.Content),
+		RunID:             metadataString(m.Metadata, runIDKey),
+		Scope:             MemoryScope(metadataString(m.Metadata, scopeKey)),
+		MemoryType:        metadataString(m.Metadata, memoryTypeKey),
+		Language:          metadataString(m.Metadata, languageKey),
+	}
+}
+
+// toStorageTimeRange builds a storage.TimeRange from created/updated bounds.
+//
+// Returns nil if no bounds are set, so backends can skip the time predicates entirely.
+func toStorageTimeRange(createdAfter, createdBefore, updatedAfter, updatedBefore time.Time) *storage.TimeRange {
+	timeRange := &storage.TimeRange{
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+		UpdatedAfter:  updatedAfter,
+		UpdatedBefore: updatedBefore,
 	}
+	if timeRange.IsZero() {
+		return nil
+	}
+	return timeRange
+}
+
+// normalizeTags trims whitespace, drops empty tags and removes duplicates
+// while keeping the original order.
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
 }
 
 // memoriesToMaps converts Memory structs to map[string]interface{} for intelligent processing.
@@ -119,6 +197,15 @@ func memoriesToMaps(memories []*Memory) []map[string]interface{} {
 		if mem.Score != 0 {
 			m["score"] = mem.Score
 		}
+		if mem.Tags != nil {
+			m["tags"] = mem.Tags
+		}
+		if mem.ExpiresAt != nil {
+			m["expires_at"] = *mem.ExpiresAt
+		}
+		if mem.Hash != "" {
+			m["hash"] = mem.Hash
+		}
 
 		results[i] = m
 	}
@@ -162,6 +249,14 @@ func mapsToMemories(results []map[string]interface{}) []*Memory {
 		} else {
 			mem.Metadata = make(map[string]interface{})
 		}
+		mem.Sources = sourcesFromMetadata(mem.Metadata)
+		mem.Entities = entitiesFromMetadata(mem.Metadata)
+		mem.SharedFrom = shareConsentFromMetadata(mem.Metadata)
+		mem.Hash, _ = r["hash"].(string)
+		mem.RunID = metadataString(mem.Metadata, runIDKey)
+		mem.Scope = MemoryScope(metadataString(mem.Metadata, scopeKey))
+		mem.MemoryType = metadataString(mem.Metadata, memoryTypeKey)
+		mem.Language = metadataString(mem.Metadata, languageKey)
 		if createdAt, ok := r["created_at"].(time.Time); ok {
 			mem.CreatedAt = createdAt
 		}
@@ -177,6 +272,12 @@ func mapsToMemories(results []map[string]interface{}) []*Memory {
 		if score, ok := r["score"].(float64); ok {
 			mem.Score = score
 		}
+		if tags, ok := r["tags"].([]string); ok {
+			mem.Tags = tags
+		}
+		if expiresAt, ok := r["expires_at"].(time.Time); ok {
+			mem.ExpiresAt = &expiresAt
+		}
 
 		// Add intelligent processing scores to metadata
 		if relevanceScore, ok := r["relevance_score"].(float64); ok {
@@ -187,6 +288,7 @@ func mapsToMemories(results []map[string]interface{}) []*Memory {
 		}
 		if finalScore, ok := r["final_score"].(float64); ok {
 			mem.Metadata["final_score"] = finalScore
+			mem.ScoreComponents = scoreComponentsFromMap(r, mem.Score, finalScore)
 			// Use final_score as the new score for ranking
 			mem.Score = finalScore
 		}
@@ -195,3 +297,58 @@ func mapsToMemories(results []map[string]interface{}) []*Memory {
 	}
 	return memories
 }
+
+// scoreComponentsFromMap collects the component scores set by intelligent ranking.
+func scoreComponentsFromMap(r map[string]interface{}, similarity, finalScore float64) *ScoreComponents {
+	components := &ScoreComponents{
+		Similarity: similarity,
+		Final:      finalScore,
+	}
+	components.Relevance, _ = r["relevance_score"].(float64)
+	components.Retention, _ = r["decay_factor"].(float64)
+	if recency, ok := r["recency_score"].(float64); ok {
+		components.Recency = &recency
+	}
+	if importance, ok := r["importance_score"].(float64); ok {
+		components.Importance = &importance
+	}
+	return components
+}
+
+// toIntelligenceRanking converts core.RankingConfig to intelligence.RankingConfig.
+// Returns nil if ranking is nil; zero weights and half-life fall back to the defaults.
+func toIntelligenceRanking(ranking *RankingConfig) *intelligence.RankingConfig {
+	if ranking == nil {
+		return nil
+	}
+	result := intelligence.DefaultRankingConfig()
+	if ranking.SimilarityWeight != 0 || ranking.RecencyWeight != 0 || ranking.ImportanceWeight != 0 || ranking.RetentionWeight != 0 {
+		result.SimilarityWeight = ranking.SimilarityWeight
+		result.RecencyWeight = ranking.RecencyWeight
+		result.ImportanceWeight = ranking.ImportanceWeight
+		result.RetentionWeight = ranking.RetentionWeight
+	}
+	if ranking.RecencyHalfLifeHours > 0 {
+		result.RecencyHalfLife = time.Duration(ranking.RecencyHalfLifeHours * float64(time.Hour))
+	}
+	return result
+}
+
+// toIntelligencePolicies converts retention policies to the intelligence
+// package type (nil if there are none).
+func toIntelligencePolicies(policies map[string]RetentionPolicy) map[string]*intelligence.RetentionPolicy {
+	if len(policies) == 0 {
+		return nil
+	}
+	result := make(map[string]*intelligence.RetentionPolicy, len(policies))
+	for name, policy := range policies {
+		result[name] = &intelligence.RetentionPolicy{
+			DecayRate:           policy.DecayRate,
+			ReinforcementFactor: policy.ReinforcementFactor,
+			WorkingThreshold:    policy.WorkingThreshold,
+			ShortTermThreshold:  policy.ShortTermThreshold,
+			LongTermThreshold:   policy.LongTermThreshold,
+		}
+	}
+	return result
+}
@@ -0,0 +1,146 @@
+package core
+
+import (
+	"context"
+	"fmt"
+)
+
+// UserSearchResults are the results of SearchAcrossUsers for one user.
+type UserSearchResults struct {
+	// UserID is the user of the memories (empty for memories without a
+	// user).
+	UserID string `json:"user_id"`
+
+	// Memories are the user's results, most relevant first.
+	Memories []*Memory `json:"memories"`
+
+	// TopScore is the score of the user's most relevant result.
+	TopScore float64 `json:"top_score"`
+}
+
+// SearchAcrossUsers searches the memories of every user and groups the
+// results per user, e.g. for trust and safety investigations. Because it
+// reads other users' data, it must be explicitly allowed with
+// WithAllowCrossUser(true), and it is recorded in the audit log if enabled
+// (see AuditConfig), attributed to the actor of ctx (see ContextWithActor);
+// it is not run if it cannot be recorded.
+//
+// It accepts the options of Search except WithUserIDForSearch. WithLimit
+// caps the total number of results, not the number per user.
+//
+// Parameters:
+//   - ctx: Context for cancellation, carrying the actor
+//   - query: Search query (required)
+//   - opts: WithAllowCrossUser(true) (required) and other search options
+//
+// Returns the results grouped per user, the user with the most relevant
+// result first, or an error wrapping ErrInvalidInput if cross-user search
+// is not allowed.
+//
+// Example:
+//
+//	ctx = core.ContextWithActor(ctx, "trust-safety@example.com")
+//	groups, err := client.SearchAcrossUsers(ctx, "payment card numbers",
+//	    core.WithAllowCrossUser(true),
+//	    core.WithLimit(100),
+//	)
+//	for _, group := range groups {
+//	    log.Printf("%s: %d memories", group.UserID, len(group.Memories))
+//	}
+func (c *Client) SearchAcrossUsers(ctx context.Context, query string, opts ...SearchOption) ([]*UserSearchResults, error) {
+	searchOpts := applySearchOptions(opts)
+	if !searchOpts.AllowCrossUser {
+		return nil, NewMemoryError("SearchAcrossUsers",
+			fmt.Errorf("%w: cross-user search requires WithAllowCrossUser(true)", ErrInvalidInput))
+	}
+	if searchOpts.UserID != "" {
+		return nil, NewMemoryError("SearchAcrossUsers",
+			fmt.Errorf("%w: use Search to search the memories of one user", ErrInvalidInput))
+	}
+	if query == "" {
+		return nil, NewMemoryError("SearchAcrossUsers", fmt.Errorf("%w: query is required", ErrInvalidInput))
+	}
+
+	ctx, err := c.begin(ctx, "SearchAcrossUsers")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if err := c.checkUnscopedSearch(ctx, "SearchAcrossUsers", query, searchOpts); err != nil {
+		return nil, NewMemoryError("SearchAcrossUsers", err)
+	}
+
+	memories, err := c.search(ctx, query, searchOpts, nil)
+	if err != nil {
+		return nil, NewMemoryError("SearchAcrossUsers", err)
+	}
+	return groupByUser(memories), nil
+}
+
+// checkUnscopedSearch checks a search as operation op, see checkUnscopedRead,
+// with the query, limit, agent and filters of the search.
+func (c *Client) checkUnscopedSearch(ctx context.Context, op, query string, searchOpts *SearchOptions) error {
+	if searchOpts.UserID != "" {
+		return nil
+	}
+	params := map[string]interface{}{"query": query, "limit": searchOpts.Limit}
+	if searchOpts.AgentID != "" {
+		params["agent_id"] = searchOpts.AgentID
+	}
+	if len(searchOpts.Filters) > 0 {
+		params["filters"] = searchOpts.Filters
+	}
+	return c.checkUnscopedRead(ctx, op, searchOpts.AllowCrossUser, "WithAllowCrossUser", params)
+}
+
+// checkUnscopedGetAll checks a listing as operation op, see
+// checkUnscopedRead, with the limit, offset, agent, tags and filters of the
+// listing.
+func (c *Client) checkUnscopedGetAll(ctx context.Context, op string, getAllOpts *GetAllOptions) error {
+	if getAllOpts.UserID != "" {
+		return nil
+	}
+	params := map[string]interface{}{"limit": getAllOpts.Limit, "offset": getAllOpts.Offset}
+	if getAllOpts.AgentID != "" {
+		params["agent_id"] = getAllOpts.AgentID
+	}
+	if len(getAllOpts.Tags) > 0 {
+		params["tags"] = getAllOpts.Tags
+	}
+	if len(getAllOpts.Filters) > 0 {
+		params["filters"] = getAllOpts.Filters
+	}
+	return c.checkUnscopedRead(ctx, op, getAllOpts.AllowCrossUser, "WithAllowCrossUserForGetAll", params)
+}
+
+// checkUnscopedRead checks a read without a user ID, which reads the
+// memories of every user: it fails with ErrInvalidInput unless allow is set
+// by the option named option, and records the read in the audit log as
+// operation op with params if the log is enabled.
+func (c *Client) checkUnscopedRead(ctx context.Context, op string, allow bool, option string, params map[string]interface{}) error {
+	if !allow {
+		return fmt.Errorf("%w: reading the memories of every user requires a user ID or %s(true)", ErrInvalidInput, option)
+	}
+	return c.audit(ctx, op, "", params)
+}
+
+// groupByUser groups memories, most relevant first, per user, keeping
+// their order within each group.
+func groupByUser(memories []*Memory) []*UserSearchResults {
+	var groups []*UserSearchResults
+	byUser := make(map[string]*UserSearchResults)
+	for _, memory := range memories {
+		group, ok := byUser[memory.UserID]
+		if !ok {
+			group = &UserSearchResults{UserID: memory.UserID, TopScore: memory.Score}
+			byUser[memory.UserID] = group
+			groups = append(groups, group)
+		}
+		group.Memories = append(group.Memories, memory)
+	}
+	return groups
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// DeleteWhereResult reports the outcome of DeleteWhere.
+type DeleteWhereResult struct {
+	// Matched is the number of memories matching the filters.
+	Matched int64 `json:"matched"`
+
+	// Deleted is the number of deleted memories (0 for a dry run).
+	Deleted int64 `json:"deleted"`
+
+	// DryRun is true when the memories were only counted, because no count
+	// was confirmed with WithConfirmedCount.
+	DryRun bool `json:"dry_run"`
+}
+
+// DeleteWhere deletes the memories whose metadata matches filters, further
+// restricted by the user, agent, time and tag options, with a single DELETE
+// statement instead of reading and deleting the memories one by one.
+//
+// Deleting is a two-step operation. Without WithConfirmedCount, DeleteWhere
+// is a dry run: it only counts the matching memories. Passing that count
+// with WithConfirmedCount then deletes them, unless the number of matching
+// memories changed in between: nothing is deleted then, and ErrCountMismatch
+// is returned with the new count in the result. At least one filter or
+// option is required; use DeleteAll to delete every memory.
+//
+// A successful deletion publishes a single memory.deleted event with the
+// user and agent filter and the number of deleted memories.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - filters: Metadata key/value pairs the memories must all match (may be nil)
+//   - opts: Optional parameters (UserID, AgentID, time range, tags, confirmed count)
+//
+// Returns the counts, or an error.
+//
+// Example:
+//
+//	filters := map[string]interface{}{"type": "debug"}
+//	plan, err := client.DeleteWhere(ctx, filters)
+//	if err != nil {
+//	    return err
+//	}
+//	log.Printf("deleting %d debug memories", plan.Matched)
+//	result, err := client.DeleteWhere(ctx, filters, core.WithConfirmedCount(plan.Matched))
+func (c *Client) DeleteWhere(ctx context.Context, filters map[string]interface{}, opts ...DeleteWhereOption) (*DeleteWhereResult, error) {
+	deleteWhereOpts := applyDeleteWhereOptions(opts)
+
+	storageOpts := &storage.DeleteWhereOptions{
+		UserID:  deleteWhereOpts.UserID,
+		AgentID: deleteWhereOpts.AgentID,
+		TimeRange: toStorageTimeRange(
+			deleteWhereOpts.CreatedAfter, deleteWhereOpts.CreatedBefore,
+			deleteWhereOpts.UpdatedAfter, deleteWhereOpts.UpdatedBefore,
+		),
+		Tags:    deleteWhereOpts.Tags,
+		Filters: filters,
+	}
+	if len(filters) == 0 && len(storageOpts.Tags) == 0 && storageOpts.TimeRange.IsZero() &&
+		storageOpts.UserID == "" && storageOpts.AgentID == "" {
+		return nil, NewMemoryError("DeleteWhere", fmt.Errorf("%w: at least one filter is required", ErrInvalidInput))
+	}
+
+	ctx, err := c.begin(ctx, "DeleteWhere")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	if deleteWhereOpts.ConfirmedCount == nil {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+
+		storageOpts.DryRun = true
+		matched, err := c.storage.DeleteWhere(ctx, storageOpts)
+		if err != nil {
+			return nil, NewMemoryError("DeleteWhere", err)
+		}
+		return &DeleteWhereResult{Matched: matched, DryRun: true}, nil
+	}
+
+	events := c.recordEvents(ctx, "DeleteWhere")
+	defer events.publish()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	storageOpts.ExpectedCount = *deleteWhereOpts.ConfirmedCount
+	deleted, err := c.storage.DeleteWhere(ctx, storageOpts)
+	if err != nil {
+		return &DeleteWhereResult{Matched: deleted}, NewMemoryError("DeleteWhere", err)
+	}
+	if deleted > 0 {
+		events.add(&Event{
+			Type:    EventDeleted,
+			UserID:  storageOpts.UserID,
+			AgentID: storageOpts.AgentID,
+			Count:   deleted,
+			Where:   newEventWhere(storageOpts),
+		})
+	}
+
+	return &DeleteWhereResult{Matched: deleted, Deleted: deleted}, nil
+}
+
+// newEventWhere returns the filter of the DeleteWhere event of opts.
+func newEventWhere(opts *storage.DeleteWhereOptions) *EventWhere {
+	where := &EventWhere{Filters: opts.Filters, Tags: opts.Tags}
+	if r := opts.TimeRange; r != nil {
+		where.CreatedAfter = r.CreatedAfter
+		where.CreatedBefore = r.CreatedBefore
+		where.UpdatedAfter = r.UpdatedAfter
+		where.UpdatedBefore = r.UpdatedBefore
+	}
+	return where
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"context"
+	"errors"
+
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// DuplicatePair is a pair of memories whose similarity meets the duplicate threshold.
+type DuplicatePair struct {
+	// FirstID is the ID of the first memory in the pair.
+	FirstID int64 `json:"first_id"`
+
+	// SecondID is the ID of the second memory in the pair.
+	SecondID int64 `json:"second_id"`
+
+	// Similarity is the cosine similarity between the two memories.
+	Similarity float64 `json:"similarity"`
+}
+
+// DuplicateCluster is a group of memories that are near-duplicates of each other.
+type DuplicateCluster struct {
+	// Memories contains the memories in the cluster.
+	Memories []*Memory `json:"memories"`
+
+	// Pairs contains the similar pairs that link the cluster, highest similarity first.
+	Pairs []DuplicatePair `json:"pairs"`
+}
+
+// FindDuplicates reports clusters of near-duplicate memories without modifying them.
+//
+// Unlike Add with Infer, which merges duplicates silently, this is a read-only audit
+// so operators can review duplicates before merging. Memories are compared using
+// their stored embeddings; no embedding or LLM calls are made.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userID: User whose memories are checked (empty for all users)
+//   - threshold: Similarity threshold (0.0-1.0). If 0, the configured
+//     Intelligence.DuplicateThreshold (or 0.95) is used.
+//
+// Returns clusters with at least two memories, largest first.
+//
+// Example:
+//
+//	clusters, err := client.FindDuplicates(ctx, "user_001", 0.9)
+//	for _, cluster := range clusters {
+//	    for _, mem := range cluster.Memories {
+//	        fmt.Println(mem.ID, mem.Content)
+//	    }
+//	}
+func (c *Client) FindDuplicates(ctx context.Context, userID string, threshold float64) ([]*DuplicateCluster, error) {
+	ctx, err := c.begin(ctx, "FindDuplicates")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	dedupManager := c.dedupManager
+	if dedupManager == nil {
+		// Intelligence is disabled; the audit still works with the default threshold
+		dedupManager = intelligence.NewDedupManager(c.storage, 0)
+	}
+
+	clusters, err := dedupManager.FindDuplicates(ctx, userID, threshold)
+	if err != nil {
+		return nil, NewMemoryError("FindDuplicates", err)
+	}
+
+	result := make([]*DuplicateCluster, len(clusters))
+	for i, cluster := range clusters {
+		memories := make([]*Memory, len(cluster.Memories))
+		for j, mem := range cluster.Memories {
+			memories[j] = fromIntelligenceMemory(mem)
+		}
+		pairs := make([]DuplicatePair, len(cluster.Pairs))
+		for j, pair := range cluster.Pairs {
+			pairs[j] = DuplicatePair{
+				FirstID:    pair.FirstID,
+				SecondID:   pair.SecondID,
+				Similarity: pair.Similarity,
+			}
+		}
+		result[i] = &DuplicateCluster{Memories: memories, Pairs: pairs}
+	}
+
+	return result, nil
+}
+
+// ContentHash returns the hash of content carried by Memory.Hash and looked
+// up by GetByHash: its hex-encoded MD5, as computed by the Python SDK. Every
+// storage backend stores and indexes it.
+func ContentHash(content string) string {
+	return storage.ContentHash(content)
+}
+
+// GetByHash retrieves a memory whose content has the given hash (see
+// ContentHash) with optional access control. If several memories have that
+// content, any of them is returned.
+//
+// Example:
+//
+//	memory, err := client.GetByHash(ctx, core.ContentHash("User prefers dark mode"),
+//	    core.WithUserIDForGet("user_001"))
+func (c *Client) GetByHash(ctx context.Context, hash string, opts ...GetOption) (*Memory, error) {
+	ctx, err := c.begin(ctx, "GetByHash")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	getOpts := applyGetOptions(opts)
+
+	memory, err := c.storage.GetByHash(ctx, hash, &storage.GetOptions{
+		UserID:  getOpts.UserID,
+		AgentID: getOpts.AgentID,
+		Now:     c.now(),
+	})
+	if err != nil {
+		return nil, NewMemoryError("GetByHash", err)
+	}
+
+	return fromStorageMemory(memory), nil
+}
+
+// exactDuplicate returns the memory of the user (and agent, if set) of opts
+// whose content is content, or nil if there is none. Memories are looked up
+// by content hash, and chunks of long memories are ignored.
+func (c *Client) exactDuplicate(ctx context.Context, content string, opts *AddOptions) (*Memory, error) {
+	stored, err := c.storage.GetByHash(ctx, ContentHash(content), &storage.GetOptions{
+		UserID:  opts.UserID,
+		AgentID: opts.AgentID,
+		Now:     c.now(),
+	})
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// An empty user ID does not filter the lookup
+	if stored.UserID != opts.UserID || stored.ParentID != 0 || stored.Content != content {
+		return nil, nil
+	}
+	return fromStorageMemory(stored), nil
+}
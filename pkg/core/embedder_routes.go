@@ -0,0 +1,197 @@
+package core
+
+import (
+	"context"
+
+	"github.com/oceanbase/powermem-go/pkg/embedder"
+	"github.com/oceanbase/powermem-go/pkg/secrets"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+const (
+	// embedderKey is the metadata key naming the EmbedderRoute that embedded
+	// a memory. Memories embedded by Config.Embedder have none.
+	embedderKey = "embedder"
+
+	// languageKey is the metadata key of the language of a memory, which
+	// EmbedderRoute.Languages are matched against.
+	languageKey = "language"
+)
+
+// embedderRoute is a configured EmbedderRoute with its embedder.
+type embedderRoute struct {
+	name        string
+	memoryTypes map[string]bool
+	languages   map[string]bool
+	provider    embedder.Provider
+}
+
+// newEmbedderRoutes creates the embedders of routes. The embedders already
+// created are closed if one cannot be.
+func newEmbedderRoutes(routes []EmbedderRoute, secretsProvider secrets.Provider) ([]*embedderRoute, error) {
+	created := make([]*embedderRoute, 0, len(routes))
+	for _, route := range routes {
+		provider, err := newEmbedderProvider(route.Embedder, secretsProvider)
+		if err != nil {
+			closeEmbedderRoutes(created)
+			return nil, err
+		}
+		r := &embedderRoute{
+			name:        route.Name,
+			memoryTypes: make(map[string]bool, len(route.MemoryTypes)),
+			languages:   make(map[string]bool, len(route.Languages)),
+			provider:    provider,
+		}
+		for _, memoryType := range route.MemoryTypes {
+			r.memoryTypes[memoryType] = true
+		}
+		for _, language := range route.Languages {
+			r.languages[language] = true
+		}
+		created = append(created, r)
+	}
+	return created, nil
+}
+
+// closeEmbedderRoutes closes the embedders of routes and returns the first
+// error.
+func closeEmbedderRoutes(routes []*embedderRoute) error {
+	var first error
+	for _, route := range routes {
+		if err := route.provider.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// routeFor returns the route embedding a new memory with metadata: the
+// first route listing its memory type, or else the first listing its
+// language, or nil for Config.Embedder.
+func (c *Client) routeFor(metadata map[string]interface{}) *embedderRoute {
+	if memoryType := metadataString(metadata, memoryTypeKey); memoryType != "" {
+		for _, route := range c.embedderRoutes {
+			if route.memoryTypes[memoryType] {
+				return route
+			}
+		}
+	}
+	if language := metadataString(metadata, languageKey); language != "" {
+		for _, route := range c.embedderRoutes {
+			if route.languages[language] {
+				return route
+			}
+		}
+	}
+	return nil
+}
+
+// routeOf returns the route that embedded a stored memory with metadata, or
+// nil for Config.Embedder, including for routes no longer configured.
+func (c *Client) routeOf(metadata map[string]interface{}) *embedderRoute {
+	name := metadataString(metadata, embedderKey)
+	if name == "" {
+		return nil
+	}
+	for _, route := range c.embedderRoutes {
+		if route.name == name {
+			return route
+		}
+	}
+	return nil
+}
+
+// embedderOf returns the embedder of route, or Config.Embedder's if route is
+// nil.
+func (c *Client) embedderOf(route *embedderRoute) embedder.Provider {
+	if route == nil {
+		return c.embedder
+	}
+	return route.provider
+}
+
+// setRoute records in metadata that route embedded the memory.
+func setRoute(metadata map[string]interface{}, route *embedderRoute) {
+	if route == nil {
+		delete(metadata, embedderKey)
+		return
+	}
+	metadata[embedderKey] = route.name
+}
+
+// searchRoutes returns the routes whose memories may match filters, with nil
+// for Config.Embedder. A memory_type filter selects a single route if a
+// route lists the memory type, or if filters also have a language;
+// otherwise every route may hold matching memories.
+func (c *Client) searchRoutes(filters map[string]interface{}) []*embedderRoute {
+	if len(c.embedderRoutes) == 0 {
+		return []*embedderRoute{nil}
+	}
+	if metadataString(filters, memoryTypeKey) != "" {
+		route := c.routeFor(filters)
+		if route != nil || metadataString(filters, languageKey) != "" {
+			return []*embedderRoute{route}
+		}
+	}
+	return append([]*embedderRoute{nil}, c.embedderRoutes...)
+}
+
+// singleRoute returns the route searched by searches that cannot merge the
+// results of several embedders, SearchMemories and SearchStream: the single
+// route selected by filters, or nil for Config.Embedder.
+func (c *Client) singleRoute(filters map[string]interface{}) *embedderRoute {
+	routes := c.searchRoutes(filters)
+	if len(routes) == 1 {
+		return routes[0]
+	}
+	return nil
+}
+
+// routeSearchOptions returns storageOpts restricted to the memories of
+// route. Config.Embedder's memories cannot be selected by a filter; see
+// inRoute.
+func routeSearchOptions(route *embedderRoute, storageOpts *storage.SearchOptions) *storage.SearchOptions {
+	if route == nil {
+		return storageOpts
+	}
+	opts := *storageOpts
+	opts.Filters = make(map[string]interface{}, len(storageOpts.Filters)+1)
+	for k, v := range storageOpts.Filters {
+		opts.Filters[k] = v
+	}
+	opts.Filters[embedderKey] = route.name
+	return &opts
+}
+
+// inRoute removes the memories that were not embedded by route from the
+// results of a search with its embedder.
+func (c *Client) inRoute(route *embedderRoute, memories []*storage.Memory) []*storage.Memory {
+	if route != nil || len(c.embedderRoutes) == 0 {
+		return memories
+	}
+	kept := memories[:0]
+	for _, memory := range memories {
+		if c.routeOf(memory.Metadata) == nil {
+			kept = append(kept, memory)
+		}
+	}
+	return kept
+}
+
+// searchRouted runs searchStorage with the embedder of every route whose
+// memories may match storageOpts, and merges the results by score.
+func (c *Client) searchRouted(ctx context.Context, query string, mode RetrievalMode, storageOpts *storage.SearchOptions, diag *SearchDiagnostics) ([]*storage.Memory, error) {
+	routes := c.searchRoutes(storageOpts.Filters)
+	lists := make([][]*storage.Memory, 0, len(routes))
+	for _, route := range routes {
+		memories, err := c.searchStorage(ctx, route, query, mode, routeSearchOptions(route, storageOpts), diag)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, c.inRoute(route, memories))
+	}
+	if len(lists) == 1 {
+		return lists[0], nil
+	}
+	return fuseSearchResults(storageOpts.Limit, lists...), nil
+}
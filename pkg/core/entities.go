@@ -0,0 +1,120 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// entitiesKey is the metadata key holding the entities a memory mentions.
+const entitiesKey = "entities"
+
+// entitiesMetadata returns the metadata value of entities, as read back from
+// the store.
+func entitiesMetadata(entities []string) []interface{} {
+	value := make([]interface{}, len(entities))
+	for i, entity := range entities {
+		value[i] = entity
+	}
+	return value
+}
+
+// entitiesFromMetadata returns the entities recorded in metadata (nil if none).
+func entitiesFromMetadata(metadata map[string]interface{}) []string {
+	switch value := metadata[entitiesKey].(type) {
+	case []string:
+		return value
+	case []interface{}:
+		entities := make([]string, 0, len(value))
+		for _, item := range value {
+			if entity, ok := item.(string); ok {
+				entities = append(entities, entity)
+			}
+		}
+		return entities
+	}
+	return nil
+}
+
+// mergeEntities returns entities followed by the entities of more that are not
+// in entities, trimmed and compared case-insensitively.
+func mergeEntities(entities []string, more ...string) []string {
+	var merged []string
+	seen := make(map[string]bool, len(entities)+len(more))
+	for _, entity := range append(append([]string(nil), entities...), more...) {
+		entity = strings.TrimSpace(entity)
+		if entity == "" || seen[strings.ToLower(entity)] {
+			continue
+		}
+		seen[strings.ToLower(entity)] = true
+		merged = append(merged, entity)
+	}
+	return merged
+}
+
+// SearchByEntity returns the memories that mention an entity (a person, place,
+// organization...), newest first.
+//
+// Memories are matched on their entity annotations (Memory.Entities, stored in
+// metadata["entities"]) rather than on the similarity of the entity name to
+// their content, so "Alice" finds "Went hiking with Alice" but not memories
+// about other people that happen to embed close to the name. Annotations are
+// set by the fact extraction of IntelligentAdd, by WithEntities and by
+// RememberRelationship; they are compared case-insensitively.
+//
+// Supports the filtering options of Search (user, agent, filters, tags, time
+// range, limit). WithMinScore has no effect since every match has a score of 1.0.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - entity: Name of the entity
+//   - opts: Optional Search options
+//
+// Example:
+//
+//	memories, err := client.SearchByEntity(ctx, "Alice", core.WithUserIDForSearch("user_001"))
+func (c *Client) SearchByEntity(ctx context.Context, entity string, opts ...SearchOption) ([]*Memory, error) {
+	ctx, err := c.begin(ctx, "SearchByEntity")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entity = strings.TrimSpace(entity)
+	if entity == "" {
+		return nil, NewMemoryError("SearchByEntity", fmt.Errorf("%w: entity is required", ErrInvalidInput))
+	}
+
+	searchOpts := applySearchOptions(opts)
+	if err := c.checkUnscopedSearch(ctx, "SearchByEntity", entity, searchOpts); err != nil {
+		return nil, NewMemoryError("SearchByEntity", err)
+	}
+
+	memories, err := c.storage.GetAll(ctx, &storage.GetAllOptions{
+		UserID:  searchOpts.UserID,
+		AgentID: searchOpts.AgentID,
+		Limit:   searchOpts.Limit,
+		TimeRange: toStorageTimeRange(
+			searchOpts.CreatedAfter, searchOpts.CreatedBefore,
+			searchOpts.UpdatedAfter, searchOpts.UpdatedBefore,
+		),
+		Tags:     searchOpts.Tags,
+		Filters:  searchOpts.Filters,
+		Entities: []string{entity},
+		Now:      c.now(),
+	})
+	if err != nil {
+		return nil, NewMemoryError("SearchByEntity", err)
+	}
+
+	results := fromStorageMemories(c.injectionGuard.screen(visible(memories, searchOpts)))
+	for _, memory := range results {
+		memory.Score = 1.0
+	}
+	return results, nil
+}
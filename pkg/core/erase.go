@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ErasureReport describes the data erased by EraseUser.
+type ErasureReport struct {
+	// UserID is the erased user.
+	UserID string `json:"user_id"`
+
+	// Memories is the number of deleted memories.
+	Memories int64 `json:"memories"`
+
+	// Changes is the number of deleted change log entries.
+	Changes int64 `json:"changes"`
+
+	// Verified is true when no data of the user was found after the erasure.
+	Verified bool `json:"verified"`
+
+	// StartedAt and CompletedAt bound the erasure.
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// EraseUser permanently erases the data of a user, e.g. for a GDPR erasure
+// request: the user's memories, their entries in the change log (the
+// event history) and their team memberships are deleted in a single
+// transaction, then the store is checked for data of the user left.
+//
+// Unlike DeleteAll, the erasure is not recorded as memory.deleted events
+// with the deleted content. A single memory.erased event with the user ID
+// and the number of deleted memories is published instead, and appended to
+// the change log, so that subscribers, webhooks and replicas erase their
+// copies.
+//
+// With the audit log enabled (see AuditConfig), the erasure is recorded in
+// it first, and is not run if it cannot be recorded. The audit log is not
+// erased: its entries only hold the user ID and operation parameters.
+//
+// Memories written for the user concurrently by another client may survive
+// the erasure: the report is then not verified and ErrErasureIncomplete is
+// returned with it. Pending AsyncClient operations for the user should be
+// waited for first.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userID: User whose data is erased (required)
+//
+// Returns the erasure report, or an error.
+//
+// Example:
+//
+//	report, err := client.EraseUser(ctx, "user_001")
+//	if err != nil {
+//	    return err
+//	}
+//	log.Printf("erased %d memories of %s", report.Memories, report.UserID)
+func (c *Client) EraseUser(ctx context.Context, userID string) (*ErasureReport, error) {
+	if userID == "" {
+		return nil, NewMemoryError("EraseUser", fmt.Errorf("%w: user ID is required", ErrInvalidInput))
+	}
+
+	ctx, err := c.begin(ctx, "EraseUser")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	events := c.recordEvents(ctx, "EraseUser")
+	defer events.publish()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.audit(ctx, "EraseUser", userID, map[string]interface{}{"user_id": userID}); err != nil {
+		return nil, NewMemoryError("EraseUser", err)
+	}
+
+	report := &ErasureReport{UserID: userID, StartedAt: time.Now()}
+	counts, err := c.storage.EraseUser(ctx, userID)
+	if err != nil {
+		return nil, NewMemoryError("EraseUser", err)
+	}
+	report.Memories = counts.Memories
+	report.Changes = counts.Changes
+	report.Verified = counts.Remaining == 0
+	report.CompletedAt = time.Now()
+
+	events.add(&Event{Type: EventErased, UserID: userID, Count: counts.Memories})
+
+	if !report.Verified {
+		return report, NewMemoryError("EraseUser",
+			fmt.Errorf("%w: %d records of user %s left", ErrErasureIncomplete, counts.Remaining, userID))
+	}
+	return report, nil
+}
@@ -4,6 +4,10 @@ package core
 import (
 	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	"github.com/oceanbase/powermem-go/pkg/storage/collections"
 )
 
 // Predefined errors for common failure scenarios.
@@ -20,6 +24,10 @@ var (
 	// ErrEmbeddingFailed indicates that embedding generation failed.
 	ErrEmbeddingFailed = errors.New("embedding generation failed")
 
+	// ErrDuplicateUID indicates that the string ID given with WithUID is
+	// already taken by another memory.
+	ErrDuplicateUID = storage.ErrDuplicateUID
+
 	// ErrDuplicateMemory indicates that a duplicate memory was detected.
 	ErrDuplicateMemory = errors.New("duplicate memory detected")
 
@@ -31,6 +39,36 @@ var (
 
 	// ErrLLMOperation indicates that an LLM operation failed.
 	ErrLLMOperation = errors.New("llm operation failed")
+
+	// ErrClientClosed indicates that the client has been closed.
+	ErrClientClosed = errors.New("client closed")
+
+	// ErrQueueFull indicates that an async operation was dropped because the queue was full.
+	ErrQueueFull = errors.New("async queue full")
+
+	// ErrBatchAborted indicates that a batch item was not processed because an
+	// earlier item failed and the batch runs with WithBatchFailFast.
+	ErrBatchAborted = errors.New("batch aborted")
+
+	// ErrVersionConflict indicates that a conditional update lost a race with
+	// another writer (see WithExpectedVersion).
+	ErrVersionConflict = storage.ErrVersionConflict
+
+	// ErrErasureIncomplete indicates that data of an erased user was found
+	// after the erasure (see EraseUser).
+	ErrErasureIncomplete = errors.New("erasure incomplete")
+
+	// ErrCountMismatch indicates that DeleteWhere deleted nothing because the
+	// number of matching memories is not the confirmed count.
+	ErrCountMismatch = storage.ErrCountMismatch
+
+	// ErrContentTooLarge indicates that memory content exceeds
+	// ChunkingConfig.MaxContentSize.
+	ErrContentTooLarge = errors.New("content too large")
+
+	// ErrCollectionNotFound indicates that the collection of an operation
+	// does not exist (see ContextWithCollection).
+	ErrCollectionNotFound = collections.ErrNotFound
 )
 
 // MemoryError wraps errors with operation context.
@@ -89,3 +127,50 @@ func NewMemoryError(op string, err error) error {
 		Err: err,
 	}
 }
+
+// FieldError describes a single invalid configuration field.
+type FieldError struct {
+	// Field is the path of the field, using the JSON names of the config,
+	// e.g. "vector_store.config.port".
+	Field string
+
+	// Message describes what is wrong with the field.
+	Message string
+}
+
+// Error returns "<Field>: <Message>".
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError lists every invalid field found by Config.Validate or by
+// decoding a VectorStoreConfig from JSON.
+//
+// It unwraps to ErrInvalidConfig, so both checks work:
+//
+//	if errors.Is(err, core.ErrInvalidConfig) { ... }
+//
+//	var validationErr *core.ValidationError
+//	if errors.As(err, &validationErr) {
+//	    for _, fieldErr := range validationErr.Fields {
+//	        log.Printf("%s: %s", fieldErr.Field, fieldErr.Message)
+//	    }
+//	}
+type ValidationError struct {
+	// Fields contains one entry per invalid field.
+	Fields []*FieldError
+}
+
+// Error returns a message listing all invalid fields.
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Error()
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidConfig, strings.Join(messages, "; "))
+}
+
+// Unwrap returns ErrInvalidConfig.
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidConfig
+}
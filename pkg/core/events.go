@@ -0,0 +1,417 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	"github.com/oceanbase/powermem-go/pkg/webhook"
+)
+
+// EventType identifies the kind of memory mutation an Event reports.
+type EventType string
+
+const (
+	// EventCreated is published when a memory is added.
+	EventCreated EventType = "memory.created"
+
+	// EventUpdated is published when the content or metadata of a memory is
+	// replaced, by Update or by an UPDATE decision of IntelligentAdd.
+	EventUpdated EventType = "memory.updated"
+
+	// EventDeleted is published when memories are deleted, by Delete,
+	// DeleteAll, DeleteWhere, Reset or a DELETE decision of IntelligentAdd.
+	EventDeleted EventType = "memory.deleted"
+
+	// EventMerged is published when Add merges new content into a duplicate
+	// memory instead of adding it.
+	EventMerged EventType = "memory.merged"
+
+	// EventForgotten is published when PurgeExpired removes expired memories,
+	// and when Forget deletes or rewrites a memory. It carries no content:
+	// neither Memory nor Diff is set.
+	EventForgotten EventType = "memory.forgotten"
+
+	// EventErased is published when EraseUser erases the data of a user.
+	EventErased EventType = "memory.erased"
+)
+
+// isEventType reports whether t is a known event type.
+func isEventType(t EventType) bool {
+	switch t {
+	case EventCreated, EventUpdated, EventDeleted, EventMerged, EventForgotten, EventErased:
+		return true
+	}
+	return false
+}
+
+// Event describes a mutation of the memory store.
+//
+// Events about a single memory have MemoryID set. Bulk deletions (DeleteAll,
+// DeleteWhere, Reset), purges (PurgeExpired) and erasures (EraseUser) publish one event
+// without MemoryID: UserID
+// and AgentID then hold the filter of the deletion, Where the rest of the
+// filter of DeleteWhere, and Count the number of memories removed when the
+// store reports it.
+type Event struct {
+	// Type is the kind of mutation.
+	Type EventType `json:"type"`
+
+	// Time is when the mutation completed.
+	Time time.Time `json:"time"`
+
+	// Operation is the client method that made the mutation (e.g. "Add",
+	// "IntelligentAdd", "Update").
+	Operation string `json:"operation"`
+
+	// Actor is who made the mutation, as set with ContextWithActor (empty if
+	// unknown).
+	Actor string `json:"actor,omitempty"`
+
+	// MemoryID and MemoryUID identify the memory (zero for bulk events).
+	MemoryID  int64  `json:"memory_id,omitempty"`
+	MemoryUID string `json:"memory_uid,omitempty"`
+
+	// UserID and AgentID are the owner of the memory, or the filter of a bulk
+	// event.
+	UserID  string `json:"user_id,omitempty"`
+	AgentID string `json:"agent_id,omitempty"`
+
+	// Memory is the memory after the mutation, or before it for deletions
+	// (nil if unknown). Embeddings are omitted.
+	Memory *Memory `json:"memory,omitempty"`
+
+	// Diff is the change made by an update or merge (nil otherwise).
+	Diff *EventDiff `json:"diff,omitempty"`
+
+	// Count is the number of memories removed by a bulk event.
+	Count int64 `json:"count,omitempty"`
+
+	// Where is the rest of the filter of a DeleteWhere event (nil
+	// otherwise).
+	Where *EventWhere `json:"where,omitempty"`
+
+	// Version is the version of the memory after a memory.forgotten event of
+	// Forget: 0 if it was deleted, its new version if it was rewritten.
+	Version int64 `json:"version,omitempty"`
+}
+
+// EventDiff is the change an update or merge made to a memory.
+type EventDiff struct {
+	// OldContent and NewContent are the content before and after the change.
+	OldContent string `json:"old_content"`
+	NewContent string `json:"new_content"`
+
+	// OldMetadata and NewMetadata are the metadata before and after the
+	// change, only set if the metadata changed.
+	OldMetadata map[string]interface{} `json:"old_metadata,omitempty"`
+	NewMetadata map[string]interface{} `json:"new_metadata,omitempty"`
+}
+
+// EventWhere is the filter of a DeleteWhere event besides its user and
+// agent, so that replicas can repeat the deletion (see Client.Sync). Zero
+// times are unbounded.
+type EventWhere struct {
+	Filters map[string]interface{} `json:"filters,omitempty"`
+	Tags    []string               `json:"tags,omitempty"`
+
+	CreatedAfter  time.Time `json:"created_after"`
+	CreatedBefore time.Time `json:"created_before"`
+	UpdatedAfter  time.Time `json:"updated_after"`
+	UpdatedBefore time.Time `json:"updated_before"`
+}
+
+// EventHandler is called with the events of a client; see Client.Subscribe.
+type EventHandler func(event *Event)
+
+// actorKey is the context key of the actor set with ContextWithActor.
+type actorKey struct{}
+
+// ContextWithActor returns a context that attributes the mutations made with
+// it to actor (a user, service or API key name), reported as Event.Actor.
+//
+// Example:
+//
+//	ctx = core.ContextWithActor(ctx, "admin@example.com")
+//	err := client.DeleteAll(ctx, core.WithUserIDForDeleteAll("user_001"))
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set with ContextWithActor, or "".
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// subscription is a handler registered with Subscribe.
+type subscription struct {
+	id      int
+	handler EventHandler
+	types   map[EventType]bool
+}
+
+// eventBus holds the subscriptions of a client.
+type eventBus struct {
+	mu            sync.RWMutex
+	subscriptions []*subscription
+	nextID        int
+
+	// changed is closed (and replaced) when changes are appended to the
+	// change log, to wake up SubscribeChanges.
+	changed chan struct{}
+}
+
+// changeSignal returns a channel that is closed on the next change log
+// append.
+func (b *eventBus) changeSignal() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.changed == nil {
+		b.changed = make(chan struct{})
+	}
+	return b.changed
+}
+
+// signalChange wakes up the waiters of changeSignal.
+func (b *eventBus) signalChange() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.changed != nil {
+		close(b.changed)
+		b.changed = nil
+	}
+}
+
+// Subscribe registers handler to be called with the events of the given
+// types (all events if none are given).
+//
+// Handlers are called in the order they were subscribed, synchronously, in the goroutine of the operation,
+// after the mutation is stored and the client is unlocked, so they may call
+// the client. The operation returns once every handler has returned: slow
+// work should be done asynchronously. A panicking handler is logged and does
+// not affect the operation or other handlers.
+//
+// Returns a function that removes the subscription.
+//
+// Example:
+//
+//	unsubscribe := client.Subscribe(func(event *core.Event) {
+//	    log.Printf("%s %d by %q", event.Type, event.MemoryID, event.Actor)
+//	}, core.EventDeleted, core.EventForgotten)
+//	defer unsubscribe()
+func (c *Client) Subscribe(handler EventHandler, types ...EventType) (unsubscribe func()) {
+	sub := &subscription{handler: handler}
+	if len(types) > 0 {
+		sub.types = make(map[EventType]bool, len(types))
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+
+	c.events.mu.Lock()
+	defer c.events.mu.Unlock()
+	sub.id = c.events.nextID
+	c.events.nextID++
+	c.events.subscriptions = append(c.events.subscriptions, sub)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.events.mu.Lock()
+			defer c.events.mu.Unlock()
+			for i, s := range c.events.subscriptions {
+				if s.id == sub.id {
+					c.events.subscriptions = append(c.events.subscriptions[:i:i], c.events.subscriptions[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+}
+
+// hasSubscribers reports whether any handler is subscribed, so that
+// operations can skip reading state only needed for events.
+func (c *Client) hasSubscribers() bool {
+	c.events.mu.RLock()
+	defer c.events.mu.RUnlock()
+	return len(c.events.subscriptions) > 0
+}
+
+// dispatch calls the subscribed handlers with event.
+func (c *Client) dispatch(event *Event) {
+	c.events.mu.RLock()
+	var handlers []EventHandler
+	for _, sub := range c.events.subscriptions {
+		if sub.types == nil || sub.types[event.Type] {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+	c.events.mu.RUnlock()
+
+	for _, handler := range handlers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Event handler panicked on %s: %v", event.Type, r)
+				}
+			}()
+			handler(event)
+		}()
+	}
+}
+
+// eventRecorder collects the events of one operation until it completes.
+//
+// Events are appended to the change log (if enabled) as they are recorded,
+// while the client is locked, so that the log is in the order of the
+// mutations. Operations create the recorder before locking the client and
+// defer publish, so that the handlers run once the client is unlocked:
+//
+//	events := c.recordEvents(ctx, "Delete")
+//	defer events.publish()
+//	c.mu.Lock()
+//	defer c.mu.Unlock()
+type eventRecorder struct {
+	ctx       context.Context
+	client    *Client
+	operation string
+	actor     string
+	events    []*Event
+
+	// logged is set when an event was appended to the change log.
+	logged bool
+}
+
+// recordEvents starts recording the events of operation op.
+func (c *Client) recordEvents(ctx context.Context, op string) *eventRecorder {
+	return &eventRecorder{ctx: ctx, client: c, operation: op, actor: ActorFromContext(ctx)}
+}
+
+// enabled reports whether the recorded events are used, by subscribers or
+// the change log. It must be called with the client locked.
+func (r *eventRecorder) enabled() bool {
+	return r.client.changeLogEnabled() || r.client.hasSubscribers()
+}
+
+// changeLogEnabled reports whether events are appended to the change log.
+// It must be called with the client locked.
+func (c *Client) changeLogEnabled() bool {
+	return c.config.ChangeLog != nil && c.config.ChangeLog.Enabled
+}
+
+// add records event, filling in the fields common to the operation.
+func (r *eventRecorder) add(event *Event) {
+	event.Time = r.client.now()
+	event.Operation = r.operation
+	event.Actor = r.actor
+	if m := event.Memory; m != nil {
+		event.MemoryID = m.ID
+		event.MemoryUID = m.UID
+		event.UserID = m.UserID
+		event.AgentID = m.AgentID
+		event.Memory = eventMemory(m)
+	}
+	r.events = append(r.events, event)
+
+	if r.client.changeLogEnabled() {
+		// The mutation is already stored: a failure to log it is reported
+		// but does not fail the operation.
+		if err := r.client.appendChange(r.ctx, event); err != nil {
+			log.Printf("Failed to record %s of memory %d in the change log: %v", event.Type, event.MemoryID, err)
+		} else {
+			r.logged = true
+		}
+	}
+}
+
+// publish dispatches the recorded events.
+func (r *eventRecorder) publish() {
+	if r.logged {
+		r.client.events.signalChange()
+	}
+	for _, event := range r.events {
+		r.client.dispatch(event)
+	}
+	r.events = nil
+}
+
+// appendChange appends event to the change log.
+func (c *Client) appendChange(ctx context.Context, event *Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return c.storage.AppendChange(ctx, &storage.Change{
+		Type:      string(event.Type),
+		MemoryID:  event.MemoryID,
+		UserID:    event.UserID,
+		AgentID:   event.AgentID,
+		Payload:   payload,
+		CreatedAt: event.Time,
+	})
+}
+
+// eventMemory returns a copy of memory without embeddings, for an event.
+func eventMemory(memory *Memory) *Memory {
+	copied := *memory
+	copied.Embedding = nil
+	copied.SparseEmbedding = nil
+	return &copied
+}
+
+// newEventDiff returns the diff between two states of a memory.
+func newEventDiff(before, after *Memory) *EventDiff {
+	diff := &EventDiff{OldContent: before.Content, NewContent: after.Content}
+	if !reflect.DeepEqual(before.Metadata, after.Metadata) {
+		diff.OldMetadata = before.Metadata
+		diff.NewMetadata = after.Metadata
+	}
+	return diff
+}
+
+// webhookCloseTimeout is how long Close waits for pending webhook deliveries.
+const webhookCloseTimeout = 5 * time.Second
+
+// initWebhooks starts the webhooks of cfg and subscribes them to the events.
+func (c *Client) initWebhooks(cfg []WebhookConfig) error {
+	for _, hook := range cfg {
+		sender, err := webhook.NewClient(&webhook.Config{
+			URL:        hook.URL,
+			Secret:     hook.Secret,
+			Timeout:    time.Duration(hook.TimeoutSeconds * float64(time.Second)),
+			MaxRetries: hook.MaxRetries,
+		})
+		if err != nil {
+			c.closeWebhooks()
+			return err
+		}
+		c.webhooks = append(c.webhooks, sender)
+
+		url := hook.URL
+		c.Subscribe(func(event *Event) {
+			if err := sender.Send(string(event.Type), event); err != nil {
+				log.Printf("Dropped %s webhook to %s: %v", event.Type, url, err)
+			}
+		}, hook.Events...)
+	}
+	return nil
+}
+
+// closeWebhooks flushes and stops the webhooks.
+func (c *Client) closeWebhooks() {
+	if len(c.webhooks) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookCloseTimeout)
+	defer cancel()
+	for _, sender := range c.webhooks {
+		if err := sender.Close(ctx); err != nil {
+			log.Printf("Pending webhook deliveries dropped: %v", err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// resolveExpiresAt computes the expiration time for a new memory added at now
+// from Add options.
+//
+// Returns nil if the memory should never expire.
+func resolveExpiresAt(opts *AddOptions, now time.Time) *time.Time {
+	if !opts.ExpiresAt.IsZero() {
+		expiresAt := opts.ExpiresAt
+		return &expiresAt
+	}
+	if opts.TTL > 0 {
+		expiresAt := now.Add(opts.TTL)
+		return &expiresAt
+	}
+	return nil
+}
+
+// PurgeExpired permanently deletes all memories whose expiration time has passed.
+//
+// Expired memories are already hidden from reads; purging reclaims their storage
+// and guarantees time-limited data is physically removed. Expiration is
+// judged by Config.Clock, like the TTL of new memories and the reads.
+//
+// Returns the number of deleted memories.
+//
+// Example:
+//
+//	deleted, err := client.PurgeExpired(ctx)
+func (c *Client) PurgeExpired(ctx context.Context) (int64, error) {
+	ctx, err := c.begin(ctx, "PurgeExpired")
+	if err != nil {
+		return 0, err
+	}
+	defer c.end()
+
+	events := c.recordEvents(ctx, "PurgeExpired")
+	defer events.publish()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	deleted, err := c.storage.PurgeExpired(ctx, c.now())
+	if err != nil {
+		return 0, NewMemoryError("PurgeExpired", err)
+	}
+	if deleted > 0 {
+		events.add(&Event{Type: EventForgotten, Count: deleted})
+	}
+
+	return deleted, nil
+}
+
+// StartExpirationPurge runs PurgeExpired every interval in a background goroutine
+// until ctx is cancelled or the client is shut down.
+//
+// Purge errors are logged and do not stop the routine.
+//
+// Example:
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	client.StartExpirationPurge(ctx, time.Hour)
+func (c *Client) StartExpirationPurge(ctx context.Context, interval time.Duration) {
+	stopped := c.stopped()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-stopped:
+				return
+			case <-ticker.C:
+				if _, err := c.PurgeExpired(ctx); err != nil && ctx.Err() == nil {
+					log.Printf("Failed to purge expired memories: %v", err)
+				}
+			}
+		}
+	}()
+}
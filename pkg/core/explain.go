@@ -0,0 +1,120 @@
+package core
+
+import (
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// SearchExplanation explains why a memory was retrieved by a search run with
+// WithExplain, in Memory.Explanation.
+type SearchExplanation struct {
+	// Query is the query that was searched.
+	Query string `json:"query"`
+
+	// OriginalQuery is the query before it was rewritten from the user's
+	// profile (set by usermemory.Client.Search when the query was
+	// rewritten).
+	OriginalQuery string `json:"original_query,omitempty"`
+
+	// HypotheticalDocument is the text generated by HyDE retrieval and
+	// searched instead of or with the query (see RetrievalModeHyDE).
+	HypotheticalDocument string `json:"hypothetical_document,omitempty"`
+
+	// TranslatedQueries are the translations of the query searched with it
+	// (see LanguageConfig.TranslateQueries), by language.
+	TranslatedQueries map[string]string `json:"translated_queries,omitempty"`
+
+	// Similarity is the similarity score of the memory returned by the
+	// store, the best one of the searched queries.
+	Similarity float64 `json:"similarity"`
+
+	// Score is the score the results were ranked by (Memory.Score).
+	Score float64 `json:"score"`
+
+	// ScoreComponents is the breakdown of Score by intelligent ranking (nil
+	// if intelligent memory is disabled, where Score is Similarity).
+	ScoreComponents *ScoreComponents `json:"score_components,omitempty"`
+
+	// Weights are the weights of the component scores in Score, divided by
+	// their sum, with IntelligenceConfig.Ranking (nil without: Score is
+	// Relevance multiplied by Retention).
+	Weights *RankingConfig `json:"weights,omitempty"`
+
+	// RetentionStrength is the retention strength of the memory, which
+	// scales ScoreComponents.Retention.
+	RetentionStrength float64 `json:"retention_strength"`
+
+	// MatchedFilters are the filters of the search with the values of the
+	// memory that matched them: "user_id", or "team_id" for a team memory,
+	// "agent_id", "tags", "created_at", "updated_at", "min_score", and the
+	// metadata filters of WithFilters and WithLanguage as "metadata.<key>".
+	MatchedFilters map[string]interface{} `json:"matched_filters,omitempty"`
+}
+
+// explain sets the explanation of memories, the results of a search for
+// query with searchOpts. diag holds the rewrites of the query.
+func (c *Client) explain(memories []*Memory, query string, searchOpts *SearchOptions, diag *SearchDiagnostics) {
+	var weights *RankingConfig
+	if c.config.Intelligence != nil && c.config.Intelligence.Ranking != nil {
+		ranking := toIntelligenceRanking(c.config.Intelligence.Ranking)
+		weights = &RankingConfig{
+			SimilarityWeight:     ranking.SimilarityWeight,
+			RecencyWeight:        ranking.RecencyWeight,
+			ImportanceWeight:     ranking.ImportanceWeight,
+			RetentionWeight:      ranking.RetentionWeight,
+			RecencyHalfLifeHours: ranking.RecencyHalfLife.Hours(),
+		}
+	}
+
+	for _, memory := range memories {
+		explanation := &SearchExplanation{
+			Query:                query,
+			HypotheticalDocument: diag.HypotheticalDocument,
+			TranslatedQueries:    diag.TranslatedQueries,
+			Similarity:           memory.Score,
+			Score:                memory.Score,
+			RetentionStrength:    memory.RetentionStrength,
+			MatchedFilters:       matchedFilters(memory, searchOpts),
+		}
+		if memory.ScoreComponents != nil {
+			explanation.Similarity = memory.ScoreComponents.Similarity
+			explanation.ScoreComponents = memory.ScoreComponents
+			explanation.Weights = weights
+		}
+		memory.Explanation = explanation
+	}
+}
+
+// matchedFilters returns the filters of searchOpts with the values of
+// memory that matched them (nil if the search had no filters).
+func matchedFilters(memory *Memory, searchOpts *SearchOptions) map[string]interface{} {
+	matched := make(map[string]interface{})
+	if searchOpts.UserID != "" {
+		if teamID := metadataString(memory.Metadata, storage.TeamIDKey); memory.UserID != searchOpts.UserID && teamID != "" {
+			matched["team_id"] = teamID
+		} else {
+			matched["user_id"] = memory.UserID
+		}
+	}
+	if searchOpts.AgentID != "" {
+		matched["agent_id"] = memory.AgentID
+	}
+	if len(searchOpts.Tags) > 0 {
+		matched["tags"] = searchOpts.Tags
+	}
+	if !searchOpts.CreatedAfter.IsZero() || !searchOpts.CreatedBefore.IsZero() {
+		matched["created_at"] = memory.CreatedAt
+	}
+	if !searchOpts.UpdatedAfter.IsZero() || !searchOpts.UpdatedBefore.IsZero() {
+		matched["updated_at"] = memory.UpdatedAt
+	}
+	if searchOpts.MinScore > 0 {
+		matched["min_score"] = searchOpts.MinScore
+	}
+	for key := range searchOpts.Filters {
+		matched["metadata."+key] = memory.Metadata[key]
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+	return matched
+}
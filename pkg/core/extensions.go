@@ -0,0 +1,53 @@
+package core
+
+import (
+	"github.com/oceanbase/powermem-go/pkg/embedder"
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+	"github.com/oceanbase/powermem-go/pkg/llm"
+)
+
+// Extensions exposes the components of a client to applications building
+// on them, for instance to run a custom consolidation with the configured
+// LLM, instead of reconstructing them from a duplicate configuration.
+//
+// The components are shared with the client and must not be closed or
+// reconfigured. Managers are nil when intelligence is disabled.
+type Extensions struct {
+	// LLM is the LLM provider, including its fallbacks and routing.
+	LLM llm.Provider
+
+	// Embedder is the embedding provider.
+	Embedder embedder.Provider
+
+	// DedupManager detects and merges duplicate memories.
+	DedupManager *intelligence.DedupManager
+
+	// EbbinghausManager computes memory retention.
+	EbbinghausManager *intelligence.EbbinghausManager
+
+	// IntelligentManager runs the intelligent add pipeline.
+	IntelligentManager *intelligence.IntelligentMemoryManager
+}
+
+// Extensions returns the current components of the client.
+//
+// Reload replaces the LLM provider and the managers, so the returned
+// components are a snapshot: call Extensions again after a reload to use
+// the new ones.
+//
+// Example:
+//
+//	ext := client.Extensions()
+//	summary, err := ext.LLM.Generate(ctx, "Summarize: "+memory.Content)
+func (c *Client) Extensions() *Extensions {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return &Extensions{
+		LLM:                c.llm,
+		Embedder:           c.embedder,
+		DedupManager:       c.dedupManager,
+		EbbinghausManager:  c.ebbinghausManager,
+		IntelligentManager: c.intelligentManager,
+	}
+}
@@ -0,0 +1,256 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// FeedbackType is the kind of feedback given on a memory.
+type FeedbackType string
+
+const (
+	// FeedbackPositive reinforces a memory that was useful.
+	FeedbackPositive FeedbackType = "positive"
+
+	// FeedbackNegative weakens a memory that was not useful.
+	FeedbackNegative FeedbackType = "negative"
+
+	// FeedbackIncorrect flags a memory that is wrong.
+	FeedbackIncorrect FeedbackType = "incorrect"
+)
+
+// Metadata keys set by Feedback.
+const (
+	// flaggedKey holds the reason a memory is flagged for review ("incorrect").
+	flaggedKey = "flagged"
+
+	// flagNoteKey holds the note of the feedback that flagged a memory.
+	flagNoteKey = "flag_note"
+
+	// feedbackKey holds the feedback counts and the last feedback of a memory.
+	feedbackKey = "feedback"
+)
+
+// defaultReinforcementFactor is the reinforcement factor of Feedback when
+// intelligent memory is not enabled.
+const defaultReinforcementFactor = 0.3
+
+// FeedbackResult is the result of Feedback.
+type FeedbackResult struct {
+	// Memory is the memory after the feedback (the deleted memory if Forgotten).
+	Memory *Memory `json:"memory"`
+
+	// Flagged is set when the memory was flagged as incorrect. Flagged
+	// memories are left out of searches unless WithIncludeFlagged is given.
+	Flagged bool `json:"flagged"`
+
+	// Rewritten is set when the LLM corrected the memory.
+	Rewritten bool `json:"rewritten"`
+
+	// PreviousContent is the content before the rewrite (empty if not Rewritten).
+	PreviousContent string `json:"previous_content,omitempty"`
+
+	// Forgotten is set when the LLM found nothing to keep and the memory was deleted.
+	Forgotten bool `json:"forgotten"`
+}
+
+// Feedback records feedback on a memory.
+//
+// FeedbackPositive reinforces the memory's retention strength, as an access
+// does, and clears a previous incorrect flag. FeedbackNegative weakens it, so
+// the memory ranks lower and is forgotten sooner. FeedbackIncorrect weakens
+// it and flags it for review: the metadata gets "flagged": "incorrect" and
+// "flag_note": note, and searches leave it out unless WithIncludeFlagged is
+// given. Flagged memories can be listed with GetAll and the filter
+// {"flagged": "incorrect"}.
+//
+// With WithFeedbackRewrite, FeedbackIncorrect instead asks the LLM to correct
+// the memory according to note: the content is replaced and re-embedded, or
+// the memory is deleted if the note does not say what is right.
+//
+// Every feedback is counted in the "feedback" metadata of the memory.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - id: Memory ID
+//   - feedback: FeedbackPositive, FeedbackNegative or FeedbackIncorrect
+//   - note: What the user said (optional)
+//   - opts: Optional Feedback options (UserID, AgentID, Rewrite)
+//
+// Returns ErrInvalidInput for an unknown feedback type, and ErrInvalidConfig
+// for a rewrite without an LLM provider.
+//
+// Example:
+//
+//	// The user says a memory is wrong
+//	result, err := client.Feedback(ctx, memoryID, core.FeedbackIncorrect,
+//	    "I moved to Lisbon in May", core.WithUserIDForFeedback("user_001"))
+func (c *Client) Feedback(ctx context.Context, id int64, feedback FeedbackType, note string, opts ...FeedbackOption) (*FeedbackResult, error) {
+	ctx, err := c.begin(ctx, "Feedback")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	events := c.recordEvents(ctx, "Feedback")
+	defer events.publish()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	feedbackOpts := applyFeedbackOptions(opts)
+
+	switch feedback {
+	case FeedbackPositive, FeedbackNegative, FeedbackIncorrect:
+	default:
+		return nil, NewMemoryError("Feedback", fmt.Errorf("%w: unknown feedback type %q", ErrInvalidInput, feedback))
+	}
+	rewrite := feedback == FeedbackIncorrect && feedbackOpts.Rewrite
+	if rewrite && c.llm == nil {
+		return nil, NewMemoryError("Feedback", fmt.Errorf("%w: rewriting memories requires an LLM provider", ErrInvalidConfig))
+	}
+
+	existing, err := c.storage.Get(ctx, id, &storage.GetOptions{
+		UserID:  feedbackOpts.UserID,
+		AgentID: feedbackOpts.AgentID,
+		Now:     c.now(),
+	})
+	if err != nil {
+		return nil, NewMemoryError("Feedback", err)
+	}
+
+	now := c.now()
+	metadata := make(map[string]interface{}, len(existing.Metadata)+2)
+	for k, v := range existing.Metadata {
+		metadata[k] = v
+	}
+	metadata[feedbackKey] = feedbackMetadata(existing.Metadata[feedbackKey], feedback, note, now)
+
+	ebbinghaus := c.ebbinghausManager
+	if ebbinghaus == nil {
+		ebbinghaus = intelligence.NewEbbinghausManager(0, defaultReinforcementFactor).WithClock(c.config.Clock)
+	} else if c.intelligentManager != nil {
+		memoryType, _ := existing.Metadata[memoryTypeKey].(string)
+		ebbinghaus = c.intelligentManager.EbbinghausManagerFor(existing.AgentID, memoryType)
+	}
+
+	result := &FeedbackResult{}
+	content := existing.Content
+	embedding := existing.Embedding
+	var chunked *chunkedContent
+	var strength float64
+	var lastAccessedAt *time.Time
+
+	switch {
+	case feedback == FeedbackPositive:
+		strength = ebbinghaus.Reinforce(existing.RetentionStrength)
+		lastAccessedAt = &now
+		delete(metadata, flaggedKey)
+		delete(metadata, flagNoteKey)
+	case rewrite:
+		corrected, err := intelligence.NewMemoryCorrector(c.llm).Correct(ctx, existing.Content, note)
+		if err != nil {
+			return nil, NewMemoryError("Feedback", err)
+		}
+		if corrected == "" {
+			if err := c.storage.Delete(ctx, id, &storage.DeleteOptions{
+				UserID:  feedbackOpts.UserID,
+				AgentID: feedbackOpts.AgentID,
+			}); err != nil {
+				return nil, NewMemoryError("Feedback", err)
+			}
+			if err := c.deleteChunks(ctx, id, existing.UserID); err != nil {
+				return nil, NewMemoryError("Feedback", err)
+			}
+			result.Memory = fromStorageMemory(existing)
+			result.Forgotten = true
+			events.add(&Event{Type: EventDeleted, MemoryID: id, UserID: existing.UserID, AgentID: existing.AgentID, Memory: result.Memory})
+			return result, nil
+		}
+		if err := c.checkContentSize(corrected); err != nil {
+			return nil, NewMemoryError("Feedback", err)
+		}
+		if embedding, chunked, err = c.embedContent(ctx, c.embedderOf(c.routeOf(existing.Metadata)), corrected); err != nil {
+			return nil, NewMemoryError("Feedback", err)
+		}
+		content = corrected
+		strength = 1.0
+		delete(metadata, flaggedKey)
+		delete(metadata, flagNoteKey)
+		result.Rewritten = true
+		result.PreviousContent = existing.Content
+	default:
+		strength = ebbinghaus.Weaken(existing.RetentionStrength)
+		if feedback == FeedbackIncorrect {
+			metadata[flaggedKey] = string(FeedbackIncorrect)
+			metadata[flagNoteKey] = note
+			result.Flagged = true
+		}
+	}
+
+	memory, err := c.storage.Update(ctx, id, content, embedding, &storage.UpdateOptions{
+		UserID:            feedbackOpts.UserID,
+		AgentID:           feedbackOpts.AgentID,
+		Metadata:          metadata,
+		ExpectedVersion:   existing.Version,
+		RetentionStrength: &strength,
+		LastAccessedAt:    lastAccessedAt,
+		Now:               c.now(),
+	})
+	if err != nil {
+		return nil, NewMemoryError("Feedback", err)
+	}
+
+	if result.Rewritten {
+		// Replace the chunks of the previous content
+		if err := c.deleteChunks(ctx, id, memory.UserID); err != nil {
+			return nil, NewMemoryError("Feedback", err)
+		}
+		if chunked != nil {
+			if err := c.insertChunks(ctx, fromStorageMemory(memory), chunked); err != nil {
+				return nil, NewMemoryError("Feedback", err)
+			}
+		}
+	} else if err := c.updateChunkMetadata(ctx, memory); err != nil {
+		// Keep the metadata of the chunks in sync for filtered searches
+		return nil, NewMemoryError("Feedback", err)
+	}
+
+	result.Memory = fromStorageMemory(memory)
+	events.add(&Event{Type: EventUpdated, Memory: result.Memory, Diff: newEventDiff(fromStorageMemory(existing), result.Memory)})
+
+	return result, nil
+}
+
+// feedbackMetadata returns the "feedback" metadata of a memory after
+// feedback: the count of each feedback type and the last feedback.
+func feedbackMetadata(previous interface{}, feedback FeedbackType, note string, at time.Time) map[string]interface{} {
+	value := make(map[string]interface{}, 6)
+	if previous, ok := previous.(map[string]interface{}); ok {
+		for _, t := range []FeedbackType{FeedbackPositive, FeedbackNegative, FeedbackIncorrect} {
+			if count, ok := previous[string(t)].(float64); ok {
+				value[string(t)] = count
+			} else if count, ok := previous[string(t)].(int); ok {
+				value[string(t)] = float64(count)
+			}
+		}
+	}
+	count, _ := value[string(feedback)].(float64)
+	value[string(feedback)] = count + 1
+	value["last"] = string(feedback)
+	value["last_at"] = at.UTC().Format(time.RFC3339)
+	if note != "" {
+		value["last_note"] = note
+	}
+	return value
+}
+
+// isFlagged reports whether a memory is flagged for review by Feedback.
+func isFlagged(memory *storage.Memory) bool {
+	flag, _ := memory.Metadata[flaggedKey].(string)
+	return flag != ""
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// MemoryField is a group of Memory fields that reads can leave out, see
+// WithFields. ID, UserID, AgentID, UID, Version, ParentID, Hash, Score and
+// RetentionStrength are always returned.
+type MemoryField string
+
+const (
+	// MemoryFieldContent selects Memory.Content.
+	MemoryFieldContent MemoryField = "content"
+
+	// MemoryFieldEmbedding selects Memory.Embedding. Search results only
+	// carry embeddings with WithIncludeEmbeddings(true).
+	MemoryFieldEmbedding MemoryField = "embedding"
+
+	// MemoryFieldMetadata selects Memory.Metadata and the fields read from
+	// it: RunID, Scope, MemoryType, Language, Sources, Entities and
+	// SharedFrom.
+	MemoryFieldMetadata MemoryField = "metadata"
+
+	// MemoryFieldTimestamps selects CreatedAt, UpdatedAt, LastAccessedAt and
+	// ExpiresAt.
+	MemoryFieldTimestamps MemoryField = "timestamps"
+
+	// MemoryFieldTags selects Memory.Tags.
+	MemoryFieldTags MemoryField = "tags"
+)
+
+// toStorageFields converts fields to the fields of the storage layer.
+func toStorageFields(fields []MemoryField) []storage.Field {
+	if len(fields) == 0 {
+		return nil
+	}
+	converted := make([]storage.Field, len(fields))
+	for i, field := range fields {
+		converted[i] = storage.Field(field)
+	}
+	return converted
+}
+
+// searchFields returns the storage fields read by a search with fields:
+// besides fields, searches need the content and metadata of the results
+// to screen them, and intelligent ranking and the staleness policy need
+// their timestamps if timestamps is set. The results are trimmed back to
+// fields by projectMemory.
+func searchFields(fields []MemoryField, timestamps bool) []storage.Field {
+	if len(fields) == 0 {
+		return nil
+	}
+	needed := append(toStorageFields(fields), storage.FieldContent, storage.FieldMetadata)
+	if timestamps {
+		needed = append(needed, storage.FieldTimestamps)
+	}
+	return needed
+}
+
+// projectMemory clears the fields of memory not selected by fields. It does
+// nothing if fields is empty.
+func projectMemory(memory *Memory, fields []MemoryField) {
+	if len(fields) == 0 {
+		return
+	}
+	selected := storage.SelectedFields(toStorageFields(fields), false)
+	if !selected[storage.FieldContent] {
+		memory.Content = ""
+	}
+	if !selected[storage.FieldEmbedding] {
+		memory.Embedding = nil
+		memory.SparseEmbedding = nil
+	}
+	if !selected[storage.FieldMetadata] {
+		memory.Metadata = nil
+		memory.RunID = ""
+		memory.Scope = ""
+		memory.MemoryType = ""
+		memory.Language = ""
+		memory.Sources = nil
+		memory.Entities = nil
+		memory.SharedFrom = nil
+	}
+	if !selected[storage.FieldTimestamps] {
+		memory.CreatedAt = time.Time{}
+		memory.UpdatedAt = time.Time{}
+		memory.LastAccessedAt = nil
+		memory.ExpiresAt = nil
+	}
+	if !selected[storage.FieldTags] {
+		memory.Tags = nil
+	}
+}
@@ -0,0 +1,277 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// defaultForgetLimit is the default number of candidate memories reviewed
+// by Forget.
+const defaultForgetLimit = 20
+
+// ForgetResult is the result of Forget.
+type ForgetResult struct {
+	// UserID is the user whose memories are forgotten.
+	UserID string `json:"user_id"`
+
+	// Instruction is what the user asked to forget.
+	Instruction string `json:"instruction"`
+
+	// Reviewed is the number of candidate memories given to the LLM (0 when
+	// confirmed actions are performed).
+	Reviewed int `json:"reviewed"`
+
+	// Actions are the planned actions when DryRun is true, or the performed
+	// ones.
+	Actions []ForgetActionResult `json:"actions"`
+
+	// DryRun indicates that the actions were planned but not performed,
+	// because none were confirmed with WithConfirmedForgetActions.
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// Conflicts are the confirmed actions that were not performed because
+	// the memory was changed or deleted since it was planned.
+	Conflicts []ForgetActionResult `json:"conflicts,omitempty"`
+}
+
+// ForgetActionResult is an action of Forget on a memory.
+type ForgetActionResult struct {
+	// Event is the operation: DELETE or UPDATE.
+	Event string `json:"event"`
+
+	// ID is the memory.
+	ID int64 `json:"id"`
+
+	// Version is the version of the memory when the action was planned.
+	Version int64 `json:"version"`
+
+	// Memory is the content of the memory when the action was planned.
+	Memory string `json:"memory"`
+
+	// NewMemory is the content of the memory after an UPDATE.
+	NewMemory string `json:"new_memory,omitempty"`
+
+	// Reason is the explanation of the LLM.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Forget honors a user's request to forget something, given in natural
+// language ("forget that I live in Berlin"): the LLM reviews the user's
+// memories most similar to the instruction and decides which to delete and
+// which to rewrite without the forgotten part.
+//
+// Forgetting is a two-step operation, like DeleteWhere. Without
+// WithConfirmedForgetActions, Forget is a dry run returning the planned
+// actions, to be shown to the user. Passing the actions the user approved
+// with WithConfirmedForgetActions then performs them as planned, without
+// asking the LLM again. An action on a memory changed since it was planned
+// is not performed and is reported in Conflicts.
+//
+// Only memories owned by the user are reviewed, including memories flagged
+// as incorrect; team memories and working memory items are left out.
+// Deletions and updates publish memory.forgotten events holding the memory
+// ID, owner and new version but not the forgotten content.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userID: User who asked to forget (required)
+//   - instruction: What the user asked to forget (required)
+//   - opts: Optional parameters (Limit, confirmed actions)
+//
+// Returns the result, or ErrInvalidInput without a user ID or
+// instruction, and ErrInvalidConfig without an LLM provider for a dry run.
+// If an action fails, the actions performed so far are returned with the
+// error.
+//
+// Example:
+//
+//	plan, err := client.Forget(ctx, "user_001", "Forget where I live")
+//	if err != nil {
+//	    return err
+//	}
+//	if userApproves(plan.Actions) {
+//	    result, err := client.Forget(ctx, "user_001", "Forget where I live",
+//	        core.WithConfirmedForgetActions(plan.Actions...))
+//	}
+func (c *Client) Forget(ctx context.Context, userID, instruction string, opts ...ForgetOption) (*ForgetResult, error) {
+	if userID == "" {
+		return nil, NewMemoryError("Forget", fmt.Errorf("%w: user ID is required", ErrInvalidInput))
+	}
+	if instruction == "" {
+		return nil, NewMemoryError("Forget", fmt.Errorf("%w: instruction is required", ErrInvalidInput))
+	}
+
+	ctx, err := c.begin(ctx, "Forget")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	forgetOpts := applyForgetOptions(opts)
+	if forgetOpts.ConfirmedActions == nil {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+
+		result, err := c.planForget(ctx, userID, instruction, forgetOpts.Limit)
+		if err != nil {
+			return nil, NewMemoryError("Forget", err)
+		}
+		return result, nil
+	}
+
+	events := c.recordEvents(ctx, "Forget")
+	defer events.publish()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := &ForgetResult{UserID: userID, Instruction: instruction, Actions: []ForgetActionResult{}}
+	for _, action := range forgetOpts.ConfirmedActions {
+		err := c.forgetMemory(ctx, userID, action, events)
+		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrVersionConflict) {
+			result.Conflicts = append(result.Conflicts, action)
+			continue
+		}
+		if err != nil {
+			return result, NewMemoryError("Forget", err)
+		}
+		result.Actions = append(result.Actions, action)
+	}
+	return result, nil
+}
+
+// planForget asks the LLM which of the memories of userID most similar to
+// instruction to delete or update.
+func (c *Client) planForget(ctx context.Context, userID, instruction string, limit int) (*ForgetResult, error) {
+	if c.llm == nil {
+		return nil, fmt.Errorf("%w: forgetting requires an LLM provider", ErrInvalidConfig)
+	}
+
+	memories, err := c.searchRouted(ctx, instruction, RetrievalModeVector, &storage.SearchOptions{
+		UserID: userID,
+		Limit:  limit,
+		Query:  instruction,
+		Now:    c.now(),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if memories, err = c.resolveChunks(ctx, memories, nil); err != nil {
+		return nil, err
+	}
+	memories = visible(memories, &SearchOptions{IncludeFlagged: true})
+
+	// Give the LLM temporary IDs, as IntelligentAdd does
+	existingMemories := make([]intelligence.ExistingMemory, 0, len(memories))
+	candidates := make(map[string]*storage.Memory, len(memories))
+	for _, memory := range memories {
+		if memory.UserID != userID {
+			continue
+		}
+		tempID := strconv.Itoa(len(existingMemories))
+		existingMemories = append(existingMemories, intelligence.ExistingMemory{ID: tempID, Text: memory.Content})
+		candidates[tempID] = memory
+	}
+
+	result := &ForgetResult{UserID: userID, Instruction: instruction, Reviewed: len(existingMemories), Actions: []ForgetActionResult{}, DryRun: true}
+	actions, err := intelligence.NewMemoryForgetter(c.llm).Locate(ctx, instruction, existingMemories)
+	if err != nil {
+		return nil, err
+	}
+	planned := make(map[int64]bool, len(actions))
+	for _, action := range actions {
+		memory, ok := candidates[action.ID]
+		if !ok {
+			log.Printf("Could not find real memory ID for forget ID: %s", action.ID)
+			continue
+		}
+		if planned[memory.ID] || (action.Event == intelligence.ForgetUpdate && action.Text == memory.Content) {
+			continue
+		}
+		planned[memory.ID] = true
+		result.Actions = append(result.Actions, ForgetActionResult{
+			Event:     action.Event,
+			ID:        memory.ID,
+			Version:   memory.Version,
+			Memory:    memory.Content,
+			NewMemory: action.Text,
+			Reason:    action.Reason,
+		})
+	}
+	return result, nil
+}
+
+// forgetMemory performs a confirmed action of Forget. It returns an error
+// wrapping storage.ErrNotFound or storage.ErrVersionConflict if the memory
+// is gone or was changed since the action was planned.
+func (c *Client) forgetMemory(ctx context.Context, userID string, action ForgetActionResult, events *eventRecorder) error {
+	existing, err := c.storage.Get(ctx, action.ID, &storage.GetOptions{UserID: userID, Now: c.now()})
+	if err != nil {
+		return err
+	}
+	if existing.Version != action.Version {
+		return fmt.Errorf("%w: memory %d changed since the action was planned", storage.ErrVersionConflict, action.ID)
+	}
+
+	switch action.Event {
+	case intelligence.ForgetDelete:
+		if err := c.storage.Delete(ctx, action.ID, &storage.DeleteOptions{UserID: userID}); err != nil {
+			return err
+		}
+		if err := c.deleteChunks(ctx, action.ID, userID); err != nil {
+			return err
+		}
+		events.add(forgottenEvent(existing, 0))
+	case intelligence.ForgetUpdate:
+		if err := c.checkContentSize(action.NewMemory); err != nil {
+			return err
+		}
+		embedding, chunked, err := c.embedContent(ctx, c.embedderOf(c.routeOf(existing.Metadata)), action.NewMemory)
+		if err != nil {
+			return err
+		}
+		// The provenance holds the previous contents, which are forgotten too
+		memory, err := c.storage.Update(ctx, action.ID, action.NewMemory, embedding, &storage.UpdateOptions{
+			UserID:          userID,
+			Metadata:        withoutProvenance(existing.Metadata),
+			ExpectedVersion: existing.Version,
+			Now:             c.now(),
+		})
+		if err != nil {
+			return err
+		}
+		if err := c.deleteChunks(ctx, action.ID, userID); err != nil {
+			return err
+		}
+		if chunked != nil {
+			if err := c.insertChunks(ctx, fromStorageMemory(memory), chunked); err != nil {
+				return err
+			}
+		}
+		events.add(forgottenEvent(memory, memory.Version))
+	default:
+		return fmt.Errorf("%w: unknown forget event %q", ErrInvalidInput, action.Event)
+	}
+	return nil
+}
+
+// forgottenEvent returns the memory.forgotten event of a memory deleted
+// (version 0) or rewritten by Forget. Unlike memory.deleted and
+// memory.updated events, it holds no content, since it is appended to the
+// change log and delivered to webhooks.
+func forgottenEvent(memory *storage.Memory, version int64) *Event {
+	return &Event{
+		Type:      EventForgotten,
+		MemoryID:  memory.ID,
+		MemoryUID: memory.UID,
+		UserID:    memory.UserID,
+		AgentID:   memory.AgentID,
+		Version:   version,
+	}
+}
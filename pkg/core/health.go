@@ -0,0 +1,180 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/llm"
+)
+
+// HealthComponent identifies a dependency checked by Health.
+type HealthComponent string
+
+const (
+	// HealthVectorStore is the vector store connection.
+	HealthVectorStore HealthComponent = "vector_store"
+
+	// HealthLLM is the LLM provider.
+	HealthLLM HealthComponent = "llm"
+
+	// HealthEmbedder is the embedding provider.
+	HealthEmbedder HealthComponent = "embedder"
+)
+
+// HealthStatus is the result of a health check.
+type HealthStatus string
+
+const (
+	// HealthStatusUp means the check succeeded.
+	HealthStatusUp HealthStatus = "up"
+
+	// HealthStatusDown means the check failed.
+	HealthStatusDown HealthStatus = "down"
+)
+
+// ComponentHealth is the health of a single dependency.
+type ComponentHealth struct {
+	// Status is HealthStatusUp if the dependency responded successfully.
+	Status HealthStatus `json:"status"`
+
+	// Provider is the configured provider name, such as "sqlite" or "openai".
+	Provider string `json:"provider"`
+
+	// Latency is how long the check took.
+	Latency time.Duration `json:"latency"`
+
+	// Error describes why the check failed (empty when Status is up).
+	Error string `json:"error,omitempty"`
+}
+
+// HealthReport is the result of Client.Health.
+type HealthReport struct {
+	// Status is HealthStatusUp if every checked component is up.
+	Status HealthStatus `json:"status"`
+
+	// Components contains the result of each check that was run.
+	Components map[HealthComponent]*ComponentHealth `json:"components"`
+
+	// CheckedAt is when the checks started.
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Healthy reports whether every checked component is up.
+func (r *HealthReport) Healthy() bool {
+	return r.Status == HealthStatusUp
+}
+
+// Health checks the vector store, LLM and embedder and reports their status.
+//
+// The checks run concurrently, each bounded by the health timeout (default
+// 5s, see WithHealthTimeout):
+//   - Vector store: pings the database connection
+//   - LLM: generates a single token
+//   - Embedder: embeds a short text, with the embedder of each EmbedderRoute too
+//
+// The LLM and embedder checks make real (minimal) provider requests. Use
+// WithHealthComponents to run only some of the checks, for example to keep
+// frequent readiness probes cheap.
+//
+// A failing component is reported in the returned HealthReport, not as an
+// error. Health returns an error only if the client is closed or shutting
+// down, in which case it should not receive traffic anyway.
+//
+// Example:
+//
+//	report, err := client.Health(ctx, core.WithHealthComponents(core.HealthVectorStore))
+//	if err != nil || !report.Healthy() {
+//	    w.WriteHeader(http.StatusServiceUnavailable)
+//	}
+//	_ = json.NewEncoder(w).Encode(report)
+func (c *Client) Health(ctx context.Context, opts ...HealthOption) (*HealthReport, error) {
+	ctx, err := c.begin(ctx, "Health")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	healthOpts := applyHealthOptions(opts)
+
+	// Reload may replace the config and LLM while the checks run
+	c.mu.RLock()
+	config, llmProvider := c.config, c.llm
+	c.mu.RUnlock()
+
+	checks := map[HealthComponent]struct {
+		provider string
+		check    func(ctx context.Context) error
+	}{
+		HealthVectorStore: {config.VectorStore.Provider, c.storage.Ping},
+		HealthLLM: {config.LLM.Provider, func(ctx context.Context) error {
+			_, err := llmProvider.Generate(ctx, "ping", llm.WithMaxTokens(1))
+			return err
+		}},
+		HealthEmbedder: {config.Embedder.Provider, func(ctx context.Context) error {
+			if _, err := c.embedder.Embed(ctx, "ping"); err != nil {
+				return err
+			}
+			for _, route := range c.embedderRoutes {
+				if _, err := route.provider.Embed(ctx, "ping"); err != nil {
+					return fmt.Errorf("embedder route %q: %w", route.name, err)
+				}
+			}
+			return nil
+		}},
+	}
+
+	for _, component := range healthOpts.Components {
+		if _, ok := checks[component]; !ok {
+			return nil, NewMemoryError("Health", ErrInvalidInput)
+		}
+	}
+
+	report := &HealthReport{
+		Status:     HealthStatusUp,
+		Components: make(map[HealthComponent]*ComponentHealth, len(healthOpts.Components)),
+		CheckedAt:  time.Now(),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	started := make(map[HealthComponent]bool, len(healthOpts.Components))
+	for _, component := range healthOpts.Components {
+		if started[component] {
+			continue
+		}
+		started[component] = true
+
+		check := checks[component]
+		wg.Add(1)
+		go func(component HealthComponent, provider string, check func(ctx context.Context) error) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, healthOpts.Timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check(checkCtx)
+			health := &ComponentHealth{
+				Status:   HealthStatusUp,
+				Provider: provider,
+				Latency:  time.Since(start),
+			}
+			if err != nil {
+				health.Status = HealthStatusDown
+				health.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[component] = health
+			if err != nil {
+				report.Status = HealthStatusDown
+			}
+		}(component, check.provider, check.check)
+	}
+	wg.Wait()
+
+	return report, nil
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/llm"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// RetrievalMode selects how Search turns a query into a query embedding,
+// and whether it also matches the query text.
+type RetrievalMode string
+
+const (
+	// RetrievalModeVector embeds the raw query (default).
+	RetrievalModeVector RetrievalMode = "vector"
+
+	// RetrievalModeHyDE asks the LLM for a hypothetical memory answering the
+	// query and searches with the embedding of that document (HyDE).
+	RetrievalModeHyDE RetrievalMode = "hyde"
+
+	// RetrievalModeHyDEFusion runs both the HyDE and the raw-query searches and
+	// fuses the results, keeping the best score of each memory.
+	RetrievalModeHyDEFusion RetrievalMode = "hyde_fusion"
+
+	// RetrievalModeHybrid embeds the raw query and also matches its text
+	// against the full-text index of the store, ranking results by both
+	// vector similarity and text relevance. Stores without full-text search
+	// (SQLite, PostgreSQL) run a vector search.
+	RetrievalModeHybrid RetrievalMode = "hybrid"
+)
+
+// hydePrompt asks the LLM for a hypothetical memory answering the query.
+const hydePrompt = `Write a short statement, as it could be stored in a personal memory, that answers the question below.
+Invent plausible details if needed. Return only the statement, without any explanation.
+
+Question: %s`
+
+// searchStorage runs the storage search for query according to mode, with
+// the query embeddings of the embedder of route.
+//
+// The HyDE modes fall back to the raw query if no LLM is configured or the
+// hypothetical document cannot be generated. If diag is not nil, stage
+// timings are accumulated into it and the store counts of the first search
+// are recorded.
+func (c *Client) searchStorage(ctx context.Context, route *embedderRoute, query string, mode RetrievalMode, storageOpts *storage.SearchOptions, diag *SearchDiagnostics) ([]*storage.Memory, error) {
+	var document string
+	if mode == RetrievalModeHyDE || mode == RetrievalModeHyDEFusion {
+		start := time.Now()
+		document = c.hypotheticalDocument(ctx, query)
+		if diag != nil {
+			diag.HypotheticalDocument = document
+			diag.LLMTime += time.Since(start)
+		}
+	}
+	if document == "" {
+		if mode == RetrievalModeHybrid {
+			opts := *storageOpts
+			opts.Hybrid = true
+			storageOpts = &opts
+		}
+		return c.embedAndSearch(ctx, route, query, storageOpts, diag)
+	}
+
+	hydeResults, err := c.embedAndSearch(ctx, route, document, storageOpts, diag)
+	if err != nil {
+		return nil, err
+	}
+	if mode == RetrievalModeHyDE {
+		return hydeResults, nil
+	}
+
+	rawResults, err := c.embedAndSearch(ctx, route, query, storageOpts, diag)
+	if err != nil {
+		return nil, err
+	}
+	return fuseSearchResults(storageOpts.Limit, hydeResults, rawResults), nil
+}
+
+// embedAndSearch embeds text with the embedder of route and runs a vector
+// search with it.
+func (c *Client) embedAndSearch(ctx context.Context, route *embedderRoute, text string, storageOpts *storage.SearchOptions, diag *SearchDiagnostics) ([]*storage.Memory, error) {
+	start := time.Now()
+	embedding, cached, err := c.embedQuery(ctx, route, text)
+	if err != nil {
+		return nil, err
+	}
+	if diag == nil {
+		return c.storage.Search(ctx, embedding, storageOpts)
+	}
+	diag.EmbeddingTime += time.Since(start)
+	if cached {
+		diag.CachedEmbeddings++
+	}
+
+	// Only the first search of a call reports store counts
+	opts := *storageOpts
+	var stats storage.SearchStats
+	if diag.storageSearches == 0 && !diag.withoutStats {
+		opts.Stats = &stats
+	}
+	start = time.Now()
+	memories, err := c.storage.Search(ctx, embedding, &opts)
+	if err != nil {
+		return nil, err
+	}
+	diag.StorageTime += time.Since(start)
+	if diag.storageSearches == 0 {
+		diag.TotalMemories = stats.TotalMemories
+		diag.Candidates = stats.Candidates
+		diag.AboveThreshold = stats.AboveThreshold
+	}
+	diag.storageSearches++
+	return memories, nil
+}
+
+// hypotheticalDocument generates a hypothetical memory answering query.
+//
+// Returns an empty string if no LLM is configured or generation fails.
+func (c *Client) hypotheticalDocument(ctx context.Context, query string) string {
+	if c.llm == nil {
+		return ""
+	}
+	response, err := c.llm.GenerateWithMessages(ctx, []llm.Message{
+		{Role: "user", Content: fmt.Sprintf(hydePrompt, query)},
+	})
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(response)
+}
+
+// fuseSearchResults merges result lists by memory ID, keeping the highest
+// score of each memory, and returns at most limit results by descending score.
+func fuseSearchResults(limit int, lists ...[]*storage.Memory) []*storage.Memory {
+	byID := make(map[int64]*storage.Memory)
+	var fused []*storage.Memory
+	for _, list := range lists {
+		for _, memory := range list {
+			existing, ok := byID[memory.ID]
+			if !ok {
+				byID[memory.ID] = memory
+				fused = append(fused, memory)
+				continue
+			}
+			if memory.Score > existing.Score {
+				existing.Score = memory.Score
+			}
+		}
+	}
+
+	sort.SliceStable(fused, func(i, j int) bool { return fused[i].Score > fused[j].Score })
+	if limit > 0 && len(fused) > limit {
+		fused = fused[:limit]
+	}
+	return fused
+}
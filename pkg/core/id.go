@@ -0,0 +1,238 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// IDType selects which IDs are assigned to new memories.
+type IDType string
+
+const (
+	// IDTypeSnowflake assigns int64 snowflake IDs only (default).
+	IDTypeSnowflake IDType = "snowflake"
+
+	// IDTypeUUID additionally assigns a UUIDv7 string ID (Memory.UID) to every
+	// new memory. Memories can then be addressed by UID with GetByUID,
+	// UpdateByUID and DeleteByUID, so applications keyed by UUID need no
+	// mapping table. The snowflake ID is still assigned and remains valid.
+	IDTypeUUID IDType = "uuid"
+)
+
+// defaultIDConflictRetries is the default of Config.IDConflictRetries.
+const defaultIDConflictRetries = 3
+
+const (
+	// defaultNodeID is the default of Config.NodeID.
+	defaultNodeID = 1
+
+	// maxNodeID is the largest snowflake node.
+	maxNodeID = 1023
+)
+
+// nodeID returns the snowflake node of cfg.
+func nodeID(cfg *Config) int64 {
+	if cfg.NodeID == 0 {
+		return defaultNodeID
+	}
+	return cfg.NodeID
+}
+
+// insertNew inserts a new memory, generating a new ID for it whenever the
+// store already holds one with its ID, up to Config.IDConflictRetries times.
+// It fails with ErrDuplicateUID if the store holds one with its UID. The
+// fields the store derives from the memory, such as Hash, are set first, and
+// its timestamps default to the time of Config.Clock.
+func (c *Client) insertNew(ctx context.Context, memory *Memory) error {
+	setDerivedFields(memory)
+	if memory.CreatedAt.IsZero() {
+		memory.CreatedAt = c.now()
+	}
+	if memory.UpdatedAt.IsZero() {
+		memory.UpdatedAt = memory.CreatedAt
+	}
+	retries := c.config.IDConflictRetries
+	if retries == 0 {
+		retries = defaultIDConflictRetries
+	}
+	for attempt := 0; ; attempt++ {
+		err := c.storage.Insert(ctx, toStorageMemory(memory))
+		if err == nil || !errors.Is(err, storage.ErrDuplicateID) || attempt >= retries {
+			return err
+		}
+		memory.ID = c.snowflakeNode.Generate().Int64()
+	}
+}
+
+// newUID returns the string ID for a new memory, or an empty string if the
+// client is not configured for string IDs.
+func (c *Client) newUID() (string, error) {
+	if c.config.IDType != IDTypeUUID {
+		return "", nil
+	}
+	return newUUIDv7(c.now())
+}
+
+// checkNewUID returns uid, a string ID set with WithUID, in its canonical
+// lowercase form. It fails with ErrInvalidInput if uid is not a UUID. A UID
+// already taken by another memory is rejected by the unique index of the
+// store, so that concurrent adds cannot both take it: insertNew then fails
+// with ErrDuplicateUID.
+func checkNewUID(uid string) (string, error) {
+	canonical, ok := canonicalUUID(uid)
+	if !ok {
+		return "", fmt.Errorf("%w: UID %q is not a UUID", ErrInvalidInput, uid)
+	}
+	return canonical, nil
+}
+
+// canonicalUUID returns s, a UUID in the 8-4-4-4-12 hex form, in lowercase.
+// It reports false if s is not a UUID.
+func canonicalUUID(s string) (string, bool) {
+	if len(s) != 36 {
+		return "", false
+	}
+	for i := 0; i < len(s); i++ {
+		switch {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if s[i] != '-' {
+				return "", false
+			}
+		case !isHexDigit(s[i]):
+			return "", false
+		}
+	}
+	return strings.ToLower(s), true
+}
+
+// isHexDigit reports whether b is a hexadecimal digit.
+func isHexDigit(b byte) bool {
+	return '0' <= b && b <= '9' || 'a' <= b && b <= 'f' || 'A' <= b && b <= 'F'
+}
+
+// uuidClock keeps UUIDv7 values generated by this process strictly increasing.
+var uuidClock struct {
+	mu       sync.Mutex
+	lastMs   int64
+	sequence uint16
+}
+
+// newUUIDv7 generates a time-ordered UUID (RFC 9562, version 7) with the
+// timestamp of now.
+//
+// UUIDs generated within the same millisecond are ordered by a 12-bit
+// counter in the rand_a field.
+func newUUIDv7(now time.Time) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[8:]); err != nil {
+		return "", fmt.Errorf("generate uuid: %w", err)
+	}
+
+	uuidClock.mu.Lock()
+	ms := now.UnixMilli()
+	if ms > uuidClock.lastMs {
+		uuidClock.lastMs = ms
+		uuidClock.sequence = 0
+	} else {
+		uuidClock.sequence++
+		if uuidClock.sequence > 0x0fff {
+			// Counter exhausted: borrow the next millisecond
+			uuidClock.lastMs++
+			uuidClock.sequence = 0
+		}
+	}
+	ms, sequence := uuidClock.lastMs, uuidClock.sequence
+	uuidClock.mu.Unlock()
+
+	// 48-bit big-endian Unix timestamp in milliseconds
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(ms))
+	copy(b[:6], ts[2:])
+	binary.BigEndian.PutUint16(b[6:8], sequence)
+
+	b[6] = (b[6] & 0x0f) | 0x70 // version 7
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+	return string(s[:]), nil
+}
+
+// GetByUID retrieves a memory by its string ID with optional access control.
+//
+// String IDs are assigned when Config.IDType is IDTypeUUID.
+//
+// Example:
+//
+//	memory, err := client.GetByUID(ctx, "01890a5d-ac96-774b-bcce-b302099a8057",
+//	    core.WithUserIDForGet("user_001"))
+func (c *Client) GetByUID(ctx context.Context, uid string, opts ...GetOption) (*Memory, error) {
+	ctx, err := c.begin(ctx, "GetByUID")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	getOpts := applyGetOptions(opts)
+
+	memory, err := c.storage.GetByUID(ctx, uid, &storage.GetOptions{
+		UserID:  getOpts.UserID,
+		AgentID: getOpts.AgentID,
+		Now:     c.now(),
+	})
+	if err != nil {
+		return nil, NewMemoryError("GetByUID", err)
+	}
+
+	return fromStorageMemory(memory), nil
+}
+
+// UpdateByUID updates the memory with the given string ID.
+//
+// It behaves like Update, including access control with
+// WithUserIDForUpdate and WithAgentIDForUpdate.
+func (c *Client) UpdateByUID(ctx context.Context, uid string, content string, opts ...UpdateOption) (*Memory, error) {
+	return c.update(ctx, "UpdateByUID", 0, uid, content, applyUpdateOptions(opts))
+}
+
+// DeleteByUID deletes the memory with the given string ID.
+//
+// It behaves like Delete, including access control with
+// WithUserIDForDelete and WithAgentIDForDelete.
+func (c *Client) DeleteByUID(ctx context.Context, uid string, opts ...DeleteOption) error {
+	return c.delete(ctx, "DeleteByUID", 0, uid, applyDeleteOptions(opts))
+}
+
+// resolveUID returns the int64 ID of the memory with the given string ID.
+// It must be called with the client locked.
+func (c *Client) resolveUID(ctx context.Context, uid, userID, agentID string) (int64, error) {
+	memory, err := c.storage.GetByUID(ctx, uid, &storage.GetOptions{
+		UserID:  userID,
+		AgentID: agentID,
+		Now:     c.now(),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return memory.ID, nil
+}
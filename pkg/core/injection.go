@@ -0,0 +1,173 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// InjectionAction is what the injection guard does with a search result
+// whose content looks like a prompt injection.
+type InjectionAction string
+
+const (
+	// InjectionActionTag returns the memory unchanged, with the suspicious
+	// passages in Metadata[InjectionMetadataKey].
+	InjectionActionTag InjectionAction = "tag"
+
+	// InjectionActionNeutralize replaces the suspicious passages of the
+	// content with InjectionPlaceholder, and tags the memory.
+	InjectionActionNeutralize InjectionAction = "neutralize"
+
+	// InjectionActionBlock leaves the memory out of the results.
+	InjectionActionBlock InjectionAction = "block"
+
+	// InjectionActionAllow returns the memory unchanged. Only useful as the
+	// decision of an InjectionPolicy.
+	InjectionActionAllow InjectionAction = "allow"
+)
+
+// InjectionMetadataKey is the metadata key holding the suspicious passages
+// ([]string) of memories tagged or neutralized by the injection guard.
+const InjectionMetadataKey = "suspected_injection"
+
+// InjectionPlaceholder replaces the suspicious passages of neutralized
+// memories.
+const InjectionPlaceholder = "[filtered]"
+
+// InjectionPolicy decides what to do with a search result whose content
+// matched the injection patterns; matches holds the suspicious passages.
+// Returning "" applies InjectionGuardConfig.Action.
+type InjectionPolicy func(content string, matches []string) InjectionAction
+
+// InjectionGuardConfig configures the screening of search results for
+// prompt injections, for results that are inserted into LLM prompts.
+//
+// Memory content that matches one of the built-in patterns (such as "ignore
+// all previous instructions", requests for the system prompt, or chat role
+// markers) or of Patterns is tagged, neutralized or left out of the
+// results of Search, SearchWithDiagnostics, SearchByKeyword,
+// SearchByEntity, SearchMemories and SearchStream. Stored memories are not
+// modified.
+//
+// Example:
+//
+//	InjectionGuard: &core.InjectionGuardConfig{
+//	    Enabled: true,
+//	    Action:  core.InjectionActionNeutralize,
+//	}
+type InjectionGuardConfig struct {
+	// Enabled screens the search results.
+	Enabled bool `json:"enabled"`
+
+	// Action is applied to suspicious memories: "tag", "neutralize" or
+	// "block". Default: "tag"
+	Action InjectionAction `json:"action,omitempty"`
+
+	// Patterns are additional regular expressions of suspicious content,
+	// matched case-insensitively (optional).
+	Patterns []string `json:"patterns,omitempty"`
+
+	// Policy decides the action per memory instead of Action (optional).
+	Policy InjectionPolicy `json:"-"`
+}
+
+// defaultInjectionPatterns match common prompt injection phrasings.
+var defaultInjectionPatterns = []string{
+	`\b(ignore|disregard|forget|override)\s+(all\s+|any\s+)?(of\s+)?(the\s+|your\s+)?(previous|prior|above|earlier|preceding|system)\s+(instructions|prompts?|messages|rules|directions)`,
+	`\b(reveal|print|show|repeat|output|leak)\s+(me\s+)?(your|the)\s+(system|hidden|initial|original)\s+(prompt|instructions|message)`,
+	`\byou\s+are\s+now\s+(in\s+)?(developer|jailbreak|dan|unrestricted|god)\s*mode\b`,
+	`\bnew\s+(system\s+)?instructions\s*:`,
+	`</?\s*(system|assistant)\s*>`,
+	`(?m)^\s*(system|assistant)\s*:`,
+	`<\|im_(start|end)\|>`,
+	`\[/?INST\]`,
+}
+
+// injectionGuard screens search results according to an
+// InjectionGuardConfig.
+type injectionGuard struct {
+	action   InjectionAction
+	patterns []*regexp.Regexp
+	policy   InjectionPolicy
+}
+
+// newInjectionGuard compiles the patterns of cfg, and returns nil if the
+// guard is disabled.
+func newInjectionGuard(cfg *InjectionGuardConfig) (*injectionGuard, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+	g := &injectionGuard{action: cfg.Action, policy: cfg.Policy}
+	if g.action == "" {
+		g.action = InjectionActionTag
+	}
+	for _, pattern := range append(defaultInjectionPatterns, cfg.Patterns...) {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("injection pattern %q: %w", pattern, err)
+		}
+		g.patterns = append(g.patterns, re)
+	}
+	return g, nil
+}
+
+// isInjectionAction reports whether action can be configured as the action
+// of the injection guard.
+func isInjectionAction(action InjectionAction) bool {
+	switch action {
+	case InjectionActionTag, InjectionActionNeutralize, InjectionActionBlock:
+		return true
+	}
+	return false
+}
+
+// matches returns the suspicious passages of content.
+func (g *injectionGuard) matches(content string) []string {
+	var matches []string
+	for _, re := range g.patterns {
+		matches = append(matches, re.FindAllString(content, -1)...)
+	}
+	return matches
+}
+
+// screen applies the guard to the results of a search, in place. A nil
+// guard returns the results unchanged.
+func (g *injectionGuard) screen(memories []*storage.Memory) []*storage.Memory {
+	if g == nil {
+		return memories
+	}
+	kept := memories[:0]
+	for _, memory := range memories {
+		matches := g.matches(memory.Content)
+		if len(matches) == 0 {
+			kept = append(kept, memory)
+			continue
+		}
+
+		action := g.action
+		if g.policy != nil {
+			if decided := g.policy(memory.Content, matches); decided != "" {
+				action = decided
+			}
+		}
+		switch action {
+		case InjectionActionAllow:
+		case InjectionActionBlock:
+			continue
+		case InjectionActionNeutralize:
+			for _, re := range g.patterns {
+				memory.Content = re.ReplaceAllLiteralString(memory.Content, InjectionPlaceholder)
+			}
+			fallthrough
+		default:
+			if memory.Metadata == nil {
+				memory.Metadata = make(map[string]interface{})
+			}
+			memory.Metadata[InjectionMetadataKey] = matches
+		}
+		kept = append(kept, memory)
+	}
+	return kept
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 
@@ -12,7 +13,16 @@ import (
 // IntelligentAddResult represents the result of an intelligent add operation.
 type IntelligentAddResult struct {
 	// Results contains the list of memory operations performed
+	// (or planned, when DryRun is true)
 	Results []MemoryActionResult `json:"results"`
+
+	// DryRun indicates that the operations were planned but not executed
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// Conflicts contains UPDATE actions that were not applied because the
+	// memory was changed by another writer after it was searched. The newer
+	// content is kept; re-run the add to reconsider it.
+	Conflicts []MemoryActionResult `json:"conflicts,omitempty"`
 }
 
 // MemoryActionResult represents a single memory operation result.
@@ -20,6 +30,9 @@ type MemoryActionResult struct {
 	// ID is the memory ID
 	ID int64 `json:"id"`
 
+	// UID is the string ID of added memories (with IDTypeUUID)
+	UID string `json:"uid,omitempty"`
+
 	// Memory is the memory content
 	Memory string `json:"memory"`
 
@@ -36,11 +49,20 @@ type MemoryActionResult struct {
 // IntelligentAdd performs intelligent memory addition with fact extraction and LLM decision making.
 //
 // This method implements the complete intelligent add flow similar to Python SDK:
-//  1. Extract facts from messages using FactExtractor
+//  1. Extract facts from messages using FactExtractor, dropping facts whose
+//     confidence is below IntelligenceConfig.MinFactConfidence
 //  2. For each fact, search for similar existing memories
 //  3. Use LLM (DecisionMaker) to decide operations: ADD / UPDATE / DELETE / NONE
 //  4. Execute the decided operations
 //
+// Memories added from an extracted fact store its confidence in metadata["fact_confidence"].
+// Their sources (see Memory.Sources) are the WithSource sources and the
+// messages the fact was extracted from; updated memories keep their sources
+// and get the new ones.
+//
+// With WithDryRun(true), steps 1-3 run as usual but no memory is modified; the
+// result lists the planned operations and has DryRun set.
+//
 // Parameters:
 //   - ctx: Context for cancellation
 //   - messages: Messages to process (can be string, []map[string]interface{}, or single map)
@@ -58,11 +80,23 @@ type MemoryActionResult struct {
 //	    core.WithAgentID("agent_001"),
 //	)
 func (c *Client) IntelligentAdd(ctx context.Context, messages interface{}, opts ...AddOption) (*IntelligentAddResult, error) {
+	ctx, err := c.begin(ctx, "IntelligentAdd")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	events := c.recordEvents(ctx, "IntelligentAdd")
+	defer events.publish()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	// Apply options
 	addOpts := applyAddOptions(opts)
+	if addOpts.UID != "" {
+		return nil, NewMemoryError("IntelligentAdd", fmt.Errorf("%w: a UID can only be set by Add", ErrInvalidInput))
+	}
 
 	// Check if intelligent manager is available
 	if c.intelligentManager == nil {
@@ -76,7 +110,7 @@ func (c *Client) IntelligentAdd(ctx context.Context, messages interface{}, opts
 
 	// Step 1: Extract facts from messages
 	log.Println("Extracting facts from messages...")
-	facts, err := c.intelligentManager.ExtractFacts(ctx, messages)
+	extracted, err := c.intelligentManager.ExtractFactsWithConfidence(ctx, messages)
 	if err != nil {
 		// Check if fallback to simple add is enabled
 		if c.config.Intelligence != nil && c.config.Intelligence.FallbackToSimpleAdd {
@@ -86,24 +120,54 @@ func (c *Client) IntelligentAdd(ctx context.Context, messages interface{}, opts
 		return nil, fmt.Errorf("failed to extract facts: %w", err)
 	}
 
-	if len(facts) == 0 {
+	if len(extracted) == 0 {
 		log.Println("No facts extracted, skip intelligent add")
 		if c.config.Intelligence != nil && c.config.Intelligence.FallbackToSimpleAdd {
 			log.Println("No facts extracted, falling back to simple add")
 			return c.fallbackToSimpleAdd(ctx, messages, opts...)
 		}
-		return &IntelligentAddResult{Results: []MemoryActionResult{}}, nil
+		return &IntelligentAddResult{Results: []MemoryActionResult{}, DryRun: addOpts.DryRun}, nil
+	}
+
+	// Drop low-confidence facts
+	minConfidence := 0.0
+	if c.config.Intelligence != nil {
+		minConfidence = c.config.Intelligence.MinFactConfidence
+	}
+	attribution := newAttribution(messages, extracted, addOpts.Sources)
+	facts := make([]string, 0, len(extracted))
+	factConfidence := make(map[string]float64, len(extracted))
+	factEntities := make(map[string][]string, len(extracted))
+	for _, fact := range extracted {
+		if fact.Confidence < minConfidence {
+			log.Printf("Dropping low-confidence fact '%s' (confidence %.2f < %.2f)", fact.Text, fact.Confidence, minConfidence)
+			continue
+		}
+		facts = append(facts, fact.Text)
+		factConfidence[fact.Text] = fact.Confidence
+		factEntities[fact.Text] = mergeEntities(addOpts.Entities, fact.Entities...)
+	}
+
+	if len(facts) == 0 {
+		log.Println("All extracted facts are below the confidence threshold, skip intelligent add")
+		return &IntelligentAddResult{Results: []MemoryActionResult{}, DryRun: addOpts.DryRun}, nil
 	}
 
 	log.Printf("Extracted %d facts: %v", len(facts), facts)
 
-	// Step 2: Search for similar memories for each fact
+	// Step 2: Search for similar memories for each fact, among the memories
+	// of the embedder routed to by the memory type or language of the facts
 	existingMemories := make([]*Memory, 0)
 	factEmbeddings := make(map[string][]float64)
+	routeMetadata := copyMetadata(addOpts.Metadata)
+	addMetadataFields(routeMetadata, addOpts)
+	c.setLanguage(routeMetadata, parseMessagesToString(messages), addOpts)
+	route := c.routeFor(routeMetadata)
+	provider := c.embedderOf(route)
 
 	for _, fact := range facts {
 		// Generate embedding for the fact
-		embedding, err := c.embedder.Embed(ctx, fact)
+		embedding, err := provider.Embed(ctx, fact)
 		if err != nil {
 			log.Printf("Failed to generate embedding for fact '%s': %v", fact, err)
 			continue
@@ -118,9 +182,13 @@ func (c *Client) IntelligentAdd(ctx context.Context, messages interface{}, opts
 			MinScore: 0.0,
 			Query:    fact, // Pass fact text for future hybrid search
 			Filters:  addOpts.Filters,
+			Now:      c.now(),
 		}
 
-		similar, err := c.storage.Search(ctx, embedding, searchOpts)
+		similar, err := c.storage.Search(ctx, embedding, routeSearchOptions(route, searchOpts))
+		if err == nil {
+			similar, err = c.resolveChunks(ctx, c.inRoute(route, similar), nil)
+		}
 		if err != nil {
 			log.Printf("Failed to search for similar memories: %v", err)
 			continue
@@ -150,10 +218,12 @@ func (c *Client) IntelligentAdd(ctx context.Context, messages interface{}, opts
 
 	// Create temporary ID mapping (index -> real ID)
 	tempIDMapping := make(map[string]int64)
+	memoryVersions := make(map[int64]int64, len(existingMemoriesList))
 	existingForDecision := make([]intelligence.ExistingMemory, len(existingMemoriesList))
 	for i, mem := range existingMemoriesList {
 		tempID := fmt.Sprintf("%d", i)
 		tempIDMapping[tempID] = mem.ID
+		memoryVersions[mem.ID] = mem.Version
 		existingForDecision[i] = intelligence.ExistingMemory{
 			ID:   tempID,
 			Text: mem.Content,
@@ -179,11 +249,19 @@ func (c *Client) IntelligentAdd(ctx context.Context, messages interface{}, opts
 			log.Println("No actions from LLM, falling back to simple add")
 			return c.fallbackToSimpleAdd(ctx, messages, opts...)
 		}
-		return &IntelligentAddResult{Results: []MemoryActionResult{}}, nil
+		return &IntelligentAddResult{Results: []MemoryActionResult{}, DryRun: addOpts.DryRun}, nil
+	}
+
+	if addOpts.DryRun {
+		return &IntelligentAddResult{
+			Results: planActions(actions, tempIDMapping, factConfidence, factEntities, attribution, addOpts),
+			DryRun:  true,
+		}, nil
 	}
 
 	// Step 4: Execute actions
 	results := make([]MemoryActionResult, 0)
+	var conflicts []MemoryActionResult
 	actionCounts := map[string]int{"ADD": 0, "UPDATE": 0, "DELETE": 0, "NONE": 0}
 
 	for _, action := range actions {
@@ -207,7 +285,7 @@ func (c *Client) IntelligentAdd(ctx context.Context, messages interface{}, opts
 			embedding := factEmbeddings[actionText]
 			if embedding == nil {
 				// Generate new embedding if not in cache
-				embedding, err = c.embedder.Embed(ctx, actionText)
+				embedding, err = provider.Embed(ctx, actionText)
 				if err != nil {
 					log.Printf("Failed to generate embedding for ADD action: %v", err)
 					continue
@@ -216,24 +294,53 @@ func (c *Client) IntelligentAdd(ctx context.Context, messages interface{}, opts
 
 			metadata := copyMetadata(addOpts.Metadata)
 			addMetadataFields(metadata, addOpts)
+			if language := metadataString(routeMetadata, languageKey); language != "" {
+				metadata[languageKey] = language
+			}
+			setRoute(metadata, route)
+			if confidence, ok := factConfidence[actionText]; ok {
+				metadata["fact_confidence"] = confidence
+			}
+			sources := attribution.sources(actionText)
+			if len(sources) > 0 {
+				metadata[sourcesKey] = sourcesMetadata(sources)
+			}
+			entities := textEntities(factEntities, actionText, addOpts)
+			if len(entities) > 0 {
+				metadata[entitiesKey] = entitiesMetadata(entities)
+			}
+
+			uid, err := c.newUID()
+			if err != nil {
+				log.Printf("Failed to generate memory ID: %v", err)
+				continue
+			}
 
 			memory := &Memory{
 				ID:                c.snowflakeNode.Generate().Int64(),
+				UID:               uid,
+				Version:           1,
 				UserID:            addOpts.UserID,
 				AgentID:           addOpts.AgentID,
 				Content:           actionText,
 				Embedding:         embedding,
 				Metadata:          metadata,
 				RetentionStrength: 1.0,
+				Tags:              normalizeTags(addOpts.Tags),
+				ExpiresAt:         resolveExpiresAt(addOpts, c.now()),
+				Sources:           sources,
+				Entities:          entities,
 			}
 
-			if err := c.storage.Insert(ctx, toStorageMemory(memory)); err != nil {
+			if err := c.insertNew(ctx, memory); err != nil {
 				log.Printf("Failed to insert memory: %v", err)
 				continue
 			}
+			events.add(&Event{Type: EventCreated, Memory: memory})
 
 			results = append(results, MemoryActionResult{
 				ID:       memory.ID,
+				UID:      memory.UID,
 				Memory:   actionText,
 				Event:    eventType,
 				Metadata: metadata,
@@ -249,18 +356,57 @@ func (c *Client) IntelligentAdd(ctx context.Context, messages interface{}, opts
 			}
 
 			// Generate new embedding
-			embedding, err := c.embedder.Embed(ctx, actionText)
+			embedding, err := provider.Embed(ctx, actionText)
 			if err != nil {
 				log.Printf("Failed to generate embedding for UPDATE action: %v", err)
 				continue
 			}
 
-			// Update the memory (without access control restrictions)
-			_, err = c.storage.Update(ctx, realMemoryID, actionText, embedding, nil)
+			// Add the new sources and entities to those of the memory, and
+			// record the update in its provenance
+			existing := uniqueMemories[realMemoryID]
+			sources := attribution.sources(actionText)
+			entities := mergeEntities(existing.Entities, textEntities(factEntities, actionText, addOpts)...)
+			updateOpts := &storage.UpdateOptions{
+				ExpectedVersion: memoryVersions[realMemoryID],
+				Metadata: withProvenance(existing.Metadata, ProvenanceEntry{
+					Operation:       ProvenanceUpdate,
+					Time:            c.now().UTC(),
+					PreviousContent: existing.Content,
+					IncomingContent: actionText,
+					Sources:         sources,
+				}),
+				Now: c.now(),
+			}
+			if len(sources) > 0 {
+				updateOpts.Metadata[sourcesKey] = sourcesMetadata(mergeSources(existing.Sources, sources...))
+			}
+			if len(entities) > 0 {
+				updateOpts.Metadata[entitiesKey] = entitiesMetadata(entities)
+			}
+
+			// Update the memory (without access control restrictions), unless it
+			// changed since the LLM saw it
+			updated, err := c.storage.Update(ctx, realMemoryID, actionText, embedding, updateOpts)
+			if errors.Is(err, storage.ErrVersionConflict) {
+				log.Printf("Skipped stale update of memory %d: %v", realMemoryID, err)
+				conflicts = append(conflicts, MemoryActionResult{
+					ID:             realMemoryID,
+					Memory:         actionText,
+					Event:          eventType,
+					PreviousMemory: action.OldMemory,
+				})
+				continue
+			}
 			if err != nil {
 				log.Printf("Failed to update memory %d: %v", realMemoryID, err)
 				continue
 			}
+			if err := c.deleteChunks(ctx, realMemoryID, updated.UserID); err != nil {
+				log.Printf("Failed to delete chunks of memory %d: %v", realMemoryID, err)
+			}
+			updatedMemory := fromStorageMemory(updated)
+			events.add(&Event{Type: EventUpdated, Memory: updatedMemory, Diff: newEventDiff(uniqueMemories[realMemoryID], updatedMemory)})
 
 			results = append(results, MemoryActionResult{
 				ID:             realMemoryID,
@@ -282,6 +428,10 @@ func (c *Client) IntelligentAdd(ctx context.Context, messages interface{}, opts
 				log.Printf("Failed to delete memory %d: %v", realMemoryID, err)
 				continue
 			}
+			if err := c.deleteChunks(ctx, realMemoryID, ""); err != nil {
+				log.Printf("Failed to delete chunks of memory %d: %v", realMemoryID, err)
+			}
+			events.add(&Event{Type: EventDeleted, Memory: uniqueMemories[realMemoryID]})
 
 			results = append(results, MemoryActionResult{
 				ID:     realMemoryID,
@@ -303,7 +453,61 @@ func (c *Client) IntelligentAdd(ctx context.Context, messages interface{}, opts
 	log.Printf("Action counts: ADD=%d, UPDATE=%d, DELETE=%d, NONE=%d",
 		actionCounts["ADD"], actionCounts["UPDATE"], actionCounts["DELETE"], actionCounts["NONE"])
 
-	return &IntelligentAddResult{Results: results}, nil
+	return &IntelligentAddResult{Results: results, Conflicts: conflicts}, nil
+}
+
+// planActions converts LLM decisions into planned results without executing them.
+func planActions(actions []intelligence.MemoryAction, tempIDMapping map[string]int64, factConfidence map[string]float64, factEntities map[string][]string, attribution *attribution, addOpts *AddOptions) []MemoryActionResult {
+	results := make([]MemoryActionResult, 0, len(actions))
+	for _, action := range actions {
+		actionText := action.Text
+		if actionText == "" {
+			actionText = action.Memory
+		}
+		if actionText == "" || action.Event == "NONE" {
+			continue
+		}
+
+		switch action.Event {
+		case "ADD":
+			metadata := copyMetadata(addOpts.Metadata)
+			addMetadataFields(metadata, addOpts)
+			if confidence, ok := factConfidence[actionText]; ok {
+				metadata["fact_confidence"] = confidence
+			}
+			if sources := attribution.sources(actionText); len(sources) > 0 {
+				metadata[sourcesKey] = sourcesMetadata(sources)
+			}
+			if entities := textEntities(factEntities, actionText, addOpts); len(entities) > 0 {
+				metadata[entitiesKey] = entitiesMetadata(entities)
+			}
+			results = append(results, MemoryActionResult{
+				Memory:   actionText,
+				Event:    action.Event,
+				Metadata: metadata,
+			})
+
+		case "UPDATE", "DELETE":
+			realMemoryID, ok := tempIDMapping[action.ID]
+			if !ok {
+				log.Printf("Could not find real memory ID for action ID: %s", action.ID)
+				continue
+			}
+			result := MemoryActionResult{
+				ID:     realMemoryID,
+				Memory: actionText,
+				Event:  action.Event,
+			}
+			if action.Event == "UPDATE" {
+				result.PreviousMemory = action.OldMemory
+			}
+			results = append(results, result)
+
+		default:
+			log.Printf("Unknown event type: %s", action.Event)
+		}
+	}
+	return results
 }
 
 // fallbackToSimpleAdd falls back to simple add when intelligent add fails.
@@ -311,7 +515,17 @@ func (c *Client) fallbackToSimpleAdd(ctx context.Context, messages interface{},
 	// Convert messages to string content
 	content := parseMessagesToString(messages)
 
-	// Use the regular Add method
+	if applyAddOptions(opts).DryRun {
+		return &IntelligentAddResult{
+			Results: []MemoryActionResult{{Memory: content, Event: "ADD"}},
+			DryRun:  true,
+		}, nil
+	}
+
+	// Use the regular Add method, from all the messages
+	if sources := messageSources(messages); len(sources) > 0 {
+		opts = append(opts[:len(opts):len(opts)], WithSource(sources...))
+	}
 	memory, err := c.Add(ctx, content, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("fallback to simple add failed: %w", err)
@@ -321,6 +535,7 @@ func (c *Client) fallbackToSimpleAdd(ctx context.Context, messages interface{},
 		Results: []MemoryActionResult{
 			{
 				ID:     memory.ID,
+				UID:    memory.UID,
 				Memory: memory.Content,
 				Event:  "ADD",
 			},
@@ -366,13 +581,16 @@ func copyMetadata(metadata map[string]interface{}) map[string]interface{} {
 // addMetadataFields adds additional fields from options to metadata.
 func addMetadataFields(metadata map[string]interface{}, opts *AddOptions) {
 	if opts.RunID != "" {
-		metadata["run_id"] = opts.RunID
+		metadata[runIDKey] = opts.RunID
 	}
 	if opts.MemoryType != "" {
-		metadata["memory_type"] = opts.MemoryType
+		metadata[memoryTypeKey] = opts.MemoryType
 	}
 	if opts.Scope != "" {
-		metadata["scope"] = string(opts.Scope)
+		metadata[scopeKey] = string(opts.Scope)
+	}
+	if opts.TeamID != "" {
+		metadata[storage.TeamIDKey] = opts.TeamID
 	}
 	if opts.Prompt != "" {
 		metadata["prompt"] = opts.Prompt
@@ -385,6 +603,16 @@ func addMetadataFields(metadata map[string]interface{}, opts *AddOptions) {
 	}
 }
 
+// textEntities returns the entities of the memory text: those of the fact with
+// this text, or the WithEntities entities if the text is not an extracted fact
+// (e.g. the text of an UPDATE combining facts).
+func textEntities(factEntities map[string][]string, text string, addOpts *AddOptions) []string {
+	if entities, ok := factEntities[text]; ok {
+		return entities
+	}
+	return mergeEntities(nil, addOpts.Entities...)
+}
+
 // truncate truncates a string to the specified length.
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
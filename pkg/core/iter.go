@@ -0,0 +1,233 @@
+//go:build go1.23
+
+package core
+
+import (
+	"context"
+	"io"
+	"iter"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// iterBatchSize is the number of memories fetched per storage call by
+// Memories and SearchMemories.
+const iterBatchSize = 100
+
+// Memories returns an iterator over the memories matching opts, for use with
+// range-over-func (Go 1.23+).
+//
+// Memories are fetched in batches like GetAllStream, but without a goroutine
+// or channel: leaving the loop early stops fetching, and nothing needs to be
+// drained. The client lock is only held while a batch is fetched, so the loop
+// body may call other client methods. After an error is yielded the iteration
+// ends. WithLimitForGetAll caps the total number of memories as in GetAll
+// (default 100); WithLimitForGetAll(0) returns all matching memories.
+//
+// Example:
+//
+//	for memory, err := range client.Memories(ctx,
+//	    core.WithUserIDForGetAll("user_001"),
+//	    core.WithLimitForGetAll(0),
+//	) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    processMemory(memory)
+//	}
+func (c *Client) Memories(ctx context.Context, opts ...GetAllOption) iter.Seq2[*Memory, error] {
+	return func(yield func(*Memory, error) bool) {
+		ctx, err := c.begin(ctx, "Memories")
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer c.end()
+
+		getAllOpts := applyGetAllOptions(opts)
+		c.mu.RLock()
+		err = c.checkUnscopedGetAll(ctx, "Memories", getAllOpts)
+		c.mu.RUnlock()
+		if err != nil {
+			yield(nil, NewMemoryError("Memories", err))
+			return
+		}
+		teamIDs, err := c.userTeams(ctx, getAllOpts.UserID)
+		if err != nil {
+			yield(nil, NewMemoryError("Memories", err))
+			return
+		}
+
+		storageOpts := &storage.GetAllOptions{
+			UserID:  getAllOpts.UserID,
+			TeamIDs: teamIDs,
+			AgentID: getAllOpts.AgentID,
+			TimeRange: toStorageTimeRange(
+				getAllOpts.CreatedAfter, getAllOpts.CreatedBefore,
+				getAllOpts.UpdatedAfter, getAllOpts.UpdatedBefore,
+			),
+			Tags:              getAllOpts.Tags,
+			Filters:           getAllOpts.Filters,
+			WithoutEmbeddings: getAllOpts.WithoutEmbeddings,
+			Fields:            toStorageFields(getAllOpts.Fields),
+			Now:               c.now(),
+		}
+
+		offset := getAllOpts.Offset
+		returned := 0
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			storageOpts.Offset = offset
+			storageOpts.Limit = iterBatchSize
+			if getAllOpts.Limit > 0 {
+				remaining := getAllOpts.Limit - returned
+				if remaining <= 0 {
+					return
+				}
+				if remaining < storageOpts.Limit {
+					storageOpts.Limit = remaining
+				}
+			}
+
+			c.mu.RLock()
+			memories, err := c.storage.GetAll(ctx, storageOpts)
+			c.mu.RUnlock()
+			if err != nil {
+				yield(nil, NewMemoryError("Memories", err))
+				return
+			}
+
+			for _, memory := range memories {
+				if !yield(fromStorageMemory(memory), nil) {
+					return
+				}
+			}
+
+			if len(memories) < storageOpts.Limit {
+				return
+			}
+			offset += len(memories)
+			returned += len(memories)
+		}
+	}
+}
+
+// SearchMemories returns an iterator over the results of a vector search,
+// for use with range-over-func (Go 1.23+).
+//
+// Results are fetched from the storage backend in batches with keyset
+// pagination like SearchStream, highest score first. Leaving the loop early
+// stops fetching. After an error is yielded the iteration ends. WithLimit caps
+// the total number of results as in Search (default 10); WithLimit(0) returns
+// all matching memories.
+//
+// Example:
+//
+//	for memory, err := range client.SearchMemories(ctx, "Python programming",
+//	    core.WithUserIDForSearch("user_001"),
+//	    core.WithMinScore(0.7),
+//	    core.WithLimit(0),
+//	) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    processMemory(memory)
+//	}
+func (c *Client) SearchMemories(ctx context.Context, query string, opts ...SearchOption) iter.Seq2[*Memory, error] {
+	return func(yield func(*Memory, error) bool) {
+		ctx, err := c.begin(ctx, "SearchMemories")
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer c.end()
+
+		searchOpts := applySearchOptions(opts)
+		c.mu.RLock()
+		err = c.checkUnscopedSearch(ctx, "SearchMemories", query, searchOpts)
+		c.mu.RUnlock()
+		if err != nil {
+			yield(nil, NewMemoryError("SearchMemories", err))
+			return
+		}
+
+		route := c.singleRoute(searchOpts.Filters)
+		c.mu.RLock()
+		queryEmbedding, _, err := c.embedQuery(ctx, route, query)
+		staleness := c.staleness()
+		c.mu.RUnlock()
+		if err != nil {
+			yield(nil, NewMemoryError("SearchMemories", err))
+			return
+		}
+
+		teamIDs, err := c.userTeams(ctx, searchOpts.UserID)
+		if err != nil {
+			yield(nil, NewMemoryError("SearchMemories", err))
+			return
+		}
+
+		storageOpts := &storage.SearchOptions{
+			UserID:   searchOpts.UserID,
+			TeamIDs:  teamIDs,
+			AgentID:  searchOpts.AgentID,
+			Limit:    searchOpts.Limit,
+			MinScore: searchOpts.MinScore,
+			Filters:  searchOpts.Filters,
+			TimeRange: toStorageTimeRange(
+				searchOpts.CreatedAfter, searchOpts.CreatedBefore,
+				searchOpts.UpdatedAfter, searchOpts.UpdatedBefore,
+			),
+			Tags:              searchOpts.Tags,
+			WithoutEmbeddings: !searchOpts.IncludeEmbeddings,
+			Fields:            searchFields(searchOpts.Fields, staleness != nil),
+			Now:               c.now(),
+		}
+
+		c.mu.RLock()
+		memoryIter, err := c.storage.SearchIter(ctx, queryEmbedding, routeSearchOptions(route, storageOpts), iterBatchSize)
+		c.mu.RUnlock()
+		if err != nil {
+			yield(nil, NewMemoryError("SearchMemories", err))
+			return
+		}
+
+		// Memories already yielded for one of their chunks
+		seen := make(map[int64]bool)
+
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			c.mu.RLock()
+			memories, err := memoryIter.Next(ctx)
+			if err == nil {
+				memories, err = c.resolveChunks(ctx, c.inRoute(route, memories), seen)
+			}
+			guard := c.injectionGuard
+			c.mu.RUnlock()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(nil, NewMemoryError("SearchMemories", err))
+				return
+			}
+
+			for _, memory := range guard.screen(visible(memories, searchOpts)) {
+				result := fromStorageMemory(memory)
+				staleness.mark(result)
+				projectMemory(result, searchOpts.Fields)
+				if !yield(result, nil) {
+					return
+				}
+			}
+		}
+	}
+}
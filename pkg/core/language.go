@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+	"github.com/oceanbase/powermem-go/pkg/llm"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// translatePrompt asks the LLM for the translation of a search query.
+const translatePrompt = `Translate the search query below into %s. Keep names, numbers and identifiers as they are.
+Return only the translation, without any explanation.
+
+Query: %s`
+
+// setLanguage stores in metadata the language of a new memory with content:
+// the one given by WithLanguageForAdd, or else the one already in
+// metadata, or else the detected one if LanguageConfig.Detect is set.
+func (c *Client) setLanguage(metadata map[string]interface{}, content string, addOpts *AddOptions) {
+	if addOpts.Language != "" {
+		metadata[languageKey] = addOpts.Language
+		return
+	}
+	if metadataString(metadata, languageKey) != "" || c.config.Language == nil || !c.config.Language.Detect {
+		return
+	}
+	if language := intelligence.DetectLanguage(content); language != "" {
+		metadata[languageKey] = language
+	}
+}
+
+// searchTranslated runs searchRouted for query and, if query translation is
+// enabled, for its translations, and merges the results by score.
+func (c *Client) searchTranslated(ctx context.Context, query string, searchOpts *SearchOptions, storageOpts *storage.SearchOptions, diag *SearchDiagnostics) ([]*storage.Memory, error) {
+	memories, err := c.searchRouted(ctx, query, searchOpts.RetrievalMode, storageOpts, diag)
+	if err != nil {
+		return nil, err
+	}
+	if searchOpts.Language != "" {
+		return memories, nil
+	}
+	translations := c.translateQuery(ctx, query, diag)
+	if len(translations) == 0 {
+		return memories, nil
+	}
+
+	languages := make([]string, 0, len(translations))
+	for language := range translations {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	lists := [][]*storage.Memory{memories}
+	for _, language := range languages {
+		opts := *storageOpts
+		opts.Query = translations[language]
+		translated, err := c.searchRouted(ctx, translations[language], searchOpts.RetrievalMode, &opts, diag)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, translated)
+	}
+	return fuseSearchResults(storageOpts.Limit, lists...), nil
+}
+
+// translateQuery translates query into the languages of
+// LanguageConfig.TranslateQueries other than its own, and returns the
+// translations by language.
+//
+// Languages whose translation fails are skipped, so that the search falls
+// back to the query. Returns nil if translation is not enabled or no LLM is
+// configured.
+func (c *Client) translateQuery(ctx context.Context, query string, diag *SearchDiagnostics) map[string]string {
+	if c.config.Language == nil || len(c.config.Language.TranslateQueries) == 0 || c.llm == nil {
+		return nil
+	}
+	queryLanguage := intelligence.DetectLanguage(query)
+
+	start := time.Now()
+	translations := make(map[string]string)
+	for _, language := range c.config.Language.TranslateQueries {
+		if language == queryLanguage || translations[language] != "" {
+			continue
+		}
+		response, err := c.llm.GenerateWithMessages(ctx, []llm.Message{
+			{Role: "user", Content: fmt.Sprintf(translatePrompt, intelligence.LanguageName(language), query)},
+		})
+		if err != nil {
+			continue
+		}
+		if translation := strings.TrimSpace(response); translation != "" && translation != query {
+			translations[language] = translation
+		}
+	}
+	if diag != nil {
+		diag.LLMTime += time.Since(start)
+		if len(translations) > 0 {
+			diag.TranslatedQueries = translations
+		}
+	}
+	return translations
+}
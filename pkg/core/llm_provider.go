@@ -0,0 +1,34 @@
+package core
+
+import (
+	"github.com/oceanbase/powermem-go/pkg/llm"
+	"github.com/oceanbase/powermem-go/pkg/llm/recorder"
+	"github.com/oceanbase/powermem-go/pkg/secrets"
+)
+
+// newLLMProvider creates the LLM provider for cfg: the configured provider,
+// a router over it and its fallbacks, and a recorder around either if
+// recording is configured.
+func newLLMProvider(cfg LLMConfig, secretsProvider secrets.Provider) (llm.Provider, error) {
+	var provider llm.Provider
+	var err error
+	if len(cfg.Fallbacks) == 0 {
+		provider, err = newSingleLLMProvider(cfg, secretsProvider)
+	} else {
+		provider, err = newRoutedLLMProvider(cfg, secretsProvider)
+	}
+	if err != nil || cfg.Recording == nil {
+		return provider, err
+	}
+
+	client, err := recorder.NewClient(&recorder.Config{
+		Provider: provider,
+		Path:     cfg.Recording.Path,
+		Mode:     recorder.Mode(cfg.Recording.Mode),
+	})
+	if err != nil {
+		_ = provider.Close()
+		return nil, NewMemoryError("initLLM", err)
+	}
+	return client, nil
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/llm"
+	"github.com/oceanbase/powermem-go/pkg/llm/router"
+	"github.com/oceanbase/powermem-go/pkg/secrets"
+)
+
+// newRoutedLLMProvider creates a router.Client over the primary and fallback
+// providers of cfg.
+func newRoutedLLMProvider(cfg LLMConfig, secretsProvider secrets.Provider) (llm.Provider, error) {
+	routerCfg := &router.Config{}
+	if r := cfg.Routing; r != nil {
+		routerCfg.Strategy = router.Strategy(r.Strategy)
+		routerCfg.FailureThreshold = r.FailureThreshold
+		routerCfg.Cooldown = time.Duration(r.CooldownSeconds * float64(time.Second))
+	}
+
+	closeBackends := func() {
+		for _, b := range routerCfg.Backends {
+			_ = b.Provider.Close()
+		}
+	}
+	for _, backendCfg := range append([]LLMConfig{cfg}, cfg.Fallbacks...) {
+		backendCfg.Fallbacks, backendCfg.Routing, backendCfg.Recording = nil, nil, nil
+		provider, err := newSingleLLMProvider(backendCfg, secretsProvider)
+		if err != nil {
+			closeBackends()
+			return nil, err
+		}
+		routerCfg.Backends = append(routerCfg.Backends, router.Backend{
+			Name:     llmBackendName(backendCfg),
+			Provider: provider,
+		})
+	}
+
+	client, err := router.NewClient(routerCfg)
+	if err != nil {
+		closeBackends()
+		return nil, NewMemoryError("initLLM", err)
+	}
+	return client, nil
+}
+
+// llmBackendName names a router backend after its provider and model.
+func llmBackendName(cfg LLMConfig) string {
+	if cfg.Model == "" {
+		return cfg.Provider
+	}
+	return cfg.Provider + "/" + cfg.Model
+}
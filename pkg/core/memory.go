@@ -2,23 +2,30 @@ package core
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/bwmarrin/snowflake"
 	"github.com/oceanbase/powermem-go/pkg/embedder"
+	mockEmbedder "github.com/oceanbase/powermem-go/pkg/embedder/mock"
 	openaiEmbedder "github.com/oceanbase/powermem-go/pkg/embedder/openai"
 	qwenEmbedder "github.com/oceanbase/powermem-go/pkg/embedder/qwen"
 	"github.com/oceanbase/powermem-go/pkg/intelligence"
 	"github.com/oceanbase/powermem-go/pkg/llm"
 	anthropicLLM "github.com/oceanbase/powermem-go/pkg/llm/anthropic"
 	deepseekLLM "github.com/oceanbase/powermem-go/pkg/llm/deepseek"
+	mockLLM "github.com/oceanbase/powermem-go/pkg/llm/mock"
 	ollamaLLM "github.com/oceanbase/powermem-go/pkg/llm/ollama"
 	openaiLLM "github.com/oceanbase/powermem-go/pkg/llm/openai"
 	qwenLLM "github.com/oceanbase/powermem-go/pkg/llm/qwen"
 	"github.com/oceanbase/powermem-go/pkg/storage"
+	"github.com/oceanbase/powermem-go/pkg/storage/collections"
 	"github.com/oceanbase/powermem-go/pkg/storage/oceanbase"
 	postgresStore "github.com/oceanbase/powermem-go/pkg/storage/postgres"
+	"github.com/oceanbase/powermem-go/pkg/storage/routing"
 	sqliteStore "github.com/oceanbase/powermem-go/pkg/storage/sqlite"
+	"github.com/oceanbase/powermem-go/pkg/webhook"
 )
 
 // Client is the main PowerMem client for memory management.
@@ -56,6 +63,12 @@ type Client struct {
 	// embedder is the embedding provider for vector generation.
 	embedder embedder.Provider
 
+	// embedderRoutes are the embedders of Config.EmbedderRoutes.
+	embedderRoutes []*embedderRoute
+
+	// queryCache caches query embeddings (nil if not enabled).
+	queryCache *queryCache
+
 	// dedupManager manages memory deduplication (nil if not enabled).
 	dedupManager *intelligence.DedupManager
 
@@ -65,11 +78,26 @@ type Client struct {
 	// intelligentManager manages complete intelligent memory processing (nil if not enabled).
 	intelligentManager *intelligence.IntelligentMemoryManager
 
+	// injectionGuard screens search results for prompt injections (nil if not enabled).
+	injectionGuard *injectionGuard
+
 	// snowflakeNode generates unique IDs for memories.
 	snowflakeNode *snowflake.Node
 
 	// mu protects concurrent access to the client.
 	mu sync.RWMutex
+
+	// lifecycle tracks in-flight operations for Shutdown.
+	lifecycle lifecycle
+
+	// events holds the handlers registered with Subscribe.
+	events eventBus
+
+	// webhooks deliver the events to the endpoints of Config.Webhooks.
+	webhooks []*webhook.Client
+
+	// replication holds the remote store of Sync (see Config.Replication).
+	replication replicationState
 }
 
 // NewClient creates a new PowerMem client.
@@ -79,6 +107,7 @@ type Client struct {
 //   - LLM provider (OpenAI, Qwen, DeepSeek, Ollama, Anthropic)
 //   - Embedding provider (OpenAI, Qwen)
 //   - Intelligent features (if enabled in config)
+//   - Event webhooks (if configured)
 //
 // Parameters:
 //   - cfg: Configuration containing storage, LLM, and embedding settings
@@ -104,82 +133,141 @@ func NewClient(cfg *Config) (*Client, error) {
 	}
 
 	// Initialize storage
-	store, err := initStorage(cfg.VectorStore)
+	store, err := initStorage(cfg.VectorStore, cfg.Embedder.vectorDimensions())
 	if err != nil {
 		return nil, err
 	}
 
-	// Initialize LLM
-	llmProvider, err := initLLM(cfg.LLM)
+	// Initialize LLM and Embedder, resolving API keys from secrets if configured
+	secretsProvider := cachedSecrets(cfg.Secrets)
+	llmProvider, err := newLLMProvider(cfg.LLM, secretsProvider)
 	if err != nil {
+		_ = store.Close()
 		return nil, err
 	}
 
-	// Initialize Embedder
-	embedderProvider, err := initEmbedder(cfg.Embedder)
+	embedderProvider, err := newEmbedderProvider(cfg.Embedder, secretsProvider)
 	if err != nil {
+		_ = store.Close()
+		_ = llmProvider.Close()
 		return nil, err
 	}
-
-	// Initialize Snowflake ID generator
-	node, err := snowflake.NewNode(1)
+	embedderRoutes, err := newEmbedderRoutes(cfg.EmbedderRoutes, secretsProvider)
 	if err != nil {
-		return nil, NewMemoryError("NewClient", err)
+		_ = store.Close()
+		_ = llmProvider.Close()
+		_ = embedderProvider.Close()
+		return nil, err
 	}
 
 	client := &Client{
-		config:        cfg,
-		storage:       store,
-		llm:           llmProvider,
-		embedder:      embedderProvider,
-		snowflakeNode: node,
+		config:         cfg,
+		storage:        store,
+		llm:            llmProvider,
+		embedder:       embedderProvider,
+		embedderRoutes: embedderRoutes,
+		queryCache:     newQueryCache(cfg.QueryCache),
+	}
+
+	// Initialize Snowflake ID generator
+	if client.snowflakeNode, err = snowflake.NewNode(nodeID(cfg)); err != nil {
+		_ = client.closeResources()
+		return nil, NewMemoryError("NewClient", err)
 	}
 
 	// Initialize intelligent features (if enabled)
-	if cfg.Intelligence != nil && cfg.Intelligence.Enabled {
-		// Initialize deduplication manager
-		client.dedupManager = intelligence.NewDedupManager(
-			store,
-			cfg.Intelligence.DuplicateThreshold,
-		)
+	if err := client.initIntelligence(cfg); err != nil {
+		_ = client.closeResources()
+		return nil, NewMemoryError("NewClient", err)
+	}
+	if client.injectionGuard, err = newInjectionGuard(cfg.InjectionGuard); err != nil {
+		_ = client.closeResources()
+		return nil, NewMemoryError("NewClient", err)
+	}
 
-		// Initialize Ebbinghaus manager
-		client.ebbinghausManager = intelligence.NewEbbinghausManager(
-			cfg.Intelligence.DecayRate,
-			cfg.Intelligence.ReinforcementFactor,
-		)
+	// Start event webhooks (if configured)
+	if err := client.initWebhooks(cfg.Webhooks); err != nil {
+		_ = client.closeResources()
+		return nil, NewMemoryError("NewClient", err)
+	}
 
-		// Initialize intelligent memory manager (for full intelligent processing)
-		intelligenceConfig := &intelligence.Config{
-			DecayRate:           cfg.Intelligence.DecayRate,
-			ReinforcementFactor: cfg.Intelligence.ReinforcementFactor,
-			WorkingThreshold:    cfg.Intelligence.WorkingThreshold,
-			ShortTermThreshold:  cfg.Intelligence.ShortTermThreshold,
-			LongTermThreshold:   cfg.Intelligence.LongTermThreshold,
-			InitialRetention:    cfg.Intelligence.InitialRetention,
-			FallbackToSimpleAdd: cfg.Intelligence.FallbackToSimpleAdd,
-		}
-		// Set defaults if not specified
-		if intelligenceConfig.WorkingThreshold == 0 {
-			intelligenceConfig.WorkingThreshold = 0.3
-		}
-		if intelligenceConfig.ShortTermThreshold == 0 {
-			intelligenceConfig.ShortTermThreshold = 0.6
-		}
-		if intelligenceConfig.LongTermThreshold == 0 {
-			intelligenceConfig.LongTermThreshold = 0.8
-		}
-		if intelligenceConfig.InitialRetention == 0 {
-			intelligenceConfig.InitialRetention = 1.0
-		}
+	return client, nil
+}
 
-		client.intelligentManager = intelligence.NewIntelligentMemoryManager(
-			llmProvider,
-			intelligenceConfig,
-		)
+// initIntelligence creates the intelligence managers from cfg, or clears them
+// if intelligence is disabled.
+func (c *Client) initIntelligence(cfg *Config) error {
+	c.dedupManager = nil
+	c.ebbinghausManager = nil
+	c.intelligentManager = nil
+	if cfg.Intelligence == nil || !cfg.Intelligence.Enabled {
+		return nil
 	}
 
-	return client, nil
+	// Initialize deduplication manager
+	dedupManager, err := intelligence.NewDedupManagerWithMerge(
+		c.storage,
+		cfg.Intelligence.DuplicateThreshold,
+		&intelligence.MergeConfig{
+			Strategy: intelligence.MergeStrategy(cfg.Intelligence.MergeStrategy),
+			LLM:      c.llm,
+			Prompt:   cfg.Intelligence.MergePrompt,
+			Embed:    c.embedder.Embed,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	c.dedupManager = dedupManager.WithClock(cfg.Clock)
+
+	// Initialize Ebbinghaus manager
+	c.ebbinghausManager = intelligence.NewEbbinghausManager(
+		cfg.Intelligence.DecayRate,
+		cfg.Intelligence.ReinforcementFactor,
+	).WithClock(cfg.Clock)
+
+	// Initialize intelligent memory manager (for full intelligent processing)
+	intelligenceConfig := &intelligence.Config{
+		DecayRate:           cfg.Intelligence.DecayRate,
+		ReinforcementFactor: cfg.Intelligence.ReinforcementFactor,
+		WorkingThreshold:    cfg.Intelligence.WorkingThreshold,
+		ShortTermThreshold:  cfg.Intelligence.ShortTermThreshold,
+		LongTermThreshold:   cfg.Intelligence.LongTermThreshold,
+		InitialRetention:    cfg.Intelligence.InitialRetention,
+		FallbackToSimpleAdd: cfg.Intelligence.FallbackToSimpleAdd,
+		Ranking:             toIntelligenceRanking(cfg.Intelligence.Ranking),
+		AgentPolicies:       toIntelligencePolicies(cfg.Intelligence.AgentPolicies),
+		MemoryTypePolicies:  toIntelligencePolicies(cfg.Intelligence.MemoryTypePolicies),
+		Clock:               cfg.Clock,
+	}
+	// Set defaults if not specified
+	if intelligenceConfig.WorkingThreshold == 0 {
+		intelligenceConfig.WorkingThreshold = 0.3
+	}
+	if intelligenceConfig.ShortTermThreshold == 0 {
+		intelligenceConfig.ShortTermThreshold = 0.6
+	}
+	if intelligenceConfig.LongTermThreshold == 0 {
+		intelligenceConfig.LongTermThreshold = 0.8
+	}
+	if intelligenceConfig.InitialRetention == 0 {
+		intelligenceConfig.InitialRetention = 1.0
+	}
+
+	c.intelligentManager = intelligence.NewIntelligentMemoryManager(
+		c.llm,
+		intelligenceConfig,
+	)
+
+	return nil
+}
+
+// now returns the current time of Config.Clock.
+func (c *Client) now() time.Time {
+	if c.config.Clock == nil {
+		return time.Now()
+	}
+	return c.config.Clock.Now()
 }
 
 // Add adds a new memory to the store.
@@ -192,6 +280,10 @@ func NewClient(cfg *Config) (*Client, error) {
 // If intelligent deduplication is enabled and a duplicate is found,
 // the memories are merged instead of creating a new one.
 //
+// If Config.SkipExactDuplicates or WithSkipExactDuplicate is set and the
+// user already has a memory with the same content, that memory is returned
+// unchanged, without embedding, LLM calls or events.
+//
 // Parameters:
 //   - ctx: Context for cancellation
 //   - content: Memory content (text string)
@@ -210,12 +302,41 @@ func NewClient(cfg *Config) (*Client, error) {
 //	    }),
 //	)
 func (c *Client) Add(ctx context.Context, content string, opts ...AddOption) (*Memory, error) {
+	ctx, err := c.begin(ctx, "Add")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	events := c.recordEvents(ctx, "Add")
+	defer events.publish()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	// Apply options
 	addOpts := applyAddOptions(opts)
 
+	// Add returns a stored memory: it cannot preview a write
+	if addOpts.DryRun {
+		return nil, NewMemoryError("Add", fmt.Errorf("%w: dry runs are only supported by IntelligentAdd", ErrInvalidInput))
+	}
+
+	if err := c.checkContentSize(content); err != nil {
+		return nil, NewMemoryError("Add", err)
+	}
+
+	// A UID set by the caller names exactly one new memory
+	uid := ""
+	if addOpts.UID != "" {
+		if addOpts.Infer {
+			return nil, NewMemoryError("Add", fmt.Errorf("%w: a UID cannot be set with inference", ErrInvalidInput))
+		}
+		if uid, err = checkNewUID(addOpts.UID); err != nil {
+			return nil, NewMemoryError("Add", err)
+		}
+	}
+
 	// Check context cancellation
 	select {
 	case <-ctx.Done():
@@ -223,6 +344,18 @@ func (c *Client) Add(ctx context.Context, content string, opts ...AddOption) (*M
 	default:
 	}
 
+	// Content added again is answered from the store, without embedding or
+	// LLM calls
+	if c.config.SkipExactDuplicates || addOpts.SkipExactDuplicate {
+		existing, err := c.exactDuplicate(ctx, content, addOpts)
+		if err != nil {
+			return nil, NewMemoryError("Add", err)
+		}
+		if existing != nil {
+			return existing, nil
+		}
+	}
+
 	// If Infer is enabled and intelligent manager is available, use IntelligentAdd
 	// This provides the complete intelligent flow: fact extraction -> search -> LLM decision -> execute
 	if addOpts.Infer && c.intelligentManager != nil && c.llm != nil {
@@ -243,6 +376,7 @@ func (c *Client) Add(ctx context.Context, content string, opts ...AddOption) (*M
 			firstResult := result.Results[0]
 			return &Memory{
 				ID:      firstResult.ID,
+				UID:     firstResult.UID,
 				Content: firstResult.Memory,
 				UserID:  addOpts.UserID,
 				AgentID: addOpts.AgentID,
@@ -251,30 +385,6 @@ func (c *Client) Add(ctx context.Context, content string, opts ...AddOption) (*M
 		// If no results from IntelligentAdd, fall through to simple add
 	}
 
-	// Generate embedding
-	embedding, err := c.embedder.Embed(ctx, content)
-	if err != nil {
-		return nil, NewMemoryError("Add", err)
-	}
-
-	// Legacy deduplication logic (kept for backward compatibility)
-	// This is simpler than IntelligentAdd and only does basic similarity checking
-	if addOpts.Infer && c.dedupManager != nil && c.intelligentManager == nil {
-		isDup, existingID, err := c.dedupManager.CheckDuplicate(ctx, embedding, addOpts.UserID, addOpts.AgentID)
-		if err != nil {
-			return nil, NewMemoryError("Add", err)
-		}
-		if isDup {
-			// Merge memories
-			merged, err := c.dedupManager.MergeMemories(ctx, existingID, content, embedding)
-			if err != nil {
-				return nil, NewMemoryError("Add", err)
-			}
-			// Convert back to core.Memory type
-			return fromIntelligenceMemory(merged), nil
-		}
-	}
-
 	// Build metadata, merge all additional parameters
 	metadata := make(map[string]interface{})
 	if addOpts.Metadata != nil {
@@ -284,13 +394,16 @@ func (c *Client) Add(ctx context.Context, content string, opts ...AddOption) (*M
 	}
 	// Add extra parameters to metadata (if provided)
 	if addOpts.RunID != "" {
-		metadata["run_id"] = addOpts.RunID
+		metadata[runIDKey] = addOpts.RunID
 	}
 	if addOpts.MemoryType != "" {
-		metadata["memory_type"] = addOpts.MemoryType
+		metadata[memoryTypeKey] = addOpts.MemoryType
 	}
 	if addOpts.Scope != "" {
-		metadata["scope"] = string(addOpts.Scope)
+		metadata[scopeKey] = string(addOpts.Scope)
+	}
+	if addOpts.TeamID != "" {
+		metadata[storage.TeamIDKey] = addOpts.TeamID
 	}
 	if addOpts.Prompt != "" {
 		metadata["prompt"] = addOpts.Prompt
@@ -301,21 +414,95 @@ func (c *Client) Add(ctx context.Context, content string, opts ...AddOption) (*M
 			metadata[k] = v
 		}
 	}
+	if len(addOpts.Sources) > 0 {
+		metadata[sourcesKey] = sourcesMetadata(addOpts.Sources)
+	}
+	if entities := mergeEntities(nil, addOpts.Entities...); len(entities) > 0 {
+		metadata[entitiesKey] = entitiesMetadata(entities)
+	}
+
+	c.setLanguage(metadata, content, addOpts)
+
+	// Generate embedding (of each chunk if the content is long), with the
+	// embedder routed to by the memory type or language of the memory
+	route := c.routeFor(metadata)
+	setRoute(metadata, route)
+	embedding, chunked, err := c.embedContent(ctx, c.embedderOf(route), content)
+	if err != nil {
+		return nil, NewMemoryError("Add", err)
+	}
+
+	// Legacy deduplication logic (kept for backward compatibility)
+	// This is simpler than IntelligentAdd and only does basic similarity checking.
+	// Chunked and routed content is not merged.
+	if addOpts.Infer && c.dedupManager != nil && c.intelligentManager == nil && chunked == nil && route == nil {
+		isDup, existingID, err := c.dedupManager.CheckDuplicate(ctx, embedding, addOpts.UserID, addOpts.AgentID)
+		if err != nil {
+			return nil, NewMemoryError("Add", err)
+		}
+
+		// Keep the existing memory for the event diff, and don't merge into chunks
+		var existing *Memory
+		if isDup {
+			if stored, err := c.storage.Get(ctx, existingID, &storage.GetOptions{Now: c.now()}); err == nil {
+				existing = fromStorageMemory(stored)
+				isDup = existing.ParentID == 0
+			}
+		}
+		if isDup {
+
+			// Merge memories
+			merged, err := c.dedupManager.MergeMemories(ctx, existingID, content, embedding)
+			if err != nil {
+				return nil, NewMemoryError("Add", err)
+			}
+			// Convert back to core.Memory type
+			memory := fromIntelligenceMemory(merged)
+			event := &Event{Type: EventMerged, Memory: memory}
+			if existing != nil {
+				event.Diff = newEventDiff(existing, memory)
+			}
+			events.add(event)
+			return memory, nil
+		}
+	}
+
+	if uid == "" {
+		if uid, err = c.newUID(); err != nil {
+			return nil, NewMemoryError("Add", err)
+		}
+	}
 
 	// Insert into storage
+	now := c.now()
 	memory := &Memory{
 		ID:                c.snowflakeNode.Generate().Int64(),
+		UID:               uid,
+		Version:           1,
 		UserID:            addOpts.UserID,
 		AgentID:           addOpts.AgentID,
 		Content:           content,
 		Embedding:         embedding,
 		Metadata:          metadata,
 		RetentionStrength: 1.0, // Initial strength: 1.0
+		Tags:              normalizeTags(addOpts.Tags),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		ExpiresAt:         resolveExpiresAt(addOpts, now),
+		Sources:           sourcesFromMetadata(metadata),
+		Entities:          entitiesFromMetadata(metadata),
 	}
 
-	if err := c.storage.Insert(ctx, toStorageMemory(memory)); err != nil {
+	if err := c.insertNew(ctx, memory); err != nil {
 		return nil, NewMemoryError("Add", err)
 	}
+	if chunked != nil {
+		if err := c.insertChunks(ctx, memory, chunked); err != nil {
+			_ = c.storage.Delete(ctx, memory.ID, nil)
+			return nil, NewMemoryError("Add", err)
+		}
+	}
+	events.add(&Event{Type: EventCreated, Memory: memory})
 
 	return memory, nil
 }
@@ -328,6 +515,11 @@ func (c *Client) Add(ctx context.Context, content string, opts ...AddOption) (*M
 //  3. Returns results sorted by similarity score
 //
 // Results can be filtered by UserID, AgentID, and custom metadata filters.
+// Without WithUserIDForSearch, the memories of every user are searched,
+// which must be allowed with WithAllowCrossUser(true): the search fails
+// with ErrInvalidInput otherwise. Such unscoped searches are recorded in the
+// audit log when it is enabled (see AuditConfig), and are not run if they
+// cannot be recorded.
 //
 // Parameters:
 //   - ctx: Context for cancellation
@@ -344,38 +536,122 @@ func (c *Client) Add(ctx context.Context, content string, opts ...AddOption) (*M
 //	    core.WithMinScore(0.7),
 //	)
 func (c *Client) Search(ctx context.Context, query string, opts ...SearchOption) ([]*Memory, error) {
+	ctx, err := c.begin(ctx, "Search")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	// Apply search options
 	searchOpts := applySearchOptions(opts)
-
-	// Generate query embedding
-	queryEmbedding, err := c.embedder.Embed(ctx, query)
+	if err := c.checkUnscopedSearch(ctx, "Search", query, searchOpts); err != nil {
+		return nil, NewMemoryError("Search", err)
+	}
+	memories, err := c.search(ctx, query, searchOpts, nil)
 	if err != nil {
 		return nil, NewMemoryError("Search", err)
 	}
+	return memories, nil
+}
+
+// SearchWithDiagnostics works like Search and additionally reports how the
+// results were produced: candidate counts before and after the score
+// threshold, the retrieval mode, and the time spent in each stage.
+//
+// Collecting diagnostics costs extra COUNT queries in the store, so use it
+// for debugging rather than on every request.
+//
+// Example:
+//
+//	results, diag, err := client.SearchWithDiagnostics(ctx, "Python programming",
+//	    core.WithUserIDForSearch("user_001"),
+//	    core.WithMinScore(0.7),
+//	)
+//	fmt.Printf("%d of %d candidates passed the threshold\n", diag.AboveThreshold, diag.Candidates)
+func (c *Client) SearchWithDiagnostics(ctx context.Context, query string, opts ...SearchOption) ([]*Memory, *SearchDiagnostics, error) {
+	ctx, err := c.begin(ctx, "SearchWithDiagnostics")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	searchOpts := applySearchOptions(opts)
+	if err := c.checkUnscopedSearch(ctx, "SearchWithDiagnostics", query, searchOpts); err != nil {
+		return nil, nil, NewMemoryError("SearchWithDiagnostics", err)
+	}
+	diag := &SearchDiagnostics{
+		Query:         query,
+		RetrievalMode: searchOpts.RetrievalMode,
+	}
+	if diag.RetrievalMode == "" {
+		diag.RetrievalMode = RetrievalModeVector
+	}
+
+	start := time.Now()
+	memories, err := c.search(ctx, query, searchOpts, diag)
+	if err != nil {
+		return nil, nil, NewMemoryError("SearchWithDiagnostics", err)
+	}
+	diag.Returned = len(memories)
+	diag.TotalTime = time.Since(start)
+	return memories, diag, nil
+}
+
+// search runs a search and records diagnostics into diag if it is not nil.
+func (c *Client) search(ctx context.Context, query string, searchOpts *SearchOptions, diag *SearchDiagnostics) ([]*Memory, error) {
+	teamIDs, err := c.userTeams(ctx, searchOpts.UserID)
+	if err != nil {
+		return nil, err
+	}
+	intelligent := c.config.Intelligence != nil && c.config.Intelligence.Enabled && c.intelligentManager != nil
+	staleness := c.staleness()
+	if searchOpts.Explain && diag == nil {
+		// Collect the rewrites of the query for the explanations
+		diag = &SearchDiagnostics{withoutStats: true}
+	}
 
 	// Execute vector similarity search
 	storageOpts := &storage.SearchOptions{
 		UserID:    searchOpts.UserID,
+		TeamIDs:   teamIDs,
 		AgentID:   searchOpts.AgentID,
 		Limit:     searchOpts.Limit,
 		MinScore:  searchOpts.MinScore,
 		Threshold: searchOpts.MinScore, // Python SDK compatibility
 		Query:     query,               // Pass original query for future hybrid search
 		Filters:   searchOpts.Filters,
+		TimeRange: toStorageTimeRange(
+			searchOpts.CreatedAfter, searchOpts.CreatedBefore,
+			searchOpts.UpdatedAfter, searchOpts.UpdatedBefore,
+		),
+		Tags:              searchOpts.Tags,
+		EfSearch:          searchOpts.EfSearch,
+		WithoutEmbeddings: !searchOpts.IncludeEmbeddings,
+		Fields:            searchFields(searchOpts.Fields, intelligent || staleness != nil || searchOpts.Explain),
+		Now:               c.now(),
 	}
 
-	memories, err := c.storage.Search(ctx, queryEmbedding, storageOpts)
+	memories, err := c.searchTranslated(ctx, query, searchOpts, storageOpts, diag)
 	if err != nil {
-		return nil, NewMemoryError("Search", err)
+		return nil, err
+	}
+	memories, err = c.resolveChunks(ctx, memories, nil)
+	if err != nil {
+		return nil, err
 	}
+	memories = c.injectionGuard.screen(visible(memories, searchOpts))
 
 	coreMemories := fromStorageMemories(memories)
 
 	// Apply intelligent processing if enabled
-	if c.config.Intelligence != nil && c.config.Intelligence.Enabled && c.intelligentManager != nil {
+	if intelligent {
+		start := time.Now()
+
 		// Convert to map format for ProcessSearchResults
 		resultsMap := memoriesToMaps(coreMemories)
 
@@ -384,11 +660,108 @@ func (c *Client) Search(ctx context.Context, query string, opts ...SearchOption)
 
 		// Convert back to Memory format
 		coreMemories = mapsToMemories(processedResults)
+
+		if diag != nil {
+			diag.IntelligentRanking = true
+			diag.RankingTime = time.Since(start)
+		}
 	}
 
+	if searchOpts.Explain {
+		c.explain(coreMemories, query, searchOpts, diag)
+	}
+	for _, memory := range coreMemories {
+		staleness.mark(memory)
+		projectMemory(memory, searchOpts.Fields)
+	}
 	return coreMemories, nil
 }
 
+// visible removes the memories that searches leave out unless searchOpts
+// includes them: memories flagged as incorrect by Feedback and the items of
+// working memories.
+func visible(memories []*storage.Memory, searchOpts *SearchOptions) []*storage.Memory {
+	kept := memories[:0]
+	for _, memory := range memories {
+		if isFlagged(memory) && !searchOpts.IncludeFlagged {
+			continue
+		}
+		if isWorking(memory) && !searchOpts.IncludeWorking {
+			continue
+		}
+		kept = append(kept, memory)
+	}
+	return kept
+}
+
+// SearchByKeyword searches memories by literal keyword match, without embedding the query.
+//
+// This is intended for exact identifiers such as order numbers, emails, and codes,
+// where vector search performs poorly and an embedding call is wasted. Matching is a
+// case-insensitive substring match on memory content; results are ordered newest first.
+//
+// Supports the same filtering options as Search (user, agent, filters, limit, time range).
+// WithMinScore has no effect since every match has a score of 1.0.
+//
+// Example:
+//
+//	results, err := client.SearchByKeyword(ctx, "ORD-2024-00417",
+//	    core.WithUserIDForSearch("user_001"),
+//	)
+func (c *Client) SearchByKeyword(ctx context.Context, text string, opts ...SearchOption) ([]*Memory, error) {
+	ctx, err := c.begin(ctx, "SearchByKeyword")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if text == "" {
+		return nil, NewMemoryError("SearchByKeyword", ErrInvalidInput)
+	}
+
+	searchOpts := applySearchOptions(opts)
+	if err := c.checkUnscopedSearch(ctx, "SearchByKeyword", text, searchOpts); err != nil {
+		return nil, NewMemoryError("SearchByKeyword", err)
+	}
+	teamIDs, err := c.userTeams(ctx, searchOpts.UserID)
+	if err != nil {
+		return nil, NewMemoryError("SearchByKeyword", err)
+	}
+	staleness := c.staleness()
+
+	storageOpts := &storage.SearchOptions{
+		UserID:  searchOpts.UserID,
+		TeamIDs: teamIDs,
+		AgentID: searchOpts.AgentID,
+		Limit:   searchOpts.Limit,
+		Query:   text,
+		Filters: searchOpts.Filters,
+		TimeRange: toStorageTimeRange(
+			searchOpts.CreatedAfter, searchOpts.CreatedBefore,
+			searchOpts.UpdatedAfter, searchOpts.UpdatedBefore,
+		),
+		Tags:              searchOpts.Tags,
+		WithoutEmbeddings: !searchOpts.IncludeEmbeddings,
+		Fields:            searchFields(searchOpts.Fields, staleness != nil),
+		Now:               c.now(),
+	}
+
+	memories, err := c.storage.SearchByKeyword(ctx, text, storageOpts)
+	if err != nil {
+		return nil, NewMemoryError("SearchByKeyword", err)
+	}
+
+	results := fromStorageMemories(c.injectionGuard.screen(visible(memories, searchOpts)))
+	for _, memory := range results {
+		staleness.mark(memory)
+		projectMemory(memory, searchOpts.Fields)
+	}
+	return results, nil
+}
+
 // Get retrieves a memory by its ID with optional access control.
 //
 // Parameters:
@@ -411,6 +784,12 @@ func (c *Client) Search(ctx context.Context, query string, opts ...SearchOption)
 //	    core.WithUserIDForGet("user_001"),
 //	    core.WithAgentIDForGet("agent_001"))
 func (c *Client) Get(ctx context.Context, id int64, opts ...GetOption) (*Memory, error) {
+	ctx, err := c.begin(ctx, "Get")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -419,6 +798,7 @@ func (c *Client) Get(ctx context.Context, id int64, opts ...GetOption) (*Memory,
 	storageOpts := &storage.GetOptions{
 		UserID:  getOpts.UserID,
 		AgentID: getOpts.AgentID,
+		Now:     c.now(),
 	}
 
 	memory, err := c.storage.Get(ctx, id, storageOpts)
@@ -429,17 +809,73 @@ func (c *Client) Get(ctx context.Context, id int64, opts ...GetOption) (*Memory,
 	return fromStorageMemory(memory), nil
 }
 
+// GetMany retrieves multiple memories by ID in a single storage round trip.
+//
+// Results follow the order of ids. IDs that do not exist, or that are not accessible
+// under the given user/agent restrictions, are skipped; duplicate IDs are returned once.
+//
+// Example:
+//
+//	memories, err := client.GetMany(ctx, []int64{id1, id2, id3},
+//	    core.WithUserIDForGet("user_001"),
+//	)
+func (c *Client) GetMany(ctx context.Context, ids []int64, opts ...GetOption) ([]*Memory, error) {
+	ctx, err := c.begin(ctx, "GetMany")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	getOpts := applyGetOptions(opts)
+
+	storageOpts := &storage.GetOptions{
+		UserID:  getOpts.UserID,
+		AgentID: getOpts.AgentID,
+		Now:     c.now(),
+	}
+
+	memories, err := c.storage.GetMany(ctx, ids, storageOpts)
+	if err != nil {
+		return nil, NewMemoryError("GetMany", err)
+	}
+
+	byID := make(map[int64]*storage.Memory, len(memories))
+	for _, memory := range memories {
+		byID[memory.ID] = memory
+	}
+
+	result := make([]*Memory, 0, len(memories))
+	for _, id := range ids {
+		if memory, ok := byID[id]; ok {
+			result = append(result, fromStorageMemory(memory))
+			delete(byID, id)
+		}
+	}
+
+	return result, nil
+}
+
 // Update updates an existing memory's content with optional access control.
 //
 // The method:
 //  1. Generates a new embedding vector for the updated content
 //  2. Updates the memory in the store (with access control if specified)
 //
+// created_at is preserved; updated_at and the embedding are always refreshed.
+// Metadata is only replaced when WithMetadataForUpdate is given.
+//
+// Every update increments Memory.Version. WithExpectedVersion makes the update
+// fail with ErrVersionConflict if the memory changed since it was read; a
+// metadata-only update (empty content) always performs this check.
+//
 // Parameters:
 //   - ctx: Context for cancellation
 //   - id: Memory ID to update
-//   - content: New content for the memory
-//   - opts: Optional Update options for access control (UserID, AgentID)
+//   - content: New content for the memory (empty to keep the existing content)
+//   - opts: Optional Update options (UserID, AgentID, Metadata)
 //
 // Returns the updated Memory, or an error if update fails or access is denied.
 //
@@ -452,29 +888,117 @@ func (c *Client) Get(ctx context.Context, id int64, opts ...GetOption) (*Memory,
 //	memory, err := client.Update(ctx, memoryID, "new content",
 //	    core.WithUserIDForUpdate("user_001"))
 func (c *Client) Update(ctx context.Context, id int64, content string, opts ...UpdateOption) (*Memory, error) {
+	return c.update(ctx, "Update", id, "", content, applyUpdateOptions(opts))
+}
+
+// update updates the memory with ID id, or with string ID uid if set, for
+// operation op.
+func (c *Client) update(ctx context.Context, op string, id int64, uid, content string, updateOpts *UpdateOptions) (*Memory, error) {
+	ctx, err := c.begin(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	events := c.recordEvents(ctx, op)
+	defer events.publish()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	updateOpts := applyUpdateOptions(opts)
+	if uid != "" {
+		if id, err = c.resolveUID(ctx, uid, updateOpts.UserID, updateOpts.AgentID); err != nil {
+			return nil, NewMemoryError(op, err)
+		}
+	}
 
-	// Generate new embedding
-	embedding, err := c.embedder.Embed(ctx, content)
-	if err != nil {
-		return nil, NewMemoryError("Update", err)
+	if err := c.checkContentSize(content); err != nil {
+		return nil, NewMemoryError(op, err)
 	}
 
 	storageOpts := &storage.UpdateOptions{
-		UserID:  updateOpts.UserID,
-		AgentID: updateOpts.AgentID,
+		UserID:          updateOpts.UserID,
+		AgentID:         updateOpts.AgentID,
+		Metadata:        updateOpts.Metadata,
+		ExpectedVersion: updateOpts.ExpectedVersion,
+		Now:             c.now(),
+	}
+
+	// The previous state is needed for metadata-only updates, event diffs
+	// and the embedder route of the memory
+	var existing *storage.Memory
+	if content == "" || events.enabled() || len(c.embedderRoutes) > 0 {
+		existing, err = c.storage.Get(ctx, id, &storage.GetOptions{
+			UserID:  updateOpts.UserID,
+			AgentID: updateOpts.AgentID,
+			Now:     c.now(),
+		})
+		if err != nil {
+			return nil, NewMemoryError(op, err)
+		}
+	}
+
+	// The memory stays in the vector space of the embedder that embedded it
+	var route *embedderRoute
+	if existing != nil {
+		route = c.routeOf(existing.Metadata)
+	}
+	if route != nil && storageOpts.Metadata != nil {
+		storageOpts.Metadata = copyMetadata(storageOpts.Metadata)
+		setRoute(storageOpts.Metadata, route)
+	}
+
+	var embedding []float64
+	var chunked *chunkedContent
+	if content == "" {
+		// Metadata-only update: keep the existing content and embedding
+		content = existing.Content
+		embedding = existing.Embedding
+
+		// Don't write back content that another writer replaced meanwhile
+		if storageOpts.ExpectedVersion == 0 {
+			storageOpts.ExpectedVersion = existing.Version
+		}
+	} else {
+		// Generate new embedding (of each chunk if the content is long)
+		var err error
+		embedding, chunked, err = c.embedContent(ctx, c.embedderOf(route), content)
+		if err != nil {
+			return nil, NewMemoryError(op, err)
+		}
 	}
 
 	// Update storage
 	memory, err := c.storage.Update(ctx, id, content, embedding, storageOpts)
 	if err != nil {
-		return nil, NewMemoryError("Update", err)
+		return nil, NewMemoryError(op, err)
 	}
 
-	return fromStorageMemory(memory), nil
+	if existing != nil && content == existing.Content {
+		// Keep the metadata of the chunks in sync for filtered searches
+		if updateOpts.Metadata != nil {
+			if err := c.updateChunkMetadata(ctx, memory); err != nil {
+				return nil, NewMemoryError(op, err)
+			}
+		}
+	} else {
+		// Replace the chunks of the previous content
+		if err := c.deleteChunks(ctx, id, memory.UserID); err != nil {
+			return nil, NewMemoryError(op, err)
+		}
+		if chunked != nil {
+			if err := c.insertChunks(ctx, fromStorageMemory(memory), chunked); err != nil {
+				return nil, NewMemoryError(op, err)
+			}
+		}
+	}
+
+	updated := fromStorageMemory(memory)
+	if existing != nil {
+		events.add(&Event{Type: EventUpdated, Memory: updated, Diff: newEventDiff(fromStorageMemory(existing), updated)})
+	}
+
+	return updated, nil
 }
 
 // Delete deletes a memory by its ID with optional access control.
@@ -494,19 +1018,54 @@ func (c *Client) Update(ctx context.Context, id int64, content string, opts ...U
 //	// Delete with user access control (prevents cross-tenant deletions)
 //	err := client.Delete(ctx, memoryID, core.WithUserIDForDelete("user_001"))
 func (c *Client) Delete(ctx context.Context, id int64, opts ...DeleteOption) error {
+	return c.delete(ctx, "Delete", id, "", applyDeleteOptions(opts))
+}
+
+// delete deletes the memory with ID id, or with string ID uid if set, for
+// operation op.
+func (c *Client) delete(ctx context.Context, op string, id int64, uid string, deleteOpts *DeleteOptions) error {
+	ctx, err := c.begin(ctx, op)
+	if err != nil {
+		return err
+	}
+	defer c.end()
+
+	events := c.recordEvents(ctx, op)
+	defer events.publish()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	deleteOpts := applyDeleteOptions(opts)
+	if uid != "" {
+		if id, err = c.resolveUID(ctx, uid, deleteOpts.UserID, deleteOpts.AgentID); err != nil {
+			return NewMemoryError(op, err)
+		}
+	}
 
 	storageOpts := &storage.DeleteOptions{
 		UserID:  deleteOpts.UserID,
 		AgentID: deleteOpts.AgentID,
 	}
 
+	// Keep the deleted memory for the event (expired memories cannot be read)
+	event := &Event{Type: EventDeleted, MemoryID: id, UserID: deleteOpts.UserID, AgentID: deleteOpts.AgentID}
+	if events.enabled() {
+		if existing, err := c.storage.Get(ctx, id, &storage.GetOptions{
+			UserID:  deleteOpts.UserID,
+			AgentID: deleteOpts.AgentID,
+			Now:     c.now(),
+		}); err == nil {
+			event.Memory = fromStorageMemory(existing)
+		}
+	}
+
 	if err := c.storage.Delete(ctx, id, storageOpts); err != nil {
-		return NewMemoryError("Delete", err)
+		return NewMemoryError(op, err)
+	}
+	if err := c.deleteChunks(ctx, id, deleteOpts.UserID); err != nil {
+		return NewMemoryError(op, err)
 	}
+	events.add(event)
 
 	return nil
 }
@@ -514,6 +1073,9 @@ func (c *Client) Delete(ctx context.Context, id int64, opts ...DeleteOption) err
 // GetAll retrieves all memories with optional filtering.
 //
 // Results can be filtered by UserID, AgentID, and paginated using Limit and Offset.
+// Without WithUserIDForGetAll, the memories of every user are listed, which
+// must be allowed with WithAllowCrossUserForGetAll(true) and is recorded in
+// the audit log, as unscoped searches are (see Search).
 //
 // Parameters:
 //   - ctx: Context for cancellation
@@ -529,16 +1091,39 @@ func (c *Client) Delete(ctx context.Context, id int64, opts ...DeleteOption) err
 //	    core.WithOffset(0),
 //	)
 func (c *Client) GetAll(ctx context.Context, opts ...GetAllOption) ([]*Memory, error) {
+	ctx, err := c.begin(ctx, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	getAllOpts := applyGetAllOptions(opts)
+	if err := c.checkUnscopedGetAll(ctx, "GetAll", getAllOpts); err != nil {
+		return nil, NewMemoryError("GetAll", err)
+	}
+	teamIDs, err := c.userTeams(ctx, getAllOpts.UserID)
+	if err != nil {
+		return nil, NewMemoryError("GetAll", err)
+	}
 
 	storageOpts := &storage.GetAllOptions{
 		UserID:  getAllOpts.UserID,
+		TeamIDs: teamIDs,
 		AgentID: getAllOpts.AgentID,
 		Limit:   getAllOpts.Limit,
 		Offset:  getAllOpts.Offset,
+		TimeRange: toStorageTimeRange(
+			getAllOpts.CreatedAfter, getAllOpts.CreatedBefore,
+			getAllOpts.UpdatedAfter, getAllOpts.UpdatedBefore,
+		),
+		Tags:              getAllOpts.Tags,
+		Filters:           getAllOpts.Filters,
+		WithoutEmbeddings: getAllOpts.WithoutEmbeddings,
+		Fields:            toStorageFields(getAllOpts.Fields),
+		Now:               c.now(),
 	}
 
 	memories, err := c.storage.GetAll(ctx, storageOpts)
@@ -549,10 +1134,38 @@ func (c *Client) GetAll(ctx context.Context, opts ...GetAllOption) ([]*Memory, e
 	return fromStorageMemories(memories), nil
 }
 
+// ListTags returns the distinct tags in use, sorted alphabetically.
+//
+// If userID is empty, tags across all users are returned.
+//
+// Example:
+//
+//	tags, err := client.ListTags(ctx, "user_001")
+func (c *Client) ListTags(ctx context.Context, userID string) ([]string, error) {
+	ctx, err := c.begin(ctx, "ListTags")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	tags, err := c.storage.ListTags(ctx, userID)
+	if err != nil {
+		return nil, NewMemoryError("ListTags", err)
+	}
+
+	return tags, nil
+}
+
 // DeleteAll deletes all memories matching the given filters.
 //
 // If no filters are provided, deletes ALL memories (use with caution).
 //
+// With the audit log enabled (see AuditConfig), the deletion is recorded in
+// it first, and is not run if it cannot be recorded.
+//
 // Parameters:
 //   - ctx: Context for cancellation
 //   - opts: Optional parameters (UserID, AgentID)
@@ -566,11 +1179,28 @@ func (c *Client) GetAll(ctx context.Context, opts ...GetAllOption) ([]*Memory, e
 //	    core.WithAgentIDForDeleteAll("agent_001"),
 //	)
 func (c *Client) DeleteAll(ctx context.Context, opts ...DeleteAllOption) error {
+	ctx, err := c.begin(ctx, "DeleteAll")
+	if err != nil {
+		return err
+	}
+	defer c.end()
+
+	events := c.recordEvents(ctx, "DeleteAll")
+	defer events.publish()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	deleteAllOpts := applyDeleteAllOptions(opts)
 
+	err = c.audit(ctx, "DeleteAll", deleteAllOpts.UserID, map[string]interface{}{
+		"user_id":  deleteAllOpts.UserID,
+		"agent_id": deleteAllOpts.AgentID,
+	})
+	if err != nil {
+		return NewMemoryError("DeleteAll", err)
+	}
+
 	storageOpts := &storage.DeleteAllOptions{
 		UserID:  deleteAllOpts.UserID,
 		AgentID: deleteAllOpts.AgentID,
@@ -579,6 +1209,7 @@ func (c *Client) DeleteAll(ctx context.Context, opts ...DeleteAllOption) error {
 	if err := c.storage.DeleteAll(ctx, storageOpts); err != nil {
 		return NewMemoryError("DeleteAll", err)
 	}
+	events.add(&Event{Type: EventDeleted, UserID: deleteAllOpts.UserID, AgentID: deleteAllOpts.AgentID})
 
 	return nil
 }
@@ -586,9 +1217,14 @@ func (c *Client) DeleteAll(ctx context.Context, opts ...DeleteAllOption) error {
 // Close closes the client and releases all resources.
 //
 // This method:
+//   - Rejects new operations with ErrClientClosed
 //   - Closes the vector store connection
 //   - Closes the LLM provider
-//   - Closes the embedder provider
+//   - Closes the embedder providers
+//   - Delivers the pending webhooks (waiting up to 5 seconds)
+//
+// Close does not wait for in-flight operations, which may then fail; use
+// Shutdown to drain them first. Calling Close more than once is safe.
 //
 // Returns the first error encountered during cleanup, or nil if all resources
 // were closed successfully.
@@ -597,8 +1233,29 @@ func (c *Client) DeleteAll(ctx context.Context, opts ...DeleteAllOption) error {
 //
 //	defer client.Close()
 func (c *Client) Close() error {
+	c.stopAccepting()
+	return c.closeProviders()
+}
+
+// closeProviders closes the storage and providers, once.
+func (c *Client) closeProviders() error {
+	c.lifecycle.closeOnce.Do(func() {
+		c.lifecycle.closeErr = c.closeResources()
+	})
+	return c.lifecycle.closeErr
+}
+
+// closeResources closes the storage and providers.
+func (c *Client) closeResources() error {
 	var errs []error
 
+	// Deliver the pending webhooks first: they may still be in flight
+	c.closeWebhooks()
+
+	if err := c.closeReplication(); err != nil {
+		errs = append(errs, err)
+	}
+
 	if c.storage != nil {
 		if err := c.storage.Close(); err != nil {
 			errs = append(errs, err)
@@ -616,6 +1273,9 @@ func (c *Client) Close() error {
 			errs = append(errs, err)
 		}
 	}
+	if err := closeEmbedderRoutes(c.embedderRoutes); err != nil {
+		errs = append(errs, err)
+	}
 
 	if len(errs) > 0 {
 		return errs[0] // Return the first error
@@ -629,7 +1289,10 @@ func (c *Client) Close() error {
 // This method will:
 //   - Delete all memories from the vector store
 //   - Drop and recreate the collection/table
-//   - Preserve the existing configuration
+//   - Preserve the existing configuration, the change log and the audit log
+//
+// With the audit log enabled (see AuditConfig), the reset is recorded in it
+// first, and is not run if it cannot be recorded.
 //
 // WARNING: This operation will delete ALL memories and cannot be undone.
 // Use with extreme caution.
@@ -646,51 +1309,184 @@ func (c *Client) Close() error {
 //	    log.Fatal(err)
 //	}
 func (c *Client) Reset(ctx context.Context) error {
+	ctx, err := c.begin(ctx, "Reset")
+	if err != nil {
+		return err
+	}
+	defer c.end()
+
+	events := c.recordEvents(ctx, "Reset")
+	defer events.publish()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if err := c.audit(ctx, "Reset", "", nil); err != nil {
+		return NewMemoryError("Reset", err)
+	}
+
 	// Reset vector store
 	if err := c.storage.Reset(ctx); err != nil {
 		return NewMemoryError("Reset", err)
 	}
+	events.add(&Event{Type: EventDeleted})
 
 	return nil
 }
 
 // initStorage initializes the storage backend.
-func initStorage(cfg VectorStoreConfig) (storage.VectorStore, error) {
-	switch cfg.Provider {
-	case "oceanbase":
+//
+// The provider settings are resolved with resolveStoreConfig, so invalid
+// values are reported as a ValidationError.
+func initStorage(cfg VectorStoreConfig, embedderDims int) (storage.VectorStore, error) {
+	typed, errs := resolveStoreConfig(cfg, embedderDims)
+	if len(errs) > 0 {
+		return nil, NewMemoryError("initStorage", &ValidationError{Fields: errs})
+	}
+
+	var policy routing.Policy
+	if cfg.Routing != nil {
+		policy = cfg.Routing.Policy
+	}
+	store, err := newStore(typed, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Serve the other collections of the database (see CreateCollection)
+	defaultName := collectionName(typed)
+	if defaultName == "" {
+		return store, nil
+	}
+	collectionStore, err := collections.NewClient(&collections.Config{
+		Default:     store,
+		DefaultName: defaultName,
+		Open: func(name string) (storage.VectorStore, error) {
+			return newStore(withCollectionName(typed, name), nil)
+		},
+	})
+	if err != nil {
+		_ = store.Close()
+		return nil, NewMemoryError("initStorage", err)
+	}
+	return collectionStore, nil
+}
+
+// ContextWithPrimaryRead returns a context whose reads go to the primary
+// database instead of a read replica (see PostgresConfig.ReadDSNs and
+// OceanBaseConfig.ReadDSNs), e.g. to read a memory that was just written
+// before replicas catch up.
+//
+// Example:
+//
+//	memory, err := client.Add(ctx, "User prefers tea", core.WithUserID("user_001"))
+//	...
+//	results, err := client.GetAll(core.ContextWithPrimaryRead(ctx), core.WithUserIDForGetAll("user_001"))
+func ContextWithPrimaryRead(ctx context.Context) context.Context {
+	return storage.ContextWithPrimary(ctx)
+}
+
+// newStore creates the vector store of resolved settings (see
+// resolveStoreConfig). policy is the Policy of a routing store.
+func newStore(typed interface{}, policy routing.Policy) (storage.VectorStore, error) {
+	switch c := typed.(type) {
+	case *OceanBaseConfig:
+		var vectorIndex *storage.HNSWParams
+		if !c.DisableVectorIndex {
+			vectorIndex = &storage.HNSWParams{
+				M:              c.HNSWM,
+				EfConstruction: c.HNSWEfConstruction,
+				EfSearch:       c.HNSWEfSearch,
+			}
+		}
+		tlsConfig, err := oceanbase.LoadTLSConfig(c.SSLMode, c.SSLCA, c.SSLCert, c.SSLKey)
+		if err != nil {
+			return nil, err
+		}
 		return oceanbase.NewClient(&oceanbase.Config{
-			Host:               cfg.Config["host"].(string),
-			Port:               cfg.Config["port"].(int),
-			User:               cfg.Config["user"].(string),
-			Password:           cfg.Config["password"].(string),
-			DBName:             cfg.Config["db_name"].(string),
-			CollectionName:     cfg.Config["collection_name"].(string),
-			EmbeddingModelDims: cfg.Config["embedding_model_dims"].(int),
+			Host:               c.Host,
+			Port:               c.Port,
+			User:               c.User,
+			Password:           c.Password,
+			DBName:             c.DBName,
+			CollectionName:     c.CollectionName,
+			EmbeddingModelDims: c.EmbeddingModelDims,
+			DSN:                c.DSN,
+			ReadDSNs:           c.ReadDSNs,
+			VectorIndex:        vectorIndex,
+			FullTextParser:     c.FullTextParser,
+			TLS:                tlsConfig,
+			Charset:            c.Charset,
+			ConnectTimeout:     time.Duration(c.ConnectTimeoutSeconds * float64(time.Second)),
+			ReadTimeout:        time.Duration(c.ReadTimeoutSeconds * float64(time.Second)),
+			WriteTimeout:       time.Duration(c.WriteTimeoutSeconds * float64(time.Second)),
 		})
-	case "sqlite":
+	case *SQLiteConfig:
+		busyRetries := c.BusyRetries
+		if busyRetries < 0 {
+			busyRetries = 0
+		}
 		return sqliteStore.NewClient(&sqliteStore.Config{
-			DBPath:             cfg.Config["db_path"].(string),
-			CollectionName:     cfg.Config["collection_name"].(string),
-			EmbeddingModelDims: cfg.Config["embedding_model_dims"].(int),
+			DBPath:             c.DBPath,
+			CollectionName:     c.CollectionName,
+			EmbeddingModelDims: c.EmbeddingModelDims,
+			BusyTimeout:        time.Duration(c.BusyTimeoutSeconds * float64(time.Second)),
+			BusyRetries:        busyRetries,
+			Synchronous:        c.Synchronous,
+			CheckpointInterval: time.Duration(c.CheckpointIntervalSeconds * float64(time.Second)),
+			Locking:            c.Locking,
+			SearchWorkers:      c.SearchWorkers,
+			Clusters:           c.Clusters,
+			ProbeClusters:      c.ProbeClusters,
+			VectorCache:        c.VectorCache,
 		})
-	case "postgres":
-		sslMode := "disable"
-		if s, ok := cfg.Config["ssl_mode"].(string); ok {
-			sslMode = s
-		}
+	case *PostgresConfig:
 		return postgresStore.NewClient(&postgresStore.Config{
-			Host:               cfg.Config["host"].(string),
-			Port:               cfg.Config["port"].(int),
-			User:               cfg.Config["user"].(string),
-			Password:           cfg.Config["password"].(string),
-			DBName:             cfg.Config["db_name"].(string),
-			CollectionName:     cfg.Config["collection_name"].(string),
-			EmbeddingModelDims: cfg.Config["embedding_model_dims"].(int),
-			SSLMode:            sslMode,
+			Host:               c.Host,
+			Port:               c.Port,
+			User:               c.User,
+			Password:           c.Password,
+			DBName:             c.DBName,
+			CollectionName:     c.CollectionName,
+			EmbeddingModelDims: c.EmbeddingModelDims,
+			SSLMode:            c.SSLMode,
+			DSN:                c.DSN,
+			ReadDSNs:           c.ReadDSNs,
+			MaxReplicaLag:      time.Duration(c.MaxReplicaLagSeconds * float64(time.Second)),
+			Quantization:       c.Quantization,
+			RerankFactor:       c.RerankFactor,
+		})
+	case *resolvedRoutingConfig:
+		stores := make(map[string]storage.VectorStore, len(c.Stores))
+		closeStores := func() {
+			for _, store := range stores {
+				_ = store.Close()
+			}
+		}
+		for name, storeConfig := range c.Stores {
+			store, err := newStore(storeConfig, nil)
+			if err != nil {
+				closeStores()
+				return nil, NewMemoryError("initStorage", fmt.Errorf("store %s: %w", name, err))
+			}
+			stores[name] = store
+		}
+
+		policies := []routing.Policy{routing.StaticPolicy(c.Users)}
+		if policy != nil {
+			policies = append(policies, policy)
+		}
+		store, err := routing.NewClient(&routing.Config{
+			Stores:         stores,
+			Default:        c.Default,
+			Policy:         routing.Chain(policies...),
+			ChangeLogStore: c.ChangeLogStore,
 		})
+		if err != nil {
+			closeStores()
+			return nil, NewMemoryError("initStorage", err)
+		}
+		return store, nil
 	default:
 		return nil, NewMemoryError("initStorage", ErrInvalidConfig)
 	}
@@ -729,6 +1525,10 @@ func initLLM(cfg LLMConfig) (llm.Provider, error) {
 			Model:   cfg.Model,
 			BaseURL: cfg.BaseURL,
 		})
+	case "mock":
+		return mockLLM.NewClient(&mockLLM.Config{
+			Responses: stringsParameter(cfg.Parameters, "responses"),
+		})
 	default:
 		return nil, NewMemoryError("initLLM", ErrInvalidConfig)
 	}
@@ -751,7 +1551,32 @@ func initEmbedder(cfg EmbedderConfig) (embedder.Provider, error) {
 			BaseURL:    cfg.BaseURL,
 			Dimensions: cfg.Dimensions,
 		})
+	case "mock":
+		return mockEmbedder.NewClient(&mockEmbedder.Config{
+			Dimensions: cfg.Dimensions,
+		})
 	default:
 		return nil, NewMemoryError("initEmbedder", ErrInvalidConfig)
 	}
 }
+
+// stringsParameter returns the string list parameter key, accepting both
+// []string and the []interface{} produced by decoding config files.
+func stringsParameter(parameters map[string]interface{}, key string) []string {
+	switch values := parameters[key].(type) {
+	case []string:
+		return values
+	case []interface{}:
+		result := make([]string, 0, len(values))
+		for _, value := range values {
+			if s, ok := value.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	case string:
+		return []string{values}
+	default:
+		return nil
+	}
+}
@@ -1,6 +1,8 @@
 // Package core provides the main PowerMem client and memory management functionality.
 package core
 
+import "time"
+
 // AddOption is a function type for configuring Add operations.
 //
 // Options are applied using the functional options pattern, allowing
@@ -28,15 +30,54 @@ type AddOptions struct {
 	// See MemoryScope constants for available scopes.
 	Scope MemoryScope
 
+	// TeamID shares the memory with the members of this team.
+	TeamID string
+
 	// MemoryType specifies the type of memory (e.g., "conversation", "fact", "preference").
 	MemoryType string
 
+	// Language is the language of the memory (e.g., "en", "zh"), stored in
+	// metadata["language"] instead of the detected one.
+	Language string
+
 	// Prompt is an optional prompt used for memory processing.
 	Prompt string
 
 	// Infer enables intelligent deduplication.
 	// When true, the system checks for duplicate memories and merges them.
 	Infer bool
+
+	// Tags are free-form labels attached to the memory.
+	Tags []string
+
+	// ExpiresAt is the absolute time after which the memory expires.
+	// Takes precedence over TTL when both are set.
+	ExpiresAt time.Time
+
+	// TTL is how long the memory lives after it is added.
+	// Zero means the memory never expires.
+	TTL time.Duration
+
+	// DryRun makes IntelligentAdd return the planned operations without executing them.
+	DryRun bool
+
+	// Batch configures how BatchAdd processes the batch. Ignored by other operations.
+	Batch []BatchOption
+
+	// Sources tells where the memory came from (see Memory.Sources).
+	Sources []Source
+
+	// Entities are the entities the memory mentions (see Memory.Entities).
+	Entities []string
+
+	// SkipExactDuplicate makes Add return the memory of the user with the
+	// same content, if there is one, as Config.SkipExactDuplicates does for
+	// every Add.
+	SkipExactDuplicate bool
+
+	// UID is the string ID of the new memory, a UUID, instead of a generated
+	// one (see WithUID).
+	UID string
 }
 
 // WithUserID sets the user ID for Add operations.
@@ -145,6 +186,75 @@ func WithMetadata(metadata map[string]interface{}) AddOption {
 	}
 }
 
+// WithSource records where the memory came from, in metadata["sources"]
+// (see Memory.Sources). Can be given several times; IntelligentAdd adds the
+// messages each fact was extracted from.
+//
+// Example:
+//
+//	client.Add(ctx, content,
+//	    core.WithUserID("user_001"),
+//	    core.WithSource(core.Source{URL: "https://example.com/pricing"}),
+//	)
+func WithSource(sources ...Source) AddOption {
+	return func(opts *AddOptions) {
+		opts.Sources = append(opts.Sources, sources...)
+	}
+}
+
+// WithSkipExactDuplicate sets whether Add returns the existing memory of the
+// user (and agent, if set) with the same content instead of adding it again,
+// as Config.SkipExactDuplicates does for every Add. This makes retried Adds
+// idempotent.
+//
+// Example:
+//
+//	// Safe to retry after a timeout
+//	memory, err := client.Add(ctx, "User prefers dark mode",
+//	    core.WithUserID("user_001"),
+//	    core.WithSkipExactDuplicate(true),
+//	)
+func WithSkipExactDuplicate(skip bool) AddOption {
+	return func(opts *AddOptions) {
+		opts.SkipExactDuplicate = skip
+	}
+}
+
+// WithUID sets the string ID (Memory.UID) of the memory created by Add to
+// uid, a UUID chosen by the application, e.g. the key of the record the
+// memory was derived from. It is accepted with any Config.IDType. Add fails
+// with ErrInvalidInput if uid is not a UUID or is combined with WithInfer,
+// and with ErrDuplicateUID if a memory already has it. IntelligentAdd, which
+// may create several memories, rejects it.
+//
+// Example:
+//
+//	memory, err := client.Add(ctx, "User prefers dark mode",
+//	    core.WithUserID("user_001"),
+//	    core.WithUID("0190b5a2-7c1e-7d3a-9f4e-2b8c6d1e0a57"),
+//	)
+func WithUID(uid string) AddOption {
+	return func(opts *AddOptions) {
+		opts.UID = uid
+	}
+}
+
+// WithEntities annotates the memory with the entities it mentions, in
+// metadata["entities"] (see Memory.Entities and SearchByEntity). Can be given
+// several times; IntelligentAdd adds the entities of each extracted fact.
+//
+// Example:
+//
+//	client.Add(ctx, "Went hiking with Alice in the Alps",
+//	    core.WithUserID("user_001"),
+//	    core.WithEntities("Alice", "Alps"),
+//	)
+func WithEntities(entities ...string) AddOption {
+	return func(opts *AddOptions) {
+		opts.Entities = append(opts.Entities, entities...)
+	}
+}
+
 // WithRunID sets the run ID for Add operations.
 //
 // RunID identifies a specific run or session, useful for grouping related memories.
@@ -189,6 +299,19 @@ func WithMemoryType(memoryType string) AddOption {
 	}
 }
 
+// WithLanguageForAdd sets the language of the memory, an ISO 639-1 code such
+// as "en" or "zh", stored in metadata["language"]. It overrides language
+// detection (see LanguageConfig).
+//
+// Example:
+//
+//	memory, _ := client.Add(ctx, "我喜欢靠窗的座位", core.WithLanguageForAdd("zh"))
+func WithLanguageForAdd(language string) AddOption {
+	return func(opts *AddOptions) {
+		opts.Language = language
+	}
+}
+
 // WithPrompt sets an optional prompt for Add operations.
 //
 // Prompt can be used to guide memory processing or extraction.
@@ -216,12 +339,83 @@ func WithInfer(infer bool) AddOption {
 	}
 }
 
+// WithTags attaches tags to the memory being added.
+//
+// Tags are trimmed and de-duplicated. Unlike metadata, tags are stored in a
+// dedicated column and can be filtered efficiently with WithTagsForSearch and
+// WithTagsForGetAll.
+//
+// Example:
+//
+//	memory, _ := client.Add(ctx, "Order ORD-1 shipped",
+//	    core.WithUserID("user_001"),
+//	    core.WithTags("orders", "shipping"),
+//	)
+func WithTags(tags ...string) AddOption {
+	return func(opts *AddOptions) {
+		opts.Tags = tags
+	}
+}
+
+// WithExpiresAt sets an absolute expiration time for the memory.
+//
+// Expired memories are excluded from Get, Search and GetAll, and are permanently
+// removed by PurgeExpired.
+//
+// Example:
+//
+//	memory, _ := client.Add(ctx, "Temporary access code 4821",
+//	    core.WithExpiresAt(time.Now().Add(24*time.Hour)),
+//	)
+func WithExpiresAt(t time.Time) AddOption {
+	return func(opts *AddOptions) {
+		opts.ExpiresAt = t
+	}
+}
+
+// WithTTL sets how long the memory lives after it is added.
+//
+// Example:
+//
+//	// Session-scoped memory
+//	memory, _ := client.Add(ctx, "User is on the checkout page",
+//	    core.WithTTL(30*time.Minute),
+//	)
+func WithTTL(ttl time.Duration) AddOption {
+	return func(opts *AddOptions) {
+		opts.TTL = ttl
+	}
+}
+
+// WithDryRun makes IntelligentAdd plan operations without executing them.
+//
+// Facts are extracted and the LLM decides ADD/UPDATE/DELETE operations as usual,
+// but no memory is inserted, updated or deleted. Planned ADD results have ID 0.
+// This lets applications ask for user confirmation before memory is modified.
+// Add, which returns a stored memory, rejects it with ErrInvalidInput.
+//
+// Example:
+//
+//	preview, _ := client.IntelligentAdd(ctx, messages,
+//	    core.WithUserID("user_001"),
+//	    core.WithDryRun(true),
+//	)
+//	for _, op := range preview.Results {
+//	    fmt.Println(op.Event, op.Memory)
+//	}
+func WithDryRun(dryRun bool) AddOption {
+	return func(opts *AddOptions) {
+		opts.DryRun = dryRun
+	}
+}
+
 // WithScope sets the memory scope for Add operations.
 //
 // Scope determines visibility:
 //   - ScopePrivate: Only visible to the creating agent
 //   - ScopeAgentGroup: Visible to all agents in the group
 //   - ScopeGlobal: Visible to all agents
+//   - ScopeTeam: Visible to all members of the team (set by WithTeamID)
 //
 // Example:
 //
@@ -232,6 +426,25 @@ func WithScope(scope MemoryScope) AddOption {
 	}
 }
 
+// WithTeamID shares the memory with the members of a team (see
+// Client.AddTeamMember) and sets its scope to ScopeTeam.
+//
+// The memory still belongs to the user of WithUserID: the other members
+// find it with Search, SearchByKeyword and GetAll, but only its user can
+// update or delete it.
+//
+// Example:
+//
+//	memory, _ := client.Add(ctx, "Deploys are frozen on Fridays",
+//	    core.WithUserID("user_001"),
+//	    core.WithTeamID("team_platform"))
+func WithTeamID(teamID string) AddOption {
+	return func(opts *AddOptions) {
+		opts.TeamID = teamID
+		opts.Scope = ScopeTeam
+	}
+}
+
 // SearchOption is a function type for configuring Search operations.
 type SearchOption func(*SearchOptions)
 
@@ -257,6 +470,62 @@ type SearchOptions struct {
 
 	// IncludeArchived indicates whether to include archived memories.
 	IncludeArchived bool
+
+	// IncludeFlagged indicates whether to include memories flagged as
+	// incorrect by Feedback.
+	IncludeFlagged bool
+
+	// IncludeWorking indicates whether to include the items of working
+	// memories (see Client.WorkingMemory).
+	IncludeWorking bool
+
+	// CreatedAfter restricts results to memories created at or after this time.
+	CreatedAfter time.Time
+
+	// CreatedBefore restricts results to memories created before this time.
+	CreatedBefore time.Time
+
+	// UpdatedAfter restricts results to memories updated at or after this time.
+	UpdatedAfter time.Time
+
+	// UpdatedBefore restricts results to memories updated before this time.
+	UpdatedBefore time.Time
+
+	// Tags restricts results to memories carrying all of these tags.
+	Tags []string
+
+	// RetrievalMode selects how the query is embedded.
+	// Default: RetrievalModeVector
+	RetrievalMode RetrievalMode
+
+	// EfSearch is the search depth of the HNSW index used by the search.
+	// Default: 0, the default of the vector store
+	EfSearch int
+
+	// MaxBatchBytes caps the estimated size of the batches of SearchStream:
+	// a batch is sent before it has batchSize memories once its memories
+	// reach this size. Default: 0 (no cap)
+	MaxBatchBytes int64
+
+	// IncludeEmbeddings indicates whether to return the embeddings of the
+	// results. Default: false, the embedding column is not even read
+	IncludeEmbeddings bool
+
+	// Fields restricts the fields of the results to these.
+	// Default: all fields
+	Fields []MemoryField
+
+	// Language restricts the results to the memories of this language,
+	// stored in metadata["language"].
+	Language string
+
+	// AllowCrossUser allows searches without a user ID, which search the
+	// memories of every user. Default: false
+	AllowCrossUser bool
+
+	// Explain indicates whether to explain each result in
+	// Memory.Explanation. Default: false
+	Explain bool
 }
 
 // WithLimit sets the maximum number of results for Search operations.
@@ -299,6 +568,34 @@ func WithFilters(filters map[string]interface{}) SearchOption {
 	}
 }
 
+// WithLanguage restricts Search results to the memories of a language, an
+// ISO 639-1 code such as "en" or "zh" (see LanguageConfig). It is a filter
+// on metadata["language"], combined with WithFilters, and disables query
+// translation.
+//
+// Example:
+//
+//	results, _ := client.Search(ctx, "seat preference", core.WithLanguage("zh"))
+func WithLanguage(language string) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.Language = language
+	}
+}
+
+// WithAllowCrossUser allows a search to read the memories of every user. It
+// is required by SearchAcrossUsers, and by the other searches without
+// WithUserIDForSearch, which fail with ErrInvalidInput otherwise. Allowed
+// searches are recorded in the audit log (see Search).
+//
+// Example:
+//
+//	groups, _ := client.SearchAcrossUsers(ctx, "query", core.WithAllowCrossUser(true))
+func WithAllowCrossUser(allow bool) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.AllowCrossUser = allow
+	}
+}
+
 // WithMinScore sets the minimum similarity score for Search results.
 //
 // Only results with similarity scores >= minScore are returned.
@@ -324,6 +621,117 @@ func WithIncludeArchived(include bool) SearchOption {
 	}
 }
 
+// WithIncludeFlagged sets whether to include memories flagged as incorrect
+// (see Client.Feedback) in Search results.
+//
+// Example:
+//
+//	results, _ := client.Search(ctx, "query", core.WithIncludeFlagged(true))
+func WithIncludeFlagged(include bool) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.IncludeFlagged = include
+	}
+}
+
+// WithIncludeWorking sets whether to include the items of working memories
+// (see Client.WorkingMemory) in Search results.
+func WithIncludeWorking(include bool) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.IncludeWorking = include
+	}
+}
+
+// WithIncludeEmbeddings sets whether to return the embeddings of Search
+// results. They are left out by default, as they are large and rarely needed.
+//
+// Example:
+//
+//	results, err := client.Search(ctx, "Python programming",
+//	    core.WithUserIDForSearch("user_001"),
+//	    core.WithIncludeEmbeddings(true),
+//	)
+func WithIncludeEmbeddings(include bool) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.IncludeEmbeddings = include
+	}
+}
+
+// WithFields restricts the fields of Search results to fields, leaving the
+// others zero. Columns that neither the results nor the search itself need
+// are not read from the store.
+//
+// Example:
+//
+//	results, err := client.Search(ctx, "Python programming",
+//	    core.WithUserIDForSearch("user_001"),
+//	    core.WithFields(core.MemoryFieldContent, core.MemoryFieldMetadata),
+//	)
+func WithFields(fields ...MemoryField) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.Fields = fields
+	}
+}
+
+// WithCreatedAfter restricts Search results to memories created at or after t.
+//
+// Example:
+//
+//	// What did the user say this week?
+//	results, _ := client.Search(ctx, "query",
+//	    core.WithCreatedAfter(time.Now().AddDate(0, 0, -7)),
+//	)
+func WithCreatedAfter(t time.Time) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.CreatedAfter = t
+	}
+}
+
+// WithCreatedBefore restricts Search results to memories created before t.
+//
+// Example:
+//
+//	results, _ := client.Search(ctx, "query", core.WithCreatedBefore(cutoff))
+func WithCreatedBefore(t time.Time) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.CreatedBefore = t
+	}
+}
+
+// WithUpdatedAfter restricts Search results to memories updated at or after t.
+//
+// Example:
+//
+//	results, _ := client.Search(ctx, "query", core.WithUpdatedAfter(since))
+func WithUpdatedAfter(t time.Time) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.UpdatedAfter = t
+	}
+}
+
+// WithUpdatedBefore restricts Search results to memories updated before t.
+//
+// Example:
+//
+//	results, _ := client.Search(ctx, "query", core.WithUpdatedBefore(cutoff))
+func WithUpdatedBefore(t time.Time) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.UpdatedBefore = t
+	}
+}
+
+// WithTagsForSearch restricts Search results to memories carrying all of the given tags.
+//
+// Example:
+//
+//	results, _ := client.Search(ctx, "shipping status",
+//	    core.WithTagsForSearch("orders"),
+//	)
+func WithTagsForSearch(tags ...string) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.Tags = tags
+	}
+}
+
 // GetAllOption is a function type for configuring GetAll operations.
 type GetAllOption func(*GetAllOptions)
 
@@ -342,6 +750,87 @@ type GetAllOptions struct {
 	// Offset sets the number of results to skip (for pagination).
 	// Default: 0
 	Offset int
+
+	// CreatedAfter restricts results to memories created at or after this time.
+	CreatedAfter time.Time
+
+	// CreatedBefore restricts results to memories created before this time.
+	CreatedBefore time.Time
+
+	// UpdatedAfter restricts results to memories updated at or after this time.
+	UpdatedAfter time.Time
+
+	// UpdatedBefore restricts results to memories updated before this time.
+	UpdatedBefore time.Time
+
+	// Tags restricts results to memories carrying all of these tags.
+	Tags []string
+
+	// Filters restricts results to memories whose metadata matches all of these
+	// key/value pairs.
+	Filters map[string]interface{}
+
+	// MaxBatchBytes caps the estimated size of the batches of GetAllStream:
+	// a batch is sent before it has batchSize memories once its memories
+	// reach this size. Default: 0 (no cap)
+	MaxBatchBytes int64
+
+	// WithoutEmbeddings leaves the embeddings of the results nil, without
+	// reading the embedding column. Default: false
+	WithoutEmbeddings bool
+
+	// Fields restricts the fields of the results to these, without reading
+	// the columns of the others. Default: all fields
+	Fields []MemoryField
+
+	// AllowCrossUser allows listings without a user ID, which list the
+	// memories of every user. Default: false
+	AllowCrossUser bool
+}
+
+// WithAllowCrossUserForGetAll allows GetAll, GetAllStream and Memories to
+// list the memories of every user. It is required without
+// WithUserIDForGetAll, and such listings are recorded in the audit log (see
+// GetAll).
+//
+// Example:
+//
+//	memories, _ := client.GetAll(ctx, core.WithAllowCrossUserForGetAll(true))
+func WithAllowCrossUserForGetAll(allow bool) GetAllOption {
+	return func(opts *GetAllOptions) {
+		opts.AllowCrossUser = allow
+	}
+}
+
+// WithoutEmbeddings makes GetAll leave the embeddings of the memories nil,
+// without reading them from the store, e.g. for listings in a UI.
+//
+// Example:
+//
+//	memories, err := client.GetAll(ctx,
+//	    core.WithUserIDForGetAll("user_001"),
+//	    core.WithoutEmbeddings(),
+//	)
+func WithoutEmbeddings() GetAllOption {
+	return func(opts *GetAllOptions) {
+		opts.WithoutEmbeddings = true
+	}
+}
+
+// WithFieldsForGetAll restricts the fields of GetAll results to fields,
+// leaving the others zero without reading their columns from the store,
+// e.g. for listings in a UI.
+//
+// Example:
+//
+//	memories, err := client.GetAll(ctx,
+//	    core.WithUserIDForGetAll("user_001"),
+//	    core.WithFieldsForGetAll(core.MemoryFieldContent, core.MemoryFieldMetadata),
+//	)
+func WithFieldsForGetAll(fields ...MemoryField) GetAllOption {
+	return func(opts *GetAllOptions) {
+		opts.Fields = fields
+	}
 }
 
 // WithOffset sets the offset for GetAll operations (for pagination).
@@ -359,33 +848,266 @@ func WithOffset(offset int) GetAllOption {
 	}
 }
 
-// DeleteAllOption is a function type for configuring DeleteAll operations.
-type DeleteAllOption func(*DeleteAllOptions)
-
-// DeleteAllOptions contains configuration options for DeleteAll operations.
-type DeleteAllOptions struct {
-	// UserID filters deletions to a specific user.
-	UserID string
+// WithCreatedAfterForGetAll restricts GetAll results to memories created at or after t.
+//
+// Example:
+//
+//	memories, _ := client.GetAll(ctx,
+//	    core.WithUserIDForGetAll("user_001"),
+//	    core.WithCreatedAfterForGetAll(time.Now().AddDate(0, 0, -7)),
+//	)
+func WithCreatedAfterForGetAll(t time.Time) GetAllOption {
+	return func(opts *GetAllOptions) {
+		opts.CreatedAfter = t
+	}
+}
 
-	// AgentID filters deletions to a specific agent.
-	AgentID string
+// WithCreatedBeforeForGetAll restricts GetAll results to memories created before t.
+func WithCreatedBeforeForGetAll(t time.Time) GetAllOption {
+	return func(opts *GetAllOptions) {
+		opts.CreatedBefore = t
+	}
 }
 
-// applyAddOptions applies Add options to create AddOptions.
-func applyAddOptions(opts []AddOption) *AddOptions {
-	options := &AddOptions{
-		Infer:    false,
-		Scope:    ScopePrivate,
-		Metadata: make(map[string]interface{}),
-		Filters:  make(map[string]interface{}),
+// WithUpdatedAfterForGetAll restricts GetAll results to memories updated at or after t.
+func WithUpdatedAfterForGetAll(t time.Time) GetAllOption {
+	return func(opts *GetAllOptions) {
+		opts.UpdatedAfter = t
 	}
-	for _, opt := range opts {
-		opt(options)
+}
+
+// WithUpdatedBeforeForGetAll restricts GetAll results to memories updated before t.
+func WithUpdatedBeforeForGetAll(t time.Time) GetAllOption {
+	return func(opts *GetAllOptions) {
+		opts.UpdatedBefore = t
 	}
-	return options
 }
 
-// applySearchOptions applies Search options to create SearchOptions.
+// WithTagsForGetAll restricts GetAll results to memories carrying all of the given tags.
+func WithTagsForGetAll(tags ...string) GetAllOption {
+	return func(opts *GetAllOptions) {
+		opts.Tags = tags
+	}
+}
+
+// WithFiltersForGetAll restricts GetAll results to memories whose metadata
+// matches all of the given key/value pairs.
+//
+// Filters are pushed down to the storage backend, so pagination with
+// WithLimitForGetAll and WithOffset applies to the filtered set.
+//
+// Example:
+//
+//	memories, _ := client.GetAll(ctx,
+//	    core.WithUserIDForGetAll("user_001"),
+//	    core.WithFiltersForGetAll(map[string]interface{}{
+//	        "category": "preference",
+//	    }),
+//	)
+func WithFiltersForGetAll(filters map[string]interface{}) GetAllOption {
+	return func(opts *GetAllOptions) {
+		opts.Filters = filters
+	}
+}
+
+// DeleteAllOption is a function type for configuring DeleteAll operations.
+type DeleteAllOption func(*DeleteAllOptions)
+
+// DeleteAllOptions contains configuration options for DeleteAll operations.
+type DeleteAllOptions struct {
+	// UserID filters deletions to a specific user.
+	UserID string
+
+	// AgentID filters deletions to a specific agent.
+	AgentID string
+}
+
+// DeleteWhereOption is a function type for configuring DeleteWhere operations.
+type DeleteWhereOption func(*DeleteWhereOptions)
+
+// DeleteWhereOptions contains configuration options for DeleteWhere operations.
+type DeleteWhereOptions struct {
+	// UserID filters deletions to a specific user.
+	UserID string
+
+	// AgentID filters deletions to a specific agent.
+	AgentID string
+
+	// CreatedAfter restricts deletions to memories created at or after this time.
+	CreatedAfter time.Time
+
+	// CreatedBefore restricts deletions to memories created before this time.
+	CreatedBefore time.Time
+
+	// UpdatedAfter restricts deletions to memories updated at or after this time.
+	UpdatedAfter time.Time
+
+	// UpdatedBefore restricts deletions to memories updated before this time.
+	UpdatedBefore time.Time
+
+	// Tags restricts deletions to memories carrying all of these tags.
+	Tags []string
+
+	// ConfirmedCount is the number of memories the deletion must match, as
+	// reported by a dry run. Nil for a dry run.
+	ConfirmedCount *int64
+}
+
+// WithUserIDForDeleteWhere sets the user ID for DeleteWhere operations.
+func WithUserIDForDeleteWhere(userID string) DeleteWhereOption {
+	return func(opts *DeleteWhereOptions) {
+		opts.UserID = userID
+	}
+}
+
+// WithAgentIDForDeleteWhere sets the agent ID for DeleteWhere operations.
+func WithAgentIDForDeleteWhere(agentID string) DeleteWhereOption {
+	return func(opts *DeleteWhereOptions) {
+		opts.AgentID = agentID
+	}
+}
+
+// WithCreatedAfterForDeleteWhere restricts DeleteWhere to memories created at or after t.
+func WithCreatedAfterForDeleteWhere(t time.Time) DeleteWhereOption {
+	return func(opts *DeleteWhereOptions) {
+		opts.CreatedAfter = t
+	}
+}
+
+// WithCreatedBeforeForDeleteWhere restricts DeleteWhere to memories created before t.
+//
+// Example:
+//
+//	// Count the debug memories older than 30 days
+//	plan, _ := client.DeleteWhere(ctx, map[string]interface{}{"type": "debug"},
+//	    core.WithCreatedBeforeForDeleteWhere(time.Now().AddDate(0, 0, -30)),
+//	)
+func WithCreatedBeforeForDeleteWhere(t time.Time) DeleteWhereOption {
+	return func(opts *DeleteWhereOptions) {
+		opts.CreatedBefore = t
+	}
+}
+
+// WithUpdatedAfterForDeleteWhere restricts DeleteWhere to memories updated at or after t.
+func WithUpdatedAfterForDeleteWhere(t time.Time) DeleteWhereOption {
+	return func(opts *DeleteWhereOptions) {
+		opts.UpdatedAfter = t
+	}
+}
+
+// WithUpdatedBeforeForDeleteWhere restricts DeleteWhere to memories updated before t.
+func WithUpdatedBeforeForDeleteWhere(t time.Time) DeleteWhereOption {
+	return func(opts *DeleteWhereOptions) {
+		opts.UpdatedBefore = t
+	}
+}
+
+// WithTagsForDeleteWhere restricts DeleteWhere to memories carrying all of the given tags.
+func WithTagsForDeleteWhere(tags ...string) DeleteWhereOption {
+	return func(opts *DeleteWhereOptions) {
+		opts.Tags = tags
+	}
+}
+
+// WithConfirmedCount confirms the number of memories a DeleteWhere dry run
+// matched, so that DeleteWhere deletes them.
+//
+// Example:
+//
+//	result, err := client.DeleteWhere(ctx, filters, core.WithConfirmedCount(plan.Matched))
+func WithConfirmedCount(count int64) DeleteWhereOption {
+	return func(opts *DeleteWhereOptions) {
+		opts.ConfirmedCount = &count
+	}
+}
+
+// applyDeleteWhereOptions applies DeleteWhere options to create DeleteWhereOptions.
+func applyDeleteWhereOptions(opts []DeleteWhereOption) *DeleteWhereOptions {
+	options := &DeleteWhereOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// applyAddOptions applies Add options to create AddOptions.
+func applyAddOptions(opts []AddOption) *AddOptions {
+	options := &AddOptions{
+		Infer:    false,
+		Scope:    ScopePrivate,
+		Metadata: make(map[string]interface{}),
+		Filters:  make(map[string]interface{}),
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithRetrievalMode sets the retrieval mode for Search operations.
+//
+// RetrievalModeHyDE searches with the embedding of an LLM-generated
+// hypothetical answer, which improves recall for vague queries at the cost of
+// an LLM call. RetrievalModeHyDEFusion additionally searches with the raw
+// query and fuses both result lists. RetrievalModeHybrid also matches the
+// query text against the full-text index of OceanBase stores.
+//
+// Example:
+//
+//	results, _ := client.Search(ctx, "what do I like to do on weekends?",
+//	    core.WithUserIDForSearch("user_001"),
+//	    core.WithRetrievalMode(core.RetrievalModeHyDEFusion),
+//	)
+func WithRetrievalMode(mode RetrievalMode) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.RetrievalMode = mode
+	}
+}
+
+// WithEfSearch sets the search depth of the HNSW vector index for Search
+// operations. Higher values improve recall but slow the search. Vector
+// stores without an HNSW index ignore it.
+//
+// Example:
+//
+//	results, _ := client.Search(ctx, "query", core.WithEfSearch(200))
+func WithEfSearch(efSearch int) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.EfSearch = efSearch
+	}
+}
+
+// WithMaxBatchBytes caps the estimated size of the batches of SearchStream,
+// which sends a batch early once its memories reach maxBytes. A batch holds
+// at least one memory. Embeddings weigh 8 bytes per dimension.
+//
+// Example:
+//
+//	stream := client.SearchStream(ctx, "query", 500, core.WithMaxBatchBytes(16<<20))
+func WithMaxBatchBytes(maxBytes int64) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.MaxBatchBytes = maxBytes
+	}
+}
+
+// WithExplain sets whether Search explains why each result was retrieved,
+// in Memory.Explanation: the similarity score and its ranking breakdown,
+// the retention weighting, the filters the memory matched and the rewrites
+// of the query. Use it to tune retrieval quality.
+//
+// Example:
+//
+//	results, _ := client.Search(ctx, "query", core.WithExplain(true))
+//	for _, memory := range results {
+//	    fmt.Println(memory.Content, memory.Explanation.Similarity, memory.Explanation.MatchedFilters)
+//	}
+func WithExplain(explain bool) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.Explain = explain
+	}
+}
+
+// applySearchOptions applies Search options to create SearchOptions.
 func applySearchOptions(opts []SearchOption) *SearchOptions {
 	options := &SearchOptions{
 		Limit:    10,
@@ -394,9 +1116,30 @@ func applySearchOptions(opts []SearchOption) *SearchOptions {
 	for _, opt := range opts {
 		opt(options)
 	}
+	if options.Language != "" {
+		filters := make(map[string]interface{}, len(options.Filters)+1)
+		for k, v := range options.Filters {
+			filters[k] = v
+		}
+		filters[languageKey] = options.Language
+		options.Filters = filters
+	}
 	return options
 }
 
+// WithMaxBatchBytesForGetAll caps the estimated size of the batches of
+// GetAllStream, which sends a batch early once its memories reach maxBytes.
+// A batch holds at least one memory.
+//
+// Example:
+//
+//	stream := client.GetAllStream(ctx, 1000, core.WithMaxBatchBytesForGetAll(16<<20))
+func WithMaxBatchBytesForGetAll(maxBytes int64) GetAllOption {
+	return func(opts *GetAllOptions) {
+		opts.MaxBatchBytes = maxBytes
+	}
+}
+
 // applyGetAllOptions applies GetAll options to create GetAllOptions.
 func applyGetAllOptions(opts []GetAllOption) *GetAllOptions {
 	options := &GetAllOptions{
@@ -454,6 +1197,14 @@ type UpdateOptions struct {
 
 	// AgentID restricts updates to memories belonging to this agent (agent-level access control).
 	AgentID string
+
+	// Metadata replaces the memory's metadata when non-nil.
+	// If not set, the existing metadata is preserved.
+	Metadata map[string]interface{}
+
+	// ExpectedVersion makes the update conditional on the memory's current
+	// Version (optimistic concurrency). 0 disables the check.
+	ExpectedVersion int64
 }
 
 // WithUserIDForUpdate sets the user ID for Update operations (access control).
@@ -470,6 +1221,43 @@ func WithAgentIDForUpdate(agentID string) UpdateOption {
 	}
 }
 
+// WithMetadataForUpdate replaces the memory's metadata as part of an Update.
+//
+// Without this option, Update keeps the existing metadata unchanged.
+//
+// Example:
+//
+//	memory, _ := client.Update(ctx, id, "User prefers light mode",
+//	    core.WithMetadataForUpdate(map[string]interface{}{"source": "settings"}),
+//	)
+func WithMetadataForUpdate(metadata map[string]interface{}) UpdateOption {
+	return func(opts *UpdateOptions) {
+		opts.Metadata = metadata
+	}
+}
+
+// WithExpectedVersion makes an Update succeed only if the memory still has the
+// given Version, i.e. nobody else updated it since it was read.
+//
+// This provides optimistic concurrency control when several service instances
+// share one storage backend. On a mismatch, Update returns an error matching
+// ErrVersionConflict and the memory is left unchanged.
+//
+// Example:
+//
+//	memory, _ := client.Get(ctx, id)
+//	_, err := client.Update(ctx, id, memory.Content+" (verified)",
+//	    core.WithExpectedVersion(memory.Version),
+//	)
+//	if errors.Is(err, core.ErrVersionConflict) {
+//	    // Re-read the memory and retry
+//	}
+func WithExpectedVersion(version int64) UpdateOption {
+	return func(opts *UpdateOptions) {
+		opts.ExpectedVersion = version
+	}
+}
+
 // DeleteOption is a function type for configuring Delete operations.
 type DeleteOption func(*DeleteOptions)
 
@@ -522,3 +1310,866 @@ func applyDeleteOptions(opts []DeleteOption) *DeleteOptions {
 	}
 	return options
 }
+
+// FeedbackOption is a function type for configuring Feedback operations.
+type FeedbackOption func(*FeedbackOptions)
+
+// FeedbackOptions contains configuration options for Feedback operations.
+type FeedbackOptions struct {
+	// UserID restricts feedback to memories belonging to this user.
+	UserID string
+
+	// AgentID restricts feedback to memories belonging to this agent.
+	AgentID string
+
+	// Rewrite makes FeedbackIncorrect ask the LLM to correct the memory
+	// from the note, or forget it.
+	Rewrite bool
+}
+
+// WithUserIDForFeedback sets the user ID for Feedback operations (access control).
+func WithUserIDForFeedback(userID string) FeedbackOption {
+	return func(opts *FeedbackOptions) {
+		opts.UserID = userID
+	}
+}
+
+// WithAgentIDForFeedback sets the agent ID for Feedback operations (access control).
+func WithAgentIDForFeedback(agentID string) FeedbackOption {
+	return func(opts *FeedbackOptions) {
+		opts.AgentID = agentID
+	}
+}
+
+// WithFeedbackRewrite makes FeedbackIncorrect rewrite the memory with the
+// LLM instead of flagging it: the memory is corrected according to the note,
+// or deleted if the note does not say what is right.
+//
+// Example:
+//
+//	result, _ := client.Feedback(ctx, memoryID, core.FeedbackIncorrect,
+//	    "I moved to Lisbon in May", core.WithFeedbackRewrite(true))
+func WithFeedbackRewrite(rewrite bool) FeedbackOption {
+	return func(opts *FeedbackOptions) {
+		opts.Rewrite = rewrite
+	}
+}
+
+// applyFeedbackOptions applies Feedback options.
+func applyFeedbackOptions(opts []FeedbackOption) *FeedbackOptions {
+	options := &FeedbackOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// ConsolidateOption is a function type for configuring Consolidate operations.
+type ConsolidateOption func(*ConsolidateOptions)
+
+// ConsolidateOptions contains configuration options for Consolidate operations.
+type ConsolidateOptions struct {
+	// AgentID restricts the review to the memories of this agent.
+	AgentID string
+
+	// Limit is the maximum number of memories reviewed (default 100).
+	Limit int
+
+	// DryRun makes Consolidate return the planned consolidations without
+	// performing them.
+	DryRun bool
+}
+
+// WithAgentIDForConsolidate restricts Consolidate to the memories of an agent.
+func WithAgentIDForConsolidate(agentID string) ConsolidateOption {
+	return func(opts *ConsolidateOptions) {
+		opts.AgentID = agentID
+	}
+}
+
+// WithLimitForConsolidate sets the maximum number of memories Consolidate
+// reviews in one LLM call (the newest ones).
+func WithLimitForConsolidate(limit int) ConsolidateOption {
+	return func(opts *ConsolidateOptions) {
+		opts.Limit = limit
+	}
+}
+
+// WithConsolidateDryRun makes Consolidate plan consolidations without
+// performing them.
+//
+// Example:
+//
+//	plan, _ := client.Consolidate(ctx, "user_001", core.WithConsolidateDryRun(true))
+//	for _, action := range plan.Actions {
+//	    fmt.Println(action.Event, action.PreviousMemories, "->", action.Memory)
+//	}
+func WithConsolidateDryRun(dryRun bool) ConsolidateOption {
+	return func(opts *ConsolidateOptions) {
+		opts.DryRun = dryRun
+	}
+}
+
+// applyConsolidateOptions applies Consolidate options.
+func applyConsolidateOptions(opts []ConsolidateOption) *ConsolidateOptions {
+	options := &ConsolidateOptions{Limit: defaultConsolidateLimit}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Limit <= 0 {
+		options.Limit = defaultConsolidateLimit
+	}
+	return options
+}
+
+// ForgetOption is a function type for configuring Forget operations.
+type ForgetOption func(*ForgetOptions)
+
+// ForgetOptions contains configuration options for Forget operations.
+type ForgetOptions struct {
+	// Limit is the maximum number of candidate memories reviewed (default 20).
+	Limit int
+
+	// ConfirmedActions are the planned actions to perform (nil for a dry
+	// run).
+	ConfirmedActions []ForgetActionResult
+}
+
+// WithLimitForForget sets the maximum number of memories, the most similar
+// to the instruction, that Forget reviews.
+func WithLimitForForget(limit int) ForgetOption {
+	return func(opts *ForgetOptions) {
+		opts.Limit = limit
+	}
+}
+
+// WithConfirmedForgetActions makes Forget perform actions planned by a dry
+// run, typically the ones the user approved.
+//
+// Example:
+//
+//	plan, _ := client.Forget(ctx, "user_001", "Forget my address")
+//	result, _ := client.Forget(ctx, "user_001", "Forget my address",
+//	    core.WithConfirmedForgetActions(plan.Actions...))
+func WithConfirmedForgetActions(actions ...ForgetActionResult) ForgetOption {
+	return func(opts *ForgetOptions) {
+		opts.ConfirmedActions = append([]ForgetActionResult{}, actions...)
+	}
+}
+
+// applyForgetOptions applies Forget options.
+func applyForgetOptions(opts []ForgetOption) *ForgetOptions {
+	options := &ForgetOptions{Limit: defaultForgetLimit}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Limit <= 0 {
+		options.Limit = defaultForgetLimit
+	}
+	return options
+}
+
+// AsyncOption is a function type for configuring an AsyncClient.
+type AsyncOption func(*AsyncOptions)
+
+// AsyncOptions contains configuration options for an AsyncClient.
+type AsyncOptions struct {
+	// Workers is the number of goroutines executing async operations.
+	// Default: 10
+	Workers int
+
+	// QueueSize is the number of operations that can wait for a free worker.
+	// Default: 100
+	QueueSize int
+
+	// Backpressure decides what happens when the queue is full.
+	// Default: BackpressureBlock
+	Backpressure BackpressurePolicy
+}
+
+// BackpressurePolicy decides how an AsyncClient handles operations when its queue is full.
+type BackpressurePolicy string
+
+const (
+	// BackpressureBlock makes async calls wait until a queue slot frees up or ctx is done.
+	BackpressureBlock BackpressurePolicy = "block"
+
+	// BackpressureDrop makes async calls fail immediately with ErrQueueFull.
+	BackpressureDrop BackpressurePolicy = "drop"
+)
+
+// WithAsyncWorkers sets the number of worker goroutines of an AsyncClient.
+//
+// Example:
+//
+//	asyncClient, _ := core.NewAsyncClient(config, core.WithAsyncWorkers(4))
+func WithAsyncWorkers(workers int) AsyncOption {
+	return func(opts *AsyncOptions) {
+		opts.Workers = workers
+	}
+}
+
+// WithAsyncQueueSize sets how many operations can be queued while all workers are busy.
+//
+// Example:
+//
+//	asyncClient, _ := core.NewAsyncClient(config, core.WithAsyncQueueSize(1000))
+func WithAsyncQueueSize(size int) AsyncOption {
+	return func(opts *AsyncOptions) {
+		opts.QueueSize = size
+	}
+}
+
+// WithAsyncBackpressure sets the policy used when the async queue is full.
+//
+// Example:
+//
+//	// Shed load instead of blocking callers
+//	asyncClient, _ := core.NewAsyncClient(config,
+//	    core.WithAsyncQueueSize(1000),
+//	    core.WithAsyncBackpressure(core.BackpressureDrop),
+//	)
+func WithAsyncBackpressure(policy BackpressurePolicy) AsyncOption {
+	return func(opts *AsyncOptions) {
+		opts.Backpressure = policy
+	}
+}
+
+// applyAsyncOptions applies Async options to create AsyncOptions.
+func applyAsyncOptions(opts []AsyncOption) *AsyncOptions {
+	options := &AsyncOptions{
+		Workers:      10,
+		QueueSize:    100,
+		Backpressure: BackpressureBlock,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Workers <= 0 {
+		options.Workers = 1
+	}
+	if options.QueueSize < 0 {
+		options.QueueSize = 0
+	}
+	if options.Backpressure != BackpressureDrop {
+		options.Backpressure = BackpressureBlock
+	}
+	return options
+}
+
+// WithBatchOptions sets the batch policy (concurrency, failure handling,
+// retries) of a BatchAdd call.
+//
+// Example:
+//
+//	result, _ := client.BatchAdd(ctx, contents,
+//	    core.WithUserID("user_001"),
+//	    core.WithBatchOptions(core.WithBatchConcurrency(4), core.WithBatchFailFast(true)),
+//	)
+func WithBatchOptions(opts ...BatchOption) AddOption {
+	return func(o *AddOptions) {
+		o.Batch = append(o.Batch, opts...)
+	}
+}
+
+// BatchOption is a function type for configuring batch operations.
+type BatchOption func(*BatchOptions)
+
+// BatchOptions contains configuration options for BatchAdd, BatchAddItems,
+// BatchUpdate and BatchDelete.
+type BatchOptions struct {
+	// Concurrency is the maximum number of items processed at the same time.
+	// Default: 10
+	Concurrency int
+
+	// FailFast stops the batch after the first item fails. Items already in
+	// progress finish; items not yet started fail with ErrBatchAborted.
+	// Default: false (process every item and report failures per item)
+	FailFast bool
+
+	// MaxRetries is how many times a failed item is retried before it is
+	// reported as failed. Invalid input and context errors are not retried.
+	// Default: 0
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry; it doubles on every
+	// further retry.
+	// Default: 100ms
+	RetryBackoff time.Duration
+}
+
+// WithBatchConcurrency sets the maximum number of items processed at the same time.
+//
+// Example:
+//
+//	result, _ := client.BatchDelete(ctx, ids, core.WithBatchConcurrency(2))
+func WithBatchConcurrency(concurrency int) BatchOption {
+	return func(opts *BatchOptions) {
+		opts.Concurrency = concurrency
+	}
+}
+
+// WithBatchFailFast stops the batch after the first failed item.
+//
+// Example:
+//
+//	result, _ := client.BatchUpdate(ctx, items, core.WithBatchFailFast(true))
+func WithBatchFailFast(failFast bool) BatchOption {
+	return func(opts *BatchOptions) {
+		opts.FailFast = failFast
+	}
+}
+
+// WithBatchRetry retries failed items up to maxRetries times, waiting backoff
+// before the first retry and doubling the wait on every further retry.
+//
+// Example:
+//
+//	result, _ := client.BatchUpdate(ctx, items,
+//	    core.WithBatchRetry(3, 200*time.Millisecond),
+//	)
+func WithBatchRetry(maxRetries int, backoff time.Duration) BatchOption {
+	return func(opts *BatchOptions) {
+		opts.MaxRetries = maxRetries
+		opts.RetryBackoff = backoff
+	}
+}
+
+// applyBatchOptions applies Batch options to create BatchOptions.
+func applyBatchOptions(opts []BatchOption) *BatchOptions {
+	options := &BatchOptions{
+		Concurrency:  10,
+		RetryBackoff: 100 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = 1
+	}
+	if options.MaxRetries < 0 {
+		options.MaxRetries = 0
+	}
+	if options.RetryBackoff < 0 {
+		options.RetryBackoff = 0
+	}
+	return options
+}
+
+// HealthOption is a function type for configuring Health.
+type HealthOption func(*HealthOptions)
+
+// HealthOptions contains configuration options for Health.
+type HealthOptions struct {
+	// Components are the checks to run.
+	// Default: HealthVectorStore, HealthLLM and HealthEmbedder
+	Components []HealthComponent
+
+	// Timeout bounds each check.
+	// Default: 5s
+	Timeout time.Duration
+}
+
+// WithHealthComponents runs only the given checks.
+//
+// Example:
+//
+//	report, _ := client.Health(ctx, core.WithHealthComponents(core.HealthVectorStore))
+func WithHealthComponents(components ...HealthComponent) HealthOption {
+	return func(opts *HealthOptions) {
+		opts.Components = components
+	}
+}
+
+// WithHealthTimeout sets the timeout of each check.
+//
+// Example:
+//
+//	report, _ := client.Health(ctx, core.WithHealthTimeout(2*time.Second))
+func WithHealthTimeout(timeout time.Duration) HealthOption {
+	return func(opts *HealthOptions) {
+		opts.Timeout = timeout
+	}
+}
+
+// applyHealthOptions applies Health options to create HealthOptions.
+func applyHealthOptions(opts []HealthOption) *HealthOptions {
+	options := &HealthOptions{
+		Components: []HealthComponent{HealthVectorStore, HealthLLM, HealthEmbedder},
+		Timeout:    5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 5 * time.Second
+	}
+	return options
+}
+
+// WatchOption is a function type for configuring WatchConfigFile.
+type WatchOption func(*WatchOptions)
+
+// WatchOptions contains configuration options for WatchConfigFile.
+type WatchOptions struct {
+	// Interval is how often the file is checked for changes.
+	// Default: 2s
+	Interval time.Duration
+
+	// OnError is called with each update that could not be applied.
+	// Default: log the error
+	OnError func(err error)
+
+	// OnReload is called with each configuration that was applied (optional).
+	OnReload func(cfg *Config)
+}
+
+// WithWatchInterval sets how often the config file is checked for changes.
+//
+// Example:
+//
+//	err := client.WatchConfigFile(ctx, "powermem.yaml", core.WithWatchInterval(10*time.Second))
+func WithWatchInterval(interval time.Duration) WatchOption {
+	return func(opts *WatchOptions) {
+		opts.Interval = interval
+	}
+}
+
+// WithReloadErrorHandler sets the function called with each config update
+// that could not be loaded or applied. The client keeps its current
+// configuration in that case.
+//
+// Example:
+//
+//	err := client.WatchConfigFile(ctx, "powermem.yaml",
+//	    core.WithReloadErrorHandler(func(err error) {
+//	        alerts.Notify("invalid powermem config: " + err.Error())
+//	    }),
+//	)
+func WithReloadErrorHandler(handler func(err error)) WatchOption {
+	return func(opts *WatchOptions) {
+		opts.OnError = handler
+	}
+}
+
+// WithReloadHandler sets the function called with each configuration that
+// was applied.
+//
+// Example:
+//
+//	err := client.WatchConfigFile(ctx, "powermem.yaml",
+//	    core.WithReloadHandler(func(cfg *core.Config) {
+//	        log.Printf("config reloaded, model %s", cfg.LLM.Model)
+//	    }),
+//	)
+func WithReloadHandler(handler func(cfg *Config)) WatchOption {
+	return func(opts *WatchOptions) {
+		opts.OnReload = handler
+	}
+}
+
+// applyWatchOptions applies WatchConfigFile options to create WatchOptions.
+func applyWatchOptions(opts []WatchOption) *WatchOptions {
+	options := &WatchOptions{
+		Interval: 2 * time.Second,
+		OnError:  logReloadError,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Interval <= 0 {
+		options.Interval = 2 * time.Second
+	}
+	if options.OnError == nil {
+		options.OnError = logReloadError
+	}
+	return options
+}
+
+// ChangesOption is a function type for configuring ListChanges and
+// SubscribeChanges.
+type ChangesOption func(*ChangesOptions)
+
+// ChangesOptions contains configuration options for reading the change log.
+type ChangesOptions struct {
+	// After is the Seq after which changes are read.
+	// Default: 0 (from the beginning of the log)
+	After int64
+
+	// UserID filters changes by user ID (optional). Bulk changes without a
+	// user, such as a DeleteAll of all memories, are included.
+	UserID string
+
+	// AgentID filters changes by agent ID (optional). Bulk changes without an
+	// agent are included.
+	AgentID string
+
+	// Types filters changes by event type (optional, all types if empty).
+	Types []EventType
+
+	// Limit is the maximum number of changes read at once.
+	// Default: 100
+	Limit int
+
+	// PollInterval is how often SubscribeChanges checks the log.
+	// Default: 1s
+	PollInterval time.Duration
+}
+
+// WithChangesAfter reads the changes after the given Seq, typically the Seq
+// of the last change processed.
+//
+// Example:
+//
+//	changes, err := client.ListChanges(ctx, core.WithChangesAfter(checkpoint))
+func WithChangesAfter(seq int64) ChangesOption {
+	return func(opts *ChangesOptions) {
+		opts.After = seq
+	}
+}
+
+// WithUserIDForChanges filters changes by user ID.
+func WithUserIDForChanges(userID string) ChangesOption {
+	return func(opts *ChangesOptions) {
+		opts.UserID = userID
+	}
+}
+
+// WithAgentIDForChanges filters changes by agent ID.
+func WithAgentIDForChanges(agentID string) ChangesOption {
+	return func(opts *ChangesOptions) {
+		opts.AgentID = agentID
+	}
+}
+
+// WithChangeTypes filters changes by event type.
+//
+// Example:
+//
+//	stream := client.SubscribeChanges(ctx, core.WithChangeTypes(core.EventUpdated, core.EventDeleted))
+func WithChangeTypes(types ...EventType) ChangesOption {
+	return func(opts *ChangesOptions) {
+		opts.Types = types
+	}
+}
+
+// WithChangesLimit sets the maximum number of changes read at once.
+func WithChangesLimit(limit int) ChangesOption {
+	return func(opts *ChangesOptions) {
+		opts.Limit = limit
+	}
+}
+
+// WithChangesPollInterval sets how often SubscribeChanges checks the log for
+// changes made by other clients.
+func WithChangesPollInterval(interval time.Duration) ChangesOption {
+	return func(opts *ChangesOptions) {
+		opts.PollInterval = interval
+	}
+}
+
+// applyChangesOptions applies change log options to create ChangesOptions.
+func applyChangesOptions(opts []ChangesOption) *ChangesOptions {
+	options := &ChangesOptions{
+		Limit:        100,
+		PollInterval: time.Second,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Limit <= 0 {
+		options.Limit = 100
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = time.Second
+	}
+	return options
+}
+
+// AuditOption is a function type for configuring ListAuditEntries.
+type AuditOption func(*AuditOptions)
+
+// AuditOptions contains configuration options for reading the audit log.
+type AuditOptions struct {
+	// After is the Seq after which entries are read.
+	// Default: 0 (from the beginning of the log)
+	After int64
+
+	// Operations filters entries by operation (optional, all operations if
+	// empty).
+	Operations []string
+
+	// Actor filters entries by actor (optional).
+	Actor string
+
+	// UserID filters entries by affected user (optional).
+	UserID string
+
+	// Limit is the maximum number of entries read at once.
+	// Default: 100
+	Limit int
+}
+
+// WithAuditAfter reads the audit entries after the given Seq.
+func WithAuditAfter(seq int64) AuditOption {
+	return func(opts *AuditOptions) {
+		opts.After = seq
+	}
+}
+
+// WithAuditOperations filters audit entries by operation.
+//
+// Example:
+//
+//	entries, err := client.ListAuditEntries(ctx, core.WithAuditOperations("EraseUser"))
+func WithAuditOperations(operations ...string) AuditOption {
+	return func(opts *AuditOptions) {
+		opts.Operations = operations
+	}
+}
+
+// WithActorForAudit filters audit entries by actor.
+func WithActorForAudit(actor string) AuditOption {
+	return func(opts *AuditOptions) {
+		opts.Actor = actor
+	}
+}
+
+// WithUserIDForAudit filters audit entries by affected user.
+func WithUserIDForAudit(userID string) AuditOption {
+	return func(opts *AuditOptions) {
+		opts.UserID = userID
+	}
+}
+
+// WithAuditLimit sets the maximum number of audit entries read at once.
+func WithAuditLimit(limit int) AuditOption {
+	return func(opts *AuditOptions) {
+		opts.Limit = limit
+	}
+}
+
+// applyAuditOptions applies audit log options to create AuditOptions.
+func applyAuditOptions(opts []AuditOption) *AuditOptions {
+	options := &AuditOptions{Limit: 100}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Limit <= 0 {
+		options.Limit = 100
+	}
+	return options
+}
+
+// ReportOption is a function type for configuring GenerateUserReport.
+type ReportOption func(*ReportOptions)
+
+// ReportOptions contains configuration options for user reports.
+type ReportOptions struct {
+	// TopMemories is the number of memories with the highest retention
+	// strength in the report.
+	// Default: 10
+	TopMemories int
+
+	// RecentChanges is the number of latest changes in the report.
+	// Default: 20
+	RecentChanges int
+}
+
+// WithReportTopMemories sets the number of memories with the highest
+// retention strength in the report.
+func WithReportTopMemories(n int) ReportOption {
+	return func(opts *ReportOptions) {
+		opts.TopMemories = n
+	}
+}
+
+// WithReportRecentChanges sets the number of latest changes in the report.
+// Zero leaves the changes out.
+//
+// Example:
+//
+//	report, _ := client.GenerateUserReport(ctx, "user_001", core.WithReportRecentChanges(50))
+func WithReportRecentChanges(n int) ReportOption {
+	return func(opts *ReportOptions) {
+		opts.RecentChanges = n
+	}
+}
+
+// applyReportOptions applies GenerateUserReport options.
+func applyReportOptions(opts []ReportOption) *ReportOptions {
+	options := &ReportOptions{TopMemories: 10, RecentChanges: 20}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.TopMemories < 0 {
+		options.TopMemories = 0
+	}
+	return options
+}
+
+// WorkingMemoryOption is a function type for configuring working memories.
+type WorkingMemoryOption func(*WorkingMemoryOptions)
+
+// WorkingMemoryOptions contains configuration options for working memories.
+type WorkingMemoryOptions struct {
+	// UserID is the user of the items.
+	UserID string
+
+	// AgentID is the agent of the items.
+	AgentID string
+
+	// TTL is how long items live after they are added (default
+	// DefaultWorkingMemoryTTL).
+	TTL time.Duration
+}
+
+// WithWorkingMemoryUserID sets the user of a working memory.
+func WithWorkingMemoryUserID(userID string) WorkingMemoryOption {
+	return func(opts *WorkingMemoryOptions) {
+		opts.UserID = userID
+	}
+}
+
+// WithWorkingMemoryAgentID sets the agent of a working memory.
+func WithWorkingMemoryAgentID(agentID string) WorkingMemoryOption {
+	return func(opts *WorkingMemoryOptions) {
+		opts.AgentID = agentID
+	}
+}
+
+// WithWorkingMemoryTTL sets how long the items of a working memory live
+// after they are added.
+//
+// Example:
+//
+//	scratch := client.WorkingMemory("run_001", core.WithWorkingMemoryTTL(10*time.Minute))
+func WithWorkingMemoryTTL(ttl time.Duration) WorkingMemoryOption {
+	return func(opts *WorkingMemoryOptions) {
+		opts.TTL = ttl
+	}
+}
+
+// applyWorkingMemoryOptions applies working memory options.
+func applyWorkingMemoryOptions(opts []WorkingMemoryOption) *WorkingMemoryOptions {
+	options := &WorkingMemoryOptions{TTL: DefaultWorkingMemoryTTL}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.TTL <= 0 {
+		options.TTL = DefaultWorkingMemoryTTL
+	}
+	return options
+}
+
+// PromoteOption is a function type for configuring Promote operations.
+type PromoteOption func(*PromoteOptions)
+
+// PromoteOptions contains configuration options for Promote operations.
+type PromoteOptions struct {
+	// UserID restricts Promote to the items of this user.
+	UserID string
+
+	// AgentID restricts Promote to the items of this agent.
+	AgentID string
+
+	// IDs are the items to promote (all items if empty).
+	IDs []int64
+}
+
+// WithUserIDForPromote restricts Promote to the items of a user.
+func WithUserIDForPromote(userID string) PromoteOption {
+	return func(opts *PromoteOptions) {
+		opts.UserID = userID
+	}
+}
+
+// WithAgentIDForPromote restricts Promote to the items of an agent.
+func WithAgentIDForPromote(agentID string) PromoteOption {
+	return func(opts *PromoteOptions) {
+		opts.AgentID = agentID
+	}
+}
+
+// WithPromoteIDs selects the items Promote persists; the other items of the
+// run are discarded.
+//
+// Example:
+//
+//	result, _ := client.Promote(ctx, "run_001", core.WithPromoteIDs(planID, decisionID))
+func WithPromoteIDs(ids ...int64) PromoteOption {
+	return func(opts *PromoteOptions) {
+		opts.IDs = append(opts.IDs, ids...)
+	}
+}
+
+// applyPromoteOptions applies Promote options.
+func applyPromoteOptions(opts []PromoteOption) *PromoteOptions {
+	options := &PromoteOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// ShareOption is a function type for configuring ShareMemory operations.
+type ShareOption func(*ShareOptions)
+
+// ShareOptions contains configuration options for ShareMemory operations.
+type ShareOptions struct {
+	// UserID restricts ShareMemory to the memories of this user.
+	UserID string
+
+	// AgentID is the agent of the copy (none by default).
+	AgentID string
+
+	// Purpose is recorded as ShareConsent.Purpose.
+	Purpose string
+
+	// GrantedBy is recorded as ShareConsent.GrantedBy.
+	GrantedBy string
+}
+
+// WithUserIDForShare restricts ShareMemory to the memories of a user, who
+// then owns the shared memory.
+func WithUserIDForShare(userID string) ShareOption {
+	return func(opts *ShareOptions) {
+		opts.UserID = userID
+	}
+}
+
+// WithAgentIDForShare sets the agent of the copy made by ShareMemory.
+func WithAgentIDForShare(agentID string) ShareOption {
+	return func(opts *ShareOptions) {
+		opts.AgentID = agentID
+	}
+}
+
+// WithSharePurpose records why a memory is shared.
+//
+// Example:
+//
+//	copied, _ := client.ShareMemory(ctx, memoryID, "bob", core.WithSharePurpose("handover of project X"))
+func WithSharePurpose(purpose string) ShareOption {
+	return func(opts *ShareOptions) {
+		opts.Purpose = purpose
+	}
+}
+
+// WithShareGrantedBy records who consented to the sharing, e.g. the owner of
+// the memory when an administrator shares it on their behalf. By default,
+// it is the actor of the context (see ContextWithActor), or the owner.
+func WithShareGrantedBy(grantedBy string) ShareOption {
+	return func(opts *ShareOptions) {
+		opts.GrantedBy = grantedBy
+	}
+}
+
+// applyShareOptions applies ShareMemory options.
+func applyShareOptions(opts []ShareOption) *ShareOptions {
+	options := &ShareOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
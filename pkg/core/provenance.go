@@ -0,0 +1,264 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+const (
+	// provenanceKey is the metadata key holding the provenance entries
+	// recorded by IntelligentAdd and Consolidate.
+	provenanceKey = "provenance"
+
+	// mergeHistoryKey is the metadata key holding the merges of duplicates
+	// recorded by Add (see intelligence.DedupManager).
+	mergeHistoryKey = "merge_history"
+
+	// maxProvenanceEntries is the maximum number of entries kept in
+	// metadata["provenance"]; the oldest ones are dropped first.
+	maxProvenanceEntries = 50
+)
+
+// ProvenanceOperation is the operation that changed a memory, in a
+// ProvenanceEntry.
+type ProvenanceOperation string
+
+const (
+	// ProvenanceMerge is a duplicate merged into the memory by Add.
+	ProvenanceMerge ProvenanceOperation = "merge"
+
+	// ProvenanceUpdate is an UPDATE decision of IntelligentAdd.
+	ProvenanceUpdate ProvenanceOperation = "update"
+
+	// ProvenanceConsolidate is a MERGE or RESOLVE of Consolidate.
+	ProvenanceConsolidate ProvenanceOperation = "consolidate"
+)
+
+// Provenance is why a memory says what it says, as returned by
+// GetProvenance: its sources and the chain of merges and updates that
+// produced its content.
+type Provenance struct {
+	// MemoryID is the memory.
+	MemoryID int64 `json:"memory_id"`
+
+	// Content is the current content of the memory.
+	Content string `json:"content"`
+
+	// Sources are where the memory came from (see Memory.Sources).
+	Sources []Source `json:"sources,omitempty"`
+
+	// Entries are the merges and updates of the memory, and of the
+	// memories merged into it, oldest first.
+	Entries []ProvenanceEntry `json:"entries"`
+}
+
+// ProvenanceEntry is a merge or update in a provenance chain.
+type ProvenanceEntry struct {
+	// Operation is the operation that changed the memory.
+	Operation ProvenanceOperation `json:"operation"`
+
+	// Time is when the memory was changed.
+	Time time.Time `json:"time"`
+
+	// MemoryID is the memory that was changed, if it is not the memory of
+	// the Provenance but one that Consolidate merged into it later.
+	MemoryID int64 `json:"memory_id,omitempty"`
+
+	// PreviousContent is the content before the change.
+	PreviousContent string `json:"previous_content,omitempty"`
+
+	// IncomingContent is the content merged in: the new memory of a
+	// ProvenanceMerge, or the content the memory was updated or
+	// consolidated to.
+	IncomingContent string `json:"incoming_content,omitempty"`
+
+	// SourceMemoryIDs are the memories that Consolidate merged into the
+	// memory and deleted, and SourceContents their content.
+	SourceMemoryIDs []int64  `json:"source_memory_ids,omitempty"`
+	SourceContents  []string `json:"source_contents,omitempty"`
+
+	// Sources are the messages or documents the incoming content came from.
+	Sources []Source `json:"sources,omitempty"`
+
+	// Strategy is the merge strategy of a ProvenanceMerge.
+	Strategy string `json:"strategy,omitempty"`
+
+	// Reason is the explanation of the LLM for a ProvenanceConsolidate.
+	Reason string `json:"reason,omitempty"`
+}
+
+// GetProvenance returns the provenance of a memory: its sources and the
+// chain of changes that produced its content, for debugging why a memory
+// says what it says. The chain holds the duplicates merged into it by Add
+// (metadata["merge_history"]), the UPDATE decisions of IntelligentAdd with
+// the messages they came from, and the MERGE and RESOLVE decisions of
+// Consolidate with the merged memories and their own chains
+// (metadata["provenance"]).
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - id: Memory ID
+//   - opts: Optional access control (WithUserIDForGet, WithAgentIDForGet)
+//
+// Returns the provenance, or an error wrapping storage.ErrNotFound if the
+// memory does not exist or is not accessible.
+//
+// Example:
+//
+//	provenance, err := client.GetProvenance(ctx, memoryID, core.WithUserIDForGet("user_001"))
+//	for _, entry := range provenance.Entries {
+//	    fmt.Println(entry.Time, entry.Operation, entry.PreviousContent, "->", entry.IncomingContent)
+//	}
+func (c *Client) GetProvenance(ctx context.Context, id int64, opts ...GetOption) (*Provenance, error) {
+	ctx, err := c.begin(ctx, "GetProvenance")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	getOpts := applyGetOptions(opts)
+	memory, err := c.storage.Get(ctx, id, &storage.GetOptions{
+		UserID:  getOpts.UserID,
+		AgentID: getOpts.AgentID,
+		Now:     c.now(),
+	})
+	if err != nil {
+		return nil, NewMemoryError("GetProvenance", err)
+	}
+
+	return &Provenance{
+		MemoryID: memory.ID,
+		Content:  memory.Content,
+		Sources:  sourcesFromMetadata(memory.Metadata),
+		Entries:  provenanceFromMetadata(memory.Metadata),
+	}, nil
+}
+
+// provenanceRecord is a ProvenanceEntry as stored in metadata. The memory
+// IDs are strings, since JSON numbers cannot hold every int64.
+type provenanceRecord struct {
+	ProvenanceEntry
+	MemoryID        int64    `json:"memory_id,omitempty,string"`
+	SourceMemoryIDs []string `json:"source_memory_ids,omitempty"`
+}
+
+// recordedProvenance returns the entries of metadata["provenance"] (nil if
+// there are none or if they are malformed).
+func recordedProvenance(metadata map[string]interface{}) []ProvenanceEntry {
+	value, ok := metadata[provenanceKey]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var records []provenanceRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil
+	}
+	entries := make([]ProvenanceEntry, 0, len(records))
+	for _, record := range records {
+		entry := record.ProvenanceEntry
+		entry.MemoryID = record.MemoryID
+		entry.SourceMemoryIDs = nil
+		for _, id := range record.SourceMemoryIDs {
+			if parsed, err := strconv.ParseInt(id, 10, 64); err == nil {
+				entry.SourceMemoryIDs = append(entry.SourceMemoryIDs, parsed)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// provenanceValue returns entries as stored in metadata["provenance"], as
+// they are read back from the store.
+func provenanceValue(entries []ProvenanceEntry) []interface{} {
+	records := make([]provenanceRecord, len(entries))
+	for i, entry := range entries {
+		records[i] = provenanceRecord{ProvenanceEntry: entry, MemoryID: entry.MemoryID}
+		for _, id := range entry.SourceMemoryIDs {
+			records[i].SourceMemoryIDs = append(records[i].SourceMemoryIDs, strconv.FormatInt(id, 10))
+		}
+	}
+	var value []interface{}
+	if data, err := json.Marshal(records); err == nil {
+		_ = json.Unmarshal(data, &value)
+	}
+	return value
+}
+
+// provenanceFromMetadata returns the provenance entries recorded in
+// metadata, oldest first. Malformed entries are skipped.
+func provenanceFromMetadata(metadata map[string]interface{}) []ProvenanceEntry {
+	entries := append([]ProvenanceEntry{}, recordedProvenance(metadata)...)
+	if value, ok := metadata[mergeHistoryKey]; ok {
+		var merges []struct {
+			Strategy        string `json:"strategy"`
+			MergedAt        string `json:"merged_at"`
+			PreviousContent string `json:"previous_content"`
+			IncomingContent string `json:"incoming_content"`
+		}
+		if data, err := json.Marshal(value); err == nil && json.Unmarshal(data, &merges) == nil {
+			for _, merge := range merges {
+				mergedAt, _ := time.Parse(time.RFC3339, merge.MergedAt)
+				entries = append(entries, ProvenanceEntry{
+					Operation:       ProvenanceMerge,
+					Time:            mergedAt,
+					PreviousContent: merge.PreviousContent,
+					IncomingContent: merge.IncomingContent,
+					Strategy:        merge.Strategy,
+				})
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	return entries
+}
+
+// withProvenance returns a copy of metadata with entry appended to its
+// provenance, after the provenance of the memories merged into it
+// (inherited, whose entries are attributed to them), keeping the last
+// maxProvenanceEntries entries.
+func withProvenance(metadata map[string]interface{}, entry ProvenanceEntry, inherited ...*storage.Memory) map[string]interface{} {
+	entries := recordedProvenance(metadata)
+	for _, memory := range inherited {
+		for _, inheritedEntry := range provenanceFromMetadata(memory.Metadata) {
+			if inheritedEntry.MemoryID == 0 {
+				inheritedEntry.MemoryID = memory.ID
+			}
+			entries = append(entries, inheritedEntry)
+		}
+	}
+	entries = append(entries, entry)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	if len(entries) > maxProvenanceEntries {
+		entries = entries[len(entries)-maxProvenanceEntries:]
+	}
+
+	result := copyMetadata(metadata)
+	result[provenanceKey] = provenanceValue(entries)
+	return result
+}
+
+// withoutProvenance returns a copy of metadata without the provenance of
+// the memory, which holds its previous contents.
+func withoutProvenance(metadata map[string]interface{}) map[string]interface{} {
+	result := copyMetadata(metadata)
+	delete(result, provenanceKey)
+	delete(result, mergeHistoryKey)
+	return result
+}
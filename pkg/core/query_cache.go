@@ -0,0 +1,126 @@
+package core
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultQueryCacheSize is the default QueryCacheConfig.Size.
+	defaultQueryCacheSize = 1000
+
+	// defaultQueryCacheTTL is the default QueryCacheConfig.TTLSeconds.
+	defaultQueryCacheTTL = time.Minute
+)
+
+// queryCache is a fixed-size LRU cache of query embeddings whose entries
+// expire after a TTL. It is safe for concurrent use.
+type queryCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// queryCacheEntry is a cached query embedding.
+type queryCacheEntry struct {
+	key       string
+	embedding []float64
+	expiresAt time.Time
+}
+
+// newQueryCache creates the cache of cfg, or returns nil if cfg does not
+// enable it.
+func newQueryCache(cfg *QueryCacheConfig) *queryCache {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	size := cfg.Size
+	if size == 0 {
+		size = defaultQueryCacheSize
+	}
+	ttl := defaultQueryCacheTTL
+	if cfg.TTLSeconds > 0 {
+		ttl = time.Duration(cfg.TTLSeconds * float64(time.Second))
+	}
+	return &queryCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// queryCacheKey returns the cache key of query embedded by route: the query
+// is lowercased and its whitespace collapsed, so that queries differing
+// only in case and spacing share an entry.
+func queryCacheKey(route *embedderRoute, query string) string {
+	name := ""
+	if route != nil {
+		name = route.name
+	}
+	return name + "\x00" + strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
+
+// get returns a copy of the embedding cached for key if it has not expired
+// at now, and marks it as recently used.
+func (c *queryCache) get(key string, now time.Time) ([]float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*queryCacheEntry)
+	if !now.Before(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return append([]float64(nil), entry.embedding...), true
+}
+
+// add caches embedding for key until now plus the TTL, evicting the least
+// recently used entry if the cache is full.
+func (c *queryCache) add(key string, embedding []float64, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	embedding = append([]float64(nil), embedding...)
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*queryCacheEntry)
+		entry.embedding = embedding
+		entry.expiresAt = now.Add(c.ttl)
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&queryCacheEntry{key: key, embedding: embedding, expiresAt: now.Add(c.ttl)})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*queryCacheEntry).key)
+	}
+}
+
+// embedQuery returns the embedding of a search query by the embedder of
+// route, from the query cache if it is enabled and holds the query.
+func (c *Client) embedQuery(ctx context.Context, route *embedderRoute, query string) ([]float64, bool, error) {
+	if c.queryCache == nil {
+		embedding, err := c.embedderOf(route).Embed(ctx, query)
+		return embedding, false, err
+	}
+	key := queryCacheKey(route, query)
+	if embedding, ok := c.queryCache.get(key, c.now()); ok {
+		return embedding, true, nil
+	}
+	embedding, err := c.embedderOf(route).Embed(ctx, query)
+	if err != nil {
+		return nil, false, err
+	}
+	c.queryCache.add(key, embedding, c.now())
+	return embedding, false, nil
+}
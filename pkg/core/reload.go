@@ -0,0 +1,207 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"reflect"
+	"time"
+)
+
+// Reload applies a new configuration to a running client without recreating
+// it, so that in-flight operations and streams keep running.
+//
+// The following settings can change at runtime:
+//   - llm: provider, model, API key, base URL and parameters
+//   - intelligence: enabling it, thresholds, decay and reinforcement rates,
+//     merge strategy, fact confidence and ranking
+//   - agent_memory
+//   - injection_guard
+//   - audit
+//   - staleness
+//   - query_cache: a changed configuration starts with an empty cache
+//   - language
+//
+// Changes to vector_store, embedder, embedder_routes, id_type, webhooks or
+// replication require a new client. Reload rejects them with a
+// *ValidationError (matching ErrInvalidConfig) and leaves the client
+// unchanged, as it does for a configuration that fails Validate.
+//
+// The new settings are applied once the operations currently holding the
+// client (including open streams) release it; later operations use them.
+//
+// Example:
+//
+//	config.Intelligence.DuplicateThreshold = 0.9
+//	if err := client.Reload(ctx, config); err != nil {
+//	    log.Printf("config not applied: %v", err)
+//	}
+func (c *Client) Reload(ctx context.Context, cfg *Config) error {
+	_, err := c.begin(ctx, "Reload")
+	if err != nil {
+		return err
+	}
+	defer c.end()
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if fields := restartRequiredFields(c.config, cfg); len(fields) > 0 {
+		return NewMemoryError("Reload", &ValidationError{Fields: fields})
+	}
+
+	// Build everything before swapping so that a failure leaves the client unchanged
+	next := &Client{storage: c.storage, llm: c.llm, embedder: c.embedder}
+	if !reflect.DeepEqual(c.config.LLM, cfg.LLM) {
+		llmProvider, err := newLLMProvider(cfg.LLM, cachedSecrets(cfg.Secrets))
+		if err != nil {
+			return NewMemoryError("Reload", err)
+		}
+		// The previous provider is not closed: a running Health check may
+		// still use it, and providers hold no resources of their own.
+		next.llm = llmProvider
+	}
+	if err := next.initIntelligence(cfg); err != nil {
+		return NewMemoryError("Reload", err)
+	}
+	guard, err := newInjectionGuard(cfg.InjectionGuard)
+	if err != nil {
+		return NewMemoryError("Reload", err)
+	}
+
+	if !reflect.DeepEqual(c.config.QueryCache, cfg.QueryCache) {
+		c.queryCache = newQueryCache(cfg.QueryCache)
+	}
+	c.config = cfg
+	c.llm = next.llm
+	c.dedupManager = next.dedupManager
+	c.ebbinghausManager = next.ebbinghausManager
+	c.intelligentManager = next.intelligentManager
+	c.injectionGuard = guard
+	return nil
+}
+
+// restartRequiredFields reports the settings that differ between current and
+// next but cannot be changed by Reload.
+func restartRequiredFields(current, next *Config) []*FieldError {
+	var fields []*FieldError
+	restart := func(field string) {
+		fields = append(fields, &FieldError{
+			Field:   field,
+			Message: "cannot be changed without recreating the client",
+		})
+	}
+
+	// Compare the effective settings, so that spelling out a default is not a change
+	currentStore, _ := resolveStoreConfig(current.VectorStore, current.Embedder.vectorDimensions())
+	nextStore, _ := resolveStoreConfig(next.VectorStore, next.Embedder.vectorDimensions())
+	if !reflect.DeepEqual(currentStore, nextStore) {
+		restart("vector_store")
+	}
+	if !reflect.DeepEqual(current.Embedder, next.Embedder) {
+		restart("embedder")
+	}
+	if !reflect.DeepEqual(current.EmbedderRoutes, next.EmbedderRoutes) {
+		restart("embedder_routes")
+	}
+	if current.IDType != next.IDType {
+		restart("id_type")
+	}
+	if current.NodeID != next.NodeID {
+		restart("node_id")
+	}
+	if !reflect.DeepEqual(current.Webhooks, next.Webhooks) {
+		restart("webhooks")
+	}
+	if !reflect.DeepEqual(current.Replication, next.Replication) {
+		restart("replication")
+	}
+	return fields
+}
+
+// WatchConfigFile watches a config file and applies its changes with Reload
+// in a background goroutine, until ctx is cancelled or the client is shut
+// down.
+//
+// The file is loaded with LoadConfigFromFile, so it may be YAML, TOML or
+// JSON and use environment variable interpolation. It is checked every
+// interval (default 2s, see WithWatchInterval); saving it in place or
+// replacing it both count as a change.
+//
+// An update that cannot be loaded or applied (invalid syntax, failed
+// validation, or a change that requires a restart) is passed to the error
+// handler set with WithReloadErrorHandler, or logged, and the client keeps
+// its current configuration. The watch continues, so fixing the file applies
+// it.
+//
+// Returns an error if the file cannot be read when the watch starts.
+//
+// Example:
+//
+//	err := client.WatchConfigFile(ctx, "powermem.yaml",
+//	    core.WithReloadErrorHandler(func(err error) {
+//	        log.Printf("rejected config update: %v", err)
+//	    }),
+//	)
+func (c *Client) WatchConfigFile(ctx context.Context, path string, opts ...WatchOption) error {
+	watchOpts := applyWatchOptions(opts)
+
+	last, err := os.ReadFile(path)
+	if err != nil {
+		return NewMemoryError("WatchConfigFile", err)
+	}
+
+	stopped := c.stopped()
+	go func() {
+		ticker := time.NewTicker(watchOpts.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-stopped:
+				return
+			case <-ticker.C:
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				// The file may be briefly missing while an editor replaces it
+				if !os.IsNotExist(err) {
+					watchOpts.OnError(NewMemoryError("WatchConfigFile", err))
+				}
+				continue
+			}
+			if bytes.Equal(data, last) {
+				continue
+			}
+			last = data
+
+			cfg, err := LoadConfigFromFile(path)
+			if err == nil {
+				err = c.Reload(ctx, cfg)
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					watchOpts.OnError(err)
+				}
+				continue
+			}
+			if watchOpts.OnReload != nil {
+				watchOpts.OnReload(cfg)
+			}
+		}
+	}()
+	return nil
+}
+
+// logReloadError is the default WatchConfigFile error handler.
+func logReloadError(err error) {
+	log.Printf("Failed to reload config: %v", err)
+}
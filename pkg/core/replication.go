@@ -0,0 +1,163 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	"github.com/oceanbase/powermem-go/pkg/storage/replication"
+)
+
+// SyncReport describes what Sync exchanged with the remote store.
+type SyncReport struct {
+	// Pushed is the number of local memory changes applied to the remote
+	// store.
+	Pushed int `json:"pushed"`
+
+	// Pulled is the number of remote memory changes applied to the local
+	// store.
+	Pulled int `json:"pulled"`
+
+	// Conflicts is the number of memories changed on both sides, settled by
+	// ReplicationConfig.Conflict.
+	Conflicts int `json:"conflicts"`
+
+	// Bulk is the number of bulk changes (DeleteAll, DeleteWhere, Reset,
+	// PurgeExpired, EraseUser) repeated on the other side, in both
+	// directions.
+	Bulk int `json:"bulk"`
+
+	// StartedAt and CompletedAt bound the sync.
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// Sync exchanges the memory changes made since the previous sync with the
+// remote store of Config.Replication: local changes are pushed to it and its
+// changes, made by other replicas or by clients of the remote store, are
+// pulled. Memories changed on both sides are settled by
+// ReplicationConfig.Conflict. See package replication.
+//
+// The remote store is connected on first use, so the client works offline:
+// Sync then fails, and the next Sync resumes from the same position.
+// Pulled memories are written to the local store directly, without events.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//
+// Returns the sync report, or ErrInvalidConfig if replication is not
+// configured.
+//
+// Example:
+//
+//	report, err := client.Sync(ctx)
+//	if err != nil {
+//	    log.Printf("offline, will retry: %v", err)
+//	}
+func (c *Client) Sync(ctx context.Context) (*SyncReport, error) {
+	ctx, err := c.begin(ctx, "Sync")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	cfg := c.config.Replication
+	dims := c.config.Embedder.vectorDimensions()
+	c.mu.RUnlock()
+	if cfg == nil {
+		return nil, NewMemoryError("Sync", fmt.Errorf("%w: replication is not configured", ErrInvalidConfig))
+	}
+
+	c.replication.mu.Lock()
+	defer c.replication.mu.Unlock()
+
+	if c.replication.replicator == nil {
+		remote, err := initStorage(cfg.Remote, dims)
+		if err != nil {
+			return nil, NewMemoryError("Sync", err)
+		}
+		replicator, err := replication.NewReplicator(&replication.Config{
+			Local:     c.storage,
+			Remote:    remote,
+			Conflict:  replication.ConflictPolicy(cfg.Conflict),
+			StatePath: cfg.StatePath,
+		})
+		if err != nil {
+			_ = remote.Close()
+			return nil, NewMemoryError("Sync", err)
+		}
+		c.replication.remote = remote
+		c.replication.replicator = replicator
+	}
+
+	report := &SyncReport{StartedAt: time.Now()}
+	result, err := c.replication.replicator.Sync(ctx)
+	if err != nil {
+		return nil, NewMemoryError("Sync", err)
+	}
+	report.Pushed = result.Pushed
+	report.Pulled = result.Pulled
+	report.Conflicts = result.Conflicts
+	report.Bulk = result.Bulk
+	report.CompletedAt = time.Now()
+	return report, nil
+}
+
+// StartSync runs Sync every interval in a background goroutine until ctx is
+// cancelled or the client is shut down.
+//
+// Sync errors, such as an unreachable remote store, are logged and do not
+// stop the routine.
+//
+// Example:
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	client.StartSync(ctx, time.Minute)
+func (c *Client) StartSync(ctx context.Context, interval time.Duration) {
+	stopped := c.stopped()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-stopped:
+				return
+			case <-ticker.C:
+				if _, err := c.Sync(ctx); err != nil && ctx.Err() == nil {
+					log.Printf("Failed to sync memories: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// closeReplication closes the remote store, if it was connected.
+func (c *Client) closeReplication() error {
+	c.replication.mu.Lock()
+	defer c.replication.mu.Unlock()
+
+	if c.replication.remote == nil {
+		return nil
+	}
+	err := c.replication.remote.Close()
+	c.replication.remote = nil
+	c.replication.replicator = nil
+	return err
+}
+
+// replicationState holds the remote store of Sync, connected on first use.
+type replicationState struct {
+	// mu serializes Sync and guards the fields below.
+	mu sync.Mutex
+
+	remote     storage.VectorStore
+	replicator *replication.Replicator
+}
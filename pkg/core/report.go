@@ -0,0 +1,258 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// UserReport is what the client knows about a user, as returned by
+// GenerateUserReport. It encodes to JSON, and Markdown renders it for the
+// user.
+type UserReport struct {
+	// UserID is the user of the report.
+	UserID string `json:"user_id"`
+
+	// GeneratedAt is when the report was generated.
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// TotalMemories is the number of memories of the user.
+	TotalMemories int `json:"total_memories"`
+
+	// Oldest and Newest are the creation times of the user's oldest and
+	// newest memories (nil if there are none).
+	Oldest *time.Time `json:"oldest,omitempty"`
+	Newest *time.Time `json:"newest,omitempty"`
+
+	// MemoryTypes and Tags count the memories of each memory type and tag,
+	// most frequent first. Memories without a memory type are counted
+	// under "".
+	MemoryTypes []TopicCount `json:"memory_types"`
+	Tags        []TopicCount `json:"tags,omitempty"`
+
+	// TopMemories are the memories with the highest retention strength,
+	// strongest first.
+	TopMemories []*Memory `json:"top_memories"`
+
+	// RecentChanges are the latest changes of the user's memories, oldest
+	// first (empty unless ChangeLog is enabled).
+	RecentChanges []*Change `json:"recent_changes,omitempty"`
+}
+
+// TopicCount is the number of memories of a memory type or tag in a
+// UserReport.
+type TopicCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// GenerateUserReport summarizes what the client knows about a user, e.g.
+// to answer a "what do you know about me" request: counts of the user's
+// memories by memory type and tag, the memories with the highest retention
+// strength, and the latest changes from the change log. Memories shared
+// with the user through teams, flagged memories and working memory items
+// are not reported.
+//
+// The report encodes to JSON; UserReport.Markdown renders it. Use
+// usermemory.Client.GenerateUserReport to include the user's profile.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userID: User of the report (required)
+//   - opts: Optional parameters (WithReportTopMemories, WithReportRecentChanges)
+//
+// Returns the report, or an error.
+//
+// Example:
+//
+//	report, err := client.GenerateUserReport(ctx, "user_001")
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(report.Markdown())
+func (c *Client) GenerateUserReport(ctx context.Context, userID string, opts ...ReportOption) (*UserReport, error) {
+	if userID == "" {
+		return nil, NewMemoryError("GenerateUserReport", fmt.Errorf("%w: user ID is required", ErrInvalidInput))
+	}
+
+	ctx, err := c.begin(ctx, "GenerateUserReport")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	reportOpts := applyReportOptions(opts)
+	report := &UserReport{UserID: userID, GeneratedAt: c.now()}
+	memoryTypes := make(map[string]int)
+	tags := make(map[string]int)
+	var memories []*Memory
+
+	storageOpts := &storage.GetAllOptions{UserID: userID, Limit: iterBatchSize, WithoutEmbeddings: true, Now: c.now()}
+	for {
+		page, err := c.storage.GetAll(ctx, storageOpts)
+		if err != nil {
+			return nil, NewMemoryError("GenerateUserReport", err)
+		}
+		for _, memory := range page {
+			if isFlagged(memory) || isWorking(memory) {
+				continue
+			}
+			m := fromStorageMemory(memory)
+			report.TotalMemories++
+			memoryTypes[m.MemoryType]++
+			for _, tag := range m.Tags {
+				tags[tag]++
+			}
+			createdAt := m.CreatedAt
+			if report.Oldest == nil || createdAt.Before(*report.Oldest) {
+				report.Oldest = &createdAt
+			}
+			if report.Newest == nil || createdAt.After(*report.Newest) {
+				report.Newest = &createdAt
+			}
+			memories = append(memories, m)
+		}
+		if len(page) < storageOpts.Limit {
+			break
+		}
+		storageOpts.Offset += len(page)
+	}
+	report.MemoryTypes = topicCounts(memoryTypes)
+	report.Tags = topicCounts(tags)
+
+	sort.SliceStable(memories, func(i, j int) bool {
+		if memories[i].RetentionStrength != memories[j].RetentionStrength {
+			return memories[i].RetentionStrength > memories[j].RetentionStrength
+		}
+		return memories[i].UpdatedAt.After(memories[j].UpdatedAt)
+	})
+	if len(memories) > reportOpts.TopMemories {
+		memories = memories[:reportOpts.TopMemories]
+	}
+	report.TopMemories = memories
+
+	if c.config.ChangeLog != nil && c.config.ChangeLog.Enabled && reportOpts.RecentChanges > 0 {
+		report.RecentChanges, err = c.recentChanges(ctx, userID, reportOpts.RecentChanges)
+		if err != nil {
+			return nil, NewMemoryError("GenerateUserReport", err)
+		}
+	}
+	return report, nil
+}
+
+// recentChanges returns the last limit changes of userID's memories in the
+// change log, oldest first.
+func (c *Client) recentChanges(ctx context.Context, userID string, limit int) ([]*Change, error) {
+	var changes []*Change
+	opts := &storage.ListChangesOptions{UserID: userID, Limit: iterBatchSize}
+	for {
+		stored, err := c.storage.ListChanges(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range stored {
+			change := &Change{Seq: s.Seq}
+			if err := json.Unmarshal(s.Payload, &change.Event); err != nil {
+				return nil, fmt.Errorf("invalid change %d: %w", s.Seq, err)
+			}
+			changes = append(changes, change)
+			opts.After = s.Seq
+		}
+		if len(changes) > limit {
+			changes = changes[len(changes)-limit:]
+		}
+		if len(stored) < opts.Limit {
+			return changes, nil
+		}
+	}
+}
+
+// topicCounts returns counts sorted by decreasing count, then by name.
+func topicCounts(counts map[string]int) []TopicCount {
+	topics := make([]TopicCount, 0, len(counts))
+	for name, count := range counts {
+		topics = append(topics, TopicCount{Name: name, Count: count})
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		if topics[i].Count != topics[j].Count {
+			return topics[i].Count > topics[j].Count
+		}
+		return topics[i].Name < topics[j].Name
+	})
+	return topics
+}
+
+// Markdown renders the report as a Markdown document for the user.
+func (r *UserReport) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# What we know about %s\n\n", r.UserID)
+	fmt.Fprintf(&b, "Generated on %s.\n\n", r.GeneratedAt.UTC().Format(time.RFC3339))
+
+	b.WriteString("## Memories\n\n")
+	if r.TotalMemories == 0 {
+		b.WriteString("No memories are stored.\n")
+	} else {
+		fmt.Fprintf(&b, "%d memories, stored between %s and %s.\n",
+			r.TotalMemories, r.Oldest.UTC().Format("2006-01-02"), r.Newest.UTC().Format("2006-01-02"))
+	}
+
+	if len(r.MemoryTypes) > 0 {
+		b.WriteString("\n## Topics\n\n| Memory type | Memories |\n|---|---|\n")
+		for _, topic := range r.MemoryTypes {
+			name := topic.Name
+			if name == "" {
+				name = "(none)"
+			}
+			fmt.Fprintf(&b, "| %s | %d |\n", markdownText(name), topic.Count)
+		}
+	}
+	if len(r.Tags) > 0 {
+		b.WriteString("\n| Tag | Memories |\n|---|---|\n")
+		for _, tag := range r.Tags {
+			fmt.Fprintf(&b, "| %s | %d |\n", markdownText(tag.Name), tag.Count)
+		}
+	}
+
+	if len(r.TopMemories) > 0 {
+		b.WriteString("\n## Most retained memories\n\n")
+		for _, memory := range r.TopMemories {
+			fmt.Fprintf(&b, "- %s (retention %.2f", markdownText(memory.Content), memory.RetentionStrength)
+			if memory.MemoryType != "" {
+				fmt.Fprintf(&b, ", %s", memory.MemoryType)
+			}
+			b.WriteString(")\n")
+		}
+	}
+
+	if len(r.RecentChanges) > 0 {
+		b.WriteString("\n## Recent changes\n\n")
+		for _, change := range r.RecentChanges {
+			fmt.Fprintf(&b, "- %s %s", change.Time.UTC().Format(time.RFC3339), change.Type)
+			switch {
+			case change.Diff != nil:
+				fmt.Fprintf(&b, ": %s → %s", markdownText(change.Diff.OldContent), markdownText(change.Diff.NewContent))
+			case change.Memory != nil:
+				fmt.Fprintf(&b, ": %s", markdownText(change.Memory.Content))
+			case change.Count > 0:
+				fmt.Fprintf(&b, ": %d memories", change.Count)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// markdownText returns text on a single line with the characters that
+// break Markdown lists and tables escaped.
+func markdownText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`").Replace(text)
+}
@@ -0,0 +1,204 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/oceanbase/powermem-go/pkg/embedder"
+	"github.com/oceanbase/powermem-go/pkg/llm"
+	"github.com/oceanbase/powermem-go/pkg/secrets"
+)
+
+// NewLLMProvider creates the LLM provider configured by cfg.LLM.
+//
+// If LLM.APIKeySecret is set, the API key is resolved through cfg.Secrets
+// and the provider is recreated whenever the secret changes, so rotated keys
+// are used without restarting. If LLM.Fallbacks is set, the provider fails
+// over between the primary and the fallbacks (see LLMRoutingConfig). Packages that need their own LLM instance,
+// such as user_memory, use it to share this behavior.
+func NewLLMProvider(cfg *Config) (llm.Provider, error) {
+	return newLLMProvider(cfg.LLM, cachedSecrets(cfg.Secrets))
+}
+
+// cachedSecrets wraps provider in a secrets.Cache unless it already is one.
+func cachedSecrets(provider secrets.Provider) secrets.Provider {
+	if provider == nil {
+		return nil
+	}
+	if _, ok := provider.(*secrets.Cache); ok {
+		return provider
+	}
+	return secrets.NewCache(provider, 0)
+}
+
+// newSingleLLMProvider creates the LLM provider for cfg, ignoring its
+// fallbacks and resolving APIKeySecret through secretsProvider.
+func newSingleLLMProvider(cfg LLMConfig, secretsProvider secrets.Provider) (llm.Provider, error) {
+	if cfg.APIKeySecret == "" {
+		return initLLM(cfg)
+	}
+	if secretsProvider == nil {
+		return nil, NewMemoryError("initLLM", ErrInvalidConfig)
+	}
+
+	provider := &secretLLM{cfg: cfg, secrets: secretsProvider}
+	// Resolve the key now so that a missing secret fails at startup
+	if _, err := provider.current(context.Background()); err != nil {
+		return nil, NewMemoryError("initLLM", err)
+	}
+	return provider, nil
+}
+
+// newEmbedderProvider creates the embedder for cfg, resolving APIKeySecret
+// through secretsProvider and truncating its embeddings to
+// TruncateDimensions.
+func newEmbedderProvider(cfg EmbedderConfig, secretsProvider secrets.Provider) (embedder.Provider, error) {
+	provider, err := newUntruncatedEmbedder(cfg, secretsProvider)
+	if err != nil || cfg.TruncateDimensions <= 0 {
+		return provider, err
+	}
+	return embedder.Truncate(provider, cfg.TruncateDimensions), nil
+}
+
+// newUntruncatedEmbedder creates the embedder of cfg, ignoring
+// TruncateDimensions.
+func newUntruncatedEmbedder(cfg EmbedderConfig, secretsProvider secrets.Provider) (embedder.Provider, error) {
+	if cfg.APIKeySecret == "" {
+		return initEmbedder(cfg)
+	}
+	if secretsProvider == nil {
+		return nil, NewMemoryError("initEmbedder", ErrInvalidConfig)
+	}
+
+	provider := &secretEmbedder{cfg: cfg, secrets: secretsProvider}
+	if _, err := provider.current(context.Background()); err != nil {
+		return nil, NewMemoryError("initEmbedder", err)
+	}
+	return provider, nil
+}
+
+// secretLLM is an llm.Provider whose API key is read from a secret. The
+// underlying provider is recreated when the secret value changes.
+type secretLLM struct {
+	cfg     LLMConfig
+	secrets secrets.Provider
+
+	// mu guards key and provider.
+	mu       sync.Mutex
+	key      string
+	provider llm.Provider
+}
+
+// current returns the provider for the current value of the secret.
+func (s *secretLLM) current(ctx context.Context) (llm.Provider, error) {
+	key, err := s.secrets.GetSecret(ctx, s.cfg.APIKeySecret)
+	if err != nil {
+		return nil, fmt.Errorf("resolve llm.api_key_secret %q: %w", s.cfg.APIKeySecret, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.provider == nil || key != s.key {
+		cfg := s.cfg
+		cfg.APIKey = key
+		provider, err := initLLM(cfg)
+		if err != nil {
+			return nil, err
+		}
+		s.provider, s.key = provider, key
+	}
+	return s.provider, nil
+}
+
+func (s *secretLLM) Generate(ctx context.Context, prompt string, opts ...llm.GenerateOption) (string, error) {
+	provider, err := s.current(ctx)
+	if err != nil {
+		return "", err
+	}
+	return provider.Generate(ctx, prompt, opts...)
+}
+
+func (s *secretLLM) GenerateWithMessages(ctx context.Context, messages []llm.Message, opts ...llm.GenerateOption) (string, error) {
+	provider, err := s.current(ctx)
+	if err != nil {
+		return "", err
+	}
+	return provider.GenerateWithMessages(ctx, messages, opts...)
+}
+
+func (s *secretLLM) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.provider == nil {
+		return nil
+	}
+	return s.provider.Close()
+}
+
+// secretEmbedder is an embedder.Provider whose API key is read from a
+// secret. The underlying provider is recreated when the secret value changes.
+type secretEmbedder struct {
+	cfg     EmbedderConfig
+	secrets secrets.Provider
+
+	// mu guards key and provider.
+	mu       sync.Mutex
+	key      string
+	provider embedder.Provider
+}
+
+// current returns the provider for the current value of the secret.
+func (s *secretEmbedder) current(ctx context.Context) (embedder.Provider, error) {
+	key, err := s.secrets.GetSecret(ctx, s.cfg.APIKeySecret)
+	if err != nil {
+		return nil, fmt.Errorf("resolve embedder.api_key_secret %q: %w", s.cfg.APIKeySecret, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.provider == nil || key != s.key {
+		cfg := s.cfg
+		cfg.APIKey = key
+		provider, err := initEmbedder(cfg)
+		if err != nil {
+			return nil, err
+		}
+		s.provider, s.key = provider, key
+	}
+	return s.provider, nil
+}
+
+func (s *secretEmbedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	provider, err := s.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Embed(ctx, text)
+}
+
+func (s *secretEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	provider, err := s.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.EmbedBatch(ctx, texts)
+}
+
+func (s *secretEmbedder) Dimensions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.provider == nil {
+		return s.cfg.Dimensions
+	}
+	return s.provider.Dimensions()
+}
+
+func (s *secretEmbedder) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.provider == nil {
+		return nil
+	}
+	return s.provider.Close()
+}
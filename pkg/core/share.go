@@ -0,0 +1,201 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// sharedFromKey is the metadata key holding the consent record of a shared
+// memory.
+const sharedFromKey = "shared_from"
+
+// ShareConsent records who allowed a memory to be shared with another user,
+// and why. It is kept with the copy made by ShareMemory.
+type ShareConsent struct {
+	// MemoryID is the ID of the shared memory.
+	MemoryID int64 `json:"memory_id,string"`
+
+	// UserID is the user the shared memory belongs to.
+	UserID string `json:"user_id"`
+
+	// GrantedBy is who consented to the sharing: the actor of the context
+	// of ShareMemory (see ContextWithActor) or the owner of the memory,
+	// unless set with WithShareGrantedBy.
+	GrantedBy string `json:"granted_by"`
+
+	// Purpose is why the memory was shared (see WithSharePurpose).
+	Purpose string `json:"purpose,omitempty"`
+
+	// GrantedAt is when the memory was shared.
+	GrantedAt time.Time `json:"granted_at"`
+}
+
+// metadata returns the metadata value of the consent. The memory ID is a
+// string, since JSON numbers cannot hold every int64.
+func (s *ShareConsent) metadata() map[string]interface{} {
+	value := map[string]interface{}{
+		"memory_id":  strconv.FormatInt(s.MemoryID, 10),
+		"user_id":    s.UserID,
+		"granted_by": s.GrantedBy,
+		"granted_at": s.GrantedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if s.Purpose != "" {
+		value["purpose"] = s.Purpose
+	}
+	return value
+}
+
+// shareConsentFromMetadata returns the consent recorded in metadata (nil if
+// none or if it is malformed).
+func shareConsentFromMetadata(metadata map[string]interface{}) *ShareConsent {
+	value, ok := metadata[sharedFromKey]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var consent ShareConsent
+	if err := json.Unmarshal(data, &consent); err != nil {
+		return nil
+	}
+	return &consent
+}
+
+// ShareMemory copies a memory to another user, e.g. to introduce a new team
+// member to what an assistant knows about a project. The copy belongs to
+// targetUserID and is independent of the original: later changes to either
+// are not propagated. It keeps the content, embedding, chunks, tags,
+// expiration and metadata of the original, except its team (see
+// WithTeamID), and records the consent in Memory.SharedFrom.
+//
+// With the audit log enabled (see AuditConfig), the sharing is recorded in
+// it first, and is not run if it cannot be recorded.
+//
+// Parameters:
+//   - ctx: Context for cancellation, carrying the actor
+//   - id: ID of the memory to share
+//   - targetUserID: User receiving the copy
+//   - opts: Optional parameters (UserID, AgentID, Purpose, GrantedBy)
+//
+// Returns the copy, ErrNotFound if the memory does not exist or does not
+// belong to WithUserIDForShare, and ErrInvalidInput without a target user,
+// for a chunk, or if the memory already belongs to the target user.
+//
+// Example:
+//
+//	ctx = core.ContextWithActor(ctx, "alice@example.com")
+//	copied, err := client.ShareMemory(ctx, memoryID, "bob",
+//	    core.WithUserIDForShare("alice"),
+//	    core.WithSharePurpose("onboarding on project X"))
+//	fmt.Println(copied.SharedFrom.GrantedBy) // alice@example.com
+func (c *Client) ShareMemory(ctx context.Context, id int64, targetUserID string, opts ...ShareOption) (*Memory, error) {
+	ctx, err := c.begin(ctx, "ShareMemory")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	events := c.recordEvents(ctx, "ShareMemory")
+	defer events.publish()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	shareOpts := applyShareOptions(opts)
+	if targetUserID == "" {
+		return nil, NewMemoryError("ShareMemory", fmt.Errorf("%w: target user ID is required", ErrInvalidInput))
+	}
+
+	source, err := c.storage.Get(ctx, id, &storage.GetOptions{UserID: shareOpts.UserID, Now: c.now()})
+	if err != nil {
+		return nil, NewMemoryError("ShareMemory", err)
+	}
+	if source.ParentID != 0 {
+		return nil, NewMemoryError("ShareMemory", fmt.Errorf("%w: memory %d is a chunk", ErrInvalidInput, id))
+	}
+	if source.UserID == targetUserID {
+		return nil, NewMemoryError("ShareMemory", fmt.Errorf("%w: memory %d already belongs to %s", ErrInvalidInput, id, targetUserID))
+	}
+
+	consent := &ShareConsent{
+		MemoryID:  source.ID,
+		UserID:    source.UserID,
+		GrantedBy: shareOpts.GrantedBy,
+		Purpose:   shareOpts.Purpose,
+		GrantedAt: c.now(),
+	}
+	if consent.GrantedBy == "" {
+		consent.GrantedBy = ActorFromContext(ctx)
+	}
+	if consent.GrantedBy == "" {
+		consent.GrantedBy = source.UserID
+	}
+
+	if err := c.audit(ctx, "ShareMemory", source.UserID, map[string]interface{}{
+		"memory_id":      id,
+		"target_user_id": targetUserID,
+		"granted_by":     consent.GrantedBy,
+		"purpose":        consent.Purpose,
+	}); err != nil {
+		return nil, NewMemoryError("ShareMemory", err)
+	}
+
+	// The copy is private to the target user
+	metadata := copyMetadata(source.Metadata)
+	if metadata[scopeKey] == string(ScopeTeam) {
+		delete(metadata, scopeKey)
+	}
+	delete(metadata, storage.TeamIDKey)
+	metadata[sharedFromKey] = consent.metadata()
+
+	uid, err := c.newUID()
+	if err != nil {
+		return nil, NewMemoryError("ShareMemory", err)
+	}
+	memory := &Memory{
+		ID:                c.snowflakeNode.Generate().Int64(),
+		UID:               uid,
+		Version:           1,
+		UserID:            targetUserID,
+		AgentID:           shareOpts.AgentID,
+		Content:           source.Content,
+		Embedding:         source.Embedding,
+		Metadata:          metadata,
+		RetentionStrength: 1.0,
+		Tags:              source.Tags,
+		ExpiresAt:         source.ExpiresAt,
+		Sources:           sourcesFromMetadata(metadata),
+		Entities:          entitiesFromMetadata(metadata),
+		SharedFrom:        consent,
+	}
+
+	chunks, err := c.getChunks(ctx, source)
+	if err != nil {
+		return nil, NewMemoryError("ShareMemory", err)
+	}
+
+	if err := c.insertNew(ctx, memory); err != nil {
+		return nil, NewMemoryError("ShareMemory", err)
+	}
+	if len(chunks) > 0 {
+		chunked := &chunkedContent{}
+		for _, chunk := range chunks {
+			chunked.chunks = append(chunked.chunks, chunk.Content)
+			chunked.embeddings = append(chunked.embeddings, chunk.Embedding)
+		}
+		if err := c.insertChunks(ctx, memory, chunked); err != nil {
+			_ = c.storage.Delete(ctx, memory.ID, nil)
+			return nil, NewMemoryError("ShareMemory", err)
+		}
+	}
+	events.add(&Event{Type: EventCreated, Memory: memory})
+
+	return memory, nil
+}
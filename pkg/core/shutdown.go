@@ -0,0 +1,166 @@
+// Package core provides the main PowerMem client and memory management functionality.
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+// lifecycle tracks the in-flight operations of a Client so that Shutdown can
+// stop accepting new operations and wait for the running ones.
+type lifecycle struct {
+	// mu guards the fields below.
+	mu sync.Mutex
+
+	// closing indicates that Shutdown or Close has been called.
+	closing bool
+
+	// active is the number of operations in flight.
+	active int
+
+	// idle is closed when closing is set and no operation is in flight.
+	idle chan struct{}
+
+	// stopping is closed when closing is set, to stop background routines.
+	stopping chan struct{}
+
+	// closeOnce makes sure the providers are closed once.
+	closeOnce sync.Once
+
+	// closeErr is the result of closing the providers.
+	closeErr error
+}
+
+// operationKey marks a context as belonging to an in-flight operation of the
+// client stored under it.
+type operationKey struct{}
+
+// begin registers an operation. Every public operation calls it first and
+// calls end when done, including the work of streams and iterators.
+//
+// Once the client is shutting down, new operations fail with ErrClientClosed.
+// Operations started by an in-flight operation (identified by the returned
+// context) are still accepted, so that a running batch can finish.
+func (c *Client) begin(ctx context.Context, op string) (context.Context, error) {
+	c.lifecycle.mu.Lock()
+	defer c.lifecycle.mu.Unlock()
+
+	if c.lifecycle.closing && ctx.Value(operationKey{}) != c {
+		return ctx, NewMemoryError(op, ErrClientClosed)
+	}
+
+	c.lifecycle.active++
+	if ctx.Value(operationKey{}) == c {
+		return ctx, nil
+	}
+	return context.WithValue(ctx, operationKey{}, c), nil
+}
+
+// end unregisters an operation registered with begin.
+func (c *Client) end() {
+	c.lifecycle.mu.Lock()
+	defer c.lifecycle.mu.Unlock()
+
+	c.lifecycle.active--
+	if c.lifecycle.closing && c.lifecycle.active == 0 && c.lifecycle.idle != nil {
+		close(c.lifecycle.idle)
+		c.lifecycle.idle = nil
+	}
+}
+
+// stopAccepting makes begin reject new operations and returns a channel that
+// is closed once no operation is in flight.
+func (c *Client) stopAccepting() <-chan struct{} {
+	c.lifecycle.mu.Lock()
+	defer c.lifecycle.mu.Unlock()
+
+	if !c.lifecycle.closing {
+		c.lifecycle.closing = true
+		if c.lifecycle.stopping != nil {
+			close(c.lifecycle.stopping)
+		}
+	}
+
+	if c.lifecycle.active == 0 {
+		idle := make(chan struct{})
+		close(idle)
+		return idle
+	}
+	if c.lifecycle.idle == nil {
+		c.lifecycle.idle = make(chan struct{})
+	}
+	return c.lifecycle.idle
+}
+
+// stopped returns a channel that is closed when the client starts shutting
+// down. Background routines such as StartExpirationPurge exit on it.
+func (c *Client) stopped() <-chan struct{} {
+	c.lifecycle.mu.Lock()
+	defer c.lifecycle.mu.Unlock()
+
+	if c.lifecycle.stopping == nil {
+		c.lifecycle.stopping = make(chan struct{})
+		if c.lifecycle.closing {
+			close(c.lifecycle.stopping)
+		}
+	}
+	return c.lifecycle.stopping
+}
+
+// Shutdown gracefully shuts the client down.
+//
+// It stops accepting new operations (they fail with ErrClientClosed), stops
+// background routines started with StartExpirationPurge, waits for in-flight
+// operations to finish, including streams and iterators that are still being
+// consumed, and then closes the storage and providers like Close.
+//
+// If ctx is done before the in-flight operations finish, Shutdown returns
+// ctx's error and leaves the providers open; call Close to release them
+// anyway. Calling Shutdown again resumes waiting.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	if err := client.Shutdown(ctx); err != nil {
+//	    log.Printf("shutdown: %v", err)
+//	    _ = client.Close()
+//	}
+func (c *Client) Shutdown(ctx context.Context) error {
+	select {
+	case <-c.stopAccepting():
+	case <-ctx.Done():
+		return NewMemoryError("Shutdown", ctx.Err())
+	}
+
+	return c.closeProviders()
+}
+
+// Shutdown gracefully shuts the async client down.
+//
+// It stops accepting new async operations, waits until the queued and running
+// ones have finished, and then shuts down the underlying Client (see
+// Client.Shutdown). If ctx is done first, Shutdown returns ctx's error; the
+// workers keep draining the queue in the background.
+func (ac *AsyncClient) Shutdown(ctx context.Context) error {
+	ac.closeMu.Lock()
+	if !ac.closed {
+		ac.closed = true
+		close(ac.tasks)
+	}
+	ac.closeMu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		ac.workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		return NewMemoryError("Shutdown", ctx.Err())
+	}
+
+	return ac.Client.Shutdown(ctx)
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// Snapshot backs up the memories of the collection of ctx at a point in
+// time to a new file at path, e.g. for nightly backups. It fails if path
+// exists.
+//
+// SQLite copies the database file, including the changes still in the WAL,
+// without blocking writers; the copy is itself a SQLite database.
+// PostgreSQL and OceanBase write a logical dump of the memory table, read
+// from the primary in a single repeatable read transaction.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - path: File the snapshot is written to
+//
+// Returns ErrInvalidInput without a path, and ErrInvalidConfig if the vector
+// store does not support snapshots (the routing store).
+//
+// Example:
+//
+//	path := fmt.Sprintf("backups/memories-%s.snapshot", time.Now().Format("20060102"))
+//	if err := client.Snapshot(ctx, path); err != nil {
+//	    return err
+//	}
+func (c *Client) Snapshot(ctx context.Context, path string) error {
+	ctx, err := c.begin(ctx, "Snapshot")
+	if err != nil {
+		return err
+	}
+	defer c.end()
+
+	if path == "" {
+		return NewMemoryError("Snapshot", fmt.Errorf("%w: snapshot path is required", ErrInvalidInput))
+	}
+	snapshotter, err := c.snapshotter("Snapshot")
+	if err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if err := snapshotter.Snapshot(ctx, path); err != nil {
+		return NewMemoryError("Snapshot", err)
+	}
+	return nil
+}
+
+// RestoreSnapshot replaces the memories of the collection of ctx with those
+// of a snapshot written by Snapshot with the same vector store provider, in
+// a single transaction. The change log and the audit log are kept.
+//
+// No events are published and no changes are logged for the restored
+// memories: consumers of the change feed should resync after a restore.
+// With the audit log enabled (see AuditConfig), the restore is recorded in
+// it first, and is not run if it cannot be recorded.
+//
+// WARNING: The memories added since the snapshot are lost.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - path: Snapshot file
+//
+// Returns ErrInvalidInput without a path, and ErrInvalidConfig if the vector
+// store does not support snapshots (the routing store).
+//
+// Example:
+//
+//	if err := client.RestoreSnapshot(ctx, "backups/memories-20260101.snapshot"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Client) RestoreSnapshot(ctx context.Context, path string) error {
+	ctx, err := c.begin(ctx, "RestoreSnapshot")
+	if err != nil {
+		return err
+	}
+	defer c.end()
+
+	if path == "" {
+		return NewMemoryError("RestoreSnapshot", fmt.Errorf("%w: snapshot path is required", ErrInvalidInput))
+	}
+	snapshotter, err := c.snapshotter("RestoreSnapshot")
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.audit(ctx, "RestoreSnapshot", "", map[string]interface{}{"path": path}); err != nil {
+		return NewMemoryError("RestoreSnapshot", err)
+	}
+	if err := snapshotter.RestoreSnapshot(ctx, path); err != nil {
+		return NewMemoryError("RestoreSnapshot", err)
+	}
+	return nil
+}
+
+// snapshotter returns the vector store of the client as a Snapshotter.
+func (c *Client) snapshotter(op string) (storage.Snapshotter, error) {
+	snapshotter, ok := c.storage.(storage.Snapshotter)
+	if !ok {
+		return nil, NewMemoryError(op, fmt.Errorf("%w: the %s vector store does not support snapshots", ErrInvalidConfig, c.config.VectorStore.resolvedProvider()))
+	}
+	return snapshotter, nil
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+)
+
+// sourcesKey is the metadata key holding the sources of a memory.
+const sourcesKey = "sources"
+
+// sourcesMetadata returns the metadata value of sources: a list of objects
+// with the non-empty fields of each source, as read back from the store.
+func sourcesMetadata(sources []Source) []interface{} {
+	value := make([]interface{}, len(sources))
+	for i, source := range sources {
+		fields := make(map[string]interface{}, 4)
+		if source.MessageID != "" {
+			fields["message_id"] = source.MessageID
+		}
+		if source.Turn != 0 {
+			fields["turn"] = source.Turn
+		}
+		if source.Document != "" {
+			fields["document"] = source.Document
+		}
+		if source.URL != "" {
+			fields["url"] = source.URL
+		}
+		value[i] = fields
+	}
+	return value
+}
+
+// sourcesFromMetadata returns the sources recorded in metadata (nil if none
+// or if they are malformed).
+func sourcesFromMetadata(metadata map[string]interface{}) []Source {
+	value, ok := metadata[sourcesKey]
+	if !ok {
+		return nil
+	}
+	if sources, ok := value.([]Source); ok {
+		return sources
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var sources []Source
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil
+	}
+	return sources
+}
+
+// mergeSources returns sources followed by the sources of more that are not
+// in sources.
+func mergeSources(sources []Source, more ...Source) []Source {
+	merged := append([]Source(nil), sources...)
+	for _, source := range more {
+		found := false
+		for _, existing := range merged {
+			if existing == source {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, source)
+		}
+	}
+	return merged
+}
+
+// attribution gives the sources of the facts extracted by IntelligentAdd.
+type attribution struct {
+	// base are the sources set with WithSource.
+	base []Source
+
+	// messages are the sources of the messages, by turn.
+	messages []Source
+
+	// facts are the message numbers of each fact, as given by the LLM.
+	facts map[string][]int
+}
+
+// newAttribution returns the attribution of facts extracted from messages.
+func newAttribution(messages interface{}, facts []intelligence.Fact, base []Source) *attribution {
+	a := &attribution{base: base, messages: messageSources(messages), facts: make(map[string][]int, len(facts))}
+	for _, fact := range facts {
+		a.facts[fact.Text] = fact.Messages
+	}
+	return a
+}
+
+// sources returns the sources of the memory text: the messages of the fact
+// with this text, or all messages if the LLM did not say which messages the
+// fact comes from (e.g. the text of an UPDATE combining facts).
+func (a *attribution) sources(text string) []Source {
+	var messages []Source
+	for _, n := range a.facts[text] {
+		if n >= 1 && n <= len(a.messages) {
+			messages = append(messages, a.messages[n-1])
+		}
+	}
+	if len(messages) == 0 {
+		messages = a.messages
+	}
+	return mergeSources(a.base, messages...)
+}
+
+// messageSources returns the source of each message passed to IntelligentAdd.
+// A single string has no message source.
+func messageSources(messages interface{}) []Source {
+	switch v := messages.(type) {
+	case []map[string]interface{}:
+		sources := make([]Source, len(v))
+		for i, message := range v {
+			sources[i] = Source{MessageID: messageID(message), Turn: i + 1}
+		}
+		return sources
+	case map[string]interface{}:
+		return []Source{{MessageID: messageID(v), Turn: 1}}
+	default:
+		return nil
+	}
+}
+
+// messageID returns the "id" or "message_id" of message.
+func messageID(message map[string]interface{}) string {
+	for _, key := range []string{"id", "message_id"} {
+		switch id := message[key].(type) {
+		case nil:
+		case string:
+			return id
+		case float64:
+			return fmt.Sprintf("%.0f", id)
+		default:
+			return fmt.Sprint(id)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+const (
+	// verifiedAtKey is the metadata key of the time a memory was last
+	// confirmed by ConfirmMemory, in RFC 3339 format.
+	verifiedAtKey = "verified_at"
+
+	// defaultStaleMaxAgeDays is the default StalenessConfig.MaxAgeDays.
+	defaultStaleMaxAgeDays = 90
+)
+
+// defaultStaleMemoryTypes are the default StalenessConfig.MemoryTypes.
+var defaultStaleMemoryTypes = []string{MemoryTypePreference, MemoryTypeFact}
+
+// verificationPrompt is the Memory.VerificationPrompt of a stale memory,
+// formatted with its age in days and its content.
+const verificationPrompt = `This memory was last confirmed %d days ago and may be outdated: %q. Ask the user whether it is still true before relying on it.`
+
+// stalenessPolicy is a StalenessConfig with its defaults applied.
+type stalenessPolicy struct {
+	maxAge      time.Duration
+	memoryTypes map[string]bool
+	verify      bool
+	now         time.Time
+}
+
+// staleness returns the staleness policy of the client at the current time,
+// or nil if it is not enabled.
+func (c *Client) staleness() *stalenessPolicy {
+	cfg := c.config.Staleness
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	maxAgeDays := cfg.MaxAgeDays
+	if maxAgeDays == 0 {
+		maxAgeDays = defaultStaleMaxAgeDays
+	}
+	memoryTypes := cfg.MemoryTypes
+	if len(memoryTypes) == 0 {
+		memoryTypes = defaultStaleMemoryTypes
+	}
+	policy := &stalenessPolicy{
+		maxAge:      time.Duration(maxAgeDays * float64(24*time.Hour)),
+		memoryTypes: make(map[string]bool, len(memoryTypes)),
+		verify:      cfg.VerificationPrompt,
+		now:         c.now(),
+	}
+	for _, memoryType := range memoryTypes {
+		policy.memoryTypes[memoryType] = true
+	}
+	return policy
+}
+
+// mark sets Stale, and VerificationPrompt if enabled, on memory if it is of
+// a memory type that goes stale and was last confirmed longer than the
+// maximum age ago. It does nothing on a nil policy.
+func (p *stalenessPolicy) mark(memory *Memory) {
+	if p == nil || !p.memoryTypes[memory.MemoryType] {
+		return
+	}
+	confirmed := lastConfirmed(memory)
+	if confirmed.IsZero() || p.now.Sub(confirmed) < p.maxAge {
+		return
+	}
+	memory.Stale = true
+	if p.verify {
+		days := int(math.Round(p.now.Sub(confirmed).Hours() / 24))
+		memory.VerificationPrompt = fmt.Sprintf(verificationPrompt, days, memory.Content)
+	}
+}
+
+// lastConfirmed returns when memory was last known to be true: the latest
+// of its update time and metadata["verified_at"], or its creation time if
+// it has neither.
+func lastConfirmed(memory *Memory) time.Time {
+	confirmed := memory.UpdatedAt
+	if confirmed.IsZero() {
+		confirmed = memory.CreatedAt
+	}
+	if value := metadataString(memory.Metadata, verifiedAtKey); value != "" {
+		if verified, err := time.Parse(time.RFC3339, value); err == nil && verified.After(confirmed) {
+			confirmed = verified
+		}
+	}
+	return confirmed
+}
+
+// ConfirmMemory records that a memory is still true, typically after the
+// user answered the VerificationPrompt of a stale search result, so that it
+// is no longer stale (see StalenessConfig). The time is stored in
+// metadata["verified_at"]; the content, embedding and retention are kept.
+// Use Update instead if the user corrected the memory.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - id: Memory ID
+//   - opts: Optional access control (WithUserIDForUpdate, WithAgentIDForUpdate)
+//
+// Returns the confirmed memory, or an error wrapping storage.ErrNotFound if
+// the memory does not exist or is not accessible.
+//
+// Example:
+//
+//	for _, memory := range results {
+//	    if memory.Stale && userConfirms(memory.VerificationPrompt) {
+//	        _, err := client.ConfirmMemory(ctx, memory.ID, core.WithUserIDForUpdate("user_001"))
+//	    }
+//	}
+func (c *Client) ConfirmMemory(ctx context.Context, id int64, opts ...UpdateOption) (*Memory, error) {
+	ctx, err := c.begin(ctx, "ConfirmMemory")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	events := c.recordEvents(ctx, "ConfirmMemory")
+	defer events.publish()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	updateOpts := applyUpdateOptions(opts)
+	existing, err := c.storage.Get(ctx, id, &storage.GetOptions{
+		UserID:  updateOpts.UserID,
+		AgentID: updateOpts.AgentID,
+		Now:     c.now(),
+	})
+	if err != nil {
+		return nil, NewMemoryError("ConfirmMemory", err)
+	}
+
+	metadata := copyMetadata(existing.Metadata)
+	metadata[verifiedAtKey] = c.now().UTC().Format(time.RFC3339)
+	memory, err := c.storage.Update(ctx, id, existing.Content, existing.Embedding, &storage.UpdateOptions{
+		UserID:          updateOpts.UserID,
+		AgentID:         updateOpts.AgentID,
+		Metadata:        metadata,
+		ExpectedVersion: existing.Version,
+		Now:             c.now(),
+	})
+	if err != nil {
+		return nil, NewMemoryError("ConfirmMemory", err)
+	}
+	// Keep the metadata of the chunks in sync for filtered searches
+	if err := c.updateChunkMetadata(ctx, memory); err != nil {
+		return nil, NewMemoryError("ConfirmMemory", err)
+	}
+
+	confirmed := fromStorageMemory(memory)
+	events.add(&Event{Type: EventUpdated, Memory: confirmed, Diff: newEventDiff(fromStorageMemory(existing), confirmed)})
+	return confirmed, nil
+}
@@ -0,0 +1,902 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/oceanbase/powermem-go/pkg/storage/routing"
+)
+
+// defaultEmbeddingModelDims is the vector dimension used when neither the
+// vector store config nor EmbedderConfig.Dimensions sets one.
+const defaultEmbeddingModelDims = 1536
+
+// SQLiteConfig contains the settings of the "sqlite" vector store.
+//
+// Zero fields use the defaults below.
+type SQLiteConfig struct {
+	// DBPath is the path of the database file. Default: "./powermem.db"
+	DBPath string `json:"db_path,omitempty"`
+
+	// CollectionName is the name of the memories table. Default: "memories"
+	CollectionName string `json:"collection_name,omitempty"`
+
+	// EmbeddingModelDims is the dimension of the stored vectors.
+	// Default: EmbedderConfig.Dimensions, or 1536 if that is not set either
+	EmbeddingModelDims int `json:"embedding_model_dims,omitempty"`
+
+	// BusyTimeoutSeconds is how long a statement waits for a lock held by
+	// another connection or process. Default: 0 (the driver default, 5s)
+	BusyTimeoutSeconds float64 `json:"busy_timeout_seconds,omitempty"`
+
+	// BusyRetries is the number of retries of writes that still find the
+	// database locked after the busy timeout; negative disables retries.
+	// Default: 3
+	BusyRetries int `json:"busy_retries,omitempty"`
+
+	// Synchronous is the synchronous mode: OFF, NORMAL, FULL or EXTRA.
+	// NORMAL is faster and safe from corruption in WAL mode, but may lose the
+	// last writes on power loss. Default: empty (the SQLite default, FULL)
+	Synchronous string `json:"synchronous,omitempty"`
+
+	// CheckpointIntervalSeconds is the interval of background WAL
+	// checkpoints. Default: 0 (automatic checkpoints only)
+	CheckpointIntervalSeconds float64 `json:"checkpoint_interval_seconds,omitempty"`
+
+	// Locking is how processes sharing the database file coordinate their
+	// writes: "none" (SQLite locks only), "advisory" (writers queue on a
+	// lock file) or "single_writer" (this client is the only writer, others
+	// fail with sqlite.ErrWriterLocked). Default: "none"
+	Locking string `json:"locking,omitempty"`
+
+	// SearchWorkers is the number of goroutines computing the similarity
+	// scores of searches. Default: 0 (one per CPU)
+	SearchWorkers int `json:"search_workers,omitempty"`
+
+	// Clusters, if positive, is the number of k-means centroids trained
+	// once the collection has 40 memories per centroid, to search only the
+	// memories of the nearest clusters. Searches become approximate.
+	// Default: 0 (exact searches)
+	Clusters int `json:"clusters,omitempty"`
+
+	// ProbeClusters is the number of clusters searched; more clusters
+	// improve recall but slow searches. Default: 0 (a tenth of the clusters)
+	ProbeClusters int `json:"probe_clusters,omitempty"`
+
+	// VectorCache keeps the embeddings in a memory-mapped file next to the
+	// database, <db_path>.<collection_name>.vectors, which searches read
+	// instead of the embedding column (Unix only). Default: false
+	VectorCache bool `json:"vector_cache,omitempty"`
+}
+
+// OceanBaseConfig contains the settings of the "oceanbase" vector store.
+//
+// Zero fields use the defaults below.
+type OceanBaseConfig struct {
+	// Host is the server host. Default: "127.0.0.1"
+	Host string `json:"host,omitempty"`
+
+	// Port is the server port (1-65535). Default: 2881
+	Port int `json:"port,omitempty"`
+
+	// User is the database user. Default: "root@sys"
+	User string `json:"user,omitempty"`
+
+	// Password is the database password. Default: empty
+	Password string `json:"password,omitempty"`
+
+	// DBName is the database name. Default: "powermem"
+	DBName string `json:"db_name,omitempty"`
+
+	// CollectionName is the name of the memories table. Default: "memories"
+	CollectionName string `json:"collection_name,omitempty"`
+
+	// EmbeddingModelDims is the dimension of the stored vectors.
+	// Default: EmbedderConfig.Dimensions, or 1536 if that is not set either
+	EmbeddingModelDims int `json:"embedding_model_dims,omitempty"`
+
+	// DSN is the go-sql-driver/mysql data source name of the primary, used
+	// instead of Host, Port, User, Password and DBName if set. It must
+	// enable parseTime. Default: empty
+	DSN string `json:"dsn,omitempty"`
+
+	// ReadDSNs are the data source names of read replicas. Searches and
+	// other reads are spread over them; see ContextWithPrimaryRead.
+	// Default: none, reads go to the primary
+	ReadDSNs []string `json:"read_dsns,omitempty"`
+
+	// DisableVectorIndex turns off the creation of an HNSW index on the
+	// embeddings when the client starts. Without a vector index, searches
+	// compute exact distances, which is slow on large tables. The index is
+	// not created either on OceanBase versions without vector index support.
+	// Default: false, the index is created if the table has none
+	DisableVectorIndex bool `json:"disable_vector_index,omitempty"`
+
+	// HNSWM is the maximum number of connections per node of the HNSW
+	// index. Higher values improve recall but use more memory. Default: 16
+	HNSWM int `json:"hnsw_m,omitempty"`
+
+	// HNSWEfConstruction is the search depth used to build the HNSW index.
+	// Higher values improve the index but slow inserts. Default: 200
+	HNSWEfConstruction int `json:"hnsw_ef_construction,omitempty"`
+
+	// HNSWEfSearch is the search depth of searches using the HNSW index;
+	// see WithEfSearch to set it per search. Higher values improve recall
+	// but slow searches. Default: 0, the depth of the index (64)
+	HNSWEfSearch int `json:"hnsw_ef_search,omitempty"`
+
+	// FullTextParser is the parser of the FULLTEXT index created on the
+	// memory content for RetrievalModeHybrid, e.g. "ngram" or "ik" for
+	// Chinese text. Default: empty, the server default (words separated by
+	// spaces and punctuation)
+	FullTextParser string `json:"fulltext_parser,omitempty"`
+
+	// SSLMode is the TLS mode of the connections, named after the MySQL
+	// client's --ssl-mode: disabled, required (encrypted, server not
+	// verified), verify_ca or verify_identity. It also applies to DSN and
+	// ReadDSNs. Default: "disabled"
+	SSLMode string `json:"ssl_mode,omitempty"`
+
+	// SSLCA is the PEM file of the CAs that sign the server certificate.
+	// Default: empty, the system roots
+	SSLCA string `json:"ssl_ca,omitempty"`
+
+	// SSLCert and SSLKey are the PEM files of the client certificate and
+	// key, for clusters that require one. Both or neither must be set.
+	// Default: empty
+	SSLCert string `json:"ssl_cert,omitempty"`
+	SSLKey  string `json:"ssl_key,omitempty"`
+
+	// Charset is the character set of the connections. Default: empty, the
+	// charset of the DSN or the server default
+	Charset string `json:"charset,omitempty"`
+
+	// ConnectTimeoutSeconds, ReadTimeoutSeconds and WriteTimeoutSeconds are
+	// the dial, read and write timeouts of the connections. Default: 0, no
+	// timeout
+	ConnectTimeoutSeconds float64 `json:"connect_timeout_seconds,omitempty"`
+	ReadTimeoutSeconds    float64 `json:"read_timeout_seconds,omitempty"`
+	WriteTimeoutSeconds   float64 `json:"write_timeout_seconds,omitempty"`
+}
+
+// PostgresConfig contains the settings of the "postgres" vector store.
+//
+// Zero fields use the defaults below.
+type PostgresConfig struct {
+	// Host is the server host. Default: "localhost"
+	Host string `json:"host,omitempty"`
+
+	// Port is the server port (1-65535). Default: 5432
+	Port int `json:"port,omitempty"`
+
+	// User is the database user. Default: "postgres"
+	User string `json:"user,omitempty"`
+
+	// Password is the database password. Default: empty
+	Password string `json:"password,omitempty"`
+
+	// DBName is the database name. Default: "powermem"
+	DBName string `json:"db_name,omitempty"`
+
+	// CollectionName is the name of the memories table. Default: "memories"
+	CollectionName string `json:"collection_name,omitempty"`
+
+	// EmbeddingModelDims is the dimension of the stored vectors.
+	// Default: EmbedderConfig.Dimensions, or 1536 if that is not set either
+	EmbeddingModelDims int `json:"embedding_model_dims,omitempty"`
+
+	// SSLMode is the libpq sslmode: disable, allow, prefer, require,
+	// verify-ca or verify-full. Default: "disable"
+	SSLMode string `json:"ssl_mode,omitempty"`
+
+	// DSN is the libpq connection string of the primary, used instead of
+	// Host, Port, User, Password, DBName and SSLMode if set. Default: empty
+	DSN string `json:"dsn,omitempty"`
+
+	// ReadDSNs are the connection strings of read replicas. Searches and
+	// other reads are spread over them; see ContextWithPrimaryRead.
+	// Default: none, reads go to the primary
+	ReadDSNs []string `json:"read_dsns,omitempty"`
+
+	// MaxReplicaLagSeconds is the maximum replication lag of a replica used
+	// for reads; more lagging replicas are skipped until they catch up.
+	// Default: 0, no limit
+	MaxReplicaLagSeconds float64 `json:"max_replica_lag_seconds,omitempty"`
+
+	// Quantization indexes quantized embeddings to shrink the vector index:
+	// "halfvec" (half precision, 2x smaller) or "bit" (binary, 32x smaller).
+	// Searches fetch candidates from that index and re-rank them by exact
+	// similarity. Requires pgvector 0.7 or later. Default: "none"
+	Quantization string `json:"quantization,omitempty"`
+
+	// RerankFactor is the number of candidates re-ranked per requested
+	// result with Quantization. Higher values improve recall but slow
+	// searches. Default: 0, 4 for halfvec and 10 for bit
+	RerankFactor int `json:"rerank_factor,omitempty"`
+}
+
+// RoutingConfig contains the settings of the "routing" vector store, which
+// keeps the memories of each user in one of several stores, e.g. in the
+// user's region for data residency. See package routing.
+//
+// Users are routed by Users, then by Policy, and go to the Default store
+// otherwise. Operations without a user, such as Get by ID, run on every
+// store.
+//
+// Example:
+//
+//	VectorStore: core.VectorStoreConfig{
+//	    Provider: "routing",
+//	    Routing: &core.RoutingConfig{
+//	        Stores: map[string]core.VectorStoreConfig{
+//	            "eu": {Provider: "postgres", Postgres: &core.PostgresConfig{Host: "pg.eu.example.com"}},
+//	            "us": {Provider: "postgres", Postgres: &core.PostgresConfig{Host: "pg.us.example.com"}},
+//	        },
+//	        Default: "us",
+//	        Policy: func(ctx context.Context, userID string) (string, error) {
+//	            return accounts.Region(ctx, userID) // "eu", "us" or "" for the default
+//	        },
+//	    },
+//	}
+type RoutingConfig struct {
+	// Stores are the routed stores by name (e.g. "eu", "us"). Required;
+	// stores cannot be routing stores themselves.
+	Stores map[string]VectorStoreConfig `json:"stores"`
+
+	// Default is the name of the store of users without a route. Required.
+	Default string `json:"default"`
+
+	// Users maps user IDs to store names (optional).
+	Users map[string]string `json:"users,omitempty"`
+
+	// ChangeLogStore is the name of the store holding the change log (see
+	// ChangeLogConfig), whose entries contain memory content.
+	// Default: Default
+	ChangeLogStore string `json:"change_log_store,omitempty"`
+
+	// Policy routes the users not listed in Users (optional). It must route
+	// a user to the same store as long as the user has memories.
+	Policy routing.Policy `json:"-"`
+}
+
+// resolvedRoutingConfig is the resolved form of a RoutingConfig.
+type resolvedRoutingConfig struct {
+	// Stores are the resolved typed settings of the stores.
+	Stores         map[string]interface{}
+	Default        string
+	Users          map[string]string
+	ChangeLogStore string
+}
+
+// postgresSSLModes are the accepted values of PostgresConfig.SSLMode.
+var postgresSSLModes = map[string]bool{
+	"disable": true, "allow": true, "prefer": true,
+	"require": true, "verify-ca": true, "verify-full": true,
+}
+
+// oceanBaseSSLModes are the accepted values of OceanBaseConfig.SSLMode.
+var oceanBaseSSLModes = map[string]bool{
+	"disabled": true, "required": true, "verify_ca": true, "verify_identity": true,
+}
+
+// sqliteSynchronousModes are the accepted values of SQLiteConfig.Synchronous.
+var sqliteSynchronousModes = map[string]bool{"OFF": true, "NORMAL": true, "FULL": true, "EXTRA": true}
+
+// sqliteLockingModes are the accepted values of SQLiteConfig.Locking.
+var sqliteLockingModes = map[string]bool{"none": true, "advisory": true, "single_writer": true}
+
+// postgresQuantizations are the accepted values of PostgresConfig.Quantization.
+var postgresQuantizations = map[string]bool{"none": true, "halfvec": true, "bit": true}
+
+// vectorStoreJSON is the JSON form of VectorStoreConfig.
+type vectorStoreJSON struct {
+	Provider string      `json:"provider"`
+	Config   interface{} `json:"config,omitempty"`
+}
+
+// MarshalJSON encodes the settings of the selected provider under "config".
+func (c VectorStoreConfig) MarshalJSON() ([]byte, error) {
+	out := vectorStoreJSON{Provider: c.Provider}
+	switch c.resolvedProvider() {
+	case "sqlite":
+		if c.SQLite != nil {
+			out.Config = c.SQLite
+		}
+	case "oceanbase":
+		if c.OceanBase != nil {
+			out.Config = c.OceanBase
+		}
+	case "postgres":
+		if c.Postgres != nil {
+			out.Config = c.Postgres
+		}
+	case "routing":
+		if c.Routing != nil {
+			out.Config = c.Routing
+		}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes "config" into the typed settings of "provider".
+//
+// Numbers are accepted for int fields as long as they are whole, and keys
+// not known to the provider are ignored. Values of the wrong type are
+// reported as a *ValidationError naming the field.
+func (c *VectorStoreConfig) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Provider string                 `json:"provider"`
+		Config   map[string]interface{} `json:"config"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*c = VectorStoreConfig{Provider: raw.Provider}
+
+	var typed interface{}
+	switch raw.Provider {
+	case "sqlite":
+		c.SQLite = &SQLiteConfig{}
+		typed = c.SQLite
+	case "oceanbase":
+		c.OceanBase = &OceanBaseConfig{}
+		typed = c.OceanBase
+	case "postgres":
+		c.Postgres = &PostgresConfig{}
+		typed = c.Postgres
+	case "routing":
+		var errs []*FieldError
+		c.Routing, errs = decodeRoutingConfig(raw.Config)
+		if len(errs) > 0 {
+			return &ValidationError{Fields: errs}
+		}
+		return nil
+	default:
+		// Reported by Validate
+		return nil
+	}
+
+	if errs := decodeConfigMap(raw.Config, typed, "vector_store.config"); len(errs) > 0 {
+		return &ValidationError{Fields: errs}
+	}
+	return nil
+}
+
+// resolvedProvider returns Provider, or the provider of the only typed
+// field that is set if Provider is empty.
+func (c VectorStoreConfig) resolvedProvider() string {
+	if c.Provider != "" {
+		return c.Provider
+	}
+
+	var providers []string
+	if c.SQLite != nil {
+		providers = append(providers, "sqlite")
+	}
+	if c.OceanBase != nil {
+		providers = append(providers, "oceanbase")
+	}
+	if c.Postgres != nil {
+		providers = append(providers, "postgres")
+	}
+	if c.Routing != nil {
+		providers = append(providers, "routing")
+	}
+	if len(providers) == 1 {
+		return providers[0]
+	}
+	return ""
+}
+
+// resolveStoreConfig returns a copy of the typed settings of cfg's provider
+// with the defaults applied, after validating them.
+//
+// The result is a *SQLiteConfig, *OceanBaseConfig, *PostgresConfig or
+// *resolvedRoutingConfig.
+func resolveStoreConfig(cfg VectorStoreConfig, embedderDims int) (interface{}, []*FieldError) {
+	dims := defaultEmbeddingModelDims
+	if embedderDims > 0 {
+		dims = embedderDims
+	}
+
+	provider := cfg.resolvedProvider()
+
+	var errs []*FieldError
+	for _, other := range []struct {
+		provider string
+		set      bool
+	}{
+		{"sqlite", cfg.SQLite != nil},
+		{"oceanbase", cfg.OceanBase != nil},
+		{"postgres", cfg.Postgres != nil},
+		{"routing", cfg.Routing != nil},
+	} {
+		if other.set && other.provider != provider && provider != "" {
+			errs = append(errs, &FieldError{
+				Field:   "vector_store.provider",
+				Message: fmt.Sprintf("is %q but %s settings are set", provider, other.provider),
+			})
+		}
+	}
+
+	var typed interface{}
+	switch provider {
+	case "sqlite":
+		c := SQLiteConfig{}
+		if cfg.SQLite != nil {
+			c = *cfg.SQLite
+		}
+		defaultString(&c.DBPath, "./powermem.db")
+		defaultString(&c.CollectionName, "memories")
+		defaultInt(&c.EmbeddingModelDims, dims)
+		defaultInt(&c.BusyRetries, 3)
+
+		errs = checkPositive(errs, "embedding_model_dims", c.EmbeddingModelDims)
+		errs = checkTimeout(errs, "busy_timeout_seconds", c.BusyTimeoutSeconds)
+		errs = checkTimeout(errs, "checkpoint_interval_seconds", c.CheckpointIntervalSeconds)
+		if c.Synchronous != "" && !sqliteSynchronousModes[strings.ToUpper(c.Synchronous)] {
+			errs = append(errs, &FieldError{
+				Field:   "vector_store.config.synchronous",
+				Message: fmt.Sprintf("unknown synchronous mode %q (want OFF, NORMAL, FULL or EXTRA)", c.Synchronous),
+			})
+		}
+		defaultString(&c.Locking, "none")
+		if !sqliteLockingModes[c.Locking] {
+			errs = append(errs, &FieldError{
+				Field:   "vector_store.config.locking",
+				Message: fmt.Sprintf("unknown locking mode %q (want none, advisory or single_writer)", c.Locking),
+			})
+		}
+		errs = checkNonNegative(errs, "clusters", c.Clusters)
+		errs = checkNonNegative(errs, "probe_clusters", c.ProbeClusters)
+		typed = &c
+	case "oceanbase":
+		c := OceanBaseConfig{}
+		if cfg.OceanBase != nil {
+			c = *cfg.OceanBase
+		}
+		defaultString(&c.Host, "127.0.0.1")
+		defaultInt(&c.Port, 2881)
+		defaultString(&c.User, "root@sys")
+		defaultString(&c.DBName, "powermem")
+		defaultString(&c.CollectionName, "memories")
+		defaultInt(&c.EmbeddingModelDims, dims)
+		defaultInt(&c.HNSWM, 16)
+		defaultInt(&c.HNSWEfConstruction, 200)
+
+		errs = checkPort(errs, c.Port)
+		errs = checkPositive(errs, "embedding_model_dims", c.EmbeddingModelDims)
+		errs = checkReadDSNs(errs, c.ReadDSNs)
+		errs = checkPositive(errs, "hnsw_m", c.HNSWM)
+		errs = checkPositive(errs, "hnsw_ef_construction", c.HNSWEfConstruction)
+		errs = checkNonNegative(errs, "hnsw_ef_search", c.HNSWEfSearch)
+		defaultString(&c.SSLMode, "disabled")
+		if !oceanBaseSSLModes[c.SSLMode] {
+			errs = append(errs, &FieldError{
+				Field:   "vector_store.config.ssl_mode",
+				Message: fmt.Sprintf("unknown ssl mode %q", c.SSLMode),
+			})
+		}
+		if (c.SSLCert == "") != (c.SSLKey == "") {
+			errs = append(errs, &FieldError{
+				Field:   "vector_store.config.ssl_cert",
+				Message: "must be set together with ssl_key",
+			})
+		}
+		errs = checkTimeout(errs, "connect_timeout_seconds", c.ConnectTimeoutSeconds)
+		errs = checkTimeout(errs, "read_timeout_seconds", c.ReadTimeoutSeconds)
+		errs = checkTimeout(errs, "write_timeout_seconds", c.WriteTimeoutSeconds)
+		typed = &c
+	case "postgres":
+		c := PostgresConfig{}
+		if cfg.Postgres != nil {
+			c = *cfg.Postgres
+		}
+		defaultString(&c.Host, "localhost")
+		defaultInt(&c.Port, 5432)
+		defaultString(&c.User, "postgres")
+		defaultString(&c.DBName, "powermem")
+		defaultString(&c.CollectionName, "memories")
+		defaultInt(&c.EmbeddingModelDims, dims)
+		defaultString(&c.SSLMode, "disable")
+
+		errs = checkPort(errs, c.Port)
+		errs = checkPositive(errs, "embedding_model_dims", c.EmbeddingModelDims)
+		if !postgresSSLModes[c.SSLMode] {
+			errs = append(errs, &FieldError{
+				Field:   "vector_store.config.ssl_mode",
+				Message: fmt.Sprintf("unknown ssl mode %q", c.SSLMode),
+			})
+		}
+		errs = checkReadDSNs(errs, c.ReadDSNs)
+		if c.MaxReplicaLagSeconds < 0 {
+			errs = append(errs, &FieldError{
+				Field:   "vector_store.config.max_replica_lag_seconds",
+				Message: fmt.Sprintf("must not be negative, got %v", c.MaxReplicaLagSeconds),
+			})
+		}
+		defaultString(&c.Quantization, "none")
+		if !postgresQuantizations[c.Quantization] {
+			errs = append(errs, &FieldError{
+				Field:   "vector_store.config.quantization",
+				Message: fmt.Sprintf("unknown quantization %q (want none, halfvec or bit)", c.Quantization),
+			})
+		}
+		if c.RerankFactor < 0 {
+			errs = append(errs, &FieldError{
+				Field:   "vector_store.config.rerank_factor",
+				Message: fmt.Sprintf("must not be negative, got %d", c.RerankFactor),
+			})
+		}
+		typed = &c
+	case "routing":
+		var routingErrs []*FieldError
+		typed, routingErrs = resolveRoutingConfig(cfg.Routing, embedderDims)
+		errs = append(errs, routingErrs...)
+	case "":
+		errs = append(errs, &FieldError{Field: "vector_store.provider", Message: "is required"})
+	default:
+		errs = append(errs, &FieldError{
+			Field:   "vector_store.provider",
+			Message: fmt.Sprintf("unknown provider %q (want sqlite, oceanbase, postgres or routing)", provider),
+		})
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return typed, nil
+}
+
+// resolveRoutingConfig resolves the settings of the routed stores and checks
+// that the routes name known stores.
+func resolveRoutingConfig(cfg *RoutingConfig, embedderDims int) (*resolvedRoutingConfig, []*FieldError) {
+	var errs []*FieldError
+	if cfg == nil || len(cfg.Stores) == 0 {
+		return nil, append(errs, &FieldError{Field: "vector_store.config.stores", Message: "is required"})
+	}
+
+	resolved := &resolvedRoutingConfig{
+		Stores:         make(map[string]interface{}, len(cfg.Stores)),
+		Default:        cfg.Default,
+		Users:          cfg.Users,
+		ChangeLogStore: cfg.ChangeLogStore,
+	}
+	defaultString(&resolved.ChangeLogStore, cfg.Default)
+
+	for _, name := range sortedKeys(cfg.Stores) {
+		store := cfg.Stores[name]
+		prefix := "vector_store.config.stores." + name
+		if store.resolvedProvider() == "routing" {
+			errs = append(errs, &FieldError{Field: prefix + ".provider", Message: "cannot be routing"})
+			continue
+		}
+		typed, storeErrs := resolveStoreConfig(store, embedderDims)
+		errs = append(errs, prefixFieldErrors(storeErrs, prefix)...)
+		resolved.Stores[name] = typed
+	}
+
+	checkStore := func(field, name string) {
+		if _, ok := cfg.Stores[name]; !ok {
+			errs = append(errs, &FieldError{Field: field, Message: fmt.Sprintf("unknown store %q", name)})
+		}
+	}
+	if cfg.Default == "" {
+		errs = append(errs, &FieldError{Field: "vector_store.config.default", Message: "is required"})
+	} else {
+		checkStore("vector_store.config.default", cfg.Default)
+	}
+	checkStore("vector_store.config.change_log_store", resolved.ChangeLogStore)
+	for _, userID := range sortedKeys(cfg.Users) {
+		checkStore("vector_store.config.users."+userID, cfg.Users[userID])
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return resolved, nil
+}
+
+// decodeRoutingConfig decodes the "config" of a routing vector store.
+func decodeRoutingConfig(raw map[string]interface{}) (*RoutingConfig, []*FieldError) {
+	cfg := &RoutingConfig{}
+	errs := decodeConfigMap(raw, cfg, "vector_store.config")
+
+	if value, ok := raw["users"]; ok && value != nil {
+		users, ok := value.(map[string]interface{})
+		if !ok {
+			errs = append(errs, &FieldError{
+				Field:   "vector_store.config.users",
+				Message: fmt.Sprintf("must be a map of user IDs to store names, got %T", value),
+			})
+		}
+		cfg.Users = make(map[string]string, len(users))
+		for _, userID := range sortedKeys(users) {
+			name := users[userID]
+			s, ok := name.(string)
+			if !ok {
+				errs = append(errs, &FieldError{
+					Field:   "vector_store.config.users." + userID,
+					Message: fmt.Sprintf("must be a string, got %T", name),
+				})
+				continue
+			}
+			cfg.Users[userID] = s
+		}
+	}
+
+	if value, ok := raw["stores"]; ok && value != nil {
+		stores, ok := value.(map[string]interface{})
+		if !ok {
+			errs = append(errs, &FieldError{
+				Field:   "vector_store.config.stores",
+				Message: fmt.Sprintf("must be a map of store names to vector stores, got %T", value),
+			})
+		}
+		cfg.Stores = make(map[string]VectorStoreConfig, len(stores))
+		for _, name := range sortedKeys(stores) {
+			prefix := "vector_store.config.stores." + name
+			data, err := json.Marshal(stores[name])
+			if err != nil {
+				errs = append(errs, &FieldError{Field: prefix, Message: err.Error()})
+				continue
+			}
+			var storeConfig VectorStoreConfig
+			if err := json.Unmarshal(data, &storeConfig); err != nil {
+				var validationErr *ValidationError
+				if errors.As(err, &validationErr) {
+					errs = append(errs, prefixFieldErrors(validationErr.Fields, prefix)...)
+				} else {
+					errs = append(errs, &FieldError{Field: prefix, Message: err.Error()})
+				}
+				continue
+			}
+			cfg.Stores[name] = storeConfig
+		}
+	}
+
+	return cfg, errs
+}
+
+// sortedKeys returns the keys of m, a map with string keys, sorted.
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	sorted := make([]string, len(keys))
+	for i, key := range keys {
+		sorted[i] = key.String()
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// prefixFieldErrors moves the "vector_store" fields of errs under prefix.
+func prefixFieldErrors(errs []*FieldError, prefix string) []*FieldError {
+	for _, err := range errs {
+		err.Field = prefix + strings.TrimPrefix(err.Field, "vector_store")
+	}
+	return errs
+}
+
+// defaultString sets *field to value if it is empty.
+func defaultString(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
+
+// defaultInt sets *field to value if it is zero.
+func defaultInt(field *int, value int) {
+	if *field == 0 {
+		*field = value
+	}
+}
+
+// checkTimeout reports a negative timeout of the vector store config.
+func checkTimeout(errs []*FieldError, key string, seconds float64) []*FieldError {
+	if seconds < 0 {
+		errs = append(errs, &FieldError{
+			Field:   "vector_store.config." + key,
+			Message: fmt.Sprintf("must not be negative, got %v", seconds),
+		})
+	}
+	return errs
+}
+
+// checkPositive reports a non-positive int field of the vector store config.
+func checkPositive(errs []*FieldError, key string, value int) []*FieldError {
+	if value <= 0 {
+		errs = append(errs, &FieldError{
+			Field:   "vector_store.config." + key,
+			Message: fmt.Sprintf("must be positive, got %d", value),
+		})
+	}
+	return errs
+}
+
+// checkNonNegative reports a negative value of the vector store config.
+func checkNonNegative(errs []*FieldError, key string, value int) []*FieldError {
+	if value < 0 {
+		errs = append(errs, &FieldError{
+			Field:   "vector_store.config." + key,
+			Message: fmt.Sprintf("must not be negative, got %d", value),
+		})
+	}
+	return errs
+}
+
+// checkPort reports a port outside 1-65535.
+func checkPort(errs []*FieldError, port int) []*FieldError {
+	if port < 1 || port > 65535 {
+		errs = append(errs, &FieldError{
+			Field:   "vector_store.config.port",
+			Message: fmt.Sprintf("must be between 1 and 65535, got %d", port),
+		})
+	}
+	return errs
+}
+
+// checkReadDSNs reports empty read replica connection strings.
+func checkReadDSNs(errs []*FieldError, dsns []string) []*FieldError {
+	for i, dsn := range dsns {
+		if strings.TrimSpace(dsn) == "" {
+			errs = append(errs, &FieldError{
+				Field:   fmt.Sprintf("vector_store.config.read_dsns[%d]", i),
+				Message: "is empty",
+			})
+		}
+	}
+	return errs
+}
+
+// decodeConfigMap copies the values of raw into the string, int, float64,
+// bool and []string fields of the struct pointed to by out, matching keys to the
+// fields' json tags.
+//
+// Fields missing from raw (or set to nil) keep their value. Ints are accepted
+// as any Go integer type, as json.Number, or as a float64 without a fraction,
+// which is what encoding/json produces for numbers. Floats are accepted as
+// any number.
+func decodeConfigMap(raw map[string]interface{}, out interface{}, prefix string) []*FieldError {
+	var errs []*FieldError
+
+	v := reflect.ValueOf(out).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		value, ok := raw[key]
+		if !ok || value == nil {
+			continue
+		}
+		field := v.Field(i)
+
+		switch field.Kind() {
+		case reflect.String:
+			s, ok := value.(string)
+			if !ok {
+				errs = append(errs, &FieldError{
+					Field:   prefix + "." + key,
+					Message: fmt.Sprintf("must be a string, got %T", value),
+				})
+				continue
+			}
+			field.SetString(s)
+		case reflect.Int:
+			n, ok := toInt(value)
+			if !ok {
+				errs = append(errs, &FieldError{
+					Field:   prefix + "." + key,
+					Message: fmt.Sprintf("must be an integer, got %T %v", value, value),
+				})
+				continue
+			}
+			field.SetInt(int64(n))
+		case reflect.Float64:
+			f, ok := toFloat(value)
+			if !ok {
+				errs = append(errs, &FieldError{
+					Field:   prefix + "." + key,
+					Message: fmt.Sprintf("must be a number, got %T %v", value, value),
+				})
+				continue
+			}
+			field.SetFloat(f)
+		case reflect.Bool:
+			b, ok := value.(bool)
+			if !ok {
+				errs = append(errs, &FieldError{
+					Field:   prefix + "." + key,
+					Message: fmt.Sprintf("must be a boolean, got %T", value),
+				})
+				continue
+			}
+			field.SetBool(b)
+		case reflect.Slice:
+			if field.Type().Elem().Kind() != reflect.String {
+				continue
+			}
+			items, ok := value.([]interface{})
+			if !ok {
+				errs = append(errs, &FieldError{
+					Field:   prefix + "." + key,
+					Message: fmt.Sprintf("must be a list of strings, got %T", value),
+				})
+				continue
+			}
+			strs := make([]string, 0, len(items))
+			for j, item := range items {
+				s, ok := item.(string)
+				if !ok {
+					errs = append(errs, &FieldError{
+						Field:   fmt.Sprintf("%s.%s[%d]", prefix, key, j),
+						Message: fmt.Sprintf("must be a string, got %T", item),
+					})
+					continue
+				}
+				strs = append(strs, s)
+			}
+			field.Set(reflect.ValueOf(strs))
+		}
+	}
+
+	return errs
+}
+
+// toFloat converts a numeric config value to float64.
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		i, ok := toInt(value)
+		return float64(i), ok
+	}
+}
+
+// toInt converts an integer-valued config value to int.
+func toInt(value interface{}) (int, bool) {
+	switch n := value.(type) {
+	case int:
+		return n, true
+	case int8:
+		return int(n), true
+	case int16:
+		return int(n), true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case uint:
+		return int(n), true
+	case uint8:
+		return int(n), true
+	case uint16:
+		return int(n), true
+	case uint32:
+		return int(n), true
+	case uint64:
+		return int(n), true
+	case float32:
+		return toInt(float64(n))
+	case float64:
+		if n != math.Trunc(n) || math.IsInf(n, 0) {
+			return 0, false
+		}
+		return int(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err == nil
+	default:
+		return 0, false
+	}
+}
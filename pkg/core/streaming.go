@@ -3,7 +3,13 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
 
 	"github.com/oceanbase/powermem-go/pkg/storage"
 )
@@ -38,27 +44,29 @@ type StreamingGetAllResult struct {
 	Error error
 }
 
+// streamChunkSize is the maximum number of memories fetched from the storage
+// backend per call by SearchStream and GetAllStream, whatever their batch
+// size.
+const streamChunkSize = 100
+
 // SearchStream performs streaming search for large datasets.
 //
 // Instead of returning all results at once, this method streams results in batches
 // through a channel, making it suitable for processing large result sets without
 // loading everything into memory at once.
 //
-// The method:
-//  1. Performs an initial search to get the first batch
-//  2. Continues fetching additional batches until all results are retrieved
-//  3. Sends each batch through the channel as it becomes available
-//
-// Note: Vector search typically doesn't support offset-based pagination well.
-// This implementation performs a single search and returns results in batches.
-// For true streaming with large result sets, consider using GetAllStream with
-// filtering or implementing cursor-based pagination in your storage backend.
+// Results are fetched from the storage backend in chunks of at most 100
+// memories with keyset pagination (see storage.VectorStore.SearchIter), and
+// converted one chunk at a time, so the stream never holds more than
+// batchSize converted memories besides the batch being received. With
+// WithMaxBatchBytes, a batch is also sent early once its memories reach
+// that estimated size.
 //
 // Parameters:
 //   - ctx: Context for cancellation
 //   - query: Search query string
 //   - batchSize: Number of results per batch
-//   - opts: Optional search parameters (UserID, AgentID, MinScore, Filters, Limit)
+//   - opts: Optional search parameters (UserID, AgentID, MinScore, Filters, Limit, MaxBatchBytes)
 //
 // Returns a channel that receives StreamingSearchResult batches.
 // The channel is closed when all results have been sent or an error occurs.
@@ -69,6 +77,7 @@ type StreamingGetAllResult struct {
 //	    50, // batch size
 //	    core.WithUserIDForSearch("user_001"),
 //	    core.WithLimit(200), // maximum total results
+//	    core.WithMaxBatchBytes(8<<20), // batches of at most about 8 MiB
 //	)
 //
 //	for result := range resultChan {
@@ -80,25 +89,49 @@ type StreamingGetAllResult struct {
 //	    }
 //	}
 func (c *Client) SearchStream(ctx context.Context, query string, batchSize int, opts ...SearchOption) <-chan *StreamingSearchResult {
-	resultChan := make(chan *StreamingSearchResult, 1)
+	ctx, err := c.begin(ctx, "SearchStream")
+	if err != nil {
+		resultChan := make(chan *StreamingSearchResult, 1)
+		resultChan <- &StreamingSearchResult{Error: err}
+		close(resultChan)
+		return resultChan
+	}
+
+	// Unbuffered, so that a batch is converted only once the previous one
+	// is received
+	resultChan := make(chan *StreamingSearchResult)
 
 	go func() {
 		defer close(resultChan)
+		defer c.end()
 
 		c.mu.RLock()
 		defer c.mu.RUnlock()
 
+		send := func(memories []*Memory, batchIndex int, isLastBatch bool, err error) {
+			resultChan <- &StreamingSearchResult{
+				Memories:    memories,
+				BatchIndex:  batchIndex,
+				IsLastBatch: isLastBatch,
+				Error:       err,
+			}
+		}
+
 		// Apply search options
 		searchOpts := applySearchOptions(opts)
+		if err := c.checkUnscopedSearch(ctx, "SearchStream", query, searchOpts); err != nil {
+			send(nil, 0, false, NewMemoryError("SearchStream", err))
+			return
+		}
 
 		// Generate query embedding
-		queryEmbedding, err := c.embedder.Embed(ctx, query)
+		route := c.singleRoute(searchOpts.Filters)
+		queryEmbedding, _, err := c.embedQuery(ctx, route, query)
 		if err != nil {
-			resultChan <- &StreamingSearchResult{
-				Error: NewMemoryError("SearchStream", err),
-			}
+			send(nil, 0, false, NewMemoryError("SearchStream", err))
 			return
 		}
+		staleness := c.staleness()
 
 		// Determine maximum results
 		maxResults := searchOpts.Limit
@@ -106,63 +139,57 @@ func (c *Client) SearchStream(ctx context.Context, query string, batchSize int,
 			maxResults = 1000 // Default maximum for streaming
 		}
 
-		// Perform search with increased limit to get all results at once
-		// Then we'll paginate in memory
+		teamIDs, err := c.userTeams(ctx, searchOpts.UserID)
+		if err != nil {
+			send(nil, 0, false, NewMemoryError("SearchStream", err))
+			return
+		}
+
 		storageOpts := &storage.SearchOptions{
 			UserID:   searchOpts.UserID,
+			TeamIDs:  teamIDs,
 			AgentID:  searchOpts.AgentID,
 			Limit:    maxResults,
 			MinScore: searchOpts.MinScore,
 			Filters:  searchOpts.Filters,
+			TimeRange: toStorageTimeRange(
+				searchOpts.CreatedAfter, searchOpts.CreatedBefore,
+				searchOpts.UpdatedAfter, searchOpts.UpdatedBefore,
+			),
+			Tags:              searchOpts.Tags,
+			WithoutEmbeddings: !searchOpts.IncludeEmbeddings,
+			Fields:            searchFields(searchOpts.Fields, staleness != nil),
+			Now:               c.now(),
 		}
 
-		// Get all matching results
-		allMemories, err := c.storage.Search(ctx, queryEmbedding, storageOpts)
+		if batchSize <= 0 {
+			send(nil, 0, false, NewMemoryError("SearchStream", fmt.Errorf("%w: batch size must be positive", ErrInvalidInput)))
+			return
+		}
+		iter, err := c.storage.SearchIter(ctx, queryEmbedding, routeSearchOptions(route, storageOpts), streamFetchSize(batchSize))
 		if err != nil {
-			resultChan <- &StreamingSearchResult{
-				Error: NewMemoryError("SearchStream", err),
-			}
+			send(nil, 0, false, NewMemoryError("SearchStream", err))
 			return
 		}
 
-		// Convert all memories
-		convertedMemories := fromStorageMemories(allMemories)
-
-		// Stream results in batches
-		batchIndex := 0
-		for i := 0; i < len(convertedMemories); i += batchSize {
-			// Check context cancellation
-			select {
-			case <-ctx.Done():
-				resultChan <- &StreamingSearchResult{
-					BatchIndex: batchIndex,
-					Error:      ctx.Err(),
-				}
-				return
-			default:
-			}
-
-			end := i + batchSize
-			if end > len(convertedMemories) {
-				end = len(convertedMemories)
-			}
-
-			batch := convertedMemories[i:end]
-			isLastBatch := end >= len(convertedMemories)
+		// Memories already sent for one of their chunks
+		seen := make(map[int64]bool)
 
-			resultChan <- &StreamingSearchResult{
-				Memories:    batch,
-				BatchIndex:  batchIndex,
-				IsLastBatch: isLastBatch,
+		next := func(ctx context.Context) ([]*storage.Memory, error) {
+			memories, err := iter.Next(ctx)
+			if err != nil {
+				return nil, err
 			}
-
-			batchIndex++
-
-			// If this was the last batch, stop
-			if isLastBatch {
-				break
+			memories, err = c.resolveChunks(ctx, c.inRoute(route, memories), seen)
+			if err != nil {
+				return nil, err
 			}
+			return c.injectionGuard.screen(visible(memories, searchOpts)), nil
 		}
+		batch := newStreamBatch(batchSize, searchOpts.MaxBatchBytes)
+		batch.fields = searchOpts.Fields
+		batch.staleness = staleness
+		streamBatches(ctx, "SearchStream", next, batch, send)
 	}()
 
 	return resultChan
@@ -174,10 +201,16 @@ func (c *Client) SearchStream(ctx context.Context, query string, batchSize int,
 // results in batches through a channel, making it suitable for processing
 // large datasets without exhausting system resources.
 //
+// Memories are fetched from the storage backend in chunks of at most 100
+// and converted one chunk at a time, so the stream never holds more than
+// batchSize converted memories besides the batch being received. With
+// WithMaxBatchBytesForGetAll, a batch is also sent early once its memories
+// reach that estimated size.
+//
 // Parameters:
 //   - ctx: Context for cancellation
 //   - batchSize: Number of memories per batch
-//   - opts: Optional parameters (UserID, AgentID, Limit, Offset)
+//   - opts: Optional parameters (UserID, AgentID, Limit, Offset, MaxBatchBytes)
 //
 // Returns a channel that receives StreamingGetAllResult batches.
 // The channel is closed when all memories have been sent or an error occurs.
@@ -198,23 +231,60 @@ func (c *Client) SearchStream(ctx context.Context, query string, batchSize int,
 //	    }
 //	}
 func (c *Client) GetAllStream(ctx context.Context, batchSize int, opts ...GetAllOption) <-chan *StreamingGetAllResult {
-	resultChan := make(chan *StreamingGetAllResult, 1)
+	ctx, err := c.begin(ctx, "GetAllStream")
+	if err != nil {
+		resultChan := make(chan *StreamingGetAllResult, 1)
+		resultChan <- &StreamingGetAllResult{Error: err}
+		close(resultChan)
+		return resultChan
+	}
+
+	// Unbuffered, so that a batch is converted only once the previous one
+	// is received
+	resultChan := make(chan *StreamingGetAllResult)
 
 	go func() {
 		defer close(resultChan)
+		defer c.end()
 
 		c.mu.RLock()
 		defer c.mu.RUnlock()
 
+		send := func(memories []*Memory, batchIndex int, isLastBatch bool, err error) {
+			resultChan <- &StreamingGetAllResult{
+				Memories:    memories,
+				BatchIndex:  batchIndex,
+				IsLastBatch: isLastBatch,
+				Error:       err,
+			}
+		}
+
 		// Apply options
 		getAllOpts := applyGetAllOptions(opts)
+		if err := c.checkUnscopedGetAll(ctx, "GetAllStream", getAllOpts); err != nil {
+			send(nil, 0, false, NewMemoryError("GetAllStream", err))
+			return
+		}
+		teamIDs, err := c.userTeams(ctx, getAllOpts.UserID)
+		if err != nil {
+			send(nil, 0, false, NewMemoryError("GetAllStream", err))
+			return
+		}
 
 		// Prepare storage options
 		storageOpts := &storage.GetAllOptions{
 			UserID:  getAllOpts.UserID,
+			TeamIDs: teamIDs,
 			AgentID: getAllOpts.AgentID,
-			Limit:   batchSize,
-			Offset:  getAllOpts.Offset,
+			TimeRange: toStorageTimeRange(
+				getAllOpts.CreatedAfter, getAllOpts.CreatedBefore,
+				getAllOpts.UpdatedAfter, getAllOpts.UpdatedBefore,
+			),
+			Tags:              getAllOpts.Tags,
+			Filters:           getAllOpts.Filters,
+			WithoutEmbeddings: getAllOpts.WithoutEmbeddings,
+			Fields:            toStorageFields(getAllOpts.Fields),
+			Now:               c.now(),
 		}
 
 		// Determine maximum results
@@ -223,81 +293,172 @@ func (c *Client) GetAllStream(ctx context.Context, batchSize int, opts ...GetAll
 			maxResults = 10000 // Default maximum for streaming
 		}
 
-		batchIndex := 0
-		offset := getAllOpts.Offset
-
-		for {
-			// Check context cancellation
-			select {
-			case <-ctx.Done():
-				resultChan <- &StreamingGetAllResult{
-					Error: ctx.Err(),
-				}
-				return
-			default:
-			}
-
-			// Update offset
-			storageOpts.Offset = offset
-			storageOpts.Limit = batchSize
-
-			// Adjust batch size for the last batch
-			remaining := maxResults - (offset - getAllOpts.Offset)
-			if remaining <= 0 {
-				break
+		if batchSize <= 0 {
+			send(nil, 0, false, NewMemoryError("GetAllStream", fmt.Errorf("%w: batch size must be positive", ErrInvalidInput)))
+			return
+		}
+		fetchSize := streamFetchSize(batchSize)
+
+		fetched := 0
+		done := false
+		next := func(ctx context.Context) ([]*storage.Memory, error) {
+			remaining := maxResults - fetched
+			if done || remaining <= 0 {
+				return nil, io.EOF
 			}
-			if remaining < batchSize {
+			storageOpts.Offset = getAllOpts.Offset + fetched
+			storageOpts.Limit = fetchSize
+			if remaining < fetchSize {
 				storageOpts.Limit = remaining
 			}
 
-			// Get batch
 			memories, err := c.storage.GetAll(ctx, storageOpts)
 			if err != nil {
-				resultChan <- &StreamingGetAllResult{
-					BatchIndex: batchIndex,
-					Error:      NewMemoryError("GetAllStream", err),
-				}
-				return
+				return nil, err
 			}
-
-			// If no more results, we're done
 			if len(memories) == 0 {
-				break
+				return nil, io.EOF
 			}
+			fetched += len(memories)
+			done = len(memories) < storageOpts.Limit
+			return memories, nil
+		}
+		streamBatches(ctx, "GetAllStream", next, newStreamBatch(batchSize, getAllOpts.MaxBatchBytes), send)
+	}()
 
-			// Convert and send batch
-			convertedMemories := fromStorageMemories(memories)
-			isLastBatch := len(memories) < batchSize
+	return resultChan
+}
 
-			resultChan <- &StreamingGetAllResult{
-				Memories:    convertedMemories,
-				BatchIndex:  batchIndex,
-				IsLastBatch: isLastBatch,
-			}
+// streamFetchSize returns the number of memories fetched per storage call
+// by a stream of batchSize memories per batch.
+func streamFetchSize(batchSize int) int {
+	if batchSize < streamChunkSize {
+		return batchSize
+	}
+	return streamChunkSize
+}
+
+// streamBatch accumulates the converted memories of a stream batch.
+type streamBatch struct {
+	batchSize int
+	maxBytes  int64
 
-			batchIndex++
-			offset += len(memories)
+	// fields are the fields kept by add, see projectMemory
+	fields []MemoryField
 
-			// If this was the last batch, stop
-			if isLastBatch {
-				break
+	// staleness marks the stale memories added (nil for none)
+	staleness *stalenessPolicy
+
+	memories []*Memory
+	bytes    int64
+}
+
+// newStreamBatch returns a batch sent at batchSize memories, or once its
+// memories reach maxBytes estimated bytes if maxBytes is positive.
+func newStreamBatch(batchSize int, maxBytes int64) *streamBatch {
+	return &streamBatch{batchSize: batchSize, maxBytes: maxBytes}
+}
+
+// add converts a memory and appends it to the batch.
+func (b *streamBatch) add(memory *storage.Memory) {
+	converted := fromStorageMemory(memory)
+	b.staleness.mark(converted)
+	projectMemory(converted, b.fields)
+	b.memories = append(b.memories, converted)
+	b.bytes += estimateMemorySize(converted)
+}
+
+// full reports whether the batch must be sent.
+func (b *streamBatch) full() bool {
+	return len(b.memories) >= b.batchSize || (b.maxBytes > 0 && b.bytes >= b.maxBytes)
+}
+
+// take returns the memories of the batch and empties it. The returned slice
+// belongs to the receiver of the batch.
+func (b *streamBatch) take() []*Memory {
+	memories := b.memories
+	b.memories = nil
+	b.bytes = 0
+	return memories
+}
+
+// streamBatches sends the memories of the chunks returned by next, until it
+// returns io.EOF, in batches of b. One chunk is fetched ahead so that the
+// last batch is flagged. Errors are sent as the last result, wrapped with op
+// unless the context is done.
+func streamBatches(ctx context.Context, op string, next func(ctx context.Context) ([]*storage.Memory, error), b *streamBatch,
+	send func(memories []*Memory, batchIndex int, isLastBatch bool, err error)) {
+	batchIndex := 0
+	chunk, err := next(ctx)
+	for {
+		if err == io.EOF {
+			if len(b.memories) > 0 {
+				send(b.take(), batchIndex, true, nil)
 			}
+			return
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				err = ctxErr
+			} else {
+				err = NewMemoryError(op, err)
+			}
+			send(nil, batchIndex, false, err)
+			return
+		}
+
+		// Check context cancellation
+		if err := ctx.Err(); err != nil {
+			send(nil, batchIndex, false, err)
+			return
+		}
 
-			// Check if we've reached the maximum
-			if offset-getAllOpts.Offset >= maxResults {
-				break
+		following, followingErr := next(ctx)
+		for i, memory := range chunk {
+			// Release the storage memory once converted
+			chunk[i] = nil
+			b.add(memory)
+			if b.full() {
+				isLastBatch := i == len(chunk)-1 && followingErr == io.EOF
+				send(b.take(), batchIndex, isLastBatch, nil)
+				batchIndex++
 			}
 		}
-	}()
+		chunk, err = following, followingErr
+	}
+}
 
-	return resultChan
+// estimateMemorySize returns an estimate of the bytes held by a memory: its
+// strings, vectors, tags and metadata. Metadata values other than strings
+// are counted as 16 bytes.
+func estimateMemorySize(m *Memory) int64 {
+	size := int64(unsafe.Sizeof(*m))
+	size += int64(len(m.UserID) + len(m.AgentID) + len(m.Content) + len(m.UID))
+	size += int64(8 * len(m.Embedding))
+	size += int64(16 * len(m.SparseEmbedding))
+	for _, tag := range m.Tags {
+		size += int64(16 + len(tag))
+	}
+	for key, value := range m.Metadata {
+		size += int64(16 + len(key))
+		if s, ok := value.(string); ok {
+			size += int64(16 + len(s))
+		} else {
+			size += 16
+		}
+	}
+	return size
 }
 
 // BatchAddResult contains the result of a batch add operation.
 type BatchAddResult struct {
-	// Created contains successfully created memories.
+	// Created contains successfully created memories, in input order.
 	Created []*Memory
 
+	// IDs contains the ID of the memory created for each input item, in input
+	// order. Items that failed have ID 0.
+	IDs []int64
+
 	// Failed contains memories that failed to be created, along with their errors.
 	Failed []BatchAddError
 
@@ -323,10 +484,76 @@ type BatchAddError struct {
 	Index int
 }
 
+// BatchAddItem represents a single item in a BatchAddItems operation.
+//
+// Non-zero fields override the options shared by the batch for this item.
+type BatchAddItem struct {
+	// Content is the content of the memory.
+	Content string
+
+	// Metadata, if non-nil, is merged over the metadata shared by the batch,
+	// with the item's values taking precedence.
+	Metadata map[string]interface{}
+
+	// AgentID identifies the agent associated with this memory.
+	AgentID string
+
+	// RunID identifies the run/session associated with this memory.
+	RunID string
+
+	// Scope defines the visibility scope of the memory.
+	Scope MemoryScope
+
+	// MemoryType specifies the type of memory (e.g., "conversation", "fact", "preference").
+	MemoryType string
+
+	// Tags, if non-nil, replace the tags shared by the batch.
+	Tags []string
+
+	// TTL is how long the memory lives after it is added.
+	TTL time.Duration
+}
+
+// options returns the Add options for the item: the shared options followed
+// by the item's overrides.
+func (item BatchAddItem) options(shared []AddOption, sharedMetadata map[string]interface{}) []AddOption {
+	opts := append([]AddOption{}, shared...)
+	if item.Metadata != nil {
+		metadata := make(map[string]interface{}, len(sharedMetadata)+len(item.Metadata))
+		for k, v := range sharedMetadata {
+			metadata[k] = v
+		}
+		for k, v := range item.Metadata {
+			metadata[k] = v
+		}
+		opts = append(opts, WithMetadata(metadata))
+	}
+	if item.AgentID != "" {
+		opts = append(opts, WithAgentID(item.AgentID))
+	}
+	if item.RunID != "" {
+		opts = append(opts, WithRunID(item.RunID))
+	}
+	if item.Scope != "" {
+		opts = append(opts, WithScope(item.Scope))
+	}
+	if item.MemoryType != "" {
+		opts = append(opts, WithMemoryType(item.MemoryType))
+	}
+	if item.Tags != nil {
+		opts = append(opts, WithTags(item.Tags...))
+	}
+	if item.TTL > 0 {
+		opts = append(opts, WithTTL(item.TTL))
+	}
+	return opts
+}
+
 // BatchAdd adds multiple memories in a single batch operation.
 //
 // This method processes memories concurrently within the batch for better performance,
-// while respecting resource limits and error handling.
+// while respecting resource limits and error handling. Concurrency, fail-fast
+// and retry behavior are set with WithBatchOptions.
 //
 // Parameters:
 //   - ctx: Context for cancellation
@@ -352,7 +579,41 @@ type BatchAddError struct {
 //	}
 //	fmt.Printf("Created %d/%d memories\n", result.CreatedCount, result.Total)
 func (c *Client) BatchAdd(ctx context.Context, contents []string, opts ...AddOption) (*BatchAddResult, error) {
-	if len(contents) == 0 {
+	ctx, err := c.begin(ctx, "BatchAdd")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	items := make([]BatchAddItem, len(contents))
+	for i, content := range contents {
+		items[i] = BatchAddItem{Content: content}
+	}
+	return c.BatchAddItems(ctx, items, opts...)
+}
+
+// BatchAddItems adds multiple heterogeneous memories in a single batch
+// operation.
+//
+// It behaves like BatchAdd; opts apply to every item, and each item's
+// fields (metadata, run ID, scope, etc.) override them for that item.
+// result.IDs holds the created ID of every item in input order.
+//
+// Example:
+//
+//	result, err := client.BatchAddItems(ctx, []core.BatchAddItem{
+//	    {Content: "User likes Python", Metadata: map[string]interface{}{"source": "chat"}},
+//	    {Content: "Team uses Go", RunID: "run_042", Scope: core.ScopeAgentGroup},
+//	}, core.WithUserID("user_001"))
+//	// result.IDs[1] is the ID of "Team uses Go"
+func (c *Client) BatchAddItems(ctx context.Context, items []BatchAddItem, opts ...AddOption) (*BatchAddResult, error) {
+	ctx, err := c.begin(ctx, "BatchAddItems")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	if len(items) == 0 {
 		return &BatchAddResult{
 			Total:        0,
 			CreatedCount: 0,
@@ -360,72 +621,46 @@ func (c *Client) BatchAdd(ctx context.Context, contents []string, opts ...AddOpt
 		}, nil
 	}
 
-	result := &BatchAddResult{
-		Total:        len(contents),
-		Created:      make([]*Memory, 0, len(contents)),
-		Failed:       make([]BatchAddError, 0),
-		CreatedCount: 0,
-		FailedCount:  0,
-	}
-
-	// Use a semaphore to limit concurrent operations
-	const maxConcurrency = 10
-	sem := make(chan struct{}, maxConcurrency)
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-
-	for i, content := range contents {
-		wg.Add(1)
-		sem <- struct{}{} // Acquire semaphore
-
-		go func(index int, text string) {
-			defer wg.Done()
-			defer func() { <-sem }() // Release semaphore
-
-			// Check context cancellation
-			select {
-			case <-ctx.Done():
-				mu.Lock()
-				result.Failed = append(result.Failed, BatchAddError{
-					Content: text,
-					Error:   ctx.Err(),
-					Index:   index,
-				})
-				result.FailedCount++
-				mu.Unlock()
-				return
-			default:
-			}
+	addOpts := applyAddOptions(opts)
 
-			// Add memory
-			memory, err := c.Add(ctx, text, opts...)
-			if err != nil {
-				mu.Lock()
-				result.Failed = append(result.Failed, BatchAddError{
-					Content: text,
-					Error:   err,
-					Index:   index,
-				})
-				result.FailedCount++
-				mu.Unlock()
-				return
-			}
+	memories := make([]*Memory, len(items))
+	errs := runBatch(ctx, len(items), applyBatchOptions(addOpts.Batch), func(ctx context.Context, index int) error {
+		item := items[index]
+		memory, err := c.Add(ctx, item.Content, item.options(opts, addOpts.Metadata)...)
+		if err != nil {
+			return err
+		}
+		memories[index] = memory
+		return nil
+	})
 
-			mu.Lock()
-			result.Created = append(result.Created, memory)
-			result.CreatedCount++
-			mu.Unlock()
-		}(i, content)
+	result := &BatchAddResult{
+		Total:   len(items),
+		Created: make([]*Memory, 0, len(items)),
+		IDs:     make([]int64, len(items)),
+		Failed:  make([]BatchAddError, 0),
 	}
-
-	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			result.Failed = append(result.Failed, BatchAddError{
+				Content: items[i].Content,
+				Error:   err,
+				Index:   i,
+			})
+			continue
+		}
+		result.Created = append(result.Created, memories[i])
+		result.IDs[i] = memories[i].ID
+	}
+	result.CreatedCount = len(result.Created)
+	result.FailedCount = len(result.Failed)
 
 	return result, nil
 }
 
 // BatchUpdateResult contains the result of a batch update operation.
 type BatchUpdateResult struct {
-	// Updated contains successfully updated memories.
+	// Updated contains successfully updated memories, in input order.
 	Updated []*Memory
 
 	// Failed contains memories that failed to be updated, along with their errors.
@@ -463,6 +698,9 @@ type BatchUpdateItem struct {
 
 	// Content is the new content for the memory.
 	Content string
+
+	// Metadata, if non-nil, replaces the memory's metadata.
+	Metadata map[string]interface{}
 }
 
 // BatchUpdate updates multiple memories in a single batch operation.
@@ -470,6 +708,7 @@ type BatchUpdateItem struct {
 // Parameters:
 //   - ctx: Context for cancellation
 //   - items: Slice of BatchUpdateItem containing ID and content pairs
+//   - opts: Optional batch policy (concurrency, fail-fast, retries)
 //
 // Returns a BatchUpdateResult containing updated memories and any failures.
 //
@@ -484,7 +723,13 @@ type BatchUpdateItem struct {
 //	    log.Fatal(err)
 //	}
 //	fmt.Printf("Updated %d/%d memories\n", result.UpdatedCount, result.Total)
-func (c *Client) BatchUpdate(ctx context.Context, items []BatchUpdateItem) (*BatchUpdateResult, error) {
+func (c *Client) BatchUpdate(ctx context.Context, items []BatchUpdateItem, opts ...BatchOption) (*BatchUpdateResult, error) {
+	ctx, err := c.begin(ctx, "BatchUpdate")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
 	if len(items) == 0 {
 		return &BatchUpdateResult{
 			Total:        0,
@@ -493,74 +738,49 @@ func (c *Client) BatchUpdate(ctx context.Context, items []BatchUpdateItem) (*Bat
 		}, nil
 	}
 
-	result := &BatchUpdateResult{
-		Total:        len(items),
-		Updated:      make([]*Memory, 0, len(items)),
-		Failed:       make([]BatchUpdateError, 0),
-		UpdatedCount: 0,
-		FailedCount:  0,
-	}
+	memories := make([]*Memory, len(items))
+	errs := runBatch(ctx, len(items), applyBatchOptions(opts), func(ctx context.Context, index int) error {
+		item := items[index]
 
-	// Use a semaphore to limit concurrent operations
-	const maxConcurrency = 10
-	sem := make(chan struct{}, maxConcurrency)
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-
-	for i, item := range items {
-		wg.Add(1)
-		sem <- struct{}{} // Acquire semaphore
-
-		go func(index int, updateItem BatchUpdateItem) {
-			defer wg.Done()
-			defer func() { <-sem }() // Release semaphore
-
-			// Check context cancellation
-			select {
-			case <-ctx.Done():
-				mu.Lock()
-				result.Failed = append(result.Failed, BatchUpdateError{
-					ID:      updateItem.ID,
-					Content: updateItem.Content,
-					Error:   ctx.Err(),
-					Index:   index,
-				})
-				result.FailedCount++
-				mu.Unlock()
-				return
-			default:
-			}
+		var updateOpts []UpdateOption
+		if item.Metadata != nil {
+			updateOpts = append(updateOpts, WithMetadataForUpdate(item.Metadata))
+		}
 
-			// Update memory
-			memory, err := c.Update(ctx, updateItem.ID, updateItem.Content)
-			if err != nil {
-				mu.Lock()
-				result.Failed = append(result.Failed, BatchUpdateError{
-					ID:      updateItem.ID,
-					Content: updateItem.Content,
-					Error:   err,
-					Index:   index,
-				})
-				result.FailedCount++
-				mu.Unlock()
-				return
-			}
+		memory, err := c.Update(ctx, item.ID, item.Content, updateOpts...)
+		if err != nil {
+			return err
+		}
+		memories[index] = memory
+		return nil
+	})
 
-			mu.Lock()
-			result.Updated = append(result.Updated, memory)
-			result.UpdatedCount++
-			mu.Unlock()
-		}(i, item)
+	result := &BatchUpdateResult{
+		Total:   len(items),
+		Updated: make([]*Memory, 0, len(items)),
+		Failed:  make([]BatchUpdateError, 0),
 	}
-
-	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			result.Failed = append(result.Failed, BatchUpdateError{
+				ID:      items[i].ID,
+				Content: items[i].Content,
+				Error:   err,
+				Index:   i,
+			})
+			continue
+		}
+		result.Updated = append(result.Updated, memories[i])
+	}
+	result.UpdatedCount = len(result.Updated)
+	result.FailedCount = len(result.Failed)
 
 	return result, nil
 }
 
 // BatchDeleteResult contains the result of a batch delete operation.
 type BatchDeleteResult struct {
-	// DeletedIDs contains successfully deleted memory IDs.
+	// DeletedIDs contains successfully deleted memory IDs, in input order.
 	DeletedIDs []int64
 
 	// Failed contains memory IDs that failed to be deleted, along with their errors.
@@ -593,6 +813,7 @@ type BatchDeleteError struct {
 // Parameters:
 //   - ctx: Context for cancellation
 //   - ids: Slice of memory IDs to delete
+//   - opts: Optional batch policy (concurrency, fail-fast, retries)
 //
 // Returns a BatchDeleteResult containing deleted IDs and any failures.
 //
@@ -604,7 +825,13 @@ type BatchDeleteError struct {
 //	    log.Fatal(err)
 //	}
 //	fmt.Printf("Deleted %d/%d memories\n", result.DeletedCount, result.Total)
-func (c *Client) BatchDelete(ctx context.Context, ids []int64) (*BatchDeleteResult, error) {
+func (c *Client) BatchDelete(ctx context.Context, ids []int64, opts ...BatchOption) (*BatchDeleteResult, error) {
+	ctx, err := c.begin(ctx, "BatchDelete")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
 	if len(ids) == 0 {
 		return &BatchDeleteResult{
 			Total:        0,
@@ -613,65 +840,95 @@ func (c *Client) BatchDelete(ctx context.Context, ids []int64) (*BatchDeleteResu
 		}, nil
 	}
 
+	errs := runBatch(ctx, len(ids), applyBatchOptions(opts), func(ctx context.Context, index int) error {
+		return c.Delete(ctx, ids[index])
+	})
+
 	result := &BatchDeleteResult{
-		Total:        len(ids),
-		DeletedIDs:   make([]int64, 0, len(ids)),
-		Failed:       make([]BatchDeleteError, 0),
-		DeletedCount: 0,
-		FailedCount:  0,
+		Total:      len(ids),
+		DeletedIDs: make([]int64, 0, len(ids)),
+		Failed:     make([]BatchDeleteError, 0),
+	}
+	for i, err := range errs {
+		if err != nil {
+			result.Failed = append(result.Failed, BatchDeleteError{
+				ID:    ids[i],
+				Error: err,
+				Index: i,
+			})
+			continue
+		}
+		result.DeletedIDs = append(result.DeletedIDs, ids[i])
 	}
+	result.DeletedCount = len(result.DeletedIDs)
+	result.FailedCount = len(result.Failed)
+
+	return result, nil
+}
+
+// runBatch calls fn for every index in [0, n), with at most
+// batchOpts.Concurrency calls in flight, and returns the error of each index
+// (nil on success).
+//
+// Failed calls are retried according to batchOpts. With FailFast, indexes
+// not started after the first failure get ErrBatchAborted; indexes not
+// started because ctx is done get ctx.Err().
+func runBatch(ctx context.Context, n int, batchOpts *BatchOptions, fn func(ctx context.Context, index int) error) []error {
+	errs := make([]error, n)
 
 	// Use a semaphore to limit concurrent operations
-	const maxConcurrency = 10
-	sem := make(chan struct{}, maxConcurrency)
+	sem := make(chan struct{}, batchOpts.Concurrency)
 	var wg sync.WaitGroup
-	var mu sync.Mutex
+	var aborted int32
 
-	for i, id := range ids {
-		wg.Add(1)
+	for i := 0; i < n; i++ {
 		sem <- struct{}{} // Acquire semaphore
 
-		go func(index int, memoryID int64) {
+		if atomic.LoadInt32(&aborted) == 1 {
+			<-sem
+			errs[i] = ErrBatchAborted
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			<-sem
+			errs[i] = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(index int) {
 			defer wg.Done()
 			defer func() { <-sem }() // Release semaphore
 
-			// Check context cancellation
-			select {
-			case <-ctx.Done():
-				mu.Lock()
-				result.Failed = append(result.Failed, BatchDeleteError{
-					ID:    memoryID,
-					Error: ctx.Err(),
-					Index: index,
-				})
-				result.FailedCount++
-				mu.Unlock()
-				return
-			default:
-			}
-
-			// Delete memory
-			err := c.Delete(ctx, memoryID)
-			if err != nil {
-				mu.Lock()
-				result.Failed = append(result.Failed, BatchDeleteError{
-					ID:    memoryID,
-					Error: err,
-					Index: index,
-				})
-				result.FailedCount++
-				mu.Unlock()
-				return
+			err := runWithRetry(ctx, batchOpts, func() error { return fn(ctx, index) })
+			errs[index] = err
+			if err != nil && batchOpts.FailFast {
+				atomic.StoreInt32(&aborted, 1)
 			}
-
-			mu.Lock()
-			result.DeletedIDs = append(result.DeletedIDs, memoryID)
-			result.DeletedCount++
-			mu.Unlock()
-		}(i, id)
+		}(i)
 	}
 
 	wg.Wait()
 
-	return result, nil
+	return errs
+}
+
+// runWithRetry calls fn until it succeeds or batchOpts.MaxRetries retries are
+// used up, doubling the backoff between attempts.
+func runWithRetry(ctx context.Context, batchOpts *BatchOptions, fn func() error) error {
+	backoff := batchOpts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= batchOpts.MaxRetries ||
+			errors.Is(err, ErrInvalidInput) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
 }
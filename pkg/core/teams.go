@@ -0,0 +1,160 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// AddTeamMember adds a user to a team. The memories added with WithTeamID
+// for the team are then returned to the user by Search, SearchByKeyword and
+// GetAll with WithUserIDForSearch/WithUserIDForGetAll. Adding a member again
+// is not an error.
+//
+// Memberships are kept in the vector store (the collection name with a
+// "_teams" suffix), so they are shared by every client of the store. With
+// the audit log enabled (see AuditConfig), the change is recorded in it
+// first.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - teamID: Team identifier
+//   - userID: User joining the team
+//
+// Returns ErrInvalidInput without a team or a user, and ErrInvalidConfig if
+// the vector store does not support teams (the routing store).
+//
+// Example:
+//
+//	_ = client.AddTeamMember(ctx, "team_support", "user_001")
+//	_, _ = client.Add(ctx, "Refunds above $500 need a manager",
+//	    core.WithUserID("user_002"),
+//	    core.WithTeamID("team_support"))
+func (c *Client) AddTeamMember(ctx context.Context, teamID, userID string) error {
+	return c.changeTeam(ctx, "AddTeamMember", teamID, userID)
+}
+
+// RemoveTeamMember removes a user from a team. The user no longer sees the
+// memories of the team, but keeps the memories they added to it. Removing a
+// user who is not a member is not an error.
+//
+// Returns ErrInvalidInput without a team or a user, and ErrInvalidConfig if
+// the vector store does not support teams.
+//
+// Example:
+//
+//	_ = client.RemoveTeamMember(ctx, "team_support", "user_001")
+func (c *Client) RemoveTeamMember(ctx context.Context, teamID, userID string) error {
+	return c.changeTeam(ctx, "RemoveTeamMember", teamID, userID)
+}
+
+// changeTeam adds userID to teamID, or removes it, for op.
+func (c *Client) changeTeam(ctx context.Context, op, teamID, userID string) error {
+	ctx, err := c.begin(ctx, op)
+	if err != nil {
+		return err
+	}
+	defer c.end()
+
+	if teamID == "" || userID == "" {
+		return NewMemoryError(op, fmt.Errorf("%w: team ID and user ID are required", ErrInvalidInput))
+	}
+	teams, err := c.teamStore(op)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.audit(ctx, op, userID, map[string]interface{}{"team_id": teamID}); err != nil {
+		return NewMemoryError(op, err)
+	}
+	if op == "AddTeamMember" {
+		err = teams.AddTeamMember(ctx, teamID, userID)
+	} else {
+		err = teams.RemoveTeamMember(ctx, teamID, userID)
+	}
+	if err != nil {
+		return NewMemoryError(op, err)
+	}
+	return nil
+}
+
+// ListTeamMembers returns the members of a team, sorted.
+//
+// Returns ErrInvalidConfig if the vector store does not support teams.
+//
+// Example:
+//
+//	members, err := client.ListTeamMembers(ctx, "team_support")
+func (c *Client) ListTeamMembers(ctx context.Context, teamID string) ([]string, error) {
+	ctx, err := c.begin(ctx, "ListTeamMembers")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	teams, err := c.teamStore("ListTeamMembers")
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	members, err := teams.ListTeamMembers(ctx, teamID)
+	if err != nil {
+		return nil, NewMemoryError("ListTeamMembers", err)
+	}
+	return members, nil
+}
+
+// ListUserTeams returns the teams of a user, sorted.
+//
+// Returns ErrInvalidConfig if the vector store does not support teams.
+//
+// Example:
+//
+//	teams, err := client.ListUserTeams(ctx, "user_001")
+func (c *Client) ListUserTeams(ctx context.Context, userID string) ([]string, error) {
+	ctx, err := c.begin(ctx, "ListUserTeams")
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	teams, err := c.teamStore("ListUserTeams")
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	userTeams, err := teams.ListUserTeams(ctx, userID)
+	if err != nil {
+		return nil, NewMemoryError("ListUserTeams", err)
+	}
+	return userTeams, nil
+}
+
+// teamStore returns the vector store of the client as a TeamStore.
+func (c *Client) teamStore(op string) (storage.TeamStore, error) {
+	teams, ok := c.storage.(storage.TeamStore)
+	if !ok {
+		return nil, NewMemoryError(op, fmt.Errorf("%w: the %s vector store does not support teams", ErrInvalidConfig, c.config.VectorStore.resolvedProvider()))
+	}
+	return teams, nil
+}
+
+// userTeams returns the teams whose memories the reads of userID include:
+// none without a user or if the vector store does not support teams.
+func (c *Client) userTeams(ctx context.Context, userID string) ([]string, error) {
+	teams, ok := c.storage.(storage.TeamStore)
+	if userID == "" || !ok {
+		return nil, nil
+	}
+	return teams.ListUserTeams(ctx, userID)
+}
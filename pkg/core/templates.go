@@ -0,0 +1,135 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Memory types of the memory templates, stored in metadata["memory_type"].
+//
+// Memories of a type can be listed with GetAll and the filter
+// {"memory_type": MemoryTypeTask}, or searched with WithFilters.
+const (
+	// MemoryTypePreference is the memory type of RememberPreference.
+	MemoryTypePreference = "preference"
+
+	// MemoryTypeFact is the memory type of RememberFact.
+	MemoryTypeFact = "fact"
+
+	// MemoryTypeTask is the memory type of RememberTask.
+	MemoryTypeTask = "task"
+
+	// MemoryTypeRelationship is the memory type of RememberRelationship.
+	MemoryTypeRelationship = "relationship"
+)
+
+// TaskStatusOpen is the status of the tasks added by RememberTask, stored in
+// metadata["status"].
+const TaskStatusOpen = "open"
+
+// RememberPreference remembers a preference of the user about a topic.
+//
+// The memory has the content preference, the memory type
+// MemoryTypePreference and metadata["topic"] = topic.
+//
+// Like the other templates, the memory is added as given: WithInfer is
+// ignored, as fact extraction would lose the structure of the memory.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - topic: What the preference is about, e.g. "seating"
+//   - preference: The preference, e.g. "Prefers window seats"
+//   - opts: Optional Add options (UserID, AgentID, Tags, TTL, ...)
+//
+// Example:
+//
+//	memory, err := client.RememberPreference(ctx, "seating", "Prefers window seats",
+//	    core.WithUserID("user_001"))
+func (c *Client) RememberPreference(ctx context.Context, topic, preference string, opts ...AddOption) (*Memory, error) {
+	if strings.TrimSpace(topic) == "" || strings.TrimSpace(preference) == "" {
+		return nil, NewMemoryError("RememberPreference", fmt.Errorf("%w: topic and preference are required", ErrInvalidInput))
+	}
+	return c.remember(ctx, preference, MemoryTypePreference, map[string]interface{}{"topic": topic}, opts)
+}
+
+// RememberFact remembers a fact about the user or the world.
+//
+// The memory has the content fact and the memory type MemoryTypeFact.
+//
+// Example:
+//
+//	memory, err := client.RememberFact(ctx, "Is allergic to peanuts", core.WithUserID("user_001"))
+func (c *Client) RememberFact(ctx context.Context, fact string, opts ...AddOption) (*Memory, error) {
+	if strings.TrimSpace(fact) == "" {
+		return nil, NewMemoryError("RememberFact", fmt.Errorf("%w: fact is required", ErrInvalidInput))
+	}
+	return c.remember(ctx, fact, MemoryTypeFact, nil, opts)
+}
+
+// RememberTask remembers a task to do before dueDate.
+//
+// The memory has the content task, the memory type MemoryTypeTask,
+// metadata["status"] = TaskStatusOpen and metadata["due_date"], the due date
+// in RFC 3339 (UTC). A zero dueDate means the task has no due date. The task
+// does not expire at its due date: use WithExpiresAt for that.
+//
+// Example:
+//
+//	memory, err := client.RememberTask(ctx, "Renew the passport", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+//	    core.WithUserID("user_001"))
+func (c *Client) RememberTask(ctx context.Context, task string, dueDate time.Time, opts ...AddOption) (*Memory, error) {
+	if strings.TrimSpace(task) == "" {
+		return nil, NewMemoryError("RememberTask", fmt.Errorf("%w: task is required", ErrInvalidInput))
+	}
+	fields := map[string]interface{}{"status": TaskStatusOpen}
+	if !dueDate.IsZero() {
+		fields["due_date"] = dueDate.UTC().Format(time.RFC3339)
+	}
+	return c.remember(ctx, task, MemoryTypeTask, fields, opts)
+}
+
+// RememberRelationship remembers that entityA has the relation rel with
+// entityB, e.g. "Anna", "is the sister of", "Tom".
+//
+// The memory has the content "entityA rel entityB", the memory type
+// MemoryTypeRelationship and metadata["subject"] = entityA,
+// metadata["relation"] = rel and metadata["object"] = entityB, so the
+// relationships of an entity can be listed with a metadata filter. Both
+// entities are annotated (see SearchByEntity).
+//
+// Example:
+//
+//	memory, err := client.RememberRelationship(ctx, "Anna", "is the sister of", "Tom",
+//	    core.WithUserID("user_001"))
+func (c *Client) RememberRelationship(ctx context.Context, entityA, rel, entityB string, opts ...AddOption) (*Memory, error) {
+	entityA, rel, entityB = strings.TrimSpace(entityA), strings.TrimSpace(rel), strings.TrimSpace(entityB)
+	if entityA == "" || rel == "" || entityB == "" {
+		return nil, NewMemoryError("RememberRelationship", fmt.Errorf("%w: entities and relation are required", ErrInvalidInput))
+	}
+	content := fmt.Sprintf("%s %s %s", entityA, rel, entityB)
+	opts = append(opts[:len(opts):len(opts)], WithEntities(entityA, entityB))
+	return c.remember(ctx, content, MemoryTypeRelationship, map[string]interface{}{
+		"subject":  entityA,
+		"relation": rel,
+		"object":   entityB,
+	}, opts)
+}
+
+// remember adds a memory of a template: the metadata of opts gets fields, and
+// the memory type and no inference are forced.
+func (c *Client) remember(ctx context.Context, content, memoryType string, fields map[string]interface{}, opts []AddOption) (*Memory, error) {
+	addOpts := append([]AddOption{}, opts...)
+	addOpts = append(addOpts, func(opts *AddOptions) {
+		metadata := make(map[string]interface{}, len(opts.Metadata)+len(fields))
+		for k, v := range opts.Metadata {
+			metadata[k] = v
+		}
+		for k, v := range fields {
+			metadata[k] = v
+		}
+		opts.Metadata = metadata
+	}, WithMemoryType(memoryType), WithInfer(false))
+	return c.Add(ctx, content, addOpts...)
+}
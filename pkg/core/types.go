@@ -1,7 +1,10 @@
 // Package core provides the main PowerMem client and memory management functionality.
 package core
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Memory represents a single memory stored in the system.
 //
@@ -35,11 +38,11 @@ type Memory struct {
 	Content string `json:"content"`
 
 	// Embedding is the vector embedding for similarity search.
-	// Omitted from JSON to reduce payload size.
+	// Omitted from JSON to reduce payload size (see MarshalJSON).
 	Embedding []float64 `json:"embedding,omitempty"`
 
 	// SparseEmbedding is the sparse vector embedding (for hybrid search).
-	// Omitted from JSON to reduce payload size.
+	// Omitted from JSON to reduce payload size (see MarshalJSON).
 	SparseEmbedding map[int]float64 `json:"sparse_embedding,omitempty"`
 
 	// Metadata contains additional structured information about the memory.
@@ -64,6 +67,227 @@ type Memory struct {
 	// Score is the similarity score from search operations (0.0-1.0).
 	// Higher scores indicate better matches.
 	Score float64 `json:"score,omitempty"`
+
+	// Tags are free-form labels attached to the memory for filtering.
+	Tags []string `json:"tags,omitempty"`
+
+	// ExpiresAt is when the memory expires (nil if it never expires).
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// UID is the string ID (UUIDv7) of the memory, set when the client is
+	// configured with IDTypeUUID.
+	UID string `json:"uid,omitempty"`
+
+	// Version is incremented on every update. Pass it to WithExpectedVersion
+	// to make an update fail if the memory changed since it was read.
+	Version int64 `json:"version,omitempty"`
+
+	// ParentID is the ID of the memory this memory is a chunk of (0 if it is
+	// not a chunk, see ChunkingConfig).
+	ParentID int64 `json:"parent_id,omitempty"`
+
+	// ScoreComponents explains how Score was computed by intelligent ranking
+	// (nil if intelligent memory is disabled or for non-search operations).
+	ScoreComponents *ScoreComponents `json:"score_components,omitempty"`
+
+	// Sources tells where the memory came from, so that agents can cite it.
+	// It is read from metadata["sources"], set by WithSource and IntelligentAdd.
+	Sources []Source `json:"sources,omitempty"`
+
+	// Entities are the named entities the memory mentions (people, places,
+	// organizations...), used by SearchByEntity. They are read from
+	// metadata["entities"], set by WithEntities and the fact extraction of
+	// IntelligentAdd.
+	Entities []string `json:"entities,omitempty"`
+
+	// SharedFrom is the consent under which the memory was copied from
+	// another user (nil if it was not). It is read from
+	// metadata["shared_from"], set by ShareMemory.
+	SharedFrom *ShareConsent `json:"shared_from,omitempty"`
+
+	// Hash is the hex-encoded MD5 of the content, maintained by the store
+	// and compared by Config.SkipExactDuplicates.
+	Hash string `json:"hash,omitempty"`
+
+	// RunID is the run (session) the memory belongs to. It is read from
+	// metadata["run_id"], set by WithRunID.
+	RunID string `json:"run_id,omitempty"`
+
+	// Scope is the visibility scope of the memory. It is read from
+	// metadata["scope"], set by WithScope and WithTeamID.
+	Scope MemoryScope `json:"scope,omitempty"`
+
+	// MemoryType is the type of the memory, e.g. MemoryTypePreference. It is
+	// read from metadata["memory_type"], set by WithMemoryType and the
+	// memory templates.
+	MemoryType string `json:"memory_type,omitempty"`
+
+	// Language is the language of the memory, an ISO 639-1 code such as
+	// "en" or "zh". It is read from metadata["language"], set by
+	// WithLanguageForAdd or language detection (see LanguageConfig).
+	Language string `json:"language,omitempty"`
+
+	// Stale indicates a search result that was last confirmed longer ago
+	// than the staleness policy allows (see StalenessConfig): the agent
+	// should re-ask rather than trust it.
+	Stale bool `json:"stale,omitempty"`
+
+	// VerificationPrompt is a prompt telling the agent to ask the user
+	// whether a stale memory is still true (empty unless
+	// StalenessConfig.VerificationPrompt is set). Once the user confirms,
+	// call ConfirmMemory.
+	VerificationPrompt string `json:"verification_prompt,omitempty"`
+
+	// Explanation explains why a search result was retrieved (nil unless
+	// the search was run with WithExplain).
+	Explanation *SearchExplanation `json:"explanation,omitempty"`
+}
+
+// memoryJSON is Memory without its JSON methods, encoded with the struct tags.
+type memoryJSON Memory
+
+// MarshalJSON encodes the memory without its embeddings, which are large and
+// rarely needed by API clients, so that memories can be returned from web
+// handlers as they are. Use WithEmbeddings to include them. Decoding accepts
+// both forms.
+//
+// Example:
+//
+//	results, _ := client.Search(ctx, query, core.WithUserIDForSearch(userID))
+//	_ = json.NewEncoder(w).Encode(results)
+func (m Memory) MarshalJSON() ([]byte, error) {
+	encoded := memoryJSON(m)
+	encoded.Embedding = nil
+	encoded.SparseEmbedding = nil
+	return json.Marshal(encoded)
+}
+
+// MemoryWithEmbeddings is a Memory whose JSON encoding includes its
+// embeddings.
+type MemoryWithEmbeddings Memory
+
+// WithEmbeddings returns the memory as a MemoryWithEmbeddings, for encoding
+// it with its embeddings.
+//
+// Example:
+//
+//	data, err := json.Marshal(memory.WithEmbeddings())
+func (m *Memory) WithEmbeddings() *MemoryWithEmbeddings {
+	return (*MemoryWithEmbeddings)(m)
+}
+
+// Source identifies where a memory came from: a message of a conversation,
+// a document or a URL. All fields are optional.
+//
+// Example:
+//
+//	client.Add(ctx, "Deploys run on Fridays",
+//	    core.WithUserID("user_001"),
+//	    core.WithSource(core.Source{Document: "runbook.md"}),
+//	)
+type Source struct {
+	// MessageID is the ID of the message (the "id" or "message_id" of the
+	// messages passed to IntelligentAdd).
+	MessageID string `json:"message_id,omitempty"`
+
+	// Turn is the position of the message in the conversation, starting at 1.
+	Turn int `json:"turn,omitempty"`
+
+	// Document identifies the document, e.g. its path.
+	Document string `json:"document,omitempty"`
+
+	// URL is the URL of the document or web page.
+	URL string `json:"url,omitempty"`
+}
+
+// ScoreComponents contains the component scores of an intelligently ranked search result.
+type ScoreComponents struct {
+	// Similarity is the vector similarity score returned by the store.
+	Similarity float64 `json:"similarity"`
+
+	// Relevance is the keyword overlap between query and content.
+	Relevance float64 `json:"relevance"`
+
+	// Recency is the recency score (only with IntelligenceConfig.Ranking).
+	Recency *float64 `json:"recency,omitempty"`
+
+	// Importance is the importance score (only with IntelligenceConfig.Ranking).
+	Importance *float64 `json:"importance,omitempty"`
+
+	// Retention is the Ebbinghaus retention (decay factor).
+	Retention float64 `json:"retention"`
+
+	// Final is the combined score used for ranking.
+	Final float64 `json:"final"`
+}
+
+// SearchDiagnostics explains how the results of SearchWithDiagnostics were produced.
+type SearchDiagnostics struct {
+	// Query is the query passed to the search.
+	Query string `json:"query"`
+
+	// RetrievalMode is the retrieval mode used.
+	RetrievalMode RetrievalMode `json:"retrieval_mode"`
+
+	// HypotheticalDocument is the text generated by HyDE retrieval (empty
+	// if HyDE was not used or fell back to the raw query).
+	HypotheticalDocument string `json:"hypothetical_document,omitempty"`
+
+	// TranslatedQueries are the translations of the query searched with it
+	// (see LanguageConfig.TranslateQueries), by language.
+	TranslatedQueries map[string]string `json:"translated_queries,omitempty"`
+
+	// QueryRewritten indicates whether the query was rewritten before the
+	// search (set by callers that rewrite queries, such as user memory).
+	QueryRewritten bool `json:"query_rewritten"`
+
+	// RewrittenQuery is the rewritten query, if QueryRewritten is true.
+	RewrittenQuery string `json:"rewritten_query,omitempty"`
+
+	// TotalMemories is the number of memories in the store.
+	TotalMemories int64 `json:"total_memories"`
+
+	// Candidates is the number of memories matching the user, agent,
+	// metadata, time range, tag and expiration filters.
+	Candidates int64 `json:"candidates"`
+
+	// AboveThreshold is the number of candidates meeting the minimum score.
+	AboveThreshold int64 `json:"above_threshold"`
+
+	// Returned is the number of memories returned after the limit.
+	Returned int `json:"returned"`
+
+	// IntelligentRanking indicates whether intelligent re-ranking was applied.
+	IntelligentRanking bool `json:"intelligent_ranking"`
+
+	// RewriteTime is the time spent rewriting the query.
+	RewriteTime time.Duration `json:"rewrite_time"`
+
+	// LLMTime is the time spent generating the HyDE document.
+	LLMTime time.Duration `json:"llm_time"`
+
+	// EmbeddingTime is the time spent embedding the query.
+	EmbeddingTime time.Duration `json:"embedding_time"`
+
+	// CachedEmbeddings is the number of query embeddings read from the
+	// query cache (see QueryCacheConfig) instead of the embedder.
+	CachedEmbeddings int `json:"cached_embeddings"`
+
+	// StorageTime is the time spent in the store search.
+	StorageTime time.Duration `json:"storage_time"`
+
+	// RankingTime is the time spent in intelligent re-ranking.
+	RankingTime time.Duration `json:"ranking_time"`
+
+	// TotalTime is the total search time.
+	TotalTime time.Duration `json:"total_time"`
+
+	// storageSearches counts store searches (HyDE fusion runs two).
+	storageSearches int
+
+	// withoutStats skips the store counts, when only the rewrites of the
+	// query are collected (see WithExplain).
+	withoutStats bool
 }
 
 // MemoryScope defines the visibility scope of a memory.
@@ -72,6 +296,7 @@ type Memory struct {
 //   - ScopePrivate: Only the creating agent can access
 //   - ScopeAgentGroup: All agents in the group can access
 //   - ScopeGlobal: All agents can access
+//   - ScopeTeam: All members of the team can access (see WithTeamID)
 type MemoryScope string
 
 const (
@@ -83,6 +308,9 @@ const (
 
 	// ScopeGlobal makes the memory visible to all agents.
 	ScopeGlobal MemoryScope = "global"
+
+	// ScopeTeam makes the memory visible to all members of its team.
+	ScopeTeam MemoryScope = "team"
 )
 
 // MetricType defines the distance metric for vector similarity.
@@ -191,8 +419,8 @@ type VectorIndexConfig struct {
 // SearchResult contains the results of a search operation.
 type SearchResult struct {
 	// Memories is the list of matching memories, sorted by relevance.
-	Memories []*Memory
+	Memories []*Memory `json:"memories"`
 
 	// TotalCount is the total number of matching memories (may be > len(Memories) if paginated).
-	TotalCount int
+	TotalCount int `json:"total_count"`
 }
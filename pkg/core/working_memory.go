@@ -0,0 +1,270 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// DefaultWorkingMemoryTTL is the default lifetime of working memory items.
+const DefaultWorkingMemoryTTL = time.Hour
+
+// workingMemoryPageSize is the page size of the reads of working memory items.
+const workingMemoryPageSize = 100
+
+// Metadata of working memory items.
+const (
+	// memoryTypeKey is the metadata key of the memory type (see WithMemoryType).
+	memoryTypeKey = "memory_type"
+
+	// workingMemoryType is the memory type of working memory items.
+	workingMemoryType = "working"
+
+	// runIDKey is the metadata key of the run ID (see WithRunID).
+	runIDKey = storage.RunIDKey
+
+	// scopeKey is the metadata key of the memory scope (see WithScope).
+	scopeKey = "scope"
+)
+
+// WorkingMemory is a short-lived scratchpad scoped to a run (an agent
+// session or task).
+//
+// Items are memories with the run ID and the memory type "working" that
+// expire after the TTL of the working memory (DefaultWorkingMemoryTTL unless
+// set with WithWorkingMemoryTTL). They are only found by the searches of the
+// working memory: Client.Search leaves them out unless WithIncludeWorking is
+// given. At the end of the run, Client.Promote persists the items worth
+// keeping as long-term memories and discards the others.
+//
+// Example:
+//
+//	scratch := client.WorkingMemory("run_001", core.WithWorkingMemoryUserID("user_001"))
+//	_, _ = scratch.Add(ctx, "The user wants flights under 300 EUR")
+//	notes, _ := scratch.Search(ctx, "budget")
+//
+//	// Session end
+//	_, _ = client.Promote(ctx, "run_001", core.WithUserIDForPromote("user_001"))
+type WorkingMemory struct {
+	client  *Client
+	runID   string
+	options *WorkingMemoryOptions
+}
+
+// WorkingMemory returns the working memory of a run.
+//
+// Parameters:
+//   - runID: Run the items belong to
+//   - opts: Optional parameters (UserID, AgentID, TTL)
+func (c *Client) WorkingMemory(runID string, opts ...WorkingMemoryOption) *WorkingMemory {
+	return &WorkingMemory{client: c, runID: runID, options: applyWorkingMemoryOptions(opts)}
+}
+
+// RunID returns the run of the working memory.
+func (w *WorkingMemory) RunID() string {
+	return w.runID
+}
+
+// Add adds an item to the working memory.
+//
+// The item gets the user, agent and run of the working memory and expires
+// after its TTL; opts may add tags, metadata or a shorter expiration.
+func (w *WorkingMemory) Add(ctx context.Context, content string, opts ...AddOption) (*Memory, error) {
+	if w.runID == "" {
+		return nil, NewMemoryError("WorkingMemory.Add", fmt.Errorf("%w: run ID is required", ErrInvalidInput))
+	}
+	addOpts := append([]AddOption{
+		WithUserID(w.options.UserID),
+		WithAgentID(w.options.AgentID),
+		WithTTL(w.options.TTL),
+	}, opts...)
+	addOpts = append(addOpts, WithRunID(w.runID), WithMemoryType(workingMemoryType), WithInfer(false))
+	return w.client.Add(ctx, content, addOpts...)
+}
+
+// Search searches the items of the working memory.
+func (w *WorkingMemory) Search(ctx context.Context, query string, opts ...SearchOption) ([]*Memory, error) {
+	searchOpts := append([]SearchOption{
+		WithUserIDForSearch(w.options.UserID),
+		WithAgentIDForSearch(w.options.AgentID),
+	}, opts...)
+	searchOpts = append(searchOpts, w.searchScope, WithIncludeWorking(true))
+	return w.client.Search(ctx, query, searchOpts...)
+}
+
+// searchScope restricts a search to the items of the working memory. The
+// run scopes it, so a working memory without a user ID may search them.
+func (w *WorkingMemory) searchScope(opts *SearchOptions) {
+	opts.Filters = w.scope(opts.Filters)
+	opts.AllowCrossUser = true
+}
+
+// scope returns filters restricted to the items of the working memory.
+func (w *WorkingMemory) scope(filters map[string]interface{}) map[string]interface{} {
+	scoped := make(map[string]interface{}, len(filters)+2)
+	for k, v := range filters {
+		scoped[k] = v
+	}
+	scoped[runIDKey] = w.runID
+	scoped[memoryTypeKey] = workingMemoryType
+	return scoped
+}
+
+// Items returns the items of the working memory that did not expire, newest first.
+func (w *WorkingMemory) Items(ctx context.Context) ([]*Memory, error) {
+	var items []*Memory
+	for offset := 0; ; offset += workingMemoryPageSize {
+		page, err := w.client.GetAll(ctx,
+			WithUserIDForGetAll(w.options.UserID),
+			WithAgentIDForGetAll(w.options.AgentID),
+			WithFiltersForGetAll(w.scope(nil)),
+			WithAllowCrossUserForGetAll(true), // scoped by the run
+			WithLimitForGetAll(workingMemoryPageSize),
+			WithOffset(offset),
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if len(page) < workingMemoryPageSize {
+			return items, nil
+		}
+	}
+}
+
+// Clear discards the items of the working memory.
+func (w *WorkingMemory) Clear(ctx context.Context) error {
+	promoteOpts := &PromoteOptions{UserID: w.options.UserID, AgentID: w.options.AgentID}
+	_, err := w.client.endRun(ctx, "WorkingMemory.Clear", w.runID, promoteOpts, func(int64) bool { return false })
+	return err
+}
+
+// PromoteResult is the result of Promote.
+type PromoteResult struct {
+	// Promoted are the items persisted as long-term memories.
+	Promoted []*Memory `json:"promoted"`
+
+	// Discarded is the number of items deleted.
+	Discarded int `json:"discarded"`
+}
+
+// Promote ends the working memory of a run (see Client.WorkingMemory): the
+// selected items become long-term memories and the others are deleted.
+//
+// Promoted items no longer expire, get the memory type "long_term" and full
+// retention strength, and are found by Search; they keep the run ID. All the
+// items of the run are promoted unless WithPromoteIDs selects some of them.
+// Items that expired are not promoted.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - runID: Run whose working memory ends
+//   - opts: Optional parameters (UserID, AgentID, IDs)
+//
+// Example:
+//
+//	// Keep the decisions of the session, drop the rest of the scratchpad
+//	result, err := client.Promote(ctx, "run_001", core.WithPromoteIDs(decisionIDs...))
+func (c *Client) Promote(ctx context.Context, runID string, opts ...PromoteOption) (*PromoteResult, error) {
+	promoteOpts := applyPromoteOptions(opts)
+	selected := make(map[int64]bool, len(promoteOpts.IDs))
+	for _, id := range promoteOpts.IDs {
+		selected[id] = true
+	}
+	return c.endRun(ctx, "Promote", runID, promoteOpts, func(id int64) bool {
+		return len(selected) == 0 || selected[id]
+	})
+}
+
+// endRun ends the working memory of a run: the items for which keep returns
+// true are promoted, the others deleted.
+func (c *Client) endRun(ctx context.Context, op, runID string, promoteOpts *PromoteOptions, keep func(id int64) bool) (*PromoteResult, error) {
+	ctx, err := c.begin(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	events := c.recordEvents(ctx, op)
+	defer events.publish()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if runID == "" {
+		return nil, NewMemoryError(op, fmt.Errorf("%w: run ID is required", ErrInvalidInput))
+	}
+
+	// Read every item before changing them, as changes move items out of the filter
+	var items []*storage.Memory
+	for offset := 0; ; offset += workingMemoryPageSize {
+		page, err := c.storage.GetAll(ctx, &storage.GetAllOptions{
+			UserID:  promoteOpts.UserID,
+			AgentID: promoteOpts.AgentID,
+			Filters: map[string]interface{}{runIDKey: runID, memoryTypeKey: workingMemoryType},
+			Limit:   workingMemoryPageSize,
+			Offset:  offset,
+			Now:     c.now(),
+		})
+		if err != nil {
+			return nil, NewMemoryError(op, err)
+		}
+		items = append(items, page...)
+		if len(page) < workingMemoryPageSize {
+			break
+		}
+	}
+
+	result := &PromoteResult{Promoted: []*Memory{}}
+	strength := 1.0
+	for _, item := range items {
+		if !keep(item.ID) {
+			if err := c.storage.Delete(ctx, item.ID, &storage.DeleteOptions{UserID: item.UserID}); err != nil {
+				return result, NewMemoryError(op, err)
+			}
+			if err := c.deleteChunks(ctx, item.ID, item.UserID); err != nil {
+				return result, NewMemoryError(op, err)
+			}
+			events.add(&Event{Type: EventDeleted, Memory: fromStorageMemory(item)})
+			result.Discarded++
+			continue
+		}
+
+		metadata := copyMetadata(item.Metadata)
+		metadata[memoryTypeKey] = longTermMemoryType
+		promoted, err := c.storage.Update(ctx, item.ID, item.Content, item.Embedding, &storage.UpdateOptions{
+			UserID:            item.UserID,
+			Metadata:          metadata,
+			ExpectedVersion:   item.Version,
+			RetentionStrength: &strength,
+			ClearExpiresAt:    true,
+			Now:               c.now(),
+		})
+		if errors.Is(err, storage.ErrVersionConflict) {
+			// Changed since it was read: it is still a working memory item
+			log.Printf("Skipped promotion of memory %d: %v", item.ID, err)
+			continue
+		}
+		if err != nil {
+			return result, NewMemoryError(op, err)
+		}
+		if err := c.updateChunkMetadata(ctx, promoted); err != nil {
+			return result, NewMemoryError(op, err)
+		}
+		promotedMemory := fromStorageMemory(promoted)
+		events.add(&Event{Type: EventUpdated, Memory: promotedMemory, Diff: newEventDiff(fromStorageMemory(item), promotedMemory)})
+		result.Promoted = append(result.Promoted, promotedMemory)
+	}
+
+	return result, nil
+}
+
+// isWorking reports whether a memory is a working memory item.
+func isWorking(memory *storage.Memory) bool {
+	memoryType, _ := memory.Metadata[memoryTypeKey].(string)
+	return memoryType == workingMemoryType
+}
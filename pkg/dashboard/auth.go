@@ -0,0 +1,285 @@
+package dashboard
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Role is the role of an API key, ordered from the least to the most
+// privileged: each role is granted the operations of the previous ones.
+type Role string
+
+const (
+	// RoleReadOnly reads memories and profiles.
+	RoleReadOnly Role = "read_only"
+
+	// RoleWriter also adds, updates and deletes memories, in the scope of
+	// the key.
+	RoleWriter Role = "writer"
+
+	// RoleAdmin also reads the pages reserved to administrators, such as
+	// the audit log.
+	RoleAdmin Role = "admin"
+)
+
+// level returns the rank of the role, 0 if it is unknown.
+func (r Role) level() int {
+	switch r {
+	case RoleReadOnly:
+		return 1
+	case RoleWriter:
+		return 2
+	case RoleAdmin:
+		return 3
+	}
+	return 0
+}
+
+// APIKey is a key accepted by the dashboard, with its role and scope.
+// Key: secret sent by clients (required)
+// Name: name of the key, for operators (optional)
+// Role: role of the key (required)
+// UserIDs: users whose memories the key can access; empty for every user
+// AgentID: agent whose memories the key can access; empty for every agent
+type APIKey struct {
+	Name    string   `json:"name,omitempty"`
+	Key     string   `json:"key"`
+	Role    Role     `json:"role"`
+	UserIDs []string `json:"user_ids,omitempty"`
+	AgentID string   `json:"agent_id,omitempty"`
+}
+
+// Allows reports whether the role of the key grants role.
+func (k *APIKey) Allows(role Role) bool {
+	return k.Role.level() >= role.level() && role.level() > 0
+}
+
+// CanAccess reports whether the memories of userID and agentID are in the
+// scope of the key.
+func (k *APIKey) CanAccess(userID, agentID string) bool {
+	if k.AgentID != "" && agentID != k.AgentID {
+		return false
+	}
+	return k.CanAccessUser(userID)
+}
+
+// CanAccessUser reports whether userID is in the scope of the key. Keys
+// restricted to an agent can access its memories of these users, but not
+// the data of the users as a whole, such as their profiles.
+func (k *APIKey) CanAccessUser(userID string) bool {
+	if len(k.UserIDs) == 0 {
+		return true
+	}
+	for _, id := range k.UserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// scoped reports whether the key is restricted to some users or an agent.
+func (k *APIKey) scoped() bool {
+	return len(k.UserIDs) > 0 || k.AgentID != ""
+}
+
+// apiKeys holds the keys of a handler by the SHA-256 of their secret, so
+// that they are compared in constant time.
+type apiKeys struct {
+	digests [][sha256.Size]byte
+	keys    []*APIKey
+}
+
+// newAPIKeys checks keys and returns them ready for authentication.
+func newAPIKeys(keys []APIKey) (*apiKeys, error) {
+	a := &apiKeys{}
+	seen := make(map[string]bool)
+	for i := range keys {
+		key := keys[i]
+		if key.Key == "" {
+			return nil, fmt.Errorf("dashboard: API key %d has no key", i)
+		}
+		if key.Role.level() == 0 {
+			return nil, fmt.Errorf("dashboard: API key %d has unknown role %q (want read_only, writer or admin)", i, key.Role)
+		}
+		if seen[key.Key] {
+			return nil, fmt.Errorf("dashboard: API key %d is a duplicate", i)
+		}
+		seen[key.Key] = true
+		a.digests = append(a.digests, sha256.Sum256([]byte(key.Key)))
+		a.keys = append(a.keys, &key)
+	}
+	return a, nil
+}
+
+// lookup returns the key whose secret is secret, or nil.
+func (a *apiKeys) lookup(secret string) *APIKey {
+	digest := sha256.Sum256([]byte(secret))
+	var found *APIKey
+	for i := range a.digests {
+		if subtle.ConstantTimeCompare(digest[:], a.digests[i][:]) == 1 {
+			found = a.keys[i]
+		}
+	}
+	return found
+}
+
+// authenticator authenticates requests with API keys and JSON Web Tokens.
+type authenticator struct {
+	keys *apiKeys
+	jwt  *jwtVerifier // nil without JWTConfig
+}
+
+// newAuthenticator checks keys and jwt, which may be nil.
+func newAuthenticator(keys []APIKey, jwt *JWTConfig) (*authenticator, error) {
+	a := &authenticator{}
+	var err error
+	if a.keys, err = newAPIKeys(keys); err != nil {
+		return nil, err
+	}
+	if jwt != nil {
+		if a.jwt, err = newJWTVerifier(jwt); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// authenticate returns the key of the request, or nil if it has none or an
+// unknown one. The key, or a JSON Web Token, is read from the Authorization
+// header, as a bearer token or the password of basic authentication (for
+// browsers), or from the X-API-Key header.
+func (a *authenticator) authenticate(r *http.Request) *APIKey {
+	secret := r.Header.Get("X-API-Key")
+	if secret == "" {
+		if _, password, ok := r.BasicAuth(); ok {
+			secret = password
+		} else if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			secret = strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+		}
+	}
+	if secret == "" {
+		return nil
+	}
+
+	if key := a.keys.lookup(secret); key != nil {
+		return key
+	}
+	if a.jwt != nil && strings.Count(secret, ".") == 2 {
+		if key, err := a.jwt.verify(secret, time.Now()); err == nil {
+			return key
+		}
+	}
+	return nil
+}
+
+// RequireAPIKey returns a handler passing to next the requests that carry
+// one of keys (see Config.APIKeys) granting role, with the key in their
+// context (see APIKeyFromContext). Other requests are answered with 401 or
+// 403. next enforces the scope of the key, with APIKey.CanAccess.
+//
+// Example:
+//
+//	handler, err := dashboard.RequireAPIKey(keys, dashboard.RoleAdmin, adminHandler)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	mux.Handle("/admin/", handler)
+func RequireAPIKey(keys []APIKey, role Role, next http.Handler) (http.Handler, error) {
+	return RequireAuth(keys, nil, role, next)
+}
+
+// RequireAuth is RequireAPIKey also accepting the JSON Web Tokens of jwt
+// (see JWTConfig), if it is not nil, as keys.
+func RequireAuth(keys []APIKey, jwt *JWTConfig, role Role, next http.Handler) (http.Handler, error) {
+	auth, err := newAuthenticator(keys, jwt)
+	if err != nil {
+		return nil, err
+	}
+	return &keyHandler{auth: auth, role: role, next: next}, nil
+}
+
+// keyHandler authenticates the requests of a handler.
+type keyHandler struct {
+	auth *authenticator
+	role Role
+	next http.Handler
+}
+
+// ServeHTTP serves the request with the next handler if its API key grants
+// the role of the handler.
+func (k *keyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := k.auth.authenticate(r)
+	if key == nil {
+		w.Header().Set("WWW-Authenticate", `Basic realm="powermem"`)
+		http.Error(w, "missing or invalid API key", http.StatusUnauthorized)
+		return
+	}
+	if !key.Allows(k.role) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	k.next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, key)))
+}
+
+// apiKeyContextKey is the context key of the API key of a request.
+type apiKeyContextKey struct{}
+
+// APIKeyFromContext returns the API key that authenticated the request of
+// ctx, or nil if it was not authenticated by RequireAPIKey, RequireAuth or
+// a dashboard with authentication.
+func APIKeyFromContext(ctx context.Context) *APIKey {
+	key, _ := ctx.Value(apiKeyContextKey{}).(*APIKey)
+	return key
+}
+
+// canAccess reports whether the memories of userID and agentID can be
+// shown to the request.
+func canAccess(r *http.Request, userID, agentID string) bool {
+	key := APIKeyFromContext(r.Context())
+	return key == nil || key.CanAccess(userID, agentID)
+}
+
+// canAccessUser reports whether the data of userID as a whole, such as the
+// tags and profile, can be shown to the request.
+func canAccessUser(r *http.Request, userID string) bool {
+	key := APIKeyFromContext(r.Context())
+	return key == nil || (key.AgentID == "" && key.CanAccessUser(userID))
+}
+
+// isScoped reports whether the request is restricted to some users or an
+// agent, and so cannot see pages spanning users.
+func isScoped(r *http.Request) bool {
+	key := APIKeyFromContext(r.Context())
+	return key != nil && key.scoped()
+}
+
+// isAdmin reports whether the request can see the pages reserved to
+// administrators, such as the audit log: it needs an API key granting
+// RoleAdmin and not restricted to some users or an agent. Requests without
+// a key, to a dashboard without authentication, are not administrators.
+func isAdmin(r *http.Request) bool {
+	key := APIKeyFromContext(r.Context())
+	return key != nil && key.Allows(RoleAdmin) && !key.scoped()
+}
+
+// canWrite reports whether the request can add, update and delete memories,
+// in the scope of its key: it needs an API key granting RoleWriter.
+// Requests without a key, to a dashboard without authentication, cannot.
+func canWrite(r *http.Request) bool {
+	key := APIKeyFromContext(r.Context())
+	return key != nil && key.Allows(RoleWriter)
+}
+
+// agentScope returns the agent the request is restricted to, or "".
+func agentScope(r *http.Request) string {
+	if key := APIKeyFromContext(r.Context()); key != nil {
+		return key.AgentID
+	}
+	return ""
+}
@@ -0,0 +1,639 @@
+// Package dashboard provides a web dashboard for inspecting memory stores:
+// the memories of each user, similarity search, user profiles, retention
+// curves, clusters of near-duplicate memories and the audit log. With
+// authentication, it also serves a JSON API adding, updating and deleting
+// memories to the keys granting RoleWriter.
+//
+// The dashboard is an http.Handler, so it can be embedded in an application
+// server or served by the powermem command-line tool (powermem dashboard).
+// Links between pages are relative, so the handler can be mounted under any
+// path ending with a slash:
+//
+//	handler, err := dashboard.NewHandler(&dashboard.Config{Memory: client})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	mux.Handle("/dashboard/", http.StripPrefix("/dashboard", handler))
+//
+// Every page is also available as JSON by adding format=json to its query.
+//
+// Without Config.APIKeys or Config.JWT, the dashboard has no authentication
+// of its own: it shows the content of every memory, so serve it only behind
+// the application's access control. It then neither writes nor shows the
+// audit log, which need a key granting their role. With authentication,
+// every request needs a key or a token, and keys restricted to some users or
+// an agent only see and write their memories.
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	usermemory "github.com/oceanbase/powermem-go/pkg/user_memory"
+)
+
+// ProfileSource provides user profiles to the dashboard.
+// *usermemory.Client implements it.
+type ProfileSource interface {
+	// GetProfile returns the profile of a user, or nil if there is none.
+	GetProfile(ctx context.Context, userID string) (*usermemory.UserProfile, error)
+
+	// GetProfiles returns the profiles matching opts.
+	GetProfiles(ctx context.Context, opts *usermemory.GetProfilesOptions) ([]*usermemory.UserProfile, error)
+}
+
+// Config is the configuration of the dashboard.
+// Memory: client of the memory store (required)
+// Profiles: source of user profiles (optional, profile pages are hidden without it)
+// DecayRate: Ebbinghaus decay rate of the retention curves, defaults to 0.1 (use the client's intelligence.decay_rate)
+// DuplicateThreshold: default similarity threshold of duplicate clusters, defaults to the client's
+// PageSize: number of memories per page, defaults to 50
+// APIKeys: keys required to access the dashboard (optional, no authentication without them or JWT)
+// JWT: JSON Web Tokens accepted as keys (optional)
+type Config struct {
+	Memory             *core.Client
+	Profiles           ProfileSource
+	DecayRate          float64
+	DuplicateThreshold float64
+	PageSize           int
+	APIKeys            []APIKey
+	JWT                *JWTConfig
+}
+
+// Handler serves the dashboard.
+// It implements the http.Handler interface.
+type Handler struct {
+	memory             *core.Client
+	profiles           ProfileSource
+	decayRate          float64
+	duplicateThreshold float64
+	pageSize           int
+
+	mux *http.ServeMux
+
+	// pages serves the pages: mux, behind RequireAuth with authentication.
+	pages http.Handler
+}
+
+// maxWriteBodySize bounds the size of the bodies of write requests.
+const maxWriteBodySize = 1 << 20
+
+// scanPageSize is the number of memories fetched per call when the overview
+// counts the memories of every user.
+const scanPageSize = 500
+
+// NewHandler creates the dashboard handler.
+//
+// Args:
+//   - cfg: dashboard configuration
+//
+// Returns:
+//   - *Handler: dashboard handler
+//   - error: Returns an error if Memory is not set, or an API key or the JWT configuration is invalid
+func NewHandler(cfg *Config) (*Handler, error) {
+	if cfg == nil || cfg.Memory == nil {
+		return nil, errors.New("dashboard: Memory is required")
+	}
+	h := &Handler{
+		memory:             cfg.Memory,
+		profiles:           cfg.Profiles,
+		decayRate:          cfg.DecayRate,
+		duplicateThreshold: cfg.DuplicateThreshold,
+		pageSize:           cfg.PageSize,
+	}
+	if h.decayRate <= 0 {
+		h.decayRate = 0.1
+	}
+	if h.pageSize <= 0 {
+		h.pageSize = 50
+	}
+
+	h.mux = http.NewServeMux()
+	h.mux.HandleFunc("/", h.serveOverview)
+	h.mux.HandleFunc("/user", h.serveUser)
+	h.mux.HandleFunc("/memory", h.serveMemory)
+	h.mux.HandleFunc("/memories", h.serveAddMemory)
+	h.mux.HandleFunc("/duplicates", h.serveDuplicates)
+	h.mux.HandleFunc("/profiles", h.serveProfiles)
+	h.mux.HandleFunc("/audit", h.serveAudit)
+
+	h.pages = h.mux
+	if len(cfg.APIKeys) > 0 || cfg.JWT != nil {
+		var err error
+		if h.pages, err = RequireAuth(cfg.APIKeys, cfg.JWT, RoleReadOnly, h.mux); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// ServeHTTP serves a dashboard page, or a write of the JSON API: POST to
+// memories, and PUT or DELETE to memory. Pages need an API key granting
+// RoleReadOnly if the handler has authentication, and writes one granting
+// RoleWriter.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	allowed := []string{http.MethodGet, http.MethodHead}
+	switch r.URL.Path {
+	case "/memories":
+		allowed = []string{http.MethodPost}
+	case "/memory":
+		allowed = append(allowed, http.MethodPut, http.MethodDelete)
+	}
+	for _, method := range allowed {
+		if r.Method == method {
+			h.pages.ServeHTTP(w, r)
+			return
+		}
+	}
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+}
+
+// UserSummary is a user listed on the overview page.
+type UserSummary struct {
+	UserID   string    `json:"user_id"`
+	Memories int       `json:"memories"`
+	Newest   time.Time `json:"newest"`
+}
+
+// overviewPage is the data of the overview page.
+type overviewPage struct {
+	Total int            `json:"total"`
+	Users []*UserSummary `json:"users"`
+}
+
+// serveOverview lists the users with their number of memories.
+func (h *Handler) serveOverview(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	users := make(map[string]*UserSummary)
+	page := &overviewPage{}
+	for offset := 0; ; offset += scanPageSize {
+		memories, err := h.memory.GetAll(r.Context(), core.WithAllowCrossUserForGetAll(true),
+			core.WithLimitForGetAll(scanPageSize), core.WithOffset(offset))
+		if err != nil {
+			h.serveError(w, r, err)
+			return
+		}
+		for _, memory := range memories {
+			if !canAccess(r, memory.UserID, memory.AgentID) {
+				continue
+			}
+			page.Total++
+			user, ok := users[memory.UserID]
+			if !ok {
+				user = &UserSummary{UserID: memory.UserID}
+				users[memory.UserID] = user
+			}
+			user.Memories++
+			if memory.CreatedAt.After(user.Newest) {
+				user.Newest = memory.CreatedAt
+			}
+		}
+		if len(memories) < scanPageSize {
+			break
+		}
+	}
+
+	page.Users = make([]*UserSummary, 0, len(users))
+	for _, user := range users {
+		page.Users = append(page.Users, user)
+	}
+	sort.Slice(page.Users, func(i, j int) bool {
+		if page.Users[i].Memories != page.Users[j].Memories {
+			return page.Users[i].Memories > page.Users[j].Memories
+		}
+		return page.Users[i].UserID < page.Users[j].UserID
+	})
+	h.render(w, r, "overview", "Users", page)
+}
+
+// userPage is the data of the user page.
+type userPage struct {
+	UserID   string                  `json:"user_id"`
+	Query    string                  `json:"query,omitempty"`
+	Profile  *usermemory.UserProfile `json:"profile,omitempty"`
+	Memories []*core.Memory          `json:"memories"`
+	Tags     []string                `json:"tags,omitempty"`
+
+	// Offset and NextOffset paginate the memories when there is no query
+	// (NextOffset is 0 on the last page).
+	Offset     int `json:"offset"`
+	NextOffset int `json:"next_offset,omitempty"`
+	PrevOffset int `json:"-"`
+}
+
+// serveUser shows the profile and memories of a user, or the results of a
+// search among them.
+func (h *Handler) serveUser(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page := &userPage{UserID: query.Get("id"), Query: strings.TrimSpace(query.Get("q"))}
+	ctx := r.Context()
+	if !canAccess(r, page.UserID, agentScope(r)) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var err error
+	if page.Query != "" {
+		page.Memories, err = h.memory.Search(ctx, page.Query,
+			core.WithUserIDForSearch(page.UserID), core.WithAgentIDForSearch(agentScope(r)),
+			core.WithLimit(h.pageSize))
+	} else {
+		page.Offset = nonNegativeInt(query.Get("offset"))
+		page.Memories, err = h.memory.GetAll(ctx, core.WithUserIDForGetAll(page.UserID),
+			core.WithAgentIDForGetAll(agentScope(r)),
+			core.WithLimitForGetAll(h.pageSize), core.WithOffset(page.Offset))
+		if len(page.Memories) == h.pageSize {
+			page.NextOffset = page.Offset + h.pageSize
+		}
+		if page.PrevOffset = page.Offset - h.pageSize; page.PrevOffset < 0 {
+			page.PrevOffset = 0
+		}
+	}
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+	// Tags and profiles also cover the memories of other agents
+	if !canAccessUser(r, page.UserID) {
+		h.render(w, r, "user", "User "+displayUser(page.UserID), page)
+		return
+	}
+	if page.Tags, err = h.memory.ListTags(ctx, page.UserID); err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+	if h.profiles != nil {
+		if page.Profile, err = h.profiles.GetProfile(ctx, page.UserID); err != nil {
+			h.serveError(w, r, err)
+			return
+		}
+	}
+	h.render(w, r, "user", "User "+displayUser(page.UserID), page)
+}
+
+// memoryPage is the data of the memory page.
+type memoryPage struct {
+	Memory    *core.Memory    `json:"memory"`
+	Retention *RetentionCurve `json:"retention"`
+}
+
+// serveMemory shows a memory and its retention curve, or updates or
+// deletes it.
+func (h *Handler) serveMemory(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		h.serveUpdateMemory(w, r)
+		return
+	case http.MethodDelete:
+		h.serveDeleteMemory(w, r)
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	memory, err := h.lookupMemory(r, id)
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+
+	page := &memoryPage{Memory: memory, Retention: NewRetentionCurve(memory, h.decayRate, time.Now())}
+	h.render(w, r, "memory", "Memory "+id, page)
+}
+
+// lookupMemory returns the memory with ID or string ID id, as not found if
+// it is out of the scope of the request.
+func (h *Handler) lookupMemory(r *http.Request, id string) (*core.Memory, error) {
+	var memory *core.Memory
+	var err error
+	if numericID, parseErr := strconv.ParseInt(id, 10, 64); parseErr == nil {
+		memory, err = h.memory.Get(r.Context(), numericID)
+	} else {
+		memory, err = h.memory.GetByUID(r.Context(), id)
+	}
+	if err == nil && !canAccess(r, memory.UserID, memory.AgentID) {
+		err = core.ErrNotFound
+	}
+	return memory, err
+}
+
+// writeRequest is the body of the requests adding or updating a memory.
+// UserID, AgentID and Tags are only read when adding; Metadata replaces
+// the metadata of an updated memory if it is set.
+type writeRequest struct {
+	Content  string                 `json:"content"`
+	UserID   string                 `json:"user_id,omitempty"`
+	AgentID  string                 `json:"agent_id,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Tags     []string               `json:"tags,omitempty"`
+}
+
+// readWriteRequest checks that the request can write and decodes its body.
+// It answers the request and returns false otherwise.
+func readWriteRequest(w http.ResponseWriter, r *http.Request, body *writeRequest) bool {
+	if !canWrite(r) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return false
+	}
+	if body == nil {
+		return true
+	}
+	// Browsers send cross-site requests with a JSON type only after a
+	// preflight, so the type guards the writes of basic authentication
+	if contentType := r.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+		http.Error(w, "the body must be JSON (Content-Type: application/json)", http.StatusUnsupportedMediaType)
+		return false
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWriteBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(body); err != nil {
+		http.Error(w, "invalid body: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	if strings.TrimSpace(body.Content) == "" {
+		http.Error(w, "content is required", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// serveAddMemory adds a memory for the user and agent of the body, which
+// must be in the scope of the request. The agent defaults to the agent the
+// key is restricted to.
+func (h *Handler) serveAddMemory(w http.ResponseWriter, r *http.Request) {
+	body := &writeRequest{}
+	if !readWriteRequest(w, r, body) {
+		return
+	}
+	if body.AgentID == "" {
+		body.AgentID = agentScope(r)
+	}
+	if !canAccess(r, body.UserID, body.AgentID) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	opts := []core.AddOption{core.WithUserID(body.UserID), core.WithAgentID(body.AgentID)}
+	if body.Metadata != nil {
+		opts = append(opts, core.WithMetadata(body.Metadata))
+	}
+	if len(body.Tags) > 0 {
+		opts = append(opts, core.WithTags(body.Tags...))
+	}
+	memory, err := h.memory.Add(r.Context(), body.Content, opts...)
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, memory)
+}
+
+// serveUpdateMemory replaces the content, and the metadata if set, of a
+// memory in the scope of the request.
+func (h *Handler) serveUpdateMemory(w http.ResponseWriter, r *http.Request) {
+	body := &writeRequest{}
+	if !readWriteRequest(w, r, body) {
+		return
+	}
+	memory, err := h.lookupMemory(r, r.URL.Query().Get("id"))
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+
+	// The owners are checked again by the update, in case they changed
+	opts := []core.UpdateOption{
+		core.WithUserIDForUpdate(memory.UserID),
+		core.WithAgentIDForUpdate(memory.AgentID),
+		core.WithExpectedVersion(memory.Version),
+	}
+	if body.Metadata != nil {
+		opts = append(opts, core.WithMetadataForUpdate(body.Metadata))
+	}
+	if memory, err = h.memory.Update(r.Context(), memory.ID, body.Content, opts...); err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, memory)
+}
+
+// serveDeleteMemory deletes a memory in the scope of the request.
+func (h *Handler) serveDeleteMemory(w http.ResponseWriter, r *http.Request) {
+	if !readWriteRequest(w, r, nil) {
+		return
+	}
+	memory, err := h.lookupMemory(r, r.URL.Query().Get("id"))
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+	err = h.memory.Delete(r.Context(), memory.ID,
+		core.WithUserIDForDelete(memory.UserID), core.WithAgentIDForDelete(memory.AgentID))
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// duplicatesPage is the data of the duplicates page.
+type duplicatesPage struct {
+	UserID    string                   `json:"user_id"`
+	Threshold float64                  `json:"threshold,omitempty"`
+	Clusters  []*core.DuplicateCluster `json:"clusters"`
+}
+
+// serveDuplicates shows the clusters of near-duplicate memories of a user.
+func (h *Handler) serveDuplicates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page := &duplicatesPage{UserID: query.Get("user"), Threshold: h.duplicateThreshold}
+	if value := query.Get("threshold"); value != "" {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold < 0 || threshold > 1 {
+			http.Error(w, "threshold must be a number between 0 and 1", http.StatusBadRequest)
+			return
+		}
+		page.Threshold = threshold
+	}
+	if isScoped(r) && (page.UserID == "" || !canAccessUser(r, page.UserID)) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	clusters, err := h.memory.FindDuplicates(r.Context(), page.UserID, page.Threshold)
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+	page.Clusters = clusters
+	h.render(w, r, "duplicates", "Duplicates of "+displayUser(page.UserID), page)
+}
+
+// profilesPage is the data of the profiles page.
+type profilesPage struct {
+	Profiles   []*usermemory.UserProfile `json:"profiles"`
+	Offset     int                       `json:"offset"`
+	NextOffset int                       `json:"next_offset,omitempty"`
+	PrevOffset int                       `json:"-"`
+}
+
+// serveProfiles lists the user profiles.
+func (h *Handler) serveProfiles(w http.ResponseWriter, r *http.Request) {
+	if h.profiles == nil {
+		http.Error(w, "no profile store is configured", http.StatusNotFound)
+		return
+	}
+	if isScoped(r) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	page := &profilesPage{Offset: nonNegativeInt(r.URL.Query().Get("offset"))}
+	profiles, err := h.profiles.GetProfiles(r.Context(), &usermemory.GetProfilesOptions{
+		Limit:  h.pageSize,
+		Offset: page.Offset,
+	})
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+	page.Profiles = profiles
+	if len(profiles) == h.pageSize {
+		page.NextOffset = page.Offset + h.pageSize
+	}
+	if page.PrevOffset = page.Offset - h.pageSize; page.PrevOffset < 0 {
+		page.PrevOffset = 0
+	}
+	h.render(w, r, "profiles", "Profiles", page)
+}
+
+// auditPage is the data of the audit log page.
+type auditPage struct {
+	Operation string             `json:"operation,omitempty"`
+	UserID    string             `json:"user_id,omitempty"`
+	Actor     string             `json:"actor,omitempty"`
+	Entries   []*core.AuditEntry `json:"entries"`
+	After     int64              `json:"after"`
+	NextAfter int64              `json:"next_after,omitempty"`
+}
+
+// serveAudit lists the entries of the audit log, oldest first, to
+// administrators.
+func (h *Handler) serveAudit(w http.ResponseWriter, r *http.Request) {
+	if !isAdmin(r) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	query := r.URL.Query()
+	page := &auditPage{
+		Operation: query.Get("operation"),
+		UserID:    query.Get("user"),
+		Actor:     query.Get("actor"),
+		After:     int64(nonNegativeInt(query.Get("after"))),
+	}
+	opts := []core.AuditOption{
+		core.WithAuditAfter(page.After),
+		core.WithUserIDForAudit(page.UserID),
+		core.WithActorForAudit(page.Actor),
+		core.WithAuditLimit(h.pageSize),
+	}
+	if page.Operation != "" {
+		opts = append(opts, core.WithAuditOperations(page.Operation))
+	}
+	entries, err := h.memory.ListAuditEntries(r.Context(), opts...)
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+	page.Entries = entries
+	if len(entries) == h.pageSize {
+		page.NextAfter = entries[len(entries)-1].Seq
+	}
+	h.render(w, r, "audit", "Audit log", page)
+}
+
+// render writes page as JSON if the query has format=json, and as the HTML
+// template name otherwise.
+func (h *Handler) render(w http.ResponseWriter, r *http.Request, name, title string, page interface{}) {
+	if r.URL.Query().Get("format") == "json" {
+		writeJSON(w, http.StatusOK, page)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := templates.ExecuteTemplate(w, name, &layout{
+		Title:       title,
+		HasProfiles: h.profiles != nil && !isScoped(r),
+		HasAudit:    isAdmin(r),
+		Page:        page,
+	})
+	if err != nil {
+		// The response has started; the error can only be reported inline
+		fmt.Fprintf(w, "<p class=error>render: %s</p>", htmlEscape(err.Error()))
+	}
+}
+
+// writeJSON writes v as the JSON response, with status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(v)
+}
+
+// serveError reports err, as 404 if the memory was not found, 409 if it
+// changed during an update and 400 if the input is invalid.
+func (h *Handler) serveError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, core.ErrNotFound) || strings.Contains(err.Error(), "not found"):
+		status = http.StatusNotFound
+	case errors.Is(err, core.ErrVersionConflict):
+		status = http.StatusConflict
+	case errors.Is(err, core.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+	if errors.Is(r.Context().Err(), context.Canceled) {
+		return
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// layout is the data of every HTML page.
+type layout struct {
+	Title       string
+	HasProfiles bool
+	HasAudit    bool
+	Page        interface{}
+}
+
+// nonNegativeInt parses a pagination offset, returning 0 if it is invalid.
+func nonNegativeInt(value string) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// displayUser returns the name shown for a user ID.
+func displayUser(userID string) string {
+	if userID == "" {
+		return "(none)"
+	}
+	return userID
+}
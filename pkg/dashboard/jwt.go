@@ -0,0 +1,159 @@
+package dashboard
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// minJWTSecretSize is the minimum size of the secret of JSON Web Tokens: the
+// size of the HS256 hash, as recommended by RFC 7518.
+const minJWTSecretSize = sha256.Size
+
+// JWTConfig configures the JSON Web Tokens accepted in place of API keys.
+// Tokens are signed with HS256 and must expire (exp claim); their role,
+// user_ids and agent_id claims are the Role, UserIDs and AgentID of an
+// APIKey, and their sub claim its Name.
+// Secret: HMAC key signing the tokens, at least 32 bytes (required)
+// Issuer: iss claim required in tokens (optional)
+// Audience: audience required in the aud claim of tokens (optional)
+type JWTConfig struct {
+	Secret   []byte
+	Issuer   string
+	Audience string
+}
+
+// jwtVerifier checks JSON Web Tokens.
+type jwtVerifier struct {
+	config JWTConfig
+}
+
+// newJWTVerifier checks cfg and returns a verifier of its tokens.
+func newJWTVerifier(cfg *JWTConfig) (*jwtVerifier, error) {
+	if len(cfg.Secret) < minJWTSecretSize {
+		return nil, fmt.Errorf("dashboard: JWT secret has %d bytes (want at least %d)", len(cfg.Secret), minJWTSecretSize)
+	}
+	return &jwtVerifier{config: *cfg}, nil
+}
+
+// jwtClaims are the claims of a token read by the dashboard.
+type jwtClaims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *float64 `json:"exp"`
+	NotBefore *float64 `json:"nbf"`
+	Role      Role     `json:"role"`
+	UserIDs   []string `json:"user_ids"`
+	AgentID   string   `json:"agent_id"`
+}
+
+// audience is the aud claim of a token: a string or an array of strings.
+type audience []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return errors.New("aud is neither a string nor an array of strings")
+	}
+	*a = many
+	return nil
+}
+
+// verify checks the signature and claims of token at now, and returns the
+// key it stands for.
+func (v *jwtVerifier) verify(token string, now time.Time) (*APIKey, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("header: %w", err)
+	}
+	// Only HS256 is accepted, so that "none" or a public-key algorithm
+	// cannot be substituted for it
+	if header.Algorithm != "HS256" {
+		return nil, fmt.Errorf("unsupported algorithm %q", header.Algorithm)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("signature: %w", err)
+	}
+	mac := hmac.New(sha256.New, v.config.Secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, errors.New("invalid signature")
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("claims: %w", err)
+	}
+	if claims.ExpiresAt == nil {
+		return nil, errors.New("token has no expiration")
+	}
+	if !now.Before(unixTime(*claims.ExpiresAt)) {
+		return nil, errors.New("token has expired")
+	}
+	if claims.NotBefore != nil && now.Before(unixTime(*claims.NotBefore)) {
+		return nil, errors.New("token is not valid yet")
+	}
+	if v.config.Issuer != "" && claims.Issuer != v.config.Issuer {
+		return nil, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if v.config.Audience != "" && !claims.Audience.contains(v.config.Audience) {
+		return nil, errors.New("token is not meant for this audience")
+	}
+	if claims.Role.level() == 0 {
+		return nil, fmt.Errorf("unknown role %q", claims.Role)
+	}
+
+	return &APIKey{
+		Name:    claims.Subject,
+		Role:    claims.Role,
+		UserIDs: claims.UserIDs,
+		AgentID: claims.AgentID,
+	}, nil
+}
+
+// contains reports whether the audience includes name.
+func (a audience) contains(name string) bool {
+	for _, value := range a {
+		if value == name {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeSegment decodes a base64url-encoded JSON segment of a token into v.
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// unixTime returns the time of a NumericDate claim, in seconds since the
+// epoch.
+func unixTime(seconds float64) time.Time {
+	whole := math.Floor(seconds)
+	return time.Unix(int64(whole), int64((seconds-whole)*float64(time.Second)))
+}
@@ -0,0 +1,126 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// Dimensions of the retention chart, in SVG user units.
+const (
+	chartWidth  = 480
+	chartHeight = 160
+)
+
+// RetentionPoint is a point of a retention curve.
+type RetentionPoint struct {
+	// Days is the time since the memory was last accessed (or created).
+	Days float64 `json:"days"`
+
+	// Retention is the retention strength at that time (0.0-1.0).
+	Retention float64 `json:"retention"`
+}
+
+// RetentionCurve is the Ebbinghaus forgetting curve of a memory: its
+// retention since it was last accessed (or created), projected into the
+// future.
+//
+// Retention follows R = e^(-decay_rate * days), as computed by
+// intelligence.EbbinghausManager.
+type RetentionCurve struct {
+	// DecayRate is the decay rate of the curve.
+	DecayRate float64 `json:"decay_rate"`
+
+	// Now is the point of the curve at the time it was computed.
+	Now RetentionPoint `json:"now"`
+
+	// Points sample the curve from day 0 to at least two weeks after Now.
+	Points []RetentionPoint `json:"points"`
+}
+
+// retentionSamples is the number of points of a retention curve.
+const retentionSamples = 61
+
+// NewRetentionCurve computes the retention curve of memory at now.
+//
+// Args:
+//   - memory: the memory
+//   - decayRate: Ebbinghaus decay rate (per day)
+//   - now: current time
+//
+// Returns:
+//   - *RetentionCurve: the curve
+func NewRetentionCurve(memory *core.Memory, decayRate float64, now time.Time) *RetentionCurve {
+	since := memory.CreatedAt
+	if memory.LastAccessedAt != nil {
+		since = *memory.LastAccessedAt
+	}
+	elapsed := now.Sub(since).Hours() / 24
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	// Show at least 30 days, and two weeks past now
+	span := math.Max(30, elapsed+14)
+	curve := &RetentionCurve{
+		DecayRate: decayRate,
+		Now:       RetentionPoint{Days: elapsed, Retention: retentionAt(decayRate, elapsed)},
+		Points:    make([]RetentionPoint, retentionSamples),
+	}
+	for i := range curve.Points {
+		days := span * float64(i) / float64(retentionSamples-1)
+		curve.Points[i] = RetentionPoint{Days: days, Retention: retentionAt(decayRate, days)}
+	}
+	return curve
+}
+
+// retentionAt returns the retention after days.
+func retentionAt(decayRate, days float64) float64 {
+	return math.Exp(-decayRate * days)
+}
+
+// span returns the number of days covered by the curve.
+func (c *RetentionCurve) span() float64 {
+	return c.Points[len(c.Points)-1].Days
+}
+
+// x returns the chart abscissa of days.
+func (c *RetentionCurve) x(days float64) float64 {
+	return days / c.span() * chartWidth
+}
+
+// y returns the chart ordinate of retention.
+func (c *RetentionCurve) y(retention float64) float64 {
+	return (1 - retention) * chartHeight
+}
+
+// Polyline returns the points of the curve as the points attribute of an
+// SVG polyline.
+func (c *RetentionCurve) Polyline() string {
+	var b strings.Builder
+	for i, point := range c.Points {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%.1f,%.1f", c.x(point.Days), c.y(point.Retention))
+	}
+	return b.String()
+}
+
+// NowX returns the chart abscissa of Now.
+func (c *RetentionCurve) NowX() string {
+	return fmt.Sprintf("%.1f", c.x(c.Now.Days))
+}
+
+// NowY returns the chart ordinate of Now.
+func (c *RetentionCurve) NowY() string {
+	return fmt.Sprintf("%.1f", c.y(c.Now.Retention))
+}
+
+// SpanDays returns the number of days covered by the curve, rounded.
+func (c *RetentionCurve) SpanDays() int {
+	return int(math.Round(c.span()))
+}
@@ -0,0 +1,213 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"html/template"
+	"strconv"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// htmlEscape escapes text for inclusion in HTML.
+var htmlEscape = template.HTMLEscapeString
+
+// memoryTable is the data of the memories template.
+type memoryTable struct {
+	Memories []*core.Memory
+	Score    bool
+}
+
+// templateFuncs are the functions available to the templates.
+var templateFuncs = template.FuncMap{
+	"id": func(memory *core.Memory) string {
+		if memory.UID != "" {
+			return memory.UID
+		}
+		return strconv.FormatInt(memory.ID, 10)
+	},
+	"time": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Local().Format("2006-01-02 15:04")
+	},
+	"percent": func(value float64) string {
+		return strconv.FormatFloat(value*100, 'f', 0, 64) + "%"
+	},
+	"score": func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 3, 64)
+	},
+	"scored": func(memories []*core.Memory) *memoryTable {
+		return &memoryTable{Memories: memories, Score: true}
+	},
+	"unscored": func(memories []*core.Memory) *memoryTable {
+		return &memoryTable{Memories: memories}
+	},
+	"json": func(value interface{}) string {
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return err.Error()
+		}
+		return string(data)
+	},
+}
+
+// templates are the HTML pages. Links are relative to the dashboard root,
+// where every page is served.
+var templates = template.Must(template.New("dashboard").Funcs(templateFuncs).Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} · PowerMem</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 0; color: #1f2328; }
+header { background: #24292f; padding: 0.75rem 1.5rem; }
+header a { color: #fff; margin-right: 1.5rem; text-decoration: none; }
+main { padding: 1rem 1.5rem; max-width: 72rem; }
+table { border-collapse: collapse; width: 100%; margin: 0.5rem 0 1rem; }
+th, td { text-align: left; padding: 0.35rem 0.6rem; border-bottom: 1px solid #d0d7de; vertical-align: top; }
+th { background: #f6f8fa; }
+td.num { text-align: right; white-space: nowrap; }
+pre { background: #f6f8fa; padding: 0.75rem; overflow-x: auto; }
+.tag { background: #ddf4ff; border-radius: 1rem; padding: 0 0.5rem; margin-right: 0.25rem; font-size: 0.85em; }
+.muted { color: #656d76; }
+.cluster { border: 1px solid #d0d7de; border-radius: 0.5rem; padding: 0 1rem; margin-bottom: 1rem; }
+</style>
+</head>
+<body>
+<header><a href="./">Users</a>{{if .HasProfiles}}<a href="profiles">Profiles</a>{{end}}{{if .HasAudit}}<a href="audit">Audit log</a>{{end}}</header>
+<main>
+<h1>{{.Title}}</h1>
+{{end}}
+
+{{define "footer"}}</main>
+</body>
+</html>
+{{end}}
+
+{{define "memories"}}<table>
+<tr><th>ID</th>{{if .Score}}<th>Score</th>{{end}}<th>Content</th><th>Tags</th><th>Retention</th><th>Created</th></tr>
+{{range .Memories}}<tr>
+<td><a href="memory?id={{id .}}">{{id .}}</a></td>
+{{if $.Score}}<td class="num">{{score .Score}}</td>{{end}}
+<td>{{.Content}}</td>
+<td>{{range .Tags}}<span class="tag">{{.}}</span>{{end}}</td>
+<td class="num">{{percent .RetentionStrength}}</td>
+<td class="num">{{time .CreatedAt}}</td>
+</tr>{{else}}<tr><td colspan="6" class="muted">No memories.</td></tr>{{end}}
+</table>
+{{end}}
+
+{{define "overview"}}{{template "header" .}}
+<p>{{.Page.Total}} memories, {{len .Page.Users}} users.</p>
+<table>
+<tr><th>User</th><th>Memories</th><th>Newest</th><th></th></tr>
+{{range .Page.Users}}<tr>
+<td>{{if .UserID}}<a href="user?id={{.UserID}}">{{.UserID}}</a>{{else}}<span class="muted">(none)</span>{{end}}</td>
+<td class="num">{{.Memories}}</td>
+<td class="num">{{time .Newest}}</td>
+<td>{{if .UserID}}<a href="duplicates?user={{.UserID}}">duplicates</a>{{end}}</td>
+</tr>{{end}}
+</table>
+{{template "footer" .}}{{end}}
+
+{{define "user"}}{{template "header" .}}{{with .Page}}
+<form action="user">
+<input type="hidden" name="id" value="{{.UserID}}">
+<input type="search" name="q" value="{{.Query}}" placeholder="Search memories" size="40">
+<button>Search</button>
+{{if .Query}}<a href="user?id={{.UserID}}">Clear</a>{{end}}
+· <a href="duplicates?user={{.UserID}}">Duplicates</a>
+</form>
+{{if .Profile}}<h2>Profile</h2>
+{{if .Profile.ProfileContent}}<p>{{.Profile.ProfileContent}}</p>{{end}}
+{{if .Profile.Topics}}<pre>{{json .Profile.Topics}}</pre>{{end}}
+<p class="muted">Updated {{time .Profile.UpdatedAt}}</p>{{end}}
+{{if .Tags}}<p>Tags: {{range .Tags}}<span class="tag">{{.}}</span>{{end}}</p>{{end}}
+<h2>{{if .Query}}Results for “{{.Query}}”{{else}}Memories{{end}}</h2>
+{{if .Query}}{{template "memories" (scored .Memories)}}{{else}}{{template "memories" (unscored .Memories)}}
+<p>{{if .Offset}}<a href="user?id={{.UserID}}&amp;offset={{.PrevOffset}}">Previous</a>{{end}}
+{{if .NextOffset}}<a href="user?id={{.UserID}}&amp;offset={{.NextOffset}}">Next</a>{{end}}</p>{{end}}
+{{end}}{{template "footer" .}}{{end}}
+
+{{define "memory"}}{{template "header" .}}{{with .Page}}{{with .Memory}}
+<table>
+<tr><th>User</th><td>{{if .UserID}}<a href="user?id={{.UserID}}">{{.UserID}}</a>{{else}}<span class="muted">(none)</span>{{end}}</td></tr>
+{{if .AgentID}}<tr><th>Agent</th><td>{{.AgentID}}</td></tr>{{end}}
+<tr><th>Content</th><td>{{.Content}}</td></tr>
+{{if .Tags}}<tr><th>Tags</th><td>{{range .Tags}}<span class="tag">{{.}}</span>{{end}}</td></tr>{{end}}
+<tr><th>Created</th><td>{{time .CreatedAt}}</td></tr>
+<tr><th>Updated</th><td>{{time .UpdatedAt}} (version {{.Version}})</td></tr>
+{{if .LastAccessedAt}}<tr><th>Last accessed</th><td>{{time .LastAccessedAt.UTC}}</td></tr>{{end}}
+{{if .ExpiresAt}}<tr><th>Expires</th><td>{{time .ExpiresAt.UTC}}</td></tr>{{end}}
+<tr><th>Stored retention</th><td>{{percent .RetentionStrength}}</td></tr>
+</table>
+{{if .Metadata}}<h2>Metadata</h2><pre>{{json .Metadata}}</pre>{{end}}
+{{end}}{{with .Retention}}
+<h2>Retention curve</h2>
+<svg viewBox="-40 -10 540 200" width="540" height="200" role="img" aria-label="Retention curve">
+<line x1="0" y1="160" x2="480" y2="160" stroke="#d0d7de"/>
+<line x1="0" y1="0" x2="0" y2="160" stroke="#d0d7de"/>
+<text x="-8" y="4" font-size="10" text-anchor="end">100%</text>
+<text x="-8" y="164" font-size="10" text-anchor="end">0%</text>
+<text x="0" y="176" font-size="10">day 0</text>
+<text x="480" y="176" font-size="10" text-anchor="end">day {{.SpanDays}}</text>
+<polyline points="{{.Polyline}}" fill="none" stroke="#0969da" stroke-width="2"/>
+<circle cx="{{.NowX}}" cy="{{.NowY}}" r="4" fill="#cf222e"/>
+<text x="{{.NowX}}" y="{{.NowY}}" dx="6" dy="-6" font-size="10">now: {{percent .Now.Retention}}</text>
+</svg>
+<p class="muted">R = e<sup>−{{.DecayRate}} × days</sup> since the memory was last accessed (or created).</p>
+{{end}}{{end}}{{template "footer" .}}{{end}}
+
+{{define "duplicates"}}{{template "header" .}}{{with .Page}}
+<form action="duplicates">
+<input type="hidden" name="user" value="{{.UserID}}">
+<label>Similarity threshold <input type="number" name="threshold" min="0" max="1" step="0.01" value="{{if .Threshold}}{{.Threshold}}{{end}}" placeholder="default"></label>
+<button>Update</button>
+{{if .UserID}}· <a href="user?id={{.UserID}}">Memories</a>{{end}}
+</form>
+<p>{{len .Clusters}} clusters.</p>
+{{range .Clusters}}<div class="cluster">
+{{template "memories" (unscored .Memories)}}
+<p class="muted">{{range .Pairs}}{{.FirstID}} ~ {{.SecondID}}: {{score .Similarity}}; {{end}}</p>
+</div>{{end}}
+{{end}}{{template "footer" .}}{{end}}
+
+{{define "profiles"}}{{template "header" .}}{{with .Page}}
+<table>
+<tr><th>User</th><th>Profile</th><th>Topics</th><th>Updated</th></tr>
+{{range .Profiles}}<tr>
+<td><a href="user?id={{.UserID}}">{{.UserID}}</a></td>
+<td>{{.ProfileContent}}</td>
+<td>{{if .Topics}}<pre>{{json .Topics}}</pre>{{end}}</td>
+<td class="num">{{time .UpdatedAt}}</td>
+</tr>{{else}}<tr><td colspan="4" class="muted">No profiles.</td></tr>{{end}}
+</table>
+<p>{{if .Offset}}<a href="profiles?offset={{.PrevOffset}}">Previous</a>{{end}}
+{{if .NextOffset}}<a href="profiles?offset={{.NextOffset}}">Next</a>{{end}}</p>
+{{end}}{{template "footer" .}}{{end}}
+
+{{define "audit"}}{{template "header" .}}{{with .Page}}
+<form action="audit">
+<input type="text" name="operation" value="{{.Operation}}" placeholder="Operation">
+<input type="text" name="user" value="{{.UserID}}" placeholder="User">
+<input type="text" name="actor" value="{{.Actor}}" placeholder="Actor">
+<button>Filter</button>
+</form>
+<table>
+<tr><th>Seq</th><th>Time</th><th>Operation</th><th>Actor</th><th>User</th><th>Parameters</th></tr>
+{{range .Entries}}<tr>
+<td class="num">{{.Seq}}</td>
+<td class="num">{{time .Time}}</td>
+<td>{{.Operation}}</td>
+<td>{{if .Actor}}{{.Actor}}{{else}}<span class="muted">(unknown)</span>{{end}}</td>
+<td>{{if .UserID}}<a href="user?id={{.UserID}}">{{.UserID}}</a>{{else}}<span class="muted">(all)</span>{{end}}</td>
+<td>{{if .Params}}<pre>{{json .Params}}</pre>{{end}}</td>
+</tr>{{else}}<tr><td colspan="6" class="muted">No entries.</td></tr>{{end}}
+</table>
+<p>{{if .NextAfter}}<a href="audit?after={{.NextAfter}}&amp;operation={{.Operation}}&amp;user={{.UserID}}&amp;actor={{.Actor}}">Next</a>{{end}}</p>
+{{end}}{{template "footer" .}}{{end}}
+`))
@@ -0,0 +1,119 @@
+package mock
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Client is a deterministic embedder for tests.
+// It implements the embedder.Provider interface without calling any service.
+//
+// Texts are embedded by feature hashing: each lowercased word is hashed to a
+// dimension and a sign, and the resulting vector is L2-normalized. The same
+// text always gets the same vector, and texts sharing words have a positive
+// cosine similarity, so similarity search behaves plausibly in tests.
+type Client struct {
+	dimensions int
+}
+
+// Config is the configuration for the mock Embedder.
+// Dimensions: Vector dimensions, defaults to 1536
+type Config struct {
+	Dimensions int
+}
+
+// NewClient creates a new mock Embedder client.
+//
+// Args:
+//   - cfg: mock Embedder configuration (may be nil)
+//
+// Returns:
+//   - *Client: mock Embedder client instance
+//   - error: always nil
+func NewClient(cfg *Config) (*Client, error) {
+	dimensions := 1536
+	if cfg != nil && cfg.Dimensions > 0 {
+		dimensions = cfg.Dimensions
+	}
+	return &Client{dimensions: dimensions}, nil
+}
+
+// Embed returns the embedding of text.
+//
+// Args:
+//   - ctx: Context for controlling the request lifecycle
+//   - text: Text to embed
+//
+// Returns:
+//   - []float64: normalized vector of Dimensions() values
+//   - error: the context error if ctx is done
+func (c *Client) Embed(ctx context.Context, text string) ([]float64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.embed(text), nil
+}
+
+// EmbedBatch returns the embeddings of texts, in order.
+func (c *Client) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	embeddings := make([][]float64, len(texts))
+	for i, text := range texts {
+		embeddings[i] = c.embed(text)
+	}
+	return embeddings, nil
+}
+
+// Dimensions returns the vector dimensions.
+func (c *Client) Dimensions() int {
+	return c.dimensions
+}
+
+// Close does nothing.
+func (c *Client) Close() error {
+	return nil
+}
+
+// embed hashes the words of text into a normalized vector.
+func (c *Client) embed(text string) []float64 {
+	vector := make([]float64, c.dimensions)
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 0 {
+		// Still give texts without words (including "") a distinct vector
+		words = []string{text}
+	}
+	for _, word := range words {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(word))
+		sum := h.Sum64()
+		index := int(sum % uint64(c.dimensions))
+		if sum&(1<<63) != 0 {
+			vector[index]--
+		} else {
+			vector[index]++
+		}
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	if norm == 0 {
+		// Opposite signs of two words cancelled out
+		vector[0] = 1
+		return vector
+	}
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] /= norm
+	}
+	return vector
+}
@@ -0,0 +1,81 @@
+package embedder
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// Truncate returns a Provider that keeps the first dims components of the
+// embeddings of provider and rescales them to unit length.
+//
+// Models trained with Matryoshka representation learning, such as OpenAI's
+// text-embedding-3 models, front-load the information of their embeddings,
+// so the truncated embeddings keep most of their quality at a fraction of
+// the index size. Memories and queries must be embedded by the same
+// truncating provider for their similarities to be meaningful.
+//
+// Example:
+//
+//	provider = embedder.Truncate(provider, 512) // 1536 -> 512 dimensions
+func Truncate(provider Provider, dims int) Provider {
+	return &truncated{Provider: provider, dims: dims}
+}
+
+// truncated is the Provider returned by Truncate.
+type truncated struct {
+	Provider
+	dims int
+}
+
+// Embed embeds text with the underlying provider and truncates the embedding.
+func (t *truncated) Embed(ctx context.Context, text string) ([]float64, error) {
+	embedding, err := t.Provider.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return t.truncate(embedding)
+}
+
+// EmbedBatch embeds texts with the underlying provider and truncates the
+// embeddings.
+func (t *truncated) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	embeddings, err := t.Provider.EmbedBatch(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	result := make([][]float64, len(embeddings))
+	for i, embedding := range embeddings {
+		if result[i], err = t.truncate(embedding); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Dimensions returns the truncated dimension.
+func (t *truncated) Dimensions() int {
+	return t.dims
+}
+
+// truncate returns the first dims components of embedding, L2-normalized.
+func (t *truncated) truncate(embedding []float64) ([]float64, error) {
+	if len(embedding) < t.dims {
+		return nil, fmt.Errorf("truncate embedding: %d dimensions, want at least %d", len(embedding), t.dims)
+	}
+	result := make([]float64, t.dims)
+	copy(result, embedding)
+
+	var norm float64
+	for _, v := range result {
+		norm += v * v
+	}
+	if norm == 0 {
+		return result, nil
+	}
+	norm = math.Sqrt(norm)
+	for i := range result {
+		result[i] /= norm
+	}
+	return result, nil
+}
@@ -0,0 +1,375 @@
+package ingest
+
+import (
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// HTML elements whose content is not text.
+var htmlSkipped = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true,
+	"svg": true, "canvas": true, "iframe": true, "object": true,
+}
+
+// HTML elements that start a new paragraph.
+var htmlBlocks = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "body": true,
+	"dd": true, "details": true, "div": true, "dl": true, "dt": true, "fieldset": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true, "h1": true,
+	"h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "header": true,
+	"hr": true, "main": true, "nav": true, "ol": true, "p": true, "pre": true,
+	"section": true, "summary": true, "table": true, "ul": true,
+}
+
+// HTML elements dropped as boilerplate with HTMLLoader.Readability.
+var htmlBoilerplate = map[string]bool{
+	"nav": true, "header": true, "footer": true, "aside": true, "form": true,
+	"button": true, "dialog": true, "menu": true,
+}
+
+// ARIA roles of boilerplate elements.
+var htmlBoilerplateRoles = map[string]bool{
+	"navigation": true, "banner": true, "contentinfo": true, "complementary": true,
+	"search": true, "dialog": true, "alertdialog": true,
+}
+
+// HTML elements dropped with HTMLLoader.Readability when their class or id
+// matches htmlBoilerplateClass.
+var htmlContainers = map[string]bool{
+	"div": true, "section": true, "ul": true, "ol": true, "table": true, "span": true,
+}
+
+var (
+	htmlBoilerplateClass = regexp.MustCompile(`(?i)(^|[\s_-])(nav|navbar|navigation|menu|breadcrumbs?|sidebar|footer|masthead|comments?|cookies?|consent|banner|ads?|advert\w*|sponsor\w*|promo\w*|share|sharing|social|related|subscribe|newsletter|popup|modal|skip)($|[\s_-])`)
+	htmlAttribute        = regexp.MustCompile(`([A-Za-z][\w:-]*)\s*=\s*("[^"]*"|'[^']*'|[^\s"'>]+)`)
+)
+
+// HTMLLoader loads HTML documents.
+//
+// The text keeps the visible text of the body: scripts, styles and other
+// non-text elements are dropped, block elements become paragraphs, list
+// items lines starting with "- " and table cells are separated by " | ".
+// Whitespace is collapsed except in <pre> elements. The title is the
+// <title> element, or else the first <h1> element.
+type HTMLLoader struct {
+	// Readability keeps only the main content of web pages. Navigation,
+	// headers, footers, sidebars, forms and elements whose class or id names
+	// menus, comments, ads, sharing buttons and the like are dropped, and if
+	// the page has <main> or <article> elements (or role="main"), only their
+	// text is kept.
+	Readability bool
+}
+
+// Load reads an HTML document.
+func (l HTMLLoader) Load(r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &htmlParser{readability: l.Readability}
+	p.parse(string(data))
+
+	text := p.text.String()
+	if p.main != "" {
+		// Unclosed main content
+		p.mainContent = append(p.mainContent, text[p.mainStart:])
+	}
+	if len(p.mainContent) > 0 {
+		text = strings.Join(p.mainContent, "\n\n")
+	}
+
+	doc := &Document{
+		Title: collapseSpace(p.title.String()),
+		Text:  normalizeText(text),
+	}
+	if doc.Title == "" {
+		doc.Title = collapseSpace(p.h1.String())
+	}
+	if doc.Text == "" {
+		return nil, ErrNoText
+	}
+	return doc, nil
+}
+
+// htmlParser extracts the text of an HTML document.
+type htmlParser struct {
+	text  strings.Builder
+	title strings.Builder
+	h1    strings.Builder
+
+	readability bool
+
+	// skip is the element whose content is being skipped (empty if none),
+	// skipDepth the number of open skip elements.
+	skip      string
+	skipDepth int
+
+	// main is the open main content element (empty if none), mainDepth the
+	// number of open main elements and mainStart the length of text at its
+	// start. mainContent is the text of the closed main content elements.
+	main        string
+	mainDepth   int
+	mainStart   int
+	mainContent []string
+
+	inTitle bool
+	inH1    bool
+	pre     int
+	// space is set when whitespace was skipped since the last text.
+	space bool
+}
+
+// parse extracts the text of src.
+func (p *htmlParser) parse(src string) {
+	for len(src) > 0 {
+		i := strings.IndexByte(src, '<')
+		if i < 0 {
+			p.addText(src)
+			return
+		}
+		if i > 0 {
+			p.addText(src[:i])
+			src = src[i:]
+		}
+
+		switch {
+		case strings.HasPrefix(src, "<!--"):
+			end := strings.Index(src, "-->")
+			if end < 0 {
+				return
+			}
+			src = src[end+3:]
+		case strings.HasPrefix(src, "<!"), strings.HasPrefix(src, "<?"):
+			end := strings.IndexByte(src, '>')
+			if end < 0 {
+				return
+			}
+			src = src[end+1:]
+		default:
+			end := tagEnd(src)
+			if end < 0 {
+				// Not a tag
+				p.addText(src[:1])
+				src = src[1:]
+				continue
+			}
+			p.tag(src[1:end])
+			src = src[end+1:]
+		}
+	}
+}
+
+// tagEnd returns the index of the ">" closing the tag at the start of src,
+// skipping quoted attribute values, or -1 if src does not start with a tag.
+func tagEnd(src string) int {
+	if len(src) < 2 || !(src[1] == '/' || isLetter(src[1])) {
+		return -1
+	}
+	var quote byte
+	for i := 1; i < len(src); i++ {
+		switch c := src[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return i
+		}
+	}
+	return -1
+}
+
+// isLetter reports whether c is an ASCII letter.
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// tag handles a start or end tag, given without its angle brackets.
+func (p *htmlParser) tag(tag string) {
+	closing := strings.HasPrefix(tag, "/")
+	tag = strings.TrimPrefix(tag, "/")
+	name := tag
+	if i := strings.IndexAny(tag, " \t\r\n/"); i >= 0 {
+		name = tag[:i]
+	}
+	name = strings.ToLower(name)
+	selfClosing := strings.HasSuffix(tag, "/")
+
+	if p.skip != "" {
+		if name == p.skip && !selfClosing {
+			if closing {
+				p.skipDepth--
+			} else {
+				p.skipDepth++
+			}
+			if p.skipDepth == 0 {
+				p.skip = ""
+			}
+		}
+		return
+	}
+
+	if p.readability {
+		p.readable(name, tag, closing, selfClosing)
+		if p.skip != "" {
+			return
+		}
+	}
+
+	switch {
+	case name == "title":
+		p.inTitle = !closing
+		return
+	case htmlSkipped[name]:
+		if !closing && !selfClosing {
+			p.skip = name
+			p.skipDepth = 1
+		}
+		return
+	case name == "h1":
+		// Only the first one is kept
+		p.inH1 = !closing && p.h1.Len() == 0
+	case name == "pre":
+		if closing && p.pre > 0 {
+			p.pre--
+		} else if !closing {
+			p.pre++
+		}
+	}
+
+	switch {
+	case name == "br":
+		p.text.WriteString("\n")
+		p.space = false
+	case name == "li" && !closing:
+		p.text.WriteString("\n- ")
+		p.space = false
+	case name == "tr" && !closing:
+		p.text.WriteString("\n")
+		p.space = false
+	case name == "li" || name == "tr":
+		// The next item or row starts the line
+	case (name == "td" || name == "th") && !closing:
+		if t := p.text.String(); t != "" && !strings.HasSuffix(t, "\n") {
+			p.text.WriteString(" | ")
+		}
+		p.space = false
+	case htmlBlocks[name]:
+		p.text.WriteString("\n\n")
+		p.space = false
+	default:
+		// Inline elements separate words only if whitespace does
+	}
+}
+
+// readable tracks the main content and skips the boilerplate elements of a
+// web page.
+func (p *htmlParser) readable(name, tag string, closing, selfClosing bool) {
+	if selfClosing {
+		return
+	}
+	role := strings.ToLower(htmlAttr(tag, "role"))
+
+	if p.main != "" {
+		if name == p.main {
+			if closing {
+				p.mainDepth--
+			} else {
+				p.mainDepth++
+			}
+			if p.mainDepth == 0 {
+				p.text.WriteString("\n\n")
+				p.mainContent = append(p.mainContent, p.text.String()[p.mainStart:])
+				p.main = ""
+				return
+			}
+		}
+	} else if !closing && (name == "main" || name == "article" || role == "main") {
+		p.text.WriteString("\n\n")
+		p.main = name
+		p.mainDepth = 1
+		p.mainStart = p.text.Len()
+		return
+	}
+
+	if closing {
+		return
+	}
+	boilerplate := htmlBoilerplate[name] || htmlBoilerplateRoles[role] ||
+		strings.EqualFold(htmlAttr(tag, "aria-hidden"), "true") ||
+		(htmlContainers[name] && htmlBoilerplateClass.MatchString(htmlAttr(tag, "class")+" "+htmlAttr(tag, "id")))
+	if (name == "header" || name == "footer") && p.main != "" {
+		// Header and footer of the article itself
+		boilerplate = false
+	}
+	if boilerplate {
+		p.skip = name
+		p.skipDepth = 1
+	}
+}
+
+// htmlAttr returns the value of the attribute name of tag (empty if it has none).
+func htmlAttr(tag, name string) string {
+	for _, m := range htmlAttribute.FindAllStringSubmatch(tag, -1) {
+		if strings.EqualFold(m[1], name) {
+			return html.UnescapeString(strings.Trim(m[2], `"'`))
+		}
+	}
+	return ""
+}
+
+// addText adds the text between two tags.
+func (p *htmlParser) addText(text string) {
+	if p.skip != "" {
+		return
+	}
+	text = html.UnescapeString(text)
+	if p.inTitle {
+		p.title.WriteString(text)
+		return
+	}
+	if p.inH1 {
+		p.h1.WriteString(text)
+		p.h1.WriteString(" ")
+	}
+
+	if p.pre > 0 {
+		p.text.WriteString(text)
+		return
+	}
+
+	// Collapse whitespace, keeping one space between words
+	for i, word := range strings.Fields(text) {
+		if i > 0 || p.space || (len(text) > 0 && isSpace(text[0])) {
+			p.writeSpace()
+		}
+		p.text.WriteString(word)
+		p.space = false
+	}
+	if len(text) > 0 && isSpace(text[len(text)-1]) {
+		p.space = true
+	}
+}
+
+// writeSpace writes a space unless the text ends with whitespace.
+func (p *htmlParser) writeSpace() {
+	s := p.text.String()
+	if s != "" && !isSpace(s[len(s)-1]) {
+		p.text.WriteString(" ")
+	}
+}
+
+// isSpace reports whether c is ASCII whitespace.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// collapseSpace trims s and replaces its runs of whitespace with one space.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,337 @@
+// Package ingest loads documents into memory.
+//
+// Loaders extract the text of PDF, Markdown, HTML and plain text documents
+// without external parsers, from files, readers or URLs. An Ingester splits the text into overlapping
+// chunks and adds them with core.Client.BatchAddItems, recording where each
+// chunk came from in its metadata.
+//
+// Example:
+//
+//	ingester := ingest.NewIngester(client, &ingest.Config{ChunkSize: 1500})
+//	result, err := ingester.IngestFile(ctx, "handbook.pdf", core.WithUserID("user_001"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("Added %d chunks\n", result.CreatedCount)
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// Format is the format of a document.
+type Format string
+
+const (
+	// FormatText is plain text.
+	FormatText Format = "text"
+
+	// FormatMarkdown is Markdown (CommonMark and GitHub extensions).
+	FormatMarkdown Format = "markdown"
+
+	// FormatHTML is HTML.
+	FormatHTML Format = "html"
+
+	// FormatPDF is PDF.
+	FormatPDF Format = "pdf"
+)
+
+// Metadata keys set on every ingested chunk.
+const (
+	// MetadataSource holds Document.Source.
+	MetadataSource = "source"
+
+	// MetadataFormat holds Document.Format.
+	MetadataFormat = "source_format"
+
+	// MetadataTitle holds Document.Title (if it is not empty).
+	MetadataTitle = "source_title"
+
+	// MetadataChunkIndex holds the position of the chunk in the document,
+	// starting at 0.
+	MetadataChunkIndex = "chunk_index"
+
+	// MetadataChunkCount holds the number of chunks of the document.
+	MetadataChunkCount = "chunk_count"
+)
+
+// ErrUnsupportedFormat is returned for documents in a format without a loader.
+var ErrUnsupportedFormat = errors.New("unsupported document format")
+
+// ErrNoText is returned when no text can be extracted from a document, e.g.
+// a scanned PDF.
+var ErrNoText = errors.New("no text in document")
+
+// Document is the text extracted from a document.
+type Document struct {
+	// Source identifies the document, e.g. its path or URL.
+	Source string
+
+	// Format is the format the document was loaded from.
+	Format Format
+
+	// Title is the title found in the document (empty if it has none).
+	Title string
+
+	// Text is the text of the document. Paragraphs are separated by blank lines.
+	Text string
+
+	// Metadata is added to the metadata of every chunk of the document (optional).
+	Metadata map[string]interface{}
+}
+
+// Loader extracts the text of documents in one format.
+type Loader interface {
+	// Load reads a document from r.
+	//
+	// Returns the Document, without Source, or ErrNoText if the document
+	// contains no text.
+	Load(r io.Reader) (*Document, error)
+}
+
+// LoaderFor returns the loader of format.
+//
+// Returns ErrUnsupportedFormat if there is no loader for format.
+func LoaderFor(format Format) (Loader, error) {
+	switch format {
+	case FormatText:
+		return TextLoader{}, nil
+	case FormatMarkdown:
+		return MarkdownLoader{}, nil
+	case FormatHTML:
+		return HTMLLoader{}, nil
+	case FormatPDF:
+		return PDFLoader{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// FormatOf returns the format of a file from its extension: .pdf, .md or
+// .markdown, .html or .htm, and .txt.
+//
+// Returns ErrUnsupportedFormat for other extensions.
+func FormatOf(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".pdf":
+		return FormatPDF, nil
+	case ".md", ".markdown":
+		return FormatMarkdown, nil
+	case ".html", ".htm":
+		return FormatHTML, nil
+	case ".txt", ".text":
+		return FormatText, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, path)
+	}
+}
+
+// Load reads a document in format from r.
+//
+// Parameters:
+//   - r: Document content
+//   - format: Format of the document
+//   - source: Document.Source of the result, e.g. a URL
+func Load(r io.Reader, format Format, source string) (*Document, error) {
+	loader, err := LoaderFor(format)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := loader.Load(r)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", source, err)
+	}
+	doc.Source = source
+	doc.Format = format
+	return doc, nil
+}
+
+// LoadFile reads the document at path, in the format of its extension (see
+// FormatOf). The source of the document is path.
+func LoadFile(path string) (*Document, error) {
+	format, err := FormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f, format, path)
+}
+
+// TextLoader loads plain text documents.
+type TextLoader struct{}
+
+// Load reads a plain text document, normalizing line endings.
+func (TextLoader) Load(r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := normalizeText(string(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))))
+	if text == "" {
+		return nil, ErrNoText
+	}
+	return &Document{Text: text}, nil
+}
+
+// Default chunking of an Ingester.
+const (
+	// DefaultChunkSize is the default maximum chunk size in bytes.
+	DefaultChunkSize = 1000
+
+	// DefaultChunkOverlap is the default number of bytes shared by
+	// consecutive chunks.
+	DefaultChunkOverlap = 100
+)
+
+// Config contains configuration for an Ingester.
+//
+// Fields:
+//   - ChunkSize: Maximum chunk size in bytes (default: DefaultChunkSize)
+//   - ChunkOverlap: Bytes repeated from the end of a chunk at the start of the next one
+//     (default: DefaultChunkOverlap, negative for none)
+//   - Metadata: Metadata added to every chunk (optional)
+//   - HTTPClient: HTTP client fetching URLs (default: 30s timeout)
+//   - MaxFetchSize: Maximum size of a fetched document in bytes (default: DefaultMaxFetchSize)
+type Config struct {
+	ChunkSize    int
+	ChunkOverlap int
+	Metadata     map[string]interface{}
+	HTTPClient   *http.Client
+	MaxFetchSize int64
+}
+
+// Ingester adds documents to a memory client as chunks.
+type Ingester struct {
+	client     *core.Client
+	config     Config
+	httpClient *http.Client
+}
+
+// NewIngester creates an Ingester adding the chunks of documents to client.
+//
+// Parameters:
+//   - client: Memory client receiving the chunks
+//   - cfg: Chunking configuration (nil for the defaults)
+func NewIngester(client *core.Client, cfg *Config) *Ingester {
+	config := Config{}
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = DefaultChunkSize
+	}
+	if config.ChunkOverlap == 0 {
+		config.ChunkOverlap = DefaultChunkOverlap
+	}
+	if config.ChunkOverlap < 0 || config.ChunkOverlap >= config.ChunkSize {
+		config.ChunkOverlap = 0
+	}
+	if config.MaxFetchSize <= 0 {
+		config.MaxFetchSize = DefaultMaxFetchSize
+	}
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Ingester{client: client, config: config, httpClient: httpClient}
+}
+
+// Chunks splits the text of doc into the contents of its memories (see
+// core.SplitContent).
+func (i *Ingester) Chunks(doc *Document) []string {
+	return core.SplitContent(doc.Text, i.config.ChunkSize, i.config.ChunkOverlap)
+}
+
+// Ingest adds the chunks of doc as memories with BatchAddItems.
+//
+// Each chunk gets the Config.Metadata, Document.Metadata, the metadata set
+// by opts and the source metadata (MetadataSource, MetadataFormat, MetadataTitle,
+// MetadataChunkIndex and MetadataChunkCount). Its core.Source is the URL of
+// documents whose source is an http or https URL, and the document otherwise.
+// opts apply to every chunk, e.g. core.WithUserID or core.WithBatchOptions.
+//
+// Returns the result of BatchAddItems, whose IDs are in chunk order, or
+// ErrNoText if doc has no text.
+//
+// Example:
+//
+//	doc, err := ingest.Load(resp.Body, ingest.FormatHTML, url)
+//	if err != nil {
+//	    return err
+//	}
+//	result, err := ingester.Ingest(ctx, doc, core.WithUserID("user_001"), core.WithTags("docs"))
+func (i *Ingester) Ingest(ctx context.Context, doc *Document, opts ...core.AddOption) (*core.BatchAddResult, error) {
+	chunks := i.Chunks(doc)
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("ingest %s: %w", doc.Source, ErrNoText)
+	}
+
+	items := make([]core.BatchAddItem, len(chunks))
+	for index, chunk := range chunks {
+		metadata := make(map[string]interface{}, len(i.config.Metadata)+len(doc.Metadata)+5)
+		for k, v := range i.config.Metadata {
+			metadata[k] = v
+		}
+		for k, v := range doc.Metadata {
+			metadata[k] = v
+		}
+		metadata[MetadataSource] = doc.Source
+		metadata[MetadataFormat] = string(doc.Format)
+		if doc.Title != "" {
+			metadata[MetadataTitle] = doc.Title
+		}
+		metadata[MetadataChunkIndex] = index
+		metadata[MetadataChunkCount] = len(chunks)
+
+		items[index] = core.BatchAddItem{Content: chunk, Metadata: metadata}
+	}
+
+	source := core.Source{Document: doc.Source}
+	if u, err := url.Parse(doc.Source); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		source = core.Source{URL: doc.Source}
+	}
+	opts = append(opts[:len(opts):len(opts)], core.WithSource(source))
+	return i.client.BatchAddItems(ctx, items, opts...)
+}
+
+// IngestFile loads the document at path (see LoadFile) and ingests it.
+func (i *Ingester) IngestFile(ctx context.Context, path string, opts ...core.AddOption) (*core.BatchAddResult, error) {
+	doc, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return i.Ingest(ctx, doc, opts...)
+}
+
+// normalizeText trims the ends of the lines of text and collapses runs of
+// blank lines into one.
+func normalizeText(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if strings.TrimSpace(line) == "" {
+			if len(lines) > 0 && lines[len(lines)-1] != "" {
+				lines = append(lines, "")
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,149 @@
+package ingest
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Inline Markdown syntax removed by MarkdownLoader, in order.
+var markdownInline = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile("`([^`]+)`"), "$1"},                                 // code
+	{regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`), "$1"},                    // images
+	{regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`), "$1"},                     // links
+	{regexp.MustCompile(`\[([^\]]+)\]\[[^\]]*\]`), "$1"},                    // reference links
+	{regexp.MustCompile(`<(https?://[^>\s]+)>`), "$1"},                      // autolinks
+	{regexp.MustCompile(`</?[A-Za-z][^>]*>`), ""},                           // HTML tags
+	{regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`), "$2"},          // strong
+	{regexp.MustCompile(`(^|[^\w*])\*(\S(?:[^*]*?\S)?)\*`), "$1$2"},         // emphasis
+	{regexp.MustCompile(`(^|[^\w])_(\S(?:[^_]*?\S)?)_($|[^\w])`), "$1$2$3"}, // emphasis
+	{regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`), "$1"},                        // strikethrough
+	{regexp.MustCompile(`\\([\\` + "`" + `*_{}\[\]()#+\-.!|>~])`), "$1"},    // escapes
+}
+
+var (
+	markdownHeading    = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	markdownSetext     = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+	markdownRule       = regexp.MustCompile(`^ {0,3}([-*_])(?:\s*[-*_]){2,}\s*$`)
+	markdownFence      = regexp.MustCompile("^ {0,3}(```|~~~)")
+	markdownQuote      = regexp.MustCompile(`^ {0,3}>\s?`)
+	markdownList       = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?`)
+	markdownLinkDef    = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:\s+\S+`)
+	markdownTableRule  = regexp.MustCompile(`^\s*\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?\s*$`)
+	markdownFrontTitle = regexp.MustCompile(`^title:\s*["']?(.*?)["']?\s*$`)
+)
+
+// MarkdownLoader loads Markdown documents.
+//
+// The text keeps the words of headings, paragraphs, lists, tables and code
+// blocks, without the Markdown syntax. Front matter is dropped. The title is
+// the front matter title, or else the first level 1 heading.
+type MarkdownLoader struct{}
+
+// Load reads a Markdown document.
+func (MarkdownLoader) Load(r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	doc := &Document{}
+	var out []string
+
+	// YAML front matter
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				for _, line := range lines[1:i] {
+					if m := markdownFrontTitle.FindStringSubmatch(line); m != nil {
+						doc.Title = m[1]
+					}
+				}
+				lines = lines[i+1:]
+				break
+			}
+		}
+	}
+
+	fence := ""
+	for i, line := range lines {
+		if fence != "" {
+			if strings.HasPrefix(strings.TrimSpace(line), fence) {
+				fence = ""
+				out = append(out, "")
+				continue
+			}
+			out = append(out, line)
+			continue
+		}
+		if m := markdownFence.FindStringSubmatch(line); m != nil {
+			fence = m[1]
+			out = append(out, "")
+			continue
+		}
+
+		for markdownQuote.MatchString(line) {
+			line = markdownQuote.ReplaceAllString(line, "")
+		}
+
+		switch {
+		case markdownLinkDef.MatchString(line), markdownTableRule.MatchString(line) && strings.Contains(line, "|"):
+			continue
+		case markdownSetext.MatchString(line) && i > 0 && strings.TrimSpace(lines[i-1]) != "":
+			// The previous line is a heading
+			if strings.HasPrefix(strings.TrimSpace(line), "=") && doc.Title == "" {
+				doc.Title = markdownText(lines[i-1])
+			}
+			out = append(out, "")
+			continue
+		case markdownRule.MatchString(line):
+			out = append(out, "")
+			continue
+		}
+
+		if m := markdownHeading.FindStringSubmatch(line); m != nil {
+			heading := markdownText(m[2])
+			if len(m[1]) == 1 && doc.Title == "" {
+				doc.Title = heading
+			}
+			out = append(out, "", heading, "")
+			continue
+		}
+
+		line = markdownList.ReplaceAllString(line, "$1- ")
+		if strings.Contains(line, "|") && strings.HasPrefix(strings.TrimSpace(line), "|") {
+			line = markdownTableRow(line)
+		}
+		out = append(out, markdownText(line))
+	}
+
+	doc.Text = normalizeText(strings.Join(out, "\n"))
+	if doc.Text == "" {
+		return nil, ErrNoText
+	}
+	return doc, nil
+}
+
+// markdownText removes the inline syntax of a line of Markdown.
+func markdownText(line string) string {
+	for _, inline := range markdownInline {
+		line = inline.pattern.ReplaceAllString(line, inline.replacement)
+	}
+	return line
+}
+
+// markdownTableRow returns the cells of a table row separated by " | ".
+func markdownTableRow(line string) string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+	cells := strings.Split(line, "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(cell)
+	}
+	return strings.Join(cells, " | ")
+}
@@ -0,0 +1,1003 @@
+package ingest
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// PDFLoader loads PDF documents.
+//
+// The text is extracted from the content streams of the pages, in page
+// order, using the ToUnicode maps of the fonts, or else their standard
+// encoding. Streams may be uncompressed or use the Flate, ASCIIHex or ASCII85
+// filters, and objects may be in object streams (PDF 1.5). Encrypted PDFs
+// are rejected with ErrUnsupportedFormat, and scanned PDFs, which have no
+// text, with ErrNoText. The title is the Title of the document information.
+type PDFLoader struct{}
+
+// Load reads a PDF document.
+func (PDFLoader) Load(r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	f, err := parsePDF(data)
+	if err != nil {
+		return nil, err
+	}
+	if f.trailer["Encrypt"] != nil {
+		return nil, fmt.Errorf("%w: encrypted PDF", ErrUnsupportedFormat)
+	}
+
+	pdfPages := f.pages()
+	if len(pdfPages) == 0 {
+		return nil, fmt.Errorf("%w: no pages", ErrUnsupportedFormat)
+	}
+	var pages []string
+	for _, page := range pdfPages {
+		if text := f.pageText(page); text != "" {
+			pages = append(pages, text)
+		}
+	}
+
+	doc := &Document{Text: normalizeText(strings.Join(pages, "\n\n"))}
+	if info, ok := f.resolve(f.trailer["Info"]).(pdfDict); ok {
+		if title, ok := f.resolve(info["Title"]).(pdfString); ok {
+			doc.Title = collapseSpace(decodeTextString(title))
+		}
+	}
+	if doc.Text == "" {
+		return nil, ErrNoText
+	}
+	return doc, nil
+}
+
+// PDF object types.
+type (
+	// pdfName is a name, without its slash.
+	pdfName string
+
+	// pdfString is a literal or hexadecimal string.
+	pdfString []byte
+
+	// pdfDict is a dictionary, keyed by name.
+	pdfDict map[string]interface{}
+
+	// pdfArray is an array.
+	pdfArray []interface{}
+
+	// pdfRef is an indirect reference.
+	pdfRef struct{ num int }
+
+	// pdfOp is an operator of a content stream, or another keyword.
+	pdfOp string
+
+	// pdfStream is a stream with its raw (encoded) data.
+	pdfStream struct {
+		dict pdfDict
+		data []byte
+	}
+)
+
+// errPDFSyntax is returned by the lexer for malformed input.
+var errPDFSyntax = errors.New("invalid PDF syntax")
+
+// maxPDFNesting is the maximum depth of nested arrays and dictionaries.
+const maxPDFNesting = 256
+
+// pdfLexer reads PDF objects and content stream tokens. Its position never
+// goes past the end of the data.
+type pdfLexer struct {
+	data []byte
+	pos  int
+
+	// refs enables the parsing of indirect references ("1 0 R").
+	refs bool
+
+	// depth is the nesting depth of the arrays and dictionaries being read.
+	depth int
+}
+
+// isPDFSpace reports whether c is PDF whitespace.
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+// isPDFDelimiter reports whether c is whitespace or a PDF delimiter.
+func isPDFDelimiter(c byte) bool {
+	return isPDFSpace(c) || strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+// skipSpace skips whitespace and comments.
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case isPDFSpace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// next returns the next object or operator, or io.EOF at the end of the data.
+func (l *pdfLexer) next() (interface{}, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, io.EOF
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		l.pos++
+		return pdfName(l.word()), nil
+	case c == '(':
+		return l.literalString(), nil
+	case c == '<' && l.peek(1) == '<':
+		l.pos += 2
+		return l.dict()
+	case c == '<':
+		return l.hexString(), nil
+	case c == '[':
+		l.pos++
+		if l.depth >= maxPDFNesting {
+			return nil, errPDFSyntax
+		}
+		l.depth++
+		defer func() { l.depth-- }()
+		var array pdfArray
+		for {
+			l.skipSpace()
+			if l.pos >= len(l.data) {
+				return nil, errPDFSyntax
+			}
+			if l.data[l.pos] == ']' {
+				l.pos++
+				return array, nil
+			}
+			v, err := l.next()
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, v)
+		}
+	case c == ']' || c == '>' || c == ')' || c == '{' || c == '}':
+		l.pos++
+		return pdfOp(string(c)), nil
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return l.number(), nil
+	default:
+		word := l.word()
+		if word == "" {
+			l.pos++
+			return pdfOp(string(c)), nil
+		}
+		switch word {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return pdfOp(word), nil
+	}
+}
+
+// peek returns the byte at offset from the position (0 past the end).
+func (l *pdfLexer) peek(offset int) byte {
+	if l.pos+offset < len(l.data) {
+		return l.data[l.pos+offset]
+	}
+	return 0
+}
+
+// word reads characters up to the next delimiter, decoding #xx escapes.
+func (l *pdfLexer) word() string {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	if strings.Contains(word, "#") {
+		var b strings.Builder
+		for i := 0; i < len(word); i++ {
+			if word[i] == '#' && i+2 < len(word) {
+				if v, err := strconv.ParseUint(word[i+1:i+3], 16, 8); err == nil {
+					b.WriteByte(byte(v))
+					i += 2
+					continue
+				}
+			}
+			b.WriteByte(word[i])
+		}
+		word = b.String()
+	}
+	return word
+}
+
+// number reads a number, or an indirect reference if refs is set.
+func (l *pdfLexer) number() interface{} {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.data) && (l.data[l.pos] == '.' || (l.data[l.pos] >= '0' && l.data[l.pos] <= '9')) {
+		l.pos++
+	}
+	text := string(l.data[start:l.pos])
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0.0
+	}
+
+	if l.refs && !strings.ContainsAny(text, "+-.") {
+		// "num gen R"
+		save := l.pos
+		l.skipSpace()
+		genStart := l.pos
+		for l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '9' {
+			l.pos++
+		}
+		if l.pos > genStart {
+			l.skipSpace()
+			if l.peek(0) == 'R' && (l.pos+1 >= len(l.data) || isPDFDelimiter(l.data[l.pos+1])) {
+				l.pos++
+				return pdfRef{num: int(v)}
+			}
+		}
+		l.pos = save
+	}
+	return v
+}
+
+// dict reads a dictionary after its "<<".
+func (l *pdfLexer) dict() (pdfDict, error) {
+	if l.depth >= maxPDFNesting {
+		return nil, errPDFSyntax
+	}
+	l.depth++
+	defer func() { l.depth-- }()
+	dict := make(pdfDict)
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.data) {
+			return nil, errPDFSyntax
+		}
+		if l.data[l.pos] == '>' && l.peek(1) == '>' {
+			l.pos += 2
+			return dict, nil
+		}
+		key, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		name, ok := key.(pdfName)
+		if !ok {
+			return nil, errPDFSyntax
+		}
+		value, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		dict[string(name)] = value
+	}
+}
+
+// literalString reads a string in parentheses.
+func (l *pdfLexer) literalString() pdfString {
+	var b []byte
+	depth := 0
+	l.pos++
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return b
+			}
+			depth--
+		case '\\':
+			if l.pos >= len(l.data) {
+				return b
+			}
+			c = l.data[l.pos]
+			l.pos++
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.peek(0) == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if c >= '0' && c <= '7' {
+					v := int(c - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				}
+			}
+		}
+		b = append(b, c)
+	}
+	return b
+}
+
+// hexString reads a string in angle brackets.
+func (l *pdfLexer) hexString() pdfString {
+	l.pos++
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !isPDFSpace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	if l.pos < len(l.data) {
+		l.pos++ // ">"
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	b := make([]byte, hex.DecodedLen(len(digits)))
+	n, _ := hex.Decode(b, digits)
+	return b[:n]
+}
+
+// pdfFile holds the objects of a PDF file.
+type pdfFile struct {
+	objects map[int]interface{}
+	trailer pdfDict
+}
+
+// pdfObject matches the start of an indirect object.
+var pdfObject = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+
+// parsePDF reads the objects of a PDF file.
+//
+// The objects are found by scanning the file rather than through the
+// cross-reference table, so that damaged files can be read too; later
+// definitions of an object replace earlier ones, as in incremental updates.
+func parsePDF(data []byte) (*pdfFile, error) {
+	start := bytes.Index(data, []byte("%PDF-"))
+	if start < 0 || start > 1024 {
+		return nil, fmt.Errorf("%w: not a PDF file", ErrUnsupportedFormat)
+	}
+
+	f := &pdfFile{objects: make(map[int]interface{}), trailer: make(pdfDict)}
+	for pos := start; ; {
+		loc := pdfObject.FindSubmatchIndex(data[pos:])
+		if loc == nil {
+			break
+		}
+		num, err := strconv.Atoi(string(data[pos+loc[2] : pos+loc[3]]))
+		l := &pdfLexer{data: data, pos: pos + loc[1], refs: true}
+		pos += loc[1]
+		if err != nil {
+			continue
+		}
+
+		v, err := l.next()
+		if err != nil {
+			continue
+		}
+		if dict, ok := v.(pdfDict); ok {
+			if stream, end := readStream(data, l, dict); stream != nil {
+				v = stream
+				l.pos = end
+			}
+		}
+		f.objects[num] = v
+		if l.pos > len(data) {
+			return nil, fmt.Errorf("%w: malformed object %d", ErrUnsupportedFormat, num)
+		}
+		pos = l.pos
+	}
+
+	// Trailer dictionaries, or cross-reference streams in PDF 1.5
+	for _, loc := range regexp.MustCompile(`trailer\s*<<`).FindAllIndex(data, -1) {
+		l := &pdfLexer{data: data, pos: loc[1], refs: true}
+		if dict, err := l.dict(); err == nil {
+			for k, v := range dict {
+				f.trailer[k] = v
+			}
+		}
+	}
+	if f.trailer["Root"] == nil {
+		for _, v := range f.objects {
+			if stream, ok := v.(*pdfStream); ok && stream.dict["Type"] == pdfName("XRef") {
+				for k, v := range stream.dict {
+					if k == "Root" || k == "Info" || k == "Encrypt" {
+						f.trailer[k] = v
+					}
+				}
+			}
+		}
+	}
+
+	// Objects of object streams
+	for _, v := range f.objects {
+		if stream, ok := v.(*pdfStream); ok && stream.dict["Type"] == pdfName("ObjStm") {
+			f.readObjectStream(stream)
+		}
+	}
+	return f, nil
+}
+
+// readStream reads the data of the stream with dictionary dict, if the
+// lexer is before the "stream" keyword. Returns the stream and the position
+// after it, or nil.
+func readStream(data []byte, l *pdfLexer, dict pdfDict) (*pdfStream, int) {
+	l.skipSpace()
+	if !bytes.HasPrefix(data[l.pos:], []byte("stream")) {
+		return nil, 0
+	}
+	start := l.pos + len("stream")
+	if start < len(data) && data[start] == '\r' {
+		start++
+	}
+	if start < len(data) && data[start] == '\n' {
+		start++
+	}
+
+	if length, ok := dict["Length"].(float64); ok && length >= 0 && length <= float64(len(data)-start) {
+		end := start + int(length)
+		if bytes.HasPrefix(bytes.TrimLeft(data[end:], " \r\n"), []byte("endstream")) {
+			return &pdfStream{dict: dict, data: data[start:end]}, end
+		}
+	}
+
+	// The length is indirect or wrong
+	end := bytes.Index(data[start:], []byte("endstream"))
+	if end < 0 {
+		return &pdfStream{dict: dict, data: data[start:]}, len(data)
+	}
+	raw := bytes.TrimRight(data[start:start+end], "\r\n")
+	return &pdfStream{dict: dict, data: raw}, start + end + len("endstream")
+}
+
+// readObjectStream adds the objects of an object stream that are not
+// defined directly.
+func (f *pdfFile) readObjectStream(stream *pdfStream) {
+	data, err := f.decode(stream)
+	if err != nil {
+		return
+	}
+	n, _ := stream.dict["N"].(float64)
+	first, _ := stream.dict["First"].(float64)
+	if first < 0 || first > float64(len(data)) {
+		return
+	}
+
+	header := &pdfLexer{data: data[:int(first)]}
+	for i := 0; i < int(n); i++ {
+		num, err1 := header.next()
+		offset, err2 := header.next()
+		if err1 != nil || err2 != nil {
+			return
+		}
+		numValue, ok1 := num.(float64)
+		offsetValue, ok2 := offset.(float64)
+		if !ok1 || !ok2 || offsetValue < 0 || first+offsetValue >= float64(len(data)) {
+			return
+		}
+		if _, ok := f.objects[int(numValue)]; ok {
+			continue
+		}
+		l := &pdfLexer{data: data, pos: int(first + offsetValue), refs: true}
+		if v, err := l.next(); err == nil {
+			f.objects[int(numValue)] = v
+		}
+	}
+}
+
+// resolve follows indirect references.
+func (f *pdfFile) resolve(v interface{}) interface{} {
+	for i := 0; i < 32; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = f.objects[ref.num]
+	}
+	return nil
+}
+
+// decode returns the decoded data of stream.
+func (f *pdfFile) decode(stream *pdfStream) ([]byte, error) {
+	var filters []interface{}
+	switch filter := f.resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []interface{}{filter}
+	case pdfArray:
+		filters = filter
+	}
+
+	data := stream.data
+	for _, filter := range filters {
+		switch f.resolve(filter) {
+		case pdfName("FlateDecode"), pdfName("Fl"):
+			zr, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			decoded, err := io.ReadAll(zr)
+			if err != nil && len(decoded) == 0 {
+				return nil, err
+			}
+			data = decoded
+		case pdfName("ASCIIHexDecode"), pdfName("AHx"):
+			l := &pdfLexer{data: append(append([]byte("<"), bytes.TrimSuffix(bytes.TrimSpace(data), []byte(">"))...), '>')}
+			data = l.hexString()
+		case pdfName("ASCII85Decode"), pdfName("A85"):
+			text := bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~"))
+			if i := bytes.Index(text, []byte("~>")); i >= 0 {
+				text = text[:i]
+			}
+			decoded := make([]byte, 4*len(text)/5+4)
+			n, _, err := ascii85.Decode(decoded, text, true)
+			if err != nil {
+				return nil, err
+			}
+			data = decoded[:n]
+		default:
+			return nil, fmt.Errorf("%w: stream filter %v", ErrUnsupportedFormat, filter)
+		}
+	}
+	return data, nil
+}
+
+// pdfPage is a page with its (possibly inherited) resources.
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages returns the pages in order.
+func (f *pdfFile) pages() []pdfPage {
+	var pages []pdfPage
+	var walk func(node pdfDict, resources pdfDict, depth int)
+	walk = func(node pdfDict, resources pdfDict, depth int) {
+		if depth > 64 {
+			return
+		}
+		if r, ok := f.resolve(node["Resources"]).(pdfDict); ok {
+			resources = r
+		}
+		if kids, ok := f.resolve(node["Kids"]).(pdfArray); ok {
+			for _, kid := range kids {
+				if kidDict, ok := f.resolve(kid).(pdfDict); ok {
+					walk(kidDict, resources, depth+1)
+				}
+			}
+			return
+		}
+		pages = append(pages, pdfPage{dict: node, resources: resources})
+	}
+
+	if root, ok := f.resolve(f.trailer["Root"]).(pdfDict); ok {
+		if tree, ok := f.resolve(root["Pages"]).(pdfDict); ok {
+			walk(tree, nil, 0)
+		}
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	// Without a page tree, take the pages in object order
+	var nums []int
+	for num, v := range f.objects {
+		if dict, ok := v.(pdfDict); ok && dict["Type"] == pdfName("Page") {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		dict := f.objects[num].(pdfDict)
+		resources, _ := f.resolve(dict["Resources"]).(pdfDict)
+		pages = append(pages, pdfPage{dict: dict, resources: resources})
+	}
+	return pages
+}
+
+// pageText returns the text of page.
+func (f *pdfFile) pageText(page pdfPage) string {
+	var contents []byte
+	streams := []interface{}{page.dict["Contents"]}
+	if array, ok := f.resolve(page.dict["Contents"]).(pdfArray); ok {
+		streams = array
+	}
+	for _, v := range streams {
+		if stream, ok := f.resolve(v).(*pdfStream); ok {
+			if data, err := f.decode(stream); err == nil {
+				contents = append(contents, data...)
+				contents = append(contents, '\n')
+			}
+		}
+	}
+
+	e := &pdfTextExtractor{file: f}
+	e.extract(contents, page.resources, 0)
+	return strings.TrimSpace(e.text.String())
+}
+
+// pdfTextExtractor extracts the text of content streams.
+type pdfTextExtractor struct {
+	file *pdfFile
+	text strings.Builder
+}
+
+// extract adds the text shown by a content stream using resources.
+func (e *pdfTextExtractor) extract(contents []byte, resources pdfDict, depth int) {
+	fonts := make(map[string]*pdfFont)
+	var font *pdfFont
+	var operands []interface{}
+	lastY := math.NaN()
+
+	l := &pdfLexer{data: contents}
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return
+		}
+		op, ok := tok.(pdfOp)
+		if !ok {
+			operands = append(operands, tok)
+			continue
+		}
+
+		switch op {
+		case "Tf":
+			if len(operands) > 0 {
+				if name, ok := operands[0].(pdfName); ok {
+					if _, ok := fonts[string(name)]; !ok {
+						fonts[string(name)] = e.font(resources, string(name))
+					}
+					font = fonts[string(name)]
+				}
+			}
+		case "Tj":
+			e.show(font, operands)
+		case "'", `"`:
+			e.newline()
+			e.show(font, operands)
+		case "TJ":
+			if len(operands) > 0 {
+				if array, ok := operands[len(operands)-1].(pdfArray); ok {
+					for _, item := range array {
+						switch item := item.(type) {
+						case pdfString:
+							e.text.WriteString(font.decode(item))
+						case float64:
+							// A large negative adjustment separates words
+							if item < -200 {
+								e.space()
+							}
+						}
+					}
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				if ty, ok := operands[1].(float64); ok && math.Abs(ty) > 0.01 {
+					e.newline()
+				} else {
+					e.space()
+				}
+			}
+		case "T*":
+			e.newline()
+		case "Tm":
+			if len(operands) >= 6 {
+				if y, ok := operands[5].(float64); ok {
+					if !math.IsNaN(lastY) && math.Abs(y-lastY) > 0.01 {
+						e.newline()
+					} else {
+						e.space()
+					}
+					lastY = y
+				}
+			}
+		case "ET":
+			e.space()
+		case "Do":
+			if len(operands) > 0 && depth < 8 {
+				if name, ok := operands[0].(pdfName); ok {
+					e.form(resources, string(name), depth)
+				}
+			}
+		case "ID":
+			// Skip the data of an inline image
+			end := bytes.Index(contents[l.pos:], []byte("EI"))
+			for end >= 0 && l.pos+end+2 < len(contents) && !isPDFDelimiter(contents[l.pos+end+2]) {
+				next := bytes.Index(contents[l.pos+end+2:], []byte("EI"))
+				if next < 0 {
+					end = -1
+					break
+				}
+				end += 2 + next
+			}
+			if end < 0 {
+				return
+			}
+			l.pos += end + 2
+		}
+		operands = operands[:0]
+	}
+}
+
+// show adds the string operand of a text showing operator.
+func (e *pdfTextExtractor) show(font *pdfFont, operands []interface{}) {
+	if len(operands) == 0 {
+		return
+	}
+	if s, ok := operands[len(operands)-1].(pdfString); ok {
+		e.text.WriteString(font.decode(s))
+	}
+}
+
+// space adds a space unless the text ends with whitespace.
+func (e *pdfTextExtractor) space() {
+	s := e.text.String()
+	if s != "" && !isSpace(s[len(s)-1]) {
+		e.text.WriteString(" ")
+	}
+}
+
+// newline starts a new line unless the text is empty or ends with one.
+func (e *pdfTextExtractor) newline() {
+	s := strings.TrimRight(e.text.String(), " ")
+	if s != "" && !strings.HasSuffix(s, "\n") {
+		e.text.Reset()
+		e.text.WriteString(s)
+		e.text.WriteString("\n")
+	}
+}
+
+// form adds the text of the form XObject name of resources.
+func (e *pdfTextExtractor) form(resources pdfDict, name string, depth int) {
+	xobjects, ok := e.file.resolve(resources["XObject"]).(pdfDict)
+	if !ok {
+		return
+	}
+	stream, ok := e.file.resolve(xobjects[name]).(*pdfStream)
+	if !ok || stream.dict["Subtype"] != pdfName("Form") {
+		return
+	}
+	data, err := e.file.decode(stream)
+	if err != nil {
+		return
+	}
+	formResources, ok := e.file.resolve(stream.dict["Resources"]).(pdfDict)
+	if !ok {
+		formResources = resources
+	}
+	e.extract(data, formResources, depth+1)
+}
+
+// font returns the font name of resources (nil if it is not defined).
+func (e *pdfTextExtractor) font(resources pdfDict, name string) *pdfFont {
+	fonts, ok := e.file.resolve(resources["Font"]).(pdfDict)
+	if !ok {
+		return nil
+	}
+	dict, ok := e.file.resolve(fonts[name]).(pdfDict)
+	if !ok {
+		return nil
+	}
+
+	font := &pdfFont{composite: dict["Subtype"] == pdfName("Type0")}
+	if stream, ok := e.file.resolve(dict["ToUnicode"]).(*pdfStream); ok {
+		if data, err := e.file.decode(stream); err == nil {
+			font.cmap = parseCMap(data)
+		}
+	}
+	return font
+}
+
+// pdfFont decodes the strings shown with a font.
+type pdfFont struct {
+	// composite is set for Type0 fonts, whose codes have 2 bytes by default.
+	composite bool
+
+	// cmap is the ToUnicode map (nil if the font has none).
+	cmap *pdfCMap
+}
+
+// decode returns the text of s shown with font f (nil for an unknown font).
+func (f *pdfFont) decode(s pdfString) string {
+	if f != nil && f.cmap != nil {
+		return f.cmap.decode(s, f.composite)
+	}
+	if f != nil && f.composite {
+		// Glyph IDs without a ToUnicode map cannot be decoded
+		return ""
+	}
+	return decodeSimple(s)
+}
+
+// pdfCMap is a ToUnicode map.
+type pdfCMap struct {
+	// codeLen is the length in bytes of the codes (0 if unknown).
+	codeLen int
+	chars   map[uint32]string
+}
+
+// parseCMap reads the mappings of a ToUnicode CMap.
+func parseCMap(data []byte) *pdfCMap {
+	cmap := &pdfCMap{chars: make(map[uint32]string)}
+	var operands []interface{}
+	section := ""
+
+	l := &pdfLexer{data: data}
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return cmap
+		}
+		op, ok := tok.(pdfOp)
+		if !ok {
+			if section != "" {
+				operands = append(operands, tok)
+			}
+			continue
+		}
+
+		switch op {
+		case "begincodespacerange", "beginbfchar", "beginbfrange":
+			section = string(op)
+			operands = operands[:0]
+		case "endcodespacerange":
+			if len(operands) > 0 {
+				if lo, ok := operands[0].(pdfString); ok {
+					cmap.codeLen = len(lo)
+				}
+			}
+			section = ""
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					cmap.chars[codeOf(src)] = decodeUTF16(dst)
+				}
+			}
+			section = ""
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 || codeOf(hi) < codeOf(lo) || codeOf(hi)-codeOf(lo) > 0xffff {
+					continue
+				}
+				switch dst := operands[i+2].(type) {
+				case pdfString:
+					units := utf16.Decode(utf16Units(dst))
+					for offset := uint32(0); len(units) > 0 && offset <= codeOf(hi)-codeOf(lo); offset++ {
+						mapped := append([]rune{}, units...)
+						mapped[len(mapped)-1] += rune(offset)
+						cmap.chars[codeOf(lo)+offset] = string(mapped)
+					}
+				case pdfArray:
+					for j, item := range dst {
+						if s, ok := item.(pdfString); ok {
+							cmap.chars[codeOf(lo)+uint32(j)] = decodeUTF16(s)
+						}
+					}
+				}
+			}
+			section = ""
+		}
+	}
+}
+
+// decode returns the text of s, whose codes have codeLen bytes, or 2 bytes
+// for composite fonts and 1 byte otherwise if codeLen is unknown.
+func (m *pdfCMap) decode(s pdfString, composite bool) string {
+	n := m.codeLen
+	if n == 0 {
+		n = 1
+		if composite {
+			n = 2
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i+n <= len(s); i += n {
+		if text, ok := m.chars[codeOf(s[i:i+n])]; ok {
+			b.WriteString(text)
+		} else if n == 1 {
+			b.WriteString(decodeSimple(s[i : i+1]))
+		}
+	}
+	return b.String()
+}
+
+// codeOf returns the big-endian value of a character code.
+func codeOf(b []byte) uint32 {
+	var code uint32
+	for _, c := range b {
+		code = code<<8 | uint32(c)
+	}
+	return code
+}
+
+// utf16Units returns the big-endian UTF-16 code units of b.
+func utf16Units(b []byte) []uint16 {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return units
+}
+
+// decodeUTF16 decodes big-endian UTF-16.
+func decodeUTF16(b []byte) string {
+	return string(utf16.Decode(utf16Units(b)))
+}
+
+// decodeTextString decodes a PDF text string: UTF-16 with a byte order mark,
+// or else PDFDocEncoding.
+func decodeTextString(s pdfString) string {
+	if len(s) >= 2 && s[0] == 0xfe && s[1] == 0xff {
+		return decodeUTF16(s[2:])
+	}
+	return decodeSimple(s)
+}
+
+// winAnsi maps the codes 0x80 to 0x9f of WinAnsiEncoding that differ from
+// Latin-1.
+var winAnsi = map[byte]rune{
+	0x80: '€', 0x85: '…', 0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”',
+	0x95: '•', 0x96: '–', 0x97: '—', 0x99: '™',
+}
+
+// decodeSimple decodes a string shown with a standard encoding, treated as
+// WinAnsiEncoding. Control characters are dropped.
+func decodeSimple(s []byte) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch r, ok := winAnsi[c]; {
+		case ok:
+			b.WriteRune(r)
+		case c == '\t' || c == '\n':
+			b.WriteByte(' ')
+		case c < 0x20 || (c >= 0x7f && c < 0xa0):
+		default:
+			b.WriteRune(rune(c))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,226 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// Metadata keys set on the chunks of URLs.
+const (
+	// MetadataSourceURL holds the URL given to IngestURL.
+	MetadataSourceURL = "source_url"
+
+	// MetadataContentHash holds the SHA-256 of the text of the document, in
+	// hexadecimal.
+	MetadataContentHash = "content_hash"
+)
+
+// DefaultMaxFetchSize is the default maximum size of a fetched document in bytes.
+const DefaultMaxFetchSize = 20 << 20
+
+// URLResult is the result of IngestURL.
+type URLResult struct {
+	// URL is the ingested URL.
+	URL string
+
+	// ContentHash is the SHA-256 of the text of the document, in hexadecimal.
+	ContentHash string
+
+	// Unchanged is set when the URL was already ingested with the same text.
+	// Nothing is added or deleted.
+	Unchanged bool
+
+	// Added is the result of adding the chunks (nil if Unchanged).
+	Added *core.BatchAddResult
+
+	// Replaced is the number of memories of previous ingestions of the URL
+	// that were deleted.
+	Replaced int
+}
+
+// FormatOfContentType returns the format of a document from its media type:
+// text/html, text/markdown, application/pdf or text/plain.
+//
+// Returns ErrUnsupportedFormat for other media types.
+func FormatOfContentType(contentType string) (Format, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, contentType)
+	}
+	switch mediaType {
+	case "text/html", "application/xhtml+xml":
+		return FormatHTML, nil
+	case "text/markdown", "text/x-markdown":
+		return FormatMarkdown, nil
+	case "application/pdf":
+		return FormatPDF, nil
+	case "text/plain":
+		return FormatText, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, mediaType)
+	}
+}
+
+// Fetch downloads and loads the document at rawURL.
+//
+// The format is given by the Content-Type of the response, or by the
+// extension of the URL path if the type is missing or generic. HTML pages
+// are loaded with HTMLLoader.Readability, dropping navigation, ads and other
+// boilerplate. The source of the document is rawURL.
+//
+// Returns an error if the URL is not http or https, the response status is
+// not 2xx or the document is larger than Config.MaxFetchSize.
+func (i *Ingester) Fetch(ctx context.Context, rawURL string) (*Document, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q: want an http or https URL", rawURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "powermem-go")
+	req.Header.Set("Accept", "text/html, application/xhtml+xml, text/markdown, text/plain, application/pdf;q=0.9, */*;q=0.1")
+
+	resp, err := i.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("fetch %s: %s", rawURL, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, i.config.MaxFetchSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", rawURL, err)
+	}
+	if int64(len(data)) > i.config.MaxFetchSize {
+		return nil, fmt.Errorf("fetch %s: document larger than %d bytes", rawURL, i.config.MaxFetchSize)
+	}
+
+	format, err := FormatOfContentType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		// Generic or missing type: use the extension
+		var extErr error
+		if format, extErr = FormatOf(u.Path); extErr != nil {
+			return nil, fmt.Errorf("fetch %s: %w", rawURL, err)
+		}
+	}
+
+	var loader Loader = HTMLLoader{Readability: true}
+	if format != FormatHTML {
+		if loader, err = LoaderFor(format); err != nil {
+			return nil, err
+		}
+	}
+	doc, err := loader.Load(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", rawURL, err)
+	}
+	doc.Source = rawURL
+	doc.Format = format
+	return doc, nil
+}
+
+// IngestURL fetches the document at rawURL (see Fetch) and ingests it,
+// replacing previous ingestions of the URL.
+//
+// The chunks get the metadata of Ingest, plus MetadataSourceURL and
+// MetadataContentHash. Previous ingestions are the memories with the same
+// MetadataSourceURL and the user and agent of opts: if their content hash is
+// the hash of the document, nothing is added and the result is Unchanged;
+// otherwise they are deleted once the new chunks are added.
+//
+// Example:
+//
+//	result, err := ingester.IngestURL(ctx, "https://example.com/docs/setup", core.WithUserID("user_001"))
+//	if err != nil {
+//	    return err
+//	}
+//	if result.Unchanged {
+//	    fmt.Println("Already up to date")
+//	}
+func (i *Ingester) IngestURL(ctx context.Context, rawURL string, opts ...core.AddOption) (*URLResult, error) {
+	doc, err := i.Fetch(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256([]byte(doc.Text))
+	result := &URLResult{URL: rawURL, ContentHash: hex.EncodeToString(sum[:])}
+
+	options := &core.AddOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	previous, err := i.ingested(ctx, rawURL, options.UserID, options.AgentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, memory := range previous {
+		if hash, _ := memory.Metadata[MetadataContentHash].(string); hash == result.ContentHash {
+			result.Unchanged = true
+			return result, nil
+		}
+	}
+
+	if doc.Metadata == nil {
+		doc.Metadata = make(map[string]interface{}, 2)
+	}
+	doc.Metadata[MetadataSourceURL] = rawURL
+	doc.Metadata[MetadataContentHash] = result.ContentHash
+	result.Added, err = i.Ingest(ctx, doc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if result.Added.FailedCount > 0 {
+		// Keep the previous ingestion rather than a partial one
+		return result, nil
+	}
+
+	for _, memory := range previous {
+		err := i.client.Delete(ctx, memory.ID,
+			core.WithUserIDForDelete(options.UserID), core.WithAgentIDForDelete(options.AgentID))
+		if err != nil {
+			return result, fmt.Errorf("delete previous ingestion of %s: %w", rawURL, err)
+		}
+		result.Replaced++
+	}
+	return result, nil
+}
+
+// ingested returns the memories of previous ingestions of rawURL.
+func (i *Ingester) ingested(ctx context.Context, rawURL, userID, agentID string) ([]*core.Memory, error) {
+	const pageSize = 100
+	var memories []*core.Memory
+	for offset := 0; ; offset += pageSize {
+		page, err := i.client.GetAll(ctx,
+			core.WithUserIDForGetAll(userID),
+			core.WithAgentIDForGetAll(agentID),
+			core.WithFiltersForGetAll(map[string]interface{}{MetadataSourceURL: rawURL}),
+			core.WithLimitForGetAll(pageSize),
+			core.WithOffset(offset),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("find previous ingestions of %s: %w", rawURL, err)
+		}
+		memories = append(memories, page...)
+		if len(page) < pageSize {
+			return memories, nil
+		}
+	}
+}
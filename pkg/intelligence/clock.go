@@ -0,0 +1,73 @@
+package intelligence
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the time source of retention and lifecycle decisions.
+//
+// The default is SystemClock. Tests and simulations use a ManualClock to
+// advance time without sleeping.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// SystemClock is the Clock of the operating system.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+// Now returns time.Now().
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ManualClock is a Clock that only moves when told to. It is safe for
+// concurrent use.
+//
+// Example:
+//
+//	clock := NewManualClock(time.Now())
+//	manager := NewEbbinghausManager(0.1, 0.3).WithClock(clock)
+//	clock.Advance(7 * 24 * time.Hour)
+//	retention := manager.CalculateRetention(createdAt, nil)
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManualClock creates a ManualClock set to now.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the time of the clock.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set sets the clock to now.
+func (c *ManualClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// clockOrSystem returns clock, or SystemClock if clock is nil.
+func clockOrSystem(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock
+	}
+	return clock
+}
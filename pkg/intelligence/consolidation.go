@@ -0,0 +1,158 @@
+// Package intelligence provides intelligent memory management features.
+package intelligence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/oceanbase/powermem-go/pkg/llm"
+)
+
+// Consolidation events.
+const (
+	// ConsolidationMerge merges redundant memories into one.
+	ConsolidationMerge = "MERGE"
+
+	// ConsolidationResolve replaces contradicting memories with the fact
+	// that holds.
+	ConsolidationResolve = "RESOLVE"
+
+	// ConsolidationPromote promotes a stable fact to long-term memory.
+	ConsolidationPromote = "PROMOTE"
+)
+
+// ConsolidationAction is a consolidation decision from the LLM.
+type ConsolidationAction struct {
+	// Event is the operation: MERGE, RESOLVE or PROMOTE.
+	Event string `json:"event"`
+
+	// IDs are the temporary IDs of the memories the action applies to.
+	IDs []string `json:"ids"`
+
+	// Text is the content of the memory replacing the memories of a MERGE
+	// or RESOLVE (empty for PROMOTE).
+	Text string `json:"text,omitempty"`
+
+	// Reason explains the decision.
+	Reason string `json:"reason,omitempty"`
+}
+
+// MemoryConsolidator reviews the memories of a user, like the "reflection"
+// of generative agents.
+//
+// It asks the LLM to:
+//   - MERGE: combine redundant memories into one
+//   - RESOLVE: replace contradicting memories with the fact that holds
+//   - PROMOTE: mark stable facts (identity, lasting preferences) as long-term
+//
+// Example usage:
+//
+//	consolidator := NewMemoryConsolidator(llmProvider)
+//	actions, err := consolidator.Consolidate(ctx, []ExistingMemory{
+//	    {ID: "0", Text: "Likes coffee"},
+//	    {ID: "1", Text: "Loves coffee in the morning"},
+//	})
+type MemoryConsolidator struct {
+	// llm is the LLM provider for consolidation.
+	llm llm.Provider
+}
+
+// NewMemoryConsolidator creates a new memory consolidator.
+func NewMemoryConsolidator(llm llm.Provider) *MemoryConsolidator {
+	return &MemoryConsolidator{llm: llm}
+}
+
+// Consolidate decides how to consolidate memories.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - memories: Memories to review, with temporary IDs
+//
+// Returns the actions of the LLM. Actions with an unknown event or without
+// IDs are dropped; the caller must check that the IDs exist.
+func (m *MemoryConsolidator) Consolidate(ctx context.Context, memories []ExistingMemory) ([]ConsolidationAction, error) {
+	if len(memories) == 0 {
+		return []ConsolidationAction{}, nil
+	}
+
+	memoriesJSON, _ := json.Marshal(memories)
+	messages := []llm.Message{
+		{Role: "system", Content: consolidationPrompt},
+		{Role: "user", Content: fmt.Sprintf("Memories:\n%s", memoriesJSON)},
+	}
+
+	response, err := m.llm.GenerateWithMessages(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consolidate memories: %w", err)
+	}
+
+	actions, err := parseConsolidationResponse(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse consolidation response: %w", err)
+	}
+	return actions, nil
+}
+
+// parseConsolidationResponse parses the actions of the LLM response.
+func parseConsolidationResponse(response string) ([]ConsolidationAction, error) {
+	var result struct {
+		Actions []struct {
+			Event  string        `json:"event"`
+			IDs    []interface{} `json:"ids"`
+			Text   string        `json:"text"`
+			Reason string        `json:"reason"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal([]byte(removeCodeBlocks(response)), &result); err != nil {
+		return nil, fmt.Errorf("invalid JSON response: %w", err)
+	}
+
+	actions := make([]ConsolidationAction, 0, len(result.Actions))
+	for _, item := range result.Actions {
+		action := ConsolidationAction{
+			Event:  strings.ToUpper(strings.TrimSpace(item.Event)),
+			Text:   strings.TrimSpace(item.Text),
+			Reason: item.Reason,
+		}
+		switch action.Event {
+		case ConsolidationMerge, ConsolidationResolve, ConsolidationPromote:
+		default:
+			continue
+		}
+		for _, id := range item.IDs {
+			switch id := id.(type) {
+			case string:
+				action.IDs = append(action.IDs, id)
+			case float64:
+				action.IDs = append(action.IDs, fmt.Sprintf("%.0f", id))
+			}
+		}
+		if len(action.IDs) == 0 {
+			continue
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// consolidationPrompt is the system prompt of MemoryConsolidator.
+const consolidationPrompt = `You consolidate the memories an assistant keeps about a user, the way people reflect on what they know.
+
+Review the memories and decide:
+- MERGE: memories that say the same thing, or that belong in one memory, are replaced with one self-contained memory keeping all their details. "ids" lists the merged memories, "text" the merged memory.
+- RESOLVE: memories that contradict each other are replaced with the fact that holds, usually the most recent or most specific one. "ids" lists the contradicting memories, "text" the fact that holds.
+- PROMOTE: stable facts that will stay true for a long time (identity, relationships, lasting preferences, health) are promoted to long-term memory. "ids" lists the promoted memories.
+
+Rules:
+1. Memories are listed oldest first. Only use the ids of the memories given. A memory is in at most one MERGE or RESOLVE.
+2. Keep time references and the language of the memories.
+3. Do not merge memories about different things, and do not promote passing states or plans.
+4. Give a short "reason" for each action. Leave out memories that need no action.
+
+Example:
+Memories: [{"id":"0","text":"Likes coffee"},{"id":"1","text":"Drinks coffee every morning"},{"id":"2","text":"Lives in Berlin"},{"id":"3","text":"Moved to Lisbon in May 2024"},{"id":"4","text":"Is vegetarian"}]
+Output: {"actions": [{"event": "MERGE", "ids": ["0", "1"], "text": "Likes coffee and drinks it every morning", "reason": "Same preference"}, {"event": "RESOLVE", "ids": ["2", "3"], "text": "Lives in Lisbon since May 2024, previously Berlin", "reason": "The move is more recent"}, {"event": "PROMOTE", "ids": ["4"], "reason": "Lasting diet"}]}
+
+Return JSON only: {"actions": [{"event": "MERGE|RESOLVE|PROMOTE", "ids": ["0"], "text": "...", "reason": "..."}]}`
@@ -0,0 +1,81 @@
+// Package intelligence provides intelligent memory management features.
+package intelligence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/oceanbase/powermem-go/pkg/llm"
+)
+
+// MemoryCorrector rewrites memories reported as incorrect.
+//
+// It gives the LLM the memory and the user's feedback, and gets back the
+// corrected memory, or nothing if the memory should be forgotten.
+//
+// Example usage:
+//
+//	corrector := NewMemoryCorrector(llmProvider)
+//	corrected, err := corrector.Correct(ctx, "Lives in Berlin", "I moved to Lisbon in May")
+//	if corrected == "" {
+//	    // Forget the memory
+//	}
+type MemoryCorrector struct {
+	// llm is the LLM provider for corrections.
+	llm llm.Provider
+}
+
+// NewMemoryCorrector creates a new memory corrector.
+func NewMemoryCorrector(llm llm.Provider) *MemoryCorrector {
+	return &MemoryCorrector{llm: llm}
+}
+
+// Correct rewrites an incorrect memory according to feedback.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - memory: Content of the incorrect memory
+//   - feedback: What the user said is wrong (may be empty)
+//
+// Returns the corrected memory, or an empty string if the memory should be
+// forgotten, e.g. because the feedback does not say what is right.
+func (m *MemoryCorrector) Correct(ctx context.Context, memory, feedback string) (string, error) {
+	if feedback == "" {
+		feedback = "(no details given)"
+	}
+	messages := []llm.Message{
+		{Role: "system", Content: correctionPrompt},
+		{Role: "user", Content: fmt.Sprintf("Memory: %s\nFeedback: %s", memory, feedback)},
+	}
+
+	response, err := m.llm.GenerateWithMessages(ctx, messages)
+	if err != nil {
+		return "", fmt.Errorf("failed to correct memory: %w", err)
+	}
+
+	var result struct {
+		Memory string `json:"memory"`
+	}
+	if err := json.Unmarshal([]byte(removeCodeBlocks(response)), &result); err != nil {
+		return "", fmt.Errorf("failed to parse correction response: invalid JSON response: %w", err)
+	}
+	return strings.TrimSpace(result.Memory), nil
+}
+
+// correctionPrompt is the system prompt of MemoryCorrector.
+const correctionPrompt = `You correct a memory that a user reported as incorrect.
+
+You are given the memory and the user's feedback. If the feedback says what is correct, rewrite the memory as one self-contained fact with the correction, keeping the details of the memory that are not disputed and the language of the memory. If the feedback only says the memory is wrong, or the memory should be forgotten, return an empty memory.
+
+Examples:
+Memory: Lives in Berlin
+Feedback: I moved to Lisbon in May
+Output: {"memory": "Lives in Lisbon since May"}
+
+Memory: Is allergic to peanuts
+Feedback: That's not true, forget it
+Output: {"memory": ""}
+
+Return JSON only: {"memory": "corrected memory"}`
@@ -4,11 +4,87 @@ package intelligence
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/oceanbase/powermem-go/pkg/llm"
 	"github.com/oceanbase/powermem-go/pkg/storage"
 )
 
+// MergeStrategy selects how MergeMemories combines a new memory with an existing duplicate.
+type MergeStrategy string
+
+const (
+	// MergeStrategyConcatenate appends the new content to the existing content
+	// and averages the embeddings. This is the default strategy.
+	MergeStrategyConcatenate MergeStrategy = "concatenate"
+
+	// MergeStrategyKeepNewest replaces the existing content and embedding with the new ones.
+	MergeStrategyKeepNewest MergeStrategy = "keep_newest"
+
+	// MergeStrategyKeepLongest keeps whichever content is longer, together with its embedding.
+	MergeStrategyKeepLongest MergeStrategy = "keep_longest"
+
+	// MergeStrategyLLMMerge asks the LLM to combine both contents into a single memory.
+	MergeStrategyLLMMerge MergeStrategy = "llm_merge"
+)
+
+// ParseMergeStrategy converts a string to a MergeStrategy.
+//
+// An empty string yields MergeStrategyConcatenate. Unknown values return an error.
+func ParseMergeStrategy(s string) (MergeStrategy, error) {
+	switch MergeStrategy(s) {
+	case "":
+		return MergeStrategyConcatenate, nil
+	case MergeStrategyConcatenate, MergeStrategyKeepNewest, MergeStrategyKeepLongest, MergeStrategyLLMMerge:
+		return MergeStrategy(s), nil
+	default:
+		return "", fmt.Errorf("unknown merge strategy: %q", s)
+	}
+}
+
+// DefaultMergePrompt is the prompt used by MergeStrategyLLMMerge when no custom prompt is set.
+//
+// The placeholders {existing} and {new} are replaced with the existing and new memory contents.
+const DefaultMergePrompt = `You are a memory consolidation assistant. Two memories describe the same information.
+Combine them into a single, concise memory that keeps every distinct detail and prefers the new memory when they conflict.
+
+Existing memory: {existing}
+New memory: {new}
+
+Return only the merged memory text, without any explanation.`
+
+// maxMergeHistory is the maximum number of merge provenance entries kept in metadata.
+const maxMergeHistory = 10
+
+// maxMergeAttempts is the number of times a merge is retried when the existing
+// memory is updated concurrently.
+const maxMergeAttempts = 3
+
+// EmbedFunc generates an embedding vector for text.
+type EmbedFunc func(ctx context.Context, text string) ([]float64, error)
+
+// MergeConfig configures how DedupManager merges duplicate memories.
+type MergeConfig struct {
+	// Strategy is the merge strategy. Defaults to MergeStrategyConcatenate.
+	Strategy MergeStrategy
+
+	// LLM is the provider used by MergeStrategyLLMMerge.
+	LLM llm.Provider
+
+	// Prompt is a custom prompt for MergeStrategyLLMMerge (optional).
+	// It may contain the {existing} and {new} placeholders.
+	Prompt string
+
+	// Embed re-embeds LLM-merged content (optional).
+	// If nil, the embeddings of both memories are averaged.
+	Embed EmbedFunc
+}
+
 // DedupManager manages memory deduplication by detecting and merging similar memories.
 //
 // It uses vector similarity search to find duplicate or highly similar memories,
@@ -29,6 +105,13 @@ type DedupManager struct {
 	// Memories with similarity >= threshold are considered duplicates.
 	// Typical range: 0.9-0.98 (higher = stricter, fewer duplicates detected)
 	threshold float64
+
+	// merge configures the merge strategy used by MergeMemories.
+	merge MergeConfig
+
+	// clock is the time source of merges and of the expiration of the
+	// memories compared.
+	clock Clock
 }
 
 // NewDedupManager creates a new deduplication manager.
@@ -45,9 +128,65 @@ func NewDedupManager(store storage.VectorStore, threshold float64) *DedupManager
 	return &DedupManager{
 		store:     store,
 		threshold: threshold,
+		merge:     MergeConfig{Strategy: MergeStrategyConcatenate},
+		clock:     SystemClock,
 	}
 }
 
+// WithClock returns a copy of the manager that reads the current time from
+// clock, or from SystemClock if clock is nil.
+func (m *DedupManager) WithClock(clock Clock) *DedupManager {
+	withClock := *m
+	withClock.clock = clockOrSystem(clock)
+	return &withClock
+}
+
+// now returns the current time of the manager's clock.
+func (m *DedupManager) now() time.Time {
+	return clockOrSystem(m.clock).Now()
+}
+
+// NewDedupManagerWithMerge creates a new deduplication manager with a merge configuration.
+//
+// Parameters:
+//   - store: Vector store for similarity search
+//   - threshold: Similarity threshold (0.0-1.0). If 0, defaults to 0.95.
+//   - merge: Merge configuration. If nil or its Strategy is empty, concatenation is used.
+//
+// Returns a new DedupManager, or an error if the strategy is unknown or
+// MergeStrategyLLMMerge is selected without an LLM provider.
+//
+// Example:
+//
+//	manager, err := NewDedupManagerWithMerge(store, 0.95, &MergeConfig{
+//	    Strategy: MergeStrategyLLMMerge,
+//	    LLM:      llmProvider,
+//	    Embed:    embedder.Embed,
+//	})
+func NewDedupManagerWithMerge(store storage.VectorStore, threshold float64, merge *MergeConfig) (*DedupManager, error) {
+	m := NewDedupManager(store, threshold)
+	if merge == nil {
+		return m, nil
+	}
+
+	strategy, err := ParseMergeStrategy(string(merge.Strategy))
+	if err != nil {
+		return nil, err
+	}
+	if strategy == MergeStrategyLLMMerge && merge.LLM == nil {
+		return nil, fmt.Errorf("merge strategy %q requires an LLM provider", strategy)
+	}
+
+	m.merge = *merge
+	m.merge.Strategy = strategy
+	return m, nil
+}
+
+// MergeStrategy returns the merge strategy used by MergeMemories.
+func (m *DedupManager) MergeStrategy() MergeStrategy {
+	return m.merge.Strategy
+}
+
 // CheckDuplicate checks if a memory is a duplicate of an existing memory.
 //
 // The method:
@@ -71,6 +210,7 @@ func (m *DedupManager) CheckDuplicate(ctx context.Context, embedding []float64,
 		UserID:  userID,
 		AgentID: agentID,
 		Limit:   5, // Only check top 5 most similar
+		Now:     m.now(),
 	}
 
 	memories, err := m.store.Search(ctx, embedding, opts)
@@ -90,14 +230,20 @@ func (m *DedupManager) CheckDuplicate(ctx context.Context, embedding []float64,
 
 // MergeMemories merges a new memory with an existing memory.
 //
-// The merge strategy:
-//  1. Combines content by appending new content to existing content
-//  2. Averages the embedding vectors
-//  3. Normalizes the resulting embedding
-//  4. Updates the existing memory with merged data
+// The content and embedding are combined according to the configured strategy:
+//   - concatenate: appends the new content and averages the embeddings (default)
+//   - keep_newest: keeps the new content and embedding
+//   - keep_longest: keeps the longer content and its embedding
+//   - llm_merge: asks the LLM to merge both contents, then re-embeds the result
+//     (or averages the embeddings when no embed function is configured)
+//
+// Merge provenance is recorded in the memory metadata under "merge_history"
+// (strategy, timestamp, previous and incoming content, most recent last) and
+// "merge_count". Only the last 10 history entries are kept.
 //
-// Note: More sophisticated merge strategies (e.g., using LLM) can be implemented
-// by extending this method.
+// The merged memory is written only if the existing memory was not updated
+// since it was read. Otherwise the merge is redone on the fresh memory, up to
+// three times, before failing with storage.ErrVersionConflict.
 //
 // Parameters:
 //   - ctx: Context for cancellation
@@ -107,39 +253,287 @@ func (m *DedupManager) CheckDuplicate(ctx context.Context, embedding []float64,
 //
 // Returns the merged memory, or an error if merge fails.
 func (m *DedupManager) MergeMemories(ctx context.Context, existingID int64, newContent string, newEmbedding []float64) (*Memory, error) {
+	var err error
+	for attempt := 0; attempt < maxMergeAttempts; attempt++ {
+		var merged *Memory
+		merged, err = m.mergeOnce(ctx, existingID, newContent, newEmbedding)
+		if !errors.Is(err, storage.ErrVersionConflict) {
+			return merged, err
+		}
+	}
+	return nil, err
+}
+
+// mergeOnce reads the existing memory, merges newContent into it and writes
+// the result if the memory's version is unchanged.
+func (m *DedupManager) mergeOnce(ctx context.Context, existingID int64, newContent string, newEmbedding []float64) (*Memory, error) {
 	// Get existing memory (without access control in dedup context)
 	existing, err := m.store.Get(ctx, existingID, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	// Simple merge strategy: append new content to existing content
-	// More sophisticated strategies can use LLM for intelligent merging
-	mergedContent := existing.Content + " " + newContent
+	var mergedContent string
+	var mergedEmbedding []float64
+
+	switch m.merge.Strategy {
+	case MergeStrategyKeepNewest:
+		mergedContent = newContent
+		mergedEmbedding = newEmbedding
+	case MergeStrategyKeepLongest:
+		if len(newContent) > len(existing.Content) {
+			mergedContent = newContent
+			mergedEmbedding = newEmbedding
+		} else {
+			mergedContent = existing.Content
+			mergedEmbedding = existing.Embedding
+		}
+	case MergeStrategyLLMMerge:
+		mergedContent, err = m.llmMerge(ctx, existing.Content, newContent)
+		if err != nil {
+			return nil, err
+		}
+		if m.merge.Embed != nil {
+			mergedEmbedding, err = m.merge.Embed(ctx, mergedContent)
+			if err != nil {
+				return nil, fmt.Errorf("embed merged content: %w", err)
+			}
+		} else {
+			mergedEmbedding = averageEmbeddings(existing.Embedding, newEmbedding)
+		}
+	default:
+		mergedContent = existing.Content + " " + newContent
+		mergedEmbedding = averageEmbeddings(existing.Embedding, newEmbedding)
+	}
 
-	// Calculate new embedding (average of both embeddings)
-	mergedEmbedding := averageEmbeddings(existing.Embedding, newEmbedding)
+	now := m.now()
+	metadata := withMergeProvenance(existing.Metadata, m.merge.Strategy, existing.Content, newContent, now)
 
 	// Update memory (without access control in dedup context)
-	updated, err := m.store.Update(ctx, existingID, mergedContent, mergedEmbedding, nil)
+	updated, err := m.store.Update(ctx, existingID, mergedContent, mergedEmbedding, &storage.UpdateOptions{
+		Metadata:        metadata,
+		ExpectedVersion: existing.Version,
+		Now:             now,
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	// Convert to intelligence.Memory type
+	return fromStorageMemory(updated), nil
+}
+
+// llmMerge asks the configured LLM to merge two memory contents.
+func (m *DedupManager) llmMerge(ctx context.Context, existingContent, newContent string) (string, error) {
+	if m.merge.LLM == nil {
+		return "", fmt.Errorf("merge strategy %q requires an LLM provider", MergeStrategyLLMMerge)
+	}
+
+	prompt := m.merge.Prompt
+	if prompt == "" {
+		prompt = DefaultMergePrompt
+	}
+	prompt = strings.NewReplacer("{existing}", existingContent, "{new}", newContent).Replace(prompt)
+
+	response, err := m.merge.LLM.Generate(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("llm merge: %w", err)
+	}
+
+	merged := strings.TrimSpace(response)
+	if merged == "" {
+		return "", fmt.Errorf("llm merge: empty response")
+	}
+	return merged, nil
+}
+
+// withMergeProvenance returns a copy of metadata with a merge history entry,
+// of a merge at now, appended.
+func withMergeProvenance(metadata map[string]interface{}, strategy MergeStrategy, previousContent, incomingContent string, now time.Time) map[string]interface{} {
+	result := make(map[string]interface{}, len(metadata)+2)
+	for k, v := range metadata {
+		result[k] = v
+	}
+
+	var history []interface{}
+	if existing, ok := result["merge_history"].([]interface{}); ok {
+		history = append(history, existing...)
+	}
+	history = append(history, map[string]interface{}{
+		"strategy":         string(strategy),
+		"merged_at":        now.UTC().Format(time.RFC3339),
+		"previous_content": previousContent,
+		"incoming_content": incomingContent,
+	})
+	if len(history) > maxMergeHistory {
+		history = history[len(history)-maxMergeHistory:]
+	}
+	result["merge_history"] = history
+
+	count := 0
+	switch v := result["merge_count"].(type) {
+	case float64:
+		count = int(v)
+	case int:
+		count = v
+	case int64:
+		count = int(v)
+	}
+	result["merge_count"] = count + 1
+
+	return result
+}
+
+// DuplicatePair is a pair of memories whose similarity meets the duplicate threshold.
+type DuplicatePair struct {
+	// FirstID is the ID of the first memory in the pair.
+	FirstID int64
+
+	// SecondID is the ID of the second memory in the pair.
+	SecondID int64
+
+	// Similarity is the cosine similarity between the two memories.
+	Similarity float64
+}
+
+// DuplicateCluster is a group of memories that are near-duplicates of each other.
+//
+// Clusters are connected components: every memory is similar to at least one
+// other memory in the cluster, but not necessarily to all of them.
+type DuplicateCluster struct {
+	// Memories contains the memories in the cluster.
+	Memories []*Memory
+
+	// Pairs contains the similar pairs that link the cluster, highest similarity first.
+	Pairs []DuplicatePair
+}
+
+// findDuplicatesPageSize is the page size used to load memories for FindDuplicates.
+const findDuplicatesPageSize = 500
+
+// FindDuplicates reports clusters of near-duplicate memories for a user without modifying them.
+//
+// All of the user's memories are loaded and compared pairwise using their stored
+// embeddings, so no embedding calls are made. This is intended for offline audits
+// where operators review duplicates before merging; cost grows quadratically with
+// the number of memories.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userID: User whose memories are checked (empty for all users)
+//   - threshold: Similarity threshold (0.0-1.0). If 0, the manager's threshold is used.
+//
+// Returns clusters with at least two memories, largest first.
+func (m *DedupManager) FindDuplicates(ctx context.Context, userID string, threshold float64) ([]*DuplicateCluster, error) {
+	if threshold == 0 {
+		threshold = m.threshold
+	}
+
+	// Load all memories for the user
+	var memories []*storage.Memory
+	for offset := 0; ; offset += findDuplicatesPageSize {
+		page, err := m.store.GetAll(ctx, &storage.GetAllOptions{
+			UserID: userID,
+			Limit:  findDuplicatesPageSize,
+			Offset: offset,
+			Now:    m.now(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		memories = append(memories, page...)
+		if len(page) < findDuplicatesPageSize {
+			break
+		}
+	}
+
+	// Union-find over memory indexes
+	parent := make([]int, len(memories))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	var pairs []DuplicatePair
+	pairFirst := []int{}
+	for i := 0; i < len(memories); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for j := i + 1; j < len(memories); j++ {
+			similarity := CosineSimilarity(memories[i].Embedding, memories[j].Embedding)
+			if similarity < threshold {
+				continue
+			}
+			pairs = append(pairs, DuplicatePair{
+				FirstID:    memories[i].ID,
+				SecondID:   memories[j].ID,
+				Similarity: similarity,
+			})
+			pairFirst = append(pairFirst, i)
+			if ri, rj := find(i), find(j); ri != rj {
+				parent[rj] = ri
+			}
+		}
+	}
+
+	// Group memories and pairs by cluster root
+	clustersByRoot := make(map[int]*DuplicateCluster)
+	var clusters []*DuplicateCluster
+	for i, mem := range memories {
+		root := find(i)
+		cluster, ok := clustersByRoot[root]
+		if !ok {
+			cluster = &DuplicateCluster{}
+			clustersByRoot[root] = cluster
+			clusters = append(clusters, cluster)
+		}
+		cluster.Memories = append(cluster.Memories, fromStorageMemory(mem))
+	}
+	for k, pair := range pairs {
+		cluster := clustersByRoot[find(pairFirst[k])]
+		cluster.Pairs = append(cluster.Pairs, pair)
+	}
+
+	// Keep only actual duplicate groups
+	result := make([]*DuplicateCluster, 0)
+	for _, cluster := range clusters {
+		if len(cluster.Memories) < 2 {
+			continue
+		}
+		sort.SliceStable(cluster.Pairs, func(a, b int) bool {
+			return cluster.Pairs[a].Similarity > cluster.Pairs[b].Similarity
+		})
+		result = append(result, cluster)
+	}
+	sort.SliceStable(result, func(a, b int) bool {
+		return len(result[a].Memories) > len(result[b].Memories)
+	})
+
+	return result, nil
+}
+
+// fromStorageMemory converts a storage.Memory to intelligence.Memory.
+func fromStorageMemory(m *storage.Memory) *Memory {
 	return &Memory{
-		ID:                updated.ID,
-		UserID:            updated.UserID,
-		AgentID:           updated.AgentID,
-		Content:           updated.Content,
-		Embedding:         updated.Embedding,
-		Metadata:          updated.Metadata,
-		CreatedAt:         updated.CreatedAt,
-		UpdatedAt:         updated.UpdatedAt,
-		RetentionStrength: updated.RetentionStrength,
-		LastAccessedAt:    updated.LastAccessedAt,
-		Score:             updated.Score,
-	}, nil
+		ID:                m.ID,
+		UserID:            m.UserID,
+		AgentID:           m.AgentID,
+		Content:           m.Content,
+		Embedding:         m.Embedding,
+		Metadata:          m.Metadata,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+		RetentionStrength: m.RetentionStrength,
+		LastAccessedAt:    m.LastAccessedAt,
+		Score:             m.Score,
+	}
 }
 
 // averageEmbeddings calculates the average of two embedding vectors.
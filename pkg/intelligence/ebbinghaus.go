@@ -52,6 +52,9 @@ type EbbinghausManager struct {
 	// reviewIntervals defines the review intervals in hours for spaced repetition.
 	// Default: [1, 6, 24, 72, 168] (1 hour, 6 hours, 1 day, 3 days, 1 week)
 	reviewIntervals []float64
+
+	// clock is the time source of retention and lifecycle decisions.
+	clock Clock
 }
 
 // NewEbbinghausManager creates a new Ebbinghaus forgetting curve manager.
@@ -74,6 +77,7 @@ func NewEbbinghausManager(decayRate, reinforcementFactor float64) *EbbinghausMan
 		longTermThreshold:   0.8,
 		initialRetention:    1.0,
 		reviewIntervals:     []float64{1, 6, 24, 72, 168}, // 1h, 6h, 1d, 3d, 1w
+		clock:               SystemClock,
 	}
 }
 
@@ -98,7 +102,109 @@ func NewEbbinghausManagerWithConfig(
 		longTermThreshold:   longTermThreshold,
 		initialRetention:    initialRetention,
 		reviewIntervals:     []float64{1, 6, 24, 72, 168},
+		clock:               SystemClock,
+	}
+}
+
+// RetentionPolicy overrides the retention settings of an EbbinghausManager,
+// e.g. for the memories of an agent that should forget quickly. Zero fields
+// keep the settings of the manager.
+type RetentionPolicy struct {
+	// DecayRate is the rate at which memories decay over time.
+	DecayRate float64
+
+	// ReinforcementFactor determines how much memories are strengthened on access.
+	ReinforcementFactor float64
+
+	// WorkingThreshold is the threshold for working memory classification.
+	WorkingThreshold float64
+
+	// ShortTermThreshold is the threshold for short-term memory classification.
+	ShortTermThreshold float64
+
+	// LongTermThreshold is the threshold for long-term memory classification.
+	LongTermThreshold float64
+}
+
+// merge returns p with its zero fields taken from base. Either may be nil.
+func (p *RetentionPolicy) merge(base *RetentionPolicy) *RetentionPolicy {
+	if p == nil {
+		return base
+	}
+	if base == nil {
+		return p
+	}
+	merged := *p
+	for _, f := range []struct {
+		value *float64
+		base  float64
+	}{
+		{&merged.DecayRate, base.DecayRate},
+		{&merged.ReinforcementFactor, base.ReinforcementFactor},
+		{&merged.WorkingThreshold, base.WorkingThreshold},
+		{&merged.ShortTermThreshold, base.ShortTermThreshold},
+		{&merged.LongTermThreshold, base.LongTermThreshold},
+	} {
+		if *f.value == 0 {
+			*f.value = f.base
+		}
+	}
+	return &merged
+}
+
+// WithPolicy returns a copy of the manager with the non-zero settings of
+// policy, or the manager itself if policy is nil.
+//
+// Example:
+//
+//	fast := manager.WithPolicy(&RetentionPolicy{DecayRate: 1.5})
+//	retention := fast.CalculateRetention(createdAt, nil)
+func (m *EbbinghausManager) WithPolicy(policy *RetentionPolicy) *EbbinghausManager {
+	if policy == nil {
+		return m
+	}
+	overridden := *m
+	if policy.DecayRate != 0 {
+		overridden.decayRate = policy.DecayRate
+	}
+	if policy.ReinforcementFactor != 0 {
+		overridden.reinforcementFactor = policy.ReinforcementFactor
+	}
+	if policy.WorkingThreshold != 0 {
+		overridden.workingThreshold = policy.WorkingThreshold
 	}
+	if policy.ShortTermThreshold != 0 {
+		overridden.shortTermThreshold = policy.ShortTermThreshold
+	}
+	if policy.LongTermThreshold != 0 {
+		overridden.longTermThreshold = policy.LongTermThreshold
+	}
+	return &overridden
+}
+
+// WithClock returns a copy of the manager that reads the current time from
+// clock, or from SystemClock if clock is nil.
+//
+// Example:
+//
+//	clock := NewManualClock(time.Now())
+//	manager := NewEbbinghausManager(0.1, 0.3).WithClock(clock)
+//	clock.Advance(30 * 24 * time.Hour)
+//	archive := manager.ShouldArchive(memory)
+func (m *EbbinghausManager) WithClock(clock Clock) *EbbinghausManager {
+	withClock := *m
+	withClock.clock = clockOrSystem(clock)
+	return &withClock
+}
+
+// now returns the current time of the manager's clock.
+func (m *EbbinghausManager) now() time.Time {
+	return clockOrSystem(m.clock).Now()
+}
+
+// DecayRate returns the decay rate of the manager.
+func (m *EbbinghausManager) DecayRate() float64 {
+	return m.decayRate
 }
 
 // CalculateRetention calculates the current retention strength of a memory
@@ -116,7 +222,7 @@ func NewEbbinghausManagerWithConfig(
 //   - 1.0 = perfect retention (just created/accessed)
 //   - 0.0 = completely forgotten
 func (m *EbbinghausManager) CalculateRetention(createdAt time.Time, lastAccessedAt *time.Time) float64 {
-	now := time.Now()
+	now := m.now()
 	var timeElapsed time.Duration
 
 	if lastAccessedAt != nil {
@@ -166,6 +272,24 @@ func (m *EbbinghausManager) Reinforce(currentStrength float64) float64 {
 	return newStrength
 }
 
+// Weaken weakens a memory, e.g. after negative feedback.
+//
+// The weakening formula mirrors Reinforce:
+//
+//	new_strength = current_strength * (1 - reinforcement_factor)
+//
+// Parameters:
+//   - currentStrength: Current retention strength (0.0-1.0)
+//
+// Returns the new retention strength after weakening.
+func (m *EbbinghausManager) Weaken(currentStrength float64) float64 {
+	newStrength := currentStrength * (1.0 - m.reinforcementFactor)
+	if newStrength < 0.0 {
+		return 0.0
+	}
+	return newStrength
+}
+
 // ClassifyMemoryType classifies a memory based on its retention strength.
 //
 // Memory types:
@@ -205,7 +329,7 @@ func (m *EbbinghausManager) ShouldPromote(memory map[string]interface{}) bool {
 
 	// Check recency
 	if createdAt, ok := memory["created_at"].(time.Time); ok {
-		timeElapsed := time.Since(createdAt)
+		timeElapsed := m.now().Sub(createdAt)
 		if timeElapsed > 24*time.Hour {
 			return true
 		}
@@ -242,7 +366,7 @@ func (m *EbbinghausManager) ShouldForget(memory map[string]interface{}) bool {
 	// Check if never accessed and old enough
 	if accessCount, ok := memory["access_count"].(int); ok && accessCount == 0 {
 		if createdAt, ok := memory["created_at"].(time.Time); ok {
-			timeElapsed := time.Since(createdAt)
+			timeElapsed := m.now().Sub(createdAt)
 			if timeElapsed > 7*24*time.Hour { // 7 days
 				return true
 			}
@@ -265,7 +389,7 @@ func (m *EbbinghausManager) ShouldForget(memory map[string]interface{}) bool {
 func (m *EbbinghausManager) ShouldArchive(memory map[string]interface{}) bool {
 	// Check age
 	if createdAt, ok := memory["created_at"].(time.Time); ok {
-		timeElapsed := time.Since(createdAt)
+		timeElapsed := m.now().Sub(createdAt)
 		if timeElapsed > 30*24*time.Hour { // 30 days
 			return true
 		}
@@ -334,7 +458,7 @@ func (m *EbbinghausManager) CalculateNextReview(retentionStrength float64) time.
 	// Review interval (hours) = 24 * (1 + strength * 10)
 	// Higher strength = longer interval
 	hoursUntilReview := 24.0 * (1.0 + retentionStrength*10.0)
-	return time.Now().Add(time.Duration(hoursUntilReview) * time.Hour)
+	return m.now().Add(time.Duration(hoursUntilReview) * time.Hour)
 }
 
 // GetDecayRateForType returns the decay rate for a specific memory type.
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -30,6 +31,25 @@ type FactExtractor struct {
 	customPrompt string
 }
 
+// Fact is a fact extracted from messages together with the model's confidence in it.
+type Fact struct {
+	// Text is the fact content.
+	Text string
+
+	// Confidence is the extraction confidence (0.0-1.0).
+	// Facts returned without a confidence score default to 1.0.
+	Confidence float64
+
+	// Messages are the numbers of the messages the fact was extracted from,
+	// starting at 1 (nil if the messages are a single string or the LLM did
+	// not say).
+	Messages []int
+
+	// Entities are the named entities the fact mentions (people, places,
+	// organizations, products...), as named in the fact.
+	Entities []string
+}
+
 // NewFactExtractor creates a new fact extractor.
 //
 // Parameters:
@@ -76,6 +96,31 @@ func NewFactExtractorWithPrompt(llm llm.Provider, customPrompt string) *FactExtr
 //
 // Returns a list of extracted fact strings, or empty list if extraction fails.
 func (e *FactExtractor) ExtractFacts(ctx context.Context, messages interface{}) ([]string, error) {
+	extracted, err := e.ExtractFactsWithConfidence(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	facts := make([]string, len(extracted))
+	for i, fact := range extracted {
+		facts[i] = fact.Text
+	}
+	return facts, nil
+}
+
+// ExtractFactsWithConfidence extracts facts from messages along with a confidence score per fact.
+//
+// The LLM is asked to score each fact from 0.0 to 1.0. Facts returned as plain
+// strings (e.g., by a custom prompt) are assigned a confidence of 1.0. A list
+// of messages is numbered in the prompt, and the LLM is asked for the numbers
+// of the messages each fact comes from.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - messages: Messages to extract facts from (can be string, []map[string]interface{}, or single map)
+//
+// Returns a list of extracted facts, or an error if extraction fails.
+func (e *FactExtractor) ExtractFactsWithConfidence(ctx context.Context, messages interface{}) ([]Fact, error) {
 	// Parse messages into conversation format
 	conversation := e.parseMessages(messages)
 
@@ -109,12 +154,13 @@ func (e *FactExtractor) parseMessages(messages interface{}) string {
 	case string:
 		return v
 	case []map[string]interface{}:
+		// Numbered by position so that facts can cite their messages
 		var parts []string
-		for _, msg := range v {
+		for i, msg := range v {
 			role, _ := msg["role"].(string)
 			content, _ := msg["content"].(string)
 			if role != "" && content != "" && role != "system" {
-				parts = append(parts, fmt.Sprintf("%s: %s", role, content))
+				parts = append(parts, fmt.Sprintf("[%d] %s: %s", i+1, role, content))
 			}
 		}
 		return strings.Join(parts, "\n")
@@ -147,29 +193,38 @@ CRITICAL Rules:
 2. COMPLETE: Extract self-contained facts with who/what/when/where when available.
 3. SEPARATE: Extract distinct facts separately, especially when they have different time periods.
 4. INTENTIONS & NEEDS: ALWAYS extract user intentions, needs, and requests even without time information. Examples: "Want to book a doctor appointment", "Need to call someone", "Plan to visit a place".
+5. CONFIDENCE: Score each fact from 0.0 to 1.0. Use high scores for facts stated explicitly, lower scores for facts that are inferred, ambiguous, or uncertain. Never invent facts that are not supported by the conversation.
+6. SOURCE: When messages are numbered like "[2] user: ...", list the numbers of the messages each fact comes from in "messages".
+7. ENTITIES: List the named entities each fact mentions (people, pets, places, organizations, products) in "entities", as named in the fact. Do not list the user.
 
 Examples:
 Input: Hi.
 Output: {"facts" : []}
 
 Input: Yesterday, I met John at 3pm. We discussed the project.
-Output: {"facts" : ["Met John at 3pm yesterday", "Discussed project with John yesterday"]}
+Output: {"facts" : [{"fact": "Met John at 3pm yesterday", "confidence": 0.95, "entities": ["John"]}, {"fact": "Discussed project with John yesterday", "confidence": 0.9, "entities": ["John"]}]}
 
 Input: Last May, I went to India. Visited Mumbai and Goa.
-Output: {"facts" : ["Went to India in May", "Visited Mumbai in May", "Visited Goa in May"]}
+Output: {"facts" : [{"fact": "Went to India in May", "confidence": 0.95, "entities": ["India"]}, {"fact": "Visited Mumbai in May", "confidence": 0.9, "entities": ["Mumbai"]}, {"fact": "Visited Goa in May", "confidence": 0.9, "entities": ["Goa"]}]}
 
 Input: I met Sarah last year and became friends. We went to movies last month.
-Output: {"facts" : ["Met Sarah last year and became friends", "Went to movies with Sarah last month"]}
+Output: {"facts" : [{"fact": "Met Sarah last year and became friends", "confidence": 0.95, "entities": ["Sarah"]}, {"fact": "Went to movies with Sarah last month", "confidence": 0.9, "entities": ["Sarah"]}]}
 
 Input: I'm John, a software engineer.
-Output: {"facts" : ["Name is John", "John is a software engineer"]}
+Output: {"facts" : [{"fact": "Name is John", "confidence": 1.0}, {"fact": "John is a software engineer", "confidence": 1.0}]}
 
 Input: I want to book an appointment with a cardiologist.
-Output: {"facts" : ["Want to book an appointment with a cardiologist"]}
+Output: {"facts" : [{"fact": "Want to book an appointment with a cardiologist", "confidence": 0.95}]}
+
+Input:
+[1] user: I'm Anna.
+[2] assistant: Nice to meet you, Anna!
+[3] user: I moved to Lisbon last month.
+Output: {"facts" : [{"fact": "Name is Anna", "confidence": 1.0, "messages": [1]}, {"fact": "Moved to Lisbon last month", "confidence": 0.95, "messages": [3], "entities": ["Lisbon"]}]}
 
 Rules:
 - Today: %s
-- Return JSON: {"facts": ["fact1", "fact2"]}
+- Return JSON: {"facts": [{"fact": "fact1", "confidence": 0.9}, {"fact": "fact2", "confidence": 0.6}]}
 - Extract from user/assistant messages only
 - Extract intentions, needs, and requests even without time information
 - If no relevant facts, return empty list
@@ -179,7 +234,10 @@ Extract facts from the conversation below:`, today)
 }
 
 // parseFactsResponse parses LLM response to extract facts.
-func (e *FactExtractor) parseFactsResponse(response string) ([]string, error) {
+//
+// Each entry in the "facts" array may be a plain string or an object with
+// "fact" and "confidence" fields.
+func (e *FactExtractor) parseFactsResponse(response string) ([]Fact, error) {
 	// Remove code blocks if present
 	response = e.removeCodeBlocks(response)
 
@@ -192,7 +250,7 @@ func (e *FactExtractor) parseFactsResponse(response string) ([]string, error) {
 	// Extract facts array
 	factsInterface, ok := result["facts"]
 	if !ok {
-		return []string{}, nil
+		return []Fact{}, nil
 	}
 
 	factsArray, ok := factsInterface.([]interface{})
@@ -200,17 +258,62 @@ func (e *FactExtractor) parseFactsResponse(response string) ([]string, error) {
 		return nil, fmt.Errorf("facts is not an array")
 	}
 
-	// Convert to string slice
-	facts := make([]string, 0, len(factsArray))
-	for _, fact := range factsArray {
-		if factStr, ok := fact.(string); ok && factStr != "" {
-			facts = append(facts, factStr)
+	// Convert to facts
+	facts := make([]Fact, 0, len(factsArray))
+	for _, item := range factsArray {
+		switch v := item.(type) {
+		case string:
+			if v != "" {
+				facts = append(facts, Fact{Text: v, Confidence: 1.0})
+			}
+		case map[string]interface{}:
+			text, _ := v["fact"].(string)
+			if text == "" {
+				text, _ = v["text"].(string)
+			}
+			if text == "" {
+				continue
+			}
+			confidence := 1.0
+			if c, ok := v["confidence"].(float64); ok {
+				confidence = math.Max(0, math.Min(1, c))
+			}
+			var messages []int
+			if numbers, ok := v["messages"].([]interface{}); ok {
+				for _, number := range numbers {
+					if n, ok := number.(float64); ok && n >= 1 && n == math.Trunc(n) {
+						messages = append(messages, int(n))
+					}
+				}
+			}
+			facts = append(facts, Fact{Text: text, Confidence: confidence, Messages: messages, Entities: parseEntities(v["entities"])})
 		}
 	}
 
 	return facts, nil
 }
 
+// parseEntities returns the entity names of an "entities" array, trimmed and
+// without case-insensitive duplicates.
+func parseEntities(value interface{}) []string {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	var entities []string
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		name, _ := item.(string)
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		entities = append(entities, name)
+	}
+	return entities
+}
+
 // removeCodeBlocks removes code blocks (```json ... ```) from response.
 func (e *FactExtractor) removeCodeBlocks(response string) string {
 	// Remove ```json and ``` markers
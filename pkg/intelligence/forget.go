@@ -0,0 +1,152 @@
+package intelligence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/oceanbase/powermem-go/pkg/llm"
+)
+
+// Forget events.
+const (
+	// ForgetDelete deletes a memory that is entirely about what the user
+	// asked to forget.
+	ForgetDelete = "DELETE"
+
+	// ForgetUpdate rewrites a memory without the part the user asked to
+	// forget.
+	ForgetUpdate = "UPDATE"
+)
+
+// ForgetAction is a decision of the LLM on a memory matching a request to
+// forget something.
+type ForgetAction struct {
+	// Event is the operation: DELETE or UPDATE.
+	Event string `json:"event"`
+
+	// ID is the temporary ID of the memory.
+	ID string `json:"id"`
+
+	// Text is the content of the memory without the forgotten part (empty
+	// for DELETE).
+	Text string `json:"text,omitempty"`
+
+	// Reason explains the decision.
+	Reason string `json:"reason,omitempty"`
+}
+
+// MemoryForgetter locates the memories a user asks to forget in natural
+// language ("forget that I live in Berlin") and decides whether to delete
+// them or remove the forgotten part.
+//
+// Example usage:
+//
+//	forgetter := NewMemoryForgetter(llmProvider)
+//	actions, err := forgetter.Locate(ctx, "Forget where I live", []ExistingMemory{
+//	    {ID: "0", Text: "Lives in Berlin"},
+//	    {ID: "1", Text: "Works as a nurse in Berlin"},
+//	})
+type MemoryForgetter struct {
+	// llm is the LLM provider for locating memories.
+	llm llm.Provider
+}
+
+// NewMemoryForgetter creates a new memory forgetter.
+func NewMemoryForgetter(llm llm.Provider) *MemoryForgetter {
+	return &MemoryForgetter{llm: llm}
+}
+
+// Locate decides which memories to delete or update to honor instruction.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - instruction: What the user asked to forget
+//   - memories: Candidate memories, with temporary IDs
+//
+// Returns the actions of the LLM. Actions with an unknown event, without an
+// ID, or updates without text are dropped; the caller must check that the
+// IDs exist.
+func (m *MemoryForgetter) Locate(ctx context.Context, instruction string, memories []ExistingMemory) ([]ForgetAction, error) {
+	if len(memories) == 0 {
+		return []ForgetAction{}, nil
+	}
+
+	memoriesJSON, _ := json.Marshal(memories)
+	messages := []llm.Message{
+		{Role: "system", Content: forgetPrompt},
+		{Role: "user", Content: fmt.Sprintf("Request: %s\nMemories:\n%s", instruction, memoriesJSON)},
+	}
+
+	response, err := m.llm.GenerateWithMessages(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate memories to forget: %w", err)
+	}
+
+	actions, err := parseForgetResponse(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse forget response: %w", err)
+	}
+	return actions, nil
+}
+
+// parseForgetResponse parses the actions of the LLM response.
+func parseForgetResponse(response string) ([]ForgetAction, error) {
+	var result struct {
+		Actions []struct {
+			Event  string      `json:"event"`
+			ID     interface{} `json:"id"`
+			Text   string      `json:"text"`
+			Reason string      `json:"reason"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal([]byte(removeCodeBlocks(response)), &result); err != nil {
+		return nil, fmt.Errorf("invalid JSON response: %w", err)
+	}
+
+	actions := make([]ForgetAction, 0, len(result.Actions))
+	for _, item := range result.Actions {
+		action := ForgetAction{
+			Event:  strings.ToUpper(strings.TrimSpace(item.Event)),
+			Text:   strings.TrimSpace(item.Text),
+			Reason: item.Reason,
+		}
+		switch id := item.ID.(type) {
+		case string:
+			action.ID = id
+		case float64:
+			action.ID = fmt.Sprintf("%.0f", id)
+		}
+		switch {
+		case action.ID == "":
+			continue
+		case action.Event == ForgetDelete:
+			action.Text = ""
+		case action.Event == ForgetUpdate && action.Text != "":
+		default:
+			continue
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// forgetPrompt is the system prompt of MemoryForgetter.
+const forgetPrompt = `You help an assistant forget what a user asked it to forget.
+
+You are given the user's request and the memories the assistant keeps about the user that may be concerned. Decide for each concerned memory:
+- DELETE: the memory is entirely about what the user wants forgotten.
+- UPDATE: the memory also holds other information. "text" is the memory rewritten without the forgotten information, keeping everything else and its language.
+
+Rules:
+1. Only use the ids of the memories given. Leave out memories the request is not about; when in doubt, leave the memory out.
+2. Forget everything the request covers, including details that reveal it indirectly.
+3. Give a short "reason" for each action.
+
+Example:
+Request: Forget where I live
+Memories: [{"id":"0","text":"Lives in Berlin"},{"id":"1","text":"Works as a nurse in Berlin"},{"id":"2","text":"Likes coffee"}]
+Output: {"actions": [{"event": "DELETE", "id": "0", "reason": "The home city"}, {"event": "UPDATE", "id": "1", "text": "Works as a nurse", "reason": "Reveals the city"}]}
+
+Return JSON only: {"actions": [{"event": "DELETE|UPDATE", "id": "0", "text": "...", "reason": "..."}]}`
@@ -0,0 +1,132 @@
+package intelligence
+
+import (
+	"strings"
+	"unicode"
+)
+
+// latinStopwords are frequent short words of the Latin-script languages
+// told apart by DetectLanguage.
+var latinStopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "to", "of", "in", "my", "i", "you", "with", "for", "on", "likes", "prefers"},
+	"es": {"el", "la", "los", "las", "y", "es", "de", "que", "en", "mi", "con", "para", "por", "una"},
+	"fr": {"le", "la", "les", "et", "est", "de", "des", "que", "en", "mon", "ma", "avec", "pour", "une", "je"},
+	"de": {"der", "die", "das", "und", "ist", "zu", "den", "mit", "ich", "mein", "meine", "für", "nicht", "ein"},
+	"pt": {"o", "os", "as", "e", "é", "de", "que", "em", "meu", "minha", "com", "para", "uma", "não"},
+	"it": {"il", "lo", "gli", "e", "è", "di", "che", "in", "mio", "mia", "con", "per", "una", "non"},
+}
+
+// latinLanguages is the order in which ties between Latin-script languages
+// are broken.
+var latinLanguages = []string{"en", "es", "fr", "de", "pt", "it"}
+
+// languageNames are the English names of the languages returned by
+// DetectLanguage.
+var languageNames = map[string]string{
+	"zh": "Chinese",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"ru": "Russian",
+	"ar": "Arabic",
+	"hi": "Hindi",
+	"th": "Thai",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"de": "German",
+	"pt": "Portuguese",
+	"it": "Italian",
+}
+
+// DetectLanguage returns the ISO 639-1 code of the main language of text,
+// or an empty string if text has no letters.
+//
+// The language is told by the script of most letters: Chinese (zh),
+// Japanese (ja, kana with or without kanji), Korean (ko), Russian (ru, any
+// Cyrillic), Arabic (ar), Hindi (hi, Devanagari) and Thai (th). Latin-script
+// text is English (en) unless it has more stopwords of Spanish (es), French
+// (fr), German (de), Portuguese (pt) or Italian (it). The detection needs
+// no model or network access, which suits short memories; it is not meant
+// for other languages.
+//
+// Example:
+//
+//	intelligence.DetectLanguage("我喜欢靠窗的座位")     // "zh"
+//	intelligence.DetectLanguage("Prefers window seats") // "en"
+func DetectLanguage(text string) string {
+	counts := make(map[string]int)
+	kana := 0
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			counts["zh"]++
+		case unicode.Is(unicode.Hangul, r):
+			counts["ko"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			counts["ru"]++
+		case unicode.Is(unicode.Arabic, r):
+			counts["ar"]++
+		case unicode.Is(unicode.Devanagari, r):
+			counts["hi"]++
+		case unicode.Is(unicode.Thai, r):
+			counts["th"]++
+		case unicode.Is(unicode.Latin, r):
+			counts["latin"]++
+		}
+	}
+	// Japanese mixes kanji with kana, which Chinese does not use
+	if kana > 0 {
+		counts["ja"] = kana + counts["zh"]
+		delete(counts, "zh")
+	}
+
+	best, bestCount := "", 0
+	for _, script := range []string{"zh", "ja", "ko", "ru", "ar", "hi", "th", "latin"} {
+		if counts[script] > bestCount {
+			best, bestCount = script, counts[script]
+		}
+	}
+	if best != "latin" {
+		return best
+	}
+	return detectLatinLanguage(text)
+}
+
+// detectLatinLanguage returns the Latin-script language of text with the
+// most stopwords, English if none has any.
+func detectLatinLanguage(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	hits := make(map[string]int)
+	for _, language := range latinLanguages {
+		stopwords := make(map[string]bool, len(latinStopwords[language]))
+		for _, word := range latinStopwords[language] {
+			stopwords[word] = true
+		}
+		for _, word := range words {
+			if stopwords[word] {
+				hits[language]++
+			}
+		}
+	}
+
+	best := "en"
+	for _, language := range latinLanguages {
+		if hits[language] > hits[best] {
+			best = language
+		}
+	}
+	return best
+}
+
+// LanguageName returns the English name of a language code returned by
+// DetectLanguage, or the code itself for other languages.
+func LanguageName(code string) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
@@ -67,6 +67,23 @@ type Config struct {
 	// FallbackToSimpleAdd indicates whether to fallback to simple add mode
 	// when intelligent processing fails.
 	FallbackToSimpleAdd bool
+
+	// Ranking weights similarity, recency, importance and retention when
+	// ranking search results. If nil, results are ranked by keyword relevance
+	// multiplied by Ebbinghaus retention.
+	Ranking *RankingConfig
+
+	// AgentPolicies overrides the retention settings for the memories of
+	// these agents. They take precedence over MemoryTypePolicies.
+	AgentPolicies map[string]*RetentionPolicy
+
+	// MemoryTypePolicies overrides the retention settings for the memories
+	// of these types (metadata["memory_type"]).
+	MemoryTypePolicies map[string]*RetentionPolicy
+
+	// Clock is the time source of retention, ranking and review schedules.
+	// If nil, SystemClock is used.
+	Clock Clock
 }
 
 // DefaultConfig returns a default configuration for intelligent memory.
@@ -104,7 +121,7 @@ func NewIntelligentMemoryManager(llm llm.Provider, config *Config) *IntelligentM
 		config.ShortTermThreshold,
 		config.LongTermThreshold,
 		config.InitialRetention,
-	)
+	).WithClock(config.Clock)
 
 	return &IntelligentMemoryManager{
 		importanceEvaluator: importanceEvaluator,
@@ -164,7 +181,8 @@ func (m *IntelligentMemoryManager) ProcessMetadata(
 	decayRate := m.ebbinghausManager.GetDecayRateForType(memoryType)
 
 	// Generate review schedule
-	reviewSchedule := m.ebbinghausManager.GenerateReviewSchedule(time.Now(), importanceScore)
+	now := m.ebbinghausManager.now()
+	reviewSchedule := m.ebbinghausManager.GenerateReviewSchedule(now, importanceScore)
 
 	// Build intelligence metadata
 	intelligenceData := map[string]interface{}{
@@ -174,7 +192,7 @@ func (m *IntelligentMemoryManager) ProcessMetadata(
 		"current_retention":    initialRetention,
 		"decay_rate":           decayRate,
 		"review_schedule":      reviewSchedule,
-		"last_reviewed":        time.Now(),
+		"last_reviewed":        now,
 		"review_count":         0,
 		"access_count":         0,
 		"reinforcement_factor": m.config.ReinforcementFactor,
@@ -195,8 +213,8 @@ func (m *IntelligentMemoryManager) ProcessMetadata(
 
 	enhancedMetadata["intelligence"] = intelligenceData
 	enhancedMetadata["memory_management"] = memoryManagement
-	enhancedMetadata["created_at"] = time.Now()
-	enhancedMetadata["updated_at"] = time.Now()
+	enhancedMetadata["created_at"] = now
+	enhancedMetadata["updated_at"] = now
 
 	return enhancedMetadata
 }
@@ -214,14 +232,32 @@ func (m *IntelligentMemoryManager) ExtractFacts(ctx context.Context, messages in
 	return m.factExtractor.ExtractFacts(ctx, messages)
 }
 
+// ExtractFactsWithConfidence extracts facts from messages with a confidence score per fact.
+//
+// This is a convenience method that delegates to the FactExtractor.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - messages: Messages to extract facts from
+//
+// Returns a list of extracted facts with their confidence scores.
+func (m *IntelligentMemoryManager) ExtractFactsWithConfidence(ctx context.Context, messages interface{}) ([]Fact, error) {
+	return m.factExtractor.ExtractFactsWithConfidence(ctx, messages)
+}
+
 // ProcessSearchResults processes search results with intelligent ranking.
 //
 // This method:
 //  1. Calculates relevance score for each result
-//  2. Applies Ebbinghaus decay based on age
-//  3. Combines relevance and decay for final score
+//  2. Applies Ebbinghaus decay based on age, scaled by the retention strength
+//  3. Combines relevance and decay for final score, or, if Config.Ranking is
+//     set, the weighted similarity, recency, importance and retention scores
 //  4. Sorts results by final score
 //
+// The component scores are stored on each result ("relevance_score",
+// "decay_factor", "final_score", and with Config.Ranking also
+// "similarity_score", "recency_score" and "importance_score").
+//
 // Parameters:
 //   - ctx: Context for cancellation
 //   - results: Search results (list of memory maps)
@@ -234,6 +270,7 @@ func (m *IntelligentMemoryManager) ProcessSearchResults(
 	query string,
 ) []map[string]interface{} {
 	processed := make([]map[string]interface{}, 0, len(results))
+	now := m.ebbinghausManager.now()
 
 	for _, result := range results {
 		// Calculate relevance (simple keyword matching)
@@ -246,19 +283,29 @@ func (m *IntelligentMemoryManager) ProcessSearchResults(
 			if lastAccess, ok := result["last_accessed_at"].(time.Time); ok {
 				lastAccessedAt = &lastAccess
 			}
-			decayFactor = m.ebbinghausManager.CalculateRetention(createdAt, lastAccessedAt)
+			decayFactor = m.ebbinghausFor(result).CalculateRetention(createdAt, lastAccessedAt)
 		} else {
 			decayFactor = 1.0 // No decay if no creation time
 		}
-
-		// Calculate final score
-		finalScore := relevanceScore * decayFactor
+		// Retention strength is lowered by negative feedback
+		if strength, ok := result["retention_strength"].(float64); ok && strength > 0 && strength < 1 {
+			decayFactor *= strength
+		}
 
 		// Update result
 		processedResult := make(map[string]interface{})
 		for k, v := range result {
 			processedResult[k] = v
 		}
+
+		// Calculate final score
+		var finalScore float64
+		if m.config.Ranking != nil {
+			finalScore = weightedScore(m.config.Ranking, processedResult, decayFactor, now)
+		} else {
+			finalScore = relevanceScore * decayFactor
+		}
+
 		processedResult["relevance_score"] = relevanceScore
 		processedResult["decay_factor"] = decayFactor
 		processedResult["final_score"] = finalScore
@@ -280,6 +327,20 @@ func (m *IntelligentMemoryManager) ProcessSearchResults(
 	return processed
 }
 
+// ebbinghausFor returns the Ebbinghaus manager for a memory map, from its
+// "agent_id" and the "memory_type" of its "metadata".
+func (m *IntelligentMemoryManager) ebbinghausFor(memory map[string]interface{}) *EbbinghausManager {
+	if len(m.config.AgentPolicies) == 0 && len(m.config.MemoryTypePolicies) == 0 {
+		return m.ebbinghausManager
+	}
+	agentID, _ := memory["agent_id"].(string)
+	var memoryType string
+	if metadata, ok := memory["metadata"].(map[string]interface{}); ok {
+		memoryType, _ = metadata["memory_type"].(string)
+	}
+	return m.EbbinghausManagerFor(agentID, memoryType)
+}
+
 // calculateRelevance calculates relevance score for a memory given a query.
 func (m *IntelligentMemoryManager) calculateRelevance(memory map[string]interface{}, query string) float64 {
 	content, ok := memory["content"].(string)
@@ -317,17 +378,17 @@ func (m *IntelligentMemoryManager) calculateRelevance(memory map[string]interfac
 
 // ShouldPromote checks if a memory should be promoted to a higher tier.
 func (m *IntelligentMemoryManager) ShouldPromote(memory map[string]interface{}) bool {
-	return m.ebbinghausManager.ShouldPromote(memory)
+	return m.ebbinghausFor(memory).ShouldPromote(memory)
 }
 
 // ShouldForget checks if a memory should be forgotten (deleted).
 func (m *IntelligentMemoryManager) ShouldForget(memory map[string]interface{}) bool {
-	return m.ebbinghausManager.ShouldForget(memory)
+	return m.ebbinghausFor(memory).ShouldForget(memory)
 }
 
 // ShouldArchive checks if a memory should be archived.
 func (m *IntelligentMemoryManager) ShouldArchive(memory map[string]interface{}) bool {
-	return m.ebbinghausManager.ShouldArchive(memory)
+	return m.ebbinghausFor(memory).ShouldArchive(memory)
 }
 
 // GetImportanceEvaluator returns the importance evaluator.
@@ -335,6 +396,14 @@ func (m *IntelligentMemoryManager) GetImportanceEvaluator() *ImportanceEvaluator
 	return m.importanceEvaluator
 }
 
+// EbbinghausManagerFor returns the Ebbinghaus manager for the memories of
+// agentID with memoryType: the manager with the policy of the memory type
+// and, over it, the policy of the agent (see Config.AgentPolicies).
+func (m *IntelligentMemoryManager) EbbinghausManagerFor(agentID, memoryType string) *EbbinghausManager {
+	policy := m.config.AgentPolicies[agentID].merge(m.config.MemoryTypePolicies[memoryType])
+	return m.ebbinghausManager.WithPolicy(policy)
+}
+
 // GetEbbinghausManager returns the Ebbinghaus manager.
 func (m *IntelligentMemoryManager) GetEbbinghausManager() *EbbinghausManager {
 	return m.ebbinghausManager
@@ -0,0 +1,99 @@
+package intelligence
+
+import (
+	"math"
+	"time"
+)
+
+// RankingConfig weights the components combined into the final search score.
+//
+// The final score is the weighted average of:
+//   - Similarity: vector similarity score returned by the store
+//   - Recency: exp decay on the time since the last update, halving every RecencyHalfLife
+//   - Importance: importance score evaluated when the memory was added
+//   - Retention: Ebbinghaus retention based on age and last access
+//
+// Example:
+//
+//	ranking := &RankingConfig{
+//	    SimilarityWeight: 0.7,
+//	    RecencyWeight:    0.3,
+//	    RecencyHalfLife:  30 * 24 * time.Hour,
+//	}
+type RankingConfig struct {
+	// SimilarityWeight is the weight of the vector similarity score.
+	SimilarityWeight float64
+
+	// RecencyWeight is the weight of the recency score.
+	RecencyWeight float64
+
+	// ImportanceWeight is the weight of the importance score.
+	ImportanceWeight float64
+
+	// RetentionWeight is the weight of the Ebbinghaus retention.
+	RetentionWeight float64
+
+	// RecencyHalfLife is the age at which the recency score drops to 0.5.
+	// Default: 7 days
+	RecencyHalfLife time.Duration
+}
+
+// DefaultRankingConfig returns the default ranking weights.
+func DefaultRankingConfig() *RankingConfig {
+	return &RankingConfig{
+		SimilarityWeight: 0.6,
+		RecencyWeight:    0.15,
+		ImportanceWeight: 0.15,
+		RetentionWeight:  0.1,
+		RecencyHalfLife:  7 * 24 * time.Hour,
+	}
+}
+
+// defaultImportance is used for memories without an importance score.
+const defaultImportance = 0.5
+
+// weightedScore combines the score components of result according to ranking.
+//
+// The component scores are stored on result under "similarity_score",
+// "recency_score" and "importance_score"; retention is expected under
+// "decay_factor". Returns the final score.
+func weightedScore(ranking *RankingConfig, result map[string]interface{}, retention float64, now time.Time) float64 {
+	similarity, _ := result["score"].(float64)
+
+	recency := 1.0
+	halfLife := ranking.RecencyHalfLife
+	if halfLife <= 0 {
+		halfLife = 7 * 24 * time.Hour
+	}
+	updatedAt, ok := result["updated_at"].(time.Time)
+	if !ok {
+		updatedAt, ok = result["created_at"].(time.Time)
+	}
+	if ok {
+		if age := now.Sub(updatedAt); age > 0 {
+			recency = math.Exp(-math.Ln2 * float64(age) / float64(halfLife))
+		}
+	}
+
+	importance := defaultImportance
+	if metadata, ok := result["metadata"].(map[string]interface{}); ok {
+		if intelligenceData, ok := metadata["intelligence"].(map[string]interface{}); ok {
+			if score, ok := intelligenceData["importance_score"].(float64); ok {
+				importance = score
+			}
+		}
+	}
+
+	result["similarity_score"] = similarity
+	result["recency_score"] = recency
+	result["importance_score"] = importance
+
+	totalWeight := ranking.SimilarityWeight + ranking.RecencyWeight + ranking.ImportanceWeight + ranking.RetentionWeight
+	if totalWeight <= 0 {
+		return similarity
+	}
+	return (ranking.SimilarityWeight*similarity +
+		ranking.RecencyWeight*recency +
+		ranking.ImportanceWeight*importance +
+		ranking.RetentionWeight*retention) / totalWeight
+}
@@ -46,16 +46,16 @@ type Message struct {
 // GenerateOptions contains options for text generation.
 type GenerateOptions struct {
 	// Temperature controls randomness (0.0-2.0). Higher = more random.
-	Temperature float64
+	Temperature float64 `json:"temperature"`
 
 	// MaxTokens limits the maximum number of tokens in the response.
-	MaxTokens int
+	MaxTokens int `json:"max_tokens"`
 
 	// TopP controls nucleus sampling (0.0-1.0). Higher = more diverse.
-	TopP float64
+	TopP float64 `json:"top_p"`
 
 	// Stop contains stop sequences that will end generation.
-	Stop []string
+	Stop []string `json:"stop,omitempty"`
 }
 
 // GenerateOption is a function type for configuring generation options.
@@ -0,0 +1,107 @@
+package mock
+
+import (
+	"context"
+	"sync"
+
+	"github.com/oceanbase/powermem-go/pkg/llm"
+)
+
+// Client is a deterministic LLM for tests.
+// It implements the llm.Provider interface without calling any service.
+//
+// Responses are produced, in order of precedence, by the Responder function,
+// the scripted Responses (returned in order, repeating the last one), or by
+// echoing the content of the last message. Every request is recorded and can
+// be inspected with Requests.
+type Client struct {
+	responder func(messages []llm.Message) (string, error)
+
+	// mu guards responses and requests.
+	mu        sync.Mutex
+	responses []string
+	requests  [][]llm.Message
+}
+
+// Config is the configuration for the mock LLM.
+// Responses: responses returned in order; the last one is repeated once the others are used
+// Responder: function computing the response of each request (optional, takes precedence over Responses)
+type Config struct {
+	Responses []string
+	Responder func(messages []llm.Message) (string, error)
+}
+
+// NewClient creates a new mock LLM client.
+//
+// Args:
+//   - cfg: mock LLM configuration (may be nil to echo the last message)
+//
+// Returns:
+//   - *Client: mock LLM client instance
+//   - error: always nil
+func NewClient(cfg *Config) (*Client, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	return &Client{
+		responder: cfg.Responder,
+		responses: append([]string(nil), cfg.Responses...),
+	}, nil
+}
+
+// Generate returns the response to a single user message.
+//
+// Args:
+//   - ctx: Context for controlling the request lifecycle
+//   - prompt: User input prompt
+//   - opts: Ignored
+//
+// Returns:
+//   - string: the response
+//   - error: the context error if ctx is done, or the Responder's error
+func (c *Client) Generate(ctx context.Context, prompt string, opts ...llm.GenerateOption) (string, error) {
+	return c.GenerateWithMessages(ctx, []llm.Message{{Role: "user", Content: prompt}}, opts...)
+}
+
+// GenerateWithMessages returns the response to a conversation. Errors are
+// reported as for Generate.
+func (c *Client) GenerateWithMessages(ctx context.Context, messages []llm.Message, opts ...llm.GenerateOption) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.requests = append(c.requests, append([]llm.Message(nil), messages...))
+	var response string
+	scripted := c.responder == nil && len(c.responses) > 0
+	if scripted {
+		response = c.responses[0]
+		if len(c.responses) > 1 {
+			c.responses = c.responses[1:]
+		}
+	}
+	c.mu.Unlock()
+
+	switch {
+	case c.responder != nil:
+		return c.responder(messages)
+	case scripted:
+		return response, nil
+	case len(messages) > 0:
+		return messages[len(messages)-1].Content, nil
+	default:
+		return "", nil
+	}
+}
+
+// Requests returns the messages of every request made so far, in order.
+func (c *Client) Requests() [][]llm.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]llm.Message(nil), c.requests...)
+}
+
+// Close does nothing.
+func (c *Client) Close() error {
+	return nil
+}
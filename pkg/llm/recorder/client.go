@@ -0,0 +1,387 @@
+// Package recorder provides an llm.Provider middleware that records LLM
+// interactions to a golden file and replays them.
+//
+// Record once against a real provider, commit the golden file, and replay it
+// in CI: tests of logic driven by LLM responses (such as IntelligentAdd's
+// add/update/delete decisions) then run reproducibly and without API calls.
+//
+// Requests are matched by their content: the method, prompt or messages, and
+// generation options, after normalization (by default, dates and timestamps
+// are masked so that prompts mentioning today's date still match). Matching
+// requests are answered with their recorded
+// responses in order, repeating the last one. Recorders opened on the same
+// golden file in one process share it, so several providers (for example the
+// core and user_memory LLMs) can record to one file.
+package recorder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"github.com/oceanbase/powermem-go/pkg/llm"
+)
+
+// Mode selects whether requests are recorded or replayed.
+type Mode string
+
+const (
+	// ModeReplay answers every request from the golden file and never calls
+	// the provider. Requests that were not recorded fail with ErrNotRecorded.
+	ModeReplay Mode = "replay"
+
+	// ModeRecord calls the provider for every request and replaces the
+	// golden file with the new interactions.
+	ModeRecord Mode = "record"
+
+	// ModeAuto replays recorded requests and calls the provider for the
+	// others, adding them to the golden file.
+	ModeAuto Mode = "auto"
+)
+
+// ErrNotRecorded is returned (wrapped) in replay mode for a request that is
+// not in the golden file.
+var ErrNotRecorded = errors.New("recorder: request not recorded")
+
+// Client is an LLM client that records or replays the interactions of
+// another provider.
+// It implements the llm.Provider interface.
+type Client struct {
+	provider llm.Provider
+	mode     Mode
+	golden   *golden
+
+	closeOnce sync.Once
+}
+
+// golden is the in-memory state of a golden file, shared by the clients
+// using it.
+type golden struct {
+	path      string
+	normalize func(text string) string
+
+	// refs is the number of open clients, guarded by openMu.
+	refs int
+
+	// mu guards the fields below.
+	mu           sync.Mutex
+	interactions []*Interaction
+	byKey        map[string][]*Interaction
+	replayed     map[string]int
+}
+
+var (
+	// openMu guards open.
+	openMu sync.Mutex
+
+	// open holds the golden files in use, by absolute path.
+	open = make(map[string]*golden)
+)
+
+// Config is the configuration for the recorder.
+// Provider: the provider to record (required in record and auto mode)
+// Path: path of the golden file (required)
+// Mode: ModeReplay, ModeRecord or ModeAuto, defaults to ModeReplay
+// Normalize: rewrites prompt and message texts before matching, defaults to MaskTimestamps
+type Config struct {
+	Provider  llm.Provider
+	Path      string
+	Mode      Mode
+	Normalize func(text string) string
+}
+
+// timestampPattern matches ISO 8601 dates and timestamps.
+var timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}([T ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:?\d{2})?)?`)
+
+// MaskTimestamps replaces ISO 8601 dates and timestamps in text with
+// "<timestamp>". It is the default normalization of requests.
+func MaskTimestamps(text string) string {
+	return timestampPattern.ReplaceAllString(text, "<timestamp>")
+}
+
+// Interaction is a recorded request and its outcome, as stored in the golden
+// file.
+type Interaction struct {
+	// Request is the request sent to the provider.
+	Request Request `json:"request"`
+
+	// Response is the generated text (empty if the request failed).
+	Response string `json:"response"`
+
+	// Error is the error message if the request failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Request is a recorded LLM request.
+type Request struct {
+	// Prompt is the prompt of a Generate call.
+	Prompt string `json:"prompt,omitempty"`
+
+	// Messages are the messages of a GenerateWithMessages call.
+	Messages []llm.Message `json:"messages,omitempty"`
+
+	// Options are the generation options.
+	Options llm.GenerateOptions `json:"options"`
+}
+
+// goldenFile is the format of the golden file.
+type goldenFile struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// NewClient creates a new recorder.
+//
+// In replay and auto mode the golden file is loaded; in replay mode it must
+// exist. In record mode it is created (or replaced) on the first request.
+//
+// Args:
+//   - cfg: recorder configuration
+//
+// Returns:
+//   - *Client: recorder instance
+//   - error: Returns an error if the configuration is invalid or the golden file cannot be read
+func NewClient(cfg *Config) (*Client, error) {
+	if cfg == nil || cfg.Path == "" {
+		return nil, errors.New("recorder: Path is required")
+	}
+
+	mode := cfg.Mode
+	switch mode {
+	case "":
+		mode = ModeReplay
+	case ModeReplay, ModeRecord, ModeAuto:
+	default:
+		return nil, fmt.Errorf("recorder: unknown mode %q (want replay, record or auto)", mode)
+	}
+	if mode != ModeReplay && cfg.Provider == nil {
+		return nil, fmt.Errorf("recorder: a Provider is required in %s mode", mode)
+	}
+
+	normalize := cfg.Normalize
+	if normalize == nil {
+		normalize = MaskTimestamps
+	}
+	g, err := openGolden(cfg.Path, mode, normalize)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{provider: cfg.Provider, mode: mode, golden: g}, nil
+}
+
+// openGolden returns the shared state of the golden file at path, loading it
+// unless it is already open or mode is ModeRecord. The normalization of the
+// first client opening the file is used.
+func openGolden(path string, mode Mode, normalize func(text string) string) (*golden, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: %w", err)
+	}
+
+	openMu.Lock()
+	defer openMu.Unlock()
+	if g, ok := open[absPath]; ok {
+		g.refs++
+		return g, nil
+	}
+
+	g := &golden{
+		path:      absPath,
+		normalize: normalize,
+		refs:      1,
+		byKey:     make(map[string][]*Interaction),
+		replayed:  make(map[string]int),
+	}
+	if mode != ModeRecord {
+		data, err := os.ReadFile(absPath)
+		switch {
+		case err == nil:
+			var file goldenFile
+			if err := json.Unmarshal(data, &file); err != nil {
+				return nil, fmt.Errorf("recorder: parse %s: %w", path, err)
+			}
+			for _, interaction := range file.Interactions {
+				g.add(interaction)
+			}
+		case errors.Is(err, os.ErrNotExist) && mode == ModeAuto:
+		default:
+			return nil, fmt.Errorf("recorder: %w", err)
+		}
+	}
+	open[absPath] = g
+	return g, nil
+}
+
+// Generate answers the prompt from the recording or the provider, depending
+// on the mode.
+//
+// Returns:
+//   - string: the (recorded) response
+//   - error: the (recorded) provider error, an error wrapping ErrNotRecorded
+//     in replay mode, or an error if the golden file cannot be written
+func (c *Client) Generate(ctx context.Context, prompt string, opts ...llm.GenerateOption) (string, error) {
+	request := Request{Prompt: prompt, Options: *llm.ApplyGenerateOptions(opts)}
+	return c.do(ctx, request, func() (string, error) {
+		return c.provider.Generate(ctx, prompt, opts...)
+	})
+}
+
+// GenerateWithMessages answers the conversation from the recording or the
+// provider, depending on the mode. Errors are reported as for Generate.
+func (c *Client) GenerateWithMessages(ctx context.Context, messages []llm.Message, opts ...llm.GenerateOption) (string, error) {
+	request := Request{Messages: messages, Options: *llm.ApplyGenerateOptions(opts)}
+	return c.do(ctx, request, func() (string, error) {
+		return c.provider.GenerateWithMessages(ctx, messages, opts...)
+	})
+}
+
+// Interactions returns the interactions of the golden file recorded or
+// loaded so far, in order.
+func (c *Client) Interactions() []Interaction {
+	g := c.golden
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	interactions := make([]Interaction, 0, len(g.interactions))
+	for _, interaction := range g.interactions {
+		interactions = append(interactions, *interaction)
+	}
+	return interactions
+}
+
+// Close releases the golden file and closes the wrapped provider.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		openMu.Lock()
+		defer openMu.Unlock()
+		if c.golden.refs--; c.golden.refs == 0 {
+			delete(open, c.golden.path)
+		}
+	})
+	if c.provider == nil {
+		return nil
+	}
+	return c.provider.Close()
+}
+
+// do replays request or calls the provider and records the outcome.
+func (c *Client) do(ctx context.Context, request Request, call func() (string, error)) (string, error) {
+	key, err := c.golden.key(request)
+	if err != nil {
+		return "", err
+	}
+
+	if c.mode != ModeRecord {
+		if interaction, ok := c.golden.replay(key); ok {
+			if interaction.Error != "" {
+				return "", errors.New(interaction.Error)
+			}
+			return interaction.Response, nil
+		}
+		if c.mode == ModeReplay {
+			return "", fmt.Errorf("%w in %s (re-record with mode %q)", ErrNotRecorded, c.golden.path, ModeRecord)
+		}
+	}
+
+	response, callErr := call()
+	if callErr != nil && ctx.Err() != nil {
+		// Canceled requests are not part of the provider's behavior
+		return "", callErr
+	}
+
+	interaction := &Interaction{Request: request, Response: response}
+	if callErr != nil {
+		interaction.Error = callErr.Error()
+	}
+	if err := c.golden.record(interaction); err != nil {
+		return "", err
+	}
+	return response, callErr
+}
+
+// replay returns the next recorded interaction for key.
+func (g *golden) replay(key string) (*Interaction, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	recorded := g.byKey[key]
+	if len(recorded) == 0 {
+		return nil, false
+	}
+	index := g.replayed[key]
+	if index >= len(recorded) {
+		index = len(recorded) - 1
+	}
+	g.replayed[key]++
+	return recorded[index], true
+}
+
+// record adds interaction and saves the golden file.
+func (g *golden) record(interaction *Interaction) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.add(interaction)
+	// In auto mode, a later matching request replays this interaction
+	if key, err := g.key(interaction.Request); err == nil {
+		g.replayed[key] = len(g.byKey[key])
+	}
+	return g.save()
+}
+
+// add indexes interaction. g.mu must be held (or g not yet shared).
+func (g *golden) add(interaction *Interaction) {
+	g.interactions = append(g.interactions, interaction)
+	if key, err := g.key(interaction.Request); err == nil {
+		g.byKey[key] = append(g.byKey[key], interaction)
+	}
+}
+
+// save writes the golden file atomically. g.mu must be held.
+func (g *golden) save() error {
+	data, err := json.MarshalIndent(goldenFile{Interactions: g.interactions}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("recorder: %w", err)
+	}
+	data = append(data, '\n')
+
+	if dir := filepath.Dir(g.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("recorder: %w", err)
+		}
+	}
+	tmp := g.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("recorder: %w", err)
+	}
+	if err := os.Rename(tmp, g.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("recorder: %w", err)
+	}
+	return nil
+}
+
+// key identifies a request by the hash of the JSON encoding of its
+// normalized form.
+func (g *golden) key(request Request) (string, error) {
+	request.Prompt = g.normalize(request.Prompt)
+	if request.Messages != nil {
+		messages := make([]llm.Message, len(request.Messages))
+		for i, message := range request.Messages {
+			messages[i] = llm.Message{Role: message.Role, Content: g.normalize(message.Content)}
+		}
+		request.Messages = messages
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("recorder: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
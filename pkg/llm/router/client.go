@@ -0,0 +1,284 @@
+// Package router provides an llm.Provider that spreads requests over several
+// LLM providers and fails over between them.
+//
+// A Client keeps the health of each provider: a provider that fails
+// FailureThreshold times in a row is skipped for Cooldown, then tried again.
+// Requests go to the healthy providers in priority order (the order given)
+// or, with the latency strategy, fastest first, and move on to the next
+// provider when one fails. Providers that are cooling down are only tried
+// when every healthy provider has failed.
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/llm"
+)
+
+// Strategy selects the order in which healthy providers are tried.
+type Strategy string
+
+const (
+	// StrategyPriority tries providers in the configured order.
+	StrategyPriority Strategy = "priority"
+
+	// StrategyLatency tries the provider with the lowest average latency
+	// first. Providers without a successful request yet are tried first so
+	// that their latency gets measured.
+	StrategyLatency Strategy = "latency"
+)
+
+// ErrAllProvidersFailed is returned (wrapped) when every provider failed.
+var ErrAllProvidersFailed = errors.New("router: all providers failed")
+
+// latencyWeight is the weight of a new sample in the average latency.
+const latencyWeight = 0.2
+
+// Client is an LLM client that routes requests across several providers.
+// It implements the llm.Provider interface.
+type Client struct {
+	backends         []*backend
+	strategy         Strategy
+	failureThreshold int
+	cooldown         time.Duration
+	now              func() time.Time
+}
+
+// Backend is a provider the router can send requests to.
+// Name: name used in errors and Status, such as "openai"
+// Provider: the provider to call
+type Backend struct {
+	Name     string
+	Provider llm.Provider
+}
+
+// Config is the configuration for the router.
+// Backends: providers in priority order; the first one is the primary
+// Strategy: order in which healthy providers are tried, defaults to StrategyPriority
+// FailureThreshold: consecutive failures after which a provider is skipped, defaults to 1
+// Cooldown: how long an unhealthy provider is skipped, defaults to 30 seconds
+type Config struct {
+	Backends         []Backend
+	Strategy         Strategy
+	FailureThreshold int
+	Cooldown         time.Duration
+}
+
+// BackendStatus is the routing state of a provider, as returned by Status.
+type BackendStatus struct {
+	// Name is the backend name.
+	Name string `json:"name"`
+
+	// Healthy is false while the provider is cooling down after failures.
+	Healthy bool `json:"healthy"`
+
+	// ConsecutiveFailures is the number of failures since the last success.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// Latency is the average latency of successful requests.
+	Latency time.Duration `json:"latency"`
+
+	// LastError is the error of the last failed request (empty if none).
+	LastError string `json:"last_error,omitempty"`
+}
+
+// backend is a provider with its routing state.
+type backend struct {
+	name     string
+	provider llm.Provider
+
+	// mu guards the fields below.
+	mu                  sync.Mutex
+	consecutiveFailures int
+	unhealthyUntil      time.Time
+	latency             time.Duration
+	lastError           string
+}
+
+// NewClient creates a new LLM router.
+//
+// Args:
+//   - cfg: router configuration with at least one backend
+//
+// Returns:
+//   - *Client: router instance
+//   - error: Returns an error if there are no backends or the strategy is unknown
+func NewClient(cfg *Config) (*Client, error) {
+	if cfg == nil || len(cfg.Backends) == 0 {
+		return nil, errors.New("router: at least one backend is required")
+	}
+
+	strategy := cfg.Strategy
+	switch strategy {
+	case "":
+		strategy = StrategyPriority
+	case StrategyPriority, StrategyLatency:
+	default:
+		return nil, fmt.Errorf("router: unknown strategy %q (want priority or latency)", strategy)
+	}
+
+	failureThreshold := cfg.FailureThreshold
+	if failureThreshold <= 0 {
+		failureThreshold = 1
+	}
+
+	cooldown := cfg.Cooldown
+	if cooldown <= 0 {
+		cooldown = 30 * time.Second
+	}
+
+	backends := make([]*backend, 0, len(cfg.Backends))
+	for i, b := range cfg.Backends {
+		if b.Provider == nil {
+			return nil, fmt.Errorf("router: backend %d has no provider", i)
+		}
+		name := b.Name
+		if name == "" {
+			name = fmt.Sprintf("backend-%d", i)
+		}
+		backends = append(backends, &backend{name: name, provider: b.Provider})
+	}
+
+	return &Client{
+		backends:         backends,
+		strategy:         strategy,
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		now:              time.Now,
+	}, nil
+}
+
+// Generate generates text from the prompt with the first provider that
+// succeeds.
+//
+// Returns:
+//   - string: generated text
+//   - error: the context error if ctx is done, or an error wrapping
+//     ErrAllProvidersFailed with the error of each provider
+func (c *Client) Generate(ctx context.Context, prompt string, opts ...llm.GenerateOption) (string, error) {
+	return c.route(ctx, func(provider llm.Provider) (string, error) {
+		return provider.Generate(ctx, prompt, opts...)
+	})
+}
+
+// GenerateWithMessages generates text from the conversation with the first
+// provider that succeeds. Errors are reported as for Generate.
+func (c *Client) GenerateWithMessages(ctx context.Context, messages []llm.Message, opts ...llm.GenerateOption) (string, error) {
+	return c.route(ctx, func(provider llm.Provider) (string, error) {
+		return provider.GenerateWithMessages(ctx, messages, opts...)
+	})
+}
+
+// Status returns the routing state of each provider in priority order.
+func (c *Client) Status() []BackendStatus {
+	now := c.now()
+	statuses := make([]BackendStatus, 0, len(c.backends))
+	for _, b := range c.backends {
+		b.mu.Lock()
+		statuses = append(statuses, BackendStatus{
+			Name:                b.name,
+			Healthy:             !now.Before(b.unhealthyUntil),
+			ConsecutiveFailures: b.consecutiveFailures,
+			Latency:             b.latency,
+			LastError:           b.lastError,
+		})
+		b.mu.Unlock()
+	}
+	return statuses
+}
+
+// Close closes all providers.
+func (c *Client) Close() error {
+	var errs []string
+	for _, b := range c.backends {
+		if err := b.provider.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", b.name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("router: close: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// route calls call with each provider in order until one succeeds.
+func (c *Client) route(ctx context.Context, call func(provider llm.Provider) (string, error)) (string, error) {
+	var errs []string
+	for _, b := range c.order() {
+		start := c.now()
+		result, err := call(b.provider)
+		if err == nil {
+			c.recordSuccess(b, c.now().Sub(start))
+			return result, nil
+		}
+
+		// A canceled request says nothing about the provider's health
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		c.recordFailure(b, err)
+		errs = append(errs, fmt.Sprintf("%s: %v", b.name, err))
+	}
+	return "", fmt.Errorf("%w: %s", ErrAllProvidersFailed, strings.Join(errs, "; "))
+}
+
+// order returns the backends in the order they should be tried: healthy
+// backends by strategy, then unhealthy backends by priority.
+func (c *Client) order() []*backend {
+	now := c.now()
+	healthy := make([]*backend, 0, len(c.backends))
+	var unhealthy []*backend
+	latencies := make(map[*backend]time.Duration, len(c.backends))
+	for _, b := range c.backends {
+		b.mu.Lock()
+		isHealthy := !now.Before(b.unhealthyUntil)
+		latencies[b] = b.latency
+		b.mu.Unlock()
+
+		if isHealthy {
+			healthy = append(healthy, b)
+		} else {
+			unhealthy = append(unhealthy, b)
+		}
+	}
+
+	if c.strategy == StrategyLatency {
+		sort.SliceStable(healthy, func(i, j int) bool {
+			return latencies[healthy[i]] < latencies[healthy[j]]
+		})
+	}
+	return append(healthy, unhealthy...)
+}
+
+// recordSuccess marks b as healthy and updates its average latency.
+func (c *Client) recordSuccess(b *backend, latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFailures = 0
+	b.unhealthyUntil = time.Time{}
+	if b.latency == 0 {
+		b.latency = latency
+	} else {
+		b.latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(b.latency))
+	}
+}
+
+// recordFailure counts a failure of b and starts its cooldown once the
+// failure threshold is reached.
+func (c *Client) recordFailure(b *backend, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFailures++
+	b.lastError = err.Error()
+	if b.consecutiveFailures >= c.failureThreshold {
+		b.unhealthyUntil = c.now().Add(c.cooldown)
+	}
+}
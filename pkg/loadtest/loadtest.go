@@ -0,0 +1,350 @@
+// Package loadtest drives concurrent mixes of Add and Search operations
+// against a memory client, and reports their throughput and latency
+// percentiles, to size deployments.
+//
+// Load testing a server mode is blocked until powermem has one: it has no
+// HTTP or gRPC server, so there is no server to load. The operations run in
+// process instead, through a core.Client, and exercise the configured
+// vector store, embedder and, with Options.Infer, LLM the way an
+// application does. The latencies include the embedding and the database
+// round trips, but no network hop to a server. To load a shared database
+// from more clients, run the test on several hosts. The binary is
+// cmd/powermem-bench.
+//
+// Example:
+//
+//	report, err := loadtest.Run(ctx, client, loadtest.Options{
+//	    Workers:  32,
+//	    Duration: time.Minute,
+//	    AddRatio: 0.1,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	_ = report.Write(os.Stdout)
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// Operation names used in reports.
+const (
+	OperationAdd    = "add"
+	OperationSearch = "search"
+)
+
+// UserPrefix is the prefix of the IDs of the synthetic users the memories
+// are added for, followed by a number below Options.Users.
+const UserPrefix = "loadtest_user_"
+
+// Options configures a load test.
+//
+// Zero fields use the defaults below.
+type Options struct {
+	// Workers is the number of operations run concurrently. Default: 8
+	Workers int
+
+	// Duration is how long new operations are started. Default: 30s, or
+	// no limit if Requests is set
+	Duration time.Duration
+
+	// Requests, if positive, stops the test after this many operations.
+	Requests int
+
+	// AddRatio is the fraction of operations that are Adds, from 0 to 1;
+	// the others are Searches. Default: 0.2; use a negative value for
+	// Searches only
+	AddRatio float64
+
+	// Users is the number of synthetic users the operations are spread
+	// over. Default: 100
+	Users int
+
+	// Rate, if positive, caps the operations started per second over all
+	// workers. Without it, every worker starts an operation as soon as the
+	// previous one completes.
+	Rate float64
+
+	// SearchLimit is the number of results of Searches. Default: 10
+	SearchLimit int
+
+	// Infer runs Adds with intelligent memory (fact extraction and
+	// deduplication by the LLM) instead of storing the content as given.
+	Infer bool
+
+	// Seed seeds the generation of contents and queries. Default: 1
+	Seed int64
+}
+
+// Report is the result of a load test. Its JSON form has durations in
+// nanoseconds.
+type Report struct {
+	// Elapsed is the wall time of the test, until the last operation
+	// completed.
+	Elapsed time.Duration `json:"elapsed"`
+
+	// Operations holds the statistics of each operation name that ran.
+	Operations map[string]*OperationStats `json:"operations"`
+}
+
+// OperationStats are the statistics of one kind of operation.
+//
+// The latencies are those of the operations that succeeded.
+type OperationStats struct {
+	Count  int `json:"count"`
+	Errors int `json:"errors"`
+
+	// Throughput is the number of successful operations per second.
+	Throughput float64 `json:"throughput"`
+
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+
+	// FirstError is the first error of the operation, if any failed.
+	FirstError string `json:"first_error,omitempty"`
+}
+
+// vocabulary is the words of the synthetic contents and queries.
+var vocabulary = strings.Fields(`
+	hiking mountains coffee tea travel japan python golang rust meeting
+	project deadline family dinner music guitar piano running marathon
+	book novel science history budget finance savings doctor allergy
+	garden tomatoes cooking pasta weekend holiday flight hotel museum
+	email phone slack office remote manager team review release bug
+	birthday gift movie series podcast language spanish french camera
+`)
+
+// Sample is the outcome of one operation.
+type Sample struct {
+	// Operation is the operation name, e.g. OperationAdd.
+	Operation string
+
+	// Latency is how long the operation took.
+	Latency time.Duration
+
+	// Err is the error of the operation if it failed.
+	Err error
+}
+
+// Run runs a load test against client, until opts.Duration has passed,
+// opts.Requests operations have run, or ctx is done. Operations in flight
+// when the test stops are completed, and count in the report.
+//
+// It returns an error only if the options are invalid; failed operations
+// are counted in the report.
+func Run(ctx context.Context, client *core.Client, opts Options) (*Report, error) {
+	if opts.AddRatio > 1 {
+		return nil, fmt.Errorf("loadtest: AddRatio must be at most 1, got %v", opts.AddRatio)
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 8
+	}
+	if opts.Duration <= 0 && opts.Requests <= 0 {
+		opts.Duration = 30 * time.Second
+	}
+	if opts.AddRatio == 0 {
+		opts.AddRatio = 0.2
+	}
+	if opts.Users <= 0 {
+		opts.Users = 100
+	}
+	if opts.SearchLimit <= 0 {
+		opts.SearchLimit = 10
+	}
+	if opts.Seed == 0 {
+		opts.Seed = 1
+	}
+
+	// stop tells the workers to start no new operation; the operations
+	// themselves run with ctx, so that they are not cut short
+	stop, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if opts.Duration > 0 {
+		stop, cancel = context.WithTimeout(stop, opts.Duration)
+		defer cancel()
+	}
+
+	var pace <-chan time.Time
+	if opts.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+		defer ticker.Stop()
+		pace = ticker.C
+	}
+
+	var started int64
+	samples := make([][]Sample, opts.Workers)
+	begin := time.Now()
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(opts.Seed + int64(w)))
+			for {
+				if pace != nil {
+					select {
+					case <-pace:
+					case <-stop.Done():
+						return
+					}
+				}
+				if stop.Err() != nil {
+					return
+				}
+				if opts.Requests > 0 && atomic.AddInt64(&started, 1) > int64(opts.Requests) {
+					return
+				}
+				samples[w] = append(samples[w], runOperation(ctx, client, rng, &opts))
+			}
+		}(w)
+	}
+	wg.Wait()
+	return NewReport(samples, time.Since(begin)), nil
+}
+
+// NewReport returns the report of the samples of each worker of a test that
+// lasted elapsed, e.g. to report the samples of load generators run on
+// several hosts together. Latency percentiles are nearest-rank.
+func NewReport(samples [][]Sample, elapsed time.Duration) *Report {
+	report := &Report{Elapsed: elapsed, Operations: make(map[string]*OperationStats)}
+	latencies := make(map[string][]time.Duration)
+	for _, workerSamples := range samples {
+		for _, s := range workerSamples {
+			stats := report.Operations[s.Operation]
+			if stats == nil {
+				stats = &OperationStats{}
+				report.Operations[s.Operation] = stats
+			}
+			stats.Count++
+			if s.Err != nil {
+				stats.Errors++
+				if stats.FirstError == "" {
+					stats.FirstError = s.Err.Error()
+				}
+				continue
+			}
+			latencies[s.Operation] = append(latencies[s.Operation], s.Latency)
+		}
+	}
+	for operation, stats := range report.Operations {
+		summarize(stats, latencies[operation], report.Elapsed)
+	}
+	return report
+}
+
+// runOperation runs a random operation of the mix of opts.
+func runOperation(ctx context.Context, client *core.Client, rng *rand.Rand, opts *Options) Sample {
+	userID := fmt.Sprintf("%s%d", UserPrefix, rng.Intn(opts.Users))
+	if rng.Float64() < opts.AddRatio {
+		content := sentence(rng, 8)
+		start := time.Now()
+		_, err := client.Add(ctx, content, core.WithUserID(userID), core.WithInfer(opts.Infer))
+		return Sample{Operation: OperationAdd, Latency: time.Since(start), Err: err}
+	}
+	query := sentence(rng, 3)
+	start := time.Now()
+	_, err := client.Search(ctx, query, core.WithUserIDForSearch(userID), core.WithLimit(opts.SearchLimit))
+	return Sample{Operation: OperationSearch, Latency: time.Since(start), Err: err}
+}
+
+// sentence returns a number of random words of the vocabulary.
+func sentence(rng *rand.Rand, words int) string {
+	parts := make([]string, words)
+	for i := range parts {
+		parts[i] = vocabulary[rng.Intn(len(vocabulary))]
+	}
+	return strings.Join(parts, " ")
+}
+
+// summarize sets the throughput and latency statistics of stats from the
+// latencies of its successful operations.
+func summarize(stats *OperationStats, latencies []time.Duration, elapsed time.Duration) {
+	if len(latencies) == 0 {
+		return
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+	stats.Mean = total / time.Duration(len(latencies))
+	stats.P50 = percentile(latencies, 50)
+	stats.P90 = percentile(latencies, 90)
+	stats.P99 = percentile(latencies, 99)
+	stats.Max = latencies[len(latencies)-1]
+	if elapsed > 0 {
+		stats.Throughput = float64(len(latencies)) / elapsed.Seconds()
+	}
+}
+
+// percentile returns the nearest-rank percentile p of sorted latencies.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Write writes the report as a table, one row per operation.
+func (r *Report) Write(w io.Writer) error {
+	names := make([]string, 0, len(r.Operations))
+	for name := range r.Operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "OPERATION\tCOUNT\tERRORS\tOPS/S\tMEAN\tP50\tP90\tP99\tMAX")
+	for _, name := range names {
+		s := r.Operations[name]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%v\t%v\t%v\t%v\t%v\n", name, s.Count, s.Errors, s.Throughput,
+			round(s.Mean), round(s.P50), round(s.P90), round(s.P99), round(s.Max))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nElapsed: %v\n", r.Elapsed.Round(time.Millisecond))
+	for _, name := range names {
+		if s := r.Operations[name]; s.FirstError != "" {
+			fmt.Fprintf(w, "First %s error: %s\n", name, s.FirstError)
+		}
+	}
+	return nil
+}
+
+// round rounds latencies for display.
+func round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
+
+// Cleanup deletes the memories of the synthetic users of a test run with
+// the given number of users (Options.Users, 0 for the default).
+func Cleanup(ctx context.Context, client *core.Client, users int) error {
+	if users <= 0 {
+		users = 100
+	}
+	for i := 0; i < users; i++ {
+		userID := fmt.Sprintf("%s%d", UserPrefix, i)
+		if err := client.DeleteAll(ctx, core.WithUserIDForDeleteAll(userID)); err != nil {
+			return fmt.Errorf("loadtest: deleting memories of %s: %w", userID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/secrets"
+)
+
+// Client resolves secrets from AWS Secrets Manager.
+// It implements the secrets.Provider interface.
+//
+// Requests are signed with AWS Signature Version 4 using static
+// credentials. To use other credential sources (instance roles, SSO, ...),
+// wrap the AWS SDK in a secrets.ProviderFunc instead.
+type Client struct {
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	endpoint        string
+	httpClient      *http.Client
+	now             func() time.Time
+}
+
+// Config is the configuration for the AWS Secrets Manager provider.
+// Region: AWS region, defaults to $AWS_REGION or $AWS_DEFAULT_REGION
+// AccessKeyID: access key ID, defaults to $AWS_ACCESS_KEY_ID
+// SecretAccessKey: secret access key, defaults to $AWS_SECRET_ACCESS_KEY
+// SessionToken: session token for temporary credentials, defaults to $AWS_SESSION_TOKEN
+// Endpoint: service endpoint, defaults to https://secretsmanager.<region>.amazonaws.com
+// HTTPClient: HTTP client to use, defaults to a client with a 10s timeout
+type Config struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Endpoint        string
+	HTTPClient      *http.Client
+}
+
+// NewClient creates a new AWS Secrets Manager provider.
+//
+// Args:
+//   - cfg: AWS configuration (may be nil to use the environment)
+//
+// Returns:
+//   - *Client: AWS Secrets Manager provider
+//   - error: Returns an error if the region or credentials are missing
+func NewClient(cfg *Config) (*Client, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+
+	c := &Client{
+		region:          firstNonEmpty(cfg.Region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION")),
+		accessKeyID:     firstNonEmpty(cfg.AccessKeyID, os.Getenv("AWS_ACCESS_KEY_ID")),
+		secretAccessKey: firstNonEmpty(cfg.SecretAccessKey, os.Getenv("AWS_SECRET_ACCESS_KEY")),
+		sessionToken:    cfg.SessionToken,
+		endpoint:        strings.TrimRight(cfg.Endpoint, "/"),
+		httpClient:      cfg.HTTPClient,
+		now:             time.Now,
+	}
+	if cfg.AccessKeyID == "" {
+		// Only take the session token from the environment with the environment's keys
+		c.sessionToken = firstNonEmpty(cfg.SessionToken, os.Getenv("AWS_SESSION_TOKEN"))
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if c.region == "" {
+		return nil, errors.New("aws: Region is required (or set AWS_REGION)")
+	}
+	if c.accessKeyID == "" || c.secretAccessKey == "" {
+		return nil, errors.New("aws: AccessKeyID and SecretAccessKey are required (or set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)")
+	}
+	if c.endpoint == "" {
+		c.endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", c.region)
+	}
+	return c, nil
+}
+
+// GetSecret returns the current value (AWSCURRENT) of a secret.
+//
+// name is the secret name or ARN, optionally followed by "#" and a key to
+// read from a secret stored as a JSON object, e.g. "prod/powermem#openai_api_key".
+// Without a key the whole SecretString is returned.
+func (c *Client) GetSecret(ctx context.Context, name string) (string, error) {
+	secretID, key := name, ""
+	if i := strings.LastIndex(name, "#"); i >= 0 {
+		secretID, key = name[:i], name[i+1:]
+	}
+	if secretID == "" {
+		return "", fmt.Errorf("aws: invalid secret name %q", name)
+	}
+
+	payload, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return "", fmt.Errorf("aws: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("aws: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	c.sign(req, payload, c.now().UTC())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("aws: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("aws: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(body, &apiErr)
+		if strings.HasSuffix(apiErr.Type, "ResourceNotFoundException") {
+			return "", fmt.Errorf("aws: %s: %w", secretID, secrets.ErrNotFound)
+		}
+		return "", fmt.Errorf("aws: %s: status %d: %s %s", secretID, resp.StatusCode, apiErr.Type, apiErr.Message)
+	}
+
+	var result struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("aws: %s: invalid response: %w", secretID, err)
+	}
+	if result.SecretString == nil {
+		return "", fmt.Errorf("aws: %s: binary secrets are not supported", secretID)
+	}
+	if key == "" {
+		return *result.SecretString, nil
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(*result.SecretString), &values); err != nil {
+		return "", fmt.Errorf("aws: %s: secret is not a JSON object, cannot read key %q", secretID, key)
+	}
+	value, ok := values[key].(string)
+	if !ok || value == "" {
+		return "", fmt.Errorf("aws: %s#%s: %w", secretID, key, secrets.ErrNotFound)
+	}
+	return value, nil
+}
+
+// sign adds the AWS Signature Version 4 headers to req.
+func (c *Client) sign(req *http.Request, payload []byte, now time.Time) {
+	const service = "secretsmanager"
+
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if c.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", c.sessionToken)
+	}
+
+	// Canonical headers: lowercase names, sorted, including host
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hashHex(payload),
+	}, "\n")
+
+	scope := strings.Join([]string{date, c.region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.secretAccessKey), date)
+	key = hmacSHA256(key, c.region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes query parameters sorted by name and value, with
+// spaces as %20 as SigV4 requires.
+func canonicalQuery(query url.Values) string {
+	var pairs []string
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, awsEscape(name)+"="+awsEscape(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape percent-encodes s as SigV4 requires.
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
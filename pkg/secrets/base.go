@@ -0,0 +1,119 @@
+// Package secrets provides interfaces and utilities for resolving secrets,
+// such as provider API keys, from external secret stores.
+//
+// It defines the Provider interface that all secret stores implement, and a
+// Cache that refreshes secrets lazily so that rotated keys are picked up
+// without restarting. Implementations live in the env, file, vault and aws
+// subpackages.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned (wrapped) by providers when a secret does not exist.
+var ErrNotFound = errors.New("secret not found")
+
+// Provider defines the interface for secret providers.
+//
+// All secret store implementations (environment, files, Vault, AWS Secrets
+// Manager, etc.) must implement this interface.
+type Provider interface {
+	// GetSecret returns the current value of the named secret.
+	//
+	// The format of name depends on the provider. Errors for missing secrets
+	// wrap ErrNotFound.
+	GetSecret(ctx context.Context, name string) (string, error)
+}
+
+// ProviderFunc adapts a function to the Provider interface, for example to
+// use a secret store without a built-in implementation.
+//
+// Example:
+//
+//	provider := secrets.ProviderFunc(func(ctx context.Context, name string) (string, error) {
+//	    return keyring.Get("powermem", name)
+//	})
+type ProviderFunc func(ctx context.Context, name string) (string, error)
+
+// GetSecret calls f(ctx, name).
+func (f ProviderFunc) GetSecret(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
+
+// DefaultRefreshInterval is how long a Cache keeps a secret when no interval
+// is given.
+const DefaultRefreshInterval = 5 * time.Minute
+
+// Cache wraps a Provider and keeps each secret for a refresh interval.
+//
+// Secrets are refreshed lazily: the first GetSecret after the interval has
+// passed fetches the secret again, so rotated secrets are picked up within
+// one interval. If the refresh fails, the previous value is returned and the
+// next GetSecret tries again, so a temporarily unavailable secret store does
+// not break a running client.
+type Cache struct {
+	provider Provider
+	interval time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// cacheEntry is a cached secret value.
+type cacheEntry struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// NewCache creates a Cache over provider that refreshes secrets after
+// interval (DefaultRefreshInterval if interval is not positive).
+//
+// Example:
+//
+//	cached := secrets.NewCache(vaultClient, time.Minute)
+func NewCache(provider Provider, interval time.Duration) *Cache {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	return &Cache{
+		provider: provider,
+		interval: interval,
+		entries:  make(map[string]*cacheEntry),
+	}
+}
+
+// GetSecret returns the cached value of the named secret, fetching it from
+// the underlying provider if it is not cached or older than the interval.
+func (c *Cache) GetSecret(ctx context.Context, name string) (string, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[name]
+	c.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < c.interval {
+		return entry.value, nil
+	}
+
+	value, err := c.provider.GetSecret(ctx, name)
+	if err != nil {
+		if ok {
+			return entry.value, nil
+		}
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.entries[name] = &cacheEntry{value: value, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// Invalidate drops the cached value of the named secret, so that the next
+// GetSecret fetches it again. Use it when a key is known to be rejected.
+func (c *Cache) Invalidate(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, name)
+}
@@ -0,0 +1,49 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/oceanbase/powermem-go/pkg/secrets"
+)
+
+// Client resolves secrets from environment variables.
+// It implements the secrets.Provider interface.
+type Client struct {
+	prefix string
+}
+
+// Config is the configuration for the environment secret provider.
+// Prefix: prepended to secret names to form the variable name (optional),
+// e.g. with Prefix "POWERMEM_" the secret "OPENAI_API_KEY" is read from
+// POWERMEM_OPENAI_API_KEY
+type Config struct {
+	Prefix string
+}
+
+// NewClient creates a new environment secret provider.
+//
+// Args:
+//   - cfg: Configuration containing the variable name prefix (may be nil)
+//
+// Returns:
+//   - *Client: Environment secret provider
+//   - error: Always nil; returned for consistency with the other providers
+func NewClient(cfg *Config) (*Client, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	return &Client{prefix: cfg.Prefix}, nil
+}
+
+// GetSecret returns the value of the environment variable named by the
+// prefix and name. An unset or empty variable is reported as
+// secrets.ErrNotFound.
+func (c *Client) GetSecret(ctx context.Context, name string) (string, error) {
+	value := os.Getenv(c.prefix + name)
+	if value == "" {
+		return "", fmt.Errorf("env: %s%s: %w", c.prefix, name, secrets.ErrNotFound)
+	}
+	return value, nil
+}
@@ -0,0 +1,66 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/oceanbase/powermem-go/pkg/secrets"
+)
+
+// Client resolves secrets from files, one file per secret, as mounted by
+// Docker and Kubernetes secrets.
+// It implements the secrets.Provider interface.
+type Client struct {
+	dir string
+}
+
+// Config is the configuration for the file secret provider.
+// Dir: directory containing one file per secret (required), e.g. "/run/secrets"
+type Config struct {
+	Dir string
+}
+
+// NewClient creates a new file secret provider.
+//
+// Args:
+//   - cfg: Configuration containing the secrets directory
+//
+// Returns:
+//   - *Client: File secret provider
+//   - error: Returns an error if Dir is not set
+func NewClient(cfg *Config) (*Client, error) {
+	if cfg == nil || cfg.Dir == "" {
+		return nil, errors.New("file: Dir is required")
+	}
+	return &Client{dir: cfg.Dir}, nil
+}
+
+// GetSecret returns the content of the file named name in the secrets
+// directory, without surrounding whitespace. The file is read on every call,
+// so rotated secrets are picked up (wrap the client in a secrets.Cache to
+// limit reads).
+//
+// Names must not contain path separators.
+func (c *Client) GetSecret(ctx context.Context, name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return "", fmt.Errorf("file: invalid secret name %q", name)
+	}
+
+	data, err := os.ReadFile(filepath.Join(c.dir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("file: %s: %w", name, secrets.ErrNotFound)
+		}
+		return "", fmt.Errorf("file: %w", err)
+	}
+
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return "", fmt.Errorf("file: %s is empty: %w", name, secrets.ErrNotFound)
+	}
+	return value, nil
+}
@@ -0,0 +1,192 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/secrets"
+)
+
+// Client resolves secrets from a HashiCorp Vault KV secrets engine over the
+// Vault HTTP API.
+// It implements the secrets.Provider interface.
+type Client struct {
+	address    string
+	token      string
+	namespace  string
+	mount      string
+	kvVersion  int
+	httpClient *http.Client
+}
+
+// Config is the configuration for the Vault secret provider.
+// Address: Vault server address, defaults to $VAULT_ADDR
+// Token: Vault token, defaults to $VAULT_TOKEN
+// Namespace: Vault Enterprise namespace (optional), defaults to $VAULT_NAMESPACE
+// Mount: KV secrets engine mount path, defaults to "secret"
+// KVVersion: KV secrets engine version (1 or 2), defaults to 2
+// HTTPClient: HTTP client to use, defaults to a client with a 10s timeout
+type Config struct {
+	Address    string
+	Token      string
+	Namespace  string
+	Mount      string
+	KVVersion  int
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new Vault secret provider.
+//
+// Args:
+//   - cfg: Vault configuration (may be nil to use the environment)
+//
+// Returns:
+//   - *Client: Vault secret provider
+//   - error: Returns an error if the address or token is missing
+func NewClient(cfg *Config) (*Client, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+
+	c := &Client{
+		address:    cfg.Address,
+		token:      cfg.Token,
+		namespace:  cfg.Namespace,
+		mount:      strings.Trim(cfg.Mount, "/"),
+		kvVersion:  cfg.KVVersion,
+		httpClient: cfg.HTTPClient,
+	}
+	if c.address == "" {
+		c.address = os.Getenv("VAULT_ADDR")
+	}
+	if c.token == "" {
+		c.token = os.Getenv("VAULT_TOKEN")
+	}
+	if c.namespace == "" {
+		c.namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+	if c.mount == "" {
+		c.mount = "secret"
+	}
+	if c.kvVersion == 0 {
+		c.kvVersion = 2
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if c.address == "" {
+		return nil, errors.New("vault: Address is required (or set VAULT_ADDR)")
+	}
+	if c.token == "" {
+		return nil, errors.New("vault: Token is required (or set VAULT_TOKEN)")
+	}
+	if c.kvVersion != 1 && c.kvVersion != 2 {
+		return nil, fmt.Errorf("vault: unsupported KV version %d", c.kvVersion)
+	}
+	c.address = strings.TrimRight(c.address, "/")
+	return c, nil
+}
+
+// GetSecret reads a secret from the KV engine.
+//
+// name is the secret path within the mount, optionally followed by "#" and
+// the key to return, e.g. "powermem/openai#api_key". The key defaults to
+// "value".
+func (c *Client) GetSecret(ctx context.Context, name string) (string, error) {
+	path, key := name, "value"
+	if i := strings.LastIndex(name, "#"); i >= 0 {
+		path, key = name[:i], name[i+1:]
+	}
+	path = strings.Trim(path, "/")
+	if path == "" || key == "" {
+		return "", fmt.Errorf("vault: invalid secret name %q", name)
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/%s/%s", c.address, c.mount, escapePath(path))
+	if c.kvVersion == 2 {
+		endpoint = fmt.Sprintf("%s/v1/%s/data/%s", c.address, c.mount, escapePath(path))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", c.token)
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", fmt.Errorf("vault: %s: %w", path, secrets.ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("vault: %s: status %d: %s", path, resp.StatusCode, vaultErrors(body))
+	}
+
+	var result struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("vault: %s: invalid response: %w", path, err)
+	}
+	data := result.Data
+	if c.kvVersion == 2 {
+		// KV v2 nests the secret under data.data
+		var nested struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(data, &nested); err != nil {
+			return "", fmt.Errorf("vault: %s: invalid response: %w", path, err)
+		}
+		data = nested.Data
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil || values == nil {
+		return "", fmt.Errorf("vault: %s: %w", path, secrets.ErrNotFound)
+	}
+	value, ok := values[key].(string)
+	if !ok || value == "" {
+		return "", fmt.Errorf("vault: %s#%s: %w", path, key, secrets.ErrNotFound)
+	}
+	return value, nil
+}
+
+// escapePath escapes each segment of a secret path.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// vaultErrors extracts the error messages of a Vault error response.
+func vaultErrors(body []byte) string {
+	var result struct {
+		Errors []string `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &result); err == nil && len(result.Errors) > 0 {
+		return strings.Join(result.Errors, "; ")
+	}
+	return strings.TrimSpace(string(body))
+}
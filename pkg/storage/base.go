@@ -6,9 +6,76 @@ package storage
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
 	"time"
 )
 
+// ErrVersionConflict is returned by Update when UpdateOptions.ExpectedVersion
+// does not match the current version of the memory, i.e. another writer
+// updated it since it was read.
+var ErrVersionConflict = errors.New("version conflict")
+
+// ErrCountMismatch is returned by DeleteWhere when the number of matching
+// memories is not DeleteWhereOptions.ExpectedCount, i.e. memories matching
+// the filters were written or deleted since they were counted.
+var ErrCountMismatch = errors.New("matching memory count changed")
+
+// ErrNotFound is returned by Get, GetByUID, GetByHash, Update and Delete when the memory
+// does not exist, has expired, or does not belong to the requested user or
+// agent.
+var ErrNotFound = errors.New("not found or access denied")
+
+// ErrDuplicateID is returned by Insert when a memory with the same ID already
+// exists.
+var ErrDuplicateID = errors.New("duplicate memory ID")
+
+// ErrDuplicateUID is returned by Insert when a memory with the same string
+// ID (Memory.UID) already exists.
+var ErrDuplicateUID = errors.New("duplicate memory UID")
+
+// ContentHash returns the hash of the content of a memory stored by Insert
+// and Update: its hex-encoded MD5, compatible with the Python SDK.
+func ContentHash(content string) string {
+	hash := md5.Sum([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+// InsertTimes returns the created_at and updated_at of a memory inserted at
+// now, and its version: those of the memory when set, see Insert.
+func InsertTimes(memory *Memory, now time.Time) (createdAt, updatedAt time.Time, version int64) {
+	createdAt, updatedAt, version = memory.CreatedAt, memory.UpdatedAt, memory.Version
+	if createdAt.IsZero() {
+		createdAt = now
+	}
+	if updatedAt.IsZero() {
+		updatedAt = createdAt
+	}
+	if version < 1 {
+		version = 1
+	}
+	return createdAt, updatedAt, version
+}
+
+// ActiveAt returns the time reads hide the memories expired at: now, the Now
+// of their options, or the current time if now is zero.
+func ActiveAt(now time.Time) time.Time {
+	if now.IsZero() {
+		return time.Now()
+	}
+	return now
+}
+
+// UpdateTime returns the updated_at of a memory updated with opts: its Now,
+// or the current time if it is zero.
+func UpdateTime(opts *UpdateOptions) time.Time {
+	if opts.Now.IsZero() {
+		return time.Now()
+	}
+	return opts.Now
+}
+
 // Memory represents a memory stored in the vector store.
 //
 // This type is defined in the storage package to avoid circular dependencies
@@ -49,8 +116,39 @@ type Memory struct {
 
 	// Score is the similarity score from search operations.
 	Score float64
+
+	// Tags are free-form labels used for filtering.
+	Tags []string
+
+	// ExpiresAt is when the memory expires (nil if it never expires).
+	// Expired memories are excluded from reads and removed by PurgeExpired.
+	ExpiresAt *time.Time
+
+	// UID is the string ID of the memory (e.g. a UUIDv7) for deployments
+	// that key memories by string. It is unique when set and empty otherwise.
+	UID string
+
+	// Version starts at 1 and is incremented by every Update. It is used for
+	// optimistic concurrency control with UpdateOptions.ExpectedVersion.
+	Version int64
+
+	// ParentID is the memory this memory is a chunk of, or 0 if it is not a
+	// chunk. Chunks are found by Search but excluded from GetAll and
+	// SearchByKeyword.
+	ParentID int64
+
+	// Hash is the ContentHash of the content, maintained by Insert and
+	// Update. It is set by reads and ignored by writes.
+	Hash string
+
+	// RunID is the run (session) the memory belongs to, read from
+	// metadata[RunIDKey]. It is set by reads and ignored by writes.
+	RunID string
 }
 
+// RunIDKey is the metadata key holding the run a memory belongs to.
+const RunIDKey = "run_id"
+
 // VectorIndexType defines the type of vector index for efficient similarity search.
 type VectorIndexType string
 
@@ -128,7 +226,13 @@ type VectorIndexConfig struct {
 //
 // All storage implementations (SQLite, PostgreSQL, OceanBase) must implement this interface.
 type VectorStore interface {
-	// Insert inserts a memory into the store.
+	// Insert inserts a memory into the store. It fails with ErrDuplicateID
+	// if a memory with the same ID exists, and with ErrDuplicateUID if one
+	// with the same UID exists.
+	//
+	// CreatedAt, UpdatedAt and Version are kept when set, so that copies of
+	// a memory (see package replication) keep them; otherwise they are set
+	// to the current time and 1.
 	Insert(ctx context.Context, memory *Memory) error
 
 	// Search performs vector similarity search.
@@ -141,12 +245,42 @@ type VectorStore interface {
 	// Returns matching memories sorted by similarity (highest first).
 	Search(ctx context.Context, embedding []float64, opts *SearchOptions) ([]*Memory, error)
 
+	// SearchIter performs vector similarity search and returns an iterator
+	// producing the results in batches of batchSize, fetched from the database
+	// one batch at a time with keyset pagination (see SearchOptions.After).
+	//
+	// opts.Limit caps the total number of results; zero means no cap.
+	SearchIter(ctx context.Context, embedding []float64, opts *SearchOptions, batchSize int) (MemoryIterator, error)
+
+	// SearchByKeyword performs a case-insensitive substring match on memory content.
+	//
+	// No embedding is required, which makes it suitable for exact identifiers
+	// such as order numbers, emails, and codes. Matching memories have Score 1.0
+	// and are returned newest first. opts.MinScore and opts.Threshold are ignored.
+	SearchByKeyword(ctx context.Context, text string, opts *SearchOptions) ([]*Memory, error)
+
 	// Get retrieves a memory by ID with optional access control.
 	//
 	// If opts.UserID or opts.AgentID is specified, the memory will only be returned
 	// if it matches the specified user/agent (multi-tenant isolation).
 	Get(ctx context.Context, id int64, opts *GetOptions) (*Memory, error)
 
+	// GetByUID retrieves a memory by its string ID (Memory.UID) with optional
+	// access control, with the same semantics as Get.
+	GetByUID(ctx context.Context, uid string, opts *GetOptions) (*Memory, error)
+
+	// GetByHash retrieves a memory whose content has the given hash (see
+	// ContentHash) with optional access control, with the same semantics as
+	// Get. If several memories match, any of them is returned.
+	GetByHash(ctx context.Context, hash string, opts *GetOptions) (*Memory, error)
+
+	// GetMany retrieves multiple memories by ID in a single query.
+	//
+	// IDs that do not exist, or that fail the opts.UserID/opts.AgentID access check,
+	// are omitted from the result rather than reported as errors. Results are
+	// returned in no particular order.
+	GetMany(ctx context.Context, ids []int64, opts *GetOptions) ([]*Memory, error)
+
 	// Update updates a memory's content and embedding with optional access control.
 	//
 	// If opts.UserID or opts.AgentID is specified, the update will only succeed
@@ -162,9 +296,30 @@ type VectorStore interface {
 	// GetAll retrieves all memories with optional filtering and pagination.
 	GetAll(ctx context.Context, opts *GetAllOptions) ([]*Memory, error)
 
+	// ListTags returns the distinct tags in use, sorted alphabetically.
+	// If userID is empty, tags across all users are returned.
+	ListTags(ctx context.Context, userID string) ([]string, error)
+
 	// DeleteAll deletes all memories matching the given filters.
 	DeleteAll(ctx context.Context, opts *DeleteAllOptions) error
 
+	// DeleteWhere deletes the memories matching opts with a single DELETE
+	// statement, or only counts them if opts.DryRun is set. Expired memories
+	// that were not purged yet are matched too.
+	//
+	// Returns the number of matching memories. Unless opts.DryRun is set,
+	// nothing is deleted and ErrCountMismatch is returned if that number is
+	// not opts.ExpectedCount.
+	DeleteWhere(ctx context.Context, opts *DeleteWhereOptions) (int64, error)
+
+	// PurgeExpired permanently deletes memories that expired at or before the given time.
+	//
+	// Returns the number of deleted memories.
+	PurgeExpired(ctx context.Context, before time.Time) (int64, error)
+
+	// Ping verifies that the store is reachable.
+	Ping(ctx context.Context) error
+
 	// Close closes the store and releases resources.
 	Close() error
 
@@ -174,8 +329,175 @@ type VectorStore interface {
 	// Reset resets the vector store by dropping and recreating the collection/table.
 	//
 	// WARNING: This operation will delete ALL memories and cannot be undone.
-	// Use with caution.
+	// Use with caution. The change log and the audit log are kept.
 	Reset(ctx context.Context) error
+
+	// AppendChange appends a change to the change log, an outbox table named
+	// after the collection with a "_changes" suffix, and sets change.Seq.
+	AppendChange(ctx context.Context, change *Change) error
+
+	// ListChanges returns the changes after opts.After, in Seq order.
+	ListChanges(ctx context.Context, opts *ListChangesOptions) ([]*Change, error)
+
+	// LastChangeSeq returns the Seq of the latest change, or 0 if the change
+	// log is empty.
+	LastChangeSeq(ctx context.Context) (int64, error)
+
+	// PurgeChanges deletes the changes recorded before the given time.
+	//
+	// Returns the number of deleted changes.
+	PurgeChanges(ctx context.Context, before time.Time) (int64, error)
+
+	// AppendAudit appends an entry to the audit log, an append-only table
+	// named after the collection with an "_audit" suffix, and sets
+	// entry.Seq. Reset, EraseUser and DropCollection keep the audit log.
+	AppendAudit(ctx context.Context, entry *AuditEntry) error
+
+	// ListAudit returns the audit entries after opts.After, in Seq order.
+	ListAudit(ctx context.Context, opts *ListAuditOptions) ([]*AuditEntry, error)
+
+	// EraseUser permanently deletes the memories and the change log entries
+	// of a user in a single transaction, then counts the user's rows left.
+	//
+	// Returns the number of deleted and remaining rows. Rows remain only if
+	// they were written concurrently, by another client.
+	EraseUser(ctx context.Context, userID string) (*ErasureCounts, error)
+}
+
+// CollectionManager is implemented by the vector stores whose database can
+// hold several collections (memory tables), e.g. one per product.
+//
+// A collection is created by opening a store with its name as the collection
+// name; it consists of the memory table and its change log.
+type CollectionManager interface {
+	// ListCollections returns the names of the collections of the database,
+	// sorted.
+	ListCollections(ctx context.Context) ([]string, error)
+
+	// DropCollection permanently deletes a collection: its memories and its
+	// change log. Its audit log is kept. Dropping a collection that does not
+	// exist is not an error.
+	DropCollection(ctx context.Context, name string) error
+}
+
+// TeamIDKey is the metadata key holding the team a memory is shared with.
+const TeamIDKey = "team_id"
+
+// TeamStore is implemented by the vector stores that keep the members of
+// teams, in a table named after the collection with a "_teams" suffix.
+// EraseUser deletes the memberships of the user; Reset keeps them.
+type TeamStore interface {
+	// AddTeamMember adds userID to teamID. Adding a member again is not an
+	// error.
+	AddTeamMember(ctx context.Context, teamID, userID string) error
+
+	// RemoveTeamMember removes userID from teamID. Removing a user who is
+	// not a member is not an error.
+	RemoveTeamMember(ctx context.Context, teamID, userID string) error
+
+	// ListTeamMembers returns the members of teamID, sorted.
+	ListTeamMembers(ctx context.Context, teamID string) ([]string, error)
+
+	// ListUserTeams returns the teams of userID, sorted.
+	ListUserTeams(ctx context.Context, userID string) ([]string, error)
+}
+
+// ErasureCounts reports the rows deleted by EraseUser.
+type ErasureCounts struct {
+	// Memories is the number of deleted memories.
+	Memories int64
+
+	// Changes is the number of deleted change log entries.
+	Changes int64
+
+	// Remaining is the number of memories and change log entries of the user
+	// found after the deletion was committed.
+	Remaining int64
+}
+
+// Change is an entry of the change log.
+type Change struct {
+	// Seq is the position of the change in the log, assigned by
+	// AppendChange. It increases with every change.
+	Seq int64
+
+	// Type is the kind of change (e.g. "memory.created").
+	Type string
+
+	// MemoryID is the changed memory, or 0 for changes to several memories.
+	MemoryID int64
+
+	// UserID and AgentID are the owner of the changed memory, or the filter
+	// of a change to several memories.
+	UserID  string
+	AgentID string
+
+	// Payload is the encoded change, opaque to the store.
+	Payload []byte
+
+	// CreatedAt is when the change was recorded.
+	CreatedAt time.Time
+}
+
+// ListChangesOptions contains options for ListChanges.
+type ListChangesOptions struct {
+	// After restricts results to changes with a greater Seq.
+	After int64
+
+	// UserID restricts results to changes of this user's memories. Changes
+	// to several memories (MemoryID 0) without a user match every user.
+	UserID string
+
+	// AgentID restricts results to changes of this agent's memories, with
+	// the same rule as UserID.
+	AgentID string
+
+	// Types restricts results to these kinds of change.
+	Types []string
+
+	// Limit sets the maximum number of results (0 for no limit).
+	Limit int
+}
+
+// AuditEntry is an entry of the audit log.
+type AuditEntry struct {
+	// Seq is the position of the entry in the log, assigned by AppendAudit.
+	Seq int64
+
+	// Operation is the audited operation (e.g. "DeleteAll").
+	Operation string
+
+	// Actor is who requested the operation (empty if unknown).
+	Actor string
+
+	// UserID is the user whose data the operation affects (empty for
+	// operations on every user).
+	UserID string
+
+	// Params are the encoded parameters of the operation, opaque to the
+	// store.
+	Params []byte
+
+	// CreatedAt is when the entry was recorded.
+	CreatedAt time.Time
+}
+
+// ListAuditOptions contains options for ListAudit.
+type ListAuditOptions struct {
+	// After restricts results to entries with a greater Seq.
+	After int64
+
+	// Operations restricts results to these operations.
+	Operations []string
+
+	// Actor restricts results to the entries of this actor.
+	Actor string
+
+	// UserID restricts results to the entries of this user.
+	UserID string
+
+	// Limit sets the maximum number of results (0 for no limit).
+	Limit int
 }
 
 // SearchOptions contains options for search operations.
@@ -213,6 +535,90 @@ type SearchOptions struct {
 
 	// Filters provides additional metadata filters.
 	Filters map[string]interface{}
+
+	// TimeRange restricts results to memories created or updated within a time window.
+	TimeRange *TimeRange
+
+	// Tags restricts results to memories carrying all of these tags.
+	Tags []string
+
+	// After, if non-nil, restricts Search to memories ranked after the cursor:
+	// a lower score, or the same score and a higher ID. Search orders results
+	// by score and then ID, so passing the last memory of a page as the
+	// cursor returns the next page.
+	After *SearchCursor
+
+	// Stats, if non-nil, is filled with diagnostic counts by Search.
+	// Some backends run additional COUNT queries to compute them.
+	Stats *SearchStats
+
+	// Hybrid, if set, asks for a hybrid search: backends with full-text
+	// search also match Query against the memory content and rank results
+	// by both vector similarity and text relevance. Backends without
+	// full-text search ignore it.
+	Hybrid bool
+
+	// EfSearch, if positive, is the search depth of the HNSW index used by
+	// the search, overriding the default of the store. Higher values improve
+	// recall but slow the search. Backends without an HNSW index ignore it.
+	EfSearch int
+
+	// TeamIDs widens UserID to the memories shared with these teams
+	// (metadata[TeamIDKey]), whoever their user is. Ignored without UserID.
+	TeamIDs []string
+
+	// WithoutEmbeddings leaves Memory.Embedding of the results nil: the
+	// embedding column is not read, which shrinks large result sets.
+	WithoutEmbeddings bool
+
+	// Fields, if non-empty, restricts the fields read for the results to
+	// these, e.g. FieldContent and FieldMetadata for a listing. The others
+	// are left zero. Default: all fields (see AllFields)
+	Fields []Field
+
+	// Now is the time memories that expired at or before it are hidden at,
+	// e.g. the time of a test clock. Default: the current time (see
+	// ActiveAt)
+	Now time.Time
+}
+
+// SearchStats contains diagnostic counts collected by Search.
+type SearchStats struct {
+	// TotalMemories is the number of memories in the collection.
+	TotalMemories int64
+
+	// Candidates is the number of memories matching the user, agent, metadata,
+	// time range, tag and expiration filters.
+	Candidates int64
+
+	// AboveThreshold is the number of candidates meeting the minimum score.
+	AboveThreshold int64
+}
+
+// TimeRange restricts queries to memories whose created_at/updated_at
+// timestamps fall within the given bounds.
+//
+// Zero-valued bounds are ignored, so callers only need to set the
+// bounds they care about. After bounds are inclusive, Before bounds are exclusive.
+type TimeRange struct {
+	// CreatedAfter matches memories created at or after this time.
+	CreatedAfter time.Time
+
+	// CreatedBefore matches memories created before this time.
+	CreatedBefore time.Time
+
+	// UpdatedAfter matches memories updated at or after this time.
+	UpdatedAfter time.Time
+
+	// UpdatedBefore matches memories updated before this time.
+	UpdatedBefore time.Time
+}
+
+// IsZero reports whether no bounds are set.
+func (r *TimeRange) IsZero() bool {
+	return r == nil ||
+		(r.CreatedAfter.IsZero() && r.CreatedBefore.IsZero() &&
+			r.UpdatedAfter.IsZero() && r.UpdatedBefore.IsZero())
 }
 
 // GetOptions contains options for get operations with access control.
@@ -226,6 +632,10 @@ type GetOptions struct {
 	// If specified, Get will return an error if the memory's AgentID doesn't match.
 	// This enables agent-level access control.
 	AgentID string
+
+	// Now hides the memories expired at this time, with the same semantics
+	// as SearchOptions.Now.
+	Now time.Time
 }
 
 // UpdateOptions contains options for update operations with access control.
@@ -239,6 +649,30 @@ type UpdateOptions struct {
 	// If specified, Update will fail if the memory's AgentID doesn't match.
 	// This prevents unauthorized modifications across agents.
 	AgentID string
+
+	// Metadata replaces the memory's metadata when non-nil.
+	// If nil, the existing metadata is left unchanged.
+	Metadata map[string]interface{}
+
+	// RetentionStrength replaces the memory's retention strength when non-nil.
+	RetentionStrength *float64
+
+	// LastAccessedAt replaces the memory's last access time when non-nil.
+	LastAccessedAt *time.Time
+
+	// ClearExpiresAt removes the memory's expiration time, so that it never
+	// expires.
+	ClearExpiresAt bool
+
+	// ExpectedVersion, if > 0, makes the update conditional on the memory's
+	// current Version. If the memory was updated in the meantime, Update
+	// fails with ErrVersionConflict and nothing is written. This keeps
+	// concurrent writers sharing one backend from overwriting each other.
+	ExpectedVersion int64
+
+	// Now is the time of the update, stored as the memory's UpdatedAt. If
+	// zero, the current time is used.
+	Now time.Time
 }
 
 // DeleteOptions contains options for delete operations with access control.
@@ -267,6 +701,40 @@ type GetAllOptions struct {
 
 	// Offset sets the number of results to skip (for pagination).
 	Offset int
+
+	// TimeRange restricts results to memories created or updated within a time window.
+	TimeRange *TimeRange
+
+	// Tags restricts results to memories carrying all of these tags.
+	Tags []string
+
+	// Filters restricts results to memories whose metadata matches all of these
+	// key/value pairs, using the same semantics as SearchOptions.Filters.
+	Filters map[string]interface{}
+
+	// Entities restricts results to memories annotated with all of these
+	// entities in metadata["entities"], compared case-insensitively.
+	Entities []string
+
+	// ParentID returns the chunks of this memory instead of the memories
+	// that are not chunks.
+	ParentID int64
+
+	// TeamIDs widens UserID to the memories shared with these teams, with
+	// the same semantics as SearchOptions.TeamIDs.
+	TeamIDs []string
+
+	// WithoutEmbeddings leaves Memory.Embedding of the results nil, with
+	// the same semantics as SearchOptions.WithoutEmbeddings.
+	WithoutEmbeddings bool
+
+	// Fields restricts the fields read for the results, with the same
+	// semantics as SearchOptions.Fields.
+	Fields []Field
+
+	// Now hides the memories expired at this time, with the same semantics
+	// as SearchOptions.Now.
+	Now time.Time
 }
 
 // DeleteAllOptions contains options for DeleteAll operations.
@@ -276,4 +744,34 @@ type DeleteAllOptions struct {
 
 	// AgentID filters deletions to a specific agent.
 	AgentID string
+
+	// ParentID filters deletions to the chunks of this memory.
+	ParentID int64
+}
+
+// DeleteWhereOptions contains options for DeleteWhere operations.
+type DeleteWhereOptions struct {
+	// UserID filters deletions to a specific user.
+	UserID string
+
+	// AgentID filters deletions to a specific agent.
+	AgentID string
+
+	// TimeRange restricts deletions to memories created or updated within a
+	// time window.
+	TimeRange *TimeRange
+
+	// Tags restricts deletions to memories carrying all of these tags.
+	Tags []string
+
+	// Filters restricts deletions to memories whose metadata matches all of
+	// these key/value pairs, using the same semantics as SearchOptions.Filters.
+	Filters map[string]interface{}
+
+	// DryRun counts the matching memories without deleting them.
+	DryRun bool
+
+	// ExpectedCount is the number of memories the deletion must match, as
+	// counted by a dry run.
+	ExpectedCount int64
 }
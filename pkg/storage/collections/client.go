@@ -0,0 +1,564 @@
+// Package collections provides a vector store that keeps memories in several
+// collections of one database, e.g. one per product.
+//
+// Each operation runs on the collection named by its context (see
+// ContextWithCollection), or on the default collection. The stores of the
+// other collections are opened on first use, behind a single VectorStore and
+// so a single memory client.
+package collections
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// ErrNotFound is returned for operations on a collection that does not exist.
+var ErrNotFound = errors.New("collection not found")
+
+// namePattern is the pattern of collection names: lower case SQL identifiers,
+// valid as table names in every backend.
+var namePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// ValidateName returns an error if name cannot be the name of a collection.
+//
+// Names are lower case letters, digits and underscores, start with a letter
+// or an underscore, are at most 63 characters long, and do not end in
+// "_changes", "_audit" or "_teams" (the suffixes of change log, audit log and
+// team membership tables).
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid collection name %q: want lower case letters, digits and underscores", name)
+	}
+	for _, suffix := range []string{"_changes", "_audit", "_teams"} {
+		if strings.HasSuffix(name, suffix) {
+			return fmt.Errorf("invalid collection name %q: the %s suffix is reserved", name, suffix)
+		}
+	}
+	return nil
+}
+
+// collectionKey is the context key of the collection of an operation.
+type collectionKey struct{}
+
+// ContextWithCollection returns a context whose operations run on the named
+// collection instead of the default one.
+func ContextWithCollection(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, collectionKey{}, name)
+}
+
+// CollectionFromContext returns the collection named by ctx ("" for the
+// default collection).
+func CollectionFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(collectionKey{}).(string)
+	return name
+}
+
+// Opener opens the store of a collection, creating its tables if they do not
+// exist.
+type Opener func(name string) (storage.VectorStore, error)
+
+// Config contains configuration for creating a collections VectorStore.
+type Config struct {
+	// Default is the store of the default collection. Required; it must
+	// implement storage.CollectionManager for the collections to be listed,
+	// dropped or opened.
+	Default storage.VectorStore
+
+	// DefaultName is the name of the default collection. Required.
+	DefaultName string
+
+	// Open opens the store of a collection. Required.
+	Open Opener
+}
+
+// Client implements VectorStore on top of the collections of a database.
+type Client struct {
+	// defaultStore is the store of the default collection.
+	defaultStore storage.VectorStore
+
+	// defaultName is the name of the default collection.
+	defaultName string
+
+	// open opens the store of a collection.
+	open Opener
+
+	// mu protects stores.
+	mu sync.Mutex
+
+	// stores are the open stores of the other collections by name.
+	stores map[string]storage.VectorStore
+}
+
+// NewClient creates a new collections VectorStore.
+//
+// Parameters:
+//   - cfg: Configuration containing the default store and the opener
+//
+// Returns:
+//   - *Client: The collections client instance
+//   - error: Error if the configuration is invalid
+func NewClient(cfg *Config) (*Client, error) {
+	if cfg.Default == nil {
+		return nil, errors.New("collections: no default store")
+	}
+	if cfg.DefaultName == "" {
+		return nil, errors.New("collections: no default collection name")
+	}
+	if cfg.Open == nil {
+		return nil, errors.New("collections: no opener")
+	}
+	return &Client{
+		defaultStore: cfg.Default,
+		defaultName:  cfg.DefaultName,
+		open:         cfg.Open,
+		stores:       make(map[string]storage.VectorStore),
+	}, nil
+}
+
+// DefaultName returns the name of the default collection.
+func (c *Client) DefaultName() string {
+	return c.defaultName
+}
+
+// manager returns the CollectionManager of the default store.
+func (c *Client) manager() (storage.CollectionManager, error) {
+	manager, ok := c.defaultStore.(storage.CollectionManager)
+	if !ok {
+		return nil, errors.New("collections: the store does not support collections")
+	}
+	return manager, nil
+}
+
+// Create creates a collection and opens its store. Creating a collection
+// that exists is not an error.
+func (c *Client) Create(ctx context.Context, name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if _, err := c.manager(); err != nil {
+		return err
+	}
+	if name == c.defaultName {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stores[name] != nil {
+		return nil
+	}
+	store, err := c.open(name)
+	if err != nil {
+		return fmt.Errorf("collections: open %s: %w", name, err)
+	}
+	c.stores[name] = store
+	return nil
+}
+
+// List returns the names of the collections, sorted.
+func (c *Client) List(ctx context.Context) ([]string, error) {
+	manager, err := c.manager()
+	if err != nil {
+		return nil, err
+	}
+	return manager.ListCollections(ctx)
+}
+
+// Drop closes the store of a collection and permanently deletes it. The
+// default collection cannot be dropped.
+func (c *Client) Drop(ctx context.Context, name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if name == c.defaultName {
+		return fmt.Errorf("collections: cannot drop the default collection %q", name)
+	}
+	manager, err := c.manager()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if store := c.stores[name]; store != nil {
+		delete(c.stores, name)
+		if err := store.Close(); err != nil {
+			return fmt.Errorf("collections: close %s: %w", name, err)
+		}
+	}
+	return manager.DropCollection(ctx, name)
+}
+
+// store returns the store of the collection of ctx, opening it if it exists.
+func (c *Client) store(ctx context.Context) (storage.VectorStore, error) {
+	name := CollectionFromContext(ctx)
+	if name == "" || name == c.defaultName {
+		return c.defaultStore, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if store := c.stores[name]; store != nil {
+		return store, nil
+	}
+
+	// Open existing collections only: opening creates the tables
+	manager, err := c.manager()
+	if err != nil {
+		return nil, err
+	}
+	names, err := manager.ListCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !contains(names, name) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	store, err := c.open(name)
+	if err != nil {
+		return nil, fmt.Errorf("collections: open %s: %w", name, err)
+	}
+	c.stores[name] = store
+	return store, nil
+}
+
+// contains reports whether names contains name.
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Insert inserts a memory into the collection of ctx.
+func (c *Client) Insert(ctx context.Context, memory *storage.Memory) error {
+	store, err := c.store(ctx)
+	if err != nil {
+		return err
+	}
+	return store.Insert(ctx, memory)
+}
+
+// Search performs a vector similarity search in the collection of ctx.
+func (c *Client) Search(ctx context.Context, embedding []float64, opts *storage.SearchOptions) ([]*storage.Memory, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.Search(ctx, embedding, opts)
+}
+
+// SearchIter returns an iterator over the search results of the collection of ctx.
+func (c *Client) SearchIter(ctx context.Context, embedding []float64, opts *storage.SearchOptions, batchSize int) (storage.MemoryIterator, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.SearchIter(ctx, embedding, opts, batchSize)
+}
+
+// SearchByKeyword performs a keyword search in the collection of ctx.
+func (c *Client) SearchByKeyword(ctx context.Context, text string, opts *storage.SearchOptions) ([]*storage.Memory, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.SearchByKeyword(ctx, text, opts)
+}
+
+// Get retrieves a memory of the collection of ctx by ID.
+func (c *Client) Get(ctx context.Context, id int64, opts *storage.GetOptions) (*storage.Memory, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.Get(ctx, id, opts)
+}
+
+// GetByUID retrieves a memory of the collection of ctx by string ID.
+func (c *Client) GetByUID(ctx context.Context, uid string, opts *storage.GetOptions) (*storage.Memory, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.GetByUID(ctx, uid, opts)
+}
+
+// GetByHash retrieves a memory of the collection of ctx by content hash.
+func (c *Client) GetByHash(ctx context.Context, hash string, opts *storage.GetOptions) (*storage.Memory, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.GetByHash(ctx, hash, opts)
+}
+
+// GetMany retrieves memories of the collection of ctx by ID.
+func (c *Client) GetMany(ctx context.Context, ids []int64, opts *storage.GetOptions) ([]*storage.Memory, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.GetMany(ctx, ids, opts)
+}
+
+// Update updates a memory of the collection of ctx.
+func (c *Client) Update(ctx context.Context, id int64, content string, embedding []float64, opts *storage.UpdateOptions) (*storage.Memory, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.Update(ctx, id, content, embedding, opts)
+}
+
+// Delete deletes a memory of the collection of ctx.
+func (c *Client) Delete(ctx context.Context, id int64, opts *storage.DeleteOptions) error {
+	store, err := c.store(ctx)
+	if err != nil {
+		return err
+	}
+	return store.Delete(ctx, id, opts)
+}
+
+// GetAll retrieves the memories of the collection of ctx.
+func (c *Client) GetAll(ctx context.Context, opts *storage.GetAllOptions) ([]*storage.Memory, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.GetAll(ctx, opts)
+}
+
+// ListTags returns the tags in use in the collection of ctx.
+func (c *Client) ListTags(ctx context.Context, userID string) ([]string, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.ListTags(ctx, userID)
+}
+
+// DeleteAll deletes the memories of the collection of ctx matching opts.
+func (c *Client) DeleteAll(ctx context.Context, opts *storage.DeleteAllOptions) error {
+	store, err := c.store(ctx)
+	if err != nil {
+		return err
+	}
+	return store.DeleteAll(ctx, opts)
+}
+
+// DeleteWhere deletes the memories of the collection of ctx matching opts.
+func (c *Client) DeleteWhere(ctx context.Context, opts *storage.DeleteWhereOptions) (int64, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return store.DeleteWhere(ctx, opts)
+}
+
+// PurgeExpired deletes the expired memories of the collection of ctx.
+func (c *Client) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return store.PurgeExpired(ctx, before)
+}
+
+// Ping checks the connection of the default store: all collections share
+// its database.
+func (c *Client) Ping(ctx context.Context) error {
+	return c.defaultStore.Ping(ctx)
+}
+
+// Close closes the stores of every open collection and the default store.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for name, store := range c.stores {
+		if err := store.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("collection %s: %w", name, err))
+		}
+		delete(c.stores, name)
+	}
+	if err := c.defaultStore.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// CreateIndex creates a vector index on the collection of ctx.
+func (c *Client) CreateIndex(ctx context.Context, config *storage.VectorIndexConfig) error {
+	store, err := c.store(ctx)
+	if err != nil {
+		return err
+	}
+	return store.CreateIndex(ctx, config)
+}
+
+// Reset deletes the memories of the collection of ctx.
+func (c *Client) Reset(ctx context.Context) error {
+	store, err := c.store(ctx)
+	if err != nil {
+		return err
+	}
+	return store.Reset(ctx)
+}
+
+// AppendChange records a change in the change log of the collection of ctx.
+func (c *Client) AppendChange(ctx context.Context, change *storage.Change) error {
+	store, err := c.store(ctx)
+	if err != nil {
+		return err
+	}
+	return store.AppendChange(ctx, change)
+}
+
+// ListChanges lists the changes of the collection of ctx.
+func (c *Client) ListChanges(ctx context.Context, opts *storage.ListChangesOptions) ([]*storage.Change, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.ListChanges(ctx, opts)
+}
+
+// LastChangeSeq returns the sequence number of the last change of the
+// collection of ctx.
+func (c *Client) LastChangeSeq(ctx context.Context) (int64, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return store.LastChangeSeq(ctx)
+}
+
+// PurgeChanges deletes the old changes of the collection of ctx.
+func (c *Client) PurgeChanges(ctx context.Context, before time.Time) (int64, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return store.PurgeChanges(ctx, before)
+}
+
+// AppendAudit records an entry in the audit log of the collection of ctx.
+func (c *Client) AppendAudit(ctx context.Context, entry *storage.AuditEntry) error {
+	store, err := c.store(ctx)
+	if err != nil {
+		return err
+	}
+	return store.AppendAudit(ctx, entry)
+}
+
+// ListAudit lists the audit entries of the collection of ctx.
+func (c *Client) ListAudit(ctx context.Context, opts *storage.ListAuditOptions) ([]*storage.AuditEntry, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.ListAudit(ctx, opts)
+}
+
+// Snapshot writes a snapshot of the collection of ctx to path.
+func (c *Client) Snapshot(ctx context.Context, path string) error {
+	snapshotter, err := c.snapshotter(ctx)
+	if err != nil {
+		return err
+	}
+	return snapshotter.Snapshot(ctx, path)
+}
+
+// RestoreSnapshot restores the collection of ctx from the snapshot at path.
+func (c *Client) RestoreSnapshot(ctx context.Context, path string) error {
+	snapshotter, err := c.snapshotter(ctx)
+	if err != nil {
+		return err
+	}
+	return snapshotter.RestoreSnapshot(ctx, path)
+}
+
+// snapshotter returns the store of the collection of ctx as a Snapshotter.
+func (c *Client) snapshotter(ctx context.Context) (storage.Snapshotter, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapshotter, ok := store.(storage.Snapshotter)
+	if !ok {
+		return nil, errors.New("collections: the store does not support snapshots")
+	}
+	return snapshotter, nil
+}
+
+// AddTeamMember adds a user to a team of the collection of ctx.
+func (c *Client) AddTeamMember(ctx context.Context, teamID, userID string) error {
+	teams, err := c.teamStore(ctx)
+	if err != nil {
+		return err
+	}
+	return teams.AddTeamMember(ctx, teamID, userID)
+}
+
+// RemoveTeamMember removes a user from a team of the collection of ctx.
+func (c *Client) RemoveTeamMember(ctx context.Context, teamID, userID string) error {
+	teams, err := c.teamStore(ctx)
+	if err != nil {
+		return err
+	}
+	return teams.RemoveTeamMember(ctx, teamID, userID)
+}
+
+// ListTeamMembers lists the members of a team of the collection of ctx.
+func (c *Client) ListTeamMembers(ctx context.Context, teamID string) ([]string, error) {
+	teams, err := c.teamStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return teams.ListTeamMembers(ctx, teamID)
+}
+
+// ListUserTeams lists the teams of a user in the collection of ctx.
+func (c *Client) ListUserTeams(ctx context.Context, userID string) ([]string, error) {
+	teams, err := c.teamStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return teams.ListUserTeams(ctx, userID)
+}
+
+// teamStore returns the store of the collection of ctx as a TeamStore.
+func (c *Client) teamStore(ctx context.Context) (storage.TeamStore, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	teams, ok := store.(storage.TeamStore)
+	if !ok {
+		return nil, errors.New("collections: the store does not support teams")
+	}
+	return teams, nil
+}
+
+// EraseUser erases the memories and changes of a user in the collection of ctx.
+func (c *Client) EraseUser(ctx context.Context, userID string) (*storage.ErasureCounts, error) {
+	store, err := c.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.EraseUser(ctx, userID)
+}
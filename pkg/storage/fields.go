@@ -0,0 +1,72 @@
+package storage
+
+import "strings"
+
+// Field is a group of Memory fields that reads can leave out to reduce I/O,
+// see SearchOptions.Fields. ID, UserID, AgentID, UID, Version, ParentID,
+// Hash and Score are always read.
+type Field string
+
+const (
+	// FieldContent selects Memory.Content.
+	FieldContent Field = "content"
+
+	// FieldEmbedding selects Memory.Embedding.
+	FieldEmbedding Field = "embedding"
+
+	// FieldMetadata selects Memory.Metadata and the fields read from it:
+	// RunID and, in stores that keep them in metadata, RetentionStrength and
+	// LastAccessedAt.
+	FieldMetadata Field = "metadata"
+
+	// FieldTimestamps selects CreatedAt, UpdatedAt, LastAccessedAt and
+	// ExpiresAt.
+	FieldTimestamps Field = "timestamps"
+
+	// FieldTags selects Memory.Tags.
+	FieldTags Field = "tags"
+)
+
+// AllFields are the fields selected by reads that do not list any.
+var AllFields = []Field{FieldContent, FieldEmbedding, FieldMetadata, FieldTimestamps, FieldTags}
+
+// SelectedFields returns the fields read by a query with the Fields and
+// WithoutEmbeddings of SearchOptions or GetAllOptions: fields, or all of
+// them if fields is empty, without FieldEmbedding if withoutEmbeddings is
+// set.
+func SelectedFields(fields []Field, withoutEmbeddings bool) map[Field]bool {
+	if len(fields) == 0 {
+		fields = AllFields
+	}
+	selected := make(map[Field]bool, len(fields))
+	for _, field := range fields {
+		selected[field] = true
+	}
+	if withoutEmbeddings {
+		delete(selected, FieldEmbedding)
+	}
+	return selected
+}
+
+// SelectColumns returns columns, a comma-separated column list, with the
+// columns of the fields that are not selected replaced by NULL, so that the
+// rows keep their layout. columnFields maps column names to the field that
+// selects them; the other columns are always read.
+//
+// Example:
+//
+//	columns := storage.SelectColumns("id, content, embedding",
+//	    map[string]storage.Field{"content": storage.FieldContent, "embedding": storage.FieldEmbedding},
+//	    storage.SelectedFields(nil, true))
+//	// "id, content, NULL AS omitted_embedding"
+func SelectColumns(columns string, columnFields map[string]Field, selected map[Field]bool) string {
+	parts := strings.Split(columns, ",")
+	for i, part := range parts {
+		column := strings.TrimSpace(part)
+		if field, ok := columnFields[column]; ok && !selected[field] {
+			// Aliased so that clauses naming the column still read the table
+			parts[i] = strings.Replace(part, column, "NULL AS omitted_"+column, 1)
+		}
+	}
+	return strings.Join(parts, ",")
+}
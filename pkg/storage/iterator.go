@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// SearchCursor marks a position in vector search results, which are ordered
+// by Score (highest first) and then by ID (lowest first).
+type SearchCursor struct {
+	// Score is the similarity score of the last memory already returned.
+	Score float64
+
+	// ID is the ID of the last memory already returned.
+	ID int64
+}
+
+// MemoryIterator produces memories in batches.
+type MemoryIterator interface {
+	// Next returns the next non-empty batch of memories, or io.EOF when all
+	// memories have been returned.
+	Next(ctx context.Context) ([]*Memory, error)
+}
+
+// SearchFunc runs a single vector search, such as VectorStore.Search.
+type SearchFunc func(ctx context.Context, embedding []float64, opts *SearchOptions) ([]*Memory, error)
+
+// NewSearchIterator returns an iterator over the results of search, fetching
+// batchSize memories per call with keyset pagination (SearchOptions.After).
+//
+// Only one batch is held in memory at a time. opts.Limit caps the total number
+// of memories returned; zero means no cap. If opts.After is set, iteration
+// starts after that position. opts.Stats is ignored.
+func NewSearchIterator(search SearchFunc, embedding []float64, opts *SearchOptions, batchSize int) (MemoryIterator, error) {
+	if batchSize <= 0 {
+		return nil, errors.New("batch size must be positive")
+	}
+
+	pageOpts := *opts
+	pageOpts.Stats = nil
+
+	return &searchIterator{
+		search:    search,
+		embedding: embedding,
+		opts:      pageOpts,
+		batchSize: batchSize,
+		remaining: opts.Limit,
+	}, nil
+}
+
+// searchIterator implements MemoryIterator on top of a SearchFunc.
+type searchIterator struct {
+	search    SearchFunc
+	embedding []float64
+	opts      SearchOptions
+	batchSize int
+
+	// remaining is the number of memories still allowed by opts.Limit
+	// (<= 0 without a limit).
+	remaining int
+	done      bool
+}
+
+// Next fetches the page after the last memory returned.
+func (it *searchIterator) Next(ctx context.Context) ([]*Memory, error) {
+	if it.done {
+		return nil, io.EOF
+	}
+
+	limit := it.batchSize
+	if it.opts.Limit > 0 && it.remaining < limit {
+		limit = it.remaining
+	}
+	pageOpts := it.opts
+	pageOpts.Limit = limit
+
+	memories, err := it.search(ctx, it.embedding, &pageOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if it.opts.Limit > 0 {
+		it.remaining -= len(memories)
+	}
+	if len(memories) < limit || (it.opts.Limit > 0 && it.remaining <= 0) {
+		it.done = true
+	}
+	if len(memories) == 0 {
+		return nil, io.EOF
+	}
+
+	last := memories[len(memories)-1]
+	it.opts.After = &SearchCursor{Score: last.Score, ID: last.ID}
+	return memories, nil
+}
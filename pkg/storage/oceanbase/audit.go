@@ -0,0 +1,123 @@
+package oceanbase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// auditTable returns the name of the audit log table.
+func (c *Client) auditTable() string {
+	return c.collectionName + "_audit"
+}
+
+// initAuditLog creates the audit log table.
+//
+// created_at is stored like the memories' timestamps (see FormatTimestamp).
+// Oracle mode stores empty strings as NULL, so its actor, user_id and params
+// columns are nullable.
+func (c *Client) initAuditLog(ctx context.Context) error {
+	table := c.quote(c.auditTable())
+	mysqlDDL := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			seq BIGINT AUTO_INCREMENT PRIMARY KEY,
+			operation VARCHAR(64) NOT NULL,
+			actor VARCHAR(255) NOT NULL DEFAULT '',
+			user_id VARCHAR(128) NOT NULL DEFAULT '',
+			params LONGTEXT NOT NULL,
+			created_at VARCHAR(128) NOT NULL
+		)
+	`, table)
+	oracleDDL := fmt.Sprintf(`
+		CREATE TABLE %s (
+			seq NUMBER(19) PRIMARY KEY,
+			operation VARCHAR2(64) NOT NULL,
+			actor VARCHAR2(255),
+			user_id VARCHAR2(128),
+			params CLOB,
+			created_at VARCHAR2(128) NOT NULL
+		)
+	`, table)
+	return c.createTable(ctx, c.auditTable(), mysqlDDL, oracleDDL,
+		fmt.Sprintf("CREATE SEQUENCE %s ORDER", c.quote(seqName(c.auditTable()))))
+}
+
+// AppendAudit appends an entry to the audit log and sets its Seq.
+func (c *Client) AppendAudit(ctx context.Context, entry *storage.AuditEntry) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	seq, err := c.insertSeq(ctx, c.auditTable(),
+		[]string{"operation", "actor", "user_id", "params", "created_at"},
+		entry.Operation, entry.Actor, entry.UserID, string(entry.Params), FormatTimestamp(entry.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("AppendAudit: %w", err)
+	}
+	entry.Seq = seq
+	return nil
+}
+
+// ListAudit returns the audit entries after opts.After, in Seq order.
+func (c *Client) ListAudit(ctx context.Context, opts *storage.ListAuditOptions) ([]*storage.AuditEntry, error) {
+	if opts == nil {
+		opts = &storage.ListAuditOptions{}
+	}
+
+	conditions := []string{"seq > ?"}
+	args := []interface{}{opts.After}
+	if opts.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, opts.Actor)
+	}
+	if opts.UserID != "" {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, opts.UserID)
+	}
+	if len(opts.Operations) > 0 {
+		placeholders := make([]string, len(opts.Operations))
+		for i, op := range opts.Operations {
+			placeholders[i] = "?"
+			args = append(args, op)
+		}
+		conditions = append(conditions, "operation IN ("+strings.Join(placeholders, ", ")+")")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT seq, operation, actor, user_id, params, created_at
+		FROM %s WHERE %s ORDER BY seq
+	`, c.quote(c.auditTable()), strings.Join(conditions, " AND "))
+	if opts.Limit > 0 {
+		query += " " + c.mode.limit(opts.Limit, 0)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListAudit: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []*storage.AuditEntry
+	for rows.Next() {
+		var entry storage.AuditEntry
+		var actor, userID, params sql.NullString
+		var createdAt string
+		if err := rows.Scan(&entry.Seq, &entry.Operation, &actor, &userID, &params, &createdAt); err != nil {
+			return nil, fmt.Errorf("ListAudit: %w", err)
+		}
+		entry.Actor, entry.UserID = actor.String, userID.String
+		entry.Params = []byte(params.String)
+		if t, err := ParseTimestamp(createdAt); err == nil {
+			entry.CreatedAt = t
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListAudit: %w", err)
+	}
+
+	return entries, nil
+}
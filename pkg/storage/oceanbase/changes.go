@@ -0,0 +1,154 @@
+package oceanbase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// changesTable returns the name of the change log table.
+func (c *Client) changesTable() string {
+	return c.collectionName + "_changes"
+}
+
+// initChangeLog creates the change log table.
+//
+// created_at is stored like the memories' timestamps (see FormatTimestamp).
+// Oracle mode stores empty strings as NULL, so its user_id and agent_id
+// columns are nullable.
+func (c *Client) initChangeLog(ctx context.Context) error {
+	table := c.quote(c.changesTable())
+	mysqlDDL := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			seq BIGINT AUTO_INCREMENT PRIMARY KEY,
+			event_type VARCHAR(64) NOT NULL,
+			memory_id BIGINT NOT NULL DEFAULT 0,
+			user_id VARCHAR(128) NOT NULL DEFAULT '',
+			agent_id VARCHAR(128) NOT NULL DEFAULT '',
+			payload LONGTEXT NOT NULL,
+			created_at VARCHAR(128) NOT NULL,
+			INDEX idx_created_at (created_at)
+		)
+	`, table)
+	oracleDDL := fmt.Sprintf(`
+		CREATE TABLE %s (
+			seq NUMBER(19) PRIMARY KEY,
+			event_type VARCHAR2(64) NOT NULL,
+			memory_id NUMBER(19) DEFAULT 0 NOT NULL,
+			user_id VARCHAR2(128),
+			agent_id VARCHAR2(128),
+			payload CLOB,
+			created_at VARCHAR2(128) NOT NULL
+		)
+	`, table)
+	return c.createTable(ctx, c.changesTable(), mysqlDDL, oracleDDL,
+		fmt.Sprintf("CREATE INDEX %s ON %s (created_at)", c.quote(c.changesTable()+"_created_at"), table),
+		fmt.Sprintf("CREATE SEQUENCE %s ORDER", c.quote(seqName(c.changesTable()))))
+}
+
+// AppendChange appends a change to the change log and sets its Seq.
+func (c *Client) AppendChange(ctx context.Context, change *storage.Change) error {
+	if change.CreatedAt.IsZero() {
+		change.CreatedAt = time.Now()
+	}
+	seq, err := c.insertSeq(ctx, c.changesTable(),
+		[]string{"event_type", "memory_id", "user_id", "agent_id", "payload", "created_at"},
+		change.Type, change.MemoryID, change.UserID, change.AgentID, string(change.Payload), FormatTimestamp(change.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("AppendChange: %w", err)
+	}
+	change.Seq = seq
+	return nil
+}
+
+// ListChanges returns the changes after opts.After, in Seq order.
+func (c *Client) ListChanges(ctx context.Context, opts *storage.ListChangesOptions) ([]*storage.Change, error) {
+	if opts == nil {
+		opts = &storage.ListChangesOptions{}
+	}
+
+	conditions := []string{"seq > ?"}
+	args := []interface{}{opts.After}
+	if opts.UserID != "" {
+		conditions = append(conditions, "(user_id = ? OR (memory_id = 0 AND (user_id = '' OR user_id IS NULL)))")
+		args = append(args, opts.UserID)
+	}
+	if opts.AgentID != "" {
+		conditions = append(conditions, "(agent_id = ? OR (memory_id = 0 AND (agent_id = '' OR agent_id IS NULL)))")
+		args = append(args, opts.AgentID)
+	}
+	if len(opts.Types) > 0 {
+		placeholders := make([]string, len(opts.Types))
+		for i, t := range opts.Types {
+			placeholders[i] = "?"
+			args = append(args, t)
+		}
+		conditions = append(conditions, "event_type IN ("+strings.Join(placeholders, ", ")+")")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT seq, event_type, memory_id, user_id, agent_id, payload, created_at
+		FROM %s WHERE %s ORDER BY seq
+	`, c.quote(c.changesTable()), strings.Join(conditions, " AND "))
+	if opts.Limit > 0 {
+		query += " " + c.mode.limit(opts.Limit, 0)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListChanges: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var changes []*storage.Change
+	for rows.Next() {
+		var change storage.Change
+		var userID, agentID, payload sql.NullString
+		var createdAt string
+		if err := rows.Scan(&change.Seq, &change.Type, &change.MemoryID, &userID, &agentID, &payload, &createdAt); err != nil {
+			return nil, fmt.Errorf("ListChanges: %w", err)
+		}
+		change.UserID, change.AgentID = userID.String, agentID.String
+		change.Payload = []byte(payload.String)
+		if t, err := ParseTimestamp(createdAt); err == nil {
+			change.CreatedAt = t
+		}
+		changes = append(changes, &change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListChanges: %w", err)
+	}
+
+	return changes, nil
+}
+
+// LastChangeSeq returns the Seq of the latest change, or 0 if there is none.
+func (c *Client) LastChangeSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	query := fmt.Sprintf("SELECT COALESCE(MAX(seq), 0) FROM %s", c.quote(c.changesTable()))
+	if err := c.db.QueryRowContext(ctx, query).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("LastChangeSeq: %w", err)
+	}
+	return seq, nil
+}
+
+// PurgeChanges deletes the changes recorded before the given time.
+func (c *Client) PurgeChanges(ctx context.Context, before time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE created_at < ?", c.quote(c.changesTable()))
+
+	result, err := c.db.ExecContext(ctx, query, FormatTimestamp(before))
+	if err != nil {
+		return 0, fmt.Errorf("PurgeChanges: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("PurgeChanges: %w", err)
+	}
+
+	return deleted, nil
+}
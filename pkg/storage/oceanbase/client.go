@@ -2,20 +2,66 @@ package oceanbase
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"github.com/oceanbase/powermem-go/pkg/storage"
 )
 
+// memoryColumns returns the column list selected for every memory read.
+// scanMemory expects columns in exactly this order.
+func (c *Client) memoryColumns() string {
+	return `id, user_id, agent_id, run_id, document, embedding, metadata,
+		created_at, updated_at, hash, tags, expires_at, ` + c.quote("uid") + `, version, parent_id`
+}
+
+// columnFields maps the columns of memoryColumns that reads can leave out to
+// the field that selects them.
+var columnFields = map[string]storage.Field{
+	"document":   storage.FieldContent,
+	"embedding":  storage.FieldEmbedding,
+	"metadata":   storage.FieldMetadata,
+	"created_at": storage.FieldTimestamps,
+	"updated_at": storage.FieldTimestamps,
+	"expires_at": storage.FieldTimestamps,
+	"tags":       storage.FieldTags,
+}
+
+// selectColumns returns memoryColumns with NULL in place of the columns of
+// the fields not selected by fields and withoutEmbeddings (see
+// storage.SelectedFields), so that they are not read.
+func (c *Client) selectColumns(fields []storage.Field, withoutEmbeddings bool) string {
+	return storage.SelectColumns(c.memoryColumns(), columnFields, storage.SelectedFields(fields, withoutEmbeddings))
+}
+
 // Client is an OceanBase client.
 type Client struct {
 	db             *sql.DB
+	replicas       *storage.ReplicaSet
 	config         *Config
 	collectionName string
+
+	// tlsName is the name of the TLS configuration registered with the
+	// driver ("" without TLS).
+	tlsName string
+
+	// mode is the compatibility mode of the tenant (see Mode).
+	mode Mode
+
+	// vectorIndex is whether the embeddings are indexed (see VectorIndexed).
+	vectorIndex atomic.Bool
+
+	// fullTextIndex is whether fulltext_content is indexed (see
+	// FullTextIndexed).
+	fullTextIndex atomic.Bool
 }
 
 // Config contains OceanBase configuration.
@@ -27,41 +73,141 @@ type Config struct {
 	DBName             string
 	CollectionName     string
 	EmbeddingModelDims int
+
+	// DSN is the go-sql-driver/mysql data source name of the primary. If
+	// set, it is used instead of Host, Port, User, Password and DBName, and
+	// must enable parseTime.
+	DSN string
+
+	// ReadDSNs are the data source names of read replicas of the primary,
+	// e.g. an OBProxy routing to read-only zones. Search, Get, GetAll and the
+	// other reads are spread over them.
+	ReadDSNs []string
+
+	// TLS, if non-nil, encrypts the connections to the primary and the
+	// replicas, e.g. for clusters that reject plain connections; see
+	// LoadTLSConfig. It replaces the tls parameter of DSN and ReadDSNs.
+	TLS *tls.Config
+
+	// Charset is the character set of the connections, e.g. "utf8mb4".
+	// Empty keeps the charset of the DSNs, or the server default.
+	Charset string
+
+	// ConnectTimeout, ReadTimeout and WriteTimeout are the dial, read and
+	// write timeouts of the connections. Zero keeps the timeouts of the
+	// DSNs, or no timeout.
+	ConnectTimeout time.Duration
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+
+	// VectorIndex, if non-nil, holds the parameters of the HNSW index
+	// created on the embeddings (cosine distance) by NewClient if the table
+	// has no vector index yet. Zero parameters use the server defaults.
+	// EfSearch is also the default search depth of Search, see
+	// storage.SearchOptions.EfSearch.
+	//
+	// On OceanBase versions without vector index support the index is not
+	// created, and Search computes exact distances.
+	VectorIndex *storage.HNSWParams
+
+	// FullTextParser is the parser of the FULLTEXT index created on the
+	// memory content by NewClient, e.g. "ngram" or "ik" for Chinese text.
+	// Empty uses the server default, which splits words on spaces and
+	// punctuation.
+	FullTextParser string
 }
 
 // NewClient creates a new OceanBase client.
+//
+// The statements of the client are written in the SQL dialect of the
+// compatibility mode of the tenant, see DetectMode.
 func NewClient(cfg *Config) (*Client, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true",
-		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName)
+	tlsName, err := registerTLS(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("NewOceanBaseClient: %w", err)
+	}
+	fail := func(err error) (*Client, error) {
+		if tlsName != "" {
+			mysql.DeregisterTLSConfig(tlsName)
+		}
+		return nil, err
+	}
+
+	dsn, err := primaryDSN(cfg, tlsName)
+	if err != nil {
+		return fail(fmt.Errorf("NewOceanBaseClient: %w", err))
+	}
 
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
-		return nil, fmt.Errorf("NewOceanBaseClient: %w", err)
+		return fail(fmt.Errorf("NewOceanBaseClient: %w", err))
 	}
 
 	// Test connection
 	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("NewOceanBaseClient: %w", err)
+		_ = db.Close()
+		return fail(fmt.Errorf("NewOceanBaseClient: %w", err))
+	}
+
+	// Replicas are checked before they serve reads, so an unavailable
+	// replica does not prevent the client from starting.
+	var replicas []*sql.DB
+	for _, readDSN := range cfg.ReadDSNs {
+		readDSN, err := withConnOptions(readDSN, cfg, tlsName)
+		if err != nil {
+			_ = storage.NewReplicaSet(db, replicas, 0, nil).Close()
+			return fail(fmt.Errorf("NewOceanBaseClient: replica: %w", err))
+		}
+		replica, err := sql.Open("mysql", readDSN)
+		if err != nil {
+			_ = storage.NewReplicaSet(db, replicas, 0, nil).Close()
+			return fail(fmt.Errorf("NewOceanBaseClient: replica: %w", err))
+		}
+		replicas = append(replicas, replica)
 	}
 
 	client := &Client{
 		db:             db,
+		replicas:       storage.NewReplicaSet(db, replicas, 0, nil),
 		config:         cfg,
 		collectionName: cfg.CollectionName,
+		tlsName:        tlsName,
+		mode:           DetectMode(context.Background(), db),
 	}
 
 	// Initialize table structure
 	if err := client.initTables(context.Background()); err != nil {
+		_ = client.Close()
 		return nil, err
 	}
 
 	return client, nil
 }
 
+// NewClientWithDB creates an OceanBase client on db, a connection pool
+// opened by the caller, e.g. with a connector of its own. The connection
+// options of cfg (DSN, ReadDSNs, TLS, Charset and the timeouts) are not
+// used, and Close closes db.
+func NewClientWithDB(db *sql.DB, cfg *Config) (*Client, error) {
+	client := &Client{
+		db:             db,
+		replicas:       storage.NewReplicaSet(db, nil, 0, nil),
+		config:         cfg,
+		collectionName: cfg.CollectionName,
+		mode:           DetectMode(context.Background(), db),
+	}
+	if err := client.initTables(context.Background()); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
 // initTables initializes the database table.
 // Compatible with Python SDK table structure
 func (c *Client) initTables(ctx context.Context) error {
-	query := fmt.Sprintf(`
+	table := c.quote(c.collectionName)
+	mysqlDDL := fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS %s (
 			id BIGINT PRIMARY KEY,
 			embedding VECTOR(%d),
@@ -76,26 +222,162 @@ func (c *Client) initTables(ctx context.Context) error {
 			updated_at VARCHAR(128),
 			category VARCHAR(64),
 			fulltext_content LONGTEXT,
-			INDEX idx_user_agent (user_id, agent_id)
+			tags JSON,
+			expires_at VARCHAR(128),
+			uid VARCHAR(64),
+			version BIGINT NOT NULL DEFAULT 1,
+			parent_id BIGINT,
+			INDEX idx_user_agent (user_id, agent_id),
+			INDEX idx_parent_id (parent_id),
+			INDEX idx_user_hash (user_id, hash),
+			UNIQUE INDEX idx_uid (uid)
 		)
-	`, c.collectionName, c.config.EmbeddingModelDims)
+	`, table, c.config.EmbeddingModelDims)
 
-	_, err := c.db.ExecContext(ctx, query)
+	// Index names are per schema in Oracle mode, so they name the table
+	oracleDDL := fmt.Sprintf(`
+		CREATE TABLE %s (
+			id NUMBER(19),
+			embedding VECTOR(%d),
+			document CLOB,
+			metadata JSON,
+			user_id VARCHAR2(128),
+			agent_id VARCHAR2(128),
+			run_id VARCHAR2(128),
+			actor_id VARCHAR2(128),
+			hash VARCHAR2(32),
+			created_at VARCHAR2(128),
+			updated_at VARCHAR2(128),
+			category VARCHAR2(64),
+			fulltext_content CLOB,
+			tags JSON,
+			expires_at VARCHAR2(128),
+			%s VARCHAR2(64),
+			version NUMBER(19) DEFAULT 1 NOT NULL,
+			parent_id NUMBER(19),
+			CONSTRAINT %s PRIMARY KEY (id)
+		)
+	`, table, c.config.EmbeddingModelDims, c.quote("uid"), c.quote(c.collectionName+"_primary"))
+	oracleIndexes := []string{
+		fmt.Sprintf("CREATE INDEX %s ON %s (user_id, agent_id)", c.quote(c.collectionName+"_user_agent"), table),
+		fmt.Sprintf("CREATE INDEX %s ON %s (parent_id)", c.quote(c.collectionName+"_parent_id"), table),
+		fmt.Sprintf("CREATE INDEX %s ON %s (user_id, hash)", c.quote(c.collectionName+"_user_hash"), table),
+		fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s)", c.quote(c.collectionName+"_uid"), table, c.quote("uid")),
+	}
+
+	if err := c.createTable(ctx, c.collectionName, mysqlDDL, oracleDDL, oracleIndexes...); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
+	if err := c.initChangeLog(ctx); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
+	if err := c.initAuditLog(ctx); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
+	if err := c.initTeams(ctx); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
+	// Oracle-mode tables are created with the current schema by this
+	// version, and have no vector or full-text index (see ModeOracle)
+	if c.mode == ModeOracle {
+		return nil
+	}
+
+	// Add columns introduced after the original schema to existing tables
+	if err := c.ensureColumn(ctx, "tags", "JSON"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+	if err := c.ensureColumn(ctx, "expires_at", "VARCHAR(128)"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+	if err := c.ensureColumn(ctx, "uid", "VARCHAR(64) UNIQUE"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+	if err := c.ensureColumn(ctx, "version", "BIGINT NOT NULL DEFAULT 1"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+	if err := c.ensureColumn(ctx, "parent_id", "BIGINT"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
+	// Index to find the memories of a user by content hash
+	if err := c.ensureIndex(ctx, "idx_user_hash", "user_id, hash"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
+	// Rewrite timestamps written by earlier versions or the Python SDK
+	if err := NormalizeTimestamps(ctx, c.db, c.collectionName, "id", "created_at", "updated_at", "expires_at"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+	if err := NormalizeTimestamps(ctx, c.db, c.changesTable(), "seq", "created_at"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+	if err := NormalizeTimestamps(ctx, c.db, c.auditTable(), "seq", "created_at"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
+	if err := c.initVectorIndex(ctx); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
+	indexed, err := InitFullTextIndex(ctx, c.db, c.collectionName, c.config.FullTextParser)
 	if err != nil {
 		return fmt.Errorf("initTables: %w", err)
 	}
+	c.fullTextIndex.Store(indexed)
 
 	return nil
 }
 
+// ensureColumn adds a column to the memories table if it does not exist yet.
+func (c *Client) ensureColumn(ctx context.Context, name, definition string) error {
+	var count int
+	err := c.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?
+	`, c.collectionName, name).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	_, err = c.db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.quote(c.collectionName), name, definition))
+	return err
+}
+
+// ensureIndex creates an index on columns of the memories table if the table
+// has no index with that name yet.
+func (c *Client) ensureIndex(ctx context.Context, name, columns string) error {
+	var count int
+	err := c.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND INDEX_NAME = ?
+	`, c.collectionName, name).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	_, err = c.db.ExecContext(ctx, fmt.Sprintf("CREATE INDEX %s ON %s (%s)", name, c.quote(c.collectionName), columns))
+	return err
+}
+
 // Insert inserts a memory.
 // Compatible with Python SDK: uses 'document' field instead of 'content'
 func (c *Client) Insert(ctx context.Context, memory *storage.Memory) error {
 	query := fmt.Sprintf(`
 		INSERT INTO %s 
-		(id, user_id, agent_id, document, embedding, metadata, created_at, updated_at, hash)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, c.collectionName)
+		(id, user_id, agent_id, run_id, document, fulltext_content, embedding, metadata, created_at, updated_at, version, hash, tags, expires_at, %s, parent_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, c.quote(c.collectionName), c.quote("uid"))
 
 	vectorStr := vectorToString(memory.Embedding)
 
@@ -113,23 +395,51 @@ func (c *Client) Insert(ctx context.Context, memory *storage.Memory) error {
 		return fmt.Errorf("Insert: %w", err)
 	}
 
-	// Generate hash for content (compatible with Python SDK)
-	hash := generateHash(memory.Content)
+	hash := storage.ContentHash(memory.Content)
+
+	tagsJSON, err := marshalTags(memory.Tags)
+	if err != nil {
+		return fmt.Errorf("Insert: %w", err)
+	}
+
+	var expiresAt interface{}
+	if memory.ExpiresAt != nil {
+		expiresAt = FormatTimestamp(*memory.ExpiresAt)
+	}
 
-	now := time.Now().Format(time.RFC3339)
+	createdAt, updatedAt, version := storage.InsertTimes(memory, time.Now())
+
+	// Also store the run ID in its own column, as the Python SDK does
+	var runID interface{}
+	if id, ok := metadataMap[storage.RunIDKey].(string); ok && id != "" {
+		runID = id
+	}
 
 	_, err = c.db.ExecContext(ctx, query,
 		memory.ID,
 		memory.UserID,
 		memory.AgentID,
+		runID,
+		memory.Content,
 		memory.Content,
 		vectorStr,
 		metadataJSON,
-		now,
-		now,
+		FormatTimestamp(createdAt),
+		FormatTimestamp(updatedAt),
+		version,
 		hash,
+		tagsJSON,
+		expiresAt,
+		nullableUID(memory.UID),
+		nullableParentID(memory.ParentID),
 	)
 
+	if isDuplicateID(err) {
+		return fmt.Errorf("Insert: %w: %v", storage.ErrDuplicateID, err)
+	}
+	if isDuplicateUID(err) {
+		return fmt.Errorf("Insert: %w: %v", storage.ErrDuplicateUID, err)
+	}
 	if err != nil {
 		return fmt.Errorf("Insert: %w", err)
 	}
@@ -137,19 +447,71 @@ func (c *Client) Insert(ctx context.Context, memory *storage.Memory) error {
 	return nil
 }
 
+// Error numbers of unique key violations: ER_DUP_ENTRY in MySQL mode, and
+// ORA-00001 in Oracle mode.
+const (
+	errDuplicateEntry   = 1062
+	oraUniqueConstraint = 1
+)
+
+// duplicateEntry returns the message of err if it is a unique key
+// violation.
+func duplicateEntry(err error) (string, bool) {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return "", false
+	}
+	if mysqlErr.Number != errDuplicateEntry && mysqlErr.Number != oraUniqueConstraint {
+		return "", false
+	}
+	return mysqlErr.Message, true
+}
+
+// isDuplicateID reports whether err is the error of an insert whose ID is
+// already taken: a duplicate entry for the primary key, named PRIMARY in
+// MySQL mode and <table>_PRIMARY in Oracle mode.
+func isDuplicateID(err error) bool {
+	message, ok := duplicateEntry(err)
+	return ok && strings.Contains(message, "PRIMARY")
+}
+
+// isDuplicateUID reports whether err is the error of an insert whose UID is
+// already taken: a duplicate entry for the unique index of the uid column,
+// named idx_uid, or uid when the column was added to an existing table, in
+// MySQL mode, and <table>_UID in Oracle mode.
+func isDuplicateUID(err error) bool {
+	message, ok := duplicateEntry(err)
+	return ok && (strings.HasSuffix(message, "uid'") || strings.Contains(message, "_UID)"))
+}
+
 // Search performs vector search.
 //
 // Compatible with Python SDK: uses 'document' field for content storage.
 //
-// The method supports hybrid search parameters for future enhancement:
-//   - opts.Query: Original query text (reserved for full-text search)
+// The method supports hybrid search parameters:
+//   - opts.Query: Original query text, matched against the FULLTEXT index if opts.Hybrid is set
 //   - opts.SparseEmbedding: Sparse vector (reserved for hybrid retrieval)
 //   - opts.Threshold: Minimum similarity score (alias for MinScore)
 //
-// Currently, only vector similarity search is implemented using OceanBase's cosine_distance.
-// Hybrid search (vector + full-text + sparse) will be added in future versions when
-// OceanBase supports additional retrieval modes.
+// Vector similarity is computed with OceanBase's cosine_distance. If the
+// embeddings are indexed (see VectorIndexed), the first page of results
+// (opts.After is nil) is an approximate search using the HNSW index; other
+// pages are exact, since keyset pagination needs an exact order.
+//
+// If opts.Hybrid is set and the memory content is indexed (see
+// FullTextIndexed), the first page of results also includes full-text
+// matches of opts.Query, ranked by a weighted sum of vector similarity and
+// text relevance (see hybridSearch).
 func (c *Client) Search(ctx context.Context, embedding []float64, opts *storage.SearchOptions) ([]*storage.Memory, error) {
+	if opts.Hybrid && opts.Query != "" && opts.After == nil && c.fullTextIndex.Load() {
+		return c.hybridSearch(ctx, embedding, opts)
+	}
+	return c.search(ctx, embedding, opts, c.vectorIndex.Load() && opts.After == nil)
+}
+
+// search performs vector similarity search, using the HNSW index if
+// approximate is true.
+func (c *Client) search(ctx context.Context, embedding []float64, opts *storage.SearchOptions, approximate bool) ([]*storage.Memory, error) {
 	// Use Threshold if MinScore is not set (Python SDK compatibility)
 	minScore := opts.MinScore
 	if minScore == 0 && opts.Threshold > 0 {
@@ -158,7 +520,16 @@ func (c *Client) Search(ctx context.Context, embedding []float64, opts *storage.
 
 	queryVectorStr := vectorToString(embedding)
 
-	whereClause, args := buildWhereClause(opts.UserID, opts.AgentID, opts.Filters)
+	filter := whereFilter{
+		userID:    opts.UserID,
+		teamIDs:   opts.TeamIDs,
+		agentID:   opts.AgentID,
+		filters:   opts.Filters,
+		timeRange: opts.TimeRange,
+		tags:      opts.Tags,
+		activeAt:  storage.ActiveAt(opts.Now),
+	}
+	whereClause, args := c.buildWhereClause(filter)
 
 	// Add similarity threshold filter if specified
 	if minScore > 0 {
@@ -171,28 +542,46 @@ func (c *Client) Search(ctx context.Context, embedding []float64, opts *storage.
 		args = append(args, queryVectorStr, minScore)
 	}
 
+	// Keyset pagination: only memories ranked after the cursor
+	if opts.After != nil {
+		condition := "(1 - cosine_distance(embedding, ?) < ? OR (1 - cosine_distance(embedding, ?) = ? AND id > ?))"
+		if whereClause == "" {
+			whereClause = "WHERE " + condition
+		} else {
+			whereClause += " AND " + condition
+		}
+		args = append(args, queryVectorStr, opts.After.Score, queryVectorStr, opts.After.Score, opts.After.ID)
+	}
+
+	// Ties are broken by ID so that results have a stable order for paging.
+	// The HNSW index is only used for an approximate order by distance.
+	orderBy := "distance ASC, id ASC"
+	if approximate {
+		orderBy = "cosine_distance(embedding, ?) APPROXIMATE"
+	}
 	query := fmt.Sprintf(`
-		SELECT 
-			id, user_id, agent_id, run_id, document, embedding, metadata,
-			created_at, updated_at, hash,
+		SELECT %s,
 			cosine_distance(embedding, ?) as distance
 		FROM %s
 		%s
-		ORDER BY distance ASC
-		LIMIT ?
-	`, c.collectionName, whereClause)
+		ORDER BY %s
+		%s
+	`, c.selectColumns(opts.Fields, opts.WithoutEmbeddings), c.quote(c.collectionName), whereClause, orderBy, c.mode.limit(opts.Limit, 0))
+
+	db := c.replicas.Reader(ctx)
+	if opts.Stats != nil {
+		if err := c.searchStats(ctx, db, filter, whereClause, args, minScore > 0, opts.Stats); err != nil {
+			return nil, fmt.Errorf("Search: %w", err)
+		}
+	}
 
-	// Build args: query vector (for SELECT and distance), then filter args, then limit
+	// Build args: query vector (for SELECT and distance), then filter args,
+	// then the query vector of the approximate order
 	allArgs := []interface{}{queryVectorStr}
 	allArgs = append(allArgs, args...)
-	allArgs = append(allArgs, opts.Limit)
-
-	// TODO: Future enhancement - add full-text search support using opts.Query
-	// This would enable hybrid retrieval combining vector similarity and keyword matching
-	// if opts.Query != "" {
-	//     // Add full-text search condition to WHERE clause
-	//     // Combine vector distance with text relevance scoring
-	// }
+	if approximate {
+		allArgs = append(allArgs, queryVectorStr)
+	}
 
 	// TODO: Future enhancement - add sparse embedding support using opts.SparseEmbedding
 	// This would enable sparse + dense hybrid retrieval
@@ -201,9 +590,103 @@ func (c *Client) Search(ctx context.Context, embedding []float64, opts *storage.
 	//     // Combine with dense vector score
 	// }
 
-	rows, err := c.db.QueryContext(ctx, query, allArgs...)
+	var rows *sql.Rows
+	var err error
+	if efSearch := c.efSearch(opts); approximate && efSearch > 0 {
+		var release func()
+		rows, release, err = queryEfSearch(ctx, db, efSearch, query, allArgs...)
+		if err != nil {
+			return nil, fmt.Errorf("Search: %w", err)
+		}
+		defer release()
+	} else {
+		rows, err = db.QueryContext(ctx, query, allArgs...)
+		if err != nil {
+			return nil, fmt.Errorf("Search: %w", err)
+		}
+	}
+	defer func() { _ = rows.Close() }()
+
+	return c.scanMemories(rows, true)
+}
+
+// SearchIter performs vector similarity search, fetching results from the
+// database one batch at a time.
+//
+// Batches are fetched with exact searches, as they are paged; opts.Hybrid is
+// ignored.
+func (c *Client) SearchIter(ctx context.Context, embedding []float64, opts *storage.SearchOptions, batchSize int) (storage.MemoryIterator, error) {
+	exact := func(ctx context.Context, embedding []float64, opts *storage.SearchOptions) ([]*storage.Memory, error) {
+		return c.search(ctx, embedding, opts, false)
+	}
+	return storage.NewSearchIterator(exact, embedding, opts, batchSize)
+}
+
+// searchStats fills stats for a Search call, reading from db. scoredWhere and scoredArgs are the
+// WHERE clause and arguments of the search, including the similarity threshold
+// if hasThreshold is true.
+func (c *Client) searchStats(ctx context.Context, db *sql.DB, filter whereFilter, scoredWhere string, scoredArgs []interface{}, hasThreshold bool, stats *storage.SearchStats) error {
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s", c.quote(c.collectionName))
+	if err := db.QueryRowContext(ctx, countQuery).Scan(&stats.TotalMemories); err != nil {
+		return err
+	}
+
+	whereClause, args := c.buildWhereClause(filter)
+	countQuery = fmt.Sprintf("SELECT COUNT(*) FROM %s %s", c.quote(c.collectionName), whereClause)
+	if err := db.QueryRowContext(ctx, countQuery, args...).Scan(&stats.Candidates); err != nil {
+		return err
+	}
+
+	if !hasThreshold {
+		stats.AboveThreshold = stats.Candidates
+		return nil
+	}
+	countQuery = fmt.Sprintf("SELECT COUNT(*) FROM %s %s", c.quote(c.collectionName), scoredWhere)
+	return db.QueryRowContext(ctx, countQuery, scoredArgs...).Scan(&stats.AboveThreshold)
+}
+
+// SearchByKeyword performs a keyword search on memory content using LIKE.
+//
+// With the default utf8mb4 collation, or in Oracle mode, the comparison is
+// case-insensitive.
+func (c *Client) SearchByKeyword(ctx context.Context, text string, opts *storage.SearchOptions) ([]*storage.Memory, error) {
+	whereClause, args := c.buildWhereClause(whereFilter{
+		userID:    opts.UserID,
+		teamIDs:   opts.TeamIDs,
+		agentID:   opts.AgentID,
+		filters:   opts.Filters,
+		timeRange: opts.TimeRange,
+		tags:      opts.Tags,
+		activeAt:  storage.ActiveAt(opts.Now),
+
+		excludeChunks: true,
+	})
+
+	// Oracle-mode comparisons are case-sensitive, and LIKE has no default
+	// escape character
+	like := "document LIKE ?"
+	if c.mode == ModeOracle {
+		like = `LOWER(document) LIKE LOWER(?) ESCAPE '\'`
+	}
+	if whereClause == "" {
+		whereClause = "WHERE " + like
+	} else {
+		whereClause += " AND " + like
+	}
+	args = append(args, "%"+escapeLikePattern(text)+"%")
+
+	query := fmt.Sprintf(`
+		SELECT %s,
+		       0 as distance
+		FROM %s
+		%s
+		ORDER BY id DESC
+		%s
+	`, c.selectColumns(opts.Fields, opts.WithoutEmbeddings), c.quote(c.collectionName), whereClause, c.mode.limit(opts.Limit, 0))
+
+	rows, err := c.replicas.Reader(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("Search: %w", err)
+		return nil, fmt.Errorf("SearchByKeyword: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
@@ -213,13 +696,28 @@ func (c *Client) Search(ctx context.Context, embedding []float64, opts *storage.
 // Get retrieves a memory by ID with optional access control.
 // Compatible with Python SDK: uses 'document' field
 func (c *Client) Get(ctx context.Context, id int64, opts *storage.GetOptions) (*storage.Memory, error) {
+	return c.getOne(ctx, "Get", "id", id, opts)
+}
+
+// GetByUID retrieves a memory by its string ID with optional access control.
+func (c *Client) GetByUID(ctx context.Context, uid string, opts *storage.GetOptions) (*storage.Memory, error) {
+	return c.getOne(ctx, "GetByUID", "uid", uid, opts)
+}
+
+// GetByHash retrieves a memory by content hash with optional access control.
+func (c *Client) GetByHash(ctx context.Context, hash string, opts *storage.GetOptions) (*storage.Memory, error) {
+	return c.getOne(ctx, "GetByHash", "hash", hash, opts)
+}
+
+// getOne retrieves the memory whose key column equals value.
+func (c *Client) getOne(ctx context.Context, op, column string, value interface{}, opts *storage.GetOptions) (*storage.Memory, error) {
 	if opts == nil {
 		opts = &storage.GetOptions{}
 	}
 
 	// Build WHERE clause with access control
-	whereClause := "WHERE id = ?"
-	args := []interface{}{id}
+	whereClause := fmt.Sprintf("WHERE %s = ?", c.quote(column))
+	args := []interface{}{value}
 
 	if opts.UserID != "" {
 		whereClause += " AND user_id = ?"
@@ -230,26 +728,122 @@ func (c *Client) Get(ctx context.Context, id int64, opts *storage.GetOptions) (*
 		args = append(args, opts.AgentID)
 	}
 
+	// Expired memories are treated as not found
+	whereClause += " AND (expires_at IS NULL OR expires_at > ?)"
+	args = append(args, FormatTimestamp(storage.ActiveAt(opts.Now)))
+
 	query := fmt.Sprintf(`
-		SELECT id, user_id, agent_id, run_id, document, embedding, metadata,
-		       created_at, updated_at, hash
+		SELECT %s
 		FROM %s
 		%s
-	`, c.collectionName, whereClause)
+	`, c.memoryColumns(), c.quote(c.collectionName), whereClause)
 
-	row := c.db.QueryRowContext(ctx, query, args...)
+	row := c.replicas.Reader(ctx).QueryRowContext(ctx, query, args...)
 
 	memory, err := c.scanMemory(row)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("Get: not found or access denied")
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrNotFound)
 	}
 	if err != nil {
-		return nil, fmt.Errorf("Get: %w", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return memory, nil
 }
 
+// ListTags returns the distinct tags in use, sorted alphabetically.
+//
+// If userID is empty, tags across all users are returned.
+func (c *Client) ListTags(ctx context.Context, userID string) ([]string, error) {
+	whereClause, args := c.buildWhereClause(whereFilter{userID: userID, activeAt: time.Now()})
+	if whereClause == "" {
+		whereClause = "WHERE tags IS NOT NULL"
+	} else {
+		whereClause += " AND tags IS NOT NULL"
+	}
+
+	query := fmt.Sprintf(`SELECT tags FROM %s %s`, c.quote(c.collectionName), whereClause)
+
+	rows, err := c.replicas.Reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListTags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var tagsJSON []byte
+		if err := rows.Scan(&tagsJSON); err != nil {
+			return nil, fmt.Errorf("ListTags: %w", err)
+		}
+		var tags []string
+		if err := json.Unmarshal(tagsJSON, &tags); err != nil {
+			return nil, fmt.Errorf("ListTags: %w", err)
+		}
+		for _, tag := range tags {
+			seen[tag] = true
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListTags: %w", err)
+	}
+
+	tags := make([]string, 0, len(seen))
+	for tag := range seen {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	return tags, nil
+}
+
+// GetMany retrieves multiple memories by ID using a single IN query.
+func (c *Client) GetMany(ctx context.Context, ids []int64, opts *storage.GetOptions) ([]*storage.Memory, error) {
+	if opts == nil {
+		opts = &storage.GetOptions{}
+	}
+	if len(ids) == 0 {
+		return []*storage.Memory{}, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, 0, len(ids)+2)
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	whereClause := fmt.Sprintf("WHERE id IN (%s)", strings.Join(placeholders, ", "))
+
+	if opts.UserID != "" {
+		whereClause += " AND user_id = ?"
+		args = append(args, opts.UserID)
+	}
+	if opts.AgentID != "" {
+		whereClause += " AND agent_id = ?"
+		args = append(args, opts.AgentID)
+	}
+
+	// Expired memories are treated as not found
+	whereClause += " AND (expires_at IS NULL OR expires_at > ?)"
+	args = append(args, FormatTimestamp(storage.ActiveAt(opts.Now)))
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		%s
+	`, c.memoryColumns(), c.quote(c.collectionName), whereClause)
+
+	rows, err := c.replicas.Reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("GetMany: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return c.scanMemories(rows, false)
+}
+
 // Update updates a memory with optional access control.
 // Compatible with Python SDK: uses 'document' field
 func (c *Client) Update(ctx context.Context, id int64, content string, embedding []float64, opts *storage.UpdateOptions) (*storage.Memory, error) {
@@ -258,12 +852,78 @@ func (c *Client) Update(ctx context.Context, id int64, content string, embedding
 	}
 
 	vectorStr := vectorToString(embedding)
-	hash := generateHash(content)
-	now := time.Now().Format(time.RFC3339)
+	hash := storage.ContentHash(content)
+	now := FormatTimestamp(storage.UpdateTime(opts))
+
+	// created_at is intentionally never part of the SET clause
+	setClause := "SET document = ?, fulltext_content = ?, embedding = ?, updated_at = ?, hash = ?, version = version + 1"
+	args := []interface{}{content, content, vectorStr, now, hash}
+
+	if opts.Metadata != nil || opts.RetentionStrength != nil || opts.LastAccessedAt != nil {
+		// retention_strength and last_accessed_at live inside metadata on
+		// OceanBase, so carry them over unless the caller replaced them.
+		var existing *storage.Memory
+		getExisting := func() (*storage.Memory, error) {
+			if existing == nil {
+				var err error
+				existing, err = c.Get(ctx, id, &storage.GetOptions{UserID: opts.UserID, AgentID: opts.AgentID})
+				if err != nil {
+					return nil, fmt.Errorf("Update: %w", storage.ErrNotFound)
+				}
+			}
+			return existing, nil
+		}
+
+		metadata := opts.Metadata
+		if metadata == nil {
+			current, err := getExisting()
+			if err != nil {
+				return nil, err
+			}
+			metadata = current.Metadata
+		}
+		metadataMap := make(map[string]interface{}, len(metadata)+2)
+		for k, v := range metadata {
+			metadataMap[k] = v
+		}
+
+		if opts.RetentionStrength != nil {
+			metadataMap["retention_strength"] = *opts.RetentionStrength
+		} else if _, ok := metadataMap["retention_strength"]; !ok {
+			current, err := getExisting()
+			if err != nil {
+				return nil, err
+			}
+			if current.RetentionStrength > 0 {
+				metadataMap["retention_strength"] = current.RetentionStrength
+			}
+		}
+		if opts.LastAccessedAt != nil {
+			metadataMap["last_accessed_at"] = FormatTimestamp(*opts.LastAccessedAt)
+		} else if _, ok := metadataMap["last_accessed_at"]; !ok {
+			current, err := getExisting()
+			if err != nil {
+				return nil, err
+			}
+			if current.LastAccessedAt != nil {
+				metadataMap["last_accessed_at"] = FormatTimestamp(*current.LastAccessedAt)
+			}
+		}
+
+		metadataJSON, err := json.Marshal(metadataMap)
+		if err != nil {
+			return nil, fmt.Errorf("Update: %w", err)
+		}
+		setClause += ", metadata = ?"
+		args = append(args, metadataJSON)
+	}
+	if opts.ClearExpiresAt {
+		setClause += ", expires_at = NULL"
+	}
 
 	// Build WHERE clause with access control
 	whereClause := "WHERE id = ?"
-	args := []interface{}{content, vectorStr, now, hash, id}
+	args = append(args, id)
 
 	if opts.UserID != "" {
 		whereClause += " AND user_id = ?"
@@ -273,12 +933,16 @@ func (c *Client) Update(ctx context.Context, id int64, content string, embedding
 		whereClause += " AND agent_id = ?"
 		args = append(args, opts.AgentID)
 	}
+	if opts.ExpectedVersion > 0 {
+		whereClause += " AND version = ?"
+		args = append(args, opts.ExpectedVersion)
+	}
 
 	query := fmt.Sprintf(`
 		UPDATE %s
-		SET document = ?, embedding = ?, updated_at = ?, hash = ?
 		%s
-	`, c.collectionName, whereClause)
+		%s
+	`, c.quote(c.collectionName), setClause, whereClause)
 
 	result, err := c.db.ExecContext(ctx, query, args...)
 	if err != nil {
@@ -291,7 +955,7 @@ func (c *Client) Update(ctx context.Context, id int64, content string, embedding
 	}
 
 	if rowsAffected == 0 {
-		return nil, fmt.Errorf("Update: not found or access denied")
+		return nil, c.updateMissError(ctx, id, opts)
 	}
 
 	// Return updated memory
@@ -301,6 +965,17 @@ func (c *Client) Update(ctx context.Context, id int64, content string, embedding
 	})
 }
 
+// updateMissError explains why Update matched no row: a version conflict if
+// the memory is still accessible, "not found or access denied" otherwise.
+func (c *Client) updateMissError(ctx context.Context, id int64, opts *storage.UpdateOptions) error {
+	if opts.ExpectedVersion > 0 {
+		if _, err := c.Get(storage.ContextWithPrimary(ctx), id, &storage.GetOptions{UserID: opts.UserID, AgentID: opts.AgentID}); err == nil {
+			return fmt.Errorf("Update: %w", storage.ErrVersionConflict)
+		}
+	}
+	return fmt.Errorf("Update: %w", storage.ErrNotFound)
+}
+
 // Delete deletes a memory with optional access control.
 func (c *Client) Delete(ctx context.Context, id int64, opts *storage.DeleteOptions) error {
 	if opts == nil {
@@ -320,7 +995,7 @@ func (c *Client) Delete(ctx context.Context, id int64, opts *storage.DeleteOptio
 		args = append(args, opts.AgentID)
 	}
 
-	query := fmt.Sprintf("DELETE FROM %s %s", c.collectionName, whereClause)
+	query := fmt.Sprintf("DELETE FROM %s %s", c.quote(c.collectionName), whereClause)
 
 	result, err := c.db.ExecContext(ctx, query, args...)
 	if err != nil {
@@ -333,7 +1008,7 @@ func (c *Client) Delete(ctx context.Context, id int64, opts *storage.DeleteOptio
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("Delete: not found or access denied")
+		return fmt.Errorf("Delete: %w", storage.ErrNotFound)
 	}
 
 	return nil
@@ -342,20 +1017,29 @@ func (c *Client) Delete(ctx context.Context, id int64, opts *storage.DeleteOptio
 // GetAll retrieves all memories.
 // Compatible with Python SDK: uses 'document' field
 func (c *Client) GetAll(ctx context.Context, opts *storage.GetAllOptions) ([]*storage.Memory, error) {
-	whereClause, args := buildWhereClause(opts.UserID, opts.AgentID, nil)
+	whereClause, args := c.buildWhereClause(whereFilter{
+		userID:    opts.UserID,
+		teamIDs:   opts.TeamIDs,
+		agentID:   opts.AgentID,
+		timeRange: opts.TimeRange,
+		tags:      opts.Tags,
+		filters:   opts.Filters,
+		entities:  opts.Entities,
+		activeAt:  storage.ActiveAt(opts.Now),
+
+		parentID:      opts.ParentID,
+		excludeChunks: true,
+	})
 
 	query := fmt.Sprintf(`
-		SELECT id, user_id, agent_id, run_id, document, embedding, metadata,
-		       created_at, updated_at, hash
+		SELECT %s
 		FROM %s
 		%s
 		ORDER BY id DESC
-		LIMIT ? OFFSET ?
-	`, c.collectionName, whereClause)
-
-	args = append(args, opts.Limit, opts.Offset)
+		%s
+	`, c.selectColumns(opts.Fields, opts.WithoutEmbeddings), c.quote(c.collectionName), whereClause, c.mode.limit(opts.Limit, opts.Offset))
 
-	rows, err := c.db.QueryContext(ctx, query, args...)
+	rows, err := c.replicas.Reader(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("GetAll: %w", err)
 	}
@@ -366,9 +1050,9 @@ func (c *Client) GetAll(ctx context.Context, opts *storage.GetAllOptions) ([]*st
 
 // DeleteAll deletes all memories.
 func (c *Client) DeleteAll(ctx context.Context, opts *storage.DeleteAllOptions) error {
-	whereClause, args := buildWhereClause(opts.UserID, opts.AgentID, nil)
+	whereClause, args := c.buildWhereClause(whereFilter{userID: opts.UserID, agentID: opts.AgentID, parentID: opts.ParentID})
 
-	query := fmt.Sprintf("DELETE FROM %s %s", c.collectionName, whereClause)
+	query := fmt.Sprintf("DELETE FROM %s %s", c.quote(c.collectionName), whereClause)
 
 	_, err := c.db.ExecContext(ctx, query, args...)
 	if err != nil {
@@ -378,32 +1062,57 @@ func (c *Client) DeleteAll(ctx context.Context, opts *storage.DeleteAllOptions)
 	return nil
 }
 
-// Close closes the database connection.
-func (c *Client) Close() error {
-	if c.db != nil {
-		return c.db.Close()
+// PurgeExpired permanently deletes memories that expired at or before the given time.
+func (c *Client) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE expires_at IS NOT NULL AND expires_at <= ?", c.quote(c.collectionName))
+
+	result, err := c.db.ExecContext(ctx, query, FormatTimestamp(before))
+	if err != nil {
+		return 0, fmt.Errorf("PurgeExpired: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("PurgeExpired: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// Ping verifies that the database connection is alive.
+func (c *Client) Ping(ctx context.Context) error {
+	if err := c.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("Ping: %w", err)
 	}
 	return nil
 }
 
+// Close closes the database connections.
+func (c *Client) Close() error {
+	var err error
+	if c.replicas != nil {
+		err = c.replicas.Close()
+	}
+	if c.tlsName != "" {
+		mysql.DeregisterTLSConfig(c.tlsName)
+	}
+	return err
+}
+
 // CreateIndex creates a vector index.
+//
+// Searches use an HNSW index created on the embeddings of the memories
+// table (see VectorIndexed). Config.VectorIndex creates one automatically.
 func (c *Client) CreateIndex(ctx context.Context, config *storage.VectorIndexConfig) error {
 	var query string
 
 	switch config.IndexType {
 	case storage.IndexTypeHNSW:
-		query = fmt.Sprintf(`
-			CREATE VECTOR INDEX %s ON %s (%s) WITH (
-				index_type = HNSW,
-				M = %d,
-				efConstruction = %d,
-				metric_type = %s
-			)`,
-			config.IndexName, config.TableName, config.VectorField,
-			config.HNSWParams.M,
-			config.HNSWParams.EfConstruction,
-			config.MetricType,
-		)
+		params := config.HNSWParams
+		if params == nil {
+			params = &storage.HNSWParams{}
+		}
+		query = hnswIndexQuery(config.IndexName, config.TableName, config.VectorField, config.MetricType, params)
 	case storage.IndexTypeIVFFlat:
 		query = fmt.Sprintf(`
 			CREATE VECTOR INDEX %s ON %s (%s) WITH (
@@ -424,14 +1133,25 @@ func (c *Client) CreateIndex(ctx context.Context, config *storage.VectorIndexCon
 		return fmt.Errorf("CreateIndex: %w", err)
 	}
 
+	if config.IndexType == storage.IndexTypeHNSW && config.TableName == c.collectionName &&
+		config.VectorField == "embedding" && config.MetricType == storage.MetricCosine {
+		c.vectorIndex.Store(true)
+	}
 	return nil
 }
 
-// scanMemory scans a single memory.
-// Compatible with Python SDK: reads from 'document' field
-func (c *Client) scanMemory(row *sql.Row) (*storage.Memory, error) {
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanMemory scans a row selected with memoryColumns into a Memory.
+//
+// extra receives any additional columns selected after memoryColumns.
+func (c *Client) scanMemory(row rowScanner, extra ...interface{}) (*storage.Memory, error) {
 	var memory storage.Memory
-	var embeddingStr string
+	var content sql.NullString
+	var embeddingStr sql.NullString
 	var metadataJSON []byte
 	var userID sql.NullString
 	var agentID sql.NullString
@@ -439,20 +1159,29 @@ func (c *Client) scanMemory(row *sql.Row) (*storage.Memory, error) {
 	var hash sql.NullString
 	var createdAt sql.NullString
 	var updatedAt sql.NullString
+	var tagsJSON []byte
+	var expiresAt sql.NullString
+	var uid sql.NullString
+	var parentID sql.NullInt64
 
-	err := row.Scan(
+	dest := []interface{}{
 		&memory.ID,
 		&userID,
 		&agentID,
 		&runID,
-		&memory.Content,
+		&content,
 		&embeddingStr,
 		&metadataJSON,
 		&createdAt,
 		&updatedAt,
 		&hash,
-	)
-	if err != nil {
+		&tagsJSON,
+		&expiresAt,
+		&uid,
+		&memory.Version,
+		&parentID,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
 		return nil, err
 	}
 
@@ -463,10 +1192,12 @@ func (c *Client) scanMemory(row *sql.Row) (*storage.Memory, error) {
 	if agentID.Valid {
 		memory.AgentID = agentID.String
 	}
+	// NULL if not selected
+	memory.Content = content.String
 
 	// Parse embedding
-	if embeddingStr != "" {
-		embedding, err := stringToVector(embeddingStr)
+	if embeddingStr.String != "" {
+		embedding, err := stringToVector(embeddingStr.String)
 		if err != nil {
 			return nil, err
 		}
@@ -479,127 +1210,83 @@ func (c *Client) scanMemory(row *sql.Row) (*storage.Memory, error) {
 			return nil, err
 		}
 
-		// Extract retention_strength from metadata if present
+		// Extract retention_strength and last_accessed_at from metadata if present
 		if memory.Metadata != nil {
 			if rs, ok := memory.Metadata["retention_strength"].(float64); ok {
 				memory.RetentionStrength = rs
 			}
+			if accessed, ok := memory.Metadata["last_accessed_at"].(string); ok {
+				if t, err := ParseTimestamp(accessed); err == nil {
+					memory.LastAccessedAt = &t
+				}
+			}
+		}
+	}
+
+	// Parse tags
+	if len(tagsJSON) > 0 {
+		if err := json.Unmarshal(tagsJSON, &memory.Tags); err != nil {
+			return nil, err
 		}
 	}
 
 	// Parse timestamps
 	if createdAt.Valid {
-		if t, err := time.Parse(time.RFC3339, createdAt.String); err == nil {
+		if t, err := ParseTimestamp(createdAt.String); err == nil {
 			memory.CreatedAt = t
 		}
 	}
 	if updatedAt.Valid {
-		if t, err := time.Parse(time.RFC3339, updatedAt.String); err == nil {
+		if t, err := ParseTimestamp(updatedAt.String); err == nil {
 			memory.UpdatedAt = t
 		}
 	}
+	if expiresAt.Valid {
+		if t, err := ParseTimestamp(expiresAt.String); err == nil {
+			memory.ExpiresAt = &t
+		}
+	}
+	if uid.Valid {
+		memory.UID = uid.String
+	}
+	memory.ParentID = parentID.Int64
+	memory.Hash = hash.String
+
+	// Rows written by the Python SDK may keep the run ID only in its column
+	memory.RunID, _ = memory.Metadata[storage.RunIDKey].(string)
+	if memory.RunID == "" {
+		memory.RunID = runID.String
+	}
 
 	return &memory, nil
 }
 
 // scanMemories scans multiple memories.
-// Compatible with Python SDK: reads from 'document' field
+//
+// If hasScore is true, a trailing distance column is expected after memoryColumns.
 func (c *Client) scanMemories(rows *sql.Rows, hasScore bool) ([]*storage.Memory, error) {
 	var memories []*storage.Memory
 
 	for rows.Next() {
-		var memory storage.Memory
-		var embeddingStr string
-		var metadataJSON []byte
-		var userID sql.NullString
-		var agentID sql.NullString
-		var runID sql.NullString
-		var hash sql.NullString
-		var createdAt sql.NullString
-		var updatedAt sql.NullString
-		var distance float64
+		var memory *storage.Memory
+		var err error
 
 		if hasScore {
-			err := rows.Scan(
-				&memory.ID,
-				&userID,
-				&agentID,
-				&runID,
-				&memory.Content,
-				&embeddingStr,
-				&metadataJSON,
-				&createdAt,
-				&updatedAt,
-				&hash,
-				&distance,
-			)
+			var distance float64
+			memory, err = c.scanMemory(rows, &distance)
 			if err != nil {
 				return nil, err
 			}
 			// Convert distance to similarity score (1 - distance)
 			memory.Score = 1.0 - distance
 		} else {
-			err := rows.Scan(
-				&memory.ID,
-				&userID,
-				&agentID,
-				&runID,
-				&memory.Content,
-				&embeddingStr,
-				&metadataJSON,
-				&createdAt,
-				&updatedAt,
-				&hash,
-			)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		// Handle nullable fields
-		if userID.Valid {
-			memory.UserID = userID.String
-		}
-		if agentID.Valid {
-			memory.AgentID = agentID.String
-		}
-
-		// Parse embedding
-		if embeddingStr != "" {
-			embedding, err := stringToVector(embeddingStr)
+			memory, err = c.scanMemory(rows)
 			if err != nil {
 				return nil, err
 			}
-			memory.Embedding = embedding
-		}
-
-		// Parse metadata
-		if len(metadataJSON) > 0 {
-			if err := json.Unmarshal(metadataJSON, &memory.Metadata); err != nil {
-				return nil, err
-			}
-
-			// Extract retention_strength from metadata if present
-			if memory.Metadata != nil {
-				if rs, ok := memory.Metadata["retention_strength"].(float64); ok {
-					memory.RetentionStrength = rs
-				}
-			}
-		}
-
-		// Parse timestamps
-		if createdAt.Valid {
-			if t, err := time.Parse(time.RFC3339, createdAt.String); err == nil {
-				memory.CreatedAt = t
-			}
-		}
-		if updatedAt.Valid {
-			if t, err := time.Parse(time.RFC3339, updatedAt.String); err == nil {
-				memory.UpdatedAt = t
-			}
 		}
 
-		memories = append(memories, &memory)
+		memories = append(memories, memory)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -612,12 +1299,11 @@ func (c *Client) scanMemories(rows *sql.Rows, hasScore bool) ([]*storage.Memory,
 // Reset resets the vector store by dropping and recreating the table.
 //
 // WARNING: This operation will delete ALL memories and cannot be undone.
-// The table will be recreated with the same schema and indexes.
+// The table will be recreated with the same schema and indexes. The change
+// log is kept.
 func (c *Client) Reset(ctx context.Context) error {
 	// Drop the table
-	dropQuery := fmt.Sprintf("DROP TABLE IF EXISTS %s", c.collectionName)
-	_, err := c.db.ExecContext(ctx, dropQuery)
-	if err != nil {
+	if err := c.dropTable(ctx, c.collectionName); err != nil {
 		return fmt.Errorf("Reset: failed to drop table: %w", err)
 	}
 
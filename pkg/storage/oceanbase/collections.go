@@ -0,0 +1,58 @@
+package oceanbase
+
+import (
+	"context"
+	"fmt"
+)
+
+// ListCollections returns the names of the collections of the database,
+// sorted: the tables that have a change log table. The names of Oracle-mode
+// tenants are returned in lower case.
+func (c *Client) ListCollections(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT t.table_name FROM information_schema.tables t
+		WHERE t.table_schema = DATABASE()
+		  AND EXISTS (
+			SELECT 1 FROM information_schema.tables l
+			WHERE l.table_schema = t.table_schema AND l.table_name = CONCAT(t.table_name, '_changes')
+		  )
+		ORDER BY t.table_name
+	`
+	if c.mode == ModeOracle {
+		query = `
+			SELECT LOWER(t.table_name) FROM user_tables t
+			WHERE EXISTS (SELECT 1 FROM user_tables l WHERE l.table_name = t.table_name || '_CHANGES')
+			ORDER BY t.table_name
+		`
+	}
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ListCollections: %w", err)
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("ListCollections: %w", err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListCollections: %w", err)
+	}
+	return names, nil
+}
+
+// DropCollection drops the memory table, the change log table and the team
+// membership table of a collection. DDL statements commit implicitly, so the
+// tables are dropped one after the other.
+func (c *Client) DropCollection(ctx context.Context, name string) error {
+	for _, table := range []string{name, name + "_changes", name + "_teams"} {
+		if err := c.dropTable(ctx, table); err != nil {
+			return fmt.Errorf("DropCollection: %w", err)
+		}
+	}
+	return nil
+}
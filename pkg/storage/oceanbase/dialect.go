@@ -0,0 +1,176 @@
+package oceanbase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Mode is the compatibility mode of an OceanBase tenant, which decides the
+// SQL dialect of the statements of the client.
+type Mode string
+
+const (
+	// ModeMySQL is the mode of MySQL-compatible tenants, and of MySQL
+	// servers.
+	ModeMySQL Mode = "MYSQL"
+
+	// ModeOracle is the mode of Oracle-compatible tenants. Their names are
+	// case-insensitive, as unquoted Oracle names, and their tables have no
+	// vector or full-text index: searches compute exact distances, and hybrid
+	// searches are vector searches.
+	ModeOracle Mode = "ORACLE"
+)
+
+// DetectMode returns the compatibility mode of the tenant db is connected
+// to. Servers whose mode cannot be read, e.g. MySQL, are in MySQL mode.
+func DetectMode(ctx context.Context, db *sql.DB) Mode {
+	var name, mode string
+	err := db.QueryRowContext(ctx, "SHOW VARIABLES LIKE 'ob_compatibility_mode'").Scan(&name, &mode)
+	if err == nil {
+		if strings.EqualFold(mode, string(ModeOracle)) {
+			return ModeOracle
+		}
+		return ModeMySQL
+	}
+
+	// Tenants that do not show the variable are recognized by a query that
+	// only Oracle mode accepts
+	var schema sql.NullString
+	if db.QueryRowContext(ctx, "SELECT SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA') FROM DUAL").Scan(&schema) == nil {
+		return ModeOracle
+	}
+	return ModeMySQL
+}
+
+// Mode returns the compatibility mode of the tenant, see DetectMode.
+func (c *Client) Mode() Mode {
+	return c.mode
+}
+
+// quote returns name quoted as an identifier. Oracle-mode names are
+// upper-cased, so that a quoted name is the same as the unquoted one.
+func (m Mode) quote(name string) string {
+	if m == ModeOracle {
+		return `"` + strings.ToUpper(name) + `"`
+	}
+	return "`" + name + "`"
+}
+
+// limit returns the clause selecting up to limit rows, after the first
+// offset ones.
+func (m Mode) limit(limit, offset int) string {
+	switch {
+	case m == ModeOracle && offset > 0:
+		return fmt.Sprintf("OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", offset, limit)
+	case m == ModeOracle:
+		return fmt.Sprintf("FETCH FIRST %d ROWS ONLY", limit)
+	case offset > 0:
+		return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+	default:
+		return fmt.Sprintf("LIMIT %d", limit)
+	}
+}
+
+// jsonValue returns the expression of the scalar at path in the JSON
+// column, as text.
+func (m Mode) jsonValue(column, path string) string {
+	if m == ModeOracle {
+		return fmt.Sprintf("JSON_VALUE(%s, '%s')", column, path)
+	}
+	return fmt.Sprintf("%s->>'%s'", column, path)
+}
+
+// seqName returns the name of the sequence numbering the rows of table on
+// Oracle-mode tenants, which have no AUTO_INCREMENT columns.
+func seqName(table string) string {
+	return table + "_seq"
+}
+
+// createTable creates table, if it does not exist yet, with the statement
+// of the mode of the tenant: mysqlDDL, a CREATE TABLE IF NOT EXISTS
+// statement declaring its indexes, or oracleDDL followed by oracleIndexes,
+// as Oracle mode has neither IF NOT EXISTS nor indexes declared with the
+// table.
+func (c *Client) createTable(ctx context.Context, table, mysqlDDL, oracleDDL string, oracleIndexes ...string) error {
+	if c.mode != ModeOracle {
+		_, err := c.db.ExecContext(ctx, mysqlDDL)
+		return err
+	}
+
+	exists, err := c.objectExists(ctx, "TABLE", table)
+	if err != nil || exists {
+		return err
+	}
+	for _, statement := range append([]string{oracleDDL}, oracleIndexes...) {
+		if _, err := c.db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropTable drops table if it exists, and on Oracle-mode tenants its
+// sequence (see seqName) if it has one.
+func (c *Client) dropTable(ctx context.Context, table string) error {
+	if c.mode != ModeOracle {
+		_, err := c.db.ExecContext(ctx, "DROP TABLE IF EXISTS "+c.mode.quote(table))
+		return err
+	}
+
+	for _, object := range []struct{ kind, name string }{{"TABLE", table}, {"SEQUENCE", seqName(table)}} {
+		exists, err := c.objectExists(ctx, object.kind, object.name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			if _, err := c.db.ExecContext(ctx, fmt.Sprintf("DROP %s %s", object.kind, c.mode.quote(object.name))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// objectExists reports whether the schema of an Oracle-mode tenant has an
+// object of kind (TABLE or SEQUENCE) named name.
+func (c *Client) objectExists(ctx context.Context, kind, name string) (bool, error) {
+	var count int
+	err := c.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM USER_OBJECTS WHERE OBJECT_TYPE = ? AND OBJECT_NAME = ?",
+		kind, strings.ToUpper(name)).Scan(&count)
+	return count > 0, err
+}
+
+// insertSeq inserts a row of values into the columns of table, whose key is
+// an increasing seq column, and returns its seq: an AUTO_INCREMENT column
+// in MySQL mode, and the next value of the sequence of table (see seqName)
+// in Oracle mode.
+func (c *Client) insertSeq(ctx context.Context, table string, columns []string, values ...interface{}) (int64, error) {
+	var seq int64
+	if c.mode == ModeOracle {
+		query := fmt.Sprintf("SELECT %s.NEXTVAL FROM DUAL", c.mode.quote(seqName(table)))
+		if err := c.db.QueryRowContext(ctx, query).Scan(&seq); err != nil {
+			return 0, err
+		}
+		columns = append([]string{"seq"}, columns...)
+		values = append([]interface{}{seq}, values...)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		c.mode.quote(table), strings.Join(columns, ", "), placeholders)
+	result, err := c.db.ExecContext(ctx, query, values...)
+	if err != nil {
+		return 0, err
+	}
+	if c.mode == ModeOracle {
+		return seq, nil
+	}
+	return result.LastInsertId()
+}
+
+// quote returns name quoted as an identifier of the tenant, see Mode.quote.
+func (c *Client) quote(name string) string {
+	return c.mode.quote(name)
+}
@@ -0,0 +1,220 @@
+package oceanbase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// fullTextIndexName is the name of the FULLTEXT index created on
+// fulltext_content by NewClient.
+const fullTextIndexName = "idx_fulltext_content"
+
+// errTableCantHandleFT is the MySQL error number (ER_TABLE_CANT_HANDLE_FT)
+// of CREATE FULLTEXT INDEX on a table that cannot have one.
+const errTableCantHandleFT = 1214
+
+// hybridTextWeight is the weight of the text relevance in the scores of
+// hybrid searches; the vector similarity has the remaining weight.
+const hybridTextWeight = 0.3
+
+// InitFullTextIndex creates the FULLTEXT index on the fulltext_content
+// column of table if it has none yet, with parser if it is not empty, and
+// reports whether hybrid searches can use one. The tenant must be in MySQL
+// mode.
+//
+// Rows written before fulltext_content was populated are backfilled from
+// document before the index is created. On OceanBase versions without
+// full-text index support, the index is not created and InitFullTextIndex
+// returns false without an error; NewClient then makes hybrid searches fall
+// back to vector searches.
+func InitFullTextIndex(ctx context.Context, db *sql.DB, table, parser string) (bool, error) {
+	var count int
+	err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = 'fulltext_content'
+	`, table).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	backfill := fmt.Sprintf("UPDATE %s SET fulltext_content = document WHERE fulltext_content IS NULL", ModeMySQL.quote(table))
+	if _, err := db.ExecContext(ctx, backfill); err != nil {
+		return false, err
+	}
+
+	query := fmt.Sprintf("CREATE FULLTEXT INDEX %s ON %s (fulltext_content)", fullTextIndexName, ModeMySQL.quote(table))
+	if parser != "" {
+		query += " WITH PARSER " + parser
+	}
+	_, err = db.ExecContext(ctx, query)
+	switch {
+	case err == nil:
+		return true, nil
+	case FullTextIndexUnsupported(err):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// FullTextIndexUnsupported reports whether err is the error of a server
+// that does not support FULLTEXT indexes: ER_TABLE_CANT_HANDLE_FT, or an
+// unsupported feature or syntax error about the FULLTEXT index itself.
+func FullTextIndexUnsupported(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	switch mysqlErr.Number {
+	case errTableCantHandleFT:
+		return true
+	case errParse, errNotSupportedYet, errFeatureNotEnabled:
+		return strings.Contains(strings.ToLower(mysqlErr.Message), "fulltext")
+	}
+	return false
+}
+
+// FullTextIndexed reports whether the memory content is indexed for the
+// full-text matching of hybrid searches (see storage.SearchOptions.Hybrid).
+// Without an index, hybrid searches are vector searches.
+func (c *Client) FullTextIndexed() bool {
+	return c.fullTextIndex.Load()
+}
+
+// hybridSearch runs a vector search and a full-text search of opts.Query,
+// and merges the results with MergeHybrid.
+func (c *Client) hybridSearch(ctx context.Context, embedding []float64, opts *storage.SearchOptions) ([]*storage.Memory, error) {
+	vectorResults, err := c.search(ctx, embedding, opts, c.vectorIndex.Load())
+	if err != nil {
+		return nil, err
+	}
+	textResults, relevances, err := c.searchFullText(ctx, embedding, opts)
+	if err != nil {
+		return nil, fmt.Errorf("Search: %w", err)
+	}
+
+	minScore := opts.MinScore
+	if minScore == 0 && opts.Threshold > 0 {
+		minScore = opts.Threshold
+	}
+	return MergeHybrid(vectorResults, textResults, relevances, minScore, opts.Limit), nil
+}
+
+// MergeHybrid merges the results of the vector search and of the full-text
+// search of a hybrid search, whose Score is their vector similarity, with
+// the text relevances of the full-text matches.
+//
+// Each memory is scored with a weighted sum of its vector similarity and its
+// text relevance, normalized by the best relevance of the full-text matches
+// (0 for memories that are not among them). Memories scoring below minScore
+// are dropped; the others are returned best first, then by ID, up to limit
+// (0 for no limit). The vector search has already applied minScore to the
+// similarity of its results.
+func MergeHybrid(vectorResults, textResults []*storage.Memory, relevances []float64, minScore float64, limit int) []*storage.Memory {
+	maxRelevance := 0.0
+	for _, relevance := range relevances {
+		if relevance > maxRelevance {
+			maxRelevance = relevance
+		}
+	}
+	textScores := make(map[int64]float64, len(textResults))
+	for i, memory := range textResults {
+		if maxRelevance > 0 {
+			textScores[memory.ID] = relevances[i] / maxRelevance
+		}
+	}
+
+	byID := make(map[int64]bool, len(vectorResults)+len(textResults))
+	var results []*storage.Memory
+	for _, memory := range append(append([]*storage.Memory{}, vectorResults...), textResults...) {
+		if byID[memory.ID] {
+			continue
+		}
+		byID[memory.ID] = true
+		memory.Score = (1-hybridTextWeight)*memory.Score + hybridTextWeight*textScores[memory.ID]
+		if memory.Score >= minScore {
+			results = append(results, memory)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// searchFullText returns the memories whose content matches opts.Query,
+// best matches first, with their vector similarity to embedding as Score
+// and their text relevances.
+func (c *Client) searchFullText(ctx context.Context, embedding []float64, opts *storage.SearchOptions) ([]*storage.Memory, []float64, error) {
+	whereClause, args := c.buildWhereClause(whereFilter{
+		userID:    opts.UserID,
+		teamIDs:   opts.TeamIDs,
+		agentID:   opts.AgentID,
+		filters:   opts.Filters,
+		timeRange: opts.TimeRange,
+		tags:      opts.Tags,
+		activeAt:  storage.ActiveAt(opts.Now),
+	})
+
+	const match = "MATCH(fulltext_content) AGAINST(? IN NATURAL LANGUAGE MODE)"
+	if whereClause == "" {
+		whereClause = "WHERE " + match
+	} else {
+		whereClause += " AND " + match
+	}
+	args = append(args, opts.Query)
+
+	query := fmt.Sprintf(`
+		SELECT %s,
+			cosine_distance(embedding, ?) as distance,
+			%s as relevance
+		FROM %s
+		%s
+		ORDER BY relevance DESC, id ASC
+		%s
+	`, c.selectColumns(opts.Fields, opts.WithoutEmbeddings), match, c.quote(c.collectionName), whereClause, c.mode.limit(opts.Limit, 0))
+
+	allArgs := []interface{}{vectorToString(embedding), opts.Query}
+	allArgs = append(allArgs, args...)
+
+	rows, err := c.replicas.Reader(ctx).QueryContext(ctx, query, allArgs...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var memories []*storage.Memory
+	var relevances []float64
+	for rows.Next() {
+		var distance, relevance float64
+		memory, err := c.scanMemory(rows, &distance, &relevance)
+		if err != nil {
+			return nil, nil, err
+		}
+		memory.Score = 1.0 - distance
+		memories = append(memories, memory)
+		relevances = append(relevances, relevance)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return memories, relevances, nil
+}
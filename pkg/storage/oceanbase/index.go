@@ -0,0 +1,146 @@
+package oceanbase
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// vectorIndexName is the name of the HNSW index created on the embeddings by
+// NewClient.
+const vectorIndexName = "idx_embedding"
+
+// MySQL error numbers returned by OceanBase versions without vector indexes
+// for CREATE VECTOR INDEX.
+const (
+	errParse             = 1064 // ER_PARSE_ERROR
+	errNotSupportedYet   = 1235 // ER_NOT_SUPPORTED_YET
+	errFeatureNotEnabled = 1289 // ER_FEATURE_DISABLED
+)
+
+// initVectorIndex creates the HNSW index of Config.VectorIndex if the
+// memories table has no vector index yet, and records whether searches can
+// use one.
+//
+// On OceanBase versions without vector index support, the index is not
+// created and searches compute exact distances instead.
+func (c *Client) initVectorIndex(ctx context.Context) error {
+	indexed, err := c.hasVectorIndex(ctx)
+	if err != nil {
+		return err
+	}
+	if !indexed && c.config.VectorIndex != nil {
+		_, err := c.db.ExecContext(ctx, hnswIndexQuery(vectorIndexName, c.quote(c.collectionName), "embedding", storage.MetricCosine, c.config.VectorIndex))
+		switch {
+		case err == nil:
+			indexed = true
+		case !vectorIndexUnsupported(err):
+			return err
+		}
+	}
+	c.vectorIndex.Store(indexed)
+	return nil
+}
+
+// hasVectorIndex reports whether the embeddings of the memories table are
+// indexed.
+func (c *Client) hasVectorIndex(ctx context.Context) (bool, error) {
+	var count int
+	err := c.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = 'embedding'
+	`, c.collectionName).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// VectorIndexed reports whether searches use an HNSW index on the
+// embeddings, i.e. whether an index was found or created by NewClient, or
+// created by CreateIndex since. Without one, searches compute exact
+// distances.
+func (c *Client) VectorIndexed() bool {
+	return c.vectorIndex.Load()
+}
+
+// hnswIndexQuery returns the statement creating an HNSW index.
+func hnswIndexQuery(name, table, column string, metric storage.MetricType, params *storage.HNSWParams) string {
+	distance := string(metric)
+	if metric == storage.MetricIP {
+		distance = "inner_product"
+	}
+	query := fmt.Sprintf("CREATE VECTOR INDEX %s ON %s (%s) WITH (distance = %s, type = hnsw, lib = vsag", name, table, column, distance)
+	if params.M > 0 {
+		query += fmt.Sprintf(", m = %d", params.M)
+	}
+	if params.EfConstruction > 0 {
+		query += fmt.Sprintf(", ef_construction = %d", params.EfConstruction)
+	}
+	if params.EfSearch > 0 {
+		query += fmt.Sprintf(", ef_search = %d", params.EfSearch)
+	}
+	return query + ")"
+}
+
+// vectorIndexUnsupported reports whether err is the error of a server that
+// does not support vector indexes.
+func vectorIndexUnsupported(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	switch mysqlErr.Number {
+	case errParse, errNotSupportedYet, errFeatureNotEnabled:
+		return true
+	}
+	return false
+}
+
+// efSearch returns the HNSW search depth of a search (0 for the default of
+// the index).
+func (c *Client) efSearch(opts *storage.SearchOptions) int {
+	if opts.EfSearch > 0 {
+		return opts.EfSearch
+	}
+	if c.config.VectorIndex != nil {
+		return c.config.VectorIndex.EfSearch
+	}
+	return 0
+}
+
+// queryEfSearch runs a search query on db with the HNSW search depth
+// efSearch. The depth is a session variable, so the query runs on a
+// dedicated connection, which is reset by the returned release function
+// before it goes back to the pool. release must be called once rows are
+// closed.
+func queryEfSearch(ctx context.Context, db *sql.DB, efSearch int, query string, args ...interface{}) (rows *sql.Rows, release func(), err error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	release = func() {
+		// A connection that cannot be reset is discarded rather than reused
+		// with the depth of this search
+		if _, err := conn.ExecContext(context.Background(), "SET ob_hnsw_ef_search = DEFAULT"); err != nil {
+			_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+		_ = conn.Close()
+	}
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET ob_hnsw_ef_search = %d", efSearch)); err != nil {
+		release()
+		return nil, nil, err
+	}
+	rows, err = conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return rows, release, nil
+}
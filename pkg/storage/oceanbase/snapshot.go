@@ -0,0 +1,44 @@
+package oceanbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// snapshotBackend identifies the dumps of this store.
+const snapshotBackend = "oceanbase"
+
+// ErrOracleMode is returned by Snapshot and RestoreSnapshot on Oracle-mode
+// tenants. Dumps are read in a REPEATABLE READ transaction, which Oracle
+// mode does not have, and restored with unquoted column names, which the
+// reserved uid column cannot be.
+var ErrOracleMode = errors.New("oceanbase: snapshots of Oracle-mode tenants are not supported")
+
+// Snapshot writes a logical dump of the memory table to a new file at path
+// (see storage.WriteDump), read from the primary in a single repeatable
+// read transaction.
+func (c *Client) Snapshot(ctx context.Context, path string) error {
+	if c.mode == ModeOracle {
+		return fmt.Errorf("Snapshot: %w", ErrOracleMode)
+	}
+	if err := storage.WriteDump(ctx, c.db, path, snapshotBackend, "memories", c.collectionName); err != nil {
+		return fmt.Errorf("Snapshot: %w", err)
+	}
+	return nil
+}
+
+// RestoreSnapshot replaces the memories with those of the dump at path in a
+// single transaction. The change log and the audit log are kept.
+func (c *Client) RestoreSnapshot(ctx context.Context, path string) error {
+	if c.mode == ModeOracle {
+		return fmt.Errorf("RestoreSnapshot: %w", ErrOracleMode)
+	}
+	placeholder := func(int) string { return "?" }
+	if _, err := storage.RestoreDump(ctx, c.db, path, snapshotBackend, "memories", c.collectionName, placeholder); err != nil {
+		return fmt.Errorf("RestoreSnapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package oceanbase
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// teamsTable returns the name of the team membership table.
+func (c *Client) teamsTable() string {
+	return c.collectionName + "_teams"
+}
+
+// initTeams creates the team membership table.
+//
+// created_at is stored like the memories' timestamps (see FormatTimestamp).
+func (c *Client) initTeams(ctx context.Context) error {
+	table := c.quote(c.teamsTable())
+	mysqlDDL := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			team_id VARCHAR(128) NOT NULL,
+			user_id VARCHAR(128) NOT NULL,
+			created_at VARCHAR(128) NOT NULL,
+			PRIMARY KEY (team_id, user_id),
+			INDEX idx_user_id (user_id)
+		)
+	`, table)
+	oracleDDL := fmt.Sprintf(`
+		CREATE TABLE %s (
+			team_id VARCHAR2(128) NOT NULL,
+			user_id VARCHAR2(128) NOT NULL,
+			created_at VARCHAR2(128) NOT NULL,
+			PRIMARY KEY (team_id, user_id)
+		)
+	`, table)
+	return c.createTable(ctx, c.teamsTable(), mysqlDDL, oracleDDL,
+		fmt.Sprintf("CREATE INDEX %s ON %s (user_id)", c.quote(c.teamsTable()+"_user_id"), table))
+}
+
+// AddTeamMember adds userID to teamID. Adding a member again keeps the
+// membership as it is: INSERT IGNORE in MySQL mode, and a MERGE inserting
+// only missing memberships in Oracle mode.
+func (c *Client) AddTeamMember(ctx context.Context, teamID, userID string) error {
+	query := fmt.Sprintf("INSERT IGNORE INTO %s (team_id, user_id, created_at) VALUES (?, ?, ?)", c.quote(c.teamsTable()))
+	if c.mode == ModeOracle {
+		query = fmt.Sprintf(`
+			MERGE INTO %s t
+			USING (SELECT ? AS team_id, ? AS user_id, ? AS created_at FROM DUAL) s
+			ON (t.team_id = s.team_id AND t.user_id = s.user_id)
+			WHEN NOT MATCHED THEN INSERT (team_id, user_id, created_at) VALUES (s.team_id, s.user_id, s.created_at)
+		`, c.quote(c.teamsTable()))
+	}
+	if _, err := c.db.ExecContext(ctx, query, teamID, userID, FormatTimestamp(time.Now())); err != nil {
+		return fmt.Errorf("AddTeamMember: %w", err)
+	}
+	return nil
+}
+
+// RemoveTeamMember removes userID from teamID.
+func (c *Client) RemoveTeamMember(ctx context.Context, teamID, userID string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE team_id = ? AND user_id = ?", c.quote(c.teamsTable()))
+	if _, err := c.db.ExecContext(ctx, query, teamID, userID); err != nil {
+		return fmt.Errorf("RemoveTeamMember: %w", err)
+	}
+	return nil
+}
+
+// ListTeamMembers returns the members of teamID, sorted.
+func (c *Client) ListTeamMembers(ctx context.Context, teamID string) ([]string, error) {
+	query := fmt.Sprintf("SELECT user_id FROM %s WHERE team_id = ? ORDER BY user_id", c.quote(c.teamsTable()))
+	members, err := c.queryStrings(ctx, query, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("ListTeamMembers: %w", err)
+	}
+	return members, nil
+}
+
+// ListUserTeams returns the teams of userID, sorted.
+func (c *Client) ListUserTeams(ctx context.Context, userID string) ([]string, error) {
+	query := fmt.Sprintf("SELECT team_id FROM %s WHERE user_id = ? ORDER BY team_id", c.quote(c.teamsTable()))
+	teams, err := c.queryStrings(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ListUserTeams: %w", err)
+	}
+	return teams, nil
+}
+
+// queryStrings returns the single string column selected by query.
+func (c *Client) queryStrings(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
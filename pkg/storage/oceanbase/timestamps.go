@@ -0,0 +1,131 @@
+package oceanbase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimestampLayout is the layout of the stored timestamps. Timestamps are
+// stored in VARCHAR columns, as the Python SDK stores them. They are written
+// in UTC with a fixed width, so that comparing the strings, as time ranges,
+// expiration and ORDER BY do, compares the times.
+//
+// Example: "2024-05-01T09:30:00.000000+00:00", as Python's isoformat()
+const TimestampLayout = "2006-01-02T15:04:05.000000-07:00"
+
+// TimestampPattern is a LIKE pattern matching the values written with
+// TimestampLayout.
+const TimestampPattern = "____-__-__T__:__:__.______+00:00"
+
+// naiveTimestampLayouts are the layouts of timestamps without a time zone,
+// as written by the Python SDK's datetime.now().isoformat().
+var naiveTimestampLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// FormatTimestamp formats a time the way it is stored, see TimestampLayout.
+func FormatTimestamp(t time.Time) string {
+	return t.UTC().Format(TimestampLayout)
+}
+
+// ParseTimestamp parses a stored timestamp into local time: values written
+// with TimestampLayout, RFC 3339 values with any offset as written by
+// earlier versions, and naive values, which are in the local time zone.
+func ParseTimestamp(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.Local(), nil
+	}
+	for _, layout := range naiveTimestampLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("parse timestamp %q", value)
+}
+
+// NormalizeTimestamps rewrites the values of the timestamp columns of table
+// that were not written with TimestampLayout, by earlier versions or by the
+// Python SDK, so that they compare with the others. key is the integer
+// primary key of table. Values that cannot be parsed are left as they are.
+//
+// The tenant must be in MySQL mode. NewClient normalizes the timestamps of
+// its tables when it starts; Oracle-mode tables are only written by this
+// version.
+func NormalizeTimestamps(ctx context.Context, db *sql.DB, table, key string, columns ...string) error {
+	const batchSize = 1000
+
+	var stale []string
+	for _, column := range columns {
+		stale = append(stale, fmt.Sprintf("(%s IS NOT NULL AND %s NOT LIKE '%s')", column, column, TimestampPattern))
+	}
+	query := fmt.Sprintf("SELECT %s, %s FROM %s WHERE %s > ? AND (%s) ORDER BY %s %s",
+		key, strings.Join(columns, ", "), ModeMySQL.quote(table), key, strings.Join(stale, " OR "), key, ModeMySQL.limit(batchSize, 0))
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = column + " = ?"
+	}
+	update := fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?", ModeMySQL.quote(table), strings.Join(assignments, ", "), key)
+
+	var after int64 = -1 << 63
+	for {
+		rows, err := queryTimestampRows(ctx, db, query, after, len(columns))
+		if err != nil {
+			return fmt.Errorf("normalize timestamps of %s: %w", table, err)
+		}
+		for _, row := range rows {
+			args := make([]interface{}, 0, len(columns)+1)
+			for _, value := range row.values {
+				if !value.Valid {
+					args = append(args, nil)
+					continue
+				}
+				normalized := value.String
+				if t, err := ParseTimestamp(value.String); err == nil {
+					normalized = FormatTimestamp(t)
+				}
+				args = append(args, normalized)
+			}
+			if _, err := db.ExecContext(ctx, update, append(args, row.key)...); err != nil {
+				return fmt.Errorf("normalize timestamps of %s: %w", table, err)
+			}
+			after = row.key
+		}
+		if len(rows) < batchSize {
+			return nil
+		}
+	}
+}
+
+// timestampRow is a row read by NormalizeTimestamps.
+type timestampRow struct {
+	key    int64
+	values []sql.NullString
+}
+
+// queryTimestampRows runs a query of NormalizeTimestamps, whose rows are a
+// key and n timestamp columns.
+func queryTimestampRows(ctx context.Context, db *sql.DB, query string, after int64, n int) ([]timestampRow, error) {
+	rows, err := db.QueryContext(ctx, query, after)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []timestampRow
+	for rows.Next() {
+		row := timestampRow{values: make([]sql.NullString, n)}
+		dest := []interface{}{&row.key}
+		for i := range row.values {
+			dest = append(dest, &row.values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
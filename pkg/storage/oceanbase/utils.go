@@ -1,10 +1,13 @@
 package oceanbase
 
 import (
-	"crypto/md5"
-	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
 )
 
 // vectorToString converts a float64 slice to an OceanBase VECTOR format string.
@@ -14,12 +17,17 @@ func vectorToString(vector []float64) string {
 		return "[]"
 	}
 
-	parts := make([]string, len(vector))
+	// About 10 bytes per value, e.g. "-0.123456,"
+	buf := make([]byte, 0, 2+10*len(vector))
+	buf = append(buf, '[')
 	for i, v := range vector {
-		parts[i] = fmt.Sprintf("%f", v)
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = strconv.AppendFloat(buf, v, 'f', 6, 64)
 	}
-
-	return "[" + strings.Join(parts, ",") + "]"
+	buf = append(buf, ']')
+	return string(buf)
 }
 
 // stringToVector converts a string to a float64 slice.
@@ -31,42 +39,139 @@ func stringToVector(s string) ([]float64, error) {
 		return []float64{}, nil
 	}
 
-	parts := strings.Split(s, ",")
-	result := make([]float64, len(parts))
-
-	for i, part := range parts {
-		var val float64
-		_, err := fmt.Sscanf(strings.TrimSpace(part), "%f", &val)
+	result := make([]float64, 0, strings.Count(s, ",")+1)
+	for len(s) > 0 {
+		part := s
+		if i := strings.IndexByte(s, ','); i >= 0 {
+			part, s = s[:i], s[i+1:]
+		} else {
+			s = ""
+		}
+		val, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
 		if err != nil {
 			return nil, err
 		}
-		result[i] = val
+		result = append(result, val)
 	}
 
 	return result, nil
 }
 
+// whereFilter describes the row filters applied by buildWhereClause.
+type whereFilter struct {
+	userID  string
+	agentID string
+
+	// teamIDs widens userID to the memories shared with these teams.
+	teamIDs []string
+
+	filters   map[string]interface{}
+	timeRange *storage.TimeRange
+	tags      []string
+
+	// entities are the entities every memory must be annotated with in
+	// metadata["entities"], compared case-insensitively.
+	entities []string
+
+	// parentID selects the chunks of this memory. If it is zero and
+	// excludeChunks is set, chunks are excluded.
+	parentID      int64
+	excludeChunks bool
+
+	// activeAt excludes memories that expired at or before this time.
+	// A zero value disables the expiration check.
+	activeAt time.Time
+}
+
 // buildWhereClause builds a WHERE clause.
-func buildWhereClause(userID, agentID string, filters map[string]interface{}) (string, []interface{}) {
+func (c *Client) buildWhereClause(f whereFilter) (string, []interface{}) {
 	conditions := []string{}
 	args := []interface{}{}
 
-	if userID != "" {
+	if f.userID != "" && len(f.teamIDs) > 0 {
+		placeholders := make([]string, len(f.teamIDs))
+		args = append(args, f.userID)
+		for i, teamID := range f.teamIDs {
+			placeholders[i] = "?"
+			args = append(args, teamID)
+		}
+		conditions = append(conditions, fmt.Sprintf("(user_id = ? OR %s IN (%s))",
+			c.mode.jsonValue("metadata", "$."+storage.TeamIDKey), strings.Join(placeholders, ", ")))
+	} else if f.userID != "" {
 		conditions = append(conditions, "user_id = ?")
-		args = append(args, userID)
+		args = append(args, f.userID)
 	}
 
-	if agentID != "" {
+	if f.agentID != "" {
 		conditions = append(conditions, "agent_id = ?")
-		args = append(args, agentID)
+		args = append(args, f.agentID)
+	}
+
+	if f.parentID != 0 {
+		conditions = append(conditions, "parent_id = ?")
+		args = append(args, f.parentID)
+	} else if f.excludeChunks {
+		conditions = append(conditions, "parent_id IS NULL")
 	}
 
 	// Handle additional filter conditions
-	for key, value := range filters {
-		conditions = append(conditions, fmt.Sprintf("metadata->>'$.%s' = ?", key))
+	for key, value := range f.filters {
+		conditions = append(conditions, c.mode.jsonValue("metadata", "$."+key)+" = ?")
 		args = append(args, value)
 	}
 
+	// Handle time range conditions.
+	// created_at/updated_at are stored as strings that compare like the
+	// times (see TimestampLayout), so bounds are formatted the same way.
+	if !f.timeRange.IsZero() {
+		if !f.timeRange.CreatedAfter.IsZero() {
+			conditions = append(conditions, "created_at >= ?")
+			args = append(args, FormatTimestamp(f.timeRange.CreatedAfter))
+		}
+		if !f.timeRange.CreatedBefore.IsZero() {
+			conditions = append(conditions, "created_at < ?")
+			args = append(args, FormatTimestamp(f.timeRange.CreatedBefore))
+		}
+		if !f.timeRange.UpdatedAfter.IsZero() {
+			conditions = append(conditions, "updated_at >= ?")
+			args = append(args, FormatTimestamp(f.timeRange.UpdatedAfter))
+		}
+		if !f.timeRange.UpdatedBefore.IsZero() {
+			conditions = append(conditions, "updated_at < ?")
+			args = append(args, FormatTimestamp(f.timeRange.UpdatedBefore))
+		}
+	}
+
+	// Memories must carry every requested tag, and be annotated with every
+	// requested entity. Oracle mode has no JSON_CONTAINS, and matches the
+	// array elements with a path filter instead.
+	if c.mode == ModeOracle {
+		for _, tag := range f.tags {
+			conditions = append(conditions, `JSON_EXISTS(tags, '$[*]?(@ == $v)' PASSING ? AS "v")`)
+			args = append(args, tag)
+		}
+	} else if len(f.tags) > 0 {
+		tagsJSON, _ := json.Marshal(f.tags)
+		conditions = append(conditions, "JSON_CONTAINS(tags, ?)")
+		args = append(args, string(tagsJSON))
+	}
+	for _, entity := range f.entities {
+		if c.mode == ModeOracle {
+			conditions = append(conditions, `JSON_EXISTS(metadata, '$.entities[*]?(@.lower() == $v)' PASSING ? AS "v")`)
+			args = append(args, strings.ToLower(entity))
+			continue
+		}
+		entityJSON, _ := json.Marshal(strings.ToLower(entity))
+		conditions = append(conditions, "JSON_CONTAINS(LOWER(JSON_EXTRACT(metadata, '$.entities')), ?)")
+		args = append(args, string(entityJSON))
+	}
+
+	// Exclude expired memories (expires_at uses the same format as created_at)
+	if !f.activeAt.IsZero() {
+		conditions = append(conditions, "(expires_at IS NULL OR expires_at > ?)")
+		args = append(args, FormatTimestamp(f.activeAt))
+	}
+
 	if len(conditions) == 0 {
 		return "", args
 	}
@@ -74,9 +179,38 @@ func buildWhereClause(userID, agentID string, filters map[string]interface{}) (s
 	return "WHERE " + strings.Join(conditions, " AND "), args
 }
 
-// generateHash generates an MD5 hash for content.
-// Compatible with Python SDK's hash generation
-func generateHash(content string) string {
-	hash := md5.Sum([]byte(content))
-	return hex.EncodeToString(hash[:])
+// escapeLikePattern escapes LIKE wildcards so text is matched literally.
+func escapeLikePattern(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(text)
+}
+
+// marshalTags encodes tags as a JSON array for the JSON tags column.
+func marshalTags(tags []string) (string, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return "", err
+	}
+	return string(tagsJSON), nil
+}
+
+// nullableUID returns uid for the uid column, or nil for memories without a
+// string ID so that the unique index ignores them.
+func nullableUID(uid string) interface{} {
+	if uid == "" {
+		return nil
+	}
+	return uid
+}
+
+// nullableParentID returns parentID for the parent_id column, or nil for
+// memories that are not chunks.
+func nullableParentID(parentID int64) interface{} {
+	if parentID == 0 {
+		return nil
+	}
+	return parentID
 }
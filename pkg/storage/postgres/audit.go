@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// auditTable returns the name of the audit log table.
+func (c *Client) auditTable() string {
+	return c.collectionName + "_audit"
+}
+
+// initAuditLog creates the audit log table.
+func (c *Client) initAuditLog(ctx context.Context) error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			seq BIGSERIAL PRIMARY KEY,
+			operation VARCHAR(64) NOT NULL,
+			actor VARCHAR(255) NOT NULL DEFAULT '',
+			user_id VARCHAR(255) NOT NULL DEFAULT '',
+			params JSONB NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)
+	`, c.auditTable())
+	_, err := c.db.ExecContext(ctx, query)
+	return err
+}
+
+// AppendAudit appends an entry to the audit log and sets its Seq.
+func (c *Client) AppendAudit(ctx context.Context, entry *storage.AuditEntry) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (operation, actor, user_id, params, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING seq
+	`, c.auditTable())
+
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	err := c.db.QueryRowContext(ctx, query,
+		entry.Operation, entry.Actor, entry.UserID, string(entry.Params), entry.CreatedAt,
+	).Scan(&entry.Seq)
+	if err != nil {
+		return fmt.Errorf("AppendAudit: %w", err)
+	}
+	return nil
+}
+
+// ListAudit returns the audit entries after opts.After, in Seq order.
+func (c *Client) ListAudit(ctx context.Context, opts *storage.ListAuditOptions) ([]*storage.AuditEntry, error) {
+	if opts == nil {
+		opts = &storage.ListAuditOptions{}
+	}
+
+	conditions := []string{"seq > $1"}
+	args := []interface{}{opts.After}
+	if opts.Actor != "" {
+		args = append(args, opts.Actor)
+		conditions = append(conditions, fmt.Sprintf("actor = $%d", len(args)))
+	}
+	if opts.UserID != "" {
+		args = append(args, opts.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+	if len(opts.Operations) > 0 {
+		placeholders := make([]string, len(opts.Operations))
+		for i, op := range opts.Operations {
+			args = append(args, op)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, "operation IN ("+strings.Join(placeholders, ", ")+")")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT seq, operation, actor, user_id, params, created_at
+		FROM %s WHERE %s ORDER BY seq
+	`, c.auditTable(), strings.Join(conditions, " AND "))
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListAudit: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []*storage.AuditEntry
+	for rows.Next() {
+		var entry storage.AuditEntry
+		var params string
+		if err := rows.Scan(&entry.Seq, &entry.Operation, &entry.Actor, &entry.UserID, &params, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ListAudit: %w", err)
+		}
+		entry.Params = []byte(params)
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListAudit: %w", err)
+	}
+
+	return entries, nil
+}
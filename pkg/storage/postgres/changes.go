@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// changesTable returns the name of the change log table.
+func (c *Client) changesTable() string {
+	return c.collectionName + "_changes"
+}
+
+// initChangeLog creates the change log table.
+func (c *Client) initChangeLog(ctx context.Context) error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			seq BIGSERIAL PRIMARY KEY,
+			event_type VARCHAR(64) NOT NULL,
+			memory_id BIGINT NOT NULL DEFAULT 0,
+			user_id VARCHAR(255) NOT NULL DEFAULT '',
+			agent_id VARCHAR(255) NOT NULL DEFAULT '',
+			payload JSONB NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)
+	`, c.changesTable())
+	if _, err := c.db.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	indexQuery := fmt.Sprintf(`
+		CREATE INDEX IF NOT EXISTS idx_%s_created_at ON %s(created_at)
+	`, c.changesTable(), c.changesTable())
+	_, err := c.db.ExecContext(ctx, indexQuery)
+	return err
+}
+
+// AppendChange appends a change to the change log and sets its Seq.
+func (c *Client) AppendChange(ctx context.Context, change *storage.Change) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (event_type, memory_id, user_id, agent_id, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING seq
+	`, c.changesTable())
+
+	if change.CreatedAt.IsZero() {
+		change.CreatedAt = time.Now()
+	}
+	err := c.db.QueryRowContext(ctx, query,
+		change.Type, change.MemoryID, change.UserID, change.AgentID, string(change.Payload), change.CreatedAt,
+	).Scan(&change.Seq)
+	if err != nil {
+		return fmt.Errorf("AppendChange: %w", err)
+	}
+	return nil
+}
+
+// ListChanges returns the changes after opts.After, in Seq order.
+func (c *Client) ListChanges(ctx context.Context, opts *storage.ListChangesOptions) ([]*storage.Change, error) {
+	if opts == nil {
+		opts = &storage.ListChangesOptions{}
+	}
+
+	conditions := []string{"seq > $1"}
+	args := []interface{}{opts.After}
+	if opts.UserID != "" {
+		args = append(args, opts.UserID)
+		conditions = append(conditions, fmt.Sprintf("(user_id = $%d OR (memory_id = 0 AND user_id = ''))", len(args)))
+	}
+	if opts.AgentID != "" {
+		args = append(args, opts.AgentID)
+		conditions = append(conditions, fmt.Sprintf("(agent_id = $%d OR (memory_id = 0 AND agent_id = ''))", len(args)))
+	}
+	if len(opts.Types) > 0 {
+		placeholders := make([]string, len(opts.Types))
+		for i, t := range opts.Types {
+			args = append(args, t)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, "event_type IN ("+strings.Join(placeholders, ", ")+")")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT seq, event_type, memory_id, user_id, agent_id, payload, created_at
+		FROM %s WHERE %s ORDER BY seq
+	`, c.changesTable(), strings.Join(conditions, " AND "))
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListChanges: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var changes []*storage.Change
+	for rows.Next() {
+		var change storage.Change
+		var payload string
+		if err := rows.Scan(&change.Seq, &change.Type, &change.MemoryID, &change.UserID, &change.AgentID, &payload, &change.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ListChanges: %w", err)
+		}
+		change.Payload = []byte(payload)
+		changes = append(changes, &change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListChanges: %w", err)
+	}
+
+	return changes, nil
+}
+
+// LastChangeSeq returns the Seq of the latest change, or 0 if there is none.
+func (c *Client) LastChangeSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	query := fmt.Sprintf("SELECT COALESCE(MAX(seq), 0) FROM %s", c.changesTable())
+	if err := c.db.QueryRowContext(ctx, query).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("LastChangeSeq: %w", err)
+	}
+	return seq, nil
+}
+
+// PurgeChanges deletes the changes recorded before the given time.
+func (c *Client) PurgeChanges(ctx context.Context, before time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE created_at < $1", c.changesTable())
+
+	result, err := c.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("PurgeChanges: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("PurgeChanges: %w", err)
+	}
+
+	return deleted, nil
+}
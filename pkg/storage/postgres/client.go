@@ -4,19 +4,52 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/oceanbase/powermem-go/pkg/storage"
 )
 
+// memoryColumns is the column list selected for every memory read.
+// scanMemory expects columns in exactly this order.
+const memoryColumns = `id, user_id, agent_id, content, embedding, metadata,
+		created_at, updated_at, retention_strength, last_accessed_at, tags, expires_at, uid, version, parent_id, hash`
+
+// columnFields maps the columns of memoryColumns that reads can leave out to
+// the field that selects them.
+var columnFields = map[string]storage.Field{
+	"content":          storage.FieldContent,
+	"embedding":        storage.FieldEmbedding,
+	"metadata":         storage.FieldMetadata,
+	"created_at":       storage.FieldTimestamps,
+	"updated_at":       storage.FieldTimestamps,
+	"last_accessed_at": storage.FieldTimestamps,
+	"expires_at":       storage.FieldTimestamps,
+	"tags":             storage.FieldTags,
+}
+
+// selectColumns returns memoryColumns with NULL in place of the columns of
+// the fields not selected by fields and withoutEmbeddings (see
+// storage.SelectedFields), so that they are not read.
+func selectColumns(fields []storage.Field, withoutEmbeddings bool) string {
+	return storage.SelectColumns(memoryColumns, columnFields, storage.SelectedFields(fields, withoutEmbeddings))
+}
+
 // Client is a PostgreSQL + pgvector client.
 type Client struct {
 	db             *sql.DB
+	replicas       *storage.ReplicaSet
 	collectionName string
 	dimensions     int
+
+	// quantization and rerankFactor are Config.Quantization and
+	// Config.RerankFactor, with defaults applied.
+	quantization string
+	rerankFactor int
 }
 
 // Config contains PostgreSQL configuration.
@@ -29,17 +62,47 @@ type Config struct {
 	CollectionName     string
 	EmbeddingModelDims int
 	SSLMode            string
+
+	// DSN is the connection string of the primary. If set, it is used
+	// instead of Host, Port, User, Password, DBName and SSLMode.
+	DSN string
+
+	// ReadDSNs are the connection strings of read replicas of the primary.
+	// Search, Get, GetAll and the other reads are spread over them.
+	ReadDSNs []string
+
+	// MaxReplicaLag is the maximum replication lag of a replica used for
+	// reads, as reported by pg_last_xact_replay_timestamp (0 for no limit).
+	MaxReplicaLag time.Duration
+
+	// Quantization selects the HNSW index created on quantized embeddings
+	// by NewClient: QuantizationHalfvec or QuantizationBit. Searches scan
+	// the index for candidates and re-rank them by exact cosine similarity.
+	// Empty or QuantizationNone creates no index.
+	Quantization string
+
+	// RerankFactor is the number of candidates fetched from the quantized
+	// index per requested result. Default: 4 for halfvec, 10 for bit
+	RerankFactor int
 }
 
 // NewClient creates a new PostgreSQL client.
 func NewClient(cfg *Config) (*Client, error) {
+	quantization, rerankFactor, err := quantizationSettings(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("NewPostgresClient: %w", err)
+	}
+
 	sslMode := cfg.SSLMode
 	if sslMode == "" {
 		sslMode = "disable"
 	}
 
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, sslMode)
+	dsn := cfg.DSN
+	if dsn == "" {
+		dsn = fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+			cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, sslMode)
+	}
 
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
@@ -48,23 +111,57 @@ func NewClient(cfg *Config) (*Client, error) {
 
 	// Test connection
 	if err := db.Ping(); err != nil {
+		_ = db.Close()
 		return nil, fmt.Errorf("NewPostgresClient: %w", err)
 	}
 
+	// Replicas are checked before they serve reads, so an unavailable
+	// replica does not prevent the client from starting.
+	var replicas []*sql.DB
+	for _, readDSN := range cfg.ReadDSNs {
+		replica, err := sql.Open("postgres", readDSN)
+		if err != nil {
+			_ = storage.NewReplicaSet(db, replicas, 0, nil).Close()
+			return nil, fmt.Errorf("NewPostgresClient: replica: %w", err)
+		}
+		replicas = append(replicas, replica)
+	}
+
 	client := &Client{
 		db:             db,
+		replicas:       storage.NewReplicaSet(db, replicas, cfg.MaxReplicaLag, replicaLag),
 		collectionName: cfg.CollectionName,
 		dimensions:     cfg.EmbeddingModelDims,
+		quantization:   quantization,
+		rerankFactor:   rerankFactor,
 	}
 
 	// Initialize pgvector extension and table structure
 	if err := client.initTables(context.Background()); err != nil {
+		_ = client.Close()
 		return nil, err
 	}
 
 	return client, nil
 }
 
+// replicaLag returns the replication lag of a standby: zero if it has
+// replayed all the WAL it received, the age of the last replayed transaction
+// otherwise.
+func replicaLag(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	var seconds float64
+	err := db.QueryRowContext(ctx, `
+		SELECT CASE
+			WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+		END
+	`).Scan(&seconds)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
 // initTables initializes the database table.
 // If the table already exists with an incompatible schema (e.g. missing user_id),
 // it is dropped and recreated.
@@ -87,7 +184,13 @@ func (c *Client) initTables(ctx context.Context) error {
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			retention_strength FLOAT DEFAULT 1.0,
-			last_accessed_at TIMESTAMP
+			last_accessed_at TIMESTAMP,
+			tags JSONB DEFAULT '[]'::jsonb,
+			expires_at TIMESTAMP,
+			uid VARCHAR(64),
+			version BIGINT NOT NULL DEFAULT 1,
+			parent_id BIGINT,
+			hash VARCHAR(32)
 		)
 	`, c.collectionName, c.dimensions)
 
@@ -113,6 +216,85 @@ func (c *Client) initTables(ctx context.Context) error {
 		return fmt.Errorf("initTables: create index: %w", err)
 	}
 
+	// Add columns introduced after the original schema to existing tables
+	alterQuery := fmt.Sprintf(`
+		ALTER TABLE %s
+			ADD COLUMN IF NOT EXISTS tags JSONB DEFAULT '[]'::jsonb,
+			ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP,
+			ADD COLUMN IF NOT EXISTS uid VARCHAR(64),
+			ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1,
+			ADD COLUMN IF NOT EXISTS parent_id BIGINT,
+			ADD COLUMN IF NOT EXISTS hash VARCHAR(32)
+	`, c.collectionName)
+	if _, err := c.db.ExecContext(ctx, alterQuery); err != nil {
+		return fmt.Errorf("initTables: add columns: %w", err)
+	}
+
+	// Hash the memories stored before the hash column existed, as
+	// storage.ContentHash does, so that GetByHash finds them too
+	backfillQuery := fmt.Sprintf(`
+		UPDATE %s SET hash = md5(content) WHERE hash IS NULL
+	`, c.collectionName)
+	if _, err := c.db.ExecContext(ctx, backfillQuery); err != nil {
+		return fmt.Errorf("initTables: backfill hashes: %w", err)
+	}
+
+	// GIN index for tag containment filters
+	tagsIndexQuery := fmt.Sprintf(`
+		CREATE INDEX IF NOT EXISTS idx_%s_tags ON %s USING GIN (tags)
+	`, c.collectionName, c.collectionName)
+	if _, err := c.db.ExecContext(ctx, tagsIndexQuery); err != nil {
+		return fmt.Errorf("initTables: create tags index: %w", err)
+	}
+
+	// Partial index so expiration checks and purges skip non-expiring rows
+	expiresIndexQuery := fmt.Sprintf(`
+		CREATE INDEX IF NOT EXISTS idx_%s_expires_at ON %s(expires_at) WHERE expires_at IS NOT NULL
+	`, c.collectionName, c.collectionName)
+	if _, err := c.db.ExecContext(ctx, expiresIndexQuery); err != nil {
+		return fmt.Errorf("initTables: create expires_at index: %w", err)
+	}
+
+	// Unique index for string IDs (NULL for memories without one)
+	uidIndexQuery := fmt.Sprintf(`
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_uid ON %s(uid)
+	`, c.collectionName, c.collectionName)
+	if _, err := c.db.ExecContext(ctx, uidIndexQuery); err != nil {
+		return fmt.Errorf("initTables: create uid index: %w", err)
+	}
+
+	// Partial index to find the chunks of a memory
+	parentIndexQuery := fmt.Sprintf(`
+		CREATE INDEX IF NOT EXISTS idx_%s_parent_id ON %s(parent_id) WHERE parent_id IS NOT NULL
+	`, c.collectionName, c.collectionName)
+	if _, err := c.db.ExecContext(ctx, parentIndexQuery); err != nil {
+		return fmt.Errorf("initTables: create parent_id index: %w", err)
+	}
+
+	// Index to find the memories of a user by content hash
+	hashIndexQuery := fmt.Sprintf(`
+		CREATE INDEX IF NOT EXISTS idx_%s_user_hash ON %s(user_id, hash)
+	`, c.collectionName, c.collectionName)
+	if _, err := c.db.ExecContext(ctx, hashIndexQuery); err != nil {
+		return fmt.Errorf("initTables: create hash index: %w", err)
+	}
+
+	if err := c.initChangeLog(ctx); err != nil {
+		return fmt.Errorf("initTables: create change log: %w", err)
+	}
+
+	if err := c.initAuditLog(ctx); err != nil {
+		return fmt.Errorf("initTables: create audit log: %w", err)
+	}
+
+	if err := c.initTeams(ctx); err != nil {
+		return fmt.Errorf("initTables: create teams table: %w", err)
+	}
+
+	if err := c.initQuantizedIndex(ctx); err != nil {
+		return fmt.Errorf("initTables: create quantized index: %w", err)
+	}
+
 	return nil
 }
 
@@ -120,8 +302,8 @@ func (c *Client) initTables(ctx context.Context) error {
 func (c *Client) Insert(ctx context.Context, memory *storage.Memory) error {
 	query := fmt.Sprintf(`
 		INSERT INTO %s 
-		(id, user_id, agent_id, content, embedding, metadata, created_at, retention_strength)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		(id, user_id, agent_id, content, embedding, metadata, created_at, updated_at, version, retention_strength, tags, expires_at, uid, parent_id, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`, c.collectionName)
 
 	// Convert vector to PostgreSQL vector format: "[0.1,0.2,0.3,...]"
@@ -132,6 +314,13 @@ func (c *Client) Insert(ctx context.Context, memory *storage.Memory) error {
 		return fmt.Errorf("Insert: %w", err)
 	}
 
+	tagsJSON, err := marshalTags(memory.Tags)
+	if err != nil {
+		return fmt.Errorf("Insert: %w", err)
+	}
+
+	createdAt, updatedAt, version := storage.InsertTimes(memory, time.Now())
+
 	_, err = c.db.ExecContext(ctx, query,
 		memory.ID,
 		memory.UserID,
@@ -139,10 +328,23 @@ func (c *Client) Insert(ctx context.Context, memory *storage.Memory) error {
 		memory.Content,
 		vectorStr,
 		string(metadataJSON),
-		time.Now(),
+		createdAt,
+		updatedAt,
+		version,
 		memory.RetentionStrength,
+		tagsJSON,
+		memory.ExpiresAt,
+		nullableUID(memory.UID),
+		nullableParentID(memory.ParentID),
+		storage.ContentHash(memory.Content),
 	)
 
+	if c.isDuplicateID(err) {
+		return fmt.Errorf("Insert: %w: %v", storage.ErrDuplicateID, err)
+	}
+	if c.isDuplicateUID(err) {
+		return fmt.Errorf("Insert: %w: %v", storage.ErrDuplicateUID, err)
+	}
 	if err != nil {
 		return fmt.Errorf("Insert: %w", err)
 	}
@@ -150,6 +352,27 @@ func (c *Client) Insert(ctx context.Context, memory *storage.Memory) error {
 	return nil
 }
 
+// isDuplicateID reports whether err is the error of an insert whose ID is
+// already taken: a unique violation of the primary key of the memories
+// table.
+func (c *Client) isDuplicateID(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == "23505" && pqErr.Constraint == c.collectionName+"_pkey"
+}
+
+// isDuplicateUID reports whether err is the error of an insert whose UID is
+// already taken: a unique violation of the uid index.
+func (c *Client) isDuplicateUID(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == "23505" && pqErr.Constraint == "idx_"+c.collectionName+"_uid"
+}
+
 // Search performs vector search using pgvector's cosine similarity.
 //
 // The method supports hybrid search parameters for future enhancement:
@@ -159,7 +382,20 @@ func (c *Client) Insert(ctx context.Context, memory *storage.Memory) error {
 //
 // Currently, only vector similarity search is implemented using pgvector.
 // Hybrid search (vector + full-text + sparse) will be added in future versions.
+//
+// With Config.Quantization, the first page of results (opts.After is nil)
+// is re-ranked from candidates found with the quantized index; other pages
+// are exact, since keyset pagination needs an exact order.
 func (c *Client) Search(ctx context.Context, embedding []float64, opts *storage.SearchOptions) ([]*storage.Memory, error) {
+	if c.quantization != QuantizationNone && opts.After == nil {
+		return c.searchQuantized(ctx, embedding, opts)
+	}
+	return c.searchExact(ctx, embedding, opts)
+}
+
+// searchExact performs vector search computing the exact cosine similarity
+// of every candidate row.
+func (c *Client) searchExact(ctx context.Context, embedding []float64, opts *storage.SearchOptions) ([]*storage.Memory, error) {
 	// Use Threshold if MinScore is not set (Python SDK compatibility)
 	minScore := opts.MinScore
 	if minScore == 0 && opts.Threshold > 0 {
@@ -168,8 +404,18 @@ func (c *Client) Search(ctx context.Context, embedding []float64, opts *storage.
 
 	queryVectorStr := vectorToString(embedding)
 
+	filter := whereFilter{
+		userID:    opts.UserID,
+		teamIDs:   opts.TeamIDs,
+		agentID:   opts.AgentID,
+		filters:   opts.Filters,
+		timeRange: opts.TimeRange,
+		tags:      opts.Tags,
+		activeAt:  storage.ActiveAt(opts.Now),
+	}
+
 	// Build WHERE clause (starting from $2 since $1 is the query vector)
-	whereClause, filterArgs := buildWhereClauseWithOffset(opts.UserID, opts.AgentID, opts.Filters, 2)
+	whereClause, filterArgs := buildWhereClauseWithOffset(filter, 2)
 
 	// Add similarity threshold to WHERE clause if specified
 	if minScore > 0 {
@@ -182,17 +428,29 @@ func (c *Client) Search(ctx context.Context, embedding []float64, opts *storage.
 		filterArgs = append(filterArgs, minScore)
 	}
 
-	// Use pgvector's <=> operator (cosine distance, 1 - cosine similarity)
+	// Keyset pagination: only memories ranked after the cursor
+	if opts.After != nil {
+		paramNum := len(filterArgs) + 2
+		condition := fmt.Sprintf("(1 - (embedding <=> $1) < $%d OR (1 - (embedding <=> $1) = $%d AND id > $%d))",
+			paramNum, paramNum, paramNum+1)
+		if whereClause == "" {
+			whereClause = "WHERE " + condition
+		} else {
+			whereClause += " AND " + condition
+		}
+		filterArgs = append(filterArgs, opts.After.Score, opts.After.ID)
+	}
+
+	// Use pgvector's <=> operator (cosine distance, 1 - cosine similarity).
+	// Ties are broken by ID so that results have a stable order for paging.
 	query := fmt.Sprintf(`
-		SELECT 
-			id, user_id, agent_id, content, embedding, metadata,
-			created_at, updated_at, retention_strength, last_accessed_at,
+		SELECT %s,
 			1 - (embedding <=> $1) as similarity
 		FROM %s
 		%s
-		ORDER BY embedding <=> $1
+		ORDER BY embedding <=> $1, id
 		LIMIT $%d
-	`, c.collectionName, whereClause, len(filterArgs)+2)
+	`, selectColumns(opts.Fields, opts.WithoutEmbeddings), c.collectionName, whereClause, len(filterArgs)+2)
 
 	// TODO: Future enhancement - add full-text search support
 	// if opts.Query != "" {
@@ -208,9 +466,17 @@ func (c *Client) Search(ctx context.Context, embedding []float64, opts *storage.
 	// Build final args: query vector, filter args, then limit
 	allArgs := []interface{}{queryVectorStr}
 	allArgs = append(allArgs, filterArgs...)
+
+	db := c.replicas.Reader(ctx)
+	if opts.Stats != nil {
+		if err := c.searchStats(ctx, db, filter, whereClause, allArgs, minScore > 0, opts.Stats); err != nil {
+			return nil, fmt.Errorf("Search: %w", err)
+		}
+	}
+
 	allArgs = append(allArgs, opts.Limit)
 
-	rows, err := c.db.QueryContext(ctx, query, allArgs...)
+	rows, err := db.QueryContext(ctx, query, allArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("Search: %w", err)
 	}
@@ -219,15 +485,102 @@ func (c *Client) Search(ctx context.Context, embedding []float64, opts *storage.
 	return c.scanMemories(rows, true)
 }
 
+// SearchIter performs vector similarity search, fetching results from the
+// database one batch at a time.
+//
+// Batches are fetched with exact searches, as they are paged.
+func (c *Client) SearchIter(ctx context.Context, embedding []float64, opts *storage.SearchOptions, batchSize int) (storage.MemoryIterator, error) {
+	return storage.NewSearchIterator(c.searchExact, embedding, opts, batchSize)
+}
+
+// searchStats fills stats for a Search call, reading from db. scoredWhere and scoredArgs are the
+// WHERE clause and arguments of the search, including the similarity threshold
+// if hasThreshold is true.
+func (c *Client) searchStats(ctx context.Context, db *sql.DB, filter whereFilter, scoredWhere string, scoredArgs []interface{}, hasThreshold bool, stats *storage.SearchStats) error {
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s", c.collectionName)
+	if err := db.QueryRowContext(ctx, countQuery).Scan(&stats.TotalMemories); err != nil {
+		return err
+	}
+
+	whereClause, args := buildWhereClause(filter)
+	countQuery = fmt.Sprintf("SELECT COUNT(*) FROM %s %s", c.collectionName, whereClause)
+	if err := db.QueryRowContext(ctx, countQuery, args...).Scan(&stats.Candidates); err != nil {
+		return err
+	}
+
+	if !hasThreshold {
+		stats.AboveThreshold = stats.Candidates
+		return nil
+	}
+	countQuery = fmt.Sprintf("SELECT COUNT(*) FROM %s %s", c.collectionName, scoredWhere)
+	return db.QueryRowContext(ctx, countQuery, scoredArgs...).Scan(&stats.AboveThreshold)
+}
+
+// SearchByKeyword performs a case-insensitive keyword search on memory content using ILIKE.
+func (c *Client) SearchByKeyword(ctx context.Context, text string, opts *storage.SearchOptions) ([]*storage.Memory, error) {
+	whereClause, args := buildWhereClause(whereFilter{
+		userID:    opts.UserID,
+		teamIDs:   opts.TeamIDs,
+		agentID:   opts.AgentID,
+		filters:   opts.Filters,
+		timeRange: opts.TimeRange,
+		tags:      opts.Tags,
+		activeAt:  storage.ActiveAt(opts.Now),
+
+		excludeChunks: true,
+	})
+
+	if whereClause == "" {
+		whereClause = fmt.Sprintf("WHERE content ILIKE $%d", len(args)+1)
+	} else {
+		whereClause += fmt.Sprintf(" AND content ILIKE $%d", len(args)+1)
+	}
+	args = append(args, "%"+escapeLikePattern(text)+"%")
+
+	query := fmt.Sprintf(`
+		SELECT %s,
+		       1.0 as similarity
+		FROM %s
+		%s
+		ORDER BY created_at DESC
+		LIMIT $%d
+	`, selectColumns(opts.Fields, opts.WithoutEmbeddings), c.collectionName, whereClause, len(args)+1)
+
+	args = append(args, opts.Limit)
+
+	rows, err := c.replicas.Reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SearchByKeyword: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return c.scanMemories(rows, true)
+}
+
 // Get retrieves a memory by ID with optional access control.
 func (c *Client) Get(ctx context.Context, id int64, opts *storage.GetOptions) (*storage.Memory, error) {
+	return c.getOne(ctx, "Get", "id", id, opts)
+}
+
+// GetByUID retrieves a memory by its string ID with optional access control.
+func (c *Client) GetByUID(ctx context.Context, uid string, opts *storage.GetOptions) (*storage.Memory, error) {
+	return c.getOne(ctx, "GetByUID", "uid", uid, opts)
+}
+
+// GetByHash retrieves a memory by content hash with optional access control.
+func (c *Client) GetByHash(ctx context.Context, hash string, opts *storage.GetOptions) (*storage.Memory, error) {
+	return c.getOne(ctx, "GetByHash", "hash", hash, opts)
+}
+
+// getOne retrieves the memory whose key column equals value.
+func (c *Client) getOne(ctx context.Context, op, column string, value interface{}, opts *storage.GetOptions) (*storage.Memory, error) {
 	if opts == nil {
 		opts = &storage.GetOptions{}
 	}
 
 	// Build WHERE clause with access control
-	whereClause := "WHERE id = $1"
-	args := []interface{}{id}
+	whereClause := fmt.Sprintf("WHERE %s = $1", column)
+	args := []interface{}{value}
 	paramNum := 2
 
 	if opts.UserID != "" {
@@ -238,28 +591,116 @@ func (c *Client) Get(ctx context.Context, id int64, opts *storage.GetOptions) (*
 	if opts.AgentID != "" {
 		whereClause += fmt.Sprintf(" AND agent_id = $%d", paramNum)
 		args = append(args, opts.AgentID)
+		paramNum++
 	}
 
+	// Expired memories are treated as not found
+	whereClause += fmt.Sprintf(" AND (expires_at IS NULL OR expires_at > $%d)", paramNum)
+	args = append(args, storage.ActiveAt(opts.Now))
+
 	query := fmt.Sprintf(`
-		SELECT id, user_id, agent_id, content, embedding, metadata,
-		       created_at, updated_at, retention_strength, last_accessed_at
+		SELECT %s
 		FROM %s
 		%s
-	`, c.collectionName, whereClause)
+	`, memoryColumns, c.collectionName, whereClause)
 
-	row := c.db.QueryRowContext(ctx, query, args...)
+	row := c.replicas.Reader(ctx).QueryRowContext(ctx, query, args...)
 
 	memory, err := c.scanMemory(row)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("Get: not found or access denied")
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrNotFound)
 	}
 	if err != nil {
-		return nil, fmt.Errorf("Get: %w", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return memory, nil
 }
 
+// ListTags returns the distinct tags in use, sorted alphabetically.
+//
+// If userID is empty, tags across all users are returned.
+func (c *Client) ListTags(ctx context.Context, userID string) ([]string, error) {
+	whereClause, args := buildWhereClause(whereFilter{userID: userID, activeAt: time.Now()})
+
+	query := fmt.Sprintf(`
+		SELECT DISTINCT t.tag
+		FROM %s, jsonb_array_elements_text(COALESCE(tags, '[]'::jsonb)) AS t(tag)
+		%s
+		ORDER BY t.tag
+	`, c.collectionName, whereClause)
+
+	rows, err := c.replicas.Reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListTags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("ListTags: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListTags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetMany retrieves multiple memories by ID using a single IN query.
+func (c *Client) GetMany(ctx context.Context, ids []int64, opts *storage.GetOptions) ([]*storage.Memory, error) {
+	if opts == nil {
+		opts = &storage.GetOptions{}
+	}
+	if len(ids) == 0 {
+		return []*storage.Memory{}, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, 0, len(ids)+2)
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args = append(args, id)
+	}
+	paramNum := len(ids) + 1
+
+	whereClause := fmt.Sprintf("WHERE id IN (%s)", strings.Join(placeholders, ", "))
+
+	if opts.UserID != "" {
+		whereClause += fmt.Sprintf(" AND user_id = $%d", paramNum)
+		args = append(args, opts.UserID)
+		paramNum++
+	}
+	if opts.AgentID != "" {
+		whereClause += fmt.Sprintf(" AND agent_id = $%d", paramNum)
+		args = append(args, opts.AgentID)
+		paramNum++
+	}
+
+	// Expired memories are treated as not found
+	whereClause += fmt.Sprintf(" AND (expires_at IS NULL OR expires_at > $%d)", paramNum)
+	args = append(args, storage.ActiveAt(opts.Now))
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		%s
+	`, memoryColumns, c.collectionName, whereClause)
+
+	rows, err := c.replicas.Reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("GetMany: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return c.scanMemories(rows, false)
+}
+
 // Update updates a memory with optional access control.
 func (c *Client) Update(ctx context.Context, id int64, content string, embedding []float64, opts *storage.UpdateOptions) (*storage.Memory, error) {
 	if opts == nil {
@@ -268,11 +709,39 @@ func (c *Client) Update(ctx context.Context, id int64, content string, embedding
 
 	vectorStr := vectorToString(embedding)
 
-	// Build WHERE clause with access control
-	whereClause := "WHERE id = $4"
-	args := []interface{}{content, vectorStr, time.Now(), id}
+	// created_at is intentionally never part of the SET clause
+	setClause := "SET content = $1, embedding = $2, updated_at = $3, hash = $4, version = version + 1"
+	args := []interface{}{content, vectorStr, storage.UpdateTime(opts), storage.ContentHash(content)}
 	paramNum := 5
 
+	if opts.Metadata != nil {
+		metadataJSON, err := json.Marshal(opts.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("Update: %w", err)
+		}
+		setClause += fmt.Sprintf(", metadata = $%d", paramNum)
+		args = append(args, string(metadataJSON))
+		paramNum++
+	}
+	if opts.RetentionStrength != nil {
+		setClause += fmt.Sprintf(", retention_strength = $%d", paramNum)
+		args = append(args, *opts.RetentionStrength)
+		paramNum++
+	}
+	if opts.LastAccessedAt != nil {
+		setClause += fmt.Sprintf(", last_accessed_at = $%d", paramNum)
+		args = append(args, *opts.LastAccessedAt)
+		paramNum++
+	}
+	if opts.ClearExpiresAt {
+		setClause += ", expires_at = NULL"
+	}
+
+	// Build WHERE clause with access control
+	whereClause := fmt.Sprintf("WHERE id = $%d", paramNum)
+	args = append(args, id)
+	paramNum++
+
 	if opts.UserID != "" {
 		whereClause += fmt.Sprintf(" AND user_id = $%d", paramNum)
 		args = append(args, opts.UserID)
@@ -281,13 +750,18 @@ func (c *Client) Update(ctx context.Context, id int64, content string, embedding
 	if opts.AgentID != "" {
 		whereClause += fmt.Sprintf(" AND agent_id = $%d", paramNum)
 		args = append(args, opts.AgentID)
+		paramNum++
+	}
+	if opts.ExpectedVersion > 0 {
+		whereClause += fmt.Sprintf(" AND version = $%d", paramNum)
+		args = append(args, opts.ExpectedVersion)
 	}
 
 	query := fmt.Sprintf(`
 		UPDATE %s
-		SET content = $1, embedding = $2, updated_at = $3
 		%s
-	`, c.collectionName, whereClause)
+		%s
+	`, c.collectionName, setClause, whereClause)
 
 	result, err := c.db.ExecContext(ctx, query, args...)
 	if err != nil {
@@ -300,7 +774,7 @@ func (c *Client) Update(ctx context.Context, id int64, content string, embedding
 	}
 
 	if rowsAffected == 0 {
-		return nil, fmt.Errorf("Update: not found or access denied")
+		return nil, c.updateMissError(ctx, id, opts)
 	}
 
 	return c.Get(ctx, id, &storage.GetOptions{
@@ -309,6 +783,17 @@ func (c *Client) Update(ctx context.Context, id int64, content string, embedding
 	})
 }
 
+// updateMissError explains why Update matched no row: a version conflict if
+// the memory is still accessible, "not found or access denied" otherwise.
+func (c *Client) updateMissError(ctx context.Context, id int64, opts *storage.UpdateOptions) error {
+	if opts.ExpectedVersion > 0 {
+		if _, err := c.Get(storage.ContextWithPrimary(ctx), id, &storage.GetOptions{UserID: opts.UserID, AgentID: opts.AgentID}); err == nil {
+			return fmt.Errorf("Update: %w", storage.ErrVersionConflict)
+		}
+	}
+	return fmt.Errorf("Update: %w", storage.ErrNotFound)
+}
+
 // Delete deletes a memory with optional access control.
 func (c *Client) Delete(ctx context.Context, id int64, opts *storage.DeleteOptions) error {
 	if opts == nil {
@@ -343,7 +828,7 @@ func (c *Client) Delete(ctx context.Context, id int64, opts *storage.DeleteOptio
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("Delete: not found or access denied")
+		return fmt.Errorf("Delete: %w", storage.ErrNotFound)
 	}
 
 	return nil
@@ -351,20 +836,31 @@ func (c *Client) Delete(ctx context.Context, id int64, opts *storage.DeleteOptio
 
 // GetAll retrieves all memories.
 func (c *Client) GetAll(ctx context.Context, opts *storage.GetAllOptions) ([]*storage.Memory, error) {
-	whereClause, args := buildWhereClause(opts.UserID, opts.AgentID, nil)
+	whereClause, args := buildWhereClause(whereFilter{
+		userID:    opts.UserID,
+		teamIDs:   opts.TeamIDs,
+		agentID:   opts.AgentID,
+		timeRange: opts.TimeRange,
+		tags:      opts.Tags,
+		filters:   opts.Filters,
+		entities:  opts.Entities,
+		activeAt:  storage.ActiveAt(opts.Now),
+
+		parentID:      opts.ParentID,
+		excludeChunks: true,
+	})
 
 	query := fmt.Sprintf(`
-		SELECT id, user_id, agent_id, content, embedding, metadata,
-		       created_at, updated_at, retention_strength, last_accessed_at
+		SELECT %s
 		FROM %s
 		%s
 		ORDER BY created_at DESC
 		LIMIT $%d OFFSET $%d
-	`, c.collectionName, whereClause, len(args)+1, len(args)+2)
+	`, selectColumns(opts.Fields, opts.WithoutEmbeddings), c.collectionName, whereClause, len(args)+1, len(args)+2)
 
 	args = append(args, opts.Limit, opts.Offset)
 
-	rows, err := c.db.QueryContext(ctx, query, args...)
+	rows, err := c.replicas.Reader(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("GetAll: %w", err)
 	}
@@ -375,7 +871,7 @@ func (c *Client) GetAll(ctx context.Context, opts *storage.GetAllOptions) ([]*st
 
 // DeleteAll deletes all memories.
 func (c *Client) DeleteAll(ctx context.Context, opts *storage.DeleteAllOptions) error {
-	whereClause, args := buildWhereClause(opts.UserID, opts.AgentID, nil)
+	whereClause, args := buildWhereClause(whereFilter{userID: opts.UserID, agentID: opts.AgentID, parentID: opts.ParentID})
 
 	query := fmt.Sprintf("DELETE FROM %s %s", c.collectionName, whereClause)
 
@@ -387,10 +883,35 @@ func (c *Client) DeleteAll(ctx context.Context, opts *storage.DeleteAllOptions)
 	return nil
 }
 
-// Close closes the database connection.
+// PurgeExpired permanently deletes memories that expired at or before the given time.
+func (c *Client) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE expires_at IS NOT NULL AND expires_at <= $1", c.collectionName)
+
+	result, err := c.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("PurgeExpired: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("PurgeExpired: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// Ping verifies that the database connection is alive.
+func (c *Client) Ping(ctx context.Context) error {
+	if err := c.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("Ping: %w", err)
+	}
+	return nil
+}
+
+// Close closes the database connections.
 func (c *Client) Close() error {
-	if c.db != nil {
-		return c.db.Close()
+	if c.replicas != nil {
+		return c.replicas.Close()
 	}
 	return nil
 }
@@ -426,43 +947,76 @@ func vectorToString(vector []float64) string {
 		return "[]"
 	}
 
-	parts := make([]string, len(vector))
+	// About 10 bytes per value, e.g. "-0.123456,"
+	buf := make([]byte, 0, 2+10*len(vector))
+	buf = append(buf, '[')
 	for i, v := range vector {
-		parts[i] = fmt.Sprintf("%f", v)
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = strconv.AppendFloat(buf, v, 'f', 6, 64)
 	}
+	buf = append(buf, ']')
+	return string(buf)
+}
 
-	return "[" + strings.Join(parts, ",") + "]"
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
 }
 
-// scanMemory scans a single memory.
-func (c *Client) scanMemory(row *sql.Row) (*storage.Memory, error) {
+// scanMemory scans a row selected with memoryColumns into a Memory.
+//
+// extra receives any additional columns selected after memoryColumns.
+func (c *Client) scanMemory(row rowScanner, extra ...interface{}) (*storage.Memory, error) {
 	var memory storage.Memory
-	var embeddingStr string
+	var content sql.NullString
+	var embeddingStr sql.NullString
 	var metadataStr []byte
+	var createdAt sql.NullTime
+	var updatedAt sql.NullTime
 	var lastAccessedAt sql.NullTime
+	var tagsStr []byte
+	var expiresAt sql.NullTime
+	var uid sql.NullString
+	var parentID sql.NullInt64
+	var hash sql.NullString
 
-	err := row.Scan(
+	dest := []interface{}{
 		&memory.ID,
 		&memory.UserID,
 		&memory.AgentID,
-		&memory.Content,
+		&content,
 		&embeddingStr,
 		&metadataStr,
-		&memory.CreatedAt,
-		&memory.UpdatedAt,
+		&createdAt,
+		&updatedAt,
 		&memory.RetentionStrength,
 		&lastAccessedAt,
-	)
-	if err != nil {
+		&tagsStr,
+		&expiresAt,
+		&uid,
+		&memory.Version,
+		&parentID,
+		&hash,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
 		return nil, err
 	}
 
-	// Parse embedding (pgvector returns string format)
-	embedding, err := parseVectorString(embeddingStr)
-	if err != nil {
-		return nil, fmt.Errorf("parse embedding: %w", err)
+	// Columns of fields that were not selected are NULL
+	memory.Content = content.String
+	memory.CreatedAt = createdAt.Time
+	memory.UpdatedAt = updatedAt.Time
+
+	// Parse embedding (pgvector returns string format), if it was selected
+	if embeddingStr.String != "" {
+		embedding, err := parseVectorString(embeddingStr.String)
+		if err != nil {
+			return nil, fmt.Errorf("parse embedding: %w", err)
+		}
+		memory.Embedding = embedding
 	}
-	memory.Embedding = embedding
 
 	// Parse metadata
 	if len(metadataStr) > 0 {
@@ -471,81 +1025,58 @@ func (c *Client) scanMemory(row *sql.Row) (*storage.Memory, error) {
 		}
 	}
 
+	// Parse tags
+	if len(tagsStr) > 0 {
+		if err := json.Unmarshal(tagsStr, &memory.Tags); err != nil {
+			return nil, fmt.Errorf("parse tags: %w", err)
+		}
+	}
+
 	// Handle last_accessed_at
 	if lastAccessedAt.Valid {
 		memory.LastAccessedAt = &lastAccessedAt.Time
 	}
 
+	// Handle expires_at
+	if expiresAt.Valid {
+		memory.ExpiresAt = &expiresAt.Time
+	}
+
+	if uid.Valid {
+		memory.UID = uid.String
+	}
+	memory.ParentID = parentID.Int64
+	memory.Hash = hash.String
+	memory.RunID, _ = memory.Metadata[storage.RunIDKey].(string)
+
 	return &memory, nil
 }
 
 // scanMemories scans multiple memories.
+//
+// If hasScore is true, a trailing similarity column is expected after memoryColumns.
 func (c *Client) scanMemories(rows *sql.Rows, hasScore bool) ([]*storage.Memory, error) {
 	var memories []*storage.Memory
 
 	for rows.Next() {
-		var memory storage.Memory
-		var embeddingStr string
-		var metadataStr []byte
-		var lastAccessedAt sql.NullTime
-		var similarity float64
+		var memory *storage.Memory
+		var err error
 
 		if hasScore {
-			err := rows.Scan(
-				&memory.ID,
-				&memory.UserID,
-				&memory.AgentID,
-				&memory.Content,
-				&embeddingStr,
-				&metadataStr,
-				&memory.CreatedAt,
-				&memory.UpdatedAt,
-				&memory.RetentionStrength,
-				&lastAccessedAt,
-				&similarity,
-			)
+			var similarity float64
+			memory, err = c.scanMemory(rows, &similarity)
 			if err != nil {
 				return nil, err
 			}
 			memory.Score = similarity
 		} else {
-			err := rows.Scan(
-				&memory.ID,
-				&memory.UserID,
-				&memory.AgentID,
-				&memory.Content,
-				&embeddingStr,
-				&metadataStr,
-				&memory.CreatedAt,
-				&memory.UpdatedAt,
-				&memory.RetentionStrength,
-				&lastAccessedAt,
-			)
+			memory, err = c.scanMemory(rows)
 			if err != nil {
 				return nil, err
 			}
 		}
 
-		// Parse embedding
-		embedding, err := parseVectorString(embeddingStr)
-		if err != nil {
-			return nil, fmt.Errorf("parse embedding: %w", err)
-		}
-		memory.Embedding = embedding
-
-		// Parse metadata
-		if len(metadataStr) > 0 {
-			if err := json.Unmarshal(metadataStr, &memory.Metadata); err != nil {
-				return nil, fmt.Errorf("parse metadata: %w", err)
-			}
-		}
-
-		// Handle last_accessed_at
-		if lastAccessedAt.Valid {
-			memory.LastAccessedAt = &lastAccessedAt.Time
-		}
-
-		memories = append(memories, &memory)
+		memories = append(memories, memory)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -563,16 +1094,19 @@ func parseVectorString(s string) ([]float64, error) {
 		return []float64{}, nil
 	}
 
-	parts := strings.Split(s, ",")
-	result := make([]float64, len(parts))
-
-	for i, part := range parts {
-		var val float64
-		_, err := fmt.Sscanf(strings.TrimSpace(part), "%f", &val)
+	result := make([]float64, 0, strings.Count(s, ",")+1)
+	for len(s) > 0 {
+		part := s
+		if i := strings.IndexByte(s, ','); i >= 0 {
+			part, s = s[:i], s[i+1:]
+		} else {
+			s = ""
+		}
+		val, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
 		if err != nil {
 			return nil, err
 		}
-		result[i] = val
+		result = append(result, val)
 	}
 
 	return result, nil
@@ -581,7 +1115,8 @@ func parseVectorString(s string) ([]float64, error) {
 // Reset resets the vector store by dropping and recreating the table.
 //
 // WARNING: This operation will delete ALL memories and cannot be undone.
-// The table will be recreated with the same schema and indexes.
+// The table will be recreated with the same schema and indexes. The change
+// log is kept.
 func (c *Client) Reset(ctx context.Context) error {
 	// Drop the table
 	dropQuery := fmt.Sprintf("DROP TABLE IF EXISTS %s", c.collectionName)
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+)
+
+// ListCollections returns the names of the collections of the current
+// schema, sorted: the tables that have a change log table.
+func (c *Client) ListCollections(ctx context.Context) ([]string, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT t.table_name FROM information_schema.tables t
+		WHERE t.table_schema = current_schema()
+		  AND EXISTS (
+			SELECT 1 FROM information_schema.tables l
+			WHERE l.table_schema = t.table_schema AND l.table_name = t.table_name || '_changes'
+		  )
+		ORDER BY t.table_name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("ListCollections: %w", err)
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("ListCollections: %w", err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListCollections: %w", err)
+	}
+	return names, nil
+}
+
+// DropCollection drops the memory table, the change log table and the team
+// membership table of a collection in a single transaction.
+func (c *Client) DropCollection(ctx context.Context, name string) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("DropCollection: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, table := range []string{name, name + "_changes", name + "_teams"} {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s", table)); err != nil {
+			return fmt.Errorf("DropCollection: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("DropCollection: %w", err)
+	}
+	return nil
+}
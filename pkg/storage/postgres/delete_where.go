@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// DeleteWhere deletes the memories matching opts with a single DELETE
+// statement, or only counts them if opts.DryRun is set.
+//
+// Chunks are not counted, but deleted with their memory.
+//
+// The deletion is rolled back, and ErrCountMismatch returned, if it does not
+// delete exactly opts.ExpectedCount memories.
+func (c *Client) DeleteWhere(ctx context.Context, opts *storage.DeleteWhereOptions) (int64, error) {
+	whereClause, args := buildWhereClause(whereFilter{
+		userID:    opts.UserID,
+		agentID:   opts.AgentID,
+		filters:   opts.Filters,
+		timeRange: opts.TimeRange,
+		tags:      opts.Tags,
+
+		excludeChunks: true,
+	})
+
+	if opts.DryRun {
+		var count int64
+		query := fmt.Sprintf("SELECT COUNT(*) FROM %s %s", c.collectionName, whereClause)
+		if err := c.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+			return 0, fmt.Errorf("DeleteWhere: %w", err)
+		}
+		return count, nil
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("DeleteWhere: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("DELETE FROM %s %s", c.collectionName, whereClause)
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("DeleteWhere: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("DeleteWhere: %w", err)
+	}
+	if deleted != opts.ExpectedCount {
+		return deleted, fmt.Errorf("DeleteWhere: %w: expected %d, matched %d",
+			storage.ErrCountMismatch, opts.ExpectedCount, deleted)
+	}
+
+	// Delete the chunks of the deleted memories
+	query = fmt.Sprintf("DELETE FROM %s WHERE parent_id IS NOT NULL AND parent_id NOT IN (SELECT id FROM %s)",
+		c.collectionName, c.collectionName)
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return 0, fmt.Errorf("DeleteWhere: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("DeleteWhere: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// EraseUser permanently deletes the memories, the change log entries and the
+// team memberships of a user in a single transaction, then counts the user's
+// memories and changes left.
+func (c *Client) EraseUser(ctx context.Context, userID string) (*storage.ErasureCounts, error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("EraseUser: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	counts := &storage.ErasureCounts{}
+	for _, table := range []struct {
+		name    string
+		deleted *int64
+	}{
+		{c.collectionName, &counts.Memories},
+		{c.changesTable(), &counts.Changes},
+	} {
+		query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", table.name)
+		result, err := tx.ExecContext(ctx, query, userID)
+		if err != nil {
+			return nil, fmt.Errorf("EraseUser: %w", err)
+		}
+		if *table.deleted, err = result.RowsAffected(); err != nil {
+			return nil, fmt.Errorf("EraseUser: %w", err)
+		}
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", c.teamsTable())
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return nil, fmt.Errorf("EraseUser: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("EraseUser: %w", err)
+	}
+
+	query = fmt.Sprintf(
+		"SELECT (SELECT COUNT(*) FROM %s WHERE user_id = $1) + (SELECT COUNT(*) FROM %s WHERE user_id = $2)",
+		c.collectionName, c.changesTable())
+	if err := c.db.QueryRowContext(ctx, query, userID, userID).Scan(&counts.Remaining); err != nil {
+		return nil, fmt.Errorf("EraseUser: %w", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// Quantization modes of Config.Quantization.
+const (
+	// QuantizationNone searches the full-precision embeddings only.
+	QuantizationNone = "none"
+
+	// QuantizationHalfvec indexes the embeddings as half-precision vectors
+	// (halfvec), halving the size of the index.
+	QuantizationHalfvec = "halfvec"
+
+	// QuantizationBit indexes the signs of the embeddings as bit strings
+	// (binary_quantize), dividing the size of the index by 32. Recall
+	// depends more on re-ranking than with halfvec.
+	QuantizationBit = "bit"
+)
+
+// quantizationSettings returns the quantization mode and re-rank factor of
+// cfg, with defaults applied.
+func quantizationSettings(cfg *Config) (string, int, error) {
+	quantization := cfg.Quantization
+	if quantization == "" {
+		quantization = QuantizationNone
+	}
+
+	rerankFactor := cfg.RerankFactor
+	if rerankFactor < 0 {
+		return "", 0, fmt.Errorf("negative rerank factor %d", rerankFactor)
+	}
+	switch quantization {
+	case QuantizationNone:
+	case QuantizationHalfvec:
+		if rerankFactor == 0 {
+			rerankFactor = 4
+		}
+	case QuantizationBit:
+		if rerankFactor == 0 {
+			rerankFactor = 10
+		}
+	default:
+		return "", 0, fmt.Errorf("unknown quantization %q", quantization)
+	}
+	return quantization, rerankFactor, nil
+}
+
+// initQuantizedIndex creates the HNSW index on the quantized embeddings, if
+// quantization is enabled.
+func (c *Client) initQuantizedIndex(ctx context.Context) error {
+	var query string
+	switch c.quantization {
+	case QuantizationHalfvec:
+		query = fmt.Sprintf(`
+			CREATE INDEX IF NOT EXISTS idx_%s_embedding_halfvec ON %s
+			USING hnsw ((embedding::halfvec(%d)) halfvec_cosine_ops)
+		`, c.collectionName, c.collectionName, c.dimensions)
+	case QuantizationBit:
+		query = fmt.Sprintf(`
+			CREATE INDEX IF NOT EXISTS idx_%s_embedding_bit ON %s
+			USING hnsw ((binary_quantize(embedding)::bit(%d)) bit_hamming_ops)
+		`, c.collectionName, c.collectionName, c.dimensions)
+	default:
+		return nil
+	}
+	_, err := c.db.ExecContext(ctx, query)
+	return err
+}
+
+// quantizedDistance returns the distance expression between the quantized
+// embeddings and the query vector $1, matching the quantized index. $1 is
+// cast to vector first so that its type is the same in the whole query.
+func (c *Client) quantizedDistance() string {
+	if c.quantization == QuantizationBit {
+		return fmt.Sprintf("binary_quantize(embedding)::bit(%d) <~> binary_quantize($1::vector)::bit(%d)", c.dimensions, c.dimensions)
+	}
+	return fmt.Sprintf("embedding::halfvec(%d) <=> $1::vector::halfvec(%d)", c.dimensions, c.dimensions)
+}
+
+// searchQuantized performs vector search in two steps: it fetches
+// opts.Limit * rerankFactor candidates by quantized distance, using the
+// quantized index, then ranks them by exact cosine similarity.
+//
+// The similarity threshold applies to the exact similarity, after the
+// candidates are selected.
+func (c *Client) searchQuantized(ctx context.Context, embedding []float64, opts *storage.SearchOptions) ([]*storage.Memory, error) {
+	// Use Threshold if MinScore is not set (Python SDK compatibility)
+	minScore := opts.MinScore
+	if minScore == 0 && opts.Threshold > 0 {
+		minScore = opts.Threshold
+	}
+
+	filter := whereFilter{
+		userID:    opts.UserID,
+		teamIDs:   opts.TeamIDs,
+		agentID:   opts.AgentID,
+		filters:   opts.Filters,
+		timeRange: opts.TimeRange,
+		tags:      opts.Tags,
+		activeAt:  storage.ActiveAt(opts.Now),
+	}
+
+	// Build WHERE clause (starting from $2 since $1 is the query vector)
+	whereClause, filterArgs := buildWhereClauseWithOffset(filter, 2)
+	args := []interface{}{vectorToString(embedding)}
+	args = append(args, filterArgs...)
+
+	// The threshold is checked on the re-ranked candidates; scoredWhere is
+	// the equivalent filter on the whole table, for Stats
+	rerankWhere := ""
+	scoredWhere := whereClause
+	if minScore > 0 {
+		args = append(args, minScore)
+		condition := fmt.Sprintf("1 - (embedding <=> $1) >= $%d", len(args))
+		rerankWhere = "WHERE " + condition
+		if scoredWhere == "" {
+			scoredWhere = "WHERE " + condition
+		} else {
+			scoredWhere += " AND " + condition
+		}
+	}
+
+	db := c.replicas.Reader(ctx)
+	if opts.Stats != nil {
+		if err := c.searchStats(ctx, db, filter, scoredWhere, args, minScore > 0, opts.Stats); err != nil {
+			return nil, fmt.Errorf("Search: %w", err)
+		}
+	}
+
+	args = append(args, opts.Limit*c.rerankFactor, opts.Limit)
+	query := fmt.Sprintf(`
+		SELECT %s,
+			1 - (embedding <=> $1) as similarity
+		FROM (
+			SELECT * FROM %s
+			%s
+			ORDER BY %s
+			LIMIT $%d
+		) candidates
+		%s
+		ORDER BY embedding <=> $1, id
+		LIMIT $%d
+	`, selectColumns(opts.Fields, opts.WithoutEmbeddings), c.collectionName, whereClause, c.quantizedDistance(), len(args)-1, rerankWhere, len(args))
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Search: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return c.scanMemories(rows, true)
+}
@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// snapshotBackend identifies the dumps of this store.
+const snapshotBackend = "postgres"
+
+// Snapshot writes a logical dump of the memory table to a new file at path
+// (see storage.WriteDump), read from the primary in a single repeatable
+// read transaction.
+func (c *Client) Snapshot(ctx context.Context, path string) error {
+	if err := storage.WriteDump(ctx, c.db, path, snapshotBackend, "memories", c.collectionName); err != nil {
+		return fmt.Errorf("Snapshot: %w", err)
+	}
+	return nil
+}
+
+// RestoreSnapshot replaces the memories with those of the dump at path in a
+// single transaction. The change log and the audit log are kept.
+func (c *Client) RestoreSnapshot(ctx context.Context, path string) error {
+	placeholder := func(n int) string { return fmt.Sprintf("$%d", n) }
+	if _, err := storage.RestoreDump(ctx, c.db, path, snapshotBackend, "memories", c.collectionName, placeholder); err != nil {
+		return fmt.Errorf("RestoreSnapshot: %w", err)
+	}
+	return nil
+}
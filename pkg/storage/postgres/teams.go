@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+)
+
+// teamsTable returns the name of the team membership table.
+func (c *Client) teamsTable() string {
+	return c.collectionName + "_teams"
+}
+
+// initTeams creates the team membership table.
+func (c *Client) initTeams(ctx context.Context) error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			team_id VARCHAR(255) NOT NULL,
+			user_id VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (team_id, user_id)
+		)
+	`, c.teamsTable())
+	if _, err := c.db.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	indexQuery := fmt.Sprintf(`
+		CREATE INDEX IF NOT EXISTS idx_%s_user_id ON %s(user_id)
+	`, c.teamsTable(), c.teamsTable())
+	_, err := c.db.ExecContext(ctx, indexQuery)
+	return err
+}
+
+// AddTeamMember adds userID to teamID.
+func (c *Client) AddTeamMember(ctx context.Context, teamID, userID string) error {
+	query := fmt.Sprintf("INSERT INTO %s (team_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", c.teamsTable())
+	if _, err := c.db.ExecContext(ctx, query, teamID, userID); err != nil {
+		return fmt.Errorf("AddTeamMember: %w", err)
+	}
+	return nil
+}
+
+// RemoveTeamMember removes userID from teamID.
+func (c *Client) RemoveTeamMember(ctx context.Context, teamID, userID string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE team_id = $1 AND user_id = $2", c.teamsTable())
+	if _, err := c.db.ExecContext(ctx, query, teamID, userID); err != nil {
+		return fmt.Errorf("RemoveTeamMember: %w", err)
+	}
+	return nil
+}
+
+// ListTeamMembers returns the members of teamID, sorted.
+func (c *Client) ListTeamMembers(ctx context.Context, teamID string) ([]string, error) {
+	query := fmt.Sprintf("SELECT user_id FROM %s WHERE team_id = $1 ORDER BY user_id", c.teamsTable())
+	members, err := c.queryStrings(ctx, query, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("ListTeamMembers: %w", err)
+	}
+	return members, nil
+}
+
+// ListUserTeams returns the teams of userID, sorted.
+func (c *Client) ListUserTeams(ctx context.Context, userID string) ([]string, error) {
+	query := fmt.Sprintf("SELECT team_id FROM %s WHERE user_id = $1 ORDER BY team_id", c.teamsTable())
+	teams, err := c.queryStrings(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ListUserTeams: %w", err)
+	}
+	return teams, nil
+}
+
+// queryStrings returns the single string column selected by query.
+func (c *Client) queryStrings(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
@@ -1,35 +1,131 @@
 package postgres
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/oceanbase/powermem-go/pkg/storage"
 )
 
+// whereFilter describes the row filters applied by buildWhereClause.
+type whereFilter struct {
+	userID  string
+	agentID string
+
+	// teamIDs widens userID to the memories shared with these teams.
+	teamIDs []string
+
+	filters   map[string]interface{}
+	timeRange *storage.TimeRange
+	tags      []string
+
+	// entities are the entities every memory must be annotated with in
+	// metadata["entities"], compared case-insensitively.
+	entities []string
+
+	// parentID selects the chunks of this memory. If it is zero and
+	// excludeChunks is set, chunks are excluded.
+	parentID      int64
+	excludeChunks bool
+
+	// activeAt excludes memories that expired at or before this time.
+	// A zero value disables the expiration check.
+	activeAt time.Time
+}
+
 // buildWhereClause builds a WHERE clause starting from $1.
-func buildWhereClause(userID, agentID string, filters map[string]interface{}) (string, []interface{}) {
-	return buildWhereClauseWithOffset(userID, agentID, filters, 1)
+func buildWhereClause(f whereFilter) (string, []interface{}) {
+	return buildWhereClauseWithOffset(f, 1)
 }
 
 // buildWhereClauseWithOffset builds a WHERE clause starting from a specific parameter index.
-func buildWhereClauseWithOffset(userID, agentID string, filters map[string]interface{}, startIndex int) (string, []interface{}) {
+func buildWhereClauseWithOffset(f whereFilter, startIndex int) (string, []interface{}) {
 	conditions := []string{}
 	args := []interface{}{}
 	argIndex := startIndex
 
-	if userID != "" {
+	if f.userID != "" && len(f.teamIDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("(user_id = $%d OR metadata->>'%s' = ANY($%d))",
+			argIndex, storage.TeamIDKey, argIndex+1))
+		args = append(args, f.userID, pq.Array(f.teamIDs))
+		argIndex += 2
+	} else if f.userID != "" {
 		conditions = append(conditions, fmt.Sprintf("user_id = $%d", argIndex))
-		args = append(args, userID)
+		args = append(args, f.userID)
 		argIndex++
 	}
 
-	if agentID != "" {
+	if f.agentID != "" {
 		conditions = append(conditions, fmt.Sprintf("agent_id = $%d", argIndex))
-		args = append(args, agentID)
-		// argIndex++ // Reserved for future expansion
+		args = append(args, f.agentID)
+		argIndex++
+	}
+
+	if f.parentID != 0 {
+		conditions = append(conditions, fmt.Sprintf("parent_id = $%d", argIndex))
+		args = append(args, f.parentID)
+		argIndex++
+	} else if f.excludeChunks {
+		conditions = append(conditions, "parent_id IS NULL")
+	}
+
+	// Metadata must contain every filter key/value pair
+	if len(f.filters) > 0 {
+		filtersJSON, _ := json.Marshal(f.filters)
+		conditions = append(conditions, fmt.Sprintf("metadata @> $%d::jsonb", argIndex))
+		args = append(args, string(filtersJSON))
+		argIndex++
+	}
+
+	if !f.timeRange.IsZero() {
+		if !f.timeRange.CreatedAfter.IsZero() {
+			conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argIndex))
+			args = append(args, f.timeRange.CreatedAfter)
+			argIndex++
+		}
+		if !f.timeRange.CreatedBefore.IsZero() {
+			conditions = append(conditions, fmt.Sprintf("created_at < $%d", argIndex))
+			args = append(args, f.timeRange.CreatedBefore)
+			argIndex++
+		}
+		if !f.timeRange.UpdatedAfter.IsZero() {
+			conditions = append(conditions, fmt.Sprintf("updated_at >= $%d", argIndex))
+			args = append(args, f.timeRange.UpdatedAfter)
+			argIndex++
+		}
+		if !f.timeRange.UpdatedBefore.IsZero() {
+			conditions = append(conditions, fmt.Sprintf("updated_at < $%d", argIndex))
+			args = append(args, f.timeRange.UpdatedBefore)
+			argIndex++
+		}
+	}
+
+	// Memories must carry every requested tag
+	if len(f.tags) > 0 {
+		tagsJSON, _ := json.Marshal(f.tags)
+		conditions = append(conditions, fmt.Sprintf("tags @> $%d::jsonb", argIndex))
+		args = append(args, string(tagsJSON))
+		argIndex++
+	}
+
+	// Memories must be annotated with every requested entity
+	for _, entity := range f.entities {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM jsonb_array_elements_text(COALESCE(metadata->'entities', '[]'::jsonb)) AS e(entity) WHERE lower(e.entity) = lower($%d))",
+			argIndex))
+		args = append(args, entity)
+		argIndex++
 	}
 
-	// Note: Currently not processing filters map for metadata conditions
-	// This would require JSON operations in PostgreSQL
+	// Exclude expired memories
+	if !f.activeAt.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("(expires_at IS NULL OR expires_at > $%d)", argIndex))
+		args = append(args, f.activeAt)
+		// argIndex++ // Reserved for future expansion
+	}
 
 	if len(conditions) == 0 {
 		return "", args
@@ -37,3 +133,39 @@ func buildWhereClauseWithOffset(userID, agentID string, filters map[string]inter
 
 	return "WHERE " + strings.Join(conditions, " AND "), args
 }
+
+// escapeLikePattern escapes LIKE wildcards so text is matched literally.
+func escapeLikePattern(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(text)
+}
+
+// marshalTags encodes tags as a JSON array for the JSONB tags column.
+func marshalTags(tags []string) (string, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return "", err
+	}
+	return string(tagsJSON), nil
+}
+
+// nullableUID returns uid for the uid column, or nil for memories without a
+// string ID so that the unique index ignores them.
+func nullableUID(uid string) interface{} {
+	if uid == "" {
+		return nil
+	}
+	return uid
+}
+
+// nullableParentID returns parentID for the parent_id column, or nil for
+// memories that are not chunks.
+func nullableParentID(parentID int64) interface{} {
+	if parentID == 0 {
+		return nil
+	}
+	return parentID
+}
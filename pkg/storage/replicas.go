@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReplicaCheckInterval is how often a ReplicaSet checks the health and lag of
+// a replica that is used for reads.
+const ReplicaCheckInterval = time.Second
+
+// LagFunc returns how far the replica db is behind its primary.
+type LagFunc func(ctx context.Context, db *sql.DB) (time.Duration, error)
+
+// ReplicaSet spreads the reads of a SQL vector store over read replicas,
+// while writes go to the primary.
+//
+// Replicas are used in turn. A replica is checked at most once per
+// ReplicaCheckInterval, with the LagFunc if MaxLag is set and a ping
+// otherwise, and skipped until its next check if the check fails or it lags
+// more than MaxLag. Reads go to the primary if no replica is usable, or if
+// their context was made with ContextWithPrimary.
+type ReplicaSet struct {
+	primary  *sql.DB
+	replicas []*replica
+	next     uint32
+	maxLag   time.Duration
+	lag      LagFunc
+}
+
+// replica is a read replica and the result of its last check.
+type replica struct {
+	db *sql.DB
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	usable    bool
+}
+
+// NewReplicaSet returns a ReplicaSet reading from replicas, or from primary
+// if there are none.
+//
+// Parameters:
+//   - primary: Database that receives the writes
+//   - replicas: Read replicas of primary
+//   - maxLag: Maximum replication lag of a replica used for reads (0 for no limit)
+//   - lag: Measures the lag of a replica, required if maxLag is set
+func NewReplicaSet(primary *sql.DB, replicas []*sql.DB, maxLag time.Duration, lag LagFunc) *ReplicaSet {
+	s := &ReplicaSet{primary: primary, maxLag: maxLag, lag: lag}
+	for _, db := range replicas {
+		s.replicas = append(s.replicas, &replica{db: db})
+	}
+	return s
+}
+
+// Primary returns the database that receives the writes.
+func (s *ReplicaSet) Primary() *sql.DB {
+	return s.primary
+}
+
+// Reader returns the database to run a read on.
+func (s *ReplicaSet) Reader(ctx context.Context) *sql.DB {
+	if len(s.replicas) == 0 || PrimaryFromContext(ctx) {
+		return s.primary
+	}
+
+	start := atomic.AddUint32(&s.next, 1)
+	for i := range s.replicas {
+		r := s.replicas[(int(start)+i)%len(s.replicas)]
+		if s.usable(ctx, r) {
+			return r.db
+		}
+	}
+	return s.primary
+}
+
+// usable reports whether r may serve reads, checking it if its last check
+// is older than ReplicaCheckInterval.
+func (s *ReplicaSet) usable(ctx context.Context, r *replica) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) < ReplicaCheckInterval {
+		return r.usable
+	}
+
+	if s.maxLag > 0 && s.lag != nil {
+		lag, err := s.lag(ctx, r.db)
+		r.usable = err == nil && lag <= s.maxLag
+	} else {
+		r.usable = r.db.PingContext(ctx) == nil
+	}
+	r.checkedAt = time.Now()
+	return r.usable
+}
+
+// Close closes the replicas and the primary.
+func (s *ReplicaSet) Close() error {
+	var firstErr error
+	for _, r := range s.replicas {
+		if err := r.db.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if err := s.primary.Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// primaryKey is the context key set by ContextWithPrimary.
+type primaryKey struct{}
+
+// ContextWithPrimary returns a context whose reads go to the primary, e.g. to
+// read a memory that was just written.
+func ContextWithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// PrimaryFromContext reports whether ctx was made with ContextWithPrimary.
+func PrimaryFromContext(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
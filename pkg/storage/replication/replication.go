@@ -0,0 +1,547 @@
+// Package replication keeps a local vector store, typically the SQLite store
+// of an edge agent, in sync with a central store (OceanBase or PostgreSQL).
+//
+// Agents write to their local store and keep working offline; Sync exchanges
+// the changes made on both sides since the previous sync whenever the
+// central store is reachable. Changes are read from the change logs of the
+// two stores (see storage.VectorStore.AppendChange), so the clients writing
+// to them must have the change log enabled. Memories keep their IDs,
+// timestamps and versions in both stores.
+//
+// A memory changed on both sides since the previous sync is a conflict,
+// settled by the ConflictPolicy of the replicator.
+//
+// Changes to several memories (DeleteAll, DeleteWhere, Reset, PurgeExpired
+// and EraseUser) are repeated on the other side with their filter, before
+// the changes to single memories. A replicated deletion only deletes the
+// memories updated before it, so that later changes on the other side are
+// kept; erasures delete every memory of the user.
+//
+// Memories are matched by ID in both stores, so the clients writing to them
+// must not assign the same IDs, e.g. with distinct snowflake nodes.
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// ConflictPolicy selects the side whose state of a memory is kept when the
+// memory was changed both locally and remotely since the previous sync.
+type ConflictPolicy string
+
+const (
+	// ConflictNewest keeps the state most recently updated (last writer
+	// wins). Deleted memories count as updated when they were deleted.
+	ConflictNewest ConflictPolicy = "newest"
+
+	// ConflictLocal keeps the local state.
+	ConflictLocal ConflictPolicy = "local"
+
+	// ConflictRemote keeps the remote state.
+	ConflictRemote ConflictPolicy = "remote"
+)
+
+// ParseConflictPolicy parses a conflict policy name. The empty string is
+// ConflictNewest.
+func ParseConflictPolicy(name string) (ConflictPolicy, error) {
+	switch ConflictPolicy(name) {
+	case "":
+		return ConflictNewest, nil
+	case ConflictNewest, ConflictLocal, ConflictRemote:
+		return ConflictPolicy(name), nil
+	}
+	return "", fmt.Errorf("unknown conflict policy %q (want newest, local or remote)", name)
+}
+
+// defaultBatchSize is the default of Config.BatchSize.
+const defaultBatchSize = 500
+
+// Config contains configuration for creating a Replicator.
+type Config struct {
+	// Local is the store of the agent. Required.
+	Local storage.VectorStore
+
+	// Remote is the central store. Required.
+	Remote storage.VectorStore
+
+	// Conflict settles the memories changed on both sides. Default:
+	// ConflictNewest
+	Conflict ConflictPolicy
+
+	// StatePath is the file keeping the position of the replicator in the
+	// change logs between runs (optional). Without it every replicator
+	// starts from the beginning of both logs, which converges too, but
+	// re-reads every change.
+	StatePath string
+
+	// BatchSize is the number of changes read per query. Default: 500
+	BatchSize int
+}
+
+// Result reports what a Sync did.
+type Result struct {
+	// Pushed is the number of memories copied or deleted from the local
+	// store to the remote store.
+	Pushed int `json:"pushed"`
+
+	// Pulled is the number of memories copied or deleted from the remote
+	// store to the local store.
+	Pulled int `json:"pulled"`
+
+	// Conflicts is the number of memories changed on both sides.
+	Conflicts int `json:"conflicts"`
+
+	// Bulk is the number of changes to several memories (DeleteAll,
+	// DeleteWhere, Reset, PurgeExpired and EraseUser) repeated on the other
+	// side, in both directions.
+	Bulk int `json:"bulk"`
+}
+
+// state is the position of a replicator in the change logs, persisted in
+// Config.StatePath.
+type state struct {
+	// Local and Remote are the Seq of the last change applied from each log.
+	Local  int64 `json:"local"`
+	Remote int64 `json:"remote"`
+
+	// Pushed are the Seq of the changes appended to the remote log by
+	// pushes that were not read back yet, so that they are not pulled.
+	Pushed []int64 `json:"pushed,omitempty"`
+}
+
+// Replicator synchronizes a local and a remote vector store.
+type Replicator struct {
+	local, remote storage.VectorStore
+	conflict      ConflictPolicy
+	statePath     string
+	batchSize     int
+
+	// mu serializes Sync.
+	mu    sync.Mutex
+	state state
+}
+
+// NewReplicator creates a Replicator, reading its state from cfg.StatePath
+// if the file exists.
+//
+// The stores are not owned by the replicator: the caller closes them.
+func NewReplicator(cfg *Config) (*Replicator, error) {
+	if cfg.Local == nil || cfg.Remote == nil {
+		return nil, errors.New("replication: local and remote stores are required")
+	}
+	conflict, err := ParseConflictPolicy(string(cfg.Conflict))
+	if err != nil {
+		return nil, fmt.Errorf("replication: %w", err)
+	}
+	r := &Replicator{
+		local:     cfg.Local,
+		remote:    cfg.Remote,
+		conflict:  conflict,
+		statePath: cfg.StatePath,
+		batchSize: cfg.BatchSize,
+	}
+	if r.batchSize <= 0 {
+		r.batchSize = defaultBatchSize
+	}
+
+	if r.statePath != "" {
+		data, err := os.ReadFile(r.statePath)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("replication: %w", err)
+		}
+		if err == nil {
+			if err := json.Unmarshal(data, &r.state); err != nil {
+				return nil, fmt.Errorf("replication: invalid state file %s: %w", r.statePath, err)
+			}
+		}
+	}
+	return r, nil
+}
+
+// pending are the memories changed in one change log since the previous
+// sync.
+type pending struct {
+	// changes are the last change of each memory, by memory ID.
+	changes map[int64]*storage.Change
+
+	// order are the changed memory IDs in the order of their last change.
+	order []int64
+
+	// bulk are the changes to several memories, in Seq order.
+	bulk []*storage.Change
+
+	// last is the Seq of the last change read.
+	last int64
+}
+
+// Sync exchanges the changes made to both stores since the previous sync:
+// the memories changed locally are copied to the remote store (or deleted
+// from it), and the memories changed remotely are copied to the local
+// store. Pushed memories are also recorded in the remote change log, so
+// that the other replicas of the remote store pull them; pulled memories
+// are not recorded in the local change log.
+//
+// Sync is idempotent: when it fails, for instance because the remote store
+// is unreachable, nothing is lost and the next Sync resumes from the same
+// position.
+func (r *Replicator) Sync(ctx context.Context) (result *Result, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Keep the changes pushed before a failure from being pulled back
+	defer func() {
+		if err != nil {
+			_ = r.saveState()
+		}
+	}()
+
+	local, err := r.readPending(ctx, r.local, r.state.Local, nil)
+	if err != nil {
+		return nil, fmt.Errorf("replication: read local changes: %w", err)
+	}
+	pushed := make(map[int64]bool, len(r.state.Pushed))
+	for _, seq := range r.state.Pushed {
+		pushed[seq] = true
+	}
+	remote, err := r.readPending(ctx, r.remote, r.state.Remote, pushed)
+	if err != nil {
+		return nil, fmt.Errorf("replication: read remote changes: %w", err)
+	}
+
+	// Bulk changes first: the changes to single memories that follow them
+	// are applied over their result
+	result = &Result{}
+	for _, change := range local.bulk {
+		if err := r.pushBulk(ctx, change); err != nil {
+			return nil, err
+		}
+		result.Bulk++
+	}
+	for _, change := range remote.bulk {
+		if err := applyBulk(ctx, r.local, change); err != nil {
+			return nil, fmt.Errorf("replication: pull change %d: %w", change.Seq, err)
+		}
+		result.Bulk++
+	}
+
+	for _, id := range local.order {
+		change := local.changes[id]
+		if remoteChange, ok := remote.changes[id]; ok {
+			result.Conflicts++
+			pushLocal, err := r.resolve(ctx, id, change, remoteChange)
+			if err != nil {
+				return nil, err
+			}
+			delete(remote.changes, id)
+			if !pushLocal {
+				if err := r.copy(ctx, r.remote, r.local, id); err != nil {
+					return nil, fmt.Errorf("replication: pull memory %d: %w", id, err)
+				}
+				result.Pulled++
+				continue
+			}
+		}
+		if err := r.push(ctx, id, change); err != nil {
+			return nil, err
+		}
+		result.Pushed++
+	}
+	for _, id := range remote.order {
+		if _, ok := remote.changes[id]; !ok {
+			continue
+		}
+		if err := r.copy(ctx, r.remote, r.local, id); err != nil {
+			return nil, fmt.Errorf("replication: pull memory %d: %w", id, err)
+		}
+		result.Pulled++
+	}
+
+	r.state.Local = local.last
+	r.state.Remote = remote.last
+	kept := r.state.Pushed[:0]
+	for _, seq := range r.state.Pushed {
+		if seq > remote.last {
+			kept = append(kept, seq)
+		}
+	}
+	r.state.Pushed = kept
+	if err := r.saveState(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// readPending reads the changes of store after the Seq after, skipping the
+// Seq in skip.
+func (r *Replicator) readPending(ctx context.Context, store storage.VectorStore, after int64, skip map[int64]bool) (*pending, error) {
+	p := &pending{changes: make(map[int64]*storage.Change), last: after}
+	// lastAt is the position in order of the last change of each memory;
+	// the earlier ones are dropped once all changes are read
+	lastAt := make(map[int64]int)
+	for {
+		changes, err := store.ListChanges(ctx, &storage.ListChangesOptions{After: p.last, Limit: r.batchSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range changes {
+			p.last = change.Seq
+			switch {
+			case skip[change.Seq]:
+			case change.MemoryID == 0:
+				p.bulk = append(p.bulk, change)
+			default:
+				p.changes[change.MemoryID] = change
+				lastAt[change.MemoryID] = len(p.order)
+				p.order = append(p.order, change.MemoryID)
+			}
+		}
+		if len(changes) < r.batchSize {
+			break
+		}
+	}
+
+	order := p.order[:0]
+	for i, id := range p.order {
+		if lastAt[id] == i {
+			order = append(order, id)
+		}
+	}
+	p.order = order
+	return p, nil
+}
+
+// resolve settles a memory changed on both sides, and reports whether the
+// local state wins.
+func (r *Replicator) resolve(ctx context.Context, id int64, localChange, remoteChange *storage.Change) (bool, error) {
+	switch r.conflict {
+	case ConflictLocal:
+		return true, nil
+	case ConflictRemote:
+		return false, nil
+	}
+
+	localTime := localChange.CreatedAt
+	if memory, err := get(ctx, r.local, id); err != nil {
+		return false, fmt.Errorf("replication: resolve memory %d: %w", id, err)
+	} else if memory != nil {
+		localTime = memory.UpdatedAt
+	}
+	remoteTime := remoteChange.CreatedAt
+	if memory, err := get(ctx, r.remote, id); err != nil {
+		return false, fmt.Errorf("replication: resolve memory %d: %w", id, err)
+	} else if memory != nil {
+		remoteTime = memory.UpdatedAt
+	}
+	return !localTime.Before(remoteTime), nil
+}
+
+// push copies the memory id to the remote store and records change in the
+// remote change log.
+func (r *Replicator) push(ctx context.Context, id int64, change *storage.Change) error {
+	if err := r.copy(ctx, r.local, r.remote, id); err != nil {
+		return fmt.Errorf("replication: push memory %d: %w", id, err)
+	}
+	copied := *change
+	copied.Seq = 0
+	if err := r.remote.AppendChange(ctx, &copied); err != nil {
+		return fmt.Errorf("replication: push memory %d: %w", id, err)
+	}
+	r.state.Pushed = append(r.state.Pushed, copied.Seq)
+	return nil
+}
+
+// pushBulk repeats the change to several memories change in the remote store
+// and records it in the remote change log.
+func (r *Replicator) pushBulk(ctx context.Context, change *storage.Change) error {
+	if err := applyBulk(ctx, r.remote, change); err != nil {
+		return fmt.Errorf("replication: push change %d: %w", change.Seq, err)
+	}
+	copied := *change
+	copied.Seq = 0
+	if err := r.remote.AppendChange(ctx, &copied); err != nil {
+		return fmt.Errorf("replication: push change %d: %w", change.Seq, err)
+	}
+	r.state.Pushed = append(r.state.Pushed, copied.Seq)
+	return nil
+}
+
+// Types of the changes to several memories.
+const (
+	changeDeleted   = "memory.deleted"
+	changeForgotten = "memory.forgotten"
+	changeErased    = "memory.erased"
+)
+
+// bulkPayload is the part of the payload of a change to several memories
+// the replicator reads: the rest of the filter of a DeleteWhere (see
+// core.Event).
+type bulkPayload struct {
+	Where *struct {
+		Filters map[string]interface{} `json:"filters"`
+		Tags    []string               `json:"tags"`
+
+		CreatedAfter  time.Time `json:"created_after"`
+		CreatedBefore time.Time `json:"created_before"`
+		UpdatedAfter  time.Time `json:"updated_after"`
+		UpdatedBefore time.Time `json:"updated_before"`
+	} `json:"where"`
+}
+
+// maxBulkAttempts is the number of times a deletion is counted and run
+// before giving up, when memories matching it are written concurrently.
+const maxBulkAttempts = 3
+
+// applyBulk repeats the change to several memories change in dst:
+// erasures erase the user, purges purge the memories expired when change
+// was recorded, and deletions delete the memories matching their filter
+// that were last updated before change was recorded.
+func applyBulk(ctx context.Context, dst storage.VectorStore, change *storage.Change) error {
+	switch change.Type {
+	case changeErased:
+		_, err := dst.EraseUser(ctx, change.UserID)
+		return err
+	case changeForgotten:
+		_, err := dst.PurgeExpired(ctx, change.CreatedAt)
+		return err
+	case changeDeleted:
+	default:
+		return fmt.Errorf("unknown type %q of a change to several memories", change.Type)
+	}
+
+	var payload bulkPayload
+	if len(change.Payload) > 0 {
+		if err := json.Unmarshal(change.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+	}
+	opts := &storage.DeleteWhereOptions{
+		UserID:    change.UserID,
+		AgentID:   change.AgentID,
+		TimeRange: &storage.TimeRange{UpdatedBefore: change.CreatedAt},
+	}
+	if where := payload.Where; where != nil {
+		opts.Filters = where.Filters
+		opts.Tags = where.Tags
+		opts.TimeRange.CreatedAfter = where.CreatedAfter
+		opts.TimeRange.CreatedBefore = where.CreatedBefore
+		opts.TimeRange.UpdatedAfter = where.UpdatedAfter
+		if !where.UpdatedBefore.IsZero() && where.UpdatedBefore.Before(change.CreatedAt) {
+			opts.TimeRange.UpdatedBefore = where.UpdatedBefore
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		opts.DryRun = true
+		matched, err := dst.DeleteWhere(ctx, opts)
+		if err != nil || matched == 0 {
+			return err
+		}
+		opts.DryRun = false
+		opts.ExpectedCount = matched
+		_, err = dst.DeleteWhere(ctx, opts)
+		if !errors.Is(err, storage.ErrCountMismatch) || attempt == maxBulkAttempts {
+			return err
+		}
+	}
+}
+
+// copy makes the memory id and its chunks in dst what they are in src:
+// deleted if src has no such memory, or a copy of the memory of src.
+func (r *Replicator) copy(ctx context.Context, src, dst storage.VectorStore, id int64) error {
+	memory, err := get(ctx, src, id)
+	if err != nil {
+		return err
+	}
+	existing, err := get(ctx, dst, id)
+	if err != nil {
+		return err
+	}
+	if memory == nil {
+		if existing == nil {
+			return nil
+		}
+		if err := dst.Delete(ctx, id, nil); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+		return dst.DeleteAll(ctx, &storage.DeleteAllOptions{UserID: existing.UserID, ParentID: id})
+	}
+	if existing != nil && sameMemory(existing, memory) {
+		return nil
+	}
+
+	chunks, err := src.GetAll(ctx, &storage.GetAllOptions{UserID: memory.UserID, ParentID: id})
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if err := dst.Delete(ctx, id, nil); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+		if err := dst.DeleteAll(ctx, &storage.DeleteAllOptions{UserID: existing.UserID, ParentID: id}); err != nil {
+			return err
+		}
+	}
+	for _, m := range append([]*storage.Memory{memory}, chunks...) {
+		m.Score = 0
+		if err := dst.Insert(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// get returns the memory id of store, or nil if it does not exist.
+func get(ctx context.Context, store storage.VectorStore, id int64) (*storage.Memory, error) {
+	memory, err := store.Get(ctx, id, nil)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	return memory, err
+}
+
+// sameMemory reports whether two copies of a memory are in the same state,
+// so that copying one over the other can be skipped.
+func sameMemory(a, b *storage.Memory) bool {
+	return a.Version == b.Version &&
+		a.UpdatedAt.Equal(b.UpdatedAt) &&
+		a.Content == b.Content &&
+		reflect.DeepEqual(a.Metadata, b.Metadata)
+}
+
+// saveState writes the state to Config.StatePath, replacing the file
+// atomically.
+func (r *Replicator) saveState() error {
+	if r.statePath == "" {
+		return nil
+	}
+	data, err := json.Marshal(&r.state)
+	if err != nil {
+		return fmt.Errorf("replication: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.statePath), filepath.Base(r.statePath)+".*")
+	if err != nil {
+		return fmt.Errorf("replication: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("replication: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("replication: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.statePath); err != nil {
+		return fmt.Errorf("replication: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,597 @@
+// Package routing provides a vector store that routes each user's memories to
+// one of several stores.
+//
+// It keeps the data of users in the store of their region (data residency)
+// behind a single VectorStore, and so a single memory client. Operations for
+// a user go to that user's store only; operations without a user, such as a
+// Get by ID or a DeleteAll of all memories, are run on every store and their
+// results merged.
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// Policy returns the name of the store holding the memories of a user, or ""
+// for the default store.
+//
+// A policy is called for every operation on a user, and must return the same
+// store for a user as long as the user has memories: memories are not moved
+// between stores.
+type Policy func(ctx context.Context, userID string) (string, error)
+
+// StaticPolicy returns a policy routing the users of routes (user ID to store
+// name) and the other users to the default store.
+func StaticPolicy(routes map[string]string) Policy {
+	return func(ctx context.Context, userID string) (string, error) {
+		return routes[userID], nil
+	}
+}
+
+// Chain returns a policy trying policies in order, until one returns a store.
+//
+// Example:
+//
+//	policy := routing.Chain(routing.StaticPolicy(overrides), regionFromAccounts)
+func Chain(policies ...Policy) Policy {
+	return func(ctx context.Context, userID string) (string, error) {
+		for _, policy := range policies {
+			name, err := policy(ctx, userID)
+			if err != nil || name != "" {
+				return name, err
+			}
+		}
+		return "", nil
+	}
+}
+
+// Client implements VectorStore on top of several named stores.
+type Client struct {
+	// stores are the routed stores by name.
+	stores map[string]storage.VectorStore
+
+	// names are the store names, sorted, for operations on every store.
+	names []string
+
+	// defaultStore is the name of the store of users without a route.
+	defaultStore string
+
+	// changeLogStore is the name of the store holding the change log.
+	changeLogStore string
+
+	// policy routes users to stores.
+	policy Policy
+}
+
+// Config contains configuration for creating a routing VectorStore.
+type Config struct {
+	// Stores are the routed stores by name (e.g. "eu", "us"). Required.
+	Stores map[string]storage.VectorStore
+
+	// Default is the name of the store of users the policy does not route,
+	// and of memories without a user. Required.
+	Default string
+
+	// Policy routes users to stores (optional, all users go to Default
+	// without a policy).
+	Policy Policy
+
+	// ChangeLogStore is the name of the store holding the change log, which
+	// is kept in a single store so that its order is global. Changes contain
+	// memory content: choose a store that may hold the data of every user,
+	// or leave the change log disabled. Default: Default
+	ChangeLogStore string
+}
+
+// NewClient creates a new routing VectorStore client.
+//
+// The stores are owned by the client: Close closes them.
+//
+// Parameters:
+//   - cfg: Configuration containing the stores, the default store and the policy
+//
+// Returns:
+//   - *Client: The routing client instance
+//   - error: Error if the configuration is invalid
+func NewClient(cfg *Config) (*Client, error) {
+	if len(cfg.Stores) == 0 {
+		return nil, errors.New("routing: no stores")
+	}
+	if cfg.Stores[cfg.Default] == nil {
+		return nil, fmt.Errorf("routing: unknown default store %q", cfg.Default)
+	}
+	changeLogStore := cfg.ChangeLogStore
+	if changeLogStore == "" {
+		changeLogStore = cfg.Default
+	}
+	if cfg.Stores[changeLogStore] == nil {
+		return nil, fmt.Errorf("routing: unknown change log store %q", changeLogStore)
+	}
+
+	c := &Client{
+		stores:         make(map[string]storage.VectorStore, len(cfg.Stores)),
+		defaultStore:   cfg.Default,
+		changeLogStore: changeLogStore,
+		policy:         cfg.Policy,
+	}
+	for name, store := range cfg.Stores {
+		if store == nil {
+			return nil, fmt.Errorf("routing: store %q is nil", name)
+		}
+		c.stores[name] = store
+		c.names = append(c.names, name)
+	}
+	sort.Strings(c.names)
+
+	return c, nil
+}
+
+// Route returns the name of the store holding the memories of userID.
+func (c *Client) Route(ctx context.Context, userID string) (string, error) {
+	if userID == "" || c.policy == nil {
+		return c.defaultStore, nil
+	}
+	name, err := c.policy(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("routing: user %s: %w", userID, err)
+	}
+	if name == "" {
+		return c.defaultStore, nil
+	}
+	if c.stores[name] == nil {
+		return "", fmt.Errorf("routing: user %s routed to unknown store %q", userID, name)
+	}
+	return name, nil
+}
+
+// store returns the store holding the memories of userID.
+func (c *Client) store(ctx context.Context, userID string) (storage.VectorStore, error) {
+	name, err := c.Route(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return c.stores[name], nil
+}
+
+// targets returns the store of userID, or every store if userID is empty.
+func (c *Client) targets(ctx context.Context, userID string) ([]storage.VectorStore, error) {
+	if userID == "" {
+		stores := make([]storage.VectorStore, len(c.names))
+		for i, name := range c.names {
+			stores[i] = c.stores[name]
+		}
+		return stores, nil
+	}
+	store, err := c.store(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return []storage.VectorStore{store}, nil
+}
+
+// Insert inserts a memory into the store of its user.
+func (c *Client) Insert(ctx context.Context, memory *storage.Memory) error {
+	store, err := c.store(ctx, memory.UserID)
+	if err != nil {
+		return err
+	}
+	return store.Insert(ctx, memory)
+}
+
+// Search searches the store of opts.UserID, or every store, and returns the
+// best matches ordered by score and then ID.
+func (c *Client) Search(ctx context.Context, embedding []float64, opts *storage.SearchOptions) ([]*storage.Memory, error) {
+	stores, err := c.targets(ctx, opts.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(stores) == 1 {
+		return stores[0].Search(ctx, embedding, opts)
+	}
+
+	var results []*storage.Memory
+	for _, store := range stores {
+		storeOpts := *opts
+		if opts.Stats != nil {
+			storeOpts.Stats = &storage.SearchStats{}
+		}
+		memories, err := store.Search(ctx, embedding, &storeOpts)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, memories...)
+		if opts.Stats != nil {
+			opts.Stats.TotalMemories += storeOpts.Stats.TotalMemories
+			opts.Stats.Candidates += storeOpts.Stats.Candidates
+			opts.Stats.AboveThreshold += storeOpts.Stats.AboveThreshold
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	return limit(results, 0, opts.Limit), nil
+}
+
+// SearchIter returns an iterator over Search, which pages through every
+// store with the SearchOptions.After cursor.
+func (c *Client) SearchIter(ctx context.Context, embedding []float64, opts *storage.SearchOptions, batchSize int) (storage.MemoryIterator, error) {
+	return storage.NewSearchIterator(c.Search, embedding, opts, batchSize)
+}
+
+// SearchByKeyword searches the store of opts.UserID, or every store, and
+// returns the matches newest first.
+func (c *Client) SearchByKeyword(ctx context.Context, text string, opts *storage.SearchOptions) ([]*storage.Memory, error) {
+	stores, err := c.targets(ctx, opts.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(stores) == 1 {
+		return stores[0].SearchByKeyword(ctx, text, opts)
+	}
+
+	var results []*storage.Memory
+	for _, store := range stores {
+		memories, err := store.SearchByKeyword(ctx, text, opts)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, memories...)
+	}
+	sortNewestFirst(results)
+	return limit(results, 0, opts.Limit), nil
+}
+
+// Get retrieves a memory from the store of opts.UserID, or from the first
+// store holding it.
+func (c *Client) Get(ctx context.Context, id int64, opts *storage.GetOptions) (*storage.Memory, error) {
+	return c.find(ctx, userIDOf(opts), func(store storage.VectorStore) (*storage.Memory, error) {
+		return store.Get(ctx, id, opts)
+	})
+}
+
+// GetByUID retrieves a memory by UID, like Get.
+func (c *Client) GetByUID(ctx context.Context, uid string, opts *storage.GetOptions) (*storage.Memory, error) {
+	return c.find(ctx, userIDOf(opts), func(store storage.VectorStore) (*storage.Memory, error) {
+		return store.GetByUID(ctx, uid, opts)
+	})
+}
+
+// GetByHash retrieves a memory by content hash, like Get.
+func (c *Client) GetByHash(ctx context.Context, hash string, opts *storage.GetOptions) (*storage.Memory, error) {
+	return c.find(ctx, userIDOf(opts), func(store storage.VectorStore) (*storage.Memory, error) {
+		return store.GetByHash(ctx, hash, opts)
+	})
+}
+
+// GetMany retrieves memories from the store of opts.UserID, or every store.
+func (c *Client) GetMany(ctx context.Context, ids []int64, opts *storage.GetOptions) ([]*storage.Memory, error) {
+	stores, err := c.targets(ctx, userIDOf(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*storage.Memory
+	for _, store := range stores {
+		memories, err := store.GetMany(ctx, ids, opts)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, memories...)
+	}
+	return results, nil
+}
+
+// Update updates a memory in the store of opts.UserID, or in the store
+// holding it.
+func (c *Client) Update(ctx context.Context, id int64, content string, embedding []float64, opts *storage.UpdateOptions) (*storage.Memory, error) {
+	var userID string
+	if opts != nil {
+		userID = opts.UserID
+	}
+	return c.find(ctx, userID, func(store storage.VectorStore) (*storage.Memory, error) {
+		return store.Update(ctx, id, content, embedding, opts)
+	})
+}
+
+// Delete deletes a memory from the store of opts.UserID, or from the store
+// holding it.
+func (c *Client) Delete(ctx context.Context, id int64, opts *storage.DeleteOptions) error {
+	var userID string
+	if opts != nil {
+		userID = opts.UserID
+	}
+	_, err := c.find(ctx, userID, func(store storage.VectorStore) (*storage.Memory, error) {
+		return nil, store.Delete(ctx, id, opts)
+	})
+	return err
+}
+
+// find runs op on the store of userID, or on every store until one does not
+// fail with ErrNotFound.
+func (c *Client) find(ctx context.Context, userID string, op func(store storage.VectorStore) (*storage.Memory, error)) (*storage.Memory, error) {
+	stores, err := c.targets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var notFound error
+	for _, store := range stores {
+		memory, err := op(store)
+		if err == nil || !errors.Is(err, storage.ErrNotFound) {
+			return memory, err
+		}
+		notFound = err
+	}
+	return nil, notFound
+}
+
+// GetAll retrieves memories from the store of opts.UserID, or from every
+// store, newest first.
+func (c *Client) GetAll(ctx context.Context, opts *storage.GetAllOptions) ([]*storage.Memory, error) {
+	stores, err := c.targets(ctx, opts.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(stores) == 1 {
+		return stores[0].GetAll(ctx, opts)
+	}
+
+	// Each store returns its first Offset+Limit memories, the page is cut
+	// from the merged list
+	storeOpts := *opts
+	storeOpts.Offset = 0
+	if opts.Limit > 0 {
+		storeOpts.Limit = opts.Offset + opts.Limit
+	}
+
+	var results []*storage.Memory
+	for _, store := range stores {
+		memories, err := store.GetAll(ctx, &storeOpts)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, memories...)
+	}
+	sortNewestFirst(results)
+	return limit(results, opts.Offset, opts.Limit), nil
+}
+
+// ListTags returns the tags of the store of userID, or of every store.
+func (c *Client) ListTags(ctx context.Context, userID string) ([]string, error) {
+	stores, err := c.targets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(stores) == 1 {
+		return stores[0].ListTags(ctx, userID)
+	}
+
+	seen := make(map[string]bool)
+	var tags []string
+	for _, store := range stores {
+		storeTags, err := store.ListTags(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range storeTags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// DeleteAll deletes the memories of the store of opts.UserID, or of every
+// store.
+func (c *Client) DeleteAll(ctx context.Context, opts *storage.DeleteAllOptions) error {
+	stores, err := c.targets(ctx, opts.UserID)
+	if err != nil {
+		return err
+	}
+	for _, store := range stores {
+		if err := store.DeleteAll(ctx, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteWhere deletes the matching memories of the user's store, or of
+// every store if opts has no user.
+//
+// Across several stores, the matching memories are counted in every store
+// first and compared with opts.ExpectedCount, then each store deletes the
+// memories it counted. The deletion is not atomic across stores: if a store
+// fails, the stores before it have already deleted their memories.
+func (c *Client) DeleteWhere(ctx context.Context, opts *storage.DeleteWhereOptions) (int64, error) {
+	stores, err := c.targets(ctx, opts.UserID)
+	if err != nil {
+		return 0, err
+	}
+	if len(stores) == 1 {
+		return stores[0].DeleteWhere(ctx, opts)
+	}
+
+	dryRun := *opts
+	dryRun.DryRun = true
+	counts := make([]int64, len(stores))
+	var total int64
+	for i, store := range stores {
+		if counts[i], err = store.DeleteWhere(ctx, &dryRun); err != nil {
+			return 0, err
+		}
+		total += counts[i]
+	}
+	if opts.DryRun {
+		return total, nil
+	}
+	if total != opts.ExpectedCount {
+		return total, fmt.Errorf("DeleteWhere: %w: expected %d, matched %d",
+			storage.ErrCountMismatch, opts.ExpectedCount, total)
+	}
+
+	var deleted int64
+	for i, store := range stores {
+		if counts[i] == 0 {
+			continue
+		}
+		storeOpts := *opts
+		storeOpts.ExpectedCount = counts[i]
+		n, err := store.DeleteWhere(ctx, &storeOpts)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
+
+// PurgeExpired purges the expired memories of every store.
+func (c *Client) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	for _, name := range c.names {
+		deleted, err := c.stores[name].PurgeExpired(ctx, before)
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// Ping verifies that every store is reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	for _, name := range c.names {
+		if err := c.stores[name].Ping(ctx); err != nil {
+			return fmt.Errorf("routing: store %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Close closes every store.
+//
+// Returns the first error encountered.
+func (c *Client) Close() error {
+	var firstErr error
+	for _, name := range c.names {
+		if err := c.stores[name].Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// CreateIndex creates the vector index in every store.
+func (c *Client) CreateIndex(ctx context.Context, config *storage.VectorIndexConfig) error {
+	for _, name := range c.names {
+		if err := c.stores[name].CreateIndex(ctx, config); err != nil {
+			return fmt.Errorf("routing: store %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Reset resets every store.
+func (c *Client) Reset(ctx context.Context) error {
+	for _, name := range c.names {
+		if err := c.stores[name].Reset(ctx); err != nil {
+			return fmt.Errorf("routing: store %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// AppendChange appends a change to the change log store.
+func (c *Client) AppendChange(ctx context.Context, change *storage.Change) error {
+	return c.stores[c.changeLogStore].AppendChange(ctx, change)
+}
+
+// ListChanges lists the changes of the change log store.
+func (c *Client) ListChanges(ctx context.Context, opts *storage.ListChangesOptions) ([]*storage.Change, error) {
+	return c.stores[c.changeLogStore].ListChanges(ctx, opts)
+}
+
+// LastChangeSeq returns the Seq of the latest change of the change log store.
+func (c *Client) LastChangeSeq(ctx context.Context) (int64, error) {
+	return c.stores[c.changeLogStore].LastChangeSeq(ctx)
+}
+
+// PurgeChanges purges the changes of the change log store.
+func (c *Client) PurgeChanges(ctx context.Context, before time.Time) (int64, error) {
+	return c.stores[c.changeLogStore].PurgeChanges(ctx, before)
+}
+
+// AppendAudit appends an entry to the audit log of the change log store,
+// which holds the audit log for the same reason.
+func (c *Client) AppendAudit(ctx context.Context, entry *storage.AuditEntry) error {
+	return c.stores[c.changeLogStore].AppendAudit(ctx, entry)
+}
+
+// ListAudit lists the audit entries of the change log store.
+func (c *Client) ListAudit(ctx context.Context, opts *storage.ListAuditOptions) ([]*storage.AuditEntry, error) {
+	return c.stores[c.changeLogStore].ListAudit(ctx, opts)
+}
+
+// EraseUser erases the user's data from the user's store and, if it is
+// another store, from the change log store.
+func (c *Client) EraseUser(ctx context.Context, userID string) (*storage.ErasureCounts, error) {
+	name, err := c.Route(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := c.stores[name].EraseUser(ctx, userID)
+	if err != nil || name == c.changeLogStore {
+		return counts, err
+	}
+
+	changeCounts, err := c.stores[c.changeLogStore].EraseUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	counts.Memories += changeCounts.Memories
+	counts.Changes += changeCounts.Changes
+	counts.Remaining += changeCounts.Remaining
+	return counts, nil
+}
+
+// userIDOf returns the user of get options, if any.
+func userIDOf(opts *storage.GetOptions) string {
+	if opts == nil {
+		return ""
+	}
+	return opts.UserID
+}
+
+// sortNewestFirst sorts memories by creation time, newest first.
+func sortNewestFirst(memories []*storage.Memory) {
+	sort.SliceStable(memories, func(i, j int) bool {
+		return memories[i].CreatedAt.After(memories[j].CreatedAt)
+	})
+}
+
+// limit returns the memories after offset, at most n of them (all if n <= 0).
+func limit(memories []*storage.Memory, offset, n int) []*storage.Memory {
+	if offset >= len(memories) {
+		return nil
+	}
+	memories = memories[offset:]
+	if n > 0 && n < len(memories) {
+		memories = memories[:n]
+	}
+	return memories
+}
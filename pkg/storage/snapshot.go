@@ -0,0 +1,279 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Snapshotter is implemented by the vector stores that can back up a
+// collection at a point in time and restore it.
+type Snapshotter interface {
+	// Snapshot writes a consistent copy of the memories of the collection
+	// to a new file at path. It fails if path exists.
+	Snapshot(ctx context.Context, path string) error
+
+	// RestoreSnapshot replaces the memories of the collection with those of
+	// the snapshot at path, in a single transaction. The change log and the
+	// audit log are kept.
+	RestoreSnapshot(ctx context.Context, path string) error
+}
+
+// A dump is the logical snapshot written by the SQL stores that cannot copy
+// their database file. It is a JSON Lines file: a DumpHeader, then one
+// record per row, {"table": "memories", "row": {"column": value, ...}},
+// where table is the role of the table (not its name) so that a dump can
+// be restored into a collection or table of another name. Values are
+// strings, numbers or null; timestamps are written as their wall clock
+// ("2006-01-02 15:04:05.999999").
+
+// DumpFormat identifies dump files.
+const DumpFormat = "powermem-dump"
+
+// DumpVersion is the version of the dump format.
+const DumpVersion = 1
+
+// dumpTimeLayout is the layout of the timestamps of a dump.
+const dumpTimeLayout = "2006-01-02 15:04:05.999999"
+
+// DumpHeader is the first line of a dump.
+type DumpHeader struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	Backend   string    `json:"backend"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// dumpRecord is a row of a dump.
+type dumpRecord struct {
+	Table string                 `json:"table"`
+	Row   map[string]interface{} `json:"row"`
+}
+
+// WriteDump writes the rows of table to a new dump file at path, as rows
+// of role, reading them in a read-only repeatable read transaction for a
+// consistent copy. It fails with an error wrapping os.ErrExist if path
+// exists, and removes the file if the dump fails.
+func WriteDump(ctx context.Context, db *sql.DB, path, backend, role, table string) (err error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(path)
+		}
+	}()
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	w, err := newDumpWriter(file, backend)
+	if err != nil {
+		return err
+	}
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s", table))
+	if err != nil {
+		return err
+	}
+	if err := w.writeRows(role, rows); err != nil {
+		return err
+	}
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// RestoreDump replaces the rows of table with the rows of role of the dump
+// of backend at path, in a single transaction, and returns the number of
+// rows restored. placeholder returns the placeholder of the nth argument
+// of a statement, counting from 1 ("?" or "$n").
+//
+// Columns the table does not have are skipped, so that dumps of older or
+// newer schemas can be restored and column names read from the dump never
+// reach a statement unchecked.
+func RestoreDump(ctx context.Context, db *sql.DB, path, backend, role, table string, placeholder func(n int) string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	columns, err := tableColumns(ctx, tx, table)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", table)); err != nil {
+		return 0, err
+	}
+
+	var restored int64
+	err = readDump(file, backend, func(recordRole string, row map[string]interface{}) error {
+		if recordRole != role {
+			return nil
+		}
+		restored++
+		return insertRow(ctx, tx, table, columns, row, placeholder)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return restored, nil
+}
+
+// dumpWriter writes a dump.
+type dumpWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// newDumpWriter writes the header of a dump of backend to w, and returns
+// the writer of its rows.
+func newDumpWriter(w io.Writer, backend string) (*dumpWriter, error) {
+	d := &dumpWriter{w: bufio.NewWriter(w)}
+	d.enc = json.NewEncoder(d.w)
+	header := DumpHeader{Format: DumpFormat, Version: DumpVersion, Backend: backend, CreatedAt: time.Now().UTC()}
+	if err := d.enc.Encode(header); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// writeRows writes every row of rows as a row of role, and closes rows.
+func (d *dumpWriter) writeRows(role string, rows *sql.Rows) error {
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	values := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		record := dumpRecord{Table: role, Row: make(map[string]interface{}, len(columns))}
+		for i, column := range columns {
+			switch value := values[i].(type) {
+			case []byte:
+				record.Row[column] = string(value)
+			case time.Time:
+				record.Row[column] = value.Format(dumpTimeLayout)
+			default:
+				record.Row[column] = value
+			}
+		}
+		if err := d.enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// readDump reads the dump of backend from r, calling fn with each row and
+// the role of its table. Numbers are passed as strings, so that IDs keep
+// their precision.
+func readDump(r io.Reader, backend string, fn func(table string, row map[string]interface{}) error) error {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	dec.UseNumber()
+
+	var header DumpHeader
+	if err := dec.Decode(&header); err != nil || header.Format != DumpFormat {
+		return errors.New("not a snapshot dump")
+	}
+	if header.Version > DumpVersion {
+		return fmt.Errorf("unsupported dump version %d", header.Version)
+	}
+	if header.Backend != backend {
+		return fmt.Errorf("dump of %s cannot be restored into %s", header.Backend, backend)
+	}
+
+	for {
+		var record dumpRecord
+		if err := dec.Decode(&record); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		for column, value := range record.Row {
+			if number, ok := value.(json.Number); ok {
+				record.Row[column] = number.String()
+			}
+		}
+		if err := fn(record.Table, record.Row); err != nil {
+			return err
+		}
+	}
+}
+
+// tableColumns returns the lowercase names of the columns of table.
+func tableColumns(ctx context.Context, tx *sql.Tx, table string) (map[string]bool, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s WHERE 1 = 0", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]bool, len(names))
+	for _, name := range names {
+		columns[strings.ToLower(name)] = true
+	}
+	return columns, nil
+}
+
+// insertRow inserts the columns of row that table has.
+func insertRow(ctx context.Context, tx *sql.Tx, table string, columns map[string]bool, row map[string]interface{}, placeholder func(n int) string) error {
+	names := make([]string, 0, len(row))
+	for name := range row {
+		if columns[strings.ToLower(name)] {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	placeholders := make([]string, len(names))
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		placeholders[i] = placeholder(i + 1)
+		args[i] = row[name]
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		table, strings.Join(names, ", "), strings.Join(placeholders, ", "))
+	_, err := tx.ExecContext(ctx, query, args...)
+	return err
+}
@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// auditTable returns the name of the audit log table.
+func (c *Client) auditTable() string {
+	return c.collectionName + "_audit"
+}
+
+// initAuditLog creates the audit log table.
+func (c *Client) initAuditLog(ctx context.Context) error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			seq INTEGER PRIMARY KEY AUTOINCREMENT,
+			operation TEXT NOT NULL,
+			actor TEXT NOT NULL DEFAULT '',
+			user_id TEXT NOT NULL DEFAULT '',
+			params TEXT NOT NULL,
+			created_at DATETIME NOT NULL
+		)
+	`, c.auditTable())
+	_, err := c.exec(ctx, query)
+	return err
+}
+
+// AppendAudit appends an entry to the audit log and sets its Seq.
+func (c *Client) AppendAudit(ctx context.Context, entry *storage.AuditEntry) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (operation, actor, user_id, params, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, c.auditTable())
+
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	result, err := c.exec(ctx, query,
+		entry.Operation, entry.Actor, entry.UserID, string(entry.Params), entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("AppendAudit: %w", err)
+	}
+
+	entry.Seq, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("AppendAudit: %w", err)
+	}
+	return nil
+}
+
+// ListAudit returns the audit entries after opts.After, in Seq order.
+func (c *Client) ListAudit(ctx context.Context, opts *storage.ListAuditOptions) ([]*storage.AuditEntry, error) {
+	if opts == nil {
+		opts = &storage.ListAuditOptions{}
+	}
+
+	conditions := []string{"seq > ?"}
+	args := []interface{}{opts.After}
+	if opts.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, opts.Actor)
+	}
+	if opts.UserID != "" {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, opts.UserID)
+	}
+	if len(opts.Operations) > 0 {
+		placeholders := make([]string, len(opts.Operations))
+		for i, op := range opts.Operations {
+			placeholders[i] = "?"
+			args = append(args, op)
+		}
+		conditions = append(conditions, "operation IN ("+strings.Join(placeholders, ", ")+")")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT seq, operation, actor, user_id, params, created_at
+		FROM %s WHERE %s ORDER BY seq
+	`, c.auditTable(), strings.Join(conditions, " AND "))
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListAudit: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []*storage.AuditEntry
+	for rows.Next() {
+		var entry storage.AuditEntry
+		var params string
+		if err := rows.Scan(&entry.Seq, &entry.Operation, &entry.Actor, &entry.UserID, &params, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ListAudit: %w", err)
+		}
+		entry.Params = []byte(params)
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListAudit: %w", err)
+	}
+
+	return entries, nil
+}
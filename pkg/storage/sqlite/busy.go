@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// busyRetryBackoff is the delay before the first retry of a statement that
+// failed with SQLITE_BUSY; it doubles with every retry.
+const busyRetryBackoff = 50 * time.Millisecond
+
+// synchronousModes are the accepted values of Config.Synchronous.
+var synchronousModes = map[string]bool{"OFF": true, "NORMAL": true, "FULL": true, "EXTRA": true}
+
+// dsn returns the data source name of cfg.
+//
+// Transactions start with BEGIN IMMEDIATE, so that they wait for the write
+// lock for the busy timeout when they begin instead of failing on their
+// first write when another connection holds it.
+func dsn(cfg *Config) (string, error) {
+	params := url.Values{}
+	params.Set("_foreign_keys", "1")
+	params.Set("_journal_mode", "WAL")
+	params.Set("_txlock", "immediate")
+	if cfg.BusyTimeout > 0 {
+		params.Set("_busy_timeout", fmt.Sprint(cfg.BusyTimeout.Milliseconds()))
+	}
+	if cfg.Synchronous != "" {
+		synchronous := strings.ToUpper(cfg.Synchronous)
+		if !synchronousModes[synchronous] {
+			return "", fmt.Errorf("unknown synchronous mode %q", cfg.Synchronous)
+		}
+		params.Set("_synchronous", synchronous)
+	}
+	return cfg.DBPath + "?" + params.Encode(), nil
+}
+
+// retryBusy calls fn until it does not fail with SQLITE_BUSY, at most
+// c.busyRetries more times, with exponential backoff between calls.
+func (c *Client) retryBusy(ctx context.Context, fn func() error) error {
+	backoff := busyRetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || !isBusy(err) || attempt >= c.busyRetries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// exec executes a write statement, retrying it if the database is locked.
+func (c *Client) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var result sql.Result
+	err = c.retryBusy(ctx, func() error {
+		var err error
+		result, err = c.db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+// beginTx begins a write transaction, retrying if the database is locked.
+// The lock file of advisory locking is held until the transaction ends.
+func (c *Client) beginTx(ctx context.Context) (*writeTx, error) {
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tx *sql.Tx
+	err = c.retryBusy(ctx, func() error {
+		var err error
+		tx, err = c.db.BeginTx(ctx, nil)
+		return err
+	})
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return &writeTx{Tx: tx, release: release}, nil
+}
+
+// checkpointLoop checkpoints the WAL every interval until c.stop is closed.
+//
+// PASSIVE checkpoints copy what they can without waiting for readers or
+// writers, so they never block other operations. They keep the WAL from
+// growing between SQLite's automatic checkpoints when readers are busy.
+func (c *Client) checkpointLoop(interval time.Duration) {
+	defer close(c.checkpointDone)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			_, _ = c.db.Exec("PRAGMA wal_checkpoint(PASSIVE)")
+		}
+	}
+}
@@ -1,7 +1,7 @@
 // Package sqlite provides SQLite implementation for vector storage.
 //
 // SQLite is a lightweight, file-based database suitable for local development
-// and small-scale applications. Vectors are stored as JSON strings in TEXT fields,
+// and small-scale applications. Vectors are stored as BLOBs of float64 values,
 // and similarity search uses in-memory cosine similarity calculation.
 package sqlite
 
@@ -10,15 +10,42 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"math"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/oceanbase/powermem-go/pkg/storage"
 )
 
+// memoryColumns is the column list selected for every memory read.
+// scanMemory expects columns in exactly this order.
+const memoryColumns = `id, user_id, agent_id, content, embedding, metadata,
+		created_at, updated_at, retention_strength, last_accessed_at, tags, expires_at, uid, version, parent_id, hash`
+
+// columnFields maps the columns of memoryColumns that reads can leave out to
+// the field that selects them.
+var columnFields = map[string]storage.Field{
+	"content":          storage.FieldContent,
+	"embedding":        storage.FieldEmbedding,
+	"metadata":         storage.FieldMetadata,
+	"created_at":       storage.FieldTimestamps,
+	"updated_at":       storage.FieldTimestamps,
+	"last_accessed_at": storage.FieldTimestamps,
+	"expires_at":       storage.FieldTimestamps,
+	"tags":             storage.FieldTags,
+}
+
+// selectColumns returns memoryColumns with NULL in place of the columns of
+// the fields not selected by fields and withoutEmbeddings (see
+// storage.SelectedFields), so that they are not read.
+func selectColumns(fields []storage.Field, withoutEmbeddings bool) string {
+	return storage.SelectColumns(memoryColumns, columnFields, storage.SelectedFields(fields, withoutEmbeddings))
+}
+
 // Client implements VectorStore using SQLite as the backend.
 type Client struct {
 	// db is the SQLite database connection.
@@ -29,6 +56,29 @@ type Client struct {
 
 	// dimensions is the dimension of embedding vectors.
 	dimensions int
+
+	// busyRetries is the number of retries of writes failing with
+	// SQLITE_BUSY.
+	busyRetries int
+
+	// searchWorkers is the number of goroutines scoring the rows of
+	// searches.
+	searchWorkers int
+
+	// clusters holds the centroids of clustered search.
+	clusters clusterIndex
+
+	// vectors is the vector cache of searches (nil without one).
+	vectors *vectorCache
+
+	// lock is the lock file of advisory locking (nil with LockingNone).
+	lock *writeLock
+
+	// stop is closed by Close to stop the checkpoint loop, which closes
+	// checkpointDone when it returns (nil without a checkpoint loop).
+	stop           chan struct{}
+	checkpointDone chan struct{}
+	closeOnce      sync.Once
 }
 
 // Config contains configuration for creating a SQLite VectorStore.
@@ -41,6 +91,56 @@ type Config struct {
 
 	// EmbeddingModelDims is the dimension of embedding vectors.
 	EmbeddingModelDims int
+
+	// BusyTimeout is how long a statement waits for a lock held by another
+	// connection or process before failing with SQLITE_BUSY ("database is
+	// locked"). Zero uses the driver default of 5 seconds.
+	BusyTimeout time.Duration
+
+	// BusyRetries is the number of times a write that still fails with
+	// SQLITE_BUSY after BusyTimeout is retried, with exponential backoff
+	// from 50ms. Zero disables retries.
+	BusyRetries int
+
+	// Synchronous is the synchronous pragma of the connections: OFF,
+	// NORMAL, FULL or EXTRA. NORMAL is safe from corruption in WAL mode but
+	// may lose the last transactions on power loss. Empty uses the SQLite
+	// default, FULL.
+	Synchronous string
+
+	// CheckpointInterval, if positive, is the interval of passive WAL
+	// checkpoints run in the background, in addition to SQLite's automatic
+	// checkpoints, to keep the WAL file small under constant load.
+	CheckpointInterval time.Duration
+
+	// Locking is how the writers sharing the database file coordinate:
+	// LockingNone (the default when empty), LockingAdvisory or
+	// LockingSingleWriter. Advisory locking is only available on Unix
+	// systems.
+	Locking string
+
+	// SearchWorkers is the number of goroutines computing the similarity
+	// scores of Search. Zero uses one per CPU (GOMAXPROCS); 1 scores the
+	// rows in the goroutine scanning them.
+	SearchWorkers int
+
+	// Clusters, if positive, is the number of k-means centroids trained in
+	// the background once the collection has 40 memories per centroid, to
+	// search only the memories of the clusters nearest to the query (see
+	// CreateIndex). Zero keeps exact searches, unless centroids were
+	// trained by CreateIndex.
+	Clusters int
+
+	// ProbeClusters is the number of clusters searched. Zero searches a
+	// tenth of them.
+	ProbeClusters int
+
+	// VectorCache keeps the embeddings in a memory-mapped file next to the
+	// database, DBPath.<CollectionName>.vectors, which searches read instead
+	// of the embedding column. It requires EmbeddingModelDims, and is only
+	// available on Linux, macOS and BSD systems. The file is used by one
+	// client at a time: other clients of the database search without it.
+	VectorCache bool
 }
 
 // NewClient creates a new SQLite VectorStore client.
@@ -60,33 +160,88 @@ func NewClient(cfg *Config) (*Client, error) {
 		}
 	}
 
-	db, err := sql.Open("sqlite3", cfg.DBPath+"?_foreign_keys=1&_journal_mode=WAL")
+	dataSource, err := dsn(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("NewSQLiteClient: %w", err)
+	}
+
+	lock, err := openWriteLock(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("NewSQLiteClient: %w", err)
+	}
+
+	db, err := sql.Open("sqlite3", dataSource)
 	if err != nil {
+		_ = lock.close()
 		return nil, fmt.Errorf("NewSQLiteClient: %w", err)
 	}
 
 	// Test connection
 	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		_ = lock.close()
 		return nil, fmt.Errorf("NewSQLiteClient: %w", err)
 	}
 
+	searchWorkers := cfg.SearchWorkers
+	if searchWorkers <= 0 {
+		searchWorkers = runtime.GOMAXPROCS(0)
+	}
+
+	var vectors *vectorCache
+	if cfg.VectorCache {
+		if cfg.EmbeddingModelDims <= 0 {
+			_ = db.Close()
+			_ = lock.close()
+			return nil, fmt.Errorf("NewSQLiteClient: the vector cache requires the embedding dimensions")
+		}
+		vectors, err = openVectorCache(cfg.DBPath+"."+cfg.CollectionName+".vectors", cfg.EmbeddingModelDims)
+		if err != nil {
+			_ = db.Close()
+			_ = lock.close()
+			return nil, fmt.Errorf("NewSQLiteClient: failed to open the vector cache: %w", err)
+		}
+	}
+
 	client := &Client{
 		db:             db,
 		collectionName: cfg.CollectionName,
 		dimensions:     cfg.EmbeddingModelDims,
+		busyRetries:    cfg.BusyRetries,
+		searchWorkers:  searchWorkers,
+		vectors:        vectors,
+		lock:           lock,
+		stop:           make(chan struct{}),
 	}
+	client.clusters.lists = cfg.Clusters
+	client.clusters.probes = cfg.ProbeClusters
 
 	// Initialize table structure
 	if err := client.initTables(context.Background()); err != nil {
+		_ = db.Close()
+		_ = vectors.close()
+		_ = lock.close()
 		return nil, err
 	}
+	if err := client.openClusters(context.Background()); err != nil {
+		_ = db.Close()
+		_ = vectors.close()
+		_ = lock.close()
+		return nil, fmt.Errorf("NewSQLiteClient: %w", err)
+	}
+
+	if cfg.CheckpointInterval > 0 {
+		client.checkpointDone = make(chan struct{})
+		go client.checkpointLoop(cfg.CheckpointInterval)
+	}
 
 	return client, nil
 }
 
 // initTables initializes the database table structure.
 //
-// SQLite stores vectors as JSON strings in TEXT fields.
+// The embedding column is declared TEXT, as earlier versions stored vectors
+// as JSON arrays; they are now stored as BLOBs (see encodeVector).
 func (c *Client) initTables(ctx context.Context) error {
 	query := fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS %s (
@@ -99,38 +254,184 @@ func (c *Client) initTables(ctx context.Context) error {
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			retention_strength REAL DEFAULT 1.0,
-			last_accessed_at DATETIME
+			last_accessed_at DATETIME,
+			tags TEXT,
+			expires_at DATETIME,
+			uid TEXT,
+			version INTEGER NOT NULL DEFAULT 1,
+			parent_id INTEGER,
+			hash TEXT
 		)
 	`, c.collectionName)
 
-	_, err := c.db.ExecContext(ctx, query)
+	_, err := c.exec(ctx, query)
 	if err != nil {
 		return fmt.Errorf("initTables: %w", err)
 	}
 
+	// Add columns introduced after the original schema to existing tables
+	if err := c.ensureColumn(ctx, "tags", "TEXT"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+	if err := c.ensureColumn(ctx, "expires_at", "DATETIME"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+	if err := c.ensureColumn(ctx, "uid", "TEXT"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+	if err := c.ensureColumn(ctx, "version", "INTEGER NOT NULL DEFAULT 1"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+	if err := c.ensureColumn(ctx, "parent_id", "INTEGER"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+	if err := c.ensureColumn(ctx, "cluster", "INTEGER"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+	if err := c.ensureColumn(ctx, "hash", "TEXT"); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+	if err := c.backfillHashes(ctx); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
 	// Create index
 	indexQuery := fmt.Sprintf(`
 		CREATE INDEX IF NOT EXISTS idx_%s_user_agent ON %s(user_id, agent_id)
 	`, c.collectionName, c.collectionName)
-	_, err = c.db.ExecContext(ctx, indexQuery)
+	_, err = c.exec(ctx, indexQuery)
 	if err != nil {
 		return fmt.Errorf("initTables: %w", err)
 	}
 
+	// Unique index for string IDs (NULL for memories without one)
+	uidIndexQuery := fmt.Sprintf(`
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_uid ON %s(uid)
+	`, c.collectionName, c.collectionName)
+	if _, err := c.exec(ctx, uidIndexQuery); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
+	// Index to find the chunks of a memory
+	parentIndexQuery := fmt.Sprintf(`
+		CREATE INDEX IF NOT EXISTS idx_%s_parent_id ON %s(parent_id)
+	`, c.collectionName, c.collectionName)
+	if _, err := c.exec(ctx, parentIndexQuery); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
+	// Index to find the memories of a user by content hash
+	hashIndexQuery := fmt.Sprintf(`
+		CREATE INDEX IF NOT EXISTS idx_%s_user_hash ON %s(user_id, hash)
+	`, c.collectionName, c.collectionName)
+	if _, err := c.exec(ctx, hashIndexQuery); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
+	if err := c.initChangeLog(ctx); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
+	if err := c.initAuditLog(ctx); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
+	if err := c.initTeams(ctx); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
+	if err := c.initCentroids(ctx); err != nil {
+		return fmt.Errorf("initTables: %w", err)
+	}
+
+	if c.vectors != nil {
+		if err := c.initVectorLog(ctx); err != nil {
+			return fmt.Errorf("initTables: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// ensureColumn adds a column to the memories table if it does not exist yet.
+func (c *Client) ensureColumn(ctx context.Context, name, definition string) error {
+	rows, err := c.db.QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", c.collectionName))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var colName, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &colName, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return err
+		}
+		if colName == name {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = c.exec(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.collectionName, name, definition))
+	return err
+}
+
+// backfillHashes sets the hash of the memories stored before the hash column
+// existed, so that GetByHash finds them too.
+func (c *Client) backfillHashes(ctx context.Context) error {
+	const batchSize = 1000
+
+	query := fmt.Sprintf("SELECT id, content FROM %s WHERE hash IS NULL AND id > ? ORDER BY id LIMIT %d", c.collectionName, batchSize)
+	update := fmt.Sprintf("UPDATE %s SET hash = ? WHERE id = ?", c.collectionName)
+	var after int64 = -1 << 63
+	for {
+		rows, err := c.db.QueryContext(ctx, query, after)
+		if err != nil {
+			return fmt.Errorf("backfill hashes: %w", err)
+		}
+		hashes := make(map[int64]string)
+		for rows.Next() {
+			var id int64
+			var content string
+			if err := rows.Scan(&id, &content); err != nil {
+				_ = rows.Close()
+				return fmt.Errorf("backfill hashes: %w", err)
+			}
+			hashes[id] = storage.ContentHash(content)
+			after = id
+		}
+		err = rows.Err()
+		_ = rows.Close()
+		if err != nil {
+			return fmt.Errorf("backfill hashes: %w", err)
+		}
+
+		for id, hash := range hashes {
+			if _, err := c.exec(ctx, update, hash, id); err != nil {
+				return fmt.Errorf("backfill hashes: %w", err)
+			}
+		}
+		if len(hashes) < batchSize {
+			return nil
+		}
+	}
+}
+
 // Insert inserts a memory into the SQLite database.
 //
-// Vectors are stored as JSON strings in TEXT fields.
+// Vectors are stored as BLOBs of little-endian float64 values.
 func (c *Client) Insert(ctx context.Context, memory *storage.Memory) error {
 	query := fmt.Sprintf(`
 		INSERT INTO %s 
-		(id, user_id, agent_id, content, embedding, metadata, created_at, retention_strength)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`, c.collectionName)
+		(id, user_id, agent_id, content, embedding, metadata, created_at, updated_at, version, retention_strength, tags, expires_at, uid, parent_id, hash, cluster)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, %s)
+	`, c.collectionName, c.clusterValue())
 
-	embeddingJSON, err := json.Marshal(memory.Embedding)
+	clusterArgs, err := c.clusterArgs(ctx, memory.Embedding)
 	if err != nil {
 		return fmt.Errorf("Insert: %w", err)
 	}
@@ -140,28 +441,54 @@ func (c *Client) Insert(ctx context.Context, memory *storage.Memory) error {
 		return fmt.Errorf("Insert: %w", err)
 	}
 
-	_, err = c.db.ExecContext(ctx, query,
+	tagsJSON, err := marshalTags(memory.Tags)
+	if err != nil {
+		return fmt.Errorf("Insert: %w", err)
+	}
+
+	createdAt, updatedAt, version := storage.InsertTimes(memory, time.Now())
+
+	_, err = c.exec(ctx, query,
 		memory.ID,
 		memory.UserID,
 		memory.AgentID,
 		memory.Content,
-		string(embeddingJSON),
+		encodeVector(memory.Embedding),
 		string(metadataJSON),
-		time.Now(),
+		createdAt,
+		updatedAt,
+		version,
 		memory.RetentionStrength,
+		tagsJSON,
+		memory.ExpiresAt,
+		nullableUID(memory.UID),
+		nullableParentID(memory.ParentID),
+		storage.ContentHash(memory.Content),
+		clusterArgs[0],
+		clusterArgs[1],
 	)
 
+	if isDuplicateID(err) {
+		return fmt.Errorf("Insert: %w: %v", storage.ErrDuplicateID, err)
+	}
+	if isDuplicateUID(err) {
+		return fmt.Errorf("Insert: %w: %v", storage.ErrDuplicateUID, err)
+	}
 	if err != nil {
 		return fmt.Errorf("Insert: %w", err)
 	}
 
+	c.maybeTrainClusters(1)
 	return nil
 }
 
 // Search performs vector similarity search using cosine similarity.
 //
 // SQLite does not have native vector operations, so similarity is calculated
-// in memory after loading all matching records.
+// in memory. Only the IDs and embeddings of the matching records are scanned,
+// and scored in parallel by Config.SearchWorkers workers (see scoreRows); the
+// best ones are loaded afterwards. With Config.VectorCache, only the IDs are
+// scanned and the embeddings are read from the vector cache.
 //
 // The method supports hybrid search parameters for future enhancement:
 //   - opts.Query: Original query text (reserved for full-text search)
@@ -177,24 +504,97 @@ func (c *Client) Search(ctx context.Context, embedding []float64, opts *storage.
 		minScore = opts.Threshold
 	}
 
-	whereClause, args := buildWhereClause(opts.UserID, opts.AgentID, opts.Filters)
+	clusters, err := c.probedClusters(ctx, embedding)
+	if err != nil {
+		return nil, fmt.Errorf("Search: %w", err)
+	}
+
+	whereClause, args := buildWhereClause(whereFilter{
+		userID:    opts.UserID,
+		teamIDs:   opts.TeamIDs,
+		agentID:   opts.AgentID,
+		filters:   opts.Filters,
+		timeRange: opts.TimeRange,
+		tags:      opts.Tags,
+		activeAt:  storage.ActiveAt(opts.Now),
+		clusters:  clusters,
+	})
+
+	// TODO: Future enhancement - add full-text search support using opts.Query
+	// This would enable hybrid retrieval combining vector similarity and keyword matching
+
+	top, candidates, aboveThreshold, err := c.scoreRows(ctx, whereClause, args, newScorer(embedding, minScore, opts.After), opts.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("Search: %w", err)
+	}
+
+	// TODO: Future enhancement - combine with sparse embedding similarity
+	// if opts.SparseEmbedding != nil {
+	//     sparseScore := calculateSparseSimilarity(opts.SparseEmbedding, memory.SparseEmbedding)
+	//     combinedScore := (score + sparseScore) / 2.0
+	//     memory.Score = combinedScore
+	// }
+
+	if opts.Stats != nil {
+		opts.Stats.Candidates = candidates
+		opts.Stats.AboveThreshold = aboveThreshold
+		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s", c.collectionName)
+		if err := c.db.QueryRowContext(ctx, countQuery).Scan(&opts.Stats.TotalMemories); err != nil {
+			return nil, fmt.Errorf("Search: %w", err)
+		}
+	}
+
+	memories, err := c.loadScored(ctx, top.sorted(), selectColumns(opts.Fields, opts.WithoutEmbeddings))
+	if err != nil {
+		return nil, fmt.Errorf("Search: %w", err)
+	}
+	return memories, nil
+}
+
+// SearchIter performs vector similarity search, returning results in batches.
+//
+// Similarity is computed in Go, so every page scans the candidate rows, but
+// only one batch of memories is kept.
+func (c *Client) SearchIter(ctx context.Context, embedding []float64, opts *storage.SearchOptions, batchSize int) (storage.MemoryIterator, error) {
+	return storage.NewSearchIterator(c.Search, embedding, opts, batchSize)
+}
+
+// SearchByKeyword performs a keyword search on memory content using LIKE.
+//
+// SQLite's LIKE is case-insensitive for ASCII characters.
+func (c *Client) SearchByKeyword(ctx context.Context, text string, opts *storage.SearchOptions) ([]*storage.Memory, error) {
+	whereClause, args := buildWhereClause(whereFilter{
+		userID:    opts.UserID,
+		teamIDs:   opts.TeamIDs,
+		agentID:   opts.AgentID,
+		filters:   opts.Filters,
+		timeRange: opts.TimeRange,
+		tags:      opts.Tags,
+		activeAt:  storage.ActiveAt(opts.Now),
+
+		excludeChunks: true,
+	})
+
+	if whereClause == "" {
+		whereClause = "WHERE content LIKE ? ESCAPE '\\'"
+	} else {
+		whereClause += " AND content LIKE ? ESCAPE '\\'"
+	}
+	args = append(args, "%"+escapeLikePattern(text)+"%")
 
-	// SQLite requires manual cosine similarity calculation
 	query := fmt.Sprintf(`
-		SELECT 
-			id, user_id, agent_id, content, embedding, metadata,
-			created_at, updated_at, retention_strength, last_accessed_at
+		SELECT %s
 		FROM %s
 		%s
-		ORDER BY id
-	`, c.collectionName, whereClause)
+		ORDER BY created_at DESC
+		LIMIT ?
+	`, selectColumns(opts.Fields, opts.WithoutEmbeddings), c.collectionName, whereClause)
 
-	// TODO: Future enhancement - add full-text search support using opts.Query
-	// This would enable hybrid retrieval combining vector similarity and keyword matching
+	args = append(args, opts.Limit)
 
 	rows, err := c.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("Search: %w", err)
+		return nil, fmt.Errorf("SearchByKeyword: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
@@ -204,43 +604,41 @@ func (c *Client) Search(ctx context.Context, embedding []float64, opts *storage.
 		if err != nil {
 			return nil, err
 		}
-
-		// Calculate cosine similarity
-		score := cosineSimilarity(embedding, memory.Embedding)
-		memory.Score = score
-
-		// Apply threshold filter
-		if score >= minScore {
-			memories = append(memories, memory)
-		}
-
-		// TODO: Future enhancement - combine with sparse embedding similarity
-		// if opts.SparseEmbedding != nil {
-		//     sparseScore := calculateSparseSimilarity(opts.SparseEmbedding, memory.SparseEmbedding)
-		//     combinedScore := (score + sparseScore) / 2.0
-		//     memory.Score = combinedScore
-		// }
+		memory.Score = 1.0
+		memories = append(memories, memory)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	// Sort by score and limit results
-	memories = sortByScore(memories, opts.Limit)
-
 	return memories, nil
 }
 
 // Get retrieves a memory by ID with optional access control.
 func (c *Client) Get(ctx context.Context, id int64, opts *storage.GetOptions) (*storage.Memory, error) {
+	return c.getOne(ctx, "Get", "id", id, opts)
+}
+
+// GetByUID retrieves a memory by its string ID with optional access control.
+func (c *Client) GetByUID(ctx context.Context, uid string, opts *storage.GetOptions) (*storage.Memory, error) {
+	return c.getOne(ctx, "GetByUID", "uid", uid, opts)
+}
+
+// GetByHash retrieves a memory by content hash with optional access control.
+func (c *Client) GetByHash(ctx context.Context, hash string, opts *storage.GetOptions) (*storage.Memory, error) {
+	return c.getOne(ctx, "GetByHash", "hash", hash, opts)
+}
+
+// getOne retrieves the memory whose key column equals value.
+func (c *Client) getOne(ctx context.Context, op, column string, value interface{}, opts *storage.GetOptions) (*storage.Memory, error) {
 	if opts == nil {
 		opts = &storage.GetOptions{}
 	}
 
 	// Build WHERE clause with access control
-	whereClause := "WHERE id = ?"
-	args := []interface{}{id}
+	whereClause := fmt.Sprintf("WHERE %s = ?", column)
+	args := []interface{}{value}
 
 	if opts.UserID != "" {
 		whereClause += " AND user_id = ?"
@@ -251,40 +649,160 @@ func (c *Client) Get(ctx context.Context, id int64, opts *storage.GetOptions) (*
 		args = append(args, opts.AgentID)
 	}
 
+	// Expired memories are treated as not found
+	whereClause += " AND (expires_at IS NULL OR julianday(expires_at) > julianday(?))"
+	args = append(args, storage.ActiveAt(opts.Now))
+
 	query := fmt.Sprintf(`
-		SELECT id, user_id, agent_id, content, embedding, metadata,
-		       created_at, updated_at, retention_strength, last_accessed_at
+		SELECT %s
 		FROM %s
 		%s
-	`, c.collectionName, whereClause)
+	`, memoryColumns, c.collectionName, whereClause)
 
 	row := c.db.QueryRowContext(ctx, query, args...)
 
 	memory, err := c.scanMemory(row)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("Get: not found or access denied")
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrNotFound)
 	}
 	if err != nil {
-		return nil, fmt.Errorf("Get: %w", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return memory, nil
 }
 
+// ListTags returns the distinct tags in use, sorted alphabetically.
+//
+// If userID is empty, tags across all users are returned.
+func (c *Client) ListTags(ctx context.Context, userID string) ([]string, error) {
+	whereClause, args := buildWhereClause(whereFilter{userID: userID, activeAt: time.Now()})
+
+	query := fmt.Sprintf(`
+		SELECT DISTINCT t.value
+		FROM %s, json_each(%s.tags) AS t
+		%s
+		ORDER BY t.value
+	`, c.collectionName, c.collectionName, whereClause)
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListTags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("ListTags: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListTags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetMany retrieves multiple memories by ID using a single IN query.
+func (c *Client) GetMany(ctx context.Context, ids []int64, opts *storage.GetOptions) ([]*storage.Memory, error) {
+	if opts == nil {
+		opts = &storage.GetOptions{}
+	}
+	if len(ids) == 0 {
+		return []*storage.Memory{}, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, 0, len(ids)+2)
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	whereClause := fmt.Sprintf("WHERE id IN (%s)", strings.Join(placeholders, ", "))
+
+	if opts.UserID != "" {
+		whereClause += " AND user_id = ?"
+		args = append(args, opts.UserID)
+	}
+	if opts.AgentID != "" {
+		whereClause += " AND agent_id = ?"
+		args = append(args, opts.AgentID)
+	}
+
+	// Expired memories are treated as not found
+	whereClause += " AND (expires_at IS NULL OR julianday(expires_at) > julianday(?))"
+	args = append(args, storage.ActiveAt(opts.Now))
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		%s
+	`, memoryColumns, c.collectionName, whereClause)
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("GetMany: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var memories []*storage.Memory
+	for rows.Next() {
+		memory, err := c.scanMemory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("GetMany: %w", err)
+		}
+		memories = append(memories, memory)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetMany: %w", err)
+	}
+
+	return memories, nil
+}
+
 // Update updates a memory with optional access control.
 func (c *Client) Update(ctx context.Context, id int64, content string, embedding []float64, opts *storage.UpdateOptions) (*storage.Memory, error) {
 	if opts == nil {
 		opts = &storage.UpdateOptions{}
 	}
 
-	embeddingJSON, err := json.Marshal(embedding)
+	// created_at is intentionally never part of the SET clause
+	clusterArgs, err := c.clusterArgs(ctx, embedding)
 	if err != nil {
 		return nil, fmt.Errorf("Update: %w", err)
 	}
+	setClause := "SET content = ?, embedding = ?, updated_at = ?, hash = ?, version = version + 1, cluster = " + c.clusterValue()
+	args := append([]interface{}{content, encodeVector(embedding), storage.UpdateTime(opts), storage.ContentHash(content)}, clusterArgs...)
+
+	if opts.Metadata != nil {
+		metadataJSON, err := json.Marshal(opts.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("Update: %w", err)
+		}
+		setClause += ", metadata = ?"
+		args = append(args, string(metadataJSON))
+	}
+	if opts.RetentionStrength != nil {
+		setClause += ", retention_strength = ?"
+		args = append(args, *opts.RetentionStrength)
+	}
+	if opts.LastAccessedAt != nil {
+		setClause += ", last_accessed_at = ?"
+		args = append(args, *opts.LastAccessedAt)
+	}
+	if opts.ClearExpiresAt {
+		setClause += ", expires_at = NULL"
+	}
 
 	// Build WHERE clause with access control
 	whereClause := "WHERE id = ?"
-	args := []interface{}{content, string(embeddingJSON), time.Now(), id}
+	args = append(args, id)
 
 	if opts.UserID != "" {
 		whereClause += " AND user_id = ?"
@@ -294,14 +812,18 @@ func (c *Client) Update(ctx context.Context, id int64, content string, embedding
 		whereClause += " AND agent_id = ?"
 		args = append(args, opts.AgentID)
 	}
+	if opts.ExpectedVersion > 0 {
+		whereClause += " AND version = ?"
+		args = append(args, opts.ExpectedVersion)
+	}
 
 	query := fmt.Sprintf(`
 		UPDATE %s
-		SET content = ?, embedding = ?, updated_at = ?
 		%s
-	`, c.collectionName, whereClause)
+		%s
+	`, c.collectionName, setClause, whereClause)
 
-	result, err := c.db.ExecContext(ctx, query, args...)
+	result, err := c.exec(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("Update: %w", err)
 	}
@@ -312,7 +834,7 @@ func (c *Client) Update(ctx context.Context, id int64, content string, embedding
 	}
 
 	if rowsAffected == 0 {
-		return nil, fmt.Errorf("Update: not found or access denied")
+		return nil, c.updateMissError(ctx, id, opts)
 	}
 
 	return c.Get(ctx, id, &storage.GetOptions{
@@ -321,6 +843,17 @@ func (c *Client) Update(ctx context.Context, id int64, content string, embedding
 	})
 }
 
+// updateMissError explains why Update matched no row: a version conflict if
+// the memory is still accessible, "not found or access denied" otherwise.
+func (c *Client) updateMissError(ctx context.Context, id int64, opts *storage.UpdateOptions) error {
+	if opts.ExpectedVersion > 0 {
+		if _, err := c.Get(ctx, id, &storage.GetOptions{UserID: opts.UserID, AgentID: opts.AgentID}); err == nil {
+			return fmt.Errorf("Update: %w", storage.ErrVersionConflict)
+		}
+	}
+	return fmt.Errorf("Update: %w", storage.ErrNotFound)
+}
+
 // Delete deletes a memory by ID with optional access control.
 func (c *Client) Delete(ctx context.Context, id int64, opts *storage.DeleteOptions) error {
 	if opts == nil {
@@ -342,7 +875,7 @@ func (c *Client) Delete(ctx context.Context, id int64, opts *storage.DeleteOptio
 
 	query := fmt.Sprintf("DELETE FROM %s %s", c.collectionName, whereClause)
 
-	result, err := c.db.ExecContext(ctx, query, args...)
+	result, err := c.exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("Delete: %w", err)
 	}
@@ -353,7 +886,7 @@ func (c *Client) Delete(ctx context.Context, id int64, opts *storage.DeleteOptio
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("Delete: not found or access denied")
+		return fmt.Errorf("Delete: %w", storage.ErrNotFound)
 	}
 
 	return nil
@@ -361,16 +894,27 @@ func (c *Client) Delete(ctx context.Context, id int64, opts *storage.DeleteOptio
 
 // GetAll retrieves all memories with optional filtering and pagination.
 func (c *Client) GetAll(ctx context.Context, opts *storage.GetAllOptions) ([]*storage.Memory, error) {
-	whereClause, args := buildWhereClause(opts.UserID, opts.AgentID, nil)
+	whereClause, args := buildWhereClause(whereFilter{
+		userID:    opts.UserID,
+		teamIDs:   opts.TeamIDs,
+		agentID:   opts.AgentID,
+		timeRange: opts.TimeRange,
+		tags:      opts.Tags,
+		filters:   opts.Filters,
+		entities:  opts.Entities,
+		activeAt:  storage.ActiveAt(opts.Now),
+
+		parentID:      opts.ParentID,
+		excludeChunks: true,
+	})
 
 	query := fmt.Sprintf(`
-		SELECT id, user_id, agent_id, content, embedding, metadata,
-		       created_at, updated_at, retention_strength, last_accessed_at
+		SELECT %s
 		FROM %s
 		%s
 		ORDER BY created_at DESC
 		LIMIT ? OFFSET ?
-	`, c.collectionName, whereClause)
+	`, selectColumns(opts.Fields, opts.WithoutEmbeddings), c.collectionName, whereClause)
 
 	args = append(args, opts.Limit, opts.Offset)
 
@@ -394,11 +938,11 @@ func (c *Client) GetAll(ctx context.Context, opts *storage.GetAllOptions) ([]*st
 
 // DeleteAll deletes all memories matching the given filters.
 func (c *Client) DeleteAll(ctx context.Context, opts *storage.DeleteAllOptions) error {
-	whereClause, args := buildWhereClause(opts.UserID, opts.AgentID, nil)
+	whereClause, args := buildWhereClause(whereFilter{userID: opts.UserID, agentID: opts.AgentID, parentID: opts.ParentID})
 
 	query := fmt.Sprintf("DELETE FROM %s %s", c.collectionName, whereClause)
 
-	_, err := c.db.ExecContext(ctx, query, args...)
+	_, err := c.exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("DeleteAll: %w", err)
 	}
@@ -406,34 +950,93 @@ func (c *Client) DeleteAll(ctx context.Context, opts *storage.DeleteAllOptions)
 	return nil
 }
 
-// Close closes the database connection.
-func (c *Client) Close() error {
-	if c.db != nil {
-		return c.db.Close()
+// PurgeExpired permanently deletes memories that expired at or before the given time.
+func (c *Client) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE expires_at IS NOT NULL AND julianday(expires_at) <= julianday(?)", c.collectionName)
+
+	result, err := c.exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("PurgeExpired: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("PurgeExpired: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// Ping verifies that the database connection is alive.
+func (c *Client) Ping(ctx context.Context) error {
+	if err := c.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("Ping: %w", err)
 	}
 	return nil
 }
 
+// Close closes the database connection.
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		if c.checkpointDone != nil {
+			<-c.checkpointDone
+		}
+		// Wait for the training of clusters, which stops with c.stop
+		c.clusters.training.Lock()
+		if c.db != nil {
+			err = c.db.Close()
+		}
+		c.clusters.training.Unlock()
+		if cacheErr := c.vectors.close(); err == nil {
+			err = cacheErr
+		}
+		if lockErr := c.lock.close(); err == nil {
+			err = lockErr
+		}
+	})
+	return err
+}
+
 // CreateIndex creates a vector index.
 //
-// SQLite does not support vector indexes, so this method is a no-op.
-// Similarity search uses full table scan with in-memory calculation.
+// With IndexTypeIVFFlat, it trains IVFParams.Nlist k-means centroids
+// (Config.Clusters by default) for clustered search, replacing the current
+// ones, and sets the number of clusters searched to IVFParams.Nprobe if it
+// is positive. It blocks until every memory is assigned to a cluster.
+//
+// SQLite has no other vector index, so other index types are a no-op:
+// similarity search uses full table scan with in-memory calculation.
 func (c *Client) CreateIndex(ctx context.Context, config *storage.VectorIndexConfig) error {
-	// SQLite does not support vector indexes, uses full table scan
+	if config == nil || config.IndexType != storage.IndexTypeIVFFlat {
+		return nil
+	}
+	var lists, probes int
+	if config.IVFParams != nil {
+		lists, probes = config.IVFParams.Nlist, config.IVFParams.Nprobe
+	}
+	if err := c.trainIndex(ctx, lists, probes); err != nil {
+		return fmt.Errorf("CreateIndex: %w", err)
+	}
 	return nil
 }
 
 // Reset resets the vector store by dropping and recreating the table.
 //
 // WARNING: This operation will delete ALL memories and cannot be undone.
-// The table will be recreated with the same schema and indexes.
+// The table will be recreated with the same schema and indexes. The change
+// log is kept.
 func (c *Client) Reset(ctx context.Context) error {
-	// Drop the table
-	dropQuery := fmt.Sprintf("DROP TABLE IF EXISTS %s", c.collectionName)
-	_, err := c.db.ExecContext(ctx, dropQuery)
-	if err != nil {
-		return fmt.Errorf("Reset: failed to drop table: %w", err)
+	// Drop the table, its centroids and its vector log, whose new epoch
+	// makes vector caches rebuild
+	for _, table := range []string{c.collectionName, c.centroidsTable(), c.vectorLogTable(), c.vectorEpochTable()} {
+		dropQuery := fmt.Sprintf("DROP TABLE IF EXISTS %s", table)
+		if _, err := c.exec(ctx, dropQuery); err != nil {
+			return fmt.Errorf("Reset: failed to drop table: %w", err)
+		}
 	}
+	c.clusters.rows.Store(0)
 
 	// Recreate the table
 	if err := c.initTables(ctx); err != nil {
@@ -443,105 +1046,86 @@ func (c *Client) Reset(ctx context.Context) error {
 	return nil
 }
 
-// scanMemory scans a memory from a database row or rows.
-func (c *Client) scanMemory(scanner interface{}) (*storage.Memory, error) {
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanMemory scans a row selected with memoryColumns into a Memory.
+//
+// extra receives any additional columns selected after memoryColumns.
+func (c *Client) scanMemory(row rowScanner, extra ...interface{}) (*storage.Memory, error) {
 	var memory storage.Memory
-	var embeddingStr string
-	var metadataStr string
+	var content sql.NullString
+	var embedding vectorScanner
+	var metadataStr sql.NullString
+	var createdAt sql.NullTime
+	var updatedAt sql.NullTime
 	var lastAccessedAt sql.NullTime
-
-	var err error
-	switch s := scanner.(type) {
-	case *sql.Row:
-		err = s.Scan(
-			&memory.ID,
-			&memory.UserID,
-			&memory.AgentID,
-			&memory.Content,
-			&embeddingStr,
-			&metadataStr,
-			&memory.CreatedAt,
-			&memory.UpdatedAt,
-			&memory.RetentionStrength,
-			&lastAccessedAt,
-		)
-	case *sql.Rows:
-		err = s.Scan(
-			&memory.ID,
-			&memory.UserID,
-			&memory.AgentID,
-			&memory.Content,
-			&embeddingStr,
-			&metadataStr,
-			&memory.CreatedAt,
-			&memory.UpdatedAt,
-			&memory.RetentionStrength,
-			&lastAccessedAt,
-		)
-	default:
-		return nil, fmt.Errorf("unsupported scanner type")
+	var tagsStr sql.NullString
+	var expiresAt sql.NullTime
+	var uid sql.NullString
+	var parentID sql.NullInt64
+	var hash sql.NullString
+
+	dest := []interface{}{
+		&memory.ID,
+		&memory.UserID,
+		&memory.AgentID,
+		&content,
+		&embedding,
+		&metadataStr,
+		&createdAt,
+		&updatedAt,
+		&memory.RetentionStrength,
+		&lastAccessedAt,
+		&tagsStr,
+		&expiresAt,
+		&uid,
+		&memory.Version,
+		&parentID,
+		&hash,
 	}
-
-	if err != nil {
+	if err := row.Scan(append(dest, extra...)...); err != nil {
 		return nil, err
 	}
 
-	// Parse embedding
-	if err := json.Unmarshal([]byte(embeddingStr), &memory.Embedding); err != nil {
-		return nil, fmt.Errorf("parse embedding: %w", err)
-	}
+	// Columns of fields that were not selected are NULL
+	memory.Content = content.String
+	memory.Embedding = embedding.vector
+	memory.CreatedAt = createdAt.Time
+	memory.UpdatedAt = updatedAt.Time
 
 	// Parse metadata
-	if metadataStr != "" {
-		if err := json.Unmarshal([]byte(metadataStr), &memory.Metadata); err != nil {
+	if metadataStr.String != "" {
+		if err := json.Unmarshal([]byte(metadataStr.String), &memory.Metadata); err != nil {
 			return nil, fmt.Errorf("parse metadata: %w", err)
 		}
 	}
 
+	// Parse tags
+	if tagsStr.Valid && tagsStr.String != "" {
+		if err := json.Unmarshal([]byte(tagsStr.String), &memory.Tags); err != nil {
+			return nil, fmt.Errorf("parse tags: %w", err)
+		}
+	}
+
 	// Handle last_accessed_at
 	if lastAccessedAt.Valid {
 		memory.LastAccessedAt = &lastAccessedAt.Time
 	}
 
-	return &memory, nil
-}
-
-// cosineSimilarity calculates the cosine similarity between two vectors.
-func cosineSimilarity(a, b []float64) float64 {
-	if len(a) != len(b) {
-		return 0
+	// Handle expires_at
+	if expiresAt.Valid {
+		memory.ExpiresAt = &expiresAt.Time
 	}
 
-	var dotProduct, normA, normB float64
-	for i := range a {
-		dotProduct += a[i] * b[i]
-		normA += a[i] * a[i]
-		normB += b[i] * b[i]
+	if uid.Valid {
+		memory.UID = uid.String
 	}
+	memory.ParentID = parentID.Int64
+	memory.Hash = hash.String
+	memory.RunID, _ = memory.Metadata[storage.RunIDKey].(string)
 
-	if normA == 0 || normB == 0 {
-		return 0
-	}
-
-	return dotProduct / (math.Sqrt(normA) * math.Sqrt(normB))
-}
-
-// sortByScore sorts memories by score (descending) and limits the number of results.
-//
-// Uses a simple bubble sort which is sufficient for small datasets.
-func sortByScore(memories []*storage.Memory, limit int) []*storage.Memory {
-	n := len(memories)
-	for i := 0; i < n-1; i++ {
-		for j := 0; j < n-i-1; j++ {
-			if memories[j].Score < memories[j+1].Score {
-				memories[j], memories[j+1] = memories[j+1], memories[j]
-			}
-		}
-	}
-
-	if limit > 0 && len(memories) > limit {
-		return memories[:limit]
-	}
-
-	return memories
+	return &memory, nil
 }
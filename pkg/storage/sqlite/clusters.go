@@ -0,0 +1,591 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clustered search narrows the scan of Search, like an IVF index: k-means
+// centroids of the embeddings are kept in the <collection>_centroids table,
+// every memory records its nearest centroid in the cluster column, and
+// Search only scores the memories of the clusters nearest to the query, and
+// those not assigned to any cluster. It is approximate: a memory whose
+// cluster is not probed is missed.
+//
+// The centroids are trained by CreateIndex with IndexTypeIVFFlat, or in the
+// background once a collection configured with Config.Clusters has
+// trainingRowsPerCluster rows per cluster. All centroids share a generation
+// number, so that the clients sharing the database file notice when one of
+// them trains new centroids; memories assigned with the centroids of
+// another generation are left unassigned.
+
+const (
+	// trainingRowsPerCluster is the number of rows per cluster sampled to
+	// train the centroids, and needed before automatic training.
+	trainingRowsPerCluster = 40
+
+	// kmeansIterations is the maximum number of k-means iterations.
+	kmeansIterations = 10
+
+	// assignBatchSize is the number of rows assigned to clusters at once
+	// after training.
+	assignBatchSize = 4096
+)
+
+// clusterIndex holds the centroids of clustered search.
+type clusterIndex struct {
+	// lists is the number of centroids trained automatically (0: none).
+	lists int
+
+	// mu guards the fields below.
+	mu sync.RWMutex
+
+	// probes is the number of clusters searched (0: a tenth of them).
+	probes int
+
+	// generation is the generation of centroids, which are normalized, and
+	// nil if the collection has none.
+	generation int64
+	centroids  [][]float32
+
+	// training is held while centroids are trained.
+	training sync.Mutex
+
+	// rows counts the rows of a collection without centroids, towards
+	// automatic training.
+	rows atomic.Int64
+}
+
+// centroidsTable returns the name of the centroids table.
+func (c *Client) centroidsTable() string {
+	return c.collectionName + "_centroids"
+}
+
+// initCentroids creates the centroids table and the indexes of the cluster
+// column, which initTables adds. The user index lets searches skip the
+// memories of other clusters without reading them.
+func (c *Client) initCentroids(ctx context.Context) error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY,
+			generation INTEGER NOT NULL,
+			vector BLOB NOT NULL
+		)
+	`, c.centroidsTable())
+	if _, err := c.exec(ctx, query); err != nil {
+		return err
+	}
+
+	for _, index := range []struct{ name, columns string }{
+		{"cluster", "cluster"},
+		{"user_cluster", "user_id, cluster"},
+	} {
+		indexQuery := fmt.Sprintf(`
+			CREATE INDEX IF NOT EXISTS idx_%s_%s ON %s(%s)
+		`, c.collectionName, index.name, c.collectionName, index.columns)
+		if _, err := c.exec(ctx, indexQuery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openClusters loads the centroids when the client opens, and starts their
+// training if the collection is configured with clusters and needs them.
+func (c *Client) openClusters(ctx context.Context) error {
+	_, centroids, err := c.currentCentroids(ctx)
+	if err != nil {
+		return err
+	}
+	if c.clusters.lists == 0 || centroids != nil {
+		return nil
+	}
+
+	var rows int64
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", c.collectionName)
+	if err := c.db.QueryRowContext(ctx, query).Scan(&rows); err != nil {
+		return err
+	}
+	c.maybeTrainClusters(rows)
+	return nil
+}
+
+// currentCentroids returns the current generation of centroids, reloading
+// them if another client trained new ones.
+func (c *Client) currentCentroids(ctx context.Context) (int64, [][]float32, error) {
+	var generation int64
+	query := fmt.Sprintf("SELECT COALESCE((SELECT generation FROM %s WHERE id = 0), 0)", c.centroidsTable())
+	if err := c.db.QueryRowContext(ctx, query).Scan(&generation); err != nil {
+		return 0, nil, err
+	}
+
+	c.clusters.mu.RLock()
+	current, centroids := c.clusters.generation, c.clusters.centroids
+	c.clusters.mu.RUnlock()
+	if generation == current {
+		return current, centroids, nil
+	}
+
+	centroids = nil
+	if generation != 0 {
+		query := fmt.Sprintf("SELECT id, vector FROM %s WHERE generation = ? ORDER BY id", c.centroidsTable())
+		rows, err := c.db.QueryContext(ctx, query, generation)
+		if err != nil {
+			return 0, nil, err
+		}
+		batch := new(scoreBatch)
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id, batch); err != nil {
+				_ = rows.Close()
+				return 0, nil, err
+			}
+			batch.ids = append(batch.ids, id)
+		}
+		err = rows.Err()
+		_ = rows.Close()
+		if err != nil {
+			return 0, nil, err
+		}
+		for i := range batch.ids {
+			centroids = append(centroids, batch.vector(i))
+		}
+	}
+
+	c.clusters.mu.Lock()
+	c.clusters.generation, c.clusters.centroids = generation, centroids
+	c.clusters.mu.Unlock()
+	return generation, centroids, nil
+}
+
+// clusterValue returns the SQL expression of the cluster column of a
+// written memory, which takes the arguments returned by clusterArgs. The
+// cluster is only kept if the centroids are still of the same generation
+// when the statement runs.
+func (c *Client) clusterValue() string {
+	return fmt.Sprintf("CASE WHEN (SELECT generation FROM %s WHERE id = 0) = ? THEN ? END", c.centroidsTable())
+}
+
+// clusterArgs returns the arguments of clusterValue for a memory with
+// embedding.
+func (c *Client) clusterArgs(ctx context.Context, embedding []float64) ([]interface{}, error) {
+	generation, centroids, err := c.currentCentroids(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if centroids == nil {
+		return []interface{}{generation, nil}, nil
+	}
+
+	vector := make([]float32, len(embedding))
+	for i, v := range embedding {
+		vector[i] = float32(v)
+	}
+	cluster := nearestCentroid(centroids, vector)
+	if cluster < 0 {
+		return []interface{}{generation, nil}, nil
+	}
+	return []interface{}{generation, cluster}, nil
+}
+
+// probedClusters returns the clusters searched for embedding, or nil to
+// search all memories.
+func (c *Client) probedClusters(ctx context.Context, embedding []float64) ([]int, error) {
+	_, centroids, err := c.currentCentroids(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.clusters.mu.RLock()
+	probes := c.clusters.probes
+	c.clusters.mu.RUnlock()
+	if probes <= 0 {
+		probes = (len(centroids) + 9) / 10
+	}
+	if centroids == nil || probes >= len(centroids) || len(embedding) != len(centroids[0]) {
+		return nil, nil
+	}
+
+	query := make([]float32, len(embedding))
+	for i, v := range embedding {
+		query[i] = float32(v)
+	}
+	clusters := make([]int, len(centroids))
+	similarities := make([]float32, len(centroids))
+	for i, centroid := range centroids {
+		clusters[i] = i
+		similarities[i] = dot32(query, centroid)
+	}
+	sort.SliceStable(clusters, func(i, j int) bool { return similarities[clusters[i]] > similarities[clusters[j]] })
+	clusters = clusters[:probes]
+	sort.Ints(clusters)
+	return clusters, nil
+}
+
+// setProbes sets the number of clusters searched.
+func (c *Client) setProbes(probes int) {
+	c.clusters.mu.Lock()
+	c.clusters.probes = probes
+	c.clusters.mu.Unlock()
+}
+
+// maybeTrainClusters counts added rows towards automatic training, and
+// starts training the centroids in the background once there are enough.
+func (c *Client) maybeTrainClusters(added int64) {
+	ci := &c.clusters
+	if ci.lists == 0 {
+		return
+	}
+	ci.mu.RLock()
+	trained := ci.centroids != nil
+	ci.mu.RUnlock()
+	if trained || ci.rows.Add(added) < int64(ci.lists*trainingRowsPerCluster) {
+		return
+	}
+	if !ci.training.TryLock() {
+		return
+	}
+
+	go func() {
+		defer ci.training.Unlock()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			select {
+			case <-c.stop:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		// Another client may have trained them
+		if _, centroids, err := c.currentCentroids(ctx); err == nil && centroids != nil {
+			return
+		}
+		if err := c.trainClusters(ctx, ci.lists); err != nil {
+			// Retried after as many rows again
+			ci.rows.Store(0)
+		}
+	}()
+}
+
+// trainClusters trains lists centroids on a sample of the embeddings,
+// replacing the current ones, and assigns every memory to its nearest
+// centroid. The caller holds c.clusters.training.
+func (c *Client) trainClusters(ctx context.Context, lists int) error {
+	query := fmt.Sprintf("SELECT id, embedding FROM %s ORDER BY RANDOM() LIMIT ?", c.collectionName)
+	rows, err := c.db.QueryContext(ctx, query, lists*trainingRowsPerCluster)
+	if err != nil {
+		return err
+	}
+	sample := new(scoreBatch)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id, sample); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		sample.ids = append(sample.ids, id)
+	}
+	err = rows.Err()
+	_ = rows.Close()
+	if err != nil {
+		return err
+	}
+
+	// The vectors of the most common dimension that are not zero
+	dims := make(map[int]int)
+	for i := range sample.ids {
+		dims[len(sample.vector(i))]++
+	}
+	dimension := 0
+	for d, n := range dims {
+		if n > dims[dimension] || (n == dims[dimension] && d > dimension) {
+			dimension = d
+		}
+	}
+	var vectors [][]float32
+	for i := range sample.ids {
+		if vector := sample.vector(i); len(vector) == dimension && normalize(vector) {
+			vectors = append(vectors, vector)
+		}
+	}
+	if len(vectors) < lists {
+		return fmt.Errorf("%d embeddings are not enough to train %d clusters", len(vectors), lists)
+	}
+
+	centroids := kmeans(vectors, lists, c.searchWorkers, rand.New(rand.NewSource(1)))
+	ids, assignments, err := c.assignClusters(ctx, centroids)
+	if err != nil {
+		return err
+	}
+	generation := time.Now().UnixNano()
+
+	tx, err := c.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", c.centroidsTable())); err != nil {
+		return err
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (id, generation, vector) VALUES (?, ?, ?)", c.centroidsTable())
+	for i, centroid := range centroids {
+		vector := make([]float64, len(centroid))
+		for j, v := range centroid {
+			vector[j] = float64(v)
+		}
+		if _, err := tx.ExecContext(ctx, insert, i, generation, encodeVector(vector)); err != nil {
+			return err
+		}
+	}
+
+	// Memories written since they were assigned stay unassigned
+	unassign := fmt.Sprintf("UPDATE %s SET cluster = NULL WHERE cluster IS NOT NULL", c.collectionName)
+	if _, err := tx.ExecContext(ctx, unassign); err != nil {
+		return err
+	}
+	update, err := tx.PrepareContext(ctx, fmt.Sprintf("UPDATE %s SET cluster = ? WHERE id = ?", c.collectionName))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = update.Close() }()
+	for i, id := range ids {
+		if _, err := update.ExecContext(ctx, assignments[i], id); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	c.clusters.mu.Lock()
+	c.clusters.generation, c.clusters.centroids = generation, centroids
+	c.clusters.mu.Unlock()
+	return nil
+}
+
+// assignClusters returns the IDs of the memories with an embedding of the
+// dimension of centroids, and their nearest centroids.
+func (c *Client) assignClusters(ctx context.Context, centroids [][]float32) ([]int64, []int, error) {
+	rows, err := c.db.QueryContext(ctx, fmt.Sprintf("SELECT id, embedding FROM %s", c.collectionName))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []int64
+	var assignments []int
+	batch := new(scoreBatch)
+	flush := func() {
+		nearest := make([]int, len(batch.ids))
+		parallelRange(len(batch.ids), c.searchWorkers, func(start, end int) {
+			for i := start; i < end; i++ {
+				nearest[i] = nearestCentroid(centroids, batch.vector(i))
+			}
+		})
+		for i, id := range batch.ids {
+			if nearest[i] >= 0 {
+				ids = append(ids, id)
+				assignments = append(assignments, nearest[i])
+			}
+		}
+		batch.reset()
+	}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id, batch); err != nil {
+			return nil, nil, err
+		}
+		batch.ids = append(batch.ids, id)
+		if len(batch.ids) == assignBatchSize {
+			flush()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	flush()
+	return ids, assignments, nil
+}
+
+// trainIndex trains the centroids for CreateIndex, waiting for automatic
+// training in progress.
+func (c *Client) trainIndex(ctx context.Context, lists, probes int) error {
+	if lists <= 0 {
+		lists = c.clusters.lists
+	}
+	if lists <= 0 {
+		return errors.New("the number of clusters (IVFParams.Nlist) is required")
+	}
+	c.clusters.training.Lock()
+	defer c.clusters.training.Unlock()
+	if err := c.trainClusters(ctx, lists); err != nil {
+		return err
+	}
+	if probes > 0 {
+		c.setProbes(probes)
+	}
+	return nil
+}
+
+// clusterCondition returns the condition restricting a query to clusters
+// and to unassigned memories.
+func clusterCondition(clusters []int) (string, []interface{}) {
+	placeholders := make([]string, len(clusters))
+	args := make([]interface{}, len(clusters))
+	for i, cluster := range clusters {
+		placeholders[i] = "?"
+		args[i] = cluster
+	}
+	return "(cluster IS NULL OR cluster IN (" + strings.Join(placeholders, ", ") + "))", args
+}
+
+// kmeans returns k centroids of the normalized vectors (at least k of them)
+// found by spherical k-means: the centroids are normalized means, and the
+// vectors are assigned by cosine similarity.
+func kmeans(vectors [][]float32, k, workers int, rng *rand.Rand) [][]float32 {
+	centroids := seedCentroids(vectors, k, workers, rng)
+
+	assignments := make([]int, len(vectors))
+	for i := range assignments {
+		assignments[i] = -1
+	}
+	for iteration := 0; iteration < kmeansIterations; iteration++ {
+		var changed int64
+		parallelRange(len(vectors), workers, func(start, end int) {
+			var n int64
+			for i := start; i < end; i++ {
+				if nearest := nearestCentroid(centroids, vectors[i]); nearest != assignments[i] {
+					assignments[i] = nearest
+					n++
+				}
+			}
+			atomic.AddInt64(&changed, n)
+		})
+		if changed == 0 {
+			break
+		}
+
+		sums := make([][]float32, k)
+		for j := range sums {
+			sums[j] = make([]float32, len(vectors[0]))
+		}
+		for i, vector := range vectors {
+			sum := sums[assignments[i]]
+			for d, v := range vector {
+				sum[d] += v
+			}
+		}
+		for j, sum := range sums {
+			if normalize(sum) {
+				centroids[j] = sum
+			} else {
+				// Empty cluster: restart it from a random vector
+				centroids[j] = append(centroids[j][:0], vectors[rng.Intn(len(vectors))]...)
+			}
+		}
+	}
+	return centroids
+}
+
+// seedCentroids picks k initial centroids among vectors with k-means++:
+// every centroid is drawn with a probability proportional to the squared
+// distance of the vectors to the nearest centroid already drawn.
+func seedCentroids(vectors [][]float32, k, workers int, rng *rand.Rand) [][]float32 {
+	centroids := make([][]float32, 0, k)
+	distances := make([]float64, len(vectors))
+	for i := range distances {
+		distances[i] = math.Inf(1)
+	}
+	next := rng.Intn(len(vectors))
+	for len(centroids) < k {
+		centroid := append([]float32(nil), vectors[next]...)
+		centroids = append(centroids, centroid)
+
+		parallelRange(len(vectors), workers, func(start, end int) {
+			for i := start; i < end; i++ {
+				// Cosine distance, squared
+				d := 1 - float64(dot32(vectors[i], centroid))
+				if d*d < distances[i] {
+					distances[i] = d * d
+				}
+			}
+		})
+		var total float64
+		for _, d := range distances {
+			total += d
+		}
+		if total <= 0 {
+			// Fewer distinct vectors than centroids
+			next = rng.Intn(len(vectors))
+			continue
+		}
+		target := rng.Float64() * total
+		for next = 0; next < len(vectors)-1; next++ {
+			if target -= distances[next]; target < 0 {
+				break
+			}
+		}
+	}
+	return centroids
+}
+
+// nearestCentroid returns the index of the centroid most similar to vector,
+// or -1 if their dimensions differ.
+func nearestCentroid(centroids [][]float32, vector []float32) int {
+	nearest := -1
+	var best float32
+	for i, centroid := range centroids {
+		if len(centroid) != len(vector) {
+			return -1
+		}
+		if similarity := dot32(vector, centroid); nearest < 0 || similarity > best {
+			nearest, best = i, similarity
+		}
+	}
+	return nearest
+}
+
+// normalize scales vector to unit norm, and reports whether it is not zero.
+func normalize(vector []float32) bool {
+	norm := math.Sqrt(float64(dot32(vector, vector)))
+	if norm == 0 {
+		return false
+	}
+	for i := range vector {
+		vector[i] = float32(float64(vector[i]) / norm)
+	}
+	return true
+}
+
+// parallelRange calls fn on consecutive ranges of [0, n) from up to workers
+// goroutines.
+func parallelRange(n, workers int, fn func(start, end int)) {
+	if workers <= 1 || n < 2*workers {
+		fn(0, n)
+		return
+	}
+	size := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			fn(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+// ListCollections returns the names of the collections of the database,
+// sorted: the tables that have a change log table.
+func (c *Client) ListCollections(ctx context.Context) ([]string, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT name FROM sqlite_master
+		WHERE type = 'table'
+		  AND name || '_changes' IN (SELECT name FROM sqlite_master WHERE type = 'table')
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("ListCollections: %w", err)
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("ListCollections: %w", err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListCollections: %w", err)
+	}
+	return names, nil
+}
+
+// DropCollection drops the memory table, the change log table, the team
+// membership table, the centroids table and the vector log tables of a
+// collection in a single transaction.
+func (c *Client) DropCollection(ctx context.Context, name string) error {
+	tx, err := c.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("DropCollection: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, table := range []string{name, name + "_changes", name + "_teams", name + "_centroids", name + "_vector_log", name + "_vector_epoch"} {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s", table)); err != nil {
+			return fmt.Errorf("DropCollection: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("DropCollection: %w", err)
+	}
+	return nil
+}
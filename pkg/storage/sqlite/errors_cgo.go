@@ -0,0 +1,40 @@
+//go:build cgo
+
+package sqlite
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// isBusy reports whether err is an SQLITE_BUSY or SQLITE_LOCKED error
+// ("database is locked").
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+}
+
+// isDuplicateID reports whether err is the error of an insert whose ID is
+// already taken.
+func isDuplicateID(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+}
+
+// isDuplicateUID reports whether err is the error of an insert whose UID is
+// already taken: a violation of the unique index of the uid column.
+func isDuplicateUID(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique && strings.HasSuffix(sqliteErr.Error(), ".uid")
+}
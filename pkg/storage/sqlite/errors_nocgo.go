@@ -0,0 +1,19 @@
+//go:build !cgo
+
+package sqlite
+
+// isBusy reports false: without cgo, go-sqlite3 has no driver and defines
+// no error codes, and opening a database fails.
+func isBusy(err error) bool {
+	return false
+}
+
+// isDuplicateID reports false, as isBusy does.
+func isDuplicateID(err error) bool {
+	return false
+}
+
+// isDuplicateUID reports false, as isBusy does.
+func isDuplicateUID(err error) bool {
+	return false
+}
@@ -0,0 +1,210 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Locking modes of Config.Locking.
+const (
+	// LockingNone relies on the locks of SQLite alone: concurrent writers
+	// wait for each other up to the busy timeout, then fail with
+	// SQLITE_BUSY.
+	LockingNone = "none"
+
+	// LockingAdvisory serializes the writes of all the clients opened with
+	// it on the same file, in this process and others, with an advisory
+	// lock on a lock file next to the database. Writers queue on the lock
+	// file instead of racing for the database lock.
+	LockingAdvisory = "advisory"
+
+	// LockingSingleWriter makes the client the only writer of the file: it
+	// holds the lock file for as long as it is open, and the writes of
+	// LockingAdvisory clients fail with ErrWriterLocked meanwhile.
+	LockingSingleWriter = "single_writer"
+)
+
+// ErrWriterLocked is returned when the lock file of the database is held by
+// a single writer (see LockingSingleWriter), or when a single writer cannot
+// take it.
+var ErrWriterLocked = errors.New("sqlite: database is locked by another writer")
+
+// lockPollInterval is the interval at which a blocked writer retries to take
+// the lock file.
+const lockPollInterval = 5 * time.Millisecond
+
+// writeLock is the lock file of a client using advisory locking.
+//
+// The lock file is locked exclusively by the writer holding it. Single
+// writers also store their process ID in it, which tells the other writers
+// to fail instead of waiting.
+type writeLock struct {
+	file *os.File
+
+	// singleWriter is set if the client holds the lock file until Close.
+	singleWriter bool
+
+	// sem serializes the writes of the client, since the lock file is
+	// locked per open file, not per goroutine.
+	sem chan struct{}
+}
+
+// openWriteLock opens the lock file of the database of cfg, or returns nil
+// for LockingNone. Single writers take it at once.
+func openWriteLock(cfg *Config) (*writeLock, error) {
+	switch cfg.Locking {
+	case "", LockingNone:
+		return nil, nil
+	case LockingAdvisory, LockingSingleWriter:
+	default:
+		return nil, fmt.Errorf("unknown locking mode %q", cfg.Locking)
+	}
+
+	path := cfg.DBPath + ".lock"
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	lock := &writeLock{
+		file:         file,
+		singleWriter: cfg.Locking == LockingSingleWriter,
+		sem:          make(chan struct{}, 1),
+	}
+	if !lock.singleWriter {
+		// Fail now rather than on the first write if a single writer is open
+		if err := lock.wait(context.Background()); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		if err := unlockFile(file); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		return lock, nil
+	}
+
+	if err := tryLockFile(file); err != nil {
+		holder := lock.holder()
+		_ = file.Close()
+		if !errors.Is(err, errLockHeld) {
+			return nil, err
+		}
+		if holder != "" {
+			return nil, fmt.Errorf("%w: %s is held by process %s", ErrWriterLocked, path, holder)
+		}
+		return nil, fmt.Errorf("%w: %s is held by a client writing to the database", ErrWriterLocked, path)
+	}
+	if err := file.Truncate(0); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if _, err := file.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return lock, nil
+}
+
+// holder returns the process ID stored in the lock file by its single
+// writer, or "" if there is none.
+func (l *writeLock) holder() string {
+	pid, err := io.ReadAll(io.NewSectionReader(l.file, 0, 32))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(pid))
+}
+
+// close releases the lock file, clearing the process ID of single writers.
+func (l *writeLock) close() error {
+	if l == nil {
+		return nil
+	}
+	if l.singleWriter {
+		_ = l.file.Truncate(0)
+	}
+	return l.file.Close()
+}
+
+// acquire takes the lock file for a write, waiting for the other writers
+// until ctx is done, and returns the function releasing it. It is a no-op
+// without advisory locking and for single writers, which hold it already.
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.lock == nil || c.lock.singleWriter {
+		return func() {}, nil
+	}
+
+	select {
+	case c.lock.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if err := c.lock.wait(ctx); err != nil {
+		<-c.lock.sem
+		return nil, err
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			_ = unlockFile(c.lock.file)
+			<-c.lock.sem
+		})
+	}, nil
+}
+
+// wait takes the lock file, polling until ctx is done. It fails with
+// ErrWriterLocked if a single writer holds it.
+func (l *writeLock) wait(ctx context.Context) error {
+	for {
+		err := tryLockFile(l.file)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errLockHeld) {
+			return err
+		}
+		if holder := l.holder(); holder != "" {
+			return fmt.Errorf("%w: %s is held by process %s", ErrWriterLocked, l.file.Name(), holder)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+
+	// A process ID left by a single writer that did not close cleanly is
+	// stale once the lock is free
+	if l.holder() != "" {
+		_ = l.file.Truncate(0)
+	}
+	return nil
+}
+
+// writeTx is a write transaction holding the lock file until it is
+// committed or rolled back.
+type writeTx struct {
+	*sql.Tx
+	release func()
+}
+
+// Commit commits the transaction and releases the lock file.
+func (tx *writeTx) Commit() error {
+	defer tx.release()
+	return tx.Tx.Commit()
+}
+
+// Rollback rolls the transaction back and releases the lock file.
+func (tx *writeTx) Rollback() error {
+	defer tx.release()
+	return tx.Tx.Rollback()
+}
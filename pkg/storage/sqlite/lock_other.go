@@ -0,0 +1,22 @@
+//go:build !unix
+
+package sqlite
+
+import (
+	"errors"
+	"os"
+)
+
+// errLockHeld is returned by tryLockFile when another open file holds the
+// lock.
+var errLockHeld = errors.New("lock file is held")
+
+// tryLockFile fails: advisory locking is only available on Unix systems.
+func tryLockFile(file *os.File) error {
+	return errors.New("advisory locking is not supported on this platform")
+}
+
+// unlockFile releases the lock of file.
+func unlockFile(file *os.File) error {
+	return nil
+}
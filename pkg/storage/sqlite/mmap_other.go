@@ -0,0 +1,19 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package sqlite
+
+import (
+	"errors"
+	"os"
+)
+
+// mapFile fails: the vector cache is only available on Linux, macOS and
+// BSD systems.
+func mapFile(file *os.File, size int) ([]byte, error) {
+	return nil, errors.New("the vector cache is not supported on this platform")
+}
+
+// unmapFile releases a mapping of mapFile.
+func unmapFile(data []byte) error {
+	return nil
+}
@@ -0,0 +1,19 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package sqlite
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps the first size bytes of file in memory, read-only and shared,
+// so that the writes to the file are visible in the mapping.
+func mapFile(file *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(file.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+// unmapFile releases a mapping of mapFile.
+func unmapFile(data []byte) error {
+	return syscall.Munmap(data)
+}
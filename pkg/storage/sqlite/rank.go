@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// loadBatchSize is the maximum number of IDs loaded by one query of
+// loadScored, below the SQLite limit on host parameters.
+const loadBatchSize = 500
+
+// scoredID is the similarity score of a memory, before it is loaded.
+type scoredID struct {
+	id    int64
+	score float64
+}
+
+// ranksBefore reports whether a comes before b in search order (score
+// descending, then ID ascending).
+func (a scoredID) ranksBefore(b scoredID) bool {
+	return a.score > b.score || (a.score == b.score && a.id < b.id)
+}
+
+// rankedAfter reports whether scored comes after cursor in search order. A
+// nil cursor matches everything.
+func rankedAfter(scored scoredID, cursor *storage.SearchCursor) bool {
+	if cursor == nil {
+		return true
+	}
+	return scoredID{id: cursor.ID, score: cursor.Score}.ranksBefore(scored)
+}
+
+// topScores keeps the limit best scores added to it, or all of them if
+// limit is not positive. Its heap holds the worst kept score at the root.
+type topScores struct {
+	limit  int
+	scores []scoredID
+}
+
+func (t *topScores) Len() int           { return len(t.scores) }
+func (t *topScores) Less(i, j int) bool { return t.scores[j].ranksBefore(t.scores[i]) }
+func (t *topScores) Swap(i, j int)      { t.scores[i], t.scores[j] = t.scores[j], t.scores[i] }
+func (t *topScores) Push(x interface{}) { t.scores = append(t.scores, x.(scoredID)) }
+func (t *topScores) Pop() interface{} {
+	last := t.scores[len(t.scores)-1]
+	t.scores = t.scores[:len(t.scores)-1]
+	return last
+}
+
+// add adds a score, dropping the worst one kept if the limit is exceeded.
+func (t *topScores) add(scored scoredID) {
+	if t.limit <= 0 {
+		t.scores = append(t.scores, scored)
+		return
+	}
+	if len(t.scores) < t.limit {
+		heap.Push(t, scored)
+		return
+	}
+	if scored.ranksBefore(t.scores[0]) {
+		t.scores[0] = scored
+		heap.Fix(t, 0)
+	}
+}
+
+// sorted returns the kept scores in search order.
+func (t *topScores) sorted() []scoredID {
+	sort.Slice(t.scores, func(i, j int) bool { return t.scores[i].ranksBefore(t.scores[j]) })
+	return t.scores
+}
+
+// loadScored loads the columns of the memories of scores, in their order and
+// with their scores. Memories deleted since they were scored are skipped.
+func (c *Client) loadScored(ctx context.Context, scores []scoredID, columns string) ([]*storage.Memory, error) {
+	loaded := make(map[int64]*storage.Memory, len(scores))
+	for start := 0; start < len(scores); start += loadBatchSize {
+		batch := scores[start:]
+		if len(batch) > loadBatchSize {
+			batch = batch[:loadBatchSize]
+		}
+		placeholders := make([]string, len(batch))
+		args := make([]interface{}, len(batch))
+		for i, scored := range batch {
+			placeholders[i] = "?"
+			args[i] = scored.id
+		}
+
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE id IN (%s)",
+			columns, c.collectionName, strings.Join(placeholders, ", "))
+		rows, err := c.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			memory, err := c.scanMemory(rows)
+			if err != nil {
+				_ = rows.Close()
+				return nil, err
+			}
+			loaded[memory.ID] = memory
+		}
+		err = rows.Err()
+		_ = rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	memories := make([]*storage.Memory, 0, len(scores))
+	for _, scored := range scores {
+		if memory, ok := loaded[scored.id]; ok {
+			memory.Score = scored.score
+			memories = append(memories, memory)
+		}
+	}
+	return memories, nil
+}
@@ -0,0 +1,311 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// Search scores rows in batches: the scanning goroutine decodes the
+// embeddings of scoreBatchSize rows into a batch, which a pool of workers
+// scores with float32 math while the next rows are scanned. Searches over
+// fewer rows than a batch are scored by the scanning goroutine alone.
+
+// scoreBatchSize is the number of rows handed to a scoring worker at once.
+const scoreBatchSize = 256
+
+// scoreBatch holds the IDs and embeddings of scanned rows. The embeddings
+// are stored back to back in values, the one of row i ending at ends[i].
+type scoreBatch struct {
+	ids    []int64
+	ends   []int
+	values []float32
+}
+
+// batchPool holds the batches of searches, whose buffers are reused from
+// search to search.
+var batchPool = sync.Pool{
+	New: func() interface{} { return new(scoreBatch) },
+}
+
+// reset empties the batch, keeping its buffers.
+func (b *scoreBatch) reset() {
+	b.ids = b.ids[:0]
+	b.ends = b.ends[:0]
+	b.values = b.values[:0]
+}
+
+// vector returns the embedding of row i.
+func (b *scoreBatch) vector(i int) []float32 {
+	start := 0
+	if i > 0 {
+		start = b.ends[i-1]
+	}
+	return b.values[start:b.ends[i]]
+}
+
+// Scan implements sql.Scanner, appending the embedding of a row to the
+// batch; the caller appends its ID.
+func (b *scoreBatch) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		if len(src)%8 != 0 {
+			return fmt.Errorf("parse embedding: embedding of %d bytes is not a float64 vector", len(src))
+		}
+		for i := 0; i < len(src); i += 8 {
+			b.values = append(b.values, float32(math.Float64frombits(binary.LittleEndian.Uint64(src[i:]))))
+		}
+	case string:
+		// JSON array written by an earlier version
+		var vector []float64
+		if err := json.Unmarshal([]byte(src), &vector); err != nil {
+			return fmt.Errorf("parse embedding: %w", err)
+		}
+		for _, v := range vector {
+			b.values = append(b.values, float32(v))
+		}
+	default:
+		return fmt.Errorf("parse embedding: unexpected embedding value of type %T", src)
+	}
+	b.ends = append(b.ends, len(b.values))
+	return nil
+}
+
+// scorer scores the batches of a search against its query.
+type scorer struct {
+	query     []float32
+	queryNorm float64
+	minScore  float64
+	after     *storage.SearchCursor
+}
+
+// newScorer returns a scorer of the rows meeting minScore and ranked after
+// the cursor after (if not nil).
+func newScorer(query []float64, minScore float64, after *storage.SearchCursor) *scorer {
+	s := &scorer{query: make([]float32, len(query)), minScore: minScore, after: after}
+	for i, v := range query {
+		s.query[i] = float32(v)
+	}
+	s.queryNorm = math.Sqrt(float64(dot32(s.query, s.query)))
+	return s
+}
+
+// score adds the rows of batch that pass the filters to top, and returns
+// their number.
+func (s *scorer) score(batch *scoreBatch, top *topScores) int64 {
+	var passed int64
+	for i, id := range batch.ids {
+		scored := scoredID{id: id, score: s.similarity(batch.vector(i))}
+		if scored.score < s.minScore || !rankedAfter(scored, s.after) {
+			continue
+		}
+		passed++
+		top.add(scored)
+	}
+	return passed
+}
+
+// similarity returns the cosine similarity of the query and vector, 0 if
+// their dimensions differ or either is zero.
+func (s *scorer) similarity(vector []float32) float64 {
+	if len(vector) != len(s.query) || s.queryNorm == 0 {
+		return 0
+	}
+	dot, norm := dotNorm32(s.query, vector)
+	if norm == 0 {
+		return 0
+	}
+	return float64(dot) / (s.queryNorm * math.Sqrt(float64(norm)))
+}
+
+// dot32 returns the dot product of a and b, which have the same length.
+func dot32(a, b []float32) float32 {
+	dot, _ := dotNorm32(a, b)
+	return dot
+}
+
+// dotNorm32 returns the dot product of a and b, which have the same length,
+// and the squared norm of b. The loop is unrolled over four independent
+// accumulators, which the compiler keeps in registers.
+func dotNorm32(a, b []float32) (dot, norm float32) {
+	var d0, d1, d2, d3, n0, n1, n2, n3 float32
+	i := 0
+	for ; i+4 <= len(a); i += 4 {
+		a4, b4 := a[i:i+4:i+4], b[i:i+4:i+4]
+		d0 += a4[0] * b4[0]
+		d1 += a4[1] * b4[1]
+		d2 += a4[2] * b4[2]
+		d3 += a4[3] * b4[3]
+		n0 += b4[0] * b4[0]
+		n1 += b4[1] * b4[1]
+		n2 += b4[2] * b4[2]
+		n3 += b4[3] * b4[3]
+	}
+	for ; i < len(a); i++ {
+		d0 += a[i] * b[i]
+		n0 += b[i] * b[i]
+	}
+	return (d0 + d1) + (d2 + d3), (n0 + n1) + (n2 + n3)
+}
+
+// scorePool scores the batches of a search on a number of workers, each
+// keeping its own top scores, which are merged by wait.
+type scorePool struct {
+	batches chan *scoreBatch
+	wg      sync.WaitGroup
+	tops    []*topScores
+	passed  []int64
+}
+
+// newScorePool starts workers scoring with s and keeping limit scores each.
+func newScorePool(s *scorer, limit, workers int) *scorePool {
+	p := &scorePool{
+		batches: make(chan *scoreBatch, workers),
+		tops:    make([]*topScores, workers),
+		passed:  make([]int64, workers),
+	}
+	for w := 0; w < workers; w++ {
+		p.tops[w] = &topScores{limit: limit}
+		p.wg.Add(1)
+		go func(w int) {
+			defer p.wg.Done()
+			for batch := range p.batches {
+				p.passed[w] += s.score(batch, p.tops[w])
+				batch.reset()
+				batchPool.Put(batch)
+			}
+		}(w)
+	}
+	return p
+}
+
+// submit hands a batch to the workers, which return it to batchPool.
+func (p *scorePool) submit(batch *scoreBatch) {
+	p.batches <- batch
+}
+
+// wait stops the workers once they have scored the submitted batches, adds
+// their top scores to top, and returns the number of rows that passed the
+// filters.
+func (p *scorePool) wait(top *topScores) int64 {
+	close(p.batches)
+	p.wg.Wait()
+
+	var passed int64
+	for w, workerTop := range p.tops {
+		for _, scored := range workerTop.scores {
+			top.add(scored)
+		}
+		passed += p.passed[w]
+	}
+	return passed
+}
+
+// rowScorer feeds the rows of a search to a scorer: rows are appended to
+// its current batch, which is scored in the calling goroutine or handed to
+// a scorePool once full.
+type rowScorer struct {
+	scorer  *scorer
+	workers int
+	top     *topScores
+	pool    *scorePool
+	batch   *scoreBatch
+
+	// candidates and passed count the rows, and those passing the filters
+	// of the scorer.
+	candidates int64
+	passed     int64
+}
+
+// newRowScorer returns a rowScorer keeping the limit best rows, scoring
+// full batches on up to workers goroutines. Its finish method must be
+// called.
+func newRowScorer(s *scorer, limit, workers int) *rowScorer {
+	return &rowScorer{
+		scorer:  s,
+		workers: workers,
+		top:     &topScores{limit: limit},
+		batch:   batchPool.Get().(*scoreBatch),
+	}
+}
+
+// added counts the row whose ID and embedding were appended to r.batch.
+func (r *rowScorer) added() {
+	r.candidates++
+	if len(r.batch.ids) < scoreBatchSize {
+		return
+	}
+	if r.workers <= 1 {
+		r.passed += r.scorer.score(r.batch, r.top)
+		r.batch.reset()
+		return
+	}
+	if r.pool == nil {
+		r.pool = newScorePool(r.scorer, r.top.limit, r.workers)
+	}
+	r.pool.submit(r.batch)
+	r.batch = batchPool.Get().(*scoreBatch)
+}
+
+// scan appends the rows of a query selecting id and embedding.
+func (r *rowScorer) scan(rows *sql.Rows) error {
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id, r.batch); err != nil {
+			return err
+		}
+		r.batch.ids = append(r.batch.ids, id)
+		r.added()
+	}
+	return rows.Err()
+}
+
+// finish scores the remaining rows, stops the workers and returns the best
+// rows. The scorer must not be used afterwards.
+func (r *rowScorer) finish() *topScores {
+	if r.pool != nil {
+		r.passed += r.pool.wait(r.top)
+	}
+	r.passed += r.scorer.score(r.batch, r.top)
+	r.batch.reset()
+	batchPool.Put(r.batch)
+	r.batch = nil
+	return r.top
+}
+
+// scoreRows scores the memories matching whereClause, and returns the limit
+// best of those passing the filters of s, the number of memories scored
+// and the number of those passing the filters. The embeddings are read from
+// the vector cache if the client has one.
+func (c *Client) scoreRows(ctx context.Context, whereClause string, args []interface{}, s *scorer, limit int) (*topScores, int64, int64, error) {
+	r := newRowScorer(s, limit, c.searchWorkers)
+	var err error
+	if c.vectors != nil {
+		err = c.scanCached(ctx, whereClause, args, r)
+	} else {
+		query := fmt.Sprintf("SELECT id, embedding FROM %s %s", c.collectionName, whereClause)
+		err = c.scanRows(ctx, r, query, args...)
+	}
+	// The workers are stopped even if the scan failed
+	top := r.finish()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return top, r.candidates, r.passed, nil
+}
+
+// scanRows appends the rows of a query selecting id and embedding to r.
+func (c *Client) scanRows(ctx context.Context, r *rowScorer, query string, args ...interface{}) error {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	return r.scan(rows)
+}
@@ -0,0 +1,148 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Snapshot writes a copy of the database file to a new file at path with
+// VACUUM INTO, which reads the database in a single transaction: the copy
+// includes the changes still in the WAL, and writers are not blocked. The
+// copy holds every collection of the database; RestoreSnapshot restores
+// the memories and the centroids of this one.
+func (c *Client) Snapshot(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("Snapshot: %s: %w", path, os.ErrExist)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("Snapshot: %w", err)
+	}
+
+	err := c.retryBusy(ctx, func() error {
+		_, err := c.db.ExecContext(ctx, "VACUUM INTO ?", path)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Snapshot: %w", err)
+	}
+	return nil
+}
+
+// RestoreSnapshot replaces the memories and the centroids of the collection
+// with those of the snapshot at path in a single transaction. The change
+// log and the audit log are kept. The vector log triggers record the
+// restored rows, so that vector caches catch up, and clients reload the
+// centroids by their generation.
+//
+// Columns the snapshot does not have, such as those added by later
+// versions, get their default values.
+func (c *Client) RestoreSnapshot(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("RestoreSnapshot: %w", err)
+	}
+
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("RestoreSnapshot: %w", err)
+	}
+	defer release()
+
+	// ATTACH applies to a connection, so the restore keeps one
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("RestoreSnapshot: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS snapshot", path); err != nil {
+		return fmt.Errorf("RestoreSnapshot: %w", err)
+	}
+	defer func() { _, _ = conn.ExecContext(context.Background(), "DETACH DATABASE snapshot") }()
+
+	var tables []string
+	for _, table := range []string{c.collectionName, c.centroidsTable()} {
+		var found int
+		query := "SELECT COUNT(*) FROM snapshot.sqlite_master WHERE type = 'table' AND name = ?"
+		if err := conn.QueryRowContext(ctx, query, table).Scan(&found); err != nil {
+			return fmt.Errorf("RestoreSnapshot: %w", err)
+		}
+		if found > 0 {
+			tables = append(tables, table)
+		} else if table == c.collectionName {
+			return fmt.Errorf("RestoreSnapshot: snapshot has no collection %s", table)
+		}
+	}
+
+	err = c.retryBusy(ctx, func() error {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		for _, table := range []string{c.collectionName, c.centroidsTable()} {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM main.%s", table)); err != nil {
+				return err
+			}
+		}
+		for _, table := range tables {
+			columns, err := sharedColumns(ctx, tx, table)
+			if err != nil {
+				return err
+			}
+			query := fmt.Sprintf("INSERT INTO main.%s (%s) SELECT %s FROM snapshot.%s",
+				table, columns, columns, table)
+			if _, err := tx.ExecContext(ctx, query); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return fmt.Errorf("RestoreSnapshot: %w", err)
+	}
+	return nil
+}
+
+// sharedColumns returns the comma-separated columns of table in both the
+// main and the snapshot databases.
+func sharedColumns(ctx context.Context, tx *sql.Tx, table string) (string, error) {
+	names := func(schema string) ([]string, error) {
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT name FROM pragma_table_info('%s', '%s')", table, schema))
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		var names []string
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return nil, err
+			}
+			names = append(names, name)
+		}
+		return names, rows.Err()
+	}
+
+	current, err := names("main")
+	if err != nil {
+		return "", err
+	}
+	snapshot, err := names("snapshot")
+	if err != nil {
+		return "", err
+	}
+	inSnapshot := make(map[string]bool, len(snapshot))
+	for _, name := range snapshot {
+		inSnapshot[name] = true
+	}
+	var columns []string
+	for _, name := range current {
+		if inSnapshot[name] {
+			columns = append(columns, name)
+		}
+	}
+	return strings.Join(columns, ", "), nil
+}
@@ -1,22 +1,111 @@
 package sqlite
 
 import (
+	"encoding/json"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
 )
 
+// whereFilter describes the row filters applied by buildWhereClause.
+type whereFilter struct {
+	userID  string
+	agentID string
+
+	// teamIDs widens userID to the memories shared with these teams.
+	teamIDs []string
+
+	filters   map[string]interface{}
+	timeRange *storage.TimeRange
+	tags      []string
+
+	// entities are the entities every memory must be annotated with in
+	// metadata["entities"], compared case-insensitively.
+	entities []string
+
+	// parentID selects the chunks of this memory. If it is zero and
+	// excludeChunks is set, chunks are excluded.
+	parentID      int64
+	excludeChunks bool
+
+	// activeAt excludes memories that expired at or before this time.
+	// A zero value disables the expiration check.
+	activeAt time.Time
+
+	// clusters, if not nil, restricts memories to these clusters and to
+	// those assigned to none (see clusters.go).
+	clusters []int
+}
+
 // buildWhereClause builds a WHERE clause (fixed version).
-func buildWhereClause(userID, agentID string, filters map[string]interface{}) (string, []interface{}) {
+func buildWhereClause(f whereFilter) (string, []interface{}) {
 	conditions := []string{}
 	args := []interface{}{}
 
-	if userID != "" {
+	if f.userID != "" && len(f.teamIDs) > 0 {
+		placeholders := make([]string, len(f.teamIDs))
+		args = append(args, f.userID, metadataPath(storage.TeamIDKey))
+		for i, teamID := range f.teamIDs {
+			placeholders[i] = "?"
+			args = append(args, teamID)
+		}
+		conditions = append(conditions, "(user_id = ? OR json_extract(metadata, ?) IN ("+strings.Join(placeholders, ", ")+"))")
+	} else if f.userID != "" {
 		conditions = append(conditions, "user_id = ?")
-		args = append(args, userID)
+		args = append(args, f.userID)
 	}
 
-	if agentID != "" {
+	if f.agentID != "" {
 		conditions = append(conditions, "agent_id = ?")
-		args = append(args, agentID)
+		args = append(args, f.agentID)
+	}
+
+	if f.parentID != 0 {
+		conditions = append(conditions, "parent_id = ?")
+		args = append(args, f.parentID)
+	} else if f.excludeChunks {
+		conditions = append(conditions, "parent_id IS NULL")
+	}
+
+	// Metadata must match every filter key/value pair
+	keys := make([]string, 0, len(f.filters))
+	for key := range f.filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		conditions = append(conditions, "json_extract(metadata, ?) = ?")
+		args = append(args, metadataPath(key), f.filters[key])
+	}
+
+	timeConditions, timeArgs := buildTimeRangeConditions(f.timeRange)
+	conditions = append(conditions, timeConditions...)
+	args = append(args, timeArgs...)
+
+	// Memories must carry every requested tag
+	for _, tag := range f.tags {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM json_each(tags) WHERE json_each.value = ?)")
+		args = append(args, tag)
+	}
+
+	// Memories must be annotated with every requested entity
+	for _, entity := range f.entities {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM json_each(metadata, '$.entities') WHERE lower(json_each.value) = lower(?))")
+		args = append(args, entity)
+	}
+
+	if f.clusters != nil {
+		condition, clusterArgs := clusterCondition(f.clusters)
+		conditions = append(conditions, condition)
+		args = append(args, clusterArgs...)
+	}
+
+	// Exclude expired memories
+	if !f.activeAt.IsZero() {
+		conditions = append(conditions, "(expires_at IS NULL OR julianday(expires_at) > julianday(?))")
+		args = append(args, f.activeAt)
 	}
 
 	if len(conditions) == 0 {
@@ -25,3 +114,81 @@ func buildWhereClause(userID, agentID string, filters map[string]interface{}) (s
 
 	return "WHERE " + strings.Join(conditions, " AND "), args
 }
+
+// nullableUID returns uid for the uid column, or nil for memories without a
+// string ID so that the unique index ignores them.
+func nullableUID(uid string) interface{} {
+	if uid == "" {
+		return nil
+	}
+	return uid
+}
+
+// nullableParentID returns parentID for the parent_id column, or nil for
+// memories that are not chunks.
+func nullableParentID(parentID int64) interface{} {
+	if parentID == 0 {
+		return nil
+	}
+	return parentID
+}
+
+// metadataPath returns the JSON path selecting a top-level metadata key.
+//
+// The key is quoted so that dots and other special characters are matched
+// literally instead of being interpreted as path syntax.
+func metadataPath(key string) string {
+	return `$."` + strings.ReplaceAll(key, `"`, `\"`) + `"`
+}
+
+// escapeLikePattern escapes LIKE wildcards so text is matched literally.
+func escapeLikePattern(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(text)
+}
+
+// buildTimeRangeConditions builds conditions for created_at/updated_at bounds.
+//
+// Timestamps are compared through julianday() so that values written with
+// different timezone offsets (time.Now() vs CURRENT_TIMESTAMP) compare correctly.
+func buildTimeRangeConditions(timeRange *storage.TimeRange) ([]string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if timeRange.IsZero() {
+		return conditions, args
+	}
+
+	if !timeRange.CreatedAfter.IsZero() {
+		conditions = append(conditions, "julianday(created_at) >= julianday(?)")
+		args = append(args, timeRange.CreatedAfter)
+	}
+	if !timeRange.CreatedBefore.IsZero() {
+		conditions = append(conditions, "julianday(created_at) < julianday(?)")
+		args = append(args, timeRange.CreatedBefore)
+	}
+	if !timeRange.UpdatedAfter.IsZero() {
+		conditions = append(conditions, "julianday(updated_at) >= julianday(?)")
+		args = append(args, timeRange.UpdatedAfter)
+	}
+	if !timeRange.UpdatedBefore.IsZero() {
+		conditions = append(conditions, "julianday(updated_at) < julianday(?)")
+		args = append(args, timeRange.UpdatedBefore)
+	}
+
+	return conditions, args
+}
+
+// marshalTags encodes tags as a JSON array for the TEXT tags column.
+//
+// Nil tags are stored as NULL.
+func marshalTags(tags []string) (interface{}, error) {
+	if tags == nil {
+		return nil, nil
+	}
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return nil, err
+	}
+	return string(tagsJSON), nil
+}
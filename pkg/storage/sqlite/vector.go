@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Embeddings are stored as BLOBs of little-endian float64 values, which are
+// decoded without allocating beyond the vector itself. Rows written by
+// earlier versions hold JSON arrays in TEXT values, and are still read.
+
+// encodeVector returns the BLOB encoding of vector.
+func encodeVector(vector []float64) []byte {
+	buf := make([]byte, 8*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint64(buf[8*i:], math.Float64bits(v))
+	}
+	return buf
+}
+
+// decodeVector decodes an embedding column value into dst, reusing its
+// capacity, and returns the vector.
+func decodeVector(dst []float64, src interface{}) ([]float64, error) {
+	switch src := src.(type) {
+	case []byte:
+		if len(src)%8 != 0 {
+			return nil, fmt.Errorf("embedding of %d bytes is not a float64 vector", len(src))
+		}
+		n := len(src) / 8
+		if cap(dst) < n {
+			dst = make([]float64, n)
+		}
+		dst = dst[:n]
+		for i := range dst {
+			dst[i] = math.Float64frombits(binary.LittleEndian.Uint64(src[8*i:]))
+		}
+		return dst, nil
+	case string:
+		// JSON array written by an earlier version
+		var vector []float64
+		if err := json.Unmarshal([]byte(src), &vector); err != nil {
+			return nil, err
+		}
+		return vector, nil
+	case nil:
+		// Not selected, see selectColumns
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unexpected embedding value of type %T", src)
+	}
+}
+
+// vectorScanner scans an embedding column into vector, reusing its
+// capacity.
+type vectorScanner struct {
+	vector []float64
+}
+
+// Scan implements sql.Scanner.
+func (s *vectorScanner) Scan(src interface{}) error {
+	vector, err := decodeVector(s.vector, src)
+	if err != nil {
+		return fmt.Errorf("parse embedding: %w", err)
+	}
+	s.vector = vector
+	return nil
+}
@@ -0,0 +1,555 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The vector cache keeps the embeddings of a collection as float32 vectors
+// in a flat file next to the database, mapped in memory, so that searches
+// read the IDs of the matching rows from SQLite (from its indexes when
+// possible) and their embeddings from the mapping, without reading or
+// decoding the embedding column. SQLite remains the source of truth:
+// triggers append the ID of every inserted, updated or deleted memory to
+// a log table, which the cache replays before each search, and the cache
+// is rebuilt from the memories table when the log does not cover its
+// state.
+//
+// The file starts with a vectorHeaderSize header (little-endian):
+//
+//	magic [8]byte "PMVCACHE"
+//	version uint32
+//	dims uint32
+//	epoch int64  epoch of the log the cache was built from
+//	seq int64    last log entry applied
+//	used int64   number of slots used
+//	clean uint32 1 if the file was closed after its last change
+//
+// followed by fixed-size slots of an int64 memory ID, a uint32 set to 1 if
+// the slot is live, 4 bytes of padding and dims float32 values. The file is
+// written with WriteAt and read through a shared read-only mapping, which
+// sees the writes. A file that was not closed cleanly is rebuilt.
+
+const (
+	vectorCacheMagic   = "PMVCACHE"
+	vectorCacheVersion = 1
+	vectorHeaderSize   = 64
+	vectorSlotHeader   = 16
+
+	// vectorCacheMinSlots is the initial number of slots of the file,
+	// which doubles whenever it is full.
+	vectorCacheMinSlots = 1024
+
+	// vectorLogSize is the number of entries kept in the vector log by the
+	// triggers. Caches that have not replayed them are rebuilt.
+	vectorLogSize = 100000
+)
+
+// vectorCache is the mapped vector file of a client.
+type vectorCache struct {
+	// mu is held for reading by searches reading the mapping, and for
+	// writing while the cache is synchronized.
+	mu sync.RWMutex
+
+	file *os.File
+	data []byte
+	dims int
+
+	epoch int64
+	seq   int64
+	used  int64
+
+	// slots maps memory IDs to their slot, free lists the slots of deleted
+	// memories.
+	slots map[int64]int64
+	free  []int64
+
+	// dirty is set once the file is marked as not clean.
+	dirty bool
+
+	buf []byte
+}
+
+// vectorLogTable returns the name of the table logging the changes of
+// embeddings.
+func (c *Client) vectorLogTable() string {
+	return c.collectionName + "_vector_log"
+}
+
+// vectorEpochTable returns the name of the table holding the epoch of the
+// vector log, which changes when the log is recreated.
+func (c *Client) vectorEpochTable() string {
+	return c.collectionName + "_vector_epoch"
+}
+
+// initVectorLog creates the vector log, its epoch and the triggers filling
+// it. The triggers keep the last vectorLogSize entries.
+func (c *Client) initVectorLog(ctx context.Context) error {
+	queries := []string{
+		fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				seq INTEGER PRIMARY KEY AUTOINCREMENT,
+				memory_id INTEGER NOT NULL
+			)
+		`, c.vectorLogTable()),
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (epoch INTEGER NOT NULL)", c.vectorEpochTable()),
+	}
+	for _, trigger := range []struct{ name, event, id string }{
+		{"insert", "INSERT", "NEW.id"},
+		{"update", "UPDATE OF embedding", "NEW.id"},
+		{"delete", "DELETE", "OLD.id"},
+	} {
+		queries = append(queries, fmt.Sprintf(`
+			CREATE TRIGGER IF NOT EXISTS %s_vector_%s AFTER %s ON %s
+			BEGIN
+				INSERT INTO %s (memory_id) VALUES (%s);
+				DELETE FROM %s WHERE seq <= (SELECT MAX(seq) FROM %s) - %d;
+			END
+		`, c.collectionName, trigger.name, trigger.event, c.collectionName,
+			c.vectorLogTable(), trigger.id, c.vectorLogTable(), c.vectorLogTable(), vectorLogSize))
+	}
+	for _, query := range queries {
+		if _, err := c.exec(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (epoch) SELECT ? WHERE NOT EXISTS (SELECT 1 FROM %s)",
+		c.vectorEpochTable(), c.vectorEpochTable())
+	_, err := c.exec(ctx, query, time.Now().UnixNano())
+	return err
+}
+
+// openVectorCache opens the vector file at path, locking it for the client.
+// It returns nil if another client holds it. A file that is new, was not
+// closed cleanly or holds vectors of other dimensions is rebuilt on the
+// first search.
+func openVectorCache(path string, dims int) (*vectorCache, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := tryLockFile(file); err != nil {
+		_ = file.Close()
+		if errors.Is(err, errLockHeld) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	v := &vectorCache{file: file, dims: dims, seq: -1, slots: make(map[int64]int64)}
+	if err := v.open(); err != nil {
+		_ = v.close()
+		return nil, err
+	}
+	return v, nil
+}
+
+// open maps the file, and loads the slots if the file is usable.
+func (v *vectorCache) open() error {
+	info, err := v.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, vectorHeaderSize)
+	usable := false
+	if info.Size() >= vectorHeaderSize {
+		if _, err := v.file.ReadAt(header, 0); err != nil {
+			return err
+		}
+		usable = string(header[:8]) == vectorCacheMagic &&
+			binary.LittleEndian.Uint32(header[8:]) == vectorCacheVersion &&
+			binary.LittleEndian.Uint32(header[12:]) == uint32(v.dims) &&
+			binary.LittleEndian.Uint32(header[40:]) == 1
+	}
+	if usable {
+		v.epoch = int64(binary.LittleEndian.Uint64(header[16:]))
+		v.seq = int64(binary.LittleEndian.Uint64(header[24:]))
+		v.used = int64(binary.LittleEndian.Uint64(header[32:]))
+		usable = info.Size() >= vectorHeaderSize+v.used*v.slotSize()
+	}
+	if !usable {
+		v.epoch, v.seq, v.used = 0, -1, 0
+		return v.resize(vectorCacheMinSlots)
+	}
+
+	if err := v.remap(int(info.Size())); err != nil {
+		return err
+	}
+	for slot := int64(0); slot < v.used; slot++ {
+		offset := v.slotOffset(slot)
+		id := int64(binary.LittleEndian.Uint64(v.data[offset:]))
+		if binary.LittleEndian.Uint32(v.data[offset+8:]) == 1 {
+			v.slots[id] = slot
+		} else {
+			v.free = append(v.free, slot)
+		}
+	}
+	return nil
+}
+
+// slotSize returns the size of a slot in bytes.
+func (v *vectorCache) slotSize() int64 {
+	return vectorSlotHeader + 4*int64(v.dims)
+}
+
+// slotOffset returns the offset of a slot in the file.
+func (v *vectorCache) slotOffset(slot int64) int64 {
+	return vectorHeaderSize + slot*v.slotSize()
+}
+
+// capacity returns the number of slots of the mapping.
+func (v *vectorCache) capacity() int64 {
+	return (int64(len(v.data)) - vectorHeaderSize) / v.slotSize()
+}
+
+// resize sets the file to hold slots slots and maps it again.
+func (v *vectorCache) resize(slots int64) error {
+	size := vectorHeaderSize + slots*v.slotSize()
+	if err := v.file.Truncate(size); err != nil {
+		return err
+	}
+	return v.remap(int(size))
+}
+
+// remap replaces the mapping by one of the first size bytes of the file.
+func (v *vectorCache) remap(size int) error {
+	if v.data != nil {
+		if err := unmapFile(v.data); err != nil {
+			return err
+		}
+		v.data = nil
+	}
+	data, err := mapFile(v.file, size)
+	if err != nil {
+		return err
+	}
+	v.data = data
+	return nil
+}
+
+// writeHeader writes the header of the file.
+func (v *vectorCache) writeHeader(clean bool) error {
+	header := make([]byte, vectorHeaderSize)
+	copy(header, vectorCacheMagic)
+	binary.LittleEndian.PutUint32(header[8:], vectorCacheVersion)
+	binary.LittleEndian.PutUint32(header[12:], uint32(v.dims))
+	binary.LittleEndian.PutUint64(header[16:], uint64(v.epoch))
+	binary.LittleEndian.PutUint64(header[24:], uint64(v.seq))
+	binary.LittleEndian.PutUint64(header[32:], uint64(v.used))
+	if clean {
+		binary.LittleEndian.PutUint32(header[40:], 1)
+	}
+	_, err := v.file.WriteAt(header, 0)
+	return err
+}
+
+// markDirty marks the file as not clean before its first change, so that
+// it is rebuilt if the client does not close it.
+func (v *vectorCache) markDirty() error {
+	if v.dirty {
+		return nil
+	}
+	if err := v.writeHeader(false); err != nil {
+		return err
+	}
+	if err := v.file.Sync(); err != nil {
+		return err
+	}
+	v.dirty = true
+	return nil
+}
+
+// clear removes every vector.
+func (v *vectorCache) clear() {
+	v.used = 0
+	v.slots = make(map[int64]int64)
+	v.free = v.free[:0]
+}
+
+// put stores the vector of a memory. Vectors of other dimensions than the
+// cache's are not stored, and are read from SQLite by searches.
+func (v *vectorCache) put(id int64, vector []float32) error {
+	if len(vector) != v.dims {
+		return nil
+	}
+	slot, ok := v.slots[id]
+	if !ok {
+		if n := len(v.free); n > 0 {
+			slot, v.free = v.free[n-1], v.free[:n-1]
+		} else {
+			if v.used == v.capacity() {
+				if err := v.resize(2 * v.capacity()); err != nil {
+					return err
+				}
+			}
+			slot = v.used
+			v.used++
+		}
+		v.slots[id] = slot
+	}
+
+	if size := int(v.slotSize()); cap(v.buf) < size {
+		v.buf = make([]byte, size)
+	}
+	buf := v.buf[:v.slotSize()]
+	binary.LittleEndian.PutUint64(buf, uint64(id))
+	binary.LittleEndian.PutUint32(buf[8:], 1)
+	binary.LittleEndian.PutUint32(buf[12:], 0)
+	for i, value := range vector {
+		binary.LittleEndian.PutUint32(buf[vectorSlotHeader+4*i:], math.Float32bits(value))
+	}
+	_, err := v.file.WriteAt(buf, v.slotOffset(slot))
+	return err
+}
+
+// remove removes the vector of a memory, if cached.
+func (v *vectorCache) remove(id int64) error {
+	slot, ok := v.slots[id]
+	if !ok {
+		return nil
+	}
+	var live [4]byte
+	if _, err := v.file.WriteAt(live[:], v.slotOffset(slot)+8); err != nil {
+		return err
+	}
+	delete(v.slots, id)
+	v.free = append(v.free, slot)
+	return nil
+}
+
+// appendVector appends the vector of a memory to batch, and reports whether
+// it is cached. The caller holds v.mu.
+func (v *vectorCache) appendVector(batch *scoreBatch, id int64) bool {
+	slot, ok := v.slots[id]
+	if !ok {
+		return false
+	}
+	values := v.data[v.slotOffset(slot)+vectorSlotHeader:]
+	for i := 0; i < v.dims; i++ {
+		batch.values = append(batch.values, math.Float32frombits(binary.LittleEndian.Uint32(values[4*i:])))
+	}
+	batch.ends = append(batch.ends, len(batch.values))
+	batch.ids = append(batch.ids, id)
+	return true
+}
+
+// close writes the file as clean if it was changed, and releases it.
+func (v *vectorCache) close() error {
+	if v == nil {
+		return nil
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	var err error
+	if v.dirty && v.seq >= 0 {
+		if err = v.file.Sync(); err == nil {
+			if err = v.writeHeader(true); err == nil {
+				err = v.file.Sync()
+			}
+		}
+	}
+	if v.data != nil {
+		if unmapErr := unmapFile(v.data); err == nil {
+			err = unmapErr
+		}
+		v.data = nil
+	}
+	_ = unlockFile(v.file)
+	if closeErr := v.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// vectorLogState returns the epoch and the sequence numbers of the first
+// and last entries of the vector log, creating it if another client
+// dropped it (see Reset).
+func (c *Client) vectorLogState(ctx context.Context) (epoch, first, last int64, err error) {
+	query := fmt.Sprintf(`
+		SELECT (SELECT epoch FROM %s),
+			COALESCE((SELECT MIN(seq) FROM %s), 0),
+			COALESCE((SELECT MAX(seq) FROM %s), 0)
+	`, c.vectorEpochTable(), c.vectorLogTable(), c.vectorLogTable())
+	err = c.db.QueryRowContext(ctx, query).Scan(&epoch, &first, &last)
+	if err != nil {
+		if initErr := c.initVectorLog(ctx); initErr != nil {
+			return 0, 0, 0, err
+		}
+		err = c.db.QueryRowContext(ctx, query).Scan(&epoch, &first, &last)
+	}
+	return epoch, first, last, err
+}
+
+// syncVectors brings the vector cache up to date with the memories table:
+// it replays the log entries written since its last synchronization, or
+// rebuilds the cache if the log does not have them.
+func (c *Client) syncVectors(ctx context.Context) error {
+	v := c.vectors
+	epoch, first, last, err := c.vectorLogState(ctx)
+	if err != nil {
+		return err
+	}
+
+	v.mu.RLock()
+	upToDate := v.epoch == epoch && v.seq == last
+	v.mu.RUnlock()
+	if upToDate {
+		return nil
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.epoch == epoch && v.seq == last {
+		return nil
+	}
+	if err := v.markDirty(); err != nil {
+		return err
+	}
+
+	// Entries written after last are replayed by the next synchronization,
+	// which reloads the embeddings of their memories again.
+	if v.epoch == epoch && v.seq >= 0 && v.seq >= first-1 && v.seq < last {
+		err = c.replayVectorLog(ctx, v.seq, last)
+	} else {
+		v.clear()
+		err = c.loadVectors(ctx, "")
+	}
+	if err != nil {
+		// Rebuild on the next search
+		v.seq = -1
+		return err
+	}
+	v.epoch, v.seq = epoch, last
+	return v.writeHeader(false)
+}
+
+// replayVectorLog reloads the vectors of the memories of the log entries
+// after seq up to last.
+func (c *Client) replayVectorLog(ctx context.Context, seq, last int64) error {
+	query := fmt.Sprintf("SELECT DISTINCT memory_id FROM %s WHERE seq > ? AND seq <= ?", c.vectorLogTable())
+	rows, err := c.db.QueryContext(ctx, query, seq, last)
+	if err != nil {
+		return err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(ids); start += loadBatchSize {
+		chunk := ids[start:]
+		if len(chunk) > loadBatchSize {
+			chunk = chunk[:loadBatchSize]
+		}
+		args := make([]interface{}, len(chunk))
+		for i, id := range chunk {
+			if err := c.vectors.remove(id); err != nil {
+				return err
+			}
+			args[i] = id
+		}
+		where := fmt.Sprintf("WHERE id IN (%s)", placeholders(len(chunk)))
+		if err := c.loadVectors(ctx, where, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadVectors stores the vectors of the memories matching whereClause in
+// the cache.
+func (c *Client) loadVectors(ctx context.Context, whereClause string, args ...interface{}) error {
+	query := fmt.Sprintf("SELECT id, embedding FROM %s %s", c.collectionName, whereClause)
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	batch := batchPool.Get().(*scoreBatch)
+	defer batchPool.Put(batch)
+	for rows.Next() {
+		batch.reset()
+		var id int64
+		if err := rows.Scan(&id, batch); err != nil {
+			return err
+		}
+		if err := c.vectors.put(id, batch.values); err != nil {
+			return err
+		}
+	}
+	batch.reset()
+	return rows.Err()
+}
+
+// scanCached appends the memories matching whereClause to r, with their
+// vectors read from the cache after synchronizing it. Only their IDs are
+// read from SQLite, except for the memories missing from the cache.
+func (c *Client) scanCached(ctx context.Context, whereClause string, args []interface{}, r *rowScorer) error {
+	if err := c.syncVectors(ctx); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("SELECT id FROM %s %s", c.collectionName, whereClause)
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var missing []interface{}
+	c.vectors.mu.RLock()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			c.vectors.mu.RUnlock()
+			return err
+		}
+		if c.vectors.appendVector(r.batch, id) {
+			r.added()
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	c.vectors.mu.RUnlock()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	_ = rows.Close()
+
+	for start := 0; start < len(missing); start += loadBatchSize {
+		chunk := missing[start:]
+		if len(chunk) > loadBatchSize {
+			chunk = chunk[:loadBatchSize]
+		}
+		query := fmt.Sprintf("SELECT id, embedding FROM %s WHERE id IN (%s)", c.collectionName, placeholders(len(chunk)))
+		if err := c.scanRows(ctx, r, query, chunk...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// placeholders returns a list of n query placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -71,6 +71,16 @@ type UserProfileStore interface {
 	// Returns a list of matching user profiles.
 	GetProfiles(ctx context.Context, opts *GetProfilesOptions) ([]*UserProfile, error)
 
+	// GetProfilesByUserIDs retrieves the profiles of several users in a single query.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation
+	//   - userIDs: User identifiers
+	//
+	// Returns the profiles found, in no particular order. Users without a
+	// profile are omitted.
+	GetProfilesByUserIDs(ctx context.Context, userIDs []string) ([]*UserProfile, error)
+
 	// DeleteProfile deletes a user profile by profile ID.
 	//
 	// Parameters:
@@ -80,12 +90,48 @@ type UserProfileStore interface {
 	// Returns an error if deletion fails.
 	DeleteProfile(ctx context.Context, profileID int64) error
 
+	// DeleteAllProfiles deletes all profiles matching filter in a single operation.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation
+	//   - filter: Profiles to delete (nil or empty UserIDs deletes all profiles)
+	//
+	// Returns the number of deleted profiles.
+	DeleteAllProfiles(ctx context.Context, filter *ProfileFilter) (int64, error)
+
+	// Snapshot writes a consistent copy of the profiles to a new file at
+	// path.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation
+	//   - path: File the snapshot is written to (must not exist)
+	//
+	// Returns an error if path exists or the snapshot fails.
+	Snapshot(ctx context.Context, path string) error
+
+	// RestoreSnapshot replaces the profiles with those of the snapshot at
+	// path in a single transaction.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation
+	//   - path: Snapshot written by Snapshot
+	//
+	// Returns an error if the restore fails.
+	RestoreSnapshot(ctx context.Context, path string) error
+
 	// Close closes the profile store and releases resources.
 	//
 	// Returns an error if closing fails.
 	Close() error
 }
 
+// ProfileFilter selects the profiles removed by DeleteAllProfiles.
+type ProfileFilter struct {
+	// UserIDs restricts deletion to the profiles of these users.
+	// If empty, all profiles are deleted.
+	UserIDs []string
+}
+
 // GetProfilesOptions contains options for querying user profiles.
 type GetProfilesOptions struct {
 	// UserID filters profiles by user ID.
@@ -14,14 +14,13 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/oceanbase/powermem-go/pkg/core"
 	"github.com/oceanbase/powermem-go/pkg/llm"
-	anthropicLLM "github.com/oceanbase/powermem-go/pkg/llm/anthropic"
-	deepseekLLM "github.com/oceanbase/powermem-go/pkg/llm/deepseek"
-	ollamaLLM "github.com/oceanbase/powermem-go/pkg/llm/ollama"
-	openaiLLM "github.com/oceanbase/powermem-go/pkg/llm/openai"
-	qwenLLM "github.com/oceanbase/powermem-go/pkg/llm/qwen"
+	"github.com/oceanbase/powermem-go/pkg/user_memory/oceanbase"
+	"github.com/oceanbase/powermem-go/pkg/user_memory/postgres"
 	"github.com/oceanbase/powermem-go/pkg/user_memory/query_rewrite"
 	"github.com/oceanbase/powermem-go/pkg/user_memory/sqlite"
 )
@@ -53,6 +52,9 @@ import (
 //	    usermemory.WithUserID("user_001"),
 //	)
 //	// Profile is automatically extracted and saved
+//
+// Set Config.AsyncProfileExtraction to extract profiles in a background worker
+// instead of on the request path.
 type Client struct {
 	// memory is the underlying core Memory client.
 	memory *core.Client
@@ -65,6 +67,27 @@ type Client struct {
 
 	// queryRewriter is the query rewriter for enhancing search queries (optional).
 	queryRewriter *query_rewrite.QueryRewriter
+
+	// profileWorker extracts profiles in the background (nil unless AsyncProfileExtraction is enabled).
+	profileWorker *profileWorker
+
+	// profileMergeMode controls how extracted profile information is merged.
+	profileMergeMode ProfileMergeMode
+
+	// config is the configuration the client was created or last reloaded with.
+	config *Config
+
+	// mu guards llm, queryRewriter, profileMergeMode and config, which Reload replaces.
+	mu sync.RWMutex
+
+	// reloadMu serializes Reload.
+	reloadMu sync.Mutex
+
+	// closeOnce makes sure the stores and providers are closed once.
+	closeOnce sync.Once
+
+	// closeErr is the result of closing the stores and providers.
+	closeErr error
 }
 
 // Config contains configuration for creating a UserMemory client.
@@ -78,13 +101,34 @@ type Config struct {
 	// ProfileStoreConfig is the configuration for the profile store.
 	// The type depends on ProfileStoreType:
 	//   - For "sqlite": *sqlite.Config
-	//   - For "oceanbase": *oceanbase.Config (future)
-	//   - For "postgres": *postgres.Config (future)
+	//   - For "oceanbase": *oceanbase.Config
+	//   - For "postgres": *postgres.Config
 	ProfileStoreConfig interface{}
 
 	// QueryRewriteConfig is the configuration for query rewriting (optional).
 	// If nil or Enabled is false, query rewriting is disabled.
 	QueryRewriteConfig *query_rewrite.Config
+
+	// AsyncProfileExtraction moves profile extraction off the request path.
+	// When true, Add and BatchAdd store memories immediately and extract/update
+	// the profile in a background worker. Default: false
+	AsyncProfileExtraction bool
+
+	// ProfileQueueSize is the number of pending background extractions.
+	// Add blocks while the queue is full. Default: 100
+	ProfileQueueSize int
+
+	// ProfileMaxRetries is how many times a failed background extraction is retried.
+	// Default: 3 (set a negative value to disable retries)
+	ProfileMaxRetries int
+
+	// ProfileRetryBackoff is the delay before the first retry; it doubles on each retry.
+	// Default: 500ms
+	ProfileRetryBackoff time.Duration
+
+	// ProfileMergeMode controls how extracted profile information is merged
+	// into the stored profile. Default: ProfileMergeRewrite
+	ProfileMergeMode ProfileMergeMode
 }
 
 // NewClient creates a new UserMemory client.
@@ -131,82 +175,104 @@ func NewClient(cfg *Config) (*Client, error) {
 		}
 		// Wrap with adapter
 		profileStore = &sqliteStoreAdapter{store: sqliteStore}
+	case "postgres":
+		postgresCfg, ok := cfg.ProfileStoreConfig.(*postgres.Config)
+		if !ok {
+			return nil, fmt.Errorf("invalid postgres config type")
+		}
+		postgresStore, err := postgres.NewStore(postgresCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create postgres profile store: %w", err)
+		}
+		profileStore = &postgresStoreAdapter{store: postgresStore}
+	case "oceanbase":
+		oceanbaseCfg, ok := cfg.ProfileStoreConfig.(*oceanbase.Config)
+		if !ok {
+			return nil, fmt.Errorf("invalid oceanbase config type")
+		}
+		oceanbaseStore, err := oceanbase.NewStore(oceanbaseCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create oceanbase profile store: %w", err)
+		}
+		profileStore = &oceanbaseStoreAdapter{store: oceanbaseStore}
 	default:
 		return nil, fmt.Errorf("unsupported profile store type: %s", cfg.ProfileStoreType)
 	}
 
 	// Create LLM from config (for profile extraction)
-	llmProvider, err := initLLMFromConfig(cfg.MemoryConfig.LLM)
+	llmProvider, err := initLLMFromConfig(cfg.MemoryConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create LLM: %w", err)
 	}
 
 	// Initialize query rewriter (if enabled)
-	var queryRewriter *query_rewrite.QueryRewriter
-	if cfg.QueryRewriteConfig != nil && cfg.QueryRewriteConfig.Enabled {
-		// Use override model if specified; otherwise use default LLM
-		rewriteLLM := llmProvider
-		if cfg.QueryRewriteConfig.ModelOverride != "" {
-			// Create LLM config with override model
-			overrideLLMConfig := cfg.MemoryConfig.LLM
-			overrideLLMConfig.Model = cfg.QueryRewriteConfig.ModelOverride
-			overrideLLM, err := initLLMFromConfig(overrideLLMConfig)
-			if err == nil {
-				rewriteLLM = overrideLLM
-			}
-			// Fall back to default LLM if creation fails
+	queryRewriter := newQueryRewriter(cfg, llmProvider)
+
+	profileMergeMode, err := resolveProfileMergeMode(cfg.ProfileMergeMode)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &Client{
+		memory:           memory,
+		profileStore:     profileStore,
+		llm:              llmProvider,
+		queryRewriter:    queryRewriter,
+		profileMergeMode: profileMergeMode,
+		config:           cfg,
+	}
+
+	// Start background profile extraction (if enabled)
+	if cfg.AsyncProfileExtraction {
+		maxRetries := cfg.ProfileMaxRetries
+		if maxRetries == 0 {
+			maxRetries = defaultProfileMaxRetries
 		}
-		queryRewriter = query_rewrite.NewQueryRewriter(rewriteLLM, cfg.QueryRewriteConfig)
+		client.profileWorker = newProfileWorker(client, cfg.ProfileQueueSize, maxRetries, cfg.ProfileRetryBackoff)
 	}
 
-	return &Client{
-		memory:        memory,
-		profileStore:  profileStore,
-		llm:           llmProvider,
-		queryRewriter: queryRewriter,
-	}, nil
+	return client, nil
+}
+
+// newQueryRewriter creates the query rewriter configured by cfg, or returns
+// nil if query rewriting is disabled.
+func newQueryRewriter(cfg *Config, llmProvider llm.Provider) *query_rewrite.QueryRewriter {
+	if cfg.QueryRewriteConfig == nil || !cfg.QueryRewriteConfig.Enabled {
+		return nil
+	}
+
+	// Use override model if specified; otherwise use default LLM
+	rewriteLLM := llmProvider
+	if cfg.QueryRewriteConfig.ModelOverride != "" {
+		// Create LLM config with override model
+		overrideConfig := *cfg.MemoryConfig
+		overrideConfig.LLM.Model = cfg.QueryRewriteConfig.ModelOverride
+		overrideLLM, err := initLLMFromConfig(&overrideConfig)
+		if err == nil {
+			rewriteLLM = overrideLLM
+		}
+		// Fall back to default LLM if creation fails
+	}
+	return query_rewrite.NewQueryRewriter(rewriteLLM, cfg.QueryRewriteConfig)
+}
+
+// resolveProfileMergeMode applies the default merge mode and rejects unknown ones.
+func resolveProfileMergeMode(mode ProfileMergeMode) (ProfileMergeMode, error) {
+	if mode == "" {
+		mode = ProfileMergeRewrite
+	}
+	if mode != ProfileMergeRewrite && mode != ProfileMergeStructured {
+		return "", fmt.Errorf("unsupported profile merge mode: %s", mode)
+	}
+	return mode, nil
 }
 
 // initLLMFromConfig initializes an LLM provider from configuration.
 //
-// This is a helper function that duplicates the LLM initialization logic
-// from the core package, allowing UserMemory to have its own LLM instance
-// for profile extraction.
-func initLLMFromConfig(cfg core.LLMConfig) (llm.Provider, error) {
-	switch cfg.Provider {
-	case "openai":
-		return openaiLLM.NewClient(&openaiLLM.Config{
-			APIKey:  cfg.APIKey,
-			Model:   cfg.Model,
-			BaseURL: cfg.BaseURL,
-		})
-	case "qwen":
-		return qwenLLM.NewClient(&qwenLLM.Config{
-			APIKey:  cfg.APIKey,
-			Model:   cfg.Model,
-			BaseURL: cfg.BaseURL,
-		})
-	case "deepseek":
-		return deepseekLLM.NewClient(&deepseekLLM.Config{
-			APIKey:  cfg.APIKey,
-			Model:   cfg.Model,
-			BaseURL: cfg.BaseURL,
-		})
-	case "ollama":
-		return ollamaLLM.NewClient(&ollamaLLM.Config{
-			APIKey:  cfg.APIKey,
-			Model:   cfg.Model,
-			BaseURL: cfg.BaseURL,
-		})
-	case "anthropic":
-		return anthropicLLM.NewClient(&anthropicLLM.Config{
-			APIKey:  cfg.APIKey,
-			Model:   cfg.Model,
-			BaseURL: cfg.BaseURL,
-		})
-	default:
-		return nil, fmt.Errorf("unsupported LLM provider: %s", cfg.Provider)
-	}
+// UserMemory has its own LLM instance for profile extraction. It is created
+// by core.NewLLMProvider, so API keys can also come from Config.Secrets.
+func initLLMFromConfig(cfg *core.Config) (llm.Provider, error) {
+	return core.NewLLMProvider(cfg)
 }
 
 // Add adds a conversation and automatically extracts/updates the user profile.
@@ -226,6 +292,8 @@ func initLLMFromConfig(cfg core.LLMConfig) (llm.Provider, error) {
 //   - opts: Optional parameters (UserID, AgentID, ProfileType, etc.)
 //
 // Returns an AddResult containing the created memory and profile extraction results.
+// With AsyncProfileExtraction enabled, steps 2-3 run in a background worker and
+// the result only has ProfilePending set.
 //
 // Example:
 //
@@ -235,7 +303,36 @@ func initLLMFromConfig(cfg core.LLMConfig) (llm.Provider, error) {
 func (c *Client) Add(ctx context.Context, messages interface{}, opts ...AddOption) (*AddResult, error) {
 	addOpts := applyAddOptions(opts)
 
-	// 1. Build core.Add options, passing all parameters
+	// 1. Store conversation event (using Memory)
+	memory, err := c.memory.Add(ctx, c.formatMessages(messages), c.coreAddOptions(addOpts)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add memory: %w", err)
+	}
+
+	// 2. Extract and save user profile
+	if c.profileWorker != nil {
+		if err := c.profileWorker.enqueue(ctx, messages, addOpts); err != nil {
+			return nil, fmt.Errorf("failed to queue profile extraction: %w", err)
+		}
+		return &AddResult{Memory: memory, ProfilePending: true}, nil
+	}
+
+	update, err := c.updateProfile(ctx, messages, addOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddResult{
+		Memory:           memory,
+		ProfileExtracted: update.extracted,
+		ProfileContent:   update.content,
+		Topics:           update.topics,
+		ProfileChanges:   update.changes,
+	}, nil
+}
+
+// coreAddOptions converts AddOptions into core.Add options, passing all parameters.
+func (c *Client) coreAddOptions(addOpts *AddOptions) []core.AddOption {
 	coreOpts := []core.AddOption{
 		core.WithUserID(addOpts.UserID),
 		core.WithAgentID(addOpts.AgentID),
@@ -258,57 +355,77 @@ func (c *Client) Add(ctx context.Context, messages interface{}, opts ...AddOptio
 	if addOpts.Prompt != "" {
 		coreOpts = append(coreOpts, core.WithPrompt(addOpts.Prompt))
 	}
-	coreOpts = append(coreOpts, core.WithInfer(addOpts.Infer))
-
-	// Store conversation event (using Memory)
-	memory, err := c.memory.Add(ctx, c.formatMessages(messages), coreOpts...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to add memory: %w", err)
+	if len(addOpts.Batch) > 0 {
+		coreOpts = append(coreOpts, core.WithBatchOptions(addOpts.Batch...))
 	}
+	return append(coreOpts, core.WithInfer(addOpts.Infer))
+}
+
+// profileUpdate is the outcome of a profile extraction.
+type profileUpdate struct {
+	// content is the extracted unstructured profile content (if extracted).
+	content *string
 
-	// 2. Extract user profile
-	var profileContent *string
-	var topics map[string]interface{}
+	// topics is the extracted structured topics or fields (if extracted).
+	topics map[string]interface{}
+
+	// extracted indicates whether a profile was saved.
+	extracted bool
+
+	// changes lists the fields changed by a structured merge.
+	changes []ProfileFieldChange
+}
+
+// updateProfile extracts the user profile from messages and saves it.
+func (c *Client) updateProfile(ctx context.Context, messages interface{}, addOpts *AddOptions) (*profileUpdate, error) {
+	update := &profileUpdate{}
 
 	// Filter messages by roles (if specified)
 	filteredMessages := c.filterMessagesByRoles(messages, addOpts.IncludeRoles, addOpts.ExcludeRoles)
 
-	if addOpts.ProfileType == "topics" {
+	switch {
+	case addOpts.ProfileType == "topics":
 		// Extract structured topics
 		extractedTopics, err := c.extractTopics(ctx, filteredMessages, addOpts.UserID, addOpts.CustomTopics, addOpts.StrictMode)
 		if err != nil {
 			return nil, fmt.Errorf("failed to extract topics: %w", err)
 		}
 		if extractedTopics != nil {
-			topics = extractedTopics
+			update.topics = extractedTopics
 		}
-	} else {
+	case c.mergeMode() == ProfileMergeStructured:
+		// Extract changed fields and merge them into the stored fields
+		fields, changes, err := c.extractProfileFields(ctx, filteredMessages, addOpts.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract profile fields: %w", err)
+		}
+		if len(changes) == 0 {
+			return update, nil
+		}
+		content := renderProfileFields(fields)
+		update.content = &content
+		update.topics = fields
+		update.changes = changes
+	default:
 		// Extract unstructured profile content
 		extractedContent, err := c.extractProfile(ctx, filteredMessages, addOpts.UserID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to extract profile: %w", err)
 		}
 		if extractedContent != "" {
-			profileContent = &extractedContent
+			update.content = &extractedContent
 		}
 	}
 
-	// 3. Save user profile
-	var profileExtracted bool
-	if profileContent != nil || topics != nil {
-		_, err = c.profileStore.SaveProfile(ctx, addOpts.UserID, profileContent, topics)
-		if err != nil {
-			return nil, fmt.Errorf("failed to save profile: %w", err)
-		}
-		profileExtracted = true
+	// Save user profile
+	if update.content == nil && update.topics == nil {
+		return update, nil
 	}
-
-	return &AddResult{
-		Memory:           memory,
-		ProfileExtracted: profileExtracted,
-		ProfileContent:   profileContent,
-		Topics:           topics,
-	}, nil
+	if _, err := c.profileStore.SaveProfile(ctx, addOpts.UserID, update.content, update.topics); err != nil {
+		return nil, fmt.Errorf("failed to save profile: %w", err)
+	}
+	update.extracted = true
+	return update, nil
 }
 
 // SearchResult contains the result of a search operation.
@@ -321,6 +438,16 @@ type SearchResult struct {
 
 	// Topics is the user profile topics (if AddProfile was true).
 	Topics map[string]interface{}
+
+	// RewrittenQuery is the query actually sent to the memory search. It equals
+	// the original query unless QueryRewritten is true.
+	RewrittenQuery string
+
+	// QueryRewritten indicates whether the query was rewritten using the user profile.
+	QueryRewritten bool
+
+	// Diagnostics explains how the results were produced (if Diagnostics was true).
+	Diagnostics *core.SearchDiagnostics
 }
 
 // Search searches for memories, optionally enhanced with user profile information.
@@ -332,44 +459,53 @@ type SearchResult struct {
 // Parameters:
 //   - ctx: Context for cancellation
 //   - query: Search query string
-//   - opts: Optional parameters (UserID, AgentID, Limit, AddProfile)
+//   - opts: Optional parameters (UserID, AgentID, Limit, AddProfile, DisableQueryRewrite, Diagnostics, Explain)
 //
-// Returns a SearchResult containing matching memories and optionally user profile.
+// Returns a SearchResult containing matching memories, the query that was
+// searched, and optionally the user profile.
 func (c *Client) Search(ctx context.Context, query string, opts ...SearchOption) (*SearchResult, error) {
 	searchOpts := applySearchOptions(opts)
 
-	// === Query rewrite step ===
-	effectiveQuery := query
-	if c.queryRewriter != nil && searchOpts.UserID != "" {
-		// Get user profile from profile store
-		profile, err := c.profileStore.GetProfileByUserID(ctx, searchOpts.UserID)
-		if err == nil && profile != nil && profile.ProfileContent != "" {
-			// Execute rewrite
-			rewriteResult := c.queryRewriter.Rewrite(ctx, query, profile.ProfileContent)
-			effectiveQuery = rewriteResult.RewrittenQuery
-		}
-	}
-	// === End of query rewrite step ===
-
 	// Call memory.search() with rewritten query
-	var searchOptions []core.SearchOption
-	if searchOpts.UserID != "" {
-		searchOptions = append(searchOptions, core.WithUserIDForSearch(searchOpts.UserID))
+	start := time.Now()
+	effectiveQuery, rewritten := c.rewriteQuery(ctx, query, searchOpts)
+	rewriteTime := time.Since(start)
+
+	var memories []*core.Memory
+	var diag *core.SearchDiagnostics
+	var err error
+	if searchOpts.Diagnostics {
+		memories, diag, err = c.memory.SearchWithDiagnostics(ctx, effectiveQuery, c.coreSearchOptions(searchOpts)...)
+	} else {
+		memories, err = c.memory.Search(ctx, effectiveQuery, c.coreSearchOptions(searchOpts)...)
 	}
-	if searchOpts.AgentID != "" {
-		searchOptions = append(searchOptions, core.WithAgentIDForSearch(searchOpts.AgentID))
+	if err != nil {
+		return nil, err
 	}
-	if searchOpts.Limit > 0 {
-		searchOptions = append(searchOptions, core.WithLimit(searchOpts.Limit))
+
+	if rewritten && searchOpts.Explain {
+		for _, memory := range memories {
+			if memory.Explanation != nil {
+				memory.Explanation.OriginalQuery = query
+			}
+		}
 	}
 
-	memories, err := c.memory.Search(ctx, effectiveQuery, searchOptions...)
-	if err != nil {
-		return nil, err
+	if diag != nil {
+		diag.Query = query
+		diag.QueryRewritten = rewritten
+		if rewritten {
+			diag.RewrittenQuery = effectiveQuery
+		}
+		diag.RewriteTime = rewriteTime
+		diag.TotalTime = time.Since(start)
 	}
 
 	result := &SearchResult{
-		Memories: memories,
+		Memories:       memories,
+		RewrittenQuery: effectiveQuery,
+		QueryRewritten: rewritten,
+		Diagnostics:    diag,
 	}
 
 	// Add profile if requested and user_id is provided
@@ -388,6 +524,52 @@ func (c *Client) Search(ctx context.Context, query string, opts ...SearchOption)
 	return result, nil
 }
 
+// rewriteQuery rewrites query based on the user's profile.
+//
+// The query is returned unchanged if query rewrite is disabled (globally or for
+// this call), UserID is empty, the user has no profile content, or the rewrite
+// fails. The boolean result reports whether the query was rewritten.
+func (c *Client) rewriteQuery(ctx context.Context, query string, searchOpts *SearchOptions) (string, bool) {
+	queryRewriter := c.rewriter()
+	if queryRewriter == nil || searchOpts.DisableQueryRewrite || searchOpts.UserID == "" {
+		return query, false
+	}
+
+	// Get user profile from profile store
+	profile, err := c.profileStore.GetProfileByUserID(ctx, searchOpts.UserID)
+	if err != nil || profile == nil || profile.ProfileContent == "" {
+		return query, false
+	}
+
+	// Execute rewrite
+	rewriteResult := queryRewriter.RewriteForUser(ctx, searchOpts.UserID, query, profile.ProfileContent)
+	if !rewriteResult.IsRewritten {
+		return query, false
+	}
+	return rewriteResult.RewrittenQuery, true
+}
+
+// coreSearchOptions converts SearchOptions into core.Search options.
+func (c *Client) coreSearchOptions(searchOpts *SearchOptions) []core.SearchOption {
+	var searchOptions []core.SearchOption
+	if searchOpts.UserID != "" {
+		searchOptions = append(searchOptions, core.WithUserIDForSearch(searchOpts.UserID))
+	}
+	if searchOpts.AgentID != "" {
+		searchOptions = append(searchOptions, core.WithAgentIDForSearch(searchOpts.AgentID))
+	}
+	if searchOpts.Limit > 0 {
+		searchOptions = append(searchOptions, core.WithLimit(searchOpts.Limit))
+	}
+	if searchOpts.Explain {
+		searchOptions = append(searchOptions, core.WithExplain(true))
+	}
+	if searchOpts.AllowCrossUser {
+		searchOptions = append(searchOptions, core.WithAllowCrossUser(true))
+	}
+	return searchOptions
+}
+
 // GetProfile retrieves the user profile for a given user ID.
 //
 // Parameters:
@@ -415,6 +597,49 @@ func (c *Client) GetProfiles(ctx context.Context, opts *GetProfilesOptions) ([]*
 	return c.profileStore.GetProfiles(ctx, opts)
 }
 
+// GetProfilesBulk retrieves the profiles of several users with a single store query.
+//
+// Duplicate and empty user IDs are ignored.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userIDs: User identifiers
+//
+// Returns a map from user ID to profile. Users without a profile are not
+// present in the map.
+//
+// Example:
+//
+//	profiles, err := client.GetProfilesBulk(ctx, []string{"user_001", "user_002"})
+//	if p, ok := profiles["user_001"]; ok {
+//	    fmt.Println(p.ProfileContent)
+//	}
+func (c *Client) GetProfilesBulk(ctx context.Context, userIDs []string) (map[string]*UserProfile, error) {
+	seen := make(map[string]bool, len(userIDs))
+	unique := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userID == "" || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		unique = append(unique, userID)
+	}
+
+	result := make(map[string]*UserProfile, len(unique))
+	if len(unique) == 0 {
+		return result, nil
+	}
+
+	profiles, err := c.profileStore.GetProfilesByUserIDs(ctx, unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profiles: %w", err)
+	}
+	for _, profile := range profiles {
+		result[profile.UserID] = profile
+	}
+	return result, nil
+}
+
 // DeleteProfile deletes a user profile by profile ID.
 //
 // Parameters:
@@ -471,13 +696,29 @@ func (c *Client) Get(ctx context.Context, memoryID int64, opts ...GetOption) (*c
 //   - ctx: Context for cancellation
 //   - memoryID: Memory ID to update
 //   - content: New content for the memory
-//   - opts: Optional parameters (currently unused, reserved for future use)
+//   - opts: Optional parameters (UserID, AgentID, Metadata, ExpectedVersion)
 //
 // Returns the updated Memory, or an error if update fails.
 func (c *Client) Update(ctx context.Context, memoryID int64, content string, opts ...UpdateOption) (*core.Memory, error) {
-	// Note: core.Update currently does not support user_id, agent_id, and metadata parameters
-	// If needed, can be implemented via other means
-	return c.memory.Update(ctx, memoryID, content)
+	return c.memory.Update(ctx, memoryID, content, c.coreUpdateOptions(applyUpdateOptions(opts))...)
+}
+
+// coreUpdateOptions converts Update options to core Update options.
+func (c *Client) coreUpdateOptions(updateOpts *UpdateOptions) []core.UpdateOption {
+	var updateOptions []core.UpdateOption
+	if updateOpts.UserID != "" {
+		updateOptions = append(updateOptions, core.WithUserIDForUpdate(updateOpts.UserID))
+	}
+	if updateOpts.AgentID != "" {
+		updateOptions = append(updateOptions, core.WithAgentIDForUpdate(updateOpts.AgentID))
+	}
+	if updateOpts.Metadata != nil {
+		updateOptions = append(updateOptions, core.WithMetadataForUpdate(updateOpts.Metadata))
+	}
+	if updateOpts.ExpectedVersion > 0 {
+		updateOptions = append(updateOptions, core.WithExpectedVersion(updateOpts.ExpectedVersion))
+	}
+	return updateOptions
 }
 
 // Delete deletes a memory by ID, optionally also deleting the user profile.
@@ -515,6 +756,49 @@ func (c *Client) Delete(ctx context.Context, memoryID int64, opts ...DeleteOptio
 	return nil
 }
 
+// GetByUID retrieves a single memory by its string ID.
+//
+// String IDs are assigned when MemoryConfig.IDType is core.IDTypeUUID.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - uid: Memory string ID
+//   - opts: Optional parameters (currently unused, reserved for future use)
+//
+// Returns the Memory if found, or an error if not found.
+func (c *Client) GetByUID(ctx context.Context, uid string, opts ...GetOption) (*core.Memory, error) {
+	return c.memory.GetByUID(ctx, uid)
+}
+
+// UpdateByUID updates the content of the memory with the given string ID.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - uid: Memory string ID to update
+//   - content: New content for the memory
+//   - opts: Optional parameters (UserID, AgentID, Metadata, ExpectedVersion)
+//
+// Returns the updated Memory, or an error if update fails.
+func (c *Client) UpdateByUID(ctx context.Context, uid string, content string, opts ...UpdateOption) (*core.Memory, error) {
+	return c.memory.UpdateByUID(ctx, uid, content, c.coreUpdateOptions(applyUpdateOptions(opts))...)
+}
+
+// DeleteByUID deletes the memory with the given string ID.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - uid: Memory string ID to delete
+//   - opts: Optional parameters (UserID, AgentID, DeleteProfile)
+//
+// Returns an error if deletion fails.
+func (c *Client) DeleteByUID(ctx context.Context, uid string, opts ...DeleteOption) error {
+	memory, err := c.memory.GetByUID(ctx, uid)
+	if err != nil {
+		return fmt.Errorf("failed to delete memory: %w", err)
+	}
+	return c.Delete(ctx, memory.ID, opts...)
+}
+
 // GetAll retrieves all memories with optional filtering and pagination.
 //
 // This method wraps the core Memory GetAll operation.
@@ -525,8 +809,11 @@ func (c *Client) Delete(ctx context.Context, memoryID int64, opts ...DeleteOptio
 //
 // Returns a list of memories matching the filters.
 func (c *Client) GetAll(ctx context.Context, opts ...GetAllOption) ([]*core.Memory, error) {
-	getAllOpts := applyGetAllOptions(opts)
+	return c.memory.GetAll(ctx, c.coreGetAllOptions(applyGetAllOptions(opts))...)
+}
 
+// coreGetAllOptions converts GetAllOptions into core.GetAll options.
+func (c *Client) coreGetAllOptions(getAllOpts *GetAllOptions) []core.GetAllOption {
 	var getAllOptions []core.GetAllOption
 	if getAllOpts.UserID != "" {
 		getAllOptions = append(getAllOptions, core.WithUserIDForGetAll(getAllOpts.UserID))
@@ -540,8 +827,13 @@ func (c *Client) GetAll(ctx context.Context, opts ...GetAllOption) ([]*core.Memo
 	if getAllOpts.Offset > 0 {
 		getAllOptions = append(getAllOptions, core.WithOffset(getAllOpts.Offset))
 	}
-
-	return c.memory.GetAll(ctx, getAllOptions...)
+	if len(getAllOpts.Filters) > 0 {
+		getAllOptions = append(getAllOptions, core.WithFiltersForGetAll(getAllOpts.Filters))
+	}
+	if getAllOpts.AllowCrossUser {
+		getAllOptions = append(getAllOptions, core.WithAllowCrossUserForGetAll(true))
+	}
+	return getAllOptions
 }
 
 // DeleteAll deletes all memories matching the filters, optionally also deleting user profiles.
@@ -575,11 +867,9 @@ func (c *Client) DeleteAll(ctx context.Context, opts ...DeleteAllOption) error {
 
 	// If delete_profile is set, also delete profile
 	if deleteAllOpts.DeleteProfile && deleteAllOpts.UserID != "" {
-		profile, err := c.profileStore.GetProfileByUserID(ctx, deleteAllOpts.UserID)
-		if err == nil && profile != nil {
-			// Ignore profile deletion errors, only log warnings
-			_ = c.profileStore.DeleteProfile(ctx, profile.ID)
-			// In actual applications, can use logger to log errors
+		filter := &ProfileFilter{UserIDs: []string{deleteAllOpts.UserID}}
+		if _, err := c.profileStore.DeleteAllProfiles(ctx, filter); err != nil {
+			return fmt.Errorf("failed to delete profile: %w", err)
 		}
 	}
 
@@ -588,30 +878,115 @@ func (c *Client) DeleteAll(ctx context.Context, opts ...DeleteAllOption) error {
 
 // Reset resets the storage by deleting all memories.
 //
-// Note: This method deletes all memories but does not delete user profiles.
-// To delete profiles as well, use DeleteAll with DeleteProfile option.
+// User profiles are kept unless WithResetProfiles(true) is given, in which
+// case all profiles are deleted as well.
 //
 // This is implemented using DeleteAll since the core package doesn't have
 // a Reset method.
 //
 // Parameters:
 //   - ctx: Context for cancellation
+//   - opts: Optional parameters (ResetProfiles)
 //
 // Returns an error if reset fails.
-func (c *Client) Reset(ctx context.Context) error {
+//
+// Example:
+//
+//	err := client.Reset(ctx, usermemory.WithResetProfiles(true))
+func (c *Client) Reset(ctx context.Context, opts ...ResetOption) error {
+	resetOpts := applyResetOptions(opts)
+
 	// Delete all memories
 	err := c.memory.DeleteAll(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to reset memories: %w", err)
 	}
 
-	// Note: UserProfileStore does not have a batch delete method
-	// If profile reset is needed, batch delete or iterative delete needs to be implemented
-	// Profile reset is not implemented here as it requires getting all profile lists
+	if resetOpts.ResetProfiles {
+		// Wait for queued extractions so they cannot recreate profiles afterwards
+		c.WaitProfileExtraction()
+		if _, err := c.profileStore.DeleteAllProfiles(ctx, nil); err != nil {
+			return fmt.Errorf("failed to reset profiles: %w", err)
+		}
+	}
 
 	return nil
 }
 
+// EraseUser permanently erases the data of a user, e.g. for a GDPR erasure
+// request: memories and change log entries (see core.Client.EraseUser), the
+// profile, and the query rewrites cached for the user.
+//
+// Queued profile extractions are waited for first, so they cannot recreate
+// the profile. Erasure goes on after a failed verification of the memories;
+// the report is then returned with an error wrapping
+// core.ErrErasureIncomplete.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userID: User whose data is erased (required)
+//
+// Returns the erasure report, or an error.
+//
+// Example:
+//
+//	report, err := client.EraseUser(ctx, "user_001")
+func (c *Client) EraseUser(ctx context.Context, userID string) (*ErasureReport, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("failed to erase user: %w", core.ErrInvalidInput)
+	}
+
+	// Wait for queued extractions so they cannot recreate the profile afterwards
+	c.WaitProfileExtraction()
+
+	memoryReport, memoryErr := c.memory.EraseUser(ctx, userID)
+	if memoryReport == nil {
+		return nil, fmt.Errorf("failed to erase memories: %w", memoryErr)
+	}
+	report := &ErasureReport{ErasureReport: *memoryReport}
+
+	filter := &ProfileFilter{UserIDs: []string{userID}}
+	profiles, err := c.profileStore.DeleteAllProfiles(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to erase profile: %w", err)
+	}
+	report.Profiles = profiles
+
+	c.mu.RLock()
+	queryRewriter := c.queryRewriter
+	c.mu.RUnlock()
+	if queryRewriter != nil {
+		report.CachedRewrites = queryRewriter.ForgetUser(userID)
+	}
+
+	// Verify that no profile was left or recreated
+	profile, err := c.profileStore.GetProfileByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify erasure: %w", err)
+	}
+	report.CompletedAt = time.Now()
+	if memoryErr != nil {
+		return report, memoryErr
+	}
+	if profile != nil {
+		report.Verified = false
+		return report, fmt.Errorf("profile of user %s left: %w", userID, core.ErrErasureIncomplete)
+	}
+
+	return report, nil
+}
+
+// Forget honors a user's request to forget something, given in natural
+// language, in two steps: a dry run planning which memories to delete or
+// rewrite, then WithConfirmedForgetActions to perform the approved actions.
+//
+// This method wraps the core Memory Forget operation; see
+// core.Client.Forget. The profile is not changed; if it holds what the user
+// asked to forget, delete it with DeleteProfileByUserID.
+func (c *Client) Forget(ctx context.Context, userID, instruction string, opts ...core.ForgetOption) (*core.ForgetResult, error) {
+	return c.memory.Forget(ctx, userID, instruction, opts...)
+}
+
 // extractProfile extracts user profile (unstructured).
 func (c *Client) extractProfile(ctx context.Context, messages interface{}, userID string) (string, error) {
 	// Format conversation text
@@ -632,7 +1007,7 @@ func (c *Client) extractProfile(ctx context.Context, messages interface{}, userI
 	userMessage := buildProfileExtractionUserMessage(conversationText, existingContent)
 
 	// Call LLM
-	response, err := c.llm.GenerateWithMessages(ctx, []llm.Message{
+	response, err := c.profileLLM().GenerateWithMessages(ctx, []llm.Message{
 		{Role: "system", Content: systemPrompt},
 		{Role: "user", Content: userMessage},
 	})
@@ -703,14 +1078,232 @@ func (a *sqliteStoreAdapter) GetProfiles(ctx context.Context, opts *GetProfilesO
 	return profiles, nil
 }
 
+func (a *sqliteStoreAdapter) GetProfilesByUserIDs(ctx context.Context, userIDs []string) ([]*UserProfile, error) {
+	sqliteProfiles, err := a.store.GetProfilesByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	profiles := make([]*UserProfile, len(sqliteProfiles))
+	for i, p := range sqliteProfiles {
+		profiles[i] = &UserProfile{
+			ID:             p.ID,
+			UserID:         p.UserID,
+			ProfileContent: p.ProfileContent,
+			Topics:         p.Topics,
+			CreatedAt:      p.CreatedAt,
+			UpdatedAt:      p.UpdatedAt,
+		}
+	}
+	return profiles, nil
+}
+
 func (a *sqliteStoreAdapter) DeleteProfile(ctx context.Context, profileID int64) error {
 	return a.store.DeleteProfile(ctx, profileID)
 }
 
+func (a *sqliteStoreAdapter) DeleteAllProfiles(ctx context.Context, filter *ProfileFilter) (int64, error) {
+	if filter == nil {
+		return a.store.DeleteAllProfiles(ctx, nil)
+	}
+	return a.store.DeleteAllProfiles(ctx, &sqlite.ProfileFilter{UserIDs: filter.UserIDs})
+}
+
+func (a *sqliteStoreAdapter) Snapshot(ctx context.Context, path string) error {
+	return a.store.Snapshot(ctx, path)
+}
+
+func (a *sqliteStoreAdapter) RestoreSnapshot(ctx context.Context, path string) error {
+	return a.store.RestoreSnapshot(ctx, path)
+}
+
 func (a *sqliteStoreAdapter) Close() error {
 	return a.store.Close()
 }
 
+// postgresStoreAdapter is an adapter that adapts postgres.Store to usermemory.UserProfileStore.
+type postgresStoreAdapter struct {
+	store *postgres.Store
+}
+
+func (a *postgresStoreAdapter) SaveProfile(ctx context.Context, userID string, profileContent *string, topics map[string]interface{}) (int64, error) {
+	return a.store.SaveProfile(ctx, userID, profileContent, topics)
+}
+
+func (a *postgresStoreAdapter) GetProfileByUserID(ctx context.Context, userID string) (*UserProfile, error) {
+	p, err := a.store.GetProfileByUserID(ctx, userID)
+	if err != nil || p == nil {
+		return nil, err
+	}
+	return &UserProfile{
+		ID:             p.ID,
+		UserID:         p.UserID,
+		ProfileContent: p.ProfileContent,
+		Topics:         p.Topics,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}, nil
+}
+
+func (a *postgresStoreAdapter) GetProfiles(ctx context.Context, opts *GetProfilesOptions) ([]*UserProfile, error) {
+	postgresProfiles, err := a.store.GetProfiles(ctx, &postgres.GetProfilesOptions{
+		UserID:     opts.UserID,
+		MainTopic:  opts.MainTopic,
+		SubTopic:   opts.SubTopic,
+		TopicValue: opts.TopicValue,
+		Limit:      opts.Limit,
+		Offset:     opts.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	profiles := make([]*UserProfile, len(postgresProfiles))
+	for i, p := range postgresProfiles {
+		profiles[i] = &UserProfile{
+			ID:             p.ID,
+			UserID:         p.UserID,
+			ProfileContent: p.ProfileContent,
+			Topics:         p.Topics,
+			CreatedAt:      p.CreatedAt,
+			UpdatedAt:      p.UpdatedAt,
+		}
+	}
+	return profiles, nil
+}
+
+func (a *postgresStoreAdapter) GetProfilesByUserIDs(ctx context.Context, userIDs []string) ([]*UserProfile, error) {
+	postgresProfiles, err := a.store.GetProfilesByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	profiles := make([]*UserProfile, len(postgresProfiles))
+	for i, p := range postgresProfiles {
+		profiles[i] = &UserProfile{
+			ID:             p.ID,
+			UserID:         p.UserID,
+			ProfileContent: p.ProfileContent,
+			Topics:         p.Topics,
+			CreatedAt:      p.CreatedAt,
+			UpdatedAt:      p.UpdatedAt,
+		}
+	}
+	return profiles, nil
+}
+
+func (a *postgresStoreAdapter) DeleteProfile(ctx context.Context, profileID int64) error {
+	return a.store.DeleteProfile(ctx, profileID)
+}
+
+func (a *postgresStoreAdapter) DeleteAllProfiles(ctx context.Context, filter *ProfileFilter) (int64, error) {
+	if filter == nil {
+		return a.store.DeleteAllProfiles(ctx, nil)
+	}
+	return a.store.DeleteAllProfiles(ctx, &postgres.ProfileFilter{UserIDs: filter.UserIDs})
+}
+
+func (a *postgresStoreAdapter) Snapshot(ctx context.Context, path string) error {
+	return a.store.Snapshot(ctx, path)
+}
+
+func (a *postgresStoreAdapter) RestoreSnapshot(ctx context.Context, path string) error {
+	return a.store.RestoreSnapshot(ctx, path)
+}
+
+func (a *postgresStoreAdapter) Close() error {
+	return a.store.Close()
+}
+
+// oceanbaseStoreAdapter is an adapter that adapts oceanbase.Store to usermemory.UserProfileStore.
+type oceanbaseStoreAdapter struct {
+	store *oceanbase.Store
+}
+
+func (a *oceanbaseStoreAdapter) SaveProfile(ctx context.Context, userID string, profileContent *string, topics map[string]interface{}) (int64, error) {
+	return a.store.SaveProfile(ctx, userID, profileContent, topics)
+}
+
+func (a *oceanbaseStoreAdapter) GetProfileByUserID(ctx context.Context, userID string) (*UserProfile, error) {
+	p, err := a.store.GetProfileByUserID(ctx, userID)
+	if err != nil || p == nil {
+		return nil, err
+	}
+	return &UserProfile{
+		ID:             p.ID,
+		UserID:         p.UserID,
+		ProfileContent: p.ProfileContent,
+		Topics:         p.Topics,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}, nil
+}
+
+func (a *oceanbaseStoreAdapter) GetProfiles(ctx context.Context, opts *GetProfilesOptions) ([]*UserProfile, error) {
+	oceanbaseProfiles, err := a.store.GetProfiles(ctx, &oceanbase.GetProfilesOptions{
+		UserID:     opts.UserID,
+		MainTopic:  opts.MainTopic,
+		SubTopic:   opts.SubTopic,
+		TopicValue: opts.TopicValue,
+		Limit:      opts.Limit,
+		Offset:     opts.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	profiles := make([]*UserProfile, len(oceanbaseProfiles))
+	for i, p := range oceanbaseProfiles {
+		profiles[i] = &UserProfile{
+			ID:             p.ID,
+			UserID:         p.UserID,
+			ProfileContent: p.ProfileContent,
+			Topics:         p.Topics,
+			CreatedAt:      p.CreatedAt,
+			UpdatedAt:      p.UpdatedAt,
+		}
+	}
+	return profiles, nil
+}
+
+func (a *oceanbaseStoreAdapter) GetProfilesByUserIDs(ctx context.Context, userIDs []string) ([]*UserProfile, error) {
+	oceanbaseProfiles, err := a.store.GetProfilesByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	profiles := make([]*UserProfile, len(oceanbaseProfiles))
+	for i, p := range oceanbaseProfiles {
+		profiles[i] = &UserProfile{
+			ID:             p.ID,
+			UserID:         p.UserID,
+			ProfileContent: p.ProfileContent,
+			Topics:         p.Topics,
+			CreatedAt:      p.CreatedAt,
+			UpdatedAt:      p.UpdatedAt,
+		}
+	}
+	return profiles, nil
+}
+
+func (a *oceanbaseStoreAdapter) DeleteProfile(ctx context.Context, profileID int64) error {
+	return a.store.DeleteProfile(ctx, profileID)
+}
+
+func (a *oceanbaseStoreAdapter) DeleteAllProfiles(ctx context.Context, filter *ProfileFilter) (int64, error) {
+	if filter == nil {
+		return a.store.DeleteAllProfiles(ctx, nil)
+	}
+	return a.store.DeleteAllProfiles(ctx, &oceanbase.ProfileFilter{UserIDs: filter.UserIDs})
+}
+
+func (a *oceanbaseStoreAdapter) Snapshot(ctx context.Context, path string) error {
+	return a.store.Snapshot(ctx, path)
+}
+
+func (a *oceanbaseStoreAdapter) RestoreSnapshot(ctx context.Context, path string) error {
+	return a.store.RestoreSnapshot(ctx, path)
+}
+
+func (a *oceanbaseStoreAdapter) Close() error {
+	return a.store.Close()
+}
+
 // extractTopics extracts structured topics.
 func (c *Client) extractTopics(ctx context.Context, messages interface{}, userID string, customTopics string, strictMode bool) (map[string]interface{}, error) {
 	// Simplified implementation, returning nil indicates not implemented
@@ -823,10 +1416,83 @@ func (c *Client) formatMessages(messages interface{}) string {
 	}
 }
 
+// WaitProfileExtraction blocks until all queued background profile extractions have finished.
+//
+// It returns immediately if AsyncProfileExtraction is disabled.
+func (c *Client) WaitProfileExtraction() {
+	if c.profileWorker != nil {
+		c.profileWorker.wait()
+	}
+}
+
+// Health checks the vector store, LLM and embedder of the underlying memory client.
+//
+// This method wraps the core Memory Health operation; see core.Client.Health.
+func (c *Client) Health(ctx context.Context, opts ...core.HealthOption) (*core.HealthReport, error) {
+	return c.memory.Health(ctx, opts...)
+}
+
+// Subscribe registers handler to be called with the memory events of the
+// underlying memory client (all events if no types are given).
+//
+// This method wraps the core Memory Subscribe operation; see
+// core.Client.Subscribe. Profile changes do not publish events.
+func (c *Client) Subscribe(handler core.EventHandler, types ...core.EventType) (unsubscribe func()) {
+	return c.memory.Subscribe(handler, types...)
+}
+
+// Shutdown gracefully shuts the client down.
+//
+// It stops accepting background profile extractions and waits for the queued
+// ones to be saved, shuts down the memory client (see core.Client.Shutdown),
+// and then closes the profile store and LLM provider.
+//
+// If ctx is done first, Shutdown returns ctx's error and leaves the stores
+// open; call Close to release them anyway.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	if err := client.Shutdown(ctx); err != nil {
+//	    log.Printf("shutdown: %v", err)
+//	    _ = client.Close()
+//	}
+func (c *Client) Shutdown(ctx context.Context) error {
+	if c.profileWorker != nil {
+		if err := c.profileWorker.shutdown(ctx); err != nil {
+			return fmt.Errorf("Shutdown: %w", err)
+		}
+	}
+
+	if c.memory != nil {
+		if err := c.memory.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+
+	return c.Close()
+}
+
 // Close closes the client.
+//
+// Pending background profile extractions are completed before the stores are closed.
+// Calling Close more than once is safe.
 func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		c.closeErr = c.closeResources()
+	})
+	return c.closeErr
+}
+
+// closeResources closes the profile worker, stores and providers.
+func (c *Client) closeResources() error {
 	var errs []error
 
+	if c.profileWorker != nil {
+		c.profileWorker.close()
+	}
+
 	if c.memory != nil {
 		if err := c.memory.Close(); err != nil {
 			errs = append(errs, err)
@@ -839,8 +1505,8 @@ func (c *Client) Close() error {
 		}
 	}
 
-	if c.llm != nil {
-		if err := c.llm.Close(); err != nil {
+	if llmProvider := c.profileLLM(); llmProvider != nil {
+		if err := llmProvider.Close(); err != nil {
 			errs = append(errs, err)
 		}
 	}
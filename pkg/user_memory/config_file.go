@@ -0,0 +1,139 @@
+package usermemory
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/user_memory/oceanbase"
+	"github.com/oceanbase/powermem-go/pkg/user_memory/postgres"
+	"github.com/oceanbase/powermem-go/pkg/user_memory/query_rewrite"
+	"github.com/oceanbase/powermem-go/pkg/user_memory/sqlite"
+)
+
+// fileConfig is the user_memory section of a config file.
+type fileConfig struct {
+	UserMemory struct {
+		ProfileStore struct {
+			Provider string          `json:"provider"`
+			Config   json.RawMessage `json:"config"`
+		} `json:"profile_store"`
+		AsyncProfileExtraction bool   `json:"async_profile_extraction"`
+		ProfileQueueSize       int    `json:"profile_queue_size"`
+		ProfileMaxRetries      int    `json:"profile_max_retries"`
+		ProfileRetryBackoff    string `json:"profile_retry_backoff"`
+		ProfileMergeMode       string `json:"profile_merge_mode"`
+		QueryRewrite           *struct {
+			Enabled              bool   `json:"enabled"`
+			CustomInstructions   string `json:"custom_instructions"`
+			ModelOverride        string `json:"model_override"`
+			CacheSize            int    `json:"cache_size"`
+			MaxRewritesPerMinute int    `json:"max_rewrites_per_minute"`
+			Timeout              string `json:"timeout"`
+		} `json:"query_rewrite"`
+	} `json:"user_memory"`
+}
+
+// LoadConfigFromFile loads a UserMemory configuration from a YAML, TOML or
+// JSON file.
+//
+// MemoryConfig is loaded with core.LoadConfigFromFile, so the file uses the
+// same format, sections and ${NAME} interpolation. The UserMemory settings are
+// read from the user_memory section; durations are strings such as "500ms".
+//
+// Example config.yaml:
+//
+//	llm:
+//	  provider: openai
+//	  api_key: ${LLM_API_KEY}
+//	# embedder, vector_store, ...
+//	user_memory:
+//	  profile_store:
+//	    provider: sqlite
+//	    config:
+//	      db_path: ./profiles.db
+//	  async_profile_extraction: true
+//	  profile_retry_backoff: 1s
+//	  profile_merge_mode: structured
+//	  query_rewrite:
+//	    enabled: true
+//	    timeout: 300ms
+//
+// Example:
+//
+//	config, err := usermemory.LoadConfigFromFile("config.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	client, err := usermemory.NewClient(config)
+func LoadConfigFromFile(path string) (*Config, error) {
+	memoryConfig, err := core.LoadConfigFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file fileConfig
+	if err := core.DecodeConfigFile(path, &file); err != nil {
+		return nil, fmt.Errorf("LoadConfigFromFile: %w", err)
+	}
+	section := file.UserMemory
+
+	cfg := &Config{
+		MemoryConfig:           memoryConfig,
+		ProfileStoreType:       section.ProfileStore.Provider,
+		AsyncProfileExtraction: section.AsyncProfileExtraction,
+		ProfileQueueSize:       section.ProfileQueueSize,
+		ProfileMaxRetries:      section.ProfileMaxRetries,
+		ProfileMergeMode:       ProfileMergeMode(section.ProfileMergeMode),
+	}
+
+	switch cfg.ProfileStoreType {
+	case "sqlite":
+		cfg.ProfileStoreConfig = &sqlite.Config{}
+	case "postgres":
+		cfg.ProfileStoreConfig = &postgres.Config{}
+	case "oceanbase":
+		cfg.ProfileStoreConfig = &oceanbase.Config{}
+	default:
+		return nil, fmt.Errorf("LoadConfigFromFile: %w: user_memory.profile_store.provider: unknown provider %q (want sqlite, oceanbase or postgres)",
+			core.ErrInvalidConfig, cfg.ProfileStoreType)
+	}
+	if len(section.ProfileStore.Config) > 0 {
+		if err := json.Unmarshal(section.ProfileStore.Config, cfg.ProfileStoreConfig); err != nil {
+			return nil, fmt.Errorf("LoadConfigFromFile: %w: user_memory.profile_store.config: %v", core.ErrInvalidConfig, err)
+		}
+	}
+
+	if cfg.ProfileRetryBackoff, err = parseConfigDuration("user_memory.profile_retry_backoff", section.ProfileRetryBackoff); err != nil {
+		return nil, err
+	}
+
+	if rewrite := section.QueryRewrite; rewrite != nil {
+		cfg.QueryRewriteConfig = &query_rewrite.Config{
+			Enabled:              rewrite.Enabled,
+			CustomInstructions:   rewrite.CustomInstructions,
+			ModelOverride:        rewrite.ModelOverride,
+			CacheSize:            rewrite.CacheSize,
+			MaxRewritesPerMinute: rewrite.MaxRewritesPerMinute,
+		}
+		if cfg.QueryRewriteConfig.Timeout, err = parseConfigDuration("user_memory.query_rewrite.timeout", rewrite.Timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// parseConfigDuration parses an optional duration setting of the user_memory
+// section.
+func parseConfigDuration(field, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("LoadConfigFromFile: %w: %s: %v", core.ErrInvalidConfig, field, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,38 @@
+//go:build go1.23
+
+package usermemory
+
+import (
+	"context"
+	"iter"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// Memories returns an iterator over the memories matching opts, for use with
+// range-over-func (Go 1.23+). See core.Client.Memories.
+//
+// Example:
+//
+//	for memory, err := range client.Memories(ctx, usermemory.WithGetAllUserID("user_001")) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(memory.Content)
+//	}
+func (c *Client) Memories(ctx context.Context, opts ...GetAllOption) iter.Seq2[*core.Memory, error] {
+	return c.memory.Memories(ctx, c.coreGetAllOptions(applyGetAllOptions(opts))...)
+}
+
+// SearchMemories returns an iterator over search results, for use with
+// range-over-func (Go 1.23+). See core.Client.SearchMemories.
+//
+// The query is rewritten based on the user profile (if query rewrite is
+// enabled) once, when iteration starts.
+func (c *Client) SearchMemories(ctx context.Context, query string, opts ...SearchOption) iter.Seq2[*core.Memory, error] {
+	return func(yield func(*core.Memory, error) bool) {
+		searchOpts := applySearchOptions(opts)
+		effectiveQuery, _ := c.rewriteQuery(ctx, query, searchOpts)
+		c.memory.SearchMemories(ctx, effectiveQuery, c.coreSearchOptions(searchOpts)...)(yield)
+	}
+}
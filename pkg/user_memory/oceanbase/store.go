@@ -0,0 +1,411 @@
+// Package oceanbase provides OceanBase implementation for user profile storage.
+package oceanbase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// Store implements UserProfileStore using OceanBase as the backend.
+type Store struct {
+	// db is the OceanBase database connection.
+	db *sql.DB
+
+	// tableName is the name of the table storing user profiles.
+	tableName string
+}
+
+// Config contains configuration for creating a OceanBase UserProfileStore.
+type Config struct {
+	// Host is the database host.
+	Host string `json:"host"`
+
+	// Port is the database port.
+	Port int `json:"port"`
+
+	// User is the database user.
+	User string `json:"user"`
+
+	// Password is the database password.
+	Password string `json:"password"`
+
+	// DBName is the database name.
+	DBName string `json:"db_name"`
+
+	// TableName is the name of the table to use (default: "user_profiles").
+	TableName string `json:"table_name"`
+}
+
+// NewStore creates a new OceanBase UserProfileStore.
+//
+// Parameters:
+//   - cfg: Configuration containing connection settings and table name
+//
+// Returns:
+//   - *Store: The store instance
+//   - error: Error if database connection or table creation fails
+func NewStore(cfg *Config) (*Store, error) {
+	if cfg.TableName == "" {
+		cfg.TableName = "user_profiles"
+	}
+
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true",
+		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName)
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	store := &Store{
+		db:        db,
+		tableName: cfg.TableName,
+	}
+
+	// Create table
+	if err := store.initTable(context.Background()); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// initTable initializes the database table structure.
+func (s *Store) initTable(ctx context.Context) error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			user_id VARCHAR(128) NOT NULL,
+			profile_content LONGTEXT,
+			topics JSON,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE KEY uk_user_id (user_id)
+		)
+	`, s.tableName)
+
+	_, err := s.db.ExecContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+
+	return nil
+}
+
+// SaveProfile saves or updates a user profile.
+//
+// If a profile for the user already exists, it is updated.
+// Otherwise, a new profile is created.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userID: User identifier
+//   - profileContent: Unstructured profile content (optional)
+//   - topics: Structured topics (optional)
+//
+// Returns the profile ID and any error.
+func (s *Store) SaveProfile(ctx context.Context, userID string, profileContent *string, topics map[string]interface{}) (int64, error) {
+	var topicsJSON interface{}
+	if topics != nil {
+		data, err := json.Marshal(topics)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal topics: %w", err)
+		}
+		topicsJSON = string(data)
+	}
+
+	now := time.Now()
+	// LAST_INSERT_ID(id) makes LastInsertId return the existing ID on update
+	query := fmt.Sprintf(`
+		INSERT INTO %s (user_id, profile_content, topics, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			id = LAST_INSERT_ID(id),
+			profile_content = VALUES(profile_content),
+			topics = VALUES(topics),
+			updated_at = VALUES(updated_at)
+	`, s.tableName)
+
+	result, err := s.db.ExecContext(ctx, query, userID, profileContent, topicsJSON, now, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save profile: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	return id, nil
+}
+
+// GetProfileByUserID retrieves a user profile by user ID.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userID: User identifier
+//
+// Returns the UserProfile if found, or nil if not found.
+func (s *Store) GetProfileByUserID(ctx context.Context, userID string) (*UserProfile, error) {
+	query := fmt.Sprintf(`
+		SELECT id, user_id, profile_content, topics, created_at, updated_at
+		FROM %s
+		WHERE user_id = ?
+	`, s.tableName)
+
+	profile, err := scanProfile(s.db.QueryRowContext(ctx, query, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	return profile, nil
+}
+
+// GetProfiles retrieves a list of user profiles with optional filtering.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - opts: Filtering and pagination options
+//
+// Returns a list of matching user profiles.
+func (s *Store) GetProfiles(ctx context.Context, opts *GetProfilesOptions) ([]*UserProfile, error) {
+	query := fmt.Sprintf(`
+		SELECT id, user_id, profile_content, topics, created_at, updated_at
+		FROM %s
+	`, s.tableName)
+
+	args := []interface{}{}
+	conditions := []string{}
+
+	if opts.UserID != "" {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, opts.UserID)
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY updated_at DESC"
+
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+		if opts.Offset > 0 {
+			query += fmt.Sprintf(" OFFSET %d", opts.Offset)
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query profiles: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var profiles []*UserProfile
+	for rows.Next() {
+		profile, err := scanProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// GetProfilesByUserIDs retrieves the profiles of several users in a single query.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userIDs: User identifiers
+//
+// Returns the profiles found, in no particular order. Users without a
+// profile are omitted.
+func (s *Store) GetProfilesByUserIDs(ctx context.Context, userIDs []string) ([]*UserProfile, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(userIDs))
+	args := make([]interface{}, len(userIDs))
+	for i, userID := range userIDs {
+		placeholders[i] = "?"
+		args[i] = userID
+	}
+	query := fmt.Sprintf(`
+		SELECT id, user_id, profile_content, topics, created_at, updated_at
+		FROM %s
+		WHERE user_id IN (%s)
+	`, s.tableName, strings.Join(placeholders, ", "))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query profiles: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var profiles []*UserProfile
+	for rows.Next() {
+		profile, err := scanProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// DeleteProfile deletes a user profile by profile ID.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - profileID: Profile ID to delete
+//
+// Returns an error if deletion fails or profile is not found.
+func (s *Store) DeleteProfile(ctx context.Context, profileID int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ?", s.tableName)
+	result, err := s.db.ExecContext(ctx, query, profileID)
+	if err != nil {
+		return fmt.Errorf("failed to delete profile: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("profile not found")
+	}
+
+	return nil
+}
+
+// DeleteAllProfiles deletes all profiles matching filter in a single statement.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - filter: Profiles to delete (nil or empty UserIDs deletes all profiles)
+//
+// Returns the number of deleted profiles.
+func (s *Store) DeleteAllProfiles(ctx context.Context, filter *ProfileFilter) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s", s.tableName)
+	var args []interface{}
+	if filter != nil && len(filter.UserIDs) > 0 {
+		placeholders := make([]string, len(filter.UserIDs))
+		args = make([]interface{}, len(filter.UserIDs))
+		for i, userID := range filter.UserIDs {
+			placeholders[i] = "?"
+			args[i] = userID
+		}
+		query += fmt.Sprintf(" WHERE user_id IN (%s)", strings.Join(placeholders, ", "))
+	}
+
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete profiles: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// Snapshot writes a logical dump of the profiles to a new file at path
+// (see storage.WriteDump), read in a single repeatable read transaction.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - path: File the snapshot is written to (must not exist)
+//
+// Returns an error if path exists or the dump fails.
+func (s *Store) Snapshot(ctx context.Context, path string) error {
+	if err := storage.WriteDump(ctx, s.db, path, "oceanbase", "profiles", s.tableName); err != nil {
+		return fmt.Errorf("failed to snapshot profiles: %w", err)
+	}
+	return nil
+}
+
+// RestoreSnapshot replaces the profiles with those of the dump at path in
+// a single transaction.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - path: Dump written by Snapshot
+//
+// Returns an error if the dump is invalid or the restore fails.
+func (s *Store) RestoreSnapshot(ctx context.Context, path string) error {
+	placeholder := func(int) string { return "?" }
+	if _, err := storage.RestoreDump(ctx, s.db, path, "oceanbase", "profiles", s.tableName, placeholder); err != nil {
+		return fmt.Errorf("failed to restore profiles: %w", err)
+	}
+	return nil
+}
+
+// Close closes the database connection.
+func (s *Store) Close() error {
+	if s.db != nil {
+		return s.db.Close()
+	}
+	return nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanProfile scans a profile row.
+func scanProfile(row rowScanner) (*UserProfile, error) {
+	var profile UserProfile
+	var profileContent sql.NullString
+	var topicsJSON sql.NullString
+
+	if err := row.Scan(
+		&profile.ID,
+		&profile.UserID,
+		&profileContent,
+		&topicsJSON,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	profile.ProfileContent = profileContent.String
+
+	// Parse topics JSON
+	if topicsJSON.Valid && topicsJSON.String != "" {
+		if err := json.Unmarshal([]byte(topicsJSON.String), &profile.Topics); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal topics: %w", err)
+		}
+	}
+
+	return &profile, nil
+}
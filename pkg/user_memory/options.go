@@ -1,7 +1,11 @@
 // Package usermemory provides user memory management with automatic profile extraction.
 package usermemory
 
-import "github.com/oceanbase/powermem-go/pkg/core"
+import (
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
 
 // AddResult contains the result of an Add operation.
 //
@@ -18,6 +22,50 @@ type AddResult struct {
 
 	// Topics is the extracted structured topics (if extracted).
 	Topics map[string]interface{}
+
+	// ProfilePending indicates that profile extraction was queued for the
+	// background worker (AsyncProfileExtraction) and has not run yet.
+	ProfilePending bool
+
+	// ProfileChanges lists the profile fields changed by a structured merge
+	// (ProfileMergeStructured only).
+	ProfileChanges []ProfileFieldChange
+}
+
+// BatchAddResult contains the result of a BatchAdd operation.
+type BatchAddResult struct {
+	// Batch is the core batch add result (created memories and failures).
+	Batch *core.BatchAddResult
+
+	// ProfileExtracted indicates whether a profile was extracted/updated.
+	ProfileExtracted bool
+
+	// ProfileContent is the extracted unstructured profile content (if extracted).
+	ProfileContent *string
+
+	// Topics is the extracted structured topics (if extracted).
+	Topics map[string]interface{}
+
+	// ProfilePending indicates that profile extraction was queued for the
+	// background worker (AsyncProfileExtraction) and has not run yet.
+	ProfilePending bool
+
+	// ProfileChanges lists the profile fields changed by a structured merge
+	// (ProfileMergeStructured only).
+	ProfileChanges []ProfileFieldChange
+}
+
+// ErasureReport describes the data erased by EraseUser.
+type ErasureReport struct {
+	// ErasureReport reports the erased memories and change log entries.
+	// Verified also covers the profile.
+	core.ErasureReport
+
+	// Profiles is the number of deleted profiles.
+	Profiles int64 `json:"profiles"`
+
+	// CachedRewrites is the number of cached query rewrites removed.
+	CachedRewrites int `json:"cached_rewrites"`
 }
 
 // AddOptions contains configuration options for Add operations.
@@ -65,6 +113,9 @@ type AddOptions struct {
 
 	// ExcludeRoles specifies which roles to exclude when filtering messages for profile extraction.
 	ExcludeRoles []string
+
+	// Batch configures how BatchAdd stores the conversations (see core.BatchOptions).
+	Batch []core.BatchOption
 }
 
 // AddOption is a function type for configuring Add operations.
@@ -268,6 +319,21 @@ func WithExcludeRoles(roles []string) AddOption {
 	}
 }
 
+// WithBatchOptions sets the batch policy (concurrency, failure handling,
+// retries) used by BatchAdd to store the conversations.
+//
+// Example:
+//
+//	result, _ := client.BatchAdd(ctx, conversations,
+//	    usermemory.WithUserID("user_001"),
+//	    usermemory.WithBatchOptions(core.WithBatchConcurrency(4)),
+//	)
+func WithBatchOptions(opts ...core.BatchOption) AddOption {
+	return func(o *AddOptions) {
+		o.Batch = append(o.Batch, opts...)
+	}
+}
+
 // applyAddOptions applies Add options to create AddOptions.
 func applyAddOptions(opts []AddOption) *AddOptions {
 	options := &AddOptions{
@@ -297,6 +363,21 @@ type SearchOptions struct {
 
 	// AddProfile indicates whether to include user profile in search results.
 	AddProfile bool
+
+	// DisableQueryRewrite skips query rewriting for this call even if it is
+	// enabled in the client configuration.
+	DisableQueryRewrite bool
+
+	// Diagnostics requests search diagnostics in SearchResult.Diagnostics.
+	Diagnostics bool
+
+	// Explain requests the explanation of each result in
+	// core.Memory.Explanation.
+	Explain bool
+
+	// AllowCrossUser allows searches without a user ID, which search the
+	// memories of every user (see core.WithAllowCrossUser).
+	AllowCrossUser bool
 }
 
 // SearchOption is a function type for configuring Search operations.
@@ -351,6 +432,62 @@ func WithAddProfile(addProfile bool) SearchOption {
 	}
 }
 
+// WithDisableQueryRewrite sets whether to skip query rewriting for this call.
+//
+// Example:
+//
+//	results, _ := client.Search(ctx, "query",
+//	    usermemory.WithSearchUserID("user_001"),
+//	    usermemory.WithDisableQueryRewrite(true),
+//	)
+func WithDisableQueryRewrite(disable bool) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.DisableQueryRewrite = disable
+	}
+}
+
+// WithSearchDiagnostics sets whether to return search diagnostics
+// (candidate counts, rewrite applied, per-stage timings).
+//
+// Example:
+//
+//	result, _ := client.Search(ctx, "query", usermemory.WithSearchDiagnostics(true))
+//	fmt.Println(result.Diagnostics.Candidates, result.Diagnostics.RewriteTime)
+func WithSearchDiagnostics(enabled bool) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.Diagnostics = enabled
+	}
+}
+
+// WithSearchExplain sets whether to explain why each result was retrieved
+// (see core.WithExplain), including the rewrite of the query from the
+// user's profile.
+//
+// Example:
+//
+//	result, _ := client.Search(ctx, "query", usermemory.WithSearchExplain(true))
+//	for _, memory := range result.Memories {
+//	    fmt.Println(memory.Explanation.OriginalQuery, "->", memory.Explanation.Query)
+//	}
+func WithSearchExplain(explain bool) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.Explain = explain
+	}
+}
+
+// WithSearchAllowCrossUser allows Search to search the memories of every
+// user. Searches without WithSearchUserID fail with core.ErrInvalidInput
+// otherwise.
+//
+// Example:
+//
+//	result, _ := client.Search(ctx, "query", usermemory.WithSearchAllowCrossUser(true))
+func WithSearchAllowCrossUser(allow bool) SearchOption {
+	return func(opts *SearchOptions) {
+		opts.AllowCrossUser = allow
+	}
+}
+
 // applySearchOptions applies Search options to create SearchOptions.
 func applySearchOptions(opts []SearchOption) *SearchOptions {
 	options := &SearchOptions{
@@ -406,6 +543,10 @@ type UpdateOptions struct {
 
 	// Metadata contains additional metadata to update.
 	Metadata map[string]interface{}
+
+	// ExpectedVersion makes the update conditional on the memory's current
+	// version (0 disables the check).
+	ExpectedVersion int64
 }
 
 // UpdateOption is a function type for configuring Update operations.
@@ -448,6 +589,30 @@ func WithUpdateMetadata(metadata map[string]interface{}) UpdateOption {
 	}
 }
 
+// WithUpdateExpectedVersion makes an Update fail with core.ErrVersionConflict
+// if the memory's version no longer matches, i.e. it was changed since it was read.
+//
+// Example:
+//
+//	memory, _ := client.Get(ctx, memoryID)
+//	_, err := client.Update(ctx, memoryID, "new content",
+//	    usermemory.WithUpdateExpectedVersion(memory.Version),
+//	)
+func WithUpdateExpectedVersion(version int64) UpdateOption {
+	return func(opts *UpdateOptions) {
+		opts.ExpectedVersion = version
+	}
+}
+
+// applyUpdateOptions applies Update options to create UpdateOptions.
+func applyUpdateOptions(opts []UpdateOption) *UpdateOptions {
+	options := &UpdateOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
 // DeleteOptions contains configuration options for Delete operations.
 type DeleteOptions struct {
 	// UserID filters results to a specific user.
@@ -529,6 +694,10 @@ type GetAllOptions struct {
 
 	// Filters provides additional metadata filters.
 	Filters map[string]interface{}
+
+	// AllowCrossUser allows listings without a user ID, which list the
+	// memories of every user (see core.WithAllowCrossUserForGetAll).
+	AllowCrossUser bool
 }
 
 // GetAllOption is a function type for configuring GetAll operations.
@@ -607,6 +776,19 @@ func WithGetAllFilters(filters map[string]interface{}) GetAllOption {
 	}
 }
 
+// WithGetAllAllowCrossUser allows GetAll to list the memories of every user.
+// Listings without WithGetAllUserID fail with core.ErrInvalidInput
+// otherwise.
+//
+// Example:
+//
+//	memories, _ := client.GetAll(ctx, usermemory.WithGetAllAllowCrossUser(true))
+func WithGetAllAllowCrossUser(allow bool) GetAllOption {
+	return func(opts *GetAllOptions) {
+		opts.AllowCrossUser = allow
+	}
+}
+
 // applyGetAllOptions applies GetAll options to create GetAllOptions.
 func applyGetAllOptions(opts []GetAllOption) *GetAllOptions {
 	options := &GetAllOptions{
@@ -695,3 +877,90 @@ func applyDeleteAllOptions(opts []DeleteAllOption) *DeleteAllOptions {
 	}
 	return options
 }
+
+// ResetOptions contains configuration options for Reset operations.
+type ResetOptions struct {
+	// ResetProfiles indicates whether to also delete all user profiles.
+	ResetProfiles bool
+}
+
+// ResetOption is a function type for configuring Reset operations.
+type ResetOption func(*ResetOptions)
+
+// WithResetProfiles sets whether Reset also deletes all user profiles.
+//
+// Example:
+//
+//	_ = client.Reset(ctx, usermemory.WithResetProfiles(true))
+func WithResetProfiles(resetProfiles bool) ResetOption {
+	return func(opts *ResetOptions) {
+		opts.ResetProfiles = resetProfiles
+	}
+}
+
+// applyResetOptions applies Reset options to create ResetOptions.
+func applyResetOptions(opts []ResetOption) *ResetOptions {
+	options := &ResetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WatchOption is a function type for configuring WatchConfigFile.
+type WatchOption func(*WatchOptions)
+
+// WatchOptions contains configuration options for WatchConfigFile.
+type WatchOptions struct {
+	// Interval is how often the file is checked for changes.
+	// Default: 2s
+	Interval time.Duration
+
+	// OnError is called with each update that could not be applied.
+	// Default: log the error
+	OnError func(err error)
+
+	// OnReload is called with each configuration that was applied (optional).
+	OnReload func(cfg *Config)
+}
+
+// WithWatchInterval sets how often the config file is checked for changes.
+func WithWatchInterval(interval time.Duration) WatchOption {
+	return func(opts *WatchOptions) {
+		opts.Interval = interval
+	}
+}
+
+// WithReloadErrorHandler sets the function called with each config update
+// that could not be loaded or applied.
+func WithReloadErrorHandler(handler func(err error)) WatchOption {
+	return func(opts *WatchOptions) {
+		opts.OnError = handler
+	}
+}
+
+// WithReloadHandler sets the function called with each configuration that
+// was applied.
+func WithReloadHandler(handler func(cfg *Config)) WatchOption {
+	return func(opts *WatchOptions) {
+		opts.OnReload = handler
+	}
+}
+
+// applyWatchOptions applies WatchConfigFile options to create WatchOptions.
+func applyWatchOptions(opts []WatchOption) *WatchOptions {
+	options := &WatchOptions{
+		Interval: 2 * time.Second,
+		OnError:  logReloadError,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Interval <= 0 {
+		options.Interval = 2 * time.Second
+	}
+	if options.OnError == nil {
+		options.OnError = logReloadError
+	}
+	return options
+}
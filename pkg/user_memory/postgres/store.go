@@ -0,0 +1,415 @@
+// Package postgres provides PostgreSQL implementation for user profile storage.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+// Store implements UserProfileStore using PostgreSQL as the backend.
+type Store struct {
+	// db is the PostgreSQL database connection.
+	db *sql.DB
+
+	// tableName is the name of the table storing user profiles.
+	tableName string
+}
+
+// Config contains configuration for creating a PostgreSQL UserProfileStore.
+type Config struct {
+	// Host is the database host.
+	Host string `json:"host"`
+
+	// Port is the database port.
+	Port int `json:"port"`
+
+	// User is the database user.
+	User string `json:"user"`
+
+	// Password is the database password.
+	Password string `json:"password"`
+
+	// DBName is the database name.
+	DBName string `json:"db_name"`
+
+	// SSLMode is the SSL mode (default: "disable").
+	SSLMode string `json:"ssl_mode"`
+
+	// TableName is the name of the table to use (default: "user_profiles").
+	TableName string `json:"table_name"`
+}
+
+// NewStore creates a new PostgreSQL UserProfileStore.
+//
+// Parameters:
+//   - cfg: Configuration containing connection settings and table name
+//
+// Returns:
+//   - *Store: The store instance
+//   - error: Error if database connection or table creation fails
+func NewStore(cfg *Config) (*Store, error) {
+	if cfg.TableName == "" {
+		cfg.TableName = "user_profiles"
+	}
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, sslMode)
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	store := &Store{
+		db:        db,
+		tableName: cfg.TableName,
+	}
+
+	// Create table
+	if err := store.initTable(context.Background()); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// initTable initializes the database table structure.
+func (s *Store) initTable(ctx context.Context) error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGSERIAL PRIMARY KEY,
+			user_id VARCHAR(255) NOT NULL UNIQUE,
+			profile_content TEXT,
+			topics JSONB,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`, s.tableName)
+
+	_, err := s.db.ExecContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+
+	return nil
+}
+
+// SaveProfile saves or updates a user profile.
+//
+// If a profile for the user already exists, it is updated.
+// Otherwise, a new profile is created.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userID: User identifier
+//   - profileContent: Unstructured profile content (optional)
+//   - topics: Structured topics (optional)
+//
+// Returns the profile ID and any error.
+func (s *Store) SaveProfile(ctx context.Context, userID string, profileContent *string, topics map[string]interface{}) (int64, error) {
+	var topicsJSON interface{}
+	if topics != nil {
+		data, err := json.Marshal(topics)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal topics: %w", err)
+		}
+		topicsJSON = string(data)
+	}
+
+	now := time.Now()
+	query := fmt.Sprintf(`
+		INSERT INTO %s (user_id, profile_content, topics, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET profile_content = EXCLUDED.profile_content, topics = EXCLUDED.topics, updated_at = EXCLUDED.updated_at
+		RETURNING id
+	`, s.tableName)
+
+	var id int64
+	if err := s.db.QueryRowContext(ctx, query, userID, profileContent, topicsJSON, now).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to save profile: %w", err)
+	}
+	return id, nil
+}
+
+// GetProfileByUserID retrieves a user profile by user ID.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userID: User identifier
+//
+// Returns the UserProfile if found, or nil if not found.
+func (s *Store) GetProfileByUserID(ctx context.Context, userID string) (*UserProfile, error) {
+	query := fmt.Sprintf(`
+		SELECT id, user_id, profile_content, topics, created_at, updated_at
+		FROM %s
+		WHERE user_id = $1
+	`, s.tableName)
+
+	profile, err := scanProfile(s.db.QueryRowContext(ctx, query, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	return profile, nil
+}
+
+// GetProfiles retrieves a list of user profiles with optional filtering.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - opts: Filtering and pagination options
+//
+// Returns a list of matching user profiles.
+func (s *Store) GetProfiles(ctx context.Context, opts *GetProfilesOptions) ([]*UserProfile, error) {
+	query := fmt.Sprintf(`
+		SELECT id, user_id, profile_content, topics, created_at, updated_at
+		FROM %s
+	`, s.tableName)
+
+	args := []interface{}{}
+	conditions := []string{}
+
+	if opts.UserID != "" {
+		args = append(args, opts.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY updated_at DESC"
+
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+		if opts.Offset > 0 {
+			query += fmt.Sprintf(" OFFSET %d", opts.Offset)
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query profiles: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var profiles []*UserProfile
+	for rows.Next() {
+		profile, err := scanProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// GetProfilesByUserIDs retrieves the profiles of several users in a single query.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userIDs: User identifiers
+//
+// Returns the profiles found, in no particular order. Users without a
+// profile are omitted.
+func (s *Store) GetProfilesByUserIDs(ctx context.Context, userIDs []string) ([]*UserProfile, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(userIDs))
+	args := make([]interface{}, len(userIDs))
+	for i, userID := range userIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = userID
+	}
+	query := fmt.Sprintf(`
+		SELECT id, user_id, profile_content, topics, created_at, updated_at
+		FROM %s
+		WHERE user_id IN (%s)
+	`, s.tableName, strings.Join(placeholders, ", "))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query profiles: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var profiles []*UserProfile
+	for rows.Next() {
+		profile, err := scanProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// DeleteProfile deletes a user profile by profile ID.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - profileID: Profile ID to delete
+//
+// Returns an error if deletion fails or profile is not found.
+func (s *Store) DeleteProfile(ctx context.Context, profileID int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", s.tableName)
+	result, err := s.db.ExecContext(ctx, query, profileID)
+	if err != nil {
+		return fmt.Errorf("failed to delete profile: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("profile not found")
+	}
+
+	return nil
+}
+
+// DeleteAllProfiles deletes all profiles matching filter in a single statement.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - filter: Profiles to delete (nil or empty UserIDs deletes all profiles)
+//
+// Returns the number of deleted profiles.
+func (s *Store) DeleteAllProfiles(ctx context.Context, filter *ProfileFilter) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s", s.tableName)
+	var args []interface{}
+	if filter != nil && len(filter.UserIDs) > 0 {
+		placeholders := make([]string, len(filter.UserIDs))
+		args = make([]interface{}, len(filter.UserIDs))
+		for i, userID := range filter.UserIDs {
+			placeholders[i] = fmt.Sprintf("$%d", i+1)
+			args[i] = userID
+		}
+		query += fmt.Sprintf(" WHERE user_id IN (%s)", strings.Join(placeholders, ", "))
+	}
+
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete profiles: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// Snapshot writes a logical dump of the profiles to a new file at path
+// (see storage.WriteDump), read in a single repeatable read transaction.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - path: File the snapshot is written to (must not exist)
+//
+// Returns an error if path exists or the dump fails.
+func (s *Store) Snapshot(ctx context.Context, path string) error {
+	if err := storage.WriteDump(ctx, s.db, path, "postgres", "profiles", s.tableName); err != nil {
+		return fmt.Errorf("failed to snapshot profiles: %w", err)
+	}
+	return nil
+}
+
+// RestoreSnapshot replaces the profiles with those of the dump at path in
+// a single transaction.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - path: Dump written by Snapshot
+//
+// Returns an error if the dump is invalid or the restore fails.
+func (s *Store) RestoreSnapshot(ctx context.Context, path string) error {
+	placeholder := func(n int) string { return fmt.Sprintf("$%d", n) }
+	if _, err := storage.RestoreDump(ctx, s.db, path, "postgres", "profiles", s.tableName, placeholder); err != nil {
+		return fmt.Errorf("failed to restore profiles: %w", err)
+	}
+	// Continue the ID sequence after the restored profiles
+	query := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM %s",
+		s.tableName, s.tableName)
+	if _, err := s.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to restore profiles: %w", err)
+	}
+	return nil
+}
+
+// Close closes the database connection.
+func (s *Store) Close() error {
+	if s.db != nil {
+		return s.db.Close()
+	}
+	return nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanProfile scans a profile row.
+func scanProfile(row rowScanner) (*UserProfile, error) {
+	var profile UserProfile
+	var profileContent sql.NullString
+	var topicsJSON sql.NullString
+
+	if err := row.Scan(
+		&profile.ID,
+		&profile.UserID,
+		&profileContent,
+		&topicsJSON,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	profile.ProfileContent = profileContent.String
+
+	// Parse topics JSON
+	if topicsJSON.Valid && topicsJSON.String != "" {
+		if err := json.Unmarshal([]byte(topicsJSON.String), &profile.Topics); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal topics: %w", err)
+		}
+	}
+
+	return &profile, nil
+}
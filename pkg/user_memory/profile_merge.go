@@ -0,0 +1,250 @@
+package usermemory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/oceanbase/powermem-go/pkg/llm"
+)
+
+// ProfileMergeMode controls how newly extracted profile information is
+// combined with the stored profile.
+type ProfileMergeMode string
+
+const (
+	// ProfileMergeRewrite asks the LLM to regenerate the whole profile text
+	// on every update (default).
+	ProfileMergeRewrite ProfileMergeMode = "rewrite"
+
+	// ProfileMergeStructured asks the LLM only for changed fields and merges
+	// them into the stored structured profile (UserProfile.Topics). The
+	// profile content is rendered from the merged fields, so unchanged fields
+	// never drift and long profiles are not truncated.
+	ProfileMergeStructured ProfileMergeMode = "structured"
+)
+
+// ProfileFieldChange describes a single field changed by a structured merge.
+type ProfileFieldChange struct {
+	// Field is the profile field name.
+	Field string `json:"field"`
+
+	// OldValue is the previous value (nil if the field was added).
+	OldValue interface{} `json:"old_value,omitempty"`
+
+	// NewValue is the merged value (nil if the field was removed).
+	NewValue interface{} `json:"new_value,omitempty"`
+}
+
+// profileFieldDelta is the LLM response for a structured profile merge.
+type profileFieldDelta struct {
+	Set    map[string]interface{} `json:"set"`
+	Remove []string               `json:"remove"`
+}
+
+// extractProfileFields extracts changed profile fields from messages and
+// merges them into the stored fields.
+//
+// Returns the merged fields and the list of changes. An empty change list
+// means nothing needs to be saved.
+func (c *Client) extractProfileFields(ctx context.Context, messages interface{}, userID string) (map[string]interface{}, []ProfileFieldChange, error) {
+	// Format conversation text
+	conversationText := c.formatMessages(messages)
+	if conversationText == "" {
+		return nil, nil, nil
+	}
+
+	// Get existing fields
+	existingProfile, _ := c.profileStore.GetProfileByUserID(ctx, userID)
+	existing := make(map[string]interface{})
+	var existingContent string
+	if existingProfile != nil {
+		for k, v := range existingProfile.Topics {
+			existing[k] = v
+		}
+		existingContent = existingProfile.ProfileContent
+	}
+
+	// Build prompt
+	userMessage, err := buildProfileFieldsUserMessage(conversationText, existing, existingContent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Call LLM
+	response, err := c.profileLLM().GenerateWithMessages(ctx, []llm.Message{
+		{Role: "system", Content: getProfileFieldsExtractionPrompt()},
+		{Role: "user", Content: userMessage},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate profile fields: %w", err)
+	}
+
+	delta, err := parseProfileFieldDelta(response)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged, changes := mergeProfileFields(existing, delta)
+	return merged, changes, nil
+}
+
+// parseProfileFieldDelta parses the LLM response for a structured merge.
+func parseProfileFieldDelta(response string) (*profileFieldDelta, error) {
+	// Remove ```json and ``` markers
+	response = strings.ReplaceAll(response, "```json", "")
+	response = strings.ReplaceAll(response, "```", "")
+	response = strings.TrimSpace(response)
+	if response == "" {
+		return &profileFieldDelta{}, nil
+	}
+
+	var delta profileFieldDelta
+	if err := json.Unmarshal([]byte(response), &delta); err != nil {
+		return nil, fmt.Errorf("failed to parse profile fields: %w", err)
+	}
+	return &delta, nil
+}
+
+// mergeProfileFields applies delta to existing and returns the merged fields
+// together with the changes made.
+//
+// List values are merged as a union (existing order first); all other values
+// overwrite the stored value. Fields listed in delta.Remove are deleted.
+func mergeProfileFields(existing map[string]interface{}, delta *profileFieldDelta) (map[string]interface{}, []ProfileFieldChange) {
+	merged := make(map[string]interface{}, len(existing)+len(delta.Set))
+	for k, v := range existing {
+		merged[k] = v
+	}
+
+	var changes []ProfileFieldChange
+	for _, field := range delta.Remove {
+		old, ok := merged[field]
+		if !ok {
+			continue
+		}
+		delete(merged, field)
+		changes = append(changes, ProfileFieldChange{Field: field, OldValue: old})
+	}
+
+	for field, value := range delta.Set {
+		field = strings.TrimSpace(field)
+		if field == "" || value == nil {
+			continue
+		}
+		old, ok := merged[field]
+		if newList, isList := value.([]interface{}); isList && ok {
+			if oldList, wasList := old.([]interface{}); wasList {
+				value = unionProfileValues(oldList, newList)
+			}
+		}
+		if ok && reflect.DeepEqual(old, value) {
+			continue
+		}
+		merged[field] = value
+		changes = append(changes, ProfileFieldChange{Field: field, OldValue: old, NewValue: value})
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return merged, changes
+}
+
+// unionProfileValues returns a followed by the elements of b not already in a.
+func unionProfileValues(a, b []interface{}) []interface{} {
+	seen := make(map[string]bool, len(a)+len(b))
+	result := make([]interface{}, 0, len(a)+len(b))
+	for _, list := range [][]interface{}{a, b} {
+		for _, v := range list {
+			key := fmt.Sprint(v)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// renderProfileFields renders fields as "field: value" lines sorted by field.
+func renderProfileFields(fields map[string]interface{}) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("%s: %s", k, formatProfileValue(fields[k])))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatProfileValue formats a field value for rendering.
+func formatProfileValue(value interface{}) string {
+	switch v := value.(type) {
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = formatProfileValue(item)
+		}
+		return strings.Join(parts, ", ")
+	case map[string]interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// getProfileFieldsExtractionPrompt returns the system prompt for structured profile merges.
+func getProfileFieldsExtractionPrompt() string {
+	return `You are a user profile extraction specialist. Your task is to analyze conversations and report changes to a structured user profile.
+
+[Instructions]:
+1. Review the current profile fields if provided below
+2. Analyze the new conversation carefully to identify any new or updated user-related information
+3. Extract only factual information explicitly mentioned in the conversation
+4. Return ONLY the fields that are new or whose value changed; do not repeat unchanged fields
+5. Reuse an existing field name when the information belongs to it; name new fields in snake_case
+6. Use a JSON array for fields that hold several values (e.g. hobbies); new items are added to the stored list
+7. List a field under "remove" only if the conversation states that it is no longer true
+8. If nothing changed, return {"set": {}, "remove": []}
+
+Return a JSON object of the form:
+{"set": {"field_name": "value"}, "remove": ["field_name"]}`
+}
+
+// buildProfileFieldsUserMessage builds the user message for structured profile merges.
+func buildProfileFieldsUserMessage(conversationText string, fields map[string]interface{}, existingContent string) (string, error) {
+	if len(fields) > 0 {
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal profile fields: %w", err)
+		}
+		return fmt.Sprintf(`Current profile fields:
+%s
+
+New conversation:
+%s
+
+Please report the profile fields changed by the new conversation.`, data, conversationText), nil
+	}
+	if existingContent != "" {
+		return fmt.Sprintf(`Current user profile:
+%s
+
+New conversation:
+%s
+
+Please convert the current profile and the new conversation into profile fields.`, existingContent, conversationText), nil
+	}
+	return fmt.Sprintf(`New conversation:
+%s
+
+Please extract user profile fields from this conversation.`, conversationText), nil
+}
@@ -0,0 +1,161 @@
+package usermemory
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Defaults for asynchronous profile extraction.
+const (
+	defaultProfileQueueSize    = 100
+	defaultProfileMaxRetries   = 3
+	defaultProfileRetryBackoff = 500 * time.Millisecond
+)
+
+// profileJob is a queued profile extraction request.
+type profileJob struct {
+	messages interface{}
+	opts     *AddOptions
+}
+
+// profileWorker extracts and saves user profiles in a background goroutine.
+//
+// Jobs are processed one at a time, in submission order, so concurrent updates
+// to the same user's profile never race. Failed jobs are retried with
+// exponential backoff.
+type profileWorker struct {
+	// client performs the profile extraction.
+	client *Client
+
+	// jobs is the queue of pending extractions.
+	jobs chan profileJob
+
+	// pending tracks queued and running jobs.
+	pending sync.WaitGroup
+
+	// done is closed when the worker goroutine exits.
+	done chan struct{}
+
+	// maxRetries is the number of retries after the first failed attempt.
+	maxRetries int
+
+	// retryBackoff is the delay before the first retry; it doubles on each retry.
+	retryBackoff time.Duration
+
+	// mu guards closed and sends on jobs.
+	mu sync.RWMutex
+
+	// closed indicates that the worker no longer accepts jobs.
+	closed bool
+}
+
+// newProfileWorker creates and starts a profile worker.
+func newProfileWorker(client *Client, queueSize, maxRetries int, retryBackoff time.Duration) *profileWorker {
+	if queueSize <= 0 {
+		queueSize = defaultProfileQueueSize
+	}
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	if retryBackoff <= 0 {
+		retryBackoff = defaultProfileRetryBackoff
+	}
+
+	w := &profileWorker{
+		client:       client,
+		jobs:         make(chan profileJob, queueSize),
+		done:         make(chan struct{}),
+		maxRetries:   maxRetries,
+		retryBackoff: retryBackoff,
+	}
+	go w.run()
+	return w
+}
+
+// enqueue queues a profile extraction, blocking while the queue is full.
+func (w *profileWorker) enqueue(ctx context.Context, messages interface{}, opts *AddOptions) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return fmt.Errorf("profile worker is closed")
+	}
+
+	w.pending.Add(1)
+	select {
+	case w.jobs <- profileJob{messages: messages, opts: opts}:
+		return nil
+	case <-ctx.Done():
+		w.pending.Done()
+		return ctx.Err()
+	}
+}
+
+// run processes jobs until the queue is closed and drained.
+func (w *profileWorker) run() {
+	defer close(w.done)
+	for job := range w.jobs {
+		w.process(job)
+		w.pending.Done()
+	}
+}
+
+// process runs a single job with retries.
+//
+// Jobs run with a background context because the request that queued them has
+// usually completed by the time they execute.
+func (w *profileWorker) process(job profileJob) {
+	ctx := context.Background()
+	backoff := w.retryBackoff
+
+	var err error
+	for attempt := 0; attempt <= w.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		if _, err = w.client.updateProfile(ctx, job.messages, job.opts); err == nil {
+			return
+		}
+		log.Printf("Profile extraction for user %s failed (attempt %d/%d): %v", job.opts.UserID, attempt+1, w.maxRetries+1, err)
+	}
+	log.Printf("Giving up profile extraction for user %s: %v", job.opts.UserID, err)
+}
+
+// wait blocks until all queued jobs have been processed.
+func (w *profileWorker) wait() {
+	w.pending.Wait()
+}
+
+// close stops accepting jobs and waits for queued jobs to finish.
+func (w *profileWorker) close() {
+	w.stop()
+	<-w.done
+}
+
+// shutdown stops accepting jobs and waits for queued jobs to finish or ctx to
+// be done, whichever happens first.
+func (w *profileWorker) shutdown(ctx context.Context) error {
+	w.stop()
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stop stops accepting jobs. Queued jobs are still processed.
+func (w *profileWorker) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return
+	}
+	w.closed = true
+	close(w.jobs)
+}
@@ -0,0 +1,118 @@
+package query_rewrite
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheKey builds the cache key for a rewrite. The profile is hashed so that
+// a profile update invalidates cached rewrites for the user.
+func cacheKey(userID, query, profileContent string) string {
+	sum := sha256.Sum256([]byte(profileContent))
+	return userID + "\x00" + query + "\x00" + hex.EncodeToString(sum[:])
+}
+
+// rewriteCache is a fixed-size LRU cache of rewritten queries.
+type rewriteCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// cacheEntry is a single cached rewrite.
+type cacheEntry struct {
+	key       string
+	rewritten string
+}
+
+// newRewriteCache creates a cache holding at most size entries.
+func newRewriteCache(size int) *rewriteCache {
+	return &rewriteCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// get returns the cached rewrite for key and marks it as recently used.
+func (c *rewriteCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).rewritten, true
+}
+
+// add stores a rewrite, evicting the least recently used entry if full.
+func (c *rewriteCache) add(key, rewritten string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*cacheEntry).rewritten = rewritten
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, rewritten: rewritten})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// removeUser removes the cached rewrites of a user and returns their number.
+func (c *rewriteCache) removeUser(userID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prefix := userID + "\x00"
+	removed := 0
+	for key, elem := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.order.Remove(elem)
+			delete(c.entries, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// rewriteBudget allows at most limit calls within a sliding window.
+type rewriteBudget struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	calls  []time.Time
+}
+
+// newRewriteBudget creates a budget of limit calls per window.
+func newRewriteBudget(limit int, window time.Duration) *rewriteBudget {
+	return &rewriteBudget{limit: limit, window: window}
+}
+
+// allow records a call at now and reports whether it fits in the budget.
+func (b *rewriteBudget) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Drop calls that left the window
+	cutoff := now.Add(-b.window)
+	i := 0
+	for i < len(b.calls) && !b.calls[i].After(cutoff) {
+		i++
+	}
+	b.calls = b.calls[i:]
+
+	if len(b.calls) >= b.limit {
+		return false
+	}
+	b.calls = append(b.calls, now)
+	return true
+}
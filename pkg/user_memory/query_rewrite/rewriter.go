@@ -46,6 +46,18 @@ type Config struct {
 	// ModelOverride is an optional LLM model override for rewriting.
 	// If empty, uses the default LLM from the client.
 	ModelOverride string
+
+	// CacheSize is the number of rewrites kept in an LRU cache keyed by
+	// (user ID, query, profile hash). Zero disables caching.
+	CacheSize int
+
+	// MaxRewritesPerMinute caps the number of LLM rewrite calls per minute.
+	// Queries over budget are searched unchanged. Zero means unlimited.
+	MaxRewritesPerMinute int
+
+	// Timeout bounds the LLM rewrite call. If it expires, the original query
+	// is used. Zero means no timeout beyond the caller's context.
+	Timeout time.Duration
 }
 
 // QueryRewriter rewrites queries based on user profiles.
@@ -58,6 +70,12 @@ type QueryRewriter struct {
 
 	// config contains the query rewrite configuration.
 	config *Config
+
+	// cache holds recent rewrites (nil if caching is disabled).
+	cache *rewriteCache
+
+	// budget limits LLM calls per minute (nil if unlimited).
+	budget *rewriteBudget
 }
 
 // NewQueryRewriter creates a new QueryRewriter instance.
@@ -68,10 +86,17 @@ type QueryRewriter struct {
 //
 // Returns a new QueryRewriter instance.
 func NewQueryRewriter(llm llm.Provider, config *Config) *QueryRewriter {
-	return &QueryRewriter{
+	r := &QueryRewriter{
 		llm:    llm,
 		config: config,
 	}
+	if config.CacheSize > 0 {
+		r.cache = newRewriteCache(config.CacheSize)
+	}
+	if config.MaxRewritesPerMinute > 0 {
+		r.budget = newRewriteBudget(config.MaxRewritesPerMinute, time.Minute)
+	}
+	return r
 }
 
 // Rewrite rewrites a query based on user profile content.
@@ -89,6 +114,25 @@ func NewQueryRewriter(llm llm.Provider, config *Config) *QueryRewriter {
 //
 // Returns the rewrite result containing original and rewritten queries.
 func (r *QueryRewriter) Rewrite(ctx context.Context, query string, profileContent string) *QueryRewriteResult {
+	return r.RewriteForUser(ctx, "", query, profileContent)
+}
+
+// RewriteForUser rewrites a query based on the profile content of userID.
+//
+// In addition to Rewrite, the method:
+//   - Serves repeated (userID, query, profile) combinations from the cache
+//   - Skips the LLM call when the per-minute budget is exhausted
+//   - Falls back to the original query when the LLM call exceeds Timeout
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userID: User identifier (used as part of the cache key)
+//   - query: Original query string
+//   - profileContent: User profile text (optional)
+//
+// Returns the rewrite result containing original and rewritten queries.
+// Metadata contains "cache_hit", "budget_exceeded" or "timed_out" when applicable.
+func (r *QueryRewriter) RewriteForUser(ctx context.Context, userID, query, profileContent string) *QueryRewriteResult {
 	// Skip if no user profile
 	if profileContent == "" || strings.TrimSpace(profileContent) == "" {
 		return &QueryRewriteResult{
@@ -110,6 +154,36 @@ func (r *QueryRewriter) Rewrite(ctx context.Context, query string, profileConten
 		}
 	}
 
+	// Serve from cache
+	key := cacheKey(userID, trimmedQuery, profileContent)
+	if r.cache != nil {
+		if rewritten, ok := r.cache.get(key); ok {
+			return &QueryRewriteResult{
+				OriginalQuery:  query,
+				RewrittenQuery: rewritten,
+				IsRewritten:    rewritten != trimmedQuery,
+				ProfileUsed:    &profileContent,
+				Metadata:       map[string]interface{}{"cache_hit": true},
+			}
+		}
+	}
+
+	// Enforce budget
+	if r.budget != nil && !r.budget.allow(time.Now()) {
+		return &QueryRewriteResult{
+			OriginalQuery:  query,
+			RewrittenQuery: query,
+			IsRewritten:    false,
+			Metadata:       map[string]interface{}{"budget_exceeded": true},
+		}
+	}
+
+	if r.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.config.Timeout)
+		defer cancel()
+	}
+
 	startTime := time.Now()
 
 	// Build prompt
@@ -124,14 +198,18 @@ func (r *QueryRewriter) Rewrite(ctx context.Context, query string, profileConten
 	response, err := r.llm.GenerateWithMessages(ctx, messages)
 	if err != nil {
 		errorMsg := err.Error()
+		metadata := map[string]interface{}{
+			"rewrite_time_seconds": time.Since(startTime).Seconds(),
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			metadata["timed_out"] = true
+		}
 		return &QueryRewriteResult{
 			OriginalQuery:  query,
 			RewrittenQuery: query,
 			IsRewritten:    false,
 			Error:          &errorMsg,
-			Metadata: map[string]interface{}{
-				"rewrite_time_seconds": time.Since(startTime).Seconds(),
-			},
+			Metadata:       metadata,
 		}
 	}
 
@@ -140,6 +218,12 @@ func (r *QueryRewriter) Rewrite(ctx context.Context, query string, profileConten
 
 	// If rewritten query is empty or same as original, mark as not rewritten
 	isRewritten := rewritten != "" && rewritten != trimmedQuery
+	if rewritten == "" {
+		rewritten = trimmedQuery
+	}
+	if r.cache != nil {
+		r.cache.add(key, rewritten)
+	}
 
 	return &QueryRewriteResult{
 		OriginalQuery:  query,
@@ -151,3 +235,14 @@ func (r *QueryRewriter) Rewrite(ctx context.Context, query string, profileConten
 		},
 	}
 }
+
+// ForgetUser removes the cached rewrites of a user, whose queries they
+// contain.
+//
+// Returns the number of removed rewrites.
+func (r *QueryRewriter) ForgetUser(userID string) int {
+	if r.cache == nil {
+		return 0
+	}
+	return r.cache.removeUser(userID)
+}
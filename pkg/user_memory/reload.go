@@ -0,0 +1,207 @@
+package usermemory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/llm"
+	"github.com/oceanbase/powermem-go/pkg/user_memory/query_rewrite"
+)
+
+// Reload applies a new configuration to a running client without recreating
+// it, so that in-flight operations and streams keep running.
+//
+// MemoryConfig is applied with core.Client.Reload (LLM, intelligence and
+// agent memory settings). In addition, the profile extraction LLM, the
+// query rewrite settings (including MaxRewritesPerMinute) and
+// ProfileMergeMode can change. Rebuilding the query rewriter clears its
+// cache and rate limit window.
+//
+// Changes to the profile store or the background extraction settings
+// require a new client, as do the restart-only settings of core.Client.Reload.
+// Reload rejects them with a *core.ValidationError (matching
+// core.ErrInvalidConfig) and leaves the client unchanged.
+//
+// Example:
+//
+//	config.QueryRewriteConfig.MaxRewritesPerMinute = 30
+//	if err := client.Reload(ctx, config); err != nil {
+//	    log.Printf("config not applied: %v", err)
+//	}
+func (c *Client) Reload(ctx context.Context, cfg *Config) error {
+	// Only Reload writes the guarded fields, so they can be read without mu
+	// here; mu is held only to swap them, so that extraction and search are
+	// not blocked while the memory client waits for open streams.
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	if fields := restartRequiredFields(c.config, cfg); len(fields) > 0 {
+		return fmt.Errorf("Reload: %w", &core.ValidationError{Fields: fields})
+	}
+
+	profileMergeMode, err := resolveProfileMergeMode(cfg.ProfileMergeMode)
+	if err != nil {
+		return fmt.Errorf("Reload: %w", err)
+	}
+
+	// Build everything before applying anything so that a failure leaves the client unchanged
+	llmProvider := c.llm
+	llmChanged := !reflect.DeepEqual(c.config.MemoryConfig.LLM, cfg.MemoryConfig.LLM)
+	if llmChanged {
+		llmProvider, err = initLLMFromConfig(cfg.MemoryConfig)
+		if err != nil {
+			return fmt.Errorf("Reload: failed to create LLM: %w", err)
+		}
+	}
+	queryRewriter := c.queryRewriter
+	if llmChanged || !reflect.DeepEqual(c.config.QueryRewriteConfig, cfg.QueryRewriteConfig) {
+		queryRewriter = newQueryRewriter(cfg, llmProvider)
+	}
+
+	if err := c.memory.Reload(ctx, cfg.MemoryConfig); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The previous LLM is not closed: a running extraction may still use it,
+	// and providers hold no resources of their own.
+	c.llm = llmProvider
+	c.queryRewriter = queryRewriter
+	c.profileMergeMode = profileMergeMode
+	c.config = cfg
+	return nil
+}
+
+// restartRequiredFields reports the UserMemory settings that differ between
+// current and next but cannot be changed by Reload.
+func restartRequiredFields(current, next *Config) []*core.FieldError {
+	var fields []*core.FieldError
+	restart := func(field string) {
+		fields = append(fields, &core.FieldError{
+			Field:   field,
+			Message: "cannot be changed without recreating the client",
+		})
+	}
+
+	if next.MemoryConfig == nil {
+		fields = append(fields, &core.FieldError{Field: "memory_config", Message: "is required"})
+	}
+	if !strings.EqualFold(current.ProfileStoreType, next.ProfileStoreType) ||
+		!reflect.DeepEqual(current.ProfileStoreConfig, next.ProfileStoreConfig) {
+		restart("user_memory.profile_store")
+	}
+	if current.AsyncProfileExtraction != next.AsyncProfileExtraction ||
+		current.ProfileQueueSize != next.ProfileQueueSize ||
+		current.ProfileMaxRetries != next.ProfileMaxRetries ||
+		current.ProfileRetryBackoff != next.ProfileRetryBackoff {
+		restart("user_memory.async_profile_extraction")
+	}
+	return fields
+}
+
+// WatchConfigFile watches a config file and applies its changes with Reload
+// in a background goroutine, until ctx is cancelled or the client is closed.
+//
+// The file is loaded with LoadConfigFromFile and checked every interval
+// (default 2s, see WithWatchInterval). An update that cannot be loaded or
+// applied is passed to the handler set with WithReloadErrorHandler, or
+// logged, and the client keeps its current configuration. See
+// core.Client.WatchConfigFile.
+//
+// Returns an error if the file cannot be read when the watch starts.
+//
+// Example:
+//
+//	err := client.WatchConfigFile(ctx, "powermem.yaml",
+//	    usermemory.WithReloadErrorHandler(func(err error) {
+//	        log.Printf("rejected config update: %v", err)
+//	    }),
+//	)
+func (c *Client) WatchConfigFile(ctx context.Context, path string, opts ...WatchOption) error {
+	watchOpts := applyWatchOptions(opts)
+
+	last, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("WatchConfigFile: %w", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(watchOpts.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				// The file may be briefly missing while an editor replaces it
+				if !os.IsNotExist(err) {
+					watchOpts.OnError(fmt.Errorf("WatchConfigFile: %w", err))
+				}
+				continue
+			}
+			if bytes.Equal(data, last) {
+				continue
+			}
+			last = data
+
+			cfg, err := LoadConfigFromFile(path)
+			if err == nil {
+				err = c.Reload(ctx, cfg)
+			}
+			if errors.Is(err, core.ErrClientClosed) {
+				return
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					watchOpts.OnError(err)
+				}
+				continue
+			}
+			if watchOpts.OnReload != nil {
+				watchOpts.OnReload(cfg)
+			}
+		}
+	}()
+	return nil
+}
+
+// logReloadError is the default WatchConfigFile error handler.
+func logReloadError(err error) {
+	log.Printf("Failed to reload user memory config: %v", err)
+}
+
+// profileLLM returns the LLM provider used for profile extraction.
+func (c *Client) profileLLM() llm.Provider {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.llm
+}
+
+// rewriter returns the query rewriter, or nil if query rewriting is disabled.
+func (c *Client) rewriter() *query_rewrite.QueryRewriter {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.queryRewriter
+}
+
+// mergeMode returns how extracted profile information is merged.
+func (c *Client) mergeMode() ProfileMergeMode {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.profileMergeMode
+}
@@ -0,0 +1,100 @@
+package usermemory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// UserReport is what the client knows about a user, as returned by
+// GenerateUserReport: the report of the memories with the user's profile.
+type UserReport struct {
+	// UserReport reports the user's memories (see
+	// core.Client.GenerateUserReport).
+	core.UserReport
+
+	// Profile is the user's profile (nil if the user has none).
+	Profile *UserProfile `json:"profile,omitempty"`
+}
+
+// GenerateUserReport summarizes what the client knows about a user, e.g. to
+// answer a "what do you know about me" request: the report of the user's
+// memories (see core.Client.GenerateUserReport) and their profile, with its
+// content and topics.
+//
+// Queued profile extractions are waited for first, so that the profile is
+// up to date.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userID: User of the report (required)
+//   - opts: Optional parameters (core.WithReportTopMemories, core.WithReportRecentChanges)
+//
+// Returns the report, or an error.
+//
+// Example:
+//
+//	report, err := client.GenerateUserReport(ctx, "user_001")
+//	if err != nil {
+//	    return err
+//	}
+//	data, _ := json.Marshal(report) // or report.Markdown()
+func (c *Client) GenerateUserReport(ctx context.Context, userID string, opts ...core.ReportOption) (*UserReport, error) {
+	c.WaitProfileExtraction()
+
+	memoryReport, err := c.memory.GenerateUserReport(ctx, userID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := c.profileStore.GetProfileByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	return &UserReport{UserReport: *memoryReport, Profile: profile}, nil
+}
+
+// Markdown renders the report as a Markdown document for the user, with the
+// profile after the memories.
+func (r *UserReport) Markdown() string {
+	var b strings.Builder
+	b.WriteString(r.UserReport.Markdown())
+	b.WriteString("\n## Profile\n\n")
+	if r.Profile == nil || (r.Profile.ProfileContent == "" && len(r.Profile.Topics) == 0) {
+		b.WriteString("No profile is stored.\n")
+		return b.String()
+	}
+	if r.Profile.ProfileContent != "" {
+		b.WriteString(strings.TrimSpace(r.Profile.ProfileContent))
+		b.WriteString("\n")
+	}
+	if len(r.Profile.Topics) > 0 {
+		if r.Profile.ProfileContent != "" {
+			b.WriteString("\n")
+		}
+		topics := make([]string, 0, len(r.Profile.Topics))
+		for topic := range r.Profile.Topics {
+			topics = append(topics, topic)
+		}
+		sort.Strings(topics)
+		for _, topic := range topics {
+			fmt.Fprintf(&b, "- %s: %s\n", topic, topicValue(r.Profile.Topics[topic]))
+		}
+	}
+	return b.String()
+}
+
+// topicValue formats the value of a profile topic on a single line.
+func topicValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
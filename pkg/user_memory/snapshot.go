@@ -0,0 +1,85 @@
+package usermemory
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// The files of a snapshot directory.
+const (
+	// SnapshotMemoriesFile is the snapshot of the memories (see core.Client.Snapshot).
+	SnapshotMemoriesFile = "memories.snapshot"
+
+	// SnapshotProfilesFile is the snapshot of the profiles.
+	SnapshotProfilesFile = "profiles.snapshot"
+)
+
+// Snapshot backs up the memories and the profiles to a new directory dir,
+// as SnapshotMemoriesFile and SnapshotProfilesFile.
+//
+// Queued profile extractions are waited for first. Each file is consistent
+// on its own; the profiles are read after the memories.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - dir: Directory the snapshot is written to (must not exist)
+//
+// Returns an error if dir exists or a snapshot fails.
+//
+// Example:
+//
+//	err := client.Snapshot(ctx, "backups/20260101")
+func (c *Client) Snapshot(ctx context.Context, dir string) error {
+	if dir == "" {
+		return fmt.Errorf("failed to snapshot: %w", core.ErrInvalidInput)
+	}
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to snapshot: %w", err)
+	}
+
+	c.WaitProfileExtraction()
+
+	if err := c.memory.Snapshot(ctx, filepath.Join(dir, SnapshotMemoriesFile)); err != nil {
+		return fmt.Errorf("failed to snapshot memories: %w", err)
+	}
+	if err := c.profileStore.Snapshot(ctx, filepath.Join(dir, SnapshotProfilesFile)); err != nil {
+		return fmt.Errorf("failed to snapshot profiles: %w", err)
+	}
+	return nil
+}
+
+// RestoreSnapshot replaces the memories and the profiles with those of the
+// snapshot directory dir written by Snapshot (see
+// core.Client.RestoreSnapshot).
+//
+// Queued profile extractions are waited for first, so they cannot update
+// the restored profiles afterwards.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - dir: Snapshot directory
+//
+// Returns an error if a restore fails.
+//
+// Example:
+//
+//	err := client.RestoreSnapshot(ctx, "backups/20260101")
+func (c *Client) RestoreSnapshot(ctx context.Context, dir string) error {
+	if dir == "" {
+		return fmt.Errorf("failed to restore snapshot: %w", core.ErrInvalidInput)
+	}
+
+	c.WaitProfileExtraction()
+
+	if err := c.memory.RestoreSnapshot(ctx, filepath.Join(dir, SnapshotMemoriesFile)); err != nil {
+		return fmt.Errorf("failed to restore memories: %w", err)
+	}
+	if err := c.profileStore.RestoreSnapshot(ctx, filepath.Join(dir, SnapshotProfilesFile)); err != nil {
+		return fmt.Errorf("failed to restore profiles: %w", err)
+	}
+	return nil
+}
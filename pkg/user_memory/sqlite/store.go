@@ -6,6 +6,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -23,10 +25,10 @@ type Store struct {
 // Config contains configuration for creating a SQLite UserProfileStore.
 type Config struct {
 	// DBPath is the path to the SQLite database file.
-	DBPath string
+	DBPath string `json:"db_path"`
 
 	// TableName is the name of the table to use (default: "user_profiles").
-	TableName string
+	TableName string `json:"table_name"`
 }
 
 // NewStore creates a new SQLite UserProfileStore.
@@ -276,6 +278,71 @@ func (s *Store) GetProfiles(ctx context.Context, opts *GetProfilesOptions) ([]*U
 	return profiles, nil
 }
 
+// GetProfilesByUserIDs retrieves the profiles of several users in a single query.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - userIDs: User identifiers
+//
+// Returns the profiles found, in no particular order. Users without a
+// profile are omitted.
+func (s *Store) GetProfilesByUserIDs(ctx context.Context, userIDs []string) ([]*UserProfile, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(userIDs))
+	args := make([]interface{}, len(userIDs))
+	for i, userID := range userIDs {
+		placeholders[i] = "?"
+		args[i] = userID
+	}
+	query := fmt.Sprintf(`
+		SELECT id, user_id, profile_content, topics, created_at, updated_at
+		FROM %s
+		WHERE user_id IN (%s)
+	`, s.tableName, strings.Join(placeholders, ", "))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query profiles: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var profiles []*UserProfile
+	for rows.Next() {
+		var profile UserProfile
+		var topicsJSON sql.NullString
+
+		err := rows.Scan(
+			&profile.ID,
+			&profile.UserID,
+			&profile.ProfileContent,
+			&topicsJSON,
+			&profile.CreatedAt,
+			&profile.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan profile: %w", err)
+		}
+
+		// Parse topics JSON
+		if topicsJSON.Valid && topicsJSON.String != "" {
+			if err := json.Unmarshal([]byte(topicsJSON.String), &profile.Topics); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal topics: %w", err)
+			}
+		}
+
+		profiles = append(profiles, &profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return profiles, nil
+}
+
 // DeleteProfile deletes a user profile by profile ID.
 //
 // Parameters:
@@ -302,6 +369,104 @@ func (s *Store) DeleteProfile(ctx context.Context, profileID int64) error {
 	return nil
 }
 
+// DeleteAllProfiles deletes all profiles matching filter in a single statement.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - filter: Profiles to delete (nil or empty UserIDs deletes all profiles)
+//
+// Returns the number of deleted profiles.
+func (s *Store) DeleteAllProfiles(ctx context.Context, filter *ProfileFilter) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s", s.tableName)
+	var args []interface{}
+	if filter != nil && len(filter.UserIDs) > 0 {
+		placeholders := make([]string, len(filter.UserIDs))
+		args = make([]interface{}, len(filter.UserIDs))
+		for i, userID := range filter.UserIDs {
+			placeholders[i] = "?"
+			args[i] = userID
+		}
+		query += fmt.Sprintf(" WHERE user_id IN (%s)", strings.Join(placeholders, ", "))
+	}
+
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete profiles: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// Snapshot writes a copy of the database file to a new file at path with
+// VACUUM INTO, which reads the database in a single transaction.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - path: File the snapshot is written to (must not exist)
+//
+// Returns an error if path exists or the copy fails.
+func (s *Store) Snapshot(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("failed to snapshot profiles: %s: %w", path, os.ErrExist)
+	}
+	if _, err := s.db.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		return fmt.Errorf("failed to snapshot profiles: %w", err)
+	}
+	return nil
+}
+
+// RestoreSnapshot replaces the profiles with those of the snapshot at path
+// in a single transaction.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - path: Snapshot written by Snapshot
+//
+// Returns an error if the snapshot has no profile table or the restore fails.
+func (s *Store) RestoreSnapshot(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("failed to restore profiles: %w", err)
+	}
+
+	// ATTACH applies to a connection, so the restore keeps one
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to restore profiles: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS snapshot", path); err != nil {
+		return fmt.Errorf("failed to restore profiles: %w", err)
+	}
+	defer func() { _, _ = conn.ExecContext(context.Background(), "DETACH DATABASE snapshot") }()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to restore profiles: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	columns := "id, user_id, profile_content, topics, created_at, updated_at"
+	queries := []string{
+		fmt.Sprintf("DELETE FROM main.%s", s.tableName),
+		fmt.Sprintf("INSERT INTO main.%s (%s) SELECT %s FROM snapshot.%s", s.tableName, columns, columns, s.tableName),
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to restore profiles: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to restore profiles: %w", err)
+	}
+	return nil
+}
+
 // Close closes the database connection.
 func (s *Store) Close() error {
 	if s.db != nil {
@@ -52,3 +52,12 @@ type GetProfilesOptions struct {
 	// Offset sets the number of results to skip (for pagination).
 	Offset int
 }
+
+// ProfileFilter selects the profiles removed by DeleteAllProfiles.
+//
+// This type is defined in the sqlite package to avoid circular dependencies.
+type ProfileFilter struct {
+	// UserIDs restricts deletion to the profiles of these users.
+	// If empty, all profiles are deleted.
+	UserIDs []string
+}
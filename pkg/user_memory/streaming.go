@@ -0,0 +1,121 @@
+package usermemory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// SearchStream searches memories and streams results in batches.
+//
+// The query is rewritten based on the user profile (if query rewrite is enabled)
+// once, before streaming starts. AddProfile is ignored; use GetProfile to fetch
+// the profile alongside the stream.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - query: Search query string
+//   - batchSize: Number of results per batch (default: 10)
+//   - opts: Optional parameters (UserID, AgentID, Limit)
+//
+// Returns a channel that streams search results in batches.
+//
+// Example:
+//
+//	for result := range client.SearchStream(ctx, "preferences", 20, usermemory.WithSearchUserID("user_001")) {
+//	    if result.Error != nil {
+//	        log.Fatal(result.Error)
+//	    }
+//	    fmt.Printf("Batch of %d memories\n", len(result.Memories))
+//	}
+func (c *Client) SearchStream(ctx context.Context, query string, batchSize int, opts ...SearchOption) <-chan *core.StreamingSearchResult {
+	searchOpts := applySearchOptions(opts)
+	effectiveQuery, _ := c.rewriteQuery(ctx, query, searchOpts)
+	return c.memory.SearchStream(ctx, effectiveQuery, batchSize, c.coreSearchOptions(searchOpts)...)
+}
+
+// GetAllStream retrieves memories and streams them in batches.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - batchSize: Number of memories per batch (default: 100)
+//   - opts: Optional parameters (UserID, AgentID, Limit, Offset)
+//
+// Returns a channel that streams memories in batches.
+func (c *Client) GetAllStream(ctx context.Context, batchSize int, opts ...GetAllOption) <-chan *core.StreamingGetAllResult {
+	return c.memory.GetAllStream(ctx, batchSize, c.coreGetAllOptions(applyGetAllOptions(opts))...)
+}
+
+// BatchAdd adds multiple conversations and updates the user profile once for the whole batch.
+//
+// Each conversation is stored as a separate memory using the core BatchAdd.
+// Instead of running profile extraction per conversation, the (role-filtered)
+// conversations are combined and the profile is extracted and saved a single time,
+// which keeps LLM usage constant per batch.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - conversations: Conversations to add (each a string, []map[string]interface{}, or single map)
+//   - opts: Optional parameters applied to every conversation (UserID, AgentID, ProfileType, etc.)
+//
+// Returns a BatchAddResult containing the core batch result and profile extraction results.
+// The profile is not updated if no conversation could be stored.
+//
+// Example:
+//
+//	result, err := client.BatchAdd(ctx, []interface{}{
+//	    "I'm Alice, a software engineer.",
+//	    []map[string]interface{}{{"role": "user", "content": "I live in Paris."}},
+//	}, usermemory.WithUserID("user_001"))
+func (c *Client) BatchAdd(ctx context.Context, conversations []interface{}, opts ...AddOption) (*BatchAddResult, error) {
+	addOpts := applyAddOptions(opts)
+
+	// 1. Store conversation events (using Memory)
+	contents := make([]string, len(conversations))
+	for i, messages := range conversations {
+		contents[i] = c.formatMessages(messages)
+	}
+
+	batch, err := c.memory.BatchAdd(ctx, contents, c.coreAddOptions(addOpts)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add memories: %w", err)
+	}
+
+	result := &BatchAddResult{Batch: batch}
+	if batch.CreatedCount == 0 {
+		return result, nil
+	}
+
+	// 2. Extract and save user profile once for the whole batch
+	var parts []string
+	for _, messages := range conversations {
+		filtered := c.filterMessagesByRoles(messages, addOpts.IncludeRoles, addOpts.ExcludeRoles)
+		if text := c.formatMessages(filtered); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	if len(parts) == 0 {
+		return result, nil
+	}
+
+	if c.profileWorker != nil {
+		if err := c.profileWorker.enqueue(ctx, strings.Join(parts, "\n\n"), addOpts); err != nil {
+			return nil, fmt.Errorf("failed to queue profile extraction: %w", err)
+		}
+		result.ProfilePending = true
+		return result, nil
+	}
+
+	update, err := c.updateProfile(ctx, strings.Join(parts, "\n\n"), addOpts)
+	if err != nil {
+		return nil, err
+	}
+	result.ProfileExtracted = update.extracted
+	result.ProfileContent = update.content
+	result.Topics = update.topics
+	result.ProfileChanges = update.changes
+
+	return result, nil
+}
@@ -0,0 +1,271 @@
+// Package webhook delivers JSON notifications to HTTP endpoints.
+//
+// A Client queues payloads and POSTs them from a background goroutine, so
+// that senders are never blocked by a slow or unavailable endpoint. Failed
+// deliveries are retried with exponential backoff. If a secret is
+// configured, every request is signed with HMAC-SHA256 so that receivers can
+// verify where it came from (see Sign).
+//
+// core.Client uses this package to deliver the webhooks of Config.Webhooks.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Headers set on every delivery.
+const (
+	// EventHeader holds the event type given to Send.
+	EventHeader = "X-PowerMem-Event"
+
+	// SignatureHeader holds "sha256=" followed by the hex HMAC-SHA256 of the
+	// body, keyed with the secret (only set if a secret is configured).
+	SignatureHeader = "X-PowerMem-Signature"
+)
+
+// ErrQueueFull is returned by Send when the delivery queue is full. The
+// payload is dropped.
+var ErrQueueFull = errors.New("webhook queue is full")
+
+// ErrClosed is returned by Send after Close.
+var ErrClosed = errors.New("webhook client is closed")
+
+// Config contains configuration for a webhook Client.
+//
+// Fields:
+//   - URL: Endpoint receiving the POST requests (required, http or https)
+//   - Secret: Key used to sign the requests (optional)
+//   - Timeout: Timeout of one request (default: 10s)
+//   - MaxRetries: Retries after a failed delivery (default: 3, negative to disable)
+//   - RetryBackoff: Delay before the first retry, doubled for each retry (default: 1s)
+//   - QueueSize: Maximum number of pending deliveries (default: 1000)
+//   - HTTPClient: HTTP client used for the requests (optional)
+type Config struct {
+	URL          string
+	Secret       string
+	Timeout      time.Duration
+	MaxRetries   int
+	RetryBackoff time.Duration
+	QueueSize    int
+	HTTPClient   *http.Client
+}
+
+// delivery is a queued payload.
+type delivery struct {
+	event string
+	body  []byte
+}
+
+// Client delivers payloads to one endpoint.
+//
+// Deliveries are sent one at a time, in the order of Send.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+	queue      chan delivery
+
+	mu     sync.RWMutex
+	closed bool
+
+	// stop is closed when Close gives up waiting, to abort retries.
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// NewClient creates a webhook client and starts its delivery goroutine.
+//
+// Args:
+//   - cfg: webhook configuration
+//
+// Returns:
+//   - *Client: the client, to be closed with Close
+//   - error: if the URL is missing or invalid
+func NewClient(cfg *Config) (*Client, error) {
+	if cfg == nil || cfg.URL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+	endpoint, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q: want an http or https URL", cfg.URL)
+	}
+
+	config := *cfg
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 3
+	} else if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = time.Second
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1000
+	}
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	c := &Client{
+		config:     config,
+		httpClient: httpClient,
+		queue:      make(chan delivery, config.QueueSize),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go c.run()
+	return c, nil
+}
+
+// Send queues payload for delivery as JSON.
+//
+// Args:
+//   - event: event type, sent in the X-PowerMem-Event header
+//   - payload: value encoded as the request body
+//
+// Returns:
+//   - error: if payload cannot be encoded, the queue is full (ErrQueueFull)
+//     or the client is closed (ErrClosed)
+func (c *Client) Send(event string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return ErrClosed
+	}
+	select {
+	case c.queue <- delivery{event: event, body: body}:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting payloads and waits for the queued ones to be
+// delivered, until ctx is done. Deliveries still pending then are dropped.
+//
+// Calling Close more than once is safe.
+func (c *Client) Close(ctx context.Context) error {
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.queue)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		c.stopOnce.Do(func() { close(c.stop) })
+		<-c.done
+		return ctx.Err()
+	}
+}
+
+// run delivers the queued payloads until the queue is closed.
+func (c *Client) run() {
+	defer close(c.done)
+	for d := range c.queue {
+		select {
+		case <-c.stop:
+			continue
+		default:
+		}
+		if err := c.deliver(d); err != nil {
+			log.Printf("Webhook delivery to %s failed: %v", c.config.URL, err)
+		}
+	}
+}
+
+// deliver posts d, retrying failed attempts.
+func (c *Client) deliver(d delivery) error {
+	backoff := c.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := c.post(d)
+		if err == nil || !retry || attempt >= c.config.MaxRetries {
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-c.stop:
+			return err
+		}
+	}
+}
+
+// post makes one delivery attempt. It reports whether a failure is worth
+// retrying: network errors, 429 and 5xx responses are, other statuses are not.
+func (c *Client) post(d delivery) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.URL, bytes.NewReader(d.body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "powermem-go")
+	req.Header.Set(EventHeader, d.event)
+	if c.config.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(c.config.Secret, d.body))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("unexpected status %s", resp.Status)
+	default:
+		return false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+}
+
+// Sign returns the value of the X-PowerMem-Signature header for body.
+//
+// Receivers verify a delivery by computing Sign over the raw request body
+// with the shared secret and comparing it to the header with hmac.Equal.
+//
+// Example:
+//
+//	body, _ := io.ReadAll(r.Body)
+//	expected := webhook.Sign(secret, body)
+//	if !hmac.Equal([]byte(expected), []byte(r.Header.Get(webhook.SignatureHeader))) {
+//	    http.Error(w, "invalid signature", http.StatusUnauthorized)
+//	    return
+//	}
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
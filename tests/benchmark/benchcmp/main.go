@@ -0,0 +1,153 @@
+// Command benchcmp compares two outputs of "go test -bench" and fails if a
+// benchmark got slower than a threshold.
+//
+// Usage:
+//
+//	benchcmp [-threshold 10] baseline.txt current.txt
+//
+// The median of the runs of each benchmark (see "go test -count") is
+// compared for every metric, and the ns/op regressions above the threshold
+// percentage make benchcmp exit with status 1. "make bench-compare" runs it
+// against the results saved by "make bench-baseline".
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// procsSuffix is the GOMAXPROCS suffix of benchmark names, e.g. "-8".
+var procsSuffix = regexp.MustCompile(`-\d+$`)
+
+// results are the values of each metric of each benchmark.
+type results map[string]map[string][]float64
+
+func main() {
+	threshold := flag.Float64("threshold", 10, "maximum ns/op regression, in percent")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: benchcmp [-threshold percent] baseline.txt current.txt")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	baseline, err := parseFile(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "benchcmp:", err)
+		os.Exit(2)
+	}
+	current, err := parseFile(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "benchcmp:", err)
+		os.Exit(2)
+	}
+
+	if regressions := compare(os.Stdout, baseline, current, *threshold); regressions > 0 {
+		fmt.Printf("\n%d benchmark(s) regressed by more than %.0f%%\n", regressions, *threshold)
+		os.Exit(1)
+	}
+}
+
+// parseFile reads the benchmark results of a "go test -bench" output.
+func parseFile(path string) (results, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	parsed := make(results)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// Name, iterations, then value and unit pairs
+		if len(fields) < 4 || len(fields)%2 != 0 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		name := procsSuffix.ReplaceAllString(fields[0], "")
+		if parsed[name] == nil {
+			parsed[name] = make(map[string][]float64)
+		}
+		for i := 2; i < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				continue
+			}
+			parsed[name][fields[i+1]] = append(parsed[name][fields[i+1]], value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(parsed) == 0 {
+		return nil, fmt.Errorf("no benchmark results in %s", path)
+	}
+	return parsed, nil
+}
+
+// compare prints the medians of baseline and current side by side, and
+// returns the number of ns/op regressions above threshold percent.
+func compare(out *os.File, baseline, current results, threshold float64) int {
+	names := make([]string, 0, len(current))
+	for name := range current {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "benchmark\tmetric\tbaseline\tcurrent\tdelta\t")
+	regressions := 0
+	for _, name := range names {
+		units := make([]string, 0, len(current[name]))
+		for unit := range current[name] {
+			units = append(units, unit)
+		}
+		sort.Strings(units)
+
+		for _, unit := range units {
+			now := median(current[name][unit])
+			before, ok := baseline[name][unit]
+			if !ok {
+				fmt.Fprintf(w, "%s\t%s\t-\t%.4g\tnew\t\n", name, unit, now)
+				continue
+			}
+			was := median(before)
+			delta := 0.0
+			if was != 0 {
+				delta = (now - was) / was * 100
+			}
+			mark := ""
+			if unit == "ns/op" && delta > threshold {
+				mark = " !"
+				regressions++
+			}
+			fmt.Fprintf(w, "%s\t%s\t%.4g\t%.4g\t%+.1f%%%s\t\n", name, unit, was, now, delta, mark)
+		}
+	}
+	_ = w.Flush()
+	return regressions
+}
+
+// median returns the median of values, which is not empty.
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
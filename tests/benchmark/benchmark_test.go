@@ -0,0 +1,404 @@
+// Package benchmark_test measures Add, BatchAdd and Search on every
+// available backend, over synthetic datasets of 10k, 100k or 1M memories.
+//
+// Datasets are seeded once through the storage clients and kept between
+// runs (SQLite files in POWERMEM_BENCH_DIR, tables in the databases), so
+// only the first run on a machine pays for seeding. Use "make bench",
+// "make bench-baseline" and "make bench-compare" rather than running the
+// benchmarks by hand; see docs/api.md#benchmarks.
+package benchmark_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	embedderMock "github.com/oceanbase/powermem-go/pkg/embedder/mock"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	oceanbaseStore "github.com/oceanbase/powermem-go/pkg/storage/oceanbase"
+	postgresStore "github.com/oceanbase/powermem-go/pkg/storage/postgres"
+	sqliteStore "github.com/oceanbase/powermem-go/pkg/storage/sqlite"
+)
+
+const (
+	// benchDims is the dimension of the embeddings of every dataset.
+	benchDims = 256
+
+	// seedUser owns the memories of the datasets; writerUser owns the
+	// memories added by the benchmarks, which are deleted afterwards.
+	seedUser   = "bench_seed"
+	writerUser = "bench_writer"
+
+	// batchSize is the number of memories added per BatchAdd operation.
+	batchSize = 100
+
+	// seedWorkers is the number of concurrent inserts while seeding the
+	// server backends.
+	seedWorkers = 8
+)
+
+// vocabulary is the words of the synthetic memories and queries.
+var vocabulary = strings.Fields(`
+	hiking mountains coffee tea travel japan python golang rust meeting
+	project deadline family dinner music guitar piano running marathon
+	book novel science history budget finance savings doctor allergy
+	garden tomatoes cooking pasta weekend holiday flight hotel museum
+	email phone slack office remote manager team review release bug
+	birthday gift movie series podcast language spanish french camera
+`)
+
+// backend is a vector store the benchmarks run on.
+type backend struct {
+	name string
+
+	// vectorStore returns the config of the memories table named table.
+	vectorStore func(table string) core.VectorStoreConfig
+
+	// openSeeder opens a storage client on table for seeding.
+	openSeeder func(table string) (storage.VectorStore, error)
+
+	// seedWorkers is the number of concurrent inserts while seeding.
+	seedWorkers int
+}
+
+// backends returns the backends available in the environment: SQLite
+// always, PostgreSQL if POSTGRES_PASSWORD is set, and OceanBase if
+// OCEANBASE_PASSWORD or OCEANBASE_DSN is set.
+func backends() []backend {
+	_ = godotenv.Load(filepath.Join("..", "..", ".env"))
+
+	dir := os.Getenv("POWERMEM_BENCH_DIR")
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "powermem-bench")
+	}
+	available := []backend{{
+		name: "sqlite",
+		vectorStore: func(table string) core.VectorStoreConfig {
+			return core.VectorStoreConfig{SQLite: &core.SQLiteConfig{
+				DBPath:             filepath.Join(dir, table+".db"),
+				CollectionName:     "memories",
+				EmbeddingModelDims: benchDims,
+			}}
+		},
+		openSeeder: func(table string) (storage.VectorStore, error) {
+			return sqliteStore.NewClient(&sqliteStore.Config{
+				DBPath:             filepath.Join(dir, table+".db"),
+				CollectionName:     "memories",
+				EmbeddingModelDims: benchDims,
+				Synchronous:        "OFF",
+			})
+		},
+		seedWorkers: 1,
+	}}
+
+	if password := os.Getenv("POSTGRES_PASSWORD"); password != "" {
+		port, _ := strconv.Atoi(getEnv("POSTGRES_PORT", "5432"))
+		config := core.PostgresConfig{
+			Host:               getEnv("POSTGRES_HOST", "127.0.0.1"),
+			Port:               port,
+			User:               getEnv("POSTGRES_USER", "postgres"),
+			Password:           password,
+			DBName:             getEnv("POSTGRES_DATABASE", "powermem_test"),
+			EmbeddingModelDims: benchDims,
+			SSLMode:            "disable",
+		}
+		available = append(available, backend{
+			name: "postgres",
+			vectorStore: func(table string) core.VectorStoreConfig {
+				c := config
+				c.CollectionName = table
+				return core.VectorStoreConfig{Postgres: &c}
+			},
+			openSeeder: func(table string) (storage.VectorStore, error) {
+				return postgresStore.NewClient(&postgresStore.Config{
+					Host:               config.Host,
+					Port:               config.Port,
+					User:               config.User,
+					Password:           config.Password,
+					DBName:             config.DBName,
+					CollectionName:     table,
+					EmbeddingModelDims: benchDims,
+					SSLMode:            config.SSLMode,
+				})
+			},
+			seedWorkers: seedWorkers,
+		})
+	}
+
+	if password, dsn := os.Getenv("OCEANBASE_PASSWORD"), os.Getenv("OCEANBASE_DSN"); password != "" || dsn != "" {
+		port, _ := strconv.Atoi(getEnv("OCEANBASE_PORT", "2881"))
+		config := core.OceanBaseConfig{
+			Host:               getEnv("OCEANBASE_HOST", "127.0.0.1"),
+			Port:               port,
+			User:               getEnv("OCEANBASE_USER", "root@sys"),
+			Password:           password,
+			DBName:             getEnv("OCEANBASE_DATABASE", "powermem_test"),
+			EmbeddingModelDims: benchDims,
+			DSN:                dsn,
+		}
+		available = append(available, backend{
+			name: "oceanbase",
+			vectorStore: func(table string) core.VectorStoreConfig {
+				c := config
+				c.CollectionName = table
+				return core.VectorStoreConfig{OceanBase: &c}
+			},
+			openSeeder: func(table string) (storage.VectorStore, error) {
+				return oceanbaseStore.NewClient(&oceanbaseStore.Config{
+					Host:               config.Host,
+					Port:               config.Port,
+					User:               config.User,
+					Password:           config.Password,
+					DBName:             config.DBName,
+					CollectionName:     table,
+					EmbeddingModelDims: benchDims,
+					DSN:                config.DSN,
+				})
+			},
+			seedWorkers: seedWorkers,
+		})
+	}
+	return available
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// datasetSizes returns the dataset sizes of POWERMEM_BENCH_SIZES, a comma
+// separated list such as "10k,100k,1m". Default: 10k
+func datasetSizes(b *testing.B) []int {
+	var sizes []int
+	for _, field := range strings.Split(getEnv("POWERMEM_BENCH_SIZES", "10k"), ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		multiplier := 1
+		switch {
+		case strings.HasSuffix(field, "k"):
+			multiplier, field = 1000, strings.TrimSuffix(field, "k")
+		case strings.HasSuffix(field, "m"):
+			multiplier, field = 1000000, strings.TrimSuffix(field, "m")
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || n <= 0 {
+			b.Fatalf("invalid POWERMEM_BENCH_SIZES entry %q", field)
+		}
+		sizes = append(sizes, n*multiplier)
+	}
+	return sizes
+}
+
+// sizeLabel returns the short form of a dataset size, e.g. "100k".
+func sizeLabel(size int) string {
+	switch {
+	case size%1000000 == 0:
+		return fmt.Sprintf("%dm", size/1000000)
+	case size%1000 == 0:
+		return fmt.Sprintf("%dk", size/1000)
+	}
+	return strconv.Itoa(size)
+}
+
+// sentence returns synthetic memory content derived from seed.
+func sentence(seed int64, words int) string {
+	rng := rand.New(rand.NewSource(seed))
+	parts := make([]string, words)
+	for i := range parts {
+		parts[i] = vocabulary[rng.Intn(len(vocabulary))]
+	}
+	return strings.Join(parts, " ")
+}
+
+var (
+	clientsMu sync.Mutex
+	clients   = make(map[string]*core.Client)
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	for _, client := range clients {
+		_ = client.Close()
+	}
+	os.Exit(code)
+}
+
+// dataset returns a client on the dataset of size memories of be, seeding
+// it first if needed. Clients are shared by the benchmarks of a run.
+func dataset(b *testing.B, be backend, size int) *core.Client {
+	table := "bench_memories_" + sizeLabel(size)
+	key := be.name + "/" + table
+
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	if client, ok := clients[key]; ok {
+		return client
+	}
+
+	if err := seed(be, table, size); err != nil {
+		b.Skipf("Skipping %s: %v", key, err)
+	}
+	client, err := core.NewClient(&core.Config{
+		LLM:         core.LLMConfig{Provider: "mock"},
+		Embedder:    core.EmbedderConfig{Provider: "mock", Dimensions: benchDims},
+		VectorStore: be.vectorStore(table),
+	})
+	if err != nil {
+		b.Skipf("Skipping %s: %v", key, err)
+	}
+	clients[key] = client
+	return client
+}
+
+// seed inserts size synthetic memories into table, unless a previous run
+// did. A marker memory with ID size+1 is inserted last to record that the
+// dataset is complete.
+func seed(be backend, table string, size int) error {
+	store, err := be.openSeeder(table)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	ctx := context.Background()
+	marker := int64(size + 1)
+	if _, err := store.Get(ctx, marker, nil); err == nil {
+		return nil
+	}
+	// Start over after an interrupted seeding
+	if err := store.DeleteAll(ctx, &storage.DeleteAllOptions{UserID: seedUser}); err != nil {
+		return err
+	}
+
+	embedder, _ := embedderMock.NewClient(&embedderMock.Config{Dimensions: benchDims})
+	ids := make(chan int64)
+	errs := make(chan error, be.seedWorkers)
+	var wg sync.WaitGroup
+	for w := 0; w < be.seedWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				content := sentence(id, 8)
+				embedding, err := embedder.Embed(ctx, content)
+				if err == nil {
+					err = store.Insert(ctx, &storage.Memory{
+						ID:                id,
+						UserID:            seedUser,
+						Content:           content,
+						Embedding:         embedding,
+						RetentionStrength: 1,
+					})
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	started := time.Now()
+	var seedErr error
+	for id := int64(1); id <= marker && seedErr == nil; id++ {
+		select {
+		case ids <- id:
+		case seedErr = <-errs:
+		}
+	}
+	close(ids)
+	wg.Wait()
+	if seedErr == nil && len(errs) > 0 {
+		seedErr = <-errs
+	}
+	if seedErr != nil {
+		return fmt.Errorf("seeding %d memories: %w", size, seedErr)
+	}
+	fmt.Fprintf(os.Stderr, "seeded %s %s with %d memories in %v\n", be.name, table, size, time.Since(started).Round(time.Second))
+	return nil
+}
+
+// runDatasets runs fn on the dataset of every size on every backend, as
+// sub-benchmarks named <backend>/<size>.
+func runDatasets(b *testing.B, fn func(b *testing.B, client *core.Client)) {
+	for _, be := range backends() {
+		for _, size := range datasetSizes(b) {
+			be, size := be, size
+			b.Run(be.name+"/"+sizeLabel(size), func(b *testing.B) {
+				fn(b, dataset(b, be, size))
+			})
+		}
+	}
+}
+
+// cleanupWriter deletes the memories added by a benchmark.
+func cleanupWriter(b *testing.B, client *core.Client) {
+	b.StopTimer()
+	if err := client.DeleteAll(context.Background(), core.WithUserIDForDeleteAll(writerUser)); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkAdd(b *testing.B) {
+	runDatasets(b, func(b *testing.B, client *core.Client) {
+		ctx := context.Background()
+		defer cleanupWriter(b, client)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := client.Add(ctx, sentence(int64(-i-1), 8), core.WithUserID(writerUser), core.WithInfer(false))
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkBatchAdd(b *testing.B) {
+	runDatasets(b, func(b *testing.B, client *core.Client) {
+		ctx := context.Background()
+		defer cleanupWriter(b, client)
+		contents := make([]string, batchSize)
+		b.ReportAllocs()
+		b.ResetTimer()
+		started := time.Now()
+		for i := 0; i < b.N; i++ {
+			for j := range contents {
+				contents[j] = sentence(int64(-(i*batchSize + j + 1)), 8)
+			}
+			result, err := client.BatchAdd(ctx, contents, core.WithUserID(writerUser), core.WithInfer(false))
+			if err != nil {
+				b.Fatal(err)
+			}
+			if result.FailedCount > 0 {
+				b.Fatalf("%d memories failed: %v", result.FailedCount, result.Failed[0])
+			}
+		}
+		b.ReportMetric(float64(time.Since(started).Nanoseconds())/float64(b.N*batchSize), "ns/memory")
+	})
+}
+
+func BenchmarkSearch(b *testing.B) {
+	runDatasets(b, func(b *testing.B, client *core.Client) {
+		ctx := context.Background()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := client.Search(ctx, sentence(int64(i), 3), core.WithUserIDForSearch(seedUser), core.WithLimit(10))
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
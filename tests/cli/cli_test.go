@@ -0,0 +1,264 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/cli"
+	"github.com/oceanbase/powermem-go/pkg/core"
+	usermemorySQLite "github.com/oceanbase/powermem-go/pkg/user_memory/sqlite"
+)
+
+// writeConfig writes a config file using the mock providers and a SQLite
+// store in dir, and returns its path.
+func writeConfig(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name+".yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+vector_store:
+  provider: sqlite
+  config:
+    db_path: `+filepath.Join(dir, name+".db")+`
+    embedding_model_dims: 64
+llm:
+  provider: mock
+embedder:
+  provider: mock
+  dimensions: 64
+user_memory:
+  profile_store:
+    provider: sqlite
+    config:
+      db_path: `+filepath.Join(dir, name+"_profiles.db")+`
+`), 0o600))
+	return path
+}
+
+// run runs the tool with stdin and returns its stdout.
+func run(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	err := cli.Run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), err
+}
+
+// seed imports memories given as JSON lines. Importing them with a single
+// client keeps their IDs distinct.
+func seed(t *testing.T, config string, lines ...string) {
+	t.Helper()
+	_, err := run(t, strings.Join(lines, "\n"), "-config", config, "import")
+	require.NoError(t, err)
+}
+
+// mustRun runs the tool and fails the test if it fails.
+func mustRun(t *testing.T, args ...string) string {
+	t.Helper()
+	out, err := run(t, "", args...)
+	require.NoError(t, err)
+	return out
+}
+
+func TestRun_AddSearchGetDelete(t *testing.T) {
+	dir := t.TempDir()
+	config := writeConfig(t, dir, "memories")
+
+	var added core.Memory
+	require.NoError(t, json.Unmarshal([]byte(mustRun(t, "-config", config,
+		"add", "User loves hiking in the mountains", "-user", "user_001", "-tags", "outdoor,hobby", "-json")), &added))
+	assert.Equal(t, "user_001", added.UserID)
+	assert.Equal(t, []string{"outdoor", "hobby"}, added.Tags)
+
+	out, err := run(t, "User prefers email communication\n", "-config", writeConfig(t, dir, "stdin"), "add", "-user", "user_001", "-metadata", `{"source":"cli"}`)
+	require.NoError(t, err)
+	assert.Contains(t, out, "added memory ")
+
+	var results []*core.Memory
+	require.NoError(t, json.Unmarshal([]byte(mustRun(t, "-config", config,
+		"search", "-user", "user_001", "-json", "hiking", "mountains")), &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, "User loves hiking in the mountains", results[0].Content)
+
+	id := strconv.FormatInt(results[0].ID, 10)
+	out = mustRun(t, "-config", config, "get", "-user", "user_001", id)
+	assert.Contains(t, out, "User loves hiking in the mountains")
+
+	_, err = run(t, "", "-config", config, "get", "-user", "user_002", id)
+	assert.ErrorContains(t, err, "not found")
+
+	out = mustRun(t, "-config", config, "delete", id)
+	assert.Equal(t, "deleted memory "+id+"\n", out)
+	_, err = run(t, "", "-config", config, "get", id)
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestRun_ExportImportStats(t *testing.T) {
+	dir := t.TempDir()
+	source := writeConfig(t, dir, "source")
+	target := writeConfig(t, dir, "target")
+
+	seed(t, source,
+		`{"user_id":"user_001","content":"User loves hiking","tags":["outdoor"]}`,
+		`{"user_id":"user_001","agent_id":"agent_a","content":"User prefers tea"}`,
+		`{"user_id":"user_002","content":"User works in finance"}`,
+	)
+
+	exported := mustRun(t, "-config", source, "export", "-user", "user_001")
+	lines := strings.Split(strings.TrimSpace(exported), "\n")
+	require.Len(t, lines, 2)
+	var memory core.Memory
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &memory))
+	assert.Equal(t, "user_001", memory.UserID)
+	assert.Empty(t, memory.Embedding, "embeddings are only exported with -embeddings")
+	withEmbeddings := mustRun(t, "-config", source, "export", "-user", "user_001", "-embeddings")
+	require.NoError(t, json.Unmarshal([]byte(strings.SplitN(withEmbeddings, "\n", 2)[0]), &memory))
+	assert.NotEmpty(t, memory.Embedding)
+
+	out, err := run(t, exported, "-config", target, "import")
+	require.NoError(t, err)
+	assert.Equal(t, "imported 2 memories (0 expired skipped)\n", out)
+
+	// Importing again only adds the memories that are not there yet
+	out, err = run(t, exported, "-config", target, "import", "-skip-duplicates")
+	require.NoError(t, err)
+	assert.Equal(t, "imported 0 memories (0 expired, 2 duplicates skipped)\n", out)
+
+	var stats cli.Stats
+	require.NoError(t, json.Unmarshal([]byte(mustRun(t, "-config", target, "stats", "-json")), &stats))
+	assert.Equal(t, 2, stats.Total)
+	assert.Equal(t, map[string]int{"user_001": 2}, stats.ByUser)
+	assert.Equal(t, map[string]int{"": 1, "agent_a": 1}, stats.ByAgent)
+	assert.NotNil(t, stats.Oldest)
+
+	var imported []*core.Memory
+	require.NoError(t, json.Unmarshal([]byte(mustRun(t, "-config", target,
+		"search", "-user", "user_001", "-tags", "outdoor", "-json", "hiking")), &imported))
+	require.Len(t, imported, 1)
+	assert.Equal(t, "User loves hiking", imported[0].Content)
+
+	out = mustRun(t, "-config", source, "stats")
+	assert.Contains(t, out, "Memories:  3")
+	assert.Contains(t, out, "user_001  2")
+}
+
+func TestRun_Migrate(t *testing.T) {
+	dir := t.TempDir()
+	source := writeConfig(t, dir, "source")
+	target := writeConfig(t, dir, "target")
+
+	seed(t, source,
+		`{"user_id":"user_001","content":"User loves hiking"}`,
+		`{"user_id":"user_002","content":"User prefers tea"}`,
+		`{"user_id":"user_002","content":"User drank tea yesterday","expires_at":"2020-01-01T00:00:00Z"}`,
+	)
+
+	assert.Equal(t, "would migrate 2 memories\n", mustRun(t, "-config", source, "migrate", "-to", target, "-dry-run"))
+	assert.Equal(t, "migrated 1 memories (0 expired skipped)\n", mustRun(t, "-config", source, "migrate", "-to", target, "-user", "user_002"))
+
+	var stats cli.Stats
+	require.NoError(t, json.Unmarshal([]byte(mustRun(t, "-config", target, "stats", "-json")), &stats))
+	assert.Equal(t, map[string]int{"user_002": 1}, stats.ByUser)
+}
+
+func TestRun_Snapshot(t *testing.T) {
+	dir := t.TempDir()
+	config := writeConfig(t, dir, "source")
+	seed(t, config, `{"user_id":"user_001","content":"User loves hiking"}`)
+
+	path := filepath.Join(dir, "backup.snapshot")
+	assert.Equal(t, "wrote snapshot "+path+"\n", mustRun(t, "-config", config, "snapshot", path))
+	seed(t, config, `{"user_id":"user_002","content":"User prefers tea"}`)
+	assert.Equal(t, "restored snapshot "+path+"\n", mustRun(t, "-config", config, "restore", path))
+
+	var stats cli.Stats
+	require.NoError(t, json.Unmarshal([]byte(mustRun(t, "-config", config, "stats", "-json")), &stats))
+	assert.Equal(t, map[string]int{"user_001": 1}, stats.ByUser)
+
+	_, err := run(t, "", "-config", config, "snapshot", path)
+	assert.Error(t, err, "the snapshot file exists")
+}
+
+func TestRun_Audit(t *testing.T) {
+	dir := t.TempDir()
+	source := writeConfig(t, dir, "source")
+	target := writeConfig(t, dir, "target")
+	file, err := os.OpenFile(target, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = file.WriteString("audit:\n  enabled: true\n")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	seed(t, source, `{"user_id":"user_001","content":"User loves hiking"}`)
+	ctx := core.ContextWithActor(context.Background(), "ops@example.com")
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, cli.Run(ctx, []string{"-config", source, "migrate", "-to", target, "-user", "user_001"},
+		strings.NewReader(""), &stdout, &stderr))
+	require.NoError(t, cli.Run(ctx, []string{"-config", target, "import", "-user", "user_002"},
+		strings.NewReader(`{"content":"User prefers tea"}`), &stdout, &stderr))
+
+	cfg, err := core.LoadConfigFromFile(target)
+	require.NoError(t, err)
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	entries, err := client.ListAuditEntries(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Migrate", entries[0].Operation)
+	assert.Equal(t, "ops@example.com", entries[0].Actor)
+	assert.Equal(t, "user_001", entries[0].UserID)
+	assert.Equal(t, source, entries[0].Params["source"])
+	assert.Equal(t, "Import", entries[1].Operation)
+	assert.Equal(t, "user_002", entries[1].UserID)
+	assert.Equal(t, "-", entries[1].Params["file"])
+}
+
+func TestRun_Profiles(t *testing.T) {
+	dir := t.TempDir()
+	config := writeConfig(t, dir, "memories")
+
+	store, err := usermemorySQLite.NewStore(&usermemorySQLite.Config{DBPath: filepath.Join(dir, "memories_profiles.db")})
+	require.NoError(t, err)
+	content := "Software engineer who enjoys hiking"
+	_, err = store.SaveProfile(context.Background(), "user_001", &content, map[string]interface{}{"occupation": "engineer"})
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	out := mustRun(t, "-config", config, "profiles")
+	assert.Contains(t, out, "user_001")
+	assert.Contains(t, out, content)
+
+	out = mustRun(t, "-config", config, "profiles", "-user", "user_002", "-json")
+	assert.Equal(t, "[]\n", out)
+}
+
+func TestRun_Usage(t *testing.T) {
+	config := writeConfig(t, t.TempDir(), "memories")
+
+	for _, args := range [][]string{
+		{},
+		{"unknown"},
+		{"-config", config, "-env", ".env", "stats"},
+		{"-config", config, "search"},
+		{"-config", config, "get"},
+		{"-config", config, "migrate"},
+		{"-config", config, "restore"},
+		{"-config", config, "stats", "-unknown"},
+		{"-config", config, "dashboard", "extra"},
+	} {
+		_, err := run(t, "", args...)
+		assert.ErrorIs(t, err, cli.ErrUsage, "args %q", args)
+	}
+
+	_, err := run(t, "", "-h")
+	assert.ErrorIs(t, err, flag.ErrHelp)
+}
@@ -0,0 +1,143 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// newOfflineAsyncClient creates an AsyncClient backed by SQLite whose providers are
+// never called by the operations under test.
+func newOfflineAsyncClient(t *testing.T, opts ...core.AsyncOption) *core.AsyncClient {
+	return newAsyncClientWithEmbedder(t, "", opts...)
+}
+
+// newEmbeddingServer starts a fake OpenAI-compatible embeddings endpoint.
+//
+// Each request is reported on started and answered only after release is closed.
+func newEmbeddingServer(t *testing.T) (url string, started chan struct{}, release chan struct{}) {
+	started = make(chan struct{}, 10)
+	release = make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"object": "list",
+			"model":  "text-embedding-ada-002",
+			"data": []map[string]interface{}{
+				{"object": "embedding", "index": 0, "embedding": []float64{1, 0, 0}},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, started, release
+}
+
+// newAsyncClientWithEmbedder creates an AsyncClient backed by SQLite that sends
+// embedding requests to embedderURL.
+func newAsyncClientWithEmbedder(t *testing.T, embedderURL string, opts ...core.AsyncOption) *core.AsyncClient {
+	testDBPath := "./test_async.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	config := &core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite: &core.SQLiteConfig{
+				DBPath:             testDBPath,
+				CollectionName:     "memories",
+				EmbeddingModelDims: 3,
+			},
+		},
+		LLM: core.LLMConfig{
+			Provider: "openai",
+			APIKey:   "test-key",
+			Model:    "gpt-3.5-turbo",
+		},
+		Embedder: core.EmbedderConfig{
+			Provider:   "openai",
+			APIKey:     "test-key",
+			Model:      "text-embedding-ada-002",
+			BaseURL:    embedderURL,
+			Dimensions: 3,
+		},
+	}
+
+	client, err := core.NewAsyncClient(config, opts...)
+	require.NoError(t, err)
+	return client
+}
+
+func TestAsyncClient_WorkerPool(t *testing.T) {
+	client := newOfflineAsyncClient(t, core.WithAsyncWorkers(2), core.WithAsyncQueueSize(1))
+	ctx := context.Background()
+
+	// More operations than workers + queue slots must all complete
+	results := make([]<-chan *core.AsyncGetAllResult, 10)
+	for i := range results {
+		results[i] = client.GetAllAsync(ctx, core.WithUserIDForGetAll("user_001"))
+	}
+	for _, ch := range results {
+		result := <-ch
+		require.NoError(t, result.Error)
+		assert.Empty(t, result.Memories)
+	}
+
+	getResult := <-client.GetAsync(ctx, 42, core.WithUserIDForGet("user_001"))
+	assert.Error(t, getResult.Error)
+
+	batchResult := <-client.BatchDeleteAsync(ctx, []int64{1, 2})
+	require.NoError(t, batchResult.Error)
+	assert.Equal(t, 2, batchResult.Result.Total)
+
+	client.Wait()
+	require.NoError(t, client.Close())
+	require.NoError(t, client.Close())
+
+	// Operations after Close fail fast
+	addResult := <-client.AddAsync(ctx, "User likes Python")
+	assert.True(t, errors.Is(addResult.Error, core.ErrClientClosed))
+	assert.True(t, errors.Is(<-client.DeleteAsync(ctx, 1), core.ErrClientClosed))
+}
+
+func TestAsyncClient_Backpressure(t *testing.T) {
+	url, started, release := newEmbeddingServer(t)
+	client := newAsyncClientWithEmbedder(t, url,
+		core.WithAsyncWorkers(1),
+		core.WithAsyncQueueSize(1),
+		core.WithAsyncBackpressure(core.BackpressureDrop),
+	)
+	ctx := context.Background()
+
+	// The only worker is busy with the first operation
+	first := client.AddAsync(ctx, "User likes Python", core.WithUserID("user_001"))
+	<-started
+
+	// The second operation fills the queue, the third is dropped
+	second := client.AddAsync(ctx, "User likes Go", core.WithUserID("user_001"))
+	assert.Equal(t, 1, client.QueueLength())
+	dropped := <-client.AddAsync(ctx, "User likes Rust", core.WithUserID("user_001"))
+	assert.True(t, errors.Is(dropped.Error, core.ErrQueueFull))
+
+	// Close drains the pending operations instead of dropping them
+	closed := make(chan error, 1)
+	go func() { closed <- client.Close() }()
+	close(release)
+	require.NoError(t, <-closed)
+
+	for _, ch := range []<-chan *core.MemoryResult{first, second} {
+		result := <-ch
+		require.NoError(t, result.Error)
+		assert.NotNil(t, result.Memory)
+	}
+}
@@ -0,0 +1,123 @@
+package core_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_AuditLog(t *testing.T) {
+	cfg := newTestConfig(filepath.Join(t.TempDir(), "test_audit.db"))
+	cfg.Audit = &core.AuditConfig{Enabled: true}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := core.ContextWithActor(context.Background(), "admin@example.com")
+
+	_, err = client.Add(ctx, "User loves hiking", core.WithUserID("user_001"), core.WithInfer(false))
+	require.NoError(t, err)
+	require.NoError(t, client.DeleteAll(ctx, core.WithUserIDForDeleteAll("user_001"), core.WithAgentIDForDeleteAll("agent_001")))
+	_, err = client.EraseUser(ctx, "user_002")
+	require.NoError(t, err)
+	require.NoError(t, client.RecordAudit(context.Background(), "Import", "user_003", map[string]interface{}{"file": "backup.jsonl"}))
+	require.NoError(t, client.Reset(ctx))
+
+	// Reset keeps the log; adding a memory is not audited
+	entries, err := client.ListAuditEntries(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	operations := make([]string, len(entries))
+	for i, entry := range entries {
+		operations[i] = entry.Operation
+		assert.False(t, entry.Time.IsZero())
+		if i > 0 {
+			assert.Greater(t, entry.Seq, entries[i-1].Seq)
+		}
+	}
+	assert.Equal(t, []string{"DeleteAll", "EraseUser", "Import", "Reset"}, operations)
+
+	assert.Equal(t, "admin@example.com", entries[0].Actor)
+	assert.Equal(t, "user_001", entries[0].UserID)
+	assert.Equal(t, map[string]interface{}{"user_id": "user_001", "agent_id": "agent_001"}, entries[0].Params)
+	assert.Equal(t, "user_002", entries[1].UserID)
+	assert.Empty(t, entries[2].Actor)
+	assert.Equal(t, "backup.jsonl", entries[2].Params["file"])
+	assert.Empty(t, entries[3].UserID)
+
+	// Filters
+	entries, err = client.ListAuditEntries(ctx, core.WithAuditOperations("EraseUser", "Reset"))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "EraseUser", entries[0].Operation)
+
+	entries, err = client.ListAuditEntries(ctx, core.WithUserIDForAudit("user_003"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Import", entries[0].Operation)
+
+	entries, err = client.ListAuditEntries(ctx, core.WithActorForAudit("admin@example.com"), core.WithAuditLimit(2))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	next, err := client.ListAuditEntries(ctx, core.WithActorForAudit("admin@example.com"), core.WithAuditAfter(entries[1].Seq))
+	require.NoError(t, err)
+	require.Len(t, next, 1)
+	assert.Equal(t, "Reset", next[0].Operation)
+
+	err = client.RecordAudit(ctx, "", "", nil)
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+}
+
+func TestClient_AuditLogDisabled(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test_audit_disabled.db")
+	client, err := core.NewClient(newTestConfig(dbPath))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	require.NoError(t, client.DeleteAll(ctx))
+	require.NoError(t, client.RecordAudit(ctx, "Import", "", nil))
+
+	entries, err := client.ListAuditEntries(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestClient_AuditLogFailClosed(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test_audit_fail.db")
+	cfg := newTestConfig(dbPath)
+	cfg.Audit = &core.AuditConfig{Enabled: true}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	_, err = client.Add(ctx, "User loves hiking", core.WithUserID("user_001"), core.WithInfer(false))
+	require.NoError(t, err)
+
+	// Make the audit log unwritable
+	db, err := sql.Open("sqlite3", dbPath)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`
+		CREATE TRIGGER reject_audit BEFORE INSERT ON memories_audit
+		BEGIN
+			SELECT RAISE(ABORT, 'audit log unavailable');
+		END`)
+	require.NoError(t, err)
+
+	assert.Error(t, client.DeleteAll(ctx, core.WithUserIDForDeleteAll("user_001")))
+	_, err = client.EraseUser(ctx, "user_001")
+	assert.Error(t, err)
+	assert.Error(t, client.Reset(ctx))
+
+	// Nothing was deleted
+	memories, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	assert.Len(t, memories, 1)
+}
@@ -0,0 +1,189 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestBatchAddItems_PerItemMetadata(t *testing.T) {
+	testDBPath := "./test_batch_items.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	client := newOfflineTestClient(t, testDBPath, "")
+	ctx := context.Background()
+
+	result, err := client.BatchAddItems(ctx, []core.BatchAddItem{
+		{Content: "I like hiking", Metadata: map[string]interface{}{"source": "chat"}},
+		{Content: "Busy weekend"},
+		{Content: "I work in tech", Metadata: map[string]interface{}{"source": "crm", "team": "sales"}},
+	},
+		core.WithUserID("user_001"),
+		core.WithMetadata(map[string]interface{}{"source": "import", "team": "core"}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 3, result.CreatedCount)
+
+	// Created is in input order regardless of completion order
+	assert.Equal(t, "I like hiking", result.Created[0].Content)
+	assert.Equal(t, "chat", result.Created[0].Metadata["source"])
+	assert.Equal(t, "core", result.Created[0].Metadata["team"])
+	assert.Equal(t, "import", result.Created[1].Metadata["source"])
+	assert.Equal(t, "crm", result.Created[2].Metadata["source"])
+	assert.Equal(t, "sales", result.Created[2].Metadata["team"])
+	for _, memory := range result.Created {
+		assert.Equal(t, "user_001", memory.UserID)
+	}
+}
+
+func TestBatchAddItems_PerItemOptions(t *testing.T) {
+	testDBPath := "./test_batch_item_options.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	client := newOfflineTestClient(t, testDBPath, "")
+	ctx := context.Background()
+
+	result, err := client.BatchAddItems(ctx, []core.BatchAddItem{
+		{Content: "I like hiking", RunID: "run_001", Tags: []string{"hobby"}},
+		{Content: "Busy weekend", AgentID: "agent_002", Scope: core.ScopeAgentGroup, MemoryType: "event"},
+		{Content: "Temporary note", TTL: time.Hour},
+	},
+		core.WithUserID("user_001"),
+		core.WithAgentID("agent_001"),
+		core.WithTags("shared"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 3, result.CreatedCount)
+	require.Len(t, result.IDs, 3)
+	for i, memory := range result.Created {
+		assert.Equal(t, memory.ID, result.IDs[i])
+	}
+
+	first, err := client.Get(ctx, result.IDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, "I like hiking", first.Content)
+	assert.Equal(t, "agent_001", first.AgentID)
+	assert.Equal(t, "run_001", first.Metadata["run_id"])
+	assert.Equal(t, []string{"hobby"}, first.Tags)
+
+	second, err := client.Get(ctx, result.IDs[1])
+	require.NoError(t, err)
+	assert.Equal(t, "agent_002", second.AgentID)
+	assert.Equal(t, "agent_group", second.Metadata["scope"])
+	assert.Equal(t, "event", second.Metadata["memory_type"])
+	assert.Equal(t, []string{"shared"}, second.Tags)
+
+	third, err := client.Get(ctx, result.IDs[2])
+	require.NoError(t, err)
+	require.NotNil(t, third.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *third.ExpiresAt, time.Minute)
+}
+
+func TestBatchDelete_FailFast(t *testing.T) {
+	testDBPath := "./test_batch_fail_fast.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	client := newOfflineTestClient(t, testDBPath, "")
+	ctx := context.Background()
+
+	added, err := client.BatchAdd(ctx, []string{"I like hiking", "Busy weekend"}, core.WithUserID("user_001"))
+	require.NoError(t, err)
+	require.Equal(t, 2, added.CreatedCount)
+	ids := []int64{added.Created[0].ID, 1, added.Created[1].ID}
+
+	result, err := client.BatchDelete(ctx, ids,
+		core.WithBatchConcurrency(1),
+		core.WithBatchFailFast(true),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{added.Created[0].ID}, result.DeletedIDs)
+	require.Equal(t, 2, result.FailedCount)
+	assert.Equal(t, 1, result.Failed[0].Index)
+	assert.NotErrorIs(t, result.Failed[0].Error, core.ErrBatchAborted)
+	assert.Equal(t, 2, result.Failed[1].Index)
+	assert.ErrorIs(t, result.Failed[1].Error, core.ErrBatchAborted)
+
+	// The aborted item was not touched
+	_, err = client.Get(ctx, added.Created[1].ID)
+	assert.NoError(t, err)
+
+	// Without fail-fast every item is processed
+	result, err = client.BatchDelete(ctx, ids, core.WithBatchConcurrency(1))
+	require.NoError(t, err)
+	assert.Equal(t, []int64{added.Created[1].ID}, result.DeletedIDs)
+	assert.Equal(t, 2, result.FailedCount)
+}
+
+func TestBatchAdd_Retry(t *testing.T) {
+	testDBPath := "./test_batch_retry.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	// The first embeddings call fails
+	var embeddingCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/embeddings") {
+			http.NotFound(w, r)
+			return
+		}
+		if atomic.AddInt32(&embeddingCalls, 1) == 1 {
+			http.Error(w, `{"error":{"message":"overloaded"}}`, http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"object": "list",
+			"data": []map[string]interface{}{
+				{"object": "embedding", "index": 0, "embedding": []float64{1, 0, 0}},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := core.NewClient(&core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite: &core.SQLiteConfig{
+				DBPath:             testDBPath,
+				CollectionName:     "memories",
+				EmbeddingModelDims: 3,
+			},
+		},
+		LLM: core.LLMConfig{Provider: "openai", APIKey: "test-key", Model: "gpt-3.5-turbo", BaseURL: server.URL},
+		Embedder: core.EmbedderConfig{
+			Provider: "openai", APIKey: "test-key", Model: "text-embedding-ada-002", BaseURL: server.URL, Dimensions: 3,
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	result, err := client.BatchAdd(ctx, []string{"I like hiking"},
+		core.WithUserID("user_001"),
+		core.WithBatchOptions(core.WithBatchRetry(2, time.Millisecond)),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.CreatedCount)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&embeddingCalls))
+
+	// Without retries the transient failure is reported
+	atomic.StoreInt32(&embeddingCalls, 0)
+	result, err = client.BatchAdd(ctx, []string{"I like hiking"}, core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.FailedCount)
+	assert.Equal(t, []int64{0}, result.IDs)
+}
@@ -0,0 +1,149 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_ListChanges(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_changes.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := core.ContextWithActor(context.Background(), "admin")
+
+	first, err := client.Add(ctx, "User loves hiking", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	second, err := client.Add(ctx, "User prefers tea", core.WithUserID("user_002"))
+	require.NoError(t, err)
+	_, err = client.Update(ctx, first.ID, "User loves hiking in the Alps")
+	require.NoError(t, err)
+	require.NoError(t, client.Delete(ctx, second.ID))
+	require.NoError(t, client.DeleteAll(ctx))
+
+	changes, err := client.ListChanges(ctx)
+	require.NoError(t, err)
+	require.Len(t, changes, 5)
+	types := make([]core.EventType, len(changes))
+	for i, change := range changes {
+		types[i] = change.Type
+		if i > 0 {
+			assert.Greater(t, change.Seq, changes[i-1].Seq)
+		}
+	}
+	assert.Equal(t, []core.EventType{core.EventCreated, core.EventCreated, core.EventUpdated, core.EventDeleted, core.EventDeleted}, types)
+	assert.Equal(t, "admin", changes[0].Actor)
+	assert.Equal(t, first.ID, changes[0].MemoryID)
+	assert.Equal(t, "User loves hiking", changes[0].Memory.Content)
+	require.NotNil(t, changes[2].Diff)
+	assert.Equal(t, "User loves hiking in the Alps", changes[2].Diff.NewContent)
+
+	last, err := client.LastChangeSeq(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, changes[4].Seq, last)
+
+	// Resume after a change
+	resumed, err := client.ListChanges(ctx, core.WithChangesAfter(changes[1].Seq), core.WithChangesLimit(2))
+	require.NoError(t, err)
+	require.Len(t, resumed, 2)
+	assert.Equal(t, changes[2].Seq, resumed[0].Seq)
+
+	// The bulk DeleteAll applies to every user
+	userChanges, err := client.ListChanges(ctx, core.WithUserIDForChanges("user_002"))
+	require.NoError(t, err)
+	require.Len(t, userChanges, 3)
+	assert.Equal(t, second.ID, userChanges[0].MemoryID)
+	assert.Equal(t, int64(0), userChanges[2].MemoryID)
+
+	deletions, err := client.ListChanges(ctx, core.WithChangeTypes(core.EventDeleted))
+	require.NoError(t, err)
+	assert.Len(t, deletions, 2)
+
+	purged, err := client.PurgeChanges(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), purged)
+	changes, err = client.ListChanges(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestClient_ListChangesDisabled(t *testing.T) {
+	client := newEventsClient(t, nil)
+	ctx := context.Background()
+
+	_, err := client.Add(ctx, "User loves hiking")
+	require.NoError(t, err)
+
+	changes, err := client.ListChanges(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestClient_SubscribeChanges(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test_changes.db")
+	writer, err := core.NewClient(newChangesConfig(dbPath))
+	require.NoError(t, err)
+	defer writer.Close()
+	// A replica only reads the log written by the other client
+	replica, err := core.NewClient(newChangesConfig(dbPath))
+	require.NoError(t, err)
+	defer replica.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err = writer.Add(ctx, "User loves hiking", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	seq, err := replica.LastChangeSeq(ctx)
+	require.NoError(t, err)
+
+	local := writer.SubscribeChanges(ctx, core.WithChangesAfter(seq), core.WithUserIDForChanges("user_002"))
+	remote := replica.SubscribeChanges(ctx, core.WithChangesAfter(seq), core.WithChangesPollInterval(10*time.Millisecond))
+
+	_, err = writer.Add(ctx, "User likes jazz", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	memory, err := writer.Add(ctx, "User prefers tea", core.WithUserID("user_002"))
+	require.NoError(t, err)
+
+	next := func(stream <-chan *core.ChangeResult) *core.Change {
+		select {
+		case result, ok := <-stream:
+			require.True(t, ok, "stream closed")
+			require.NoError(t, result.Error)
+			return result.Change
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "no change received")
+			return nil
+		}
+	}
+
+	change := next(local)
+	assert.Equal(t, memory.ID, change.MemoryID)
+	assert.Equal(t, "User likes jazz", next(remote).Memory.Content)
+	assert.Equal(t, "User prefers tea", next(remote).Memory.Content)
+
+	cancel()
+	for range remote {
+	}
+	for range local {
+	}
+}
+
+func TestClient_SubscribeChangesClose(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_changes.db")))
+	require.NoError(t, err)
+
+	stream := client.SubscribeChanges(context.Background())
+	require.NoError(t, client.Close())
+
+	select {
+	case <-stream:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "stream not closed on shutdown")
+	}
+}
@@ -0,0 +1,169 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// chunkedDocument is a document longer than the chunk size of newChunkingConfig.
+var chunkedDocument = strings.Join([]string{
+	"The team moved the weekly planning meeting to Tuesday mornings. Everyone agreed that Monday was too busy after the weekend.",
+	"The release checklist now includes a database backup step. Backups are verified by restoring them into a staging cluster.",
+	"Customer feedback asked for a dark theme in the dashboard. Design will share mockups before the next sprint starts.",
+	"The office coffee machine was replaced with an espresso grinder. Barista training happens on Friday afternoons.",
+}, "\n\n")
+
+// newChunkingConfig returns a config splitting content into chunks of 150
+// bytes, stored in dbPath.
+func newChunkingConfig(dbPath string) *core.Config {
+	cfg := newChangesConfig(dbPath)
+	cfg.ChangeLog = nil
+	cfg.Chunking = &core.ChunkingConfig{MaxContentSize: 1000, ChunkSize: 150, ChunkOverlap: 20}
+	return cfg
+}
+
+func TestClient_Chunking(t *testing.T) {
+	client, err := core.NewClient(newChunkingConfig(filepath.Join(t.TempDir(), "test_chunking.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	memory, err := client.Add(ctx, chunkedDocument, core.WithUserID("user_001"),
+		core.WithMetadata(map[string]interface{}{"source": "notes"}))
+	require.NoError(t, err)
+	assert.Equal(t, chunkedDocument, memory.Content)
+
+	chunks, err := client.GetChunks(ctx, memory.ID)
+	require.NoError(t, err)
+	require.Greater(t, len(chunks), 3)
+	for _, chunk := range chunks {
+		assert.Equal(t, memory.ID, chunk.ParentID)
+		assert.Equal(t, "user_001", chunk.UserID)
+		assert.Equal(t, "notes", chunk.Metadata["source"])
+		assert.LessOrEqual(t, len(chunk.Content), 150)
+		assert.Contains(t, chunkedDocument, chunk.Content)
+	}
+	assert.True(t, strings.HasPrefix(chunkedDocument, chunks[0].Content))
+	assert.True(t, strings.HasSuffix(chunkedDocument, chunks[len(chunks)-1].Content))
+
+	// Chunks are not listed
+	memories, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	require.Len(t, memories, 1)
+	assert.Equal(t, memory.ID, memories[0].ID)
+
+	// Searches match a chunk and return the whole memory once
+	results, err := client.Search(ctx, "espresso grinder barista training",
+		core.WithUserIDForSearch("user_001"), core.WithLimit(10))
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, memory.ID, results[0].ID)
+	assert.Equal(t, chunkedDocument, results[0].Content)
+	ids := make(map[int64]bool)
+	for _, result := range results {
+		assert.False(t, ids[result.ID], "memory %d returned twice", result.ID)
+		ids[result.ID] = true
+	}
+
+	results, err = client.SearchByKeyword(ctx, "espresso", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, memory.ID, results[0].ID)
+
+	// Metadata updates reach the chunks
+	_, err = client.Update(ctx, memory.ID, "", core.WithMetadataForUpdate(map[string]interface{}{"source": "wiki"}))
+	require.NoError(t, err)
+	results, err = client.Search(ctx, "espresso grinder barista training",
+		core.WithUserIDForSearch("user_001"), core.WithFilters(map[string]interface{}{"source": "wiki"}))
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, memory.ID, results[0].ID)
+
+	// Short content replaces the chunks
+	_, err = client.Update(ctx, memory.ID, "The planning meeting is on Tuesday")
+	require.NoError(t, err)
+	chunks, err = client.GetChunks(ctx, memory.ID)
+	require.NoError(t, err)
+	assert.Empty(t, chunks)
+
+	// Deleting a memory deletes its chunks
+	_, err = client.Update(ctx, memory.ID, chunkedDocument)
+	require.NoError(t, err)
+	chunks, err = client.GetChunks(ctx, memory.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, chunks)
+	require.NoError(t, client.Delete(ctx, memory.ID))
+	_, err = client.Get(ctx, chunks[0].ID)
+	assert.Error(t, err)
+	results, err = client.Search(ctx, "espresso grinder barista training", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestClient_ChunkingMaxContentSize(t *testing.T) {
+	client, err := core.NewClient(newChunkingConfig(filepath.Join(t.TempDir(), "test_chunking_max.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	tooLarge := strings.Repeat("word ", 201)
+	_, err = client.Add(ctx, tooLarge, core.WithUserID("user_001"))
+	assert.True(t, errors.Is(err, core.ErrContentTooLarge))
+
+	memory, err := client.Add(ctx, "User likes tea", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	_, err = client.Update(ctx, memory.ID, tooLarge)
+	assert.True(t, errors.Is(err, core.ErrContentTooLarge))
+
+	memories, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	require.Len(t, memories, 1)
+	assert.Equal(t, "User likes tea", memories[0].Content)
+}
+
+func TestClient_ChunkingDeleteWhere(t *testing.T) {
+	client, err := core.NewClient(newChunkingConfig(filepath.Join(t.TempDir(), "test_chunking_delete_where.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	memory, err := client.Add(ctx, chunkedDocument, core.WithUserID("user_001"),
+		core.WithMetadata(map[string]interface{}{"source": "notes"}))
+	require.NoError(t, err)
+	chunks, err := client.GetChunks(ctx, memory.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, chunks)
+
+	// Chunks are not counted but deleted with their memory
+	filters := map[string]interface{}{"source": "notes"}
+	plan, err := client.DeleteWhere(ctx, filters)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), plan.Matched)
+	_, err = client.DeleteWhere(ctx, filters, core.WithConfirmedCount(plan.Matched))
+	require.NoError(t, err)
+	_, err = client.Get(ctx, chunks[0].ID)
+	assert.Error(t, err)
+}
+
+func TestConfig_ValidateChunking(t *testing.T) {
+	cfg := newChunkingConfig("test.db")
+	cfg.Chunking = &core.ChunkingConfig{MaxContentSize: -1, ChunkSize: 100, ChunkOverlap: 100}
+
+	err := cfg.Validate()
+	var validationErr *core.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	fields := make(map[string]string)
+	for _, fieldErr := range validationErr.Fields {
+		fields[fieldErr.Field] = fieldErr.Message
+	}
+	assert.Equal(t, "must not be negative, got -1", fields["chunking.max_content_size"])
+	assert.Equal(t, "must be less than chunk_size, got 100", fields["chunking.chunk_overlap"])
+}
@@ -0,0 +1,94 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_Collections(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_collections.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	require.NoError(t, client.CreateCollection(ctx, "shop"))
+	// Creating an existing collection is not an error
+	require.NoError(t, client.CreateCollection(ctx, "shop"))
+
+	names, err := client.ListCollections(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"memories", "shop"}, names)
+
+	shop := core.ContextWithCollection(ctx, "shop")
+	inShop, err := client.Add(shop, "Prefers express delivery", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	inDefault, err := client.Add(ctx, "Likes hiking", core.WithUserID("user_001"))
+	require.NoError(t, err)
+
+	// Collections are isolated from each other
+	shopMemories, err := client.GetAll(shop, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	require.Len(t, shopMemories, 1)
+	assert.Equal(t, inShop.ID, shopMemories[0].ID)
+
+	defaultMemories, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	require.Len(t, defaultMemories, 1)
+	assert.Equal(t, inDefault.ID, defaultMemories[0].ID)
+
+	results, err := client.Search(shop, "delivery", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, inShop.ID, results[0].ID)
+
+	// Each collection has its own change log
+	changes, err := client.ListChanges(shop)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, inShop.ID, changes[0].MemoryID)
+
+	// The default collection can be named explicitly
+	named, err := client.GetAll(core.ContextWithCollection(ctx, "memories"), core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	assert.Len(t, named, 1)
+
+	_, err = client.GetAll(core.ContextWithCollection(ctx, "unknown"), core.WithUserIDForGetAll("user_001"))
+	assert.True(t, errors.Is(err, core.ErrCollectionNotFound))
+
+	require.NoError(t, client.DropCollection(ctx, "shop"))
+	names, err = client.ListCollections(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"memories"}, names)
+
+	_, err = client.GetAll(shop, core.WithUserIDForGetAll("user_001"))
+	assert.True(t, errors.Is(err, core.ErrCollectionNotFound))
+	// Dropping a missing collection is not an error
+	require.NoError(t, client.DropCollection(ctx, "shop"))
+
+	// The default collection is left untouched
+	defaultMemories, err = client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	assert.Len(t, defaultMemories, 1)
+}
+
+func TestClient_CollectionsInvalid(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_collections_invalid.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	for _, name := range []string{"", "Shop", "shop-1", "1shop", "shop; DROP TABLE memories", "shop_changes", "shop_audit"} {
+		err := client.CreateCollection(ctx, name)
+		assert.True(t, errors.Is(err, core.ErrInvalidInput), name)
+	}
+
+	err = client.DropCollection(ctx, "memories")
+	assert.True(t, errors.Is(err, core.ErrInvalidInput))
+}
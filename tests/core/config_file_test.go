@@ -0,0 +1,240 @@
+package core_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	powermem "github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// writeConfigFile writes content to name in a temporary directory.
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfigFromFile_YAML(t *testing.T) {
+	t.Setenv("TEST_LLM_API_KEY", "sk-yaml")
+	t.Setenv("TEST_PG_PORT", "6543")
+	t.Setenv("TEST_PG_PASSWORD", "12345")
+
+	path := writeConfigFile(t, "config.yaml", `
+llm:
+  provider: openai
+  api_key: ${TEST_LLM_API_KEY}
+  model: gpt-4
+embedder:
+  provider: openai
+  api_key: ${TEST_LLM_API_KEY}
+  dimensions: 768
+vector_store:
+  provider: postgres
+  config:
+    host: ${TEST_PG_HOST:-db.internal}
+    port: ${TEST_PG_PORT}
+    password: "${TEST_PG_PASSWORD}"
+    db_name: "$${literal}"
+intelligence:
+  enabled: true
+  duplicate_threshold: 0.9
+  merge_strategy: keep_newest
+  ranking:
+    similarity_weight: 0.7
+    recency_weight: 0.3
+agent_memory:
+  default_scope: agent
+  allow_cross_agent_access: true
+`)
+
+	config, err := powermem.LoadConfigFromFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "sk-yaml", config.LLM.APIKey)
+	assert.Equal(t, "gpt-4", config.LLM.Model)
+	assert.Equal(t, "sk-yaml", config.Embedder.APIKey)
+	assert.Equal(t, 768, config.Embedder.Dimensions)
+
+	require.NotNil(t, config.VectorStore.Postgres)
+	assert.Equal(t, "db.internal", config.VectorStore.Postgres.Host)
+	assert.Equal(t, 6543, config.VectorStore.Postgres.Port)
+	// A quoted reference stays a string even if it looks like a number
+	assert.Equal(t, "12345", config.VectorStore.Postgres.Password)
+	assert.Equal(t, "${literal}", config.VectorStore.Postgres.DBName)
+
+	require.NotNil(t, config.Intelligence)
+	assert.True(t, config.Intelligence.Enabled)
+	assert.Equal(t, 0.9, config.Intelligence.DuplicateThreshold)
+	assert.Equal(t, "keep_newest", config.Intelligence.MergeStrategy)
+	require.NotNil(t, config.Intelligence.Ranking)
+	assert.Equal(t, 0.7, config.Intelligence.Ranking.SimilarityWeight)
+
+	require.NotNil(t, config.AgentMemory)
+	assert.Equal(t, powermem.MemoryScope("agent"), config.AgentMemory.DefaultScope)
+	assert.True(t, config.AgentMemory.AllowCrossAgentAccess)
+
+	assert.NoError(t, config.Validate())
+}
+
+func TestLoadConfigFromFile_TOML(t *testing.T) {
+	t.Setenv("TEST_LLM_API_KEY", "sk-toml")
+	t.Setenv("TEST_STOP", "END")
+
+	path := writeConfigFile(t, "config.toml", `
+# PowerMem configuration
+[llm]
+provider = "qwen"
+api_key = "${TEST_LLM_API_KEY}"
+model = 'qwen-plus'
+parameters = { temperature = 0.2, stop = ["\n", "${TEST_STOP}"] }
+
+[embedder]
+provider = "qwen"
+api_key = "${TEST_LLM_API_KEY}"
+dimensions = 384
+
+[vector_store]
+provider = "sqlite"
+config.db_path = "${TEST_DB_DIR:-/tmp}/powermem.db"
+
+[intelligence]
+enabled = true
+decay_rate = 0.1
+merge_prompt = """
+Merge {existing}
+with {new}."""
+
+[intelligence.ranking]
+similarity_weight = 1
+
+[[llm.fallbacks]]
+provider = "openai"
+api_key = "${TEST_LLM_API_KEY}"
+`)
+
+	config, err := powermem.LoadConfigFromFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "qwen", config.LLM.Provider)
+	assert.Equal(t, "sk-toml", config.LLM.APIKey)
+	assert.Equal(t, "qwen-plus", config.LLM.Model)
+	assert.Equal(t, 0.2, config.LLM.Parameters["temperature"])
+	assert.Equal(t, []interface{}{"\n", "END"}, config.LLM.Parameters["stop"])
+	require.Len(t, config.LLM.Fallbacks, 1)
+	assert.Equal(t, "openai", config.LLM.Fallbacks[0].Provider)
+	assert.Equal(t, "sk-toml", config.LLM.Fallbacks[0].APIKey)
+	assert.Equal(t, 384, config.Embedder.Dimensions)
+
+	require.NotNil(t, config.VectorStore.SQLite)
+	assert.Equal(t, "/tmp/powermem.db", config.VectorStore.SQLite.DBPath)
+
+	require.NotNil(t, config.Intelligence)
+	assert.Equal(t, 0.1, config.Intelligence.DecayRate)
+	assert.Equal(t, "Merge {existing}\nwith {new}.", config.Intelligence.MergePrompt)
+	require.NotNil(t, config.Intelligence.Ranking)
+	assert.Equal(t, 1.0, config.Intelligence.Ranking.SimilarityWeight)
+
+	// The explicit TOML loader ignores the extension
+	tomlPath := writeConfigFile(t, "powermem.conf", "[llm]\nprovider = \"openai\"\n")
+	config, err = powermem.LoadConfigFromTOML(tomlPath)
+	require.NoError(t, err)
+	assert.Equal(t, "openai", config.LLM.Provider)
+}
+
+func TestLoadConfigFromFile_JSON(t *testing.T) {
+	t.Setenv("TEST_LLM_API_KEY", "sk-json")
+
+	path := writeConfigFile(t, "config.json", `{
+  "llm": {"provider": "openai", "api_key": "${TEST_LLM_API_KEY}", "model": "gpt-4"},
+  "vector_store": {"provider": "sqlite", "config": {"db_path": "./test.db"}}
+}`)
+
+	config, err := powermem.LoadConfigFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "sk-json", config.LLM.APIKey)
+	require.NotNil(t, config.VectorStore.SQLite)
+	assert.Equal(t, "./test.db", config.VectorStore.SQLite.DBPath)
+}
+
+func TestLoadConfigFromYAML_AnyExtension(t *testing.T) {
+	path := writeConfigFile(t, "powermem.conf", "llm:\n  provider: deepseek\n")
+
+	config, err := powermem.LoadConfigFromYAML(path)
+	require.NoError(t, err)
+	assert.Equal(t, "deepseek", config.LLM.Provider)
+}
+
+func TestLoadConfigFromFile_Errors(t *testing.T) {
+	os.Unsetenv("TEST_UNSET_VARIABLE")
+
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		contains string
+	}{
+		{
+			name:     "unset variable",
+			file:     "config.yaml",
+			content:  "llm:\n  api_key: ${TEST_UNSET_VARIABLE}\n",
+			contains: "llm.api_key: environment variable TEST_UNSET_VARIABLE is not set",
+		},
+		{
+			name:     "unset variable in TOML",
+			file:     "config.toml",
+			content:  "[llm]\napi_key = \"${TEST_UNSET_VARIABLE}\"\n",
+			contains: "environment variable TEST_UNSET_VARIABLE is not set",
+		},
+		{
+			name:     "unsupported extension",
+			file:     "config.ini",
+			content:  "[llm]\n",
+			contains: "unsupported config file extension",
+		},
+		{
+			name:     "invalid YAML",
+			file:     "config.yaml",
+			content:  "llm: [\n",
+			contains: "config.yaml",
+		},
+		{
+			name:     "unquoted TOML string",
+			file:     "config.toml",
+			content:  "[llm]\nprovider = openai\n",
+			contains: "llm.provider",
+		},
+		{
+			name:     "unquoted TOML reference",
+			file:     "config.toml",
+			content:  "[embedder]\ndimensions = ${TEST_UNSET_VARIABLE}\n",
+			contains: "embedder.dimensions",
+		},
+		{
+			name:     "wrong type",
+			file:     "config.yaml",
+			content:  "vector_store:\n  provider: sqlite\n  config:\n    embedding_model_dims: many\n",
+			contains: "vector_store.config.embedding_model_dims",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, tt.file, tt.content)
+
+			_, err := powermem.LoadConfigFromFile(path)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, powermem.ErrInvalidConfig), "got %v", err)
+			assert.Contains(t, err.Error(), tt.contains)
+		})
+	}
+
+	_, err := powermem.LoadConfigFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
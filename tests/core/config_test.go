@@ -1,6 +1,8 @@
 package core_test
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"testing"
 
@@ -94,9 +96,7 @@ func TestConfigValidate(t *testing.T) {
 				},
 				VectorStore: powermem.VectorStoreConfig{
 					Provider: "sqlite",
-					Config: map[string]interface{}{
-						"db_path": "./test.db",
-					},
+					SQLite:   &powermem.SQLiteConfig{DBPath: "./test.db"},
 				},
 			},
 			wantErr: false,
@@ -184,9 +184,7 @@ func TestDefaultConfig(t *testing.T) {
 		},
 		VectorStore: powermem.VectorStoreConfig{
 			Provider: "sqlite",
-			Config: map[string]interface{}{
-				"db_path": "./test.db",
-			},
+			SQLite:   &powermem.SQLiteConfig{DBPath: "./test.db"},
 		},
 	}
 
@@ -197,3 +195,110 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t, "openai", config.Embedder.Provider)
 	assert.Equal(t, "sqlite", config.VectorStore.Provider)
 }
+
+func TestConfigValidate_FieldErrors(t *testing.T) {
+	config := &powermem.Config{
+		LLM:      powermem.LLMConfig{Provider: "gpt"},
+		Embedder: powermem.EmbedderConfig{Provider: "openai", Dimensions: -1},
+		VectorStore: powermem.VectorStoreConfig{
+			Provider: "postgres",
+			Postgres: &powermem.PostgresConfig{Port: 70000, SSLMode: "always"},
+		},
+		Intelligence: &powermem.IntelligenceConfig{
+			Enabled:            true,
+			DuplicateThreshold: 1.5,
+			MergeStrategy:      "newest",
+		},
+	}
+
+	err := config.Validate()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, powermem.ErrInvalidConfig))
+
+	var validationErr *powermem.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	fields := make(map[string]string)
+	for _, fieldErr := range validationErr.Fields {
+		fields[fieldErr.Field] = fieldErr.Message
+	}
+	assert.Contains(t, fields, "llm.provider")
+	assert.Contains(t, fields, "embedder.dimensions")
+	assert.Equal(t, "must be between 1 and 65535, got 70000", fields["vector_store.config.port"])
+	assert.Equal(t, `unknown ssl mode "always"`, fields["vector_store.config.ssl_mode"])
+	assert.Contains(t, fields, "intelligence.duplicate_threshold")
+	assert.Contains(t, fields, "intelligence.merge_strategy")
+	assert.Contains(t, err.Error(), "vector_store.config.port")
+
+	// Settings of another provider are reported
+	config = &powermem.Config{
+		LLM:      powermem.LLMConfig{Provider: "openai"},
+		Embedder: powermem.EmbedderConfig{Provider: "openai"},
+		VectorStore: powermem.VectorStoreConfig{
+			Provider: "sqlite",
+			Postgres: &powermem.PostgresConfig{Host: "db.internal"},
+		},
+	}
+	err = config.Validate()
+	require.True(t, errors.As(err, &validationErr))
+	require.Len(t, validationErr.Fields, 1)
+	assert.Equal(t, "vector_store.provider", validationErr.Fields[0].Field)
+}
+
+func TestVectorStoreConfig_JSON(t *testing.T) {
+	// encoding/json decodes numbers as float64
+	var config powermem.Config
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"llm": {"provider": "openai"},
+		"embedder": {"provider": "openai"},
+		"vector_store": {"provider": "oceanbase", "config": {"host": "ob.internal", "port": 2881, "embedding_model_dims": 3}}
+	}`), &config))
+	require.NoError(t, config.Validate())
+	require.NotNil(t, config.VectorStore.OceanBase)
+	assert.Equal(t, "ob.internal", config.VectorStore.OceanBase.Host)
+	assert.Equal(t, 2881, config.VectorStore.OceanBase.Port)
+	assert.Equal(t, 3, config.VectorStore.OceanBase.EmbeddingModelDims)
+
+	// The JSON form is unchanged
+	data, err := json.Marshal(config.VectorStore)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"provider": "oceanbase", "config": {"host": "ob.internal", "port": 2881, "embedding_model_dims": 3}}`, string(data))
+
+	// Values of the wrong type name the field
+	var store powermem.VectorStoreConfig
+	err = json.Unmarshal([]byte(`{"provider": "postgres", "config": {"port": "5432", "host": 42}}`), &store)
+	assert.True(t, errors.Is(err, powermem.ErrInvalidConfig))
+	var validationErr *powermem.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Len(t, validationErr.Fields, 2)
+	assert.Equal(t, "vector_store.config.host", validationErr.Fields[0].Field)
+	assert.Equal(t, "must be a string, got float64", validationErr.Fields[0].Message)
+	assert.Equal(t, "vector_store.config.port", validationErr.Fields[1].Field)
+	assert.Equal(t, "must be an integer, got string 5432", validationErr.Fields[1].Message)
+
+	err = json.Unmarshal([]byte(`{"provider": "postgres", "config": {"port": 5432.5}}`), &store)
+	assert.True(t, errors.Is(err, powermem.ErrInvalidConfig))
+}
+
+func TestVectorStoreConfig_Defaults(t *testing.T) {
+	testDBPath := "./test_typed_store_config.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	// The provider is inferred and unset fields use the defaults
+	client, err := powermem.NewClient(&powermem.Config{
+		LLM:         powermem.LLMConfig{Provider: "openai", APIKey: "test-key"},
+		Embedder:    powermem.EmbedderConfig{Provider: "openai", APIKey: "test-key", Dimensions: 3},
+		VectorStore: powermem.VectorStoreConfig{SQLite: &powermem.SQLiteConfig{DBPath: testDBPath}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+
+	_, err = powermem.NewClient(&powermem.Config{
+		LLM:      powermem.LLMConfig{Provider: "openai"},
+		Embedder: powermem.EmbedderConfig{Provider: "openai"},
+		VectorStore: powermem.VectorStoreConfig{
+			SQLite: &powermem.SQLiteConfig{DBPath: testDBPath, EmbeddingModelDims: -3},
+		},
+	})
+	assert.True(t, errors.Is(err, powermem.ErrInvalidConfig))
+}
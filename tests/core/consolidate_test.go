@@ -0,0 +1,93 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_Consolidate(t *testing.T) {
+	cfg := newChangesConfig(filepath.Join(t.TempDir(), "test_consolidate.db"))
+	plan := `{"actions": [
+		{"event": "MERGE", "ids": ["0", "1"], "text": "Likes coffee and drinks it every morning", "reason": "Same preference"},
+		{"event": "RESOLVE", "ids": ["2", "3"], "text": "Lives in Lisbon since May, previously Berlin", "reason": "The move is more recent"},
+		{"event": "PROMOTE", "ids": ["4", "9"], "reason": "Lasting diet"}
+	]}`
+	cfg.LLM.Parameters = map[string]interface{}{"responses": []string{plan}}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	var memories []*core.Memory
+	for _, content := range []string{"Likes coffee", "Drinks coffee every morning", "Lives in Berlin", "Moved to Lisbon in May", "Is vegetarian"} {
+		memory, err := client.Add(ctx, content, core.WithUserID("user_001"), core.WithSource(core.Source{Document: content + ".md"}))
+		require.NoError(t, err)
+		memories = append(memories, memory)
+		time.Sleep(2 * time.Millisecond) // distinct creation times
+	}
+	other, err := client.Add(ctx, "Likes tea", core.WithUserID("user_002"))
+	require.NoError(t, err)
+
+	_, err = client.Consolidate(ctx, "")
+	assert.True(t, errors.Is(err, core.ErrInvalidInput))
+
+	// A dry run changes nothing
+	result, err := client.Consolidate(ctx, "user_001", core.WithConsolidateDryRun(true))
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 5, result.Reviewed)
+	require.Len(t, result.Actions, 3)
+	all, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	assert.Len(t, all, 5)
+
+	result, err = client.Consolidate(ctx, "user_001")
+	require.NoError(t, err)
+	require.Len(t, result.Actions, 3)
+
+	assert.Equal(t, "MERGE", result.Actions[0].Event)
+	assert.Equal(t, memories[0].ID, result.Actions[0].ID)
+	assert.Equal(t, []int64{memories[1].ID}, result.Actions[0].Replaced)
+	assert.Equal(t, []string{"Likes coffee", "Drinks coffee every morning"}, result.Actions[0].PreviousMemories)
+	assert.Equal(t, "RESOLVE", result.Actions[1].Event)
+	assert.Equal(t, memories[2].ID, result.Actions[1].ID)
+	assert.Equal(t, "PROMOTE", result.Actions[2].Event)
+	assert.Equal(t, memories[4].ID, result.Actions[2].ID)
+
+	merged, err := client.Get(ctx, memories[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Likes coffee and drinks it every morning", merged.Content)
+	assert.Equal(t, []core.Source{{Document: "Likes coffee.md"}, {Document: "Drinks coffee every morning.md"}}, merged.Sources)
+	_, err = client.Get(ctx, memories[1].ID)
+	assert.Error(t, err)
+
+	resolved, err := client.Get(ctx, memories[2].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Lives in Lisbon since May, previously Berlin", resolved.Content)
+	_, err = client.Get(ctx, memories[3].ID)
+	assert.Error(t, err)
+
+	promoted, err := client.Get(ctx, memories[4].ID)
+	require.NoError(t, err)
+	intelligenceData, ok := promoted.Metadata["intelligence"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "long_term", intelligenceData["memory_type"])
+	assert.InDelta(t, 1.0, promoted.RetentionStrength, 1e-9)
+
+	all, err = client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	// Other users are not reviewed
+	got, err := client.Get(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Likes tea", got.Content)
+}
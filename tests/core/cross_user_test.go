@@ -0,0 +1,122 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_SearchAcrossUsers(t *testing.T) {
+	cfg := newTestConfig(filepath.Join(t.TempDir(), "test_cross_user.db"))
+	cfg.Audit = &core.AuditConfig{Enabled: true}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := core.ContextWithActor(context.Background(), "trust-safety@example.com")
+
+	for _, add := range []struct{ userID, content string }{
+		{"user_001", "Card number 4111 1111 1111 1111"},
+		{"user_002", "Prefers window seats"},
+		{"user_001", "Lives in Berlin"},
+	} {
+		_, err := client.Add(ctx, add.content, core.WithUserID(add.userID), core.WithInfer(false))
+		require.NoError(t, err)
+	}
+
+	// Cross-user search must be allowed explicitly
+	_, err = client.SearchAcrossUsers(ctx, "card number")
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+	_, err = client.SearchAcrossUsers(ctx, "card number", core.WithAllowCrossUser(true), core.WithUserIDForSearch("user_001"))
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+
+	groups, err := client.SearchAcrossUsers(ctx, "card number", core.WithAllowCrossUser(true), core.WithLimit(10))
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	counts := make(map[string]int)
+	for i, group := range groups {
+		counts[group.UserID] = len(group.Memories)
+		assert.Equal(t, group.Memories[0].Score, group.TopScore)
+		for _, memory := range group.Memories {
+			assert.Equal(t, group.UserID, memory.UserID)
+		}
+		if i > 0 {
+			assert.GreaterOrEqual(t, groups[i-1].TopScore, group.TopScore)
+		}
+	}
+	assert.Equal(t, map[string]int{"user_001": 2, "user_002": 1}, counts)
+
+	// Only the allowed search is audited
+	entries, err := client.ListAuditEntries(ctx, core.WithAuditOperations("SearchAcrossUsers"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "trust-safety@example.com", entries[0].Actor)
+	assert.Equal(t, "card number", entries[0].Params["query"])
+}
+
+func TestClient_UnscopedReads(t *testing.T) {
+	cfg := newTestConfig(filepath.Join(t.TempDir(), "test_unscoped_reads.db"))
+	cfg.Audit = &core.AuditConfig{Enabled: true}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := core.ContextWithActor(context.Background(), "support@example.com")
+
+	_, err = client.Add(ctx, "Card number 4111 1111 1111 1111", core.WithUserID("user_001"), core.WithInfer(false))
+	require.NoError(t, err)
+
+	// A read of one user is not recorded
+	_, err = client.Search(ctx, "Card number 4111 1111 1111 1111", core.WithUserIDForSearch("user_002"))
+	require.NoError(t, err)
+	_, err = client.GetAll(ctx, core.WithUserIDForGetAll("user_002"))
+	require.NoError(t, err)
+	operations := core.WithAuditOperations("Search", "SearchByKeyword", "GetAll")
+	entries, err := client.ListAuditEntries(ctx, operations)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// Without a user ID, reads are rejected unless allowed
+	_, err = client.Search(ctx, "Card number 4111 1111 1111 1111")
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+	_, err = client.SearchByKeyword(ctx, "Card")
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+	_, err = client.SearchByEntity(ctx, "Alice")
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+	_, _, err = client.SearchWithDiagnostics(ctx, "Card")
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+	_, err = client.GetAll(ctx)
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+	for result := range client.SearchStream(ctx, "Card", 10) {
+		assert.ErrorIs(t, result.Error, core.ErrInvalidInput)
+	}
+	for result := range client.GetAllStream(ctx, 10) {
+		assert.ErrorIs(t, result.Error, core.ErrInvalidInput)
+	}
+
+	// Allowed, other users' memories are read, and the read is recorded
+	results, err := client.Search(ctx, "Card number 4111 1111 1111 1111", core.WithAllowCrossUser(true))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "user_001", results[0].UserID)
+	_, err = client.SearchByKeyword(ctx, "Card", core.WithAllowCrossUser(true))
+	require.NoError(t, err)
+	all, err := client.GetAll(ctx, core.WithAllowCrossUserForGetAll(true), core.WithLimitForGetAll(10))
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	entries, err = client.ListAuditEntries(ctx, operations)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "Search", entries[0].Operation)
+	assert.Equal(t, "support@example.com", entries[0].Actor)
+	assert.Empty(t, entries[0].UserID)
+	assert.Equal(t, "Card number 4111 1111 1111 1111", entries[0].Params["query"])
+	assert.Equal(t, "SearchByKeyword", entries[1].Operation)
+	assert.Equal(t, "Card", entries[1].Params["query"])
+	assert.Equal(t, "GetAll", entries[2].Operation)
+	assert.EqualValues(t, 10, entries[2].Params["limit"])
+}
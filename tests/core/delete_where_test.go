@@ -0,0 +1,80 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_DeleteWhere(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_delete_where.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	var events []*core.Event
+	client.Subscribe(func(event *core.Event) {
+		events = append(events, event)
+	}, core.EventDeleted)
+
+	for _, content := range []string{"Cache miss on profile load", "Retry after timeout", "Slow embedding call"} {
+		_, err := client.Add(ctx, content, core.WithUserID("user_001"),
+			core.WithMetadata(map[string]interface{}{"type": "debug"}))
+		require.NoError(t, err)
+	}
+	kept, err := client.Add(ctx, "User prefers tea", core.WithUserID("user_001"),
+		core.WithMetadata(map[string]interface{}{"type": "preference"}))
+	require.NoError(t, err)
+	_, err = client.Add(ctx, "Connection reset", core.WithUserID("user_002"),
+		core.WithMetadata(map[string]interface{}{"type": "debug"}))
+	require.NoError(t, err)
+
+	filters := map[string]interface{}{"type": "debug"}
+
+	// Without a confirmed count, DeleteWhere only counts
+	plan, err := client.DeleteWhere(ctx, filters, core.WithUserIDForDeleteWhere("user_001"))
+	require.NoError(t, err)
+	assert.Equal(t, &core.DeleteWhereResult{Matched: 3, DryRun: true}, plan)
+
+	// A stale count deletes nothing
+	result, err := client.DeleteWhere(ctx, filters, core.WithConfirmedCount(2), core.WithUserIDForDeleteWhere("user_001"))
+	assert.ErrorIs(t, err, core.ErrCountMismatch)
+	require.NotNil(t, result)
+	assert.Equal(t, int64(0), result.Deleted)
+	memories, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	assert.Len(t, memories, 4)
+
+	result, err = client.DeleteWhere(ctx, filters,
+		core.WithUserIDForDeleteWhere("user_001"), core.WithConfirmedCount(plan.Matched))
+	require.NoError(t, err)
+	assert.Equal(t, &core.DeleteWhereResult{Matched: 3, Deleted: 3}, result)
+
+	memories, err = client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	require.Len(t, memories, 1)
+	assert.Equal(t, kept.ID, memories[0].ID)
+	memories, err = client.GetAll(ctx, core.WithUserIDForGetAll("user_002"))
+	require.NoError(t, err)
+	assert.Len(t, memories, 1)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "DeleteWhere", events[0].Operation)
+	assert.Equal(t, "user_001", events[0].UserID)
+	assert.Equal(t, int64(3), events[0].Count)
+
+	// Time filters: nothing was created in the future
+	plan, err = client.DeleteWhere(ctx, nil, core.WithCreatedAfterForDeleteWhere(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), plan.Matched)
+
+	// A filter is required
+	_, err = client.DeleteWhere(ctx, nil)
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+}
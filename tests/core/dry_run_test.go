@@ -0,0 +1,72 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_IntelligentAddDryRun(t *testing.T) {
+	cfg := newChangesConfig(filepath.Join(t.TempDir(), "test_dry_run.db"))
+	cfg.LLM.Parameters = map[string]interface{}{"responses": []string{
+		`{"facts": [{"fact": "Lives in Berlin", "confidence": 0.95}, {"fact": "Likes tea", "confidence": 0.9}]}`,
+		`{"memory": [
+			{"id": "0", "text": "Lives in Lisbon", "event": "UPDATE", "old_memory": "Lives in Berlin"},
+			{"id": "1", "text": "Likes tea", "event": "DELETE"},
+			{"id": "2", "text": "Works as a nurse", "event": "ADD"}
+		]}`,
+	}}
+	cfg.Intelligence = &core.IntelligenceConfig{Enabled: true, DuplicateThreshold: 0.95, FallbackToSimpleAdd: true}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	existing := map[int64]string{}
+	for _, content := range []string{"Lives in Berlin", "Likes tea"} {
+		memory, err := client.Add(ctx, content, core.WithUserID("user_001"))
+		require.NoError(t, err)
+		existing[memory.ID] = content
+	}
+
+	// The plan lists the operations of the LLM without performing them
+	preview, err := client.IntelligentAdd(ctx, "I moved to Lisbon, I no longer like tea and I work as a nurse",
+		core.WithUserID("user_001"), core.WithDryRun(true))
+	require.NoError(t, err)
+	assert.True(t, preview.DryRun)
+	require.Len(t, preview.Results, 3)
+	sort.Slice(preview.Results, func(i, j int) bool { return preview.Results[i].Event < preview.Results[j].Event })
+
+	add, del, update := preview.Results[0], preview.Results[1], preview.Results[2]
+	assert.Equal(t, "ADD", add.Event)
+	assert.Zero(t, add.ID)
+	assert.Equal(t, "Works as a nurse", add.Memory)
+	assert.Equal(t, "DELETE", del.Event)
+	assert.Contains(t, existing, del.ID)
+	assert.Equal(t, "UPDATE", update.Event)
+	assert.Contains(t, existing, update.ID)
+	assert.NotEqual(t, del.ID, update.ID)
+	assert.Equal(t, "Lives in Lisbon", update.Memory)
+
+	// Add cannot preview, even with a fallback to a simple add configured
+	_, err = client.Add(ctx, "Works as a nurse", core.WithUserID("user_001"), core.WithDryRun(true))
+	assert.True(t, errors.Is(err, core.ErrInvalidInput))
+	_, err = client.Add(ctx, "Works as a nurse", core.WithUserID("user_001"), core.WithInfer(true), core.WithDryRun(true))
+	assert.True(t, errors.Is(err, core.ErrInvalidInput))
+
+	// The store is unchanged
+	all, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	stored := map[int64]string{}
+	for _, memory := range all {
+		stored[memory.ID] = memory.Content
+	}
+	assert.Equal(t, existing, stored)
+}
@@ -0,0 +1,86 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_SkipExactDuplicates(t *testing.T) {
+	client, err := core.NewClient(&core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite: &core.SQLiteConfig{
+				DBPath: filepath.Join(t.TempDir(), "exact.db"), CollectionName: "memories", EmbeddingModelDims: 8,
+			},
+		},
+		LLM:                 core.LLMConfig{Provider: "mock"},
+		Embedder:            core.EmbedderConfig{Provider: "mock", Dimensions: 8},
+		SkipExactDuplicates: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	first, err := client.Add(ctx, "User's name is Alice", core.WithUserID("alice"))
+	require.NoError(t, err)
+
+	// Resent content returns the stored memory, even with Infer
+	again, err := client.Add(ctx, "User's name is Alice", core.WithUserID("alice"), core.WithInfer(true))
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, again.ID)
+	assert.Equal(t, "User's name is Alice", again.Content)
+
+	// Other users and other content are added
+	other, err := client.Add(ctx, "User's name is Alice", core.WithUserID("bob"))
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, other.ID)
+	_, err = client.Add(ctx, "User's name is Alice.", core.WithUserID("alice"))
+	require.NoError(t, err)
+
+	memories, err := client.GetAll(ctx, core.WithUserIDForGetAll("alice"))
+	require.NoError(t, err)
+	assert.Len(t, memories, 2)
+
+	// Updated content is matched by its new hash
+	_, err = client.Update(ctx, first.ID, "User's name is Alice Smith")
+	require.NoError(t, err)
+	again, err = client.Add(ctx, "User's name is Alice Smith", core.WithUserID("alice"))
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, again.ID)
+	renamed, err := client.Add(ctx, "User's name is Alice", core.WithUserID("alice"))
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, renamed.ID)
+}
+
+func TestClient_SkipExactDuplicate(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "idempotent.db")))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	first, err := client.Add(ctx, "User's name is Alice", core.WithUserID("alice"), core.WithSkipExactDuplicate(true))
+	require.NoError(t, err)
+	assert.Equal(t, core.ContentHash("User's name is Alice"), first.Hash)
+
+	// A retried Add returns the first memory
+	retried, err := client.Add(ctx, "User's name is Alice", core.WithUserID("alice"), core.WithSkipExactDuplicate(true))
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, retried.ID)
+
+	// Without the option, the content is added again
+	copied, err := client.Add(ctx, "User's name is Alice", core.WithUserID("alice"))
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, copied.ID)
+
+	found, err := client.GetByHash(ctx, core.ContentHash("User's name is Alice"), core.WithUserIDForGet("alice"))
+	require.NoError(t, err)
+	assert.Equal(t, "User's name is Alice", found.Content)
+	_, err = client.GetByHash(ctx, core.ContentHash("User's name is Alice"), core.WithUserIDForGet("bob"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/embedder"
+	"github.com/oceanbase/powermem-go/pkg/embedder/mock"
+)
+
+func TestClient_EmbedderRoutes(t *testing.T) {
+	config := newChangesConfig(filepath.Join(t.TempDir(), "test_embedder_routes.db"))
+	// A different model with the dimension of the store
+	config.EmbedderRoutes = []core.EmbedderRoute{{
+		Name:        "code",
+		MemoryTypes: []string{"code"},
+		Embedder:    core.EmbedderConfig{Provider: "mock", Dimensions: 128, TruncateDimensions: 64},
+	}, {
+		Name:      "multilingual",
+		Languages: []string{"zh"},
+		Embedder:  core.EmbedderConfig{Provider: "mock", Dimensions: 256, TruncateDimensions: 64},
+	}}
+	client, err := core.NewClient(config)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	const code = "func main prints hello world to the terminal and exits with status zero"
+	const prose = "Prefers window seats on long flights to visit family abroad"
+	codeMemory, err := client.Add(ctx, code, core.WithUserID("user_001"), core.WithMemoryType("code"))
+	require.NoError(t, err)
+	proseMemory, err := client.Add(ctx, prose, core.WithUserID("user_001"))
+	require.NoError(t, err)
+	zhMemory, err := client.Add(ctx, "我 喜欢 靠窗 的 座位", core.WithUserID("user_001"),
+		core.WithMetadata(map[string]interface{}{"language": "zh"}))
+	require.NoError(t, err)
+
+	// Each memory is embedded by its route and records it
+	base, err := mock.NewClient(&mock.Config{Dimensions: 128})
+	require.NoError(t, err)
+	expected, err := embedder.Truncate(base, 64).Embed(ctx, code)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, expected, codeMemory.Embedding, 1e-9)
+	assert.Equal(t, "code", codeMemory.Metadata["embedder"])
+	assert.NotContains(t, proseMemory.Metadata, "embedder")
+	assert.Equal(t, "multilingual", zhMemory.Metadata["embedder"])
+
+	// Search embeds the query with every embedder and merges the results
+	results, err := client.Search(ctx, code, core.WithUserIDForSearch("user_001"), core.WithLimit(3))
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, codeMemory.ID, results[0].ID)
+	assert.InDelta(t, 1.0, results[0].Score, 1e-6)
+	results, err = client.Search(ctx, prose, core.WithUserIDForSearch("user_001"), core.WithLimit(3))
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, proseMemory.ID, results[0].ID)
+	assert.InDelta(t, 1.0, results[0].Score, 1e-6)
+
+	// A memory_type filter selects the code embedder only
+	results, err = client.Search(ctx, code, core.WithUserIDForSearch("user_001"),
+		core.WithFilters(map[string]interface{}{"memory_type": "code"}))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, codeMemory.ID, results[0].ID)
+
+	// Updates re-embed with the embedder of the memory
+	const updated = "func main prints goodbye world to the terminal and exits with status one"
+	updatedMemory, err := client.Update(ctx, codeMemory.ID, updated,
+		core.WithMetadataForUpdate(map[string]interface{}{"memory_type": "code"}))
+	require.NoError(t, err)
+	expected, err = embedder.Truncate(base, 64).Embed(ctx, updated)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, expected, updatedMemory.Embedding, 1e-9)
+	assert.Equal(t, "code", updatedMemory.Metadata["embedder"])
+
+	// Routes must produce vectors of the dimension of the store
+	config.EmbedderRoutes[0].Embedder.TruncateDimensions = 0
+	err = config.Validate()
+	assert.ErrorContains(t, err, "embedder_routes[0].embedder.dimensions")
+}
@@ -0,0 +1,112 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_Embeddings(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_embeddings.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	added, err := client.Add(ctx, "Prefers window seats", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	require.NotEmpty(t, added.Embedding)
+
+	// Search leaves embeddings out unless asked for
+	results, err := client.Search(ctx, "Prefers window seats", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].Embedding)
+	results, err = client.Search(ctx, "Prefers window seats", core.WithUserIDForSearch("user_001"), core.WithIncludeEmbeddings(true))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, added.Embedding, results[0].Embedding)
+
+	results, err = client.SearchByKeyword(ctx, "window", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].Embedding)
+
+	// GetAll returns them unless told not to
+	all, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, added.Embedding, all[0].Embedding)
+	all, err = client.GetAll(ctx, core.WithUserIDForGetAll("user_001"), core.WithoutEmbeddings())
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Nil(t, all[0].Embedding)
+	assert.Equal(t, "Prefers window seats", all[0].Content)
+}
+
+func TestClient_Fields(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_fields.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	_, err = client.Add(ctx, "Prefers aisle seats",
+		core.WithUserID("user_001"),
+		core.WithMetadata(map[string]interface{}{"source": "chat"}),
+	)
+	require.NoError(t, err)
+
+	all, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_001"),
+		core.WithFieldsForGetAll(core.MemoryFieldContent, core.MemoryFieldMetadata))
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, "Prefers aisle seats", all[0].Content)
+	assert.Equal(t, "chat", all[0].Metadata["source"])
+	assert.Nil(t, all[0].Embedding)
+	assert.True(t, all[0].CreatedAt.IsZero())
+
+	// Searches read the content and metadata they need, but only return fields
+	results, err := client.Search(ctx, "Prefers aisle seats", core.WithUserIDForSearch("user_001"),
+		core.WithFields(core.MemoryFieldTimestamps))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Empty(t, results[0].Content)
+	assert.Nil(t, results[0].Metadata)
+	assert.False(t, results[0].CreatedAt.IsZero())
+	assert.NotZero(t, results[0].ID)
+
+	results, err = client.SearchByKeyword(ctx, "aisle", core.WithUserIDForSearch("user_001"),
+		core.WithFields(core.MemoryFieldContent))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Prefers aisle seats", results[0].Content)
+	assert.Nil(t, results[0].Metadata)
+}
+
+func TestClient_TruncateDimensions(t *testing.T) {
+	config := newChangesConfig(filepath.Join(t.TempDir(), "test_truncate.db"))
+	config.Embedder.TruncateDimensions = 32
+	config.VectorStore.SQLite.EmbeddingModelDims = 0 // follows the truncated dimension
+	client, err := core.NewClient(config)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	added, err := client.Add(ctx, "Prefers window seats on long flights to visit family abroad", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.Len(t, added.Embedding, 32)
+
+	// Queries are truncated the same way
+	results, err := client.Search(ctx, "Prefers window seats on long flights to visit family abroad", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.InDelta(t, 1.0, results[0].Score, 1e-6)
+
+	config.Embedder.TruncateDimensions = 64
+	err = config.Validate()
+	assert.ErrorContains(t, err, "embedder.truncate_dimensions")
+}
@@ -0,0 +1,96 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_SearchByEntity(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_entities.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	hiking, err := client.Add(ctx, "Went hiking with Alice in the Alps",
+		core.WithUserID("user_001"), core.WithEntities("Alice", "Alps", "alice"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice", "Alps"}, hiking.Entities)
+
+	got, err := client.Get(ctx, hiking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice", "Alps"}, got.Entities)
+
+	sister, err := client.RememberRelationship(ctx, "Alice", "is the sister of", "Tom", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice", "Tom"}, sister.Entities)
+
+	// Mentions the name without the annotation
+	_, err = client.Add(ctx, "Alice is a common name", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	_, err = client.Add(ctx, "Met Alice at the conference", core.WithUserID("user_002"), core.WithEntities("Alice"))
+	require.NoError(t, err)
+
+	results, err := client.SearchByEntity(ctx, "alice", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, sister.ID, results[0].ID)
+	assert.Equal(t, hiking.ID, results[1].ID)
+	assert.Equal(t, 1.0, results[0].Score)
+
+	results, err = client.SearchByEntity(ctx, "Tom", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, sister.ID, results[0].ID)
+
+	results, err = client.SearchByEntity(ctx, "Alice", core.WithUserIDForSearch("user_001"), core.WithLimit(1))
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	results, err = client.SearchByEntity(ctx, "Bob", core.WithAllowCrossUser(true))
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	_, err = client.SearchByEntity(ctx, " ")
+	assert.True(t, errors.Is(err, core.ErrInvalidInput))
+}
+
+func TestClient_IntelligentAddEntities(t *testing.T) {
+	cfg := newChangesConfig(filepath.Join(t.TempDir(), "test_intelligent_entities.db"))
+	cfg.LLM.Parameters = map[string]interface{}{"responses": []string{
+		`{"facts": [{"fact": "Went hiking with Alice in the Alps", "confidence": 0.95, "entities": ["Alice", "Alps"]}]}`,
+		`{"memory": [{"id": "0", "text": "Went hiking with Alice in the Alps", "event": "ADD"}]}`,
+		`{"facts": [{"fact": "Went hiking with Alice and Bob in the Alps", "confidence": 0.95, "entities": ["Alice", "Bob", "Alps"]}]}`,
+		`{"memory": [{"id": "0", "text": "Went hiking with Alice and Bob in the Alps", "event": "UPDATE", "old_memory": "Went hiking with Alice in the Alps"}]}`,
+	}}
+	cfg.Intelligence = &core.IntelligenceConfig{Enabled: true, DuplicateThreshold: 0.95}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	result, err := client.IntelligentAdd(ctx, "I went hiking with Alice in the Alps",
+		core.WithUserID("user_001"), core.WithEntities("Trip 2024"))
+	require.NoError(t, err)
+	require.Len(t, result.Results, 1)
+
+	memory, err := client.Get(ctx, result.Results[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Trip 2024", "Alice", "Alps"}, memory.Entities)
+
+	// An update keeps the entities of the memory and adds the new ones
+	_, err = client.IntelligentAdd(ctx, "Bob came along too", core.WithUserID("user_001"))
+	require.NoError(t, err)
+
+	results, err := client.SearchByEntity(ctx, "Bob", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, memory.ID, results[0].ID)
+	assert.Equal(t, []string{"Trip 2024", "Alice", "Alps", "Bob"}, results[0].Entities)
+}
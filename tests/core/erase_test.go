@@ -0,0 +1,65 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_EraseUser(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_erase.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	var events []*core.Event
+	client.Subscribe(func(event *core.Event) {
+		events = append(events, event)
+	}, core.EventErased)
+
+	memory, err := client.Add(ctx, "User loves hiking", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	_, err = client.Update(ctx, memory.ID, "User loves hiking in the Alps")
+	require.NoError(t, err)
+	_, err = client.Add(ctx, "User prefers tea", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	kept, err := client.Add(ctx, "User likes jazz", core.WithUserID("user_002"))
+	require.NoError(t, err)
+
+	report, err := client.EraseUser(ctx, "user_001")
+	require.NoError(t, err)
+	assert.Equal(t, "user_001", report.UserID)
+	assert.Equal(t, int64(2), report.Memories)
+	assert.Equal(t, int64(3), report.Changes)
+	assert.True(t, report.Verified)
+	assert.False(t, report.CompletedAt.Before(report.StartedAt))
+
+	memories, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	assert.Empty(t, memories)
+	_, err = client.Get(ctx, kept.ID)
+	require.NoError(t, err)
+
+	// Only the erasure itself is left in the change log for the user
+	changes, err := client.ListChanges(ctx, core.WithUserIDForChanges("user_001"))
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, core.EventErased, changes[0].Type)
+	assert.Equal(t, int64(2), changes[0].Count)
+	assert.Nil(t, changes[0].Memory)
+	changes, err = client.ListChanges(ctx, core.WithUserIDForChanges("user_002"))
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "user_001", events[0].UserID)
+	assert.Equal(t, "EraseUser", events[0].Operation)
+
+	_, err = client.EraseUser(ctx, "")
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+}
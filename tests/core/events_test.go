@@ -0,0 +1,225 @@
+package core_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/webhook"
+)
+
+// newEventsClient creates a client with mock providers.
+func newEventsClient(t *testing.T, cfg *core.Config) *core.Client {
+	t.Helper()
+	if cfg == nil {
+		cfg = &core.Config{}
+	}
+	cfg.VectorStore = core.VectorStoreConfig{
+		Provider: "sqlite",
+		SQLite:   &core.SQLiteConfig{DBPath: filepath.Join(t.TempDir(), "test_events.db"), EmbeddingModelDims: 64},
+	}
+	if cfg.LLM.Provider == "" {
+		cfg.LLM = core.LLMConfig{Provider: "mock"}
+	}
+	cfg.Embedder = core.EmbedderConfig{Provider: "mock", Dimensions: 64}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestClient_Subscribe(t *testing.T) {
+	client := newEventsClient(t, nil)
+	ctx := core.ContextWithActor(context.Background(), "admin")
+
+	var events []*core.Event
+	unsubscribe := client.Subscribe(func(event *core.Event) {
+		events = append(events, event)
+	})
+	var deletions []*core.Event
+	client.Subscribe(func(event *core.Event) {
+		// Handlers run after the client is unlocked, so they may use it
+		_, err := client.GetAll(context.Background(), core.WithUserIDForGetAll("user_001"))
+		assert.NoError(t, err)
+		deletions = append(deletions, event)
+	}, core.EventDeleted)
+
+	memory, err := client.Add(ctx, "User loves hiking", core.WithUserID("user_001"), core.WithMetadata(map[string]interface{}{"source": "chat"}))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, core.EventCreated, events[0].Type)
+	assert.Equal(t, "Add", events[0].Operation)
+	assert.Equal(t, "admin", events[0].Actor)
+	assert.Equal(t, memory.ID, events[0].MemoryID)
+	assert.Equal(t, "user_001", events[0].UserID)
+	assert.Equal(t, "User loves hiking", events[0].Memory.Content)
+	assert.Nil(t, events[0].Memory.Embedding, "embeddings are omitted")
+
+	_, err = client.Update(ctx, memory.ID, "User loves hiking in the Alps",
+		core.WithMetadataForUpdate(map[string]interface{}{"source": "profile"}))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, core.EventUpdated, events[1].Type)
+	require.NotNil(t, events[1].Diff)
+	assert.Equal(t, "User loves hiking", events[1].Diff.OldContent)
+	assert.Equal(t, "User loves hiking in the Alps", events[1].Diff.NewContent)
+	assert.Equal(t, "chat", events[1].Diff.OldMetadata["source"])
+	assert.Equal(t, "profile", events[1].Diff.NewMetadata["source"])
+
+	require.NoError(t, client.Delete(ctx, memory.ID))
+	require.Len(t, events, 3)
+	assert.Equal(t, core.EventDeleted, events[2].Type)
+	assert.Equal(t, memory.ID, events[2].MemoryID)
+	require.NotNil(t, events[2].Memory)
+	assert.Equal(t, "User loves hiking in the Alps", events[2].Memory.Content)
+
+	require.NoError(t, client.DeleteAll(ctx, core.WithUserIDForDeleteAll("user_001")))
+	require.Len(t, events, 4)
+	assert.Equal(t, "DeleteAll", events[3].Operation)
+	assert.Zero(t, events[3].MemoryID)
+	assert.Equal(t, "user_001", events[3].UserID)
+
+	// Failed mutations publish nothing
+	assert.Error(t, client.Delete(ctx, memory.ID))
+	assert.Len(t, events, 4)
+
+	assert.Len(t, deletions, 2, "only deletions are delivered to the filtered handler")
+
+	unsubscribe()
+	_, err = client.Add(ctx, "User prefers tea", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.Len(t, events, 4)
+}
+
+func TestClient_SubscribeIntelligentAdd(t *testing.T) {
+	client := newEventsClient(t, &core.Config{
+		LLM: core.LLMConfig{
+			Provider: "mock",
+			Parameters: map[string]interface{}{"responses": []string{
+				`{"facts": [{"fact": "Loves hiking in the Alps", "confidence": 0.9}, {"fact": "Lives in Berlin", "confidence": 0.95}]}`,
+				`{"memory": [{"id": "0", "text": "Loves hiking in the Alps", "event": "UPDATE", "old_memory": "Likes hiking"}, {"id": "1", "text": "Lives in Berlin", "event": "ADD"}]}`,
+			}},
+		},
+		Intelligence: &core.IntelligenceConfig{Enabled: true, DuplicateThreshold: 0.95},
+	})
+	ctx := context.Background()
+
+	existing, err := client.Add(ctx, "Likes hiking", core.WithUserID("user_001"))
+	require.NoError(t, err)
+
+	var events []*core.Event
+	client.Subscribe(func(event *core.Event) {
+		events = append(events, event)
+	})
+	_, err = client.IntelligentAdd(ctx, "I love hiking in the Alps and I live in Berlin", core.WithUserID("user_001"))
+	require.NoError(t, err)
+
+	require.Len(t, events, 2)
+	assert.Equal(t, core.EventUpdated, events[0].Type)
+	assert.Equal(t, "IntelligentAdd", events[0].Operation)
+	assert.Equal(t, existing.ID, events[0].MemoryID)
+	require.NotNil(t, events[0].Diff)
+	assert.Equal(t, "Likes hiking", events[0].Diff.OldContent)
+	assert.Equal(t, "Loves hiking in the Alps", events[0].Diff.NewContent)
+	assert.Equal(t, core.EventCreated, events[1].Type)
+	assert.Equal(t, "Lives in Berlin", events[1].Memory.Content)
+}
+
+func TestClient_SubscribePurgeExpired(t *testing.T) {
+	client := newEventsClient(t, nil)
+	ctx := context.Background()
+
+	var events []*core.Event
+	client.Subscribe(func(event *core.Event) {
+		events = append(events, event)
+	}, core.EventForgotten)
+
+	_, err := client.Add(ctx, "Temporary note", core.WithUserID("user_001"), core.WithExpiresAt(time.Now().Add(-time.Minute)))
+	require.NoError(t, err)
+	deleted, err := client.PurgeExpired(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, int64(1), events[0].Count)
+
+	// Nothing to purge, no event
+	_, err = client.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestClient_Webhooks(t *testing.T) {
+	type delivery struct {
+		header http.Header
+		body   []byte
+	}
+	var mu sync.Mutex
+	var deliveries []delivery
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		deliveries = append(deliveries, delivery{header: r.Header, body: body})
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	client := newEventsClient(t, &core.Config{
+		Webhooks: []core.WebhookConfig{{
+			URL:    server.URL,
+			Secret: "s3cret",
+			Events: []core.EventType{core.EventDeleted},
+		}},
+	})
+	ctx := core.ContextWithActor(context.Background(), "gdpr-tool")
+
+	memory, err := client.Add(ctx, "User loves hiking", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	require.NoError(t, client.Delete(ctx, memory.ID))
+
+	// Close delivers the pending webhooks
+	require.NoError(t, client.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, deliveries, 1, "only the subscribed events are sent")
+	d := deliveries[0]
+	assert.Equal(t, string(core.EventDeleted), d.header.Get(webhook.EventHeader))
+	assert.True(t, hmac.Equal([]byte(webhook.Sign("s3cret", d.body)), []byte(d.header.Get(webhook.SignatureHeader))))
+
+	var event core.Event
+	require.NoError(t, json.Unmarshal(d.body, &event))
+	assert.Equal(t, core.EventDeleted, event.Type)
+	assert.Equal(t, "gdpr-tool", event.Actor)
+	assert.Equal(t, memory.ID, event.MemoryID)
+	assert.Equal(t, "user_001", event.UserID)
+}
+
+func TestConfig_ValidateWebhooks(t *testing.T) {
+	cfg := &core.Config{
+		VectorStore: core.VectorStoreConfig{Provider: "sqlite"},
+		LLM:         core.LLMConfig{Provider: "mock"},
+		Embedder:    core.EmbedderConfig{Provider: "mock"},
+		Webhooks: []core.WebhookConfig{
+			{URL: "ftp://example.com/hook", Events: []core.EventType{"memory.touched"}},
+			{TimeoutSeconds: -1},
+		},
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `webhooks[0].url: invalid URL "ftp://example.com/hook"`)
+	assert.Contains(t, err.Error(), `webhooks[0].events: unknown event type "memory.touched"`)
+	assert.Contains(t, err.Error(), "webhooks[1].url: is required")
+	assert.Contains(t, err.Error(), "webhooks[1].timeout_seconds: must not be negative")
+}
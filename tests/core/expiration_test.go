@@ -0,0 +1,106 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+)
+
+func TestClient_ExpirationFollowsClock(t *testing.T) {
+	// A day behind the system time: memories expired by the system time are
+	// still live by the clock
+	start := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	clock := intelligence.NewManualClock(start)
+	cfg := newChangesConfig(filepath.Join(t.TempDir(), "test_expiration_clock.db"))
+	cfg.Clock = clock
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	memory, err := client.Add(ctx, "Parked on level 3", core.WithUserID("user_001"), core.WithTTL(time.Hour))
+	require.NoError(t, err)
+	require.NotNil(t, memory.ExpiresAt)
+	assert.True(t, start.Add(time.Hour).Equal(*memory.ExpiresAt))
+	scratch := client.WorkingMemory("run_001", core.WithWorkingMemoryUserID("user_001"))
+	_, err = scratch.Add(ctx, "Comparing flights")
+	require.NoError(t, err)
+
+	_, err = client.Get(ctx, memory.ID)
+	require.NoError(t, err)
+	results, err := client.Search(ctx, "Parked on level 3", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+	items, err := scratch.Items(ctx)
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+	deleted, err := client.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	// Both expire when the clock passes their deadlines
+	clock.Advance(2 * time.Hour)
+	_, err = client.Get(ctx, memory.ID)
+	assert.Error(t, err)
+	results, err = client.Search(ctx, "Parked on level 3", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	all, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	assert.Empty(t, all)
+	items, err = scratch.Items(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+	deleted, err = client.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+}
+
+func TestClient_TimestampsFollowClock(t *testing.T) {
+	start := time.Date(2020, 3, 1, 9, 0, 0, 0, time.UTC)
+	clock := intelligence.NewManualClock(start)
+	cfg := newChangesConfig(filepath.Join(t.TempDir(), "test_timestamps_clock.db"))
+	cfg.Clock = clock
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	// New memories are created at the time of the clock
+	memory, err := client.Add(ctx, "Parked on level 3", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.True(t, start.Equal(memory.CreatedAt), memory.CreatedAt)
+	assert.True(t, start.Equal(memory.UpdatedAt), memory.UpdatedAt)
+	stored, err := client.Get(ctx, memory.ID)
+	require.NoError(t, err)
+	assert.True(t, start.Equal(stored.CreatedAt), stored.CreatedAt)
+	assert.True(t, start.Equal(stored.UpdatedAt), stored.UpdatedAt)
+
+	// Updates and shares are stamped with it too
+	clock.Advance(time.Hour)
+	updated, err := client.Update(ctx, memory.ID, "Parked on level 4")
+	require.NoError(t, err)
+	assert.True(t, start.Equal(updated.CreatedAt), updated.CreatedAt)
+	assert.True(t, start.Add(time.Hour).Equal(updated.UpdatedAt), updated.UpdatedAt)
+
+	clock.Advance(time.Hour)
+	shared, err := client.ShareMemory(ctx, memory.ID, "user_002")
+	require.NoError(t, err)
+	require.NotNil(t, shared.SharedFrom)
+	assert.True(t, start.Add(2*time.Hour).Equal(shared.SharedFrom.GrantedAt), shared.SharedFrom.GrantedAt)
+	assert.True(t, start.Add(2*time.Hour).Equal(shared.CreatedAt), shared.CreatedAt)
+
+	// So are the events
+	changes, err := client.ListChanges(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, changes)
+	assert.True(t, start.Equal(changes[0].Time), changes[0].Time)
+	assert.True(t, start.Add(2*time.Hour).Equal(changes[len(changes)-1].Time), changes[len(changes)-1].Time)
+}
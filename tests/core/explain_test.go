@@ -0,0 +1,97 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_SearchExplain(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_explain.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	_, err = client.Add(ctx, "Likes coffee",
+		core.WithUserID("user_001"),
+		core.WithAgentID("agent_001"),
+		core.WithTags("food"),
+		core.WithMetadata(map[string]interface{}{"category": "preference"}),
+	)
+	require.NoError(t, err)
+
+	// Results are only explained on request
+	results, err := client.Search(ctx, "Likes coffee", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].Explanation)
+
+	results, err = client.Search(ctx, "Likes coffee",
+		core.WithUserIDForSearch("user_001"),
+		core.WithAgentIDForSearch("agent_001"),
+		core.WithTagsForSearch("food"),
+		core.WithFilters(map[string]interface{}{"category": "preference"}),
+		core.WithMinScore(0.5),
+		core.WithExplain(true),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	explanation := results[0].Explanation
+	require.NotNil(t, explanation)
+	assert.Equal(t, "Likes coffee", explanation.Query)
+	assert.Empty(t, explanation.OriginalQuery)
+	assert.Equal(t, results[0].Score, explanation.Similarity)
+	assert.Equal(t, results[0].Score, explanation.Score)
+	assert.Nil(t, explanation.ScoreComponents)
+	assert.Nil(t, explanation.Weights)
+	assert.Equal(t, 1.0, explanation.RetentionStrength)
+	assert.Equal(t, map[string]interface{}{
+		"user_id":           "user_001",
+		"agent_id":          "agent_001",
+		"tags":              []string{"food"},
+		"min_score":         0.5,
+		"metadata.category": "preference",
+	}, explanation.MatchedFilters)
+}
+
+func TestClient_SearchExplainRanking(t *testing.T) {
+	cfg := newChangesConfig(filepath.Join(t.TempDir(), "test_explain_ranking.db"))
+	cfg.Intelligence = &core.IntelligenceConfig{Enabled: true, Ranking: &core.RankingConfig{}}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	_, err = client.Add(ctx, "Likes coffee", core.WithUserID("user_001"))
+	require.NoError(t, err)
+
+	results, diag, err := client.SearchWithDiagnostics(ctx, "Likes coffee",
+		core.WithUserIDForSearch("user_001"),
+		core.WithExplain(true),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, int64(1), diag.Candidates)
+
+	// The explanation breaks the ranked score down
+	explanation := results[0].Explanation
+	require.NotNil(t, explanation)
+	require.NotNil(t, explanation.ScoreComponents)
+	assert.Equal(t, explanation.ScoreComponents.Similarity, explanation.Similarity)
+	assert.Equal(t, results[0].Score, explanation.Score)
+	assert.Equal(t, explanation.ScoreComponents.Final, explanation.Score)
+	assert.NotNil(t, explanation.ScoreComponents.Recency)
+	assert.Equal(t, &core.RankingConfig{
+		SimilarityWeight:     0.6,
+		RecencyWeight:        0.15,
+		ImportanceWeight:     0.15,
+		RetentionWeight:      0.1,
+		RecencyHalfLifeHours: 168,
+	}, explanation.Weights)
+	assert.Equal(t, map[string]interface{}{"user_id": "user_001"}, explanation.MatchedFilters)
+}
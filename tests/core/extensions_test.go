@@ -0,0 +1,56 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_Extensions(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "extensions.db")
+	client, err := core.NewClient(newTestConfig(dbPath))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	ext := client.Extensions()
+	require.NotNil(t, ext.LLM)
+	require.NotNil(t, ext.Embedder)
+	assert.Nil(t, ext.DedupManager)
+	assert.Nil(t, ext.EbbinghausManager)
+	assert.Nil(t, ext.IntelligentManager)
+
+	// The embedder is the one of the client
+	memory, err := client.Add(ctx, "I like hiking", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	embedding, err := ext.Embedder.Embed(ctx, "I like hiking")
+	require.NoError(t, err)
+	assert.Len(t, embedding, 64)
+
+	// Managers created by a reload are returned by later calls
+	reloaded := newTestConfig(dbPath)
+	reloaded.Intelligence = &core.IntelligenceConfig{
+		Enabled:             true,
+		DecayRate:           0.1,
+		ReinforcementFactor: 0.3,
+		DuplicateThreshold:  0.95,
+	}
+	require.NoError(t, client.Reload(ctx, reloaded))
+	assert.Nil(t, ext.DedupManager)
+
+	ext = client.Extensions()
+	assert.NotNil(t, ext.DedupManager)
+	assert.NotNil(t, ext.EbbinghausManager)
+	assert.NotNil(t, ext.IntelligentManager)
+
+	// The stored embedding matches the one of ext.Embedder
+	isDup, existingID, err := ext.DedupManager.CheckDuplicate(ctx, embedding, "user_001", "")
+	require.NoError(t, err)
+	assert.True(t, isDup)
+	assert.Equal(t, memory.ID, existingID)
+}
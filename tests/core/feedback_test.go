@@ -0,0 +1,198 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+)
+
+func TestClient_FeedbackRetention(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_feedback.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	memory, err := client.Add(ctx, "Prefers window seats", core.WithUserID("user_001"))
+	require.NoError(t, err)
+
+	// Other users cannot give feedback on the memory
+	_, err = client.Feedback(ctx, memory.ID, core.FeedbackNegative, "", core.WithUserIDForFeedback("user_002"))
+	assert.Error(t, err)
+
+	result, err := client.Feedback(ctx, memory.ID, core.FeedbackNegative, "not relevant", core.WithUserIDForFeedback("user_001"))
+	require.NoError(t, err)
+	assert.False(t, result.Flagged)
+	assert.InDelta(t, 0.7, result.Memory.RetentionStrength, 1e-9)
+
+	got, err := client.Get(ctx, memory.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.7, got.RetentionStrength, 1e-9)
+	assert.Equal(t, memory.Version+1, got.Version)
+
+	result, err = client.Feedback(ctx, memory.ID, core.FeedbackPositive, "")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.79, result.Memory.RetentionStrength, 1e-9)
+	require.NotNil(t, result.Memory.LastAccessedAt)
+
+	got, err = client.Get(ctx, memory.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.79, got.RetentionStrength, 1e-9)
+	require.NotNil(t, got.LastAccessedAt)
+	feedback, ok := got.Metadata["feedback"].(map[string]interface{})
+	require.True(t, ok)
+	assert.EqualValues(t, 1, feedback["negative"])
+	assert.EqualValues(t, 1, feedback["positive"])
+	assert.Equal(t, "positive", feedback["last"])
+
+	_, err = client.Feedback(ctx, memory.ID, core.FeedbackType("meh"), "")
+	assert.True(t, errors.Is(err, core.ErrInvalidInput))
+}
+
+func TestClient_FeedbackRetentionPolicies(t *testing.T) {
+	cfg := newChangesConfig(filepath.Join(t.TempDir(), "test_feedback_policies.db"))
+	cfg.Intelligence = &core.IntelligenceConfig{
+		Enabled:             true,
+		DecayRate:           0.1,
+		ReinforcementFactor: 0.3,
+		DuplicateThreshold:  0.95,
+		AgentPolicies:       map[string]core.RetentionPolicy{"task_manager": {ReinforcementFactor: 0.9}},
+	}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	task, err := client.Add(ctx, "Finish the quarterly report", core.WithUserID("user_001"), core.WithAgentID("task_manager"))
+	require.NoError(t, err)
+	preference, err := client.Add(ctx, "Prefers window seats", core.WithUserID("user_001"), core.WithAgentID("assistant"))
+	require.NoError(t, err)
+
+	result, err := client.Feedback(ctx, task.ID, core.FeedbackNegative, "")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.1, result.Memory.RetentionStrength, 1e-9, "the task manager's policy applies")
+	result, err = client.Feedback(ctx, preference.ID, core.FeedbackNegative, "")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.7, result.Memory.RetentionStrength, 1e-9)
+
+	cfg.Intelligence.MemoryTypePolicies = map[string]core.RetentionPolicy{"preference": {DecayRate: -1}}
+	assert.ErrorIs(t, cfg.Validate(), core.ErrInvalidConfig)
+}
+
+func TestClient_FeedbackClock(t *testing.T) {
+	cfg := newChangesConfig(filepath.Join(t.TempDir(), "test_feedback_clock.db"))
+	clock := intelligence.NewManualClock(time.Now())
+	cfg.Clock = clock
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	memory, err := client.Add(ctx, "Prefers window seats", core.WithUserID("user_001"))
+	require.NoError(t, err)
+
+	clock.Advance(30 * 24 * time.Hour)
+	result, err := client.Feedback(ctx, memory.ID, core.FeedbackPositive, "")
+	require.NoError(t, err)
+	require.NotNil(t, result.Memory.LastAccessedAt)
+	assert.True(t, clock.Now().Equal(*result.Memory.LastAccessedAt), "feedback is timestamped by the client's clock")
+	feedback, ok := result.Memory.Metadata["feedback"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, clock.Now().UTC().Format(time.RFC3339), feedback["last_at"])
+}
+
+func TestClient_FeedbackIncorrect(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_feedback_incorrect.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	memory, err := client.Add(ctx, "Lives in Berlin", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	_, err = client.Add(ctx, "Works as a nurse", core.WithUserID("user_001"))
+	require.NoError(t, err)
+
+	result, err := client.Feedback(ctx, memory.ID, core.FeedbackIncorrect, "I never lived in Berlin")
+	require.NoError(t, err)
+	assert.True(t, result.Flagged)
+	assert.Equal(t, "incorrect", result.Memory.Metadata["flagged"])
+	assert.Equal(t, "I never lived in Berlin", result.Memory.Metadata["flag_note"])
+	assert.Less(t, result.Memory.RetentionStrength, 1.0)
+
+	// Flagged memories are left out of searches
+	results, err := client.Search(ctx, "Lives in Berlin", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Works as a nurse", results[0].Content)
+
+	results, err = client.SearchByKeyword(ctx, "Berlin", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	results, err = client.Search(ctx, "Lives in Berlin", core.WithUserIDForSearch("user_001"), core.WithIncludeFlagged(true))
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	// ...and listed for review
+	flagged, err := client.GetAll(ctx, core.WithAllowCrossUserForGetAll(true),
+		core.WithFiltersForGetAll(map[string]interface{}{"flagged": "incorrect"}))
+	require.NoError(t, err)
+	require.Len(t, flagged, 1)
+	assert.Equal(t, memory.ID, flagged[0].ID)
+
+	// Positive feedback clears the flag
+	result, err = client.Feedback(ctx, memory.ID, core.FeedbackPositive, "")
+	require.NoError(t, err)
+	assert.NotContains(t, result.Memory.Metadata, "flagged")
+	results, err = client.Search(ctx, "Lives in Berlin", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+}
+
+func TestClient_FeedbackRewrite(t *testing.T) {
+	cfg := newChangesConfig(filepath.Join(t.TempDir(), "test_feedback_rewrite.db"))
+	cfg.LLM.Parameters = map[string]interface{}{"responses": []string{
+		`{"memory": "Lives in Lisbon since May"}`,
+		`{"memory": ""}`,
+	}}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	memory, err := client.Add(ctx, "Lives in Berlin", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	_, err = client.Feedback(ctx, memory.ID, core.FeedbackIncorrect, "")
+	require.NoError(t, err)
+
+	result, err := client.Feedback(ctx, memory.ID, core.FeedbackIncorrect, "I moved to Lisbon in May",
+		core.WithFeedbackRewrite(true))
+	require.NoError(t, err)
+	assert.True(t, result.Rewritten)
+	assert.False(t, result.Flagged)
+	assert.Equal(t, "Lives in Berlin", result.PreviousContent)
+	assert.Equal(t, "Lives in Lisbon since May", result.Memory.Content)
+	assert.NotContains(t, result.Memory.Metadata, "flagged")
+	assert.InDelta(t, 1.0, result.Memory.RetentionStrength, 1e-9)
+
+	results, err := client.Search(ctx, "Lives in Lisbon since May", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, memory.ID, results[0].ID)
+
+	// Nothing to keep: the memory is forgotten
+	other, err := client.Add(ctx, "Is allergic to peanuts", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	result, err = client.Feedback(ctx, other.ID, core.FeedbackIncorrect, "Forget it", core.WithFeedbackRewrite(true))
+	require.NoError(t, err)
+	assert.True(t, result.Forgotten)
+	_, err = client.Get(ctx, other.ID)
+	assert.Error(t, err)
+}
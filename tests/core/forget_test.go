@@ -0,0 +1,101 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_Forget(t *testing.T) {
+	cfg := newChangesConfig(filepath.Join(t.TempDir(), "test_forget.db"))
+	plan := `{"actions": [
+		{"event": "DELETE", "id": "0", "reason": "The home city"},
+		{"event": "UPDATE", "id": "1", "text": "Works as a nurse", "reason": "Reveals the city"},
+		{"event": "DELETE", "id": "7"}
+	]}`
+	cfg.LLM.Parameters = map[string]interface{}{"responses": []string{plan}}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	contents := map[int64]string{}
+	for _, content := range []string{"Lives in Berlin", "Works as a nurse in Berlin"} {
+		memory, err := client.Add(ctx, content, core.WithUserID("user_001"))
+		require.NoError(t, err)
+		contents[memory.ID] = content
+	}
+	other, err := client.Add(ctx, "Lives in Berlin too", core.WithUserID("user_002"))
+	require.NoError(t, err)
+
+	_, err = client.Forget(ctx, "user_001", "")
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+
+	// A dry run plans the actions on the user's memories only
+	preview, err := client.Forget(ctx, "user_001", "Forget that I live in Berlin")
+	require.NoError(t, err)
+	assert.True(t, preview.DryRun)
+	assert.Equal(t, 2, preview.Reviewed)
+	require.Len(t, preview.Actions, 2)
+	deleteAction, updateAction := preview.Actions[0], preview.Actions[1]
+	assert.Equal(t, "DELETE", deleteAction.Event)
+	assert.Equal(t, contents[deleteAction.ID], deleteAction.Memory)
+	assert.Equal(t, "UPDATE", updateAction.Event)
+	assert.Equal(t, "Works as a nurse", updateAction.NewMemory)
+	all, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	// Confirming performs the approved actions as planned
+	seq, err := client.LastChangeSeq(ctx)
+	require.NoError(t, err)
+	result, err := client.Forget(ctx, "user_001", "Forget that I live in Berlin", core.WithConfirmedForgetActions(preview.Actions...))
+	require.NoError(t, err)
+	assert.False(t, result.DryRun)
+	assert.Len(t, result.Actions, 2)
+	assert.Empty(t, result.Conflicts)
+	_, err = client.Get(ctx, deleteAction.ID)
+	assert.Error(t, err)
+	updated, err := client.Get(ctx, updateAction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Works as a nurse", updated.Content)
+	_, err = client.Get(ctx, other.ID)
+	assert.NoError(t, err)
+
+	// The change log records the forgotten memories without their content
+	changes, err := client.ListChanges(ctx, core.WithChangesAfter(seq))
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	versions := map[int64]int64{}
+	for _, change := range changes {
+		assert.Equal(t, core.EventForgotten, change.Type)
+		assert.Equal(t, "user_001", change.UserID)
+		assert.Nil(t, change.Memory)
+		assert.Nil(t, change.Diff)
+		encoded, err := json.Marshal(change)
+		require.NoError(t, err)
+		assert.NotContains(t, string(encoded), "Berlin")
+		versions[change.MemoryID] = change.Version
+	}
+	assert.Equal(t, map[int64]int64{deleteAction.ID: 0, updateAction.ID: updated.Version}, versions)
+
+	// Actions on memories changed since they were planned are conflicts
+	result, err = client.Forget(ctx, "user_001", "Forget that I live in Berlin", core.WithConfirmedForgetActions(preview.Actions...))
+	require.NoError(t, err)
+	assert.Empty(t, result.Actions)
+	assert.Len(t, result.Conflicts, 2)
+
+	// Actions on other users' memories are not performed
+	result, err = client.Forget(ctx, "user_003", "Forget that I live in Berlin",
+		core.WithConfirmedForgetActions(core.ForgetActionResult{Event: "DELETE", ID: other.ID, Version: other.Version}))
+	require.NoError(t, err)
+	assert.Len(t, result.Conflicts, 1)
+	_, err = client.Get(ctx, other.ID)
+	assert.NoError(t, err)
+}
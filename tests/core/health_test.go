@@ -0,0 +1,83 @@
+package core_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_Health(t *testing.T) {
+	testDBPath := "./test_health.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	client := newOfflineTestClient(t, testDBPath, "")
+	ctx := context.Background()
+
+	report, err := client.Health(ctx)
+	require.NoError(t, err)
+	assert.True(t, report.Healthy())
+	require.Len(t, report.Components, 3)
+	assert.Equal(t, "sqlite", report.Components[core.HealthVectorStore].Provider)
+	assert.Equal(t, "openai", report.Components[core.HealthLLM].Provider)
+	for component, health := range report.Components {
+		assert.Equal(t, core.HealthStatusUp, health.Status, component)
+		assert.Empty(t, health.Error, component)
+		assert.Greater(t, int64(health.Latency), int64(0), component)
+	}
+
+	// Only the requested checks run
+	report, err = client.Health(ctx, core.WithHealthComponents(core.HealthVectorStore))
+	require.NoError(t, err)
+	assert.Len(t, report.Components, 1)
+	assert.Contains(t, report.Components, core.HealthVectorStore)
+
+	_, err = client.Health(ctx, core.WithHealthComponents("cache"))
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+
+	require.NoError(t, client.Shutdown(ctx))
+	_, err = client.Health(ctx)
+	assert.ErrorIs(t, err, core.ErrClientClosed)
+}
+
+func TestClient_HealthUnreachableProvider(t *testing.T) {
+	testDBPath := "./test_health_down.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	url, _ := newHyDEServer(t)
+	down := httptest.NewServer(nil)
+	down.Close()
+
+	client, err := core.NewClient(&core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite: &core.SQLiteConfig{
+				DBPath:             testDBPath,
+				CollectionName:     "memories",
+				EmbeddingModelDims: 3,
+			},
+		},
+		LLM: core.LLMConfig{Provider: "openai", APIKey: "test-key", Model: "gpt-3.5-turbo", BaseURL: down.URL},
+		Embedder: core.EmbedderConfig{
+			Provider: "openai", APIKey: "test-key", Model: "text-embedding-ada-002", BaseURL: url, Dimensions: 3,
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	report, err := client.Health(context.Background())
+	require.NoError(t, err)
+	assert.False(t, report.Healthy())
+	assert.Equal(t, core.HealthStatusDown, report.Status)
+	assert.Equal(t, core.HealthStatusDown, report.Components[core.HealthLLM].Status)
+	assert.NotEmpty(t, report.Components[core.HealthLLM].Error)
+	assert.Equal(t, core.HealthStatusUp, report.Components[core.HealthVectorStore].Status)
+	assert.Equal(t, core.HealthStatusUp, report.Components[core.HealthEmbedder].Status)
+}
@@ -0,0 +1,25 @@
+package core_test
+
+import (
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// newTestConfig returns a config of a SQLite store in dbPath with the mock
+// LLM and embedder, to which tests add the features they exercise.
+func newTestConfig(dbPath string) *core.Config {
+	return &core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite:   &core.SQLiteConfig{DBPath: dbPath, CollectionName: "memories", EmbeddingModelDims: 64},
+		},
+		LLM:      core.LLMConfig{Provider: "mock"},
+		Embedder: core.EmbedderConfig{Provider: "mock", Dimensions: 64},
+	}
+}
+
+// newChangesConfig returns the test config with the change log enabled.
+func newChangesConfig(dbPath string) *core.Config {
+	cfg := newTestConfig(dbPath)
+	cfg.ChangeLog = &core.ChangeLogConfig{Enabled: true}
+	return cfg
+}
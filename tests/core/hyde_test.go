@@ -0,0 +1,187 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// newHyDEServer starts a fake OpenAI-compatible server. Chat completions answer
+// with a hypothetical document about hiking; embeddings map texts mentioning
+// hiking and weekends to orthogonal vectors. It counts chat completion calls.
+func newHyDEServer(t *testing.T) (string, *int32) {
+	var chatCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/chat/completions"):
+			atomic.AddInt32(&chatCalls, 1)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "chat.completion",
+				"choices": []map[string]interface{}{
+					{"index": 0, "finish_reason": "stop", "message": map[string]interface{}{
+						"role": "assistant", "content": "I go hiking in the mountains.",
+					}},
+				},
+			})
+		case strings.HasSuffix(r.URL.Path, "/embeddings"):
+			var req struct {
+				Input []string `json:"input"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			embedding := []float64{0, 0, 1}
+			if len(req.Input) > 0 {
+				text := strings.ToLower(req.Input[0])
+				switch {
+				case strings.Contains(text, "hiking"):
+					embedding = []float64{1, 0, 0}
+				case strings.Contains(text, "weekend"):
+					embedding = []float64{0, 1, 0}
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "list",
+				"data": []map[string]interface{}{
+					{"object": "embedding", "index": 0, "embedding": embedding},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, &chatCalls
+}
+
+func TestSearch_RetrievalModes(t *testing.T) {
+	testDBPath := "./test_hyde.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	url, chatCalls := newHyDEServer(t)
+	client, err := core.NewClient(&core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite: &core.SQLiteConfig{
+				DBPath:             testDBPath,
+				CollectionName:     "memories",
+				EmbeddingModelDims: 3,
+			},
+		},
+		LLM: core.LLMConfig{Provider: "openai", APIKey: "test-key", Model: "gpt-3.5-turbo", BaseURL: url},
+		Embedder: core.EmbedderConfig{
+			Provider: "openai", APIKey: "test-key", Model: "text-embedding-ada-002", BaseURL: url, Dimensions: 3,
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx := context.Background()
+	hiking, err := client.Add(ctx, "Loves hiking", core.WithUserID("user_001"), core.WithInfer(false))
+	require.NoError(t, err)
+	weekend, err := client.Add(ctx, "Weekend schedule is busy", core.WithUserID("user_001"), core.WithInfer(false))
+	require.NoError(t, err)
+
+	query := "what do I do on weekends?"
+
+	results, err := client.Search(ctx, query, core.WithUserIDForSearch("user_001"), core.WithLimit(1))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, weekend.ID, results[0].ID)
+	assert.Equal(t, int32(0), atomic.LoadInt32(chatCalls))
+
+	results, err = client.Search(ctx, query, core.WithUserIDForSearch("user_001"), core.WithLimit(1),
+		core.WithRetrievalMode(core.RetrievalModeHyDE))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, hiking.ID, results[0].ID)
+	assert.Equal(t, int32(1), atomic.LoadInt32(chatCalls))
+
+	results, err = client.Search(ctx, query, core.WithUserIDForSearch("user_001"), core.WithLimit(2),
+		core.WithRetrievalMode(core.RetrievalModeHyDEFusion))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	ids := []int64{results[0].ID, results[1].ID}
+	assert.ElementsMatch(t, []int64{hiking.ID, weekend.ID}, ids)
+	assert.InDelta(t, 1.0, results[0].Score, 1e-6)
+	assert.InDelta(t, 1.0, results[1].Score, 1e-6)
+
+	// SQLite has no full-text index: hybrid mode is a vector search
+	results, err = client.Search(ctx, query, core.WithUserIDForSearch("user_001"), core.WithLimit(1),
+		core.WithRetrievalMode(core.RetrievalModeHybrid))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, weekend.ID, results[0].ID)
+	assert.InDelta(t, 1.0, results[0].Score, 1e-6)
+	assert.Equal(t, int32(2), atomic.LoadInt32(chatCalls))
+}
+
+func TestSearchWithDiagnostics(t *testing.T) {
+	testDBPath := "./test_search_diagnostics.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	url, _ := newHyDEServer(t)
+	client, err := core.NewClient(&core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite: &core.SQLiteConfig{
+				DBPath:             testDBPath,
+				CollectionName:     "memories",
+				EmbeddingModelDims: 3,
+			},
+		},
+		LLM: core.LLMConfig{Provider: "openai", APIKey: "test-key", Model: "gpt-3.5-turbo", BaseURL: url},
+		Embedder: core.EmbedderConfig{
+			Provider: "openai", APIKey: "test-key", Model: "text-embedding-ada-002", BaseURL: url, Dimensions: 3,
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx := context.Background()
+	for _, m := range []struct{ user, content string }{
+		{"user_001", "Loves hiking"},
+		{"user_001", "Weekend schedule is busy"},
+		{"user_002", "Weekend trip planned"},
+	} {
+		_, err := client.Add(ctx, m.content, core.WithUserID(m.user), core.WithInfer(false))
+		require.NoError(t, err)
+	}
+
+	results, diag, err := client.SearchWithDiagnostics(ctx, "weekend plans",
+		core.WithUserIDForSearch("user_001"),
+		core.WithMinScore(0.5),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NotNil(t, diag)
+	assert.Equal(t, "weekend plans", diag.Query)
+	assert.Equal(t, core.RetrievalModeVector, diag.RetrievalMode)
+	assert.Equal(t, int64(3), diag.TotalMemories)
+	assert.Equal(t, int64(2), diag.Candidates)
+	assert.Equal(t, int64(1), diag.AboveThreshold)
+	assert.Equal(t, 1, diag.Returned)
+	assert.False(t, diag.IntelligentRanking)
+	assert.Greater(t, diag.EmbeddingTime, time.Duration(0))
+	assert.GreaterOrEqual(t, diag.TotalTime, diag.EmbeddingTime+diag.StorageTime)
+
+	_, diag, err = client.SearchWithDiagnostics(ctx, "weekend plans",
+		core.WithUserIDForSearch("user_001"),
+		core.WithRetrievalMode(core.RetrievalModeHyDE),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "I go hiking in the mountains.", diag.HypotheticalDocument)
+	assert.Greater(t, diag.LLMTime, time.Duration(0))
+}
@@ -0,0 +1,230 @@
+package core_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+var uuidV7Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func newOfflineTestClient(t *testing.T, dbPath string, idType core.IDType) *core.Client {
+	url, _ := newHyDEServer(t)
+	client, err := core.NewClient(&core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite: &core.SQLiteConfig{
+				DBPath:             dbPath,
+				CollectionName:     "memories",
+				EmbeddingModelDims: 3,
+			},
+		},
+		LLM: core.LLMConfig{Provider: "openai", APIKey: "test-key", Model: "gpt-3.5-turbo", BaseURL: url},
+		Embedder: core.EmbedderConfig{
+			Provider: "openai", APIKey: "test-key", Model: "text-embedding-ada-002", BaseURL: url, Dimensions: 3,
+		},
+		IDType: idType,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestClient_UUIDMemoryIDs(t *testing.T) {
+	testDBPath := "./test_uuid_ids.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	client := newOfflineTestClient(t, testDBPath, core.IDTypeUUID)
+	ctx := context.Background()
+
+	first, err := client.Add(ctx, "I like hiking", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.NotZero(t, first.ID)
+	assert.Regexp(t, uuidV7Pattern, first.UID)
+
+	second, err := client.Add(ctx, "Busy weekend", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.NotEqual(t, first.UID, second.UID)
+	assert.Less(t, first.UID, second.UID, "UUIDv7 IDs are time ordered")
+
+	// Reads return the string ID and memories can be addressed by it
+	got, err := client.GetByUID(ctx, first.UID)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, got.ID)
+	assert.Equal(t, "I like hiking", got.Content)
+
+	got, err = client.Get(ctx, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, second.UID, got.UID)
+
+	_, err = client.GetByUID(ctx, first.UID, core.WithUserIDForGet("user_002"))
+	assert.Error(t, err)
+
+	updated, err := client.UpdateByUID(ctx, first.UID, "I like hiking a lot")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, updated.ID)
+	assert.Equal(t, first.UID, updated.UID)
+
+	err = client.DeleteByUID(ctx, first.UID, core.WithUserIDForDelete("user_002"))
+	assert.Error(t, err)
+	require.NoError(t, client.DeleteByUID(ctx, first.UID))
+	_, err = client.GetByUID(ctx, first.UID)
+	assert.Error(t, err)
+}
+
+func TestClient_SnowflakeIDsHaveNoUID(t *testing.T) {
+	testDBPath := "./test_snowflake_ids.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	client := newOfflineTestClient(t, testDBPath, "")
+	ctx := context.Background()
+
+	// Memories without a string ID do not conflict in the unique index
+	for _, content := range []string{"I like hiking", "Busy weekend"} {
+		memory, err := client.Add(ctx, content, core.WithUserID("user_001"))
+		require.NoError(t, err)
+		assert.Empty(t, memory.UID)
+	}
+}
+
+func TestClient_IDConflictRetries(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "id_conflicts.db")
+	client, err := core.NewClient(&core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite:   &core.SQLiteConfig{DBPath: dbPath, CollectionName: "memories", EmbeddingModelDims: 8},
+		},
+		LLM:               core.LLMConfig{Provider: "mock"},
+		Embedder:          core.EmbedderConfig{Provider: "mock", Dimensions: 8},
+		IDConflictRetries: 50,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	// Take the even IDs of the memories of "collide", and every ID of the
+	// memories of "always", just before they are inserted
+	db, err := sql.Open("sqlite3", dbPath)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`
+		CREATE TRIGGER take_ids BEFORE INSERT ON memories
+		WHEN (NEW.user_id = 'collide' AND NEW.id % 2 = 0) OR NEW.user_id = 'always'
+		BEGIN
+			INSERT INTO memories (id, user_id, content, embedding) VALUES (NEW.id, 'other', 'taken', x'');
+		END`)
+	require.NoError(t, err)
+
+	// Consecutive snowflake IDs alternate parity within a millisecond
+	for i := 0; i < 5; i++ {
+		memory, err := client.Add(ctx, "I like hiking", core.WithUserID("collide"), core.WithInfer(false))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), memory.ID%2)
+
+		got, err := client.Get(ctx, memory.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "collide", got.UserID)
+	}
+
+	_, err = client.Add(ctx, "I like hiking", core.WithUserID("always"), core.WithInfer(false))
+	assert.ErrorIs(t, err, storage.ErrDuplicateID)
+}
+
+func TestConfig_InvalidIDType(t *testing.T) {
+	cfg := &core.Config{
+		LLM:         core.LLMConfig{Provider: "openai"},
+		Embedder:    core.EmbedderConfig{Provider: "openai"},
+		VectorStore: core.VectorStoreConfig{Provider: "sqlite"},
+		IDType:      "ulid",
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, core.ErrInvalidConfig))
+}
+
+func TestClient_AddWithUID(t *testing.T) {
+	dir := t.TempDir()
+	client, err := core.NewClient(newChangesConfig(filepath.Join(dir, "test_add_uid.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	// The UID is stored in canonical form, without IDTypeUUID
+	uid := "0190b5a2-7c1e-7d3a-9f4e-2b8c6d1e0a57"
+	memory, err := client.Add(ctx, "User prefers dark mode", core.WithUserID("user_001"), core.WithUID("0190B5A2-7C1E-7D3A-9F4E-2B8C6D1E0A57"))
+	require.NoError(t, err)
+	assert.Equal(t, uid, memory.UID)
+	found, err := client.GetByUID(ctx, uid)
+	require.NoError(t, err)
+	assert.Equal(t, memory.ID, found.ID)
+
+	// A taken UID is rejected, even for another user
+	_, err = client.Add(ctx, "User prefers light mode", core.WithUserID("user_002"), core.WithUID(uid))
+	assert.ErrorIs(t, err, core.ErrDuplicateUID)
+
+	for _, invalid := range []string{"42", "0190b5a2-7c1e-7d3a-9f4e-2b8c6d1e0a5", "0190b5a2+7c1e-7d3a-9f4e-2b8c6d1e0a57", "0190b5a2-7c1e-7d3a-9f4e-2b8c6d1e0a5z"} {
+		_, err = client.Add(ctx, "User prefers light mode", core.WithUserID("user_001"), core.WithUID(invalid))
+		assert.ErrorIs(t, err, core.ErrInvalidInput, invalid)
+	}
+	_, err = client.Add(ctx, "User prefers light mode", core.WithUserID("user_001"),
+		core.WithUID("0190b5a2-7c1e-7d3a-9f4e-2b8c6d1e0a58"), core.WithInfer(true))
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+
+	// The store rejects UIDs taken by expired memories and by other clients
+	expired := "0190b5a2-7c1e-7d3a-9f4e-2b8c6d1e0a59"
+	_, err = client.Add(ctx, "User is on vacation", core.WithUserID("user_001"), core.WithUID(expired),
+		core.WithExpiresAt(time.Now().Add(-time.Hour)))
+	require.NoError(t, err)
+	_, err = client.Add(ctx, "User is back", core.WithUserID("user_001"), core.WithUID(expired))
+	assert.ErrorIs(t, err, core.ErrDuplicateUID)
+
+	other, err := core.NewClient(newChangesConfig(filepath.Join(dir, "test_add_uid.db")))
+	require.NoError(t, err)
+	defer other.Close()
+	_, err = other.Add(ctx, "User prefers light mode", core.WithUserID("user_002"), core.WithUID(uid))
+	assert.ErrorIs(t, err, core.ErrDuplicateUID)
+
+	all, err := client.GetAll(ctx, core.WithAllowCrossUserForGetAll(true))
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func TestClient_ByUIDOperations(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_by_uid.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	uid := "0190b5a2-7c1e-7d3a-9f4e-2b8c6d1e0a57"
+	memory, err := client.Add(ctx, "User prefers dark mode", core.WithUserID("user_001"), core.WithUID(uid))
+	require.NoError(t, err)
+
+	// The changes are recorded once, under the name of the UID operation
+	_, err = client.UpdateByUID(ctx, uid, "User prefers light mode", core.WithUserIDForUpdate("user_002"))
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	updated, err := client.UpdateByUID(ctx, uid, "User prefers light mode", core.WithUserIDForUpdate("user_001"))
+	require.NoError(t, err)
+	assert.Equal(t, memory.ID, updated.ID)
+	require.NoError(t, client.DeleteByUID(ctx, uid, core.WithUserIDForDelete("user_001")))
+
+	changes, err := client.ListChanges(ctx)
+	require.NoError(t, err)
+	var operations []string
+	for _, change := range changes {
+		operations = append(operations, change.Operation)
+	}
+	assert.Equal(t, []string{"Add", "UpdateByUID", "DeleteByUID"}, operations)
+}
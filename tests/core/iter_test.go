@@ -0,0 +1,111 @@
+//go:build go1.23
+
+package core_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_Memories(t *testing.T) {
+	testDBPath := "./test_memories_iter.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	client := newOfflineTestClient(t, testDBPath, "")
+	ctx := context.Background()
+
+	contents := make([]string, 150)
+	for i := range contents {
+		contents[i] = "I like hiking"
+	}
+	added, err := client.BatchAdd(ctx, contents, core.WithUserID("user_001"))
+	require.NoError(t, err)
+	require.Equal(t, 150, added.CreatedCount)
+	_, err = client.Add(ctx, "Busy weekend", core.WithUserID("user_002"))
+	require.NoError(t, err)
+
+	// All memories of the user, across storage batches
+	seen := make(map[int64]bool)
+	for memory, err := range client.Memories(ctx, core.WithUserIDForGetAll("user_001"), core.WithLimitForGetAll(0)) {
+		require.NoError(t, err)
+		assert.Equal(t, "user_001", memory.UserID)
+		seen[memory.ID] = true
+	}
+	assert.Len(t, seen, 150)
+
+	// GetAll's default limit applies
+	count := 0
+	for _, err := range client.Memories(ctx, core.WithUserIDForGetAll("user_001")) {
+		require.NoError(t, err)
+		count++
+	}
+	assert.Equal(t, 100, count)
+
+	// Limit caps the total
+	count = 0
+	for _, err := range client.Memories(ctx, core.WithUserIDForGetAll("user_001"), core.WithLimitForGetAll(120)) {
+		require.NoError(t, err)
+		count++
+	}
+	assert.Equal(t, 120, count)
+
+	// The loop body may call the client, and leaving early stops iteration
+	count = 0
+	for memory, err := range client.Memories(ctx, core.WithUserIDForGetAll("user_001")) {
+		require.NoError(t, err)
+		_, err = client.Update(ctx, memory.ID, "I like hiking a lot")
+		require.NoError(t, err)
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	assert.Equal(t, 3, count)
+
+	// Errors are yielded
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	for _, err := range client.Memories(canceled, core.WithUserIDForGetAll("user_001")) {
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+}
+
+func TestClient_SearchMemories(t *testing.T) {
+	testDBPath := "./test_search_memories_iter.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	client := newOfflineTestClient(t, testDBPath, "")
+	ctx := context.Background()
+
+	for _, content := range []string{"Busy weekend", "I like hiking", "I like hiking too"} {
+		_, err := client.Add(ctx, content, core.WithUserID("user_001"))
+		require.NoError(t, err)
+	}
+
+	var results []*core.Memory
+	for memory, err := range client.SearchMemories(ctx, "hiking", core.WithUserIDForSearch("user_001"), core.WithLimit(0)) {
+		require.NoError(t, err)
+		results = append(results, memory)
+	}
+	require.Len(t, results, 3)
+	assert.Contains(t, results[0].Content, "hiking")
+	assert.Contains(t, results[1].Content, "hiking")
+	assert.Equal(t, "Busy weekend", results[2].Content)
+	assert.GreaterOrEqual(t, results[1].Score, results[2].Score)
+
+	// Limit caps the total
+	count := 0
+	for _, err := range client.SearchMemories(ctx, "hiking", core.WithUserIDForSearch("user_001"), core.WithLimit(2)) {
+		require.NoError(t, err)
+		count++
+	}
+	assert.Equal(t, 2, count)
+}
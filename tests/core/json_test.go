@@ -0,0 +1,60 @@
+package core_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestMemory_JSON(t *testing.T) {
+	memory := &core.Memory{
+		ID:              1234567890,
+		UserID:          "user_001",
+		Content:         "Prefers window seats",
+		Embedding:       []float64{0.1, 0.2},
+		SparseEmbedding: map[int]float64{3: 0.5},
+		Metadata:        map[string]interface{}{"memory_type": "preference"},
+		CreatedAt:       time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		MemoryType:      core.MemoryTypePreference,
+	}
+
+	// Embeddings are omitted by default, by value and by pointer
+	for _, value := range []interface{}{memory, *memory} {
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &fields))
+		assert.NotContains(t, fields, "embedding")
+		assert.NotContains(t, fields, "sparse_embedding")
+		assert.Equal(t, "Prefers window seats", fields["content"])
+		assert.Equal(t, "preference", fields["memory_type"])
+		assert.Equal(t, "2025-01-02T03:04:05Z", fields["created_at"])
+	}
+	assert.Equal(t, []float64{0.1, 0.2}, memory.Embedding, "the memory itself is unchanged")
+
+	// WithEmbeddings includes them, and decoding restores them
+	data, err := json.Marshal(memory.WithEmbeddings())
+	require.NoError(t, err)
+	var decoded core.Memory
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, memory.Embedding, decoded.Embedding)
+	assert.Equal(t, memory.SparseEmbedding, decoded.SparseEmbedding)
+	assert.Equal(t, memory.ID, decoded.ID)
+	assert.True(t, memory.CreatedAt.Equal(decoded.CreatedAt))
+
+	// Memories nested in results are encoded the same way
+	data, err = json.Marshal(&core.SearchResult{Memories: []*core.Memory{memory}, TotalCount: 1})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "embedding")
+	assert.Contains(t, string(data), `"total_count":1`)
+	assert.Contains(t, string(data), `"memories":[{"id":1234567890`)
+
+	data, err = json.Marshal(&core.IntelligentAddResult{Results: []core.MemoryActionResult{{ID: 1, Memory: "Likes tea", Event: "ADD"}}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"results":[{"id":1,"memory":"Likes tea","event":"ADD"}]}`, string(data))
+}
@@ -0,0 +1,53 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_Language(t *testing.T) {
+	config := newChangesConfig(filepath.Join(t.TempDir(), "test_language.db"))
+	config.Language = &core.LanguageConfig{Detect: true, TranslateQueries: []string{"en", "zh"}}
+	config.LLM.Parameters = map[string]interface{}{"responses": []string{"喜欢 靠窗 座位"}}
+	client, err := core.NewClient(config)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	zh, err := client.Add(ctx, "喜欢 靠窗 座位", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	en, err := client.Add(ctx, "Prefers window seats", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	given, err := client.Add(ctx, "Prefiere té", core.WithUserID("user_001"), core.WithLanguageForAdd("es"))
+	require.NoError(t, err)
+	assert.Equal(t, "zh", zh.Language)
+	assert.Equal(t, "zh", zh.Metadata["language"])
+	assert.Equal(t, "en", en.Language)
+	assert.Equal(t, "es", given.Language)
+
+	// WithLanguage restricts the results to a language, without translation
+	results, err := client.Search(ctx, "window seats", core.WithUserIDForSearch("user_001"), core.WithLanguage("zh"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, zh.ID, results[0].ID)
+
+	// The query is also searched in Chinese, finding the Chinese memory
+	results, diag, err := client.SearchWithDiagnostics(ctx, "window seats",
+		core.WithUserIDForSearch("user_001"), core.WithMinScore(0.5))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"zh": "喜欢 靠窗 座位"}, diag.TranslatedQueries)
+	ids := make([]int64, len(results))
+	for i, result := range results {
+		ids[i] = result.ID
+	}
+	assert.ElementsMatch(t, []int64{zh.ID, en.ID}, ids)
+
+	config.Language.TranslateQueries = []string{""}
+	assert.ErrorContains(t, config.Validate(), "language.translate_queries[0]")
+}
@@ -0,0 +1,83 @@
+package core_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_LLMFallbacks(t *testing.T) {
+	var primaryCalls int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&primaryCalls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error":{"message":"overloaded"}}`))
+	}))
+	defer primary.Close()
+	fallbackURL, keys := newKeyRecordingServer(t)
+
+	client, err := core.NewClient(&core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite:   &core.SQLiteConfig{DBPath: filepath.Join(t.TempDir(), "test_llm_fallbacks.db"), EmbeddingModelDims: 3},
+		},
+		LLM: core.LLMConfig{
+			Provider: "openai", APIKey: "sk-primary", Model: "gpt-4", BaseURL: primary.URL,
+			Fallbacks: []core.LLMConfig{
+				{Provider: "deepseek", APIKey: "sk-fallback", Model: "deepseek-chat", BaseURL: fallbackURL},
+			},
+			Routing: &core.LLMRoutingConfig{CooldownSeconds: 3600},
+		},
+		Embedder: core.EmbedderConfig{Provider: "openai", APIKey: "sk-embed", BaseURL: fallbackURL, Dimensions: 3},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		report, err := client.Health(ctx, core.WithHealthComponents(core.HealthLLM))
+		require.NoError(t, err)
+		assert.True(t, report.Healthy(), "the fallback should serve while the primary is down")
+	}
+
+	// The primary is skipped during its cooldown
+	assert.Equal(t, int32(1), atomic.LoadInt32(&primaryCalls))
+	assert.Equal(t, []string{"sk-fallback", "sk-fallback"}, keys())
+}
+
+func TestConfig_ValidateLLMFallbacks(t *testing.T) {
+	cfg := &core.Config{
+		VectorStore: core.VectorStoreConfig{Provider: "sqlite"},
+		LLM: core.LLMConfig{
+			Provider: "openai",
+			Fallbacks: []core.LLMConfig{
+				{Provider: "deepseek"},
+				{Provider: "mistral", Fallbacks: []core.LLMConfig{{Provider: "ollama"}}},
+			},
+			Routing: &core.LLMRoutingConfig{Strategy: "random", CooldownSeconds: -1},
+		},
+		Embedder: core.EmbedderConfig{Provider: "openai"},
+	}
+
+	var validationErr *core.ValidationError
+	require.ErrorAs(t, cfg.Validate(), &validationErr)
+
+	fields := make([]string, 0, len(validationErr.Fields))
+	for _, f := range validationErr.Fields {
+		fields = append(fields, f.Field)
+	}
+	assert.ElementsMatch(t, []string{
+		"llm.fallbacks[1].fallbacks",
+		"llm.fallbacks[1].provider",
+		"llm.routing.strategy",
+		"llm.routing.cooldown_seconds",
+	}, fields)
+}
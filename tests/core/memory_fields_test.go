@@ -0,0 +1,62 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+func TestClient_MemoryTypedFields(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_memory_fields.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	added, err := client.Add(ctx, "Prefers window seats",
+		core.WithUserID("user_001"),
+		core.WithRunID("run_42"),
+		core.WithScope(core.ScopePrivate),
+		core.WithMemoryType(core.MemoryTypePreference),
+	)
+	require.NoError(t, err)
+
+	check := func(memory *core.Memory) {
+		t.Helper()
+		assert.Equal(t, storage.ContentHash("Prefers window seats"), memory.Hash)
+		assert.Equal(t, "run_42", memory.RunID)
+		assert.Equal(t, core.ScopePrivate, memory.Scope)
+		assert.Equal(t, core.MemoryTypePreference, memory.MemoryType)
+	}
+	check(added)
+
+	got, err := client.Get(ctx, added.ID)
+	require.NoError(t, err)
+	check(got)
+
+	results, err := client.Search(ctx, "Prefers window seats", core.WithUserIDForSearch("user_001"), core.WithLimit(1))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	check(results[0])
+
+	all, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	check(all[0])
+
+	// The hash follows the content
+	updated, err := client.Update(ctx, added.ID, "Prefers aisle seats")
+	require.NoError(t, err)
+	assert.Equal(t, storage.ContentHash("Prefers aisle seats"), updated.Hash)
+	assert.Equal(t, "run_42", updated.RunID)
+
+	plain, err := client.Add(ctx, "Lives in Lisbon", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.Empty(t, plain.RunID)
+	assert.Empty(t, plain.MemoryType)
+}
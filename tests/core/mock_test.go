@@ -0,0 +1,57 @@
+package core_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_MockProviders(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "powermem.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+vector_store:
+  provider: sqlite
+  config:
+    db_path: `+filepath.Join(dir, "test_mock.db")+`
+    embedding_model_dims: 256
+llm:
+  provider: mock
+  parameters:
+    responses: ["I go hiking in the mountains."]
+embedder:
+  provider: mock
+  dimensions: 256
+`), 0o600))
+
+	config, err := core.LoadConfigFromFile(path)
+	require.NoError(t, err)
+	client, err := core.NewClient(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	for _, content := range []string{
+		"User loves hiking in the mountains",
+		"User prefers email communication",
+		"User works in the tech industry",
+	} {
+		_, err := client.Add(ctx, content, core.WithUserID("user_001"))
+		require.NoError(t, err)
+	}
+
+	results, err := client.Search(ctx, "hiking mountains", core.WithUserIDForSearch("user_001"), core.WithLimit(3))
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "User loves hiking in the mountains", results[0].Content)
+
+	report, err := client.Health(ctx)
+	require.NoError(t, err)
+	assert.True(t, report.Healthy())
+}
@@ -0,0 +1,77 @@
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestVectorStoreConfig_OceanBaseTLSJSON(t *testing.T) {
+	var store core.VectorStoreConfig
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"provider": "oceanbase",
+		"config": {
+			"user": "powermem@app_tenant#ob_cluster",
+			"ssl_mode": "verify_identity",
+			"ssl_ca": "/etc/powermem/ob-ca.pem",
+			"charset": "utf8mb4",
+			"connect_timeout_seconds": 5,
+			"read_timeout_seconds": 2.5
+		}
+	}`), &store))
+	require.NotNil(t, store.OceanBase)
+	assert.Equal(t, "powermem@app_tenant#ob_cluster", store.OceanBase.User)
+	assert.Equal(t, "verify_identity", store.OceanBase.SSLMode)
+	assert.Equal(t, "/etc/powermem/ob-ca.pem", store.OceanBase.SSLCA)
+	assert.Equal(t, "utf8mb4", store.OceanBase.Charset)
+	assert.Equal(t, 5.0, store.OceanBase.ConnectTimeoutSeconds)
+	assert.Equal(t, 2.5, store.OceanBase.ReadTimeoutSeconds)
+	assert.Zero(t, store.OceanBase.WriteTimeoutSeconds)
+}
+
+func TestConfigValidate_OceanBaseTLS(t *testing.T) {
+	config := &core.Config{
+		LLM:      core.LLMConfig{Provider: "mock"},
+		Embedder: core.EmbedderConfig{Provider: "mock"},
+		VectorStore: core.VectorStoreConfig{
+			OceanBase: &core.OceanBaseConfig{
+				SSLMode:             "verify-full",
+				SSLCert:             "/etc/powermem/ob-client.pem",
+				WriteTimeoutSeconds: -1,
+			},
+		},
+	}
+	err := config.Validate()
+	var validationErr *core.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	fields := make(map[string]string)
+	for _, fieldErr := range validationErr.Fields {
+		fields[fieldErr.Field] = fieldErr.Message
+	}
+	assert.Equal(t, map[string]string{
+		"vector_store.config.ssl_mode":              `unknown ssl mode "verify-full"`,
+		"vector_store.config.ssl_cert":              "must be set together with ssl_key",
+		"vector_store.config.write_timeout_seconds": "must not be negative, got -1",
+	}, fields)
+}
+
+func TestLoadConfigFromEnv_OceanBaseTLS(t *testing.T) {
+	t.Setenv("DATABASE_PROVIDER", "oceanbase")
+	t.Setenv("OCEANBASE_SSL_MODE", "verify_ca")
+	t.Setenv("OCEANBASE_SSL_CA", "/etc/powermem/ob-ca.pem")
+	t.Setenv("OCEANBASE_CHARSET", "utf8mb4")
+	t.Setenv("OCEANBASE_CONNECT_TIMEOUT_SECONDS", "3")
+
+	config, err := core.LoadConfigFromEnv()
+	require.NoError(t, err)
+	require.NotNil(t, config.VectorStore.OceanBase)
+	assert.Equal(t, "verify_ca", config.VectorStore.OceanBase.SSLMode)
+	assert.Equal(t, "/etc/powermem/ob-ca.pem", config.VectorStore.OceanBase.SSLCA)
+	assert.Equal(t, "utf8mb4", config.VectorStore.OceanBase.Charset)
+	assert.Equal(t, 3.0, config.VectorStore.OceanBase.ConnectTimeoutSeconds)
+}
@@ -23,6 +23,15 @@ func TestAddOptions(t *testing.T) {
 	assert.NotNil(t, opt)
 }
 
+func TestWithDryRun(t *testing.T) {
+	opts := &powermem.AddOptions{}
+	powermem.WithDryRun(true)(opts)
+	assert.True(t, opts.DryRun)
+
+	powermem.WithDryRun(false)(opts)
+	assert.False(t, opts.DryRun)
+}
+
 func TestSearchOptions(t *testing.T) {
 	// Test WithUserIDForSearch
 	opt := powermem.WithUserIDForSearch("user123")
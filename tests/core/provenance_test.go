@@ -0,0 +1,100 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_GetProvenance(t *testing.T) {
+	cfg := newChangesConfig(filepath.Join(t.TempDir(), "test_provenance.db"))
+	cfg.LLM.Parameters = map[string]interface{}{"responses": []string{
+		`{"facts": [{"fact": "Lives in Berlin", "confidence": 0.95, "messages": [1]}]}`,
+		`{"memory": [{"id": "0", "text": "Lives in Berlin", "event": "ADD"}]}`,
+		`{"facts": [{"fact": "Moved from Berlin to Lisbon", "confidence": 0.95, "messages": [1]}]}`,
+		`{"memory": [{"id": "0", "text": "Moved from Berlin to Lisbon", "event": "UPDATE", "old_memory": "Lives in Berlin"}]}`,
+		`{"actions": [{"event": "RESOLVE", "ids": ["1", "0"], "text": "Lives in Lisbon with a cat, previously Berlin", "reason": "Same home"}]}`,
+	}}
+	cfg.Intelligence = &core.IntelligenceConfig{Enabled: true, DuplicateThreshold: 0.95}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	result, err := client.IntelligentAdd(ctx, []map[string]interface{}{
+		{"id": "msg-1", "role": "user", "content": "I live in Berlin"},
+	}, core.WithUserID("user_001"))
+	require.NoError(t, err)
+	require.Len(t, result.Results, 1)
+	berlin := result.Results[0]
+
+	// A new memory has no provenance entries
+	provenance, err := client.GetProvenance(ctx, berlin.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Lives in Berlin", provenance.Content)
+	assert.Empty(t, provenance.Entries)
+
+	// An update records the previous content and the incoming message
+	_, err = client.IntelligentAdd(ctx, []map[string]interface{}{
+		{"id": "msg-2", "role": "user", "content": "I moved to Lisbon"},
+	}, core.WithUserID("user_001"))
+	require.NoError(t, err)
+	provenance, err = client.GetProvenance(ctx, berlin.ID, core.WithUserIDForGet("user_001"))
+	require.NoError(t, err)
+	assert.Equal(t, "Moved from Berlin to Lisbon", provenance.Content)
+	require.Len(t, provenance.Entries, 1)
+	update := provenance.Entries[0]
+	assert.Equal(t, core.ProvenanceUpdate, update.Operation)
+	assert.Zero(t, update.MemoryID)
+	assert.Equal(t, "Lives in Berlin", update.PreviousContent)
+	assert.Equal(t, "Moved from Berlin to Lisbon", update.IncomingContent)
+	assert.Equal(t, []core.Source{{MessageID: "msg-2", Turn: 1}}, update.Sources)
+	assert.False(t, update.Time.IsZero())
+
+	_, err = client.GetProvenance(ctx, berlin.ID, core.WithUserIDForGet("user_002"))
+	assert.Error(t, err)
+
+	// Consolidating into another memory keeps the chain of the merged one
+	time.Sleep(2 * time.Millisecond) // distinct creation times
+	cat, err := client.Add(ctx, "Has a cat", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	_, err = client.Consolidate(ctx, "user_001")
+	require.NoError(t, err)
+
+	provenance, err = client.GetProvenance(ctx, cat.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Lives in Lisbon with a cat, previously Berlin", provenance.Content)
+	require.Len(t, provenance.Entries, 2)
+	assert.Equal(t, core.ProvenanceUpdate, provenance.Entries[0].Operation)
+	assert.Equal(t, berlin.ID, provenance.Entries[0].MemoryID)
+	assert.Equal(t, "Lives in Berlin", provenance.Entries[0].PreviousContent)
+	consolidation := provenance.Entries[1]
+	assert.Equal(t, core.ProvenanceConsolidate, consolidation.Operation)
+	assert.Equal(t, "Has a cat", consolidation.PreviousContent)
+	assert.Equal(t, "Lives in Lisbon with a cat, previously Berlin", consolidation.IncomingContent)
+	assert.Equal(t, []int64{berlin.ID}, consolidation.SourceMemoryIDs)
+	assert.Equal(t, []string{"Moved from Berlin to Lisbon"}, consolidation.SourceContents)
+	assert.Equal(t, "Same home", consolidation.Reason)
+
+	// Forgetting part of the memory forgets its previous contents too
+	memory, err := client.Get(ctx, cat.ID)
+	require.NoError(t, err)
+	_, err = client.Forget(ctx, "user_001", "Forget where I lived", core.WithConfirmedForgetActions(core.ForgetActionResult{
+		Event:     "UPDATE",
+		ID:        cat.ID,
+		Version:   memory.Version,
+		Memory:    memory.Content,
+		NewMemory: "Has a cat",
+	}))
+	require.NoError(t, err)
+	provenance, err = client.GetProvenance(ctx, cat.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Has a cat", provenance.Content)
+	assert.Empty(t, provenance.Entries)
+}
@@ -0,0 +1,56 @@
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestVectorStoreConfig_QuantizationJSON(t *testing.T) {
+	var store core.VectorStoreConfig
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"provider": "postgres",
+		"config": {"quantization": "bit", "rerank_factor": 16}
+	}`), &store))
+	require.NotNil(t, store.Postgres)
+	assert.Equal(t, "bit", store.Postgres.Quantization)
+	assert.Equal(t, 16, store.Postgres.RerankFactor)
+}
+
+func TestConfigValidate_Quantization(t *testing.T) {
+	config := &core.Config{
+		LLM:      core.LLMConfig{Provider: "mock"},
+		Embedder: core.EmbedderConfig{Provider: "mock"},
+		VectorStore: core.VectorStoreConfig{
+			Postgres: &core.PostgresConfig{Quantization: "int8", RerankFactor: -1},
+		},
+	}
+	err := config.Validate()
+	var validationErr *core.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	fields := make(map[string]string)
+	for _, fieldErr := range validationErr.Fields {
+		fields[fieldErr.Field] = fieldErr.Message
+	}
+	assert.Equal(t, map[string]string{
+		"vector_store.config.quantization":  `unknown quantization "int8" (want none, halfvec or bit)`,
+		"vector_store.config.rerank_factor": "must not be negative, got -1",
+	}, fields)
+}
+
+func TestLoadConfigFromEnv_Quantization(t *testing.T) {
+	t.Setenv("DATABASE_PROVIDER", "postgres")
+	t.Setenv("POSTGRES_QUANTIZATION", "halfvec")
+	t.Setenv("POSTGRES_RERANK_FACTOR", "8")
+
+	config, err := core.LoadConfigFromEnv()
+	require.NoError(t, err)
+	require.NotNil(t, config.VectorStore.Postgres)
+	assert.Equal(t, "halfvec", config.VectorStore.Postgres.Quantization)
+	assert.Equal(t, 8, config.VectorStore.Postgres.RerankFactor)
+}
@@ -0,0 +1,54 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+)
+
+func TestClient_QueryCache(t *testing.T) {
+	clock := intelligence.NewManualClock(time.Now())
+	config := newChangesConfig(filepath.Join(t.TempDir(), "test_query_cache.db"))
+	config.QueryCache = &core.QueryCacheConfig{Enabled: true, TTLSeconds: 30}
+	config.Clock = clock
+	client, err := core.NewClient(config)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	_, err = client.Add(ctx, "Prefers window seats", core.WithUserID("user_001"))
+	require.NoError(t, err)
+
+	search := func(query string) *core.SearchDiagnostics {
+		results, diag, err := client.SearchWithDiagnostics(ctx, query, core.WithUserIDForSearch("user_001"))
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		return diag
+	}
+
+	assert.Equal(t, 0, search("What do you know about me?").CachedEmbeddings)
+	// Case and spacing do not matter
+	assert.Equal(t, 1, search("  what do you KNOW about   me?").CachedEmbeddings)
+	assert.Equal(t, 0, search("Where do I like to sit?").CachedEmbeddings)
+
+	// Entries expire after the TTL
+	clock.Advance(31 * time.Second)
+	assert.Equal(t, 0, search("What do you know about me?").CachedEmbeddings)
+	assert.Equal(t, 1, search("What do you know about me?").CachedEmbeddings)
+
+	// A changed configuration starts with an empty cache
+	next := *config
+	next.QueryCache = &core.QueryCacheConfig{Enabled: true, TTLSeconds: 60}
+	require.NoError(t, client.Reload(ctx, &next))
+	assert.Equal(t, 0, search("What do you know about me?").CachedEmbeddings)
+
+	next.QueryCache = &core.QueryCacheConfig{Size: -1}
+	assert.ErrorContains(t, next.Validate(), "query_cache.size")
+}
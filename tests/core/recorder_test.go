@@ -0,0 +1,81 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestIntelligentAdd_RecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	golden := filepath.Join(dir, "intelligent_add.json")
+
+	run := func(name string, llmConfig core.LLMConfig) *core.IntelligentAddResult {
+		client, err := core.NewClient(&core.Config{
+			VectorStore: core.VectorStoreConfig{
+				Provider: "sqlite",
+				SQLite:   &core.SQLiteConfig{DBPath: filepath.Join(dir, name+".db"), EmbeddingModelDims: 64},
+			},
+			LLM:          llmConfig,
+			Embedder:     core.EmbedderConfig{Provider: "mock", Dimensions: 64},
+			Intelligence: &core.IntelligenceConfig{Enabled: true, DuplicateThreshold: 0.95},
+		})
+		require.NoError(t, err)
+		defer client.Close()
+		ctx := context.Background()
+
+		_, err = client.Add(ctx, "Likes hiking", core.WithUserID("user_001"))
+		require.NoError(t, err)
+		result, err := client.IntelligentAdd(ctx, "I love hiking in the Alps and I live in Berlin", core.WithUserID("user_001"))
+		require.NoError(t, err)
+		return result
+	}
+
+	// Record the decisions of a (here scripted) provider
+	recorded := run("record", core.LLMConfig{
+		Provider: "mock",
+		Parameters: map[string]interface{}{"responses": []string{
+			`{"facts": [{"fact": "Loves hiking in the Alps", "confidence": 0.9}, {"fact": "Lives in Berlin", "confidence": 0.95}]}`,
+			`{"memory": [{"id": "0", "text": "Loves hiking in the Alps", "event": "UPDATE", "old_memory": "Likes hiking"}, {"id": "1", "text": "Lives in Berlin", "event": "ADD"}]}`,
+		}},
+		Recording: &core.LLMRecordingConfig{Path: golden, Mode: "record"},
+	})
+	require.FileExists(t, golden)
+
+	// Replay against a provider that cannot be reached
+	replayed := run("replay", core.LLMConfig{
+		Provider:  "openai",
+		BaseURL:   "http://127.0.0.1:1",
+		Recording: &core.LLMRecordingConfig{Path: golden},
+	})
+
+	events := func(result *core.IntelligentAddResult) []string {
+		var events []string
+		for _, r := range result.Results {
+			events = append(events, r.Event+": "+r.Memory)
+		}
+		return events
+	}
+	assert.NotEmpty(t, events(recorded))
+	assert.Equal(t, events(recorded), events(replayed))
+}
+
+func TestConfig_ValidateLLMRecording(t *testing.T) {
+	cfg := &core.Config{
+		VectorStore: core.VectorStoreConfig{Provider: "sqlite"},
+		LLM: core.LLMConfig{
+			Provider:  "mock",
+			Recording: &core.LLMRecordingConfig{Mode: "rewind"},
+		},
+		Embedder: core.EmbedderConfig{Provider: "mock"},
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.recording.path: is required")
+	assert.Contains(t, err.Error(), `llm.recording.mode: unknown mode "rewind"`)
+}
@@ -0,0 +1,184 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+// newReloadTestConfig returns an offline config whose LLM uses llmURL.
+func newReloadTestConfig(dbPath, llmURL, embedderURL string) *core.Config {
+	return &core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite:   &core.SQLiteConfig{DBPath: dbPath, EmbeddingModelDims: 3},
+		},
+		LLM: core.LLMConfig{Provider: "openai", APIKey: "test-key", Model: "gpt-3.5-turbo", BaseURL: llmURL},
+		Embedder: core.EmbedderConfig{
+			Provider: "openai", APIKey: "test-key", Model: "text-embedding-ada-002", BaseURL: embedderURL, Dimensions: 3,
+		},
+	}
+}
+
+func TestClient_Reload(t *testing.T) {
+	testDBPath := "./test_reload.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	url, chatCalls := newHyDEServer(t)
+	newURL, newChatCalls := newHyDEServer(t)
+
+	client, err := core.NewClient(newReloadTestConfig(testDBPath, url, url))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	// Without intelligence, Infer is a simple add
+	_, err = client.Add(ctx, "I like hiking", core.WithUserID("user_001"), core.WithInfer(true))
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(chatCalls))
+
+	// Switch the LLM and enable intelligence at runtime
+	reloaded := newReloadTestConfig(testDBPath, newURL, url)
+	reloaded.LLM.Model = "gpt-4"
+	reloaded.Intelligence = &core.IntelligenceConfig{
+		Enabled:             true,
+		DecayRate:           0.1,
+		ReinforcementFactor: 0.3,
+		DuplicateThreshold:  0.95,
+	}
+	require.NoError(t, client.Reload(ctx, reloaded))
+
+	// The fake LLM returns no facts; only which provider is called matters here
+	_, _ = client.Add(ctx, "I go hiking on weekends", core.WithUserID("user_001"), core.WithInfer(true))
+	assert.Equal(t, int32(0), atomic.LoadInt32(chatCalls))
+	assert.Greater(t, atomic.LoadInt32(newChatCalls), int32(0), "intelligent add should use the reloaded LLM")
+
+	report, err := client.Health(ctx, core.WithHealthComponents(core.HealthLLM))
+	require.NoError(t, err)
+	assert.True(t, report.Healthy())
+	assert.Equal(t, int32(0), atomic.LoadInt32(chatCalls))
+}
+
+func TestClient_ReloadRejectsUnsafeChanges(t *testing.T) {
+	testDBPath := "./test_reload_reject.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	url, _ := newHyDEServer(t)
+	client, err := core.NewClient(newReloadTestConfig(testDBPath, url, url))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	// Spelling out a default is not a change
+	same := newReloadTestConfig(testDBPath, url, url)
+	same.VectorStore.SQLite.CollectionName = "memories"
+	require.NoError(t, client.Reload(ctx, same))
+
+	moved := newReloadTestConfig("./elsewhere.db", url, url)
+	moved.Embedder.Model = "text-embedding-3-small"
+	moved.IDType = core.IDTypeUUID
+	err = client.Reload(ctx, moved)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, core.ErrInvalidConfig))
+
+	var validationErr *core.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	var fields []string
+	for _, fieldErr := range validationErr.Fields {
+		fields = append(fields, fieldErr.Field)
+	}
+	assert.Equal(t, []string{"vector_store", "embedder", "id_type"}, fields)
+
+	invalid := newReloadTestConfig(testDBPath, url, url)
+	invalid.Intelligence = &core.IntelligenceConfig{Enabled: true, DuplicateThreshold: 2}
+	err = client.Reload(ctx, invalid)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, core.ErrInvalidConfig))
+
+	require.NoError(t, client.Close())
+	assert.True(t, errors.Is(client.Reload(ctx, same), core.ErrClientClosed))
+}
+
+func TestClient_WatchConfigFile(t *testing.T) {
+	testDBPath := "./test_reload_watch.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	url, _ := newHyDEServer(t)
+	client, err := core.NewClient(newReloadTestConfig(testDBPath, url, url))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	configFile := func(model string, threshold float64) string {
+		return fmt.Sprintf(`
+llm:
+  provider: openai
+  api_key: test-key
+  model: %s
+  base_url: %s
+embedder:
+  provider: openai
+  api_key: test-key
+  model: text-embedding-ada-002
+  base_url: %s
+  dimensions: 3
+vector_store:
+  provider: sqlite
+  config:
+    db_path: %s
+    embedding_model_dims: 3
+intelligence:
+  enabled: true
+  duplicate_threshold: %v
+`, model, url, url, testDBPath, threshold)
+	}
+
+	path := filepath.Join(t.TempDir(), "powermem.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(configFile("gpt-3.5-turbo", 0.9)), 0o600))
+
+	reloads := make(chan *core.Config, 10)
+	reloadErrors := make(chan error, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	require.NoError(t, client.WatchConfigFile(ctx, path,
+		core.WithWatchInterval(10*time.Millisecond),
+		core.WithReloadHandler(func(cfg *core.Config) { reloads <- cfg }),
+		core.WithReloadErrorHandler(func(err error) { reloadErrors <- err }),
+	))
+
+	// An invalid update is reported and not applied
+	require.NoError(t, os.WriteFile(path, []byte(configFile("gpt-4", 1.5)), 0o600))
+	select {
+	case err := <-reloadErrors:
+		assert.True(t, errors.Is(err, core.ErrInvalidConfig))
+		assert.Contains(t, err.Error(), "intelligence.duplicate_threshold")
+	case <-time.After(5 * time.Second):
+		t.Fatal("invalid update was not reported")
+	}
+
+	// Fixing the file applies it
+	require.NoError(t, os.WriteFile(path, []byte(configFile("gpt-4", 0.8)), 0o600))
+	select {
+	case cfg := <-reloads:
+		assert.Equal(t, "gpt-4", cfg.LLM.Model)
+		assert.Equal(t, 0.8, cfg.Intelligence.DuplicateThreshold)
+	case <-time.After(5 * time.Second):
+		t.Fatal("valid update was not applied")
+	}
+	assert.Empty(t, reloadErrors)
+
+	assert.Error(t, client.WatchConfigFile(ctx, filepath.Join(t.TempDir(), "missing.yaml")))
+}
@@ -0,0 +1,101 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+func TestVectorStoreConfig_ReadReplicasJSON(t *testing.T) {
+	var store core.VectorStoreConfig
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"provider": "postgres",
+		"config": {
+			"dsn": "postgres://powermem@pg-primary/powermem",
+			"read_dsns": ["postgres://powermem@pg-replica-1/powermem", "postgres://powermem@pg-replica-2/powermem"],
+			"max_replica_lag_seconds": 2.5
+		}
+	}`), &store))
+	require.NotNil(t, store.Postgres)
+	assert.Equal(t, "postgres://powermem@pg-primary/powermem", store.Postgres.DSN)
+	assert.Equal(t, []string{
+		"postgres://powermem@pg-replica-1/powermem",
+		"postgres://powermem@pg-replica-2/powermem",
+	}, store.Postgres.ReadDSNs)
+	assert.Equal(t, 2.5, store.Postgres.MaxReplicaLagSeconds)
+
+	// The JSON form round-trips
+	data, err := json.Marshal(store)
+	require.NoError(t, err)
+	var decoded core.VectorStoreConfig
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, store, decoded)
+
+	// Values of the wrong type name the field
+	err = json.Unmarshal([]byte(`{
+		"provider": "oceanbase",
+		"config": {"read_dsns": ["root@tcp(ob-replica:2881)/powermem", 1], "dsn": []}
+	}`), &store)
+	var validationErr *core.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Len(t, validationErr.Fields, 2)
+	assert.Equal(t, "vector_store.config.dsn", validationErr.Fields[0].Field)
+	assert.Equal(t, "vector_store.config.read_dsns[1]", validationErr.Fields[1].Field)
+	assert.Equal(t, "must be a string, got float64", validationErr.Fields[1].Message)
+
+	err = json.Unmarshal([]byte(`{"provider": "postgres", "config": {"max_replica_lag_seconds": "2s"}}`), &store)
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "vector_store.config.max_replica_lag_seconds", validationErr.Fields[0].Field)
+}
+
+func TestConfigValidate_ReadReplicas(t *testing.T) {
+	config := &core.Config{
+		LLM:      core.LLMConfig{Provider: "mock"},
+		Embedder: core.EmbedderConfig{Provider: "mock"},
+		VectorStore: core.VectorStoreConfig{
+			Postgres: &core.PostgresConfig{
+				ReadDSNs:             []string{"host=pg-replica-1", " "},
+				MaxReplicaLagSeconds: -1,
+			},
+		},
+	}
+	err := config.Validate()
+	var validationErr *core.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	fields := make(map[string]string)
+	for _, fieldErr := range validationErr.Fields {
+		fields[fieldErr.Field] = fieldErr.Message
+	}
+	assert.Equal(t, map[string]string{
+		"vector_store.config.read_dsns[1]":            "is empty",
+		"vector_store.config.max_replica_lag_seconds": "must not be negative, got -1",
+	}, fields)
+}
+
+func TestLoadConfigFromEnv_ReadReplicas(t *testing.T) {
+	t.Setenv("DATABASE_PROVIDER", "postgres")
+	t.Setenv("POSTGRES_DSN", "host=pg-primary dbname=powermem")
+	t.Setenv("POSTGRES_READ_DSNS", "host=pg-replica-1 dbname=powermem; host=pg-replica-2 dbname=powermem")
+	t.Setenv("POSTGRES_MAX_REPLICA_LAG_SECONDS", "5")
+
+	config, err := core.LoadConfigFromEnv()
+	require.NoError(t, err)
+	require.NotNil(t, config.VectorStore.Postgres)
+	assert.Equal(t, "host=pg-primary dbname=powermem", config.VectorStore.Postgres.DSN)
+	assert.Equal(t, []string{"host=pg-replica-1 dbname=powermem", "host=pg-replica-2 dbname=powermem"},
+		config.VectorStore.Postgres.ReadDSNs)
+	assert.Equal(t, 5.0, config.VectorStore.Postgres.MaxReplicaLagSeconds)
+}
+
+func TestContextWithPrimaryRead(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, storage.PrimaryFromContext(ctx))
+	assert.True(t, storage.PrimaryFromContext(core.ContextWithPrimaryRead(ctx)))
+}
@@ -0,0 +1,204 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+)
+
+func newReplicaConfig(dir, name string, nodeID int64) *core.Config {
+	return &core.Config{
+		NodeID: nodeID,
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite:   &core.SQLiteConfig{DBPath: filepath.Join(dir, name+".db"), CollectionName: "memories", EmbeddingModelDims: 64},
+		},
+		LLM:       core.LLMConfig{Provider: "mock"},
+		Embedder:  core.EmbedderConfig{Provider: "mock", Dimensions: 64},
+		ChangeLog: &core.ChangeLogConfig{Enabled: true},
+		Replication: &core.ReplicationConfig{
+			Remote: core.VectorStoreConfig{
+				Provider: "sqlite",
+				SQLite:   &core.SQLiteConfig{DBPath: filepath.Join(dir, "central.db"), CollectionName: "memories", EmbeddingModelDims: 64},
+			},
+			StatePath: filepath.Join(dir, name+".sync.json"),
+		},
+	}
+}
+
+func TestClient_Sync(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	edgeA, err := core.NewClient(newReplicaConfig(dir, "edge_a", 2))
+	require.NoError(t, err)
+	defer edgeA.Close()
+	edgeB, err := core.NewClient(newReplicaConfig(dir, "edge_b", 3))
+	require.NoError(t, err)
+	defer edgeB.Close()
+
+	// A memory added offline on A reaches B through the central store
+	added, err := edgeA.Add(ctx, "User loves hiking", core.WithUserID("user_001"), core.WithInfer(false))
+	require.NoError(t, err)
+	report, err := edgeA.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Pushed)
+	report, err = edgeB.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Pulled)
+
+	original, err := edgeA.Get(ctx, added.ID)
+	require.NoError(t, err)
+	copied, err := edgeB.Get(ctx, added.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "User loves hiking", copied.Content)
+	assert.True(t, original.CreatedAt.Equal(copied.CreatedAt), "the copy keeps its timestamps")
+	results, err := edgeB.Search(ctx, "User loves hiking", core.WithUserIDForSearch("user_001"), core.WithLimit(1))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, added.ID, results[0].ID)
+
+	// Nothing is exchanged again, and A does not pull back its own push
+	report, err = edgeA.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Pushed+report.Pulled)
+
+	// Concurrent updates: the newest one wins on both sides
+	_, err = edgeB.Update(ctx, added.ID, "User loves climbing")
+	require.NoError(t, err)
+	_, err = edgeA.Update(ctx, added.ID, "User loves skiing")
+	require.NoError(t, err)
+	_, err = edgeB.Sync(ctx)
+	require.NoError(t, err)
+	report, err = edgeA.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Conflicts)
+	_, err = edgeB.Sync(ctx)
+	require.NoError(t, err)
+	for _, client := range []*core.Client{edgeA, edgeB} {
+		memory, err := client.Get(ctx, added.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "User loves skiing", memory.Content)
+	}
+
+	// Deletions are replicated
+	require.NoError(t, edgeB.Delete(ctx, added.ID))
+	_, err = edgeB.Sync(ctx)
+	require.NoError(t, err)
+	_, err = edgeA.Sync(ctx)
+	require.NoError(t, err)
+	_, err = edgeA.Get(ctx, added.ID)
+	assert.Error(t, err)
+
+	// A new client resumes from the saved state
+	require.NoError(t, edgeA.Close())
+	edgeA, err = core.NewClient(newReplicaConfig(dir, "edge_a", 2))
+	require.NoError(t, err)
+	report, err = edgeA.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Pushed+report.Pulled)
+}
+
+func TestClient_SyncBulkChanges(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	edgeA, err := core.NewClient(newReplicaConfig(dir, "edge_a", 2))
+	require.NoError(t, err)
+	defer edgeA.Close()
+	edgeB, err := core.NewClient(newReplicaConfig(dir, "edge_b", 3))
+	require.NoError(t, err)
+	defer edgeB.Close()
+
+	add := func(content, userID string, metadata map[string]interface{}) *core.Memory {
+		memory, err := edgeA.Add(ctx, content, core.WithUserID(userID), core.WithMetadata(metadata), core.WithInfer(false))
+		require.NoError(t, err)
+		return memory
+	}
+	debug := add("Debug trace", "user_001", map[string]interface{}{"type": "debug"})
+	kept := add("User loves hiking", "user_001", nil)
+	erased := add("User lives in Berlin", "user_002", nil)
+	sync := func() {
+		for _, client := range []*core.Client{edgeA, edgeB, edgeA} {
+			_, err := client.Sync(ctx)
+			require.NoError(t, err)
+		}
+	}
+	sync()
+
+	// Memories added on both edges get distinct IDs
+	other, err := edgeB.Add(ctx, "User has a cat", core.WithUserID("user_001"), core.WithInfer(false))
+	require.NoError(t, err)
+	assert.NotEqual(t, other.ID, kept.ID)
+	sync()
+
+	// DeleteWhere and EraseUser on B reach A through the central store
+	filters := map[string]interface{}{"type": "debug"}
+	plan, err := edgeB.DeleteWhere(ctx, filters, core.WithUserIDForDeleteWhere("user_001"))
+	require.NoError(t, err)
+	_, err = edgeB.DeleteWhere(ctx, filters, core.WithUserIDForDeleteWhere("user_001"), core.WithConfirmedCount(plan.Matched))
+	require.NoError(t, err)
+	_, err = edgeB.EraseUser(ctx, "user_002")
+	require.NoError(t, err)
+	report, err := edgeB.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Bulk)
+	report, err = edgeA.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Bulk)
+
+	for _, id := range []int64{debug.ID, erased.ID} {
+		_, err = edgeA.Get(ctx, id)
+		assert.ErrorIs(t, err, storage.ErrNotFound, "memory %d", id)
+	}
+	for _, id := range []int64{kept.ID, other.ID} {
+		_, err = edgeA.Get(ctx, id)
+		assert.NoError(t, err, "memory %d", id)
+	}
+
+	// A deletion keeps the memories updated on the other side after it
+	require.NoError(t, edgeB.DeleteAll(ctx, core.WithUserIDForDeleteAll("user_001")))
+	time.Sleep(10 * time.Millisecond)
+	_, err = edgeA.Update(ctx, kept.ID, "User loves climbing")
+	require.NoError(t, err)
+	sync()
+	_, err = edgeB.Sync(ctx)
+	require.NoError(t, err)
+	for _, client := range []*core.Client{edgeA, edgeB} {
+		memories, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_001"))
+		require.NoError(t, err)
+		require.Len(t, memories, 1)
+		assert.Equal(t, "User loves climbing", memories[0].Content)
+	}
+}
+
+func TestClient_SyncConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg := newReplicaConfig(dir, "edge", 2)
+	cfg.ChangeLog = nil
+	assert.ErrorIs(t, cfg.Validate(), core.ErrInvalidConfig, "replication requires the change log")
+
+	cfg = newReplicaConfig(dir, "edge", 2)
+	cfg.Replication.Conflict = "oldest"
+	assert.ErrorIs(t, cfg.Validate(), core.ErrInvalidConfig)
+
+	// Replicas must not assign the IDs of the default node
+	for _, nodeID := range []int64{0, 1, 1024} {
+		cfg = newReplicaConfig(dir, "edge", nodeID)
+		assert.ErrorIs(t, cfg.Validate(), core.ErrInvalidConfig, "node %d", nodeID)
+	}
+
+	cfg = newReplicaConfig(dir, "edge", 2)
+	cfg.Replication = nil
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	_, err = client.Sync(context.Background())
+	assert.ErrorIs(t, err, core.ErrInvalidConfig)
+}
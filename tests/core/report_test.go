@@ -0,0 +1,70 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_GenerateUserReport(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_report.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	seats, err := client.Add(ctx, "Prefers window seats", core.WithUserID("user_001"),
+		core.WithMemoryType(core.MemoryTypePreference), core.WithTags("travel"))
+	require.NoError(t, err)
+	_, err = client.Add(ctx, "Allergic to peanuts", core.WithUserID("user_001"),
+		core.WithMemoryType(core.MemoryTypeFact), core.WithTags("health"))
+	require.NoError(t, err)
+	_, err = client.Add(ctx, "Likes | pipes", core.WithUserID("user_001"),
+		core.WithMemoryType(core.MemoryTypePreference), core.WithTags("travel"))
+	require.NoError(t, err)
+	_, err = client.Add(ctx, "Prefers aisle seats", core.WithUserID("user_002"))
+	require.NoError(t, err)
+	_, err = client.Update(ctx, seats.ID, "Prefers window seats on long flights")
+	require.NoError(t, err)
+
+	report, err := client.GenerateUserReport(ctx, "user_001",
+		core.WithReportTopMemories(2), core.WithReportRecentChanges(2))
+	require.NoError(t, err)
+	assert.Equal(t, "user_001", report.UserID)
+	assert.Equal(t, 3, report.TotalMemories)
+	assert.Equal(t, []core.TopicCount{{Name: "preference", Count: 2}, {Name: "fact", Count: 1}}, report.MemoryTypes)
+	assert.Equal(t, []core.TopicCount{{Name: "travel", Count: 2}, {Name: "health", Count: 1}}, report.Tags)
+	assert.Len(t, report.TopMemories, 2)
+	require.NotNil(t, report.Oldest)
+
+	// The latest changes of the user only, oldest first
+	require.Len(t, report.RecentChanges, 2)
+	assert.Equal(t, core.EventCreated, report.RecentChanges[0].Type)
+	assert.Equal(t, core.EventUpdated, report.RecentChanges[1].Type)
+	assert.Equal(t, seats.ID, report.RecentChanges[1].MemoryID)
+
+	markdown := report.Markdown()
+	assert.Contains(t, markdown, "# What we know about user_001")
+	assert.Contains(t, markdown, "3 memories")
+	assert.Contains(t, markdown, "| preference | 2 |")
+	assert.Contains(t, markdown, "| travel | 2 |")
+	assert.Contains(t, markdown, "Prefers window seats → Prefers window seats on long flights")
+	assert.NotContains(t, markdown, "aisle")
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"total_memories":3`)
+
+	empty, err := client.GenerateUserReport(ctx, "user_003")
+	require.NoError(t, err)
+	assert.Zero(t, empty.TotalMemories)
+	assert.Contains(t, empty.Markdown(), "No memories are stored.")
+
+	_, err = client.GenerateUserReport(ctx, "")
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+}
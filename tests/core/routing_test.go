@@ -0,0 +1,157 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	sqliteStore "github.com/oceanbase/powermem-go/pkg/storage/sqlite"
+)
+
+func TestVectorStoreConfig_RoutingJSON(t *testing.T) {
+	var store core.VectorStoreConfig
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"provider": "routing",
+		"config": {
+			"stores": {
+				"eu": {"provider": "postgres", "config": {"host": "pg.eu.internal", "port": 5432}},
+				"us": {"provider": "sqlite", "config": {"db_path": "./us.db"}}
+			},
+			"default": "us",
+			"users": {"user_001": "eu"}
+		}
+	}`), &store))
+	require.NotNil(t, store.Routing)
+	assert.Equal(t, "us", store.Routing.Default)
+	assert.Equal(t, map[string]string{"user_001": "eu"}, store.Routing.Users)
+	require.NotNil(t, store.Routing.Stores["eu"].Postgres)
+	assert.Equal(t, "pg.eu.internal", store.Routing.Stores["eu"].Postgres.Host)
+	assert.Equal(t, 5432, store.Routing.Stores["eu"].Postgres.Port)
+
+	// The JSON form round-trips
+	data, err := json.Marshal(store)
+	require.NoError(t, err)
+	var decoded core.VectorStoreConfig
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, store, decoded)
+
+	// Errors of the routed stores are reported under their name
+	err = json.Unmarshal([]byte(`{
+		"provider": "routing",
+		"config": {"stores": {"eu": {"provider": "postgres", "config": {"port": "5432"}}}, "users": {"user_001": 1}}
+	}`), &store)
+	var validationErr *core.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Len(t, validationErr.Fields, 2)
+	assert.Equal(t, "vector_store.config.users.user_001", validationErr.Fields[0].Field)
+	assert.Equal(t, "vector_store.config.stores.eu.config.port", validationErr.Fields[1].Field)
+}
+
+func TestConfigValidate_Routing(t *testing.T) {
+	config := &core.Config{
+		LLM:      core.LLMConfig{Provider: "mock"},
+		Embedder: core.EmbedderConfig{Provider: "mock"},
+		VectorStore: core.VectorStoreConfig{
+			Provider: "routing",
+			Routing: &core.RoutingConfig{
+				Stores: map[string]core.VectorStoreConfig{
+					"eu":     {Postgres: &core.PostgresConfig{Port: 70000}},
+					"nested": {Routing: &core.RoutingConfig{}},
+				},
+				Default: "us",
+				Users:   map[string]string{"user_001": "apac"},
+			},
+		},
+	}
+	err := config.Validate()
+	var validationErr *core.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	fields := make(map[string]string)
+	for _, fieldErr := range validationErr.Fields {
+		fields[fieldErr.Field] = fieldErr.Message
+	}
+	assert.Equal(t, map[string]string{
+		"vector_store.config.stores.eu.config.port":  "must be between 1 and 65535, got 70000",
+		"vector_store.config.stores.nested.provider": "cannot be routing",
+		"vector_store.config.default":                `unknown store "us"`,
+		"vector_store.config.change_log_store":       `unknown store "us"`,
+		"vector_store.config.users.user_001":         `unknown store "apac"`,
+	}, fields)
+
+	config.VectorStore.Routing = nil
+	err = config.Validate()
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "vector_store.config.stores", validationErr.Fields[0].Field)
+}
+
+func TestClient_RoutingStore(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := func(name string) string { return filepath.Join(dir, name+".db") }
+	stores := make(map[string]core.VectorStoreConfig)
+	for _, name := range []string{"eu", "us"} {
+		stores[name] = core.VectorStoreConfig{SQLite: &core.SQLiteConfig{DBPath: dbPath(name)}}
+	}
+
+	client, err := core.NewClient(&core.Config{
+		LLM:      core.LLMConfig{Provider: "mock"},
+		Embedder: core.EmbedderConfig{Provider: "mock", Dimensions: 64},
+		VectorStore: core.VectorStoreConfig{
+			Provider: "routing",
+			Routing: &core.RoutingConfig{
+				Stores:  stores,
+				Default: "us",
+				Users:   map[string]string{"user_001": "eu"},
+				Policy: func(ctx context.Context, userID string) (string, error) {
+					if userID == "user_002" {
+						return "eu", nil
+					}
+					return "", nil
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	ids := make(map[string]int64)
+	for _, userID := range []string{"user_001", "user_002", "user_003"} {
+		memory, err := client.Add(ctx, fmt.Sprintf("Memory of %s", userID), core.WithUserID(userID))
+		require.NoError(t, err)
+		ids[userID] = memory.ID
+	}
+
+	// Reads without a user see every store
+	memory, err := client.Get(ctx, ids["user_001"])
+	require.NoError(t, err)
+	assert.Equal(t, "Memory of user_001", memory.Content)
+	memories, err := client.GetAll(ctx, core.WithAllowCrossUserForGetAll(true))
+	require.NoError(t, err)
+	assert.Len(t, memories, 3)
+	results, err := client.Search(ctx, "Memory of user_002", core.WithUserIDForSearch("user_002"))
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, ids["user_002"], results[0].ID)
+	require.NoError(t, client.Close())
+
+	// Each user's memories are kept in the user's store
+	for name, userIDs := range map[string][]string{"eu": {"user_001", "user_002"}, "us": {"user_003"}} {
+		store, err := sqliteStore.NewClient(&sqliteStore.Config{DBPath: dbPath(name), CollectionName: "memories", EmbeddingModelDims: 64})
+		require.NoError(t, err)
+		stored, err := store.GetAll(ctx, &storage.GetAllOptions{Limit: 10})
+		require.NoError(t, err)
+		var storedUsers []string
+		for _, memory := range stored {
+			storedUsers = append(storedUsers, memory.UserID)
+		}
+		assert.ElementsMatch(t, userIDs, storedUsers, name)
+		require.NoError(t, store.Close())
+	}
+}
@@ -0,0 +1,140 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/secrets"
+)
+
+// newKeyRecordingServer starts an OpenAI-compatible server that records the
+// API key of each request.
+func newKeyRecordingServer(t *testing.T) (string, func() []string) {
+	var mu sync.Mutex
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/embeddings") {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "list",
+				"data":   []map[string]interface{}{{"object": "embedding", "index": 0, "embedding": []float64{1, 0, 0}}},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"object": "chat.completion",
+			"choices": []map[string]interface{}{
+				{"index": 0, "finish_reason": "stop", "message": map[string]interface{}{"role": "assistant", "content": "pong"}},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), keys...)
+	}
+}
+
+func TestClient_APIKeysFromSecrets(t *testing.T) {
+	testDBPath := "./test_secrets.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	url, keys := newKeyRecordingServer(t)
+
+	var mu sync.Mutex
+	values := map[string]string{"llm-key": "sk-llm-1", "embedder-key": "sk-embed-1"}
+	provider := secrets.ProviderFunc(func(ctx context.Context, name string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		value, ok := values[name]
+		if !ok {
+			return "", secrets.ErrNotFound
+		}
+		return value, nil
+	})
+
+	client, err := core.NewClient(&core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite:   &core.SQLiteConfig{DBPath: testDBPath, EmbeddingModelDims: 3},
+		},
+		LLM: core.LLMConfig{Provider: "openai", APIKeySecret: "llm-key", Model: "gpt-4", BaseURL: url},
+		Embedder: core.EmbedderConfig{
+			Provider: "openai", APIKeySecret: "embedder-key", Model: "text-embedding-ada-002", BaseURL: url, Dimensions: 3,
+		},
+		Secrets: secrets.NewCache(provider, 20*time.Millisecond),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	_, err = client.Health(ctx, core.WithHealthComponents(core.HealthLLM, core.HealthEmbedder))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"sk-llm-1", "sk-embed-1"}, keys())
+
+	// Rotate the keys; they are picked up after the refresh interval
+	mu.Lock()
+	values["llm-key"] = "sk-llm-2"
+	values["embedder-key"] = "sk-embed-2"
+	mu.Unlock()
+	time.Sleep(30 * time.Millisecond)
+
+	report, err := client.Health(ctx, core.WithHealthComponents(core.HealthLLM, core.HealthEmbedder))
+	require.NoError(t, err)
+	assert.True(t, report.Healthy())
+	assert.ElementsMatch(t, []string{"sk-llm-2", "sk-embed-2"}, keys()[2:])
+}
+
+func TestClient_APIKeySecretValidation(t *testing.T) {
+	provider := secrets.ProviderFunc(func(ctx context.Context, name string) (string, error) {
+		return "", secrets.ErrNotFound
+	})
+	dbPath := filepath.Join(t.TempDir(), "test_secrets_validation.db")
+	base := func() *core.Config {
+		return &core.Config{
+			VectorStore: core.VectorStoreConfig{Provider: "sqlite", SQLite: &core.SQLiteConfig{DBPath: dbPath}},
+			LLM:         core.LLMConfig{Provider: "openai", APIKeySecret: "llm-key"},
+			Embedder:    core.EmbedderConfig{Provider: "openai", APIKey: "sk-test"},
+		}
+	}
+
+	// A secret name needs a provider
+	err := base().Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.api_key_secret: requires a secrets provider")
+
+	// and excludes a plain key
+	cfg := base()
+	cfg.Secrets = provider
+	cfg.LLM.APIKey = "sk-plain"
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.api_key_secret: cannot be used together with api_key")
+
+	// A missing secret fails at startup
+	cfg = base()
+	cfg.Secrets = provider
+	_, err = core.NewClient(cfg)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, secrets.ErrNotFound))
+	assert.Contains(t, err.Error(), `"llm-key"`)
+}
@@ -0,0 +1,68 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_ShareMemory(t *testing.T) {
+	cfg := newTestConfig(filepath.Join(t.TempDir(), "test_share.db"))
+	cfg.Audit = &core.AuditConfig{Enabled: true}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := core.ContextWithActor(context.Background(), "alice@example.com")
+
+	original, err := client.Add(ctx, "Project X ships in March",
+		core.WithUserID("alice"), core.WithTags("project_x"), core.WithInfer(false))
+	require.NoError(t, err)
+
+	copied, err := client.ShareMemory(ctx, original.ID, "bob",
+		core.WithUserIDForShare("alice"), core.WithSharePurpose("onboarding"))
+	require.NoError(t, err)
+	assert.NotEqual(t, original.ID, copied.ID)
+	assert.Equal(t, "bob", copied.UserID)
+	require.NotNil(t, copied.SharedFrom)
+	assert.Equal(t, original.ID, copied.SharedFrom.MemoryID)
+	assert.Equal(t, "alice", copied.SharedFrom.UserID)
+	assert.Equal(t, "alice@example.com", copied.SharedFrom.GrantedBy)
+	assert.Equal(t, "onboarding", copied.SharedFrom.Purpose)
+
+	// The consent is read back from the store
+	stored, err := client.Get(ctx, copied.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Project X ships in March", stored.Content)
+	assert.Equal(t, []string{"project_x"}, stored.Tags)
+	require.NotNil(t, stored.SharedFrom)
+	assert.Equal(t, original.ID, stored.SharedFrom.MemoryID)
+	assert.False(t, stored.SharedFrom.GrantedAt.IsZero())
+
+	results, err := client.Search(ctx, "Project X ships in March", core.WithUserIDForSearch("bob"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, copied.ID, results[0].ID)
+
+	// The copy is independent of the original
+	require.NoError(t, client.Delete(ctx, original.ID))
+	_, err = client.Get(ctx, copied.ID)
+	require.NoError(t, err)
+
+	entries, err := client.ListAuditEntries(ctx, core.WithAuditOperations("ShareMemory"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "alice", entries[0].UserID)
+
+	// Only the owner's memories are shared, and not with the owner
+	_, err = client.ShareMemory(ctx, copied.ID, "carol", core.WithUserIDForShare("alice"))
+	assert.Error(t, err)
+	_, err = client.ShareMemory(ctx, copied.ID, "bob")
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+	_, err = client.ShareMemory(ctx, copied.ID, "")
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+}
@@ -0,0 +1,170 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_ShutdownWaitsForStreams(t *testing.T) {
+	testDBPath := "./test_shutdown_stream.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	client := newOfflineTestClient(t, testDBPath, "")
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Add(ctx, "I like hiking", core.WithUserID("user_001"))
+		require.NoError(t, err)
+	}
+
+	stream := client.SearchStream(ctx, "hiking", 1, core.WithUserIDForSearch("user_001"))
+	first := <-stream
+	require.NoError(t, first.Error)
+
+	// The stream is still being consumed
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	err := client.Shutdown(timeoutCtx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// New operations are rejected while shutting down
+	_, err = client.GetAll(ctx)
+	assert.ErrorIs(t, err, core.ErrClientClosed)
+	rejected := <-client.GetAllStream(ctx, 10)
+	assert.ErrorIs(t, rejected.Error, core.ErrClientClosed)
+
+	// The in-flight stream still completes
+	received := len(first.Memories)
+	for result := range stream {
+		require.NoError(t, result.Error)
+		received += len(result.Memories)
+	}
+	assert.Equal(t, 3, received)
+
+	require.NoError(t, client.Shutdown(ctx))
+	require.NoError(t, client.Close())
+}
+
+func TestClient_ShutdownDrainsBatch(t *testing.T) {
+	testDBPath := "./test_shutdown_batch.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	// Embedding calls block until released
+	release := make(chan struct{})
+	var embeddingCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/embeddings") {
+			http.NotFound(w, r)
+			return
+		}
+		atomic.AddInt32(&embeddingCalls, 1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"object": "list",
+			"data": []map[string]interface{}{
+				{"object": "embedding", "index": 0, "embedding": []float64{1, 0, 0}},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := core.NewClient(&core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite: &core.SQLiteConfig{
+				DBPath:             testDBPath,
+				CollectionName:     "memories",
+				EmbeddingModelDims: 3,
+			},
+		},
+		LLM: core.LLMConfig{Provider: "openai", APIKey: "test-key", Model: "gpt-3.5-turbo", BaseURL: server.URL},
+		Embedder: core.EmbedderConfig{
+			Provider: "openai", APIKey: "test-key", Model: "text-embedding-ada-002", BaseURL: server.URL, Dimensions: 3,
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	batchDone := make(chan *core.BatchAddResult, 1)
+	go func() {
+		result, _ := client.BatchAdd(ctx, []string{"a", "b", "c"},
+			core.WithUserID("user_001"),
+			core.WithBatchOptions(core.WithBatchConcurrency(1)),
+		)
+		batchDone <- result
+	}()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&embeddingCalls) == 1 }, time.Second, time.Millisecond)
+
+	// Shutdown stops accepting operations even when it times out
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, client.Shutdown(timeoutCtx), context.DeadlineExceeded)
+
+	shutdownDone := make(chan error, 1)
+	go func() { shutdownDone <- client.Shutdown(ctx) }()
+
+	// The batch started before Shutdown adds all of its items
+	close(release)
+	result := <-batchDone
+	require.NotNil(t, result)
+	assert.Equal(t, 3, result.CreatedCount)
+	assert.NoError(t, <-shutdownDone)
+}
+
+func TestAsyncClient_Shutdown(t *testing.T) {
+	testDBPath := "./test_shutdown_async.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	url, _ := newHyDEServer(t)
+	asyncClient, err := core.NewAsyncClient(&core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite: &core.SQLiteConfig{
+				DBPath:             testDBPath,
+				CollectionName:     "memories",
+				EmbeddingModelDims: 3,
+			},
+		},
+		LLM: core.LLMConfig{Provider: "openai", APIKey: "test-key", Model: "gpt-3.5-turbo", BaseURL: url},
+		Embedder: core.EmbedderConfig{
+			Provider: "openai", APIKey: "test-key", Model: "text-embedding-ada-002", BaseURL: url, Dimensions: 3,
+		},
+	}, core.WithAsyncWorkers(1))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	results := make([]<-chan *core.MemoryResult, 5)
+	for i := range results {
+		results[i] = asyncClient.AddAsync(ctx, "I like hiking", core.WithUserID("user_001"))
+	}
+
+	require.NoError(t, asyncClient.Shutdown(ctx))
+
+	// Queued operations were completed before the client was closed
+	for _, resultChan := range results {
+		result := <-resultChan
+		assert.NoError(t, result.Error)
+	}
+
+	result := <-asyncClient.AddAsync(ctx, "Busy weekend")
+	assert.ErrorIs(t, result.Error, core.ErrClientClosed)
+	_, err = asyncClient.Get(ctx, 1)
+	assert.ErrorIs(t, err, core.ErrClientClosed)
+}
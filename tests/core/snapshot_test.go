@@ -0,0 +1,68 @@
+package core_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_Snapshot(t *testing.T) {
+	dir := t.TempDir()
+	cfg := newTestConfig(filepath.Join(dir, "test_snapshot.db"))
+	cfg.Audit = &core.AuditConfig{Enabled: true}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	kept, err := client.Add(ctx, "User loves hiking", core.WithUserID("user_001"), core.WithInfer(false))
+	require.NoError(t, err)
+	deleted, err := client.Add(ctx, "User lives in Paris", core.WithUserID("user_001"), core.WithInfer(false))
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "memories.snapshot")
+	require.NoError(t, client.Snapshot(ctx, path))
+	assert.Error(t, client.Snapshot(ctx, path), "the snapshot file exists")
+
+	// Changes after the snapshot are undone by the restore
+	require.NoError(t, client.Delete(ctx, deleted.ID))
+	_, err = client.Update(ctx, kept.ID, "User loves climbing")
+	require.NoError(t, err)
+	_, err = client.Add(ctx, "User has a cat", core.WithUserID("user_002"), core.WithInfer(false))
+	require.NoError(t, err)
+
+	require.NoError(t, client.RestoreSnapshot(ctx, path))
+
+	memories, err := client.GetAll(ctx, core.WithAllowCrossUserForGetAll(true))
+	require.NoError(t, err)
+	require.Len(t, memories, 2)
+	restored, err := client.Get(ctx, kept.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "User loves hiking", restored.Content)
+	_, err = client.Get(ctx, deleted.ID)
+	require.NoError(t, err)
+
+	// The restored embeddings are searched
+	results, err := client.Search(ctx, "User lives in Paris", core.WithUserIDForSearch("user_001"), core.WithLimit(1))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, deleted.ID, results[0].ID)
+
+	// The restore is audited
+	entries, err := client.ListAuditEntries(ctx, core.WithAuditOperations("RestoreSnapshot"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, path, entries[0].Params["path"])
+
+	// Invalid paths
+	assert.ErrorIs(t, client.Snapshot(ctx, ""), core.ErrInvalidInput)
+	assert.ErrorIs(t, client.RestoreSnapshot(ctx, ""), core.ErrInvalidInput)
+	err = client.RestoreSnapshot(ctx, filepath.Join(dir, "missing.snapshot"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
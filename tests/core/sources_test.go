@@ -0,0 +1,91 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_AddWithSource(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_sources.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	source := core.Source{Document: "runbook.md"}
+	memory, err := client.Add(ctx, "Deploys run on Fridays", core.WithUserID("user_001"), core.WithSource(source))
+	require.NoError(t, err)
+	assert.Equal(t, []core.Source{source}, memory.Sources)
+
+	got, err := client.Get(ctx, memory.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []core.Source{source}, got.Sources)
+
+	results, err := client.Search(ctx, "When are deploys?", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, []core.Source{source}, results[0].Sources)
+
+	// Memories without sources
+	other, err := client.Add(ctx, "Prefers tea", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	got, err = client.Get(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.Sources)
+}
+
+func TestClient_IntelligentAddSources(t *testing.T) {
+	cfg := newChangesConfig(filepath.Join(t.TempDir(), "test_intelligent_sources.db"))
+	cfg.LLM.Parameters = map[string]interface{}{"responses": []string{
+		`{"facts": [{"fact": "Lives in Berlin", "confidence": 0.95, "messages": [1]}, {"fact": "Works as a nurse", "confidence": 0.9, "messages": [3]}]}`,
+		`{"memory": [{"id": "0", "text": "Lives in Berlin", "event": "ADD"}, {"id": "1", "text": "Works as a nurse", "event": "ADD"}]}`,
+		`{"facts": [{"fact": "Moved from Berlin to Lisbon", "confidence": 0.95, "messages": [1]}]}`,
+		`{"memory": [{"id": "0", "text": "Moved from Berlin to Lisbon", "event": "UPDATE", "old_memory": "Lives in Berlin"}]}`,
+	}}
+	cfg.Intelligence = &core.IntelligenceConfig{Enabled: true, DuplicateThreshold: 0.95}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	chat := core.Source{URL: "https://chat.example.com/c/42"}
+	result, err := client.IntelligentAdd(ctx, []map[string]interface{}{
+		{"id": "msg-1", "role": "user", "content": "I live in Berlin"},
+		{"id": "msg-2", "role": "assistant", "content": "Nice city!"},
+		{"id": "msg-3", "role": "user", "content": "I work as a nurse"},
+	}, core.WithUserID("user_001"), core.WithSource(chat))
+	require.NoError(t, err)
+	require.Len(t, result.Results, 2)
+
+	berlin, err := client.Get(ctx, result.Results[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, []core.Source{chat, {MessageID: "msg-1", Turn: 1}}, berlin.Sources)
+	nurse, err := client.Get(ctx, result.Results[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, []core.Source{chat, {MessageID: "msg-3", Turn: 3}}, nurse.Sources)
+
+	// An update keeps the sources of the memory and adds the new ones. The
+	// other memory is deleted so that the Berlin one is the only candidate.
+	require.NoError(t, client.Delete(ctx, nurse.ID))
+	_, err = client.IntelligentAdd(ctx, []map[string]interface{}{
+		{"message_id": 7, "role": "user", "content": "I moved to Lisbon"},
+	}, core.WithUserID("user_001"))
+	require.NoError(t, err)
+
+	results, err := client.Search(ctx, "Where does the user live?", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	var moved *core.Memory
+	for _, memory := range results {
+		if memory.ID == berlin.ID {
+			moved = memory
+		}
+	}
+	require.NotNil(t, moved)
+	assert.Equal(t, "Moved from Berlin to Lisbon", moved.Content)
+	assert.Equal(t, []core.Source{chat, {MessageID: "msg-1", Turn: 1}, {MessageID: "7", Turn: 1}}, moved.Sources)
+}
@@ -0,0 +1,117 @@
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestVectorStoreConfig_SQLiteLockingJSON(t *testing.T) {
+	var store core.VectorStoreConfig
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"provider": "sqlite",
+		"config": {"busy_timeout_seconds": 2.5, "busy_retries": 5, "synchronous": "NORMAL", "checkpoint_interval_seconds": 60}
+	}`), &store))
+	require.NotNil(t, store.SQLite)
+	assert.Equal(t, 2.5, store.SQLite.BusyTimeoutSeconds)
+	assert.Equal(t, 5, store.SQLite.BusyRetries)
+	assert.Equal(t, "NORMAL", store.SQLite.Synchronous)
+	assert.Equal(t, 60.0, store.SQLite.CheckpointIntervalSeconds)
+}
+
+func TestConfigValidate_SQLiteLocking(t *testing.T) {
+	config := &core.Config{
+		LLM:      core.LLMConfig{Provider: "mock"},
+		Embedder: core.EmbedderConfig{Provider: "mock"},
+		VectorStore: core.VectorStoreConfig{
+			SQLite: &core.SQLiteConfig{BusyTimeoutSeconds: -1, Synchronous: "sometimes"},
+		},
+	}
+	err := config.Validate()
+	var validationErr *core.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	fields := make(map[string]string)
+	for _, fieldErr := range validationErr.Fields {
+		fields[fieldErr.Field] = fieldErr.Message
+	}
+	assert.Equal(t, map[string]string{
+		"vector_store.config.busy_timeout_seconds": "must not be negative, got -1",
+		"vector_store.config.synchronous":          `unknown synchronous mode "sometimes" (want OFF, NORMAL, FULL or EXTRA)`,
+	}, fields)
+}
+
+func TestLoadConfigFromEnv_SQLiteLocking(t *testing.T) {
+	t.Setenv("DATABASE_PROVIDER", "sqlite")
+	t.Setenv("SQLITE_TIMEOUT", "30")
+	t.Setenv("SQLITE_BUSY_TIMEOUT_SECONDS", "")
+	t.Setenv("SQLITE_BUSY_RETRIES", "-1")
+	t.Setenv("SQLITE_SYNCHRONOUS", "normal")
+	t.Setenv("SQLITE_CHECKPOINT_INTERVAL_SECONDS", "15")
+
+	config, err := core.LoadConfigFromEnv()
+	require.NoError(t, err)
+	require.NotNil(t, config.VectorStore.SQLite)
+	assert.Equal(t, 30.0, config.VectorStore.SQLite.BusyTimeoutSeconds)
+	assert.Equal(t, -1, config.VectorStore.SQLite.BusyRetries)
+	assert.Equal(t, "normal", config.VectorStore.SQLite.Synchronous)
+	assert.Equal(t, 15.0, config.VectorStore.SQLite.CheckpointIntervalSeconds)
+}
+
+func TestConfigValidate_SQLiteLockingMode(t *testing.T) {
+	config := &core.Config{
+		LLM:         core.LLMConfig{Provider: "mock"},
+		Embedder:    core.EmbedderConfig{Provider: "mock"},
+		VectorStore: core.VectorStoreConfig{SQLite: &core.SQLiteConfig{Locking: "mutex"}},
+	}
+	err := config.Validate()
+	var validationErr *core.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Len(t, validationErr.Fields, 1)
+	assert.Equal(t, "vector_store.config.locking", validationErr.Fields[0].Field)
+	assert.Equal(t, `unknown locking mode "mutex" (want none, advisory or single_writer)`, validationErr.Fields[0].Message)
+}
+
+func TestLoadConfigFromEnv_SQLiteLockingMode(t *testing.T) {
+	t.Setenv("DATABASE_PROVIDER", "sqlite")
+	t.Setenv("SQLITE_LOCKING", "single_writer")
+
+	config, err := core.LoadConfigFromEnv()
+	require.NoError(t, err)
+	require.NotNil(t, config.VectorStore.SQLite)
+	assert.Equal(t, "single_writer", config.VectorStore.SQLite.Locking)
+}
+
+func TestLoadConfigFromEnv_SQLiteSearchWorkers(t *testing.T) {
+	t.Setenv("DATABASE_PROVIDER", "sqlite")
+	t.Setenv("SQLITE_SEARCH_WORKERS", "2")
+	t.Setenv("SQLITE_CLUSTERS", "256")
+	t.Setenv("SQLITE_PROBE_CLUSTERS", "16")
+	t.Setenv("SQLITE_VECTOR_CACHE", "true")
+
+	config, err := core.LoadConfigFromEnv()
+	require.NoError(t, err)
+	require.NotNil(t, config.VectorStore.SQLite)
+	assert.Equal(t, 2, config.VectorStore.SQLite.SearchWorkers)
+	assert.Equal(t, 256, config.VectorStore.SQLite.Clusters)
+	assert.Equal(t, 16, config.VectorStore.SQLite.ProbeClusters)
+	assert.True(t, config.VectorStore.SQLite.VectorCache)
+}
+
+func TestConfigValidate_SQLiteClusters(t *testing.T) {
+	config := &core.Config{
+		LLM:         core.LLMConfig{Provider: "mock"},
+		Embedder:    core.EmbedderConfig{Provider: "mock"},
+		VectorStore: core.VectorStoreConfig{SQLite: &core.SQLiteConfig{Clusters: -1}},
+	}
+	err := config.Validate()
+	var validationErr *core.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Len(t, validationErr.Fields, 1)
+	assert.Equal(t, "vector_store.config.clusters", validationErr.Fields[0].Field)
+	assert.Equal(t, "must not be negative, got -1", validationErr.Fields[0].Message)
+}
@@ -0,0 +1,71 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+)
+
+func TestClient_Staleness(t *testing.T) {
+	clock := intelligence.NewManualClock(time.Now())
+	config := newChangesConfig(filepath.Join(t.TempDir(), "test_staleness.db"))
+	config.Staleness = &core.StalenessConfig{Enabled: true, MaxAgeDays: 30, VerificationPrompt: true}
+	config.Clock = clock
+	client, err := core.NewClient(config)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	preference, err := client.Add(ctx, "Prefers window seats", core.WithUserID("user_001"),
+		core.WithMemoryType(core.MemoryTypePreference))
+	require.NoError(t, err)
+	_, err = client.Add(ctx, "Book window seats for the trip", core.WithUserID("user_001"),
+		core.WithMemoryType(core.MemoryTypeTask))
+	require.NoError(t, err)
+
+	search := func() map[string]*core.Memory {
+		results, err := client.Search(ctx, "window seats", core.WithUserIDForSearch("user_001"))
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		byType := make(map[string]*core.Memory)
+		for _, result := range results {
+			byType[result.MemoryType] = result
+		}
+		return byType
+	}
+
+	// Recent memories are not stale
+	assert.False(t, search()[core.MemoryTypePreference].Stale)
+
+	// Old preferences are, but not tasks
+	clock.Advance(45 * 24 * time.Hour)
+	results := search()
+	assert.True(t, results[core.MemoryTypePreference].Stale)
+	assert.Contains(t, results[core.MemoryTypePreference].VerificationPrompt, "45 days ago")
+	assert.Contains(t, results[core.MemoryTypePreference].VerificationPrompt, "Prefers window seats")
+	assert.False(t, results[core.MemoryTypeTask].Stale)
+
+	keyword, err := client.SearchByKeyword(ctx, "Prefers", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	require.Len(t, keyword, 1)
+	assert.True(t, keyword[0].Stale)
+
+	// Confirming the memory refreshes it
+	confirmed, err := client.ConfirmMemory(ctx, preference.ID, core.WithUserIDForUpdate("user_001"))
+	require.NoError(t, err)
+	assert.Equal(t, "Prefers window seats", confirmed.Content)
+	assert.Contains(t, confirmed.Metadata, "verified_at")
+	results = search()
+	assert.False(t, results[core.MemoryTypePreference].Stale)
+	assert.Empty(t, results[core.MemoryTypePreference].VerificationPrompt)
+
+	_, err = client.ConfirmMemory(ctx, preference.ID, core.WithUserIDForUpdate("user_002"))
+	assert.Error(t, err)
+}
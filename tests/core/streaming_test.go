@@ -2,6 +2,7 @@ package core_test
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
 
@@ -19,6 +20,17 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// useMockProviders replaces the LLM and embedder of cfg with the mock
+// providers, so that the tests run without API keys. Set POWERMEM_TEST_LIVE
+// to test against the providers configured in the environment instead.
+func useMockProviders(cfg *core.Config) {
+	if os.Getenv("POWERMEM_TEST_LIVE") != "" {
+		return
+	}
+	cfg.LLM = core.LLMConfig{Provider: "mock"}
+	cfg.Embedder = core.EmbedderConfig{Provider: "mock", Dimensions: cfg.Embedder.Dimensions}
+}
+
 func setupStreamingTest(t *testing.T) (*core.Client, func()) {
 	testDBPath := "./test_streaming.db"
 	_ = os.Remove(testDBPath)
@@ -28,10 +40,10 @@ func setupStreamingTest(t *testing.T) (*core.Client, func()) {
 		config = &core.Config{
 			VectorStore: core.VectorStoreConfig{
 				Provider: "sqlite",
-				Config: map[string]interface{}{
-					"db_path":              testDBPath,
-					"collection_name":      "memories",
-					"embedding_model_dims": 1536,
+				SQLite: &core.SQLiteConfig{
+					DBPath:             testDBPath,
+					CollectionName:     "memories",
+					EmbeddingModelDims: 1536,
 				},
 			},
 			LLM: core.LLMConfig{
@@ -48,13 +60,15 @@ func setupStreamingTest(t *testing.T) (*core.Client, func()) {
 		}
 	} else {
 		if config.VectorStore.Provider == "sqlite" {
-			if config.VectorStore.Config == nil {
-				config.VectorStore.Config = make(map[string]interface{})
+			if config.VectorStore.SQLite == nil {
+				config.VectorStore.SQLite = &core.SQLiteConfig{}
 			}
-			config.VectorStore.Config["db_path"] = testDBPath
+			config.VectorStore.SQLite.DBPath = testDBPath
 		}
 	}
 
+	useMockProviders(config)
+
 	client, err := core.NewClient(config)
 	require.NoError(t, err)
 	require.NotNil(t, client)
@@ -181,6 +195,56 @@ func TestGetAllStream(t *testing.T) {
 	assert.Greater(t, batchCount, 0)
 }
 
+func TestGetAllStream_Batches(t *testing.T) {
+	client, cleanup := setupStreamingTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	contents := make([]string, 250)
+	for i := range contents {
+		contents[i] = fmt.Sprintf("Streamed memory %d", i)
+	}
+	result, err := client.BatchAdd(ctx, contents, core.WithUserID("user_stream_batches"))
+	require.NoError(t, err)
+	require.Equal(t, len(contents), result.CreatedCount)
+
+	collect := func(batchSize int, opts ...core.GetAllOption) []int {
+		opts = append(opts, core.WithUserIDForGetAll("user_stream_batches"), core.WithLimitForGetAll(0))
+		var sizes []int
+		for result := range client.GetAllStream(ctx, batchSize, opts...) {
+			require.NoError(t, result.Error)
+			assert.Equal(t, len(sizes), result.BatchIndex)
+			sizes = append(sizes, len(result.Memories))
+			if result.IsLastBatch {
+				total := 0
+				for _, size := range sizes {
+					total += size
+				}
+				assert.Equal(t, len(contents), total)
+			}
+		}
+		return sizes
+	}
+
+	// Batches larger than a storage call, the last one flagged
+	assert.Equal(t, []int{150, 100}, collect(150))
+	assert.Equal(t, []int{125, 125}, collect(125))
+
+	// Batches capped by their estimated size: each memory holds a
+	// 1536-dimension embedding of 12 KiB
+	sizes := collect(200, core.WithMaxBatchBytesForGetAll(40<<10))
+	require.Len(t, sizes, 63)
+	for _, size := range sizes[:62] {
+		assert.Equal(t, 4, size)
+	}
+	assert.Equal(t, 2, sizes[62])
+
+	for result := range client.GetAllStream(ctx, 0) {
+		assert.ErrorIs(t, result.Error, core.ErrInvalidInput)
+	}
+}
+
 func TestBatchAdd(t *testing.T) {
 	client, cleanup := setupStreamingTest(t)
 	defer cleanup()
@@ -397,7 +461,7 @@ func TestSearchStream_ContextCancellation(t *testing.T) {
 	}
 
 	// Start streaming search
-	resultChan := client.SearchStream(ctx, "test", 2)
+	resultChan := client.SearchStream(ctx, "test", 2, core.WithUserIDForSearch("user_cancel_001"))
 
 	// Cancel context after receiving first batch
 	receivedFirst := false
@@ -428,7 +492,7 @@ func TestGetAllStream_ContextCancellation(t *testing.T) {
 	}
 
 	// Start streaming GetAll
-	resultChan := client.GetAllStream(ctx, 2)
+	resultChan := client.GetAllStream(ctx, 2, core.WithUserIDForGetAll("user_cancel_002"))
 
 	// Cancel context after receiving first batch
 	receivedFirst := false
@@ -444,3 +508,50 @@ func TestGetAllStream_ContextCancellation(t *testing.T) {
 		}
 	}
 }
+
+func TestSearchStream_Paged(t *testing.T) {
+	testDBPath := "./test_search_stream_paged.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	client := newOfflineTestClient(t, testDBPath, "")
+	ctx := context.Background()
+
+	added := make(map[int64]bool)
+	for i := 0; i < 5; i++ {
+		memory, err := client.Add(ctx, "I like hiking", core.WithUserID("user_stream_paged"))
+		require.NoError(t, err)
+		added[memory.ID] = true
+	}
+
+	var sizes []int
+	var last []bool
+	seen := make(map[int64]bool)
+	for result := range client.SearchStream(ctx, "hiking", 2, core.WithUserIDForSearch("user_stream_paged")) {
+		require.NoError(t, result.Error)
+		sizes = append(sizes, len(result.Memories))
+		last = append(last, result.IsLastBatch)
+		for _, memory := range result.Memories {
+			assert.False(t, seen[memory.ID], "memory %d streamed twice", memory.ID)
+			seen[memory.ID] = true
+		}
+	}
+
+	assert.Equal(t, []int{2, 2, 1}, sizes)
+	assert.Equal(t, []bool{false, false, true}, last)
+	assert.Equal(t, added, seen)
+
+	// Limit caps the total number of streamed memories
+	total := 0
+	for result := range client.SearchStream(ctx, "hiking", 2,
+		core.WithUserIDForSearch("user_stream_paged"),
+		core.WithLimit(4),
+	) {
+		require.NoError(t, result.Error)
+		total += len(result.Memories)
+		if total == 4 {
+			assert.True(t, result.IsLastBatch)
+		}
+	}
+	assert.Equal(t, 4, total)
+}
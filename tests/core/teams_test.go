@@ -0,0 +1,77 @@
+package core_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_Teams(t *testing.T) {
+	cfg := newTestConfig(filepath.Join(t.TempDir(), "test_teams.db"))
+	cfg.Audit = &core.AuditConfig{Enabled: true}
+	client, err := core.NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	require.NoError(t, client.AddTeamMember(ctx, "team_support", "user_001"))
+	require.NoError(t, client.AddTeamMember(ctx, "team_support", "user_002"))
+	require.NoError(t, client.AddTeamMember(ctx, "team_support", "user_002"), "adding a member again is not an error")
+	require.NoError(t, client.AddTeamMember(ctx, "team_sales", "user_002"))
+
+	members, err := client.ListTeamMembers(ctx, "team_support")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user_001", "user_002"}, members)
+	teams, err := client.ListUserTeams(ctx, "user_002")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team_sales", "team_support"}, teams)
+
+	shared, err := client.Add(ctx, "Refunds above 500 dollars need a manager",
+		core.WithUserID("user_001"), core.WithTeamID("team_support"), core.WithInfer(false))
+	require.NoError(t, err)
+	assert.Equal(t, "team", shared.Metadata["scope"])
+	assert.Equal(t, "team_support", shared.Metadata["team_id"])
+	_, err = client.Add(ctx, "User_001 prefers tea", core.WithUserID("user_001"), core.WithInfer(false))
+	require.NoError(t, err)
+
+	// Members see the team's memories, not the private ones of the others
+	results, err := client.Search(ctx, "Refunds above 500 dollars need a manager", core.WithUserIDForSearch("user_002"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, shared.ID, results[0].ID)
+	memories, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_002"))
+	require.NoError(t, err)
+	require.Len(t, memories, 1)
+	keyword, err := client.SearchByKeyword(ctx, "Refunds", core.WithUserIDForSearch("user_002"))
+	require.NoError(t, err)
+	assert.Len(t, keyword, 1)
+
+	// Outsiders do not
+	results, err = client.Search(ctx, "Refunds above 500 dollars need a manager", core.WithUserIDForSearch("user_003"))
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	// Only the author updates the memory
+	_, err = client.Update(ctx, shared.ID, "Refunds need a manager", core.WithUserIDForUpdate("user_002"))
+	assert.Error(t, err)
+
+	// Removed members lose access
+	require.NoError(t, client.RemoveTeamMember(ctx, "team_support", "user_002"))
+	memories, err = client.GetAll(ctx, core.WithUserIDForGetAll("user_002"))
+	require.NoError(t, err)
+	assert.Empty(t, memories)
+
+	entries, err := client.ListAuditEntries(ctx, core.WithAuditOperations("AddTeamMember", "RemoveTeamMember"))
+	require.NoError(t, err)
+	require.Len(t, entries, 5)
+	assert.Equal(t, "RemoveTeamMember", entries[4].Operation)
+	assert.Equal(t, "user_002", entries[4].UserID)
+
+	err = client.AddTeamMember(ctx, "", "user_001")
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+}
@@ -0,0 +1,73 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestClient_Templates(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_templates.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	preference, err := client.RememberPreference(ctx, "seating", "Prefers window seats",
+		core.WithUserID("user_001"),
+		core.WithMetadata(map[string]interface{}{"channel": "chat"}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "Prefers window seats", preference.Content)
+	assert.Equal(t, core.MemoryTypePreference, preference.Metadata["memory_type"])
+	assert.Equal(t, "seating", preference.Metadata["topic"])
+	assert.Equal(t, "chat", preference.Metadata["channel"])
+
+	fact, err := client.RememberFact(ctx, "Is allergic to peanuts", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.Equal(t, core.MemoryTypeFact, fact.Metadata["memory_type"])
+
+	due := time.Date(2025, 6, 1, 9, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	task, err := client.RememberTask(ctx, "Renew the passport", due, core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.Equal(t, core.MemoryTypeTask, task.Metadata["memory_type"])
+	assert.Equal(t, core.TaskStatusOpen, task.Metadata["status"])
+	assert.Equal(t, "2025-06-01T07:00:00Z", task.Metadata["due_date"])
+
+	undated, err := client.RememberTask(ctx, "Call the bank", time.Time{}, core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.NotContains(t, undated.Metadata, "due_date")
+
+	relationship, err := client.RememberRelationship(ctx, "Anna", "is the sister of", "Tom", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.Equal(t, "Anna is the sister of Tom", relationship.Content)
+	assert.Equal(t, core.MemoryTypeRelationship, relationship.Metadata["memory_type"])
+	assert.Equal(t, "Anna", relationship.Metadata["subject"])
+	assert.Equal(t, "is the sister of", relationship.Metadata["relation"])
+	assert.Equal(t, "Tom", relationship.Metadata["object"])
+
+	// The structured metadata is stored and can be filtered on
+	tasks, err := client.GetAll(ctx,
+		core.WithUserIDForGetAll("user_001"),
+		core.WithFiltersForGetAll(map[string]interface{}{"memory_type": core.MemoryTypeTask}),
+	)
+	require.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	anna, err := client.GetAll(ctx, core.WithAllowCrossUserForGetAll(true),
+		core.WithFiltersForGetAll(map[string]interface{}{"subject": "Anna"}))
+	require.NoError(t, err)
+	require.Len(t, anna, 1)
+	assert.Equal(t, relationship.ID, anna[0].ID)
+
+	_, err = client.RememberRelationship(ctx, "Anna", " ", "Tom")
+	assert.True(t, errors.Is(err, core.ErrInvalidInput))
+	_, err = client.RememberPreference(ctx, "", "Prefers tea")
+	assert.True(t, errors.Is(err, core.ErrInvalidInput))
+}
@@ -0,0 +1,77 @@
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestVectorStoreConfig_VectorIndexJSON(t *testing.T) {
+	var store core.VectorStoreConfig
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"provider": "oceanbase",
+		"config": {
+			"disable_vector_index": true,
+			"hnsw_m": 32,
+			"hnsw_ef_construction": 400,
+			"hnsw_ef_search": 128
+		}
+	}`), &store))
+	require.NotNil(t, store.OceanBase)
+	assert.True(t, store.OceanBase.DisableVectorIndex)
+	assert.Equal(t, 32, store.OceanBase.HNSWM)
+	assert.Equal(t, 400, store.OceanBase.HNSWEfConstruction)
+	assert.Equal(t, 128, store.OceanBase.HNSWEfSearch)
+
+	// Values of the wrong type name the field
+	err := json.Unmarshal([]byte(`{"provider": "oceanbase", "config": {"disable_vector_index": "yes"}}`), &store)
+	var validationErr *core.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Len(t, validationErr.Fields, 1)
+	assert.Equal(t, "vector_store.config.disable_vector_index", validationErr.Fields[0].Field)
+	assert.Equal(t, "must be a boolean, got string", validationErr.Fields[0].Message)
+}
+
+func TestConfigValidate_VectorIndex(t *testing.T) {
+	config := &core.Config{
+		LLM:      core.LLMConfig{Provider: "mock"},
+		Embedder: core.EmbedderConfig{Provider: "mock"},
+		VectorStore: core.VectorStoreConfig{
+			OceanBase: &core.OceanBaseConfig{
+				HNSWM:        -1,
+				HNSWEfSearch: -1,
+			},
+		},
+	}
+	err := config.Validate()
+	var validationErr *core.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	fields := make(map[string]string)
+	for _, fieldErr := range validationErr.Fields {
+		fields[fieldErr.Field] = fieldErr.Message
+	}
+	assert.Contains(t, fields, "vector_store.config.hnsw_m")
+	assert.Equal(t, "must not be negative, got -1", fields["vector_store.config.hnsw_ef_search"])
+	assert.NotContains(t, fields, "vector_store.config.hnsw_ef_construction")
+}
+
+func TestLoadConfigFromEnv_VectorIndex(t *testing.T) {
+	t.Setenv("DATABASE_PROVIDER", "oceanbase")
+	t.Setenv("OCEANBASE_DISABLE_VECTOR_INDEX", "true")
+	t.Setenv("OCEANBASE_HNSW_M", "24")
+	t.Setenv("OCEANBASE_HNSW_EF_CONSTRUCTION", "300")
+	t.Setenv("OCEANBASE_HNSW_EF_SEARCH", "100")
+
+	config, err := core.LoadConfigFromEnv()
+	require.NoError(t, err)
+	require.NotNil(t, config.VectorStore.OceanBase)
+	assert.True(t, config.VectorStore.OceanBase.DisableVectorIndex)
+	assert.Equal(t, 24, config.VectorStore.OceanBase.HNSWM)
+	assert.Equal(t, 300, config.VectorStore.OceanBase.HNSWEfConstruction)
+	assert.Equal(t, 100, config.VectorStore.OceanBase.HNSWEfSearch)
+}
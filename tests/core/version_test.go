@@ -0,0 +1,140 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	sqliteStore "github.com/oceanbase/powermem-go/pkg/storage/sqlite"
+)
+
+func TestClient_UpdateWithExpectedVersion(t *testing.T) {
+	testDBPath := "./test_version.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	client := newOfflineTestClient(t, testDBPath, "")
+	ctx := context.Background()
+
+	memory, err := client.Add(ctx, "I like hiking", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), memory.Version)
+
+	// Two writers read version 1; the first one wins
+	updated, err := client.Update(ctx, memory.ID, "I like hiking in the Alps", core.WithExpectedVersion(memory.Version))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated.Version)
+
+	_, err = client.Update(ctx, memory.ID, "I like hiking on weekends", core.WithExpectedVersion(memory.Version))
+	assert.ErrorIs(t, err, core.ErrVersionConflict)
+
+	got, err := client.Get(ctx, memory.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "I like hiking in the Alps", got.Content)
+	assert.Equal(t, int64(2), got.Version)
+
+	// Updates without an expected version are unconditional
+	updated, err = client.Update(ctx, memory.ID, "I like hiking on weekends")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), updated.Version)
+}
+
+func TestIntelligentAdd_SkipsStaleUpdate(t *testing.T) {
+	testDBPath := "./test_stale_update.db"
+	_ = os.Remove(testDBPath)
+	t.Cleanup(func() { _ = os.Remove(testDBPath) })
+
+	// A second store on the same database plays the other service instance
+	other, err := sqliteStore.NewClient(&sqliteStore.Config{
+		DBPath:             testDBPath,
+		CollectionName:     "memories",
+		EmbeddingModelDims: 3,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = other.Close() })
+
+	ctx := context.Background()
+	require.NoError(t, other.Insert(ctx, &storage.Memory{
+		ID: 1, UserID: "user_001", Content: "Likes hiking", Embedding: []float64{1, 0, 0},
+	}))
+
+	responses := []string{
+		`{"facts": [{"fact": "Loves hiking in the Alps", "confidence": 0.9}]}`,
+		`{"memory": [{"id": "0", "text": "Loves hiking in the Alps", "event": "UPDATE", "old_memory": "Likes hiking"}]}`,
+	}
+	var chatCalls, embedCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/chat/completions"):
+			call := atomic.AddInt32(&chatCalls, 1)
+			index := int(call) - 1
+			if index >= len(responses) {
+				index = len(responses) - 1
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "chat.completion",
+				"choices": []map[string]interface{}{
+					{"index": 0, "finish_reason": "stop", "message": map[string]interface{}{"role": "assistant", "content": responses[index]}},
+				},
+			})
+		case strings.HasSuffix(r.URL.Path, "/embeddings"):
+			// The UPDATE is embedded after the decision was made: change the
+			// memory in between, as a concurrent writer would
+			if atomic.AddInt32(&embedCalls, 1) == 2 {
+				_, err := other.Update(ctx, 1, "Likes hiking and climbing", []float64{1, 0, 0}, nil)
+				assert.NoError(t, err)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "list",
+				"data": []map[string]interface{}{
+					{"object": "embedding", "index": 0, "embedding": []float64{1, 0, 0}},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := core.NewClient(&core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite: &core.SQLiteConfig{
+				DBPath:             testDBPath,
+				CollectionName:     "memories",
+				EmbeddingModelDims: 3,
+			},
+		},
+		LLM: core.LLMConfig{Provider: "openai", APIKey: "test-key", Model: "gpt-3.5-turbo", BaseURL: server.URL},
+		Embedder: core.EmbedderConfig{
+			Provider: "openai", APIKey: "test-key", Model: "text-embedding-ada-002", BaseURL: server.URL, Dimensions: 3,
+		},
+		Intelligence: &core.IntelligenceConfig{Enabled: true, DuplicateThreshold: 0.95},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	result, err := client.IntelligentAdd(ctx, "I love hiking in the Alps", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.Empty(t, result.Results)
+	require.Len(t, result.Conflicts, 1)
+	assert.Equal(t, int64(1), result.Conflicts[0].ID)
+	assert.Equal(t, "Loves hiking in the Alps", result.Conflicts[0].Memory)
+
+	// The concurrent write is preserved
+	got, err := client.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Likes hiking and climbing", got.Content)
+	assert.Equal(t, int64(2), got.Version)
+}
@@ -0,0 +1,115 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+)
+
+func TestWorkingMemory(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_working_memory.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	scratch := client.WorkingMemory("run_001", core.WithWorkingMemoryUserID("user_001"))
+	assert.Equal(t, "run_001", scratch.RunID())
+
+	budget, err := scratch.Add(ctx, "The user wants flights under 300 EUR")
+	require.NoError(t, err)
+	require.NotNil(t, budget.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(core.DefaultWorkingMemoryTTL), *budget.ExpiresAt, time.Minute)
+	assert.Equal(t, "user_001", budget.UserID)
+	assert.Equal(t, "run_001", budget.Metadata["run_id"])
+	assert.Equal(t, "working", budget.Metadata["memory_type"])
+
+	seat, err := scratch.Add(ctx, "Prefers aisle seats", core.WithTags("travel"))
+	require.NoError(t, err)
+	_, err = scratch.Add(ctx, "Draft itinerary: Lisbon, then Porto")
+	require.NoError(t, err)
+	longTerm, err := client.Add(ctx, "Flights under 300 EUR are a good deal", core.WithUserID("user_001"))
+	require.NoError(t, err)
+
+	// Other runs have their own scratchpad
+	other := client.WorkingMemory("run_002", core.WithWorkingMemoryUserID("user_001"))
+	_, err = other.Add(ctx, "The user wants flights under 500 EUR")
+	require.NoError(t, err)
+
+	results, err := scratch.Search(ctx, "flights under 300 EUR")
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for _, result := range results {
+		assert.Equal(t, "run_001", result.Metadata["run_id"])
+	}
+
+	// Searches leave the items out
+	results, err = client.Search(ctx, "flights under 300 EUR", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, longTerm.ID, results[0].ID)
+	results, err = client.Search(ctx, "flights under 300 EUR", core.WithUserIDForSearch("user_001"), core.WithIncludeWorking(true))
+	require.NoError(t, err)
+	assert.Len(t, results, 5)
+
+	items, err := scratch.Items(ctx)
+	require.NoError(t, err)
+	assert.Len(t, items, 3)
+
+	// Session end: keep two items, discard the draft
+	result, err := client.Promote(ctx, "run_001", core.WithUserIDForPromote("user_001"), core.WithPromoteIDs(budget.ID, seat.ID))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Discarded)
+	require.Len(t, result.Promoted, 2)
+	for _, memory := range result.Promoted {
+		assert.Nil(t, memory.ExpiresAt)
+		assert.Equal(t, "long_term", memory.Metadata["memory_type"])
+		assert.Equal(t, "run_001", memory.Metadata["run_id"])
+	}
+
+	got, err := client.Get(ctx, seat.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.ExpiresAt)
+	assert.Equal(t, []string{"travel"}, got.Tags)
+
+	items, err = scratch.Items(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+	results, err = client.Search(ctx, "aisle seats", core.WithUserIDForSearch("user_001"))
+	require.NoError(t, err)
+	assert.Len(t, results, 3)
+
+	// Discarding a scratchpad
+	require.NoError(t, other.Clear(ctx))
+	items, err = other.Items(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	_, err = client.Promote(ctx, "")
+	assert.True(t, errors.Is(err, core.ErrInvalidInput))
+}
+
+func TestWorkingMemory_Expires(t *testing.T) {
+	client, err := core.NewClient(newChangesConfig(filepath.Join(t.TempDir(), "test_working_memory_ttl.db")))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+
+	scratch := client.WorkingMemory("run_001", core.WithWorkingMemoryTTL(50*time.Millisecond))
+	_, err = scratch.Add(ctx, "Temporary note")
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	items, err := scratch.Items(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+	result, err := client.Promote(ctx, "run_001")
+	require.NoError(t, err)
+	assert.Empty(t, result.Promoted)
+}
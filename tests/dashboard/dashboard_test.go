@@ -0,0 +1,543 @@
+package dashboard_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/dashboard"
+	usermemory "github.com/oceanbase/powermem-go/pkg/user_memory"
+)
+
+// fakeProfiles is a ProfileSource serving fixed profiles.
+type fakeProfiles struct {
+	profiles []*usermemory.UserProfile
+}
+
+func (f *fakeProfiles) GetProfile(ctx context.Context, userID string) (*usermemory.UserProfile, error) {
+	for _, profile := range f.profiles {
+		if profile.UserID == userID {
+			return profile, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeProfiles) GetProfiles(ctx context.Context, opts *usermemory.GetProfilesOptions) ([]*usermemory.UserProfile, error) {
+	return f.profiles, nil
+}
+
+// newDashboard seeds a store and serves the dashboard under /dashboard/,
+// requiring apiKeys if any.
+func newDashboard(t *testing.T, apiKeys ...dashboard.APIKey) (*httptest.Server, []*core.Memory) {
+	t.Helper()
+	return newAuthDashboard(t, apiKeys, nil)
+}
+
+// newAuthDashboard is newDashboard also accepting the tokens of jwt if it
+// is not nil.
+func newAuthDashboard(t *testing.T, apiKeys []dashboard.APIKey, jwt *dashboard.JWTConfig) (*httptest.Server, []*core.Memory) {
+	t.Helper()
+	client, err := core.NewClient(&core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite:   &core.SQLiteConfig{DBPath: filepath.Join(t.TempDir(), "test_dashboard.db"), EmbeddingModelDims: 64},
+		},
+		LLM:      core.LLMConfig{Provider: "mock"},
+		Embedder: core.EmbedderConfig{Provider: "mock", Dimensions: 64},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx := context.Background()
+	var memories []*core.Memory
+	for _, content := range []string{
+		"User loves hiking in the mountains",
+		"User loves hiking in the mountains!",
+		"User prefers <b>tea</b> over coffee",
+	} {
+		memory, err := client.Add(ctx, content, core.WithUserID("user_001"), core.WithTags("profile"))
+		require.NoError(t, err)
+		memories = append(memories, memory)
+	}
+	memory, err := client.Add(ctx, "User works in finance", core.WithUserID("user_002"))
+	require.NoError(t, err)
+	memories = append(memories, memory)
+
+	handler, err := dashboard.NewHandler(&dashboard.Config{
+		Memory: client,
+		Profiles: &fakeProfiles{profiles: []*usermemory.UserProfile{
+			{UserID: "user_001", ProfileContent: "Outdoor enthusiast", Topics: map[string]interface{}{"hobby": "hiking"}},
+		}},
+		DuplicateThreshold: 0.9,
+		APIKeys:            apiKeys,
+		JWT:                jwt,
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.Handle("/dashboard/", http.StripPrefix("/dashboard", handler))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, memories
+}
+
+// get fetches path and returns the status code and body.
+func get(t *testing.T, server *httptest.Server, path string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(server.URL + path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestDashboard_Pages(t *testing.T) {
+	server, memories := newDashboard(t)
+
+	status, body := get(t, server, "/dashboard/")
+	require.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "4 memories, 2 users.")
+	assert.Contains(t, body, `href="user?id=user_001"`)
+	assert.Contains(t, body, `href="profiles"`)
+
+	status, body = get(t, server, "/dashboard/user?id=user_001")
+	require.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "Outdoor enthusiast")
+	assert.Contains(t, body, "User prefers &lt;b&gt;tea&lt;/b&gt; over coffee", "content is escaped")
+	assert.NotContains(t, body, "User works in finance")
+
+	status, body = get(t, server, "/dashboard/user?id=user_001&q=tea")
+	require.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "Results for")
+	assert.Contains(t, body, "<th>Score</th>")
+
+	id := strconv.FormatInt(memories[0].ID, 10)
+	status, body = get(t, server, "/dashboard/memory?id="+id)
+	require.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "User loves hiking in the mountains")
+	assert.Contains(t, body, "<polyline points=")
+
+	status, body = get(t, server, "/dashboard/duplicates?user=user_001")
+	require.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "1 clusters.")
+
+	status, body = get(t, server, "/dashboard/profiles")
+	require.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "Outdoor enthusiast")
+
+	// Without authentication, nobody is an administrator
+	status, _ = get(t, server, "/dashboard/audit")
+	assert.Equal(t, http.StatusForbidden, status)
+	_, body = get(t, server, "/dashboard/")
+	assert.NotContains(t, body, `href="audit"`)
+}
+
+func TestDashboard_JSON(t *testing.T) {
+	server, memories := newDashboard(t)
+
+	var overview struct {
+		Total int                      `json:"total"`
+		Users []*dashboard.UserSummary `json:"users"`
+	}
+	status, body := get(t, server, "/dashboard/?format=json")
+	require.Equal(t, http.StatusOK, status)
+	require.NoError(t, json.Unmarshal([]byte(body), &overview))
+	assert.Equal(t, 4, overview.Total)
+	require.Len(t, overview.Users, 2)
+	assert.Equal(t, "user_001", overview.Users[0].UserID)
+	assert.Equal(t, 3, overview.Users[0].Memories)
+
+	var user struct {
+		Memories []*core.Memory          `json:"memories"`
+		Profile  *usermemory.UserProfile `json:"profile"`
+		Tags     []string                `json:"tags"`
+	}
+	_, body = get(t, server, "/dashboard/user?id=user_002&format=json")
+	require.NoError(t, json.Unmarshal([]byte(body), &user))
+	require.Len(t, user.Memories, 1)
+	assert.Equal(t, "User works in finance", user.Memories[0].Content)
+	assert.Nil(t, user.Profile)
+
+	var memory struct {
+		Memory    *core.Memory              `json:"memory"`
+		Retention *dashboard.RetentionCurve `json:"retention"`
+	}
+	_, body = get(t, server, "/dashboard/memory?format=json&id="+strconv.FormatInt(memories[3].ID, 10))
+	require.NoError(t, json.Unmarshal([]byte(body), &memory))
+	assert.Equal(t, memories[3].ID, memory.Memory.ID)
+	require.NotNil(t, memory.Retention)
+	assert.Equal(t, 0.1, memory.Retention.DecayRate)
+
+	var duplicates struct {
+		Clusters []*core.DuplicateCluster `json:"clusters"`
+	}
+	_, body = get(t, server, "/dashboard/duplicates?user=user_001&format=json")
+	require.NoError(t, json.Unmarshal([]byte(body), &duplicates))
+	require.Len(t, duplicates.Clusters, 1)
+	assert.Len(t, duplicates.Clusters[0].Memories, 2)
+}
+
+func TestDashboard_Errors(t *testing.T) {
+	server, _ := newDashboard(t)
+
+	status, _ := get(t, server, "/dashboard/memory?id=12345")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _ = get(t, server, "/dashboard/unknown")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _ = get(t, server, "/dashboard/duplicates?threshold=2")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	resp, err := http.Post(server.URL+"/dashboard/", "text/plain", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	_, err = dashboard.NewHandler(&dashboard.Config{})
+	assert.Error(t, err)
+}
+
+// getWithKey fetches path with an API key and returns the status code and
+// body.
+func getWithKey(t *testing.T, server *httptest.Server, path, key string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+key)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestDashboard_APIKeys(t *testing.T) {
+	server, memories := newDashboard(t,
+		dashboard.APIKey{Key: "admin-key", Role: dashboard.RoleAdmin},
+		dashboard.APIKey{Key: "user-key", Role: dashboard.RoleReadOnly, UserIDs: []string{"user_002"}},
+		dashboard.APIKey{Key: "agent-key", Role: dashboard.RoleReadOnly, UserIDs: []string{"user_001"}, AgentID: "agent_x"},
+	)
+
+	// Requests without a valid key are rejected
+	status, _ := get(t, server, "/dashboard/")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = getWithKey(t, server, "/dashboard/", "wrong-key")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	// Unscoped keys see everything; basic authentication is accepted
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/dashboard/", nil)
+	require.NoError(t, err)
+	req.SetBasicAuth("", "admin-key")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	status, body := getWithKey(t, server, "/dashboard/profiles", "admin-key")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "Outdoor enthusiast")
+
+	// Keys scoped to users only see their memories
+	status, body = getWithKey(t, server, "/dashboard/", "user-key")
+	require.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "1 memories, 1 users.")
+	assert.NotContains(t, body, "user_001")
+	assert.NotContains(t, body, `href="profiles"`)
+
+	status, body = getWithKey(t, server, "/dashboard/user?id=user_002", "user-key")
+	require.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "User works in finance")
+
+	for _, path := range []string{
+		"/dashboard/user?id=user_001",
+		"/dashboard/duplicates?user=user_001",
+		"/dashboard/duplicates",
+		"/dashboard/profiles",
+	} {
+		status, _ = getWithKey(t, server, path, "user-key")
+		assert.Equal(t, http.StatusForbidden, status, path)
+	}
+	status, _ = getWithKey(t, server, "/dashboard/memory?id="+strconv.FormatInt(memories[0].ID, 10), "user-key")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = getWithKey(t, server, "/dashboard/memory?id="+strconv.FormatInt(memories[3].ID, 10), "user-key")
+	assert.Equal(t, http.StatusOK, status)
+
+	// Keys scoped to an agent only see the memories of the agent, without
+	// the tags and profile of the user
+	var user struct {
+		Memories []*core.Memory          `json:"memories"`
+		Profile  *usermemory.UserProfile `json:"profile"`
+		Tags     []string                `json:"tags"`
+	}
+	status, body = getWithKey(t, server, "/dashboard/user?id=user_001&format=json", "agent-key")
+	require.Equal(t, http.StatusOK, status)
+	require.NoError(t, json.Unmarshal([]byte(body), &user))
+	assert.Empty(t, user.Memories)
+	assert.Empty(t, user.Tags)
+	assert.Nil(t, user.Profile)
+	status, _ = getWithKey(t, server, "/dashboard/memory?id="+strconv.FormatInt(memories[0].ID, 10), "agent-key")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	// The audit log is reserved to unscoped admin keys
+	status, body = getWithKey(t, server, "/dashboard/audit", "admin-key")
+	require.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "No entries.")
+	for _, key := range []string{"user-key", "agent-key"} {
+		status, _ = getWithKey(t, server, "/dashboard/audit", key)
+		assert.Equal(t, http.StatusForbidden, status, key)
+	}
+
+	// Invalid keys are rejected
+	for _, keys := range [][]dashboard.APIKey{
+		{{Key: "", Role: dashboard.RoleAdmin}},
+		{{Key: "key", Role: "owner"}},
+		{{Key: "key", Role: dashboard.RoleAdmin}, {Key: "key", Role: dashboard.RoleReadOnly}},
+	} {
+		_, err := dashboard.NewHandler(&dashboard.Config{Memory: &core.Client{}, APIKeys: keys})
+		assert.Error(t, err)
+	}
+}
+
+func TestAPIKey_Allows(t *testing.T) {
+	reader := &dashboard.APIKey{Role: dashboard.RoleReadOnly}
+	assert.True(t, reader.Allows(dashboard.RoleReadOnly))
+	assert.False(t, reader.Allows(dashboard.RoleWriter))
+	assert.False(t, reader.Allows(dashboard.RoleAdmin))
+
+	writer := &dashboard.APIKey{Role: dashboard.RoleWriter}
+	assert.True(t, writer.Allows(dashboard.RoleReadOnly))
+	assert.True(t, writer.Allows(dashboard.RoleWriter))
+	assert.False(t, writer.Allows(dashboard.RoleAdmin))
+
+	admin := &dashboard.APIKey{Role: dashboard.RoleAdmin}
+	assert.True(t, admin.Allows(dashboard.RoleReadOnly))
+	assert.True(t, admin.Allows(dashboard.RoleWriter))
+	assert.True(t, admin.Allows(dashboard.RoleAdmin))
+	assert.False(t, admin.Allows("owner"))
+
+	scoped := &dashboard.APIKey{Role: dashboard.RoleReadOnly, UserIDs: []string{"u1"}, AgentID: "a1"}
+	assert.True(t, scoped.CanAccess("u1", "a1"))
+	assert.False(t, scoped.CanAccess("u1", "a2"))
+	assert.False(t, scoped.CanAccess("u2", "a1"))
+}
+
+// send makes a request with a JSON body, if body is not empty, and an API
+// key, and returns the status code and body of the response.
+func send(t *testing.T, server *httptest.Server, method, path, key, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(data)
+}
+
+func TestDashboard_Writes(t *testing.T) {
+	server, memories := newDashboard(t,
+		dashboard.APIKey{Key: "admin-key", Role: dashboard.RoleAdmin},
+		dashboard.APIKey{Key: "reader-key", Role: dashboard.RoleReadOnly},
+		dashboard.APIKey{Key: "agent-key", Role: dashboard.RoleWriter, UserIDs: []string{"user_001"}, AgentID: "agent_x"},
+	)
+
+	// Writers add memories in their scope, for their agent by default
+	status, body := send(t, server, http.MethodPost, "/dashboard/memories", "agent-key",
+		`{"content": "User likes green tea", "user_id": "user_001", "tags": ["drinks"]}`)
+	require.Equal(t, http.StatusCreated, status, body)
+	var added core.Memory
+	require.NoError(t, json.Unmarshal([]byte(body), &added))
+	assert.Equal(t, "user_001", added.UserID)
+	assert.Equal(t, "agent_x", added.AgentID)
+	assert.Equal(t, []string{"drinks"}, added.Tags)
+
+	for _, body := range []string{
+		`{"content": "User likes coffee", "user_id": "user_002"}`,
+		`{"content": "User likes coffee", "user_id": "user_001", "agent_id": "agent_y"}`,
+	} {
+		status, _ = send(t, server, http.MethodPost, "/dashboard/memories", "agent-key", body)
+		assert.Equal(t, http.StatusForbidden, status, body)
+	}
+
+	// They update and delete their memories; others are not found
+	id := strconv.FormatInt(added.ID, 10)
+	status, body = send(t, server, http.MethodPut, "/dashboard/memory?id="+id, "agent-key",
+		`{"content": "User likes jasmine tea", "metadata": {"source": "support"}}`)
+	require.Equal(t, http.StatusOK, status, body)
+	var updated core.Memory
+	require.NoError(t, json.Unmarshal([]byte(body), &updated))
+	assert.Equal(t, "User likes jasmine tea", updated.Content)
+	assert.Equal(t, "support", updated.Metadata["source"])
+
+	other := "/dashboard/memory?id=" + strconv.FormatInt(memories[0].ID, 10)
+	status, _ = send(t, server, http.MethodPut, other, "agent-key", `{"content": "User hates hiking"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = send(t, server, http.MethodDelete, other, "agent-key", "")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _ = send(t, server, http.MethodDelete, "/dashboard/memory?id="+id, "agent-key", "")
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = getWithKey(t, server, "/dashboard/memory?id="+id, "admin-key")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	// Readers cannot write, and unscoped admins write every memory
+	status, _ = send(t, server, http.MethodDelete, other, "reader-key", "")
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = send(t, server, http.MethodPost, "/dashboard/memories", "reader-key", `{"content": "x", "user_id": "user_001"}`)
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = send(t, server, http.MethodDelete, other, "admin-key", "")
+	assert.Equal(t, http.StatusNoContent, status)
+
+	// Bodies must be JSON with content
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/dashboard/memories", strings.NewReader(`{"content": "x"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Authorization", "Bearer admin-key")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+	for _, body := range []string{`{"content": " "}`, `{"content": "x", "owner": "me"}`, `[`} {
+		status, _ = send(t, server, http.MethodPost, "/dashboard/memories", "admin-key", body)
+		assert.Equal(t, http.StatusBadRequest, status, body)
+	}
+	status, _ = send(t, server, http.MethodPut, "/dashboard/", "admin-key", `{"content": "x"}`)
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+}
+
+func TestDashboard_WritesNeedAuthentication(t *testing.T) {
+	server, memories := newDashboard(t)
+	status, _ := send(t, server, http.MethodPost, "/dashboard/memories", "", `{"content": "x", "user_id": "user_001"}`)
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = send(t, server, http.MethodDelete, "/dashboard/memory?id="+strconv.FormatInt(memories[0].ID, 10), "", "")
+	assert.Equal(t, http.StatusForbidden, status)
+}
+
+// signJWT returns a token of claims signed with secret, with the algorithm
+// alg.
+func signJWT(t *testing.T, secret []byte, alg string, claims map[string]interface{}) string {
+	t.Helper()
+	header, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestDashboard_JWT(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	server, memories := newAuthDashboard(t,
+		[]dashboard.APIKey{{Key: "admin-key", Role: dashboard.RoleAdmin}},
+		&dashboard.JWTConfig{Secret: secret, Issuer: "auth.example.com", Audience: "powermem"})
+	exp := time.Now().Add(time.Hour).Unix()
+	claims := func(changes map[string]interface{}) map[string]interface{} {
+		claims := map[string]interface{}{
+			"sub": "travel agent", "iss": "auth.example.com", "aud": []string{"other", "powermem"}, "exp": exp,
+			"role": "writer", "user_ids": []string{"user_002"},
+		}
+		for name, value := range changes {
+			if value == nil {
+				delete(claims, name)
+			} else {
+				claims[name] = value
+			}
+		}
+		return claims
+	}
+
+	// Tokens stand for keys, with their role and scope
+	token := signJWT(t, secret, "HS256", claims(nil))
+	status, body := getWithKey(t, server, "/dashboard/", token)
+	require.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "1 memories, 1 users.")
+	status, _ = send(t, server, http.MethodDelete, "/dashboard/memory?id="+strconv.FormatInt(memories[0].ID, 10), token, "")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = send(t, server, http.MethodDelete, "/dashboard/memory?id="+strconv.FormatInt(memories[3].ID, 10), token, "")
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = getWithKey(t, server, "/dashboard/audit", token)
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = getWithKey(t, server, "/dashboard/audit", "admin-key")
+	assert.Equal(t, http.StatusOK, status)
+
+	// Tokens with another signature, algorithm or invalid claims are
+	// rejected
+	unsigned := func(token string) string {
+		return strings.Join(strings.Split(token, ".")[:2], ".") + "."
+	}
+	parts := strings.Split(token, ".")
+	admin := strings.Split(signJWT(t, secret, "HS256", claims(map[string]interface{}{"role": "admin"})), ".")
+	for name, token := range map[string]string{
+		"other secret":    signJWT(t, []byte("fedcba9876543210fedcba9876543210"), "HS256", claims(nil)),
+		"algorithm none":  unsigned(signJWT(t, secret, "none", claims(nil))),
+		"HS512":           signJWT(t, secret, "HS512", claims(nil)),
+		"expired":         signJWT(t, secret, "HS256", claims(map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()})),
+		"no expiration":   signJWT(t, secret, "HS256", claims(map[string]interface{}{"exp": nil})),
+		"not valid yet":   signJWT(t, secret, "HS256", claims(map[string]interface{}{"nbf": time.Now().Add(time.Hour).Unix()})),
+		"other issuer":    signJWT(t, secret, "HS256", claims(map[string]interface{}{"iss": "evil.example.com"})),
+		"other audience":  signJWT(t, secret, "HS256", claims(map[string]interface{}{"aud": "other"})),
+		"unknown role":    signJWT(t, secret, "HS256", claims(map[string]interface{}{"role": "owner"})),
+		"unsigned":        unsigned(token),
+		"tampered claims": parts[0] + "." + admin[1] + "." + parts[2],
+	} {
+		status, _ := getWithKey(t, server, "/dashboard/", token)
+		assert.Equal(t, http.StatusUnauthorized, status, name)
+	}
+
+	// Secrets must have at least 32 bytes
+	_, err := dashboard.NewHandler(&dashboard.Config{Memory: &core.Client{}, JWT: &dashboard.JWTConfig{Secret: []byte("short")}})
+	assert.Error(t, err)
+}
+
+func TestNewRetentionCurve(t *testing.T) {
+	now := time.Now()
+	lastAccessed := now.Add(-10 * 24 * time.Hour)
+	curve := dashboard.NewRetentionCurve(&core.Memory{
+		CreatedAt:      now.Add(-40 * 24 * time.Hour),
+		LastAccessedAt: &lastAccessed,
+	}, 0.1, now)
+
+	// Retention is measured from the last access
+	assert.InDelta(t, 10, curve.Now.Days, 1e-6)
+	assert.InDelta(t, math.Exp(-1), curve.Now.Retention, 1e-6)
+
+	require.NotEmpty(t, curve.Points)
+	assert.Equal(t, 0.0, curve.Points[0].Days)
+	assert.Equal(t, 1.0, curve.Points[0].Retention)
+	last := curve.Points[len(curve.Points)-1]
+	assert.InDelta(t, 30, last.Days, 1e-6, "at least 30 days are shown")
+	for i := 1; i < len(curve.Points); i++ {
+		assert.Less(t, curve.Points[i].Retention, curve.Points[i-1].Retention)
+	}
+
+	// Old memories are shown two weeks past now
+	curve = dashboard.NewRetentionCurve(&core.Memory{CreatedAt: now.Add(-60 * 24 * time.Hour)}, 0.1, now)
+	assert.InDelta(t, 74, curve.Points[len(curve.Points)-1].Days, 1e-6)
+}
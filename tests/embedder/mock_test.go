@@ -0,0 +1,64 @@
+package embedder_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/embedder/mock"
+)
+
+func cosine(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func TestMockEmbedder_Deterministic(t *testing.T) {
+	client, err := mock.NewClient(&mock.Config{Dimensions: 64})
+	require.NoError(t, err)
+	ctx := context.Background()
+	assert.Equal(t, 64, client.Dimensions())
+
+	first, err := client.Embed(ctx, "I love hiking on weekends")
+	require.NoError(t, err)
+	require.Len(t, first, 64)
+	assert.InDelta(t, 1.0, cosine(first, first), 1e-9)
+
+	// A new client produces the same vector, regardless of case and punctuation
+	other, err := mock.NewClient(&mock.Config{Dimensions: 64})
+	require.NoError(t, err)
+	second, err := other.Embed(ctx, "I LOVE hiking, on weekends!")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	batch, err := client.EmbedBatch(ctx, []string{"I love hiking on weekends", "", "!!!"})
+	require.NoError(t, err)
+	require.Len(t, batch, 3)
+	assert.Equal(t, first, batch[0])
+	assert.NotEqual(t, batch[1], batch[2])
+}
+
+func TestMockEmbedder_SharedWordsAreSimilar(t *testing.T) {
+	client, err := mock.NewClient(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1536, client.Dimensions())
+	ctx := context.Background()
+
+	vectors, err := client.EmbedBatch(ctx, []string{
+		"User loves hiking in the mountains",
+		"hiking in the mountains",
+		"Quarterly revenue report",
+	})
+	require.NoError(t, err)
+
+	assert.Greater(t, cosine(vectors[0], vectors[1]), 0.5)
+	assert.Less(t, cosine(vectors[0], vectors[2]), cosine(vectors[0], vectors[1]))
+}
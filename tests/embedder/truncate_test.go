@@ -0,0 +1,44 @@
+package embedder_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/embedder"
+	"github.com/oceanbase/powermem-go/pkg/embedder/mock"
+)
+
+func TestTruncate(t *testing.T) {
+	full, err := mock.NewClient(&mock.Config{Dimensions: 64})
+	require.NoError(t, err)
+	truncated := embedder.Truncate(full, 16)
+	ctx := context.Background()
+	assert.Equal(t, 16, truncated.Dimensions())
+
+	whole, err := full.Embed(ctx, "I love hiking on weekends")
+	require.NoError(t, err)
+	short, err := truncated.Embed(ctx, "I love hiking on weekends")
+	require.NoError(t, err)
+	require.Len(t, short, 16)
+	assert.InDelta(t, 1.0, cosine(short, short), 1e-9)
+	var norm float64
+	for _, v := range short {
+		norm += v * v
+	}
+	assert.InDelta(t, 1.0, norm, 1e-9)
+	// The components keep their direction
+	assert.InDelta(t, 1.0, cosine(short, whole[:16]), 1e-9)
+
+	batch, err := truncated.EmbedBatch(ctx, []string{"I love hiking on weekends", "tea"})
+	require.NoError(t, err)
+	require.Len(t, batch, 2)
+	assert.Equal(t, short, batch[0])
+	assert.Len(t, batch[1], 16)
+
+	// Embeddings shorter than the dimension cannot be truncated
+	_, err = embedder.Truncate(full, 128).Embed(ctx, "tea")
+	assert.Error(t, err)
+}
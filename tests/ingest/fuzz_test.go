@@ -0,0 +1,34 @@
+package ingest_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/oceanbase/powermem-go/pkg/ingest"
+)
+
+// FuzzLoad checks that the PDF, HTML and Markdown loaders fail cleanly on
+// any input, since IngestURL feeds them remote content.
+func FuzzLoad(f *testing.F) {
+	f.Add([]byte("%PDF-12345678901234567890123456789012345678901234567890 0 obj<"))
+	f.Add(pdfObjects(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		pdfStream("BT /F1 12 Tf 72 720 Td (Hello) Tj ET", true),
+	))
+	f.Add([]byte("%PDF-1.5\n1 0 obj\n<< /Type /ObjStm /N 1 /First -4 /Length 3 >>\nstream\nabc\nendstream\nendobj\n"))
+	f.Add([]byte("%PDF-1.4\n1 0 obj\n<< /Length -10 >>\nstream\nabc\nendstream\nendobj\n"))
+	f.Add([]byte("<html><head><title>T</title></head><body><p>a &amp; b</p><pre>x</pre></body></html>"))
+	f.Add([]byte("---\ntitle: x\n---\n# Title\n\n- [x] item\n\n| a | b |\n|---|---|\n\n```go\ncode\n```\n"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, err := ingest.Load(bytes.NewReader(data), ingest.FormatPDF, "fuzz.pdf")
+		if err != nil && !errors.Is(err, ingest.ErrUnsupportedFormat) && !errors.Is(err, ingest.ErrNoText) {
+			t.Errorf("unexpected PDF error: %v", err)
+		}
+		_, _ = ingest.Load(bytes.NewReader(data), ingest.FormatHTML, "fuzz.html")
+		_, _ = ingest.Load(bytes.NewReader(data), ingest.FormatMarkdown, "fuzz.md")
+	})
+}
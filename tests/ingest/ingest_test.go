@@ -0,0 +1,286 @@
+package ingest_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/ingest"
+)
+
+func TestMarkdownLoader(t *testing.T) {
+	doc, err := ingest.Load(strings.NewReader(`---
+title: "Team Handbook"
+author: ops
+---
+# Onboarding
+
+Welcome to the **team**! Read the [wiki](https://wiki.internal) and the *style guide*.
+
+## Tools
+
+- Install `+"`go`"+` 1.21
+- [x] Request VPN access
+
+| Tool | Owner |
+|------|-------|
+| CI   | Dana  |
+
+> Ask questions in the team channel.
+
+`+"```go\nfmt.Println(\"hi\")\n```\n"), ingest.FormatMarkdown, "handbook.md")
+	require.NoError(t, err)
+
+	assert.Equal(t, "handbook.md", doc.Source)
+	assert.Equal(t, ingest.FormatMarkdown, doc.Format)
+	assert.Equal(t, "Team Handbook", doc.Title)
+	assert.Equal(t, `Onboarding
+
+Welcome to the team! Read the wiki and the style guide.
+
+Tools
+
+- Install go 1.21
+- Request VPN access
+
+Tool | Owner
+CI | Dana
+
+Ask questions in the team channel.
+
+fmt.Println("hi")`, doc.Text)
+}
+
+func TestHTMLLoader(t *testing.T) {
+	doc, err := ingest.Load(strings.NewReader(`<!DOCTYPE html>
+<html><head><title>Release  Notes</title>
+<style>body { color: red; }</style>
+<script>var x = "<p>not text</p>";</script></head>
+<body>
+<!-- navigation -->
+<h1>Version 2.0</h1>
+<p>The <b>new</b> search is
+   faster &amp; cheaper.</p>
+<ul><li>Hybrid retrieval</li><li>Chunking</li></ul>
+<table><tr><th>Store</th><th>Status</th></tr><tr><td>SQLite</td><td>ready</td></tr></table>
+<pre>line 1
+  line 2</pre>
+</body></html>`), ingest.FormatHTML, "https://example.com/notes")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Release Notes", doc.Title)
+	assert.Equal(t, `Version 2.0
+
+The new search is faster & cheaper.
+
+- Hybrid retrieval
+- Chunking
+
+Store | Status
+SQLite | ready
+
+line 1
+  line 2`, doc.Text)
+}
+
+// pdfObjects builds a PDF file from the bodies of its objects, numbered from 1.
+func pdfObjects(objects ...string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	for i, object := range objects {
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	b.WriteString("trailer\n<< /Root 1 0 R /Info 2 0 R >>\n%%EOF\n")
+	return b.Bytes()
+}
+
+// pdfStream returns a stream object, Flate compressed if compress is set.
+func pdfStream(content string, compress bool) string {
+	if !compress {
+		return fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content)
+	}
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	_, _ = w.Write([]byte(content))
+	_ = w.Close()
+	return fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", b.Len(), b.String())
+}
+
+func TestPDFLoader(t *testing.T) {
+	toUnicode := `/CIDInit /ProcSet findresource begin
+begincmap
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+2 beginbfchar <0001> <0048> <0002> <0069> endbfchar
+1 beginbfrange <0010> <0012> <0061> endbfrange
+endcmap`
+
+	data := pdfObjects(
+		"<< /Type /Catalog /Pages 3 0 R >>",
+		"<< /Title (Quarterly \\(Q3\\) Report) >>",
+		"<< /Type /Pages /Kids [4 0 R 5 0 R] /Count 2 /Resources << /Font << /F1 6 0 R /F2 7 0 R >> >> >>",
+		"<< /Type /Page /Parent 3 0 R /Contents 8 0 R >>",
+		"<< /Type /Page /Parent 3 0 R /Contents [9 0 R] >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Font /Subtype /Type0 /BaseFont /Custom /ToUnicode 10 0 R >>",
+		pdfStream("BT /F1 12 Tf 72 720 Td (Revenue grew ) Tj [(by)-300(12%)] TJ 0 -14 Td (Costs were flat.) Tj ET", false),
+		pdfStream("BT /F2 12 Tf 72 720 Td <00010002> Tj T* <001000110012> Tj ET", true),
+		pdfStream(toUnicode, true),
+	)
+
+	doc, err := ingest.Load(bytes.NewReader(data), ingest.FormatPDF, "report.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, "Quarterly (Q3) Report", doc.Title)
+	assert.Equal(t, "Revenue grew by 12%\nCosts were flat.\n\nHi\nabc", doc.Text)
+
+	// Scanned and encrypted PDFs
+	scanned := pdfObjects(
+		"<< /Type /Catalog /Pages 3 0 R >>",
+		"<< >>",
+		"<< /Type /Pages /Kids [4 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 3 0 R /Contents 5 0 R >>",
+		pdfStream("q 612 0 0 792 0 0 cm /Im1 Do Q", true),
+	)
+	_, err = ingest.Load(bytes.NewReader(scanned), ingest.FormatPDF, "scan.pdf")
+	assert.True(t, errors.Is(err, ingest.ErrNoText))
+
+	encrypted := bytes.Replace(data, []byte("/Info 2 0 R"), []byte("/Info 2 0 R /Encrypt 2 0 R"), 1)
+	_, err = ingest.Load(bytes.NewReader(encrypted), ingest.FormatPDF, "secret.pdf")
+	assert.True(t, errors.Is(err, ingest.ErrUnsupportedFormat))
+
+	_, err = ingest.Load(strings.NewReader("plain text"), ingest.FormatPDF, "fake.pdf")
+	assert.True(t, errors.Is(err, ingest.ErrUnsupportedFormat))
+}
+
+func TestPDFLoader_ObjectStreams(t *testing.T) {
+	// PDF 1.5: the catalog, page tree, font and document information are in
+	// a compressed object stream, and a cross-reference stream replaces the
+	// trailer
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Title (Compressed) >>",
+	}
+	var header, body strings.Builder
+	for i, object := range objects {
+		num := []int{1, 2, 5, 6}[i]
+		fmt.Fprintf(&header, "%d %d ", num, body.Len())
+		body.WriteString(object + "\n")
+	}
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	_, _ = w.Write([]byte(header.String() + body.String()))
+	_ = w.Close()
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.5\n")
+	fmt.Fprintf(&b, "3 0 obj\n%s\nendobj\n", "<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>")
+	fmt.Fprintf(&b, "4 0 obj\n%s\nendobj\n", pdfStream("BT /F1 12 Tf 72 720 Td (Stored in an object stream) Tj ET", true))
+	fmt.Fprintf(&b, "7 0 obj\n<< /Type /ObjStm /N %d /First %d /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream\nendobj\n",
+		len(objects), header.Len(), compressed.Len(), compressed.String())
+	fmt.Fprintf(&b, "8 0 obj\n<< /Type /XRef /Root 1 0 R /Info 6 0 R /Size 9 /W [1 2 1] /Length 4 >>\nstream\n\x01\x00\x00\x00\nendstream\nendobj\n")
+	b.WriteString("startxref\n0\n%%EOF\n")
+
+	doc, err := ingest.Load(bytes.NewReader(b.Bytes()), ingest.FormatPDF, "compressed.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, "Compressed", doc.Title)
+	assert.Equal(t, "Stored in an object stream", doc.Text)
+
+	// An object stream with an offset past its data is ignored: the pages
+	// are still found without the catalog, but not the title
+	broken := bytes.Replace(b.Bytes(), []byte(fmt.Sprintf("/First %d", header.Len())), []byte("/First 100000"), 1)
+	doc, err = ingest.Load(bytes.NewReader(broken), ingest.FormatPDF, "broken.pdf")
+	require.NoError(t, err)
+	assert.Empty(t, doc.Title)
+	assert.Equal(t, "Stored in an object stream", doc.Text)
+}
+
+func TestFormatOf(t *testing.T) {
+	for path, want := range map[string]ingest.Format{
+		"a.pdf":       ingest.FormatPDF,
+		"b.MD":        ingest.FormatMarkdown,
+		"c.markdown":  ingest.FormatMarkdown,
+		"d.html":      ingest.FormatHTML,
+		"e.htm":       ingest.FormatHTML,
+		"notes/f.txt": ingest.FormatText,
+	} {
+		format, err := ingest.FormatOf(path)
+		require.NoError(t, err, path)
+		assert.Equal(t, want, format, path)
+	}
+
+	_, err := ingest.FormatOf("slides.pptx")
+	assert.True(t, errors.Is(err, ingest.ErrUnsupportedFormat))
+}
+
+// newClient creates a client with mock providers and a temporary SQLite store.
+func newClient(t *testing.T) *core.Client {
+	client, err := core.NewClient(&core.Config{
+		VectorStore: core.VectorStoreConfig{
+			Provider: "sqlite",
+			SQLite:   &core.SQLiteConfig{DBPath: filepath.Join(t.TempDir(), "test_ingest.db"), EmbeddingModelDims: 64},
+		},
+		LLM:      core.LLMConfig{Provider: "mock"},
+		Embedder: core.EmbedderConfig{Provider: "mock", Dimensions: 64},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestIngester_IngestFile(t *testing.T) {
+	dir := t.TempDir()
+	client := newClient(t)
+	ctx := context.Background()
+
+	var paragraphs []string
+	for i := 1; i <= 6; i++ {
+		paragraphs = append(paragraphs, fmt.Sprintf("Section %d describes the deployment of service number %d in the staging cluster.", i, i))
+	}
+	path := filepath.Join(dir, "runbook.md")
+	require.NoError(t, os.WriteFile(path, []byte("# Runbook\n\n"+strings.Join(paragraphs, "\n\n")), 0o600))
+
+	ingester := ingest.NewIngester(client, &ingest.Config{
+		ChunkSize:    200,
+		ChunkOverlap: 40,
+		Metadata:     map[string]interface{}{"team": "ops"},
+	})
+	result, err := ingester.IngestFile(ctx, path, core.WithUserID("user_001"), core.WithTags("docs"))
+	require.NoError(t, err)
+	require.Greater(t, result.CreatedCount, 1)
+	assert.Zero(t, result.FailedCount)
+
+	for index, id := range result.IDs {
+		memory, err := client.Get(ctx, id)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(memory.Content), 200)
+		assert.Equal(t, "user_001", memory.UserID)
+		assert.Equal(t, []string{"docs"}, memory.Tags)
+		assert.Equal(t, path, memory.Metadata[ingest.MetadataSource])
+		assert.Equal(t, []core.Source{{Document: path}}, memory.Sources)
+		assert.Equal(t, "markdown", memory.Metadata[ingest.MetadataFormat])
+		assert.Equal(t, "Runbook", memory.Metadata[ingest.MetadataTitle])
+		assert.Equal(t, "ops", memory.Metadata["team"])
+		assert.EqualValues(t, index, memory.Metadata[ingest.MetadataChunkIndex])
+		assert.EqualValues(t, result.CreatedCount, memory.Metadata[ingest.MetadataChunkCount])
+	}
+
+	results, err := client.Search(ctx, "service number 6 deployment",
+		core.WithUserIDForSearch("user_001"), core.WithFilters(map[string]interface{}{ingest.MetadataSource: path}))
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Contains(t, results[0].Content, "Section 6")
+
+	_, err = ingester.Ingest(ctx, &ingest.Document{Source: "empty.txt"})
+	assert.True(t, errors.Is(err, ingest.ErrNoText))
+}
@@ -0,0 +1,166 @@
+package ingest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/ingest"
+)
+
+const articlePage = `<html><head><title>Deploying the API</title></head>
+<body>
+<header><a href="/">Home</a> <a href="/docs">Docs</a></header>
+<nav><ul><li>Getting started</li><li>Reference</li></ul></nav>
+<div class="cookie-banner">We use cookies.</div>
+<main>
+<article>
+<header><h1>Deploying the API</h1></header>
+<p>%s</p>
+<div class="share-buttons">Share on social media</div>
+<p>Roll back with the previous image tag.</p>
+</article>
+<section id="comments"><p>Great post!</p></section>
+</main>
+<aside>Related articles</aside>
+<footer>Copyright 2024</footer>
+</body></html>`
+
+func TestHTMLLoader_Readability(t *testing.T) {
+	page := strings.Replace(articlePage, "%s", "Build the image and push it to the registry.", 1)
+
+	doc, err := ingest.HTMLLoader{Readability: true}.Load(strings.NewReader(page))
+	require.NoError(t, err)
+	assert.Equal(t, "Deploying the API", doc.Title)
+	assert.Equal(t, `Deploying the API
+
+Build the image and push it to the registry.
+
+Roll back with the previous image tag.`, doc.Text)
+
+	// Without main content, only the boilerplate is dropped
+	doc, err = ingest.HTMLLoader{Readability: true}.Load(strings.NewReader(
+		`<body><nav>Menu</nav><div class="content"><p>Body text.</p><div class="sidebar"><div>Links</div></div></div><footer>Legal</footer></body>`))
+	require.NoError(t, err)
+	assert.Equal(t, "Body text.", doc.Text)
+
+	// The plain loader keeps everything
+	doc, err = ingest.HTMLLoader{}.Load(strings.NewReader(page))
+	require.NoError(t, err)
+	assert.Contains(t, doc.Text, "We use cookies.")
+	assert.Contains(t, doc.Text, "Great post!")
+}
+
+func TestFormatOfContentType(t *testing.T) {
+	for contentType, want := range map[string]ingest.Format{
+		"text/html; charset=utf-8": ingest.FormatHTML,
+		"application/xhtml+xml":    ingest.FormatHTML,
+		"text/markdown":            ingest.FormatMarkdown,
+		"application/pdf":          ingest.FormatPDF,
+		"text/plain; charset=utf8": ingest.FormatText,
+	} {
+		format, err := ingest.FormatOfContentType(contentType)
+		require.NoError(t, err, contentType)
+		assert.Equal(t, want, format, contentType)
+	}
+
+	_, err := ingest.FormatOfContentType("image/png")
+	assert.True(t, errors.Is(err, ingest.ErrUnsupportedFormat))
+}
+
+func TestIngester_IngestURL(t *testing.T) {
+	var mu sync.Mutex
+	body := "Build the image and push it to the registry."
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/deploy":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(strings.Replace(articlePage, "%s", body, 1)))
+		case "/notes.md":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write([]byte("# Notes\n\nRotate the keys monthly."))
+		case "/logo.png":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte{0x89, 'P', 'N', 'G'})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := newClient(t)
+	ctx := context.Background()
+	ingester := ingest.NewIngester(client, nil)
+	url := server.URL + "/deploy"
+
+	first, err := ingester.IngestURL(ctx, url, core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.False(t, first.Unchanged)
+	require.NotNil(t, first.Added)
+	require.Equal(t, 1, first.Added.CreatedCount)
+	assert.Zero(t, first.Replaced)
+	assert.Len(t, first.ContentHash, 64)
+
+	memory, err := client.Get(ctx, first.Added.IDs[0])
+	require.NoError(t, err)
+	assert.NotContains(t, memory.Content, "cookies")
+	assert.Equal(t, url, memory.Metadata[ingest.MetadataSourceURL])
+	assert.Equal(t, []core.Source{{URL: url}}, memory.Sources)
+	assert.Equal(t, first.ContentHash, memory.Metadata[ingest.MetadataContentHash])
+	assert.Equal(t, "html", memory.Metadata[ingest.MetadataFormat])
+	assert.Equal(t, "Deploying the API", memory.Metadata[ingest.MetadataTitle])
+
+	// Same content: nothing added
+	second, err := ingester.IngestURL(ctx, url, core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.True(t, second.Unchanged)
+	assert.Nil(t, second.Added)
+	assert.Equal(t, first.ContentHash, second.ContentHash)
+
+	// Other users ingest their own copy
+	other, err := ingester.IngestURL(ctx, url, core.WithUserID("user_002"))
+	require.NoError(t, err)
+	assert.False(t, other.Unchanged)
+
+	// Changed content replaces the previous ingestion
+	mu.Lock()
+	body = "Build the image with the release tag and push it to the registry."
+	mu.Unlock()
+	third, err := ingester.IngestURL(ctx, url, core.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.False(t, third.Unchanged)
+	assert.NotEqual(t, first.ContentHash, third.ContentHash)
+	assert.Equal(t, 1, third.Replaced)
+
+	memories, err := client.GetAll(ctx, core.WithUserIDForGetAll("user_001"),
+		core.WithFiltersForGetAll(map[string]interface{}{ingest.MetadataSourceURL: url}))
+	require.NoError(t, err)
+	require.Len(t, memories, 1)
+	assert.Contains(t, memories[0].Content, "release tag")
+
+	// Generic content type: the extension gives the format
+	notes, err := ingester.IngestURL(ctx, server.URL+"/notes.md", core.WithUserID("user_001"))
+	require.NoError(t, err)
+	memory, err = client.Get(ctx, notes.Added.IDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, "markdown", memory.Metadata[ingest.MetadataFormat])
+
+	_, err = ingester.IngestURL(ctx, server.URL+"/logo.png")
+	assert.True(t, errors.Is(err, ingest.ErrUnsupportedFormat))
+
+	_, err = ingester.IngestURL(ctx, server.URL+"/missing")
+	assert.ErrorContains(t, err, "404")
+
+	_, err = ingester.IngestURL(ctx, "file:///etc/passwd")
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+package intelligence_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+)
+
+func TestMemoryConsolidator_Consolidate(t *testing.T) {
+	provider := &stubLLM{response: "```json\n" + `{"actions": [
+		{"event": "merge", "ids": ["0", 1], "text": " Likes coffee every morning ", "reason": "Same preference"},
+		{"event": "PROMOTE", "ids": ["2"]},
+		{"event": "FORGET", "ids": ["1"]},
+		{"event": "RESOLVE", "ids": []}
+	]}` + "\n```"}
+
+	actions, err := intelligence.NewMemoryConsolidator(provider).Consolidate(context.Background(), []intelligence.ExistingMemory{
+		{ID: "0", Text: "Likes coffee"},
+		{ID: "1", Text: "Drinks coffee every morning"},
+		{ID: "2", Text: "Is vegetarian"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []intelligence.ConsolidationAction{
+		{Event: intelligence.ConsolidationMerge, IDs: []string{"0", "1"}, Text: "Likes coffee every morning", Reason: "Same preference"},
+		{Event: intelligence.ConsolidationPromote, IDs: []string{"2"}},
+	}, actions)
+
+	// Nothing to review
+	actions, err = intelligence.NewMemoryConsolidator(provider).Consolidate(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, actions)
+
+	provider.response = "not json"
+	_, err = intelligence.NewMemoryConsolidator(provider).Consolidate(context.Background(), []intelligence.ExistingMemory{{ID: "0", Text: "Likes tea"}})
+	assert.Error(t, err)
+}
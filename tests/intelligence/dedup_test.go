@@ -1,11 +1,18 @@
 package intelligence_test
 
 import (
+	"context"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/oceanbase/powermem-go/pkg/intelligence"
+	"github.com/oceanbase/powermem-go/pkg/llm"
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	sqliteStore "github.com/oceanbase/powermem-go/pkg/storage/sqlite"
 )
 
 func TestDedupManager(t *testing.T) {
@@ -49,3 +56,235 @@ func TestMergeMemories(t *testing.T) {
 	assert.NotNil(t, memory2)
 	assert.NotEqual(t, memory1.ID, memory2.ID)
 }
+
+func TestFindDuplicates(t *testing.T) {
+	testDBPath := "./test_find_duplicates.db"
+	_ = os.Remove(testDBPath)
+
+	store, err := sqliteStore.NewClient(&sqliteStore.Config{
+		DBPath:             testDBPath,
+		CollectionName:     "memories",
+		EmbeddingModelDims: 3,
+	})
+	require.NoError(t, err)
+	defer func() {
+		_ = store.Close()
+		_ = os.Remove(testDBPath)
+	}()
+
+	ctx := context.Background()
+
+	memories := []*storage.Memory{
+		{ID: 1, UserID: "user_001", Content: "User likes Python", Embedding: []float64{1, 0, 0}},
+		{ID: 2, UserID: "user_001", Content: "User loves Python", Embedding: []float64{0.99, 0.01, 0}},
+		{ID: 3, UserID: "user_001", Content: "User enjoys Python", Embedding: []float64{0.98, 0.02, 0}},
+		{ID: 4, UserID: "user_001", Content: "User lives in Paris", Embedding: []float64{0, 1, 0}},
+		{ID: 5, UserID: "user_002", Content: "User likes Python", Embedding: []float64{1, 0, 0}},
+	}
+	for _, mem := range memories {
+		require.NoError(t, store.Insert(ctx, mem))
+	}
+
+	manager := intelligence.NewDedupManager(store, 0.95)
+
+	clusters, err := manager.FindDuplicates(ctx, "user_001", 0)
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+	assert.Len(t, clusters[0].Memories, 3)
+	assert.Len(t, clusters[0].Pairs, 3)
+	assert.GreaterOrEqual(t, clusters[0].Pairs[0].Similarity, clusters[0].Pairs[2].Similarity)
+
+	// Reporting must not modify memories
+	all, err := store.GetAll(ctx, &storage.GetAllOptions{UserID: "user_001", Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+
+	// A stricter threshold finds nothing
+	clusters, err = manager.FindDuplicates(ctx, "user_001", 0.99999)
+	require.NoError(t, err)
+	assert.Empty(t, clusters)
+}
+
+// stubLLM returns a fixed response and records the last prompt.
+type stubLLM struct {
+	response string
+	prompt   string
+}
+
+func (s *stubLLM) Generate(ctx context.Context, prompt string, opts ...llm.GenerateOption) (string, error) {
+	s.prompt = prompt
+	return s.response, nil
+}
+
+func (s *stubLLM) GenerateWithMessages(ctx context.Context, messages []llm.Message, opts ...llm.GenerateOption) (string, error) {
+	return s.response, nil
+}
+
+func (s *stubLLM) Close() error {
+	return nil
+}
+
+func TestMergeStrategies(t *testing.T) {
+	testDBPath := "./test_merge_strategies.db"
+	_ = os.Remove(testDBPath)
+
+	store, err := sqliteStore.NewClient(&sqliteStore.Config{
+		DBPath:             testDBPath,
+		CollectionName:     "memories",
+		EmbeddingModelDims: 3,
+	})
+	require.NoError(t, err)
+	defer func() {
+		_ = store.Close()
+		_ = os.Remove(testDBPath)
+	}()
+
+	ctx := context.Background()
+	existingEmbedding := []float64{1, 0, 0}
+	newEmbedding := []float64{0, 1, 0}
+
+	tests := []struct {
+		name          string
+		merge         *intelligence.MergeConfig
+		newContent    string
+		wantContent   string
+		wantEmbedding []float64
+	}{
+		{
+			name:        "concatenate",
+			merge:       nil,
+			newContent:  "User prefers Python 3",
+			wantContent: "User likes Python User prefers Python 3",
+		},
+		{
+			name:          "keep_newest",
+			merge:         &intelligence.MergeConfig{Strategy: intelligence.MergeStrategyKeepNewest},
+			newContent:    "User uses Go",
+			wantContent:   "User uses Go",
+			wantEmbedding: newEmbedding,
+		},
+		{
+			name:          "keep_longest",
+			merge:         &intelligence.MergeConfig{Strategy: intelligence.MergeStrategyKeepLongest},
+			newContent:    "Python",
+			wantContent:   "User likes Python",
+			wantEmbedding: existingEmbedding,
+		},
+		{
+			name: "llm_merge",
+			merge: &intelligence.MergeConfig{
+				Strategy: intelligence.MergeStrategyLLMMerge,
+				LLM:      &stubLLM{response: "  User likes Python and Go  "},
+				Prompt:   "Existing: {existing} New: {new}",
+				Embed: func(ctx context.Context, text string) ([]float64, error) {
+					return []float64{0, 0, 1}, nil
+				},
+			},
+			newContent:    "User likes Go",
+			wantContent:   "User likes Python and Go",
+			wantEmbedding: []float64{0, 0, 1},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := int64(i + 1)
+			require.NoError(t, store.Insert(ctx, &storage.Memory{
+				ID:        id,
+				UserID:    "user_001",
+				Content:   "User likes Python",
+				Embedding: existingEmbedding,
+				Metadata:  map[string]interface{}{"source": "chat"},
+			}))
+
+			manager, err := intelligence.NewDedupManagerWithMerge(store, 0.95, tt.merge)
+			require.NoError(t, err)
+			mergedAt := time.Date(2020, 3, 1, 9, 0, 0, 0, time.UTC)
+			manager = manager.WithClock(intelligence.NewManualClock(mergedAt))
+
+			merged, err := manager.MergeMemories(ctx, id, tt.newContent, newEmbedding)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantContent, merged.Content)
+
+			stored, err := store.Get(ctx, id, nil)
+			require.NoError(t, err)
+			if tt.wantEmbedding != nil {
+				assert.InDeltaSlice(t, tt.wantEmbedding, stored.Embedding, 1e-6)
+			}
+
+			// Provenance is recorded and existing metadata is preserved
+			assert.Equal(t, "chat", stored.Metadata["source"])
+			assert.EqualValues(t, 1, stored.Metadata["merge_count"])
+			history, ok := stored.Metadata["merge_history"].([]interface{})
+			require.True(t, ok)
+			require.Len(t, history, 1)
+			entry := history[0].(map[string]interface{})
+			assert.Equal(t, string(manager.MergeStrategy()), entry["strategy"])
+			assert.Equal(t, "User likes Python", entry["previous_content"])
+			assert.Equal(t, tt.newContent, entry["incoming_content"])
+
+			// The merge is stamped with the time of the clock
+			assert.Equal(t, "2020-03-01T09:00:00Z", entry["merged_at"])
+			assert.True(t, mergedAt.Equal(stored.UpdatedAt), stored.UpdatedAt)
+		})
+	}
+
+	stub := tests[3].merge.LLM.(*stubLLM)
+	assert.Equal(t, "Existing: User likes Python New: User likes Go", stub.prompt)
+
+	_, err = intelligence.NewDedupManagerWithMerge(store, 0.95, &intelligence.MergeConfig{Strategy: "unknown"})
+	assert.Error(t, err)
+	_, err = intelligence.NewDedupManagerWithMerge(store, 0.95, &intelligence.MergeConfig{Strategy: intelligence.MergeStrategyLLMMerge})
+	assert.Error(t, err)
+}
+
+func TestMergeMemories_RetriesOnConcurrentUpdate(t *testing.T) {
+	testDBPath := "./test_merge_conflict.db"
+	_ = os.Remove(testDBPath)
+
+	store, err := sqliteStore.NewClient(&sqliteStore.Config{
+		DBPath:             testDBPath,
+		CollectionName:     "memories",
+		EmbeddingModelDims: 3,
+	})
+	require.NoError(t, err)
+	defer func() {
+		_ = store.Close()
+		_ = os.Remove(testDBPath)
+	}()
+
+	ctx := context.Background()
+	require.NoError(t, store.Insert(ctx, &storage.Memory{
+		ID: 1, UserID: "user_001", Content: "User likes Python", Embedding: []float64{1, 0, 0},
+	}))
+
+	// Another writer updates the memory while the first merge is in progress
+	stub := &stubLLM{response: "User likes Python, Rust and Go"}
+	embedCalls := 0
+	manager, err := intelligence.NewDedupManagerWithMerge(store, 0.95, &intelligence.MergeConfig{
+		Strategy: intelligence.MergeStrategyLLMMerge,
+		LLM:      stub,
+		Prompt:   "Existing: {existing} New: {new}",
+		Embed: func(ctx context.Context, text string) ([]float64, error) {
+			embedCalls++
+			if embedCalls == 1 {
+				_, err := store.Update(ctx, 1, "User likes Python and Rust", []float64{1, 0, 0}, nil)
+				require.NoError(t, err)
+			}
+			return []float64{0, 0, 1}, nil
+		},
+	})
+	require.NoError(t, err)
+
+	merged, err := manager.MergeMemories(ctx, 1, "User likes Go", []float64{0, 1, 0})
+	require.NoError(t, err)
+	assert.Equal(t, "User likes Python, Rust and Go", merged.Content)
+
+	// The merge was redone on top of the concurrent update
+	assert.Equal(t, 2, embedCalls)
+	assert.Equal(t, "Existing: User likes Python and Rust New: User likes Go", stub.prompt)
+
+	got, err := store.Get(ctx, 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), got.Version)
+}
@@ -1,6 +1,7 @@
 package intelligence_test
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -87,6 +88,14 @@ func TestReinforcementFactor(t *testing.T) {
 	assert.LessOrEqual(t, reinforced, 1.0, "Strength should not exceed 1.0")
 }
 
+func TestWeaken(t *testing.T) {
+	manager := intelligence.NewEbbinghausManager(0.1, 0.3)
+
+	assert.InDelta(t, 0.7, manager.Weaken(1.0), 1e-9)
+	assert.InDelta(t, 0.35, manager.Weaken(0.5), 1e-9)
+	assert.Equal(t, 0.0, manager.Weaken(0.0), "Strength should not go below 0")
+}
+
 func TestEbbinghausEdgeCases(t *testing.T) {
 	decayRate := 0.1
 	reinforcementFactor := 0.3
@@ -107,3 +116,24 @@ func TestEbbinghausEdgeCases(t *testing.T) {
 	reinforced := manager.Reinforce(highStrength)
 	assert.LessOrEqual(t, reinforced, 1.0, "Should not exceed 1.0 after reinforcement")
 }
+
+func TestEbbinghausManager_WithClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := intelligence.NewManualClock(start)
+	manager := intelligence.NewEbbinghausManager(0.1, 0.3).WithClock(clock)
+
+	assert.Equal(t, 1.0, manager.CalculateRetention(start, nil))
+	memory := map[string]interface{}{"created_at": start, "access_count": 0}
+	assert.False(t, manager.ShouldForget(memory))
+	assert.False(t, manager.ShouldArchive(memory))
+
+	clock.Advance(8 * 24 * time.Hour)
+	assert.InDelta(t, math.Exp(-0.8), manager.CalculateRetention(start, nil), 1e-9)
+	assert.True(t, manager.ShouldPromote(memory))
+	assert.True(t, manager.ShouldForget(memory))
+	assert.False(t, manager.ShouldArchive(memory))
+	assert.Equal(t, clock.Now().Add(24*time.Hour), manager.CalculateNextReview(0))
+
+	clock.Set(start.Add(31 * 24 * time.Hour))
+	assert.True(t, manager.ShouldArchive(memory))
+}
@@ -0,0 +1,97 @@
+package intelligence_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+	"github.com/oceanbase/powermem-go/pkg/llm/mock"
+)
+
+func TestExtractFactsWithConfidence(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		response string
+		want     []intelligence.Fact
+	}{
+		{
+			name:     "objects with confidence",
+			response: "```json\n{\"facts\": [{\"fact\": \"Name is John\", \"confidence\": 0.95}, {\"fact\": \"Likes tea\", \"confidence\": 0.4}]}\n```",
+			want: []intelligence.Fact{
+				{Text: "Name is John", Confidence: 0.95},
+				{Text: "Likes tea", Confidence: 0.4},
+			},
+		},
+		{
+			name:     "plain strings default to full confidence",
+			response: `{"facts": ["Name is John", ""]}`,
+			want:     []intelligence.Fact{{Text: "Name is John", Confidence: 1.0}},
+		},
+		{
+			name:     "confidence is clamped and missing text skipped",
+			response: `{"facts": [{"fact": "Lives in Paris", "confidence": 1.7}, {"confidence": 0.9}, {"fact": "Owns a cat"}]}`,
+			want: []intelligence.Fact{
+				{Text: "Lives in Paris", Confidence: 1.0},
+				{Text: "Owns a cat", Confidence: 1.0},
+			},
+		},
+		{
+			name:     "message numbers",
+			response: `{"facts": [{"fact": "Name is John", "confidence": 1.0, "messages": [1, 0, 2.5, 3]}, {"fact": "Likes tea", "messages": "2"}]}`,
+			want: []intelligence.Fact{
+				{Text: "Name is John", Confidence: 1.0, Messages: []int{1, 3}},
+				{Text: "Likes tea", Confidence: 1.0},
+			},
+		},
+		{
+			name:     "entities",
+			response: `{"facts": [{"fact": "Went hiking with Alice in the Alps", "entities": [" Alice", "Alps", "alice", "", 3]}, {"fact": "Likes tea", "entities": "tea"}]}`,
+			want: []intelligence.Fact{
+				{Text: "Went hiking with Alice in the Alps", Confidence: 1.0, Entities: []string{"Alice", "Alps"}},
+				{Text: "Likes tea", Confidence: 1.0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractor := intelligence.NewFactExtractor(&stubLLM{response: tt.response})
+
+			facts, err := extractor.ExtractFactsWithConfidence(ctx, "I'm John")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, facts)
+
+			texts, err := extractor.ExtractFacts(ctx, "I'm John")
+			require.NoError(t, err)
+			require.Len(t, texts, len(tt.want))
+			for i, fact := range tt.want {
+				assert.Equal(t, fact.Text, texts[i])
+			}
+		})
+	}
+
+	extractor := intelligence.NewFactExtractor(&stubLLM{response: "not json"})
+	_, err := extractor.ExtractFactsWithConfidence(ctx, "I'm John")
+	assert.Error(t, err)
+}
+
+func TestExtractFactsWithConfidence_NumbersMessages(t *testing.T) {
+	provider, err := mock.NewClient(&mock.Config{Responses: []string{`{"facts": []}`}})
+	require.NoError(t, err)
+
+	_, err = intelligence.NewFactExtractor(provider).ExtractFactsWithConfidence(context.Background(), []map[string]interface{}{
+		{"role": "system", "content": "Be nice"},
+		{"role": "user", "content": "I'm John"},
+		{"role": "assistant", "content": "Hi John"},
+	})
+	require.NoError(t, err)
+
+	requests := provider.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "Input:\n[2] user: I'm John\n[3] assistant: Hi John", requests[0][1].Content)
+}
@@ -0,0 +1,42 @@
+package intelligence_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+)
+
+func TestMemoryForgetter_Locate(t *testing.T) {
+	provider := &stubLLM{response: "```json\n" + `{"actions": [
+		{"event": "delete", "id": 0, "text": "ignored", "reason": "The home city"},
+		{"event": "UPDATE", "id": "1", "text": " Works as a nurse ", "reason": "Reveals the city"},
+		{"event": "UPDATE", "id": "2"},
+		{"event": "MERGE", "id": "2"},
+		{"event": "DELETE"}
+	]}` + "\n```"}
+
+	memories := []intelligence.ExistingMemory{
+		{ID: "0", Text: "Lives in Berlin"},
+		{ID: "1", Text: "Works as a nurse in Berlin"},
+		{ID: "2", Text: "Likes coffee"},
+	}
+	actions, err := intelligence.NewMemoryForgetter(provider).Locate(context.Background(), "Forget where I live", memories)
+	require.NoError(t, err)
+	assert.Equal(t, []intelligence.ForgetAction{
+		{Event: intelligence.ForgetDelete, ID: "0", Reason: "The home city"},
+		{Event: intelligence.ForgetUpdate, ID: "1", Text: "Works as a nurse", Reason: "Reveals the city"},
+	}, actions)
+
+	// No candidates
+	actions, err = intelligence.NewMemoryForgetter(provider).Locate(context.Background(), "Forget where I live", nil)
+	require.NoError(t, err)
+	assert.Empty(t, actions)
+
+	provider.response = "not json"
+	_, err = intelligence.NewMemoryForgetter(provider).Locate(context.Background(), "Forget where I live", memories)
+	assert.Error(t, err)
+}
@@ -0,0 +1,35 @@
+package intelligence_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"我喜欢靠窗的座位", "zh"},
+		{"窓側の席が好きです", "ja"},
+		{"창가 좌석을 좋아해요", "ko"},
+		{"Я люблю места у окна", "ru"},
+		{"Prefers window seats", "en"},
+		{"The user is allergic to peanuts", "en"},
+		{"Prefiere los asientos de la ventana", "es"},
+		{"Il préfère les places côté fenêtre et le thé", "fr"},
+		{"Ich mag die Plätze am Fenster und den Tee", "de"},
+		{"用户 prefers 靠窗的座位", "zh"},
+		{"12345 !?", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, intelligence.DetectLanguage(tt.text), tt.text)
+	}
+
+	assert.Equal(t, "Chinese", intelligence.LanguageName("zh"))
+	assert.Equal(t, "sv", intelligence.LanguageName("sv"))
+}
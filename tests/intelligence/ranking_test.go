@@ -0,0 +1,84 @@
+package intelligence_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/intelligence"
+)
+
+func TestProcessSearchResults_Ranking(t *testing.T) {
+	now := time.Now()
+	results := []map[string]interface{}{
+		{
+			"id": int64(1), "content": "old but very similar", "score": 0.9,
+			"created_at": now.Add(-30 * 24 * time.Hour), "updated_at": now.Add(-30 * 24 * time.Hour),
+		},
+		{
+			"id": int64(2), "content": "fresh and important", "score": 0.6,
+			"created_at": now, "updated_at": now,
+			"metadata": map[string]interface{}{
+				"intelligence": map[string]interface{}{"importance_score": 1.0},
+			},
+		},
+	}
+
+	// Similarity only keeps the vector order
+	config := intelligence.DefaultConfig()
+	config.Ranking = &intelligence.RankingConfig{SimilarityWeight: 1}
+	manager := intelligence.NewIntelligentMemoryManager(&stubLLM{}, config)
+	processed := manager.ProcessSearchResults(context.Background(), results, "query")
+	require.Len(t, processed, 2)
+	assert.Equal(t, int64(1), processed[0]["id"])
+	assert.InDelta(t, 0.9, processed[0]["final_score"], 1e-9)
+
+	// Recency and importance promote the fresh memory
+	config.Ranking = &intelligence.RankingConfig{
+		SimilarityWeight: 0.4,
+		RecencyWeight:    0.3,
+		ImportanceWeight: 0.3,
+		RecencyHalfLife:  24 * time.Hour,
+	}
+	manager = intelligence.NewIntelligentMemoryManager(&stubLLM{}, config)
+	processed = manager.ProcessSearchResults(context.Background(), results, "query")
+	require.Len(t, processed, 2)
+	assert.Equal(t, int64(2), processed[0]["id"])
+	assert.InDelta(t, 0.6, processed[0]["similarity_score"], 1e-9)
+	assert.InDelta(t, 1.0, processed[0]["recency_score"], 1e-3)
+	assert.InDelta(t, 1.0, processed[0]["importance_score"], 1e-9)
+	assert.InDelta(t, 0.5, processed[1]["importance_score"], 1e-9)
+	assert.Less(t, processed[1]["recency_score"], 0.001)
+}
+
+func TestProcessSearchResults_RetentionPolicies(t *testing.T) {
+	weekAgo := time.Now().Add(-7 * 24 * time.Hour)
+	results := []map[string]interface{}{
+		{"id": int64(1), "content": "query", "agent_id": "task_manager", "created_at": weekAgo},
+		{"id": int64(2), "content": "query", "agent_id": "assistant", "created_at": weekAgo,
+			"metadata": map[string]interface{}{"memory_type": "preference"}},
+		{"id": int64(3), "content": "query", "agent_id": "assistant", "created_at": weekAgo},
+	}
+
+	config := intelligence.DefaultConfig()
+	config.AgentPolicies = map[string]*intelligence.RetentionPolicy{"task_manager": {DecayRate: 2}}
+	config.MemoryTypePolicies = map[string]*intelligence.RetentionPolicy{"preference": {DecayRate: 0.01, ReinforcementFactor: 0.5}}
+	manager := intelligence.NewIntelligentMemoryManager(&stubLLM{}, config)
+
+	processed := manager.ProcessSearchResults(context.Background(), results, "query")
+	require.Len(t, processed, 3)
+	assert.Equal(t, int64(2), processed[0]["id"], "preferences decay slowly")
+	assert.Equal(t, int64(3), processed[1]["id"])
+	assert.Equal(t, int64(1), processed[2]["id"], "the task manager forgets quickly")
+	assert.InDelta(t, math.Exp(-0.01*7), processed[0]["decay_factor"], 1e-6)
+
+	// The agent's policy takes precedence, the memory type's fills the rest
+	ebbinghaus := manager.EbbinghausManagerFor("task_manager", "preference")
+	assert.Equal(t, 2.0, ebbinghaus.DecayRate())
+	assert.InDelta(t, 0.75, ebbinghaus.Reinforce(0.5), 1e-9)
+	assert.Equal(t, 0.1, manager.EbbinghausManagerFor("assistant", "").DecayRate())
+}
@@ -0,0 +1,66 @@
+package llm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/llm"
+	"github.com/oceanbase/powermem-go/pkg/llm/mock"
+)
+
+func TestMock_ScriptedResponses(t *testing.T) {
+	client, err := mock.NewClient(&mock.Config{Responses: []string{"first", "second"}})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for _, want := range []string{"first", "second", "second"} {
+		got, err := client.Generate(ctx, "hello")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	requests := client.Requests()
+	require.Len(t, requests, 3)
+	assert.Equal(t, []llm.Message{{Role: "user", Content: "hello"}}, requests[0])
+}
+
+func TestMock_EchoAndResponder(t *testing.T) {
+	ctx := context.Background()
+
+	echo, err := mock.NewClient(nil)
+	require.NoError(t, err)
+	got, err := echo.GenerateWithMessages(ctx, []llm.Message{
+		{Role: "system", Content: "You are helpful."},
+		{Role: "user", Content: "I like hiking."},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "I like hiking.", got)
+
+	failure := errors.New("scripted failure")
+	responder, err := mock.NewClient(&mock.Config{
+		Responses: []string{"ignored"},
+		Responder: func(messages []llm.Message) (string, error) {
+			if messages[len(messages)-1].Content == "fail" {
+				return "", failure
+			}
+			return `{"facts": []}`, nil
+		},
+	})
+	require.NoError(t, err)
+
+	got, err = responder.Generate(ctx, "extract")
+	require.NoError(t, err)
+	assert.Equal(t, `{"facts": []}`, got)
+
+	_, err = responder.Generate(ctx, "fail")
+	assert.True(t, errors.Is(err, failure))
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = responder.Generate(canceled, "extract")
+	assert.True(t, errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,168 @@
+package llm_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/llm"
+	"github.com/oceanbase/powermem-go/pkg/llm/mock"
+	"github.com/oceanbase/powermem-go/pkg/llm/recorder"
+)
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "golden.json")
+	ctx := context.Background()
+
+	provider, err := mock.NewClient(&mock.Config{
+		Responder: func(messages []llm.Message) (string, error) {
+			switch messages[len(messages)-1].Content {
+			case "fail":
+				return "", errors.New("rate limited")
+			case "Today is 2026-10-16. Hello":
+				return "hello back", nil
+			}
+			return "answer to " + messages[len(messages)-1].Content, nil
+		},
+	})
+	require.NoError(t, err)
+
+	rec, err := recorder.NewClient(&recorder.Config{Provider: provider, Path: path, Mode: recorder.ModeRecord})
+	require.NoError(t, err)
+
+	got, err := rec.Generate(ctx, "question", llm.WithTemperature(0))
+	require.NoError(t, err)
+	assert.Equal(t, "answer to question", got)
+
+	got, err = rec.GenerateWithMessages(ctx, []llm.Message{{Role: "system", Content: "sys"}, {Role: "user", Content: "Today is 2026-10-16. Hello"}})
+	require.NoError(t, err)
+	assert.Equal(t, "hello back", got)
+
+	_, err = rec.Generate(ctx, "fail")
+	require.EqualError(t, err, "rate limited")
+	require.NoError(t, rec.Close())
+
+	assert.FileExists(t, path)
+	assert.Len(t, rec.Interactions(), 3)
+
+	// Replay needs no provider and does not call one
+	replay, err := recorder.NewClient(&recorder.Config{Path: path})
+	require.NoError(t, err)
+	defer replay.Close()
+
+	got, err = replay.Generate(ctx, "question", llm.WithTemperature(0))
+	require.NoError(t, err)
+	assert.Equal(t, "answer to question", got)
+
+	// Dates are masked, so a prompt with another date matches
+	got, err = replay.GenerateWithMessages(ctx, []llm.Message{{Role: "system", Content: "sys"}, {Role: "user", Content: "Today is 2027-01-01. Hello"}})
+	require.NoError(t, err)
+	assert.Equal(t, "hello back", got)
+
+	_, err = replay.Generate(ctx, "fail")
+	assert.EqualError(t, err, "rate limited")
+
+	// Options and the method are part of the match
+	_, err = replay.Generate(ctx, "question")
+	assert.True(t, errors.Is(err, recorder.ErrNotRecorded))
+	_, err = replay.GenerateWithMessages(ctx, []llm.Message{{Role: "user", Content: "question"}}, llm.WithTemperature(0))
+	assert.True(t, errors.Is(err, recorder.ErrNotRecorded))
+}
+
+func TestRecorder_ReplaysRepeatedRequestsInOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+	ctx := context.Background()
+
+	provider, err := mock.NewClient(&mock.Config{Responses: []string{"first", "second"}})
+	require.NoError(t, err)
+	rec, err := recorder.NewClient(&recorder.Config{Provider: provider, Path: path, Mode: recorder.ModeRecord})
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err := rec.Generate(ctx, "same")
+		require.NoError(t, err)
+	}
+	require.NoError(t, rec.Close())
+
+	replay, err := recorder.NewClient(&recorder.Config{Path: path, Mode: recorder.ModeReplay})
+	require.NoError(t, err)
+	defer replay.Close()
+	for _, want := range []string{"first", "second", "second"} {
+		got, err := replay.Generate(ctx, "same")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestRecorder_AutoMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+	ctx := context.Background()
+
+	provider, err := mock.NewClient(nil)
+	require.NoError(t, err)
+
+	// A missing golden file is created
+	rec, err := recorder.NewClient(&recorder.Config{Provider: provider, Path: path, Mode: recorder.ModeAuto})
+	require.NoError(t, err)
+	_, err = rec.Generate(ctx, "one")
+	require.NoError(t, err)
+	require.NoError(t, rec.Close())
+
+	rec, err = recorder.NewClient(&recorder.Config{Provider: provider, Path: path, Mode: recorder.ModeAuto})
+	require.NoError(t, err)
+	_, err = rec.Generate(ctx, "one")
+	require.NoError(t, err)
+	_, err = rec.Generate(ctx, "two")
+	require.NoError(t, err)
+	require.NoError(t, rec.Close())
+
+	// Only the new request reached the provider
+	assert.Len(t, provider.Requests(), 2)
+	assert.Len(t, rec.Interactions(), 2)
+}
+
+func TestRecorder_SharedGoldenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+	ctx := context.Background()
+
+	provider, err := mock.NewClient(nil)
+	require.NoError(t, err)
+	first, err := recorder.NewClient(&recorder.Config{Provider: provider, Path: path, Mode: recorder.ModeRecord})
+	require.NoError(t, err)
+	second, err := recorder.NewClient(&recorder.Config{Provider: provider, Path: path, Mode: recorder.ModeRecord})
+	require.NoError(t, err)
+
+	_, err = first.Generate(ctx, "from first")
+	require.NoError(t, err)
+	_, err = second.Generate(ctx, "from second")
+	require.NoError(t, err)
+	require.NoError(t, first.Close())
+	require.NoError(t, second.Close())
+
+	replay, err := recorder.NewClient(&recorder.Config{Path: path})
+	require.NoError(t, err)
+	defer replay.Close()
+	assert.Len(t, replay.Interactions(), 2)
+}
+
+func TestRecorder_Config(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+
+	_, err := recorder.NewClient(&recorder.Config{})
+	assert.Error(t, err)
+
+	_, err = recorder.NewClient(&recorder.Config{Path: path, Mode: "rewind"})
+	assert.Error(t, err)
+
+	_, err = recorder.NewClient(&recorder.Config{Path: path, Mode: recorder.ModeRecord})
+	assert.Error(t, err, "record mode needs a provider")
+
+	_, err = recorder.NewClient(&recorder.Config{Path: path})
+	assert.True(t, errors.Is(err, os.ErrNotExist), "replay mode needs the golden file")
+
+	assert.Equal(t, "at <timestamp> on <timestamp>", recorder.MaskTimestamps("at 2026-10-16T02:44:19Z on 2026-10-16"))
+}
@@ -0,0 +1,235 @@
+package llm_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/llm"
+	"github.com/oceanbase/powermem-go/pkg/llm/router"
+)
+
+// fakeProvider is an llm.Provider returning a fixed response or error.
+type fakeProvider struct {
+	mu     sync.Mutex
+	name   string
+	err    error
+	delay  time.Duration
+	calls  int
+	closed bool
+}
+
+func (p *fakeProvider) Generate(ctx context.Context, prompt string, opts ...llm.GenerateOption) (string, error) {
+	return p.GenerateWithMessages(ctx, []llm.Message{{Role: "user", Content: prompt}}, opts...)
+}
+
+func (p *fakeProvider) GenerateWithMessages(ctx context.Context, messages []llm.Message, opts ...llm.GenerateOption) (string, error) {
+	p.mu.Lock()
+	p.calls++
+	err, delay := p.err, p.delay
+	p.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return p.name, nil
+}
+
+func (p *fakeProvider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
+
+func (p *fakeProvider) setErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+func (p *fakeProvider) callCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+func TestRouter_FailsOverInPriorityOrder(t *testing.T) {
+	primary := &fakeProvider{name: "openai", err: errors.New("503 service unavailable")}
+	secondary := &fakeProvider{name: "deepseek"}
+	tertiary := &fakeProvider{name: "ollama"}
+
+	client, err := router.NewClient(&router.Config{
+		Backends: []router.Backend{
+			{Name: "openai", Provider: primary},
+			{Name: "deepseek", Provider: secondary},
+			{Name: "ollama", Provider: tertiary},
+		},
+		Cooldown: time.Hour,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	result, err := client.Generate(ctx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "deepseek", result)
+	assert.Equal(t, 1, primary.callCount())
+	assert.Equal(t, 0, tertiary.callCount())
+
+	// The failed primary is skipped during its cooldown
+	result, err = client.GenerateWithMessages(ctx, []llm.Message{{Role: "user", Content: "hello"}})
+	require.NoError(t, err)
+	assert.Equal(t, "deepseek", result)
+	assert.Equal(t, 1, primary.callCount())
+
+	status := client.Status()
+	require.Len(t, status, 3)
+	assert.Equal(t, "openai", status[0].Name)
+	assert.False(t, status[0].Healthy)
+	assert.Equal(t, 1, status[0].ConsecutiveFailures)
+	assert.Contains(t, status[0].LastError, "503")
+	assert.True(t, status[1].Healthy)
+}
+
+func TestRouter_RecoversAfterCooldown(t *testing.T) {
+	primary := &fakeProvider{name: "primary", err: errors.New("timeout")}
+	fallback := &fakeProvider{name: "fallback"}
+
+	client, err := router.NewClient(&router.Config{
+		Backends:         []router.Backend{{Name: "primary", Provider: primary}, {Name: "fallback", Provider: fallback}},
+		FailureThreshold: 2,
+		Cooldown:         50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// Below the threshold the primary is still tried first
+	for i := 0; i < 2; i++ {
+		result, err := client.Generate(ctx, "hello")
+		require.NoError(t, err)
+		assert.Equal(t, "fallback", result)
+	}
+	assert.Equal(t, 2, primary.callCount())
+
+	_, err = client.Generate(ctx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, 2, primary.callCount(), "primary should be cooling down")
+
+	primary.setErr(nil)
+	time.Sleep(60 * time.Millisecond)
+
+	result, err := client.Generate(ctx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "primary", result)
+	assert.True(t, client.Status()[0].Healthy)
+	assert.Equal(t, 0, client.Status()[0].ConsecutiveFailures)
+}
+
+func TestRouter_TriesUnhealthyProvidersLast(t *testing.T) {
+	primary := &fakeProvider{name: "primary", err: errors.New("down")}
+	fallback := &fakeProvider{name: "fallback"}
+
+	client, err := router.NewClient(&router.Config{
+		Backends: []router.Backend{{Name: "primary", Provider: primary}, {Name: "fallback", Provider: fallback}},
+		Cooldown: time.Hour,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// Both fail: both cool down, and the error lists each provider
+	fallback.setErr(errors.New("rate limited"))
+	_, err = client.Generate(ctx, "hello")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, router.ErrAllProvidersFailed))
+	assert.Contains(t, err.Error(), "primary: down")
+	assert.Contains(t, err.Error(), "fallback: rate limited")
+
+	// While all are cooling down they are still tried, in priority order
+	primary.setErr(nil)
+	result, err := client.Generate(ctx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "primary", result)
+}
+
+func TestRouter_LatencyStrategy(t *testing.T) {
+	slow := &fakeProvider{name: "slow", delay: 30 * time.Millisecond}
+	fast := &fakeProvider{name: "fast", delay: time.Millisecond}
+
+	client, err := router.NewClient(&router.Config{
+		Backends: []router.Backend{{Name: "slow", Provider: slow}, {Name: "fast", Provider: fast}},
+		Strategy: router.StrategyLatency,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// The first request measures the primary, then the unmeasured fallback
+	// is preferred, after which the fastest one is used
+	_, err = client.Generate(ctx, "hello")
+	require.NoError(t, err)
+	result, err := client.Generate(ctx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "fast", result)
+
+	for i := 0; i < 3; i++ {
+		result, err = client.Generate(ctx, "hello")
+		require.NoError(t, err)
+		assert.Equal(t, "fast", result)
+	}
+	assert.Equal(t, 1, slow.callCount())
+
+	status := client.Status()
+	assert.Greater(t, status[0].Latency, status[1].Latency)
+}
+
+func TestRouter_ContextCanceled(t *testing.T) {
+	primary := &fakeProvider{name: "primary", delay: time.Second}
+	fallback := &fakeProvider{name: "fallback"}
+
+	client, err := router.NewClient(&router.Config{
+		Backends: []router.Backend{{Name: "primary", Provider: primary}, {Name: "fallback", Provider: fallback}},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.Generate(ctx, "hello")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// The caller's deadline does not count against the provider
+	assert.Equal(t, 0, fallback.callCount())
+	assert.True(t, client.Status()[0].Healthy)
+}
+
+func TestRouter_Config(t *testing.T) {
+	_, err := router.NewClient(&router.Config{})
+	assert.Error(t, err)
+
+	_, err = router.NewClient(&router.Config{
+		Backends: []router.Backend{{Name: "a", Provider: &fakeProvider{}}},
+		Strategy: "random",
+	})
+	assert.Error(t, err)
+
+	a, b := &fakeProvider{}, &fakeProvider{}
+	client, err := router.NewClient(&router.Config{
+		Backends: []router.Backend{{Provider: a}, {Provider: b}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "backend-1", client.Status()[1].Name)
+
+	require.NoError(t, client.Close())
+	assert.True(t, a.closed)
+	assert.True(t, b.closed)
+}
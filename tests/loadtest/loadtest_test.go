@@ -0,0 +1,73 @@
+package loadtest_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/loadtest"
+)
+
+func newClient(t *testing.T) *core.Client {
+	client, err := core.NewClient(&core.Config{
+		LLM:      core.LLMConfig{Provider: "mock"},
+		Embedder: core.EmbedderConfig{Provider: "mock", Dimensions: 64},
+		VectorStore: core.VectorStoreConfig{SQLite: &core.SQLiteConfig{
+			DBPath:             filepath.Join(t.TempDir(), "loadtest.db"),
+			EmbeddingModelDims: 64,
+		}},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestRun_Requests(t *testing.T) {
+	client := newClient(t)
+	ctx := context.Background()
+
+	report, err := loadtest.Run(ctx, client, loadtest.Options{Workers: 4, Requests: 200, AddRatio: 0.5, Users: 5})
+	require.NoError(t, err)
+
+	add, search := report.Operations[loadtest.OperationAdd], report.Operations[loadtest.OperationSearch]
+	require.NotNil(t, add)
+	require.NotNil(t, search)
+	assert.Equal(t, 200, add.Count+search.Count)
+	for _, stats := range []*loadtest.OperationStats{add, search} {
+		assert.Zero(t, stats.Errors, stats.FirstError)
+		assert.Positive(t, stats.Throughput)
+		assert.LessOrEqual(t, stats.P50, stats.P90)
+		assert.LessOrEqual(t, stats.P90, stats.P99)
+		assert.LessOrEqual(t, stats.P99, stats.Max)
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, report.Write(&out))
+	assert.Contains(t, out.String(), "OPERATION")
+	assert.Contains(t, out.String(), "search")
+
+	memories, err := client.GetAll(ctx, core.WithUserIDForGetAll(loadtest.UserPrefix+"0"))
+	require.NoError(t, err)
+	require.NotEmpty(t, memories)
+	require.NoError(t, loadtest.Cleanup(ctx, client, 5))
+	memories, err = client.GetAll(ctx, core.WithUserIDForGetAll(loadtest.UserPrefix+"0"))
+	require.NoError(t, err)
+	assert.Empty(t, memories)
+}
+
+func TestRun_SearchOnly(t *testing.T) {
+	report, err := loadtest.Run(context.Background(), newClient(t), loadtest.Options{Requests: 20, AddRatio: -1})
+	require.NoError(t, err)
+	assert.Nil(t, report.Operations[loadtest.OperationAdd])
+	assert.Equal(t, 20, report.Operations[loadtest.OperationSearch].Count)
+}
+
+func TestRun_InvalidAddRatio(t *testing.T) {
+	_, err := loadtest.Run(context.Background(), newClient(t), loadtest.Options{AddRatio: 1.5})
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+package loadtest_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/loadtest"
+)
+
+func TestNewReport(t *testing.T) {
+	// Searches took 1ms to 100ms, split between two workers out of order;
+	// failed operations count but have no latency
+	samples := make([][]loadtest.Sample, 2)
+	for i := 100; i >= 1; i-- {
+		samples[i%2] = append(samples[i%2], loadtest.Sample{Operation: loadtest.OperationSearch, Latency: time.Duration(i) * time.Millisecond})
+	}
+	samples[0] = append(samples[0],
+		loadtest.Sample{Operation: loadtest.OperationSearch, Latency: time.Hour, Err: errors.New("search timed out")},
+		loadtest.Sample{Operation: loadtest.OperationAdd, Latency: time.Minute, Err: errors.New("embedder unavailable")},
+		loadtest.Sample{Operation: loadtest.OperationAdd, Latency: 7 * time.Millisecond},
+	)
+	samples[1] = append(samples[1], loadtest.Sample{Operation: loadtest.OperationAdd, Latency: time.Second, Err: errors.New("database is locked")})
+
+	report := loadtest.NewReport(samples, 10*time.Second)
+	assert.Equal(t, 10*time.Second, report.Elapsed)
+	require.Len(t, report.Operations, 2)
+
+	assert.Equal(t, &loadtest.OperationStats{
+		Count:      101,
+		Errors:     1,
+		Throughput: 10,
+		Mean:       50500 * time.Microsecond,
+		P50:        50 * time.Millisecond,
+		P90:        90 * time.Millisecond,
+		P99:        99 * time.Millisecond,
+		Max:        100 * time.Millisecond,
+		FirstError: "search timed out",
+	}, report.Operations[loadtest.OperationSearch])
+
+	assert.Equal(t, &loadtest.OperationStats{
+		Count:      3,
+		Errors:     2,
+		Throughput: 0.1,
+		Mean:       7 * time.Millisecond,
+		P50:        7 * time.Millisecond,
+		P90:        7 * time.Millisecond,
+		P99:        7 * time.Millisecond,
+		Max:        7 * time.Millisecond,
+		FirstError: "embedder unavailable",
+	}, report.Operations[loadtest.OperationAdd])
+
+	var out bytes.Buffer
+	require.NoError(t, report.Write(&out))
+	assert.Equal(t, `OPERATION  COUNT  ERRORS  OPS/S  MEAN    P50   P90   P99   MAX
+add        3      2       0.1    7ms     7ms   7ms   7ms   7ms
+search     101    1       10.0   50.5ms  50ms  90ms  99ms  100ms
+
+Elapsed: 10s
+First add error: embedder unavailable
+First search error: search timed out
+`, out.String())
+}
+
+func TestNewReport_AllFailed(t *testing.T) {
+	report := loadtest.NewReport([][]loadtest.Sample{{{Operation: loadtest.OperationAdd, Err: errors.New("failed")}}}, time.Second)
+	assert.Equal(t, &loadtest.OperationStats{Count: 1, Errors: 1, FirstError: "failed"}, report.Operations[loadtest.OperationAdd])
+}
+
+func TestNewReport_Percentiles(t *testing.T) {
+	ms := func(values ...int) []loadtest.Sample {
+		samples := make([]loadtest.Sample, len(values))
+		for i, v := range values {
+			samples[i] = loadtest.Sample{Operation: loadtest.OperationSearch, Latency: time.Duration(v) * time.Millisecond}
+		}
+		return samples
+	}
+
+	// Nearest rank: the smallest latency at or above p percent of them
+	for _, tt := range []struct {
+		samples       []loadtest.Sample
+		p50, p90, p99 time.Duration
+	}{
+		{ms(5), 5 * time.Millisecond, 5 * time.Millisecond, 5 * time.Millisecond},
+		{ms(1, 2, 3), 2 * time.Millisecond, 3 * time.Millisecond, 3 * time.Millisecond},
+		{ms(1, 2, 3, 4), 2 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond},
+		{ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 5 * time.Millisecond, 9 * time.Millisecond, 10 * time.Millisecond},
+	} {
+		stats := loadtest.NewReport([][]loadtest.Sample{tt.samples}, time.Second).Operations[loadtest.OperationSearch]
+		assert.Equal(t, tt.p50, stats.P50, "p50 of %d latencies", len(tt.samples))
+		assert.Equal(t, tt.p90, stats.P90, "p90 of %d latencies", len(tt.samples))
+		assert.Equal(t, tt.p99, stats.P99, "p99 of %d latencies", len(tt.samples))
+	}
+}
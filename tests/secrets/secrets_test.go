@@ -0,0 +1,223 @@
+package secrets_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/secrets"
+	awsSecrets "github.com/oceanbase/powermem-go/pkg/secrets/aws"
+	envSecrets "github.com/oceanbase/powermem-go/pkg/secrets/env"
+	fileSecrets "github.com/oceanbase/powermem-go/pkg/secrets/file"
+	vaultSecrets "github.com/oceanbase/powermem-go/pkg/secrets/vault"
+)
+
+func TestCache_RefreshesLazily(t *testing.T) {
+	var calls int32
+	var fail atomic.Value
+	fail.Store(false)
+	provider := secrets.ProviderFunc(func(ctx context.Context, name string) (string, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if fail.Load().(bool) {
+			return "", errors.New("store unavailable")
+		}
+		return name + "-v" + string(rune('0'+n)), nil
+	})
+	cache := secrets.NewCache(provider, 50*time.Millisecond)
+	ctx := context.Background()
+
+	value, err := cache.GetSecret(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, "key-v1", value)
+
+	// Cached within the interval
+	value, err = cache.GetSecret(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, "key-v1", value)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// Refreshed on the first use after the interval
+	time.Sleep(60 * time.Millisecond)
+	value, err = cache.GetSecret(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, "key-v2", value)
+
+	// A failed refresh keeps the previous value
+	fail.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	value, err = cache.GetSecret(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, "key-v2", value)
+
+	// Without a previous value the error is returned
+	_, err = cache.GetSecret(ctx, "other")
+	assert.Error(t, err)
+
+	fail.Store(false)
+	cache.Invalidate("key")
+	value, err = cache.GetSecret(ctx, "key")
+	require.NoError(t, err)
+	assert.NotEqual(t, "key-v2", value)
+}
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("POWERMEM_TEST_OPENAI_KEY", "sk-env")
+
+	provider, err := envSecrets.NewClient(&envSecrets.Config{Prefix: "POWERMEM_TEST_"})
+	require.NoError(t, err)
+
+	value, err := provider.GetSecret(context.Background(), "OPENAI_KEY")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-env", value)
+
+	_, err = provider.GetSecret(context.Background(), "MISSING_KEY")
+	assert.True(t, errors.Is(err, secrets.ErrNotFound))
+}
+
+func TestFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "openai_api_key"), []byte("sk-file\n"), 0o600))
+
+	provider, err := fileSecrets.NewClient(&fileSecrets.Config{Dir: dir})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	value, err := provider.GetSecret(ctx, "openai_api_key")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-file", value)
+
+	// Rotations are read on the next call
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "openai_api_key"), []byte("sk-rotated"), 0o600))
+	value, err = provider.GetSecret(ctx, "openai_api_key")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-rotated", value)
+
+	_, err = provider.GetSecret(ctx, "missing")
+	assert.True(t, errors.Is(err, secrets.ErrNotFound))
+
+	_, err = provider.GetSecret(ctx, "../etc/passwd")
+	assert.Error(t, err)
+
+	_, err = fileSecrets.NewClient(&fileSecrets.Config{})
+	assert.Error(t, err)
+}
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/powermem/openai":
+			_, _ = w.Write([]byte(`{"data":{"data":{"api_key":"sk-vault","value":"default"},"metadata":{"version":3}}}`))
+		case "/v1/kv/powermem/openai":
+			_, _ = w.Write([]byte(`{"data":{"api_key":"sk-vault-v1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	provider, err := vaultSecrets.NewClient(&vaultSecrets.Config{Address: server.URL, Token: "test-token"})
+	require.NoError(t, err)
+
+	value, err := provider.GetSecret(ctx, "powermem/openai#api_key")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-vault", value)
+
+	value, err = provider.GetSecret(ctx, "powermem/openai")
+	require.NoError(t, err)
+	assert.Equal(t, "default", value)
+
+	_, err = provider.GetSecret(ctx, "powermem/openai#missing")
+	assert.True(t, errors.Is(err, secrets.ErrNotFound))
+
+	_, err = provider.GetSecret(ctx, "powermem/missing#api_key")
+	assert.True(t, errors.Is(err, secrets.ErrNotFound))
+
+	v1, err := vaultSecrets.NewClient(&vaultSecrets.Config{Address: server.URL, Token: "test-token", Mount: "kv", KVVersion: 1})
+	require.NoError(t, err)
+	value, err = v1.GetSecret(ctx, "powermem/openai#api_key")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-vault-v1", value)
+
+	denied, err := vaultSecrets.NewClient(&vaultSecrets.Config{Address: server.URL, Token: "wrong"})
+	require.NoError(t, err)
+	_, err = denied.GetSecret(ctx, "powermem/openai#api_key")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "permission denied")
+}
+
+func TestAWSSecretsManagerProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.Equal(t, "session-token", r.Header.Get("X-Amz-Security-Token"))
+
+		auth := r.Header.Get("Authorization")
+		assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDTEST/"), auth)
+		assert.Contains(t, auth, "/us-west-2/secretsmanager/aws4_request")
+		assert.Contains(t, auth, "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target")
+
+		var req struct {
+			SecretID string `json:"SecretId"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		switch req.SecretID {
+		case "prod/powermem":
+			_, _ = w.Write([]byte(`{"Name":"prod/powermem","SecretString":"{\"openai_api_key\":\"sk-aws\"}"}`))
+		case "plain":
+			_, _ = w.Write([]byte(`{"Name":"plain","SecretString":"sk-plain"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`))
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	provider, err := awsSecrets.NewClient(&awsSecrets.Config{
+		Region:          "us-west-2",
+		AccessKeyID:     "AKIDTEST",
+		SecretAccessKey: "secret",
+		SessionToken:    "session-token",
+		Endpoint:        server.URL,
+	})
+	require.NoError(t, err)
+
+	value, err := provider.GetSecret(ctx, "prod/powermem#openai_api_key")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-aws", value)
+
+	value, err = provider.GetSecret(ctx, "plain")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-plain", value)
+
+	_, err = provider.GetSecret(ctx, "missing")
+	assert.True(t, errors.Is(err, secrets.ErrNotFound))
+
+	_, err = provider.GetSecret(ctx, "prod/powermem#missing")
+	assert.True(t, errors.Is(err, secrets.ErrNotFound))
+
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	_, err = awsSecrets.NewClient(&awsSecrets.Config{AccessKeyID: "a", SecretAccessKey: "b"})
+	assert.Error(t, err)
+}
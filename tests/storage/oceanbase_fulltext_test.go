@@ -0,0 +1,213 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	"github.com/oceanbase/powermem-go/pkg/storage/oceanbase"
+)
+
+// MySQL error numbers of the servers without FULLTEXT index support.
+const (
+	errParse             = 1064 // ER_PARSE_ERROR
+	errTableCantHandleFT = 1214 // ER_TABLE_CANT_HANDLE_FT
+	errNotSupportedYet   = 1235 // ER_NOT_SUPPORTED_YET
+	errFeatureNotEnabled = 1289 // ER_FEATURE_DISABLED
+)
+
+func TestMergeHybrid(t *testing.T) {
+	memories := func() (vector, text []*storage.Memory) {
+		vector = []*storage.Memory{{ID: 1, Score: 0.9}, {ID: 2, Score: 0.6}}
+		text = []*storage.Memory{{ID: 2, Score: 0.6}, {ID: 3, Score: 0.2}}
+		return vector, text
+	}
+	relevances := []float64{4, 2}
+
+	// The relevances are normalized by the best one: 1 for memory 2, 0.5 for
+	// memory 3, and 0 for memory 1, which the text search did not match
+	vector, text := memories()
+	results := oceanbase.MergeHybrid(vector, text, relevances, 0, 0)
+	require.Len(t, results, 3)
+	assert.Equal(t, int64(2), results[0].ID)
+	assert.InDelta(t, 0.7*0.6+0.3*1, results[0].Score, 1e-9)
+	assert.Equal(t, int64(1), results[1].ID)
+	assert.InDelta(t, 0.7*0.9, results[1].Score, 1e-9)
+	assert.Equal(t, int64(3), results[2].ID)
+	assert.InDelta(t, 0.7*0.2+0.3*0.5, results[2].Score, 1e-9)
+
+	// The minimum score applies to the blended score
+	vector, text = memories()
+	results = oceanbase.MergeHybrid(vector, text, relevances, 0.5, 0)
+	require.Len(t, results, 2)
+	assert.Equal(t, int64(2), results[0].ID)
+	assert.Equal(t, int64(1), results[1].ID)
+
+	vector, text = memories()
+	results = oceanbase.MergeHybrid(vector, text, relevances, 0, 1)
+	require.Len(t, results, 1)
+	assert.Equal(t, int64(2), results[0].ID)
+
+	// Without relevance, scores are the weighted similarities, ties by ID
+	results = oceanbase.MergeHybrid(
+		[]*storage.Memory{{ID: 5, Score: 0.5}},
+		[]*storage.Memory{{ID: 4, Score: 0.5}},
+		[]float64{0}, 0, 0)
+	require.Len(t, results, 2)
+	assert.Equal(t, int64(4), results[0].ID)
+	assert.InDelta(t, 0.35, results[0].Score, 1e-9)
+	assert.Equal(t, int64(5), results[1].ID)
+
+	assert.Empty(t, oceanbase.MergeHybrid(nil, nil, nil, 0, 10))
+}
+
+func TestFullTextIndexUnsupported(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"table cannot handle fulltext", &mysql.MySQLError{Number: errTableCantHandleFT, Message: "The used table type doesn't support FULLTEXT indexes"}, true},
+		{"fulltext not supported", &mysql.MySQLError{Number: errNotSupportedYet, Message: "Fulltext index not supported"}, true},
+		{"fulltext syntax", &mysql.MySQLError{Number: errParse, Message: "You have an error in your SQL syntax near 'FULLTEXT INDEX'"}, true},
+		{"other unsupported feature", &mysql.MySQLError{Number: errNotSupportedYet, Message: "This version doesn't yet support 'WITH PARSER ik'"}, false},
+		{"vector index error", &mysql.MySQLError{Number: errFeatureNotEnabled, Message: "vector index is not enabled"}, false},
+		{"other MySQL error", &mysql.MySQLError{Number: 1146, Message: "Table 'memories' doesn't exist"}, false},
+		{"not a MySQL error", errors.New("fulltext failed"), false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, oceanbase.FullTextIndexUnsupported(tt.err))
+		})
+	}
+}
+
+func TestInitFullTextIndex(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		indexed     int64
+		createErr   error
+		wantExecs   []string
+		wantIndexed bool
+		wantErr     bool
+	}{
+		{
+			name:        "existing index",
+			indexed:     1,
+			wantIndexed: true,
+		},
+		{
+			name: "backfill then create",
+			wantExecs: []string{
+				"UPDATE `memories` SET fulltext_content = document WHERE fulltext_content IS NULL",
+				"CREATE FULLTEXT INDEX idx_fulltext_content ON `memories` (fulltext_content) WITH PARSER ik",
+			},
+			wantIndexed: true,
+		},
+		{
+			name:      "unsupported",
+			createErr: &mysql.MySQLError{Number: errTableCantHandleFT, Message: "The used table type doesn't support FULLTEXT indexes"},
+			wantExecs: []string{
+				"UPDATE `memories` SET fulltext_content = document WHERE fulltext_content IS NULL",
+				"CREATE FULLTEXT INDEX idx_fulltext_content ON `memories` (fulltext_content) WITH PARSER ik",
+			},
+		},
+		{
+			name:      "failure",
+			createErr: &mysql.MySQLError{Number: errNotSupportedYet, Message: "This version doesn't yet support 'WITH PARSER ik'"},
+			wantExecs: []string{
+				"UPDATE `memories` SET fulltext_content = document WHERE fulltext_content IS NULL",
+				"CREATE FULLTEXT INDEX idx_fulltext_content ON `memories` (fulltext_content) WITH PARSER ik",
+			},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &fakeConn{count: tt.indexed, createErr: tt.createErr}
+			db := sql.OpenDB(conn)
+			defer db.Close()
+
+			indexed, err := oceanbase.InitFullTextIndex(context.Background(), db, "memories", "ik")
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantIndexed, indexed)
+			}
+			assert.Equal(t, tt.wantExecs, conn.executed())
+		})
+	}
+}
+
+// fakeConn is a database connection answering the queries of
+// InitFullTextIndex: the index count query returns count, and CREATE
+// FULLTEXT INDEX fails with createErr. It is its own connector.
+type fakeConn struct {
+	count     int64
+	createErr error
+
+	mu    sync.Mutex
+	execs []string
+}
+
+func (c *fakeConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c *fakeConn) Driver() driver.Driver                        { return nil }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fakeConn: Prepare is not supported")
+}
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fakeConn: Begin is not supported")
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	query = strings.Join(strings.Fields(query), " ")
+	c.mu.Lock()
+	c.execs = append(c.execs, query)
+	c.mu.Unlock()
+	if strings.HasPrefix(query, "CREATE FULLTEXT INDEX") && c.createErr != nil {
+		return nil, c.createErr
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if !strings.Contains(query, "information_schema.STATISTICS") {
+		return nil, errors.New("fakeConn: unexpected query: " + query)
+	}
+	return &countRows{count: c.count}, nil
+}
+
+func (c *fakeConn) executed() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.execs
+}
+
+// countRows is the single-row result of a COUNT(*) query.
+type countRows struct {
+	count int64
+	read  bool
+}
+
+func (r *countRows) Columns() []string { return []string{"COUNT(*)"} }
+func (r *countRows) Close() error      { return nil }
+
+func (r *countRows) Next(dest []driver.Value) error {
+	if r.read {
+		return io.EOF
+	}
+	r.read = true
+	dest[0] = r.count
+	return nil
+}
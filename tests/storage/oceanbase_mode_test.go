@@ -0,0 +1,257 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	"github.com/oceanbase/powermem-go/pkg/storage/oceanbase"
+)
+
+func TestDetectMode(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		conn *modeConn
+		want oceanbase.Mode
+	}{
+		{name: "MySQL mode", conn: &modeConn{mode: "MYSQL"}, want: oceanbase.ModeMySQL},
+		{name: "Oracle mode", conn: &modeConn{mode: "ORACLE"}, want: oceanbase.ModeOracle},
+		{name: "Oracle mode in lower case", conn: &modeConn{mode: "oracle"}, want: oceanbase.ModeOracle},
+		{name: "MySQL server", conn: &modeConn{}, want: oceanbase.ModeMySQL},
+		{name: "Oracle mode without the variable", conn: &modeConn{oracleDual: true}, want: oceanbase.ModeOracle},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sql.OpenDB(tt.conn)
+			defer db.Close()
+			assert.Equal(t, tt.want, oceanbase.DetectMode(context.Background(), db))
+		})
+	}
+}
+
+func TestNewClientWithDB_OracleMode(t *testing.T) {
+	conn := &modeConn{mode: "ORACLE"}
+	client, err := oceanbase.NewClientWithDB(sql.OpenDB(conn), &oceanbase.Config{CollectionName: "memories", EmbeddingModelDims: 3})
+	require.NoError(t, err)
+	defer client.Close()
+	assert.Equal(t, oceanbase.ModeOracle, client.Mode())
+	assert.False(t, client.VectorIndexed())
+	assert.False(t, client.FullTextIndexed())
+
+	// Tables are created without IF NOT EXISTS, and their indexes and
+	// sequences are created after them, with names of their own
+	execs := conn.statements(true)
+	require.NotEmpty(t, execs)
+	assert.True(t, strings.HasPrefix(execs[0], `CREATE TABLE "MEMORIES" ( id NUMBER(19),`), execs[0])
+	for _, want := range []string{
+		`CREATE INDEX "MEMORIES_USER_AGENT" ON "MEMORIES" (user_id, agent_id)`,
+		`CREATE UNIQUE INDEX "MEMORIES_UID" ON "MEMORIES" ("UID")`,
+		`CREATE INDEX "MEMORIES_CHANGES_CREATED_AT" ON "MEMORIES_CHANGES" (created_at)`,
+		`CREATE SEQUENCE "MEMORIES_CHANGES_SEQ" ORDER`,
+		`CREATE SEQUENCE "MEMORIES_AUDIT_SEQ" ORDER`,
+		`CREATE INDEX "MEMORIES_TEAMS_USER_ID" ON "MEMORIES_TEAMS" (user_id)`,
+	} {
+		assert.Contains(t, execs, want)
+	}
+	for _, statement := range execs {
+		for _, mysqlOnly := range []string{"IF NOT EXISTS", "AUTO_INCREMENT", "LONGTEXT", "`"} {
+			assert.NotContains(t, statement, mysqlOnly)
+		}
+	}
+
+	ctx := context.Background()
+	conn.reset()
+	change := &storage.Change{Type: "memory.created", MemoryID: 1, UserID: "alice"}
+	require.NoError(t, client.AppendChange(ctx, change))
+	assert.Equal(t, int64(42), change.Seq)
+	assert.Equal(t, []string{
+		`SELECT "MEMORIES_CHANGES_SEQ".NEXTVAL FROM DUAL`,
+		`INSERT INTO "MEMORIES_CHANGES" (seq, event_type, memory_id, user_id, agent_id, payload, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+	}, conn.statements(false))
+
+	conn.reset()
+	require.NoError(t, client.AddTeamMember(ctx, "team", "alice"))
+	assert.Equal(t, []string{
+		`MERGE INTO "MEMORIES_TEAMS" t USING (SELECT ? AS team_id, ? AS user_id, ? AS created_at FROM DUAL) s ` +
+			`ON (t.team_id = s.team_id AND t.user_id = s.user_id) ` +
+			`WHEN NOT MATCHED THEN INSERT (team_id, user_id, created_at) VALUES (s.team_id, s.user_id, s.created_at)`,
+	}, conn.statements(false))
+
+	conn.reset()
+	_, err = client.GetAll(ctx, &storage.GetAllOptions{
+		UserID: "alice", Limit: 10, Offset: 20, Tags: []string{"work"}, Filters: map[string]interface{}{"topic": "go"},
+	})
+	require.NoError(t, err)
+	query := conn.statements(false)[0]
+	assert.Contains(t, query, `"UID"`)
+	assert.Contains(t, query, `FROM "MEMORIES"`)
+	assert.Contains(t, query, `JSON_VALUE(metadata, '$.topic') = ?`)
+	assert.Contains(t, query, `JSON_EXISTS(tags, '$[*]?(@ == $v)' PASSING ? AS "v")`)
+	assert.True(t, strings.HasSuffix(query, "ORDER BY id DESC OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"), query)
+
+	conn.reset()
+	_, err = client.Search(ctx, []float64{1, 0, 0}, &storage.SearchOptions{UserID: "alice", Limit: 5})
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(conn.statements(false)[0], "ORDER BY distance ASC, id ASC FETCH FIRST 5 ROWS ONLY"))
+
+	conn.reset()
+	_, err = client.ListChanges(ctx, &storage.ListChangesOptions{UserID: "alice", Limit: 3})
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(conn.statements(false)[0], `ORDER BY seq FETCH FIRST 3 ROWS ONLY`))
+
+	assert.ErrorIs(t, client.Snapshot(ctx, t.TempDir()+"/dump"), oceanbase.ErrOracleMode)
+}
+
+func TestNewClientWithDB_MySQLMode(t *testing.T) {
+	// The tables, columns and indexes exist already
+	conn := &modeConn{mode: "MYSQL", count: 1}
+	client, err := oceanbase.NewClientWithDB(sql.OpenDB(conn), &oceanbase.Config{CollectionName: "memories", EmbeddingModelDims: 3})
+	require.NoError(t, err)
+	defer client.Close()
+	assert.Equal(t, oceanbase.ModeMySQL, client.Mode())
+	assert.True(t, client.VectorIndexed())
+	assert.True(t, client.FullTextIndexed())
+
+	execs := conn.statements(true)
+	require.NotEmpty(t, execs)
+	assert.True(t, strings.HasPrefix(execs[0], "CREATE TABLE IF NOT EXISTS `memories` ( id BIGINT PRIMARY KEY,"), execs[0])
+
+	ctx := context.Background()
+	conn.reset()
+	change := &storage.Change{Type: "memory.created", MemoryID: 1, UserID: "alice"}
+	require.NoError(t, client.AppendChange(ctx, change))
+	assert.Equal(t, int64(7), change.Seq)
+	assert.Equal(t, []string{
+		"INSERT INTO `memories_changes` (event_type, memory_id, user_id, agent_id, payload, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+	}, conn.statements(false))
+
+	conn.reset()
+	require.NoError(t, client.AddTeamMember(ctx, "team", "alice"))
+	assert.Equal(t, []string{
+		"INSERT IGNORE INTO `memories_teams` (team_id, user_id, created_at) VALUES (?, ?, ?)",
+	}, conn.statements(false))
+
+	conn.reset()
+	_, err = client.GetAll(ctx, &storage.GetAllOptions{
+		UserID: "alice", Limit: 10, Offset: 20, Tags: []string{"work"}, Filters: map[string]interface{}{"topic": "go"},
+	})
+	require.NoError(t, err)
+	query := conn.statements(false)[0]
+	assert.Contains(t, query, "`uid`")
+	assert.Contains(t, query, "metadata->>'$.topic' = ?")
+	assert.Contains(t, query, "JSON_CONTAINS(tags, ?)")
+	assert.True(t, strings.HasSuffix(query, "ORDER BY id DESC LIMIT 10 OFFSET 20"), query)
+}
+
+// modeConn is a database connection of a tenant in mode, or of a MySQL
+// server if mode is empty. It records the statements it runs; COUNT(*)
+// queries return count, NEXTVAL returns 42 and inserts have the ID 7. Other
+// queries return no rows. It is its own connector.
+type modeConn struct {
+	mode string
+	// oracleDual makes queries of DUAL succeed when mode is empty, as on
+	// Oracle-mode tenants that do not show their mode.
+	oracleDual bool
+	count      int64
+
+	mu    sync.Mutex
+	execs []string
+	all   []string
+}
+
+func (c *modeConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c *modeConn) Driver() driver.Driver                        { return nil }
+
+func (c *modeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("modeConn: Prepare is not supported")
+}
+func (c *modeConn) Close() error { return nil }
+func (c *modeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("modeConn: Begin is not supported")
+}
+
+func (c *modeConn) record(query string, exec bool) string {
+	query = strings.Join(strings.Fields(query), " ")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.all = append(c.all, query)
+	if exec {
+		c.execs = append(c.execs, query)
+	}
+	return query
+}
+
+func (c *modeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	query = c.record(query, false)
+	switch {
+	case strings.Contains(query, "ob_compatibility_mode"):
+		if c.mode == "" {
+			return &valueRows{}, nil
+		}
+		return &valueRows{columns: []string{"Variable_name", "Value"}, values: [][]driver.Value{{"ob_compatibility_mode", c.mode}}}, nil
+	case strings.Contains(query, "SYS_CONTEXT"):
+		if c.mode != "ORACLE" && !c.oracleDual {
+			return nil, errors.New("modeConn: unknown function SYS_CONTEXT")
+		}
+		return &valueRows{columns: []string{"SCHEMA"}, values: [][]driver.Value{{"POWERMEM"}}}, nil
+	case strings.Contains(query, "COUNT(*)"):
+		return &valueRows{columns: []string{"COUNT(*)"}, values: [][]driver.Value{{c.count}}}, nil
+	case strings.Contains(query, "NEXTVAL"):
+		return &valueRows{columns: []string{"NEXTVAL"}, values: [][]driver.Value{{int64(42)}}}, nil
+	}
+	return &valueRows{}, nil
+}
+
+func (c *modeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.record(query, true)
+	return insertResult(7), nil
+}
+
+// statements returns the statements run since the last reset: only the
+// executed ones if execs is set, or all of them.
+func (c *modeConn) statements(execs bool) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if execs {
+		return c.execs
+	}
+	return c.all
+}
+
+func (c *modeConn) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.execs, c.all = nil, nil
+}
+
+// insertResult is the result of a statement inserting one row with an ID.
+type insertResult int64
+
+func (r insertResult) LastInsertId() (int64, error) { return int64(r), nil }
+func (r insertResult) RowsAffected() (int64, error) { return 1, nil }
+
+// valueRows is the result of a query, made of values.
+type valueRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *valueRows) Columns() []string { return r.columns }
+func (r *valueRows) Close() error      { return nil }
+
+func (r *valueRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
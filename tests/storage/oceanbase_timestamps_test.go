@@ -0,0 +1,256 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/storage/oceanbase"
+)
+
+// withLocal runs the test with time.Local set to loc.
+func withLocal(t *testing.T, loc *time.Location) {
+	local := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = local })
+}
+
+func TestParseTimestamp(t *testing.T) {
+	withLocal(t, time.FixedZone("UTC+8", 8*60*60))
+
+	for _, tt := range []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"stored", "2024-05-01T09:30:00.123456+00:00", "2024-05-01T09:30:00.123456+00:00"},
+		{"RFC 3339 UTC", "2024-05-01T09:30:00Z", "2024-05-01T09:30:00.000000+00:00"},
+		{"RFC 3339 positive offset", "2024-05-01T11:30:00.5+02:00", "2024-05-01T09:30:00.500000+00:00"},
+		{"RFC 3339 negative offset", "2024-04-30T23:30:00-10:00", "2024-05-01T09:30:00.000000+00:00"},
+		{"RFC 3339 nanoseconds", "2024-05-01T09:30:00.123456789Z", "2024-05-01T09:30:00.123456+00:00"},
+		{"naive Python", "2024-05-01T17:30:00.123456", "2024-05-01T09:30:00.123456+00:00"},
+		{"naive Python without fraction", "2024-05-01T17:30:00", "2024-05-01T09:30:00.000000+00:00"},
+		{"legacy local", "2024-05-01 17:30:00", "2024-05-01T09:30:00.000000+00:00"},
+		{"legacy local with fraction", "2024-05-01 17:30:00.25", "2024-05-01T09:30:00.250000+00:00"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := oceanbase.ParseTimestamp(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, time.Local, parsed.Location())
+			assert.Equal(t, tt.want, oceanbase.FormatTimestamp(parsed))
+
+			// Stored values round-trip unchanged
+			reparsed, err := oceanbase.ParseTimestamp(oceanbase.FormatTimestamp(parsed))
+			require.NoError(t, err)
+			assert.True(t, parsed.Truncate(time.Microsecond).Equal(reparsed))
+			assert.Equal(t, tt.want, oceanbase.FormatTimestamp(reparsed))
+		})
+	}
+
+	for _, value := range []string{"", "yesterday", "2024-05-01", "2024-13-01T09:30:00Z", "1714555800"} {
+		_, err := oceanbase.ParseTimestamp(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestFormatTimestampSortsByTime(t *testing.T) {
+	base := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	times := []time.Time{
+		base,
+		base.Add(time.Microsecond),
+		base.Add(-time.Microsecond),
+		base.Add(time.Second).In(time.FixedZone("UTC-10", -10*60*60)),
+		base.Add(-time.Hour).In(time.FixedZone("UTC+14", 14*60*60)),
+		base.AddDate(-24, 0, 0),
+		base.AddDate(0, 0, 1).In(time.FixedZone("UTC+5:30", 5*60*60+30*60)),
+		base.AddDate(75, 7, 0),
+		time.Date(1000, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(9999, 12, 31, 23, 59, 59, 999999000, time.UTC),
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	formatted := make([]string, len(times))
+	for i, ts := range times {
+		formatted[i] = oceanbase.FormatTimestamp(ts)
+		assert.Len(t, formatted[i], len(oceanbase.TimestampLayout))
+		assert.True(t, matchesLike(formatted[i], oceanbase.TimestampPattern), formatted[i])
+	}
+	assert.True(t, sort.StringsAreSorted(formatted), "%v", formatted)
+}
+
+func TestNormalizeTimestamps(t *testing.T) {
+	withLocal(t, time.FixedZone("UTC+8", 8*60*60))
+
+	// More rows than a batch, some of them unparseable
+	table := &timestampTable{rows: map[int64][2]sql.NullString{}}
+	valid := func(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+	for key := int64(1); key <= 2500; key++ {
+		switch key % 4 {
+		case 0:
+			table.rows[key] = [2]sql.NullString{valid("2024-05-01T17:30:00.123456"), valid("not a time")}
+		case 1:
+			table.rows[key] = [2]sql.NullString{valid("2024-05-01T11:30:00+02:00"), {}}
+		case 2:
+			table.rows[key] = [2]sql.NullString{valid("2024-05-01T09:30:00.000000+00:00"), valid("2024-05-01 17:30:00")}
+		case 3:
+			table.rows[key] = [2]sql.NullString{valid("garbage"), valid("")}
+		}
+	}
+	db := sql.OpenDB(table)
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, oceanbase.NormalizeTimestamps(ctx, db, "memories", "id", "created_at", "expires_at"))
+
+	// Each row is read once, in three batches
+	assert.Equal(t, 3, table.queries)
+	assert.Equal(t, 2500, table.updates)
+	for key, row := range table.rows {
+		switch key % 4 {
+		case 0:
+			assert.Equal(t, "2024-05-01T09:30:00.123456+00:00", row[0].String)
+			assert.Equal(t, "not a time", row[1].String)
+		case 1:
+			assert.Equal(t, "2024-05-01T09:30:00.000000+00:00", row[0].String)
+			assert.False(t, row[1].Valid)
+		case 2:
+			assert.Equal(t, "2024-05-01T09:30:00.000000+00:00", row[0].String)
+			assert.Equal(t, "2024-05-01T09:30:00.000000+00:00", row[1].String)
+		case 3:
+			assert.Equal(t, "garbage", row[0].String)
+			assert.Equal(t, "", row[1].String)
+		}
+	}
+
+	// Unparseable values are selected again, but only once per run
+	table.queries, table.updates = 0, 0
+	require.NoError(t, oceanbase.NormalizeTimestamps(ctx, db, "memories", "id", "created_at", "expires_at"))
+	assert.Equal(t, 2, table.queries)
+	assert.Equal(t, 1250, table.updates)
+}
+
+// matchesLike reports whether value matches a LIKE pattern made of literal
+// characters and "_" wildcards.
+func matchesLike(value, pattern string) bool {
+	if len(value) != len(pattern) {
+		return false
+	}
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '_' && pattern[i] != value[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// timestampTable is a database connection holding a table with an integer
+// key and two timestamp columns, answering the queries of
+// NormalizeTimestamps. It is its own connector.
+type timestampTable struct {
+	mu      sync.Mutex
+	rows    map[int64][2]sql.NullString
+	queries int
+	updates int
+}
+
+func (c *timestampTable) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c *timestampTable) Driver() driver.Driver                        { return nil }
+
+func (c *timestampTable) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("timestampTable: Prepare is not supported")
+}
+func (c *timestampTable) Close() error { return nil }
+func (c *timestampTable) Begin() (driver.Tx, error) {
+	return nil, errors.New("timestampTable: Begin is not supported")
+}
+
+// QueryContext selects the rows after args[0] with a value not matching
+// oceanbase.TimestampPattern, by key, up to the LIMIT of the query.
+func (c *timestampTable) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	var limit int
+	if _, err := fmt.Sscanf(query[strings.LastIndex(query, "LIMIT"):], "LIMIT %d", &limit); err != nil {
+		return nil, fmt.Errorf("timestampTable: unexpected query: %s", query)
+	}
+	after := args[0].Value.(int64)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queries++
+	var keys []int64
+	for key, row := range c.rows {
+		if key <= after {
+			continue
+		}
+		for _, value := range row {
+			if value.Valid && !matchesLike(value.String, oceanbase.TimestampPattern) {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+	result := &tableRows{}
+	for _, key := range keys {
+		row := c.rows[key]
+		values := []driver.Value{key, nil, nil}
+		for i, value := range row {
+			if value.Valid {
+				values[i+1] = value.String
+			}
+		}
+		result.rows = append(result.rows, values)
+	}
+	return result, nil
+}
+
+// ExecContext sets the two columns of the row whose key is the last
+// argument.
+func (c *timestampTable) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if !strings.HasPrefix(query, "UPDATE") || len(args) != 3 {
+		return nil, fmt.Errorf("timestampTable: unexpected statement: %s", query)
+	}
+	var row [2]sql.NullString
+	for i := range row {
+		if s, ok := args[i].Value.(string); ok {
+			row[i] = sql.NullString{String: s, Valid: true}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.updates++
+	c.rows[args[2].Value.(int64)] = row
+	return driver.RowsAffected(1), nil
+}
+
+// tableRows is the result of a query of timestampTable.
+type tableRows struct {
+	rows [][]driver.Value
+}
+
+func (r *tableRows) Columns() []string { return []string{"id", "created_at", "expires_at"} }
+func (r *tableRows) Close() error      { return nil }
+
+func (r *tableRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
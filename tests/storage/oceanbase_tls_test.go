@@ -0,0 +1,84 @@
+package storage_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/storage/oceanbase"
+)
+
+// writeTestCA writes a self-signed CA certificate to a PEM file and returns
+// the file path and the DER bytes of the certificate.
+func writeTestCA(t *testing.T) (string, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "powermem test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	return path, der
+}
+
+func TestLoadTLSConfig(t *testing.T) {
+	caFile, caDER := writeTestCA(t)
+
+	config, err := oceanbase.LoadTLSConfig(oceanbase.TLSDisabled, "", "", "")
+	require.NoError(t, err)
+	assert.Nil(t, config)
+
+	config, err = oceanbase.LoadTLSConfig(oceanbase.TLSRequired, "", "", "")
+	require.NoError(t, err)
+	require.NotNil(t, config)
+	assert.True(t, config.InsecureSkipVerify)
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+
+	config, err = oceanbase.LoadTLSConfig(oceanbase.TLSVerifyIdentity, caFile, "", "")
+	require.NoError(t, err)
+	assert.False(t, config.InsecureSkipVerify)
+	assert.NotNil(t, config.RootCAs)
+
+	// verify_ca checks the chain but not the host name
+	config, err = oceanbase.LoadTLSConfig(oceanbase.TLSVerifyCA, caFile, "", "")
+	require.NoError(t, err)
+	assert.True(t, config.InsecureSkipVerify)
+	require.NotNil(t, config.VerifyPeerCertificate)
+	assert.NoError(t, config.VerifyPeerCertificate([][]byte{caDER}, nil))
+	_, otherDER := writeTestCA(t)
+	assert.Error(t, config.VerifyPeerCertificate([][]byte{otherDER}, nil))
+
+	_, err = oceanbase.LoadTLSConfig("verify-full", "", "", "")
+	assert.Error(t, err)
+
+	_, err = oceanbase.LoadTLSConfig(oceanbase.TLSRequired, filepath.Join(t.TempDir(), "missing.pem"), "", "")
+	assert.Error(t, err)
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	require.NoError(t, os.WriteFile(empty, []byte("no certificate"), 0o600))
+	_, err = oceanbase.LoadTLSConfig(oceanbase.TLSRequired, empty, "", "")
+	assert.Error(t, err)
+}
@@ -15,7 +15,7 @@ import (
 	postgresStore "github.com/oceanbase/powermem-go/pkg/storage/postgres"
 )
 
-func setupPostgresTest(t *testing.T) (storage.VectorStore, string, func()) {
+func setupPostgresTest(t *testing.T, configure ...func(*postgresStore.Config)) (storage.VectorStore, string, func()) {
 	// Load .env file from project root
 	envPath := filepath.Join("..", "..", ".env")
 	_ = godotenv.Load(envPath)
@@ -62,6 +62,9 @@ func setupPostgresTest(t *testing.T) (storage.VectorStore, string, func()) {
 		EmbeddingModelDims: 1536,
 		SSLMode:            "disable",
 	}
+	for _, fn := range configure {
+		fn(config)
+	}
 
 	store, err := postgresStore.NewClient(config)
 	if err != nil {
@@ -590,3 +593,73 @@ func TestPostgresClient_Reset(t *testing.T) {
 	assert.Equal(t, 1, len(results))
 	assert.Equal(t, "new_user", results[0].UserID)
 }
+
+func TestPostgresClient_Snapshot(t *testing.T) {
+	store, _, cleanup := setupPostgresTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	embedding := make([]float64, 1536)
+	embedding[0] = 0.1
+	require.NoError(t, store.Insert(ctx, &storage.Memory{
+		ID:        1,
+		UserID:    "test_user",
+		Content:   "Test memory content",
+		Embedding: embedding,
+		Metadata:  map[string]interface{}{"key": "value"},
+		Tags:      []string{"work"},
+	}))
+
+	path := filepath.Join(t.TempDir(), "memories.snapshot")
+	snapshotter := store.(storage.Snapshotter)
+	require.NoError(t, snapshotter.Snapshot(ctx, path))
+	assert.Error(t, snapshotter.Snapshot(ctx, path), "the snapshot file exists")
+
+	require.NoError(t, store.DeleteAll(ctx, &storage.DeleteAllOptions{}))
+	require.NoError(t, snapshotter.RestoreSnapshot(ctx, path))
+
+	memory, err := store.Get(ctx, 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Test memory content", memory.Content)
+	assert.Equal(t, "value", memory.Metadata["key"])
+	assert.Equal(t, []string{"work"}, memory.Tags)
+	assert.InDelta(t, 0.1, memory.Embedding[0], 1e-6)
+}
+
+func TestPostgresClient_QuantizedSearch(t *testing.T) {
+	for _, quantization := range []string{postgresStore.QuantizationHalfvec, postgresStore.QuantizationBit} {
+		t.Run(quantization, func(t *testing.T) {
+			store, _, cleanup := setupPostgresTest(t, func(config *postgresStore.Config) {
+				config.Quantization = quantization
+			})
+			defer cleanup()
+
+			ctx := context.Background()
+			for i := 0; i < 5; i++ {
+				embedding := make([]float64, 1536)
+				embedding[i] = 1
+				embedding[5] = 0.5
+				require.NoError(t, store.Insert(ctx, &storage.Memory{
+					ID:        int64(i + 1),
+					UserID:    "test_user",
+					Content:   "Memory " + strconv.Itoa(i),
+					Embedding: embedding,
+				}))
+			}
+
+			query := make([]float64, 1536)
+			query[2] = 1
+			query[5] = 0.5
+			results, err := store.Search(ctx, query, &storage.SearchOptions{UserID: "test_user", Limit: 2, MinScore: 0.1})
+			require.NoError(t, err)
+			require.NotEmpty(t, results)
+
+			// Candidates are re-ranked by exact similarity
+			assert.Equal(t, int64(3), results[0].ID)
+			assert.InDelta(t, 1.0, results[0].Score, 1e-3)
+			for _, result := range results {
+				assert.GreaterOrEqual(t, result.Score, 0.1)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	_ "github.com/oceanbase/powermem-go/pkg/storage/sqlite"
+)
+
+// openReplicaTestDB opens an SQLite database standing in for a primary or a
+// replica.
+func openReplicaTestDB(t *testing.T, name string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), name+".db"))
+	require.NoError(t, err)
+	return db
+}
+
+func TestReplicaSet_Reader(t *testing.T) {
+	ctx := context.Background()
+	primary := openReplicaTestDB(t, "primary")
+	replica1 := openReplicaTestDB(t, "replica1")
+	replica2 := openReplicaTestDB(t, "replica2")
+	set := storage.NewReplicaSet(primary, []*sql.DB{replica1, replica2}, 0, nil)
+
+	// Reads alternate between the replicas
+	first := set.Reader(ctx)
+	second := set.Reader(ctx)
+	assert.ElementsMatch(t, []*sql.DB{replica1, replica2}, []*sql.DB{first, second})
+	assert.Same(t, primary, set.Primary())
+
+	// Reads can be sent to the primary
+	assert.Same(t, primary, set.Reader(storage.ContextWithPrimary(ctx)))
+	assert.False(t, storage.PrimaryFromContext(ctx))
+
+	require.NoError(t, set.Close())
+
+	// Without replicas, reads go to the primary
+	single := storage.NewReplicaSet(primary, nil, 0, nil)
+	assert.Same(t, primary, single.Reader(ctx))
+}
+
+func TestReplicaSet_Staleness(t *testing.T) {
+	ctx := context.Background()
+	primary := openReplicaTestDB(t, "primary")
+	fresh := openReplicaTestDB(t, "fresh")
+	lagging := openReplicaTestDB(t, "lagging")
+	broken := openReplicaTestDB(t, "broken")
+	defer func() { _ = primary.Close() }()
+	defer func() { _ = fresh.Close() }()
+	defer func() { _ = lagging.Close() }()
+
+	lags := map[*sql.DB]time.Duration{fresh: 0, lagging: time.Minute}
+	lag := func(ctx context.Context, db *sql.DB) (time.Duration, error) {
+		if db == broken {
+			return 0, errors.New("connection refused")
+		}
+		return lags[db], nil
+	}
+
+	// Replicas lagging more than the limit or failing their check are skipped
+	set := storage.NewReplicaSet(primary, []*sql.DB{fresh, lagging, broken}, 5*time.Second, lag)
+	for i := 0; i < 6; i++ {
+		assert.Same(t, fresh, set.Reader(ctx))
+	}
+
+	// Without a usable replica, reads go to the primary
+	set = storage.NewReplicaSet(primary, []*sql.DB{lagging, broken}, 5*time.Second, lag)
+	assert.Same(t, primary, set.Reader(ctx))
+
+	// Without a lag limit, replicas are pinged
+	require.NoError(t, broken.Close())
+	set = storage.NewReplicaSet(primary, []*sql.DB{broken}, 0, nil)
+	assert.Same(t, primary, set.Reader(ctx))
+}
@@ -0,0 +1,202 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	"github.com/oceanbase/powermem-go/pkg/storage/routing"
+	sqliteStore "github.com/oceanbase/powermem-go/pkg/storage/sqlite"
+)
+
+// setupRoutingTest creates a routing store over an "eu" and a "us" SQLite
+// store, routing "eu_user" to "eu" and the other users to "us".
+func setupRoutingTest(t *testing.T) (*routing.Client, map[string]storage.VectorStore) {
+	t.Helper()
+	stores := make(map[string]storage.VectorStore)
+	for _, name := range []string{"eu", "us"} {
+		store, err := sqliteStore.NewClient(&sqliteStore.Config{
+			DBPath:             filepath.Join(t.TempDir(), name+".db"),
+			CollectionName:     "memories",
+			EmbeddingModelDims: 3,
+		})
+		require.NoError(t, err)
+		stores[name] = store
+	}
+
+	client, err := routing.NewClient(&routing.Config{
+		Stores:  stores,
+		Default: "us",
+		Policy:  routing.StaticPolicy(map[string]string{"eu_user": "eu"}),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client, stores
+}
+
+func TestRoutingClient_Routes(t *testing.T) {
+	client, stores := setupRoutingTest(t)
+	ctx := context.Background()
+
+	memories := []*storage.Memory{
+		{ID: 1, UserID: "eu_user", Content: "EU memory", Embedding: []float64{1, 0, 0}, Tags: []string{"eu"}},
+		{ID: 2, UserID: "us_user", Content: "US memory", Embedding: []float64{0.9, 0.1, 0}, Tags: []string{"us"}},
+		{ID: 3, Content: "Memory without user", Embedding: []float64{0, 1, 0}},
+	}
+	for i, memory := range memories {
+		memory.CreatedAt = time.Now().Add(time.Duration(i) * time.Second)
+		memory.UpdatedAt = memory.CreatedAt
+		require.NoError(t, client.Insert(ctx, memory))
+	}
+
+	// Each memory is kept in the store of its user only
+	euMemories, err := stores["eu"].GetAll(ctx, &storage.GetAllOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, euMemories, 1)
+	assert.Equal(t, int64(1), euMemories[0].ID)
+	usMemories, err := stores["us"].GetAll(ctx, &storage.GetAllOptions{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, usMemories, 2)
+
+	// Without a user, reads run on every store
+	memory, err := client.Get(ctx, 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "EU memory", memory.Content)
+	_, err = client.Get(ctx, 42, nil)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = client.Get(ctx, 1, &storage.GetOptions{UserID: "us_user"})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	all, err := client.GetAll(ctx, &storage.GetAllOptions{Limit: 2, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, []int64{2, 1}, []int64{all[0].ID, all[1].ID}, "newest first across stores")
+
+	results, err := client.Search(ctx, []float64{1, 0, 0}, &storage.SearchOptions{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, []int64{1, 2}, []int64{results[0].ID, results[1].ID})
+	results, err = client.Search(ctx, []float64{1, 0, 0}, &storage.SearchOptions{UserID: "us_user", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, int64(2), results[0].ID)
+
+	tags, err := client.ListTags(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"eu", "us"}, tags)
+
+	// Updates and deletes find the store holding the memory
+	updated, err := client.Update(ctx, 1, "EU memory updated", []float64{1, 0, 0}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "EU memory updated", updated.Content)
+	require.NoError(t, client.Delete(ctx, 1, nil))
+	_, err = stores["eu"].Get(ctx, 1, nil)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	require.NoError(t, client.DeleteAll(ctx, &storage.DeleteAllOptions{}))
+	all, err = client.GetAll(ctx, &storage.GetAllOptions{Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestRoutingClient_ChangeLogAndErasure(t *testing.T) {
+	client, stores := setupRoutingTest(t)
+	ctx := context.Background()
+
+	require.NoError(t, client.Insert(ctx, &storage.Memory{ID: 1, UserID: "eu_user", Content: "EU memory", Embedding: []float64{1, 0, 0}}))
+	require.NoError(t, client.AppendChange(ctx, &storage.Change{Type: "memory.created", MemoryID: 1, UserID: "eu_user", Payload: []byte(`{}`)}))
+
+	// The change log is kept in the default store
+	changes, err := stores["us"].ListChanges(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+	changes, err = stores["eu"].ListChanges(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	counts, err := client.EraseUser(ctx, "eu_user")
+	require.NoError(t, err)
+	assert.Equal(t, &storage.ErasureCounts{Memories: 1, Changes: 1}, counts)
+}
+
+func TestRoutingClient_PolicyErrors(t *testing.T) {
+	store, err := sqliteStore.NewClient(&sqliteStore.Config{
+		DBPath:             filepath.Join(t.TempDir(), "us.db"),
+		CollectionName:     "memories",
+		EmbeddingModelDims: 3,
+	})
+	require.NoError(t, err)
+	defer store.Close()
+
+	_, err = routing.NewClient(&routing.Config{Stores: map[string]storage.VectorStore{"us": store}, Default: "eu"})
+	assert.Error(t, err)
+
+	client, err := routing.NewClient(&routing.Config{
+		Stores:  map[string]storage.VectorStore{"us": store},
+		Default: "us",
+		Policy: routing.Chain(
+			routing.StaticPolicy(map[string]string{"moved_user": "eu"}),
+			func(ctx context.Context, userID string) (string, error) {
+				if userID == "unknown_user" {
+					return "", errors.New("no account")
+				}
+				return "", nil
+			},
+		),
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	name, err := client.Route(ctx, "any_user")
+	require.NoError(t, err)
+	assert.Equal(t, "us", name)
+	_, err = client.Route(ctx, "moved_user")
+	assert.ErrorContains(t, err, `unknown store "eu"`)
+	err = client.Insert(ctx, &storage.Memory{ID: 1, UserID: "unknown_user", Embedding: []float64{1, 0, 0}})
+	assert.ErrorContains(t, err, "no account")
+}
+
+func TestRoutingClient_DeleteWhere(t *testing.T) {
+	client, stores := setupRoutingTest(t)
+	ctx := context.Background()
+
+	for i, userID := range []string{"eu_user", "eu_user", "us_user"} {
+		require.NoError(t, client.Insert(ctx, &storage.Memory{
+			ID:        int64(i + 1),
+			UserID:    userID,
+			Content:   "Debug trace",
+			Embedding: []float64{0.1, 0.2, 0.3},
+			Metadata:  map[string]interface{}{"type": "debug"},
+		}))
+	}
+
+	opts := &storage.DeleteWhereOptions{Filters: map[string]interface{}{"type": "debug"}, DryRun: true}
+	count, err := client.DeleteWhere(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	// The count is checked across the stores before any of them deletes
+	opts.DryRun = false
+	opts.ExpectedCount = 2
+	_, err = client.DeleteWhere(ctx, opts)
+	assert.ErrorIs(t, err, storage.ErrCountMismatch)
+	count, err = stores["eu"].DeleteWhere(ctx, &storage.DeleteWhereOptions{DryRun: true, UserID: "eu_user"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	opts.ExpectedCount = 3
+	count, err = client.DeleteWhere(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	for name, store := range stores {
+		memories, err := store.GetAll(ctx, &storage.GetAllOptions{Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, memories, name)
+	}
+}
@@ -0,0 +1,92 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	sqliteStore "github.com/oceanbase/powermem-go/pkg/storage/sqlite"
+)
+
+// lockSQLite holds the write lock of the database at path until the returned
+// function is called.
+func lockSQLite(t *testing.T, path string) func() {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "BEGIN IMMEDIATE")
+	require.NoError(t, err)
+	return func() {
+		_, _ = conn.ExecContext(ctx, "COMMIT")
+		_ = conn.Close()
+		_ = db.Close()
+	}
+}
+
+func newBusySQLiteClient(t *testing.T, path string, retries int) *sqliteStore.Client {
+	store, err := sqliteStore.NewClient(&sqliteStore.Config{
+		DBPath:             path,
+		CollectionName:     "memories",
+		EmbeddingModelDims: 3,
+		BusyTimeout:        10 * time.Millisecond,
+		BusyRetries:        retries,
+		Synchronous:        "normal",
+		CheckpointInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestSQLiteClient_BusyRetries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "busy.db")
+	store := newBusySQLiteClient(t, path, 4)
+
+	unlock := lockSQLite(t, path)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		unlock()
+	}()
+
+	err := store.Insert(context.Background(), &storage.Memory{
+		ID: 1, UserID: "u1", Content: "written once the lock is released", Embedding: []float64{1, 0, 0},
+	})
+	require.NoError(t, err)
+
+	memory, err := store.Get(context.Background(), 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "written once the lock is released", memory.Content)
+}
+
+func TestSQLiteClient_BusyWithoutRetries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "busy.db")
+	store := newBusySQLiteClient(t, path, 0)
+
+	unlock := lockSQLite(t, path)
+	defer unlock()
+
+	err := store.Insert(context.Background(), &storage.Memory{
+		ID: 1, UserID: "u1", Content: "locked out", Embedding: []float64{1, 0, 0},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database is locked")
+}
+
+func TestSQLiteClient_InvalidSynchronous(t *testing.T) {
+	_, err := sqliteStore.NewClient(&sqliteStore.Config{
+		DBPath:             filepath.Join(t.TempDir(), "sync.db"),
+		CollectionName:     "memories",
+		EmbeddingModelDims: 3,
+		Synchronous:        "sometimes",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown synchronous mode "sometimes"`)
+}
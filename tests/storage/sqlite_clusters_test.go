@@ -0,0 +1,132 @@
+package storage_test
+
+import (
+	"context"
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	sqliteStore "github.com/oceanbase/powermem-go/pkg/storage/sqlite"
+)
+
+// clusteredVector returns a vector near the axis of group in 16 dimensions.
+func clusteredVector(rng *rand.Rand, group int) []float64 {
+	v := make([]float64, 16)
+	for i := range v {
+		v[i] = rng.Float64() * 0.1
+	}
+	v[group] += 1
+	return v
+}
+
+// axisVector returns the vector of length scale on the axis of group.
+func axisVector(group int, scale float64) []float64 {
+	v := make([]float64, 16)
+	v[group] = scale
+	return v
+}
+
+func newClusteredSQLiteClient(t *testing.T, path string, clusters int) *sqliteStore.Client {
+	store, err := sqliteStore.NewClient(&sqliteStore.Config{
+		DBPath: path, CollectionName: "memories", EmbeddingModelDims: 16, Clusters: clusters,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestSQLiteClient_ClusteredSearch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clusters.db")
+	store := newClusteredSQLiteClient(t, path, 0)
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(1))
+
+	// 8 groups of 50 memories, ID 100*group+i
+	for group := 0; group < 8; group++ {
+		for i := 1; i <= 50; i++ {
+			require.NoError(t, store.Insert(ctx, &storage.Memory{
+				ID: int64(100*group + i), UserID: "user", Content: "memory", Embedding: clusteredVector(rng, group),
+			}))
+		}
+	}
+
+	// Without centroids, searches are exact
+	stats := &storage.SearchStats{}
+	_, err := store.Search(ctx, clusteredVector(rng, 3), &storage.SearchOptions{UserID: "user", Limit: 10, Stats: stats})
+	require.NoError(t, err)
+	assert.Equal(t, int64(400), stats.Candidates)
+
+	err = store.CreateIndex(ctx, &storage.VectorIndexConfig{IndexType: storage.IndexTypeIVFFlat})
+	assert.Error(t, err, "the number of clusters is required")
+
+	require.NoError(t, store.CreateIndex(ctx, &storage.VectorIndexConfig{
+		IndexType: storage.IndexTypeIVFFlat,
+		IVFParams: &storage.IVFParams{Nlist: 8, Nprobe: 1},
+	}))
+
+	search := func(store storage.VectorStore, group int) ([]*storage.Memory, *storage.SearchStats) {
+		stats := &storage.SearchStats{}
+		results, err := store.Search(ctx, clusteredVector(rng, group), &storage.SearchOptions{UserID: "user", Limit: 10, Stats: stats})
+		require.NoError(t, err)
+		require.Len(t, results, 10)
+		return results, stats
+	}
+
+	// Only the cluster of the query is scored
+	for group := 0; group < 8; group++ {
+		results, stats := search(store, group)
+		assert.Equal(t, int64(50), stats.Candidates)
+		assert.Equal(t, int64(400), stats.TotalMemories)
+		for _, m := range results {
+			assert.Equal(t, int64(group), m.ID/100, "memory %d found for group %d", m.ID, group)
+		}
+	}
+
+	// New memories are assigned to their cluster
+	require.NoError(t, store.Insert(ctx, &storage.Memory{
+		ID: 1000, UserID: "user", Content: "memory", Embedding: axisVector(5, 5),
+	}))
+	results, err := store.Search(ctx, axisVector(5, 1), &storage.SearchOptions{UserID: "user", Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, int64(1000), results[0].ID)
+
+	// Other clients of the file use the centroids
+	other := newClusteredSQLiteClient(t, path, 0)
+	_, stats = search(other, 2)
+	assert.Less(t, stats.Candidates, int64(400))
+
+	// Updated embeddings move to their new cluster
+	_, err = other.Update(ctx, 1000, "memory", axisVector(2, 5), nil)
+	require.NoError(t, err)
+	stats = &storage.SearchStats{}
+	results, err = store.Search(ctx, axisVector(2, 1), &storage.SearchOptions{UserID: "user", Limit: 1, Stats: stats})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, int64(1000), results[0].ID)
+	assert.Equal(t, int64(51), stats.Candidates)
+}
+
+func TestSQLiteClient_AutomaticClusters(t *testing.T) {
+	store := newClusteredSQLiteClient(t, filepath.Join(t.TempDir(), "auto.db"), 2)
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(2))
+
+	// Training starts at 40 memories per cluster
+	for id := int64(1); id <= 100; id++ {
+		require.NoError(t, store.Insert(ctx, &storage.Memory{
+			ID: id, UserID: "user", Content: "memory", Embedding: clusteredVector(rng, int(id%2)),
+		}))
+	}
+
+	assert.Eventually(t, func() bool {
+		stats := &storage.SearchStats{}
+		_, err := store.Search(ctx, clusteredVector(rng, 0), &storage.SearchOptions{UserID: "user", Limit: 5, Stats: stats})
+		return err == nil && stats.Candidates == 50
+	}, 10*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,97 @@
+package storage_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	sqliteStore "github.com/oceanbase/powermem-go/pkg/storage/sqlite"
+)
+
+func newLockingSQLiteClient(path, locking string) (*sqliteStore.Client, error) {
+	return sqliteStore.NewClient(&sqliteStore.Config{
+		DBPath:             path,
+		CollectionName:     "memories",
+		EmbeddingModelDims: 3,
+		BusyTimeout:        time.Millisecond,
+		Locking:            locking,
+	})
+}
+
+func TestSQLiteClient_AdvisoryLocking(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shared.db")
+	var stores []*sqliteStore.Client
+	for i := 0; i < 2; i++ {
+		store, err := newLockingSQLiteClient(path, sqliteStore.LockingAdvisory)
+		require.NoError(t, err)
+		defer func() { _ = store.Close() }()
+		stores = append(stores, store)
+	}
+
+	// Without retries, writers racing for the database lock would fail
+	// after the 1ms busy timeout; the lock file makes them queue instead
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			store := stores[w%2]
+			for i := 0; i < 25; i++ {
+				errs <- store.Insert(context.Background(), &storage.Memory{
+					ID:        int64(w*100 + i + 1),
+					UserID:    "u1",
+					Content:   fmt.Sprintf("memory %d of writer %d", i, w),
+					Embedding: []float64{1, 0, 0},
+				})
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	memory, err := stores[0].Get(context.Background(), 325, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "memory 24 of writer 3", memory.Content)
+}
+
+func TestSQLiteClient_SingleWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "single.db")
+	writer, err := newLockingSQLiteClient(path, sqliteStore.LockingSingleWriter)
+	require.NoError(t, err)
+
+	for _, locking := range []string{sqliteStore.LockingSingleWriter, sqliteStore.LockingAdvisory} {
+		_, err := newLockingSQLiteClient(path, locking)
+		require.ErrorIs(t, err, sqliteStore.ErrWriterLocked, locking)
+		assert.Contains(t, err.Error(), fmt.Sprintf("held by process %d", os.Getpid()))
+	}
+
+	require.NoError(t, writer.Insert(context.Background(), &storage.Memory{
+		ID: 1, UserID: "u1", Content: "single writer", Embedding: []float64{1, 0, 0},
+	}))
+	require.NoError(t, writer.Close())
+
+	next, err := newLockingSQLiteClient(path, sqliteStore.LockingAdvisory)
+	require.NoError(t, err)
+	defer func() { _ = next.Close() }()
+	memory, err := next.Get(context.Background(), 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "single writer", memory.Content)
+}
+
+func TestSQLiteClient_UnknownLocking(t *testing.T) {
+	_, err := newLockingSQLiteClient(filepath.Join(t.TempDir(), "lock.db"), "mutex")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown locking mode "mutex"`)
+}
@@ -2,8 +2,10 @@ package storage_test
 
 import (
 	"context"
+	"io"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -54,6 +56,10 @@ func TestSQLiteClient_Insert(t *testing.T) {
 	err := store.Insert(ctx, memory)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(100), memory.ID)
+
+	// IDs are unique
+	err = store.Insert(ctx, &storage.Memory{ID: 100, UserID: "test_user", Content: "Duplicate", Embedding: []float64{0.1, 0.2}})
+	assert.ErrorIs(t, err, storage.ErrDuplicateID)
 }
 
 func TestSQLiteClient_Get(t *testing.T) {
@@ -85,6 +91,39 @@ func TestSQLiteClient_Get(t *testing.T) {
 	assert.Equal(t, "Test memory content", retrieved.Content)
 }
 
+func TestSQLiteClient_GetMany(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		memory := &storage.Memory{
+			ID:        int64(40 + i),
+			UserID:    "test_user",
+			Content:   "Test memory",
+			Embedding: []float64{0.1, 0.2, 0.3},
+		}
+		err := store.Insert(ctx, memory)
+		require.NoError(t, err)
+	}
+
+	// Missing IDs are skipped
+	results, err := store.GetMany(ctx, []int64{40, 42, 999}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(results))
+
+	// Access control applies to every ID
+	results, err = store.GetMany(ctx, []int64{40, 41, 42}, &storage.GetOptions{UserID: "other_user"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(results))
+
+	// Empty input does not query
+	results, err = store.GetMany(ctx, []int64{}, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
 func TestSQLiteClient_Update(t *testing.T) {
 	store, cleanup := setupSQLiteTest(t)
 	defer cleanup()
@@ -117,6 +156,42 @@ func TestSQLiteClient_Update(t *testing.T) {
 	_ = updated
 }
 
+func TestSQLiteClient_UpdateMetadata(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	memory := &storage.Memory{
+		ID:        50,
+		UserID:    "test_user",
+		Content:   "Original content",
+		Embedding: []float64{0.1, 0.2, 0.3},
+		Metadata:  map[string]interface{}{"source": "chat"},
+	}
+	err := store.Insert(ctx, memory)
+	require.NoError(t, err)
+
+	original, err := store.Get(ctx, memory.ID, nil)
+	require.NoError(t, err)
+
+	// Metadata is preserved when not provided
+	updated, err := store.Update(ctx, memory.ID, "Second content", []float64{0.2, 0.3, 0.4}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "chat", updated.Metadata["source"])
+	assert.True(t, updated.CreatedAt.Equal(original.CreatedAt))
+	assert.False(t, updated.UpdatedAt.Before(original.UpdatedAt))
+
+	// Metadata is replaced when provided
+	updated, err = store.Update(ctx, memory.ID, "Third content", []float64{0.3, 0.4, 0.5}, &storage.UpdateOptions{
+		Metadata: map[string]interface{}{"source": "settings"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "settings", updated.Metadata["source"])
+	assert.Equal(t, []float64{0.3, 0.4, 0.5}, updated.Embedding)
+	assert.True(t, updated.CreatedAt.Equal(original.CreatedAt))
+}
+
 func TestSQLiteClient_Delete(t *testing.T) {
 	store, cleanup := setupSQLiteTest(t)
 	defer cleanup()
@@ -193,6 +268,102 @@ func TestSQLiteClient_Search(t *testing.T) {
 	assert.LessOrEqual(t, len(results), 2)
 }
 
+func TestSQLiteClient_SearchIter(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// IDs 1-3 share the same embedding (tied scores), IDs 4-7 rank lower
+	embeddings := map[int64][]float64{
+		1: {1, 0, 0}, 2: {1, 0, 0}, 3: {1, 0, 0},
+		4: {0.9, 0.1, 0}, 5: {0.8, 0.2, 0}, 6: {0.7, 0.3, 0}, 7: {0, 1, 0},
+	}
+	for id := int64(1); id <= 7; id++ {
+		require.NoError(t, store.Insert(ctx, &storage.Memory{
+			ID: id, UserID: "test_user", Content: "memory", Embedding: embeddings[id],
+		}))
+	}
+
+	query := []float64{1, 0, 0}
+	all, err := store.Search(ctx, query, &storage.SearchOptions{UserID: "test_user", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, all, 7)
+
+	collect := func(opts *storage.SearchOptions, batchSize int) ([]int64, []int) {
+		iter, err := store.SearchIter(ctx, query, opts, batchSize)
+		require.NoError(t, err)
+		var ids []int64
+		var sizes []int
+		for {
+			batch, err := iter.Next(ctx)
+			if err == io.EOF {
+				return ids, sizes
+			}
+			require.NoError(t, err)
+			sizes = append(sizes, len(batch))
+			for _, m := range batch {
+				ids = append(ids, m.ID)
+			}
+		}
+	}
+
+	var expected []int64
+	for _, m := range all {
+		expected = append(expected, m.ID)
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7}, expected)
+
+	// Pages split tied scores without skipping or repeating memories
+	ids, sizes := collect(&storage.SearchOptions{UserID: "test_user"}, 2)
+	assert.Equal(t, expected, ids)
+	assert.Equal(t, []int{2, 2, 2, 1}, sizes)
+
+	// Limit caps the total across batches
+	ids, sizes = collect(&storage.SearchOptions{UserID: "test_user", Limit: 5}, 2)
+	assert.Equal(t, expected[:5], ids)
+	assert.Equal(t, []int{2, 2, 1}, sizes)
+
+	// A cursor resumes after the given position
+	ids, _ = collect(&storage.SearchOptions{
+		UserID: "test_user",
+		After:  &storage.SearchCursor{Score: all[1].Score, ID: all[1].ID},
+	}, 10)
+	assert.Equal(t, expected[2:], ids)
+
+	_, err = store.SearchIter(ctx, query, &storage.SearchOptions{}, 0)
+	assert.Error(t, err)
+}
+
+func TestSQLiteClient_SearchByKeyword(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	memories := []*storage.Memory{
+		{ID: 7, UserID: "test_user", Content: "Order ORD-2024-00417 shipped", Embedding: []float64{0.1, 0.2, 0.3}},
+		{ID: 8, UserID: "test_user", Content: "Contact me at alice@example.com", Embedding: []float64{0.1, 0.2, 0.3}},
+		{ID: 9, UserID: "other_user", Content: "Order ord-2024-00417 cancelled", Embedding: []float64{0.1, 0.2, 0.3}},
+	}
+	for _, mem := range memories {
+		err := store.Insert(ctx, mem)
+		require.NoError(t, err)
+	}
+
+	// Case-insensitive substring match scoped to a user
+	results, err := store.SearchByKeyword(ctx, "ord-2024-00417", &storage.SearchOptions{UserID: "test_user", Limit: 10})
+	assert.NoError(t, err)
+	require.Equal(t, 1, len(results))
+	assert.Equal(t, int64(7), results[0].ID)
+	assert.Equal(t, 1.0, results[0].Score)
+
+	// LIKE wildcards in the keyword are matched literally
+	results, err = store.SearchByKeyword(ctx, "%", &storage.SearchOptions{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(results))
+}
+
 func TestSQLiteClient_GetAll(t *testing.T) {
 	store, cleanup := setupSQLiteTest(t)
 	defer cleanup()
@@ -219,6 +390,373 @@ func TestSQLiteClient_GetAll(t *testing.T) {
 	results, err := store.GetAll(ctx, options)
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, len(results), 3)
+	assert.Equal(t, []float64{0.1, 0.2, 0.3}, results[0].Embedding)
+}
+
+func TestSQLiteClient_WithoutEmbeddings(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	err := store.Insert(ctx, &storage.Memory{ID: 20, UserID: "test_user", Content: "Likes tea", Embedding: []float64{0.1, 0.2, 0.3}})
+	require.NoError(t, err)
+
+	results, err := store.Search(ctx, []float64{0.1, 0.2, 0.3}, &storage.SearchOptions{UserID: "test_user", Limit: 1, WithoutEmbeddings: true})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].Embedding)
+	assert.Equal(t, "Likes tea", results[0].Content)
+	assert.Greater(t, results[0].Score, 0.99)
+
+	results, err = store.SearchByKeyword(ctx, "tea", &storage.SearchOptions{UserID: "test_user", Limit: 1, WithoutEmbeddings: true})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].Embedding)
+
+	results, err = store.GetAll(ctx, &storage.GetAllOptions{UserID: "test_user", Limit: 1, WithoutEmbeddings: true})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].Embedding)
+	assert.Equal(t, storage.ContentHash("Likes tea"), results[0].Hash)
+}
+
+func TestSQLiteClient_Fields(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	err := store.Insert(ctx, &storage.Memory{
+		ID:        21,
+		UserID:    "test_user",
+		Content:   "Likes coffee",
+		Embedding: []float64{0.1, 0.2, 0.3},
+		Metadata:  map[string]interface{}{"source": "chat", storage.RunIDKey: "run_1"},
+		Tags:      []string{"drinks"},
+	})
+	require.NoError(t, err)
+
+	fields := []storage.Field{storage.FieldContent, storage.FieldMetadata}
+	results, err := store.GetAll(ctx, &storage.GetAllOptions{UserID: "test_user", Limit: 1, Fields: fields})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Likes coffee", results[0].Content)
+	assert.Equal(t, "chat", results[0].Metadata["source"])
+	assert.Equal(t, "run_1", results[0].RunID)
+	assert.Nil(t, results[0].Embedding)
+	assert.Nil(t, results[0].Tags)
+	assert.True(t, results[0].CreatedAt.IsZero())
+	assert.Equal(t, int64(1), results[0].Version)
+
+	results, err = store.Search(ctx, []float64{0.1, 0.2, 0.3}, &storage.SearchOptions{UserID: "test_user", Limit: 1, Fields: []storage.Field{storage.FieldTags}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Empty(t, results[0].Content)
+	assert.Nil(t, results[0].Metadata)
+	assert.Equal(t, []string{"drinks"}, results[0].Tags)
+	assert.Greater(t, results[0].Score, 0.99)
+
+	// Fields are still subject to WithoutEmbeddings
+	results, err = store.SearchByKeyword(ctx, "coffee", &storage.SearchOptions{UserID: "test_user", Limit: 1, Fields: []storage.Field{storage.FieldEmbedding}, WithoutEmbeddings: true})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].Embedding)
+	assert.Equal(t, int64(21), results[0].ID)
+}
+
+func TestSQLiteClient_TimeRange(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		memory := &storage.Memory{
+			ID:        int64(30 + i),
+			UserID:    "test_user",
+			Content:   "Test memory",
+			Embedding: []float64{0.1, 0.2, 0.3},
+		}
+		err := store.Insert(ctx, memory)
+		require.NoError(t, err)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	// All memories were created within the range
+	results, err := store.GetAll(ctx, &storage.GetAllOptions{
+		Limit:     10,
+		TimeRange: &storage.TimeRange{CreatedAfter: past, CreatedBefore: future},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(results))
+
+	// No memories were created after the future bound
+	results, err = store.GetAll(ctx, &storage.GetAllOptions{
+		Limit:     10,
+		TimeRange: &storage.TimeRange{CreatedAfter: future},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(results))
+
+	// Search honors the same bounds
+	searchResults, err := store.Search(ctx, []float64{0.1, 0.2, 0.3}, &storage.SearchOptions{
+		Limit:     10,
+		TimeRange: &storage.TimeRange{UpdatedBefore: past},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(searchResults))
+}
+
+func TestSQLiteClient_Tags(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	memories := []*storage.Memory{
+		{ID: 60, UserID: "test_user", Content: "Order shipped", Embedding: []float64{0.1, 0.2, 0.3}, Tags: []string{"orders", "shipping"}},
+		{ID: 61, UserID: "test_user", Content: "Order paid", Embedding: []float64{0.1, 0.2, 0.3}, Tags: []string{"orders", "billing"}},
+		{ID: 62, UserID: "test_user", Content: "Likes tea", Embedding: []float64{0.1, 0.2, 0.3}},
+		{ID: 63, UserID: "other_user", Content: "Other", Embedding: []float64{0.1, 0.2, 0.3}, Tags: []string{"private"}},
+	}
+	for _, mem := range memories {
+		err := store.Insert(ctx, mem)
+		require.NoError(t, err)
+	}
+
+	// Tags round-trip through storage
+	got, err := store.Get(ctx, 60, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders", "shipping"}, got.Tags)
+
+	// A single tag matches every memory carrying it
+	results, err := store.GetAll(ctx, &storage.GetAllOptions{Limit: 10, Tags: []string{"orders"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(results))
+
+	// Multiple tags must all be present
+	results, err = store.GetAll(ctx, &storage.GetAllOptions{Limit: 10, Tags: []string{"orders", "billing"}})
+	assert.NoError(t, err)
+	require.Equal(t, 1, len(results))
+	assert.Equal(t, int64(61), results[0].ID)
+
+	searchResults, err := store.Search(ctx, []float64{0.1, 0.2, 0.3}, &storage.SearchOptions{Limit: 10, Tags: []string{"shipping"}})
+	assert.NoError(t, err)
+	require.Equal(t, 1, len(searchResults))
+	assert.Equal(t, int64(60), searchResults[0].ID)
+
+	// ListTags is scoped to a user
+	tags, err := store.ListTags(ctx, "test_user")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"billing", "orders", "shipping"}, tags)
+
+	tags, err = store.ListTags(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"billing", "orders", "private", "shipping"}, tags)
+}
+
+func TestSQLiteClient_GetAllFilters(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	memories := []*storage.Memory{
+		{ID: 70, UserID: "test_user", Content: "Likes tea", Embedding: []float64{0.1, 0.2, 0.3}, Metadata: map[string]interface{}{"category": "preference"}},
+		{ID: 71, UserID: "test_user", Content: "Likes coffee", Embedding: []float64{0.1, 0.2, 0.3}, Metadata: map[string]interface{}{"category": "preference"}},
+		{ID: 72, UserID: "test_user", Content: "Lives in Paris", Embedding: []float64{0.1, 0.2, 0.3}, Metadata: map[string]interface{}{"category": "fact"}},
+		{ID: 73, UserID: "other_user", Content: "Likes juice", Embedding: []float64{0.1, 0.2, 0.3}, Metadata: map[string]interface{}{"category": "preference"}},
+	}
+	for _, mem := range memories {
+		err := store.Insert(ctx, mem)
+		require.NoError(t, err)
+	}
+
+	results, err := store.GetAll(ctx, &storage.GetAllOptions{
+		UserID:  "test_user",
+		Limit:   10,
+		Filters: map[string]interface{}{"category": "preference"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(results))
+
+	// Pagination applies to the filtered set
+	results, err = store.GetAll(ctx, &storage.GetAllOptions{
+		Limit:   10,
+		Offset:  2,
+		Filters: map[string]interface{}{"category": "preference"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(results))
+
+	results, err = store.GetAll(ctx, &storage.GetAllOptions{
+		Limit:   10,
+		Filters: map[string]interface{}{"category": "unknown"},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestSQLiteClient_GetByUID(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	memories := []*storage.Memory{
+		{ID: 80, UID: "01890a5d-ac96-774b-bcce-b302099a8057", UserID: "test_user", Content: "Keyed by UUID", Embedding: []float64{0.1, 0.2, 0.3}},
+		{ID: 81, UserID: "test_user", Content: "No string ID", Embedding: []float64{0.1, 0.2, 0.3}},
+		{ID: 82, UserID: "test_user", Content: "No string ID either", Embedding: []float64{0.1, 0.2, 0.3}},
+	}
+	for _, mem := range memories {
+		err := store.Insert(ctx, mem)
+		require.NoError(t, err)
+	}
+
+	got, err := store.GetByUID(ctx, "01890a5d-ac96-774b-bcce-b302099a8057", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(80), got.ID)
+	assert.Equal(t, "01890a5d-ac96-774b-bcce-b302099a8057", got.UID)
+
+	got, err = store.Get(ctx, 81, nil)
+	require.NoError(t, err)
+	assert.Empty(t, got.UID)
+
+	// Access control applies as for Get
+	_, err = store.GetByUID(ctx, "01890a5d-ac96-774b-bcce-b302099a8057", &storage.GetOptions{UserID: "other_user"})
+	assert.Error(t, err)
+
+	// String IDs are unique
+	err = store.Insert(ctx, &storage.Memory{ID: 83, UID: "01890a5d-ac96-774b-bcce-b302099a8057", UserID: "test_user", Content: "Duplicate", Embedding: []float64{0.1, 0.2, 0.3}})
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, storage.ErrDuplicateID)
+}
+
+func TestSQLiteClient_GetByHash(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	err := store.Insert(ctx, &storage.Memory{
+		ID: 85, UserID: "test_user", AgentID: "agent", Content: "Likes tea", Embedding: []float64{0.1, 0.2, 0.3},
+		Metadata: map[string]interface{}{storage.RunIDKey: "run_1"},
+	})
+	require.NoError(t, err)
+
+	got, err := store.GetByHash(ctx, storage.ContentHash("Likes tea"), &storage.GetOptions{UserID: "test_user"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(85), got.ID)
+	assert.Equal(t, storage.ContentHash("Likes tea"), got.Hash)
+	assert.Equal(t, "run_1", got.RunID)
+
+	_, err = store.GetByHash(ctx, storage.ContentHash("Likes tea"), &storage.GetOptions{UserID: "test_user", AgentID: "other_agent"})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = store.GetByHash(ctx, storage.ContentHash("Likes coffee"), &storage.GetOptions{UserID: "test_user"})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Update records the hash of the new content
+	_, err = store.Update(ctx, 85, "Likes coffee", []float64{0.1, 0.2, 0.3}, nil)
+	require.NoError(t, err)
+	got, err = store.GetByHash(ctx, storage.ContentHash("Likes coffee"), &storage.GetOptions{UserID: "test_user"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(85), got.ID)
+	assert.Equal(t, storage.ContentHash("Likes coffee"), got.Hash)
+	_, err = store.GetByHash(ctx, storage.ContentHash("Likes tea"), nil)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestSQLiteClient_UpdateVersion(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	err := store.Insert(ctx, &storage.Memory{ID: 90, UserID: "test_user", Content: "v1", Embedding: []float64{0.1, 0.2, 0.3}})
+	require.NoError(t, err)
+
+	got, err := store.Get(ctx, 90, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), got.Version)
+
+	updated, err := store.Update(ctx, 90, "v2", []float64{0.1, 0.2, 0.3}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated.Version)
+
+	// A writer holding the stale version loses
+	_, err = store.Update(ctx, 90, "stale", []float64{0.1, 0.2, 0.3}, &storage.UpdateOptions{ExpectedVersion: 1})
+	assert.ErrorIs(t, err, storage.ErrVersionConflict)
+	got, err = store.Get(ctx, 90, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", got.Content)
+
+	updated, err = store.Update(ctx, 90, "v3", []float64{0.1, 0.2, 0.3}, &storage.UpdateOptions{ExpectedVersion: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), updated.Version)
+
+	// Missing memories are not reported as conflicts
+	_, err = store.Update(ctx, 91, "missing", []float64{0.1, 0.2, 0.3}, &storage.UpdateOptions{ExpectedVersion: 1})
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, storage.ErrVersionConflict)
+}
+
+func TestSQLiteClient_Expiration(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	memories := []*storage.Memory{
+		{ID: 70, UserID: "test_user", Content: "Expired", Embedding: []float64{0.1, 0.2, 0.3}, ExpiresAt: &past},
+		{ID: 71, UserID: "test_user", Content: "Expires later", Embedding: []float64{0.1, 0.2, 0.3}, ExpiresAt: &future},
+		{ID: 72, UserID: "test_user", Content: "Never expires", Embedding: []float64{0.1, 0.2, 0.3}},
+	}
+	for _, mem := range memories {
+		err := store.Insert(ctx, mem)
+		require.NoError(t, err)
+	}
+
+	// Expired memories are hidden from reads
+	_, err := store.Get(ctx, 70, nil)
+	assert.Error(t, err)
+
+	got, err := store.Get(ctx, 71, nil)
+	require.NoError(t, err)
+	require.NotNil(t, got.ExpiresAt)
+	assert.WithinDuration(t, future, *got.ExpiresAt, time.Second)
+
+	results, err := store.GetAll(ctx, &storage.GetAllOptions{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(results))
+
+	searchResults, err := store.Search(ctx, []float64{0.1, 0.2, 0.3}, &storage.SearchOptions{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(searchResults))
+
+	// Reads judge expiration at Now when set
+	_, err = store.Get(ctx, 70, &storage.GetOptions{Now: past.Add(-time.Minute)})
+	assert.NoError(t, err)
+	results, err = store.GetAll(ctx, &storage.GetAllOptions{Limit: 10, Now: future.Add(time.Minute)})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(results))
+	searchResults, err = store.Search(ctx, []float64{0.1, 0.2, 0.3}, &storage.SearchOptions{Limit: 10, Now: past.Add(-time.Minute)})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(searchResults))
+
+	// Purge removes only memories that have already expired
+	deleted, err := store.PurgeExpired(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	deleted, err = store.PurgeExpired(ctx, future.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
 }
 
 func TestSQLiteClient_DeleteAll(t *testing.T) {
@@ -304,3 +842,320 @@ func TestSQLiteClient_Reset(t *testing.T) {
 	assert.Equal(t, 1, len(results))
 	assert.Equal(t, "new_user", results[0].UserID)
 }
+
+func TestSQLiteClient_Ping(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, store.Ping(ctx))
+
+	require.NoError(t, store.Close())
+	assert.Error(t, store.Ping(ctx))
+}
+
+func TestSQLiteClient_Changes(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	seq, err := store.LastChangeSeq(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), seq)
+
+	changes := []*storage.Change{
+		{Type: "memory.created", MemoryID: 1, UserID: "user_001", Payload: []byte(`{"memory_id":1}`)},
+		{Type: "memory.created", MemoryID: 2, UserID: "user_002", Payload: []byte(`{"memory_id":2}`)},
+		{Type: "memory.deleted", Payload: []byte(`{}`)},
+	}
+	for _, change := range changes {
+		require.NoError(t, store.AppendChange(ctx, change))
+		assert.Greater(t, change.Seq, seq)
+		seq = change.Seq
+	}
+
+	results, err := store.ListChanges(ctx, &storage.ListChangesOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, changes[0].Seq, results[0].Seq)
+	assert.Equal(t, "memory.created", results[0].Type)
+	assert.JSONEq(t, `{"memory_id":1}`, string(results[0].Payload))
+	assert.False(t, results[0].CreatedAt.IsZero())
+
+	// Bulk changes match every user
+	results, err = store.ListChanges(ctx, &storage.ListChangesOptions{UserID: "user_002"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, int64(2), results[0].MemoryID)
+	assert.Equal(t, int64(0), results[1].MemoryID)
+
+	results, err = store.ListChanges(ctx, &storage.ListChangesOptions{After: changes[0].Seq, Types: []string{"memory.created"}, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, int64(2), results[0].MemoryID)
+
+	// The change log survives a reset
+	require.NoError(t, store.Reset(ctx))
+	last, err := store.LastChangeSeq(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, seq, last)
+
+	deleted, err := store.PurgeChanges(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	results, err = store.ListChanges(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestSQLiteClient_Audit(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	entries := []*storage.AuditEntry{
+		{Operation: "DeleteAll", Actor: "admin", UserID: "user_001", Params: []byte(`{"user_id":"user_001"}`)},
+		{Operation: "EraseUser", Actor: "admin", UserID: "user_002", Params: []byte(`{"user_id":"user_002"}`)},
+		{Operation: "Reset", Actor: "ops", Params: []byte(`{}`)},
+	}
+	var seq int64
+	for _, entry := range entries {
+		require.NoError(t, store.AppendAudit(ctx, entry))
+		assert.Greater(t, entry.Seq, seq)
+		seq = entry.Seq
+	}
+
+	results, err := store.ListAudit(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, entries[0].Seq, results[0].Seq)
+	assert.Equal(t, "DeleteAll", results[0].Operation)
+	assert.Equal(t, "admin", results[0].Actor)
+	assert.JSONEq(t, `{"user_id":"user_001"}`, string(results[0].Params))
+	assert.False(t, results[0].CreatedAt.IsZero())
+
+	results, err = store.ListAudit(ctx, &storage.ListAuditOptions{Actor: "admin", Operations: []string{"EraseUser", "Reset"}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "user_002", results[0].UserID)
+
+	results, err = store.ListAudit(ctx, &storage.ListAuditOptions{After: entries[0].Seq, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "EraseUser", results[0].Operation)
+
+	// The audit log survives a reset and an erasure
+	require.NoError(t, store.Reset(ctx))
+	_, err = store.EraseUser(ctx, "user_002")
+	require.NoError(t, err)
+	results, err = store.ListAudit(ctx, &storage.ListAuditOptions{UserID: "user_002"})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestSQLiteClient_EraseUser(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for i, userID := range []string{"user_001", "user_001", "user_002"} {
+		require.NoError(t, store.Insert(ctx, &storage.Memory{
+			ID:        int64(i + 1),
+			UserID:    userID,
+			Content:   "Test memory content",
+			Embedding: []float64{0.1, 0.2, 0.3},
+		}))
+		require.NoError(t, store.AppendChange(ctx, &storage.Change{
+			Type: "memory.created", MemoryID: int64(i + 1), UserID: userID, Payload: []byte(`{}`),
+		}))
+	}
+
+	counts, err := store.EraseUser(ctx, "user_001")
+	require.NoError(t, err)
+	assert.Equal(t, &storage.ErasureCounts{Memories: 2, Changes: 2, Remaining: 0}, counts)
+
+	results, err := store.GetAll(ctx, &storage.GetAllOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "user_002", results[0].UserID)
+
+	changes, err := store.ListChanges(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "user_002", changes[0].UserID)
+}
+
+func TestSQLiteClient_Teams(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	teamStore := store.(storage.TeamStore)
+
+	require.NoError(t, teamStore.AddTeamMember(ctx, "team_a", "user_002"))
+	require.NoError(t, teamStore.AddTeamMember(ctx, "team_a", "user_002"))
+	members, err := teamStore.ListTeamMembers(ctx, "team_a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user_002"}, members)
+
+	for i, metadata := range []map[string]interface{}{
+		{storage.TeamIDKey: "team_a"},
+		{storage.TeamIDKey: "team_b"},
+		nil,
+	} {
+		require.NoError(t, store.Insert(ctx, &storage.Memory{
+			ID:        int64(i + 1),
+			UserID:    "user_001",
+			Content:   "Test memory content",
+			Embedding: []float64{0.1, 0.2, 0.3},
+			Metadata:  metadata,
+		}))
+	}
+
+	results, err := store.GetAll(ctx, &storage.GetAllOptions{UserID: "user_002", TeamIDs: []string{"team_a"}, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, int64(1), results[0].ID)
+
+	results, err = store.Search(ctx, []float64{0.1, 0.2, 0.3}, &storage.SearchOptions{UserID: "user_002", TeamIDs: []string{"team_a", "team_b"}, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	_, err = store.EraseUser(ctx, "user_002")
+	require.NoError(t, err)
+	teams, err := teamStore.ListUserTeams(ctx, "user_002")
+	require.NoError(t, err)
+	assert.Empty(t, teams)
+}
+
+func TestSQLiteClient_DeleteWhere(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for i, memoryType := range []string{"debug", "debug", "debug", "note"} {
+		userID := "user_001"
+		if i == 2 {
+			userID = "user_002"
+		}
+		require.NoError(t, store.Insert(ctx, &storage.Memory{
+			ID:        int64(i + 1),
+			UserID:    userID,
+			Content:   "Test memory content",
+			Embedding: []float64{0.1, 0.2, 0.3},
+			Metadata:  map[string]interface{}{"type": memoryType},
+		}))
+	}
+
+	opts := &storage.DeleteWhereOptions{
+		UserID:  "user_001",
+		Filters: map[string]interface{}{"type": "debug"},
+		DryRun:  true,
+	}
+	count, err := store.DeleteWhere(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// A wrong expected count deletes nothing
+	opts.DryRun = false
+	opts.ExpectedCount = 3
+	_, err = store.DeleteWhere(ctx, opts)
+	assert.ErrorIs(t, err, storage.ErrCountMismatch)
+	results, err := store.GetAll(ctx, &storage.GetAllOptions{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, results, 4)
+
+	opts.ExpectedCount = 2
+	count, err = store.DeleteWhere(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	results, err = store.GetAll(ctx, &storage.GetAllOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	ids := []int64{results[0].ID, results[1].ID}
+	assert.ElementsMatch(t, []int64{3, 4}, ids)
+}
+
+func TestSQLiteClient_Chunks(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, memory := range []*storage.Memory{
+		{ID: 1, UserID: "user_001", Content: "Long document about coffee"},
+		{ID: 2, UserID: "user_001", Content: "Long document", ParentID: 1},
+		{ID: 3, UserID: "user_001", Content: "about coffee", ParentID: 1},
+		{ID: 4, UserID: "user_001", Content: "Short note about tea"},
+	} {
+		memory.Embedding = []float64{0.1, 0.2, 0.3}
+		require.NoError(t, store.Insert(ctx, memory))
+	}
+
+	// Chunks are only listed by their parent ID
+	results, err := store.GetAll(ctx, &storage.GetAllOptions{UserID: "user_001", Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.Zero(t, result.ParentID)
+	}
+
+	results, err = store.GetAll(ctx, &storage.GetAllOptions{ParentID: 1, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.Equal(t, int64(1), result.ParentID)
+	}
+
+	// Vector search finds the chunks, keyword search does not
+	results, err = store.Search(ctx, []float64{0.1, 0.2, 0.3}, &storage.SearchOptions{UserID: "user_001", Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, results, 4)
+	results, err = store.SearchByKeyword(ctx, "coffee", &storage.SearchOptions{UserID: "user_001", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, int64(1), results[0].ID)
+
+	// DeleteWhere counts the memories without their chunks, and deletes both
+	count, err := store.DeleteWhere(ctx, &storage.DeleteWhereOptions{UserID: "user_001", DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	require.NoError(t, store.DeleteAll(ctx, &storage.DeleteAllOptions{ParentID: 1}))
+	_, err = store.Get(ctx, 2, nil)
+	assert.Error(t, err)
+	memory, err := store.Get(ctx, 1, nil)
+	require.NoError(t, err)
+	assert.Zero(t, memory.ParentID)
+}
+
+func TestSQLiteClient_Collections(t *testing.T) {
+	store, cleanup := setupSQLiteTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	manager, ok := store.(storage.CollectionManager)
+	require.True(t, ok)
+
+	other, err := sqliteStore.NewClient(&sqliteStore.Config{
+		DBPath:             "./test_powermem.db",
+		CollectionName:     "shop",
+		EmbeddingModelDims: 1536,
+	})
+	require.NoError(t, err)
+	require.NoError(t, other.Close())
+
+	names, err := manager.ListCollections(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"memories", "shop"}, names)
+
+	require.NoError(t, manager.DropCollection(ctx, "shop"))
+	names, err = manager.ListCollections(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"memories"}, names)
+}
@@ -0,0 +1,161 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	sqliteStore "github.com/oceanbase/powermem-go/pkg/storage/sqlite"
+)
+
+func newCachedSQLiteClient(t *testing.T, path string, cache bool) *sqliteStore.Client {
+	store, err := sqliteStore.NewClient(&sqliteStore.Config{
+		DBPath: path, CollectionName: "memories", EmbeddingModelDims: 16, VectorCache: cache,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+// searchIDs returns the IDs and scores of the results of a search.
+func searchIDs(t *testing.T, store storage.VectorStore, query []float64, userID string) ([]int64, []float64) {
+	results, err := store.Search(context.Background(), query, &storage.SearchOptions{UserID: userID, Limit: 10})
+	require.NoError(t, err)
+	ids := make([]int64, len(results))
+	scores := make([]float64, len(results))
+	for i, m := range results {
+		ids[i], scores[i] = m.ID, m.Score
+	}
+	return ids, scores
+}
+
+func TestSQLiteClient_VectorCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	cached := newCachedSQLiteClient(t, path, true)
+	uncached := newCachedSQLiteClient(t, path, false)
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(3))
+
+	// Enough memories to grow the file
+	for id := int64(1); id <= 2000; id++ {
+		user := "alice"
+		if id%2 == 0 {
+			user = "bob"
+		}
+		require.NoError(t, uncached.Insert(ctx, &storage.Memory{
+			ID: id, UserID: user, Content: "memory", Embedding: clusteredVector(rng, int(id%16)),
+		}))
+	}
+	assertSameResults := func() {
+		t.Helper()
+		for group := 0; group < 4; group++ {
+			query := clusteredVector(rng, group)
+			for _, user := range []string{"alice", "bob"} {
+				wantIDs, wantScores := searchIDs(t, uncached, query, user)
+				ids, scores := searchIDs(t, cached, query, user)
+				require.Equal(t, wantIDs, ids)
+				assert.InDeltaSlice(t, wantScores, scores, 1e-6)
+			}
+		}
+	}
+	assertSameResults()
+
+	// The embeddings were copied to the file in float32
+	info, err := os.Stat(path + ".memories.vectors")
+	require.NoError(t, err)
+	assert.Greater(t, info.Size(), int64(2000*16*4))
+
+	// Changes of other clients are applied before searches
+	require.NoError(t, uncached.Insert(ctx, &storage.Memory{
+		ID: 5000, UserID: "alice", Content: "memory", Embedding: axisVector(7, 1),
+	}))
+	ids, _ := searchIDs(t, cached, axisVector(7, 1), "alice")
+	assert.Equal(t, int64(5000), ids[0])
+
+	_, err = uncached.Update(ctx, 5000, "memory", axisVector(9, 1), nil)
+	require.NoError(t, err)
+	ids, _ = searchIDs(t, cached, axisVector(9, 1), "alice")
+	assert.Equal(t, int64(5000), ids[0])
+
+	require.NoError(t, uncached.Delete(ctx, 5000, nil))
+	ids, _ = searchIDs(t, cached, axisVector(9, 1), "alice")
+	assert.NotContains(t, ids, int64(5000))
+
+	// Including writes bypassing the store
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("DELETE FROM memories WHERE id % 3 = 0")
+	require.NoError(t, err)
+	assertSameResults()
+
+	// The file of a closed client is reused, and rebuilt after a reset
+	require.NoError(t, cached.Close())
+	reopened := newCachedSQLiteClient(t, path, true)
+	cached = reopened
+	assertSameResults()
+
+	require.NoError(t, reopened.Reset(ctx))
+	require.NoError(t, reopened.Insert(ctx, &storage.Memory{
+		ID: 1, UserID: "alice", Content: "memory", Embedding: axisVector(2, 1),
+	}))
+	ids, _ = searchIDs(t, reopened, axisVector(2, 1), "alice")
+	assert.Equal(t, []int64{1}, ids)
+}
+
+func TestSQLiteClient_VectorCacheHeld(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "held.db")
+	first := newCachedSQLiteClient(t, path, true)
+	second := newCachedSQLiteClient(t, path, true)
+	ctx := context.Background()
+
+	require.NoError(t, first.Insert(ctx, &storage.Memory{
+		ID: 1, UserID: "user", Content: "memory", Embedding: axisVector(1, 1),
+	}))
+
+	// The second client searches without the file held by the first
+	for _, store := range []*sqliteStore.Client{first, second} {
+		ids, _ := searchIDs(t, store, axisVector(1, 1), "user")
+		assert.Equal(t, []int64{1}, ids)
+	}
+}
+
+func TestSQLiteClient_VectorCacheRestoreSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	store := newCachedSQLiteClient(t, filepath.Join(dir, "cache.db"), true)
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(5))
+
+	for id := int64(1); id <= 100; id++ {
+		require.NoError(t, store.Insert(ctx, &storage.Memory{
+			ID: id, UserID: "alice", Content: "memory", Embedding: clusteredVector(rng, int(id%4)),
+		}))
+	}
+	query := clusteredVector(rng, 1)
+	before, _ := searchIDs(t, store, query, "alice")
+
+	path := filepath.Join(dir, "cache.snapshot")
+	require.NoError(t, store.Snapshot(ctx, path))
+	require.NoError(t, store.DeleteAll(ctx, &storage.DeleteAllOptions{UserID: "alice"}))
+	ids, _ := searchIDs(t, store, query, "alice")
+	assert.Empty(t, ids)
+
+	require.NoError(t, store.RestoreSnapshot(ctx, path))
+	after, _ := searchIDs(t, store, query, "alice")
+	assert.Equal(t, before, after)
+
+	// A snapshot of another collection cannot be restored
+	other, err := sqliteStore.NewClient(&sqliteStore.Config{
+		DBPath: filepath.Join(dir, "other.db"), CollectionName: "other", EmbeddingModelDims: 16,
+	})
+	require.NoError(t, err)
+	defer other.Close()
+	assert.Error(t, other.RestoreSnapshot(ctx, path))
+}
@@ -0,0 +1,185 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/storage"
+	sqliteStore "github.com/oceanbase/powermem-go/pkg/storage/sqlite"
+)
+
+func TestSQLiteClient_LegacyHashes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy_hash.db")
+	ctx := context.Background()
+
+	store, err := sqliteStore.NewClient(&sqliteStore.Config{DBPath: path, CollectionName: "memories", EmbeddingModelDims: 3})
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	// A row written by a version that did not record hashes
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO memories (id, user_id, agent_id, content, embedding, metadata) VALUES (3, 'user', '', 'Likes tea', '[1,0,0]', '{}')`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	// The store hashes it when it opens the database
+	store, err = sqliteStore.NewClient(&sqliteStore.Config{DBPath: path, CollectionName: "memories", EmbeddingModelDims: 3})
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+
+	legacy, err := store.GetByHash(ctx, storage.ContentHash("Likes tea"), &storage.GetOptions{UserID: "user"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), legacy.ID)
+	assert.Equal(t, storage.ContentHash("Likes tea"), legacy.Hash)
+}
+
+func TestSQLiteClient_LegacyJSONEmbeddings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	ctx := context.Background()
+
+	store, err := sqliteStore.NewClient(&sqliteStore.Config{DBPath: path, CollectionName: "memories", EmbeddingModelDims: 3})
+	require.NoError(t, err)
+	require.NoError(t, store.Insert(ctx, &storage.Memory{
+		ID: 1, UserID: "user", Content: "binary", Embedding: []float64{0, 1, 0},
+	}))
+	require.NoError(t, store.Close())
+
+	// A row written by a version that stored embeddings as JSON arrays
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO memories (id, user_id, agent_id, content, embedding, metadata) VALUES (2, 'user', '', 'json', '[1,0,0]', '{}')`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	store, err = sqliteStore.NewClient(&sqliteStore.Config{DBPath: path, CollectionName: "memories", EmbeddingModelDims: 3})
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+
+	legacy, err := store.Get(ctx, 2, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1, 0, 0}, legacy.Embedding)
+
+	results, err := store.Search(ctx, []float64{1, 0.1, 0}, &storage.SearchOptions{UserID: "user", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, int64(2), results[0].ID)
+	assert.Equal(t, int64(1), results[1].ID)
+
+	// Updating a legacy row rewrites its embedding in binary
+	_, err = store.Update(ctx, 2, "json", []float64{0, 0, 1}, nil)
+	require.NoError(t, err)
+	updated, err := store.Get(ctx, 2, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0, 0, 1}, updated.Embedding)
+}
+
+func TestSQLiteClient_SearchTopK(t *testing.T) {
+	store, err := sqliteStore.NewClient(&sqliteStore.Config{
+		DBPath:             filepath.Join(t.TempDir(), "topk.db"),
+		CollectionName:     "memories",
+		EmbeddingModelDims: 2,
+	})
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+	ctx := context.Background()
+
+	// Memory i is at angle i/20 degrees from the query; every tenth one is
+	// a duplicate of the previous, so scores tie
+	const n = 1200
+	for id := int64(1); id <= n; id++ {
+		angle := float64(id) * math.Pi / 180 / 20
+		if id%10 == 0 {
+			angle = float64(id-1) * math.Pi / 180 / 20
+		}
+		require.NoError(t, store.Insert(ctx, &storage.Memory{
+			ID: id, UserID: "user", Content: "memory", Embedding: []float64{math.Cos(angle), math.Sin(angle)},
+		}))
+	}
+
+	all, err := store.Search(ctx, []float64{1, 0}, &storage.SearchOptions{UserID: "user"})
+	require.NoError(t, err)
+	require.Equal(t, n, len(all))
+	for i := 1; i < len(all); i++ {
+		prev, cur := all[i-1], all[i]
+		require.True(t, prev.Score > cur.Score || (prev.Score == cur.Score && prev.ID < cur.ID),
+			"results %d and %d out of order", prev.ID, cur.ID)
+	}
+
+	top, err := store.Search(ctx, []float64{1, 0}, &storage.SearchOptions{UserID: "user", Limit: 25})
+	require.NoError(t, err)
+	assert.Equal(t, all[:25], top)
+
+	stats := &storage.SearchStats{}
+	_, err = store.Search(ctx, []float64{1, 0}, &storage.SearchOptions{UserID: "user", Limit: 5, MinScore: all[99].Score, Stats: stats})
+	require.NoError(t, err)
+	assert.Equal(t, int64(n), stats.Candidates)
+	assert.GreaterOrEqual(t, stats.AboveThreshold, int64(100))
+}
+
+func TestSQLiteClient_ParallelSearch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "parallel.db")
+	newStore := func(workers int) *sqliteStore.Client {
+		store, err := sqliteStore.NewClient(&sqliteStore.Config{
+			DBPath: path, CollectionName: "memories", EmbeddingModelDims: 64, SearchWorkers: workers,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = store.Close() })
+		return store
+	}
+	ctx := context.Background()
+
+	// Enough rows for several batches per worker
+	parallel := newStore(4)
+	rng := rand.New(rand.NewSource(1))
+	vector := func() []float64 {
+		v := make([]float64, 64)
+		for i := range v {
+			v[i] = rng.Float64()*2 - 1
+		}
+		return v
+	}
+	for id := int64(1); id <= 3000; id++ {
+		require.NoError(t, parallel.Insert(ctx, &storage.Memory{ID: id, UserID: "user", Content: "memory", Embedding: vector()}))
+	}
+	sequential := newStore(1)
+
+	query := vector()
+	for _, opts := range []storage.SearchOptions{
+		{UserID: "user", Limit: 10},
+		{UserID: "user", Limit: 300, MinScore: 0.1},
+		{UserID: "user"},
+	} {
+		parallelStats, sequentialStats := &storage.SearchStats{}, &storage.SearchStats{}
+		parallelOpts, sequentialOpts := opts, opts
+		parallelOpts.Stats, sequentialOpts.Stats = parallelStats, sequentialStats
+
+		want, err := sequential.Search(ctx, query, &sequentialOpts)
+		require.NoError(t, err)
+		got, err := parallel.Search(ctx, query, &parallelOpts)
+		require.NoError(t, err)
+		require.NotEmpty(t, want)
+		assert.Equal(t, want, got)
+		assert.Equal(t, sequentialStats, parallelStats)
+		assert.Equal(t, int64(3000), parallelStats.Candidates)
+	}
+
+	// Scores match the float64 cosine similarity closely
+	results, err := parallel.Search(ctx, query, &storage.SearchOptions{UserID: "user", Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	var dot, normA, normB float64
+	for i := range query {
+		dot += query[i] * results[0].Embedding[i]
+		normA += query[i] * query[i]
+		normB += results[0].Embedding[i] * results[0].Embedding[i]
+	}
+	assert.InDelta(t, dot/math.Sqrt(normA*normB), results[0].Score, 1e-5)
+}
@@ -0,0 +1,117 @@
+package usermemory_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	usermemory "github.com/oceanbase/powermem-go/pkg/user_memory"
+	usermemoryPostgres "github.com/oceanbase/powermem-go/pkg/user_memory/postgres"
+	usermemorySQLite "github.com/oceanbase/powermem-go/pkg/user_memory/sqlite"
+)
+
+func TestLoadConfigFromFile(t *testing.T) {
+	t.Setenv("TEST_LLM_API_KEY", "sk-test")
+	t.Setenv("TEST_PROFILE_DIR", "/data")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+llm:
+  provider: openai
+  api_key: ${TEST_LLM_API_KEY}
+vector_store:
+  provider: sqlite
+  config:
+    db_path: ./memories.db
+user_memory:
+  profile_store:
+    provider: sqlite
+    config:
+      db_path: ${TEST_PROFILE_DIR}/profiles.db
+      table_name: profiles
+  async_profile_extraction: true
+  profile_queue_size: 50
+  profile_retry_backoff: 2s
+  profile_merge_mode: structured
+  query_rewrite:
+    enabled: true
+    cache_size: 128
+    timeout: 300ms
+`), 0o600))
+
+	config, err := usermemory.LoadConfigFromFile(path)
+	require.NoError(t, err)
+
+	require.NotNil(t, config.MemoryConfig)
+	assert.Equal(t, "sk-test", config.MemoryConfig.LLM.APIKey)
+	require.NotNil(t, config.MemoryConfig.VectorStore.SQLite)
+	assert.Equal(t, "./memories.db", config.MemoryConfig.VectorStore.SQLite.DBPath)
+
+	assert.Equal(t, "sqlite", config.ProfileStoreType)
+	assert.Equal(t, &usermemorySQLite.Config{DBPath: "/data/profiles.db", TableName: "profiles"}, config.ProfileStoreConfig)
+	assert.True(t, config.AsyncProfileExtraction)
+	assert.Equal(t, 50, config.ProfileQueueSize)
+	assert.Equal(t, 2*time.Second, config.ProfileRetryBackoff)
+	assert.Equal(t, usermemory.ProfileMergeStructured, config.ProfileMergeMode)
+
+	require.NotNil(t, config.QueryRewriteConfig)
+	assert.True(t, config.QueryRewriteConfig.Enabled)
+	assert.Equal(t, 128, config.QueryRewriteConfig.CacheSize)
+	assert.Equal(t, 300*time.Millisecond, config.QueryRewriteConfig.Timeout)
+}
+
+func TestLoadConfigFromFile_TOMLPostgresProfileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+[llm]
+provider = "openai"
+
+[user_memory.profile_store]
+provider = "postgres"
+config = { host = "pg", port = 5433, ssl_mode = "require" }
+`), 0o600))
+
+	config, err := usermemory.LoadConfigFromFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "postgres", config.ProfileStoreType)
+	assert.Equal(t, &usermemoryPostgres.Config{Host: "pg", Port: 5433, SSLMode: "require"}, config.ProfileStoreConfig)
+	assert.Nil(t, config.QueryRewriteConfig)
+}
+
+func TestLoadConfigFromFile_InvalidUserMemorySection(t *testing.T) {
+	tests := []struct {
+		name     string
+		section  string
+		contains string
+	}{
+		{
+			name:     "unknown profile store",
+			section:  "  profile_store:\n    provider: redis\n",
+			contains: "user_memory.profile_store.provider",
+		},
+		{
+			name:     "invalid duration",
+			section:  "  profile_store:\n    provider: sqlite\n  profile_retry_backoff: soon\n",
+			contains: "user_memory.profile_retry_backoff",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(path, []byte("user_memory:\n"+tt.section), 0o600))
+
+			_, err := usermemory.LoadConfigFromFile(path)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, core.ErrInvalidConfig), "got %v", err)
+			assert.Contains(t, err.Error(), tt.contains)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package usermemory_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	usermemoryPostgres "github.com/oceanbase/powermem-go/pkg/user_memory/postgres"
+)
+
+func setupPostgresProfileStore(t *testing.T) *usermemoryPostgres.Store {
+	// Load .env file from project root
+	_ = godotenv.Load(filepath.Join("..", "..", ".env"))
+
+	password := os.Getenv("POSTGRES_PASSWORD")
+	if password == "" {
+		t.Skip("Skipping PostgreSQL profile store test: POSTGRES_PASSWORD not set")
+	}
+	port, err := strconv.Atoi(getEnvOrDefault("POSTGRES_PORT", "5432"))
+	if err != nil {
+		t.Skipf("Skipping PostgreSQL profile store test: invalid POSTGRES_PORT: %v", err)
+	}
+
+	store, err := usermemoryPostgres.NewStore(&usermemoryPostgres.Config{
+		Host:      getEnvOrDefault("POSTGRES_HOST", "127.0.0.1"),
+		Port:      port,
+		User:      getEnvOrDefault("POSTGRES_USER", "postgres"),
+		Password:  password,
+		DBName:    getEnvOrDefault("POSTGRES_DATABASE", "powermem_test"),
+		TableName: "test_user_profiles",
+	})
+	if err != nil {
+		t.Skipf("Skipping PostgreSQL profile store test: failed to connect: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestPostgresProfileStore(t *testing.T) {
+	store := setupPostgresProfileStore(t)
+	ctx := context.Background()
+
+	// Remove leftovers from previous runs
+	if existing, _ := store.GetProfileByUserID(ctx, "pg_user_001"); existing != nil {
+		_ = store.DeleteProfile(ctx, existing.ID)
+	}
+
+	content := "Alice is a software engineer"
+	id, err := store.SaveProfile(ctx, "pg_user_001", &content, map[string]interface{}{"job": "engineer"})
+	require.NoError(t, err)
+
+	// Saving again updates the same profile
+	updated := "Alice is a senior software engineer"
+	updatedID, err := store.SaveProfile(ctx, "pg_user_001", &updated, nil)
+	require.NoError(t, err)
+	assert.Equal(t, id, updatedID)
+
+	profile, err := store.GetProfileByUserID(ctx, "pg_user_001")
+	require.NoError(t, err)
+	require.NotNil(t, profile)
+	assert.Equal(t, updated, profile.ProfileContent)
+	assert.Empty(t, profile.Topics)
+
+	profiles, err := store.GetProfiles(ctx, &usermemoryPostgres.GetProfilesOptions{UserID: "pg_user_001", Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, profiles, 1)
+
+	profiles, err = store.GetProfilesByUserIDs(ctx, []string{"pg_user_001", "pg_user_missing"})
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Equal(t, "pg_user_001", profiles[0].UserID)
+
+	require.NoError(t, store.DeleteProfile(ctx, id))
+
+	_, err = store.SaveProfile(ctx, "pg_user_001", &content, nil)
+	require.NoError(t, err)
+	deleted, err := store.DeleteAllProfiles(ctx, &usermemoryPostgres.ProfileFilter{UserIDs: []string{"pg_user_001"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	profile, err = store.GetProfileByUserID(ctx, "pg_user_001")
+	require.NoError(t, err)
+	assert.Nil(t, profile)
+}
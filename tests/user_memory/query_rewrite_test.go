@@ -3,12 +3,15 @@ package usermemory_test
 import (
 	"context"
 	"os"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/oceanbase/powermem-go/pkg/core"
+	"github.com/oceanbase/powermem-go/pkg/llm"
 	usermemory "github.com/oceanbase/powermem-go/pkg/user_memory"
 	queryrewrite "github.com/oceanbase/powermem-go/pkg/user_memory/query_rewrite"
 	usermemorySQLite "github.com/oceanbase/powermem-go/pkg/user_memory/sqlite"
@@ -29,10 +32,10 @@ func setupUserMemoryTestWithQueryRewrite(t *testing.T) (*usermemory.Client, *cor
 		memoryConfig = &core.Config{
 			VectorStore: core.VectorStoreConfig{
 				Provider: "sqlite",
-				Config: map[string]interface{}{
-					"db_path":              testDBPath,
-					"collection_name":      "memories",
-					"embedding_model_dims": 1536,
+				SQLite: &core.SQLiteConfig{
+					DBPath:             testDBPath,
+					CollectionName:     "memories",
+					EmbeddingModelDims: 1536,
 				},
 			},
 			LLM: core.LLMConfig{
@@ -51,14 +54,16 @@ func setupUserMemoryTestWithQueryRewrite(t *testing.T) (*usermemory.Client, *cor
 		}
 	} else {
 		if memoryConfig.VectorStore.Provider == "sqlite" {
-			if memoryConfig.VectorStore.Config == nil {
-				memoryConfig.VectorStore.Config = make(map[string]interface{})
+			if memoryConfig.VectorStore.SQLite == nil {
+				memoryConfig.VectorStore.SQLite = &core.SQLiteConfig{}
 			}
-			memoryConfig.VectorStore.Config["db_path"] = testDBPath
-			memoryConfig.VectorStore.Config["collection_name"] = "memories"
+			memoryConfig.VectorStore.SQLite.DBPath = testDBPath
+			memoryConfig.VectorStore.SQLite.CollectionName = "memories"
 		}
 	}
 
+	useMockProviders(memoryConfig)
+
 	// Create UserMemory config with query rewrite enabled
 	userMemoryConfig := &usermemory.Config{
 		MemoryConfig:     memoryConfig,
@@ -137,10 +142,10 @@ func TestQueryRewrite_Disabled(t *testing.T) {
 		memoryConfig = &core.Config{
 			VectorStore: core.VectorStoreConfig{
 				Provider: "sqlite",
-				Config: map[string]interface{}{
-					"db_path":              testDBPath,
-					"collection_name":      "memories",
-					"embedding_model_dims": 1536,
+				SQLite: &core.SQLiteConfig{
+					DBPath:             testDBPath,
+					CollectionName:     "memories",
+					EmbeddingModelDims: 1536,
 				},
 			},
 			LLM: core.LLMConfig{
@@ -157,13 +162,15 @@ func TestQueryRewrite_Disabled(t *testing.T) {
 		}
 	} else {
 		if memoryConfig.VectorStore.Provider == "sqlite" {
-			if memoryConfig.VectorStore.Config == nil {
-				memoryConfig.VectorStore.Config = make(map[string]interface{})
+			if memoryConfig.VectorStore.SQLite == nil {
+				memoryConfig.VectorStore.SQLite = &core.SQLiteConfig{}
 			}
-			memoryConfig.VectorStore.Config["db_path"] = testDBPath
+			memoryConfig.VectorStore.SQLite.DBPath = testDBPath
 		}
 	}
 
+	useMockProviders(memoryConfig)
+
 	// Create UserMemory config WITHOUT query rewrite
 	userMemoryConfig := &usermemory.Config{
 		MemoryConfig:     memoryConfig,
@@ -329,10 +336,10 @@ func TestQueryRewrite_CustomInstructions(t *testing.T) {
 		memoryConfig = &core.Config{
 			VectorStore: core.VectorStoreConfig{
 				Provider: "sqlite",
-				Config: map[string]interface{}{
-					"db_path":              testDBPath,
-					"collection_name":      "memories",
-					"embedding_model_dims": 1536,
+				SQLite: &core.SQLiteConfig{
+					DBPath:             testDBPath,
+					CollectionName:     "memories",
+					EmbeddingModelDims: 1536,
 				},
 			},
 			LLM: core.LLMConfig{
@@ -349,13 +356,15 @@ func TestQueryRewrite_CustomInstructions(t *testing.T) {
 		}
 	} else {
 		if memoryConfig.VectorStore.Provider == "sqlite" {
-			if memoryConfig.VectorStore.Config == nil {
-				memoryConfig.VectorStore.Config = make(map[string]interface{})
+			if memoryConfig.VectorStore.SQLite == nil {
+				memoryConfig.VectorStore.SQLite = &core.SQLiteConfig{}
 			}
-			memoryConfig.VectorStore.Config["db_path"] = testDBPath
+			memoryConfig.VectorStore.SQLite.DBPath = testDBPath
 		}
 	}
 
+	useMockProviders(memoryConfig)
+
 	// Create UserMemory config with custom instructions
 	userMemoryConfig := &usermemory.Config{
 		MemoryConfig:     memoryConfig,
@@ -404,3 +413,140 @@ func TestQueryRewrite_CustomInstructions(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, searchResult)
 }
+
+func TestQueryRewrite_ResultSurfaced(t *testing.T) {
+	client, chatCalls := setupOfflineUserMemoryTest(t, 0, func(cfg *usermemory.Config) {
+		cfg.QueryRewriteConfig = &queryrewrite.Config{Enabled: true}
+	},
+		"Alice is a Go developer.",
+		"Alice's Go projects",
+	)
+	ctx := context.Background()
+
+	_, err := client.Add(ctx, "I'm Alice, I write Go.", usermemory.WithUserID("user_001"), usermemory.WithInfer(false))
+	require.NoError(t, err)
+
+	result, err := client.Search(ctx, "my projects",
+		usermemory.WithSearchUserID("user_001"),
+		usermemory.WithSearchDiagnostics(true),
+		usermemory.WithSearchExplain(true),
+	)
+	require.NoError(t, err)
+	assert.True(t, result.QueryRewritten)
+	assert.Equal(t, "Alice's Go projects", result.RewrittenQuery)
+	assert.Equal(t, int32(2), atomic.LoadInt32(chatCalls))
+	require.NotNil(t, result.Diagnostics)
+	assert.Equal(t, "my projects", result.Diagnostics.Query)
+	assert.True(t, result.Diagnostics.QueryRewritten)
+	assert.Equal(t, "Alice's Go projects", result.Diagnostics.RewrittenQuery)
+	assert.Greater(t, result.Diagnostics.RewriteTime, time.Duration(0))
+	assert.Equal(t, int64(1), result.Diagnostics.Candidates)
+	require.NotEmpty(t, result.Memories)
+	for _, memory := range result.Memories {
+		require.NotNil(t, memory.Explanation)
+		assert.Equal(t, "my projects", memory.Explanation.OriginalQuery)
+		assert.Equal(t, "Alice's Go projects", memory.Explanation.Query)
+	}
+
+	// Disabling rewrite per call skips the LLM
+	result, err = client.Search(ctx, "my projects",
+		usermemory.WithSearchUserID("user_001"),
+		usermemory.WithDisableQueryRewrite(true),
+	)
+	require.NoError(t, err)
+	assert.False(t, result.QueryRewritten)
+	assert.Equal(t, "my projects", result.RewrittenQuery)
+	assert.Equal(t, int32(2), atomic.LoadInt32(chatCalls))
+}
+
+// countingLLM answers every request with response after delay and counts calls.
+type countingLLM struct {
+	response string
+	delay    time.Duration
+	calls    int32
+}
+
+func (s *countingLLM) Generate(ctx context.Context, prompt string, opts ...llm.GenerateOption) (string, error) {
+	return s.GenerateWithMessages(ctx, nil, opts...)
+}
+
+func (s *countingLLM) GenerateWithMessages(ctx context.Context, messages []llm.Message, opts ...llm.GenerateOption) (string, error) {
+	atomic.AddInt32(&s.calls, 1)
+	select {
+	case <-time.After(s.delay):
+		return s.response, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (s *countingLLM) Close() error {
+	return nil
+}
+
+func TestQueryRewrite_CacheAndBudget(t *testing.T) {
+	ctx := context.Background()
+	stub := &countingLLM{response: "Alice's Go projects"}
+	rewriter := queryrewrite.NewQueryRewriter(stub, &queryrewrite.Config{
+		Enabled:              true,
+		CacheSize:            1,
+		MaxRewritesPerMinute: 2,
+	})
+
+	result := rewriter.RewriteForUser(ctx, "user_001", "my projects", "Alice writes Go.")
+	assert.True(t, result.IsRewritten)
+	assert.Equal(t, "Alice's Go projects", result.RewrittenQuery)
+
+	// Same user, query and profile is served from the cache
+	result = rewriter.RewriteForUser(ctx, "user_001", "my projects", "Alice writes Go.")
+	assert.True(t, result.IsRewritten)
+	assert.Equal(t, true, result.Metadata["cache_hit"])
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stub.calls))
+
+	// A profile change misses the cache and uses the second budget slot
+	result = rewriter.RewriteForUser(ctx, "user_001", "my projects", "Alice writes Go and Rust.")
+	assert.Nil(t, result.Metadata["cache_hit"])
+	assert.Equal(t, int32(2), atomic.LoadInt32(&stub.calls))
+
+	// The budget is exhausted: the original query is used without an LLM call
+	result = rewriter.RewriteForUser(ctx, "user_002", "my projects", "Bob writes Java.")
+	assert.False(t, result.IsRewritten)
+	assert.Equal(t, "my projects", result.RewrittenQuery)
+	assert.Equal(t, true, result.Metadata["budget_exceeded"])
+	assert.Equal(t, int32(2), atomic.LoadInt32(&stub.calls))
+}
+
+func TestQueryRewrite_ForgetUser(t *testing.T) {
+	ctx := context.Background()
+	stub := &countingLLM{response: "Alice's Go projects"}
+	rewriter := queryrewrite.NewQueryRewriter(stub, &queryrewrite.Config{Enabled: true, CacheSize: 10})
+
+	rewriter.RewriteForUser(ctx, "user_001", "my projects", "Alice writes Go.")
+	rewriter.RewriteForUser(ctx, "user_001", "my languages", "Alice writes Go.")
+	rewriter.RewriteForUser(ctx, "user_002", "my projects", "Bob writes Java.")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&stub.calls))
+
+	assert.Equal(t, 2, rewriter.ForgetUser("user_001"))
+	assert.Equal(t, 0, rewriter.ForgetUser("user_001"))
+
+	// The forgotten rewrites miss the cache, the others still hit it
+	result := rewriter.RewriteForUser(ctx, "user_001", "my projects", "Alice writes Go.")
+	assert.Nil(t, result.Metadata["cache_hit"])
+	result = rewriter.RewriteForUser(ctx, "user_002", "my projects", "Bob writes Java.")
+	assert.Equal(t, true, result.Metadata["cache_hit"])
+	assert.Equal(t, int32(4), atomic.LoadInt32(&stub.calls))
+}
+
+func TestQueryRewrite_Timeout(t *testing.T) {
+	stub := &countingLLM{response: "Alice's Go projects", delay: time.Second}
+	rewriter := queryrewrite.NewQueryRewriter(stub, &queryrewrite.Config{
+		Enabled: true,
+		Timeout: 10 * time.Millisecond,
+	})
+
+	result := rewriter.Rewrite(context.Background(), "my projects", "Alice writes Go.")
+	assert.False(t, result.IsRewritten)
+	assert.Equal(t, "my projects", result.RewrittenQuery)
+	assert.Equal(t, true, result.Metadata["timed_out"])
+	require.NotNil(t, result.Error)
+}
@@ -0,0 +1,65 @@
+package usermemory_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	usermemory "github.com/oceanbase/powermem-go/pkg/user_memory"
+	queryrewrite "github.com/oceanbase/powermem-go/pkg/user_memory/query_rewrite"
+	usermemorySQLite "github.com/oceanbase/powermem-go/pkg/user_memory/sqlite"
+)
+
+func TestUserMemory_Reload(t *testing.T) {
+	var cfg *usermemory.Config
+	client, chatCalls := setupOfflineUserMemoryTest(t, 0, func(c *usermemory.Config) {
+		cfg = c
+	},
+		"Alice is a Go developer.",
+		"Alice's Go projects",
+	)
+	ctx := context.Background()
+
+	_, err := client.Add(ctx, "I'm Alice, I write Go.", usermemory.WithUserID("user_001"), usermemory.WithInfer(false))
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(chatCalls))
+
+	result, err := client.Search(ctx, "my projects", usermemory.WithSearchUserID("user_001"))
+	require.NoError(t, err)
+	assert.False(t, result.QueryRewritten)
+
+	// Enable query rewrite with a budget of one rewrite per minute
+	reloaded := *cfg
+	reloaded.QueryRewriteConfig = &queryrewrite.Config{Enabled: true, MaxRewritesPerMinute: 1}
+	reloaded.ProfileMergeMode = usermemory.ProfileMergeStructured
+	require.NoError(t, client.Reload(ctx, &reloaded))
+
+	result, err = client.Search(ctx, "my projects", usermemory.WithSearchUserID("user_001"))
+	require.NoError(t, err)
+	assert.True(t, result.QueryRewritten)
+	assert.Equal(t, int32(2), atomic.LoadInt32(chatCalls))
+
+	result, err = client.Search(ctx, "my other projects", usermemory.WithSearchUserID("user_001"))
+	require.NoError(t, err)
+	assert.False(t, result.QueryRewritten, "over budget queries are searched unchanged")
+	assert.Equal(t, int32(2), atomic.LoadInt32(chatCalls))
+
+	// The profile store cannot change at runtime
+	moved := reloaded
+	moved.ProfileStoreConfig = &usermemorySQLite.Config{DBPath: "./elsewhere.db"}
+	moved.AsyncProfileExtraction = true
+	err = client.Reload(ctx, &moved)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, core.ErrInvalidConfig))
+	assert.Contains(t, err.Error(), "user_memory.profile_store")
+	assert.Contains(t, err.Error(), "user_memory.async_profile_extraction")
+
+	invalid := reloaded
+	invalid.ProfileMergeMode = "append"
+	assert.Error(t, client.Reload(ctx, &invalid))
+}
@@ -0,0 +1,38 @@
+package usermemory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	usermemory "github.com/oceanbase/powermem-go/pkg/user_memory"
+)
+
+func TestUserMemory_GenerateUserReport(t *testing.T) {
+	client, cfg, cleanup := setupUserMemoryTest(t)
+	defer cleanup()
+
+	if !hasLLMConfig(cfg) {
+		t.Skip("Skipping test: LLM_API_KEY not set in config")
+	}
+
+	ctx := context.Background()
+	_, err := client.Add(ctx, []map[string]interface{}{
+		{"role": "user", "content": "I'm Carol, a pilot who loves gardening."},
+	}, usermemory.WithUserID("user_010"))
+	require.NoError(t, err)
+
+	report, err := client.GenerateUserReport(ctx, "user_010")
+	require.NoError(t, err)
+	assert.Equal(t, "user_010", report.UserID)
+	assert.Positive(t, report.TotalMemories)
+
+	markdown := report.Markdown()
+	assert.Contains(t, markdown, "# What we know about user_010")
+	assert.Contains(t, markdown, "## Profile")
+	if report.Profile != nil && report.Profile.ProfileContent != "" {
+		assert.Contains(t, markdown, report.Profile.ProfileContent)
+	}
+}
@@ -0,0 +1,362 @@
+package usermemory_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/core"
+	usermemory "github.com/oceanbase/powermem-go/pkg/user_memory"
+	usermemorySQLite "github.com/oceanbase/powermem-go/pkg/user_memory/sqlite"
+)
+
+// newFakeOpenAIServer starts an OpenAI-compatible server answering embeddings with
+// a fixed vector and chat completions with responses in order, repeating the last
+// one. The first chatFailures chat completion calls fail with a server error. It
+// counts chat completion calls.
+func newFakeOpenAIServer(t *testing.T, chatFailures int32, responses ...string) (string, *int32) {
+	var chatCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/embeddings"):
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "list",
+				"data": []map[string]interface{}{
+					{"object": "embedding", "index": 0, "embedding": []float64{1, 0, 0}},
+				},
+			})
+		case strings.HasSuffix(r.URL.Path, "/chat/completions"):
+			call := atomic.AddInt32(&chatCalls, 1)
+			if call <= chatFailures {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"error": {"message": "temporary failure"}}`))
+				return
+			}
+			index := int(call-chatFailures) - 1
+			if index >= len(responses) {
+				index = len(responses) - 1
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "chat.completion",
+				"choices": []map[string]interface{}{
+					{"index": 0, "finish_reason": "stop", "message": map[string]interface{}{"role": "assistant", "content": responses[index]}},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, &chatCalls
+}
+
+func setupOfflineUserMemoryTest(t *testing.T, chatFailures int32, configure func(*usermemory.Config), responses ...string) (*usermemory.Client, *int32) {
+	testDBPath := "./test_usermemory_streaming.db"
+	profileDBPath := "./test_user_profiles_streaming.db"
+	_ = os.Remove(testDBPath)
+	_ = os.Remove(profileDBPath)
+
+	url, chatCalls := newFakeOpenAIServer(t, chatFailures, responses...)
+
+	cfg := &usermemory.Config{
+		MemoryConfig: &core.Config{
+			VectorStore: core.VectorStoreConfig{
+				Provider: "sqlite",
+				SQLite: &core.SQLiteConfig{
+					DBPath:             testDBPath,
+					CollectionName:     "memories",
+					EmbeddingModelDims: 3,
+				},
+			},
+			LLM: core.LLMConfig{Provider: "openai", APIKey: "test-key", Model: "gpt-3.5-turbo", BaseURL: url},
+			Embedder: core.EmbedderConfig{
+				Provider: "openai", APIKey: "test-key", Model: "text-embedding-ada-002", BaseURL: url, Dimensions: 3,
+			},
+		},
+		ProfileStoreType: "sqlite",
+		ProfileStoreConfig: &usermemorySQLite.Config{
+			DBPath:    profileDBPath,
+			TableName: "user_profiles",
+		},
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+
+	client, err := usermemory.NewClient(cfg)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = os.Remove(testDBPath)
+		_ = os.Remove(profileDBPath)
+	})
+
+	return client, chatCalls
+}
+
+func TestUserMemory_BatchAddAndStreams(t *testing.T) {
+	client, chatCalls := setupOfflineUserMemoryTest(t, 0, nil, "Alice is a software engineer living in Paris.")
+	ctx := context.Background()
+
+	result, err := client.BatchAdd(ctx, []interface{}{
+		"I'm Alice, a software engineer.",
+		[]map[string]interface{}{{"role": "user", "content": "I live in Paris."}},
+		[]map[string]interface{}{{"role": "user", "content": "I like Go."}},
+	}, usermemory.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Batch.CreatedCount)
+	assert.True(t, result.ProfileExtracted)
+	require.NotNil(t, result.ProfileContent)
+	assert.Contains(t, *result.ProfileContent, "Paris")
+
+	// Profile extraction runs once per batch, not once per conversation
+	assert.Equal(t, int32(1), atomic.LoadInt32(chatCalls))
+
+	profile, err := client.GetProfile(ctx, "user_001")
+	require.NoError(t, err)
+	require.NotNil(t, profile)
+	assert.Equal(t, *result.ProfileContent, profile.ProfileContent)
+
+	total := 0
+	for batch := range client.GetAllStream(ctx, 2, usermemory.WithGetAllUserID("user_001")) {
+		require.NoError(t, batch.Error)
+		total += len(batch.Memories)
+	}
+	assert.Equal(t, 3, total)
+
+	total = 0
+	for batch := range client.SearchStream(ctx, "Alice", 2, usermemory.WithSearchUserID("user_001")) {
+		require.NoError(t, batch.Error)
+		total += len(batch.Memories)
+	}
+	assert.Equal(t, 3, total)
+}
+
+func TestUserMemory_AsyncProfileExtraction(t *testing.T) {
+	// The first extraction attempt fails and is retried in the background
+	client, chatCalls := setupOfflineUserMemoryTest(t, 1, func(cfg *usermemory.Config) {
+		cfg.AsyncProfileExtraction = true
+		cfg.ProfileRetryBackoff = time.Millisecond
+	}, "Alice is a software engineer.")
+	ctx := context.Background()
+
+	result, err := client.Add(ctx, "I'm Alice, a software engineer.", usermemory.WithUserID("user_001"))
+	require.NoError(t, err)
+	require.NotNil(t, result.Memory)
+	assert.True(t, result.ProfilePending)
+	assert.False(t, result.ProfileExtracted)
+
+	client.WaitProfileExtraction()
+	assert.Equal(t, int32(2), atomic.LoadInt32(chatCalls))
+
+	profile, err := client.GetProfile(ctx, "user_001")
+	require.NoError(t, err)
+	require.NotNil(t, profile)
+	assert.Equal(t, "Alice is a software engineer.", profile.ProfileContent)
+}
+
+func TestUserMemory_ShutdownFlushesProfileExtraction(t *testing.T) {
+	client, chatCalls := setupOfflineUserMemoryTest(t, 1, func(cfg *usermemory.Config) {
+		cfg.AsyncProfileExtraction = true
+		cfg.ProfileRetryBackoff = time.Millisecond
+	}, "Alice is a software engineer.")
+	ctx := context.Background()
+
+	result, err := client.Add(ctx, "I'm Alice, a software engineer.", usermemory.WithUserID("user_001"))
+	require.NoError(t, err)
+	assert.True(t, result.ProfilePending)
+
+	// The pending extraction, including its retry, runs before the client closes
+	require.NoError(t, client.Shutdown(ctx))
+	assert.Equal(t, int32(2), atomic.LoadInt32(chatCalls))
+
+	_, err = client.Add(ctx, "I also like hiking.", usermemory.WithUserID("user_001"))
+	assert.ErrorIs(t, err, core.ErrClientClosed)
+	require.NoError(t, client.Close())
+}
+
+func TestUserMemory_StructuredProfileMerge(t *testing.T) {
+	client, _ := setupOfflineUserMemoryTest(t, 0, func(cfg *usermemory.Config) {
+		cfg.ProfileMergeMode = usermemory.ProfileMergeStructured
+	},
+		`{"set": {"name": "Alice", "city": "Paris", "hobbies": ["hiking"]}, "remove": []}`,
+		"```json\n{\"set\": {\"city\": \"Berlin\", \"hobbies\": [\"chess\", \"hiking\"]}, \"remove\": [\"name\"]}\n```",
+	)
+	ctx := context.Background()
+
+	result, err := client.Add(ctx, "I'm Alice from Paris and I like hiking.",
+		usermemory.WithUserID("user_001"), usermemory.WithInfer(false))
+	require.NoError(t, err)
+	assert.True(t, result.ProfileExtracted)
+	assert.Len(t, result.ProfileChanges, 3)
+
+	result, err = client.Add(ctx, "I moved to Berlin and picked up chess. Call me A.",
+		usermemory.WithUserID("user_001"), usermemory.WithInfer(false))
+	require.NoError(t, err)
+	assert.True(t, result.ProfileExtracted)
+	require.Len(t, result.ProfileChanges, 3)
+	assert.Equal(t, usermemory.ProfileFieldChange{Field: "city", OldValue: "Paris", NewValue: "Berlin"}, result.ProfileChanges[0])
+	assert.Equal(t, "hobbies", result.ProfileChanges[1].Field)
+	assert.Equal(t, usermemory.ProfileFieldChange{Field: "name", OldValue: "Alice"}, result.ProfileChanges[2])
+
+	profile, err := client.GetProfile(ctx, "user_001")
+	require.NoError(t, err)
+	require.NotNil(t, profile)
+	assert.Equal(t, map[string]interface{}{
+		"city":    "Berlin",
+		"hobbies": []interface{}{"hiking", "chess"},
+	}, profile.Topics)
+	assert.Equal(t, "city: Berlin\nhobbies: hiking, chess", profile.ProfileContent)
+}
+
+func TestUserMemory_GetProfilesBulk(t *testing.T) {
+	client, chatCalls := setupOfflineUserMemoryTest(t, 0, nil, "The user is a software engineer.")
+	ctx := context.Background()
+
+	for _, userID := range []string{"user_001", "user_002", "user_003"} {
+		_, err := client.Add(ctx, "I'm a software engineer.", usermemory.WithUserID(userID), usermemory.WithInfer(false))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(chatCalls))
+
+	profiles, err := client.GetProfilesBulk(ctx, []string{"user_001", "user_003", "user_001", "missing", ""})
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.Equal(t, "user_001", profiles["user_001"].UserID)
+	assert.Equal(t, "The user is a software engineer.", profiles["user_003"].ProfileContent)
+	assert.NotContains(t, profiles, "missing")
+
+	profiles, err = client.GetProfilesBulk(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, profiles)
+}
+
+func TestUserMemory_ResetProfiles(t *testing.T) {
+	client, _ := setupOfflineUserMemoryTest(t, 0, nil, "The user is a software engineer.")
+	ctx := context.Background()
+
+	for _, userID := range []string{"user_001", "user_002", "user_003"} {
+		_, err := client.Add(ctx, "I'm a software engineer.", usermemory.WithUserID(userID), usermemory.WithInfer(false))
+		require.NoError(t, err)
+	}
+
+	// DeleteAll with DeleteProfile only removes the given user's profile
+	require.NoError(t, client.DeleteAll(ctx,
+		usermemory.WithDeleteAllUserID("user_001"),
+		usermemory.WithDeleteAllProfile(true),
+	))
+	profiles, err := client.GetProfilesBulk(ctx, []string{"user_001", "user_002", "user_003"})
+	require.NoError(t, err)
+	assert.Len(t, profiles, 2)
+	assert.NotContains(t, profiles, "user_001")
+
+	// Reset keeps profiles by default
+	require.NoError(t, client.Reset(ctx))
+	profiles, err = client.GetProfilesBulk(ctx, []string{"user_002", "user_003"})
+	require.NoError(t, err)
+	assert.Len(t, profiles, 2)
+
+	require.NoError(t, client.Reset(ctx, usermemory.WithResetProfiles(true)))
+	all, err := client.GetProfiles(ctx, &usermemory.GetProfilesOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestUserMemory_EraseUser(t *testing.T) {
+	client, _ := setupOfflineUserMemoryTest(t, 0, nil, "The user is a software engineer.")
+	ctx := context.Background()
+
+	for _, userID := range []string{"user_001", "user_002"} {
+		_, err := client.Add(ctx, "I'm a software engineer.", usermemory.WithUserID(userID), usermemory.WithInfer(false))
+		require.NoError(t, err)
+	}
+
+	report, err := client.EraseUser(ctx, "user_001")
+	require.NoError(t, err)
+	assert.Equal(t, "user_001", report.UserID)
+	assert.Equal(t, int64(1), report.Memories)
+	assert.Equal(t, int64(1), report.Profiles)
+	assert.True(t, report.Verified)
+
+	profile, err := client.GetProfile(ctx, "user_001")
+	require.NoError(t, err)
+	assert.Nil(t, profile)
+	memories, err := client.GetAll(ctx, usermemory.WithGetAllUserID("user_001"))
+	require.NoError(t, err)
+	assert.Empty(t, memories)
+
+	// Other users are untouched
+	profile, err = client.GetProfile(ctx, "user_002")
+	require.NoError(t, err)
+	assert.NotNil(t, profile)
+	memories, err = client.GetAll(ctx, usermemory.WithGetAllUserID("user_002"))
+	require.NoError(t, err)
+	assert.Len(t, memories, 1)
+
+	_, err = client.EraseUser(ctx, "")
+	assert.ErrorIs(t, err, core.ErrInvalidInput)
+}
+
+func TestUserMemory_Snapshot(t *testing.T) {
+	client, _ := setupOfflineUserMemoryTest(t, 0, nil, "The user is a software engineer.")
+	ctx := context.Background()
+
+	_, err := client.Add(ctx, "I'm a software engineer.", usermemory.WithUserID("user_001"), usermemory.WithInfer(false))
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "backup")
+	require.NoError(t, client.Snapshot(ctx, dir))
+	assert.FileExists(t, filepath.Join(dir, usermemory.SnapshotMemoriesFile))
+	assert.FileExists(t, filepath.Join(dir, usermemory.SnapshotProfilesFile))
+	assert.Error(t, client.Snapshot(ctx, dir), "the snapshot directory exists")
+
+	_, err = client.EraseUser(ctx, "user_001")
+	require.NoError(t, err)
+	require.NoError(t, client.RestoreSnapshot(ctx, dir))
+
+	profile, err := client.GetProfile(ctx, "user_001")
+	require.NoError(t, err)
+	require.NotNil(t, profile)
+	assert.Equal(t, "The user is a software engineer.", profile.ProfileContent)
+	memories, err := client.GetAll(ctx, usermemory.WithGetAllUserID("user_001"))
+	require.NoError(t, err)
+	assert.Len(t, memories, 1)
+
+	assert.ErrorIs(t, client.RestoreSnapshot(ctx, ""), core.ErrInvalidInput)
+}
+
+func TestUserMemory_UpdateExpectedVersion(t *testing.T) {
+	client, _ := setupOfflineUserMemoryTest(t, 0, nil, "The user is a software engineer.")
+	ctx := context.Background()
+
+	result, err := client.Add(ctx, "I'm a software engineer.", usermemory.WithUserID("user_001"), usermemory.WithInfer(false))
+	require.NoError(t, err)
+	require.NotNil(t, result.Memory)
+	assert.Equal(t, int64(1), result.Memory.Version)
+
+	updated, err := client.Update(ctx, result.Memory.ID, "I'm a senior software engineer.",
+		usermemory.WithUpdateUserID("user_001"),
+		usermemory.WithUpdateExpectedVersion(1),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated.Version)
+
+	// A second writer holding the old version is rejected
+	_, err = client.Update(ctx, result.Memory.ID, "I'm a junior software engineer.",
+		usermemory.WithUpdateUserID("user_001"),
+		usermemory.WithUpdateExpectedVersion(1),
+	)
+	assert.ErrorIs(t, err, core.ErrVersionConflict)
+}
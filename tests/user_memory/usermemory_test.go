@@ -30,10 +30,10 @@ func setupUserMemoryTest(t *testing.T) (*usermemory.Client, *core.Config, func()
 		memoryConfig = &core.Config{
 			VectorStore: core.VectorStoreConfig{
 				Provider: "sqlite",
-				Config: map[string]interface{}{
-					"db_path":              testDBPath,
-					"collection_name":      "memories",
-					"embedding_model_dims": 1536,
+				SQLite: &core.SQLiteConfig{
+					DBPath:             testDBPath,
+					CollectionName:     "memories",
+					EmbeddingModelDims: 1536,
 				},
 			},
 			LLM: core.LLMConfig{
@@ -53,15 +53,18 @@ func setupUserMemoryTest(t *testing.T) (*usermemory.Client, *core.Config, func()
 	} else {
 		// Override test database path
 		if memoryConfig.VectorStore.Provider == "sqlite" {
-			if memoryConfig.VectorStore.Config == nil {
-				memoryConfig.VectorStore.Config = make(map[string]interface{})
+			if memoryConfig.VectorStore.SQLite == nil {
+				memoryConfig.VectorStore.SQLite = &core.SQLiteConfig{}
 			}
-			memoryConfig.VectorStore.Config["db_path"] = testDBPath
-			memoryConfig.VectorStore.Config["collection_name"] = "memories"
+			memoryConfig.VectorStore.SQLite.DBPath = testDBPath
+			memoryConfig.VectorStore.SQLite.CollectionName = "memories"
 		}
 	}
 
+	useMockProviders(memoryConfig)
+
 	// Create UserMemory config
+
 	userMemoryConfig := &usermemory.Config{
 		MemoryConfig:     memoryConfig,
 		ProfileStoreType: "sqlite",
@@ -92,9 +95,21 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
-// hasLLMConfig checks if LLM API Key exists in config.
+// useMockProviders replaces the LLM and embedder of cfg with the mock
+// providers, so that the tests run without API keys. Set POWERMEM_TEST_LIVE
+// to test against the providers configured in the environment instead.
+func useMockProviders(cfg *core.Config) {
+	if os.Getenv("POWERMEM_TEST_LIVE") != "" {
+		return
+	}
+	cfg.LLM = core.LLMConfig{Provider: "mock"}
+	cfg.Embedder = core.EmbedderConfig{Provider: "mock", Dimensions: cfg.Embedder.Dimensions}
+}
+
+// hasLLMConfig checks if an LLM is available: the mock provider or one with
+// an API key.
 func hasLLMConfig(cfg *core.Config) bool {
-	return cfg != nil && cfg.LLM.APIKey != ""
+	return cfg != nil && (cfg.LLM.Provider == "mock" || cfg.LLM.APIKey != "")
 }
 
 func TestUserMemory_Add(t *testing.T) {
@@ -607,6 +622,7 @@ func TestUserMemory_Reset(t *testing.T) {
 	// Verify memories have been deleted
 	memories, err := client.GetAll(ctx,
 		usermemory.WithGetAllLimit(10),
+		usermemory.WithGetAllAllowCrossUser(true),
 	)
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(memories))
@@ -0,0 +1,84 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oceanbase/powermem-go/pkg/webhook"
+)
+
+func TestClient_SendRetries(t *testing.T) {
+	var mu sync.Mutex
+	var attempts int
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "test.event", r.Header.Get(webhook.EventHeader))
+		assert.Empty(t, r.Header.Get(webhook.SignatureHeader), "unsigned without a secret")
+	}))
+	defer server.Close()
+
+	client, err := webhook.NewClient(&webhook.Config{URL: server.URL, RetryBackoff: time.Millisecond})
+	require.NoError(t, err)
+	require.NoError(t, client.Send("test.event", map[string]string{"hello": "world"}))
+	require.NoError(t, client.Close(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 3, attempts)
+	assert.JSONEq(t, `{"hello": "world"}`, body)
+
+	assert.ErrorIs(t, client.Send("test.event", nil), webhook.ErrClosed)
+}
+
+func TestClient_NoRetryOnClientError(t *testing.T) {
+	var mu sync.Mutex
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		mu.Unlock()
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, err := webhook.NewClient(&webhook.Config{URL: server.URL, RetryBackoff: time.Millisecond})
+	require.NoError(t, err)
+	require.NoError(t, client.Send("test.event", "payload"))
+	require.NoError(t, client.Close(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, attempts)
+}
+
+func TestNewClient_InvalidURL(t *testing.T) {
+	for _, url := range []string{"", "example.com/hook", "ftp://example.com/hook"} {
+		_, err := webhook.NewClient(&webhook.Config{URL: url})
+		assert.Error(t, err, url)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256("key", "The quick brown fox jumps over the lazy dog")
+	assert.Equal(t,
+		"sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		webhook.Sign("key", []byte("The quick brown fox jumps over the lazy dog")))
+}